@@ -21,7 +21,9 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 
+	_ "github.com/mostynb/go-grpc-compression/nonclobbering/zstd" // registers the zstd compressor for the query gRPC server
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	_ "go.uber.org/automaxprocs"
@@ -34,6 +36,7 @@ import (
 	collectorApp "github.com/jaegertracing/jaeger/cmd/collector/app"
 	collectorFlags "github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/doctor"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
@@ -42,11 +45,13 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	metricsPlugin "github.com/jaegertracing/jaeger/plugin/metrics"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategyprovider"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategyprovider/adaptive"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
@@ -64,7 +69,8 @@ func main() {
 	if os.Getenv(storage.SpanStorageTypeEnvVar) == "" {
 		os.Setenv(storage.SpanStorageTypeEnvVar, "memory") // other storage types default to SpanStorage
 	}
-	storageFactory, err := storage.NewFactory(storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr))
+	storageFactoryCfg := storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr)
+	storageFactory, err := storage.NewFactory(storageFactoryCfg)
 	if err != nil {
 		log.Fatalf("Cannot initialize storage factory: %v", err)
 	}
@@ -141,6 +147,9 @@ by default uses only in-memory database.`,
 			if err != nil {
 				logger.Fatal("Failed to create sampling strategy provider", zap.Error(err))
 			}
+			if introspector, ok := samplingAggregator.(adaptive.Introspector); ok {
+				svc.Admin.Handle("/sampling/adaptive", adaptive.NewIntrospectionHandler(introspector))
+			}
 
 			aOpts := new(agentApp.Builder).InitFromViper(v)
 			repOpts := new(agentRep.Options).InitFromViper(v, logger)
@@ -159,16 +168,23 @@ by default uses only in-memory database.`,
 
 			tm := tenancy.NewManager(&cOpts.GRPC.Tenancy)
 
+			var liveTail *livetail.Broadcaster
+			if qOpts.EnableLiveTail {
+				liveTail = livetail.NewBroadcaster(livetail.DefaultBufferSize)
+			}
+
 			// collector
 			c := collectorApp.New(&collectorApp.CollectorParams{
 				ServiceName:        "jaeger-collector",
 				Logger:             logger,
 				MetricsFactory:     collectorMetricsFactory,
-				SpanWriter:         spanWriter,
+				SpanWriter:         storageMetrics.NewWriteMetricsDecorator(spanWriter, collectorMetricsFactory),
 				SamplingProvider:   samplingProvider,
 				SamplingAggregator: samplingAggregator,
 				HealthCheck:        svc.HC(),
 				TenancyMgr:         tm,
+				StorageSink:        strings.Join(storageFactoryCfg.SpanWriterTypes, ","),
+				LiveTail:           liveTail,
 			})
 			if err := c.Start(cOpts); err != nil {
 				log.Fatal(err)
@@ -195,8 +211,10 @@ by default uses only in-memory database.`,
 			agent := startAgent(cp, aOpts, logger, agentMetricsFactory)
 
 			// query
+			queryServiceOpts := qOpts.BuildQueryServiceOptions(storageFactory, logger)
+			queryServiceOpts.LiveTail = liveTail
 			querySrv := startQuery(
-				svc, qOpts, qOpts.BuildQueryServiceOptions(storageFactory, logger),
+				svc, qOpts, queryServiceOpts,
 				spanReader, dependencyReader, metricsQueryService,
 				queryMetricsFactory, tm, tracer,
 			)
@@ -227,6 +245,7 @@ by default uses only in-memory database.`,
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.CollectorAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(doctor.Command(v))
 
 	config.AddFlags(
 		v,
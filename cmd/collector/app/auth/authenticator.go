@@ -0,0 +1,239 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	otelauth "go.opentelemetry.io/collector/extension/auth"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// ExtensionID identifies the authenticator among the extensions of the OTLP receivers.
+var ExtensionID = component.MustNewID("jaeger_collector_auth")
+
+var (
+	errMissingToken = status.Error(codes.Unauthenticated, "missing bearer token")
+	errInvalidToken = status.Error(codes.Unauthenticated, "invalid bearer token")
+)
+
+type staticToken struct {
+	token  []byte
+	tenant string
+}
+
+// Authenticator authenticates the requests by their bearer token, which is either one
+// of the static tokens or a JWT of the OIDC issuer. The tenant mapped to the token,
+// if any, is attached to the context of the request with tenancy.WithTenant.
+type Authenticator struct {
+	logger *zap.Logger
+	tokens []staticToken
+	oidc   *oidcVerifier
+
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewAuthenticator creates an Authenticator with the static tokens of the bearer tokens file.
+func NewAuthenticator(options Options, logger *zap.Logger) (*Authenticator, error) {
+	a := &Authenticator{logger: logger, stop: make(chan struct{})}
+	if options.BearerTokensFile != "" {
+		tokens, err := loadBearerTokens(options.BearerTokensFile)
+		if err != nil {
+			return nil, err
+		}
+		a.tokens = tokens
+	}
+	if options.OIDC.IssuerURL != "" {
+		if options.OIDC.Audience == "" {
+			return nil, fmt.Errorf("--%s is required to validate the OIDC tokens", flagOIDCAudience)
+		}
+		if options.OIDC.JWKSRefreshInterval <= 0 {
+			options.OIDC.JWKSRefreshInterval = DefaultJWKSRefreshInterval
+		}
+		a.oidc = newOIDCVerifier(options.OIDC, &http.Client{Timeout: 10 * time.Second}, logger)
+	}
+	return a, nil
+}
+
+func loadBearerTokens(path string) ([]staticToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bearer tokens: %w", err)
+	}
+	var tokens []staticToken
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 0:
+		case 1:
+			tokens = append(tokens, staticToken{token: []byte(fields[0])})
+		case 2:
+			tokens = append(tokens, staticToken{token: []byte(fields[0]), tenant: fields[1]})
+		default:
+			return nil, fmt.Errorf("invalid line in bearer tokens file %s, expected a token and an optional tenant", path)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no bearer tokens in %s", path)
+	}
+	return tokens, nil
+}
+
+// Start fetches the signing keys of the OIDC issuer, and refreshes them periodically until Close.
+func (a *Authenticator) Start(ctx context.Context) error {
+	if a.oidc == nil {
+		return nil
+	}
+	if err := a.oidc.refresh(ctx); err != nil {
+		return err
+	}
+	a.stopped.Add(1)
+	go func() {
+		defer a.stopped.Done()
+		ticker := time.NewTicker(a.oidc.options.JWKSRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := a.oidc.refresh(context.Background()); err != nil {
+					a.logger.Warn("Failed to refresh the OIDC signing keys", zap.Error(err))
+				}
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops the refresh of the OIDC signing keys.
+func (a *Authenticator) Close() error {
+	close(a.stop)
+	a.stopped.Wait()
+	return nil
+}
+
+// Authenticate validates the bearer token of the Authorization header, looked up ignoring case
+// so that both HTTP headers and gRPC metadata can be passed. It implements auth.Server.Authenticate.
+func (a *Authenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	token, err := bearerToken(headers)
+	if err != nil {
+		return ctx, err
+	}
+	tenant, err := a.authenticateToken(ctx, token)
+	if err != nil {
+		a.logger.Debug("Rejecting unauthenticated request", zap.Error(err))
+		return ctx, errInvalidToken
+	}
+	if tenant != "" {
+		ctx = tenancy.WithTenant(ctx, tenant)
+	}
+	return ctx, nil
+}
+
+func bearerToken(headers map[string][]string) (string, error) {
+	for key, values := range headers {
+		if !strings.EqualFold(key, "Authorization") || len(values) == 0 {
+			continue
+		}
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", errInvalidToken
+		}
+		return token, nil
+	}
+	return "", errMissingToken
+}
+
+func (a *Authenticator) authenticateToken(ctx context.Context, token string) (string, error) {
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(t.token, []byte(token)) == 1 {
+			return t.tenant, nil
+		}
+	}
+	if a.oidc == nil {
+		return "", errors.New("unknown static token")
+	}
+	return a.oidc.verify(ctx, token)
+}
+
+// ServerExtension returns the authenticator as an extension of the OTLP receivers,
+// which refer to it by ExtensionID. Its lifecycle is managed by Start and Close.
+func (a *Authenticator) ServerExtension() otelauth.Server {
+	return otelauth.NewServer(otelauth.WithServerAuthenticate(a.Authenticate))
+}
+
+// guarded returns whether the gRPC method belongs to one of the services, or to any service if none.
+func guarded(fullMethod string, services []string) bool {
+	if len(services) == 0 {
+		return true
+	}
+	for _, service := range services {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticatedServerStream is a wrapper for ServerStream providing the authenticated context
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	context context.Context
+}
+
+func (ass *authenticatedServerStream) Context() context.Context {
+	return ass.context
+}
+
+// NewGuardingUnaryInterceptor rejects the RPCs of the given services, or of all services
+// if none is given, that the Authenticator does not authenticate.
+func NewGuardingUnaryInterceptor(a *Authenticator, services ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !guarded(info.FullMethod, services) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, err := a.Authenticate(ctx, md)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewGuardingStreamInterceptor rejects the streams of the given services, or of all services
+// if none is given, that the Authenticator does not authenticate.
+func NewGuardingStreamInterceptor(a *Authenticator, services ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !guarded(info.FullMethod, services) {
+			return handler(srv, ss)
+		}
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		ctx, err := a.Authenticate(ss.Context(), md)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{ServerStream: ss, context: ctx})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func writeBearerTokens(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name    string
+		options Options
+		err     string
+	}{
+		{
+			name:    "missing file",
+			options: Options{BearerTokensFile: filepath.Join(dir, "missing")},
+			err:     "failed to read bearer tokens",
+		},
+		{
+			name:    "empty file",
+			options: Options{BearerTokensFile: writeBearerTokens(t, "\n")},
+			err:     "no bearer tokens in",
+		},
+		{
+			name:    "invalid line",
+			options: Options{BearerTokensFile: writeBearerTokens(t, "token tenant extra\n")},
+			err:     "invalid line in bearer tokens file",
+		},
+		{
+			name:    "missing audience",
+			options: Options{OIDC: OIDCOptions{IssuerURL: "https://issuer"}},
+			err:     "--collector.auth.oidc.audience is required",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAuthenticator(tc.options, zap.NewNop())
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestAuthenticateStaticTokens(t *testing.T) {
+	a, err := NewAuthenticator(Options{BearerTokensFile: writeBearerTokens(t, "shared\n\nsecret acme\n")}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, a.Start(context.Background()))
+	defer a.Close()
+
+	testCases := []struct {
+		name    string
+		headers map[string][]string
+		tenant  string
+		err     error
+	}{
+		{name: "token without tenant", headers: map[string][]string{"Authorization": {"Bearer shared"}}},
+		{name: "token with tenant", headers: map[string][]string{"authorization": {"bearer secret"}}, tenant: "acme"},
+		{name: "missing header", headers: map[string][]string{"X-Tenant": {"acme"}}, err: errMissingToken},
+		{name: "basic auth", headers: map[string][]string{"Authorization": {"Basic c2VjcmV0"}}, err: errInvalidToken},
+		{name: "unknown token", headers: map[string][]string{"Authorization": {"Bearer guess"}}, err: errInvalidToken},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, err := a.Authenticate(context.Background(), tc.headers)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.tenant, tenancy.GetTenant(ctx))
+		})
+	}
+}
+
+func TestAuthenticateOIDC(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := newTestIssuer(t)
+	issuer.setKeys(rsaJSONWebKey("rsa", key))
+
+	options := Options{
+		BearerTokensFile: writeBearerTokens(t, "shared\n"),
+		OIDC:             issuer.options(),
+	}
+	options.OIDC.TenantClaim = "tenant"
+	options.OIDC.JWKSRefreshInterval = time.Millisecond
+	a, err := NewAuthenticator(options, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, a.Start(context.Background()))
+	defer a.Close()
+
+	token := signJWT(t, "RS256", "rsa", key, validClaims(issuer.server.URL))
+	ctx, err := a.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + token}})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenancy.GetTenant(ctx))
+
+	ctx, err = a.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer shared"}})
+	require.NoError(t, err)
+	assert.Empty(t, tenancy.GetTenant(ctx))
+
+	_, err = a.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + token + "x"}})
+	require.ErrorIs(t, err, errInvalidToken)
+
+	// the issuer is discovered when the authenticator starts
+	options.OIDC.IssuerURL += "/missing"
+	a, err = NewAuthenticator(options, zap.NewNop())
+	require.NoError(t, err)
+	require.ErrorContains(t, a.Start(context.Background()), "failed to discover the OIDC issuer")
+}
+
+func TestServerExtension(t *testing.T) {
+	a, err := NewAuthenticator(Options{BearerTokensFile: writeBearerTokens(t, "secret acme\n")}, zap.NewNop())
+	require.NoError(t, err)
+	ext := a.ServerExtension()
+	ctx, err := ext.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer secret"}})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenancy.GetTenant(ctx))
+	_, err = ext.Authenticate(context.Background(), map[string][]string{})
+	require.ErrorIs(t, err, errMissingToken)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestGuardingInterceptors(t *testing.T) {
+	a, err := NewAuthenticator(Options{BearerTokensFile: writeBearerTokens(t, "secret acme\n")}, zap.NewNop())
+	require.NoError(t, err)
+	const service = "jaeger.api_v2.CollectorService"
+	unary := NewGuardingUnaryInterceptor(a, service)
+	stream := NewGuardingStreamInterceptor(a, service)
+
+	authenticated := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+	testCases := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		tenant string
+		err    error
+	}{
+		{name: "authenticated", ctx: authenticated, method: "/" + service + "/PostSpans", tenant: "acme"},
+		{name: "unauthenticated", ctx: context.Background(), method: "/" + service + "/PostSpans", err: errMissingToken},
+		{name: "unguarded service", ctx: context.Background(), method: "/grpc.health.v1.Health/Check"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var tenant string
+			_, err := unary(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, func(ctx context.Context, _ any) (any, error) {
+				tenant = tenancy.GetTenant(ctx)
+				return nil, nil
+			})
+			require.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.tenant, tenant)
+
+			tenant = ""
+			err = stream(nil, &testServerStream{ctx: tc.ctx}, &grpc.StreamServerInfo{FullMethod: tc.method}, func(_ any, ss grpc.ServerStream) error {
+				tenant = tenancy.GetTenant(ss.Context())
+				return nil
+			})
+			require.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.tenant, tenant)
+		})
+	}
+
+	// without services, every RPC is guarded
+	_, err = NewGuardingUnaryInterceptor(a)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+		func(context.Context, any) (any, error) { return nil, nil })
+	require.ErrorIs(t, err, errMissingToken)
+}
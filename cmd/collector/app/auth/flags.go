@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagPrefix              = "collector.auth"
+	flagBearerTokensFile    = flagPrefix + ".bearer-tokens-file"
+	flagOIDCIssuerURL       = flagPrefix + ".oidc.issuer-url"
+	flagOIDCAudience        = flagPrefix + ".oidc.audience"
+	flagOIDCTenantClaim     = flagPrefix + ".oidc.tenant-claim"
+	flagOIDCJWKSRefreshTime = flagPrefix + ".oidc.jwks-refresh-interval"
+
+	// DefaultJWKSRefreshInterval is how often the signing keys of the OIDC issuer are fetched by default
+	DefaultJWKSRefreshInterval = time.Hour
+)
+
+// Options configures the authentication of the requests sending spans to the collector.
+type Options struct {
+	// BearerTokensFile is the path to a file of static bearer tokens, one per line,
+	// each optionally followed by whitespace and the tenant of the token.
+	BearerTokensFile string
+	// OIDC configures the validation of the bearer tokens that are JWTs signed by an OIDC issuer.
+	OIDC OIDCOptions
+}
+
+// OIDCOptions configures the validation of JWTs issued by an OpenID Connect provider.
+type OIDCOptions struct {
+	// IssuerURL is the issuer the JWTs must come from, whose discovery document lists the signing keys.
+	IssuerURL string
+	// Audience is the audience the JWTs must be issued for.
+	Audience string
+	// TenantClaim is the claim of the JWTs holding the tenant of the spans, if any.
+	TenantClaim string
+	// JWKSRefreshInterval is how often the signing keys of the issuer are fetched.
+	JWKSRefreshInterval time.Duration
+}
+
+// Enabled returns whether the requests must be authenticated.
+func (o *Options) Enabled() bool {
+	return o.BearerTokensFile != "" || o.OIDC.IssuerURL != ""
+}
+
+// AddFlags adds flags for the authentication of the collector receivers to the FlagSet.
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(flagBearerTokensFile, "",
+		"Path to a file of static bearer tokens accepted by the OTLP receivers and the gRPC server, one per line, "+
+			"each optionally followed by whitespace and the tenant of its spans")
+	flags.String(flagOIDCIssuerURL, "",
+		"URL of the OpenID Connect issuer whose JWTs are accepted as bearer tokens by the OTLP receivers and the gRPC server")
+	flags.String(flagOIDCAudience, "", "The audience the OpenID Connect JWTs must be issued for")
+	flags.String(flagOIDCTenantClaim, "",
+		"The claim of the OpenID Connect JWTs holding the tenant of the spans, which takes precedence over the tenancy header")
+	flags.Duration(flagOIDCJWKSRefreshTime, DefaultJWKSRefreshInterval,
+		"How often the signing keys of the OpenID Connect issuer are fetched")
+}
+
+// InitFromViper creates auth.Options populated with values retrieved from Viper.
+func InitFromViper(v *viper.Viper) Options {
+	return Options{
+		BearerTokensFile: v.GetString(flagBearerTokensFile),
+		OIDC: OIDCOptions{
+			IssuerURL:           v.GetString(flagOIDCIssuerURL),
+			Audience:            v.GetString(flagOIDCAudience),
+			TenantClaim:         v.GetString(flagOIDCTenantClaim),
+			JWKSRefreshInterval: v.GetDuration(flagOIDCJWKSRefreshTime),
+		},
+	}
+}
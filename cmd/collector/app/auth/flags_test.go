@@ -0,0 +1,44 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsFromFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--collector.auth.bearer-tokens-file=/etc/jaeger/tokens",
+		"--collector.auth.oidc.issuer-url=https://issuer",
+		"--collector.auth.oidc.audience=jaeger",
+		"--collector.auth.oidc.tenant-claim=org",
+		"--collector.auth.oidc.jwks-refresh-interval=5m",
+	}))
+	options := InitFromViper(v)
+	assert.Equal(t, Options{
+		BearerTokensFile: "/etc/jaeger/tokens",
+		OIDC: OIDCOptions{
+			IssuerURL:           "https://issuer",
+			Audience:            "jaeger",
+			TenantClaim:         "org",
+			JWKSRefreshInterval: 5 * time.Minute,
+		},
+	}, options)
+	assert.True(t, options.Enabled())
+}
+
+func TestDefaultOptions(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags(nil))
+	options := InitFromViper(v)
+	assert.False(t, options.Enabled())
+	assert.Equal(t, DefaultJWKSRefreshInterval, options.OIDC.JWKSRefreshInterval)
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// maxDocumentSize limits the size of the discovery document and of the key set of the issuer
+	maxDocumentSize = 1024 * 1024
+	// clockSkew is the tolerance of the validation of the expiration and not-before times of the JWTs
+	clockSkew = time.Minute
+	// minKeysRefreshInterval limits how often a JWT signed by an unknown key refreshes the keys
+	minKeysRefreshInterval = time.Minute
+)
+
+var errUnknownKey = errors.New("the JWT is not signed by a known key")
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type signingKey struct {
+	kid string
+	key crypto.PublicKey
+}
+
+// oidcVerifier validates the JWTs signed by the keys an OpenID Connect issuer
+// publishes in the key set of its discovery document.
+type oidcVerifier struct {
+	options OIDCOptions
+	client  *http.Client
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu          sync.RWMutex
+	jwksURI     string
+	keys        []signingKey
+	lastRefresh time.Time
+}
+
+func newOIDCVerifier(options OIDCOptions, client *http.Client, logger *zap.Logger) *oidcVerifier {
+	return &oidcVerifier{
+		options: options,
+		client:  client,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// refresh fetches the keys of the issuer, after its discovery document the first time.
+func (v *oidcVerifier) refresh(ctx context.Context) error {
+	v.mu.RLock()
+	jwksURI := v.jwksURI
+	v.mu.RUnlock()
+	if jwksURI == "" {
+		var doc discoveryDocument
+		if err := v.getJSON(ctx, strings.TrimSuffix(v.options.IssuerURL, "/")+discoveryPath, &doc); err != nil {
+			return fmt.Errorf("failed to discover the OIDC issuer: %w", err)
+		}
+		if doc.Issuer != v.options.IssuerURL {
+			return fmt.Errorf("the OIDC discovery document is for issuer %q instead of %q", doc.Issuer, v.options.IssuerURL)
+		}
+		if doc.JWKSURI == "" {
+			return errors.New("the OIDC discovery document has no jwks_uri")
+		}
+		jwksURI = doc.JWKSURI
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(ctx, jwksURI, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch the OIDC signing keys: %w", err)
+	}
+	var keys []signingKey
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(jwk)
+		if err != nil {
+			v.logger.Warn("Ignoring OIDC signing key", zap.String("kid", jwk.Kid), zap.Error(err))
+			continue
+		}
+		keys = append(keys, signingKey{kid: jwk.Kid, key: key})
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.jwksURI = jwksURI
+	v.keys = keys
+	v.lastRefresh = v.now()
+	return nil
+}
+
+func (v *oidcVerifier) getJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(target)
+}
+
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// candidateKeys returns the keys with the given ID, or all the keys if the ID is empty.
+func (v *oidcVerifier) candidateKeys(kid string) []crypto.PublicKey {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	var keys []crypto.PublicKey
+	for _, key := range v.keys {
+		if kid == "" || key.kid == kid {
+			keys = append(keys, key.key)
+		}
+	}
+	return keys
+}
+
+// verify checks the signature, issuer, audience and validity period of the JWT,
+// and returns the tenant of its claims when a tenant claim is configured.
+func (v *oidcVerifier) verify(ctx context.Context, token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("the token is not a JWT")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", fmt.Errorf("invalid JWT header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid JWT signature: %w", err)
+	}
+
+	keys := v.candidateKeys(header.Kid)
+	if len(keys) == 0 && v.refreshAllowed() {
+		// the issuer may have rotated its keys since the last refresh
+		if err := v.refresh(ctx); err != nil {
+			v.logger.Warn("Failed to refresh the OIDC signing keys", zap.Error(err))
+		}
+		keys = v.candidateKeys(header.Kid)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	err = errUnknownKey
+	for _, key := range keys {
+		if err = verifySignature(header.Alg, key, signed, signature); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("invalid JWT claims: %w", err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return "", err
+	}
+	if v.options.TenantClaim == "" {
+		return "", nil
+	}
+	tenant, ok := claims[v.options.TenantClaim].(string)
+	if !ok || tenant == "" {
+		return "", fmt.Errorf("the JWT has no %s claim", v.options.TenantClaim)
+	}
+	return tenant, nil
+}
+
+func (v *oidcVerifier) refreshAllowed() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.now().Sub(v.lastRefresh) >= minKeysRefreshInterval
+}
+
+func (v *oidcVerifier) validateClaims(claims map[string]any) error {
+	if iss, _ := claims["iss"].(string); iss != v.options.IssuerURL {
+		return fmt.Errorf("the JWT is issued by %q", iss)
+	}
+	var audiences []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = []string{aud}
+	case []any:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	if !slices.Contains(audiences, v.options.Audience) {
+		return fmt.Errorf("the JWT is not issued for audience %q", v.options.Audience)
+	}
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("the JWT has no expiration time")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return errors.New("the JWT is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("the JWT is not valid yet")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	var hash crypto.Hash
+	switch alg[min(2, len(alg)):] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("the JWT algorithm %q does not match the key", alg)
+		}
+		if alg[:2] == "RS" {
+			return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+		}
+		return rsa.VerifyPSS(rsaKey, hash, digest, signature, nil)
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("the JWT algorithm %q does not match the key", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid ECDSA signature size")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+}
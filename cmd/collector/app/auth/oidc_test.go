@@ -0,0 +1,244 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testAudience = "jaeger-collector"
+
+// testIssuer is an OIDC issuer serving its discovery document and key set.
+type testIssuer struct {
+	server *httptest.Server
+
+	mu   sync.Mutex
+	keys []jsonWebKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(discoveryDocument{Issuer: issuer.server.URL, JWKSURI: issuer.server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]any{"keys": issuer.keys})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) setKeys(keys ...jsonWebKey) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = keys
+}
+
+func (i *testIssuer) options() OIDCOptions {
+	return OIDCOptions{
+		IssuerURL:           i.server.URL,
+		Audience:            testAudience,
+		JWKSRefreshInterval: time.Hour,
+	}
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func rsaJSONWebKey(kid string, key *rsa.PrivateKey) jsonWebKey {
+	return jsonWebKey{Kty: "RSA", Kid: kid, N: encodeBigInt(key.N), E: encodeBigInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJSONWebKey(kid string, key *ecdsa.PrivateKey) jsonWebKey {
+	return jsonWebKey{Kty: "EC", Kid: kid, Crv: "P-256", X: encodeBigInt(key.X), Y: encodeBigInt(key.Y)}
+}
+
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(jwtHeader{Alg: alg, Kid: kid}) + "." + encode(claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest.Sum(nil))
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest.Sum(nil))
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims(issuer string) map[string]any {
+	return map[string]any{
+		"iss":    issuer,
+		"aud":    []string{"other", testAudience},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"tenant": "acme",
+	}
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := newTestIssuer(t)
+	issuer.setKeys(rsaJSONWebKey("rsa", rsaKey), ecJSONWebKey("ec", ecKey), jsonWebKey{Kty: "oct", Kid: "hmac"})
+
+	options := issuer.options()
+	options.TenantClaim = "tenant"
+	v := newOIDCVerifier(options, http.DefaultClient, zap.NewNop())
+	require.NoError(t, v.refresh(context.Background()))
+	assert.Len(t, v.keys, 2)
+
+	claims := func(update func(map[string]any)) map[string]any {
+		c := validClaims(issuer.server.URL)
+		update(c)
+		return c
+	}
+	testCases := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{name: "RS256", token: signJWT(t, "RS256", "rsa", rsaKey, validClaims(issuer.server.URL))},
+		{name: "ES256", token: signJWT(t, "ES256", "ec", ecKey, validClaims(issuer.server.URL))},
+		{name: "no key ID", token: signJWT(t, "ES256", "", ecKey, validClaims(issuer.server.URL))},
+		{name: "not a JWT", token: "static-token", err: "the token is not a JWT"},
+		{name: "wrong key type", token: signJWT(t, "ES256", "rsa", ecKey, validClaims(issuer.server.URL)), err: "does not match the key"},
+		{name: "wrong key", token: signJWT(t, "RS256", "rsa", ecKey, validClaims(issuer.server.URL)), err: "crypto/rsa: verification error"},
+		{name: "unsupported algorithm", token: signJWT(t, "HS256", "rsa", rsaKey, validClaims(issuer.server.URL)), err: `unsupported JWT algorithm "HS256"`},
+		{name: "none algorithm", token: signJWT(t, "none", "rsa", rsaKey, validClaims(issuer.server.URL)), err: `unsupported JWT algorithm "none"`},
+		{name: "unknown key", token: signJWT(t, "RS256", "rotated", rsaKey, validClaims(issuer.server.URL)), err: errUnknownKey.Error()},
+		{
+			name:  "wrong issuer",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { c["iss"] = "https://evil" })),
+			err:   `the JWT is issued by "https://evil"`,
+		},
+		{
+			name:  "wrong audience",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { c["aud"] = "other" })),
+			err:   `the JWT is not issued for audience "jaeger-collector"`,
+		},
+		{
+			name:  "expired",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() })),
+			err:   "the JWT is expired",
+		},
+		{
+			name:  "no expiration",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { delete(c, "exp") })),
+			err:   "the JWT has no expiration time",
+		},
+		{
+			name:  "not valid yet",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() })),
+			err:   "the JWT is not valid yet",
+		},
+		{
+			name:  "no tenant",
+			token: signJWT(t, "RS256", "rsa", rsaKey, claims(func(c map[string]any) { delete(c, "tenant") })),
+			err:   "the JWT has no tenant claim",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tenant, err := v.verify(context.Background(), tc.token)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "acme", tenant)
+		})
+	}
+}
+
+func TestOIDCVerifierKeyRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := newTestIssuer(t)
+	issuer.setKeys(rsaJSONWebKey("old", oldKey))
+
+	v := newOIDCVerifier(issuer.options(), http.DefaultClient, zap.NewNop())
+	now := time.Now()
+	v.now = func() time.Time { return now }
+	require.NoError(t, v.refresh(context.Background()))
+
+	issuer.setKeys(rsaJSONWebKey("old", oldKey), rsaJSONWebKey("new", newKey))
+	token := signJWT(t, "RS256", "new", newKey, validClaims(issuer.server.URL))
+	_, err = v.verify(context.Background(), token)
+	require.ErrorIs(t, err, errUnknownKey, "the keys were just refreshed")
+
+	now = now.Add(minKeysRefreshInterval)
+	tenant, err := v.verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Empty(t, tenant, "no tenant claim is configured")
+}
+
+func TestOIDCVerifierDiscoveryErrors(t *testing.T) {
+	issuer := newTestIssuer(t)
+	options := issuer.options()
+	options.IssuerURL += "/"
+	err := newOIDCVerifier(options, http.DefaultClient, zap.NewNop()).refresh(context.Background())
+	require.ErrorContains(t, err, "the OIDC discovery document is for issuer")
+
+	options.IssuerURL = issuer.server.URL + "/missing"
+	err = newOIDCVerifier(options, http.DefaultClient, zap.NewNop()).refresh(context.Background())
+	require.ErrorContains(t, err, "failed to discover the OIDC issuer")
+	require.ErrorContains(t, err, "returned status 404")
+}
+
+func TestParseJSONWebKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		jwk  jsonWebKey
+		err  string
+	}{
+		{name: "unsupported type", jwk: jsonWebKey{Kty: "oct"}, err: `unsupported key type "oct"`},
+		{name: "invalid modulus", jwk: jsonWebKey{Kty: "RSA", N: "!", E: "AQAB"}, err: "invalid modulus"},
+		{name: "invalid exponent", jwk: jsonWebKey{Kty: "RSA", N: "AQAB", E: ""}, err: "invalid exponent"},
+		{name: "unsupported curve", jwk: jsonWebKey{Kty: "EC", Crv: "P-224"}, err: `unsupported curve "P-224"`},
+		{name: "invalid x", jwk: jsonWebKey{Kty: "EC", Crv: "P-384", X: "!"}, err: "invalid x coordinate"},
+		{name: "invalid y", jwk: jsonWebKey{Kty: "EC", Crv: "P-521", X: "AQAB", Y: ""}, err: "invalid y coordinate"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseJSONWebKey(tc.jwk)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
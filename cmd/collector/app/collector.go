@@ -25,14 +25,17 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/registry"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/samplingstrategy"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/server"
 	"github.com/jaegertracing/jaeger/internal/safeexpvar"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -56,12 +59,17 @@ type Collector struct {
 	spanProcessor      processor.SpanProcessor
 	spanHandlers       *SpanHandlers
 	tenancyMgr         *tenancy.Manager
+	storageSink        string
+	liveTail           *livetail.Broadcaster
 
 	// state, read only
 	hServer                    *http.Server
 	grpcServer                 *grpc.Server
 	otlpReceiver               receiver.Traces
 	zipkinReceiver             receiver.Traces
+	datadogServer              *http.Server
+	registryReporter           *registry.Reporter
+	authenticator              *auth.Authenticator
 	tlsGRPCCertWatcherCloser   io.Closer
 	tlsHTTPCertWatcherCloser   io.Closer
 	tlsZipkinCertWatcherCloser io.Closer
@@ -77,6 +85,10 @@ type CollectorParams struct {
 	SamplingAggregator samplingstrategy.Aggregator
 	HealthCheck        *healthcheck.HealthCheck
 	TenancyMgr         *tenancy.Manager
+	// StorageSink names the storage the spans are written to, e.g. "cassandra"
+	StorageSink string
+	// LiveTail, if set, receives every processed span for live tail clients
+	LiveTail *livetail.Broadcaster
 }
 
 // New constructs a new collector component, ready to be started
@@ -90,29 +102,63 @@ func New(params *CollectorParams) *Collector {
 		samplingAggregator: params.SamplingAggregator,
 		hCheck:             params.HealthCheck,
 		tenancyMgr:         params.TenancyMgr,
+		storageSink:        params.StorageSink,
+		liveTail:           params.LiveTail,
 	}
 }
 
 // Start the component and underlying dependencies
 func (c *Collector) Start(options *flags.CollectorOptions) error {
+	processors, err := processor.DefaultRegistry.Build(options.Processors, processor.Params{
+		Logger:         c.logger,
+		MetricsFactory: c.metricsFactory,
+	})
+	if err != nil {
+		return fmt.Errorf("could not build span processors: %w", err)
+	}
 	handlerBuilder := &SpanHandlerBuilder{
 		SpanWriter:     c.spanWriter,
 		CollectorOpts:  options,
 		Logger:         c.logger,
 		MetricsFactory: c.metricsFactory,
 		TenancyMgr:     c.tenancyMgr,
+		StorageSink:    c.storageSink,
+		Processors:     processors,
 	}
 
 	var additionalProcessors []ProcessSpan
 	if c.samplingAggregator != nil {
-		additionalProcessors = append(additionalProcessors, func(span *model.Span, _ /* tenant */ string) {
-			c.samplingAggregator.HandleRootSpan(span, c.logger)
-		})
+		if options.SamplingObservationPoint == flags.SamplingObservationReceived {
+			// observe the spans before the queue, which drops them when it is full
+			handlerBuilder.PreProcessSpans = func(spans []*model.Span, _ /* tenant */ string) {
+				for _, span := range spans {
+					c.samplingAggregator.HandleRootSpan(span, c.logger)
+				}
+			}
+		} else {
+			additionalProcessors = append(additionalProcessors, func(span *model.Span, _ /* tenant */ string) {
+				c.samplingAggregator.HandleRootSpan(span, c.logger)
+			})
+		}
+	}
+	if c.liveTail != nil {
+		additionalProcessors = append(additionalProcessors, c.liveTail.Publish)
 	}
 
 	c.spanProcessor = handlerBuilder.BuildSpanProcessor(additionalProcessors...)
 	c.spanHandlers = handlerBuilder.BuildHandlers(c.spanProcessor)
 
+	if options.Auth.Enabled() {
+		authenticator, err := auth.NewAuthenticator(options.Auth, c.logger)
+		if err != nil {
+			return fmt.Errorf("could not create the receivers authenticator: %w", err)
+		}
+		if err := authenticator.Start(context.Background()); err != nil {
+			return fmt.Errorf("could not start the receivers authenticator: %w", err)
+		}
+		c.authenticator = authenticator
+	}
+
 	grpcServer, err := server.StartGRPCServer(&server.GRPCServerParams{
 		HostPort:                options.GRPC.HostPort,
 		Handler:                 c.spanHandlers.GRPCHandler,
@@ -122,6 +168,7 @@ func (c *Collector) Start(options *flags.CollectorOptions) error {
 		MaxReceiveMessageLength: options.GRPC.MaxReceiveMessageLength,
 		MaxConnectionAge:        options.GRPC.MaxConnectionAge,
 		MaxConnectionAgeGrace:   options.GRPC.MaxConnectionAgeGrace,
+		Authenticator:           c.authenticator,
 	})
 	if err != nil {
 		return fmt.Errorf("could not start gRPC server: %w", err)
@@ -136,6 +183,13 @@ func (c *Collector) Start(options *flags.CollectorOptions) error {
 		MetricsFactory:   c.metricsFactory,
 		SamplingProvider: c.samplingProvider,
 		Logger:           c.logger,
+
+		GRPCHandler:             c.spanHandlers.GRPCHandler,
+		GRPCWeb:                 options.HTTP.GRPCWeb,
+		Authenticator:           c.authenticator,
+		MaxReceiveMessageLength: options.GRPC.MaxReceiveMessageLength,
+		H2C:                     options.HTTP.H2C,
+		CORS:                    options.HTTP.CORS,
 	})
 	if err != nil {
 		return fmt.Errorf("could not start HTTP server: %w", err)
@@ -156,19 +210,47 @@ func (c *Collector) Start(options *flags.CollectorOptions) error {
 		c.zipkinReceiver = zipkinReceiver
 	}
 
+	if options.Datadog.HTTPHostPort == "" {
+		c.logger.Info("Not listening for Datadog traffic, port not configured")
+	} else {
+		datadogServer, err := handler.StartDatadogReceiver(options, c.logger, c.spanProcessor, c.tenancyMgr)
+		if err != nil {
+			return fmt.Errorf("could not start Datadog receiver: %w", err)
+		}
+		c.datadogServer = datadogServer
+	}
+
 	if options.OTLP.Enabled {
-		otlpReceiver, err := handler.StartOTLPReceiver(options, c.logger, c.spanProcessor, c.tenancyMgr)
+		otlpReceiver, err := handler.StartOTLPReceiver(options, c.logger, c.spanProcessor, c.tenancyMgr, c.authenticator)
 		if err != nil {
 			return fmt.Errorf("could not start OTLP receiver: %w", err)
 		}
 		c.otlpReceiver = otlpReceiver
 	}
 
+	if options.Registry.Type != "" {
+		reporter, err := registry.NewReporter(options.Registry, c.registryStatus, c.logger)
+		if err != nil {
+			return fmt.Errorf("could not start service registry reporter: %w", err)
+		}
+		reporter.Start()
+		c.registryReporter = reporter
+	}
+
 	c.publishOpts(options)
 
 	return nil
 }
 
+// registryStatus returns the collector health published to the service registry.
+func (c *Collector) registryStatus() registry.Status {
+	status := registry.Status{Healthy: c.hCheck.Get() == healthcheck.Ready}
+	if sp, ok := c.spanProcessor.(*spanProcessor); ok {
+		status.QueueLength, status.QueueCapacity = sp.queueStatus()
+	}
+	return status
+}
+
 func (*Collector) publishOpts(cOpts *flags.CollectorOptions) {
 	safeexpvar.SetInt(metricNumWorkers, int64(cOpts.NumWorkers))
 	safeexpvar.SetInt(metricQueueSize, int64(cOpts.QueueSize))
@@ -176,6 +258,11 @@ func (*Collector) publishOpts(cOpts *flags.CollectorOptions) {
 
 // Close the component and all its underlying dependencies
 func (c *Collector) Close() error {
+	// Steer traffic away before the servers stop accepting spans
+	if c.registryReporter != nil {
+		_ = c.registryReporter.Close()
+	}
+
 	// Stop gRPC server
 	if c.grpcServer != nil {
 		c.grpcServer.GracefulStop()
@@ -199,6 +286,15 @@ func (c *Collector) Close() error {
 		defer cancel()
 	}
 
+	// Stop Datadog receiver
+	if c.datadogServer != nil {
+		timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := c.datadogServer.Shutdown(timeout); err != nil {
+			c.logger.Fatal("failed to stop the Datadog receiver", zap.Error(err))
+		}
+		defer cancel()
+	}
+
 	// Stop OpenTelemetry OTLP receiver
 	if c.otlpReceiver != nil {
 		timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -208,6 +304,10 @@ func (c *Collector) Close() error {
 		defer cancel()
 	}
 
+	if c.authenticator != nil {
+		_ = c.authenticator.Close()
+	}
+
 	if err := c.spanProcessor.Close(); err != nil {
 		c.logger.Error("failed to close span processor.", zap.Error(err))
 	}
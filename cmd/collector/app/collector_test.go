@@ -16,8 +16,12 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"expvar"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -28,6 +32,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/registry"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
@@ -94,6 +99,54 @@ func TestNewCollector(t *testing.T) {
 	require.NoError(t, c.Close())
 }
 
+func TestCollectorPublishesToRegistry(t *testing.T) {
+	var reports []registry.Report
+	var mu sync.Mutex
+	registryServer := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var report registry.Report
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		mu.Lock()
+		reports = append(reports, report)
+		mu.Unlock()
+	}))
+	defer registryServer.Close()
+
+	hc := healthcheck.New()
+	hc.Ready()
+	baseMetrics := metricstest.NewFactory(time.Hour)
+	defer baseMetrics.Backend.Stop()
+	c := New(&CollectorParams{
+		ServiceName:      "collector",
+		Logger:           zap.NewNop(),
+		MetricsFactory:   baseMetrics,
+		SpanWriter:       &fakeSpanWriter{},
+		SamplingProvider: &mockSamplingProvider{},
+		HealthCheck:      hc,
+		TenancyMgr:       &tenancy.Manager{},
+	})
+
+	collectorOpts := optionsForEphemeralPorts()
+	collectorOpts.QueueSize = 10
+	collectorOpts.Registry = registry.Options{
+		Type:              registry.TypeHTTP,
+		Endpoint:          registryServer.URL,
+		InstanceID:        "collector-1",
+		Interval:          time.Hour,
+		PressureThreshold: 0.8,
+	}
+	require.NoError(t, c.Start(collectorOpts))
+	require.NoError(t, c.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reports, 2)
+	assert.True(t, reports[0].Healthy)
+	assert.Equal(t, "collector-1", reports[0].InstanceID)
+	assert.Equal(t, 10, reports[0].QueueCapacity)
+	assert.Equal(t, 100, reports[0].Weight)
+	assert.False(t, reports[1].Healthy, "the collector is reported unhealthy when closing")
+}
+
 func TestCollector_StartErrors(t *testing.T) {
 	run := func(name string, options *flags.CollectorOptions, expErr string) {
 		t.Run(name, func(t *testing.T) {
@@ -185,6 +238,14 @@ func TestCollector_PublishOpts(t *testing.T) {
 }
 
 func TestAggregator(t *testing.T) {
+	for _, observationPoint := range []string{"", flags.SamplingObservationProcessed, flags.SamplingObservationReceived} {
+		t.Run(observationPoint, func(t *testing.T) {
+			testAggregator(t, observationPoint)
+		})
+	}
+}
+
+func testAggregator(t *testing.T, observationPoint string) {
 	// prepare
 	hc := healthcheck.New()
 	logger := zap.NewNop()
@@ -208,6 +269,7 @@ func TestAggregator(t *testing.T) {
 	collectorOpts := optionsForEphemeralPorts()
 	collectorOpts.NumWorkers = 10
 	collectorOpts.QueueSize = 10
+	collectorOpts.SamplingObservationPoint = observationPoint
 	require.NoError(t, c.Start(collectorOpts))
 
 	// assert that aggregator was added to the collector
@@ -231,6 +293,10 @@ func TestAggregator(t *testing.T) {
 	}
 	_, err := c.spanProcessor.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
 	require.NoError(t, err)
+	if observationPoint == flags.SamplingObservationReceived {
+		// received spans are observed before they are queued
+		assert.EqualValues(t, 1, agg.callCount.Load(), "aggregator was used before the queue")
+	}
 	require.NoError(t, c.Close())
 
 	// spans are processed by background workers, so we may need to wait
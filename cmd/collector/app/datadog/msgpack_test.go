@@ -0,0 +1,161 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package datadog
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeMsgpack encodes test payloads, using the largest formats of each type
+// unless the values are small, like the encoders of the Datadog tracers.
+func encodeMsgpack(v any) []byte {
+	var b []byte
+	header := func(fix byte, fixMax int, t16 byte, n int) {
+		switch {
+		case n <= fixMax:
+			b = append(b, fix|byte(n))
+		default:
+			b = append(b, t16+1)
+			b = binary.BigEndian.AppendUint32(b, uint32(n))
+		}
+	}
+	switch x := v.(type) {
+	case nil:
+		b = append(b, 0xc0)
+	case bool:
+		if x {
+			b = append(b, 0xc3)
+		} else {
+			b = append(b, 0xc2)
+		}
+	case int:
+		b = append(b, 0xd3)
+		b = binary.BigEndian.AppendUint64(b, uint64(x))
+	case uint64:
+		b = append(b, 0xcf)
+		b = binary.BigEndian.AppendUint64(b, x)
+	case float64:
+		b = append(b, 0xcb)
+		b = binary.BigEndian.AppendUint64(b, math.Float64bits(x))
+	case string:
+		header(0xa0, 31, 0xda, len(x))
+		b = append(b, x...)
+	case []any:
+		header(0x90, 15, 0xdc, len(x))
+		for _, e := range x {
+			b = append(b, encodeMsgpack(e)...)
+		}
+	case map[string]any:
+		header(0x80, 15, 0xde, len(x))
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b = append(b, encodeMsgpack(k)...)
+			b = append(b, encodeMsgpack(x[k])...)
+		}
+	default:
+		panic("unsupported type")
+	}
+	return b
+}
+
+func TestDecodeMsgpack(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected any
+	}{
+		{name: "nil", data: []byte{0xc0}, expected: nil},
+		{name: "false", data: []byte{0xc2}, expected: false},
+		{name: "true", data: []byte{0xc3}, expected: true},
+		{name: "positive fixint", data: []byte{0x7f}, expected: int64(127)},
+		{name: "negative fixint", data: []byte{0xff}, expected: int64(-1)},
+		{name: "uint8", data: []byte{0xcc, 0xff}, expected: uint64(255)},
+		{name: "uint16", data: []byte{0xcd, 0x01, 0x00}, expected: uint64(256)},
+		{name: "uint32", data: []byte{0xce, 0, 1, 0, 0}, expected: uint64(65536)},
+		{name: "uint64", data: []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, expected: uint64(math.MaxUint64)},
+		{name: "int8", data: []byte{0xd0, 0x80}, expected: int64(-128)},
+		{name: "int16", data: []byte{0xd1, 0xff, 0x00}, expected: int64(-256)},
+		{name: "int32", data: []byte{0xd2, 0xff, 0xff, 0xff, 0x00}, expected: int64(-256)},
+		{name: "int64", data: []byte{0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}, expected: int64(-256)},
+		{name: "float32", data: []byte{0xca, 0x3f, 0xc0, 0, 0}, expected: 1.5},
+		{name: "float64", data: []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}, expected: 1.5},
+		{name: "fixstr", data: []byte{0xa2, 'o', 'k'}, expected: "ok"},
+		{name: "str8", data: []byte{0xd9, 2, 'o', 'k'}, expected: "ok"},
+		{name: "str16", data: []byte{0xda, 0, 2, 'o', 'k'}, expected: "ok"},
+		{name: "str32", data: []byte{0xdb, 0, 0, 0, 2, 'o', 'k'}, expected: "ok"},
+		{name: "bin8", data: []byte{0xc4, 2, 1, 2}, expected: []byte{1, 2}},
+		{name: "bin16", data: []byte{0xc5, 0, 1, 1}, expected: []byte{1}},
+		{name: "bin32", data: []byte{0xc6, 0, 0, 0, 1, 1}, expected: []byte{1}},
+		{name: "fixarray", data: []byte{0x92, 0x01, 0xc0}, expected: []any{int64(1), nil}},
+		{name: "array16", data: []byte{0xdc, 0, 1, 0x01}, expected: []any{int64(1)}},
+		{name: "array32", data: []byte{0xdd, 0, 0, 0, 1, 0x01}, expected: []any{int64(1)}},
+		{name: "fixmap", data: []byte{0x81, 0xa1, 'k', 0x01}, expected: map[string]any{"k": int64(1)}},
+		{name: "map16", data: []byte{0xde, 0, 1, 0xa1, 'k', 0x01}, expected: map[string]any{"k": int64(1)}},
+		{name: "map32", data: []byte{0xdf, 0, 0, 0, 1, 0xa1, 'k', 0x01}, expected: map[string]any{"k": int64(1)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := decodeMsgpack(tc.data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func TestDecodeMsgpackErrors(t *testing.T) {
+	nested := []byte(strings.Repeat("\x91", maxDepth+1) + "\xc0")
+	testCases := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{name: "empty", data: nil, err: errTruncated.Error()},
+		{name: "truncated number", data: []byte{0xcd, 0x01}, err: errTruncated.Error()},
+		{name: "truncated string", data: []byte{0xa2, 'o'}, err: errTruncated.Error()},
+		{name: "truncated string length", data: []byte{0xda, 0}, err: errTruncated.Error()},
+		{name: "corrupt length", data: []byte{0xdd, 0xff, 0xff, 0xff, 0xff, 0x01}, err: errTruncated.Error()},
+		{name: "corrupt binary length", data: []byte{0xc4, 0x05}, err: errTruncated.Error()},
+		{name: "truncated array", data: []byte{0x92, 0x01}, err: errTruncated.Error()},
+		{name: "truncated map key", data: []byte{0x81}, err: errTruncated.Error()},
+		{name: "truncated map value", data: []byte{0x81, 0xa1, 'k'}, err: errTruncated.Error()},
+		{name: "corrupt array16 length", data: []byte{0xdc, 0xff}, err: errTruncated.Error()},
+		{name: "corrupt map16 length", data: []byte{0xde, 0x00, 0x05}, err: errTruncated.Error()},
+		{name: "corrupt str8 length", data: []byte{0xd9}, err: errTruncated.Error()},
+		{name: "trailing bytes", data: []byte{0xc0, 0xc0}, err: "msgpack: 1 trailing bytes"},
+		{name: "extension", data: []byte{0xd4, 0x01, 0x01}, err: "msgpack: unsupported type 0xd4"},
+		{name: "integer map key", data: []byte{0x81, 0x01, 0x01}, err: "msgpack: map key of type int64 is not a string"},
+		{name: "nesting", data: nested, err: "msgpack: maximum nesting depth exceeded"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeMsgpack(tc.data)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestEncodeMsgpackRoundTrip(t *testing.T) {
+	long := strings.Repeat("x", 40)
+	value := map[string]any{
+		"array": []any{nil, true, false, int64(-5), uint64(math.MaxUint64), 2.5, long},
+		"map":   map[string]any{"nested": "value"},
+	}
+	decoded, err := decodeMsgpack(encodeMsgpack(map[string]any{
+		"array": []any{nil, true, false, -5, uint64(math.MaxUint64), 2.5, long},
+		"map":   map[string]any{"nested": "value"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, value, decoded)
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package datadog translates the trace payloads that Datadog tracers send to the
+// Datadog agent into OTLP traces, so that the collector can stand in for the agent
+// while applications are being migrated.
+package datadog
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+)
+
+const (
+	// scopeName is the instrumentation scope of the translated spans.
+	scopeName = "datadog"
+
+	// ResourceAttribute is the span attribute holding the Datadog resource,
+	// e.g. the route of a web request or the normalized query of a database call.
+	ResourceAttribute = "dd.span.Resource"
+
+	// traceIDHighKey holds the hex encoded upper 64 bits of 128-bit trace IDs.
+	traceIDHighKey = "_dd.p.tid"
+	spanKindKey    = "span.kind"
+	errorMsgKey    = "error.msg"
+)
+
+// TracerInfo describes the tracer that sent a payload, as found in the
+// Datadog-Meta-* headers of the request.
+type TracerInfo struct {
+	Language        string
+	LanguageVersion string
+	TracerVersion   string
+}
+
+// TracesFromV04 translates a v0.4 payload, an array of traces that are arrays of spans.
+func TracesFromV04(data []byte, info TracerInfo) (ptrace.Traces, error) {
+	payload, err := decodeMsgpack(data)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	traces, err := asArray(payload, "payload")
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	t := newTranslator(tracerPayload{TracerInfo: info})
+	for _, trace := range traces {
+		spans, err := asArray(trace, "trace")
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		if err := t.addSpans(spans); err != nil {
+			return ptrace.Traces{}, err
+		}
+	}
+	return t.traces, nil
+}
+
+// TracesFromV07 translates a v0.7 payload, a tracer payload with chunks of spans
+// and the details of the tracer and the host it runs on.
+func TracesFromV07(data []byte, info TracerInfo) (ptrace.Traces, error) {
+	decoded, err := decodeMsgpack(data)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	fields, err := asMap(decoded, "payload")
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	payload := tracerPayload{TracerInfo: info}
+	if v := asString(fields["language_name"]); v != "" {
+		payload.Language = v
+	}
+	if v := asString(fields["language_version"]); v != "" {
+		payload.LanguageVersion = v
+	}
+	if v := asString(fields["tracer_version"]); v != "" {
+		payload.TracerVersion = v
+	}
+	payload.containerID = asString(fields["container_id"])
+	payload.env = asString(fields["env"])
+	payload.hostname = asString(fields["hostname"])
+	payload.appVersion = asString(fields["app_version"])
+	if fields["tags"] != nil {
+		if payload.tags, err = asMap(fields["tags"], "tags"); err != nil {
+			return ptrace.Traces{}, err
+		}
+	}
+
+	t := newTranslator(payload)
+	if fields["chunks"] == nil {
+		return t.traces, nil
+	}
+	chunks, err := asArray(fields["chunks"], "chunks")
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	for _, chunk := range chunks {
+		chunkFields, err := asMap(chunk, "chunk")
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		if chunkFields["spans"] == nil {
+			continue
+		}
+		spans, err := asArray(chunkFields["spans"], "spans")
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		if err := t.addSpans(spans); err != nil {
+			return ptrace.Traces{}, err
+		}
+	}
+	return t.traces, nil
+}
+
+type tracerPayload struct {
+	TracerInfo
+	containerID string
+	env         string
+	hostname    string
+	appVersion  string
+	tags        map[string]any
+}
+
+// translator appends spans to the scope of the resource of their service.
+type translator struct {
+	payload tracerPayload
+	traces  ptrace.Traces
+	scopes  map[string]ptrace.ScopeSpans
+}
+
+func newTranslator(payload tracerPayload) *translator {
+	return &translator{
+		payload: payload,
+		traces:  ptrace.NewTraces(),
+		scopes:  make(map[string]ptrace.ScopeSpans),
+	}
+}
+
+func (t *translator) scope(service string) ptrace.ScopeSpans {
+	if scope, ok := t.scopes[service]; ok {
+		return scope
+	}
+	rs := t.traces.ResourceSpans().AppendEmpty()
+	attrs := rs.Resource().Attributes()
+	attrs.PutStr(string(semconv.ServiceNameKey), service)
+	putNonEmpty(attrs, string(semconv.TelemetrySDKLanguageKey), t.payload.Language)
+	putNonEmpty(attrs, string(semconv.ProcessRuntimeVersionKey), t.payload.LanguageVersion)
+	putNonEmpty(attrs, string(semconv.TelemetrySDKVersionKey), t.payload.TracerVersion)
+	putNonEmpty(attrs, string(semconv.ContainerIDKey), t.payload.containerID)
+	putNonEmpty(attrs, string(semconv.DeploymentEnvironmentKey), t.payload.env)
+	putNonEmpty(attrs, string(semconv.HostNameKey), t.payload.hostname)
+	putNonEmpty(attrs, string(semconv.ServiceVersionKey), t.payload.appVersion)
+	for _, key := range sortedKeys(t.payload.tags) {
+		attrs.PutStr(key, asString(t.payload.tags[key]))
+	}
+	scope := rs.ScopeSpans().AppendEmpty()
+	scope.Scope().SetName(scopeName)
+	scope.Scope().SetVersion(t.payload.TracerVersion)
+	t.scopes[service] = scope
+	return scope
+}
+
+func (t *translator) addSpans(spans []any) error {
+	for _, s := range spans {
+		fields, err := asMap(s, "span")
+		if err != nil {
+			return err
+		}
+		if err := t.addSpan(fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *translator) addSpan(fields map[string]any) error {
+	var meta, spanMetrics map[string]any
+	var err error
+	if fields["meta"] != nil {
+		if meta, err = asMap(fields["meta"], "meta"); err != nil {
+			return err
+		}
+	}
+	if fields["metrics"] != nil {
+		if spanMetrics, err = asMap(fields["metrics"], "metrics"); err != nil {
+			return err
+		}
+	}
+
+	span := t.scope(asString(fields["service"])).Spans().AppendEmpty()
+	tid, err := traceID(asUint64(fields["trace_id"]), asString(meta[traceIDHighKey]))
+	if err != nil {
+		return err
+	}
+	span.SetTraceID(tid)
+	span.SetSpanID(spanID(asUint64(fields["span_id"])))
+	if parentID := asUint64(fields["parent_id"]); parentID != 0 {
+		span.SetParentSpanID(spanID(parentID))
+	}
+	span.SetName(asString(fields["name"]))
+	start := asInt64(fields["start"])
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, start)))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, start+asInt64(fields["duration"]))))
+	span.SetKind(spanKind(asString(meta[spanKindKey]), asString(fields["type"])))
+	if asInt64(fields["error"]) != 0 {
+		span.Status().SetCode(ptrace.StatusCodeError)
+		span.Status().SetMessage(asString(meta[errorMsgKey]))
+	}
+
+	attrs := span.Attributes()
+	putNonEmpty(attrs, ResourceAttribute, asString(fields["resource"]))
+	for _, key := range sortedKeys(meta) {
+		if key == traceIDHighKey || key == spanKindKey {
+			continue
+		}
+		attrs.PutStr(key, asString(meta[key]))
+	}
+	for _, key := range sortedKeys(spanMetrics) {
+		attrs.PutDouble(key, asFloat64(spanMetrics[key]))
+	}
+	return nil
+}
+
+func traceID(low uint64, high string) (pcommon.TraceID, error) {
+	var id [16]byte
+	if high != "" {
+		b, err := hex.DecodeString(high)
+		if err != nil || len(b) != 8 {
+			return id, fmt.Errorf("invalid %s tag %q", traceIDHighKey, high)
+		}
+		copy(id[:8], b)
+	}
+	binary.BigEndian.PutUint64(id[8:], low)
+	return id, nil
+}
+
+func spanID(id uint64) pcommon.SpanID {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], id)
+	return b
+}
+
+func spanKind(kind, spanType string) ptrace.SpanKind {
+	switch kind {
+	case "server":
+		return ptrace.SpanKindServer
+	case "client":
+		return ptrace.SpanKindClient
+	case "producer":
+		return ptrace.SpanKindProducer
+	case "consumer":
+		return ptrace.SpanKindConsumer
+	case "internal":
+		return ptrace.SpanKindInternal
+	}
+	// older tracers only set the type of the span
+	switch spanType {
+	case "web":
+		return ptrace.SpanKindServer
+	case "http", "db", "cache":
+		return ptrace.SpanKindClient
+	default:
+		return ptrace.SpanKindUnspecified
+	}
+}
+
+func putNonEmpty(attrs pcommon.Map, key, value string) {
+	if value != "" {
+		attrs.PutStr(key, value)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func asArray(v any, what string) ([]any, error) {
+	a, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: expected an array, got %T", what, v)
+	}
+	return a, nil
+}
+
+func asMap(v any, what string) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: expected a map, got %T", what, v)
+	}
+	return m, nil
+}
+
+func asString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+// asUint64 returns the value of an ID, which encoders write as signed or unsigned integers.
+func asUint64(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		return uint64(n)
+	case float64:
+		return uint64(n)
+	default:
+		return 0
+	}
+}
+
+func asInt64(v any) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case uint64:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case bool:
+		if n {
+			return 1
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+func asFloat64(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package datadog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	testStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	webSpan = map[string]any{
+		"service":   "web-store",
+		"name":      "rack.request",
+		"resource":  "GET /cart",
+		"trace_id":  uint64(0xfedcba9876543210),
+		"span_id":   1,
+		"parent_id": 0,
+		"start":     int(testStart.UnixNano()),
+		"duration":  int(50 * time.Millisecond),
+		"error":     1,
+		"type":      "web",
+		"meta": map[string]any{
+			"_dd.p.tid":   "0123456789abcdef",
+			"error.msg":   "cart not found",
+			"http.method": "GET",
+		},
+		"metrics": map[string]any{
+			"_sampling_priority_v1": 1,
+			"http.status_code":      404.0,
+		},
+	}
+	dbSpan = map[string]any{
+		"service":   "web-store-db",
+		"name":      "postgres.query",
+		"resource":  "SELECT * FROM carts WHERE id = ?",
+		"trace_id":  uint64(0xfedcba9876543210),
+		"span_id":   2,
+		"parent_id": 1,
+		"start":     int(testStart.Add(time.Millisecond).UnixNano()),
+		"duration":  int(10 * time.Millisecond),
+		"type":      "sql",
+		"meta": map[string]any{
+			"_dd.p.tid": "0123456789abcdef",
+			"span.kind": "client",
+		},
+	}
+)
+
+func TestTracesFromV04(t *testing.T) {
+	payload := encodeMsgpack([]any{
+		[]any{webSpan, dbSpan},
+		[]any{map[string]any{"service": "web-store", "name": "worker.job", "trace_id": 7, "span_id": 8}},
+	})
+	traces, err := TracesFromV04(payload, TracerInfo{Language: "ruby", LanguageVersion: "3.3.0", TracerVersion: "1.20.0"})
+	require.NoError(t, err)
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+
+	web := traces.ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{
+		"service.name":            "web-store",
+		"telemetry.sdk.language":  "ruby",
+		"process.runtime.version": "3.3.0",
+		"telemetry.sdk.version":   "1.20.0",
+	}, web.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, web.ScopeSpans().Len())
+	scope := web.ScopeSpans().At(0)
+	assert.Equal(t, "datadog", scope.Scope().Name())
+	assert.Equal(t, "1.20.0", scope.Scope().Version())
+	require.Equal(t, 2, scope.Spans().Len())
+
+	span := scope.Spans().At(0)
+	assert.Equal(t, pcommon.TraceID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10}, span.TraceID())
+	assert.Equal(t, pcommon.SpanID{0, 0, 0, 0, 0, 0, 0, 1}, span.SpanID())
+	assert.True(t, span.ParentSpanID().IsEmpty())
+	assert.Equal(t, "rack.request", span.Name())
+	assert.Equal(t, ptrace.SpanKindServer, span.Kind())
+	assert.Equal(t, testStart, span.StartTimestamp().AsTime())
+	assert.Equal(t, testStart.Add(50*time.Millisecond), span.EndTimestamp().AsTime())
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "cart not found", span.Status().Message())
+	assert.Equal(t, map[string]any{
+		"dd.span.Resource":      "GET /cart",
+		"error.msg":             "cart not found",
+		"http.method":           "GET",
+		"_sampling_priority_v1": 1.0,
+		"http.status_code":      404.0,
+	}, span.Attributes().AsRaw())
+
+	job := scope.Spans().At(1)
+	assert.Equal(t, "worker.job", job.Name())
+	assert.Equal(t, pcommon.TraceID{15: 7}, job.TraceID())
+	assert.Equal(t, ptrace.SpanKindUnspecified, job.Kind())
+	assert.Equal(t, ptrace.StatusCodeUnset, job.Status().Code())
+	assert.Equal(t, map[string]any{}, job.Attributes().AsRaw())
+
+	db := traces.ResourceSpans().At(1)
+	assert.Equal(t, "web-store-db", db.Resource().Attributes().AsRaw()["service.name"])
+	dbSpan := db.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, span.TraceID(), dbSpan.TraceID())
+	assert.Equal(t, span.SpanID(), dbSpan.ParentSpanID())
+	assert.Equal(t, ptrace.SpanKindClient, dbSpan.Kind())
+	assert.Equal(t, map[string]any{"dd.span.Resource": "SELECT * FROM carts WHERE id = ?"}, dbSpan.Attributes().AsRaw())
+}
+
+func TestTracesFromV07(t *testing.T) {
+	payload := encodeMsgpack(map[string]any{
+		"container_id":     "abc123",
+		"language_name":    "python",
+		"language_version": "3.12.1",
+		"tracer_version":   "2.8.0",
+		"runtime_id":       "e5f1",
+		"env":              "staging",
+		"hostname":         "host-1",
+		"app_version":      "1.4.2",
+		"tags":             map[string]any{"_dd.git.commit.sha": "f00d"},
+		"chunks": []any{
+			map[string]any{"priority": 1, "origin": "", "spans": []any{webSpan}},
+			map[string]any{"priority": 1, "dropped_trace": false},
+		},
+	})
+	traces, err := TracesFromV07(payload, TracerInfo{Language: "ruby", LanguageVersion: "3.3.0", TracerVersion: "1.20.0"})
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.ResourceSpans().Len())
+	rs := traces.ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{
+		"service.name":            "web-store",
+		"telemetry.sdk.language":  "python",
+		"process.runtime.version": "3.12.1",
+		"telemetry.sdk.version":   "2.8.0",
+		"container.id":            "abc123",
+		"deployment.environment":  "staging",
+		"host.name":               "host-1",
+		"service.version":         "1.4.2",
+		"_dd.git.commit.sha":      "f00d",
+	}, rs.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rs.ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, "rack.request", rs.ScopeSpans().At(0).Spans().At(0).Name())
+
+	traces, err = TracesFromV07(encodeMsgpack(map[string]any{}), TracerInfo{Language: "go"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, traces.SpanCount())
+}
+
+func TestSpanKind(t *testing.T) {
+	testCases := []struct {
+		kind     string
+		spanType string
+		expected ptrace.SpanKind
+	}{
+		{kind: "server", spanType: "http", expected: ptrace.SpanKindServer},
+		{kind: "client", expected: ptrace.SpanKindClient},
+		{kind: "producer", expected: ptrace.SpanKindProducer},
+		{kind: "consumer", expected: ptrace.SpanKindConsumer},
+		{kind: "internal", spanType: "web", expected: ptrace.SpanKindInternal},
+		{spanType: "web", expected: ptrace.SpanKindServer},
+		{spanType: "http", expected: ptrace.SpanKindClient},
+		{spanType: "db", expected: ptrace.SpanKindClient},
+		{spanType: "cache", expected: ptrace.SpanKindClient},
+		{spanType: "custom", expected: ptrace.SpanKindUnspecified},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.kind+"/"+tc.spanType, func(t *testing.T) {
+			assert.Equal(t, tc.expected, spanKind(tc.kind, tc.spanType))
+		})
+	}
+}
+
+func TestValueConversions(t *testing.T) {
+	assert.Equal(t, "bytes", asString([]byte("bytes")))
+	assert.Equal(t, "", asString(nil))
+	assert.Equal(t, "42", asString(int64(42)))
+	assert.Equal(t, uint64(3), asUint64(3.0))
+	assert.Equal(t, uint64(0), asUint64("3"))
+	assert.Equal(t, int64(3), asInt64(uint64(3)))
+	assert.Equal(t, int64(3), asInt64(3.0))
+	assert.Equal(t, int64(1), asInt64(true))
+	assert.Equal(t, int64(0), asInt64(false))
+	assert.Equal(t, int64(0), asInt64("3"))
+	assert.InDelta(t, 3.0, asFloat64(uint64(3)), 0)
+	assert.InDelta(t, 0.0, asFloat64("3"), 0)
+}
+
+func TestTranslationErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		payload any
+		v07     bool
+		err     string
+	}{
+		{name: "v0.4 payload", payload: map[string]any{}, err: "invalid payload: expected an array, got map[string]interface {}"},
+		{name: "v0.4 trace", payload: []any{"trace"}, err: "invalid trace: expected an array, got string"},
+		{name: "v0.4 span", payload: []any{[]any{1}}, err: "invalid span: expected a map, got int64"},
+		{name: "meta", payload: []any{[]any{map[string]any{"meta": 1}}}, err: "invalid meta: expected a map, got int64"},
+		{name: "metrics", payload: []any{[]any{map[string]any{"metrics": "x"}}}, err: "invalid metrics: expected a map, got string"},
+		{
+			name:    "trace ID",
+			payload: []any{[]any{map[string]any{"meta": map[string]any{"_dd.p.tid": "xyz"}}}},
+			err:     `invalid _dd.p.tid tag "xyz"`,
+		},
+		{name: "v0.7 payload", payload: []any{}, v07: true, err: "invalid payload: expected a map, got []interface {}"},
+		{name: "v0.7 tags", payload: map[string]any{"tags": 1}, v07: true, err: "invalid tags: expected a map, got int64"},
+		{name: "v0.7 chunks", payload: map[string]any{"chunks": 1}, v07: true, err: "invalid chunks: expected an array, got int64"},
+		{name: "v0.7 chunk", payload: map[string]any{"chunks": []any{1}}, v07: true, err: "invalid chunk: expected a map, got int64"},
+		{
+			name:    "v0.7 spans",
+			payload: map[string]any{"chunks": []any{map[string]any{"spans": 1}}},
+			v07:     true,
+			err:     "invalid spans: expected an array, got int64",
+		},
+		{
+			name:    "v0.7 span",
+			payload: map[string]any{"chunks": []any{map[string]any{"spans": []any{1}}}},
+			v07:     true,
+			err:     "invalid span: expected a map, got int64",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			translate := TracesFromV04
+			if tc.v07 {
+				translate = TracesFromV07
+			}
+			_, err := translate(encodeMsgpack(tc.payload), TracerInfo{})
+			require.EqualError(t, err, tc.err)
+		})
+	}
+
+	_, err := TracesFromV04([]byte{0xc1}, TracerInfo{})
+	require.EqualError(t, err, "msgpack: unsupported type 0xc1")
+	_, err = TracesFromV07([]byte{0xc1}, TracerInfo{})
+	require.EqualError(t, err, "msgpack: unsupported type 0xc1")
+}
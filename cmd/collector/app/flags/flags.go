@@ -18,11 +18,18 @@ package flags
 import (
 	"flag"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/registry"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
@@ -31,11 +38,29 @@ import (
 )
 
 const (
-	flagDynQueueSizeMemory     = "collector.queue-size-memory"
-	flagNumWorkers             = "collector.num-workers"
-	flagQueueSize              = "collector.queue-size"
-	flagCollectorTags          = "collector.tags"
-	flagSpanSizeMetricsEnabled = "collector.enable-span-size-metrics"
+	flagDynQueueSizeMemory      = "collector.queue-size-memory"
+	flagNumWorkers              = "collector.num-workers"
+	flagQueueSize               = "collector.queue-size"
+	flagWorkersAutoTuning       = "collector.workers-auto-tuning.enabled"
+	flagWorkersMin              = "collector.workers-auto-tuning.min-workers"
+	flagWorkersMax              = "collector.workers-auto-tuning.max-workers"
+	flagWorkersTuningInterval   = "collector.workers-auto-tuning.interval"
+	flagCollectorTags           = "collector.tags"
+	flagSpanSizeMetricsEnabled  = "collector.enable-span-size-metrics"
+	flagIngestLatencySampling   = "collector.ingest-latency-sampling"
+	flagDroppedSpansLogSampling = "collector.dropped-spans-log-sampling"
+	flagProcessors              = "collector.processors"
+	flagBaggageKeys             = "collector.baggage-keys"
+	flagTraceStateKeys          = "collector.tracestate-keys"
+	flagProvenanceAttributes    = "collector.provenance.attributes"
+	flagProvenanceInstance      = "collector.provenance.instance"
+	flagResourceAttributes      = "collector.resource-detection.attributes"
+	flagResourceFiles           = "collector.resource-detection.files"
+	flagUTF8Repair              = "collector.utf8-repair"
+	flagSamplingObservation     = "collector.sampling.observation-point"
+	flagSpanKindPolicies        = "collector.span-kind-policies"
+	flagQueueFullPolicies       = "collector.queue-full-policies"
+	flagQueueFullBlockTimeout   = "collector.queue-full-block-timeout"
 
 	flagSuffixHostPort = "host-port"
 
@@ -43,28 +68,88 @@ const (
 	flagSuffixHTTPReadHeaderTimeout = "read-header-timeout"
 	flagSuffixHTTPIdleTimeout       = "idle-timeout"
 
+	flagCollectorHTTPH2C     = "collector.http-server.h2c"
+	flagCollectorHTTPGRPCWeb = "collector.http-server.grpc-web"
+
 	flagSuffixGRPCMaxReceiveMessageLength = "max-message-size"
 	flagSuffixGRPCMaxConnectionAge        = "max-connection-age"
 	flagSuffixGRPCMaxConnectionAgeGrace   = "max-connection-age-grace"
 
 	flagCollectorOTLPEnabled = "collector.otlp.enabled"
 
+	flagOTLPHTTPMaxRequestBodySize    = "collector.otlp.http.max-request-body-size"
+	flagOTLPHTTPCompressionAlgorithms = "collector.otlp.http.compression-algorithms"
+
 	flagZipkinHTTPHostPort     = "collector.zipkin.host-port"
 	flagZipkinKeepAliveEnabled = "collector.zipkin.keep-alive"
+	flagDatadogHTTPHostPort    = "collector.datadog.host-port"
+
+	// ProvenanceInstance is the provenance attribute identifying the collector instance that received a span
+	ProvenanceInstance = "instance"
+	// ProvenanceProtocol is the provenance attribute with the transport and format a span was received with
+	ProvenanceProtocol = "protocol"
+	// ProvenanceTimestamp is the provenance attribute with the time a span was received
+	ProvenanceTimestamp = "timestamp"
+	// ProvenanceVersion is the provenance attribute with the version of the collector that received a span
+	ProvenanceVersion = "version"
+
+	// SamplingObservationReceived makes the adaptive sampling aggregator observe the spans as soon as they are received
+	SamplingObservationReceived = "received"
+	// SamplingObservationProcessed makes the adaptive sampling aggregator observe the spans after they are written
+	SamplingObservationProcessed = "processed"
 
 	// DefaultNumWorkers is the default number of workers consuming from the processor queue
 	DefaultNumWorkers = 50
+	// DefaultMaxWorkers is the default maximum number of workers when they are auto-tuned
+	DefaultMaxWorkers = 500
+	// DefaultWorkersTuningInterval is the default interval between two adjustments of the auto-tuned workers
+	DefaultWorkersTuningInterval = 10 * time.Second
 	// DefaultQueueSize is the size of the processor's queue
 	DefaultQueueSize = 2000
+	// DefaultQueueFullBlockTimeout is the default longest time a receiver waits for room in the full queue
+	DefaultQueueFullBlockTimeout = time.Second
 	// DefaultGRPCMaxReceiveMessageLength is the default max receivable message size for the gRPC Collector
 	DefaultGRPCMaxReceiveMessageLength = 4 * 1024 * 1024
+	// DefaultOTLPHTTPMaxRequestBodySize is the default max size of the requests of the OTLP/HTTP receiver
+	DefaultOTLPHTTPMaxRequestBodySize = 20 * 1024 * 1024
+)
+
+// SpanKinds are the values of the span.kind tag the span kind policies apply to, spans without
+// the tag being of the unspecified kind.
+var SpanKinds = []string{"server", "client", "producer", "consumer", "internal", "unspecified"}
+
+// QueueFullPolicy is how a receiver handles the spans it receives while the queue is full.
+type QueueFullPolicy string
+
+const (
+	// QueueFullDrop drops the spans, the receiver still reporting success to the client
+	QueueFullDrop QueueFullPolicy = "drop"
+	// QueueFullBlock makes the receiver wait for room in the queue, and drop the spans if there is
+	// still none after the block timeout, which pushes back on the clients
+	QueueFullBlock QueueFullPolicy = "block"
+	// QueueFullFail makes the receiver fail the request with a retryable error,
+	// e.g. RESOURCE_EXHAUSTED for gRPC
+	QueueFullFail QueueFullPolicy = "fail"
 )
 
+// QueueFullReceivers are the receivers, identified by the format of their spans, the queue full policies apply to.
+var QueueFullReceivers = []processor.SpanFormat{
+	processor.JaegerSpanFormat,
+	processor.ProtoSpanFormat,
+	processor.ZipkinSpanFormat,
+	processor.OTLPSpanFormat,
+	processor.DatadogSpanFormat,
+}
+
+// OTLPHTTPCompressionAlgorithms are the Content-Encoding values the OTLP/HTTP receiver can decompress.
+var OTLPHTTPCompressionAlgorithms = []string{"gzip", "zstd", "zlib", "snappy", "deflate"}
+
 var grpcServerFlagsCfg = serverFlagsConfig{
 	// for legacy reasons the prefixes are different
 	prefix: "collector.grpc-server",
 	tls: tlscfg.ServerFlagsConfig{
-		Prefix: "collector.grpc",
+		Prefix:       "collector.grpc",
+		EnableSPIFFE: true,
 	},
 }
 
@@ -72,7 +157,8 @@ var httpServerFlagsCfg = serverFlagsConfig{
 	// for legacy reasons the prefixes are different
 	prefix: "collector.http-server",
 	tls: tlscfg.ServerFlagsConfig{
-		Prefix: "collector.http",
+		Prefix:       "collector.http",
+		EnableSPIFFE: true,
 	},
 }
 
@@ -97,7 +183,12 @@ var otlpServerFlagsCfg = struct {
 }
 
 var tlsZipkinFlagsConfig = tlscfg.ServerFlagsConfig{
-	Prefix: "collector.zipkin",
+	Prefix:       "collector.zipkin",
+	EnableSPIFFE: true,
+}
+
+var corsCollectorHTTPFlags = corscfg.Flags{
+	Prefix: "collector.http-server",
 }
 
 var corsZipkinFlags = corscfg.Flags{
@@ -114,8 +205,11 @@ type CollectorOptions struct {
 	DynQueueSizeMemory uint
 	// QueueSize is the size of collector's queue
 	QueueSize int
-	// NumWorkers is the number of internal workers in a collector
+	// NumWorkers is the number of internal workers in a collector,
+	// or their initial number when they are auto-tuned
 	NumWorkers int
+	// WorkersAutoTuning configures the adjustment of the number of workers at runtime
+	WorkersAutoTuning WorkersAutoTuning
 	// HTTP section defines options for HTTP server
 	HTTP HTTPOptions
 	// GRPC section defines options for gRPC server
@@ -137,10 +231,81 @@ type CollectorOptions struct {
 		// KeepAlive configures allow Keep-Alive for Zipkin HTTP server
 		KeepAlive bool
 	}
+	// Datadog section defines options for the Datadog agent-compatible HTTP server
+	Datadog struct {
+		// HTTPHostPort is the host:port address that the collector listens in on for traces from Datadog tracers
+		HTTPHostPort string
+	}
 	// CollectorTags is the string representing collector tags to append to each and every span
 	CollectorTags map[string]string
 	// SpanSizeMetricsEnabled determines whether to enable metrics based on processed span size
 	SpanSizeMetricsEnabled bool
+	// IngestLatencySampling is the fraction of spans for which the end-to-end ingest latency is measured
+	IngestLatencySampling float64
+	// DroppedSpansLogSampling is the fraction of dropped spans logged at debug level with the reason why they were dropped
+	DroppedSpansLogSampling float64
+	// Processors are the names of the span processors of processor.DefaultRegistry to run before spans are saved
+	Processors []string
+	// BaggageKeys are the baggage entries carried by spans that are stored as baggage.<key> span tags
+	BaggageKeys []string
+	// TraceStateKeys are the W3C tracestate entries carried by spans that are stored as tracestate.<key> span tags
+	TraceStateKeys []string
+	// Provenance configures the jaeger.ingest.* tags recording how each span was received
+	Provenance struct {
+		// Attributes are the provenance attributes added to each span, e.g. ProvenanceInstance
+		Attributes []string
+		// Instance identifies this collector in the ProvenanceInstance attribute, the hostname by default
+		Instance string
+	}
+	// ResourceAttributes are the deployment attributes, e.g. the cluster or region, added to the
+	// Process tags of the spans that do not have them already
+	ResourceAttributes map[string]string
+	// UTF8Repair is how the strings of spans that are not valid UTF-8 are repaired, see sanitizer.UTF8Repair.
+	// The strings are not checked when empty.
+	UTF8Repair string
+	// SamplingObservationPoint is where the adaptive sampling aggregator observes the spans,
+	// SamplingObservationReceived or SamplingObservationProcessed
+	SamplingObservationPoint string
+	// SpanKindPolicies reject the spans of some kinds before they are queued, see SpanKindPolicy
+	SpanKindPolicies []SpanKindPolicy
+	// QueueFullPolicies are the policies of the receivers, by span format, when the queue is full.
+	// The spans of the other receivers are dropped.
+	QueueFullPolicies map[processor.SpanFormat]QueueFullPolicy
+	// QueueFullBlockTimeout is the longest time a receiver with the QueueFullBlock policy waits for room in the queue
+	QueueFullBlockTimeout time.Duration
+	// Registry configures publishing of the collector health to a service registry
+	Registry registry.Options
+	// Auth configures the authentication of the OTLP receivers and of the gRPC CollectorService
+	Auth auth.Options
+}
+
+// SpanKindPolicy rejects spans by kind, e.g. the internal spans of a chatty library,
+// to reduce the volume of stored spans without changing the instrumentation.
+type SpanKindPolicy struct {
+	// Name identifies the policy in the name tag of the span_kind_policy.hits metric
+	Name string
+	// Service restricts the policy to the spans of a service, it applies to all services when empty
+	Service string
+	// Library restricts the policy to the spans of an instrumentation library, identified by the
+	// otel.scope.name or otel.library.name span tag, it applies to all libraries when empty
+	Library string
+	// Keep rejects the spans whose kind is not one of Kinds, otherwise the spans whose kind is one of Kinds are rejected
+	Keep bool
+	// Kinds are values of SpanKinds
+	Kinds []string
+}
+
+// WorkersAutoTuning configures the adjustment of the number of workers to the latency
+// of the span writer and to the occupancy of the queue.
+type WorkersAutoTuning struct {
+	// Enabled turns the auto-tuning on, NumWorkers is then the initial number of workers
+	Enabled bool
+	// MinWorkers is the lowest number of workers
+	MinWorkers int
+	// MaxWorkers is the highest number of workers
+	MaxWorkers int
+	// Interval is the time between two adjustments
+	Interval time.Duration
 }
 
 type serverFlagsConfig struct {
@@ -162,6 +327,15 @@ type HTTPOptions struct {
 	IdleTimeout time.Duration
 	// CORS allows CORS requests , sets the values for Allowed Headers and Allowed Origins.
 	CORS corscfg.Options
+	// H2C enables HTTP/2 over cleartext connections when TLS is disabled
+	H2C bool
+	// GRPCWeb enables the gRPC-web translation of the CollectorService on the HTTP server
+	GRPCWeb bool
+	// MaxRequestBodySize limits the size of the request bodies, both before and after decompression
+	MaxRequestBodySize int64
+	// CompressionAlgorithms are the Content-Encoding values of the requests the server decompresses.
+	// Uncompressed requests are always accepted.
+	CompressionAlgorithms []string
 }
 
 // GRPCOptions defines options for a gRPC server
@@ -185,16 +359,44 @@ type GRPCOptions struct {
 // AddFlags adds flags for CollectorOptions
 func AddFlags(flags *flag.FlagSet) {
 	flags.Int(flagNumWorkers, DefaultNumWorkers, "The number of workers pulling items from the queue")
+	flags.Bool(flagWorkersAutoTuning, false, "(experimental) Adjusts the number of workers at runtime, starting from --"+flagNumWorkers+": more workers when spans wait in the queue while the span writer keeps its latency, fewer when the writer latency degrades or the queue stays empty")
+	flags.Int(flagWorkersMin, 1, "The lowest number of workers when they are auto-tuned")
+	flags.Int(flagWorkersMax, DefaultMaxWorkers, "The highest number of workers when they are auto-tuned")
+	flags.Duration(flagWorkersTuningInterval, DefaultWorkersTuningInterval, "The interval between two adjustments of the number of workers when they are auto-tuned")
 	flags.Int(flagQueueSize, DefaultQueueSize, "The queue size of the collector")
 	flags.Uint(flagDynQueueSizeMemory, 0, "(experimental) The max memory size in MiB to use for the dynamic queue.")
 	flags.String(flagCollectorTags, "", "One or more tags to be added to the Process tags of all spans passing through this collector. Ex: key1=value1,key2=${envVar:defaultValue}")
 	flags.Bool(flagSpanSizeMetricsEnabled, false, "Enables metrics based on processed span size, which are more expensive to calculate.")
+	flags.Float64(flagIngestLatencySampling, 0, "The fraction of spans, between 0 and 1, for which the latency from receipt to storage write is measured and broken down by pipeline stage.")
+	flags.Float64(flagDroppedSpansLogSampling, 0, "The fraction of dropped spans, between 0 and 1, logged at debug level with the reason why they were dropped, which is also the reason tag of the spans.dropped metric: queue_full, sanitizer_reject, storage_error, quota or tenant_invalid.")
+	flags.String(flagProcessors, "", fmt.Sprintf("Comma-separated list of the span processors compiled into this binary to run before spans are saved, in this order unless the processors require another one. Registered processors: [%s]", strings.Join(processor.DefaultRegistry.Names(), ", ")))
+	flags.String(flagBaggageKeys, "", "Comma-separated list of baggage keys whose values carried by spans are stored as baggage.<key> span tags, so that spans can be searched by them. Ex: experiment,route")
+	flags.String(flagProvenanceAttributes, "", fmt.Sprintf("Comma-separated list of provenance attributes added to each span as jaeger.ingest.<attribute> tags, to help debugging data issues: %s (the receiving collector), %s (the receiver transport and span format), %s (the receipt time), %s (the collector version)", ProvenanceInstance, ProvenanceProtocol, ProvenanceTimestamp, ProvenanceVersion))
+	flags.String(flagProvenanceInstance, "", "The name of this collector in the jaeger.ingest.instance tag, the hostname by default")
+	flags.String(flagResourceAttributes, "", "One or more deployment attributes added to the Process tags of the spans that do not have them, so that traces can be searched by infrastructure. Ex: k8s.cluster.name=${CLUSTER_NAME},cloud.region=${REGION:us-east-1}")
+	flags.String(flagResourceFiles, "", "One or more deployment attributes read from files, e.g. mounted by the Kubernetes downward API, added to the Process tags of the spans that do not have them. Take precedence over --"+flagResourceAttributes+". Ex: cloud.availability_zone=/etc/podinfo/zone")
+	flags.String(flagUTF8Repair, "", fmt.Sprintf("How the span names, service names, tags and log fields that are not valid UTF-8 are repaired, as invalid strings fail the writes of some storage backends like Elasticsearch: %q (stored as binary tags), %q (with the U+FFFD replacement character), %q (with \\xNN escapes), or empty to leave them unchecked. The repaired spans are given a warning", sanitizer.UTF8RepairBinary, sanitizer.UTF8RepairReplace, sanitizer.UTF8RepairHex))
+	flags.String(flagTraceStateKeys, "", "Comma-separated list of W3C tracestate keys whose values carried by spans are stored as tracestate.<key> span tags, so that spans can be searched by them.")
+	flags.String(flagSpanKindPolicies, "", fmt.Sprintf("Semicolon-separated list of policies rejecting spans by kind before they are queued, counted by the span_kind_policy.hits metric tagged with the policy name. "+
+		"Each policy is <name>:<key>=<value>,... with the optional keys service and library (the otel.scope.name or otel.library.name span tag) restricting the spans it applies to, "+
+		"and either drop, the |-separated kinds rejected, or keep, the only kinds accepted. Kinds: [%s]. "+
+		"Ex: grpc-internal:library=io.opentelemetry.grpc-1.6,drop=internal;checkout:service=checkout,keep=server|client", strings.Join(SpanKinds, ", ")))
+	flags.String(flagQueueFullPolicies, "", fmt.Sprintf("Comma-separated list of the policies of the receivers when the queue is full, as <receiver>=<policy>: %q drops the spans, %q waits for room in the queue for up to --%s, which pushes back on the clients, %q fails the request with a retryable error. "+
+		"The spans of the other receivers are dropped; the queue_full_policy.spans metric counts the spans handled by each policy. Receivers: [%s]. Ex: otlp=block,proto=fail",
+		QueueFullDrop, QueueFullBlock, flagQueueFullBlockTimeout, QueueFullFail, joinSpanFormats(QueueFullReceivers)))
+	flags.Duration(flagQueueFullBlockTimeout, DefaultQueueFullBlockTimeout, "The longest time a receiver with the block policy waits for room in the full queue before dropping the spans")
+	flags.String(flagSamplingObservation, SamplingObservationProcessed, fmt.Sprintf("Where the adaptive sampling aggregator observes the spans to compute the throughput of each service operation: %q observes every span received, including the spans dropped when the queue is full, %q only the spans taken from the queue. Both observe the spans not stored because of --downsampling.ratio", SamplingObservationReceived, SamplingObservationProcessed))
 
 	addHTTPFlags(flags, httpServerFlagsCfg, ports.PortToHostPort(ports.CollectorHTTP))
+	flags.Bool(flagCollectorHTTPH2C, false, "Enables HTTP/2 over cleartext (h2c) on the collector's HTTP server; ignored when TLS is enabled, which negotiates HTTP/2 already")
+	flags.Bool(flagCollectorHTTPGRPCWeb, false, "Enables gRPC-web requests to the CollectorService (PostSpans) on the collector's HTTP server, for browsers and proxies that cannot use native gRPC")
+	corsCollectorHTTPFlags.AddFlags(flags)
 	addGRPCFlags(flags, grpcServerFlagsCfg, ports.PortToHostPort(ports.CollectorGRPC))
 
 	flags.Bool(flagCollectorOTLPEnabled, true, "Enables OpenTelemetry OTLP receiver on dedicated HTTP and gRPC ports")
 	addHTTPFlags(flags, otlpServerFlagsCfg.HTTP, "")
+	flags.Int64(flagOTLPHTTPMaxRequestBodySize, DefaultOTLPHTTPMaxRequestBodySize, "The maximum size in bytes of the requests of the OTLP/HTTP receiver. Compressed requests are rejected once their decompressed body exceeds it, which protects the collector from decompression bombs")
+	flags.String(flagOTLPHTTPCompressionAlgorithms, strings.Join(OTLPHTTPCompressionAlgorithms, ","), "Comma-separated list of the compression algorithms (Content-Encoding) of the requests accepted by the OTLP/HTTP receiver; uncompressed requests are always accepted")
 	corsOTLPFlags.AddFlags(flags)
 	addGRPCFlags(flags, otlpServerFlagsCfg.GRPC, "")
 
@@ -203,7 +405,11 @@ func AddFlags(flags *flag.FlagSet) {
 	tlsZipkinFlagsConfig.AddFlags(flags)
 	corsZipkinFlags.AddFlags(flags)
 
+	flags.String(flagDatadogHTTPHostPort, "", "The host:port (e.g. 127.0.0.1:8126 or :8126) of the collector's Datadog agent-compatible trace server (disabled by default)")
+
 	tenancy.AddFlags(flags)
+	registry.AddFlags(flags)
+	auth.AddFlags(flags)
 }
 
 func addHTTPFlags(flags *flag.FlagSet, cfg serverFlagsConfig, defaultHostPort string) {
@@ -222,7 +428,7 @@ func addGRPCFlags(flags *flag.FlagSet, cfg serverFlagsConfig, defaultHostPort st
 	flags.Int(
 		cfg.prefix+"."+flagSuffixGRPCMaxReceiveMessageLength,
 		DefaultGRPCMaxReceiveMessageLength,
-		"The maximum receivable message size for the collector's gRPC server")
+		"The maximum receivable message size for the collector's gRPC server, which also applies to compressed messages once decompressed")
 	flags.Duration(
 		cfg.prefix+"."+flagSuffixGRPCMaxConnectionAge,
 		0,
@@ -266,13 +472,80 @@ func (opts *GRPCOptions) initFromViper(v *viper.Viper, _ *zap.Logger, cfg server
 func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*CollectorOptions, error) {
 	cOpts.CollectorTags = flags.ParseJaegerTags(v.GetString(flagCollectorTags))
 	cOpts.NumWorkers = v.GetInt(flagNumWorkers)
+	cOpts.WorkersAutoTuning.Enabled = v.GetBool(flagWorkersAutoTuning)
+	cOpts.WorkersAutoTuning.MinWorkers = v.GetInt(flagWorkersMin)
+	cOpts.WorkersAutoTuning.MaxWorkers = v.GetInt(flagWorkersMax)
+	cOpts.WorkersAutoTuning.Interval = v.GetDuration(flagWorkersTuningInterval)
+	if cOpts.WorkersAutoTuning.Enabled {
+		if cOpts.WorkersAutoTuning.MinWorkers < 1 || cOpts.WorkersAutoTuning.MaxWorkers < cOpts.WorkersAutoTuning.MinWorkers {
+			return cOpts, fmt.Errorf("%s must be at least 1 and at most %s, got %d and %d",
+				flagWorkersMin, flagWorkersMax, cOpts.WorkersAutoTuning.MinWorkers, cOpts.WorkersAutoTuning.MaxWorkers)
+		}
+		if cOpts.WorkersAutoTuning.Interval <= 0 {
+			return cOpts, fmt.Errorf("%s must be positive, got %v", flagWorkersTuningInterval, cOpts.WorkersAutoTuning.Interval)
+		}
+	}
 	cOpts.QueueSize = v.GetInt(flagQueueSize)
 	cOpts.DynQueueSizeMemory = v.GetUint(flagDynQueueSizeMemory) * 1024 * 1024 // we receive in MiB and store in bytes
 	cOpts.SpanSizeMetricsEnabled = v.GetBool(flagSpanSizeMetricsEnabled)
+	cOpts.IngestLatencySampling = v.GetFloat64(flagIngestLatencySampling)
+	if cOpts.IngestLatencySampling < 0 || cOpts.IngestLatencySampling > 1 {
+		return cOpts, fmt.Errorf("%s must be between 0 and 1, got %v", flagIngestLatencySampling, cOpts.IngestLatencySampling)
+	}
+	cOpts.DroppedSpansLogSampling = v.GetFloat64(flagDroppedSpansLogSampling)
+	if cOpts.DroppedSpansLogSampling < 0 || cOpts.DroppedSpansLogSampling > 1 {
+		return cOpts, fmt.Errorf("%s must be between 0 and 1, got %v", flagDroppedSpansLogSampling, cOpts.DroppedSpansLogSampling)
+	}
+	cOpts.Processors = splitList(v.GetString(flagProcessors))
+	cOpts.BaggageKeys = splitList(v.GetString(flagBaggageKeys))
+	cOpts.TraceStateKeys = splitList(v.GetString(flagTraceStateKeys))
+	cOpts.Provenance.Attributes = splitList(v.GetString(flagProvenanceAttributes))
+	for _, attribute := range cOpts.Provenance.Attributes {
+		switch attribute {
+		case ProvenanceInstance, ProvenanceProtocol, ProvenanceTimestamp, ProvenanceVersion:
+		default:
+			return cOpts, fmt.Errorf("unknown provenance attribute %q in %s", attribute, flagProvenanceAttributes)
+		}
+	}
+	cOpts.Provenance.Instance = v.GetString(flagProvenanceInstance)
+	resourceAttributes, err := readResourceAttributes(v.GetString(flagResourceAttributes), v.GetString(flagResourceFiles))
+	if err != nil {
+		return cOpts, err
+	}
+	cOpts.ResourceAttributes = resourceAttributes
+	cOpts.UTF8Repair = v.GetString(flagUTF8Repair)
+	switch sanitizer.UTF8Repair(cOpts.UTF8Repair) {
+	case "", sanitizer.UTF8RepairBinary, sanitizer.UTF8RepairReplace, sanitizer.UTF8RepairHex:
+	default:
+		return cOpts, fmt.Errorf("unknown UTF-8 repair %q in %s", cOpts.UTF8Repair, flagUTF8Repair)
+	}
+	spanKindPolicies, err := parseSpanKindPolicies(v.GetString(flagSpanKindPolicies))
+	if err != nil {
+		return cOpts, err
+	}
+	cOpts.SpanKindPolicies = spanKindPolicies
+	queueFullPolicies, err := parseQueueFullPolicies(v.GetString(flagQueueFullPolicies))
+	if err != nil {
+		return cOpts, err
+	}
+	cOpts.QueueFullPolicies = queueFullPolicies
+	cOpts.QueueFullBlockTimeout = v.GetDuration(flagQueueFullBlockTimeout)
+	if cOpts.QueueFullBlockTimeout <= 0 {
+		return cOpts, fmt.Errorf("%s must be positive, got %v", flagQueueFullBlockTimeout, cOpts.QueueFullBlockTimeout)
+	}
+	cOpts.SamplingObservationPoint = v.GetString(flagSamplingObservation)
+	switch cOpts.SamplingObservationPoint {
+	case SamplingObservationReceived, SamplingObservationProcessed:
+	default:
+		return cOpts, fmt.Errorf("unknown sampling observation point %q in %s", cOpts.SamplingObservationPoint, flagSamplingObservation)
+	}
 
 	if err := cOpts.HTTP.initFromViper(v, logger, httpServerFlagsCfg); err != nil {
 		return cOpts, fmt.Errorf("failed to parse HTTP server options: %w", err)
 	}
+	cOpts.HTTP.H2C = v.GetBool(flagCollectorHTTPH2C)
+	cOpts.HTTP.GRPCWeb = v.GetBool(flagCollectorHTTPGRPCWeb)
+	cOpts.HTTP.CORS = corsCollectorHTTPFlags.InitFromViper(v)
 
 	if err := cOpts.GRPC.initFromViper(v, logger, grpcServerFlagsCfg); err != nil {
 		return cOpts, fmt.Errorf("failed to parse gRPC server options: %w", err)
@@ -283,6 +556,16 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger)
 		return cOpts, fmt.Errorf("failed to parse OTLP/HTTP server options: %w", err)
 	}
 	cOpts.OTLP.HTTP.CORS = corsOTLPFlags.InitFromViper(v)
+	cOpts.OTLP.HTTP.MaxRequestBodySize = v.GetInt64(flagOTLPHTTPMaxRequestBodySize)
+	if cOpts.OTLP.HTTP.MaxRequestBodySize <= 0 {
+		return cOpts, fmt.Errorf("%s must be positive, got %d", flagOTLPHTTPMaxRequestBodySize, cOpts.OTLP.HTTP.MaxRequestBodySize)
+	}
+	cOpts.OTLP.HTTP.CompressionAlgorithms = splitList(v.GetString(flagOTLPHTTPCompressionAlgorithms))
+	for _, algorithm := range cOpts.OTLP.HTTP.CompressionAlgorithms {
+		if !slices.Contains(OTLPHTTPCompressionAlgorithms, algorithm) {
+			return cOpts, fmt.Errorf("unknown compression algorithm %q in %s", algorithm, flagOTLPHTTPCompressionAlgorithms)
+		}
+	}
 	if err := cOpts.OTLP.GRPC.initFromViper(v, logger, otlpServerFlagsCfg.GRPC); err != nil {
 		return cOpts, fmt.Errorf("failed to parse OTLP/gRPC server options: %w", err)
 	}
@@ -296,5 +579,136 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger)
 	cOpts.Zipkin.TLS = tlsZipkin
 	cOpts.Zipkin.CORS = corsZipkinFlags.InitFromViper(v)
 
+	cOpts.Datadog.HTTPHostPort = ports.FormatHostPort(v.GetString(flagDatadogHTTPHostPort))
+
+	registryOpts, err := registry.InitFromViper(v)
+	if err != nil {
+		return cOpts, fmt.Errorf("failed to parse service registry options: %w", err)
+	}
+	cOpts.Registry = registryOpts
+	cOpts.Auth = auth.InitFromViper(v)
+
 	return cOpts, nil
 }
+
+// readResourceAttributes merges the key=value attributes with the key=path ones whose
+// values are the content of the files. Empty values are ignored.
+func readResourceAttributes(attributes string, files string) (map[string]string, error) {
+	resourceAttributes := make(map[string]string)
+	for k, v := range flags.ParseJaegerTags(attributes) {
+		if v != "" {
+			resourceAttributes[k] = v
+		}
+	}
+	for k, path := range flags.ParseJaegerTags(files) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource attribute %s in %s: %w", k, flagResourceFiles, err)
+		}
+		if v := strings.TrimSpace(string(data)); v != "" {
+			resourceAttributes[k] = v
+		}
+	}
+	if len(resourceAttributes) == 0 {
+		return nil, nil
+	}
+	return resourceAttributes, nil
+}
+
+// parseSpanKindPolicies parses the policies of flagSpanKindPolicies,
+// e.g. checkout:service=checkout,keep=server|client.
+func parseSpanKindPolicies(value string) ([]SpanKindPolicy, error) {
+	var policies []SpanKindPolicy
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, settings, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("span kind policy %q in %s must be <name>:<key>=<value>,...", item, flagSpanKindPolicies)
+		}
+		if slices.ContainsFunc(policies, func(p SpanKindPolicy) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("duplicate span kind policy %q in %s", name, flagSpanKindPolicies)
+		}
+		policy := SpanKindPolicy{Name: name}
+		hasAction := false
+		for _, setting := range splitList(settings) {
+			key, val, _ := strings.Cut(setting, "=")
+			key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+			switch key {
+			case "service":
+				policy.Service = val
+			case "library":
+				policy.Library = val
+			case "drop", "keep":
+				if hasAction {
+					return nil, fmt.Errorf("span kind policy %q in %s must have either drop or keep", name, flagSpanKindPolicies)
+				}
+				hasAction = true
+				policy.Keep = key == "keep"
+				for _, kind := range strings.Split(val, "|") {
+					kind = strings.TrimSpace(kind)
+					if !slices.Contains(SpanKinds, kind) {
+						return nil, fmt.Errorf("unknown span kind %q in span kind policy %q in %s", kind, name, flagSpanKindPolicies)
+					}
+					policy.Kinds = append(policy.Kinds, kind)
+				}
+			default:
+				return nil, fmt.Errorf("unknown key %q in span kind policy %q in %s", key, name, flagSpanKindPolicies)
+			}
+		}
+		if !hasAction {
+			return nil, fmt.Errorf("span kind policy %q in %s must have either drop or keep", name, flagSpanKindPolicies)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// parseQueueFullPolicies parses the policies of flagQueueFullPolicies, e.g. otlp=block,proto=fail.
+func parseQueueFullPolicies(value string) (map[processor.SpanFormat]QueueFullPolicy, error) {
+	var policies map[processor.SpanFormat]QueueFullPolicy
+	for _, item := range splitList(value) {
+		key, val, ok := strings.Cut(item, "=")
+		receiver, policy := processor.SpanFormat(strings.TrimSpace(key)), QueueFullPolicy(strings.TrimSpace(val))
+		if !ok {
+			return nil, fmt.Errorf("queue full policy %q in %s must be <receiver>=<policy>", item, flagQueueFullPolicies)
+		}
+		if !slices.Contains(QueueFullReceivers, receiver) {
+			return nil, fmt.Errorf("unknown receiver %q in %s", receiver, flagQueueFullPolicies)
+		}
+		switch policy {
+		case QueueFullDrop, QueueFullBlock, QueueFullFail:
+		default:
+			return nil, fmt.Errorf("unknown queue full policy %q of receiver %q in %s", policy, receiver, flagQueueFullPolicies)
+		}
+		if _, ok := policies[receiver]; ok {
+			return nil, fmt.Errorf("duplicate receiver %q in %s", receiver, flagQueueFullPolicies)
+		}
+		if policies == nil {
+			policies = make(map[processor.SpanFormat]QueueFullPolicy)
+		}
+		policies[receiver] = policy
+	}
+	return policies, nil
+}
+
+func joinSpanFormats(formats []processor.SpanFormat) string {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitList returns the non-empty items of a comma-separated list.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
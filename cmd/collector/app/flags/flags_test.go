@@ -15,6 +15,8 @@
 package flags
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
@@ -33,6 +36,7 @@ func TestCollectorOptionsWithFlags_CheckHostPort(t *testing.T) {
 		"--collector.http-server.host-port=5678",
 		"--collector.grpc-server.host-port=1234",
 		"--collector.zipkin.host-port=3456",
+		"--collector.datadog.host-port=8126",
 	})
 	_, err := c.InitFromViper(v, zap.NewNop())
 	require.NoError(t, err)
@@ -40,6 +44,7 @@ func TestCollectorOptionsWithFlags_CheckHostPort(t *testing.T) {
 	assert.Equal(t, ":5678", c.HTTP.HostPort)
 	assert.Equal(t, ":1234", c.GRPC.HostPort)
 	assert.Equal(t, ":3456", c.Zipkin.HTTPHostPort)
+	assert.Equal(t, ":8126", c.Datadog.HTTPHostPort)
 }
 
 func TestCollectorOptionsWithFlags_CheckFullHostPort(t *testing.T) {
@@ -49,6 +54,7 @@ func TestCollectorOptionsWithFlags_CheckFullHostPort(t *testing.T) {
 		"--collector.http-server.host-port=:5678",
 		"--collector.grpc-server.host-port=127.0.0.1:1234",
 		"--collector.zipkin.host-port=0.0.0.0:3456",
+		"--collector.datadog.host-port=127.0.0.1:8126",
 	})
 	_, err := c.InitFromViper(v, zap.NewNop())
 	require.NoError(t, err)
@@ -56,6 +62,7 @@ func TestCollectorOptionsWithFlags_CheckFullHostPort(t *testing.T) {
 	assert.Equal(t, ":5678", c.HTTP.HostPort)
 	assert.Equal(t, "127.0.0.1:1234", c.GRPC.HostPort)
 	assert.Equal(t, "0.0.0.0:3456", c.Zipkin.HTTPHostPort)
+	assert.Equal(t, "127.0.0.1:8126", c.Datadog.HTTPHostPort)
 }
 
 func TestCollectorOptionsWithFailedTLSFlags(t *testing.T) {
@@ -179,6 +186,21 @@ func TestCollectorOptionsWithFlags_CheckFullTenancy(t *testing.T) {
 	assert.Equal(t, []string{"acme", "hardware-store"}, c.GRPC.Tenancy.Tenants)
 }
 
+func TestCollectorOptionsWithFlags_CheckAuth(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.auth.oidc.issuer-url=https://issuer",
+		"--collector.auth.oidc.audience=jaeger",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.True(t, c.Auth.Enabled())
+	assert.Equal(t, "https://issuer", c.Auth.OIDC.IssuerURL)
+	assert.Equal(t, "jaeger", c.Auth.OIDC.Audience)
+}
+
 func TestCollectorOptionsWithFlags_CheckZipkinKeepAlive(t *testing.T) {
 	c := &CollectorOptions{}
 	v, command := config.Viperize(AddFlags)
@@ -190,6 +212,329 @@ func TestCollectorOptionsWithFlags_CheckZipkinKeepAlive(t *testing.T) {
 	assert.False(t, c.Zipkin.KeepAlive)
 }
 
+func TestCollectorOptionsWithFlags_CheckHTTPGRPCWeb(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.http-server.h2c=true",
+		"--collector.http-server.grpc-web=true",
+		"--collector.http-server.cors.allowed-origins=https://app.example.com",
+		"--collector.http-server.cors.allowed-headers=x-tenant",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.True(t, c.HTTP.H2C)
+	assert.True(t, c.HTTP.GRPCWeb)
+	assert.Equal(t, []string{"https://app.example.com"}, c.HTTP.CORS.AllowedOrigins)
+	assert.Equal(t, []string{"x-tenant"}, c.HTTP.CORS.AllowedHeaders)
+	assert.False(t, c.OTLP.HTTP.GRPCWeb)
+}
+
+func TestCollectorOptionsWithFlags_CheckOTLPHTTPLimits(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.EqualValues(t, DefaultOTLPHTTPMaxRequestBodySize, c.OTLP.HTTP.MaxRequestBodySize)
+	assert.Equal(t, OTLPHTTPCompressionAlgorithms, c.OTLP.HTTP.CompressionAlgorithms)
+	assert.Zero(t, c.HTTP.MaxRequestBodySize)
+
+	command.ParseFlags([]string{
+		"--collector.otlp.http.max-request-body-size=1048576",
+		"--collector.otlp.http.compression-algorithms=gzip, zstd",
+	})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1048576, c.OTLP.HTTP.MaxRequestBodySize)
+	assert.Equal(t, []string{"gzip", "zstd"}, c.OTLP.HTTP.CompressionAlgorithms)
+
+	command.ParseFlags([]string{"--collector.otlp.http.compression-algorithms=brotli"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, `unknown compression algorithm "brotli" in collector.otlp.http.compression-algorithms`)
+
+	command.ParseFlags([]string{
+		"--collector.otlp.http.compression-algorithms=gzip",
+		"--collector.otlp.http.max-request-body-size=0",
+	})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "collector.otlp.http.max-request-body-size must be positive, got 0")
+}
+
+func TestCollectorOptionsWithFlags_CheckIngestLatencySampling(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.ingest-latency-sampling=0.25",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.25, c.IngestLatencySampling, 0)
+}
+
+func TestCollectorOptionsWithFlags_CheckInvalidIngestLatencySampling(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.ingest-latency-sampling=1.5",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "collector.ingest-latency-sampling must be between 0 and 1")
+}
+
+func TestCollectorOptionsWithFlags_CheckWorkersAutoTuning(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.workers-auto-tuning.enabled=true",
+		"--collector.workers-auto-tuning.min-workers=10",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, WorkersAutoTuning{
+		Enabled:    true,
+		MinWorkers: 10,
+		MaxWorkers: DefaultMaxWorkers,
+		Interval:   DefaultWorkersTuningInterval,
+	}, c.WorkersAutoTuning)
+}
+
+func TestCollectorOptionsWithFlags_CheckInvalidWorkersAutoTuning(t *testing.T) {
+	tests := []struct {
+		flags []string
+		err   string
+	}{
+		{
+			flags: []string{"--collector.workers-auto-tuning.min-workers=0"},
+			err:   "collector.workers-auto-tuning.min-workers must be at least 1 and at most collector.workers-auto-tuning.max-workers, got 0 and 500",
+		},
+		{
+			flags: []string{"--collector.workers-auto-tuning.min-workers=20", "--collector.workers-auto-tuning.max-workers=10"},
+			err:   "collector.workers-auto-tuning.min-workers must be at least 1 and at most collector.workers-auto-tuning.max-workers, got 20 and 10",
+		},
+		{
+			flags: []string{"--collector.workers-auto-tuning.interval=0s"},
+			err:   "collector.workers-auto-tuning.interval must be positive, got 0s",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.err, func(t *testing.T) {
+			c := &CollectorOptions{}
+			v, command := config.Viperize(AddFlags)
+			command.ParseFlags(append([]string{"--collector.workers-auto-tuning.enabled=true"}, test.flags...))
+			_, err := c.InitFromViper(v, zap.NewNop())
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestCollectorOptionsWithFlags_CheckDroppedSpansLogSampling(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.dropped-spans-log-sampling=0.01",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.01, c.DroppedSpansLogSampling, 0)
+}
+
+func TestCollectorOptionsWithFlags_CheckInvalidDroppedSpansLogSampling(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.dropped-spans-log-sampling=-1",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "collector.dropped-spans-log-sampling must be between 0 and 1")
+}
+
+func TestCollectorOptionsWithFlags_CheckProcessors(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.processors=geoip, redactor,",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"geoip", "redactor"}, c.Processors)
+}
+
+func TestCollectorOptionsWithFlags_CheckPropagationKeys(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.baggage-keys=experiment, route",
+		"--collector.tracestate-keys=rojo",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"experiment", "route"}, c.BaggageKeys)
+	assert.Equal(t, []string{"rojo"}, c.TraceStateKeys)
+}
+
+func TestCollectorOptionsWithFlags_CheckProvenance(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.provenance.attributes=instance, protocol,timestamp,version",
+		"--collector.provenance.instance=collector-1",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"instance", "protocol", "timestamp", "version"}, c.Provenance.Attributes)
+	assert.Equal(t, "collector-1", c.Provenance.Instance)
+
+	command.ParseFlags([]string{"--collector.provenance.attributes=region"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, `unknown provenance attribute "region" in collector.provenance.attributes`)
+}
+
+func TestCollectorOptionsWithFlags_CheckResourceAttributes(t *testing.T) {
+	t.Setenv("CLUSTER_NAME", "prod-1")
+	zoneFile := filepath.Join(t.TempDir(), "zone")
+	require.NoError(t, os.WriteFile(zoneFile, []byte("eu-west-1a\n"), 0o600))
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, c.ResourceAttributes)
+
+	command.ParseFlags([]string{
+		"--collector.resource-detection.attributes=k8s.cluster.name=${CLUSTER_NAME},cloud.region=${REGION:eu-west-1},cloud.availability_zone=eu-west-1b,host.type=${HOST_TYPE}",
+		"--collector.resource-detection.files=cloud.availability_zone=" + zoneFile,
+	})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"k8s.cluster.name":        "prod-1",
+		"cloud.region":            "eu-west-1",
+		"cloud.availability_zone": "eu-west-1a",
+	}, c.ResourceAttributes)
+
+	command.ParseFlags([]string{"--collector.resource-detection.files=cloud.availability_zone=" + zoneFile + ".missing"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to read resource attribute cloud.availability_zone in collector.resource-detection.files")
+}
+
+func TestCollectorOptionsWithFlags_CheckUTF8Repair(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Empty(t, c.UTF8Repair)
+
+	command.ParseFlags([]string{"--collector.utf8-repair=hex"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "hex", c.UTF8Repair)
+
+	command.ParseFlags([]string{"--collector.utf8-repair=drop"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, `unknown UTF-8 repair "drop" in collector.utf8-repair`)
+}
+
+func TestCollectorOptionsWithFlags_CheckSamplingObservationPoint(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, SamplingObservationProcessed, c.SamplingObservationPoint)
+
+	command.ParseFlags([]string{"--collector.sampling.observation-point=received"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, SamplingObservationReceived, c.SamplingObservationPoint)
+
+	command.ParseFlags([]string{"--collector.sampling.observation-point=stored"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, `unknown sampling observation point "stored" in collector.sampling.observation-point`)
+}
+
+func TestCollectorOptionsWithFlags_CheckSpanKindPolicies(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Empty(t, c.SpanKindPolicies)
+
+	command.ParseFlags([]string{"--collector.span-kind-policies=grpc-internal:library=io.opentelemetry.grpc-1.6,drop=internal; checkout : service=checkout, keep=server|client;"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []SpanKindPolicy{
+		{Name: "grpc-internal", Library: "io.opentelemetry.grpc-1.6", Kinds: []string{"internal"}},
+		{Name: "checkout", Service: "checkout", Keep: true, Kinds: []string{"server", "client"}},
+	}, c.SpanKindPolicies)
+}
+
+func TestParseSpanKindPolicies_Errors(t *testing.T) {
+	testCases := []struct {
+		policies    string
+		expectedErr string
+	}{
+		{policies: "drop=internal", expectedErr: `span kind policy "drop=internal" in collector.span-kind-policies must be <name>:<key>=<value>,...`},
+		{policies: ":drop=internal", expectedErr: `span kind policy ":drop=internal" in collector.span-kind-policies must be <name>:<key>=<value>,...`},
+		{policies: "a:drop=internal;a:drop=client", expectedErr: `duplicate span kind policy "a" in collector.span-kind-policies`},
+		{policies: "a:service=checkout", expectedErr: `span kind policy "a" in collector.span-kind-policies must have either drop or keep`},
+		{policies: "a:drop=internal,keep=server", expectedErr: `span kind policy "a" in collector.span-kind-policies must have either drop or keep`},
+		{policies: "a:drop=internal|local", expectedErr: `unknown span kind "local" in span kind policy "a" in collector.span-kind-policies`},
+		{policies: "a:operation=get,drop=internal", expectedErr: `unknown key "operation" in span kind policy "a" in collector.span-kind-policies`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policies, func(t *testing.T) {
+			_, err := parseSpanKindPolicies(tc.policies)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestCollectorOptionsWithFlags_CheckQueueFullPolicies(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Empty(t, c.QueueFullPolicies)
+	assert.Equal(t, DefaultQueueFullBlockTimeout, c.QueueFullBlockTimeout)
+
+	command.ParseFlags([]string{"--collector.queue-full-policies=otlp=block, proto = fail,zipkin=drop", "--collector.queue-full-block-timeout=5s"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, map[processor.SpanFormat]QueueFullPolicy{
+		processor.OTLPSpanFormat:   QueueFullBlock,
+		processor.ProtoSpanFormat:  QueueFullFail,
+		processor.ZipkinSpanFormat: QueueFullDrop,
+	}, c.QueueFullPolicies)
+	assert.Equal(t, 5*time.Second, c.QueueFullBlockTimeout)
+
+	command.ParseFlags([]string{"--collector.queue-full-block-timeout=0s"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "collector.queue-full-block-timeout must be positive, got 0s")
+}
+
+func TestParseQueueFullPolicies_Errors(t *testing.T) {
+	testCases := []struct {
+		policies    string
+		expectedErr string
+	}{
+		{policies: "otlp", expectedErr: `queue full policy "otlp" in collector.queue-full-policies must be <receiver>=<policy>`},
+		{policies: "kafka=block", expectedErr: `unknown receiver "kafka" in collector.queue-full-policies`},
+		{policies: "otlp=wait", expectedErr: `unknown queue full policy "wait" of receiver "otlp" in collector.queue-full-policies`},
+		{policies: "otlp=block,otlp=fail", expectedErr: `duplicate receiver "otlp" in collector.queue-full-policies`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policies, func(t *testing.T) {
+			_, err := parseQueueFullPolicies(tc.policies)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
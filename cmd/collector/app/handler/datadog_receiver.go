@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/datadog"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// maxDatadogPayloadSize is the maximum size of a payload, the same as the default of the Datadog agent.
+const maxDatadogPayloadSize = 25 * 1024 * 1024
+
+// datadogResponse is the response of the Datadog agent. Tracers read sampling rates from it,
+// none are sent so that they keep their own configuration.
+const datadogResponse = `{"rate_by_service":{}}`
+
+// DatadogHandler accepts the trace payloads that Datadog tracers send to the Datadog agent,
+// so that applications can be pointed at the collector while they are migrated to OpenTelemetry.
+type DatadogHandler struct {
+	logger     *zap.Logger
+	consumer   *consumerDelegate
+	tenancyMgr *tenancy.Manager
+}
+
+// NewDatadogHandler creates a DatadogHandler.
+func NewDatadogHandler(logger *zap.Logger, spanProcessor processor.SpanProcessor, tm *tenancy.Manager) *DatadogHandler {
+	consumer := newConsumerDelegate(logger, spanProcessor, tm)
+	consumer.batchConsumer.spanOptions.InboundTransport = processor.HTTPTransport
+	consumer.batchConsumer.spanOptions.SpanFormat = processor.DatadogSpanFormat
+	return &DatadogHandler{
+		logger:     logger,
+		consumer:   consumer,
+		tenancyMgr: tm,
+	}
+}
+
+// RegisterRoutes registers the trace endpoints of the Datadog agent API.
+func (h *DatadogHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/v0.4/traces", h.handle(datadog.TracesFromV04)).Methods(http.MethodPut, http.MethodPost)
+	router.HandleFunc("/v0.7/traces", h.handle(datadog.TracesFromV07)).Methods(http.MethodPut, http.MethodPost)
+}
+
+func (h *DatadogHandler) handle(translate func([]byte, datadog.TracerInfo) (ptrace.Traces, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDatadogPayloadSize))
+		r.Body.Close()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusInternalServerError)
+			return
+		}
+		traces, err := translate(body, datadog.TracerInfo{
+			Language:        r.Header.Get("Datadog-Meta-Lang"),
+			LanguageVersion: r.Header.Get("Datadog-Meta-Lang-Version"),
+			TracerVersion:   r.Header.Get("Datadog-Meta-Tracer-Version"),
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		if h.tenancyMgr.Enabled {
+			// the batch consumer reads the tenant from the gRPC metadata
+			md := metadata.MD{}
+			md.Append(h.tenancyMgr.Header, r.Header.Values(h.tenancyMgr.Header)...)
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if err := h.consumer.consume(ctx, traces); err != nil {
+			switch status.Code(err) {
+			case codes.PermissionDenied:
+				http.Error(w, err.Error(), http.StatusForbidden)
+			case codes.ResourceExhausted:
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+			default:
+				http.Error(w, fmt.Sprintf("Cannot submit Datadog traces: %v", err), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(datadogResponse))
+	}
+}
+
+// StartDatadogReceiver starts an HTTP server accepting Datadog trace payloads.
+func StartDatadogReceiver(
+	options *flags.CollectorOptions,
+	logger *zap.Logger,
+	spanProcessor processor.SpanProcessor,
+	tm *tenancy.Manager,
+) (*http.Server, error) {
+	logger.Info("Starting Datadog receiver", zap.String("http host-port", options.Datadog.HTTPHostPort))
+	router := mux.NewRouter()
+	NewDatadogHandler(logger, spanProcessor, tm).RegisterRoutes(router)
+
+	errorLog, _ := zap.NewStdLogAt(logger, zapcore.ErrorLevel)
+	server := &http.Server{
+		Handler:           recoveryhandler.NewRecoveryHandler(logger, true)(router),
+		ReadTimeout:       options.HTTP.ReadTimeout,
+		ReadHeaderTimeout: options.HTTP.ReadHeaderTimeout,
+		IdleTimeout:       options.HTTP.IdleTimeout,
+		ErrorLog:          errorLog,
+	}
+	listener, err := net.Listen("tcp", options.Datadog.HTTPHostPort)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Could not start Datadog receiver", zap.Error(err))
+		}
+	}()
+	return server, nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+)
+
+// datadogV04Payload is [[{"name": "op", "service": "svc", "span_id": 1, "trace_id": 1}]] in MessagePack.
+var datadogV04Payload = []byte("\x91\x91\x84" +
+	"\xa4name\xa2op" +
+	"\xa7service\xa3svc" +
+	"\xa7span_id\x01" +
+	"\xa8trace_id\x01")
+
+// datadogV07Payload is {"chunks": [{"spans": [<the span above>]}], "env": "prod"} in MessagePack.
+var datadogV07Payload = []byte("\x82" +
+	"\xa6chunks\x91\x81\xa5spans\x91\x84" +
+	"\xa4name\xa2op" +
+	"\xa7service\xa3svc" +
+	"\xa7span_id\x01" +
+	"\xa8trace_id\x01" +
+	"\xa3env\xa4prod")
+
+func postDatadog(t *testing.T, h *DatadogHandler, url string, body []byte, header http.Header) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	h.RegisterRoutes(router)
+	req := httptest.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/msgpack")
+	req.Header.Set("Datadog-Meta-Lang", "go")
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDatadogHandler(t *testing.T) {
+	for _, url := range []string{"/v0.4/traces", "/v0.7/traces"} {
+		t.Run(url, func(t *testing.T) {
+			spanProcessor := &mockSpanProcessor{}
+			h := NewDatadogHandler(zap.NewNop(), spanProcessor, &tenancy.Manager{})
+			payload := datadogV04Payload
+			if url == "/v0.7/traces" {
+				payload = datadogV07Payload
+			}
+			rec := postDatadog(t, h, url, payload, nil)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{"rate_by_service":{}}`, rec.Body.String())
+
+			spans := spanProcessor.getSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "op", spans[0].OperationName)
+			assert.Equal(t, "svc", spans[0].Process.ServiceName)
+			assert.Equal(t, processor.HTTPTransport, spanProcessor.getTransport())
+			assert.Equal(t, processor.DatadogSpanFormat, spanProcessor.getSpanFormat())
+		})
+	}
+}
+
+func TestDatadogHandlerErrors(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	testCases := []struct {
+		name          string
+		body          []byte
+		tm            *tenancy.Manager
+		expectedError error
+		expectedCode  int
+	}{
+		{
+			name:         "invalid payload",
+			body:         []byte{0xc1},
+			tm:           &tenancy.Manager{},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "payload too large",
+			body:         make([]byte, maxDatadogPayloadSize+1),
+			tm:           &tenancy.Manager{},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "missing tenant",
+			body:         datadogV04Payload,
+			tm:           tm,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:          "busy",
+			body:          datadogV04Payload,
+			tm:            &tenancy.Manager{},
+			expectedError: processor.ErrBusy,
+			expectedCode:  http.StatusTooManyRequests,
+		},
+		{
+			name:          "processing error",
+			body:          datadogV04Payload,
+			tm:            &tenancy.Manager{},
+			expectedError: assert.AnError,
+			expectedCode:  http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spanProcessor := &mockSpanProcessor{expectedError: tc.expectedError}
+			h := NewDatadogHandler(zap.NewNop(), spanProcessor, tc.tm)
+			rec := postDatadog(t, h, "/v0.4/traces", tc.body, nil)
+			assert.Equal(t, tc.expectedCode, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestDatadogHandlerTenancy(t *testing.T) {
+	spanProcessor := &mockSpanProcessor{}
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	h := NewDatadogHandler(zap.NewNop(), spanProcessor, tm)
+	rec := postDatadog(t, h, "/v0.4/traces", datadogV04Payload, http.Header{tm.Header: {"acme"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, map[string]bool{"acme": true}, spanProcessor.getTenants())
+}
+
+func TestStartDatadogReceiver(t *testing.T) {
+	spanProcessor := &mockSpanProcessor{}
+	logger, _ := testutils.NewLogger()
+	opts := &flags.CollectorOptions{}
+	opts.Datadog.HTTPHostPort = ":18126"
+
+	server, err := StartDatadogReceiver(opts, logger, spanProcessor, &tenancy.Manager{})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+	}()
+
+	response, err := http.Post("http://localhost:18126/v0.4/traces", "application/msgpack", bytes.NewReader(datadogV04Payload))
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, http.StatusOK, response.StatusCode, string(body))
+	assert.Len(t, spanProcessor.getSpans(), 1)
+}
+
+func TestStartDatadogReceiverListenError(t *testing.T) {
+	opts := &flags.CollectorOptions{}
+	opts.Datadog.HTTPHostPort = ":-1"
+	_, err := StartDatadogReceiver(opts, zap.NewNop(), &mockSpanProcessor{}, &tenancy.Manager{})
+	require.Error(t, err)
+}
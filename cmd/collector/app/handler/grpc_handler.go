@@ -78,6 +78,9 @@ func (c *batchConsumer) consume(ctx context.Context, batch *model.Batch) error {
 	tenant, err := c.validateTenant(ctx)
 	if err != nil {
 		c.logger.Debug("rejecting spans (tenancy)", zap.Error(err))
+		if reporter, ok := c.spanProcessor.(processor.DroppedSpansReporter); ok {
+			reporter.ReportDroppedSpans(batch.Spans, processor.TenantInvalidDropReason)
+		}
 		return err
 	}
 
@@ -106,6 +109,14 @@ func (c *batchConsumer) validateTenant(ctx context.Context) (string, error) {
 		return "", nil
 	}
 
+	// the tenant of an authenticated request takes precedence over the tenancy header
+	if tenant := tenancy.GetTenant(ctx); tenant != "" {
+		if !c.tenancyMgr.Valid(tenant) {
+			return "", status.Errorf(codes.PermissionDenied, "unknown tenant")
+		}
+		return tenant, nil
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return "", status.Errorf(codes.PermissionDenied, "missing tenant header")
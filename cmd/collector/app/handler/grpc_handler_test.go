@@ -40,6 +40,7 @@ type mockSpanProcessor struct {
 	mux           sync.Mutex
 	spans         []*model.Span
 	tenants       map[string]bool
+	dropped       map[processor.DropReason]int
 	transport     processor.InboundTransport
 	spanFormat    processor.SpanFormat
 }
@@ -58,6 +59,21 @@ func (p *mockSpanProcessor) ProcessSpans(spans []*model.Span, opts processor.Spa
 	return oks, p.expectedError
 }
 
+func (p *mockSpanProcessor) ReportDroppedSpans(spans []*model.Span, reason processor.DropReason) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.dropped == nil {
+		p.dropped = make(map[processor.DropReason]int)
+	}
+	p.dropped[reason] += len(spans)
+}
+
+func (p *mockSpanProcessor) getDropped() map[processor.DropReason]int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.dropped
+}
+
 func (p *mockSpanProcessor) getSpans() []*model.Span {
 	p.mux.Lock()
 	defer p.mux.Unlock()
@@ -87,6 +103,7 @@ func (p *mockSpanProcessor) reset() {
 	defer p.mux.Unlock()
 	p.spans = nil
 	p.tenants = nil
+	p.dropped = nil
 	p.transport = ""
 	p.spanFormat = ""
 }
@@ -360,6 +377,18 @@ func TestGetTenant(t *testing.T) {
 			mustFail: true,
 			tenant:   "",
 		},
+		{
+			name:     "authenticated tenant",
+			ctx:      tenancy.WithTenant(withIncomingMetadata(context.TODO(), tenantHeader, "acme", t), "another-example"),
+			mustFail: false,
+			tenant:   "another-example",
+		},
+		{
+			name:     "invalid authenticated tenant",
+			ctx:      tenancy.WithTenant(context.TODO(), "an-invalid-tenant"),
+			mustFail: true,
+			tenant:   "",
+		},
 	}
 
 	processor := &mockSpanProcessor{}
@@ -382,6 +411,27 @@ func TestGetTenant(t *testing.T) {
 	}
 }
 
+func TestBatchConsumerReportsInvalidTenant(t *testing.T) {
+	spanProcessor := &mockSpanProcessor{}
+	batchConsumer := newBatchConsumer(zap.NewNop(), spanProcessor, processor.GRPCTransport, processor.ProtoSpanFormat,
+		tenancy.NewManager(&tenancy.Options{
+			Enabled: true,
+			Header:  "x-tenant",
+			Tenants: []string{"acme"},
+		}))
+	batch := &model.Batch{Spans: []*model.Span{{OperationName: "op1"}, {OperationName: "op2"}}}
+
+	err := batchConsumer.consume(withIncomingMetadata(context.Background(), "x-tenant", "unknown", t), batch)
+	require.Error(t, err)
+	assert.Empty(t, spanProcessor.getSpans())
+	assert.Equal(t, map[processor.DropReason]int{processor.TenantInvalidDropReason: 2}, spanProcessor.getDropped())
+
+	spanProcessor.reset()
+	require.NoError(t, batchConsumer.consume(withIncomingMetadata(context.Background(), "x-tenant", "acme", t), batch))
+	assert.Len(t, spanProcessor.getSpans(), 2)
+	assert.Nil(t, spanProcessor.getDropped())
+}
+
 func TestBatchConsumer(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -16,6 +16,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -87,7 +88,12 @@ func (aH *APIHandler) SaveSpan(w http.ResponseWriter, r *http.Request) {
 	batches := []*tJaeger.Batch{batch}
 	opts := SubmitBatchOptions{InboundTransport: processor.HTTPTransport}
 	if _, err = aH.jaegerBatchesHandler.SubmitBatches(batches, opts); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusInternalServerError)
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, processor.ErrBusy) {
+			// the queue is full, the client can retry later
+			statusCode = http.StatusServiceUnavailable
+		}
+		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), statusCode)
 		return
 	}
 
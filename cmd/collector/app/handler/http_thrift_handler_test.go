@@ -33,6 +33,7 @@ import (
 	jaegerClient "github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/transport"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 )
 
@@ -95,6 +96,12 @@ func TestThriftFormat(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, statusCode)
 	assert.EqualValues(t, "Cannot submit Jaeger batch: Bad times ahead\n", resBodyStr)
+
+	handler.jaegerBatchesHandler.(*mockJaegerHandler).err = processor.ErrBusy
+	statusCode, resBodyStr, err = postBytes("application/x-thrift", server.URL+`/api/traces`, someBytes)
+	require.NoError(t, err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, statusCode)
+	assert.EqualValues(t, "Cannot submit Jaeger batch: server busy\n", resBodyStr)
 }
 
 func TestViaClient(t *testing.T) {
@@ -20,6 +20,7 @@ import (
 
 	otlp2jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -32,6 +33,7 @@ import (
 	nooptrace "go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model"
@@ -41,14 +43,24 @@ import (
 
 var _ component.Host = (*otelHost)(nil) // API check
 
+const mib = 1024 * 1024
+
 // StartOTLPReceiver starts OpenTelemetry OTLP receiver listening on gRPC and HTTP ports.
-func StartOTLPReceiver(options *flags.CollectorOptions, logger *zap.Logger, spanProcessor processor.SpanProcessor, tm *tenancy.Manager) (receiver.Traces, error) {
+// The requests are authenticated by the authenticator, if not nil.
+func StartOTLPReceiver(
+	options *flags.CollectorOptions,
+	logger *zap.Logger,
+	spanProcessor processor.SpanProcessor,
+	tm *tenancy.Manager,
+	authenticator *auth.Authenticator,
+) (receiver.Traces, error) {
 	otlpFactory := otlpreceiver.NewFactory()
 	return startOTLPReceiver(
 		options,
 		logger,
 		spanProcessor,
 		tm,
+		authenticator,
 		otlpFactory,
 		consumer.NewTraces,
 		otlpFactory.CreateTracesReceiver,
@@ -63,6 +75,7 @@ func startOTLPReceiver(
 	logger *zap.Logger,
 	spanProcessor processor.SpanProcessor,
 	tm *tenancy.Manager,
+	authenticator *auth.Authenticator,
 	// from here: params that can be mocked in tests
 	otlpFactory receiver.Factory,
 	newTraces func(consume consumer.ConsumeTracesFunc, options ...consumer.Option) (consumer.Traces, error),
@@ -72,6 +85,14 @@ func startOTLPReceiver(
 	otlpReceiverConfig := otlpFactory.CreateDefaultConfig().(*otlpreceiver.Config)
 	applyGRPCSettings(otlpReceiverConfig.GRPC, &options.OTLP.GRPC)
 	applyHTTPSettings(otlpReceiverConfig.HTTP.ServerConfig, &options.OTLP.HTTP)
+	host := &otelHost{logger: logger}
+	if authenticator != nil {
+		otlpReceiverConfig.GRPC.Auth = &configauth.Authentication{AuthenticatorID: auth.ExtensionID}
+		otlpReceiverConfig.HTTP.Auth = &configauth.Authentication{AuthenticatorID: auth.ExtensionID}
+		host.extensions = map[component.ID]extension.Extension{
+			auth.ExtensionID: authenticator.ServerExtension(),
+		}
+	}
 	statusReporter := func(ev *component.StatusEvent) {
 		// TODO this could be wired into changing healthcheck.HealthCheck
 		logger.Info("OTLP receiver status change", zap.Stringer("status", ev.Status()))
@@ -100,7 +121,7 @@ func startOTLPReceiver(
 	if err != nil {
 		return nil, fmt.Errorf("could not create the OTLP receiver: %w", err)
 	}
-	if err := otlpReceiver.Start(context.Background(), &otelHost{logger: logger}); err != nil {
+	if err := otlpReceiver.Start(context.Background(), host); err != nil {
 		return nil, fmt.Errorf("could not start the OTLP receiver: %w", err)
 	}
 	return otlpReceiver, nil
@@ -114,7 +135,9 @@ func applyGRPCSettings(cfg *configgrpc.ServerConfig, opts *flags.GRPCOptions) {
 		cfg.TLSSetting = applyTLSSettings(&opts.TLS)
 	}
 	if opts.MaxReceiveMessageLength > 0 {
-		cfg.MaxRecvMsgSizeMiB = uint64(opts.MaxReceiveMessageLength / (1024 * 1024))
+		// gRPC applies the limit to the decompressed messages. It is rounded up to whole MiB,
+		// so that limits lower than 1MiB are not ignored in favor of the default limit.
+		cfg.MaxRecvMsgSizeMiB = uint64((opts.MaxReceiveMessageLength + mib - 1) / mib)
 	}
 	if opts.MaxConnectionAge != 0 || opts.MaxConnectionAgeGrace != 0 {
 		cfg.Keepalive = &configgrpc.KeepaliveServerConfig{
@@ -134,6 +157,15 @@ func applyHTTPSettings(cfg *confighttp.ServerConfig, opts *flags.HTTPOptions) {
 		cfg.TLSSetting = applyTLSSettings(&opts.TLS)
 	}
 
+	if opts.MaxRequestBodySize > 0 {
+		// the limit applies to the decompressed body too, so that decompression bombs are rejected
+		cfg.MaxRequestBodySize = opts.MaxRequestBodySize
+	}
+	if opts.CompressionAlgorithms != nil {
+		// the empty algorithm stands for uncompressed requests
+		cfg.CompressionAlgorithms = append([]string{""}, opts.CompressionAlgorithms...)
+	}
+
 	cfg.CORS = &confighttp.CORSConfig{
 		AllowedOrigins: opts.CORS.AllowedOrigins,
 		AllowedHeaders: opts.CORS.AllowedHeaders,
@@ -187,6 +219,8 @@ func (c *consumerDelegate) consume(ctx context.Context, td ptrace.Traces) error
 // otelHost is a mostly no-op implementation of OTEL component.Host
 type otelHost struct {
 	logger *zap.Logger
+	// extensions are the extensions the receivers refer to, e.g. their authenticator
+	extensions map[component.ID]extension.Extension
 }
 
 func (h *otelHost) ReportFatalError(err error) {
@@ -197,8 +231,8 @@ func (*otelHost) GetFactory(_ component.Kind, _ component.Type) component.Factor
 	return nil
 }
 
-func (*otelHost) GetExtensions() map[component.ID]extension.Extension {
-	return nil
+func (h *otelHost) GetExtensions() map[component.ID]extension.Extension {
+	return h.extensions
 }
 
 func (*otelHost) GetExporters() map[component.DataType]map[component.ID]component.Component {
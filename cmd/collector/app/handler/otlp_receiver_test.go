@@ -17,6 +17,8 @@ package handler
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -27,7 +29,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
@@ -51,7 +55,7 @@ func TestStartOtlpReceiver(t *testing.T) {
 	spanProcessor := &mockSpanProcessor{}
 	logger, _ := testutils.NewLogger()
 	tm := &tenancy.Manager{}
-	rec, err := StartOTLPReceiver(optionsWithPorts(":0"), logger, spanProcessor, tm)
+	rec, err := StartOTLPReceiver(optionsWithPorts(":0"), logger, spanProcessor, tm, nil)
 	require.NoError(t, err)
 	defer func() {
 		require.NoError(t, rec.Shutdown(context.Background()))
@@ -102,7 +106,7 @@ func TestStartOtlpReceiver_Error(t *testing.T) {
 	logger, _ := testutils.NewLogger()
 	opts := optionsWithPorts(":-1")
 	tm := &tenancy.Manager{}
-	_, err := StartOTLPReceiver(opts, logger, spanProcessor, tm)
+	_, err := StartOTLPReceiver(opts, logger, spanProcessor, tm, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not start the OTLP receiver")
 
@@ -110,7 +114,7 @@ func TestStartOtlpReceiver_Error(t *testing.T) {
 		return nil, errors.New("mock error")
 	}
 	f := otlpreceiver.NewFactory()
-	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, f, newTraces, f.CreateTracesReceiver)
+	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, nil, f, newTraces, f.CreateTracesReceiver)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not create the OTLP consumer")
 
@@ -119,11 +123,32 @@ func TestStartOtlpReceiver_Error(t *testing.T) {
 	) (receiver.Traces, error) {
 		return nil, errors.New("mock error")
 	}
-	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, f, consumer.NewTraces, createTracesReceiver)
+	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, nil, f, consumer.NewTraces, createTracesReceiver)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not create the OTLP receiver")
 }
 
+func TestStartOtlpReceiverWithAuth(t *testing.T) {
+	tokens := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(tokens, []byte("secret\n"), 0o600))
+	authenticator, err := auth.NewAuthenticator(auth.Options{BearerTokensFile: tokens}, zap.NewNop())
+	require.NoError(t, err)
+
+	var cfg *otlpreceiver.Config
+	createTracesReceiver := func(
+		_ context.Context, _ receiver.Settings, c component.Config, _ consumer.Traces,
+	) (receiver.Traces, error) {
+		cfg = c.(*otlpreceiver.Config)
+		return nil, errors.New("mock error")
+	}
+	f := otlpreceiver.NewFactory()
+	_, err = startOTLPReceiver(optionsWithPorts(":0"), zap.NewNop(), &mockSpanProcessor{}, &tenancy.Manager{},
+		authenticator, f, consumer.NewTraces, createTracesReceiver)
+	require.Error(t, err)
+	assert.Equal(t, auth.ExtensionID, cfg.GRPC.Auth.AuthenticatorID)
+	assert.Equal(t, auth.ExtensionID, cfg.HTTP.Auth.AuthenticatorID)
+}
+
 func TestProtoFromTracesError(t *testing.T) {
 	mockErr := errors.New("mock error")
 	c := &consumerDelegate{
@@ -212,6 +237,8 @@ func TestApplyOTLPHTTPServerSettings(t *testing.T) {
 			AllowedOrigins: []string{"http://example.domain.com", "http://*.domain.com"},
 			AllowedHeaders: []string{"Content-Type", "Accept", "X-Requested-With"},
 		},
+		MaxRequestBodySize:    1024,
+		CompressionAlgorithms: []string{"gzip"},
 	}
 
 	applyHTTPSettings(otlpReceiverConfig.HTTP.ServerConfig, httpOpts)
@@ -229,4 +256,17 @@ func TestApplyOTLPHTTPServerSettings(t *testing.T) {
 	assert.Equal(t, 24*time.Hour, out.TLSSetting.ReloadInterval)
 	assert.Equal(t, []string{"Content-Type", "Accept", "X-Requested-With"}, out.CORS.AllowedHeaders)
 	assert.Equal(t, []string{"http://example.domain.com", "http://*.domain.com"}, out.CORS.AllowedOrigins)
+	assert.EqualValues(t, 1024, out.MaxRequestBodySize)
+	assert.Equal(t, []string{"", "gzip"}, out.CompressionAlgorithms)
+}
+
+func TestApplyOTLPGRPCServerSettingsRoundsMaxMessageSize(t *testing.T) {
+	otlpFactory := otlpreceiver.NewFactory()
+	otlpReceiverConfig := otlpFactory.CreateDefaultConfig().(*otlpreceiver.Config)
+
+	applyGRPCSettings(otlpReceiverConfig.GRPC, &flags.GRPCOptions{MaxReceiveMessageLength: 512 * 1024})
+	assert.EqualValues(t, 1, otlpReceiverConfig.GRPC.MaxRecvMsgSizeMiB)
+
+	applyGRPCSettings(otlpReceiverConfig.GRPC, &flags.GRPCOptions{MaxReceiveMessageLength: 5*1024*1024 + 1})
+	assert.EqualValues(t, 6, otlpReceiverConfig.GRPC.MaxRecvMsgSizeMiB)
 }
@@ -18,6 +18,7 @@ package app
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model"
@@ -39,6 +40,9 @@ const (
 	// numOfSamplerTypes = 4
 
 	concatenation = "$_$"
+
+	// defaultStorageSink is the sink tag of the ingest latency metrics when the storage is not named
+	defaultStorageSink = "default"
 )
 
 var otherServicesSamplers map[model.SamplerType]string = initOtherServicesSamplers()
@@ -65,8 +69,8 @@ type SpanProcessorMetrics struct {
 	SaveLatency metrics.Timer
 	// InQueueLatency measures how long the span spends in the queue
 	InQueueLatency metrics.Timer
-	// SpansDropped measures the number of spans we discarded because the queue was full
-	SpansDropped metrics.Counter
+	// SpansDropped measures the number of spans we discarded, by reason
+	SpansDropped map[processor.DropReason]metrics.Counter
 	// SpansBytes records how many bytes were processed
 	SpansBytes metrics.Gauge
 	// BatchSize measures the span batch size
@@ -82,6 +86,43 @@ type SpanProcessorMetrics struct {
 	spanCounts    SpanCountsByFormat
 }
 
+// IngestLatencyMetrics break down the end-to-end ingest latency of a sample of spans
+// into the stages of the collector pipeline, for a given storage sink.
+type IngestLatencyMetrics struct {
+	// Total measures the time from the receipt of a span until its storage write returned
+	Total metrics.Timer
+	// Receive measures the time from the receipt of a span until it is enqueued
+	Receive metrics.Timer
+	// Queue measures the time a span waits in the queue until a worker dequeues it
+	Queue metrics.Timer
+	// Storage measures the time from dequeue until the storage write returned
+	Storage metrics.Timer
+}
+
+// NewIngestLatencyMetrics returns IngestLatencyMetrics tagged with the storage sink.
+func NewIngestLatencyMetrics(hostMetrics metrics.Factory, sink string) *IngestLatencyMetrics {
+	timer := func(stage string) metrics.Timer {
+		return hostMetrics.Timer(metrics.TimerOptions{
+			Name: "ingest-latency",
+			Tags: map[string]string{"stage": stage, "sink": sink},
+		})
+	}
+	return &IngestLatencyMetrics{
+		Total:   timer("total"),
+		Receive: timer("receive"),
+		Queue:   timer("queue"),
+		Storage: timer("storage"),
+	}
+}
+
+// Record reports the latency of every stage from the timestamps taken along the pipeline.
+func (m *IngestLatencyMetrics) Record(received, enqueued, dequeued, acked time.Time) {
+	m.Total.Record(acked.Sub(received))
+	m.Receive.Record(enqueued.Sub(received))
+	m.Queue.Record(dequeued.Sub(enqueued))
+	m.Storage.Record(acked.Sub(dequeued))
+}
+
 type countsBySvc struct {
 	counts          map[string]metrics.Counter // counters per service
 	debugCounts     map[string]metrics.Counter // debug counters per service
@@ -133,7 +174,7 @@ func NewSpanProcessorMetrics(serviceMetrics metrics.Factory, hostMetrics metrics
 	m := &SpanProcessorMetrics{
 		SaveLatency:    hostMetrics.Timer(metrics.TimerOptions{Name: "save-latency", Tags: nil}),
 		InQueueLatency: hostMetrics.Timer(metrics.TimerOptions{Name: "in-queue-latency", Tags: nil}),
-		SpansDropped:   hostMetrics.Counter(metrics.Options{Name: "spans.dropped", Tags: nil}),
+		SpansDropped:   newSpansDropped(hostMetrics),
 		BatchSize:      hostMetrics.Gauge(metrics.Options{Name: "batch-size", Tags: nil}),
 		QueueCapacity:  hostMetrics.Gauge(metrics.Options{Name: "queue-capacity", Tags: nil}),
 		QueueLength:    hostMetrics.Gauge(metrics.Options{Name: "queue-length", Tags: nil}),
@@ -147,6 +188,14 @@ func NewSpanProcessorMetrics(serviceMetrics metrics.Factory, hostMetrics metrics
 	return m
 }
 
+func newSpansDropped(hostMetrics metrics.Factory) map[processor.DropReason]metrics.Counter {
+	m := make(map[processor.DropReason]metrics.Counter, len(processor.AllDropReasons))
+	for _, reason := range processor.AllDropReasons {
+		m[reason] = hostMetrics.Counter(metrics.Options{Name: "spans.dropped", Tags: map[string]string{"reason": string(reason)}})
+	}
+	return m
+}
+
 func newMetricsBySvc(factory metrics.Factory, category string) metricsBySvc {
 	spansFactory := factory.Namespace(metrics.NSOptions{Name: "spans", Tags: nil})
 	tracesFactory := factory.Namespace(metrics.NSOptions{Name: "traces", Tags: nil})
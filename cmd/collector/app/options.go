@@ -16,6 +16,8 @@
 package app
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
@@ -26,23 +28,33 @@ import (
 )
 
 type options struct {
-	logger                 *zap.Logger
-	serviceMetrics         metrics.Factory
-	hostMetrics            metrics.Factory
-	preProcessSpans        ProcessSpans // see docs in PreProcessSpans option.
-	sanitizer              sanitizer.SanitizeSpan
-	preSave                ProcessSpan
-	spanFilter             FilterSpan
-	numWorkers             int
-	blockingSubmit         bool
-	queueSize              int
-	dynQueueSizeWarmup     uint
-	dynQueueSizeMemory     uint
-	reportBusy             bool
-	extraFormatTypes       []processor.SpanFormat
-	collectorTags          map[string]string
-	spanSizeMetricsEnabled bool
-	onDroppedSpan          func(span *model.Span)
+	logger                  *zap.Logger
+	serviceMetrics          metrics.Factory
+	hostMetrics             metrics.Factory
+	preProcessSpans         ProcessSpans // see docs in PreProcessSpans option.
+	sanitizer               sanitizer.SanitizeSpan
+	preSave                 ProcessSpan
+	spanFilter              FilterSpan
+	numWorkers              int
+	workersAutoTuning       flags.WorkersAutoTuning
+	blockingSubmit          bool
+	queueSize               int
+	dynQueueSizeWarmup      uint
+	dynQueueSizeMemory      uint
+	reportBusy              bool
+	extraFormatTypes        []processor.SpanFormat
+	collectorTags           map[string]string
+	provenanceAttributes    []string
+	provenanceInstance      string
+	resourceAttributes      map[string]string
+	spanKindPolicies        []flags.SpanKindPolicy
+	queueFullPolicies       map[processor.SpanFormat]flags.QueueFullPolicy
+	queueFullBlockTimeout   time.Duration
+	spanSizeMetricsEnabled  bool
+	onDroppedSpan           func(span *model.Span)
+	ingestLatencySampling   float64
+	droppedSpansLogSampling float64
+	storageSink             string
 }
 
 // Option is a function that sets some option on StorageBuilder.
@@ -74,7 +86,8 @@ func (options) HostMetrics(hostMetrics metrics.Factory) Option {
 
 // PreProcessSpans creates an Option that initializes the preProcessSpans function.
 // This function can implement non-standard pre-processing of the spans when extending
-// the collector from source. Jaeger itself does not define any pre-processing.
+// the collector from source. Jaeger itself only uses it to let the adaptive sampling
+// aggregator observe the received spans, see flags.SamplingObservationReceived.
 func (options) PreProcessSpans(preProcessSpans ProcessSpans) Option {
 	return func(b *options) {
 		b.preProcessSpans = preProcessSpans
@@ -109,6 +122,13 @@ func (options) NumWorkers(numWorkers int) Option {
 	}
 }
 
+// WorkersAutoTuning creates an Option that initializes the auto-tuning of the number of workers
+func (options) WorkersAutoTuning(cfg flags.WorkersAutoTuning) Option {
+	return func(b *options) {
+		b.workersAutoTuning = cfg
+	}
+}
+
 // BlockingSubmit creates an Option that initializes the blockingSubmit boolean
 func (options) BlockingSubmit(blockingSubmit bool) Option {
 	return func(b *options) {
@@ -158,6 +178,39 @@ func (options) CollectorTags(extraTags map[string]string) Option {
 	}
 }
 
+// Provenance creates an Option that initializes the provenance attributes added as tags to each span,
+// see flags.ProvenanceInstance and the like
+func (options) Provenance(attributes []string, instance string) Option {
+	return func(b *options) {
+		b.provenanceAttributes = attributes
+		b.provenanceInstance = instance
+	}
+}
+
+// ResourceAttributes creates an Option that initializes the deployment attributes added to the
+// Process tags of the spans that do not have them
+func (options) ResourceAttributes(attributes map[string]string) Option {
+	return func(b *options) {
+		b.resourceAttributes = attributes
+	}
+}
+
+// SpanKindPolicies creates an Option that initializes the policies rejecting spans by kind before they are queued
+func (options) SpanKindPolicies(policies []flags.SpanKindPolicy) Option {
+	return func(b *options) {
+		b.spanKindPolicies = policies
+	}
+}
+
+// QueueFullPolicies creates an Option that initializes the policies of the receivers, by span format,
+// when the queue is full, and the longest time the receivers with the block policy wait for room in the queue
+func (options) QueueFullPolicies(policies map[processor.SpanFormat]flags.QueueFullPolicy, blockTimeout time.Duration) Option {
+	return func(b *options) {
+		b.queueFullPolicies = policies
+		b.queueFullBlockTimeout = blockTimeout
+	}
+}
+
 // SpanSizeMetricsEnabled creates an Option that initializes the spanSizeMetrics boolean
 func (options) SpanSizeMetricsEnabled(spanSizeMetrics bool) Option {
 	return func(b *options) {
@@ -172,6 +225,30 @@ func (options) OnDroppedSpan(onDroppedSpan func(span *model.Span)) Option {
 	}
 }
 
+// IngestLatencySampling creates an Option that initializes the fraction of spans, between 0 and 1,
+// for which the end-to-end ingest latency is measured
+func (options) IngestLatencySampling(ratio float64) Option {
+	return func(b *options) {
+		b.ingestLatencySampling = ratio
+	}
+}
+
+// DroppedSpansLogSampling creates an Option that initializes the fraction of dropped spans, between 0 and 1,
+// logged at debug level with the reason why they were dropped
+func (options) DroppedSpansLogSampling(ratio float64) Option {
+	return func(b *options) {
+		b.droppedSpansLogSampling = ratio
+	}
+}
+
+// StorageSink creates an Option that initializes the name of the storage the spans are written to,
+// used to tag the ingest latency metrics
+func (options) StorageSink(name string) Option {
+	return func(b *options) {
+		b.storageSink = name
+	}
+}
+
 func (options) apply(opts ...Option) options {
 	ret := options{}
 	for _, opt := range opts {
@@ -201,5 +278,11 @@ func (options) apply(opts ...Option) options {
 	if ret.numWorkers == 0 {
 		ret.numWorkers = flags.DefaultNumWorkers
 	}
+	if ret.queueFullBlockTimeout == 0 {
+		ret.queueFullBlockTimeout = flags.DefaultQueueFullBlockTimeout
+	}
+	if ret.storageSink == "" {
+		ret.storageSink = defaultStorageSink
+	}
 	return ret
 }
@@ -17,6 +17,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -38,6 +39,7 @@ func TestAllOptionSet(t *testing.T) {
 		Options.ServiceMetrics(metrics.NullFactory),
 		Options.Logger(zap.NewNop()),
 		Options.NumWorkers(5),
+		Options.WorkersAutoTuning(flags.WorkersAutoTuning{Enabled: true, MinWorkers: 1, MaxWorkers: 10}),
 		Options.PreProcessSpans(func(_ []*model.Span, _ /* tenant */ string) {}),
 		Options.Sanitizer(func(span *model.Span) *model.Span { return span }),
 		Options.QueueSize(10),
@@ -47,14 +49,24 @@ func TestAllOptionSet(t *testing.T) {
 		Options.CollectorTags(map[string]string{"extra": "tags"}),
 		Options.SpanSizeMetricsEnabled(true),
 		Options.OnDroppedSpan(func(_ *model.Span) {}),
+		Options.IngestLatencySampling(0.5),
+		Options.DroppedSpansLogSampling(0.1),
+		Options.StorageSink("cassandra"),
+		Options.QueueFullPolicies(map[processor.SpanFormat]flags.QueueFullPolicy{processor.OTLPSpanFormat: flags.QueueFullBlock}, time.Minute),
 	)
 	assert.EqualValues(t, 5, opts.numWorkers)
+	assert.Equal(t, flags.WorkersAutoTuning{Enabled: true, MinWorkers: 1, MaxWorkers: 10}, opts.workersAutoTuning)
 	assert.EqualValues(t, 10, opts.queueSize)
 	assert.EqualValues(t, map[string]string{"extra": "tags"}, opts.collectorTags)
 	assert.EqualValues(t, 1000, opts.dynQueueSizeWarmup)
 	assert.EqualValues(t, 1024, opts.dynQueueSizeMemory)
 	assert.True(t, opts.spanSizeMetricsEnabled)
 	assert.NotNil(t, opts.onDroppedSpan)
+	assert.InDelta(t, 0.5, opts.ingestLatencySampling, 0)
+	assert.InDelta(t, 0.1, opts.droppedSpansLogSampling, 0)
+	assert.Equal(t, "cassandra", opts.storageSink)
+	assert.Equal(t, map[processor.SpanFormat]flags.QueueFullPolicy{processor.OTLPSpanFormat: flags.QueueFullBlock}, opts.queueFullPolicies)
+	assert.Equal(t, time.Minute, opts.queueFullBlockTimeout)
 }
 
 func TestNoOptionsSet(t *testing.T) {
@@ -64,6 +76,8 @@ func TestNoOptionsSet(t *testing.T) {
 	assert.Nil(t, opts.collectorTags)
 	assert.False(t, opts.reportBusy)
 	assert.False(t, opts.blockingSubmit)
+	assert.Nil(t, opts.queueFullPolicies)
+	assert.Equal(t, flags.DefaultQueueFullBlockTimeout, opts.queueFullBlockTimeout)
 	assert.NotPanics(t, func() { opts.preProcessSpans(nil, "") })
 	assert.NotPanics(t, func() { opts.preSave(nil, "") })
 	assert.True(t, opts.spanFilter(nil))
@@ -72,4 +86,7 @@ func TestNoOptionsSet(t *testing.T) {
 	assert.EqualValues(t, 0, opts.dynQueueSizeWarmup)
 	assert.False(t, opts.spanSizeMetricsEnabled)
 	assert.Nil(t, opts.onDroppedSpan)
+	assert.Zero(t, opts.ingestLatencySampling)
+	assert.Zero(t, opts.droppedSpansLogSampling)
+	assert.Equal(t, defaultStorageSink, opts.storageSink)
 }
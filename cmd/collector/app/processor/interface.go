@@ -38,6 +38,37 @@ type SpanProcessor interface {
 	io.Closer
 }
 
+// DropReason identifies why spans were dropped by the collector.
+type DropReason string
+
+const (
+	// QueueFullDropReason indicates spans dropped because the internal queue was full.
+	QueueFullDropReason DropReason = "queue_full"
+	// SanitizerRejectDropReason indicates spans rejected as invalid by the sanitizers.
+	SanitizerRejectDropReason DropReason = "sanitizer_reject"
+	// StorageErrorDropReason indicates spans the storage failed to write.
+	StorageErrorDropReason DropReason = "storage_error"
+	// QuotaDropReason indicates spans dropped because a quota was exceeded.
+	QuotaDropReason DropReason = "quota"
+	// TenantInvalidDropReason indicates spans rejected because their tenant was missing or unknown.
+	TenantInvalidDropReason DropReason = "tenant_invalid"
+)
+
+// AllDropReasons are the reasons why spans may be dropped.
+var AllDropReasons = []DropReason{
+	QueueFullDropReason,
+	SanitizerRejectDropReason,
+	StorageErrorDropReason,
+	QuotaDropReason,
+	TenantInvalidDropReason,
+}
+
+// DroppedSpansReporter is implemented by the span processors that account for the spans
+// dropped before reaching them, e.g. by the handlers rejecting the spans of an invalid tenant.
+type DroppedSpansReporter interface {
+	ReportDroppedSpans(mSpans []*model.Span, reason DropReason)
+}
+
 // InboundTransport identifies the transport used to receive spans.
 type InboundTransport string
 
@@ -62,6 +93,8 @@ const (
 	ProtoSpanFormat SpanFormat = "proto"
 	// OTLPSpanFormat is for OpenTelemetry OTLP format.
 	OTLPSpanFormat SpanFormat = "otlp"
+	// DatadogSpanFormat is for spans sent by Datadog tracers.
+	DatadogSpanFormat SpanFormat = "datadog"
 	// UnknownSpanFormat is the fallback/catch-all category.
 	UnknownSpanFormat SpanFormat = "unknown"
 )
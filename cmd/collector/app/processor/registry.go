@@ -0,0 +1,189 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// DefaultRegistry is the registry of the span processors that the collector can enable
+// with --collector.processors. Distributions compiling in their own processors usually
+// register them from an init function of their package.
+var DefaultRegistry = NewRegistry()
+
+// ProcessSpanFunc processes a span before it is written to storage, and may modify it.
+type ProcessSpanFunc func(span *model.Span, tenant string)
+
+// Params are the dependencies passed to the constructors of registered span processors.
+type Params struct {
+	Logger         *zap.Logger
+	MetricsFactory metrics.Factory
+}
+
+// Registration describes a span processor that can be enabled by name.
+type Registration struct {
+	// Name identifies the processor in the configuration.
+	Name string
+	// After lists the processors that must run before this one when they are enabled too.
+	After []string
+	// Before lists the processors that must run after this one when they are enabled too.
+	Before []string
+	// New creates the processor. It is called once when the collector starts.
+	New func(params Params) (ProcessSpanFunc, error)
+}
+
+// Registry holds the span processors compiled into the collector.
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[string]Registration
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{registrations: make(map[string]Registration)}
+}
+
+// Register adds a span processor to the registry.
+func (r *Registry) Register(registration Registration) error {
+	if registration.Name == "" {
+		return errors.New("span processor must have a name")
+	}
+	if registration.New == nil {
+		return fmt.Errorf("span processor %q has no constructor", registration.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.registrations[registration.Name]; ok {
+		return fmt.Errorf("span processor %q is already registered", registration.Name)
+	}
+	r.registrations[registration.Name] = registration
+	return nil
+}
+
+// MustRegister is like Register but panics on errors, for use in init functions.
+func (r *Registry) MustRegister(registration Registration) {
+	if err := r.Register(registration); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns the sorted names of the registered span processors.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.namesLocked()
+}
+
+// Build creates the enabled span processors in the order they must run. The ordering
+// constraints of the registrations take precedence over the order of the names, which
+// is kept otherwise. Constraints referring to processors that are not enabled are ignored.
+func (r *Registry) Build(names []string, params Params) ([]ProcessSpanFunc, error) {
+	ordered, err := r.order(names)
+	if err != nil {
+		return nil, err
+	}
+	processors := make([]ProcessSpanFunc, 0, len(ordered))
+	for _, registration := range ordered {
+		p := params
+		p.Logger = params.Logger.With(zap.String("span-processor", registration.Name))
+		p.MetricsFactory = params.MetricsFactory.Namespace(metrics.NSOptions{
+			Name: "span_processor",
+			Tags: map[string]string{"name": registration.Name},
+		})
+		processor, err := registration.New(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create span processor %q: %w", registration.Name, err)
+		}
+		processors = append(processors, processor)
+	}
+	return processors, nil
+}
+
+func (r *Registry) order(names []string) ([]Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	position := make(map[string]int, len(names))
+	enabled := make([]Registration, 0, len(names))
+	for _, name := range names {
+		registration, ok := r.registrations[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown span processor %q, registered processors: %s", name, strings.Join(r.namesLocked(), ", "))
+		}
+		if _, ok := position[name]; ok {
+			return nil, fmt.Errorf("span processor %q is enabled more than once", name)
+		}
+		position[name] = len(enabled)
+		enabled = append(enabled, registration)
+	}
+
+	// edges[i] are the processors that must run after processor i
+	edges := make([][]int, len(enabled))
+	inDegree := make([]int, len(enabled))
+	addEdge := func(from, to string) {
+		i, ok := position[from]
+		if !ok {
+			return
+		}
+		j, ok := position[to]
+		if !ok {
+			return
+		}
+		edges[i] = append(edges[i], j)
+		inDegree[j]++
+	}
+	for _, registration := range enabled {
+		for _, name := range registration.After {
+			addEdge(name, registration.Name)
+		}
+		for _, name := range registration.Before {
+			addEdge(registration.Name, name)
+		}
+	}
+
+	// topological sort, picking the ready processor enabled first
+	ordered := make([]Registration, 0, len(enabled))
+	done := make([]bool, len(enabled))
+	for len(ordered) < len(enabled) {
+		next := -1
+		for i := range enabled {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, registration := range enabled {
+				if !done[i] {
+					cycle = append(cycle, registration.Name)
+				}
+			}
+			return nil, fmt.Errorf("ordering constraints of span processors %s form a cycle", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, enabled[next])
+		for _, j := range edges[next] {
+			inDegree[j]--
+		}
+	}
+	return ordered, nil
+}
+
+func (r *Registry) namesLocked() []string {
+	names := make([]string, 0, len(r.registrations))
+	for name := range r.registrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
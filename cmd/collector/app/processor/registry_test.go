@@ -0,0 +1,172 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// tagging registers a processor that appends its name to the "processors" tag of the span.
+func tagging(name string, after, before []string) Registration {
+	return Registration{
+		Name:   name,
+		After:  after,
+		Before: before,
+		New: func(Params) (ProcessSpanFunc, error) {
+			return func(span *model.Span, _ string) {
+				span.Tags = append(span.Tags, model.String("processors", name))
+			}, nil
+		},
+	}
+}
+
+func runProcessors(processors []ProcessSpanFunc) []string {
+	span := &model.Span{}
+	for _, p := range processors {
+		p(span, "")
+	}
+	names := []string{}
+	for _, tag := range span.Tags {
+		names = append(names, tag.VStr)
+	}
+	return names
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(tagging("b", nil, nil)))
+	require.NoError(t, r.Register(tagging("a", nil, nil)))
+	assert.Equal(t, []string{"a", "b"}, r.Names())
+
+	require.EqualError(t, r.Register(tagging("a", nil, nil)), `span processor "a" is already registered`)
+	require.EqualError(t, r.Register(tagging("", nil, nil)), "span processor must have a name")
+	require.EqualError(t, r.Register(Registration{Name: "c"}), `span processor "c" has no constructor`)
+	assert.Panics(t, func() { r.MustRegister(tagging("a", nil, nil)) })
+}
+
+func TestRegistryBuildOrder(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(tagging("geoip", nil, nil))
+	r.MustRegister(tagging("tenant-enricher", []string{"geoip"}, nil))
+	r.MustRegister(tagging("normalizer", nil, []string{"geoip", "redactor"}))
+	r.MustRegister(tagging("redactor", []string{"unknown"}, nil))
+
+	testCases := []struct {
+		name     string
+		enabled  []string
+		expected []string
+	}{
+		{
+			name:     "none",
+			expected: []string{},
+		},
+		{
+			name:     "configured order without constraints",
+			enabled:  []string{"redactor", "geoip"},
+			expected: []string{"redactor", "geoip"},
+		},
+		{
+			name:     "after constraint",
+			enabled:  []string{"tenant-enricher", "geoip", "redactor"},
+			expected: []string{"geoip", "tenant-enricher", "redactor"},
+		},
+		{
+			name:     "before constraint",
+			enabled:  []string{"redactor", "geoip", "normalizer"},
+			expected: []string{"normalizer", "redactor", "geoip"},
+		},
+		{
+			name:     "constraints on disabled processors are ignored",
+			enabled:  []string{"tenant-enricher", "redactor"},
+			expected: []string{"tenant-enricher", "redactor"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			processors, err := r.Build(tc.enabled, Params{Logger: zap.NewNop(), MetricsFactory: metrics.NullFactory})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, runProcessors(processors))
+		})
+	}
+}
+
+func TestRegistryBuildErrors(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(tagging("a", []string{"b"}, nil))
+	r.MustRegister(tagging("b", []string{"c"}, nil))
+	r.MustRegister(tagging("c", []string{"a"}, nil))
+	r.MustRegister(tagging("d", nil, nil))
+	r.MustRegister(Registration{
+		Name: "broken",
+		New: func(Params) (ProcessSpanFunc, error) {
+			return nil, errors.New("bad config")
+		},
+	})
+	params := Params{Logger: zap.NewNop(), MetricsFactory: metrics.NullFactory}
+
+	testCases := []struct {
+		name    string
+		enabled []string
+		err     string
+	}{
+		{
+			name:    "unknown processor",
+			enabled: []string{"d", "e"},
+			err:     `unknown span processor "e", registered processors: a, b, broken, c, d`,
+		},
+		{
+			name:    "duplicate processor",
+			enabled: []string{"d", "d"},
+			err:     `span processor "d" is enabled more than once`,
+		},
+		{
+			name:    "cycle",
+			enabled: []string{"d", "a", "b", "c"},
+			err:     "ordering constraints of span processors a, b, c form a cycle",
+		},
+		{
+			name:    "constructor error",
+			enabled: []string{"d", "broken"},
+			err:     `failed to create span processor "broken": bad config`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := r.Build(tc.enabled, params)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestRegistryBuildParams(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(Registration{
+		Name: "counter",
+		New: func(params Params) (ProcessSpanFunc, error) {
+			counter := params.MetricsFactory.Counter(metrics.Options{Name: "spans"})
+			return func(*model.Span, string) {
+				counter.Inc(1)
+			}, nil
+		},
+	})
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+
+	processors, err := r.Build([]string{"counter"}, Params{Logger: zap.NewNop(), MetricsFactory: metricsFactory})
+	require.NoError(t, err)
+	require.Len(t, processors, 1)
+	processors[0](&model.Span{}, "")
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "span_processor.spans", Tags: map[string]string{"name": "counter"}, Value: 1,
+	})
+}
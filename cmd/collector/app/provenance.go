@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/version"
+)
+
+// provenanceTagPrefix is the namespace of the provenance tags.
+const provenanceTagPrefix = "jaeger.ingest."
+
+// provenance adds tags recording how each span was received, so that data issues
+// can be traced back to a collector instance, receiver or release.
+type provenance struct {
+	instance  string
+	protocol  bool
+	timestamp bool
+	version   string
+}
+
+// newProvenance returns nil when no provenance attribute is enabled.
+func newProvenance(attributes []string, instance string) *provenance {
+	if len(attributes) == 0 {
+		return nil
+	}
+	p := &provenance{}
+	for _, attribute := range attributes {
+		switch attribute {
+		case flags.ProvenanceInstance:
+			p.instance = instance
+		case flags.ProvenanceProtocol:
+			p.protocol = true
+		case flags.ProvenanceTimestamp:
+			p.timestamp = true
+		case flags.ProvenanceVersion:
+			p.version = version.Get().GitVersion
+			if p.version == "" {
+				// development builds are not stamped with a version
+				p.version = "unknown"
+			}
+		}
+	}
+	return p
+}
+
+func (p *provenance) addTags(span *model.Span, format processor.SpanFormat, transport processor.InboundTransport, receivedTime time.Time) {
+	if p.instance != "" {
+		span.Tags = append(span.Tags, model.String(provenanceTagPrefix+flags.ProvenanceInstance, p.instance))
+	}
+	if p.protocol {
+		span.Tags = append(span.Tags, model.String(provenanceTagPrefix+flags.ProvenanceProtocol, string(transport)+"/"+string(format)))
+	}
+	if p.timestamp {
+		span.Tags = append(span.Tags, model.String(provenanceTagPrefix+flags.ProvenanceTimestamp, receivedTime.UTC().Format(time.RFC3339Nano)))
+	}
+	if p.version != "" {
+		span.Tags = append(span.Tags, model.String(provenanceTagPrefix+flags.ProvenanceVersion, p.version))
+	}
+}
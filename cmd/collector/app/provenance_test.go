@@ -0,0 +1,48 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestProvenance(t *testing.T) {
+	receivedTime := time.Date(2024, 5, 1, 12, 0, 0, 500, time.FixedZone("CEST", 2*60*60))
+	testCases := []struct {
+		name       string
+		attributes []string
+		expected   []model.KeyValue
+	}{
+		{
+			name:       "all attributes",
+			attributes: []string{"instance", "protocol", "timestamp", "version"},
+			expected: []model.KeyValue{
+				model.String("jaeger.ingest.instance", "collector-1"),
+				model.String("jaeger.ingest.protocol", "grpc/proto"),
+				model.String("jaeger.ingest.timestamp", "2024-05-01T10:00:00.0000005Z"),
+				model.String("jaeger.ingest.version", "unknown"),
+			},
+		},
+		{
+			name:       "protocol only",
+			attributes: []string{"protocol"},
+			expected:   []model.KeyValue{model.String("jaeger.ingest.protocol", "grpc/proto")},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvenance(test.attributes, "collector-1")
+			span := &model.Span{}
+			p.addTags(span, processor.ProtoSpanFormat, processor.GRPCTransport, receivedTime)
+			assert.Equal(t, test.expected, span.Tags)
+		})
+	}
+	assert.Nil(t, newProvenance(nil, "collector-1"))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+)
+
+// otherReceivers tags the queue_full_policy.spans metric of the receivers without a policy.
+const otherReceivers = "other"
+
+// queueFullPolicy is how the spans of a receiver are handled when the queue is full, see flags.QueueFullPolicy.
+type queueFullPolicy struct {
+	policy flags.QueueFullPolicy
+	spans  metrics.Counter
+}
+
+// queueFullPolicies are the policies of the receivers by span format.
+type queueFullPolicies struct {
+	byFormat     map[processor.SpanFormat]*queueFullPolicy
+	fallback     *queueFullPolicy
+	blockTimeout time.Duration
+}
+
+// newQueueFullPolicies creates the policies of the receivers, the spans of the receivers without a policy
+// being dropped, or failed if reportBusy is set.
+func newQueueFullPolicies(
+	policies map[processor.SpanFormat]flags.QueueFullPolicy,
+	blockTimeout time.Duration,
+	reportBusy bool,
+	metricsFactory metrics.Factory,
+) *queueFullPolicies {
+	newPolicy := func(receiver string, policy flags.QueueFullPolicy) *queueFullPolicy {
+		return &queueFullPolicy{
+			policy: policy,
+			spans: metricsFactory.Counter(metrics.Options{
+				Name: "queue_full_policy.spans",
+				Tags: map[string]string{"receiver": receiver, "policy": string(policy)},
+				Help: "Number of spans received while the queue was full, by receiver and queue full policy",
+			}),
+		}
+	}
+	fallback := flags.QueueFullDrop
+	if reportBusy {
+		fallback = flags.QueueFullFail
+	}
+	p := &queueFullPolicies{
+		byFormat:     make(map[processor.SpanFormat]*queueFullPolicy, len(policies)),
+		fallback:     newPolicy(otherReceivers, fallback),
+		blockTimeout: blockTimeout,
+	}
+	for format, policy := range policies {
+		p.byFormat[format] = newPolicy(string(format), policy)
+	}
+	return p
+}
+
+func (p *queueFullPolicies) get(format processor.SpanFormat) *queueFullPolicy {
+	if policy, ok := p.byFormat[format]; ok {
+		return policy
+	}
+	return p.fallback
+}
+
+// produce submits the item to the queue following the policy, returning false if the item was dropped.
+// The caller fails the request if the policy is flags.QueueFullFail.
+func (p *queueFullPolicies) produce(q *queue.BoundedQueue, policy *queueFullPolicy, item any) bool {
+	if policy.policy != flags.QueueFullBlock {
+		if !q.Produce(item) {
+			policy.spans.Inc(1)
+			return false
+		}
+		return true
+	}
+	if q.Size() >= q.Capacity() {
+		policy.spans.Inc(1)
+	}
+	return q.ProduceWithTimeout(item, p.blockTimeout)
+}
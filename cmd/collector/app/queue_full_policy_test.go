@@ -0,0 +1,86 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+func TestQueueFullPolicies(t *testing.T) {
+	policies := newQueueFullPolicies(map[processor.SpanFormat]flags.QueueFullPolicy{
+		processor.OTLPSpanFormat: flags.QueueFullBlock,
+	}, time.Second, false, metrics.NullFactory)
+	assert.Equal(t, flags.QueueFullBlock, policies.get(processor.OTLPSpanFormat).policy)
+	assert.Equal(t, flags.QueueFullDrop, policies.get(processor.ZipkinSpanFormat).policy)
+
+	policies = newQueueFullPolicies(nil, time.Second, true, metrics.NullFactory)
+	assert.Equal(t, flags.QueueFullFail, policies.get(processor.ZipkinSpanFormat).policy, "report busy fails the other receivers")
+}
+
+func TestSpanProcessorQueueFullPolicies(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	w := &blockingWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+		Options.NumWorkers(1),
+		Options.QueueSize(1),
+		Options.QueueFullPolicies(map[processor.SpanFormat]flags.QueueFullPolicy{
+			processor.OTLPSpanFormat:  flags.QueueFullBlock,
+			processor.ProtoSpanFormat: flags.QueueFullFail,
+		}, time.Minute),
+	)
+	defer func() { require.NoError(t, p.Close()) }()
+	process := func(format processor.SpanFormat) ([]bool, error) {
+		return p.ProcessSpans([]*model.Span{{OperationName: string(format)}}, processor.SpansOptions{SpanFormat: format})
+	}
+
+	// block the writer so that the first span blocks the sole worker and the second one fills the queue
+	w.Lock()
+	_, err := process(processor.OTLPSpanFormat)
+	require.NoError(t, err)
+	assert.Eventually(t,
+		func() bool { return w.inWriteSpan.Load() == 1 },
+		time.Second, time.Microsecond)
+	res, err := process(processor.OTLPSpanFormat)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, res)
+
+	res, err = process(processor.JaegerSpanFormat)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, res, "dropped")
+
+	_, err = process(processor.ProtoSpanFormat)
+	require.ErrorIs(t, err, processor.ErrBusy)
+
+	blocked := make(chan []bool)
+	go func() {
+		res, err := process(processor.OTLPSpanFormat)
+		assert.NoError(t, err)
+		blocked <- res
+	}()
+	assert.Eventually(t, func() bool {
+		counters, _ := mb.Snapshot()
+		return counters["host.queue_full_policy.spans|policy=block|receiver=otlp"] == 1
+	}, time.Second, time.Millisecond)
+	w.Unlock()
+	assert.Equal(t, []bool{true}, <-blocked, "enqueued once the worker made room")
+
+	mb.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "host.queue_full_policy.spans", Tags: map[string]string{"receiver": "other", "policy": "drop"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "host.queue_full_policy.spans", Tags: map[string]string{"receiver": "proto", "policy": "fail"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "host.queue_full_policy.spans", Tags: map[string]string{"receiver": "otlp", "policy": "block"}, Value: 1},
+	)
+}
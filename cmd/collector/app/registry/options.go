@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagPrefix            = "collector.registry"
+	flagType              = flagPrefix + ".type"
+	flagEndpoint          = flagPrefix + ".endpoint"
+	flagKey               = flagPrefix + ".key"
+	flagInstanceID        = flagPrefix + ".instance-id"
+	flagInterval          = flagPrefix + ".interval"
+	flagPressureThreshold = flagPrefix + ".pressure-threshold"
+	flagTokenFile         = flagPrefix + ".token-file"
+
+	// TypeConsul updates a TTL check of a Consul agent.
+	TypeConsul = "consul"
+	// TypeEtcd writes the report to a key of etcd via its v3 JSON gateway.
+	TypeEtcd = "etcd"
+	// TypeHTTP posts the report as JSON to an HTTP endpoint.
+	TypeHTTP = "http"
+
+	defaultInterval          = 10 * time.Second
+	defaultPressureThreshold = 0.8
+)
+
+// Options configures publishing of the collector health to a service registry.
+type Options struct {
+	// Type is the kind of registry, publishing is disabled when empty.
+	Type string
+	// Endpoint is the base URL of the registry, or the URL receiving the reports for TypeHTTP.
+	Endpoint string
+	// Key is the Consul check ID or the etcd key updated with the reports.
+	Key string
+	// InstanceID identifies this collector replica in the reports.
+	InstanceID string
+	// Interval is how often the health is published.
+	Interval time.Duration
+	// PressureThreshold is the fraction of the queue capacity above which
+	// the replica is reported as saturated.
+	PressureThreshold float64
+	// Token authenticates the requests to the registry.
+	Token string
+}
+
+// AddFlags adds flags for the service registry integration to the FlagSet.
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(flagType, "", fmt.Sprintf("The type of service registry to publish the collector health and queue pressure to, one of %s, %s, %s; disabled when empty", TypeConsul, TypeEtcd, TypeHTTP))
+	flags.String(flagEndpoint, "", "The base URL of the Consul agent or the etcd gateway, or the URL receiving the JSON health reports for the http registry")
+	flags.String(flagKey, "", "The Consul TTL check ID or the etcd key updated with the collector health")
+	flags.String(flagInstanceID, "", "The ID of this collector replica in the health reports; the hostname by default")
+	flags.Duration(flagInterval, defaultInterval, "How often the collector health is published; Consul TTL checks should allow for a few missed updates")
+	flags.Float64(flagPressureThreshold, defaultPressureThreshold, "The fraction of the queue capacity above which the collector is reported as saturated, e.g. with the warning status of the Consul check")
+	flags.String(flagTokenFile, "", "Path to a file with the token authenticating requests to the service registry")
+}
+
+// InitFromViper initializes Options with properties from viper.
+func InitFromViper(v *viper.Viper) (Options, error) {
+	opts := Options{
+		Type:              v.GetString(flagType),
+		Endpoint:          strings.TrimSuffix(v.GetString(flagEndpoint), "/"),
+		Key:               v.GetString(flagKey),
+		InstanceID:        v.GetString(flagInstanceID),
+		Interval:          v.GetDuration(flagInterval),
+		PressureThreshold: v.GetFloat64(flagPressureThreshold),
+	}
+	if opts.Type == "" {
+		return opts, nil
+	}
+	switch opts.Type {
+	case TypeConsul, TypeEtcd:
+		if opts.Key == "" {
+			return opts, fmt.Errorf("%s is required for the %s registry", flagKey, opts.Type)
+		}
+	case TypeHTTP:
+	default:
+		return opts, fmt.Errorf("unknown %s %q, expected one of %s, %s, %s", flagType, opts.Type, TypeConsul, TypeEtcd, TypeHTTP)
+	}
+	if opts.Endpoint == "" {
+		return opts, fmt.Errorf("%s is required for the %s registry", flagEndpoint, opts.Type)
+	}
+	if opts.Interval <= 0 {
+		return opts, fmt.Errorf("%s must be positive, got %v", flagInterval, opts.Interval)
+	}
+	if opts.PressureThreshold <= 0 || opts.PressureThreshold > 1 {
+		return opts, fmt.Errorf("%s must be greater than 0 and at most 1, got %v", flagPressureThreshold, opts.PressureThreshold)
+	}
+	if opts.InstanceID == "" {
+		opts.InstanceID, _ = os.Hostname()
+	}
+	if tokenFile := v.GetString(flagTokenFile); tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read service registry token: %w", err)
+		}
+		opts.Token = strings.TrimSpace(string(token))
+	}
+	return opts, nil
+}
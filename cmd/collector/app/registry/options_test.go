@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestInitFromViper(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--collector.registry.type=consul",
+		"--collector.registry.endpoint=http://localhost:8500/",
+		"--collector.registry.key=service:jaeger-collector",
+		"--collector.registry.instance-id=collector-1",
+		"--collector.registry.interval=5s",
+		"--collector.registry.pressure-threshold=0.5",
+		"--collector.registry.token-file=" + tokenFile,
+	}))
+	opts, err := InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		Type:              TypeConsul,
+		Endpoint:          "http://localhost:8500",
+		Key:               "service:jaeger-collector",
+		InstanceID:        "collector-1",
+		Interval:          5 * time.Second,
+		PressureThreshold: 0.5,
+		Token:             "secret",
+	}, opts)
+}
+
+func TestInitFromViperDefaults(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags(nil))
+	opts, err := InitFromViper(v)
+	require.NoError(t, err)
+	assert.Empty(t, opts.Type)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--collector.registry.type=http",
+		"--collector.registry.endpoint=http://registry/collectors",
+	}))
+	opts, err = InitFromViper(v)
+	require.NoError(t, err)
+	hostname, _ := os.Hostname()
+	assert.Equal(t, hostname, opts.InstanceID)
+	assert.Equal(t, defaultInterval, opts.Interval)
+	assert.InDelta(t, defaultPressureThreshold, opts.PressureThreshold, 0)
+}
+
+func TestInitFromViperErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		flags  []string
+		errMsg string
+	}{
+		{
+			name:   "unknown type",
+			flags:  []string{"--collector.registry.type=zookeeper"},
+			errMsg: `unknown collector.registry.type "zookeeper", expected one of consul, etcd, http`,
+		},
+		{
+			name:   "no key",
+			flags:  []string{"--collector.registry.type=etcd", "--collector.registry.endpoint=http://etcd:2379"},
+			errMsg: "collector.registry.key is required for the etcd registry",
+		},
+		{
+			name:   "no endpoint",
+			flags:  []string{"--collector.registry.type=http"},
+			errMsg: "collector.registry.endpoint is required for the http registry",
+		},
+		{
+			name:   "no interval",
+			flags:  []string{"--collector.registry.type=http", "--collector.registry.endpoint=http://registry", "--collector.registry.interval=0s"},
+			errMsg: "collector.registry.interval must be positive, got 0s",
+		},
+		{
+			name:   "bad threshold",
+			flags:  []string{"--collector.registry.type=http", "--collector.registry.endpoint=http://registry", "--collector.registry.pressure-threshold=1.5"},
+			errMsg: "collector.registry.pressure-threshold must be greater than 0 and at most 1, got 1.5",
+		},
+		{
+			name:   "missing token file",
+			flags:  []string{"--collector.registry.type=http", "--collector.registry.endpoint=http://registry", "--collector.registry.token-file=/does/not/exist"},
+			errMsg: "failed to read service registry token",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, command := config.Viperize(AddFlags)
+			require.NoError(t, command.ParseFlags(tc.flags))
+			_, err := InitFromViper(v)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registry publishes the health and the queue pressure of a collector
+// replica to a service registry, so that clients and load balancers can steer
+// traffic away from saturated replicas before they start dropping spans.
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Consul check statuses, see https://developer.hashicorp.com/consul/api-docs/agent/check#ttl-check-update
+const (
+	statusPassing  = "passing"
+	statusWarning  = "warning"
+	statusCritical = "critical"
+)
+
+// Report describes the health of a collector replica.
+type Report struct {
+	InstanceID string `json:"instanceId"`
+	Healthy    bool   `json:"healthy"`
+	// Saturated is true when the queue pressure exceeds the configured threshold.
+	Saturated     bool `json:"saturated"`
+	QueueLength   int  `json:"queueLength"`
+	QueueCapacity int  `json:"queueCapacity"`
+	// Pressure is the fraction of the queue capacity in use.
+	Pressure float64 `json:"pressure"`
+	// Weight, from 0 to 100, is the share of traffic the replica is ready to accept,
+	// e.g. for weighted DNS records; it is 0 for unhealthy replicas.
+	Weight    int       `json:"weight"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// status maps the report to a Consul check status.
+func (r Report) status() string {
+	switch {
+	case !r.Healthy:
+		return statusCritical
+	case r.Saturated:
+		return statusWarning
+	default:
+		return statusPassing
+	}
+}
+
+// Publisher sends health reports to a service registry.
+type Publisher interface {
+	Publish(ctx context.Context, report Report) error
+}
+
+// NewPublisher creates the Publisher for the registry type of the options.
+func NewPublisher(opts Options, client *http.Client) (Publisher, error) {
+	switch opts.Type {
+	case TypeConsul:
+		return &consulPublisher{opts: opts, client: client}, nil
+	case TypeEtcd:
+		return &etcdPublisher{opts: opts, client: client}, nil
+	case TypeHTTP:
+		return &httpPublisher{opts: opts, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown service registry type %q", opts.Type)
+	}
+}
+
+// consulPublisher updates a TTL check, which takes replicas out of the DNS
+// and catalog answers that are filtered by health.
+type consulPublisher struct {
+	opts   Options
+	client *http.Client
+}
+
+func (p *consulPublisher) Publish(ctx context.Context, report Report) error {
+	body, err := json.Marshal(struct {
+		Status string `json:"Status"`
+		Output string `json:"Output"`
+	}{
+		Status: report.status(),
+		Output: fmt.Sprintf("instance %s, queue %d/%d, weight %d", report.InstanceID, report.QueueLength, report.QueueCapacity, report.Weight),
+	})
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if p.opts.Token != "" {
+		header.Set("X-Consul-Token", p.opts.Token)
+	}
+	endpoint := p.opts.Endpoint + "/v1/agent/check/update/" + url.PathEscape(p.opts.Key)
+	return send(ctx, p.client, http.MethodPut, endpoint, header, body)
+}
+
+// etcdPublisher writes the JSON report to a key, watched by smart clients.
+type etcdPublisher struct {
+	opts   Options
+	client *http.Client
+}
+
+func (p *etcdPublisher) Publish(ctx context.Context, report Report) error {
+	value, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{
+		Key:   base64.StdEncoding.EncodeToString([]byte(p.opts.Key)),
+		Value: base64.StdEncoding.EncodeToString(value),
+	})
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if p.opts.Token != "" {
+		header.Set("Authorization", p.opts.Token)
+	}
+	return send(ctx, p.client, http.MethodPost, p.opts.Endpoint+"/v3/kv/put", header, body)
+}
+
+// httpPublisher posts the JSON report to a custom endpoint.
+type httpPublisher struct {
+	opts   Options
+	client *http.Client
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if p.opts.Token != "" {
+		header.Set("Authorization", "Bearer "+p.opts.Token)
+	}
+	return send(ctx, p.client, http.MethodPost, p.opts.Endpoint, header, body)
+}
+
+func send(ctx context.Context, client *http.Client, method, endpoint string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("service registry responded with %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
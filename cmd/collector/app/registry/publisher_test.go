@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newRegistryServer(t *testing.T, statusCode int) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.EscapedPath(), header: r.Header, body: body})
+		w.WriteHeader(statusCode)
+		w.Write([]byte("registry says no\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+var testReport = Report{
+	InstanceID:    "collector-1",
+	Healthy:       true,
+	Saturated:     true,
+	QueueLength:   90,
+	QueueCapacity: 100,
+	Pressure:      0.9,
+	Weight:        10,
+	Timestamp:     time.Unix(1700000000, 0).UTC(),
+}
+
+func TestConsulPublisher(t *testing.T) {
+	server, requests := newRegistryServer(t, http.StatusOK)
+	p, err := NewPublisher(Options{Type: TypeConsul, Endpoint: server.URL, Key: "service:jaeger/collector", Token: "secret"}, server.Client())
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(context.Background(), testReport))
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPut, req.method)
+	assert.Equal(t, "/v1/agent/check/update/service:jaeger%2Fcollector", req.path)
+	assert.Equal(t, "secret", req.header.Get("X-Consul-Token"))
+	assert.JSONEq(t, `{"Status":"warning","Output":"instance collector-1, queue 90/100, weight 10"}`, string(req.body))
+}
+
+func TestEtcdPublisher(t *testing.T) {
+	server, requests := newRegistryServer(t, http.StatusOK)
+	p, err := NewPublisher(Options{Type: TypeEtcd, Endpoint: server.URL, Key: "/jaeger/collectors/1", Token: "secret"}, server.Client())
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(context.Background(), testReport))
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPost, req.method)
+	assert.Equal(t, "/v3/kv/put", req.path)
+	assert.Equal(t, "secret", req.header.Get("Authorization"))
+	var put struct{ Key, Value string }
+	require.NoError(t, json.Unmarshal(req.body, &put))
+	key, err := base64.StdEncoding.DecodeString(put.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "/jaeger/collectors/1", string(key))
+	value, err := base64.StdEncoding.DecodeString(put.Value)
+	require.NoError(t, err)
+	var report Report
+	require.NoError(t, json.Unmarshal(value, &report))
+	assert.Equal(t, testReport, report)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	server, requests := newRegistryServer(t, http.StatusNoContent)
+	p, err := NewPublisher(Options{Type: TypeHTTP, Endpoint: server.URL + "/collectors", Token: "secret"}, server.Client())
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(context.Background(), testReport))
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPost, req.method)
+	assert.Equal(t, "/collectors", req.path)
+	assert.Equal(t, "Bearer secret", req.header.Get("Authorization"))
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	var report Report
+	require.NoError(t, json.Unmarshal(req.body, &report))
+	assert.Equal(t, testReport, report)
+}
+
+func TestPublisherErrors(t *testing.T) {
+	_, err := NewPublisher(Options{Type: "zookeeper"}, http.DefaultClient)
+	require.EqualError(t, err, `unknown service registry type "zookeeper"`)
+
+	server, _ := newRegistryServer(t, http.StatusForbidden)
+	p, err := NewPublisher(Options{Type: TypeHTTP, Endpoint: server.URL}, server.Client())
+	require.NoError(t, err)
+	err = p.Publish(context.Background(), testReport)
+	require.EqualError(t, err, "service registry responded with 403 Forbidden: registry says no")
+
+	p, err = NewPublisher(Options{Type: TypeHTTP, Endpoint: "http://\x7f"}, server.Client())
+	require.NoError(t, err)
+	require.Error(t, p.Publish(context.Background(), testReport))
+}
+
+func TestReportStatus(t *testing.T) {
+	assert.Equal(t, statusPassing, Report{Healthy: true}.status())
+	assert.Equal(t, statusWarning, Report{Healthy: true, Saturated: true}.status())
+	assert.Equal(t, statusCritical, Report{Saturated: true}.status())
+}
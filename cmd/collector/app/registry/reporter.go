@@ -0,0 +1,111 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Status is the health of the collector replica at a point in time.
+type Status struct {
+	Healthy       bool
+	QueueLength   int
+	QueueCapacity int
+}
+
+// StatusFunc returns the current health of the collector replica.
+type StatusFunc func() Status
+
+// Reporter periodically publishes the health of the collector replica.
+type Reporter struct {
+	opts      Options
+	publisher Publisher
+	status    StatusFunc
+	logger    *zap.Logger
+	timeNow   func() time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReporter creates a Reporter publishing to the registry configured by the options.
+func NewReporter(opts Options, status StatusFunc, logger *zap.Logger) (*Reporter, error) {
+	publisher, err := NewPublisher(opts, &http.Client{Timeout: opts.Interval})
+	if err != nil {
+		return nil, err
+	}
+	return &Reporter{
+		opts:      opts,
+		publisher: publisher,
+		status:    status,
+		logger:    logger,
+		timeNow:   time.Now,
+		stop:      make(chan struct{}),
+	}, nil
+}
+
+// Start publishes the health immediately and then at every interval.
+func (r *Reporter) Start() {
+	r.logger.Info("Publishing collector health to service registry",
+		zap.String("type", r.opts.Type), zap.String("endpoint", r.opts.Endpoint), zap.Duration("interval", r.opts.Interval))
+	r.publish(r.report(r.status()))
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.publish(r.report(r.status()))
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the periodic publishing and reports the replica as unhealthy,
+// so that traffic is steered away from it while it shuts down.
+func (r *Reporter) Close() error {
+	close(r.stop)
+	r.wg.Wait()
+	status := r.status()
+	status.Healthy = false
+	r.publish(r.report(status))
+	return nil
+}
+
+func (r *Reporter) publish(report Report) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Interval)
+	defer cancel()
+	if err := r.publisher.Publish(ctx, report); err != nil {
+		r.logger.Warn("Failed to publish collector health to service registry", zap.Error(err))
+	}
+}
+
+func (r *Reporter) report(status Status) Report {
+	report := Report{
+		InstanceID:    r.opts.InstanceID,
+		Healthy:       status.Healthy,
+		QueueLength:   status.QueueLength,
+		QueueCapacity: status.QueueCapacity,
+		Timestamp:     r.timeNow(),
+	}
+	if status.QueueCapacity > 0 {
+		report.Pressure = math.Min(float64(status.QueueLength)/float64(status.QueueCapacity), 1)
+	}
+	report.Saturated = report.Pressure >= r.opts.PressureThreshold
+	if report.Healthy {
+		// a healthy replica keeps a minimal weight even when its queue is full
+		report.Weight = max(int(math.Round(100*(1-report.Pressure))), 1)
+	}
+	return report
+}
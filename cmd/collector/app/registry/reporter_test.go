@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type fakePublisher struct {
+	mu      sync.Mutex
+	reports []Report
+	err     error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, report Report) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reports = append(p.reports, report)
+	return p.err
+}
+
+func (p *fakePublisher) published() []Report {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Report(nil), p.reports...)
+}
+
+func newTestReporter(t *testing.T, interval time.Duration, status StatusFunc) (*Reporter, *fakePublisher) {
+	r, err := NewReporter(Options{
+		Type:              TypeHTTP,
+		Endpoint:          "http://registry",
+		InstanceID:        "collector-1",
+		Interval:          interval,
+		PressureThreshold: 0.8,
+	}, status, zap.NewNop())
+	require.NoError(t, err)
+	publisher := &fakePublisher{}
+	r.publisher = publisher
+	r.timeNow = func() time.Time { return time.Unix(1700000000, 0) }
+	return r, publisher
+}
+
+func TestReporterPublishesPeriodically(t *testing.T) {
+	r, publisher := newTestReporter(t, time.Millisecond, func() Status {
+		return Status{Healthy: true, QueueLength: 25, QueueCapacity: 100}
+	})
+	r.Start()
+	assert.Eventually(t, func() bool {
+		return len(publisher.published()) >= 3
+	}, time.Second, time.Millisecond)
+	require.NoError(t, r.Close())
+
+	reports := publisher.published()
+	assert.Equal(t, Report{
+		InstanceID:    "collector-1",
+		Healthy:       true,
+		QueueLength:   25,
+		QueueCapacity: 100,
+		Pressure:      0.25,
+		Weight:        75,
+		Timestamp:     time.Unix(1700000000, 0),
+	}, reports[0])
+	last := reports[len(reports)-1]
+	assert.False(t, last.Healthy, "the replica is reported unhealthy when closing")
+	assert.Zero(t, last.Weight)
+}
+
+func TestReporterReport(t *testing.T) {
+	r, _ := newTestReporter(t, time.Hour, nil)
+	testCases := []struct {
+		name      string
+		status    Status
+		saturated bool
+		weight    int
+	}{
+		{name: "empty queue", status: Status{Healthy: true, QueueCapacity: 100}, weight: 100},
+		{name: "saturated", status: Status{Healthy: true, QueueLength: 80, QueueCapacity: 100}, saturated: true, weight: 20},
+		{name: "full queue", status: Status{Healthy: true, QueueLength: 150, QueueCapacity: 100}, saturated: true, weight: 1},
+		{name: "unhealthy", status: Status{QueueCapacity: 100}, weight: 0},
+		{name: "unknown queue", status: Status{Healthy: true}, weight: 100},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := r.report(tc.status)
+			assert.Equal(t, tc.saturated, report.Saturated)
+			assert.Equal(t, tc.weight, report.Weight)
+			assert.LessOrEqual(t, report.Pressure, 1.0)
+		})
+	}
+}
+
+func TestReporterLogsPublishErrors(t *testing.T) {
+	r, publisher := newTestReporter(t, time.Hour, func() Status { return Status{Healthy: true} })
+	core, logs := observer.New(zap.WarnLevel)
+	r.logger = zap.New(core)
+	publisher.err = errors.New("registry unavailable")
+	r.Start()
+	require.NoError(t, r.Close())
+	assert.Equal(t, 2, logs.FilterMessage("Failed to publish collector health to service registry").Len())
+}
+
+func TestNewReporterError(t *testing.T) {
+	_, err := NewReporter(Options{Type: "zookeeper"}, nil, zap.NewNop())
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// resourceAttributes adds the deployment attributes, e.g. the cluster or region the collector
+// runs in, to the Process of the spans whose SDK did not detect them, so that the stored traces
+// can be searched by infrastructure. The attributes the Process has already are left untouched.
+type resourceAttributes struct {
+	tags model.KeyValues
+}
+
+// newResourceAttributes returns nil when there are no attributes.
+func newResourceAttributes(attributes map[string]string) *resourceAttributes {
+	if len(attributes) == 0 {
+		return nil
+	}
+	r := &resourceAttributes{}
+	for k, v := range attributes {
+		r.tags = append(r.tags, model.String(k, v))
+	}
+	r.tags.Sort()
+	return r
+}
+
+func (r *resourceAttributes) addMissing(process *model.Process) {
+	if process == nil {
+		return
+	}
+	var missing []model.KeyValue
+	for _, tag := range r.tags {
+		if _, ok := model.KeyValues(process.Tags).FindByKey(tag.Key); !ok {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	process.Tags = append(process.Tags, missing...)
+	model.KeyValues(process.Tags).Sort()
+}
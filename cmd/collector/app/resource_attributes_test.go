@@ -0,0 +1,34 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestResourceAttributes(t *testing.T) {
+	assert.Nil(t, newResourceAttributes(nil))
+
+	r := newResourceAttributes(map[string]string{
+		"k8s.cluster.name": "prod-1",
+		"cloud.region":     "eu-west-1",
+	})
+	process := model.NewProcess("frontend", []model.KeyValue{
+		model.String("cloud.region", "us-east-1"),
+		model.String("host.name", "web-1"),
+	})
+	// the spans of a batch may share the Process
+	r.addMissing(process)
+	r.addMissing(process)
+	r.addMissing(nil)
+	assert.Equal(t, []model.KeyValue{
+		model.String("cloud.region", "us-east-1"),
+		model.String("host.name", "web-1"),
+		model.String("k8s.cluster.name", "prod-1"),
+	}, process.Tags, "the attributes detected by the SDK are kept")
+}
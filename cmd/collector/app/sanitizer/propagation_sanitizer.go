@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// baggageTagPrefix prefixes the tags holding the baggage entries of a span.
+	baggageTagPrefix = "baggage."
+	// traceStateTagPrefix prefixes the tags holding the W3C tracestate entries of a span.
+	traceStateTagPrefix = "tracestate."
+	// traceStateTag is the tag holding the W3C tracestate of spans received as OTLP.
+	traceStateTag = "w3c.tracestate"
+)
+
+// NewPropagationSanitizer returns a function that copies the allowed baggage and
+// W3C tracestate entries carried by a span into tags, so that spans can be searched by them.
+//
+// Baggage is found in the logs that Jaeger clients record when baggage is set, and in the tags
+// that OpenTelemetry and Zipkin (Brave) tracers record for baggage fields, which are kept as is.
+// The tracestate is found in the w3c.tracestate tag of spans received as OTLP.
+// Entries are stored as baggage.<key> and tracestate.<key> tags, unless the span has such a tag already.
+func NewPropagationSanitizer(baggageKeys, traceStateKeys []string) SanitizeSpan {
+	s := &propagationSanitizer{
+		baggageKeys:    toSet(baggageKeys),
+		traceStateKeys: toSet(traceStateKeys),
+	}
+	return s.sanitize
+}
+
+type propagationSanitizer struct {
+	baggageKeys    map[string]struct{}
+	traceStateKeys map[string]struct{}
+}
+
+func (s *propagationSanitizer) sanitize(span *model.Span) *model.Span {
+	if len(s.baggageKeys) > 0 {
+		for _, log := range span.Logs {
+			if key, value, ok := baggageFromLog(log); ok {
+				s.addTag(span, s.baggageKeys, baggageTagPrefix+key, key, value)
+			}
+		}
+	}
+	if len(s.traceStateKeys) > 0 {
+		if tag, ok := model.KeyValues(span.Tags).FindByKey(traceStateTag); ok {
+			for _, member := range strings.Split(tag.AsString(), ",") {
+				key, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+				if ok {
+					s.addTag(span, s.traceStateKeys, traceStateTagPrefix+key, key, value)
+				}
+			}
+		}
+	}
+	return span
+}
+
+func (*propagationSanitizer) addTag(span *model.Span, allowed map[string]struct{}, tagKey, key, value string) {
+	if _, ok := allowed[key]; !ok {
+		return
+	}
+	if _, ok := model.KeyValues(span.Tags).FindByKey(tagKey); ok {
+		return
+	}
+	span.Tags = append(span.Tags, model.String(tagKey, value))
+}
+
+// baggageFromLog returns the baggage entry recorded by a Jaeger client,
+// a log with the fields event=baggage, key and value.
+func baggageFromLog(log model.Log) (key string, value string, ok bool) {
+	var isBaggage, hasKey bool
+	for _, field := range log.Fields {
+		switch field.Key {
+		case "event":
+			isBaggage = field.AsString() == "baggage"
+		case "key":
+			key, hasKey = field.AsString(), true
+		case "value":
+			value = field.AsString()
+		}
+	}
+	return key, value, isBaggage && hasKey
+}
+
+func toSet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			set[key] = struct{}{}
+		}
+	}
+	return set
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func baggageLog(key, value string) model.Log {
+	return model.Log{Fields: []model.KeyValue{
+		model.String("event", "baggage"),
+		model.String("key", key),
+		model.String("value", value),
+	}}
+}
+
+func TestPropagationSanitizer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		span     *model.Span
+		expected []model.KeyValue
+	}{
+		{
+			name: "baggage logs",
+			span: &model.Span{Logs: []model.Log{
+				baggageLog("experiment", "blue"),
+				baggageLog("user.id", "42"),
+				{Fields: []model.KeyValue{model.String("event", "baggage"), model.String("value", "no key")}},
+				{Fields: []model.KeyValue{model.String("event", "retry"), model.String("key", "route")}},
+				baggageLog("route", "canary"),
+			}},
+			expected: []model.KeyValue{
+				model.String("baggage.experiment", "blue"),
+				model.String("baggage.route", "canary"),
+			},
+		},
+		{
+			name: "tracestate",
+			span: &model.Span{Tags: []model.KeyValue{
+				model.String("w3c.tracestate", "rojo=00f067aa0ba902b7, congo=t61rcWkgMzE,route=canary,invalid"),
+			}},
+			expected: []model.KeyValue{
+				model.String("w3c.tracestate", "rojo=00f067aa0ba902b7, congo=t61rcWkgMzE,route=canary,invalid"),
+				model.String("tracestate.rojo", "00f067aa0ba902b7"),
+				model.String("tracestate.route", "canary"),
+			},
+		},
+		{
+			name: "existing tags are kept",
+			span: &model.Span{
+				Tags: []model.KeyValue{model.String("baggage.experiment", "green")},
+				Logs: []model.Log{baggageLog("experiment", "blue")},
+			},
+			expected: []model.KeyValue{model.String("baggage.experiment", "green")},
+		},
+	}
+	s := NewPropagationSanitizer([]string{"experiment", " route", ""}, []string{"rojo", "route"})
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, s(test.span).Tags)
+		})
+	}
+}
+
+func TestPropagationSanitizerNoKeys(t *testing.T) {
+	span := &model.Span{
+		Tags: []model.KeyValue{model.String("w3c.tracestate", "rojo=00f067aa0ba902b7")},
+		Logs: []model.Log{baggageLog("experiment", "blue")},
+	}
+	s := NewPropagationSanitizer(nil, nil)
+	assert.Equal(t, []model.KeyValue{model.String("w3c.tracestate", "rojo=00f067aa0ba902b7")}, s(span).Tags)
+}
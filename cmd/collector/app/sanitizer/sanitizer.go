@@ -20,13 +20,14 @@ import (
 )
 
 // SanitizeSpan sanitizes/normalizes spans. Any business logic that needs to be applied to normalize the contents of a
-// span should implement this interface.
+// span should implement this interface. A sanitizer returns nil to reject the span, which is then dropped.
 type SanitizeSpan func(span *model.Span) *model.Span
 
 // NewStandardSanitizers are automatically applied by SpanProcessor.
 func NewStandardSanitizers() []SanitizeSpan {
 	return []SanitizeSpan{
 		NewEmptyServiceNameSanitizer(),
+		NewServiceAttributesSanitizer(),
 	}
 }
 
@@ -38,7 +39,9 @@ func NewChainedSanitizer(sanitizers ...SanitizeSpan) SanitizeSpan {
 	}
 	return func(span *model.Span) *model.Span {
 		for _, s := range sanitizers {
-			span = s(span)
+			if span = s(span); span == nil {
+				return nil
+			}
 		}
 		return span
 	}
@@ -42,3 +42,15 @@ func TestChainedSanitizer(t *testing.T) {
 	sp2 := c2(&model.Span{})
 	assert.Equal(t, "s2", sp2.Process.ServiceName)
 }
+
+func TestChainedSanitizerReject(t *testing.T) {
+	var reject SanitizeSpan = func(*model.Span) *model.Span {
+		return nil
+	}
+	var s2 SanitizeSpan = func(span *model.Span) *model.Span {
+		span.Process = &model.Process{ServiceName: "s2"}
+		return span
+	}
+	c := NewChainedSanitizer(reject, s2)
+	assert.Nil(t, c(&model.Span{}))
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// NewServiceAttributesSanitizer returns a sanitizer normalizing the service.namespace and
+// service.instance.id process tags, which are searched as dimensions of the services:
+// their values are stored as trimmed strings, and the empty ones are removed.
+func NewServiceAttributesSanitizer() SanitizeSpan {
+	return sanitizeServiceAttributes
+}
+
+func isServiceAttribute(key string) bool {
+	return key == model.ServiceNamespaceKey || key == model.ServiceInstanceIDKey
+}
+
+func isSanitizedServiceAttribute(kv model.KeyValue) bool {
+	return kv.VType == model.StringType && kv.VStr != "" && strings.TrimSpace(kv.VStr) == kv.VStr
+}
+
+func sanitizeServiceAttributes(span *model.Span) *model.Span {
+	if span.Process == nil {
+		return span
+	}
+	sanitized := true
+	for _, kv := range span.Process.Tags {
+		if isServiceAttribute(kv.Key) && !isSanitizedServiceAttribute(kv) {
+			sanitized = false
+			break
+		}
+	}
+	if sanitized {
+		return span
+	}
+	tags := make([]model.KeyValue, 0, len(span.Process.Tags))
+	for _, kv := range span.Process.Tags {
+		if isServiceAttribute(kv.Key) {
+			value := strings.TrimSpace(kv.AsString())
+			if value == "" {
+				continue
+			}
+			kv = model.String(kv.Key, value)
+		}
+		tags = append(tags, kv)
+	}
+	// the process may be shared by the spans of a batch, so it is copied rather than changed
+	process := *span.Process
+	process.Tags = tags
+	span.Process = &process
+	return span
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestServiceAttributesSanitizer(t *testing.T) {
+	s := NewServiceAttributesSanitizer()
+	assert.Nil(t, s(&model.Span{}).Process)
+
+	process := &model.Process{ServiceName: "checkout", Tags: []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, "shop"),
+		model.String("hostname", " host-1 "),
+	}}
+	span := s(&model.Span{Process: process})
+	assert.Same(t, process, span.Process, "sanitized processes are kept as is")
+
+	process = &model.Process{ServiceName: "checkout", Tags: []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, " shop "),
+		model.Int64(model.ServiceInstanceIDKey, 42),
+		model.String("hostname", " host-1 "),
+	}}
+	span = s(&model.Span{Process: process})
+	assert.Equal(t, []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, "shop"),
+		model.String(model.ServiceInstanceIDKey, "42"),
+		model.String("hostname", " host-1 "),
+	}, span.Process.Tags)
+	assert.Equal(t, " shop ", process.Tags[0].VStr, "the shared process is not changed")
+
+	span = s(&model.Span{Process: &model.Process{Tags: []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, "  "),
+	}}})
+	assert.Empty(t, span.Process.Tags)
+}
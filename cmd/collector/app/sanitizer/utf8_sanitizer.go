@@ -17,11 +17,13 @@ package sanitizer
 
 import (
 	"fmt"
+	"strings"
 	"unicode/utf8"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/model"
 )
 
@@ -29,39 +31,89 @@ const (
 	invalidOperation = "InvalidOperationName"
 	invalidService   = "InvalidServiceName"
 	invalidTagKey    = "InvalidTagKey"
+
+	utf8WarningCode   = "invalid_utf8_sanitized"
+	utf8WarningSource = "collector"
+)
+
+// UTF8Repair is how the UTF-8 sanitizer repairs the strings that are not valid UTF-8.
+type UTF8Repair string
+
+const (
+	// UTF8RepairBinary keeps the invalid strings as binary tags, renaming the invalid names and keys.
+	UTF8RepairBinary UTF8Repair = "binary"
+	// UTF8RepairReplace replaces each sequence of invalid bytes with the U+FFFD replacement character.
+	UTF8RepairReplace UTF8Repair = "replace"
+	// UTF8RepairHex replaces each invalid byte with its \xNN hexadecimal escape.
+	UTF8RepairHex UTF8Repair = "hex"
 )
 
 // utf8Sanitizer sanitizes all strings in spans
 type utf8Sanitizer struct {
 	logger *zap.Logger
+	repair UTF8Repair
 }
 
-// NewUTF8Sanitizer creates a UTF8 sanitizer.
+// NewUTF8Sanitizer creates a UTF8 sanitizer that keeps the invalid strings as binary tags.
 func NewUTF8Sanitizer(logger *zap.Logger) SanitizeSpan {
-	utf8Sanitizer := utf8Sanitizer{logger: logger}
+	return NewUTF8SanitizerWithRepair(UTF8RepairBinary, logger)
+}
+
+// NewUTF8SanitizerWithRepair creates a UTF8 sanitizer that repairs the invalid strings as specified.
+// The sanitized spans are given a warning listing the invalid strings.
+func NewUTF8SanitizerWithRepair(repair UTF8Repair, logger *zap.Logger) SanitizeSpan {
+	utf8Sanitizer := utf8Sanitizer{logger: logger, repair: repair}
 	return utf8Sanitizer.Sanitize
 }
 
 // Sanitize sanitizes the UTF8 in the spans.
 func (s *utf8Sanitizer) Sanitize(span *model.Span) *model.Span {
+	var sanitized []string
 	if !utf8.ValidString(span.OperationName) {
 		s.logSpan(span, "Invalid utf8 operation name", zap.String("operation_name", span.OperationName))
-		span.Tags = append(span.Tags, model.Binary(invalidOperation, []byte(span.OperationName)))
-		span.OperationName = invalidOperation
+		if s.repair == UTF8RepairBinary {
+			span.Tags = append(span.Tags, model.Binary(invalidOperation, []byte(span.OperationName)))
+			span.OperationName = invalidOperation
+		} else {
+			span.OperationName = s.repairString(span.OperationName)
+		}
+		sanitized = append(sanitized, "operation name")
 	}
 	if !utf8.ValidString(span.Process.ServiceName) {
 		s.logSpan(span, "Invalid utf8 service name", zap.String("service_name", span.Process.ServiceName))
-		span.Tags = append(span.Tags, model.Binary(invalidService, []byte(span.Process.ServiceName)))
-		span.Process.ServiceName = invalidService
+		if s.repair == UTF8RepairBinary {
+			span.Tags = append(span.Tags, model.Binary(invalidService, []byte(span.Process.ServiceName)))
+			span.Process.ServiceName = invalidService
+		} else {
+			span.Process.ServiceName = s.repairString(span.Process.ServiceName)
+		}
+		sanitized = append(sanitized, "service name")
 	}
-	sanitizeKV(span.Process.Tags)
-	sanitizeKV(span.Tags)
+	sanitized = append(sanitized, s.sanitizeKV(span.Process.Tags, "process tag")...)
+	sanitized = append(sanitized, s.sanitizeKV(span.Tags, "tag")...)
 	for _, log := range span.Logs {
-		sanitizeKV(log.Fields)
+		sanitized = append(sanitized, s.sanitizeKV(log.Fields, "log field")...)
+	}
+	if len(sanitized) > 0 {
+		jptrace.AddStructuredWarningTags(span, jptrace.Warning{
+			Code:     utf8WarningCode,
+			Severity: s.warningSeverity(),
+			Message:  "invalid UTF-8 was sanitized in " + strings.Join(sanitized, ", "),
+			Source:   utf8WarningSource,
+		})
 	}
 	return span
 }
 
+// warningSeverity returns the severity of the warning of the sanitized spans:
+// the replacement character loses the invalid bytes, the other repairs keep them.
+func (s *utf8Sanitizer) warningSeverity() jptrace.WarningSeverity {
+	if s.repair == UTF8RepairReplace {
+		return jptrace.WarningSeverityDataLoss
+	}
+	return jptrace.WarningSeverityInfo
+}
+
 func (s *utf8Sanitizer) logSpan(span *model.Span, message string, field zapcore.Field) {
 	s.logger.Info(
 		message,
@@ -69,12 +121,44 @@ func (s *utf8Sanitizer) logSpan(span *model.Span, message string, field zapcore.
 		zap.String("span_id", span.SpanID.String()), field)
 }
 
-func sanitizeKV(keyValues model.KeyValues) {
+// sanitizeKV sanitizes the key values in place and returns the descriptions of the sanitized ones.
+func (s *utf8Sanitizer) sanitizeKV(keyValues model.KeyValues, kind string) []string {
+	var sanitized []string
 	for i, kv := range keyValues {
-		if !utf8.ValidString(kv.Key) {
+		validKey := utf8.ValidString(kv.Key)
+		validValue := kv.VType != model.StringType || utf8.ValidString(kv.VStr)
+		if validKey && validValue {
+			continue
+		}
+		switch {
+		case s.repair != UTF8RepairBinary:
+			keyValues[i].Key = s.repairString(kv.Key)
+			if !validValue {
+				keyValues[i].VStr = s.repairString(kv.VStr)
+			}
+		case !validKey:
 			keyValues[i] = model.Binary(invalidTagKey, []byte(fmt.Sprintf("%s:%s", kv.Key, kv.AsStringLossy())))
-		} else if kv.VType == model.StringType && !utf8.ValidString(kv.VStr) {
+		default:
 			keyValues[i] = model.Binary(kv.Key, []byte(kv.VStr))
 		}
+		sanitized = append(sanitized, kind+" "+keyValues[i].Key)
+	}
+	return sanitized
+}
+
+func (s *utf8Sanitizer) repairString(str string) string {
+	if s.repair == UTF8RepairReplace {
+		return strings.ToValidUTF8(str, string(utf8.RuneError))
+	}
+	var b strings.Builder
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&b, "\\x%02x", str[i])
+		} else {
+			b.WriteString(str[i : i+size])
+		}
+		i += size
 	}
+	return b.String()
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/model"
 )
 
@@ -87,8 +88,9 @@ func TestUTF8Sanitizer_SanitizeKV(t *testing.T) {
 			},
 		},
 	}
+	s := &utf8Sanitizer{repair: UTF8RepairBinary}
 	for _, kv := range tests {
-		sanitizeKV(kv.input)
+		s.sanitizeKV(kv.input, "tag")
 		assert.Equal(t, kv.expected, kv.input)
 	}
 }
@@ -147,3 +149,58 @@ func TestUTF8Sanitizer_SanitizeLogs(t *testing.T) {
 	)
 	assert.Equal(t, invalidTagKey, actual.Logs[0].Fields[0].Key)
 }
+
+func TestUTF8Sanitizer_Repair(t *testing.T) {
+	invalid := "a" + invalidUTF8() + "b"
+	tests := []struct {
+		repair   UTF8Repair
+		expected string
+		severity jptrace.WarningSeverity
+	}{
+		{repair: UTF8RepairReplace, expected: "a\uFFFDb", severity: jptrace.WarningSeverityDataLoss},
+		{repair: UTF8RepairHex, expected: `a\xfe\xfe\xff\xffb`, severity: jptrace.WarningSeverityInfo},
+	}
+	for _, test := range tests {
+		t.Run(string(test.repair), func(t *testing.T) {
+			actual := NewUTF8SanitizerWithRepair(test.repair, zap.NewNop())(&model.Span{
+				OperationName: invalid,
+				Process: &model.Process{
+					ServiceName: invalid,
+					Tags:        model.KeyValues{model.String("valid", "é"), model.String(invalid, "value")},
+				},
+				Tags: model.KeyValues{model.String("key", invalid), model.Int64(invalid, 42)},
+				Logs: []model.Log{{Fields: model.KeyValues{model.String(invalid, invalid)}}},
+			})
+			assert.Equal(t, test.expected, actual.OperationName)
+			assert.Equal(t, test.expected, actual.Process.ServiceName)
+			assert.Equal(t, []model.KeyValue{model.String("valid", "é"), model.String(test.expected, "value")}, actual.Process.Tags)
+			assert.Equal(t, []model.KeyValue{
+				model.String("key", test.expected),
+				model.Int64(test.expected, 42),
+			}, actual.Tags[:2])
+			assert.Equal(t, []jptrace.Warning{{
+				Code:     "invalid_utf8_sanitized",
+				Severity: test.severity,
+				Message: "invalid UTF-8 was sanitized in operation name, service name, " +
+					"process tag " + test.expected + ", tag key, tag " + test.expected + ", log field " + test.expected,
+				Source: "collector",
+			}}, jptrace.GetStructuredWarningTags(actual))
+			assert.Equal(t, []model.KeyValue{model.String(test.expected, test.expected)}, actual.Logs[0].Fields)
+		})
+	}
+}
+
+func TestUTF8Sanitizer_Warning(t *testing.T) {
+	actual := undertest(&model.Span{
+		Tags:    model.KeyValues{model.String("key", invalidUTF8())},
+		Process: &model.Process{},
+	})
+	assert.Equal(t, []model.KeyValue{
+		model.Binary("key", []byte(invalidUTF8())),
+		model.String(jptrace.WarningsAttribute, `{"code":"invalid_utf8_sanitized","severity":"info",`+
+			`"message":"invalid UTF-8 was sanitized in tag key","source":"collector"}`),
+	}, actual.Tags)
+
+	valid := &model.Span{Tags: model.KeyValues{model.String("key", "value")}, Process: &model.Process{}}
+	assert.Len(t, undertest(valid).Tags, 1, "valid spans are not given a warning")
+}
@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/samplingstrategy"
@@ -34,6 +35,8 @@ import (
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
+const collectorServiceName = "jaeger.api_v2.CollectorService"
+
 // GRPCServerParams to construct a new Jaeger Collector gRPC Server
 type GRPCServerParams struct {
 	TLSConfig               tlscfg.Options
@@ -45,6 +48,8 @@ type GRPCServerParams struct {
 	MaxReceiveMessageLength int
 	MaxConnectionAge        time.Duration
 	MaxConnectionAgeGrace   time.Duration
+	// Authenticator, if set, authenticates the RPCs of the CollectorService
+	Authenticator *auth.Authenticator
 
 	// Set by the server to indicate the actual host:port of the server.
 	HostPortActual string
@@ -74,6 +79,14 @@ func StartGRPCServer(params *GRPCServerParams) (*grpc.Server, error) {
 		grpcOpts = append(grpcOpts, grpc.Creds(creds))
 	}
 
+	if params.Authenticator != nil {
+		// the sampling and health services stay open to the SDKs and probes
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(auth.NewGuardingUnaryInterceptor(params.Authenticator, collectorServiceName)),
+			grpc.ChainStreamInterceptor(auth.NewGuardingStreamInterceptor(params.Authenticator, collectorServiceName)),
+		)
+	}
+
 	server = grpc.NewServer(grpcOpts...)
 	reflection.Register(server)
 
@@ -96,7 +109,7 @@ func serveGRPC(server *grpc.Server, listener net.Listener, params *GRPCServerPar
 	api_v2.RegisterCollectorServiceServer(server, params.Handler)
 	api_v2.RegisterSamplingManagerServer(server, sampling.NewGRPCHandler(params.SamplingProvider))
 
-	healthServer.SetServingStatus("jaeger.api_v2.CollectorService", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(collectorServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
 	healthServer.SetServingStatus("jaeger.api_v2.SamplingManager", grpc_health_v1.HealthCheckResponse_SERVING)
 
 	grpc_health_v1.RegisterHealthServer(server, healthServer)
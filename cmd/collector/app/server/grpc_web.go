@@ -0,0 +1,179 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+const (
+	grpcWebPostSpansPath = "/jaeger.api_v2.CollectorService/PostSpans"
+
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// grpcWebFrameHeaderLen is the length of the flags byte and the big-endian message length.
+	grpcWebFrameHeaderLen = 5
+	grpcWebTrailerFlag    = 0x80
+	grpcWebCompressedFlag = 0x01
+
+	defaultGRPCWebMaxMessageSize = 4 * 1024 * 1024
+)
+
+// postSpansFunc matches handler.GRPCHandler.PostSpans.
+type postSpansFunc func(context.Context, *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error)
+
+// authenticatedPostSpans authenticates the gRPC-web calls like the gRPC server authenticates the native ones.
+func authenticatedPostSpans(authenticator *auth.Authenticator, postSpans postSpansFunc) postSpansFunc {
+	return func(ctx context.Context, req *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, err := authenticator.Authenticate(ctx, md)
+		if err != nil {
+			return nil, err
+		}
+		return postSpans(ctx, req)
+	}
+}
+
+// grpcWebHandler translates unary gRPC-web calls of CollectorService.PostSpans
+// into calls of the gRPC handler, so that browsers and proxies that cannot speak
+// native gRPC can submit spans without a separate translating proxy.
+// Both the binary (application/grpc-web) and the base64 (application/grpc-web-text)
+// encodings are supported; message compression is not.
+type grpcWebHandler struct {
+	postSpans      postSpansFunc
+	maxMessageSize int
+	logger         *zap.Logger
+}
+
+func newGRPCWebHandler(postSpans postSpansFunc, maxMessageSize int, logger *zap.Logger) *grpcWebHandler {
+	if maxMessageSize <= 0 {
+		maxMessageSize = defaultGRPCWebMaxMessageSize
+	}
+	return &grpcWebHandler{
+		postSpans:      postSpans,
+		maxMessageSize: maxMessageSize,
+		logger:         logger,
+	}
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, grpcWebContentType) {
+		http.Error(w, fmt.Sprintf("unsupported content type, expected %s", grpcWebContentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	text := strings.HasPrefix(mediaType, grpcWebTextContentType)
+
+	var body io.Reader = r.Body
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+	var resp *api_v2.PostSpansResponse
+	req, err := h.readRequest(body)
+	if err == nil {
+		ctx := metadata.NewIncomingContext(r.Context(), headersToMetadata(r.Header))
+		resp, err = h.postSpans(ctx, req)
+	}
+
+	var out bytes.Buffer
+	if err == nil {
+		var payload []byte
+		if payload, err = resp.Marshal(); err == nil {
+			writeGRPCWebFrame(&out, 0, payload)
+		}
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.OK {
+		h.logger.Debug("gRPC-web PostSpans failed", zap.Stringer("code", st.Code()), zap.String("message", st.Message()))
+	}
+	var trailer bytes.Buffer
+	fmt.Fprintf(&trailer, "grpc-status: %d\r\n", st.Code())
+	if st.Message() != "" {
+		fmt.Fprintf(&trailer, "grpc-message: %s\r\n", encodeGRPCMessage(st.Message()))
+	}
+	writeGRPCWebFrame(&out, grpcWebTrailerFlag, trailer.Bytes())
+
+	respBody := out.Bytes()
+	contentType := grpcWebContentType + "+proto"
+	if text {
+		contentType = grpcWebTextContentType + "+proto"
+		respBody = []byte(base64.StdEncoding.EncodeToString(respBody))
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+// readRequest reads a single length-prefixed message and decodes it.
+func (h *grpcWebHandler) readRequest(body io.Reader) (*api_v2.PostSpansRequest, error) {
+	var header [grpcWebFrameHeaderLen]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot read gRPC-web message header: %v", err)
+	}
+	if header[0]&grpcWebCompressedFlag != 0 {
+		return nil, status.Error(codes.Unimplemented, "compressed gRPC-web messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if uint64(length) > uint64(h.maxMessageSize) {
+		return nil, status.Errorf(codes.ResourceExhausted, "gRPC-web message larger than max (%d vs. %d)", length, h.maxMessageSize)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(body, payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot read gRPC-web message: %v", err)
+	}
+	req := &api_v2.PostSpansRequest{}
+	if err := req.Unmarshal(payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot unmarshal PostSpansRequest: %v", err)
+	}
+	return req, nil
+}
+
+func writeGRPCWebFrame(w *bytes.Buffer, flags byte, payload []byte) {
+	var header [grpcWebFrameHeaderLen]byte
+	header[0] = flags
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	w.Write(header[:])
+	w.Write(payload)
+}
+
+// headersToMetadata converts HTTP request headers into gRPC metadata,
+// which carries e.g. the tenant header to the gRPC handler.
+func headersToMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for k, v := range header {
+		md.Append(strings.ToLower(k), v...)
+	}
+	return md
+}
+
+// encodeGRPCMessage percent-encodes the status message as required by the gRPC protocol.
+func encodeGRPCMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+func grpcWebFrame(t *testing.T, flags byte, req *api_v2.PostSpansRequest) []byte {
+	payload, err := req.Marshal()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	writeGRPCWebFrame(&buf, flags, payload)
+	return buf.Bytes()
+}
+
+// readGRPCWebResponse splits a response body into the message and the trailer frames.
+func readGRPCWebResponse(t *testing.T, body []byte) (messages [][]byte, trailer string) {
+	for len(body) > 0 {
+		require.GreaterOrEqual(t, len(body), grpcWebFrameHeaderLen)
+		length := int(binary.BigEndian.Uint32(body[1:grpcWebFrameHeaderLen]))
+		frame := body[grpcWebFrameHeaderLen : grpcWebFrameHeaderLen+length]
+		if body[0]&grpcWebTrailerFlag != 0 {
+			trailer = string(frame)
+		} else {
+			messages = append(messages, frame)
+		}
+		body = body[grpcWebFrameHeaderLen+length:]
+	}
+	return messages, trailer
+}
+
+func TestGRPCWebHandler(t *testing.T) {
+	var received *api_v2.PostSpansRequest
+	var tenants []string
+	h := newGRPCWebHandler(func(ctx context.Context, r *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error) {
+		received = r
+		md, _ := metadata.FromIncomingContext(ctx)
+		tenants = md.Get("x-tenant")
+		return &api_v2.PostSpansResponse{}, nil
+	}, 0, zap.NewNop())
+
+	req := &api_v2.PostSpansRequest{
+		Batch: model.Batch{
+			Process: model.NewProcess("frontend", nil),
+			Spans:   []*model.Span{{OperationName: "fake-operation"}},
+		},
+	}
+	testCases := []struct {
+		name        string
+		contentType string
+		encode      func([]byte) []byte
+		decode      func([]byte) []byte
+	}{
+		{
+			name:        "binary",
+			contentType: "application/grpc-web+proto",
+			encode:      func(b []byte) []byte { return b },
+			decode:      func(b []byte) []byte { return b },
+		},
+		{
+			name:        "text",
+			contentType: "application/grpc-web-text",
+			encode:      func(b []byte) []byte { return []byte(base64.StdEncoding.EncodeToString(b)) },
+			decode: func(b []byte) []byte {
+				out, err := base64.StdEncoding.DecodeString(string(b))
+				require.NoError(t, err)
+				return out
+			},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			received, tenants = nil, nil
+			r := httptest.NewRequest(http.MethodPost, grpcWebPostSpansPath, bytes.NewReader(test.encode(grpcWebFrame(t, 0, req))))
+			r.Header.Set("Content-Type", test.contentType)
+			r.Header.Set("X-Tenant", "acme")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), test.contentType[:len("application/grpc-web")])
+			require.NotNil(t, received)
+			assert.Equal(t, "frontend", received.Batch.Process.ServiceName)
+			assert.Equal(t, "fake-operation", received.Batch.Spans[0].OperationName)
+			assert.Equal(t, []string{"acme"}, tenants)
+
+			messages, trailer := readGRPCWebResponse(t, test.decode(w.Body.Bytes()))
+			assert.Len(t, messages, 1)
+			assert.Equal(t, "grpc-status: 0\r\n", trailer)
+		})
+	}
+}
+
+func TestGRPCWebHandlerErrors(t *testing.T) {
+	req := &api_v2.PostSpansRequest{Batch: model.Batch{Process: model.NewProcess("frontend", nil)}}
+	testCases := []struct {
+		name      string
+		body      []byte
+		postErr   error
+		maxSize   int
+		expStatus string
+	}{
+		{
+			name:      "truncated header",
+			body:      []byte{0, 0},
+			expStatus: "grpc-status: 3\r\n",
+		},
+		{
+			name:      "compressed",
+			body:      grpcWebFrame(t, grpcWebCompressedFlag, req),
+			expStatus: "grpc-status: 12\r\n",
+		},
+		{
+			name:      "too large",
+			body:      grpcWebFrame(t, 0, req),
+			maxSize:   1,
+			expStatus: "grpc-status: 8\r\n",
+		},
+		{
+			name:      "truncated message",
+			body:      grpcWebFrame(t, 0, req)[:grpcWebFrameHeaderLen+1],
+			expStatus: "grpc-status: 3\r\n",
+		},
+		{
+			name:      "handler error",
+			body:      grpcWebFrame(t, 0, req),
+			postErr:   status.Error(codes.PermissionDenied, "unknown tenant"),
+			expStatus: "grpc-status: 7\r\ngrpc-message: unknown tenant\r\n",
+		},
+		{
+			name:      "non-status error",
+			body:      grpcWebFrame(t, 0, req),
+			postErr:   errors.New("100% broken"),
+			expStatus: "grpc-status: 2\r\ngrpc-message: 100%25 broken\r\n",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			h := newGRPCWebHandler(func(context.Context, *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error) {
+				return &api_v2.PostSpansResponse{}, test.postErr
+			}, test.maxSize, zap.NewNop())
+			r := httptest.NewRequest(http.MethodPost, grpcWebPostSpansPath, bytes.NewReader(test.body))
+			r.Header.Set("Content-Type", "application/grpc-web")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			messages, trailer := readGRPCWebResponse(t, w.Body.Bytes())
+			assert.Empty(t, messages)
+			assert.True(t, strings.HasPrefix(trailer, test.expStatus), trailer)
+		})
+	}
+}
+
+func TestGRPCWebHandlerUnsupportedContentType(t *testing.T) {
+	h := newGRPCWebHandler(nil, 0, zap.NewNop())
+	r := httptest.NewRequest(http.MethodPost, grpcWebPostSpansPath, nil)
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestSpanCollectorHTTPGRPCWebAndH2C(t *testing.T) {
+	logger := zap.NewNop()
+	params := &HTTPServerParams{
+		Handler:          handler.NewJaegerSpanHandler(logger, &mockSpanProcessor{}),
+		GRPCHandler:      handler.NewGRPCHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		GRPCWeb:          true,
+		H2C:              true,
+		CORS:             corscfg.Options{AllowedOrigins: []string{"https://app.example.com"}},
+		SamplingProvider: &mockSamplingProvider{},
+		MetricsFactory:   metrics.NullFactory,
+		HealthCheck:      healthcheck.New(),
+		Logger:           logger,
+	}
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := &http.Server{}
+	defer server.Close()
+	serveHTTP(server, listener, params)
+
+	req := &api_v2.PostSpansRequest{Batch: model.Batch{Process: model.NewProcess("frontend", nil)}}
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+	// forces HTTP/2 over cleartext with prior knowledge
+	client := &http.Client{Transport: transport}
+	httpReq, err := http.NewRequest(http.MethodPost, "http://"+listener.Addr().String()+grpcWebPostSpansPath, bytes.NewReader(grpcWebFrame(t, 0, req)))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("Origin", "https://app.example.com")
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_, trailer := readGRPCWebResponse(t, body)
+	assert.Equal(t, "grpc-status: 0\r\n", trailer)
+}
@@ -20,12 +20,17 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/rs/cors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/auth"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/samplingstrategy"
 	clientcfgHandler "github.com/jaegertracing/jaeger/pkg/clientcfg/clientcfghttp"
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/httpmetrics"
@@ -43,6 +48,19 @@ type HTTPServerParams struct {
 	HealthCheck      *healthcheck.HealthCheck
 	Logger           *zap.Logger
 
+	// GRPCHandler serves gRPC-web requests to the CollectorService when GRPCWeb is enabled
+	GRPCHandler *handler.GRPCHandler
+	// GRPCWeb enables the gRPC-web translation of CollectorService.PostSpans
+	GRPCWeb bool
+	// Authenticator, if set, authenticates the gRPC-web requests
+	Authenticator *auth.Authenticator
+	// MaxReceiveMessageLength limits the size of gRPC-web messages, 4MiB if not set
+	MaxReceiveMessageLength int
+	// H2C enables HTTP/2 over cleartext connections when TLS is disabled
+	H2C bool
+	// CORS allows cross-origin requests, e.g. gRPC-web requests from browsers
+	CORS corscfg.Options
+
 	// ReadTimeout sets the respective parameter of http.Server
 	ReadTimeout time.Duration
 	// ReadHeaderTimeout sets the respective parameter of http.Server
@@ -97,8 +115,29 @@ func serveHTTP(server *http.Server, listener net.Listener, params *HTTPServerPar
 	})
 	cfgHandler.RegisterRoutes(r)
 
+	if params.GRPCWeb && params.GRPCHandler != nil {
+		postSpans := params.GRPCHandler.PostSpans
+		if params.Authenticator != nil {
+			postSpans = authenticatedPostSpans(params.Authenticator, postSpans)
+		}
+		grpcWeb := newGRPCWebHandler(postSpans, params.MaxReceiveMessageLength, params.Logger)
+		r.Handle(grpcWebPostSpansPath, grpcWeb).Methods(http.MethodPost)
+	}
+
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(params.Logger, true)
-	server.Handler = httpmetrics.Wrap(recoveryHandler(r), params.MetricsFactory, params.Logger)
+	var h http.Handler = httpmetrics.Wrap(recoveryHandler(r), params.MetricsFactory, params.Logger)
+	if len(params.CORS.AllowedOrigins) > 0 {
+		h = cors.New(cors.Options{
+			AllowedOrigins: params.CORS.AllowedOrigins,
+			AllowedHeaders: append([]string{"Content-Type", "X-Grpc-Web", "X-User-Agent"}, params.CORS.AllowedHeaders...),
+			AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		}).Handler(h)
+	}
+	if params.H2C && !params.TLSConfig.Enabled {
+		// with TLS, HTTP/2 is negotiated via ALPN by the standard library
+		h = h2c.NewHandler(h, &http2.Server{IdleTimeout: params.IdleTimeout})
+	}
+	server.Handler = h
 	go func() {
 		var err error
 		if params.TLSConfig.Enabled {
@@ -23,6 +23,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	zs "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -37,6 +38,11 @@ type SpanHandlerBuilder struct {
 	Logger         *zap.Logger
 	MetricsFactory metrics.Factory
 	TenancyMgr     *tenancy.Manager
+	StorageSink    string
+	// Processors run in order before each span is saved
+	Processors []processor.ProcessSpanFunc
+	// PreProcessSpans, if set, is called with every batch of received spans, before they are queued
+	PreProcessSpans ProcessSpans
 }
 
 // SpanHandlers holds instances to the span handlers built by the SpanHandlerBuilder
@@ -49,23 +55,53 @@ type SpanHandlers struct {
 // BuildSpanProcessor builds the span processor to be used with the handlers
 func (b *SpanHandlerBuilder) BuildSpanProcessor(additional ...ProcessSpan) processor.SpanProcessor {
 	hostname, _ := os.Hostname()
+	provenanceInstance := b.CollectorOpts.Provenance.Instance
+	if provenanceInstance == "" {
+		provenanceInstance = hostname
+	}
 	svcMetrics := b.metricsFactory()
 	hostMetrics := svcMetrics.Namespace(metrics.NSOptions{Tags: map[string]string{"host": hostname}})
+	preSave := make([]ProcessSpan, 0, len(b.Processors))
+	for _, p := range b.Processors {
+		preSave = append(preSave, ProcessSpan(p))
+	}
 
-	return NewSpanProcessor(
-		b.SpanWriter,
-		additional,
+	opts := []Option{
 		Options.ServiceMetrics(svcMetrics),
 		Options.HostMetrics(hostMetrics),
 		Options.Logger(b.logger()),
 		Options.SpanFilter(defaultSpanFilter),
+		Options.PreSave(ChainedProcessSpan(preSave...)),
 		Options.NumWorkers(b.CollectorOpts.NumWorkers),
+		Options.WorkersAutoTuning(b.CollectorOpts.WorkersAutoTuning),
 		Options.QueueSize(b.CollectorOpts.QueueSize),
 		Options.CollectorTags(b.CollectorOpts.CollectorTags),
+		Options.Provenance(b.CollectorOpts.Provenance.Attributes, provenanceInstance),
+		Options.ResourceAttributes(b.CollectorOpts.ResourceAttributes),
+		Options.SpanKindPolicies(b.CollectorOpts.SpanKindPolicies),
+		Options.QueueFullPolicies(b.CollectorOpts.QueueFullPolicies, b.CollectorOpts.QueueFullBlockTimeout),
 		Options.DynQueueSizeWarmup(uint(b.CollectorOpts.QueueSize)), // same as queue size for now
 		Options.DynQueueSizeMemory(b.CollectorOpts.DynQueueSizeMemory),
 		Options.SpanSizeMetricsEnabled(b.CollectorOpts.SpanSizeMetricsEnabled),
-	)
+		Options.IngestLatencySampling(b.CollectorOpts.IngestLatencySampling),
+		Options.DroppedSpansLogSampling(b.CollectorOpts.DroppedSpansLogSampling),
+		Options.StorageSink(b.StorageSink),
+	}
+	var sanitizers []sanitizer.SanitizeSpan
+	if b.CollectorOpts.UTF8Repair != "" {
+		sanitizers = append(sanitizers, sanitizer.NewUTF8SanitizerWithRepair(sanitizer.UTF8Repair(b.CollectorOpts.UTF8Repair), b.logger()))
+	}
+	if len(b.CollectorOpts.BaggageKeys) > 0 || len(b.CollectorOpts.TraceStateKeys) > 0 {
+		sanitizers = append(sanitizers,
+			sanitizer.NewPropagationSanitizer(b.CollectorOpts.BaggageKeys, b.CollectorOpts.TraceStateKeys))
+	}
+	if len(sanitizers) > 0 {
+		opts = append(opts, Options.Sanitizer(sanitizer.NewChainedSanitizer(sanitizers...)))
+	}
+	if b.PreProcessSpans != nil {
+		opts = append(opts, Options.PreProcessSpans(b.PreProcessSpans))
+	}
+	return NewSpanProcessor(b.SpanWriter, additional, opts...)
 }
 
 // BuildHandlers builds span handlers (Zipkin, Jaeger)
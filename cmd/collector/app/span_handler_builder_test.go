@@ -16,18 +16,23 @@
 package app
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	cmdFlags "github.com/jaegertracing/jaeger/cmd/internal/flags"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 func TestNewSpanHandlerBuilder(t *testing.T) {
@@ -64,6 +69,50 @@ func TestNewSpanHandlerBuilder(t *testing.T) {
 	require.NoError(t, spanProcessor.Close())
 }
 
+func TestSpanHandlerBuilderProcessors(t *testing.T) {
+	v, command := config.Viperize(cmdFlags.AddFlags, flags.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	cOpts, err := new(flags.CollectorOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	spanWriter := memory.NewStore()
+	builder := &SpanHandlerBuilder{
+		SpanWriter:    spanWriter,
+		CollectorOpts: cOpts,
+		TenancyMgr:    &tenancy.Manager{},
+		Processors: []processor.ProcessSpanFunc{
+			func(span *model.Span, _ string) {
+				span.Tags = append(span.Tags, model.String("enriched", "first"))
+			},
+			func(span *model.Span, _ string) {
+				span.Tags = append(span.Tags, model.String("enriched", "second"))
+			},
+		},
+	}
+	spanProcessor := builder.BuildSpanProcessor()
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "op",
+		Process:       model.NewProcess("svc", nil),
+	}
+	defer spanProcessor.Close()
+	_, err = spanProcessor.ProcessSpans([]*model.Span{span}, processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat})
+	require.NoError(t, err)
+
+	var trace *model.Trace
+	require.Eventually(t, func() bool {
+		trace, err = spanWriter.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: span.TraceID})
+		return err == nil
+	}, time.Second, time.Millisecond)
+	require.Len(t, trace.Spans, 1)
+	assert.Equal(t, []model.KeyValue{
+		model.String("internal.span.format", string(processor.ProtoSpanFormat)),
+		model.String("enriched", "first"),
+		model.String("enriched", "second"),
+	}, trace.Spans[0].Tags)
+}
+
 func TestDefaultSpanFilter(t *testing.T) {
 	assert.True(t, defaultSpanFilter(nil))
 }
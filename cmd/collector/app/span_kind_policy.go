@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// libraryTags identify the instrumentation library of a span, the OTLP receivers translate
+// the instrumentation scope to otel.scope.name and older SDKs report otel.library.name.
+var libraryTags = []string{"otel.scope.name", "otel.library.name"}
+
+// spanKindPolicy rejects the spans of some kinds of a service or an instrumentation library.
+type spanKindPolicy struct {
+	service string
+	library string
+	keep    bool
+	kinds   map[string]struct{}
+	hits    metrics.Counter
+}
+
+// spanKindPolicies reject spans by kind before they are queued, see flags.SpanKindPolicy.
+type spanKindPolicies []*spanKindPolicy
+
+// newSpanKindPolicies returns nil when there are no policies.
+func newSpanKindPolicies(policies []flags.SpanKindPolicy, metricsFactory metrics.Factory) spanKindPolicies {
+	var p spanKindPolicies
+	for _, policy := range policies {
+		kinds := make(map[string]struct{}, len(policy.Kinds))
+		for _, kind := range policy.Kinds {
+			kinds[kind] = struct{}{}
+		}
+		p = append(p, &spanKindPolicy{
+			service: policy.Service,
+			library: policy.Library,
+			keep:    policy.Keep,
+			kinds:   kinds,
+			hits: metricsFactory.Counter(metrics.Options{
+				Name: "span_kind_policy.hits",
+				Tags: map[string]string{"name": policy.Name},
+				Help: "Number of spans rejected by a span kind policy",
+			}),
+		})
+	}
+	return p
+}
+
+// reject returns true if a policy rejects the span, counting the hit of the first such policy.
+func (p spanKindPolicies) reject(span *model.Span) bool {
+	if len(p) == 0 {
+		return false
+	}
+	kind, _ := span.GetSpanKind()
+	for _, policy := range p {
+		if !policy.appliesTo(span) {
+			continue
+		}
+		if _, ok := policy.kinds[kind.String()]; ok != policy.keep {
+			policy.hits.Inc(1)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *spanKindPolicy) appliesTo(span *model.Span) bool {
+	if p.service != "" && span.GetProcess().GetServiceName() != p.service {
+		return false
+	}
+	if p.library == "" {
+		return true
+	}
+	for _, key := range libraryTags {
+		if tag, ok := model.KeyValues(span.Tags).FindByKey(key); ok {
+			return tag.AsString() == p.library
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+func kindSpan(service string, kind string, tags ...model.KeyValue) *model.Span {
+	if kind != "" {
+		tags = append(tags, model.String("span.kind", kind))
+	}
+	return &model.Span{Process: &model.Process{ServiceName: service}, Tags: tags}
+}
+
+func TestSpanKindPolicies(t *testing.T) {
+	assert.Nil(t, newSpanKindPolicies(nil, metrics.NullFactory))
+	assert.False(t, newSpanKindPolicies(nil, metrics.NullFactory).reject(kindSpan("checkout", "internal")))
+
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	p := newSpanKindPolicies([]flags.SpanKindPolicy{
+		{Name: "grpc-internal", Library: "io.grpc", Kinds: []string{"internal"}},
+		{Name: "checkout", Service: "checkout", Keep: true, Kinds: []string{"server", "client"}},
+	}, mb)
+
+	grpcScope := model.String("otel.scope.name", "io.grpc")
+	grpcLibrary := model.String("otel.library.name", "io.grpc")
+	testCases := []struct {
+		caption  string
+		span     *model.Span
+		rejected bool
+	}{
+		{caption: "internal span of the library", span: kindSpan("frontend", "internal", grpcScope), rejected: true},
+		{caption: "internal span of the legacy library tag", span: kindSpan("frontend", "internal", grpcLibrary), rejected: true},
+		{caption: "client span of the library", span: kindSpan("frontend", "client", grpcScope)},
+		{caption: "internal span of another library", span: kindSpan("frontend", "internal", model.String("otel.scope.name", "net/http"))},
+		{caption: "internal span without library", span: kindSpan("frontend", "internal")},
+		{caption: "server span of the service", span: kindSpan("checkout", "server")},
+		{caption: "client span of the service", span: kindSpan("checkout", "client", grpcScope)},
+		{caption: "producer span of the service", span: kindSpan("checkout", "producer"), rejected: true},
+		{caption: "span of the service without kind", span: kindSpan("checkout", ""), rejected: true},
+		{caption: "internal span of the service and library", span: kindSpan("checkout", "internal", grpcScope), rejected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			assert.Equal(t, tc.rejected, p.reject(tc.span))
+		})
+	}
+	mb.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "span_kind_policy.hits", Tags: map[string]string{"name": "grpc-internal"}, Value: 3},
+		metricstest.ExpectedMetric{Name: "span_kind_policy.hits", Tags: map[string]string{"name": "checkout"}, Value: 2},
+	)
+}
+
+func TestSpanProcessorSpanKindPolicies(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+		Options.SpanKindPolicies([]flags.SpanKindPolicy{{Name: "no-internal", Kinds: []string{"internal"}}}),
+		Options.QueueSize(2),
+	)
+	res, err := p.ProcessSpans([]*model.Span{
+		kindSpan("x", "internal"),
+		kindSpan("x", "server"),
+	}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+	require.NoError(t, p.Close())
+
+	require.Len(t, w.spans, 1)
+	kind, _ := w.spans[0].GetSpanKind()
+	assert.Equal(t, "server", kind.String())
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "host.span_kind_policy.hits", Tags: map[string]string{"name": "no-internal"}, Value: 1,
+	})
+}
@@ -17,12 +17,14 @@ package app
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
@@ -43,15 +45,23 @@ type spanProcessor struct {
 	queue              *queue.BoundedQueue
 	queueResizeMu      sync.Mutex
 	metrics            *SpanProcessorMetrics
+	ingestLatency      *IngestLatencyMetrics
+	ingestSampling     float64
+	dropLogSampling    float64
+	onDroppedSpan      func(span *model.Span)
 	preProcessSpans    ProcessSpans
 	filterSpan         FilterSpan             // filter is called before the sanitizer but after preProcessSpans
+	spanKindPolicies   spanKindPolicies       // policies are applied after filterSpan
 	sanitizer          sanitizer.SanitizeSpan // sanitizer is called before processSpan
 	processSpan        ProcessSpan
 	logger             *zap.Logger
 	spanWriter         spanstore.Writer
-	reportBusy         bool
+	queueFullPolicies  *queueFullPolicies
 	numWorkers         int
+	workerTuner        *workerTuner
 	collectorTags      map[string]string
+	provenance         *provenance
+	resourceAttributes *resourceAttributes
 	dynQueueSizeWarmup uint
 	dynQueueSizeMemory uint
 	bytesProcessed     atomic.Uint64
@@ -63,6 +73,8 @@ type queueItem struct {
 	queuedTime time.Time
 	span       *model.Span
 	tenant     string
+	// receivedTime is only set for spans sampled for ingest latency metrics
+	receivedTime time.Time
 }
 
 // NewSpanProcessor returns a SpanProcessor that preProcesses, filters, queues, sanitizes, and processes spans.
@@ -80,6 +92,10 @@ func NewSpanProcessor(
 
 	sp.background(1*time.Second, sp.updateGauges)
 
+	if sp.workerTuner != nil {
+		sp.background(sp.workerTuner.interval, sp.workerTuner.adjust)
+	}
+
 	if sp.dynQueueSizeMemory > 0 {
 		sp.background(1*time.Minute, sp.updateQueueSize)
 	}
@@ -93,34 +109,48 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 		options.serviceMetrics,
 		options.hostMetrics,
 		options.extraFormatTypes)
-	droppedItemHandler := func(item any) {
-		handlerMetrics.SpansDropped.Inc(1)
-		if options.onDroppedSpan != nil {
-			options.onDroppedSpan(item.(*queueItem).span)
-		}
-	}
-	boundedQueue := queue.NewBoundedQueue(options.queueSize, droppedItemHandler)
-
 	sanitizers := sanitizer.NewStandardSanitizers()
 	if options.sanitizer != nil {
 		sanitizers = append(sanitizers, options.sanitizer)
 	}
 
 	sp := spanProcessor{
-		queue:              boundedQueue,
 		metrics:            handlerMetrics,
+		ingestLatency:      NewIngestLatencyMetrics(options.hostMetrics, options.storageSink),
+		ingestSampling:     options.ingestLatencySampling,
+		dropLogSampling:    options.droppedSpansLogSampling,
+		onDroppedSpan:      options.onDroppedSpan,
 		logger:             options.logger,
 		preProcessSpans:    options.preProcessSpans,
 		filterSpan:         options.spanFilter,
+		spanKindPolicies:   newSpanKindPolicies(options.spanKindPolicies, options.hostMetrics),
 		sanitizer:          sanitizer.NewChainedSanitizer(sanitizers...),
-		reportBusy:         options.reportBusy,
+		queueFullPolicies:  newQueueFullPolicies(options.queueFullPolicies, options.queueFullBlockTimeout, options.reportBusy, options.hostMetrics),
 		numWorkers:         options.numWorkers,
 		spanWriter:         spanWriter,
 		collectorTags:      options.collectorTags,
+		provenance:         newProvenance(options.provenanceAttributes, options.provenanceInstance),
+		resourceAttributes: newResourceAttributes(options.resourceAttributes),
 		stopCh:             make(chan struct{}),
 		dynQueueSizeMemory: options.dynQueueSizeMemory,
 		dynQueueSizeWarmup: options.dynQueueSizeWarmup,
 	}
+	sp.queue = queue.NewBoundedQueue(options.queueSize, func(item any) {
+		span := item.(*queueItem).span
+		sp.reportDroppedSpan(span, processor.QueueFullDropReason)
+		if sp.onDroppedSpan != nil {
+			sp.onDroppedSpan(span)
+		}
+	})
+
+	if options.workersAutoTuning.Enabled {
+		sp.workerTuner = newWorkerTuner(sp.queue, options.workersAutoTuning, options.hostMetrics, options.logger)
+		sp.numWorkers = sp.workerTuner.clamp(sp.numWorkers)
+		options.logger.Info("Auto-tuning the number of workers at runtime.",
+			zap.Int("initial-workers", sp.numWorkers),
+			zap.Int("min-workers", options.workersAutoTuning.MinWorkers),
+			zap.Int("max-workers", options.workersAutoTuning.MaxWorkers))
+	}
 
 	processSpanFuncs := []ProcessSpan{options.preSave, sp.saveSpan}
 	if options.dynQueueSizeMemory > 0 {
@@ -150,6 +180,7 @@ func (sp *spanProcessor) saveSpan(span *model.Span, tenant string) {
 	if nil == span.Process {
 		sp.logger.Error("process is empty for the span")
 		sp.metrics.SavedErrBySvc.ReportServiceNameForSpan(span)
+		sp.reportDroppedSpan(span, processor.SanitizerRejectDropReason)
 		return
 	}
 
@@ -161,12 +192,17 @@ func (sp *spanProcessor) saveSpan(span *model.Span, tenant string) {
 	if err := sp.spanWriter.WriteSpan(ctx, span); err != nil {
 		sp.logger.Error("Failed to save span", zap.Error(err))
 		sp.metrics.SavedErrBySvc.ReportServiceNameForSpan(span)
+		sp.reportDroppedSpan(span, processor.StorageErrorDropReason)
 	} else {
 		sp.logger.Debug("Span written to the storage by the collector",
 			zap.Stringer("trace-id", span.TraceID), zap.Stringer("span-id", span.SpanID))
 		sp.metrics.SavedOkBySvc.ReportServiceNameForSpan(span)
 	}
-	sp.metrics.SaveLatency.Record(time.Since(startTime))
+	latency := time.Since(startTime)
+	sp.metrics.SaveLatency.Record(latency)
+	if sp.workerTuner != nil {
+		sp.workerTuner.observe(latency)
+	}
 }
 
 func (sp *spanProcessor) countSpan(span *model.Span, _ string /* tenant */) {
@@ -175,6 +211,7 @@ func (sp *spanProcessor) countSpan(span *model.Span, _ string /* tenant */) {
 }
 
 func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.SpansOptions) ([]bool, error) {
+	receivedTime := time.Now()
 	sp.preProcessSpans(mSpans, options.Tenant)
 	sp.metrics.BatchSize.Update(int64(len(mSpans)))
 	retMe := make([]bool, len(mSpans))
@@ -186,11 +223,15 @@ func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.Sp
 	// kicks in.
 	for _, span := range mSpans {
 		sp.addCollectorTags(span)
+		if sp.resourceAttributes != nil {
+			sp.resourceAttributes.addMissing(span.Process)
+		}
 	}
 
+	queueFullPolicy := sp.queueFullPolicies.get(options.SpanFormat)
 	for i, mSpan := range mSpans {
-		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport, options.Tenant)
-		if !ok && sp.reportBusy {
+		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport, options.Tenant, receivedTime, queueFullPolicy)
+		if !ok && queueFullPolicy.policy == flags.QueueFullFail {
 			return nil, processor.ErrBusy
 		}
 		retMe[i] = ok
@@ -199,8 +240,18 @@ func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.Sp
 }
 
 func (sp *spanProcessor) processItemFromQueue(item *queueItem) {
-	sp.processSpan(sp.sanitizer(item.span), item.tenant)
+	dequeuedTime := time.Now()
+	span := sp.sanitizer(item.span)
+	if span == nil {
+		sp.reportDroppedSpan(item.span, processor.SanitizerRejectDropReason)
+	} else {
+		sp.processSpan(span, item.tenant)
+	}
 	sp.metrics.InQueueLatency.Record(time.Since(item.queuedTime))
+	if span != nil && !item.receivedTime.IsZero() {
+		// processSpan writes the span synchronously, so by now the storage has acknowledged it
+		sp.ingestLatency.Record(item.receivedTime, item.queuedTime, dequeuedTime, time.Now())
+	}
 }
 
 func (sp *spanProcessor) addCollectorTags(span *model.Span) {
@@ -226,24 +277,60 @@ func (sp *spanProcessor) addCollectorTags(span *model.Span) {
 
 // Note: spans may share the Process object, so no changes should be made to Process
 // in this function as it may cause race conditions.
-func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat processor.SpanFormat, transport processor.InboundTransport, tenant string) bool {
+func (sp *spanProcessor) enqueueSpan(
+	span *model.Span,
+	originalFormat processor.SpanFormat,
+	transport processor.InboundTransport,
+	tenant string,
+	receivedTime time.Time,
+	queueFullPolicy *queueFullPolicy,
+) bool {
 	spanCounts := sp.metrics.GetCountsForFormat(originalFormat, transport)
 	spanCounts.ReceivedBySvc.ReportServiceNameForSpan(span)
 
-	if !sp.filterSpan(span) {
+	if !sp.filterSpan(span) || sp.spanKindPolicies.reject(span) {
 		spanCounts.RejectedBySvc.ReportServiceNameForSpan(span)
 		return true // as in "not dropped", because it's actively rejected
 	}
 
 	// add format tag
 	span.Tags = append(span.Tags, model.String("internal.span.format", string(originalFormat)))
+	if sp.provenance != nil {
+		sp.provenance.addTags(span, originalFormat, transport, receivedTime)
+	}
 
 	item := &queueItem{
 		queuedTime: time.Now(),
 		span:       span,
 		tenant:     tenant,
 	}
-	return sp.queue.Produce(item)
+	if sp.sampleIngestLatency() {
+		item.receivedTime = receivedTime
+	}
+	return sp.queueFullPolicies.produce(sp.queue, queueFullPolicy, item)
+}
+
+// ReportDroppedSpans implements processor.DroppedSpansReporter.
+func (sp *spanProcessor) ReportDroppedSpans(mSpans []*model.Span, reason processor.DropReason) {
+	for _, span := range mSpans {
+		sp.reportDroppedSpan(span, reason)
+	}
+}
+
+// reportDroppedSpan counts a dropped span by reason, and logs a sample of the dropped spans at debug level.
+func (sp *spanProcessor) reportDroppedSpan(span *model.Span, reason processor.DropReason) {
+	sp.metrics.SpansDropped[reason].Inc(1)
+	if sp.dropLogSampling > 0 && rand.Float64() < sp.dropLogSampling {
+		sp.logger.Debug("Span dropped",
+			zap.String("reason", string(reason)),
+			zap.String("service", span.GetProcess().GetServiceName()),
+			zap.Stringer("trace-id", span.TraceID),
+			zap.Stringer("span-id", span.SpanID))
+	}
+}
+
+func (sp *spanProcessor) sampleIngestLatency() bool {
+	return sp.ingestSampling > 0 && rand.Float64() < sp.ingestSampling
 }
 
 func (sp *spanProcessor) background(reportPeriod time.Duration, callback func()) {
@@ -309,3 +396,8 @@ func (sp *spanProcessor) updateGauges() {
 	sp.metrics.QueueLength.Update(int64(sp.queue.Size()))
 	sp.metrics.QueueCapacity.Update(int64(sp.queue.Capacity()))
 }
+
+// queueStatus returns the number of spans in the queue and its capacity.
+func (sp *spanProcessor) queueStatus() (length, capacity int) {
+	return sp.queue.Size(), sp.queue.Capacity()
+}
@@ -151,7 +151,7 @@ func TestBySvcMetrics(t *testing.T) {
 			// we defined the queue capacity as 0, all submitted items are dropped.
 			// The debug spans are always accepted.
 			expected = append(expected, metricstest.ExpectedMetric{
-				Name: "host.spans.dropped", Value: 2,
+				Name: "host.spans.dropped|reason=queue_full", Value: 2,
 			})
 		} else {
 			expected = append(expected, metricstest.ExpectedMetric{
@@ -270,6 +270,7 @@ func TestSpanProcessorErrors(t *testing.T) {
 		nil,
 		Options.Logger(logger),
 		Options.ServiceMetrics(serviceMetrics),
+		Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
 		Options.QueueSize(1),
 	).(*spanProcessor)
 
@@ -293,6 +294,8 @@ func TestSpanProcessorErrors(t *testing.T) {
 
 	expected := []metricstest.ExpectedMetric{{
 		Name: "service.spans.saved-by-svc|debug=false|result=err|svc=x", Value: 1,
+	}, {
+		Name: "host.spans.dropped|reason=storage_error", Value: 1,
 	}}
 	mb.AssertCounterMetrics(t, expected...)
 }
@@ -353,13 +356,18 @@ func TestSpanProcessorWithNilProcess(t *testing.T) {
 	serviceMetrics := mb.Namespace(metrics.NSOptions{Name: "service", Tags: nil})
 
 	w := &fakeSpanWriter{}
-	p := NewSpanProcessor(w, nil, Options.ServiceMetrics(serviceMetrics)).(*spanProcessor)
+	p := NewSpanProcessor(w, nil,
+		Options.ServiceMetrics(serviceMetrics),
+		Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+	).(*spanProcessor)
 	defer require.NoError(t, p.Close())
 
 	p.saveSpan(&model.Span{}, "")
 
 	expected := []metricstest.ExpectedMetric{{
 		Name: "service.spans.saved-by-svc|debug=false|result=err|svc=__unknown", Value: 1,
+	}, {
+		Name: "host.spans.dropped|reason=sanitizer_reject", Value: 1,
 	}}
 	mb.AssertCounterMetrics(t, expected...)
 }
@@ -717,3 +725,122 @@ func TestSpanProcessorWithOnDroppedSpanOption(t *testing.T) {
 	require.EqualError(t, err, processor.ErrBusy.Error())
 	assert.Equal(t, []string{"op3"}, droppedOperations)
 }
+
+func TestSpanProcessorIngestLatency(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampling float64
+		recorded bool
+	}{
+		{name: "sampled", sampling: 1, recorded: true},
+		{name: "disabled", sampling: 0, recorded: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mb := metricstest.NewFactory(time.Hour)
+			defer mb.Backend.Stop()
+			p := NewSpanProcessor(&fakeSpanWriter{},
+				nil,
+				Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+				Options.QueueSize(1),
+				Options.IngestLatencySampling(test.sampling),
+				Options.StorageSink("memory"),
+			)
+			res, err := p.ProcessSpans([]*model.Span{
+				{
+					Process: &model.Process{
+						ServiceName: "x",
+					},
+				},
+			}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+			require.NoError(t, err)
+			assert.Equal(t, []bool{true}, res)
+			require.NoError(t, p.Close())
+
+			_, gauges := mb.Snapshot()
+			for _, stage := range []string{"total", "receive", "queue", "storage"} {
+				_, ok := gauges["host.ingest-latency|sink=memory|stage="+stage+".P50"]
+				assert.Equal(t, test.recorded, ok, stage)
+			}
+		})
+	}
+}
+
+func TestSpanProcessorSanitizerReject(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+		Options.Sanitizer(func(span *model.Span) *model.Span {
+			if span.OperationName == "invalid" {
+				return nil
+			}
+			return span
+		}),
+		Options.QueueSize(2),
+	)
+	res, err := p.ProcessSpans([]*model.Span{
+		{OperationName: "invalid", Process: &model.Process{ServiceName: "x"}},
+		{OperationName: "valid", Process: &model.Process{ServiceName: "x"}},
+	}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+	require.NoError(t, p.Close())
+
+	require.Len(t, w.spans, 1)
+	assert.Equal(t, "valid", w.spans[0].OperationName)
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "host.spans.dropped|reason=sanitizer_reject", Value: 1,
+	})
+}
+
+func TestSpanProcessorReportDroppedSpans(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampling float64
+		logged   bool
+	}{
+		{name: "logged", sampling: 1, logged: true},
+		{name: "not logged", sampling: 0, logged: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mb := metricstest.NewFactory(time.Hour)
+			defer mb.Backend.Stop()
+			logger, logBuf := testutils.NewLogger()
+			p := NewSpanProcessor(&fakeSpanWriter{},
+				nil,
+				Options.Logger(logger),
+				Options.HostMetrics(mb.Namespace(metrics.NSOptions{Name: "host"})),
+				Options.DroppedSpansLogSampling(test.sampling),
+			)
+			defer p.Close()
+
+			reporter, ok := p.(processor.DroppedSpansReporter)
+			require.True(t, ok)
+			reporter.ReportDroppedSpans([]*model.Span{
+				{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(2), Process: &model.Process{ServiceName: "x"}},
+				{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(3)},
+			}, processor.TenantInvalidDropReason)
+
+			mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+				Name: "host.spans.dropped|reason=tenant_invalid", Value: 2,
+			})
+			if test.logged {
+				assert.Equal(t, map[string]string{
+					"level":    "debug",
+					"msg":      "Span dropped",
+					"reason":   "tenant_invalid",
+					"service":  "x",
+					"trace-id": "0000000000000001",
+					"span-id":  "0000000000000002",
+				}, logBuf.JSONLine(0))
+				assert.Equal(t, "", logBuf.JSONLine(1)["service"])
+			} else {
+				assert.Empty(t, logBuf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	// the workers are added when the queue is at least this full
+	workersBacklogOccupancy = 0.1
+	// the workers are removed when the writer latency is this many times its baseline
+	workersLatencyDegradation = 2.0
+	// the fraction of the workers added or removed at once, at least one worker: the
+	// workers are backed off faster when the writer latency degrades
+	workersStep        = 0.1
+	workersBackoffStep = 0.25
+	// how fast the latency baseline follows a higher writer latency, so that a backend
+	// that becomes permanently slower is eventually not seen as degraded
+	baselineDrift = 0.1
+)
+
+// workerPool is the queue whose consumers are tuned.
+type workerPool interface {
+	Size() int
+	Capacity() int
+	Workers() int
+	SetWorkers(num int)
+}
+
+type workerTunerMetrics struct {
+	Workers            metrics.Gauge   `metric:"workers" help:"The number of workers consuming from the queue"`
+	LatencyBaseline    metrics.Gauge   `metric:"workers-tuning.latency-baseline-us" help:"The span writer latency in microseconds the auto-tuning compares the observed latency with"`
+	IncreasedOnBacklog metrics.Counter `metric:"workers-tuning.adjustments" tags:"decision=increase,reason=queue_backlog" help:"The number of adjustments of the workers, by decision and reason"`
+	DecreasedOnLatency metrics.Counter `metric:"workers-tuning.adjustments" tags:"decision=decrease,reason=writer_latency"`
+	DecreasedOnIdle    metrics.Counter `metric:"workers-tuning.adjustments" tags:"decision=decrease,reason=idle_queue"`
+}
+
+// workerTuner periodically adjusts the number of workers to the observed span writer latency
+// and queue occupancy. Workers are added while spans wait in the queue and the writer keeps up
+// its latency, and removed when the writer latency degrades, as more concurrency would then
+// only overload the storage backend, or when the queue stays empty.
+type workerTuner struct {
+	pool       workerPool
+	minWorkers int
+	maxWorkers int
+	interval   time.Duration
+	logger     *zap.Logger
+	metrics    workerTunerMetrics
+
+	latencySum   atomic.Int64
+	latencyCount atomic.Int64
+
+	// baseline is the lowest writer latency observed, drifting towards the recent ones;
+	// it is only accessed by adjust
+	baseline time.Duration
+}
+
+func newWorkerTuner(pool workerPool, cfg flags.WorkersAutoTuning, hostMetrics metrics.Factory, logger *zap.Logger) *workerTuner {
+	t := &workerTuner{
+		pool:       pool,
+		minWorkers: cfg.MinWorkers,
+		maxWorkers: cfg.MaxWorkers,
+		interval:   cfg.Interval,
+		logger:     logger,
+	}
+	metrics.MustInit(&t.metrics, hostMetrics, nil)
+	return t
+}
+
+// clamp returns the number of workers within the bounds of the tuner.
+func (t *workerTuner) clamp(workers int) int {
+	return min(max(workers, t.minWorkers), t.maxWorkers)
+}
+
+// observe records the latency of a span write.
+func (t *workerTuner) observe(latency time.Duration) {
+	t.latencySum.Add(int64(latency))
+	t.latencyCount.Add(1)
+}
+
+// adjust changes the number of workers based on the writes observed since the previous call.
+func (t *workerTuner) adjust() {
+	var latency time.Duration
+	if count := t.latencyCount.Swap(0); count > 0 {
+		latency = time.Duration(t.latencySum.Swap(0) / count)
+	}
+	workers := t.pool.Workers()
+	size, capacity := t.pool.Size(), t.pool.Capacity()
+
+	target, adjustments := workers, metrics.NullCounter
+	switch {
+	case latency > 0 && t.baseline > 0 && float64(latency) > workersLatencyDegradation*float64(t.baseline):
+		target, adjustments = workers-step(workers, workersBackoffStep), t.metrics.DecreasedOnLatency
+	case capacity > 0 && float64(size) >= workersBacklogOccupancy*float64(capacity):
+		target, adjustments = workers+step(workers, workersStep), t.metrics.IncreasedOnBacklog
+	case size == 0:
+		target, adjustments = workers-step(workers, workersStep), t.metrics.DecreasedOnIdle
+	}
+	if latency > 0 {
+		if t.baseline == 0 || latency < t.baseline {
+			t.baseline = latency
+		} else {
+			t.baseline += time.Duration(baselineDrift * float64(latency-t.baseline))
+		}
+	}
+	t.metrics.LatencyBaseline.Update(t.baseline.Microseconds())
+
+	if target = t.clamp(target); target != workers {
+		adjustments.Inc(1)
+		t.logger.Debug("Adjusting the number of workers",
+			zap.Int("previous-workers", workers),
+			zap.Int("workers", target),
+			zap.Duration("writer-latency", latency),
+			zap.Duration("writer-latency-baseline", t.baseline),
+			zap.Int("queue-length", size))
+		t.pool.SetWorkers(target)
+	}
+	t.metrics.Workers.Update(int64(t.pool.Workers()))
+}
+
+// step returns the number of workers to add or remove, a fraction of the current ones but at least one.
+func step(workers int, fraction float64) int {
+	return max(int(float64(workers)*fraction), 1)
+}
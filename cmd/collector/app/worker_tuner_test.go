@@ -0,0 +1,134 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeWorkerPool struct {
+	size, capacity, workers int
+}
+
+func (p *fakeWorkerPool) Size() int          { return p.size }
+func (p *fakeWorkerPool) Capacity() int      { return p.capacity }
+func (p *fakeWorkerPool) Workers() int       { return p.workers }
+func (p *fakeWorkerPool) SetWorkers(num int) { p.workers = num }
+
+func adjustmentsMetric(decision, reason string, value int) metricstest.ExpectedMetric {
+	return metricstest.ExpectedMetric{
+		Name:  "workers-tuning.adjustments",
+		Tags:  map[string]string{"decision": decision, "reason": reason},
+		Value: value,
+	}
+}
+
+func TestWorkerTuner(t *testing.T) {
+	pool := &fakeWorkerPool{capacity: 100, workers: 20}
+	mFactory := metricstest.NewFactory(time.Hour)
+	defer mFactory.Stop()
+	tuner := newWorkerTuner(pool, flags.WorkersAutoTuning{MinWorkers: 5, MaxWorkers: 25}, mFactory, zap.NewNop())
+
+	// the queue backs up while the writer latency is stable
+	pool.size = 50
+	tuner.observe(10 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 22, pool.workers)
+	tuner.observe(12 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 24, pool.workers)
+	tuner.adjust()
+	assert.Equal(t, 25, pool.workers, "the workers do not exceed the maximum")
+	tuner.adjust()
+	assert.Equal(t, 25, pool.workers)
+
+	// the writer latency degrades, adding workers would overload the storage
+	tuner.observe(30 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 19, pool.workers)
+
+	// the queue is drained
+	pool.size = 0
+	tuner.observe(10 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 18, pool.workers)
+
+	mFactory.AssertCounterMetrics(t,
+		adjustmentsMetric("increase", "queue_backlog", 3),
+		adjustmentsMetric("decrease", "writer_latency", 1),
+		adjustmentsMetric("decrease", "idle_queue", 1),
+	)
+	mFactory.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "workers", Value: 18},
+		metricstest.ExpectedMetric{Name: "workers-tuning.latency-baseline-us", Value: 10000},
+	)
+}
+
+func TestWorkerTunerBounds(t *testing.T) {
+	pool := &fakeWorkerPool{capacity: 100, workers: 5}
+	tuner := newWorkerTuner(pool, flags.WorkersAutoTuning{MinWorkers: 5, MaxWorkers: 10}, metricstest.NewFactory(0), zap.NewNop())
+	assert.Equal(t, 5, tuner.clamp(1))
+	assert.Equal(t, 10, tuner.clamp(50))
+
+	tuner.adjust()
+	assert.Equal(t, 5, pool.workers, "the workers are not reduced below the minimum")
+
+	tuner.observe(time.Millisecond)
+	tuner.adjust()
+	tuner.observe(10 * time.Millisecond)
+	pool.size = 50
+	tuner.adjust()
+	assert.Equal(t, 5, pool.workers, "the workers are not added while the writer latency is degraded")
+}
+
+func TestWorkerTunerBaselineDrift(t *testing.T) {
+	tuner := newWorkerTuner(&fakeWorkerPool{capacity: 100, workers: 10}, flags.WorkersAutoTuning{MinWorkers: 1, MaxWorkers: 10}, metricstest.NewFactory(0), zap.NewNop())
+	tuner.observe(10 * time.Millisecond)
+	tuner.observe(30 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 20*time.Millisecond, tuner.baseline, "the baseline starts from the average latency")
+
+	tuner.observe(120 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 30*time.Millisecond, tuner.baseline, "the baseline follows higher latencies slowly")
+
+	tuner.adjust()
+	assert.Equal(t, 30*time.Millisecond, tuner.baseline, "the baseline is kept without writes")
+
+	tuner.observe(5 * time.Millisecond)
+	tuner.adjust()
+	assert.Equal(t, 5*time.Millisecond, tuner.baseline)
+}
+
+func TestSpanProcessorWorkersAutoTuning(t *testing.T) {
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w, nil,
+		Options.NumWorkers(50),
+		Options.WorkersAutoTuning(flags.WorkersAutoTuning{
+			Enabled:    true,
+			MinWorkers: 1,
+			MaxWorkers: 10,
+			Interval:   time.Millisecond,
+		}),
+	).(*spanProcessor)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+	assert.Equal(t, 10, p.numWorkers, "the initial workers are within the bounds")
+
+	_, err := p.ProcessSpans([]*model.Span{{Process: &model.Process{ServiceName: "x"}}}, processor.SpansOptions{})
+	require.NoError(t, err)
+	// the queue stays empty, so the workers are reduced down to the minimum
+	assert.Eventually(t, func() bool { return p.queue.Workers() == 1 }, 5*time.Second, time.Millisecond)
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/doctor"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	cmdFlags "github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
@@ -38,8 +40,10 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategyprovider"
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategyprovider/adaptive"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
 
 const serviceName = "jaeger-collector"
@@ -47,7 +51,8 @@ const serviceName = "jaeger-collector"
 func main() {
 	svc := cmdFlags.NewService(ports.CollectorAdminHTTP)
 
-	storageFactory, err := storage.NewFactory(storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr))
+	storageFactoryCfg := storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr)
+	storageFactory, err := storage.NewFactory(storageFactoryCfg)
 	if err != nil {
 		log.Fatalf("Cannot initialize storage factory: %v", err)
 	}
@@ -96,6 +101,9 @@ func main() {
 			if err != nil {
 				logger.Fatal("Failed to create sampling strategy provider", zap.Error(err))
 			}
+			if introspector, ok := samplingAggregator.(adaptive.Introspector); ok {
+				svc.Admin.Handle("/sampling/adaptive", adaptive.NewIntrospectionHandler(introspector))
+			}
 			collectorOpts, err := new(flags.CollectorOptions).InitFromViper(v, logger)
 			if err != nil {
 				logger.Fatal("Failed to initialize collector", zap.Error(err))
@@ -106,11 +114,12 @@ func main() {
 				ServiceName:        serviceName,
 				Logger:             logger,
 				MetricsFactory:     metricsFactory,
-				SpanWriter:         spanWriter,
+				SpanWriter:         storageMetrics.NewWriteMetricsDecorator(spanWriter, metricsFactory),
 				SamplingProvider:   samplingProvider,
 				SamplingAggregator: samplingAggregator,
 				HealthCheck:        svc.HC(),
 				TenancyMgr:         tm,
+				StorageSink:        strings.Join(storageFactoryCfg.SpanWriterTypes, ","),
 			})
 			// Start all Collector services
 			if err := collector.Start(collectorOpts); err != nil {
@@ -143,6 +152,7 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.CollectorAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(doctor.Command(v))
 
 	config.AddFlags(
 		v,
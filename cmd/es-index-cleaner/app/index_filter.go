@@ -17,6 +17,7 @@ package app
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/es/client"
@@ -35,12 +36,37 @@ type IndexFilter struct {
 	Rollover bool
 	// Indices created before this date will be deleted.
 	DeleteBeforeThisDate time.Time
+	// Span indices of a retention family, e.g. jaeger-span-retention-30d-2024-05-01, are deleted
+	// when created longer than their retention before this date instead. Zero leaves them untouched.
+	RetentionReferenceDate time.Time
 }
 
 // Filter filters indices.
 func (i *IndexFilter) Filter(indices []client.Index) []client.Index {
-	indices = i.filter(indices)
-	return filter.ByDate(indices, i.DeleteBeforeThisDate)
+	filtered := filter.ByDate(i.filter(indices), i.DeleteBeforeThisDate)
+	return append(filtered, i.filterRetention(indices)...)
+}
+
+func (i *IndexFilter) filterRetention(indices []client.Index) []client.Index {
+	if i.Archive || i.Rollover || i.RetentionReferenceDate.IsZero() {
+		return nil
+	}
+	reg, _ := regexp.Compile(fmt.Sprintf("^%sjaeger-span-retention-(\\d+)d-\\d{4}%s\\d{2}%s\\d{2}", i.IndexPrefix, i.IndexDateSeparator, i.IndexDateSeparator))
+	var filtered []client.Index
+	for _, in := range indices {
+		match := reg.FindStringSubmatch(in.Index)
+		if match == nil {
+			continue
+		}
+		days, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if in.CreationTime.Before(i.RetentionReferenceDate.AddDate(0, 0, -days)) {
+			filtered = append(filtered, in)
+		}
+	}
+	return filtered
 }
 
 func (i *IndexFilter) filter(indices []client.Index) []client.Index {
@@ -372,3 +372,38 @@ func testIndexFilter(t *testing.T, prefix string) {
 		})
 	}
 }
+
+func TestIndexFilterRetention(t *testing.T) {
+	time20200807 := time.Date(2020, time.August, 0o6, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	indices := []client.Index{
+		{
+			Index:        "jaeger-span-2020-07-01",
+			CreationTime: time.Date(2020, time.July, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			Index:        "jaeger-span-retention-30d-2020-07-01",
+			CreationTime: time.Date(2020, time.July, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			Index:        "jaeger-span-retention-90d-2020-07-01",
+			CreationTime: time.Date(2020, time.July, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			Index:        "jaeger-span-retention-30d-2020-07-20",
+			CreationTime: time.Date(2020, time.July, 20, 15, 0, 0, 0, time.UTC),
+		},
+	}
+	filter := &IndexFilter{
+		IndexDateSeparator:     "-",
+		DeleteBeforeThisDate:   time20200807.AddDate(0, 0, -3),
+		RetentionReferenceDate: time20200807,
+	}
+	assert.Equal(t, []client.Index{indices[0], indices[1]}, filter.Filter(indices))
+
+	filter.RetentionReferenceDate = time.Time{}
+	assert.Equal(t, []client.Index{indices[0]}, filter.Filter(indices))
+
+	filter.Rollover = true
+	filter.RetentionReferenceDate = time20200807
+	assert.Empty(t, filter.Filter(indices))
+}
@@ -94,6 +94,8 @@ func main() {
 				Archive:              cfg.Archive,
 				Rollover:             cfg.Rollover,
 				DeleteBeforeThisDate: deleteIndicesBefore,
+				// retention indices are kept as many days as their retention, counted like NUM_OF_DAYS
+				RetentionReferenceDate: tomorrowMidnight,
 			}
 			logger.Info("Queried indices", zap.Any("indices", indices))
 			indices = filter.Filter(indices)
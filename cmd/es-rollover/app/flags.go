@@ -27,6 +27,8 @@ const (
 	password         = "es.password"
 	useILM           = "es.use-ilm"
 	ilmPolicyName    = "es.ilm-policy-name"
+	useISM           = "es.use-ism"
+	ismPolicyName    = "es.ism-policy-name"
 	timeout          = "timeout"
 	skipDependencies = "skip-dependencies"
 	adaptiveSampling = "adaptive-sampling"
@@ -41,6 +43,8 @@ type Config struct {
 	TLSEnabled       bool
 	ILMPolicyName    string
 	UseILM           bool
+	ISMPolicyName    string
+	UseISM           bool
 	Timeout          int
 	SkipDependencies bool
 	AdaptiveSampling bool
@@ -53,7 +57,9 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.String(username, "", "The username required by storage")
 	flags.String(password, "", "The password required by storage")
 	flags.Bool(useILM, false, "Use ILM to manage jaeger indices")
-	flags.String(ilmPolicyName, "jaeger-ilm-policy", "The name of the ILM policy to use if ILM is active")
+	flags.String(ilmPolicyName, "jaeger-ilm-policy", "The name of the ILM policy to use if ILM is active; use a distinct policy for archive indices to give them their own rollover conditions")
+	flags.Bool(useISM, false, "Use OpenSearch Index State Management (ISM) to manage jaeger indices")
+	flags.String(ismPolicyName, "jaeger-ism-policy", "The name of the ISM policy to create or update if ISM is active; use a distinct name for archive indices")
 	flags.Int(timeout, 120, "Number of seconds to wait for master node response")
 	flags.Bool(skipDependencies, false, "Disable rollover for dependencies index")
 	flags.Bool(adaptiveSampling, false, "Enable rollover for adaptive sampling index")
@@ -70,6 +76,8 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.Password = v.GetString(password)
 	c.ILMPolicyName = v.GetString(ilmPolicyName)
 	c.UseILM = v.GetBool(useILM)
+	c.ISMPolicyName = v.GetString(ismPolicyName)
+	c.UseISM = v.GetBool(useISM)
 	c.Timeout = v.GetInt(timeout)
 	c.SkipDependencies = v.GetBool(skipDependencies)
 	c.AdaptiveSampling = v.GetBool(adaptiveSampling)
@@ -41,6 +41,8 @@ func TestBindFlags(t *testing.T) {
 		"--es.password=qwerty123",
 		"--es.use-ilm=true",
 		"--es.ilm-policy-name=jaeger-ilm",
+		"--es.use-ism=true",
+		"--es.ism-policy-name=jaeger-ism",
 		"--skip-dependencies=true",
 		"--adaptive-sampling=true",
 	})
@@ -53,6 +55,8 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, "admin", c.Username)
 	assert.Equal(t, "qwerty123", c.Password)
 	assert.Equal(t, "jaeger-ilm", c.ILMPolicyName)
+	assert.True(t, c.UseISM)
+	assert.Equal(t, "jaeger-ism", c.ISMPolicyName)
 	assert.True(t, c.SkipDependencies)
 	assert.True(t, c.AdaptiveSampling)
 }
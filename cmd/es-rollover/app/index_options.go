@@ -95,7 +95,7 @@ func (i *IndexOption) InitialRolloverIndex() string {
 	return fmt.Sprintf(rolloverIndexFormat, i.IndexName())
 }
 
-// TemplateName returns the prefixed template name
+// TemplateName returns the prefixed template name, the archive indices have a template of their own
 func (i *IndexOption) TemplateName() string {
-	return strings.TrimLeft(fmt.Sprintf("%s%s", i.prefix, i.Mapping), "-")
+	return strings.TrimLeft(fmt.Sprintf("%s%s", i.prefix, i.indexType), "-")
 }
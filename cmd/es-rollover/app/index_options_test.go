@@ -69,7 +69,7 @@ func TestRolloverIndices(t *testing.T) {
 			prefix:  "mytenant",
 			expected: []expectedValues{
 				{
-					templateName:         "mytenant-jaeger-span",
+					templateName:         "mytenant-jaeger-span-archive",
 					mapping:              "jaeger-span",
 					readAliasName:        "mytenant-jaeger-span-archive-read",
 					writeAliasName:       "mytenant-jaeger-span-archive-write",
@@ -83,7 +83,7 @@ func TestRolloverIndices(t *testing.T) {
 			expected: []expectedValues{
 				{
 					mapping:              "jaeger-span",
-					templateName:         "jaeger-span",
+					templateName:         "jaeger-span-archive",
 					readAliasName:        "jaeger-span-archive-read",
 					writeAliasName:       "jaeger-span-archive-write",
 					initialRolloverIndex: "jaeger-span-archive-000001",
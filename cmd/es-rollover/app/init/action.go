@@ -30,12 +30,22 @@ import (
 
 const ilmVersionSupport = 7
 
+// ISM policy states and the priorities of their templates. The archive policy has a higher
+// priority because the pattern of the span indices also matches the archive indices.
+const (
+	ismHotState                = "hot"
+	ismDeleteState             = "delete"
+	ismTemplatePriority        = 100
+	ismArchiveTemplatePriority = 200
+)
+
 // Action holds the configuration and clients for init action
 type Action struct {
 	Config        Config
 	ClusterClient client.ClusterAPI
 	IndicesClient client.IndexAPI
 	ILMClient     client.IndexManagementLifecycleAPI
+	ISMClient     client.IndexStateManagementAPI
 }
 
 func (c Action) getMapping(version uint, templateName string) (string, error) {
@@ -50,13 +60,18 @@ func (c Action) getMapping(version uint, templateName string) (string, error) {
 		IndexPrefix:                  c.Config.IndexPrefix,
 		UseILM:                       c.Config.UseILM,
 		ILMPolicyName:                c.Config.ILMPolicyName,
+		UseISM:                       c.Config.UseISM,
 		EsVersion:                    version,
+		Archive:                      c.Config.Archive,
 	}
 	return mappingBuilder.GetMapping(templateName)
 }
 
 // Do the init action
 func (c Action) Do() error {
+	if c.Config.UseILM && c.Config.UseISM {
+		return errors.New("ILM and ISM cannot be used together")
+	}
 	version, err := c.ClusterClient.Version()
 	if err != nil {
 		return err
@@ -65,6 +80,13 @@ func (c Action) Do() error {
 		if version < ilmVersionSupport {
 			return fmt.Errorf("ILM is supported only for ES version 7+")
 		}
+		isOpenSearch, err := c.ClusterClient.IsOpenSearch()
+		if err != nil {
+			return err
+		}
+		if isOpenSearch {
+			return errors.New("ILM is not supported by OpenSearch, use ISM instead")
+		}
 		policyExist, err := c.ILMClient.Exists(c.Config.ILMPolicyName)
 		if err != nil {
 			return err
@@ -74,6 +96,12 @@ func (c Action) Do() error {
 		}
 	}
 	rolloverIndices := app.RolloverIndices(c.Config.Archive, c.Config.SkipDependencies, c.Config.AdaptiveSampling, c.Config.IndexPrefix)
+	if c.Config.UseISM {
+		// the policy must exist before the initial indices are created for its template to apply to them
+		if err := c.ensureISMPolicy(rolloverIndices); err != nil {
+			return err
+		}
+	}
 	for _, indexName := range rolloverIndices {
 		if err := c.init(version, indexName); err != nil {
 			return err
@@ -82,6 +110,67 @@ func (c Action) Do() error {
 	return nil
 }
 
+// ensureISMPolicy creates the ISM policy managing the rollover indices, or updates it
+// if it differs from the configured one, so that init can be re-run safely.
+func (c Action) ensureISMPolicy(rolloverIndices []app.IndexOption) error {
+	isOpenSearch, err := c.ClusterClient.IsOpenSearch()
+	if err != nil {
+		return err
+	}
+	if !isOpenSearch {
+		return errors.New("ISM is supported only by OpenSearch, use ILM with Elasticsearch")
+	}
+	policy := c.ismPolicy(rolloverIndices)
+	stored, err := c.ISMClient.GetPolicy(c.Config.ISMPolicyName)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return c.ISMClient.CreatePolicy(c.Config.ISMPolicyName, policy)
+	}
+	if stored.Policy.Equal(policy) {
+		return nil
+	}
+	return c.ISMClient.UpdatePolicy(c.Config.ISMPolicyName, policy, stored.SeqNo, stored.PrimaryTerm)
+}
+
+// ismPolicy returns the ISM policy rolling the indices over in the hot state,
+// and deleting them once they are old enough if deletion is configured.
+func (c Action) ismPolicy(rolloverIndices []app.IndexOption) client.ISMPolicy {
+	hot := client.ISMState{
+		Name: ismHotState,
+		Actions: []client.ISMAction{{Rollover: &client.ISMRollover{
+			MinIndexAge: c.Config.ISMRolloverMinIndexAge,
+			MinSize:     c.Config.ISMRolloverMinSize,
+		}}},
+	}
+	states := []client.ISMState{hot}
+	if c.Config.ISMDeleteMinIndexAge != "" {
+		hot.Transitions = []client.ISMTransition{{
+			StateName:  ismDeleteState,
+			Conditions: &client.ISMConditions{MinIndexAge: c.Config.ISMDeleteMinIndexAge},
+		}}
+		states = []client.ISMState{hot, {
+			Name:    ismDeleteState,
+			Actions: []client.ISMAction{{Delete: &client.ISMDelete{}}},
+		}}
+	}
+	patterns := make([]string, len(rolloverIndices))
+	for i, indexOption := range rolloverIndices {
+		patterns[i] = indexOption.IndexName() + "-*"
+	}
+	priority := ismTemplatePriority
+	if c.Config.Archive {
+		priority = ismArchiveTemplatePriority
+	}
+	return client.ISMPolicy{
+		Description:  "Jaeger indices managed by es-rollover",
+		DefaultState: ismHotState,
+		States:       states,
+		ISMTemplate:  []client.ISMTemplate{{IndexPatterns: patterns, Priority: priority}},
+	}
+}
+
 func createIndexIfNotExist(c client.IndexAPI, index string) error {
 	err := c.CreateIndex(index)
 	if err != nil {
@@ -146,7 +235,7 @@ func (c Action) init(version uint, indexopt app.IndexOption) error {
 		aliases = append(aliases, client.Alias{
 			Index:        index,
 			Name:         writeAlias,
-			IsWriteIndex: c.Config.UseILM,
+			IsWriteIndex: c.Config.UseILM || c.Config.UseISM,
 		})
 	}
 
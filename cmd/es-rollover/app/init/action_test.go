@@ -125,6 +125,7 @@ func TestRolloverAction(t *testing.T) {
 			name: "ilm doesnt exist",
 			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ilmClient *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(false, nil)
 				ilmClient.On("Exists", "myilmpolicy").Return(false, nil)
 			},
 			expectedErr: errors.New("ILM policy myilmpolicy doesn't exist in Elasticsearch. Please create it and re-run init"),
@@ -140,6 +141,7 @@ func TestRolloverAction(t *testing.T) {
 			name: "fail get ilm policy",
 			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ilmClient *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(false, nil)
 				ilmClient.On("Exists", "myilmpolicy").Return(false, errors.New("error getting ilm policy"))
 			},
 			expectedErr: errors.New("error getting ilm policy"),
@@ -151,11 +153,36 @@ func TestRolloverAction(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ilm with opensearch",
+			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexManagementLifecycleAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+			},
+			expectedErr: errors.New("ILM is not supported by OpenSearch, use ISM instead"),
+			config: Config{
+				Config: app.Config{
+					Archive: true,
+					UseILM:  true,
+				},
+			},
+		},
+		{
+			name:                  "ilm and ism",
+			setupCallExpectations: func(*mocks.IndexAPI, *mocks.ClusterAPI, *mocks.IndexManagementLifecycleAPI) {},
+			expectedErr:           errors.New("ILM and ISM cannot be used together"),
+			config: Config{
+				Config: app.Config{
+					UseILM: true,
+					UseISM: true,
+				},
+			},
+		},
 		{
 			name: "fail to create template",
 			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
-				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span").Return(errors.New("error creating template"))
+				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span-archive").Return(errors.New("error creating template"))
 			},
 			expectedErr: errors.New("error creating template"),
 			config: Config{
@@ -169,7 +196,7 @@ func TestRolloverAction(t *testing.T) {
 			name: "fail to get jaeger indices",
 			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
-				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span").Return(nil)
+				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span-archive").Return(nil)
 				indexClient.On("CreateIndex", "jaeger-span-archive-000001").Return(nil)
 				indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, errors.New("error getting jaeger indices"))
 			},
@@ -185,7 +212,7 @@ func TestRolloverAction(t *testing.T) {
 			name: "fail to create alias",
 			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
-				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span").Return(nil)
+				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span-archive").Return(nil)
 				indexClient.On("CreateIndex", "jaeger-span-archive-000001").Return(nil)
 				indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
 				indexClient.On("CreateAlias", []client.Alias{
@@ -205,7 +232,7 @@ func TestRolloverAction(t *testing.T) {
 			name: "create rollover index",
 			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
-				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span").Return(nil)
+				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span-archive").Return(nil)
 				indexClient.On("CreateIndex", "jaeger-span-archive-000001").Return(nil)
 				indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
 				indexClient.On("CreateAlias", []client.Alias{
@@ -225,7 +252,8 @@ func TestRolloverAction(t *testing.T) {
 			name: "create rollover index with ilm",
 			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ilmClient *mocks.IndexManagementLifecycleAPI) {
 				clusterClient.On("Version").Return(uint(7), nil)
-				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span").Return(nil)
+				clusterClient.On("IsOpenSearch").Return(false, nil)
+				indexClient.On("CreateTemplate", mock.Anything, "jaeger-span-archive").Return(nil)
 				indexClient.On("CreateIndex", "jaeger-span-archive-000001").Return(nil)
 				indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
 				ilmClient.On("Exists", "jaeger-ilm").Return(true, nil)
@@ -271,3 +299,181 @@ func TestRolloverAction(t *testing.T) {
 		})
 	}
 }
+
+func TestRolloverActionISM(t *testing.T) {
+	archivePolicy := client.ISMPolicy{
+		Description:  "Jaeger indices managed by es-rollover",
+		DefaultState: "hot",
+		States: []client.ISMState{
+			{
+				Name:        "hot",
+				Actions:     []client.ISMAction{{Rollover: &client.ISMRollover{MinIndexAge: "1d", MinSize: "50gb"}}},
+				Transitions: []client.ISMTransition{{StateName: "delete", Conditions: &client.ISMConditions{MinIndexAge: "7d"}}},
+			},
+			{
+				Name:    "delete",
+				Actions: []client.ISMAction{{Delete: &client.ISMDelete{}}},
+			},
+		},
+		ISMTemplate: []client.ISMTemplate{{IndexPatterns: []string{"jaeger-span-archive-*"}, Priority: 200}},
+	}
+	outdatedPolicy := archivePolicy
+	outdatedPolicy.ISMTemplate = []client.ISMTemplate{{IndexPatterns: []string{"jaeger-span-archive-*"}, Priority: 100}}
+	config := Config{
+		Config: app.Config{
+			Archive:       true,
+			UseISM:        true,
+			ISMPolicyName: "jaeger-ism",
+		},
+		ISMRolloverMinIndexAge: "1d",
+		ISMRolloverMinSize:     "50gb",
+		ISMDeleteMinIndexAge:   "7d",
+	}
+	expectInit := func(indexClient *mocks.IndexAPI) {
+		indexClient.On("CreateTemplate", mock.MatchedBy(func(mapping string) bool {
+			return strings.Contains(mapping, `"plugins.index_state_management.rollover_alias": "jaeger-span-archive-write"`)
+		}), "jaeger-span-archive").Return(nil)
+		indexClient.On("CreateIndex", "jaeger-span-archive-000001").Return(nil)
+		indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
+		indexClient.On("CreateAlias", []client.Alias{
+			{Index: "jaeger-span-archive-000001", Name: "jaeger-span-archive-read", IsWriteIndex: false},
+			{Index: "jaeger-span-archive-000001", Name: "jaeger-span-archive-write", IsWriteIndex: true},
+		}).Return(nil)
+	}
+
+	tests := []struct {
+		name                  string
+		setupCallExpectations func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI)
+		config                Config
+		expectedErr           error
+	}{
+		{
+			name: "elasticsearch",
+			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(false, nil)
+			},
+			config:      config,
+			expectedErr: errors.New("ISM is supported only by OpenSearch, use ILM with Elasticsearch"),
+		},
+		{
+			name: "fail to detect opensearch",
+			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, _ *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(false, errors.New("cluster error"))
+			},
+			config:      config,
+			expectedErr: errors.New("cluster error"),
+		},
+		{
+			name: "fail to get policy",
+			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(nil, errors.New("error getting ism policy"))
+			},
+			config:      config,
+			expectedErr: errors.New("error getting ism policy"),
+		},
+		{
+			name: "create policy",
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(nil, nil)
+				ismClient.On("CreatePolicy", "jaeger-ism", archivePolicy).Return(nil)
+				expectInit(indexClient)
+			},
+			config: config,
+		},
+		{
+			name: "fail to create policy",
+			setupCallExpectations: func(_ *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(nil, nil)
+				ismClient.On("CreatePolicy", "jaeger-ism", archivePolicy).Return(errors.New("error creating ism policy"))
+			},
+			config:      config,
+			expectedErr: errors.New("error creating ism policy"),
+		},
+		{
+			name: "policy up to date",
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(&client.StoredISMPolicy{Policy: archivePolicy, SeqNo: 3, PrimaryTerm: 1}, nil)
+				expectInit(indexClient)
+			},
+			config: config,
+		},
+		{
+			name: "update outdated policy",
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(&client.StoredISMPolicy{Policy: outdatedPolicy, SeqNo: 3, PrimaryTerm: 1}, nil)
+				ismClient.On("UpdatePolicy", "jaeger-ism", archivePolicy, int64(3), int64(1)).Return(nil)
+				expectInit(indexClient)
+			},
+			config: config,
+		},
+		{
+			name: "policy without delete state",
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, clusterClient *mocks.ClusterAPI, ismClient *mocks.IndexStateManagementAPI) {
+				clusterClient.On("Version").Return(uint(7), nil)
+				clusterClient.On("IsOpenSearch").Return(true, nil)
+				ismClient.On("GetPolicy", "jaeger-ism").Return(nil, nil)
+				ismClient.On("CreatePolicy", "jaeger-ism", client.ISMPolicy{
+					Description:  "Jaeger indices managed by es-rollover",
+					DefaultState: "hot",
+					States: []client.ISMState{{
+						Name:    "hot",
+						Actions: []client.ISMAction{{Rollover: &client.ISMRollover{MinIndexAge: "1d"}}},
+					}},
+					ISMTemplate: []client.ISMTemplate{{IndexPatterns: []string{"jaeger-span-*", "jaeger-service-*"}, Priority: 100}},
+				}).Return(nil)
+				indexClient.On("CreateTemplate", mock.Anything, mock.Anything).Return(nil)
+				indexClient.On("CreateIndex", mock.Anything).Return(nil)
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
+				indexClient.On("CreateAlias", mock.Anything).Return(nil)
+			},
+			config: Config{
+				Config: app.Config{
+					UseISM:           true,
+					ISMPolicyName:    "jaeger-ism",
+					SkipDependencies: true,
+				},
+				ISMRolloverMinIndexAge: "1d",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexClient := &mocks.IndexAPI{}
+			clusterClient := &mocks.ClusterAPI{}
+			ismClient := &mocks.IndexStateManagementAPI{}
+			initAction := Action{
+				Config:        test.config,
+				IndicesClient: indexClient,
+				ClusterClient: clusterClient,
+				ISMClient:     ismClient,
+			}
+
+			test.setupCallExpectations(indexClient, clusterClient, ismClient)
+
+			err := initAction.Do()
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			indexClient.AssertExpectations(t)
+			clusterClient.AssertExpectations(t)
+			ismClient.AssertExpectations(t)
+		})
+	}
+}
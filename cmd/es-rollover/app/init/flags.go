@@ -29,6 +29,9 @@ const (
 	priorityServiceTemplate      = "priority-service-template"
 	priorityDependenciesTemplate = "priority-dependencies-template"
 	prioritySamplingTemplate     = "priority-sampling-template"
+	ismRolloverMinIndexAge       = "ism-rollover-min-index-age"
+	ismRolloverMinSize           = "ism-rollover-min-size"
+	ismDeleteMinIndexAge         = "ism-delete-min-index-age"
 )
 
 // Config holds configuration for index cleaner binary.
@@ -40,6 +43,9 @@ type Config struct {
 	PriorityServiceTemplate      int
 	PriorityDependenciesTemplate int
 	PrioritySamplingTemplate     int
+	ISMRolloverMinIndexAge       string
+	ISMRolloverMinSize           string
+	ISMDeleteMinIndexAge         string
 }
 
 // AddFlags adds flags for TLS to the FlagSet.
@@ -50,6 +56,9 @@ func (*Config) AddFlags(flags *flag.FlagSet) {
 	flags.Int(priorityServiceTemplate, 0, "Priority of jaeger-service index template (ESv8 only)")
 	flags.Int(priorityDependenciesTemplate, 0, "Priority of jaeger-dependencies index template (ESv8 only)")
 	flags.Int(prioritySamplingTemplate, 0, "Priority of jaeger-sampling index template (ESv8 only)")
+	flags.String(ismRolloverMinIndexAge, "1d", "Minimum age of the write index before ISM rolls it over (ISM only)")
+	flags.String(ismRolloverMinSize, "", "Minimum size of the write index before ISM rolls it over, e.g. 50gb (ISM only)")
+	flags.String(ismDeleteMinIndexAge, "7d", "Minimum age of an index before ISM deletes it; empty keeps indices forever (ISM only)")
 }
 
 // InitFromViper initializes config from viper.Viper.
@@ -60,4 +69,7 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.PriorityServiceTemplate = v.GetInt(priorityServiceTemplate)
 	c.PriorityDependenciesTemplate = v.GetInt(priorityDependenciesTemplate)
 	c.PrioritySamplingTemplate = v.GetInt(prioritySamplingTemplate)
+	c.ISMRolloverMinIndexAge = v.GetString(ismRolloverMinIndexAge)
+	c.ISMRolloverMinSize = v.GetString(ismRolloverMinSize)
+	c.ISMDeleteMinIndexAge = v.GetString(ismDeleteMinIndexAge)
 }
@@ -40,6 +40,9 @@ func TestBindFlags(t *testing.T) {
 		"--priority-service-template=301",
 		"--priority-dependencies-template=302",
 		"--priority-sampling-template=303",
+		"--ism-rollover-min-index-age=2d",
+		"--ism-rollover-min-size=50gb",
+		"--ism-delete-min-index-age=30d",
 	})
 	require.NoError(t, err)
 
@@ -50,4 +53,7 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, 301, c.PriorityServiceTemplate)
 	assert.Equal(t, 302, c.PriorityDependenciesTemplate)
 	assert.Equal(t, 303, c.PrioritySamplingTemplate)
+	assert.Equal(t, "2d", c.ISMRolloverMinIndexAge)
+	assert.Equal(t, "50gb", c.ISMRolloverMinSize)
+	assert.Equal(t, "30d", c.ISMDeleteMinIndexAge)
 }
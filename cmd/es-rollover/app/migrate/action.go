@@ -0,0 +1,125 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/es-rollover/app"
+	"github.com/jaegertracing/jaeger/pkg/es/client"
+)
+
+// indexSuffixRegex matches the date (daily/hourly indices) or the counter (rollover indices)
+// that follows the index set name.
+var indexSuffixRegex = regexp.MustCompile(`^-\d[\d-]*$`)
+
+var errSamePrefix = errors.New("new index prefix must differ from the current index prefix")
+
+// Action holds the configuration and clients for the migrate-prefix action.
+// It copies every existing index of the current prefix into an index of the new prefix
+// and, once a copy is complete, adds it to the read alias of the new layout. The write
+// indices and aliases of the new layout are expected to be created beforehand by the init action,
+// and writers switched to them, so that no documents land in the current indices after their copy starts.
+type Action struct {
+	Config
+	IndicesClient client.IndexAPI
+	ReindexClient client.ReindexAPI
+	Logger        *zap.Logger
+}
+
+// Do the migrate-prefix action
+func (a *Action) Do() error {
+	if a.Config.NewIndexPrefix == a.Config.IndexPrefix {
+		return errSamePrefix
+	}
+	oldIndexSets := app.RolloverIndices(a.Config.Archive, a.Config.SkipDependencies, a.Config.AdaptiveSampling, a.Config.IndexPrefix)
+	newIndexSets := app.RolloverIndices(a.Config.Archive, a.Config.SkipDependencies, a.Config.AdaptiveSampling, a.Config.NewIndexPrefix)
+
+	oldIndices, err := a.IndicesClient.GetJaegerIndices(a.Config.IndexPrefix)
+	if err != nil {
+		return err
+	}
+	newIndices, err := a.IndicesClient.GetJaegerIndices(a.Config.NewIndexPrefix)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]client.Index, len(newIndices))
+	for _, index := range newIndices {
+		existing[index.Index] = index
+	}
+	for i := range oldIndexSets {
+		if err := a.migrate(oldIndices, existing, oldIndexSets[i], newIndexSets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Action) migrate(oldIndices []client.Index, existing map[string]client.Index, oldSet, newSet app.IndexOption) error {
+	readAlias := newSet.ReadAliasName()
+	for _, source := range sourceIndices(oldIndices, oldSet) {
+		dest := newSet.IndexName() + strings.TrimPrefix(source.Index, oldSet.IndexName())
+		if index, ok := existing[dest]; ok {
+			if index.Aliases[readAlias] && !index.Aliases[newSet.WriteAliasName()] {
+				a.Logger.Info("Index already migrated, skipping", zap.String("source", source.Index), zap.String("dest", dest))
+				continue
+			}
+			return fmt.Errorf("destination index %s already exists", dest)
+		}
+		if err := a.reindex(source.Index, dest); err != nil {
+			return err
+		}
+		if err := a.IndicesClient.CreateAlias([]client.Alias{{Index: dest, Name: readAlias}}); err != nil {
+			return err
+		}
+		a.Logger.Info("Index migrated", zap.String("source", source.Index), zap.String("dest", dest), zap.String("readAlias", readAlias))
+	}
+	return nil
+}
+
+func (a *Action) reindex(source, dest string) error {
+	taskID, err := a.ReindexClient.Reindex(source, dest)
+	if err != nil {
+		return err
+	}
+	a.Logger.Info("Reindex started", zap.String("source", source), zap.String("dest", dest), zap.String("task", taskID))
+	for {
+		task, err := a.ReindexClient.GetTask(taskID)
+		if err != nil {
+			return err
+		}
+		if task.Completed {
+			if task.Error != "" {
+				return fmt.Errorf("reindex of %s into %s failed: %s", source, dest, task.Error)
+			}
+			return nil
+		}
+		a.Logger.Info("Reindex in progress", zap.String("source", source), zap.Int64("done", task.Done), zap.Int64("total", task.Total))
+		time.Sleep(a.Config.PollInterval)
+	}
+}
+
+// sourceIndices returns the indices belonging to the index set, oldest first.
+func sourceIndices(indices []client.Index, indexSet app.IndexOption) []client.Index {
+	name := indexSet.IndexName()
+	var result []client.Index
+	for _, index := range indices {
+		suffix, ok := strings.CutPrefix(index.Index, name)
+		if !ok || (suffix != "" && !indexSuffixRegex.MatchString(suffix)) {
+			continue
+		}
+		result = append(result, index)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+	return result
+}
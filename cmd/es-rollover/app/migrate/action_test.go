@@ -0,0 +1,205 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/es-rollover/app"
+	"github.com/jaegertracing/jaeger/pkg/es/client"
+	"github.com/jaegertracing/jaeger/pkg/es/client/mocks"
+)
+
+func TestMigrateAction(t *testing.T) {
+	oldIndices := []client.Index{
+		{Index: "jaeger-span-2021-10-11"},
+		{Index: "jaeger-span-2021-10-10"},
+		{Index: "jaeger-span-archive"},
+		{Index: "jaeger-service-2021-10-10"},
+	}
+	newIndices := []client.Index{
+		{
+			Index:   "new-jaeger-span-000001",
+			Aliases: map[string]bool{"new-jaeger-span-read": true, "new-jaeger-span-write": true},
+		},
+		{
+			Index:   "new-jaeger-service-000001",
+			Aliases: map[string]bool{"new-jaeger-service-read": true, "new-jaeger-service-write": true},
+		},
+	}
+	config := Config{
+		Config: app.Config{
+			IndexPrefix:      "",
+			SkipDependencies: true,
+		},
+		NewIndexPrefix: "new-",
+		PollInterval:   time.Millisecond,
+	}
+
+	tests := []struct {
+		name                  string
+		config                Config
+		setupCallExpectations func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI)
+		expectedErr           string
+	}{
+		{
+			name:   "migrate indices in order with progress tracking",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return(oldIndices, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(newIndices, nil)
+				reindexClient.On("Reindex", "jaeger-span-2021-10-10", "new-jaeger-span-2021-10-10").Return("n:1", nil).Once()
+				reindexClient.On("GetTask", "n:1").Return(client.Task{ID: "n:1", Total: 2, Done: 1}, nil).Once()
+				reindexClient.On("GetTask", "n:1").Return(client.Task{ID: "n:1", Completed: true, Total: 2, Done: 2}, nil).Once()
+				indexClient.On("CreateAlias", []client.Alias{{Index: "new-jaeger-span-2021-10-10", Name: "new-jaeger-span-read"}}).Return(nil).Once()
+				reindexClient.On("Reindex", "jaeger-span-2021-10-11", "new-jaeger-span-2021-10-11").Return("n:2", nil).Once()
+				reindexClient.On("GetTask", "n:2").Return(client.Task{ID: "n:2", Completed: true}, nil).Once()
+				indexClient.On("CreateAlias", []client.Alias{{Index: "new-jaeger-span-2021-10-11", Name: "new-jaeger-span-read"}}).Return(nil).Once()
+				reindexClient.On("Reindex", "jaeger-service-2021-10-10", "new-jaeger-service-2021-10-10").Return("n:3", nil).Once()
+				reindexClient.On("GetTask", "n:3").Return(client.Task{ID: "n:3", Completed: true}, nil).Once()
+				indexClient.On("CreateAlias", []client.Alias{{Index: "new-jaeger-service-2021-10-10", Name: "new-jaeger-service-read"}}).Return(nil).Once()
+			},
+		},
+		{
+			name:   "skip already migrated indices",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, _ *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-2021-10-10"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return([]client.Index{
+					{Index: "new-jaeger-span-2021-10-10", Aliases: map[string]bool{"new-jaeger-span-read": true}},
+				}, nil)
+			},
+		},
+		{
+			name:   "destination collides with write index",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, _ *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-000001"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(newIndices, nil)
+			},
+			expectedErr: "destination index new-jaeger-span-000001 already exists",
+		},
+		{
+			name:   "reindex task fails",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-2021-10-10"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(nil, nil)
+				reindexClient.On("Reindex", "jaeger-span-2021-10-10", "new-jaeger-span-2021-10-10").Return("n:1", nil)
+				reindexClient.On("GetTask", "n:1").Return(client.Task{ID: "n:1", Completed: true, Error: "boom"}, nil)
+			},
+			expectedErr: "reindex of jaeger-span-2021-10-10 into new-jaeger-span-2021-10-10 failed: boom",
+		},
+		{
+			name:   "get task error",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-2021-10-10"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(nil, nil)
+				reindexClient.On("Reindex", "jaeger-span-2021-10-10", "new-jaeger-span-2021-10-10").Return("n:1", nil)
+				reindexClient.On("GetTask", "n:1").Return(client.Task{}, errors.New("task error"))
+			},
+			expectedErr: "task error",
+		},
+		{
+			name:   "reindex error",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-2021-10-10"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(nil, nil)
+				reindexClient.On("Reindex", "jaeger-span-2021-10-10", "new-jaeger-span-2021-10-10").Return("", errors.New("reindex error"))
+			},
+			expectedErr: "reindex error",
+		},
+		{
+			name:   "create alias error",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, reindexClient *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return([]client.Index{{Index: "jaeger-span-2021-10-10"}}, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(nil, nil)
+				reindexClient.On("Reindex", "jaeger-span-2021-10-10", "new-jaeger-span-2021-10-10").Return("n:1", nil)
+				reindexClient.On("GetTask", "n:1").Return(client.Task{ID: "n:1", Completed: true}, nil)
+				indexClient.On("CreateAlias", []client.Alias{{Index: "new-jaeger-span-2021-10-10", Name: "new-jaeger-span-read"}}).Return(errors.New("alias error"))
+			},
+			expectedErr: "alias error",
+		},
+		{
+			name:   "get current indices error",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, _ *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return(nil, errors.New("get indices error"))
+			},
+			expectedErr: "get indices error",
+		},
+		{
+			name:   "get new indices error",
+			config: config,
+			setupCallExpectations: func(indexClient *mocks.IndexAPI, _ *mocks.ReindexAPI) {
+				indexClient.On("GetJaegerIndices", "").Return(nil, nil)
+				indexClient.On("GetJaegerIndices", "new-").Return(nil, errors.New("get new indices error"))
+			},
+			expectedErr: "get new indices error",
+		},
+		{
+			name: "same prefix",
+			config: Config{
+				Config:         app.Config{IndexPrefix: "new-"},
+				NewIndexPrefix: "new-",
+			},
+			setupCallExpectations: func(*mocks.IndexAPI, *mocks.ReindexAPI) {},
+			expectedErr:           errSamePrefix.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexClient := mocks.NewIndexAPI(t)
+			reindexClient := mocks.NewReindexAPI(t)
+			test.setupCallExpectations(indexClient, reindexClient)
+			action := Action{
+				Config:        test.config,
+				IndicesClient: indexClient,
+				ReindexClient: reindexClient,
+				Logger:        zap.NewNop(),
+			}
+			err := action.Do()
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSourceIndices(t *testing.T) {
+	indices := []client.Index{
+		{Index: "jaeger-span-2021-10-11-05"},
+		{Index: "jaeger-span-2021-10-10"},
+		{Index: "jaeger-span-archive"},
+		{Index: "jaeger-span-archive-000001"},
+		{Index: "jaeger-span-000002"},
+		{Index: "jaeger-service-2021-10-10"},
+	}
+	spanSet := app.RolloverIndices(false, true, false, "")[0]
+	archiveSet := app.RolloverIndices(true, false, false, "")[0]
+
+	var names []string
+	for _, index := range sourceIndices(indices, spanSet) {
+		names = append(names, index.Index)
+	}
+	assert.Equal(t, []string{"jaeger-span-000002", "jaeger-span-2021-10-10", "jaeger-span-2021-10-11-05"}, names)
+
+	names = nil
+	for _, index := range sourceIndices(indices, archiveSet) {
+		names = append(names, index.Index)
+	}
+	assert.Equal(t, []string{"jaeger-span-archive", "jaeger-span-archive-000001"}, names)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/cmd/es-rollover/app"
+)
+
+const (
+	newIndexPrefix      = "new-index-prefix"
+	pollInterval        = "poll-interval"
+	defaultPollInterval = 5 * time.Second
+)
+
+// Config holds configuration for the migrate-prefix action.
+type Config struct {
+	app.Config
+	NewIndexPrefix string
+	PollInterval   time.Duration
+}
+
+// AddFlags adds flags for the migrate-prefix action to the FlagSet.
+func (*Config) AddFlags(flags *flag.FlagSet) {
+	flags.String(newIndexPrefix, "", "Index prefix of the read/write alias layout the existing indices are migrated to")
+	flags.Duration(pollInterval, defaultPollInterval, "How often the progress of a running reindex task is checked")
+}
+
+// InitFromViper initializes config from viper.Viper.
+func (c *Config) InitFromViper(v *viper.Viper) {
+	c.NewIndexPrefix = v.GetString(newIndexPrefix)
+	if c.NewIndexPrefix != "" {
+		c.NewIndexPrefix += "-"
+	}
+	c.PollInterval = v.GetDuration(pollInterval)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFlags(t *testing.T) {
+	v := viper.New()
+	c := &Config{}
+	command := cobra.Command{}
+	flags := &flag.FlagSet{}
+	c.AddFlags(flags)
+	command.PersistentFlags().AddGoFlagSet(flags)
+	v.BindPFlags(command.PersistentFlags())
+
+	err := command.ParseFlags([]string{
+		"--new-index-prefix=tenant1",
+		"--poll-interval=1m",
+	})
+	require.NoError(t, err)
+
+	c.InitFromViper(v)
+	assert.Equal(t, "tenant1-", c.NewIndexPrefix)
+	assert.Equal(t, time.Minute, c.PollInterval)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/es-rollover/app"
 	initialize "github.com/jaegertracing/jaeger/cmd/es-rollover/app/init"
 	"github.com/jaegertracing/jaeger/cmd/es-rollover/app/lookback"
+	"github.com/jaegertracing/jaeger/cmd/es-rollover/app/migrate"
 	"github.com/jaegertracing/jaeger/cmd/es-rollover/app/rollover"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
@@ -70,10 +71,14 @@ func main() {
 				ilmClient := &client.ILMClient{
 					Client: c,
 				}
+				ismClient := &client.ISMClient{
+					Client: c,
+				}
 				return &initialize.Action{
 					IndicesClient: indicesClient,
 					ClusterClient: clusterClient,
 					ILMClient:     ilmClient,
+					ISMClient:     ismClient,
 					Config:        *initCfg,
 				}
 			})
@@ -140,10 +145,41 @@ func main() {
 		},
 	}
 
+	migrateCfg := migrate.Config{}
+	migrateCommand := &cobra.Command{
+		Use:   "migrate-prefix http://HOSTNAME:PORT",
+		Short: "reindexes existing indices into the read/write alias layout of a new prefix",
+		Long: "reindexes existing indices into the read/write alias layout of a new prefix, tracking the progress of each reindex " +
+			"task and adding the copied indices to the new read alias; run init with the new prefix first",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return app.ExecuteAction(app.ActionExecuteOptions{
+				Args:     args,
+				Viper:    v,
+				Logger:   logger,
+				TLSFlags: tlsFlags,
+			}, func(c client.Client, cfg app.Config) app.Action {
+				migrateCfg.Config = cfg
+				migrateCfg.InitFromViper(v)
+				indicesClient := &client.IndicesClient{
+					Client:               c,
+					MasterTimeoutSeconds: migrateCfg.Timeout,
+				}
+				return &migrate.Action{
+					IndicesClient: indicesClient,
+					ReindexClient: indicesClient,
+					Config:        migrateCfg,
+					Logger:        logger,
+				}
+			})
+		},
+	}
+
 	addPersistentFlags(v, rootCmd, tlsFlags.AddFlags, app.AddFlags)
 	addSubCommand(v, rootCmd, initCommand, initCfg.AddFlags)
 	addSubCommand(v, rootCmd, rolloverCommand, rolloverCfg.AddFlags)
 	addSubCommand(v, rootCmd, lookbackCommand, lookbackCfg.AddFlags)
+	addSubCommand(v, rootCmd, migrateCommand, migrateCfg.AddFlags)
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
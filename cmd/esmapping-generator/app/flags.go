@@ -27,6 +27,7 @@ type Options struct {
 	IndexPrefix   string
 	UseILM        string // using string as util is being used in python and using bool leads to type issues.
 	ILMPolicyName string
+	DocValuesOnly string
 }
 
 const (
@@ -37,6 +38,7 @@ const (
 	indexPrefixFlag   = "index-prefix"
 	useILMFlag        = "use-ilm"
 	ilmPolicyNameFlag = "ilm-policy-name"
+	docValuesOnlyFlag = "doc-values-only"
 )
 
 // AddFlags adds flags for esmapping-generator main program
@@ -76,6 +78,11 @@ func (o *Options) AddFlags(command *cobra.Command) {
 		ilmPolicyNameFlag,
 		"jaeger-ilm-policy",
 		"The name of the ILM policy to use if ILM is active")
+	command.Flags().StringVar(
+		&o.DocValuesOnly,
+		docValuesOnlyFlag,
+		"",
+		"Comma delimited list of span field groups stored without indexing, keeping only doc_values: logs, references, tags, process-tags")
 
 	// mark mapping flag as mandatory
 	command.MarkFlagRequired(mappingFlag)
@@ -36,6 +36,7 @@ func TestOptionsWithDefaultFlags(t *testing.T) {
 	assert.Equal(t, "", o.IndexPrefix)
 	assert.Equal(t, "false", o.UseILM)
 	assert.Equal(t, "jaeger-ilm-policy", o.ILMPolicyName)
+	assert.Equal(t, "", o.DocValuesOnly)
 }
 
 func TestOptionsWithFlags(t *testing.T) {
@@ -51,6 +52,7 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--index-prefix=test",
 		"--use-ilm=true",
 		"--ilm-policy-name=jaeger-test-policy",
+		"--doc-values-only=logs,references",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "jaeger-span", o.Mapping)
@@ -60,6 +62,7 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, "test", o.IndexPrefix)
 	assert.Equal(t, "true", o.UseILM)
 	assert.Equal(t, "jaeger-test-policy", o.ILMPolicyName)
+	assert.Equal(t, "logs,references", o.DocValuesOnly)
 }
 
 func TestMain(m *testing.M) {
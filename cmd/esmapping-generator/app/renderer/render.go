@@ -19,6 +19,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/esmapping-generator/app"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
 )
 
@@ -35,6 +36,10 @@ func GetMappingAsString(builder es.TemplateBuilder, opt *app.Options) (string, e
 	if err != nil {
 		return "", err
 	}
+	docValuesOnly, err := config.ParseDocValuesOnly(opt.DocValuesOnly)
+	if err != nil {
+		return "", err
+	}
 
 	mappingBuilder := mappings.MappingBuilder{
 		TemplateBuilder: builder,
@@ -44,6 +49,7 @@ func GetMappingAsString(builder es.TemplateBuilder, opt *app.Options) (string, e
 		IndexPrefix:     opt.IndexPrefix,
 		UseILM:          enableILM,
 		ILMPolicyName:   opt.ILMPolicyName,
+		DocValuesOnly:   docValuesOnly,
 	}
 	return mappingBuilder.GetMapping(opt.Mapping)
 }
@@ -18,12 +18,18 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	kafkaConsumer "github.com/jaegertracing/jaeger/pkg/kafka/consumer"
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
+	kafkaProducer "github.com/jaegertracing/jaeger/pkg/kafka/producer"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -31,28 +37,59 @@ import (
 
 // CreateConsumer creates a new span consumer for the ingester
 func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWriter spanstore.Writer, options app.Options) (*consumer.Consumer, error) {
-	var unmarshaller kafka.Unmarshaller
-	switch options.Encoding {
-	case kafka.EncodingJSON:
-		unmarshaller = kafka.NewJSONUnmarshaller()
-	case kafka.EncodingProto:
-		unmarshaller = kafka.NewProtobufUnmarshaller()
-	case kafka.EncodingZipkinThrift:
-		unmarshaller = kafka.NewZipkinThriftUnmarshaller()
-	default:
-		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
-			options.Encoding, strings.Join(kafka.AllEncodings, "\", \""))
+	unmarshallers := make(map[string]kafka.Unmarshaller)
+	unmarshallerOf := func(encoding string) (kafka.Unmarshaller, error) {
+		if unmarshaller, ok := unmarshallers[encoding]; ok {
+			return unmarshaller, nil
+		}
+		unmarshaller, err := createUnmarshaller(logger, encoding, options)
+		if err != nil {
+			return nil, err
+		}
+		unmarshallers[encoding] = unmarshaller
+		return unmarshaller, nil
+	}
+	unmarshaller, err := unmarshallerOf(options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	var topicUnmarshallers map[string]kafka.Unmarshaller
+	for topic, encoding := range options.TopicEncodings {
+		if encoding == options.Encoding {
+			continue
+		}
+		topicUnmarshaller, err := unmarshallerOf(encoding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encoding of topic %s: %w", topic, err)
+		}
+		if topicUnmarshallers == nil {
+			topicUnmarshallers = make(map[string]kafka.Unmarshaller)
+		}
+		topicUnmarshallers[topic] = topicUnmarshaller
+	}
+	// the messages telling their encoding in a header are decoded whatever their topic,
+	// except the avro ones if there is no schema registry
+	for _, encoding := range kafka.AllEncodings {
+		if encoding == kafka.EncodingAvro && options.SchemaRegistry.URL == "" {
+			continue
+		}
+		if _, err := unmarshallerOf(encoding); err != nil {
+			return nil, err
+		}
 	}
 
 	spParams := processor.SpanProcessorParams{
-		Writer:       spanWriter,
-		Unmarshaller: unmarshaller,
+		Writer:                spanWriter,
+		Unmarshaller:          unmarshaller,
+		TopicUnmarshallers:    topicUnmarshallers,
+		EncodingUnmarshallers: unmarshallers,
 	}
 	spanProcessor := processor.NewSpanProcessor(spParams)
 
 	consumerConfig := kafkaConsumer.Configuration{
 		Brokers:              options.Brokers,
 		Topic:                options.Topic,
+		Topics:               options.Topics,
 		InitialOffset:        options.InitialOffset,
 		GroupID:              options.GroupID,
 		ClientID:             options.ClientID,
@@ -73,6 +110,17 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 		Logger:         logger,
 		Factory:        metricsFactory,
 	}
+	if options.Quarantine.Enabled() {
+		q, err := createQuarantine(logger, metricsFactory, options)
+		if err != nil {
+			return nil, err
+		}
+		factoryParams.Quarantine = q
+		factoryParams.RetryOptions = []decorator.RetryOption{
+			decorator.MaxAttempts(max(options.Quarantine.MaxAttempts, 1) - 1),
+			decorator.PropagateError(true),
+		}
+	}
 	processorFactory, err := consumer.NewProcessorFactory(factoryParams)
 	if err != nil {
 		return nil, err
@@ -84,6 +132,61 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 		MetricsFactory:        metricsFactory,
 		Logger:                logger,
 		DeadlockCheckInterval: options.DeadlockInterval,
+		TracePartitions:       options.VerifyTracePartitions,
 	}
 	return consumer.New(consumerParams)
 }
+
+func createUnmarshaller(logger *zap.Logger, encoding string, options app.Options) (kafka.Unmarshaller, error) {
+	var unmarshaller kafka.Unmarshaller
+	switch encoding {
+	case kafka.EncodingJSON:
+		unmarshaller = kafka.NewJSONUnmarshaller()
+	case kafka.EncodingProto:
+		unmarshaller = kafka.NewProtobufUnmarshaller()
+	case kafka.EncodingZipkinThrift:
+		unmarshaller = kafka.NewZipkinThriftUnmarshaller()
+	case kafka.EncodingAvro:
+		registry, err := schemaregistry.NewClient(options.SchemaRegistry, logger)
+		if err != nil {
+			return nil, err
+		}
+		unmarshaller = kafka.NewAvroUnmarshaller(registry)
+	default:
+		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
+			encoding, strings.Join(kafka.AllEncodings, "\", \""))
+	}
+	if options.EncryptionKeyFile != "" {
+		keys, err := encryption.NewFileKeyProvider(options.EncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		unmarshaller = kafka.NewDecryptingUnmarshaller(encryption.NewEncryptor(keys), unmarshaller)
+	}
+	return unmarshaller, nil
+}
+
+func createQuarantine(logger *zap.Logger, metricsFactory metrics.Factory, options app.Options) (*quarantine.Quarantine, error) {
+	if err := options.Quarantine.Validate(); err != nil {
+		return nil, err
+	}
+	if options.Quarantine.Directory != "" {
+		sink, err := quarantine.NewDirectorySink(options.Quarantine.Directory)
+		if err != nil {
+			return nil, err
+		}
+		return quarantine.New(sink, metricsFactory, logger), nil
+	}
+	producerConfig := kafkaProducer.Configuration{
+		Brokers:              options.Brokers,
+		RequiredAcks:         sarama.WaitForAll,
+		ProtocolVersion:      options.ProtocolVersion,
+		MaxMessageBytes:      int(options.FetchMaxMessageBytes),
+		AuthenticationConfig: options.AuthenticationConfig,
+	}
+	producer, err := producerConfig.NewSyncProducer(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the quarantine producer: %w", err)
+	}
+	return quarantine.New(quarantine.NewKafkaSink(producer, options.Quarantine.Topic), metricsFactory, logger), nil
+}
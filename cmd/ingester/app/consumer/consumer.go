@@ -15,6 +15,7 @@
 package consumer
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -23,8 +24,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	"github.com/jaegertracing/jaeger/pkg/kafka/consumer"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 )
 
 // Params are the parameters of a Consumer
@@ -34,6 +37,9 @@ type Params struct {
 	Logger                *zap.Logger
 	InternalConsumer      consumer.Consumer
 	DeadlockCheckInterval time.Duration
+	// TracePartitions is the number of partitions of the topic, to verify that the messages were
+	// assigned to partitions by kafka.TraceIDPartition. It is disabled if 0.
+	TracePartitions int32
 }
 
 // Consumer uses sarama to consume and handle messages from kafka
@@ -45,6 +51,7 @@ type Consumer struct {
 	processorFactory ProcessorFactory
 
 	deadlockDetector deadlockDetector
+	tracePartitions  int32
 
 	partitionIDToState  map[int32]*consumerState
 	partitionMapLock    sync.Mutex
@@ -67,6 +74,7 @@ func New(params Params) (*Consumer, error) {
 		internalConsumer:    params.InternalConsumer,
 		processorFactory:    params.ProcessorFactory,
 		deadlockDetector:    deadlockDetector,
+		tracePartitions:     params.TracePartitions,
 		partitionIDToState:  make(map[int32]*consumerState),
 		partitionsHeldGauge: partitionsHeldGauge(params.MetricsFactory),
 	}, nil
@@ -104,9 +112,18 @@ func (c *Consumer) Close() error {
 	c.logger.Debug("Waiting for messages and errors to be handled")
 	c.doneWg.Wait()
 
+	if q := c.processorFactory.quarantine; q != nil {
+		c.logger.Debug("Closing quarantine")
+		err = errors.Join(err, q.Close())
+	}
 	return err
 }
 
+// Quarantine returns the quarantine of the messages that cannot be processed, or nil if disabled.
+func (c *Consumer) Quarantine() *quarantine.Quarantine {
+	return c.processorFactory.quarantine
+}
+
 // handleMessages handles incoming Kafka messages on a channel
 func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 	c.logger.Info("Starting message handler", zap.Int32("partition", pc.Partition()))
@@ -142,6 +159,9 @@ func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 			msgMetrics.offsetGauge.Update(msg.Offset)
 			msgMetrics.lagGauge.Update(pc.HighWaterMarkOffset() - msg.Offset - 1)
 			deadlockDetector.incrementMsgCount()
+			if c.tracePartitions > 0 {
+				c.verifyTracePartition(msg, msgMetrics)
+			}
 
 			if msgProcessor == nil {
 				msgProcessor = c.processorFactory.new(pc.Topic(), pc.Partition(), msg.Offset-1)
@@ -161,6 +181,24 @@ func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 	}
 }
 
+// verifyTracePartition logs and counts the message if it is not in the partition of its trace ID.
+func (c *Consumer) verifyTracePartition(msg *sarama.ConsumerMessage, msgMetrics msgMetrics) {
+	expected, err := kafka.KeyPartition(msg.Key, c.tracePartitions)
+	if err == nil && expected == msg.Partition {
+		return
+	}
+	msgMetrics.misroutedCounter.Inc(1)
+	if err != nil {
+		c.logger.Warn("Cannot verify the partition of a Kafka message", zap.Error(err), zap.Int32("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+		return
+	}
+	c.logger.Warn("Kafka message is not in the partition of its trace ID",
+		zap.ByteString("trace_id", msg.Key),
+		zap.Int32("partition", msg.Partition),
+		zap.Int32("expected_partition", expected),
+		zap.Int64("offset", msg.Offset))
+}
+
 func (c *Consumer) closePartition(partitionConsumer sc.PartitionConsumer) {
 	c.logger.Info("Closing partition consumer", zap.Int32("partition", partitionConsumer.Partition()))
 	partitionConsumer.Close() // blocks until messages channel is drained
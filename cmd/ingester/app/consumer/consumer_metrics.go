@@ -26,6 +26,8 @@ type msgMetrics struct {
 	counter     metrics.Counter
 	offsetGauge metrics.Gauge
 	lagGauge    metrics.Gauge
+	// misroutedCounter counts the messages that are not in the partition of their trace ID
+	misroutedCounter metrics.Counter
 }
 
 type errMetrics struct {
@@ -54,6 +56,8 @@ func (c *Consumer) newMsgMetrics(topic string, partition int32) msgMetrics {
 		counter:     f.Counter(metrics.Options{Name: "messages", Tags: nil}),
 		offsetGauge: f.Gauge(metrics.Options{Name: "current-offset", Tags: nil}),
 		lagGauge:    f.Gauge(metrics.Options{Name: "offset-lag", Tags: nil}),
+
+		misroutedCounter: f.Counter(metrics.Options{Name: "misrouted-messages", Tags: nil}),
 	}
 }
 
@@ -32,9 +32,12 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	pmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/kafka/consumer"
 	kmocks "github.com/jaegertracing/jaeger/pkg/kafka/consumer/mocks"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 )
 
 //go:generate mockery -dir ../../../../pkg/kafka/config/ -name Consumer
@@ -203,6 +206,30 @@ func TestSaramaConsumerWrapper_start_Messages(t *testing.T) {
 	})
 }
 
+func TestVerifyTracePartition(t *testing.T) {
+	localFactory := metricstest.NewFactory(0)
+	logger, logBuffer := testutils.NewLogger()
+	undertest, err := New(Params{MetricsFactory: localFactory, Logger: logger, TracePartitions: 4})
+	require.NoError(t, err)
+	msgMetrics := undertest.newMsgMetrics(topic, partition)
+
+	traceID := model.NewTraceID(1, 2)
+	expected := kafka.TraceIDPartition(traceID, 4)
+	undertest.verifyTracePartition(&sarama.ConsumerMessage{Key: []byte(traceID.String()), Partition: expected}, msgMetrics)
+	assert.Empty(t, logBuffer.Lines())
+
+	undertest.verifyTracePartition(&sarama.ConsumerMessage{Key: []byte(traceID.String()), Partition: (expected + 1) % 4}, msgMetrics)
+	assert.Contains(t, logBuffer.String(), "Kafka message is not in the partition of its trace ID")
+	undertest.verifyTracePartition(&sarama.ConsumerMessage{Partition: expected}, msgMetrics)
+	assert.Contains(t, logBuffer.String(), "Cannot verify the partition of a Kafka message")
+
+	localFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name:  "sarama-consumer.misrouted-messages",
+		Tags:  map[string]string{"topic": topic, "partition": fmt.Sprint(partition)},
+		Value: 2,
+	})
+}
+
 func TestSaramaConsumerWrapper_start_Errors(t *testing.T) {
 	localFactory := metricstest.NewFactory(0)
 
@@ -50,3 +50,14 @@ func (m saramaMessageWrapper) Partition() int32 {
 func (m saramaMessageWrapper) Offset() int64 {
 	return m.ConsumerMessage.Offset
 }
+
+// Header returns the value of the last header of the message with the key, nil if there is none.
+func (m saramaMessageWrapper) Header(key string) []byte {
+	var value []byte
+	for _, header := range m.ConsumerMessage.Headers {
+		if header != nil && string(header.Key) == key {
+			value = header.Value
+		}
+	}
+	return value
+}
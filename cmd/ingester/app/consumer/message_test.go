@@ -38,3 +38,16 @@ func TestSaramaMessageWrapper(t *testing.T) {
 	assert.Equal(t, saramaMessage.Partition, wrappedMessage.Partition())
 	assert.Equal(t, saramaMessage.Offset, wrappedMessage.Offset())
 }
+
+func TestSaramaMessageWrapperHeader(t *testing.T) {
+	wrappedMessage := saramaMessageWrapper{&sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("jaeger-tenant"), Value: []byte("acme")},
+			nil,
+			{Key: []byte("jaeger-tenant"), Value: []byte("globex")},
+		},
+	}}
+
+	assert.Equal(t, []byte("globex"), wrappedMessage.Header("jaeger-tenant"))
+	assert.Nil(t, wrappedMessage.Header("jaeger-encoding"))
+}
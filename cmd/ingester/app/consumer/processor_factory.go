@@ -22,6 +22,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/offset"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	"github.com/jaegertracing/jaeger/pkg/kafka/consumer"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
@@ -34,6 +35,8 @@ type ProcessorFactoryParams struct {
 	Factory        metrics.Factory
 	Logger         *zap.Logger
 	RetryOptions   []decorator.RetryOption
+	// Quarantine quarantines the messages that still fail after the retries, if not nil.
+	Quarantine *quarantine.Quarantine
 }
 
 // ProcessorFactory is a factory for creating startedProcessors
@@ -44,6 +47,7 @@ type ProcessorFactory struct {
 	baseProcessor  processor.SpanProcessor
 	parallelism    int
 	retryOptions   []decorator.RetryOption
+	quarantine     *quarantine.Quarantine
 }
 
 // NewProcessorFactory constructs a new ProcessorFactory
@@ -55,6 +59,7 @@ func NewProcessorFactory(params ProcessorFactoryParams) (*ProcessorFactory, erro
 		baseProcessor:  params.BaseProcessor,
 		parallelism:    params.Parallelism,
 		retryOptions:   params.RetryOptions,
+		quarantine:     params.Quarantine,
 	}, nil
 }
 
@@ -68,6 +73,9 @@ func (c *ProcessorFactory) new(topic string, partition int32, minOffset int64) p
 	om := offset.NewManager(minOffset, markOffset, topic, partition, c.metricsFactory)
 
 	retryProcessor := decorator.NewRetryingProcessor(c.metricsFactory, c.baseProcessor, c.retryOptions...)
+	if c.quarantine != nil {
+		retryProcessor = c.quarantine.Decorate(retryProcessor)
+	}
 	cp := NewCommittingProcessor(retryProcessor, om)
 	spanProcessor := processor.NewDecoratedProcessor(c.metricsFactory, cp)
 	pp := processor.NewParallelProcessor(spanProcessor, c.parallelism, c.logger)
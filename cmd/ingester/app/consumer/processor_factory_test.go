@@ -15,6 +15,7 @@
 package consumer
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -24,7 +25,9 @@ import (
 	"go.uber.org/zap"
 
 	cmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	kmocks "github.com/jaegertracing/jaeger/pkg/kafka/consumer/mocks"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
@@ -67,6 +70,47 @@ func Test_new(t *testing.T) {
 	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
 }
 
+func Test_newWithQuarantine(t *testing.T) {
+	mockConsumer := &kmocks.Consumer{}
+	mockConsumer.On("MarkPartitionOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	topic := "coelacanth"
+	partition := int32(21)
+	offset := int64(555)
+
+	sp := &mocks.SpanProcessor{}
+	sp.On("Process", mock.Anything).Return(errors.New("cannot unmarshall byte array into span"))
+
+	sink, err := quarantine.NewDirectorySink(t.TempDir())
+	require.NoError(t, err)
+	q := quarantine.New(sink, metrics.NullFactory, zap.NewNop())
+	pf := ProcessorFactory{
+		consumer:       mockConsumer,
+		metricsFactory: metrics.NullFactory,
+		logger:         zap.NewNop(),
+		baseProcessor:  sp,
+		parallelism:    1,
+		retryOptions:   []decorator.RetryOption{decorator.MaxAttempts(0), decorator.PropagateError(true)},
+		quarantine:     q,
+	}
+
+	processor := pf.new(topic, partition, offset)
+	defer processor.Close()
+	msg := &cmocks.Message{}
+	msg.On("Key").Return([]byte("key"))
+	msg.On("Value").Return([]byte("value"))
+	msg.On("Topic").Return(topic)
+	msg.On("Partition").Return(partition)
+	msg.On("Offset").Return(offset + 1)
+	processor.Process(msg)
+
+	// The quarantined message is committed, so that the ingester continues with the next messages.
+	time.Sleep(150 * time.Millisecond)
+	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
+	require.Len(t, q.Recent(), 1)
+	assert.Equal(t, offset+1, q.Recent()[0].Offset)
+}
+
 type fakeService struct {
 	startCalled bool
 	closeCalled bool
@@ -17,14 +17,17 @@ package app
 import (
 	"flag"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	"github.com/jaegertracing/jaeger/pkg/kafka/auth"
 	kafkaConsumer "github.com/jaegertracing/jaeger/pkg/kafka/consumer"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 )
 
@@ -37,6 +40,8 @@ const (
 	SuffixBrokers = ".brokers"
 	// SuffixTopic is a suffix for the topic flag
 	SuffixTopic = ".topic"
+	// SuffixTopics is a suffix for the additional topics flag
+	SuffixTopics = ".topics"
 	// SuffixRackID is a suffix for the consumer rack-id flag
 	SuffixRackID = ".rack-id"
 	// SuffixFetchMaxMessageBytes is a suffix for the consumer fetch-max-message-bytes flag
@@ -53,6 +58,16 @@ const (
 	SuffixDeadlockInterval = ".deadlockInterval"
 	// SuffixParallelism is a suffix for the parallelism flag
 	SuffixParallelism = ".parallelism"
+	// SuffixEncryptionKeyFile is a suffix for the encryption key file flag
+	SuffixEncryptionKeyFile = ".encryption.key-file"
+	// SuffixVerifyTracePartitions is a suffix for the trace partitioning verification flag
+	SuffixVerifyTracePartitions = ".verify-trace-partitions"
+	// SuffixQuarantineTopic is a suffix for the quarantine topic flag
+	SuffixQuarantineTopic = ".quarantine.topic"
+	// SuffixQuarantineDirectory is a suffix for the quarantine directory flag
+	SuffixQuarantineDirectory = ".quarantine.directory"
+	// SuffixQuarantineMaxAttempts is a suffix for the quarantine max attempts flag
+	SuffixQuarantineMaxAttempts = ".quarantine.max-attempts"
 	// SuffixHTTPPort is a suffix for the HTTP port
 	SuffixHTTPPort = ".http-port"
 	// DefaultBroker is the default kafka broker
@@ -71,14 +86,22 @@ const (
 	DefaultDeadlockInterval = time.Duration(0)
 	// DefaultFetchMaxMessageBytes is the default for kafka.consumer.fetch-max-message-bytes flag
 	DefaultFetchMaxMessageBytes = 1024 * 1024 // 1MB
+	// DefaultQuarantineMaxAttempts is the default number of attempts to process a message before it is quarantined
+	DefaultQuarantineMaxAttempts = 3
 )
 
 // Options stores the configuration options for the Ingester
 type Options struct {
 	kafkaConsumer.Configuration `mapstructure:",squash"`
-	Parallelism                 int           `mapstructure:"parallelism"`
-	Encoding                    string        `mapstructure:"encoding"`
-	DeadlockInterval            time.Duration `mapstructure:"deadlock_interval"`
+	Parallelism                 int                   `mapstructure:"parallelism"`
+	Encoding                    string                `mapstructure:"encoding"`
+	EncryptionKeyFile           string                `mapstructure:"encryption_key_file"`
+	SchemaRegistry              schemaregistry.Config `mapstructure:"schema_registry"`
+	DeadlockInterval            time.Duration         `mapstructure:"deadlock_interval"`
+	VerifyTracePartitions       int32                 `mapstructure:"verify_trace_partitions"`
+	Quarantine                  quarantine.Options    `mapstructure:"quarantine"`
+	// TopicEncodings are the encodings of the spans of the Topics that differ from Encoding
+	TopicEncodings map[string]string `mapstructure:"topic_encodings"`
 }
 
 // AddFlags adds flags for Builder
@@ -91,6 +114,22 @@ func AddFlags(flagSet *flag.FlagSet) {
 		ConfigPrefix+SuffixDeadlockInterval,
 		DefaultDeadlockInterval,
 		"Interval to check for deadlocks. If no messages gets processed in given time, ingester app will exit. Value of 0 disables deadlock check.")
+	flagSet.Int(
+		ConfigPrefix+SuffixVerifyTracePartitions,
+		0,
+		fmt.Sprintf(`(experimental) The number of partitions of the topic, to verify that the spans were written by collectors with --kafka.producer.partitioner=%s, i.e. that each message is in the partition of its trace ID. Misrouted messages are logged and counted, but still processed. Value of 0 disables the verification.`, kafka.PartitionerTraceID))
+	flagSet.String(
+		ConfigPrefix+SuffixQuarantineTopic,
+		"",
+		"The Kafka topic, on the brokers of the consumer, to publish the messages that cannot be unmarshalled or stored after the maximum number of attempts, so that the ingester continues with the next messages.")
+	flagSet.String(
+		ConfigPrefix+SuffixQuarantineDirectory,
+		"",
+		"The directory to write the messages that cannot be unmarshalled or stored after the maximum number of attempts, if no quarantine topic is set.")
+	flagSet.Uint(
+		ConfigPrefix+SuffixQuarantineMaxAttempts,
+		DefaultQuarantineMaxAttempts,
+		"The number of attempts to process a message before it is quarantined.")
 
 	// Authentication flags
 	flagSet.String(
@@ -101,6 +140,11 @@ func AddFlags(flagSet *flag.FlagSet) {
 		KafkaConsumerConfigPrefix+SuffixTopic,
 		DefaultTopic,
 		"The name of the kafka topic to consume from")
+	flagSet.String(
+		KafkaConsumerConfigPrefix+SuffixTopics,
+		"",
+		"The comma-separated list of additional kafka topics to consume from, e.g. during a migration to a new topic or encoding. "+
+			"Each topic may be suffixed with \"=\" and the encoding of its spans, e.g. jaeger-spans-json=json, and uses the encoding of the topic otherwise")
 	flagSet.String(
 		KafkaConsumerConfigPrefix+SuffixGroupID,
 		DefaultGroupID,
@@ -117,6 +161,10 @@ func AddFlags(flagSet *flag.FlagSet) {
 		KafkaConsumerConfigPrefix+SuffixEncoding,
 		DefaultEncoding,
 		fmt.Sprintf(`The encoding of spans ("%s") consumed from kafka`, strings.Join(kafka.AllEncodings, "\", \"")))
+	flagSet.String(
+		KafkaConsumerConfigPrefix+SuffixEncryptionKeyFile,
+		"",
+		"(experimental) Path to a JSON file with the AES keys decrypting span payloads encrypted by the collector. Only encrypted spans are accepted when set.")
 	flagSet.String(
 		KafkaConsumerConfigPrefix+SuffixRackID,
 		"",
@@ -125,6 +173,7 @@ func AddFlags(flagSet *flag.FlagSet) {
 		KafkaConsumerConfigPrefix+SuffixFetchMaxMessageBytes,
 		DefaultFetchMaxMessageBytes,
 		"The maximum number of message bytes to fetch from the broker in a single request. So you must be sure this is at least as large as your largest message.")
+	schemaregistry.AddFlags(KafkaConsumerConfigPrefix, flagSet)
 
 	auth.AddFlags(KafkaConsumerConfigPrefix, flagSet)
 }
@@ -133,20 +182,49 @@ func AddFlags(flagSet *flag.FlagSet) {
 func (o *Options) InitFromViper(v *viper.Viper) {
 	o.Brokers = strings.Split(stripWhiteSpace(v.GetString(KafkaConsumerConfigPrefix+SuffixBrokers)), ",")
 	o.Topic = v.GetString(KafkaConsumerConfigPrefix + SuffixTopic)
+	o.Topics, o.TopicEncodings = parseTopics(stripWhiteSpace(v.GetString(KafkaConsumerConfigPrefix + SuffixTopics)))
 	o.GroupID = v.GetString(KafkaConsumerConfigPrefix + SuffixGroupID)
 	o.ClientID = v.GetString(KafkaConsumerConfigPrefix + SuffixClientID)
 	o.ProtocolVersion = v.GetString(KafkaConsumerConfigPrefix + SuffixProtocolVersion)
 	o.Encoding = v.GetString(KafkaConsumerConfigPrefix + SuffixEncoding)
+	o.EncryptionKeyFile = v.GetString(KafkaConsumerConfigPrefix + SuffixEncryptionKeyFile)
 	o.RackID = v.GetString(KafkaConsumerConfigPrefix + SuffixRackID)
 	o.FetchMaxMessageBytes = v.GetInt32(KafkaConsumerConfigPrefix + SuffixFetchMaxMessageBytes)
+	if err := o.SchemaRegistry.InitFromViper(KafkaConsumerConfigPrefix, v); err != nil {
+		log.Fatal(err)
+	}
 
 	o.Parallelism = v.GetInt(ConfigPrefix + SuffixParallelism)
 	o.DeadlockInterval = v.GetDuration(ConfigPrefix + SuffixDeadlockInterval)
+	o.VerifyTracePartitions = v.GetInt32(ConfigPrefix + SuffixVerifyTracePartitions)
+	o.Quarantine.Topic = v.GetString(ConfigPrefix + SuffixQuarantineTopic)
+	o.Quarantine.Directory = v.GetString(ConfigPrefix + SuffixQuarantineDirectory)
+	o.Quarantine.MaxAttempts = v.GetUint(ConfigPrefix + SuffixQuarantineMaxAttempts)
 	authenticationOptions := auth.AuthenticationConfig{}
 	authenticationOptions.InitFromViper(KafkaConsumerConfigPrefix, v)
 	o.AuthenticationConfig = authenticationOptions
 }
 
+// parseTopics parses a comma-separated list of topics, each optionally suffixed with "=<encoding>".
+func parseTopics(list string) ([]string, map[string]string) {
+	var topics []string
+	var encodings map[string]string
+	for _, item := range strings.Split(list, ",") {
+		if item == "" {
+			continue
+		}
+		topic, encoding, found := strings.Cut(item, "=")
+		topics = append(topics, topic)
+		if found {
+			if encodings == nil {
+				encodings = make(map[string]string)
+			}
+			encodings[topic] = encoding
+		}
+	}
+	return topics, encodings
+}
+
 // stripWhiteSpace removes all whitespace characters from a string
 func stripWhiteSpace(str string) string {
 	return strings.ReplaceAll(str, " ", "")
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/kafka/auth"
@@ -34,19 +35,28 @@ func TestOptionsWithFlags(t *testing.T) {
 	v, command := config.Viperize(AddFlags)
 	command.ParseFlags([]string{
 		"--kafka.consumer.topic=topic1",
+		"--kafka.consumer.topics=topic2, topic3=proto",
 		"--kafka.consumer.brokers=127.0.0.1:9092, 0.0.0:1234",
 		"--kafka.consumer.group-id=group1",
 		"--kafka.consumer.client-id=client-id1",
 		"--kafka.consumer.rack-id=rack1",
 		"--kafka.consumer.fetch-max-message-bytes=10485760",
 		"--kafka.consumer.encoding=json",
+		"--kafka.consumer.encryption.key-file=/etc/jaeger/keys.json",
+		"--kafka.consumer.schema-registry.url=http://registry:8081",
+		"--kafka.consumer.schema-registry.username=ingester",
 		"--kafka.consumer.protocol-version=1.0.0",
 		"--ingester.parallelism=5",
 		"--ingester.deadlockInterval=2m",
+		"--ingester.verify-trace-partitions=12",
+		"--ingester.quarantine.topic=jaeger-spans-quarantine",
+		"--ingester.quarantine.max-attempts=5",
 	})
 	o.InitFromViper(v)
 
 	assert.Equal(t, "topic1", o.Topic)
+	assert.Equal(t, []string{"topic2", "topic3"}, o.Topics)
+	assert.Equal(t, map[string]string{"topic3": "proto"}, o.TopicEncodings)
 	assert.Equal(t, []string{"127.0.0.1:9092", "0.0.0:1234"}, o.Brokers)
 	assert.Equal(t, "group1", o.GroupID)
 	assert.Equal(t, "rack1", o.RackID)
@@ -55,35 +65,41 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, "1.0.0", o.ProtocolVersion)
 	assert.Equal(t, 5, o.Parallelism)
 	assert.Equal(t, 2*time.Minute, o.DeadlockInterval)
+	assert.Equal(t, int32(12), o.VerifyTracePartitions)
+	assert.Equal(t, quarantine.Options{Topic: "jaeger-spans-quarantine", MaxAttempts: 5}, o.Quarantine)
 	assert.Equal(t, kafka.EncodingJSON, o.Encoding)
+	assert.Equal(t, "/etc/jaeger/keys.json", o.EncryptionKeyFile)
+	assert.Equal(t, "http://registry:8081", o.SchemaRegistry.URL)
+	assert.Equal(t, "ingester", o.SchemaRegistry.Username)
 }
 
 func TestTLSFlags(t *testing.T) {
 	kerb := auth.KerberosConfig{ServiceName: "kafka", ConfigPath: "/etc/krb5.conf", KeyTabPath: "/etc/security/kafka.keytab"}
 	plain := auth.PlainTextConfig{Username: "", Password: "", Mechanism: "PLAIN"}
+	delegation := auth.DelegationTokenConfig{Mechanism: "SCRAM-SHA-512", RefreshBeforeExpiry: 5 * time.Minute}
 	tests := []struct {
 		flags    []string
 		expected auth.AuthenticationConfig
 	}{
 		{
 			flags:    []string{},
-			expected: auth.AuthenticationConfig{Authentication: "none", Kerberos: kerb, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "none", Kerberos: kerb, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.consumer.authentication=foo"},
-			expected: auth.AuthenticationConfig{Authentication: "foo", Kerberos: kerb, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "foo", Kerberos: kerb, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.consumer.authentication=kerberos", "--kafka.consumer.tls.enabled=true"},
-			expected: auth.AuthenticationConfig{Authentication: "kerberos", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "kerberos", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.consumer.authentication=tls"},
-			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.consumer.authentication=tls", "--kafka.consumer.tls.enabled=false"},
-			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 	}
 
@@ -112,7 +128,11 @@ func TestFlagDefaults(t *testing.T) {
 	assert.Equal(t, DefaultParallelism, o.Parallelism)
 	assert.Equal(t, int32(DefaultFetchMaxMessageBytes), o.FetchMaxMessageBytes)
 	assert.Equal(t, DefaultEncoding, o.Encoding)
+	assert.Empty(t, o.EncryptionKeyFile)
+	assert.Empty(t, o.SchemaRegistry.URL)
 	assert.Equal(t, DefaultDeadlockInterval, o.DeadlockInterval)
+	assert.Zero(t, o.VerifyTracePartitions)
+	assert.Equal(t, quarantine.Options{MaxAttempts: DefaultQuarantineMaxAttempts}, o.Quarantine)
 }
 
 func TestMain(m *testing.M) {
@@ -20,6 +20,7 @@ import (
 	"io"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -37,36 +38,92 @@ type Message interface {
 	Value() []byte
 }
 
+// topicMessage is a Message that tells the topic it was consumed from.
+type topicMessage interface {
+	Message
+	Topic() string
+}
+
+// headerMessage is a Message that tells its headers, see the kafka.Header* constants.
+type headerMessage interface {
+	Message
+	Header(key string) []byte
+}
+
 // SpanProcessorParams stores the necessary parameters for a SpanProcessor
 type SpanProcessorParams struct {
 	Writer       spanstore.Writer
 	Unmarshaller kafka.Unmarshaller
+	// TopicUnmarshallers are used instead of the Unmarshaller for the messages of their topics
+	TopicUnmarshallers map[string]kafka.Unmarshaller
+	// EncodingUnmarshallers are used for the messages telling their encoding in the kafka.HeaderEncoding header,
+	// whatever their topic
+	EncodingUnmarshallers map[string]kafka.Unmarshaller
 }
 
 // KafkaSpanProcessor implements SpanProcessor for Kafka messages
 type KafkaSpanProcessor struct {
-	unmarshaller kafka.Unmarshaller
-	sanitizer    sanitizer.SanitizeSpan
-	writer       spanstore.Writer
+	unmarshaller          kafka.Unmarshaller
+	topicUnmarshallers    map[string]kafka.Unmarshaller
+	encodingUnmarshallers map[string]kafka.Unmarshaller
+	sanitizer             sanitizer.SanitizeSpan
+	writer                spanstore.Writer
 	io.Closer
 }
 
 // NewSpanProcessor creates a new KafkaSpanProcessor
 func NewSpanProcessor(params SpanProcessorParams) *KafkaSpanProcessor {
 	return &KafkaSpanProcessor{
-		unmarshaller: params.Unmarshaller,
-		writer:       params.Writer,
-		sanitizer:    sanitizer.NewChainedSanitizer(sanitizer.NewStandardSanitizers()...),
+		unmarshaller:          params.Unmarshaller,
+		topicUnmarshallers:    params.TopicUnmarshallers,
+		encodingUnmarshallers: params.EncodingUnmarshallers,
+		writer:                params.Writer,
+		sanitizer:             sanitizer.NewChainedSanitizer(sanitizer.NewStandardSanitizers()...),
 	}
 }
 
-// Process unmarshals and writes a single kafka message
+// Process unmarshals and writes a single kafka message.
+// The span is written for the tenant of the kafka.HeaderTenant header of the message, if any.
 func (s KafkaSpanProcessor) Process(message Message) error {
-	span, err := s.unmarshaller.Unmarshal(message.Value())
+	unmarshaller, err := s.messageUnmarshaller(message)
+	if err != nil {
+		return err
+	}
+	span, err := unmarshaller.Unmarshal(message.Value())
 	if err != nil {
 		return fmt.Errorf("cannot unmarshall byte array into span: %w", err)
 	}
 
 	// TODO context should be propagated from upstream components
-	return s.writer.WriteSpan(context.TODO(), s.sanitizer(span))
+	ctx := context.TODO()
+	if msg, ok := message.(headerMessage); ok {
+		if tenant := msg.Header(kafka.HeaderTenant); len(tenant) > 0 {
+			ctx = tenancy.WithTenant(ctx, string(tenant))
+		}
+	}
+	return s.writer.WriteSpan(ctx, s.sanitizer(span))
+}
+
+// messageUnmarshaller returns the unmarshaller of the encoding of the kafka.HeaderEncoding header
+// of the message, falling back to the unmarshaller of its topic then to the default one.
+// The messages of an unknown encoding or schema version are refused.
+func (s KafkaSpanProcessor) messageUnmarshaller(message Message) (kafka.Unmarshaller, error) {
+	if msg, ok := message.(headerMessage); ok {
+		if version := msg.Header(kafka.HeaderSchemaVersion); version != nil && string(version) != kafka.SchemaVersion {
+			return nil, fmt.Errorf("unsupported span schema version '%s', expected '%s'", version, kafka.SchemaVersion)
+		}
+		if encoding := msg.Header(kafka.HeaderEncoding); encoding != nil {
+			unmarshaller, ok := s.encodingUnmarshallers[string(encoding)]
+			if !ok {
+				return nil, fmt.Errorf("cannot unmarshall span of unsupported encoding '%s'", encoding)
+			}
+			return unmarshaller, nil
+		}
+	}
+	if msg, ok := message.(topicMessage); ok && len(s.topicUnmarshallers) > 0 {
+		if topicUnmarshaller, ok := s.topicUnmarshallers[msg.Topic()]; ok {
+			return topicUnmarshaller, nil
+		}
+	}
+	return s.unmarshaller, nil
 }
@@ -25,6 +25,8 @@ import (
 
 	cmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	umocks "github.com/jaegertracing/jaeger/plugin/storage/kafka/mocks"
 	smocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
@@ -63,6 +65,98 @@ func TestSpanProcessor_Process(t *testing.T) {
 	mockWriter.AssertExpectations(t)
 }
 
+func TestSpanProcessor_ProcessTopicUnmarshaller(t *testing.T) {
+	defaultUnmarshaller, topicUnmarshaller := &umocks.Unmarshaller{}, &umocks.Unmarshaller{}
+	mockWriter := &smocks.Writer{}
+	processor := NewSpanProcessor(SpanProcessorParams{
+		Unmarshaller:       defaultUnmarshaller,
+		TopicUnmarshallers: map[string]kafka.Unmarshaller{"jaeger-spans-proto": topicUnmarshaller},
+		Writer:             mockWriter,
+	})
+
+	data := []byte("irrelevant, mock unmarshaller should return the span")
+	span := &model.Span{Process: model.NewProcess("frontend", nil)}
+	topicUnmarshaller.On("Unmarshal", data).Return(span, nil).Once()
+	defaultUnmarshaller.On("Unmarshal", data).Return(span, nil).Once()
+	mockWriter.On("WriteSpan", context.TODO(), span).Return(nil).Twice()
+
+	for _, topic := range []string{"jaeger-spans-proto", "jaeger-spans"} {
+		message := &cmocks.Message{}
+		message.On("Value").Return(data)
+		message.On("Topic").Return(topic)
+		require.NoError(t, processor.Process(message))
+	}
+
+	topicUnmarshaller.AssertExpectations(t)
+	defaultUnmarshaller.AssertExpectations(t)
+	mockWriter.AssertExpectations(t)
+}
+
+// testHeaderMessage is a message with headers, consumed from the jaeger-spans topic.
+type testHeaderMessage struct {
+	value   []byte
+	headers map[string]string
+}
+
+func (m testHeaderMessage) Value() []byte { return m.value }
+
+func (testHeaderMessage) Topic() string { return "jaeger-spans" }
+
+func (m testHeaderMessage) Header(key string) []byte {
+	if value, ok := m.headers[key]; ok {
+		return []byte(value)
+	}
+	return nil
+}
+
+func TestSpanProcessor_ProcessHeaders(t *testing.T) {
+	defaultUnmarshaller, jsonUnmarshaller := &umocks.Unmarshaller{}, &umocks.Unmarshaller{}
+	mockWriter := &smocks.Writer{}
+	processor := NewSpanProcessor(SpanProcessorParams{
+		Unmarshaller:          defaultUnmarshaller,
+		TopicUnmarshallers:    map[string]kafka.Unmarshaller{"jaeger-spans": defaultUnmarshaller},
+		EncodingUnmarshallers: map[string]kafka.Unmarshaller{kafka.EncodingJSON: jsonUnmarshaller},
+		Writer:                mockWriter,
+	})
+
+	data := []byte("irrelevant, mock unmarshaller should return the span")
+	span := &model.Span{Process: model.NewProcess("frontend", nil)}
+	jsonUnmarshaller.On("Unmarshal", data).Return(span, nil).Once()
+	defaultUnmarshaller.On("Unmarshal", data).Return(span, nil).Once()
+	mockWriter.On("WriteSpan", tenancy.WithTenant(context.TODO(), "acme"), span).Return(nil).Once()
+	mockWriter.On("WriteSpan", context.TODO(), span).Return(nil).Once()
+
+	require.NoError(t, processor.Process(testHeaderMessage{value: data, headers: map[string]string{
+		kafka.HeaderEncoding:      kafka.EncodingJSON,
+		kafka.HeaderSchemaVersion: kafka.SchemaVersion,
+		kafka.HeaderTenant:        "acme",
+	}}))
+	require.NoError(t, processor.Process(testHeaderMessage{value: data}))
+
+	jsonUnmarshaller.AssertExpectations(t)
+	defaultUnmarshaller.AssertExpectations(t)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestSpanProcessor_ProcessHeadersError(t *testing.T) {
+	mockWriter := &smocks.Writer{}
+	processor := NewSpanProcessor(SpanProcessorParams{
+		Unmarshaller:          &umocks.Unmarshaller{},
+		EncodingUnmarshallers: map[string]kafka.Unmarshaller{kafka.EncodingJSON: &umocks.Unmarshaller{}},
+		Writer:                mockWriter,
+	})
+
+	err := processor.Process(testHeaderMessage{headers: map[string]string{kafka.HeaderEncoding: kafka.EncodingAvro}})
+	require.EqualError(t, err, "cannot unmarshall span of unsupported encoding 'avro'")
+
+	err = processor.Process(testHeaderMessage{headers: map[string]string{
+		kafka.HeaderEncoding:      kafka.EncodingJSON,
+		kafka.HeaderSchemaVersion: "2",
+	}})
+	require.EqualError(t, err, "unsupported span schema version '2', expected '1'")
+	mockWriter.AssertNotCalled(t, "WriteSpan")
+}
+
 func TestSpanProcessor_ProcessError(t *testing.T) {
 	writer := &smocks.Writer{}
 	unmarshallerMock := &umocks.Unmarshaller{}
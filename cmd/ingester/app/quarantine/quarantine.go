@@ -0,0 +1,161 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// recentEntries is the number of quarantined messages listed by the admin endpoint.
+const recentEntries = 100
+
+// Options configures where the messages that cannot be processed are quarantined.
+type Options struct {
+	// Topic is the Kafka topic the messages are published to.
+	Topic string `mapstructure:"topic"`
+	// Directory is the directory the messages are written to, if Topic is not set.
+	Directory string `mapstructure:"directory"`
+	// MaxAttempts is the number of times a message is processed before it is quarantined.
+	MaxAttempts uint `mapstructure:"max_attempts"`
+}
+
+// Enabled returns true if the messages that cannot be processed are quarantined.
+func (o Options) Enabled() bool {
+	return o.Topic != "" || o.Directory != ""
+}
+
+// Validate returns an error if the options are inconsistent.
+func (o Options) Validate() error {
+	if o.Topic != "" && o.Directory != "" {
+		return errors.New("the quarantine topic and directory cannot be both set")
+	}
+	return nil
+}
+
+// Message contains the parts of a Kafka message that are quarantined.
+type Message interface {
+	Key() []byte
+	Value() []byte
+	Topic() string
+	Partition() int32
+	Offset() int64
+}
+
+// Entry describes a quarantined message.
+type Entry struct {
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+}
+
+// Sink stores the quarantined messages.
+type Sink interface {
+	Publish(msg Message, entry Entry) error
+	io.Closer
+}
+
+// Quarantine moves the messages that cannot be processed out of the way, so that the ingester
+// continues with the next messages, and remembers the most recent ones.
+type Quarantine struct {
+	sink        Sink
+	logger      *zap.Logger
+	quarantined metrics.Counter
+	failures    metrics.Counter
+
+	mu     sync.Mutex
+	recent []Entry
+	next   int
+}
+
+// New creates a Quarantine storing the messages in the sink.
+func New(sink Sink, metricsFactory metrics.Factory, logger *zap.Logger) *Quarantine {
+	m := metricsFactory.Namespace(metrics.NSOptions{Name: "quarantine", Tags: nil})
+	return &Quarantine{
+		sink:        sink,
+		logger:      logger,
+		quarantined: m.Counter(metrics.Options{Name: "messages", Tags: nil}),
+		failures:    m.Counter(metrics.Options{Name: "failures", Tags: nil}),
+	}
+}
+
+// Decorate returns a processor quarantining the messages the given processor fails to process.
+// The processor is expected to have retried them already.
+func (q *Quarantine) Decorate(p processor.SpanProcessor) processor.SpanProcessor {
+	return &quarantiningProcessor{processor: p, quarantine: q}
+}
+
+// Recent returns the most recently quarantined messages, the latest first.
+func (q *Quarantine) Recent() []Entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entries := make([]Entry, 0, len(q.recent))
+	for i := 1; i <= len(q.recent); i++ {
+		entries = append(entries, q.recent[(q.next-i+len(q.recent))%len(q.recent)])
+	}
+	return entries
+}
+
+// Close closes the sink.
+func (q *Quarantine) Close() error {
+	return q.sink.Close()
+}
+
+func (q *Quarantine) add(msg Message, cause error) error {
+	entry := Entry{
+		Topic:     msg.Topic(),
+		Partition: msg.Partition(),
+		Offset:    msg.Offset(),
+		Error:     cause.Error(),
+		Time:      time.Now(),
+	}
+	if err := q.sink.Publish(msg, entry); err != nil {
+		q.failures.Inc(1)
+		return fmt.Errorf("failed to quarantine a message that cannot be processed: %w, caused by: %w", err, cause)
+	}
+	q.quarantined.Inc(1)
+	q.logger.Warn("Quarantined a Kafka message that cannot be processed",
+		zap.Error(cause),
+		zap.String("topic", entry.Topic),
+		zap.Int32("partition", entry.Partition),
+		zap.Int64("offset", entry.Offset))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.recent) < recentEntries {
+		q.recent = append(q.recent, entry)
+	} else {
+		q.recent[q.next] = entry
+	}
+	q.next = (q.next + 1) % recentEntries
+	return nil
+}
+
+type quarantiningProcessor struct {
+	processor  processor.SpanProcessor
+	quarantine *Quarantine
+	io.Closer
+}
+
+func (p *quarantiningProcessor) Process(message processor.Message) error {
+	err := p.processor.Process(message)
+	if err == nil {
+		return nil
+	}
+	msg, ok := message.(Message)
+	if !ok {
+		return err
+	}
+	return p.quarantine.add(msg, err)
+}
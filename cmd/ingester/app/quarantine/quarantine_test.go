@@ -0,0 +1,131 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package quarantine
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+)
+
+type fakeMessage struct {
+	offset int64
+}
+
+func (*fakeMessage) Key() []byte      { return []byte("trace-id") }
+func (*fakeMessage) Value() []byte    { return []byte("span") }
+func (*fakeMessage) Topic() string    { return "jaeger-spans" }
+func (*fakeMessage) Partition() int32 { return 3 }
+func (m *fakeMessage) Offset() int64  { return m.offset }
+
+type valueMessage struct{}
+
+func (valueMessage) Value() []byte { return nil }
+
+type fakeSink struct {
+	published []Entry
+	err       error
+	closed    bool
+}
+
+func (s *fakeSink) Publish(_ Message, entry Entry) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.published = append(s.published, entry)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestOptions(t *testing.T) {
+	assert.False(t, Options{MaxAttempts: 3}.Enabled())
+	assert.True(t, Options{Topic: "jaeger-spans-quarantine"}.Enabled())
+	assert.True(t, Options{Directory: "/var/lib/jaeger/quarantine"}.Enabled())
+	require.NoError(t, Options{Topic: "jaeger-spans-quarantine"}.Validate())
+	require.ErrorContains(t, Options{Topic: "jaeger-spans-quarantine", Directory: "/tmp"}.Validate(), "cannot be both set")
+}
+
+func TestQuarantineProcessor(t *testing.T) {
+	sink := &fakeSink{}
+	lf := metricstest.NewFactory(0)
+	q := New(sink, lf, zap.NewNop())
+
+	mockProcessor := &mocks.SpanProcessor{}
+	ok, poison := &fakeMessage{offset: 1}, &fakeMessage{offset: 2}
+	mockProcessor.On("Process", ok).Return(nil)
+	mockProcessor.On("Process", poison).Return(errors.New("cannot unmarshall byte array into span"))
+	mockProcessor.On("Process", mock.AnythingOfType("quarantine.valueMessage")).Return(errors.New("storage unavailable"))
+	p := q.Decorate(mockProcessor)
+
+	require.NoError(t, p.Process(ok))
+	require.NoError(t, p.Process(poison))
+	require.EqualError(t, p.Process(valueMessage{}), "storage unavailable", "non-kafka messages cannot be quarantined")
+
+	require.Len(t, sink.published, 1)
+	entry := sink.published[0]
+	assert.Equal(t, "jaeger-spans", entry.Topic)
+	assert.Equal(t, int32(3), entry.Partition)
+	assert.Equal(t, int64(2), entry.Offset)
+	assert.Equal(t, "cannot unmarshall byte array into span", entry.Error)
+	assert.False(t, entry.Time.IsZero())
+	assert.Equal(t, []Entry{entry}, q.Recent())
+
+	sink.err = errors.New("broker unavailable")
+	err := p.Process(poison)
+	require.ErrorContains(t, err, "failed to quarantine a message that cannot be processed: broker unavailable")
+	require.ErrorContains(t, err, "caused by: cannot unmarshall byte array into span")
+
+	c, _ := lf.Snapshot()
+	assert.Equal(t, int64(1), c["quarantine.messages"])
+	assert.Equal(t, int64(1), c["quarantine.failures"])
+
+	require.NoError(t, q.Close())
+	assert.True(t, sink.closed)
+}
+
+func TestQuarantineRecent(t *testing.T) {
+	q := New(&fakeSink{}, metricstest.NewFactory(0), zap.NewNop())
+	assert.Empty(t, q.Recent())
+
+	for offset := int64(0); offset < recentEntries+10; offset++ {
+		require.NoError(t, q.add(&fakeMessage{offset: offset}, errors.New("poison")))
+	}
+	recent := q.Recent()
+	require.Len(t, recent, recentEntries)
+	assert.Equal(t, int64(recentEntries+9), recent[0].Offset)
+	assert.Equal(t, int64(10), recent[recentEntries-1].Offset)
+}
+
+func TestHandler(t *testing.T) {
+	q := New(&fakeSink{}, metricstest.NewFactory(0), zap.NewNop())
+	handler := NewHandler(q)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quarantine", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	require.NoError(t, q.add(&fakeMessage{offset: 7}, errors.New("poison")))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quarantine", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"topic":"jaeger-spans","partition":3,"offset":7,"error":"poison"`)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/quarantine", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+)
+
+// Headers of the messages published to the quarantine topic, describing the original messages.
+const (
+	HeaderTopic     = "jaeger-quarantine-topic"
+	HeaderPartition = "jaeger-quarantine-partition"
+	HeaderOffset    = "jaeger-quarantine-offset"
+	HeaderError     = "jaeger-quarantine-error"
+)
+
+type kafkaSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafkaSink creates a Sink publishing the messages to the topic, with their original key and
+// value, and headers describing where they come from and why they were quarantined.
+func NewKafkaSink(producer sarama.SyncProducer, topic string) Sink {
+	return &kafkaSink{producer: producer, topic: topic}
+}
+
+func (s *kafkaSink) Publish(msg Message, entry Entry) error {
+	message := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(msg.Value()),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(HeaderTopic), Value: []byte(entry.Topic)},
+			{Key: []byte(HeaderPartition), Value: []byte(strconv.Itoa(int(entry.Partition)))},
+			{Key: []byte(HeaderOffset), Value: []byte(strconv.FormatInt(entry.Offset, 10))},
+			{Key: []byte(HeaderError), Value: []byte(entry.Error)},
+		},
+	}
+	if key := msg.Key(); key != nil {
+		message.Key = sarama.ByteEncoder(key)
+	}
+	_, _, err := s.producer.SendMessage(message)
+	return err
+}
+
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
+
+// fileEntry is the content of a file written by the directory sink.
+type fileEntry struct {
+	Entry
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type directorySink struct {
+	directory string
+}
+
+// NewDirectorySink creates a Sink writing each message to a JSON file named after its topic,
+// partition and offset in the directory, which is created if needed.
+func NewDirectorySink(directory string) (Sink, error) {
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the quarantine directory: %w", err)
+	}
+	return &directorySink{directory: directory}, nil
+}
+
+func (s *directorySink) Publish(msg Message, entry Entry) error {
+	data, err := json.Marshal(fileEntry{Entry: entry, Key: msg.Key(), Value: msg.Value()})
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d-%d.json", entry.Topic, entry.Partition, entry.Offset)
+	return os.WriteFile(filepath.Join(s.directory, name), data, 0o600)
+}
+
+func (*directorySink) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package quarantine
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEntry = Entry{
+	Topic:     "jaeger-spans",
+	Partition: 3,
+	Offset:    42,
+	Error:     "cannot unmarshall byte array into span",
+	Time:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestKafkaSink(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	var published *sarama.ProducerMessage
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		published = msg
+		return nil
+	})
+	producer.ExpectSendMessageAndFail(errors.New("broker unavailable"))
+	sink := NewKafkaSink(producer, "jaeger-spans-quarantine")
+
+	require.NoError(t, sink.Publish(&fakeMessage{offset: 42}, testEntry))
+	assert.Equal(t, "jaeger-spans-quarantine", published.Topic)
+	assert.Equal(t, sarama.ByteEncoder("trace-id"), published.Key)
+	assert.Equal(t, sarama.ByteEncoder("span"), published.Value)
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte(HeaderTopic), Value: []byte("jaeger-spans")},
+		{Key: []byte(HeaderPartition), Value: []byte("3")},
+		{Key: []byte(HeaderOffset), Value: []byte("42")},
+		{Key: []byte(HeaderError), Value: []byte("cannot unmarshall byte array into span")},
+	}, published.Headers)
+
+	require.EqualError(t, sink.Publish(&fakeMessage{offset: 43}, testEntry), "broker unavailable")
+	require.NoError(t, sink.Close())
+}
+
+func TestDirectorySink(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "quarantine")
+	sink, err := NewDirectorySink(dir)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Publish(&fakeMessage{offset: 42}, testEntry))
+	data, err := os.ReadFile(filepath.Join(dir, "jaeger-spans-3-42.json"))
+	require.NoError(t, err)
+	var written fileEntry
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, fileEntry{Entry: testEntry, Key: []byte("trace-id"), Value: []byte("span")}, written)
+
+	_, err = NewDirectorySink(filepath.Join(dir, "jaeger-spans-3-42.json", "quarantine"))
+	require.ErrorContains(t, err, "failed to create the quarantine directory")
+}
@@ -27,7 +27,9 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/builder"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/quarantine"
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/doctor"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
@@ -76,6 +78,9 @@ func main() {
 			if err != nil {
 				logger.Fatal("Unable to create consumer", zap.Error(err))
 			}
+			if q := consumer.Quarantine(); q != nil {
+				svc.Admin.Handle("/quarantine", quarantine.NewHandler(q))
+			}
 			consumer.Start()
 
 			svc.RunAndThen(func() {
@@ -104,6 +109,7 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.IngesterAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(doctor.Command(v))
 
 	config.AddFlags(
 		v,
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// status is the outcome of a check.
+type status string
+
+const (
+	statusOK   status = "OK"
+	statusWarn status = "WARN"
+	statusFail status = "FAIL"
+)
+
+// result is the outcome of one check, with the round-trip latency of the connectivity checks.
+type result struct {
+	name    string
+	status  status
+	message string
+	latency time.Duration
+}
+
+// check returns the results of a diagnosis, or none if it does not apply to the configuration.
+type check func(ctx context.Context, v *viper.Viper) []result
+
+// checks are the diagnoses run by the doctor command, in the order of the report.
+var checks = []check{
+	validateFlags,
+	checkElasticsearch("es"),
+	checkElasticsearch("es-archive"),
+	checkCassandra("cassandra"),
+	checkCassandra("cassandra-archive"),
+	checkRemoteStorage,
+	checkKafka("kafka.producer"),
+	checkKafka("kafka.consumer"),
+	checkSamplingStrategies,
+}
+
+// runChecks runs all checks concurrently, and returns their results in the order of the checks.
+func runChecks(ctx context.Context, v *viper.Viper) []result {
+	checkResults := make([][]result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			checkResults[i] = c(ctx, v)
+		}(i, c)
+	}
+	wg.Wait()
+	var results []result
+	for _, r := range checkResults {
+		results = append(results, r...)
+	}
+	return results
+}
+
+// isConfigured returns true if the component has the flag, i.e. if the storage or the feature
+// of the flag is configured for the component.
+func isConfigured(v *viper.Viper, key string) bool {
+	return slices.Contains(v.AllKeys(), key)
+}
+
+// isEnabled returns true for the archive storage when it is enabled, and for all other storage.
+func isEnabled(v *viper.Viper, prefix string) bool {
+	if !strings.HasSuffix(prefix, "-archive") {
+		return true
+	}
+	return v.GetBool(prefix + ".enabled")
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateFlags reports the combinations of flags that are rejected when the component starts
+// or that are likely misconfigurations.
+func validateFlags(_ context.Context, v *viper.Viper) []result {
+	var results []result
+	for _, prefix := range []string{"es", "es-archive"} {
+		if !isConfigured(v, prefix+".server-urls") || !isEnabled(v, prefix) {
+			continue
+		}
+		name := prefix + " flags"
+		if v.GetBool(prefix+".use-ilm") && !v.GetBool(prefix+".use-aliases") {
+			results = append(results, result{
+				name: name, status: statusFail,
+				message: fmt.Sprintf("--%s.use-ilm must always be used in conjunction with --%s.use-aliases", prefix, prefix),
+			})
+		}
+		if v.GetBool(prefix + ".tls.enabled") {
+			for _, url := range splitList(v.GetString(prefix + ".server-urls")) {
+				if strings.HasPrefix(url, "http://") {
+					results = append(results, result{
+						name: name, status: statusWarn,
+						message: fmt.Sprintf("--%s.tls.enabled is set but the server URL %s is not https", prefix, url),
+					})
+				}
+			}
+		}
+	}
+	if isConfigured(v, "kafka.producer.brokers") && v.GetString("kafka.producer.authentication") == "tls" &&
+		!v.GetBool("kafka.producer.tls.enabled") {
+		results = append(results, result{
+			name: "kafka.producer flags", status: statusWarn,
+			message: "--kafka.producer.authentication=tls is set but --kafka.producer.tls.enabled is not",
+		})
+	}
+	return results
+}
+
+// checkElasticsearch checks that every server of an Elasticsearch or OpenSearch storage responds.
+func checkElasticsearch(prefix string) check {
+	return func(ctx context.Context, v *viper.Viper) []result {
+		if !isConfigured(v, prefix+".server-urls") || !isEnabled(v, prefix) {
+			return nil
+		}
+		var results []result
+		for _, url := range splitList(v.GetString(prefix + ".server-urls")) {
+			results = append(results, checkHTTP(ctx, prefix+" server", url))
+		}
+		return results
+	}
+}
+
+// checkCassandra checks that every server of a Cassandra storage accepts connections.
+func checkCassandra(prefix string) check {
+	return func(ctx context.Context, v *viper.Viper) []result {
+		if !isConfigured(v, prefix+".servers") || !isEnabled(v, prefix) {
+			return nil
+		}
+		port := strconv.Itoa(v.GetInt(prefix + ".port"))
+		var results []result
+		for _, server := range splitList(v.GetString(prefix + ".servers")) {
+			results = append(results, checkTCP(ctx, prefix+" server", net.JoinHostPort(server, port)))
+		}
+		return results
+	}
+}
+
+// checkRemoteStorage checks that the remote gRPC storage accepts connections.
+func checkRemoteStorage(ctx context.Context, v *viper.Viper) []result {
+	if !isConfigured(v, "grpc-storage.server") {
+		return nil
+	}
+	server := v.GetString("grpc-storage.server")
+	if server == "" {
+		return nil
+	}
+	return []result{checkTCP(ctx, "grpc-storage server", server)}
+}
+
+// checkKafka checks that every broker of a Kafka producer or consumer accepts connections.
+func checkKafka(prefix string) check {
+	return func(ctx context.Context, v *viper.Viper) []result {
+		if !isConfigured(v, prefix+".brokers") {
+			return nil
+		}
+		var results []result
+		for _, broker := range splitList(v.GetString(prefix + ".brokers")) {
+			results = append(results, checkTCP(ctx, prefix+" broker", broker))
+		}
+		return results
+	}
+}
+
+// checkSamplingStrategies checks that the file of sampling strategies exists, or that its URL responds.
+func checkSamplingStrategies(ctx context.Context, v *viper.Viper) []result {
+	if !isConfigured(v, "sampling.strategies-file") {
+		return nil
+	}
+	path := v.GetString("sampling.strategies-file")
+	switch {
+	case path == "":
+		return nil
+	case strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://"):
+		return []result{checkHTTP(ctx, "sampling strategies", path)}
+	default:
+		name := "sampling strategies"
+		if _, err := os.Stat(path); err != nil {
+			return []result{{name: name, status: statusFail, message: err.Error()}}
+		}
+		return []result{{name: name, status: statusOK, message: path}}
+	}
+}
+
+// checkHTTP sends a GET request to the URL, any response shows that it is reachable.
+func checkHTTP(ctx context.Context, name string, url string) result {
+	r := result{name: name}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		r.status, r.message = statusFail, err.Error()
+		return r
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		r.status, r.message = statusFail, err.Error()
+		return r
+	}
+	r.latency = time.Since(start)
+	resp.Body.Close()
+	r.status, r.message = statusOK, fmt.Sprintf("%s responded with %s", url, resp.Status)
+	if resp.StatusCode >= http.StatusBadRequest {
+		// the server is reachable but the requests of the component may fail, e.g. without credentials
+		r.status = statusWarn
+	}
+	return r
+}
+
+// checkTCP opens a TCP connection to the address.
+func checkTCP(ctx context.Context, name string, address string) result {
+	r := result{name: name}
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		r.status, r.message = statusFail, err.Error()
+		return r
+	}
+	r.latency = time.Since(start)
+	conn.Close()
+	r.status, r.message = statusOK, address+" is reachable"
+	return r
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"flag"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func viperWithFlags(t *testing.T, values map[string]string) *viper.Viper {
+	v, command := config.Viperize(func(flagSet *flag.FlagSet) {
+		for key := range values {
+			flagSet.String(key, "", "")
+		}
+	})
+	var args []string
+	for key, value := range values {
+		args = append(args, "--"+key+"="+value)
+	}
+	require.NoError(t, command.ParseFlags(args))
+	return v
+}
+
+func listen(t *testing.T) (string, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	host, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+	return host, port
+}
+
+func statuses(results []result) []status {
+	var s []status
+	for _, r := range results {
+		s = append(s, r.status)
+	}
+	return s
+}
+
+func TestRunChecksNothingConfigured(t *testing.T) {
+	assert.Empty(t, runChecks(context.Background(), viper.New()))
+}
+
+func TestValidateFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]string
+		expected []string
+	}{
+		{
+			name:   "valid",
+			values: map[string]string{"es.server-urls": "https://es:9200", "es.tls.enabled": "true", "es.use-ilm": "true", "es.use-aliases": "true"},
+		},
+		{
+			name:     "ilm without aliases",
+			values:   map[string]string{"es.server-urls": "http://es:9200", "es.use-ilm": "true"},
+			expected: []string{"--es.use-ilm must always be used in conjunction with --es.use-aliases"},
+		},
+		{
+			name:     "tls with http",
+			values:   map[string]string{"es.server-urls": "http://es:9200", "es.tls.enabled": "true"},
+			expected: []string{"--es.tls.enabled is set but the server URL http://es:9200 is not https"},
+		},
+		{
+			name:   "disabled archive",
+			values: map[string]string{"es-archive.server-urls": "http://es:9200", "es-archive.use-ilm": "true"},
+		},
+		{
+			name:     "kafka tls authentication",
+			values:   map[string]string{"kafka.producer.brokers": "kafka:9092", "kafka.producer.authentication": "tls"},
+			expected: []string{"--kafka.producer.authentication=tls is set but --kafka.producer.tls.enabled is not"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var messages []string
+			for _, r := range validateFlags(context.Background(), viperWithFlags(t, test.values)) {
+				messages = append(messages, r.message)
+			}
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+}
+
+func TestCheckElasticsearch(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+	v := viperWithFlags(t, map[string]string{
+		"es.server-urls":         ok.URL + ", " + unauthorized.URL + ",http://127.0.0.1:0",
+		"es-archive.server-urls": ok.URL,
+	})
+
+	results := checkElasticsearch("es")(context.Background(), v)
+	assert.Equal(t, []status{statusOK, statusWarn, statusFail}, statuses(results))
+	assert.Equal(t, ok.URL+" responded with 200 OK", results[0].message)
+	assert.Positive(t, results[0].latency)
+	assert.Empty(t, checkElasticsearch("es-archive")(context.Background(), v), "the archive storage is disabled")
+	assert.Empty(t, checkElasticsearch("opensearch")(context.Background(), v))
+}
+
+func TestCheckCassandra(t *testing.T) {
+	host, port := listen(t)
+	v := viperWithFlags(t, map[string]string{
+		"cassandra.servers":         host,
+		"cassandra.port":            port,
+		"cassandra-archive.servers": "127.0.0.1",
+		"cassandra-archive.port":    "0",
+		"cassandra-archive.enabled": "true",
+	})
+	results := checkCassandra("cassandra")(context.Background(), v)
+	assert.Equal(t, []status{statusOK}, statuses(results))
+	assert.Equal(t, net.JoinHostPort(host, port)+" is reachable", results[0].message)
+	assert.Equal(t, []status{statusFail}, statuses(checkCassandra("cassandra-archive")(context.Background(), v)))
+}
+
+func TestCheckKafka(t *testing.T) {
+	host, port := listen(t)
+	v := viperWithFlags(t, map[string]string{"kafka.consumer.brokers": net.JoinHostPort(host, port) + ",127.0.0.1:0"})
+	assert.Equal(t, []status{statusOK, statusFail}, statuses(checkKafka("kafka.consumer")(context.Background(), v)))
+	assert.Empty(t, checkKafka("kafka.producer")(context.Background(), v))
+}
+
+func TestCheckRemoteStorage(t *testing.T) {
+	host, port := listen(t)
+	assert.Empty(t, checkRemoteStorage(context.Background(), viperWithFlags(t, map[string]string{"grpc-storage.server": ""})))
+	v := viperWithFlags(t, map[string]string{"grpc-storage.server": net.JoinHostPort(host, port)})
+	assert.Equal(t, []status{statusOK}, statuses(checkRemoteStorage(context.Background(), v)))
+}
+
+func TestCheckSamplingStrategies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	tests := []struct {
+		path     string
+		expected []status
+	}{
+		{path: ""},
+		{path: "checks_test.go", expected: []status{statusOK}},
+		{path: filepath.Join(t.TempDir(), "missing.json"), expected: []status{statusFail}},
+		{path: ts.URL, expected: []status{statusWarn}},
+		{path: "http://127.0.0.1:0", expected: []status{statusFail}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			v := viperWithFlags(t, map[string]string{"sampling.strategies-file": test.path})
+			assert.Equal(t, test.expected, statuses(checkSamplingStrategies(context.Background(), v)))
+		})
+	}
+}
+
+func TestCheckHTTPInvalidURL(t *testing.T) {
+	r := checkHTTP(context.Background(), "es server", "http://invalid url")
+	assert.Equal(t, statusFail, r.status)
+}
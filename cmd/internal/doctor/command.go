@@ -0,0 +1,87 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	doctorTimeout = "doctor.timeout"
+	doctorNoColor = "doctor.no-color"
+)
+
+// Command diagnoses the environment of a Jaeger component: it checks the connectivity to the
+// configured storage, Kafka and sampling stores, and validates the combinations of flags.
+func Command(v *viper.Viper) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the environment.",
+		Long: `Check the connectivity to the configured storage, Kafka and sampling stores, measuring their round-trip latencies,
+and validate the combinations of flags, exit non-zero on any failure.
+The configuration is read from the environment variables, as for the other commands.`,
+		RunE: func(cmd *cobra.Command, _ /* args */ []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), v.GetDuration(doctorTimeout))
+			defer cancel()
+			results := runChecks(ctx, v)
+			return printReport(cmd.OutOrStdout(), results, !v.GetBool(doctorNoColor))
+		},
+	}
+	c.Flags().AddGoFlagSet(flags(&flag.FlagSet{}))
+	v.BindPFlags(c.Flags())
+	return c
+}
+
+func flags(flagSet *flag.FlagSet) *flag.FlagSet {
+	flagSet.Duration(doctorTimeout, 5*time.Second, "The timeout of all checks")
+	flagSet.Bool(doctorNoColor, false, "Print the report without colors")
+	return flagSet
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+var statusColors = map[status]string{
+	statusOK:   colorGreen,
+	statusWarn: colorYellow,
+	statusFail: colorRed,
+}
+
+// printReport writes one line per check result, and returns an error if any check failed.
+func printReport(w io.Writer, results []result, color bool) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "Nothing to check: no storage, Kafka or sampling store is configured")
+		return nil
+	}
+	failed := 0
+	for _, r := range results {
+		label := fmt.Sprintf("[%-4s]", r.status)
+		if color {
+			label = statusColors[r.status] + label + colorReset
+		}
+		line := fmt.Sprintf("%s %s: %s", label, r.name, r.message)
+		if r.latency > 0 {
+			line += fmt.Sprintf(" (%v)", r.latency.Round(time.Microsecond))
+		}
+		fmt.Fprintln(w, line)
+		if r.status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+)
+
+func TestCommand(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	v, _ := config.Viperize(func(flagSet *flag.FlagSet) {
+		flagSet.String("es.server-urls", ts.URL, "")
+	})
+	cmd := Command(v)
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--doctor.no-color"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "[OK  ] es server: "+ts.URL+" responded with 200 OK (")
+}
+
+func TestCommandFailure(t *testing.T) {
+	v, _ := config.Viperize(func(flagSet *flag.FlagSet) {
+		flagSet.String("es.server-urls", "http://127.0.0.1:9200", "")
+		flagSet.Bool("es.use-ilm", true, "")
+		flagSet.Bool("es.use-aliases", false, "")
+	})
+	cmd := Command(v)
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--doctor.timeout=10ms"})
+	require.EqualError(t, cmd.Execute(), "2 of 2 checks failed")
+	assert.Contains(t, out.String(), colorRed+"[FAIL]"+colorReset+" es flags: --es.use-ilm must always be used")
+}
+
+func TestPrintReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []result
+		color    bool
+		expected string
+		err      error
+	}{
+		{
+			name:     "nothing configured",
+			expected: "Nothing to check: no storage, Kafka or sampling store is configured\n",
+		},
+		{
+			name: "without colors",
+			results: []result{
+				{name: "kafka.producer broker", status: statusOK, message: "kafka:9092 is reachable", latency: 1500 * time.Nanosecond},
+				{name: "es flags", status: statusWarn, message: "not https"},
+			},
+			expected: "[OK  ] kafka.producer broker: kafka:9092 is reachable (2µs)\n[WARN] es flags: not https\n",
+		},
+		{
+			name:     "with colors",
+			results:  []result{{name: "sampling strategies", status: statusFail, message: "no such file"}},
+			color:    true,
+			expected: "\033[31m[FAIL]\033[0m sampling strategies: no such file\n",
+			err:      errors.New("1 of 1 checks failed"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			err := printReport(out, test.results, test.color)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expected, out.String())
+		})
+	}
+}
+
+func TestMain(m *testing.M) {
+	testutils.VerifyGoLeaks(m)
+}
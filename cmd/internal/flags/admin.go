@@ -23,7 +23,6 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"net/http/pprof"
 	"time"
 
 	"github.com/spf13/viper"
@@ -37,11 +36,14 @@ import (
 )
 
 const (
-	adminHTTPHostPort = "admin.http.host-port"
+	adminHTTPHostPort                     = "admin.http.host-port"
+	adminHTTPHealthCheckWithoutClientCert = "admin.http.tls.health-check-without-client-cert"
 )
 
 var tlsAdminHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
-	Prefix: "admin.http",
+	Prefix:                   "admin.http",
+	EnableCertReloadInterval: true,
+	EnableSPIFFE:             true,
 }
 
 // AdminServer runs an HTTP server with admin endpoints, such as healthcheck at /, /metrics, etc.
@@ -53,6 +55,10 @@ type AdminServer struct {
 	server               *http.Server
 	tlsCfg               *tls.Config
 	tlsCertWatcherCloser io.Closer
+	pprof                pprofOptions
+	// healthCheckWithoutClientCert lets the clients without a certificate, e.g. the probes
+	// of the orchestrator, check the health when client certificates are verified
+	healthCheckWithoutClientCert bool
 }
 
 // NewAdminServer creates a new admin server.
@@ -80,6 +86,8 @@ func (s *AdminServer) setLogger(logger *zap.Logger) {
 func (s *AdminServer) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(adminHTTPHostPort, s.adminHostPort, fmt.Sprintf("The host:port (e.g. 127.0.0.1%s or %s) for the admin server, including health check, /metrics, etc.", s.adminHostPort, s.adminHostPort))
 	tlsAdminHTTPFlagsConfig.AddFlags(flagSet)
+	flagSet.Bool(adminHTTPHealthCheckWithoutClientCert, false, "Allow the clients without a certificate, such as the liveness and readiness probes, to request the health check at / when the admin server verifies client certificates; the other endpoints still require a verified client certificate")
+	addPprofFlags(flagSet)
 }
 
 // InitFromViper initializes the server with properties retrieved from Viper.
@@ -87,8 +95,13 @@ func (s *AdminServer) initFromViper(v *viper.Viper, logger *zap.Logger) error {
 	s.setLogger(logger)
 
 	s.adminHostPort = v.GetString(adminHTTPHostPort)
+	pprofOpts, err := initPprofFromViper(v)
+	if err != nil {
+		return fmt.Errorf("failed to parse admin server pprof options: %w", err)
+	}
+	s.pprof = pprofOpts
 	var tlsAdminHTTP tlscfg.Options
-	tlsAdminHTTP, err := tlsAdminHTTPFlagsConfig.InitFromViper(v)
+	tlsAdminHTTP, err = tlsAdminHTTPFlagsConfig.InitFromViper(v)
 	if err != nil {
 		return fmt.Errorf("failed to parse admin server TLS options: %w", err)
 	}
@@ -102,6 +115,14 @@ func (s *AdminServer) initFromViper(v *viper.Viper, logger *zap.Logger) error {
 	} else {
 		s.tlsCertWatcherCloser = io.NopCloser(nil)
 	}
+	s.healthCheckWithoutClientCert = v.GetBool(adminHTTPHealthCheckWithoutClientCert)
+	if s.healthCheckWithoutClientCert {
+		if s.tlsCfg == nil || s.tlsCfg.ClientAuth != tls.RequireAndVerifyClientCert {
+			return fmt.Errorf("%s requires the admin server to verify client certificates", adminHTTPHealthCheckWithoutClientCert)
+		}
+		// the certificates are still verified when given, and required by requireClientCert
+		s.tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
 	return nil
 }
 
@@ -131,10 +152,14 @@ func (s *AdminServer) serveWithListener(l net.Listener) {
 	s.mux.Handle("/", s.hc.Handler())
 	version.RegisterHandler(s.mux, s.logger)
 	s.registerPprofHandlers()
+	var handler http.Handler = s.mux
+	if s.healthCheckWithoutClientCert {
+		handler = requireClientCert(handler)
+	}
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(s.logger, true)
 	errorLog, _ := zap.NewStdLogAt(s.logger, zapcore.ErrorLevel)
 	s.server = &http.Server{
-		Handler:           recoveryHandler(s.mux),
+		Handler:           recoveryHandler(handler),
 		ErrorLog:          errorLog,
 		ReadHeaderTimeout: 2 * time.Second,
 	}
@@ -156,16 +181,15 @@ func (s *AdminServer) serveWithListener(l net.Listener) {
 	}()
 }
 
-func (s *AdminServer) registerPprofHandlers() {
-	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
-	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
-	s.mux.Handle("/debug/pprof/goroutine", pprof.Handler("goroutine"))
-	s.mux.Handle("/debug/pprof/heap", pprof.Handler("heap"))
-	s.mux.Handle("/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
-	s.mux.Handle("/debug/pprof/block", pprof.Handler("block"))
+// requireClientCert rejects the requests without a verified client certificate, except the health checks.
+func requireClientCert(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
 }
 
 // Close stops the HTTP server
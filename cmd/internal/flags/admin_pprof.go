@@ -0,0 +1,165 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"crypto/subtle"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	adminPprofTokenFile            = "admin.pprof.bearer-token-file"
+	adminPprofRateLimit            = "admin.pprof.rate-limit"
+	adminPprofBlockProfileRate     = "admin.pprof.block-profile-rate"
+	adminPprofMutexProfileFraction = "admin.pprof.mutex-profile-fraction"
+
+	// maxCPUProfileSeconds caps the duration of on-demand CPU profiles and execution traces.
+	maxCPUProfileSeconds = 30
+	pprofRateWindow      = time.Minute
+)
+
+// pprofOptions configures the runtime profile endpoints of the admin server.
+type pprofOptions struct {
+	// token, if not empty, is required as a bearer token to access the profiles.
+	token string
+	// rateLimit is the maximum number of profile requests per minute, 0 for unlimited.
+	rateLimit int
+	// blockProfileRate is passed to runtime.SetBlockProfileRate.
+	blockProfileRate int
+	// mutexProfileFraction is passed to runtime.SetMutexProfileFraction.
+	mutexProfileFraction int
+}
+
+func addPprofFlags(flagSet *flag.FlagSet) {
+	flagSet.String(adminPprofTokenFile, "", "Path to a file with a bearer token required to access the /debug/pprof endpoints of the admin server; no authentication when empty")
+	flagSet.Int(adminPprofRateLimit, 0, "The maximum number of /debug/pprof requests per minute; 0 means unlimited")
+	flagSet.Int(adminPprofBlockProfileRate, 0, "The rate of blocking events reported in the block profile, see runtime.SetBlockProfileRate; 0 disables the block profile")
+	flagSet.Int(adminPprofMutexProfileFraction, 0, "The fraction of mutex contention events reported in the mutex profile, see runtime.SetMutexProfileFraction; 0 disables the mutex profile")
+}
+
+func initPprofFromViper(v *viper.Viper) (pprofOptions, error) {
+	opts := pprofOptions{
+		rateLimit:            v.GetInt(adminPprofRateLimit),
+		blockProfileRate:     v.GetInt(adminPprofBlockProfileRate),
+		mutexProfileFraction: v.GetInt(adminPprofMutexProfileFraction),
+	}
+	if opts.rateLimit < 0 {
+		return opts, fmt.Errorf("%s must not be negative, got %d", adminPprofRateLimit, opts.rateLimit)
+	}
+	if tokenFile := v.GetString(adminPprofTokenFile); tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read pprof bearer token: %w", err)
+		}
+		opts.token = strings.TrimSpace(string(token))
+		if opts.token == "" {
+			return opts, fmt.Errorf("pprof bearer token file %s is empty", tokenFile)
+		}
+	}
+	return opts, nil
+}
+
+func (s *AdminServer) registerPprofHandlers() {
+	runtime.SetBlockProfileRate(s.pprof.blockProfileRate)
+	runtime.SetMutexProfileFraction(s.pprof.mutexProfileFraction)
+
+	guard := s.pprof.newGuard()
+	handle := func(path string, h http.Handler) {
+		s.mux.Handle(path, guard(h))
+	}
+	handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
+	handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+	handle("/debug/pprof/profile", capSeconds(http.HandlerFunc(pprof.Profile)))
+	handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	handle("/debug/pprof/trace", capSeconds(http.HandlerFunc(pprof.Trace)))
+	handle("/debug/pprof/goroutine", pprof.Handler("goroutine"))
+	handle("/debug/pprof/heap", pprof.Handler("heap"))
+	handle("/debug/pprof/allocs", pprof.Handler("allocs"))
+	handle("/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
+	handle("/debug/pprof/block", pprof.Handler("block"))
+	handle("/debug/pprof/mutex", pprof.Handler("mutex"))
+}
+
+// newGuard returns a function wrapping profile handlers with bearer token
+// authentication and a rate limit shared by all of them.
+func (opts pprofOptions) newGuard() func(http.Handler) http.Handler {
+	limiter := &windowLimiter{limit: opts.rateLimit, window: pprofRateWindow, timeNow: time.Now}
+	return func(h http.Handler) http.Handler {
+		if opts.token == "" && opts.rateLimit == 0 {
+			return h
+		}
+		return opts.guard(h, limiter)
+	}
+}
+
+func (opts pprofOptions) guard(h http.Handler, limiter *windowLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if opts.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(opts.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		if !limiter.allow() {
+			w.Header().Set("Retry-After", strconv.Itoa(int(pprofRateWindow.Seconds())))
+			http.Error(w, "too many profile requests", http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// capSeconds limits the "seconds" parameter of CPU profiles and execution traces.
+func capSeconds(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if sec, err := strconv.Atoi(q.Get("seconds")); err == nil && sec > maxCPUProfileSeconds {
+			q.Set("seconds", strconv.Itoa(maxCPUProfileSeconds))
+			r.URL.RawQuery = q.Encode()
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// windowLimiter allows up to limit requests per fixed time window; limit 0 allows all requests.
+type windowLimiter struct {
+	limit   int
+	window  time.Duration
+	timeNow func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+func (l *windowLimiter) allow() bool {
+	if l.limit == 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.timeNow()
+	if now.Sub(l.windowStart) >= l.window {
+		l.windowStart = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestPprofFlags(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	v, command := config.Viperize(addPprofFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--admin.pprof.bearer-token-file=" + tokenFile,
+		"--admin.pprof.rate-limit=5",
+		"--admin.pprof.block-profile-rate=100",
+		"--admin.pprof.mutex-profile-fraction=10",
+	}))
+	opts, err := initPprofFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, pprofOptions{
+		token:                "secret",
+		rateLimit:            5,
+		blockProfileRate:     100,
+		mutexProfileFraction: 10,
+	}, opts)
+}
+
+func TestPprofFlagsErrors(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0o600))
+
+	testCases := []struct {
+		name   string
+		flags  []string
+		errMsg string
+	}{
+		{name: "negative rate limit", flags: []string{"--admin.pprof.rate-limit=-1"}, errMsg: "must not be negative"},
+		{name: "missing token file", flags: []string{"--admin.pprof.bearer-token-file=" + filepath.Join(t.TempDir(), "missing")}, errMsg: "failed to read pprof bearer token"},
+		{name: "empty token file", flags: []string{"--admin.pprof.bearer-token-file=" + emptyFile}, errMsg: "is empty"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, command := config.Viperize(addPprofFlags)
+			require.NoError(t, command.ParseFlags(tc.flags))
+			_, err := initPprofFromViper(v)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestPprofGuard(t *testing.T) {
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	guard := pprofOptions{token: "secret", rateLimit: 2}.newGuard()
+	heap, goroutine := guard(ok), guard(ok)
+
+	serve := func(h http.Handler, auth string) int {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, serve(heap, ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(heap, "Bearer wrong"))
+	assert.Equal(t, http.StatusUnauthorized, serve(heap, "secret"))
+	assert.Equal(t, http.StatusOK, serve(heap, "Bearer secret"))
+	assert.Equal(t, http.StatusOK, serve(goroutine, "Bearer secret"))
+	// the limit is shared by all profile endpoints
+	assert.Equal(t, http.StatusTooManyRequests, serve(heap, "Bearer secret"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(goroutine, "Bearer secret"))
+}
+
+func TestPprofGuardDisabled(t *testing.T) {
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	guard := pprofOptions{}.newGuard()
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		guard(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestWindowLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := &windowLimiter{limit: 1, window: time.Minute, timeNow: func() time.Time { return now }}
+	assert.True(t, l.allow())
+	assert.False(t, l.allow())
+	now = now.Add(time.Minute)
+	assert.True(t, l.allow())
+}
+
+func TestCapSeconds(t *testing.T) {
+	var seconds string
+	h := capSeconds(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seconds = r.URL.Query().Get("seconds")
+	}))
+	for _, tc := range []struct{ in, out string }{
+		{in: "120", out: "30"},
+		{in: "10", out: "10"},
+		{in: "", out: ""},
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/pprof/profile?seconds="+tc.in, nil))
+		assert.Equal(t, tc.out, seconds)
+	}
+}
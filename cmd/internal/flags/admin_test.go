@@ -110,6 +110,14 @@ func TestAdminWithFailedFlags(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to parse admin server TLS options")
 }
 
+func TestAdminWithFailedPprofFlags(t *testing.T) {
+	adminServer := NewAdminServer(":0")
+	v, command := config.Viperize(adminServer.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--admin.pprof.rate-limit=-1"}))
+	err := adminServer.initFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to parse admin server pprof options")
+}
+
 func TestAdminServerTLS(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -169,3 +177,62 @@ func TestAdminServerTLS(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminServerHealthCheckWithoutClientCert(t *testing.T) {
+	adminServer := NewAdminServer(":0")
+	v, command := config.Viperize(adminServer.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--admin.http.tls.enabled=true",
+		"--admin.http.tls.cert=" + testCertKeyLocation + "/example-server-cert.pem",
+		"--admin.http.tls.key=" + testCertKeyLocation + "/example-server-key.pem",
+		"--admin.http.tls.client-ca=" + testCertKeyLocation + "/example-CA-cert.pem",
+		"--admin.http.tls.health-check-without-client-cert=true",
+	}))
+	require.NoError(t, adminServer.initFromViper(v, zaptest.NewLogger(t)))
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	adminServer.serveWithListener(l)
+	defer adminServer.Close()
+	adminServer.HC().Ready()
+
+	get := func(t *testing.T, clientTLS tlscfg.Options, path string) int {
+		clientTLSCfg, err := clientTLS.Config(zap.NewNop())
+		require.NoError(t, err)
+		defer clientTLS.Close()
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSCfg}}
+		defer client.CloseIdleConnections()
+		req, err := http.NewRequest(http.MethodGet, "https://"+l.Addr().String()+path, nil)
+		require.NoError(t, err)
+		req.Close = true
+		response, err := client.Do(req)
+		require.NoError(t, err)
+		response.Body.Close()
+		return response.StatusCode
+	}
+	withoutCert := tlscfg.Options{
+		Enabled:    true,
+		CAPath:     testCertKeyLocation + "/example-CA-cert.pem",
+		ServerName: "example.com",
+	}
+	withCert := withoutCert
+	withCert.CertPath = testCertKeyLocation + "/example-client-cert.pem"
+	withCert.KeyPath = testCertKeyLocation + "/example-client-key.pem"
+
+	assert.Equal(t, http.StatusOK, get(t, withoutCert, "/"))
+	assert.Equal(t, http.StatusUnauthorized, get(t, withoutCert, "/version"))
+	assert.Equal(t, http.StatusOK, get(t, withCert, "/version"))
+}
+
+func TestAdminServerHealthCheckWithoutClientCertRequiresClientAuth(t *testing.T) {
+	adminServer := NewAdminServer(":0")
+	v, command := config.Viperize(adminServer.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--admin.http.tls.enabled=true",
+		"--admin.http.tls.cert=" + testCertKeyLocation + "/example-server-cert.pem",
+		"--admin.http.tls.key=" + testCertKeyLocation + "/example-server-key.pem",
+		"--admin.http.tls.health-check-without-client-cert=true",
+	}))
+	err := adminServer.initFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "admin.http.tls.health-check-without-client-cert requires the admin server to verify client certificates")
+	require.NoError(t, adminServer.tlsCertWatcherCloser.Close())
+}
@@ -29,17 +29,25 @@ import (
 	"github.com/jaegertracing/jaeger/ports"
 )
 
-const statusHTTPHostPort = "status.http.host-port"
+const (
+	statusHTTPHostPort = "status.http.host-port"
+	statusEndpoints    = "status.endpoints"
+	statusTimeout      = "status.timeout"
+)
 
 // Command for check component status.
 func Command(v *viper.Viper, adminPort int) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "status",
 		Short: "Print the status.",
-		Long:  `Print Jaeger component status information, exit non-zero on any error.`,
-		RunE: func(_ *cobra.Command, _ /* args */ []string) error {
+		Long: `Print Jaeger component status information, exit non-zero on any error.
+When a list of admin endpoints is given, print a table with the health and version of every component.`,
+		RunE: func(cmd *cobra.Command, _ /* args */ []string) error {
+			if endpoints := v.GetString(statusEndpoints); endpoints != "" {
+				return fleetStatus(cmd.OutOrStdout(), strings.Split(endpoints, ","), v.GetDuration(statusTimeout))
+			}
 			url := convert(v.GetString(statusHTTPHostPort))
-			ctx, cx := context.WithTimeout(context.Background(), time.Second)
+			ctx, cx := context.WithTimeout(context.Background(), v.GetDuration(statusTimeout))
 			defer cx()
 			req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 			resp, err := http.DefaultClient.Do(req)
@@ -64,6 +72,11 @@ func flags(flagSet *flag.FlagSet, adminPort int) *flag.FlagSet {
 	adminPortStr := ports.PortToHostPort(adminPort)
 	flagSet.String(statusHTTPHostPort, adminPortStr, fmt.Sprintf(
 		"The host:port (e.g. 127.0.0.1%s or %s) for the health check", adminPortStr, adminPortStr))
+	flagSet.String(statusEndpoints, "", fmt.Sprintf(
+		"A comma-separated list of admin host:port of the components to check, e.g. of collector, query and ingester replicas. "+
+			"Endpoints prefixed with %s (e.g. %scollector%s) are resolved to all addresses of their host. "+
+			"When set, %s is ignored.", dnsPrefix, dnsPrefix, ports.PortToHostPort(ports.CollectorAdminHTTP), statusHTTPHostPort))
+	flagSet.Duration(statusTimeout, time.Second, "The timeout of the status check")
 	return flagSet
 }
 
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// dnsPrefix marks an endpoint whose host is resolved to the admin endpoints of all its replicas.
+const dnsPrefix = "dns:///"
+
+// lookupHost resolves the addresses of a host, it is replaced in tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// endpointStatus is the status reported by the admin server of one component.
+type endpointStatus struct {
+	endpoint string
+	healthy  bool
+	status   string
+	uptime   string
+	version  string
+	err      error
+}
+
+// healthResponse is the body returned by the health check of the admin server, see pkg/healthcheck.
+type healthResponse struct {
+	Status string `json:"status"`
+	Uptime string `json:"uptime"`
+}
+
+// versionResponse is the body returned by the /version endpoint of the admin server, see pkg/version.
+type versionResponse struct {
+	GitVersion string `json:"gitVersion"`
+}
+
+// resolveEndpoints expands the endpoints prefixed with dns:/// into the host:port
+// of every address their host resolves to, so that all replicas of a component are queried.
+func resolveEndpoints(ctx context.Context, endpoints []string) ([]string, error) {
+	var resolved []string
+	for _, endpoint := range endpoints {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		if !strings.HasPrefix(endpoint, dnsPrefix) {
+			resolved = append(resolved, endpoint)
+			continue
+		}
+		host, port, err := net.SplitHostPort(strings.TrimPrefix(endpoint, dnsPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %s: %w", endpoint, err)
+		}
+		addrs, err := lookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve endpoint %s: %w", endpoint, err)
+		}
+		for _, addr := range addrs {
+			resolved = append(resolved, net.JoinHostPort(addr, port))
+		}
+	}
+	return resolved, nil
+}
+
+// checkEndpoints queries the admin servers of all endpoints concurrently.
+func checkEndpoints(ctx context.Context, endpoints []string) []endpointStatus {
+	statuses := make([]endpointStatus, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			statuses[i] = checkEndpoint(ctx, endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func checkEndpoint(ctx context.Context, endpoint string) endpointStatus {
+	s := endpointStatus{endpoint: endpoint}
+	url := convert(endpoint)
+	var health healthResponse
+	code, err := getJSON(ctx, url, &health)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	s.healthy = code == http.StatusOK
+	s.status = health.Status
+	s.uptime = health.Uptime
+	// the version is informational, components without the endpoint are still reported
+	var version versionResponse
+	if code, err := getJSON(ctx, url+"/version", &version); err == nil && code == http.StatusOK {
+		s.version = version.GitVersion
+	}
+	return s
+}
+
+func getJSON(ctx context.Context, url string, target any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return 0, fmt.Errorf("invalid response from %s: %w", url, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// printStatuses writes the statuses as a table, and returns an error if any endpoint is unhealthy.
+func printStatuses(w io.Writer, statuses []endpointStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tHEALTHY\tSTATUS\tUPTIME\tVERSION")
+	unhealthy := 0
+	for _, s := range statuses {
+		status := s.status
+		if s.err != nil {
+			status = s.err.Error()
+		}
+		if !s.healthy {
+			unhealthy++
+		}
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%s\t%s\n", s.endpoint, s.healthy, status, orDash(s.uptime), orDash(s.version))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if unhealthy > 0 {
+		return fmt.Errorf("%d of %d endpoints are unhealthy", unhealthy, len(statuses))
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func fleetStatus(w io.Writer, endpoints []string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	resolved, err := resolveEndpoints(ctx, endpoints)
+	if err != nil {
+		return err
+	}
+	if len(resolved) == 0 {
+		return errors.New("no endpoints to query")
+	}
+	return printStatuses(w, checkEndpoints(ctx, resolved))
+}
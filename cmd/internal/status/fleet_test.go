@@ -0,0 +1,99 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminServer(t *testing.T, healthCode int, status string) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(healthCode)
+		w.Write([]byte(`{"status":"` + status + `","uptime":"1h0m0s"}`))
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"gitVersion":"v1.60.0"}`))
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return strings.TrimPrefix(ts.URL, "http://")
+}
+
+func TestFleetStatus(t *testing.T) {
+	collector := newAdminServer(t, http.StatusOK, "Server available")
+	query := newAdminServer(t, http.StatusOK, "Server available")
+
+	v := viper.New()
+	cmd := Command(v, 80)
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.ParseFlags([]string{"--status.endpoints=" + collector + ", " + query})
+	require.NoError(t, cmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"ENDPOINT", "HEALTHY", "STATUS", "UPTIME", "VERSION"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{collector, "true", "Server", "available", "1h0m0s", "v1.60.0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{query, "true", "Server", "available", "1h0m0s", "v1.60.0"}, strings.Fields(lines[2]))
+}
+
+func TestFleetStatusUnhealthy(t *testing.T) {
+	healthy := newAdminServer(t, http.StatusOK, "Server available")
+	unavailable := newAdminServer(t, http.StatusServiceUnavailable, "Server not available")
+	noVersion := httptest.NewServer(http.HandlerFunc(readyHandler))
+	defer noVersion.Close()
+	noVersionHostPort := strings.TrimPrefix(noVersion.URL, "http://")
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer invalid.Close()
+
+	out := &bytes.Buffer{}
+	err := fleetStatus(out, []string{healthy, unavailable, noVersionHostPort, strings.TrimPrefix(invalid.URL, "http://")}, time.Second)
+	require.EqualError(t, err, "2 of 4 endpoints are unhealthy")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, []string{unavailable, "false", "Server", "not", "available", "1h0m0s", "v1.60.0"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{noVersionHostPort, "true", "Server", "available", "-", "-"}, strings.Fields(lines[3]))
+	assert.Contains(t, lines[4], "invalid response from "+invalid.URL)
+}
+
+func TestFleetStatusDNS(t *testing.T) {
+	admin := newAdminServer(t, http.StatusOK, "Server available")
+	host, port, err := net.SplitHostPort(admin)
+	require.NoError(t, err)
+
+	defer func(f func(context.Context, string) ([]string, error)) { lookupHost = f }(lookupHost)
+	lookupHost = func(_ context.Context, name string) ([]string, error) {
+		if name == "collector" {
+			return []string{host, host}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, fleetStatus(out, []string{"dns:///collector:" + port}, time.Second))
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 3)
+
+	err = fleetStatus(out, []string{"dns:///query:" + port}, time.Second)
+	require.EqualError(t, err, "cannot resolve endpoint dns:///query:"+port+": no such host")
+	err = fleetStatus(out, []string{"dns:///query"}, time.Second)
+	require.ErrorContains(t, err, "invalid endpoint dns:///query")
+	err = fleetStatus(out, []string{" ", ""}, time.Second)
+	require.EqualError(t, err, "no endpoints to query")
+}
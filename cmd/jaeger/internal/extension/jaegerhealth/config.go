@@ -0,0 +1,33 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var _ component.ConfigValidator = (*Config)(nil)
+
+// Config represents the configuration of the health check extension.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// Storages are the names of the jaeger_storage backends whose connectivity is checked.
+	Storages []string `mapstructure:"storages"`
+	// SamplingStores are the names of the jaeger_storage backends whose sampling store is checked.
+	SamplingStores []string `mapstructure:"sampling_stores"`
+	// CheckInterval is the period of the storage checks.
+	CheckInterval time.Duration `valid:"required" mapstructure:"check_interval"`
+	// CheckTimeout bounds the duration of each storage check.
+	CheckTimeout time.Duration `valid:"required" mapstructure:"check_timeout"`
+}
+
+func (cfg *Config) Validate() error {
+	_, err := govalidator.ValidateStruct(cfg)
+	return err
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/jaeger/internal/extension/jaegerstorage"
+	"github.com/jaegertracing/jaeger/storage"
+)
+
+var (
+	_ extension.Extension     = (*healthExt)(nil)
+	_ extension.Dependent     = (*healthExt)(nil)
+	_ extension.StatusWatcher = (*healthExt)(nil)
+)
+
+const (
+	// LivePath responds 200 when all components are live, 503 otherwise.
+	LivePath = "/live"
+	// ReadyPath responds 200 when all components are ready, 503 otherwise.
+	ReadyPath = "/ready"
+	// StatusPath always responds 200 with the health of every component.
+	StatusPath = "/status"
+)
+
+// healthExt aggregates the status reported by the components of the collector,
+// e.g. a Kafka receiver that cannot join its consumer group, with periodic checks
+// of the storages declared in the jaeger_storage extension.
+type healthExt struct {
+	config   *Config
+	telset   component.TelemetrySettings
+	registry *registry
+	checks   map[string]func(context.Context) error
+	server   *http.Server
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newHealthExt(config *Config, telset component.TelemetrySettings) *healthExt {
+	return &healthExt{
+		config:   config,
+		telset:   telset,
+		registry: newRegistry(),
+		checks:   make(map[string]func(context.Context) error),
+		done:     make(chan struct{}),
+	}
+}
+
+// Dependencies implements extension.Dependent to ensure this starts after jaegerstorage extension
+// when storages are checked.
+func (h *healthExt) Dependencies() []component.ID {
+	if len(h.config.Storages) == 0 && len(h.config.SamplingStores) == 0 {
+		return nil
+	}
+	return []component.ID{jaegerstorage.ID}
+}
+
+// ComponentStatusChanged implements extension.StatusWatcher.
+func (h *healthExt) ComponentStatusChanged(source *component.InstanceID, event *component.StatusEvent) {
+	if event.Status() == component.StatusNone {
+		return
+	}
+	h.registry.set(instanceName(source), statusEventHealth(event))
+}
+
+func (h *healthExt) Start(ctx context.Context, host component.Host) error {
+	if err := h.addStorageChecks(host); err != nil {
+		return err
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc(LivePath, h.reportHandler(func(report healthReport) bool { return report.Live })).Methods(http.MethodGet)
+	r.HandleFunc(ReadyPath, h.reportHandler(func(report healthReport) bool { return report.Ready })).Methods(http.MethodGet)
+	r.HandleFunc(StatusPath, h.reportHandler(func(healthReport) bool { return true })).Methods(http.MethodGet)
+
+	listener, err := h.config.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", h.config.Endpoint, err)
+	}
+	h.server, err = h.config.ToServer(ctx, host, h.telset, r)
+	if err != nil {
+		listener.Close()
+		return fmt.Errorf("cannot create health check server: %w", err)
+	}
+	h.telset.Logger.Info("Starting health check server", zap.String("endpoint", listener.Addr().String()))
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			err = fmt.Errorf("error running health check server: %w", err)
+			h.telset.ReportStatus(component.NewFatalErrorEvent(err))
+		}
+	}()
+
+	if len(h.checks) > 0 {
+		h.wg.Add(1)
+		go h.runChecks()
+	}
+	return nil
+}
+
+func (h *healthExt) addStorageChecks(host component.Host) error {
+	for _, name := range h.config.Storages {
+		f, err := jaegerstorage.GetStorageFactory(name, host)
+		if err != nil {
+			return fmt.Errorf("cannot find storage %s: %w", name, err)
+		}
+		reader, err := f.CreateSpanReader()
+		if err != nil {
+			return fmt.Errorf("cannot create span reader of storage %s: %w", name, err)
+		}
+		h.addCheck("storage/"+name, func(ctx context.Context) error {
+			_, err := reader.GetServices(ctx)
+			return err
+		})
+	}
+	for _, name := range h.config.SamplingStores {
+		f, err := jaegerstorage.GetStorageFactory(name, host)
+		if err != nil {
+			return fmt.Errorf("cannot find storage %s: %w", name, err)
+		}
+		ssf, ok := f.(storage.SamplingStoreFactory)
+		if !ok {
+			return fmt.Errorf("storage %s does not support sampling stores", name)
+		}
+		store, err := ssf.CreateSamplingStore(1)
+		if err != nil {
+			return fmt.Errorf("cannot create sampling store of storage %s: %w", name, err)
+		}
+		h.addCheck("sampling_store/"+name, func(context.Context) error {
+			_, err := store.GetLatestProbabilities()
+			return err
+		})
+	}
+	return nil
+}
+
+func (h *healthExt) addCheck(name string, check func(context.Context) error) {
+	h.checks[name] = check
+	h.registry.set(name, componentHealth{Status: statusStarting, Live: true, Since: time.Now()})
+}
+
+func (h *healthExt) runChecks() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		for name, check := range h.checks {
+			h.registry.set(name, checkHealth(h.runCheck(check), time.Now()))
+		}
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCheck returns the error of the check, or a timeout error when it takes longer than
+// CheckTimeout, e.g. because the sampling store does not accept a context.
+func (h *healthExt) runCheck(check func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.CheckTimeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out after %v", h.config.CheckTimeout)
+	}
+}
+
+func (h *healthExt) reportHandler(healthy func(healthReport) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		report := h.registry.report()
+		w.Header().Set("Content-Type", "application/json")
+		if healthy(report) {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			h.telset.Logger.Error("Failed to write the health report", zap.Error(err))
+		}
+	}
+}
+
+func (h *healthExt) Shutdown(ctx context.Context) error {
+	close(h.done)
+	var err error
+	if h.server != nil {
+		err = h.server.Shutdown(ctx)
+	}
+	h.wg.Wait()
+	return err
+}
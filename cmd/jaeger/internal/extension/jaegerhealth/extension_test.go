@@ -0,0 +1,221 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/jaegertracing/jaeger/cmd/jaeger/internal/extension/jaegerstorage"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/storage"
+	factoryMocks "github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	samplingStoreMocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type fakeFactory struct {
+	factoryMocks.Factory
+	reader *spanStoreMocks.Reader
+	store  *samplingStoreMocks.Store
+}
+
+func (f *fakeFactory) CreateSpanReader() (spanstore.Reader, error) {
+	return f.reader, nil
+}
+
+func (f *fakeFactory) CreateSamplingStore(int) (samplingstore.Store, error) {
+	return f.store, nil
+}
+
+func (*fakeFactory) CreateLock() (distributedlock.Lock, error) {
+	return nil, nil
+}
+
+type fakeStorageExt struct {
+	factories map[string]storage.Factory
+}
+
+var _ jaegerstorage.Extension = (*fakeStorageExt)(nil)
+
+func (e fakeStorageExt) Factory(name string) (storage.Factory, bool) {
+	f, ok := e.factories[name]
+	return f, ok
+}
+
+func (fakeStorageExt) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (fakeStorageExt) Shutdown(context.Context) error {
+	return nil
+}
+
+type storageHost struct {
+	extension component.Component
+}
+
+func (storageHost) ReportFatalError(error) {
+}
+
+func (host storageHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{
+		jaegerstorage.ID: host.extension,
+	}
+}
+
+func (storageHost) GetFactory(_ component.Kind, _ component.Type) component.Factory {
+	return nil
+}
+
+func (storageHost) GetExporters() map[component.DataType]map[component.ID]component.Component {
+	return nil
+}
+
+func newTestHealthExt(config *Config) *healthExt {
+	config.Endpoint = "localhost:0"
+	return newHealthExt(config, componenttest.NewNopTelemetrySettings())
+}
+
+func getReport(t *testing.T, h *healthExt, path string) (int, healthReport) {
+	rec := httptest.NewRecorder()
+	h.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var report healthReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestHealthExtDependencies(t *testing.T) {
+	h := newTestHealthExt(createDefaultConfig().(*Config))
+	assert.Empty(t, h.Dependencies())
+
+	h = newTestHealthExt(&Config{SamplingStores: []string{"cassandra"}})
+	assert.Equal(t, []component.ID{jaegerstorage.ID}, h.Dependencies())
+}
+
+func TestHealthExtComponentStatus(t *testing.T) {
+	h := newTestHealthExt(createDefaultConfig().(*Config))
+	require.NoError(t, h.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, h.Shutdown(context.Background()))
+	}()
+
+	kafka := &component.InstanceID{ID: component.MustNewID("kafka"), Kind: component.KindReceiver}
+	h.ComponentStatusChanged(kafka, component.NewStatusEvent(component.StatusStarting))
+	h.ComponentStatusChanged(kafka, component.NewStatusEvent(component.StatusNone))
+
+	code, report := getReport(t, h, LivePath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "starting", report.Components["receiver/kafka"].Status)
+	code, _ = getReport(t, h, ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	h.ComponentStatusChanged(kafka, component.NewStatusEvent(component.StatusOK))
+	code, _ = getReport(t, h, ReadyPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	h.ComponentStatusChanged(kafka, component.NewFatalErrorEvent(errors.New("port in use")))
+	code, report = getReport(t, h, LivePath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "port in use", report.Components["receiver/kafka"].Error)
+	code, report = getReport(t, h, StatusPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, report.Live)
+}
+
+func TestHealthExtStorageChecks(t *testing.T) {
+	reader := &spanStoreMocks.Reader{}
+	reader.On("GetServices", mock.Anything).Return(nil, errors.New("no route to host")).Once()
+	reader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+	store := &samplingStoreMocks.Store{}
+	store.On("GetLatestProbabilities").Return(nil, nil)
+	host := storageHost{extension: fakeStorageExt{factories: map[string]storage.Factory{
+		"cassandra": &fakeFactory{reader: reader, store: store},
+	}}}
+
+	h := newTestHealthExt(&Config{
+		Storages:       []string{"cassandra"},
+		SamplingStores: []string{"cassandra"},
+		CheckInterval:  10 * time.Millisecond,
+		CheckTimeout:   time.Second,
+	})
+	require.NoError(t, h.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, h.Shutdown(context.Background()))
+	}()
+
+	require.Eventually(t, func() bool {
+		code, _ := getReport(t, h, ReadyPath)
+		return code == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+	_, report := getReport(t, h, StatusPath)
+	assert.Equal(t, []string{"sampling_store/cassandra", "storage/cassandra"}, sortedKeys(report.Components))
+	assert.True(t, report.Components["storage/cassandra"].Ready)
+	assert.True(t, report.Components["sampling_store/cassandra"].Ready)
+}
+
+func sortedKeys(components map[string]componentHealth) []string {
+	var keys []string
+	for key := range components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestHealthExtRunCheckTimeout(t *testing.T) {
+	h := newTestHealthExt(&Config{CheckTimeout: time.Millisecond})
+	unblock := make(chan struct{})
+	defer close(unblock)
+	err := h.runCheck(func(context.Context) error {
+		<-unblock
+		return nil
+	})
+	require.EqualError(t, err, "check timed out after 1ms")
+}
+
+func TestHealthExtStartErrors(t *testing.T) {
+	plainFactory := &factoryMocks.Factory{}
+	failingFactory := &factoryMocks.Factory{}
+	failingFactory.On("CreateSpanReader").Return(nil, errors.New("cannot connect"))
+	host := storageHost{extension: fakeStorageExt{factories: map[string]storage.Factory{
+		"plain":   plainFactory,
+		"failing": failingFactory,
+	}}}
+	testCases := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{name: "missing storage", config: &Config{Storages: []string{"missing"}}, err: "cannot find storage missing"},
+		{name: "missing sampling store", config: &Config{SamplingStores: []string{"missing"}}, err: "cannot find storage missing"},
+		{name: "span reader error", config: &Config{Storages: []string{"failing"}}, err: "cannot create span reader of storage failing: cannot connect"},
+		{name: "no sampling store", config: &Config{SamplingStores: []string{"plain"}}, err: "storage plain does not support sampling stores"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newTestHealthExt(tc.config)
+			require.ErrorContains(t, h.Start(context.Background(), host), tc.err)
+		})
+	}
+
+	h := newHealthExt(&Config{}, componenttest.NewNopTelemetrySettings())
+	h.config.Endpoint = "invalid-endpoint"
+	require.ErrorContains(t, h.Start(context.Background(), componenttest.NewNopHost()), "cannot listen on invalid-endpoint")
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/jaegertracing/jaeger/ports"
+)
+
+// componentType is the name of this extension in configuration.
+var componentType = component.MustNewType("jaeger_health")
+
+// ID is the identifier of this extension.
+var ID = component.NewID(componentType)
+
+// Port is the default port of the health check endpoints.
+const Port = 13133
+
+func NewFactory() extension.Factory {
+	return extension.NewFactory(componentType, createDefaultConfig, createExtension, component.StabilityLevelAlpha)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: ports.PortToHostPort(Port),
+		},
+		CheckInterval: 10 * time.Second,
+		CheckTimeout:  5 * time.Second,
+	}
+}
+
+// createExtension creates the extension based on this config.
+func createExtension(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
+	return newHealthExt(cfg.(*Config), set.TelemetrySettings), nil
+}
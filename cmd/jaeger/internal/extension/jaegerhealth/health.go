@@ -0,0 +1,119 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	statusStarting    = "starting"
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+var statusNames = map[component.Status]string{
+	component.StatusStarting:         statusStarting,
+	component.StatusOK:               statusOK,
+	component.StatusRecoverableError: "recoverable_error",
+	component.StatusPermanentError:   "permanent_error",
+	component.StatusFatalError:       "fatal_error",
+	component.StatusStopping:         "stopping",
+	component.StatusStopped:          "stopped",
+}
+
+// componentHealth is the health of a component or of a storage check.
+// A component is live unless it failed in a way that only a restart can repair,
+// and it is ready when it is running without error.
+type componentHealth struct {
+	Status string    `json:"status"`
+	Live   bool      `json:"live"`
+	Ready  bool      `json:"ready"`
+	Error  string    `json:"error,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// healthReport is the JSON document served by the endpoints of the extension.
+type healthReport struct {
+	Live       bool                       `json:"live"`
+	Ready      bool                       `json:"ready"`
+	Components map[string]componentHealth `json:"components"`
+}
+
+func statusEventHealth(event *component.StatusEvent) componentHealth {
+	h := componentHealth{
+		Status: statusNames[event.Status()],
+		Live:   event.Status() != component.StatusPermanentError && event.Status() != component.StatusFatalError,
+		Ready:  event.Status() == component.StatusOK,
+		Since:  event.Timestamp(),
+	}
+	if err := event.Err(); err != nil {
+		h.Error = err.Error()
+	}
+	return h
+}
+
+// checkHealth returns the health of a storage after a check. A storage that cannot be
+// reached is not ready, but restarting Jaeger does not help, so it is still live.
+func checkHealth(err error, now time.Time) componentHealth {
+	if err != nil {
+		return componentHealth{Status: statusUnavailable, Live: true, Error: err.Error(), Since: now}
+	}
+	return componentHealth{Status: statusOK, Live: true, Ready: true, Since: now}
+}
+
+// instanceName names a component in the report as <kind>/<id>. The processors are
+// instantiated in each pipeline, so their name is followed by @<pipeline>.
+func instanceName(source *component.InstanceID) string {
+	name := strings.ToLower(source.Kind.String()) + "/" + source.ID.String()
+	if source.Kind != component.KindProcessor {
+		return name
+	}
+	pipelines := make([]string, 0, len(source.PipelineIDs))
+	for id := range source.PipelineIDs {
+		pipelines = append(pipelines, id.String())
+	}
+	sort.Strings(pipelines)
+	return name + "@" + strings.Join(pipelines, ",")
+}
+
+type registry struct {
+	mu         sync.RWMutex
+	components map[string]componentHealth
+}
+
+func newRegistry() *registry {
+	return &registry{components: make(map[string]componentHealth)}
+}
+
+// set records the health of a component. Since is only updated when its status changes.
+func (r *registry) set(name string, h componentHealth) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prev, ok := r.components[name]; ok && prev.Status == h.Status {
+		h.Since = prev.Since
+	}
+	r.components[name] = h
+}
+
+func (r *registry) report() healthReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	report := healthReport{
+		Live:       true,
+		Ready:      true,
+		Components: make(map[string]componentHealth, len(r.components)),
+	}
+	for name, h := range r.components {
+		report.Live = report.Live && h.Live
+		report.Ready = report.Ready && h.Ready
+		report.Components[name] = h
+	}
+	return report
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerhealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestStatusEventHealth(t *testing.T) {
+	testCases := []struct {
+		event    *component.StatusEvent
+		expected componentHealth
+	}{
+		{event: component.NewStatusEvent(component.StatusStarting), expected: componentHealth{Status: "starting", Live: true}},
+		{event: component.NewStatusEvent(component.StatusOK), expected: componentHealth{Status: "ok", Live: true, Ready: true}},
+		{
+			event:    component.NewRecoverableErrorEvent(errors.New("consumer group not joined")),
+			expected: componentHealth{Status: "recoverable_error", Live: true, Error: "consumer group not joined"},
+		},
+		{
+			event:    component.NewPermanentErrorEvent(errors.New("bad config")),
+			expected: componentHealth{Status: "permanent_error", Error: "bad config"},
+		},
+		{
+			event:    component.NewFatalErrorEvent(errors.New("port in use")),
+			expected: componentHealth{Status: "fatal_error", Error: "port in use"},
+		},
+		{event: component.NewStatusEvent(component.StatusStopped), expected: componentHealth{Status: "stopped", Live: true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected.Status, func(t *testing.T) {
+			tc.expected.Since = tc.event.Timestamp()
+			assert.Equal(t, tc.expected, statusEventHealth(tc.event))
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, componentHealth{Status: "ok", Live: true, Ready: true, Since: now}, checkHealth(nil, now))
+	assert.Equal(t, componentHealth{Status: "unavailable", Live: true, Error: "no route", Since: now}, checkHealth(errors.New("no route"), now))
+}
+
+func TestInstanceName(t *testing.T) {
+	traces := component.MustNewID("traces")
+	tracesKafka := component.MustNewIDWithName("traces", "kafka")
+	assert.Equal(t, "receiver/kafka", instanceName(&component.InstanceID{
+		ID:          component.MustNewID("kafka"),
+		Kind:        component.KindReceiver,
+		PipelineIDs: map[component.ID]struct{}{traces: {}, tracesKafka: {}},
+	}))
+	assert.Equal(t, "processor/batch@traces", instanceName(&component.InstanceID{
+		ID:          component.MustNewID("batch"),
+		Kind:        component.KindProcessor,
+		PipelineIDs: map[component.ID]struct{}{traces: {}},
+	}))
+	assert.Equal(t, "extension/jaeger_health", instanceName(&component.InstanceID{ID: ID, Kind: component.KindExtension}))
+}
+
+func TestRegistry(t *testing.T) {
+	r := newRegistry()
+	assert.Equal(t, healthReport{Live: true, Ready: true, Components: map[string]componentHealth{}}, r.report())
+
+	start := time.Unix(1_700_000_000, 0)
+	r.set("receiver/otlp", componentHealth{Status: "ok", Live: true, Ready: true, Since: start})
+	r.set("storage/cassandra", checkHealth(errors.New("timeout"), start))
+	// the time of the status is kept while it does not change
+	r.set("storage/cassandra", checkHealth(errors.New("no route"), start.Add(time.Minute)))
+	report := r.report()
+	assert.True(t, report.Live)
+	assert.False(t, report.Ready)
+	assert.Equal(t, componentHealth{Status: "unavailable", Live: true, Error: "no route", Since: start}, report.Components["storage/cassandra"])
+
+	r.set("storage/cassandra", checkHealth(nil, start.Add(2*time.Minute)))
+	r.set("exporter/kafka", componentHealth{Status: "fatal_error", Since: start})
+	report = r.report()
+	assert.False(t, report.Live)
+	assert.False(t, report.Ready)
+	assert.Equal(t, start.Add(2*time.Minute), report.Components["storage/cassandra"].Since)
+}
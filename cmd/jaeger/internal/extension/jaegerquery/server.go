@@ -68,6 +68,19 @@ func (s *server) Start(_ context.Context, host component.Host) error {
 	if err := s.addArchiveStorage(&opts, host); err != nil {
 		return err
 	}
+	if s.config.DeleteTracesTokenFile != "" && !opts.InitTraceDeleter(f, s.logger) {
+		s.logger.Info("Deleting traces not initialized")
+	}
+	if s.config.Redaction.Enabled() {
+		opts.Redactor = querysvc.NewRedactor(s.config.Redaction)
+	}
+	if s.config.DependenciesFallback.Enabled() {
+		opts.DependenciesFallback = querysvc.NewDependenciesFallback(s.config.DependenciesFallback)
+	}
+	if s.config.LookupCache.Enabled() {
+		opts.LookupCache = querysvc.NewLookupCache(s.config.LookupCache, nil)
+	}
+	opts.CompletenessWindow = s.config.CompletenessWindow
 	qs := querysvc.NewQueryService(spanReader, depReader, opts)
 	metricsQueryService, _ := disabled.NewMetricsReader()
 	tm := tenancy.NewManager(&s.config.Tenancy)
@@ -109,13 +122,13 @@ func (s *server) addArchiveStorage(opts *querysvc.QueryServiceOptions, host comp
 		return nil
 	}
 
-	f, err := jaegerstorage.GetStorageFactory(s.config.TraceStorageArchive, host)
+	f, err := jaegerstorage.GetArchiveStorageFactory(s.config.TraceStorageArchive, host)
 	if err != nil {
 		return fmt.Errorf("cannot find archive storage factory: %w", err)
 	}
 
 	if !opts.InitArchiveStorage(f, s.logger) {
-		s.logger.Info("Archive storage not initialized")
+		return fmt.Errorf("cannot initialize archive storage %s", s.config.TraceStorageArchive)
 	}
 	return nil
 }
@@ -203,15 +203,25 @@ func TestServerAddArchiveStorage(t *testing.T) {
 			expectedErr:    "cannot find archive storage factory: cannot find extension",
 		},
 		{
-			name: "Archive storage not supported",
+			name: "Archive storage initialized",
 			config: &Config{
 				TraceStorageArchive: "badger",
 			},
 			qSvcOpts:       &querysvc.QueryServiceOptions{},
 			extension:      fakeStorageExt{},
-			expectedOutput: "Archive storage not supported by the factory",
+			expectedOutput: "",
 			expectedErr:    "",
 		},
+		{
+			name: "Archive storage initialization error",
+			config: &Config{
+				TraceStorageArchive: "need-span-reader-error",
+			},
+			qSvcOpts:       &querysvc.QueryServiceOptions{},
+			extension:      fakeStorageExt{},
+			expectedOutput: "Cannot init archive storage reader",
+			expectedErr:    "cannot initialize archive storage need-span-reader-error",
+		},
 	}
 
 	for _, tt := range tests {
@@ -232,6 +242,10 @@ func TestServerAddArchiveStorage(t *testing.T) {
 			}
 
 			assert.Contains(t, buf.String(), tt.expectedOutput)
+			if tt.expectedErr == "" && tt.config.TraceStorageArchive != "" {
+				assert.NotNil(t, tt.qSvcOpts.ArchiveSpanReader)
+				assert.NotNil(t, tt.qSvcOpts.ArchiveSpanWriter)
+			}
 		})
 	}
 }
@@ -0,0 +1,40 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerstorage
+
+import (
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var _ storage.ArchiveFactory = (*archiveFactory)(nil)
+
+// archiveFactory designates a storage backend as archive storage: the archive reader
+// and writer are the primary reader and writer of the backend.
+type archiveFactory struct {
+	storage.Factory
+}
+
+// GetArchiveStorageFactory locates the extension in Host and retrieves the storage factory
+// with the given name, designated as archive storage. Any backend can hold archived traces,
+// with its own configuration, e.g. a longer retention than the primary storage.
+func GetArchiveStorageFactory(name string, host component.Host) (storage.Factory, error) {
+	f, err := GetStorageFactory(name, host)
+	if err != nil {
+		return nil, err
+	}
+	return &archiveFactory{Factory: f}, nil
+}
+
+// CreateArchiveSpanReader implements storage.ArchiveFactory.
+func (f *archiveFactory) CreateArchiveSpanReader() (spanstore.Reader, error) {
+	return f.CreateSpanReader()
+}
+
+// CreateArchiveSpanWriter implements storage.ArchiveFactory.
+func (f *archiveFactory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
+	return f.CreateSpanWriter()
+}
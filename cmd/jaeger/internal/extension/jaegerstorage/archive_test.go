@@ -0,0 +1,39 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerstorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/jaegertracing/jaeger/storage"
+)
+
+func TestGetArchiveStorageFactory(t *testing.T) {
+	const name = "foo"
+	host := storageHost{t: t, ext: startStorageExtension(t, name)}
+	f, err := GetArchiveStorageFactory(name, host)
+	require.NoError(t, err)
+
+	archive, ok := f.(storage.ArchiveFactory)
+	require.True(t, ok)
+	primaryReader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	archiveReader, err := archive.CreateArchiveSpanReader()
+	require.NoError(t, err)
+	assert.Equal(t, primaryReader, archiveReader)
+	primaryWriter, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	archiveWriter, err := archive.CreateArchiveSpanWriter()
+	require.NoError(t, err)
+	assert.Equal(t, primaryWriter, archiveWriter)
+}
+
+func TestGetArchiveStorageFactoryError(t *testing.T) {
+	_, err := GetArchiveStorageFactory("something", componenttest.NewNopHost())
+	require.ErrorContains(t, err, "cannot find extension")
+}
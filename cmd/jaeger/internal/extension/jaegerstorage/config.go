@@ -50,7 +50,8 @@ func (cfg *Backend) Unmarshal(conf *confmap.Conf) error {
 	// apply defaults
 	if conf.IsSet("memory") {
 		cfg.Memory = &memory.Configuration{
-			MaxTraces: 1_000_000,
+			MaxTraces:            1_000_000,
+			DependenciesCacheTTL: time.Minute,
 		}
 	}
 	if conf.IsSet("badger") {
@@ -73,6 +74,9 @@ func (cfg *Backend) Unmarshal(conf *confmap.Conf) error {
 				ProcessTags: true,
 				Logs:        true,
 			},
+			Sampling: cassandra.SamplingConfig{
+				TTL: 48 * time.Hour,
+			},
 		}
 	}
 	if conf.IsSet("elasticsearch") {
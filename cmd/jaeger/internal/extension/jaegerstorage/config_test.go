@@ -51,6 +51,7 @@ backends:
 	cfg := createDefaultConfig().(*Config)
 	require.NoError(t, conf.Unmarshal(cfg))
 	assert.NotEmpty(t, cfg.Backends["some_storage"].Memory.MaxTraces)
+	assert.NotEmpty(t, cfg.Backends["some_storage"].Memory.DependenciesCacheTTL)
 }
 
 func TestConfigDefaultBadger(t *testing.T) {
@@ -9,8 +9,10 @@ import (
 	"fmt"
 
 	otlp2jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"github.com/jaegertracing/jaeger/model"
 	spanstore_v1 "github.com/jaegertracing/jaeger/storage/spanstore"
 	"github.com/jaegertracing/jaeger/storage_v2/spanstore"
 )
@@ -45,3 +47,20 @@ func (t *TraceWriter) WriteTraces(ctx context.Context, td ptrace.Traces) error {
 	}
 	return errors.Join(errs...)
 }
+
+// DeleteTraces implements spanstore.Writer, if the v1 span writer supports deleting traces.
+func (t *TraceWriter) DeleteTraces(ctx context.Context, traceIDs []pcommon.TraceID) error {
+	deleter, ok := t.spanWriter.(spanstore_v1.TraceDeleter)
+	if !ok {
+		return spanstore.ErrDeleteTracesNotSupported
+	}
+	ids := make([]model.TraceID, len(traceIDs))
+	for i, traceID := range traceIDs {
+		id, err := model.TraceIDFromBytes(traceID[:])
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+	return deleter.DeleteTraces(ctx, ids)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
@@ -32,7 +33,7 @@ func TestWriteTraces(t *testing.T) {
 	tdID := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
 	traceID, err := model.TraceIDFromBytes(tdID[:])
 	require.NoError(t, err)
-	trace, err := memstore.GetTrace(context.Background(), traceID)
+	trace, err := memstore.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: traceID})
 	require.NoError(t, err)
 	require.NotNil(t, trace)
 	assert.Len(t, trace.Spans, 1)
@@ -54,6 +55,31 @@ func TestWriteTracesError(t *testing.T) {
 	require.ErrorContains(t, err, "mocked error")
 }
 
+func TestDeleteTraces(t *testing.T) {
+	memstore := memory.NewStore()
+	traceWriter := &TraceWriter{
+		spanWriter: memstore,
+	}
+	td := makeTraces()
+	require.NoError(t, traceWriter.WriteTraces(context.Background(), td))
+
+	tdID := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+	require.NoError(t, traceWriter.DeleteTraces(context.Background(), []pcommon.TraceID{tdID}))
+
+	traceID, err := model.TraceIDFromBytes(tdID[:])
+	require.NoError(t, err)
+	_, err = memstore.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: traceID})
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
+func TestDeleteTracesNotSupported(t *testing.T) {
+	traceWriter := &TraceWriter{
+		spanWriter: spanstoreMocks.NewWriter(t),
+	}
+	err := traceWriter.DeleteTraces(context.Background(), []pcommon.TraceID{pcommon.NewTraceIDEmpty()})
+	require.ErrorIs(t, err, spanstore.ErrDeleteTracesNotSupported)
+}
+
 func makeTraces() ptrace.Traces {
 	traces := ptrace.NewTraces()
 	rSpans := traces.ResourceSpans().AppendEmpty()
@@ -6,15 +6,15 @@ package integration
 import (
 	"testing"
 
-	"github.com/jaegertracing/jaeger/plugin/storage/integration"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 func TestBadgerStorage(t *testing.T) {
-	integration.SkipUnlessEnv(t, "badger")
+	storagetest.SkipUnlessEnv(t, "badger")
 
 	s := &E2EStorageIntegration{
 		ConfigFile: "../../config-badger.yaml",
-		StorageIntegration: integration.StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			SkipArchiveTest: true,
 			CleanUp:         purge,
 
@@ -6,19 +6,19 @@ package integration
 import (
 	"testing"
 
-	"github.com/jaegertracing/jaeger/plugin/storage/integration"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 func TestCassandraStorage(t *testing.T) {
-	integration.SkipUnlessEnv(t, "cassandra")
+	storagetest.SkipUnlessEnv(t, "cassandra")
 	s := &E2EStorageIntegration{
 		ConfigFile: "../../config-cassandra.yaml",
-		StorageIntegration: integration.StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			CleanUp:                      purge,
 			GetDependenciesReturnsSource: true,
 			SkipArchiveTest:              true,
 
-			SkipList: integration.CassandraSkippedTests,
+			SkipList: storagetest.CassandraSkippedTests,
 		},
 	}
 	s.e2eInitialize(t, "cassandra")
@@ -6,17 +6,17 @@ package integration
 import (
 	"testing"
 
-	"github.com/jaegertracing/jaeger/plugin/storage/integration"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 func TestESStorage(t *testing.T) {
-	integration.SkipUnlessEnv(t, "elasticsearch")
+	storagetest.SkipUnlessEnv(t, "elasticsearch")
 
 	s := &E2EStorageIntegration{
 		ConfigFile: "../../config-elasticsearch.yaml",
-		StorageIntegration: integration.StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			CleanUp:                      purge,
-			Fixtures:                     integration.LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
+			Fixtures:                     storagetest.LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
 			GetOperationsMissingSpanKind: true,
 		},
 	}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/jaegertracing/jaeger/plugin/storage/integration"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 type GRPCStorageIntegration struct {
@@ -25,7 +26,7 @@ func (s *GRPCStorageIntegration) cleanUp(t *testing.T) {
 }
 
 func TestGRPCStorage(t *testing.T) {
-	integration.SkipUnlessEnv(t, "grpc")
+	storagetest.SkipUnlessEnv(t, "grpc")
 
 	s := &GRPCStorageIntegration{
 		E2EStorageIntegration: E2EStorageIntegration{
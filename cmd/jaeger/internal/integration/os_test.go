@@ -6,16 +6,16 @@ package integration
 import (
 	"testing"
 
-	"github.com/jaegertracing/jaeger/plugin/storage/integration"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 func TestOSStorage(t *testing.T) {
-	integration.SkipUnlessEnv(t, "opensearch")
+	storagetest.SkipUnlessEnv(t, "opensearch")
 	s := &E2EStorageIntegration{
 		ConfigFile: "../../config-opensearch.yaml",
-		StorageIntegration: integration.StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			CleanUp:                      purge,
-			Fixtures:                     integration.LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
+			Fixtures:                     storagetest.LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
 			GetOperationsMissingSpanKind: true,
 		},
 	}
@@ -66,10 +66,17 @@ func unwrapNotFoundErr(err error) error {
 	return err
 }
 
-func (r *spanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	stream, err := r.client.GetTrace(ctx, &api_v2.GetTraceRequest{
-		TraceID: traceID,
-	})
+func (r *spanReader) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	req := &api_v2.GetTraceRequest{
+		TraceID: query.TraceID,
+	}
+	if !query.StartTime.IsZero() {
+		req.StartTime = &query.StartTime
+	}
+	if !query.EndTime.IsZero() {
+		req.EndTime = &query.EndTime
+	}
+	stream, err := r.client.GetTrace(ctx, req)
 	if err != nil {
 		return nil, unwrapNotFoundErr(err)
 	}
@@ -154,7 +154,7 @@ func (gw *testGateway) runGatewayGetOperations(t *testing.T) {
 
 func (gw *testGateway) runGatewayGetTrace(t *testing.T) {
 	trace, traceID := makeTestTrace()
-	gw.reader.On("GetTrace", matchContext, traceID).Return(trace, nil).Once()
+	gw.reader.On("GetTrace", matchContext, spanstore.GetTraceParameters{TraceID: traceID}).Return(trace, nil).Once()
 	gw.getTracesAndVerify(t, "/api/v3/traces/"+traceID.String(), traceID)
 }
 
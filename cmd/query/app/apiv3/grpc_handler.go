@@ -43,7 +43,19 @@ func (h *Handler) GetTrace(request *api_v3.GetTraceRequest, stream api_v3.QueryS
 		return fmt.Errorf("malform trace ID: %w", err)
 	}
 
-	trace, err := h.QueryService.GetTrace(stream.Context(), traceID)
+	query := spanstore.GetTraceParameters{
+		TraceID: traceID,
+	}
+	if request.GetStartTime() != nil {
+		query.StartTime = *request.GetStartTime()
+	}
+	if request.GetEndTime() != nil {
+		query.EndTime = *request.GetEndTime()
+	}
+	if err := query.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	trace, err := h.QueryService.GetTrace(stream.Context(), query)
 	if err != nil {
 		return fmt.Errorf("cannot retrieve trace: %w", err)
 	}
@@ -19,13 +19,16 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
@@ -38,7 +41,7 @@ import (
 
 var (
 	matchContext = mock.AnythingOfType("*context.valueCtx")
-	matchTraceID = mock.AnythingOfType("model.TraceID")
+	matchTraceID = mock.AnythingOfType("spanstore.GetTraceParameters")
 )
 
 func newGrpcServer(t *testing.T, handler *Handler) (*grpc.Server, net.Addr) {
@@ -113,6 +116,54 @@ func TestGetTrace(t *testing.T) {
 		td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
 }
 
+func TestGetTraceWithTimeWindow(t *testing.T) {
+	tsc := newTestServerClient(t)
+	startTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(time.Hour)
+	tsc.reader.On("GetTrace", matchContext, spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 0x156),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}).Return(&model.Trace{
+		Spans: []*model.Span{
+			{
+				OperationName: "foobar",
+			},
+		},
+	}, nil).Once()
+
+	getTraceStream, err := tsc.client.GetTrace(context.Background(),
+		&api_v3.GetTraceRequest{
+			TraceId:   "156",
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		},
+	)
+	require.NoError(t, err)
+	recv, err := getTraceStream.Recv()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, recv.ToTraces().SpanCount())
+}
+
+func TestGetTraceInvertedTimeWindow(t *testing.T) {
+	tsc := newTestServerClient(t)
+	startTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(-time.Hour)
+
+	getTraceStream, err := tsc.client.GetTrace(context.Background(),
+		&api_v3.GetTraceRequest{
+			TraceId:   "156",
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		},
+	)
+	require.NoError(t, err)
+	recv, err := getTraceStream.Recv()
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Nil(t, recv)
+}
+
 func TestGetTraceStorageError(t *testing.T) {
 	tsc := newTestServerClient(t)
 	tsc.reader.On("GetTrace", matchContext, matchTraceID).Return(
@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -28,7 +30,9 @@ import (
 )
 
 const (
-	paramTraceID       = "trace_id"           // get trace by ID
+	paramTraceID       = "trace_id" // get trace by ID
+	paramStartTime     = "start_time"
+	paramEndTime       = "end_time"
 	paramServiceName   = "query.service_name" // find traces
 	paramOperationName = "query.operation_name"
 	paramTimeMin       = "query.start_time_min"
@@ -41,6 +45,9 @@ const (
 	routeFindTraces    = "/api/v3/traces"
 	routeGetServices   = "/api/v3/services"
 	routeGetOperations = "/api/v3/operations"
+
+	mediaTypeJSON     = "application/json"
+	mediaTypeProtobuf = "application/x-protobuf"
 )
 
 // HTTPGateway exposes APIv3 HTTP endpoints.
@@ -110,14 +117,15 @@ func (h *HTTPGateway) tryParamError(w http.ResponseWriter, err error, paramName
 	return h.tryHandleError(w, fmt.Errorf("malformed parameter %s: %w", paramName, err), http.StatusBadRequest)
 }
 
-func (h *HTTPGateway) returnSpans(spans []*model.Span, w http.ResponseWriter) {
+func (h *HTTPGateway) returnSpans(spans []*model.Span, w http.ResponseWriter, r *http.Request) {
 	// modelToOTLP does not easily return an error, so allow mocking it
-	h.returnSpansTestable(spans, w, modelToOTLP)
+	h.returnSpansTestable(spans, w, r, modelToOTLP)
 }
 
 func (h *HTTPGateway) returnSpansTestable(
 	spans []*model.Span,
 	w http.ResponseWriter,
+	r *http.Request,
 	modelToOTLP func(_ []*model.Span) (ptrace.Traces, error),
 ) {
 	td, err := modelToOTLP(spans)
@@ -125,14 +133,59 @@ func (h *HTTPGateway) returnSpansTestable(
 		return
 	}
 	tracesData := api_v3.TracesData(td)
+	if acceptsProtobuf(r) {
+		// the binary response is the message streamed by the gRPC API, without the JSON envelope
+		h.marshalResponse(w, r, &tracesData)
+		return
+	}
 	response := &api_v3.GRPCGatewayWrapper{
 		Result: &tracesData,
 	}
-	h.marshalResponse(response, w)
+	h.marshalResponse(w, r, response)
 }
 
-func (*HTTPGateway) marshalResponse(response proto.Message, w http.ResponseWriter) {
-	_ = new(jsonpb.Marshaler).Marshal(w, response)
+// marshalResponse writes the response in the protobuf binary encoding if the client accepts it,
+// and in JSON otherwise. The response is compressed by the HTTP server according to Accept-Encoding.
+func (h *HTTPGateway) marshalResponse(w http.ResponseWriter, r *http.Request, response proto.Message) {
+	w.Header().Add("Vary", "Accept")
+	if !acceptsProtobuf(r) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_ = new(jsonpb.Marshaler).Marshal(w, response)
+		return
+	}
+	body, err := proto.Marshal(response)
+	if h.tryHandleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	w.Header().Set("Content-Type", mediaTypeProtobuf)
+	_, _ = w.Write(body)
+}
+
+// acceptsProtobuf returns true if the Accept header of the request prefers the protobuf
+// binary encoding to JSON, or accepts only the former.
+func acceptsProtobuf(r *http.Request) bool {
+	var protobufQ, jsonQ float64
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			switch mediaType {
+			case mediaTypeProtobuf, "application/protobuf":
+				protobufQ = max(protobufQ, q)
+			case mediaTypeJSON, "application/*", "*/*":
+				jsonQ = max(jsonQ, q)
+			}
+		}
+	}
+	return protobufQ > 0 && protobufQ >= jsonQ
 }
 
 func (h *HTTPGateway) getTrace(w http.ResponseWriter, r *http.Request) {
@@ -142,11 +195,31 @@ func (h *HTTPGateway) getTrace(w http.ResponseWriter, r *http.Request) {
 	if h.tryParamError(w, err, paramTraceID) {
 		return
 	}
-	trace, err := h.QueryService.GetTrace(r.Context(), traceID)
+	query := spanstore.GetTraceParameters{
+		TraceID: traceID,
+	}
+	if startTime := r.FormValue(paramStartTime); startTime != "" {
+		timeParsed, err := time.Parse(time.RFC3339Nano, startTime)
+		if h.tryParamError(w, err, paramStartTime) {
+			return
+		}
+		query.StartTime = timeParsed.UTC()
+	}
+	if endTime := r.FormValue(paramEndTime); endTime != "" {
+		timeParsed, err := time.Parse(time.RFC3339Nano, endTime)
+		if h.tryParamError(w, err, paramEndTime) {
+			return
+		}
+		query.EndTime = timeParsed.UTC()
+	}
+	if h.tryHandleError(w, query.Validate(), http.StatusBadRequest) {
+		return
+	}
+	trace, err := h.QueryService.GetTrace(r.Context(), query)
 	if h.tryHandleError(w, err, http.StatusInternalServerError) {
 		return
 	}
-	h.returnSpans(trace.Spans, w)
+	h.returnSpans(trace.Spans, w, r)
 }
 
 func (h *HTTPGateway) findTraces(w http.ResponseWriter, r *http.Request) {
@@ -164,7 +237,7 @@ func (h *HTTPGateway) findTraces(w http.ResponseWriter, r *http.Request) {
 	for _, trace := range traces {
 		spans = append(spans, trace.Spans...)
 	}
-	h.returnSpans(spans, w)
+	h.returnSpans(spans, w, r)
 }
 
 func (h *HTTPGateway) parseFindTracesQuery(q url.Values, w http.ResponseWriter) (*spanstore.TraceQueryParameters, bool) {
@@ -222,9 +295,9 @@ func (h *HTTPGateway) getServices(w http.ResponseWriter, r *http.Request) {
 	if h.tryHandleError(w, err, http.StatusInternalServerError) {
 		return
 	}
-	h.marshalResponse(&api_v3.GetServicesResponse{
+	h.marshalResponse(w, r, &api_v3.GetServicesResponse{
 		Services: services,
-	}, w)
+	})
 }
 
 func (h *HTTPGateway) getOperations(w http.ResponseWriter, r *http.Request) {
@@ -244,5 +317,5 @@ func (h *HTTPGateway) getOperations(w http.ResponseWriter, r *http.Request) {
 			SpanKind: operations[i].SpanKind,
 		}
 	}
-	h.marshalResponse(&api_v3.GetOperationsResponse{Operations: apiOperations}, w)
+	h.marshalResponse(w, r, &api_v3.GetOperationsResponse{Operations: apiOperations})
 }
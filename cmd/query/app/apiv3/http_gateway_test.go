@@ -12,12 +12,14 @@ import (
 	"testing"
 	"time"
 
+	gogoproto "github.com/gogo/protobuf/proto"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
@@ -116,7 +118,8 @@ func TestHTTPGatewayOTLPError(t *testing.T) {
 		Logger: zap.NewNop(),
 	}
 	const simErr = "simulated error"
-	gw.returnSpansTestable(nil, w,
+	r := httptest.NewRequest(http.MethodGet, "/api/v3/traces/123", nil)
+	gw.returnSpansTestable(nil, w, r,
 		func(_ []*model.Span) (ptrace.Traces, error) {
 			return ptrace.Traces{}, fmt.Errorf(simErr)
 		},
@@ -124,6 +127,81 @@ func TestHTTPGatewayOTLPError(t *testing.T) {
 	assert.Contains(t, w.Body.String(), simErr)
 }
 
+func TestHTTPGatewayProtobuf(t *testing.T) {
+	gw := setupHTTPGatewayNoServer(t, "", tenancy.Options{})
+	get := func(t *testing.T, url string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Accept", mediaTypeProtobuf)
+		w := httptest.NewRecorder()
+		gw.router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code, "response=%s", w.Body.String())
+		assert.Equal(t, mediaTypeProtobuf, w.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		return w
+	}
+
+	t.Run("GetTrace", func(t *testing.T) {
+		trace, traceID := makeTestTrace()
+		gw.reader.On("GetTrace", matchContext, spanstore.GetTraceParameters{TraceID: traceID}).Return(trace, nil).Once()
+		w := get(t, "/api/v3/traces/"+traceID.String())
+
+		var td api_v3.TracesData
+		require.NoError(t, gogoproto.Unmarshal(w.Body.Bytes(), &td))
+		traces := td.ToTraces()
+		require.EqualValues(t, 1, traces.SpanCount())
+		span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+		assert.Equal(t, traceID.String(), span.TraceID().String())
+		assert.Equal(t, "foobar", span.Name())
+	})
+
+	t.Run("GetServices", func(t *testing.T) {
+		gw.reader.On("GetServices", matchContext).Return([]string{"foo"}, nil).Once()
+		w := get(t, "/api/v3/services")
+
+		var response api_v3.GetServicesResponse
+		require.NoError(t, gogoproto.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"foo"}, response.Services)
+	})
+
+	t.Run("errors remain JSON", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v3/traces/xyz", nil)
+		r.Header.Set("Accept", mediaTypeProtobuf)
+		w := httptest.NewRecorder()
+		gw.router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "malformed parameter trace_id")
+	})
+}
+
+func TestAcceptsProtobuf(t *testing.T) {
+	testCases := []struct {
+		accept   []string
+		expected bool
+	}{
+		{accept: nil},
+		{accept: []string{"*/*"}},
+		{accept: []string{"application/json"}},
+		{accept: []string{"application/x-protobuf"}, expected: true},
+		{accept: []string{"application/protobuf"}, expected: true},
+		{accept: []string{"application/x-protobuf, application/json"}, expected: true},
+		{accept: []string{"application/json", "application/x-protobuf"}, expected: true},
+		{accept: []string{"application/x-protobuf;q=0.5, application/json"}},
+		{accept: []string{"application/x-protobuf, */*;q=0.1"}, expected: true},
+		{accept: []string{"application/x-protobuf;q=0"}},
+		{accept: []string{"application/x-protobuf;q=high"}},
+		{accept: []string{"application/x-protobuf;;"}},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%q", tc.accept), func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, accept := range tc.accept {
+				r.Header.Add("Accept", accept)
+			}
+			assert.Equal(t, tc.expected, acceptsProtobuf(r))
+		})
+	}
+}
+
 func TestHTTPGatewayGetTraceErrors(t *testing.T) {
 	gw := setupHTTPGatewayNoServer(t, "", tenancy.Options{})
 
@@ -147,6 +225,64 @@ func TestHTTPGatewayGetTraceErrors(t *testing.T) {
 	assert.Contains(t, w.Body.String(), simErr)
 }
 
+func TestHTTPGatewayGetTraceWithTimeWindow(t *testing.T) {
+	gw := setupHTTPGatewayNoServer(t, "", tenancy.Options{})
+	traceID := model.NewTraceID(0, 0x123)
+	startTime := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(time.Hour)
+	gw.reader.
+		On("GetTrace", matchContext, spanstore.GetTraceParameters{
+			TraceID:   traceID,
+			StartTime: startTime,
+			EndTime:   endTime,
+		}).
+		Return(&model.Trace{}, nil).Once()
+
+	q := url.Values{}
+	q.Set(paramStartTime, startTime.Format(time.RFC3339Nano))
+	q.Set(paramEndTime, endTime.Format(time.RFC3339Nano))
+	r, err := http.NewRequest(http.MethodGet, "/api/v3/traces/123?"+q.Encode(), nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	gw.router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestHTTPGatewayGetTraceBadTimeWindow(t *testing.T) {
+	gw := setupHTTPGatewayNoServer(t, "", tenancy.Options{})
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "malformed start_time",
+			query:    "start_time=foo",
+			expected: "malformed parameter start_time",
+		},
+		{
+			name:     "malformed end_time",
+			query:    "end_time=foo",
+			expected: "malformed parameter end_time",
+		},
+		{
+			name:     "end_time before start_time",
+			query:    "start_time=2020-01-01T12:00:00Z&end_time=2020-01-01T11:00:00Z",
+			expected: spanstore.ErrInvalidTimeWindow.Error(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/api/v3/traces/123?"+tc.query, nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			gw.router.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expected)
+		})
+	}
+}
+
 func mockFindQueries() (url.Values, *spanstore.TraceQueryParameters) {
 	// mock performs deep comparison of the timestamps and can fail
 	// if they are different in the timezone or the monotonic clocks.
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jaegertracing/jaeger/model"
+	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// maxBatchTraceIDs is the maximum number of trace IDs of a batch request.
+	maxBatchTraceIDs = 1000
+	// maxBatchRequestBytes bounds the size of the body of a batch request, which only holds trace IDs.
+	maxBatchRequestBytes = 1 << 20
+	// batchTracesConcurrency is the number of traces of a batch fetched concurrently.
+	batchTracesConcurrency = 8
+)
+
+// Statuses of the traces of a batch.
+const (
+	batchTraceFound    = "found"
+	batchTraceNotFound = "not_found"
+	batchTraceError    = "error"
+)
+
+var errNoBatchTraceIDs = errors.New("no trace IDs requested")
+
+// batchTracesRequest is the body of the batch traces API.
+type batchTracesRequest struct {
+	TraceIDs []string `json:"traceIDs"`
+}
+
+// batchTraceResult is the result of a trace of a batch, which is streamed as a line of JSON.
+// The error is set when the trace could not be fetched, or when it was found but could not be adjusted.
+type batchTraceResult struct {
+	TraceID ui.TraceID `json:"traceID"`
+	Status  string     `json:"status"`
+	Trace   *ui.Trace  `json:"trace,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// getTracesBatch implements the REST API POST:/traces/batch?start={start}&end={end}.
+// It fetches the traces whose IDs are listed in the body, e.g. {"traceIDs": ["1", "2"]},
+// and streams one JSON line per trace ID, in the requested order, with the status of the trace:
+// found with the trace, not_found, or error with the error. The failure of a trace does not fail
+// the other traces. The optional start and end define the time window of all the traces.
+func (aH *APIHandler) getTracesBatch(w http.ResponseWriter, r *http.Request) {
+	var request batchTracesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchRequestBytes)).Decode(&request); err != nil {
+		aH.handleError(w, fmt.Errorf("cannot parse batch request: %w", err), http.StatusBadRequest)
+		return
+	}
+	traceIDs, err := parseBatchTraceIDs(request.TraceIDs)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	startTime, endTime, err := aH.queryParser.parseTraceTimeWindow(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	window := spanstore.GetTraceParameters{StartTime: startTime, EndTime: endTime}
+	if aH.handleError(w, window.Validate(), http.StatusBadRequest) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		aH.handleError(w, errStreamingNotSupported, http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	adjust := shouldAdjust(r)
+	results := make([]chan batchTraceResult, len(traceIDs))
+	for i := range results {
+		results[i] = make(chan batchTraceResult, 1)
+	}
+	go func() {
+		sem := make(chan struct{}, batchTracesConcurrency)
+		for i, traceID := range traceIDs {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, query spanstore.GetTraceParameters) {
+				defer func() { <-sem }()
+				results[i] <- aH.getBatchTrace(ctx, query, adjust)
+			}(i, spanstore.GetTraceParameters{TraceID: traceID, StartTime: startTime, EndTime: endTime})
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	for _, resultCh := range results {
+		select {
+		case result := <-resultCh:
+			if err := encoder.Encode(result); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func parseBatchTraceIDs(ids []string) ([]model.TraceID, error) {
+	if len(ids) == 0 {
+		return nil, errNoBatchTraceIDs
+	}
+	if len(ids) > maxBatchTraceIDs {
+		return nil, fmt.Errorf("too many trace IDs requested: %d, the maximum is %d", len(ids), maxBatchTraceIDs)
+	}
+	traceIDs := make([]model.TraceID, len(ids))
+	for i, id := range ids {
+		traceID, err := model.TraceIDFromString(id)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse traceID param: %w", err)
+		}
+		traceIDs[i] = traceID
+	}
+	return traceIDs, nil
+}
+
+func (aH *APIHandler) getBatchTrace(ctx context.Context, query spanstore.GetTraceParameters, adjust bool) batchTraceResult {
+	result := batchTraceResult{TraceID: ui.TraceID(query.TraceID.String())}
+	trace, err := aH.queryService.GetTrace(ctx, query)
+	switch {
+	case errors.Is(err, spanstore.ErrTraceNotFound):
+		result.Status = batchTraceNotFound
+	case err != nil:
+		result.Status = batchTraceError
+		result.Error = err.Error()
+	default:
+		result.Status = batchTraceFound
+		var uiErr *structuredError
+		result.Trace, uiErr = aH.convertModelToUI(trace, adjust)
+		if uiErr != nil {
+			result.Error = uiErr.Msg
+		}
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func postBatch(t *testing.T, url, body string) *http.Response {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestGetTracesBatch(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	start := time.UnixMicro(1_700_000_000_000_000)
+	end := start.Add(time.Hour)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID, StartTime: start, EndTime: end}).
+		Return(mockTrace, nil)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1), StartTime: start, EndTime: end}).
+		Return(nil, spanstore.ErrTraceNotFound)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 2), StartTime: start, EndTime: end}).
+		Return(nil, errStorage)
+
+	resp := postBatch(t, ts.server.URL+`/api/traces/batch?start=1700000000000000&end=1700003600000000`,
+		`{"traceIDs": ["1", "1e240", "2", "1"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var results []batchTraceResult
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var result batchTraceResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		results = append(results, result)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, results, 4)
+
+	assert.Equal(t, batchTraceResult{TraceID: "0000000000000001", Status: batchTraceNotFound}, results[0])
+	assert.Equal(t, "000000000001e240", string(results[1].TraceID))
+	assert.Equal(t, batchTraceFound, results[1].Status)
+	require.NotNil(t, results[1].Trace)
+	assert.Len(t, results[1].Trace.Spans, len(mockTrace.Spans))
+	assert.Equal(t, batchTraceResult{TraceID: "0000000000000002", Status: batchTraceError, Error: errStorage.Error()}, results[2])
+	assert.Equal(t, results[0], results[3])
+}
+
+func TestGetTracesBatchInvalid(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	tooMany := make([]string, maxBatchTraceIDs+1)
+	for i := range tooMany {
+		tooMany[i] = "1"
+	}
+	tooManyBody, err := json.Marshal(batchTracesRequest{TraceIDs: tooMany})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		query string
+		body  string
+		err   string
+	}{
+		{name: "invalid body", body: `{"traceIDs": `, err: "cannot parse batch request"},
+		{name: "no trace IDs", body: `{"traceIDs": []}`, err: errNoBatchTraceIDs.Error()},
+		{name: "too many trace IDs", body: string(tooManyBody), err: "too many trace IDs requested: 1001, the maximum is 1000"},
+		{name: "invalid trace ID", body: `{"traceIDs": ["1", "xyz"]}`, err: "cannot parse traceID param"},
+		{name: "invalid start", query: "?start=xyz", body: `{"traceIDs": ["1"]}`, err: "unable to parse param 'start'"},
+		{name: "inverted window", query: "?start=2000&end=1000", body: `{"traceIDs": ["1"]}`, err: spanstore.ErrInvalidTimeWindow.Error()},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postBatch(t, ts.server.URL+`/api/traces/batch`+tc.query, tc.body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			var response structuredResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			require.Len(t, response.Errors, 1)
+			assert.Contains(t, response.Errors[0].Msg, tc.err)
+		})
+	}
+}
+
+type nonFlushingWriter struct {
+	http.ResponseWriter
+}
+
+func TestGetTracesBatchStreamingNotSupported(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/traces/batch", strings.NewReader(`{"traceIDs": ["1"]}`))
+	recorder := httptest.NewRecorder()
+	ts.handler.getTracesBatch(nonFlushingWriter{recorder}, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), errStreamingNotSupported.Error())
+}
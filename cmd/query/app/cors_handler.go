@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
+)
+
+const corsAllowedMethods = "GET, POST, OPTIONS"
+
+// corsHandler answers CORS preflight requests and sets Access-Control-Allow-Origin
+// on responses to allowed origins. Origins may contain a single "*" wildcard,
+// e.g. "https://*.example.com", or be "*" to allow any origin.
+// If no origins are configured the handler is returned unchanged.
+func corsHandler(h http.Handler, opts corscfg.Options) http.Handler {
+	origins := nonEmpty(opts.AllowedOrigins)
+	if len(origins) == 0 {
+		return h
+	}
+	allowedHeaders := strings.Join(nonEmpty(opts.AllowedHeaders), ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !originAllowed(origin, origins) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			if allowedHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// frameAncestorsHandler sets a Content-Security-Policy restricting which origins
+// may embed the UI in a frame. If no ancestors are configured the handler is returned unchanged.
+func frameAncestorsHandler(h http.Handler, ancestors []string) http.Handler {
+	ancestors = nonEmpty(ancestors)
+	if len(ancestors) == 0 {
+		return h
+	}
+	policy := "frame-ancestors " + strings.Join(ancestors, " ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", policy)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func originAllowed(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == "*" || a == origin {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(a, "*"); ok &&
+			len(origin) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
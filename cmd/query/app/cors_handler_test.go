@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
+)
+
+func TestCORSHandler(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte{})
+	})
+	handler := corsHandler(emptyHandler, corscfg.Options{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+	})
+
+	testCases := []struct {
+		name          string
+		method        string
+		origin        string
+		preflight     bool
+		expectOrigin  string
+		expectHeaders string
+		expectStatus  int
+	}{
+		{name: "no origin", method: http.MethodGet, expectStatus: http.StatusOK},
+		{name: "exact origin", method: http.MethodGet, origin: "https://app.example.com", expectOrigin: "https://app.example.com", expectStatus: http.StatusOK},
+		{name: "wildcard origin", method: http.MethodGet, origin: "https://ui.example.org", expectOrigin: "https://ui.example.org", expectStatus: http.StatusOK},
+		{name: "disallowed origin", method: http.MethodGet, origin: "https://evil.com", expectStatus: http.StatusOK},
+		{name: "wildcard suffix only", method: http.MethodGet, origin: "https://example.org", expectStatus: http.StatusOK},
+		{
+			name: "preflight", method: http.MethodOptions, origin: "https://app.example.com", preflight: true,
+			expectOrigin: "https://app.example.com", expectHeaders: "Authorization, Content-Type", expectStatus: http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/traces", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tc.expectHeaders, rec.Header().Get("Access-Control-Allow-Headers"))
+		})
+	}
+}
+
+func TestCORSHandlerDisabled(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	handler := corsHandler(emptyHandler, corscfg.Options{AllowedOrigins: []string{""}})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestFrameAncestorsHandler(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	frameAncestorsHandler(emptyHandler, []string{"'self'", "https://app.example.com"}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "frame-ancestors 'self' https://app.example.com", rec.Header().Get("Content-Security-Policy"))
+
+	rec = httptest.NewRecorder()
+	frameAncestorsHandler(emptyHandler, []string{""}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, rec.Header().Get("Content-Security-Policy"))
+}
@@ -29,4 +29,8 @@ var (
 	defaultMetricsQueryStepDuration     = 5 * time.Second
 	defaultMetricsQueryRateDuration     = 10 * time.Minute
 	defaultMetricsSpanKinds             = []string{metrics.SpanKind_SPAN_KIND_SERVER.String()}
+	defaultSLOObjective                 = 0.999
+	defaultSLOWindows                   = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+	defaultSnapshotQuantile             = 0.95
+	defaultSnapshotLimit                = 10
 )
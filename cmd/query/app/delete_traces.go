@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var errDeleteTracesUnauthorized = errors.New("a valid bearer token is required to delete traces")
+
+// loadDeleteTracesToken reads the bearer token authorizing the deletion of traces from a file.
+// Surrounding whitespace is ignored.
+func loadDeleteTracesToken(tokenFile string) (string, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read delete traces token: %w", err)
+	}
+	trimmed := strings.TrimSpace(string(token))
+	if trimmed == "" {
+		return "", fmt.Errorf("delete traces token file %s is empty", tokenFile)
+	}
+	return trimmed, nil
+}
+
+// deleteTraces implements the REST API DELETE:/traces. It deletes the traces whose IDs are listed
+// in the body, e.g. {"traceIDs": ["1", "2"]}, from the span storage and from the archive storage.
+// The request must carry the configured token as a bearer token in the Authorization header.
+// Deleting a trace that is not stored is not an error, so that deletions can be retried.
+func (aH *APIHandler) deleteTraces(w http.ResponseWriter, r *http.Request) {
+	if !aH.isDeleteTracesAuthorized(r) {
+		aH.handleError(w, errDeleteTracesUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var request batchTracesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchRequestBytes)).Decode(&request); err != nil {
+		aH.handleError(w, fmt.Errorf("cannot parse delete request: %w", err), http.StatusBadRequest)
+		return
+	}
+	traceIDs, err := parseBatchTraceIDs(request.TraceIDs)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	err = aH.queryService.DeleteTraces(r.Context(), traceIDs)
+	if errors.Is(err, spanstore.ErrDeleteTracesNotSupported) {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	deleted := make([]string, len(traceIDs))
+	for i, traceID := range traceIDs {
+		deleted[i] = traceID.String()
+	}
+	aH.logger.Info("Traces deleted", zap.Strings("trace_ids", deleted))
+	structuredRes := structuredResponse{
+		Data:   deleted,
+		Total:  len(deleted),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) isDeleteTracesAuthorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(aH.deleteTracesToken)) == 1
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+const testDeleteTracesToken = "delete-token"
+
+func deleteTracesRequest(t *testing.T, url, token, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodDelete, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestLoadDeleteTracesToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(testDeleteTracesToken+"\n"), 0o600))
+	token, err := loadDeleteTracesToken(tokenFile)
+	require.NoError(t, err)
+	assert.Equal(t, testDeleteTracesToken, token)
+
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte(" \n"), 0o600))
+	_, err = loadDeleteTracesToken(emptyFile)
+	require.ErrorContains(t, err, "is empty")
+
+	_, err = loadDeleteTracesToken(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "failed to read delete traces token")
+}
+
+func TestDeleteTraces(t *testing.T) {
+	deleter := &spanstoremocks.TraceDeleter{}
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{TraceDeleter: deleter},
+		HandlerOptions.DeleteTraces(testDeleteTracesToken))
+	defer ts.server.Close()
+	traceIDs := []model.TraceID{model.NewTraceID(0, 1), mockTraceID}
+	deleter.On("DeleteTraces", mock.Anything, traceIDs).Return(nil).Once()
+
+	resp := deleteTracesRequest(t, ts.server.URL+"/api/traces", testDeleteTracesToken, `{"traceIDs": ["1", "1e240"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var response structuredResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, []any{"0000000000000001", "000000000001e240"}, response.Data)
+	assert.Equal(t, 2, response.Total)
+	deleter.AssertExpectations(t)
+}
+
+func TestDeleteTracesErrors(t *testing.T) {
+	deleter := &spanstoremocks.TraceDeleter{}
+	deleter.On("DeleteTraces", mock.Anything, []model.TraceID{model.NewTraceID(0, 2)}).Return(errors.New("storage error"))
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{TraceDeleter: deleter},
+		HandlerOptions.DeleteTraces(testDeleteTracesToken))
+	defer ts.server.Close()
+
+	testCases := []struct {
+		name   string
+		token  string
+		body   string
+		status int
+		err    string
+	}{
+		{name: "no token", body: `{"traceIDs": ["1"]}`, status: http.StatusUnauthorized, err: errDeleteTracesUnauthorized.Error()},
+		{name: "invalid token", token: "other", body: `{"traceIDs": ["1"]}`, status: http.StatusUnauthorized, err: errDeleteTracesUnauthorized.Error()},
+		{name: "invalid body", token: testDeleteTracesToken, body: `{"traceIDs": `, status: http.StatusBadRequest, err: "cannot parse delete request"},
+		{name: "no trace IDs", token: testDeleteTracesToken, body: `{"traceIDs": []}`, status: http.StatusBadRequest, err: errNoBatchTraceIDs.Error()},
+		{name: "invalid trace ID", token: testDeleteTracesToken, body: `{"traceIDs": ["xyz"]}`, status: http.StatusBadRequest, err: "cannot parse traceID param"},
+		{name: "storage error", token: testDeleteTracesToken, body: `{"traceIDs": ["2"]}`, status: http.StatusInternalServerError, err: "storage error"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := deleteTracesRequest(t, ts.server.URL+"/api/traces", tc.token, tc.body)
+			assert.Equal(t, tc.status, resp.StatusCode)
+			var response structuredResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			require.Len(t, response.Errors, 1)
+			assert.Contains(t, response.Errors[0].Msg, tc.err)
+		})
+	}
+}
+
+func TestDeleteTracesNotSupported(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.DeleteTraces(testDeleteTracesToken))
+	defer ts.server.Close()
+	resp := deleteTracesRequest(t, ts.server.URL+"/api/traces", testDeleteTracesToken, `{"traceIDs": ["1"]}`)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestDeleteTracesDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	resp := deleteTracesRequest(t, ts.server.URL+"/api/traces", testDeleteTracesToken, `{"traceIDs": ["1"]}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
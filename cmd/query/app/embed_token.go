@@ -0,0 +1,121 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	defaultEmbedTokenTTL = 15 * time.Minute
+	minEmbedSigningKey   = 32
+)
+
+var (
+	errEmbedTokenMalformed = errors.New("malformed embed token")
+	errEmbedTokenSignature = errors.New("invalid embed token signature")
+	errEmbedTokenExpired   = errors.New("embed token expired")
+	errEmbedTokenTrace     = errors.New("embed token does not grant access to this trace")
+)
+
+// embedTokenClaims is the payload of a signed embed token.
+type embedTokenClaims struct {
+	TraceID   string `json:"tid"`
+	Tenant    string `json:"ten,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// embedTokenSigner mints and verifies short-lived tokens granting read access
+// to a single trace. A token is base64url(claims) + "." + base64url(HMAC-SHA256(claims)).
+type embedTokenSigner struct {
+	key     []byte
+	ttl     time.Duration
+	timeNow func() time.Time
+}
+
+func newEmbedTokenSigner(key []byte, ttl time.Duration) (*embedTokenSigner, error) {
+	if len(key) < minEmbedSigningKey {
+		return nil, fmt.Errorf("embed signing key must be at least %d bytes, got %d", minEmbedSigningKey, len(key))
+	}
+	if ttl <= 0 {
+		ttl = defaultEmbedTokenTTL
+	}
+	return &embedTokenSigner{
+		key:     key,
+		ttl:     ttl,
+		timeNow: time.Now,
+	}, nil
+}
+
+// loadEmbedTokenSigner reads the signing key from a file. Surrounding whitespace is ignored.
+func loadEmbedTokenSigner(keyFile string, ttl time.Duration) (*embedTokenSigner, error) {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embed signing key: %w", err)
+	}
+	return newEmbedTokenSigner([]byte(strings.TrimSpace(string(key))), ttl)
+}
+
+// mint returns a token for the given trace and tenant, and its expiration time.
+func (s *embedTokenSigner) mint(traceID model.TraceID, tenant string) (string, time.Time, error) {
+	expiresAt := s.timeNow().Add(s.ttl).Truncate(time.Second)
+	payload, err := json.Marshal(embedTokenClaims{
+		TraceID:   traceID.String(),
+		Tenant:    tenant,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload)), expiresAt, nil
+}
+
+// verify checks the token signature and expiration, and that it was minted for traceID.
+// It returns the tenant recorded in the token.
+func (s *embedTokenSigner) verify(token string, traceID model.TraceID) (string, error) {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errEmbedTokenMalformed
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return "", errEmbedTokenMalformed
+	}
+	sig, err := enc.DecodeString(encSig)
+	if err != nil {
+		return "", errEmbedTokenMalformed
+	}
+	if !hmac.Equal(sig, s.sign(payload)) {
+		return "", errEmbedTokenSignature
+	}
+	var claims embedTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errEmbedTokenMalformed
+	}
+	if !s.timeNow().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return "", errEmbedTokenExpired
+	}
+	if claims.TraceID != traceID.String() {
+		return "", errEmbedTokenTrace
+	}
+	return claims.Tenant, nil
+}
+
+func (s *embedTokenSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
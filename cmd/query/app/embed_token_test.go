@@ -0,0 +1,164 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var testEmbedKey = []byte(strings.Repeat("k", minEmbedSigningKey))
+
+func newTestEmbedTokenSigner(t *testing.T) *embedTokenSigner {
+	signer, err := newEmbedTokenSigner(testEmbedKey, time.Minute)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestEmbedTokenSigner(t *testing.T) {
+	signer := newTestEmbedTokenSigner(t)
+	now := time.Unix(1700000000, 0)
+	signer.timeNow = func() time.Time { return now }
+	traceID := model.NewTraceID(0, 0x123456)
+
+	token, expiresAt, err := signer.mint(traceID, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+
+	tenant, err := signer.verify(token, traceID)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	_, err = signer.verify(token, model.NewTraceID(0, 1))
+	require.ErrorIs(t, err, errEmbedTokenTrace)
+
+	other, err := newEmbedTokenSigner([]byte(strings.Repeat("x", minEmbedSigningKey)), time.Minute)
+	require.NoError(t, err)
+	_, err = other.verify(token, traceID)
+	require.ErrorIs(t, err, errEmbedTokenSignature)
+
+	for _, malformed := range []string{"", "abc", "!!!.abc", "abc.!!!", "e30.abc"} {
+		_, err = signer.verify(malformed, traceID)
+		require.Error(t, err, malformed)
+	}
+
+	now = now.Add(time.Minute)
+	_, err = signer.verify(token, traceID)
+	require.ErrorIs(t, err, errEmbedTokenExpired)
+}
+
+func TestNewEmbedTokenSigner(t *testing.T) {
+	_, err := newEmbedTokenSigner([]byte("short"), time.Minute)
+	require.ErrorContains(t, err, "at least 32 bytes")
+
+	signer, err := newEmbedTokenSigner(testEmbedKey, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultEmbedTokenTTL, signer.ttl)
+}
+
+func TestLoadEmbedTokenSigner(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, append(testEmbedKey, '\n'), 0o600))
+
+	signer, err := loadEmbedTokenSigner(keyFile, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, testEmbedKey, signer.key)
+
+	_, err = loadEmbedTokenSigner(filepath.Join(t.TempDir(), "missing"), time.Minute)
+	require.ErrorContains(t, err, "failed to read embed signing key")
+}
+
+func TestEmbedTokenEndpoints(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.EmbedTokens(newTestEmbedTokenSigner(t)))
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.Anything, mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Return(mockTrace, nil)
+
+	var response struct {
+		Data embedToken `json:"data"`
+	}
+	require.NoError(t, postJSON(ts.server.URL+`/api/embed/tokens/123456`, nil, &response))
+	assert.NotEmpty(t, response.Data.Token)
+	assert.Equal(t, "/api/embed/traces/0000000000123456?token="+response.Data.Token, response.Data.URL)
+
+	var traceResponse structuredTraceResponse
+	require.NoError(t, getJSON(ts.server.URL+response.Data.URL, &traceResponse))
+	assert.Empty(t, traceResponse.Errors)
+	require.Len(t, traceResponse.Traces, 1)
+
+	testCases := []struct {
+		name   string
+		url    string
+		status string
+	}{
+		{name: "missing token", url: "/api/embed/traces/123456", status: "401 error from server"},
+		{name: "other trace", url: "/api/embed/traces/654321?token=" + response.Data.Token, status: "401 error from server"},
+		{name: "bad trace ID", url: "/api/embed/traces/xyz?token=" + response.Data.Token, status: "400 error from server"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := getJSON(ts.server.URL+tc.url, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.status)
+		})
+	}
+	err := postJSON(ts.server.URL+`/api/embed/tokens/xyz`, nil, nil)
+	require.ErrorContains(t, err, "400 error from server")
+}
+
+func TestEmbedTokenEndpointsDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	err := postJSON(ts.server.URL+`/api/embed/tokens/123456`, nil, nil)
+	require.ErrorContains(t, err, "404 error from server")
+	err = getJSON(ts.server.URL+`/api/embed/traces/123456?token=abc`, nil)
+	require.ErrorContains(t, err, "404 error from server")
+}
+
+func TestEmbedTokenTenancy(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	reader := &spanstoremocks.Reader{}
+	qs := querysvc.NewQueryService(reader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, tm, HandlerOptions.EmbedTokens(newTestEmbedTokenSigner(t))).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	var tenants []string
+	reader.On("GetTrace", mock.Anything, mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Run(func(args mock.Arguments) {
+			tenants = append(tenants, tenancy.GetTenant(args.Get(0).(context.Context)))
+		}).
+		Return(mockTrace, nil)
+
+	var response struct {
+		Data embedToken `json:"data"`
+	}
+	err := postJSON(server.URL+`/api/embed/tokens/123456`, nil, &response)
+	require.ErrorContains(t, err, "401 error from server: missing tenant header")
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+`/api/embed/tokens/123456`, nil)
+	require.NoError(t, err)
+	require.NoError(t, execJSON(req, map[string]string{tm.Header: "acme"}, &response))
+
+	// the embedded view sends no tenant header, the tenant comes from the token
+	require.NoError(t, getJSON(server.URL+response.Data.URL, nil))
+	assert.Equal(t, []string{"acme"}, tenants)
+}
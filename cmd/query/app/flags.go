@@ -32,6 +32,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
@@ -41,6 +42,7 @@ import (
 const (
 	queryHTTPHostPort          = "query.http-server.host-port"
 	queryGRPCHostPort          = "query.grpc-server.host-port"
+	queryGRPCCompression       = "query.grpc-server.compression"
 	queryBasePath              = "query.base-path"
 	queryStaticFiles           = "query.static-files"
 	queryLogStaticAssetsAccess = "query.log-static-assets-access"
@@ -49,14 +51,43 @@ const (
 	queryAdditionalHeaders     = "query.additional-headers"
 	queryMaxClockSkewAdjust    = "query.max-clock-skew-adjustment"
 	queryEnableTracing         = "query.enable-tracing"
+	queryEnableRawSpans        = "query.enable-raw-spans"
+	queryEnableLiveTail        = "query.enable-live-tail"
+	queryEmbedFrameAncestors   = "query.embed.frame-ancestors"
+	queryEmbedSigningKeyFile   = "query.embed.signing-key-file"
+	queryEmbedTokenTTL         = "query.embed.token-ttl"
+	queryLinksConfig           = "query.links-config"
+	queryTimePresetsConfig     = "query.time-presets-config"
+	queryDeleteTracesTokenFile = "query.delete-traces.token-file"
+
+	queryRedactionKeys              = "query.redaction.keys"
+	queryRedactionRoleHeader        = "query.redaction.role-header"
+	queryRedactionPrivilegedRoles   = "query.redaction.privileged-roles"
+	queryRedactionPrivilegedTenants = "query.redaction.privileged-tenants"
+
+	queryDependenciesFallbackMaxTraces = "query.dependencies-fallback.max-traces"
+	queryDependenciesFallbackCacheTTL  = "query.dependencies-fallback.cache-ttl"
+
+	queryLookupCacheTTL        = "query.lookup-cache.ttl"
+	queryLookupCacheMaxEntries = "query.lookup-cache.max-entries"
+
+	queryCompletenessWindow = "query.completeness-window"
+
+	defaultRedactionRoleHeader = "x-jaeger-role"
 )
 
+var corsFlagsConfig = corscfg.Flags{
+	Prefix: "query",
+}
+
 var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
-	Prefix: "query.grpc",
+	Prefix:       "query.grpc",
+	EnableSPIFFE: true,
 }
 
 var tlsHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
-	Prefix: "query.http",
+	Prefix:       "query.http",
+	EnableSPIFFE: true,
 }
 
 // QueryOptionsStaticAssets contains configuration for handling static assets
@@ -67,6 +98,16 @@ type QueryOptionsStaticAssets struct {
 	LogAccess bool `valid:"optional" mapstructure:"log_access"`
 }
 
+// QueryOptionsEmbed contains configuration for embedding the UI in other web applications
+type QueryOptionsEmbed struct {
+	// FrameAncestors lists the origins allowed to embed the UI in a frame (CSP frame-ancestors)
+	FrameAncestors []string `valid:"optional" mapstructure:"frame_ancestors"`
+	// SigningKeyFile is the path to the key used to sign embed tokens; embed tokens are disabled when empty
+	SigningKeyFile string `valid:"optional" mapstructure:"signing_key_file"`
+	// TokenTTL is how long an embed token remains valid
+	TokenTTL time.Duration `valid:"optional" mapstructure:"token_ttl"`
+}
+
 // QueryOptionsBase holds configuration for query service shared with jaeger(v2)
 type QueryOptionsBase struct {
 	// BasePath is the base path for all HTTP routes
@@ -86,6 +127,31 @@ type QueryOptionsBase struct {
 	Tenancy tenancy.Options
 	// EnableTracing determines whether traces will be emitted by jaeger-query.
 	EnableTracing bool
+	// EnableRawSpans exposes the HTTP endpoint returning spans as stored by the span storage.
+	EnableRawSpans bool
+	// EnableLiveTail exposes the HTTP endpoint streaming incoming spans, when spans are received in the same process.
+	EnableLiveTail bool
+	// CORS configures cross-origin access to the HTTP API
+	CORS corscfg.Options `valid:"optional" mapstructure:"cors"`
+	// Embed configures embedding of the UI and signed per-trace embed tokens
+	Embed QueryOptionsEmbed `valid:"optional" mapstructure:"embed"`
+	// LinksConfig is the path to a file with the templates of the deep links to external systems resolved for spans
+	LinksConfig string `valid:"optional" mapstructure:"links_config"`
+	// TimePresetsConfig is the path to a file with the named search windows and business hours, per tenant
+	TimePresetsConfig string `valid:"optional" mapstructure:"time_presets_config"`
+	// GRPCCompression is the compressor of the gRPC responses sent to clients accepting it, e.g. gzip or zstd
+	GRPCCompression string `valid:"optional" mapstructure:"grpc_compression"`
+	// DeleteTracesTokenFile is the path to a file with the bearer token authorizing the deletion of traces;
+	// deleting traces is disabled when empty
+	DeleteTracesTokenFile string `valid:"optional" mapstructure:"delete_traces_token_file"`
+	// Redaction configures the masking of span attributes in the traces returned to unprivileged callers
+	Redaction querysvc.RedactionOptions `valid:"optional" mapstructure:"redaction"`
+	// DependenciesFallback configures the computation of the dependencies from the spans when the dependency storage has none
+	DependenciesFallback querysvc.DependenciesFallbackOptions `valid:"optional" mapstructure:"dependencies_fallback"`
+	// LookupCache configures the caching of the services and operations of the span storage
+	LookupCache querysvc.LookupCacheOptions `valid:"optional" mapstructure:"lookup_cache"`
+	// CompletenessWindow is how long after its last span a trace is hinted to be still receiving spans
+	CompletenessWindow time.Duration `valid:"optional" mapstructure:"completeness_window"`
 }
 
 // QueryOptions holds configuration for query service
@@ -114,6 +180,25 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Bool(queryTokenPropagation, false, "Allow propagation of bearer token to be used by storage plugins")
 	flagSet.Duration(queryMaxClockSkewAdjust, 0, "The maximum delta by which span timestamps may be adjusted in the UI due to clock skew; set to 0s to disable clock skew adjustments")
 	flagSet.Bool(queryEnableTracing, false, "Enables emitting jaeger-query traces")
+	flagSet.Bool(queryEnableRawSpans, false, "Enables the /api/traces/{traceID}/spans/{spanID}/raw endpoint returning spans as stored by the span storage (for administrators diagnosing storage mappings)")
+	flagSet.Bool(queryEnableLiveTail, false, "Enables the /api/live/spans endpoint streaming incoming spans matching a filter as Server-Sent Events (only in all-in-one, where the collector runs in the same process)")
+	flagSet.String(queryEmbedFrameAncestors, "", "Comma-separated origins allowed to embed the UI in a frame, sent as Content-Security-Policy frame-ancestors. See https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Security-Policy/frame-ancestors")
+	flagSet.String(queryEmbedSigningKeyFile, "", "Path to a file with the key (at least 32 bytes) used to sign embed tokens granting read access to a single trace; embed tokens are disabled when empty")
+	flagSet.Duration(queryEmbedTokenTTL, defaultEmbedTokenTTL, "How long an embed token remains valid")
+	flagSet.String(queryLinksConfig, "", "The path to a JSON file with the templates of the links to external systems (e.g. logs or metrics) resolved for spans by the /api/traces/{traceID}/spans/{spanID}/links endpoint; the endpoint is disabled when empty")
+	flagSet.String(queryTimePresetsConfig, "", "The path to a JSON file with the named search windows (e.g. yesterday's business hours), time zone, locale and business hours, optionally per tenant, resolved by the /api/time-presets endpoint; the endpoint is disabled when empty")
+	flagSet.String(queryGRPCCompression, "", "The compression (gzip or zstd) of the gRPC responses, used for clients that accept it; responses are not compressed when empty")
+	flagSet.String(queryDeleteTracesTokenFile, "", "Path to a file with the bearer token authorizing the DELETE /api/traces endpoint, which deletes traces from the span storage (e.g. for data subject deletion requests); the endpoint is disabled when empty")
+	flagSet.String(queryRedactionKeys, "", "Comma-separated keys of the span, process and log attributes whose values are masked in the traces returned to unprivileged callers, e.g. user.email,http.request.header.*; a key ending with * masks the keys starting with the part before it")
+	flagSet.String(queryRedactionRoleHeader, defaultRedactionRoleHeader, "The HTTP header or gRPC metadata carrying the role of the caller, which must be set by a trusted proxy authenticating the callers")
+	flagSet.String(queryRedactionPrivilegedRoles, "", "Comma-separated roles of the callers seeing the attributes listed in "+queryRedactionKeys+" unmasked")
+	flagSet.String(queryRedactionPrivilegedTenants, "", "Comma-separated tenants whose callers see the attributes listed in "+queryRedactionKeys+" unmasked")
+	flagSet.Int(queryDependenciesFallbackMaxTraces, 0, "The maximum number of traces of the time window the dependencies are computed from when the dependency storage has none (e.g. no Spark job aggregates them), split evenly between the services; the dependencies are not computed when 0")
+	flagSet.Duration(queryDependenciesFallbackCacheTTL, time.Minute, "How long the dependencies computed from the traces are reused for the requests of a similar time window; they are not cached when 0")
+	flagSet.Duration(queryLookupCacheTTL, 0, "How long the services and operations returned by the span storage are cached, as the UI looks them up on every page load; they are not cached when 0")
+	flagSet.Int(queryLookupCacheMaxEntries, 1000, "The maximum number of services and operations lookups (one per tenant, service and span kind) kept in the cache")
+	flagSet.Duration(queryCompletenessWindow, 30*time.Second, "How long after its last span was received a trace is hinted to be still receiving spans in the responses of GetTrace, so that clients can refresh it; never when 0")
+	corsFlagsConfig.AddFlags(flagSet)
 	tlsGRPCFlagsConfig.AddFlags(flagSet)
 	tlsHTTPFlagsConfig.AddFlags(flagSet)
 }
@@ -122,6 +207,7 @@ func AddFlags(flagSet *flag.FlagSet) {
 func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*QueryOptions, error) {
 	qOpts.HTTPHostPort = v.GetString(queryHTTPHostPort)
 	qOpts.GRPCHostPort = v.GetString(queryGRPCHostPort)
+	qOpts.GRPCCompression = v.GetString(queryGRPCCompression)
 	tlsGrpc, err := tlsGRPCFlagsConfig.InitFromViper(v)
 	if err != nil {
 		return qOpts, fmt.Errorf("failed to process gRPC TLS options: %w", err)
@@ -148,6 +234,30 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*Q
 	}
 	qOpts.Tenancy = tenancy.InitFromViper(v)
 	qOpts.EnableTracing = v.GetBool(queryEnableTracing)
+	qOpts.EnableRawSpans = v.GetBool(queryEnableRawSpans)
+	qOpts.EnableLiveTail = v.GetBool(queryEnableLiveTail)
+	qOpts.CORS = corsFlagsConfig.InitFromViper(v)
+	qOpts.Embed.FrameAncestors = strings.Split(strings.ReplaceAll(v.GetString(queryEmbedFrameAncestors), " ", ""), ",")
+	qOpts.Embed.SigningKeyFile = v.GetString(queryEmbedSigningKeyFile)
+	qOpts.Embed.TokenTTL = v.GetDuration(queryEmbedTokenTTL)
+	qOpts.LinksConfig = v.GetString(queryLinksConfig)
+	qOpts.TimePresetsConfig = v.GetString(queryTimePresetsConfig)
+	qOpts.DeleteTracesTokenFile = v.GetString(queryDeleteTracesTokenFile)
+	qOpts.Redaction = querysvc.RedactionOptions{
+		Keys:              splitList(v.GetString(queryRedactionKeys)),
+		RoleHeader:        v.GetString(queryRedactionRoleHeader),
+		PrivilegedRoles:   splitList(v.GetString(queryRedactionPrivilegedRoles)),
+		PrivilegedTenants: splitList(v.GetString(queryRedactionPrivilegedTenants)),
+	}
+	qOpts.DependenciesFallback = querysvc.DependenciesFallbackOptions{
+		MaxTraces: v.GetInt(queryDependenciesFallbackMaxTraces),
+		CacheTTL:  v.GetDuration(queryDependenciesFallbackCacheTTL),
+	}
+	qOpts.LookupCache = querysvc.LookupCacheOptions{
+		TTL:        v.GetDuration(queryLookupCacheTTL),
+		MaxEntries: v.GetInt(queryLookupCacheMaxEntries),
+	}
+	qOpts.CompletenessWindow = v.GetDuration(queryCompletenessWindow)
 	return qOpts, nil
 }
 
@@ -157,12 +267,36 @@ func (qOpts *QueryOptions) BuildQueryServiceOptions(storageFactory storage.Facto
 	if !opts.InitArchiveStorage(storageFactory, logger) {
 		logger.Info("Archive storage not initialized")
 	}
+	if qOpts.DeleteTracesTokenFile != "" && !opts.InitTraceDeleter(storageFactory, logger) {
+		logger.Info("Deleting traces not initialized")
+	}
 
 	opts.Adjuster = adjuster.Sequence(querysvc.StandardAdjusters(qOpts.MaxClockSkewAdjust)...)
+	if qOpts.Redaction.Enabled() {
+		opts.Redactor = querysvc.NewRedactor(qOpts.Redaction)
+	}
+	if qOpts.DependenciesFallback.Enabled() {
+		opts.DependenciesFallback = querysvc.NewDependenciesFallback(qOpts.DependenciesFallback)
+	}
+	if qOpts.LookupCache.Enabled() {
+		opts.LookupCache = querysvc.NewLookupCache(qOpts.LookupCache, nil)
+	}
+	opts.CompletenessWindow = qOpts.CompletenessWindow
 
 	return opts
 }
 
+// splitList returns the non-empty items of a comma-separated list.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // stringSliceAsHeader parses a slice of strings and returns a http.Header.
 // Each string in the slice is expected to be in the format "key: value"
 func stringSliceAsHeader(slice []string) (http.Header, error) {
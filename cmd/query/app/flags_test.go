@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/storage/mocks"
@@ -43,6 +44,16 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"--query.additional-headers=access-control-allow-origin:blerg",
 		"--query.additional-headers=whatever:thing",
 		"--query.max-clock-skew-adjustment=10s",
+		"--query.enable-raw-spans=true",
+		"--query.enable-live-tail=true",
+		"--query.cors.allowed-origins=https://app.example.com, https://*.example.org",
+		"--query.embed.frame-ancestors='self', https://app.example.com",
+		"--query.embed.signing-key-file=/etc/jaeger/embed.key",
+		"--query.embed.token-ttl=5m",
+		"--query.links-config=/etc/jaeger/links.json",
+		"--query.time-presets-config=/etc/jaeger/time-presets.json",
+		"--query.grpc-server.compression=gzip",
+		"--query.delete-traces.token-file=/etc/jaeger/delete.token",
 	})
 	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
 	require.NoError(t, err)
@@ -57,6 +68,16 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"Whatever":                    []string{"thing"},
 	}, qOpts.AdditionalHeaders)
 	assert.Equal(t, 10*time.Second, qOpts.MaxClockSkewAdjust)
+	assert.True(t, qOpts.EnableRawSpans)
+	assert.True(t, qOpts.EnableLiveTail)
+	assert.Equal(t, []string{"https://app.example.com", "https://*.example.org"}, qOpts.CORS.AllowedOrigins)
+	assert.Equal(t, []string{"'self'", "https://app.example.com"}, qOpts.Embed.FrameAncestors)
+	assert.Equal(t, "/etc/jaeger/embed.key", qOpts.Embed.SigningKeyFile)
+	assert.Equal(t, 5*time.Minute, qOpts.Embed.TokenTTL)
+	assert.Equal(t, "/etc/jaeger/links.json", qOpts.LinksConfig)
+	assert.Equal(t, "/etc/jaeger/time-presets.json", qOpts.TimePresetsConfig)
+	assert.Equal(t, "gzip", qOpts.GRPCCompression)
+	assert.Equal(t, "/etc/jaeger/delete.token", qOpts.DeleteTracesTokenFile)
 }
 
 func TestQueryBuilderBadHeadersFlags(t *testing.T) {
@@ -126,6 +147,98 @@ func TestBuildQueryServiceOptions(t *testing.T) {
 	assert.NotNil(t, qSvcOpts.ArchiveSpanWriter)
 }
 
+func TestBuildQueryServiceOptionsDeleteTraces(t *testing.T) {
+	writer := struct {
+		*spanstore_mocks.Writer
+		*spanstore_mocks.TraceDeleter
+	}{
+		&spanstore_mocks.Writer{},
+		&spanstore_mocks.TraceDeleter{},
+	}
+	factory := &mocks.Factory{}
+	factory.On("CreateSpanWriter").Return(writer, nil)
+
+	qOpts := &QueryOptions{}
+	qSvcOpts := qOpts.BuildQueryServiceOptions(factory, zap.NewNop())
+	assert.Nil(t, qSvcOpts.TraceDeleter)
+	factory.AssertNotCalled(t, "CreateSpanWriter")
+
+	qOpts.DeleteTracesTokenFile = "/etc/jaeger/delete.token"
+	qSvcOpts = qOpts.BuildQueryServiceOptions(factory, zap.NewNop())
+	assert.Equal(t, writer, qSvcOpts.TraceDeleter)
+}
+
+func TestQueryOptionsRedaction(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--query.redaction.keys=user.email, http.request.header.*",
+		"--query.redaction.privileged-roles=admin,auditor",
+		"--query.redaction.privileged-tenants=security",
+	}))
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, querysvc.RedactionOptions{
+		Keys:              []string{"user.email", "http.request.header.*"},
+		RoleHeader:        defaultRedactionRoleHeader,
+		PrivilegedRoles:   []string{"admin", "auditor"},
+		PrivilegedTenants: []string{"security"},
+	}, qOpts.Redaction)
+	assert.NotNil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).Redactor)
+
+	qOpts.Redaction.Keys = nil
+	assert.Nil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).Redactor)
+}
+
+func TestQueryOptionsDependenciesFallback(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--query.dependencies-fallback.max-traces=500",
+	}))
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, querysvc.DependenciesFallbackOptions{
+		MaxTraces: 500,
+		CacheTTL:  time.Minute,
+	}, qOpts.DependenciesFallback)
+	assert.NotNil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).DependenciesFallback)
+
+	qOpts.DependenciesFallback.MaxTraces = 0
+	assert.Nil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).DependenciesFallback)
+}
+
+func TestQueryOptionsLookupCache(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).LookupCache)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--query.lookup-cache.ttl=30s",
+		"--query.lookup-cache.max-entries=100",
+	}))
+	qOpts, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, querysvc.LookupCacheOptions{
+		TTL:        30 * time.Second,
+		MaxEntries: 100,
+	}, qOpts.LookupCache)
+	assert.NotNil(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).LookupCache)
+}
+
+func TestQueryOptionsCompletenessWindow(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).CompletenessWindow)
+
+	require.NoError(t, command.ParseFlags([]string{"--query.completeness-window=0"}))
+	qOpts, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Zero(t, qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop()).CompletenessWindow)
+}
+
 func TestQueryOptionsPortAllocationFromFlags(t *testing.T) {
 	flagPortCases := []struct {
 		name                 string
@@ -0,0 +1,75 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	spanRefParam = "span"
+	// maxSpanRefs is the maximum number of spans of a request.
+	maxSpanRefs = 100
+)
+
+var errNoSpanRefs = errors.New("no spans requested")
+
+// getSpans implements the REST API /spans?span={trace-id}:{span-id}&span=...
+// It responds with the requested spans grouped by trace, without loading the whole traces
+// if the span storage supports it, e.g. to follow the links of a span to other traces.
+// The spans that are not found are omitted.
+func (aH *APIHandler) getSpans(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseSpanRefs(r.URL.Query()[spanRefParam])
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	spans, err := aH.queryService.GetSpans(r.Context(), ids)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	var traces []*model.Trace
+	tracesByID := make(map[model.TraceID]*model.Trace)
+	for _, span := range spans {
+		trace, ok := tracesByID[span.TraceID]
+		if !ok {
+			trace = &model.Trace{}
+			tracesByID[span.TraceID] = trace
+			traces = append(traces, trace)
+		}
+		trace.Spans = append(trace.Spans, span)
+	}
+	structuredRes := aH.tracesToResponse(traces, false, nil)
+	structuredRes.Total = len(spans)
+	aH.writeJSON(w, r, structuredRes)
+}
+
+func parseSpanRefs(refs []string) ([]spanstore.TraceSpanID, error) {
+	if len(refs) == 0 {
+		return nil, errNoSpanRefs
+	}
+	if len(refs) > maxSpanRefs {
+		return nil, fmt.Errorf("too many spans requested: %d, the maximum is %d", len(refs), maxSpanRefs)
+	}
+	ids := make([]spanstore.TraceSpanID, len(refs))
+	for i, ref := range refs {
+		traceID, spanID, ok := strings.Cut(ref, ":")
+		if !ok {
+			return nil, fmt.Errorf("cannot parse span param %q, expected traceID:spanID", ref)
+		}
+		var err error
+		if ids[i].TraceID, err = model.TraceIDFromString(traceID); err != nil {
+			return nil, fmt.Errorf("cannot parse traceID of span param %q: %w", ref, err)
+		}
+		if ids[i].SpanID, err = model.SpanIDFromString(spanID); err != nil {
+			return nil, fmt.Errorf("cannot parse spanID of span param %q: %w", ref, err)
+		}
+	}
+	return ids, nil
+}
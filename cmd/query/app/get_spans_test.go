@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestGetSpans(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	otherTraceID := model.NewTraceID(0, 1)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID}).Return(mockTrace, nil).Once()
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: otherTraceID}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	var response structuredTraceResponse
+	err := getJSON(ts.server.URL+`/api/spans?span=1e240:2&span=1:3&span=1e240:1`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, 2, response.Total)
+	require.Len(t, response.Traces, 1)
+	assert.Equal(t, "000000000001e240", string(response.Traces[0].TraceID))
+	require.Len(t, response.Traces[0].Spans, 2)
+	ts.spanReader.AssertExpectations(t)
+}
+
+func TestGetSpansStorageError(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID}).Return(nil, errStorage).Once()
+
+	err := getJSON(ts.server.URL+`/api/spans?span=1e240:2`, nil)
+	require.ErrorContains(t, err, "500 error from server")
+	require.ErrorContains(t, err, errStorageMsg)
+}
+
+func TestGetSpansInvalid(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	tooMany := strings.Repeat("&span=1:1", maxSpanRefs+1)
+
+	testCases := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{name: "no spans", query: "", err: errNoSpanRefs.Error()},
+		{name: "too many spans", query: "?" + tooMany[1:], err: "too many spans requested: 101, the maximum is 100"},
+		{name: "missing span ID", query: "?span=1", err: `cannot parse span param \"1\"`},
+		{name: "invalid trace ID", query: "?span=xyz:1", err: `cannot parse traceID of span param \"xyz:1\"`},
+		{name: "invalid span ID", query: "?span=1:xyz", err: `cannot parse spanID of span param \"1:xyz\"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := getJSON(ts.server.URL+`/api/spans`+tc.query, nil)
+			require.ErrorContains(t, err, "400 error from server")
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
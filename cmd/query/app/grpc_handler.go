@@ -17,10 +17,12 @@ package app
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
@@ -38,6 +40,12 @@ const (
 	maxSpanCountInChunk = 10
 
 	msgTraceNotFound = "trace not found"
+
+	// the headers of the GetTrace responses hinting whether the trace is complete,
+	// see querysvc.TraceCompleteness
+	traceCompleteHeader           = "jaeger-trace-complete"
+	traceStillReceivingHeader     = "jaeger-trace-still-receiving"
+	traceMissingParentSpansHeader = "jaeger-trace-missing-parent-spans"
 )
 
 var (
@@ -100,7 +108,11 @@ func (g *GRPCHandler) GetTrace(r *api_v2.GetTraceRequest, stream api_v2.QuerySer
 	if r.TraceID == (model.TraceID{}) {
 		return errUninitializedTraceID
 	}
-	trace, err := g.queryService.GetTrace(stream.Context(), r.TraceID)
+	query, err := newGetTraceParameters(r.TraceID, r.StartTime, r.EndTime)
+	if err != nil {
+		return err
+	}
+	trace, err := g.queryService.GetTrace(stream.Context(), query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		g.logger.Warn(msgTraceNotFound, zap.Stringer("id", r.TraceID), zap.Error(err))
 		return status.Errorf(codes.NotFound, "%s: %v", msgTraceNotFound, err)
@@ -109,6 +121,14 @@ func (g *GRPCHandler) GetTrace(r *api_v2.GetTraceRequest, stream api_v2.QuerySer
 		g.logger.Error("failed to fetch spans from the backend", zap.Error(err))
 		return status.Errorf(codes.Internal, "failed to fetch spans from the backend: %v", err)
 	}
+	completeness := g.queryService.TraceCompleteness(trace)
+	if err := stream.SetHeader(metadata.Pairs(
+		traceCompleteHeader, strconv.FormatBool(completeness.Complete()),
+		traceStillReceivingHeader, strconv.FormatBool(completeness.StillReceiving),
+		traceMissingParentSpansHeader, strconv.Itoa(completeness.MissingParentSpans),
+	)); err != nil {
+		g.logger.Warn("failed to set the trace completeness headers", zap.Error(err))
+	}
 	return g.sendSpanChunks(trace.Spans, stream.Send)
 }
 
@@ -120,7 +140,11 @@ func (g *GRPCHandler) ArchiveTrace(ctx context.Context, r *api_v2.ArchiveTraceRe
 	if r.TraceID == (model.TraceID{}) {
 		return nil, errUninitializedTraceID
 	}
-	err := g.queryService.ArchiveTrace(ctx, r.TraceID)
+	query, err := newGetTraceParameters(r.TraceID, r.StartTime, r.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	err = g.queryService.ArchiveTrace(ctx, query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		g.logger.Warn(msgTraceNotFound, zap.Stringer("id", r.TraceID), zap.Error(err))
 		return nil, status.Errorf(codes.NotFound, "%s: %v", msgTraceNotFound, err)
@@ -133,6 +157,24 @@ func (g *GRPCHandler) ArchiveTrace(ctx context.Context, r *api_v2.ArchiveTraceRe
 	return &api_v2.ArchiveTraceResponse{}, nil
 }
 
+// newGetTraceParameters builds the storage query for a trace ID and the optional
+// time window supplied in the request.
+func newGetTraceParameters(traceID model.TraceID, startTime, endTime *time.Time) (spanstore.GetTraceParameters, error) {
+	query := spanstore.GetTraceParameters{
+		TraceID: traceID,
+	}
+	if startTime != nil {
+		query.StartTime = *startTime
+	}
+	if endTime != nil {
+		query.EndTime = *endTime
+	}
+	if err := query.Validate(); err != nil {
+		return spanstore.GetTraceParameters{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return query, nil
+}
+
 // FindTraces is the gRPC handler to fetch traces based on TraceQueryParameters.
 func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.QueryService_FindTracesServer) error {
 	if r == nil {
@@ -359,3 +359,52 @@ func TestGetDependenciesLookbackParsingFailure(t *testing.T) {
 	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&service=testing&lookback=shazbot", &response)
 	require.Error(t, err)
 }
+
+func TestGetDependenciesDiffSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	baseEndTs := endTs.Add(-7 * 24 * time.Hour)
+	ts.dependencyReader.On("GetDependencies", mock.Anything, baseEndTs, time.Hour).Return([]model.DependencyLink{
+		{Parent: "killer", Child: "queen", CallCount: 12},
+		{Parent: "killer", Child: "bishop", CallCount: 3},
+		{Parent: "rook", Child: "pawn", CallCount: 1},
+	}, nil).Once()
+	ts.dependencyReader.On("GetDependencies", mock.Anything, endTs, time.Hour).Return([]model.DependencyLink{
+		{Parent: "killer", Child: "queen", CallCount: 2},
+		{Parent: "killer", Child: "knight", CallCount: 5},
+		{Parent: "rook", Child: "pawn", CallCount: 1},
+	}, nil).Once()
+
+	var response struct {
+		Data dependenciesDiff `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/dependencies/diff?endTs=1476374248550&lookback=3600000&baseEndTs=1475769448550&service=killer", &response)
+	require.NoError(t, err)
+	assert.Equal(t, dependenciesDiff{
+		Base: []ui.DependencyLink{
+			{Parent: "killer", Child: "bishop", CallCount: 3},
+			{Parent: "killer", Child: "queen", CallCount: 12},
+		},
+		Current: []ui.DependencyLink{
+			{Parent: "killer", Child: "knight", CallCount: 5},
+			{Parent: "killer", Child: "queen", CallCount: 2},
+		},
+		Added:   []dependencyLinkDiff{{Parent: "killer", Child: "knight", CallCount: 5, CallCountDelta: 5}},
+		Removed: []dependencyLinkDiff{{Parent: "killer", Child: "bishop", BaseCallCount: 3, CallCountDelta: -3}},
+		Changed: []dependencyLinkDiff{{Parent: "killer", Child: "queen", BaseCallCount: 12, CallCount: 2, CallCountDelta: -10}},
+	}, response.Data)
+}
+
+func TestGetDependenciesDiffFailure(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.dependencyReader.On("GetDependencies", mock.Anything, mock.Anything, mock.Anything).Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/dependencies/diff?endTs=1476374248550", &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+
+	err = getJSON(ts.server.URL+"/api/dependencies/diff?baseEndTs=shazbot", &response)
+	require.ErrorContains(t, err, "400 error")
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 )
 
 // HandlerOption is a function that sets some option on the APIHandler
@@ -74,3 +75,50 @@ func (handlerOptions) MetricsQueryService(mqs querysvc.MetricsQueryService) Hand
 		apiHandler.metricsQueryService = mqs
 	}
 }
+
+// RawSpans creates a HandlerOption that exposes the endpoint returning spans as stored by the span storage.
+func (handlerOptions) RawSpans(enabled bool) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.rawSpansEnabled = enabled
+	}
+}
+
+// EmbedTokens creates a HandlerOption that exposes the endpoints minting and accepting
+// signed tokens granting read access to a single trace.
+func (handlerOptions) EmbedTokens(signer *embedTokenSigner) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.embedTokens = signer
+	}
+}
+
+// LiveTail creates a HandlerOption that exposes the endpoint streaming spans
+// received by the collector as they arrive.
+func (handlerOptions) LiveTail(broadcaster *livetail.Broadcaster) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.liveTail = broadcaster
+	}
+}
+
+// DeleteTraces creates a HandlerOption that exposes the endpoint deleting traces,
+// authorized by the given bearer token.
+func (handlerOptions) DeleteTraces(token string) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.deleteTracesToken = token
+	}
+}
+
+// Links creates a HandlerOption that exposes the endpoint resolving the configured
+// deep links to external systems for a span.
+func (handlerOptions) Links(resolver *linkResolver) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.links = resolver
+	}
+}
+
+// TimePresets creates a HandlerOption that exposes the endpoint resolving the configured
+// named search windows, e.g. yesterday's business hours.
+func (handlerOptions) TimePresets(resolver *timePresetsResolver) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.timePresets = resolver
+	}
+}
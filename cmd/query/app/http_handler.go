@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strconv"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
 	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
@@ -46,17 +48,37 @@ import (
 
 const (
 	traceIDParam          = "traceID"
+	spanIDParam           = "spanID"
 	endTsParam            = "endTs"
 	lookbackParam         = "lookback"
+	baseEndTsParam        = "baseEndTs"
+	baseLookbackParam     = "baseLookback"
+	baseTraceIDParam      = "baseTraceID"
 	stepParam             = "step"
 	rateParam             = "ratePer"
 	quantileParam         = "quantile"
 	groupByOperationParam = "groupByOperation"
+	embedTokenParam       = "token"
+	objectiveParam        = "objective"
+	windowParam           = "window"
+	nameSearchParam       = "search"
+	nameMatchParam        = "match"
+	withStatsParam        = "withStats"
 
 	defaultAPIPrefix  = "api"
 	prettyPrintIndent = "    "
 )
 
+var (
+	errSpanNotFound          = errors.New("span not found")
+	errStreamingNotSupported = errors.New("streaming not supported")
+	errSampleTraceIDs        = fmt.Errorf("parameter '%s' is not supported when sampling traces", traceIDParam)
+)
+
+// liveTailKeepAlive is how often a comment is sent on idle live tail streams
+// so that proxies do not close them.
+var liveTailKeepAlive = 15 * time.Second
+
 // HTTPHandler handles http requests
 type HTTPHandler interface {
 	RegisterRoutes(router *mux.Router)
@@ -89,6 +111,12 @@ type APIHandler struct {
 	tenancyMgr          *tenancy.Manager
 	basePath            string
 	apiPrefix           string
+	rawSpansEnabled     bool
+	embedTokens         *embedTokenSigner
+	liveTail            *livetail.Broadcaster
+	links               *linkResolver
+	timePresets         *timePresetsResolver
+	deleteTracesToken   string
 	logger              *zap.Logger
 	tracer              *jtracer.JTracer
 }
@@ -121,20 +149,49 @@ func NewAPIHandler(queryService *querysvc.QueryService, tm *tenancy.Manager, opt
 
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
+	aH.handleFunc(router, aH.sampleTraces, "/traces/sample").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.compareTraces, "/traces/compare").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getTracesBatch, "/traces/batch").Methods(http.MethodPost)
 	aH.handleFunc(router, aH.getTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.traceExists, "/traces/{%s}", traceIDParam).Methods(http.MethodHead)
+	aH.handleFunc(router, aH.getTraceGraph, "/traces/{%s}/graph", traceIDParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.archiveTrace, "/archive/{%s}", traceIDParam).Methods(http.MethodPost)
+	aH.handleFunc(router, aH.getSpans, "/spans").Methods(http.MethodGet)
+	if aH.rawSpansEnabled {
+		aH.handleFunc(router, aH.getRawSpans, "/traces/{%s}/spans/{%s}/raw", traceIDParam, spanIDParam).Methods(http.MethodGet)
+	}
+	if aH.embedTokens != nil {
+		aH.handleFunc(router, aH.mintEmbedToken, "/embed/tokens/{%s}", traceIDParam).Methods(http.MethodPost)
+		// Embedded views cannot send auth or tenant headers; access is granted by the token.
+		aH.handleUntenantedFunc(router, aH.getEmbeddedTrace, "/embed/traces/{%s}", traceIDParam).Methods(http.MethodGet)
+	}
+	if aH.links != nil {
+		aH.handleFunc(router, aH.getSpanLinks, "/traces/{%s}/spans/{%s}/links", traceIDParam, spanIDParam).Methods(http.MethodGet)
+	}
+	if aH.timePresets != nil {
+		aH.handleFunc(router, aH.getTimePresets, "/time-presets").Methods(http.MethodGet)
+	}
+	if aH.liveTail != nil {
+		aH.handleFunc(router, aH.tailSpans, "/live/spans").Methods(http.MethodGet)
+	}
+	if aH.deleteTracesToken != "" {
+		aH.handleFunc(router, aH.deleteTraces, "/traces").Methods(http.MethodDelete)
+	}
 	aH.handleFunc(router, aH.search, "/traces").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getServices, "/services").Methods(http.MethodGet)
 	// TODO change the UI to use this endpoint. Requires ?service= parameter.
 	aH.handleFunc(router, aH.getOperations, "/operations").Methods(http.MethodGet)
 	// TODO - remove this when UI catches up
 	aH.handleFunc(router, aH.getOperationsLegacy, "/services/{%s}/operations", serviceParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.serviceSnapshot, "/services/snapshot").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.transformOTLP, "/transform").Methods(http.MethodPost)
 	aH.handleFunc(router, aH.dependencies, "/dependencies").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.dependenciesDiff, "/dependencies/diff").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.latencies, "/metrics/latencies").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.calls, "/metrics/calls").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.errors, "/metrics/errors").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.minStep, "/metrics/minstep").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.sloSummary, "/metrics/slo").Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) handleFunc(
@@ -143,11 +200,24 @@ func (aH *APIHandler) handleFunc(
 	routeFmt string,
 	args ...any,
 ) *mux.Route {
-	route := aH.formatRoute(routeFmt, args...)
 	var handler http.Handler = http.HandlerFunc(f)
 	if aH.tenancyMgr.Enabled {
 		handler = tenancy.ExtractTenantHTTPHandler(aH.tenancyMgr, handler)
 	}
+	return aH.handleRoute(router, handler, aH.formatRoute(routeFmt, args...))
+}
+
+// handleUntenantedFunc registers a route that does not require the tenancy header.
+func (aH *APIHandler) handleUntenantedFunc(
+	router *mux.Router,
+	f func(http.ResponseWriter, *http.Request),
+	routeFmt string,
+	args ...any,
+) *mux.Route {
+	return aH.handleRoute(router, http.HandlerFunc(f), aH.formatRoute(routeFmt, args...))
+}
+
+func (aH *APIHandler) handleRoute(router *mux.Router, handler http.Handler, route string) *mux.Route {
 	traceMiddleware := otelhttp.NewHandler(
 		otelhttp.WithRouteTag(route, traceResponseHandler(handler)),
 		route,
@@ -160,8 +230,34 @@ func (aH *APIHandler) formatRoute(route string, args ...any) string {
 	return fmt.Sprintf("/%s"+route, args...)
 }
 
+// parseNameSearch returns the search of service or operation names requested with the search
+// and match parameters, or nil without search parameter. The names are matched fuzzily by default.
+func parseNameSearch(r *http.Request) (*spanstore.NameSearchParameters, error) {
+	text := r.FormValue(nameSearchParam)
+	if text == "" {
+		return nil, nil
+	}
+	search := &spanstore.NameSearchParameters{Text: text, Mode: r.FormValue(nameMatchParam)}
+	if search.Mode == "" {
+		search.Mode = spanstore.NameMatchFuzzy
+	}
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
 func (aH *APIHandler) getServices(w http.ResponseWriter, r *http.Request) {
-	services, err := aH.queryService.GetServices(r.Context())
+	search, err := parseNameSearch(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	var services []string
+	if search != nil {
+		services, err = aH.queryService.SearchServices(r.Context(), *search)
+	} else {
+		services, err = aH.queryService.GetServices(r.Context())
+	}
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
 	}
@@ -176,13 +272,17 @@ func (aH *APIHandler) getOperationsLegacy(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	// given how getOperationsLegacy is bound to URL route, serviceParam cannot be empty
 	service, _ := url.QueryUnescape(vars[serviceParam])
+	search, err := parseNameSearch(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
 	// for backwards compatibility, we will retrieve operations with all span kind
-	operations, err := aH.queryService.GetOperations(r.Context(),
+	operations, err := aH.queryOperations(r.Context(),
 		spanstore.OperationQueryParameters{
 			ServiceName: service,
 			// include all kinds
 			SpanKind: "",
-		})
+		}, search)
 
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
@@ -219,9 +319,18 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	spanKind := r.FormValue(spanKindParam)
-	operations, err := aH.queryService.GetOperations(
+	search, err := parseNameSearch(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	withStats, err := parseBool(r, withStatsParam)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	operations, err := aH.queryOperations(
 		r.Context(),
-		spanstore.OperationQueryParameters{ServiceName: service, SpanKind: spanKind},
+		spanstore.OperationQueryParameters{ServiceName: service, SpanKind: spanKind, WithStats: withStats},
+		search,
 	)
 
 	if aH.handleError(w, err, http.StatusInternalServerError) {
@@ -230,8 +339,12 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 	data := make([]ui.Operation, len(operations))
 	for i, operation := range operations {
 		data[i] = ui.Operation{
-			Name:     operation.Name,
-			SpanKind: operation.SpanKind,
+			Name:      operation.Name,
+			SpanKind:  operation.SpanKind,
+			SpanCount: operation.SpanCount,
+		}
+		if !operation.LastSeen.IsZero() {
+			data[i].LastSeen = model.TimeAsEpochMicroseconds(operation.LastSeen)
 		}
 	}
 	structuredRes := structuredResponse{
@@ -241,6 +354,17 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func (aH *APIHandler) queryOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+	search *spanstore.NameSearchParameters,
+) ([]spanstore.Operation, error) {
+	if search != nil {
+		return aH.queryService.SearchOperations(ctx, query, *search)
+	}
+	return aH.queryService.GetOperations(ctx, query)
+}
+
 func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
 	if aH.handleError(w, err, http.StatusBadRequest) {
@@ -250,7 +374,15 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	var uiErrors []structuredError
 	var tracesFromStorage []*model.Trace
 	if len(tQuery.traceIDs) > 0 {
-		tracesFromStorage, uiErrors, err = aH.tracesByIDs(r.Context(), tQuery.traceIDs)
+		startTime, endTime, err := aH.queryParser.parseTraceTimeWindow(r)
+		if aH.handleError(w, err, http.StatusBadRequest) {
+			return
+		}
+		window := spanstore.GetTraceParameters{StartTime: startTime, EndTime: endTime}
+		if aH.handleError(w, window.Validate(), http.StatusBadRequest) {
+			return
+		}
+		tracesFromStorage, uiErrors, err = aH.tracesByIDs(r.Context(), tQuery.traceIDs, window)
 		if aH.handleError(w, err, http.StatusInternalServerError) {
 			return
 		}
@@ -265,6 +397,33 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, structuredRes)
 }
 
+// sampleTraces returns a reproducible random sample of the traces matching a search query,
+// e.g. to extract a dataset. The limit parameter bounds the number of matching traces
+// that the sample is drawn from.
+func (aH *APIHandler) sampleTraces(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if len(tQuery.traceIDs) > 0 {
+		aH.handleError(w, errSampleTraceIDs, http.StatusBadRequest)
+		return
+	}
+	params, err := aH.queryParser.parseTraceSamplingParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traces, total, err := aH.queryService.SampleTraces(r.Context(), &tQuery.TraceQueryParameters, params)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	structuredRes := aH.tracesToResponse(traces, true, nil)
+	structuredRes.Total = total
+	structuredRes.Limit = params.SampleSize
+	aH.writeJSON(w, r, structuredRes)
+}
+
 func (aH *APIHandler) tracesToResponse(traces []*model.Trace, adjust bool, uiErrors []structuredError) *structuredResponse {
 	uiTraces := make([]*ui.Trace, len(traces))
 	for i, v := range traces {
@@ -281,11 +440,15 @@ func (aH *APIHandler) tracesToResponse(traces []*model.Trace, adjust bool, uiErr
 	}
 }
 
-func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
+// tracesByIDs fetches the traces with the given IDs, applying the time window hints
+// carried by the window parameter to each of them.
+func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID, window spanstore.GetTraceParameters) ([]*model.Trace, []structuredError, error) {
 	var traceErrors []structuredError
 	retMe := make([]*model.Trace, 0, len(traceIDs))
 	for _, traceID := range traceIDs {
-		if trace, err := aH.queryService.GetTrace(ctx, traceID); err != nil {
+		query := window
+		query.TraceID = traceID
+		if trace, err := aH.queryService.GetTrace(ctx, query); err != nil {
 			if !errors.Is(err, spanstore.ErrTraceNotFound) {
 				return nil, nil, err
 			}
@@ -300,6 +463,97 @@ func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID)
 	return retMe, traceErrors, nil
 }
 
+// traceComparison is the response of the trace comparison API.
+type traceComparison struct {
+	BaseTraces    int                   `json:"baseTraces"`
+	CurrentTraces int                   `json:"currentTraces"`
+	Operations    []operationComparison `json:"operations"`
+}
+
+type operationComparison struct {
+	Service   string         `json:"service"`
+	Operation string         `json:"operation"`
+	Base      operationStats `json:"base"`
+	Current   operationStats `json:"current"`
+	// MeanDurationChange is the relative change of the mean duration.
+	MeanDurationChange float64 `json:"meanDurationChange"`
+	ErrorRateDelta     float64 `json:"errorRateDelta"`
+	// The p-values are null when there are too few spans of the operation to test.
+	DurationPValue       *float64 `json:"durationPValue"`
+	DurationSignificant  bool     `json:"durationSignificant"`
+	ErrorRatePValue      *float64 `json:"errorRatePValue"`
+	ErrorRateSignificant bool     `json:"errorRateSignificant"`
+}
+
+// operationStats has the same duration units as the spans of the UI model, i.e. microseconds.
+type operationStats struct {
+	Count        int     `json:"count"`
+	Errors       int     `json:"errors"`
+	ErrorRate    float64 `json:"errorRate"`
+	MeanDuration int64   `json:"meanDuration"`
+	P50Duration  int64   `json:"p50Duration"`
+	P95Duration  int64   `json:"p95Duration"`
+	P99Duration  int64   `json:"p99Duration"`
+}
+
+func (aH *APIHandler) compareTraces(w http.ResponseWriter, r *http.Request) {
+	baseIDs, currentIDs, err := aH.queryParser.parseTraceComparisonParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	base, baseErrors, err := aH.tracesByIDs(r.Context(), baseIDs, spanstore.GetTraceParameters{})
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	current, currentErrors, err := aH.tracesByIDs(r.Context(), currentIDs, spanstore.GetTraceParameters{})
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	comparison := querysvc.CompareTraces(base, current)
+	operations := make([]operationComparison, 0, len(comparison.Operations))
+	for _, op := range comparison.Operations {
+		oc := operationComparison{
+			Service:            op.Service,
+			Operation:          op.Operation,
+			Base:               toOperationStats(op.Base),
+			Current:            toOperationStats(op.Current),
+			MeanDurationChange: op.MeanDurationChange(),
+			ErrorRateDelta:     op.ErrorRateDelta(),
+		}
+		if op.DurationTest != nil {
+			oc.DurationPValue = &op.DurationTest.PValue
+			oc.DurationSignificant = op.DurationTest.Significant()
+		}
+		if op.ErrorRateTest != nil {
+			oc.ErrorRatePValue = &op.ErrorRateTest.PValue
+			oc.ErrorRateSignificant = op.ErrorRateTest.Significant()
+		}
+		operations = append(operations, oc)
+	}
+	structuredRes := structuredResponse{
+		Data: traceComparison{
+			BaseTraces:    comparison.BaseTraces,
+			CurrentTraces: comparison.CurrentTraces,
+			Operations:    operations,
+		},
+		Errors: append(baseErrors, currentErrors...),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func toOperationStats(stats querysvc.OperationStats) operationStats {
+	return operationStats{
+		Count:        stats.Count,
+		Errors:       stats.Errors,
+		ErrorRate:    stats.ErrorRate(),
+		MeanDuration: stats.MeanDuration.Microseconds(),
+		P50Duration:  stats.P50Duration.Microseconds(),
+		P95Duration:  stats.P95Duration.Microseconds(),
+		P99Duration:  stats.P99Duration.Microseconds(),
+	}
+}
+
 func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	dqp, err := aH.queryParser.parseDependenciesQueryParams(r)
 	if aH.handleError(w, err, http.StatusBadRequest) {
@@ -319,6 +573,74 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// dependenciesDiff is the response of the dependencies diff API.
+type dependenciesDiff struct {
+	Base    []ui.DependencyLink  `json:"base"`
+	Current []ui.DependencyLink  `json:"current"`
+	Added   []dependencyLinkDiff `json:"added"`
+	Removed []dependencyLinkDiff `json:"removed"`
+	Changed []dependencyLinkDiff `json:"changed"`
+}
+
+type dependencyLinkDiff struct {
+	Parent         string `json:"parent"`
+	Child          string `json:"child"`
+	BaseCallCount  uint64 `json:"baseCallCount"`
+	CallCount      uint64 `json:"callCount"`
+	CallCountDelta int64  `json:"callCountDelta"`
+}
+
+func (aH *APIHandler) dependenciesDiff(w http.ResponseWriter, r *http.Request) {
+	current, base, err := aH.queryParser.parseDependenciesDiffQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	service := r.FormValue(serviceParam)
+
+	diff, err := aH.queryService.GetDependenciesDiff(
+		r.Context(),
+		querysvc.DependenciesTimeRange{EndTs: base.endTs, Lookback: base.lookback},
+		querysvc.DependenciesTimeRange{EndTs: current.endTs, Lookback: current.lookback},
+		service,
+	)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	structuredRes := structuredResponse{
+		Data: dependenciesDiff{
+			Base:    toUIDependencyLinks(diff.Base),
+			Current: toUIDependencyLinks(diff.Current),
+			Added:   toDependencyLinkDiffs(diff.Added),
+			Removed: toDependencyLinkDiffs(diff.Removed),
+			Changed: toDependencyLinkDiffs(diff.Changed),
+		},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func toUIDependencyLinks(links []model.DependencyLink) []ui.DependencyLink {
+	result := make([]ui.DependencyLink, 0, len(links))
+	for _, l := range links {
+		result = append(result, ui.DependencyLink{Parent: l.Parent, Child: l.Child, CallCount: l.CallCount})
+	}
+	return result
+}
+
+func toDependencyLinkDiffs(diffs []querysvc.DependencyLinkDiff) []dependencyLinkDiff {
+	result := make([]dependencyLinkDiff, 0, len(diffs))
+	for _, d := range diffs {
+		result = append(result, dependencyLinkDiff{
+			Parent:         d.Parent,
+			Child:          d.Child,
+			BaseCallCount:  d.BaseCallCount,
+			CallCount:      d.CallCount,
+			CallCountDelta: d.CallCountDelta(),
+		})
+	}
+	return result
+}
+
 func (aH *APIHandler) latencies(w http.ResponseWriter, r *http.Request) {
 	q, err := strconv.ParseFloat(r.FormValue(quantileParam), 64)
 	if err != nil {
@@ -361,6 +683,120 @@ func (aH *APIHandler) minStep(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// sloSummary is the response of the SLO summary API.
+type sloSummary struct {
+	Objective float64      `json:"objective"`
+	Services  []serviceSLO `json:"services"`
+}
+
+type serviceSLO struct {
+	Service string      `json:"service"`
+	Windows []sloWindow `json:"windows"`
+}
+
+type sloWindow struct {
+	// Window is the duration of the window in milliseconds.
+	Window               int64   `json:"window"`
+	Calls                float64 `json:"calls"`
+	ErrorRate            float64 `json:"errorRate"`
+	Availability         float64 `json:"availability"`
+	BurnRate             float64 `json:"burnRate"`
+	ErrorBudgetRemaining float64 `json:"errorBudgetRemaining"`
+}
+
+func (aH *APIHandler) sloSummary(w http.ResponseWriter, r *http.Request) {
+	params, err := aH.queryParser.parseSLOQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	summaries, err := querysvc.GetSLOSummary(r.Context(), aH.metricsQueryService, params)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	services := make([]serviceSLO, 0, len(summaries))
+	for _, summary := range summaries {
+		windows := make([]sloWindow, 0, len(summary.Windows))
+		for _, sw := range summary.Windows {
+			windows = append(windows, sloWindow{
+				Window:               sw.Window.Milliseconds(),
+				Calls:                sw.Calls,
+				ErrorRate:            sw.ErrorRate,
+				Availability:         sw.Availability,
+				BurnRate:             sw.BurnRate,
+				ErrorBudgetRemaining: sw.ErrorBudgetRemaining,
+			})
+		}
+		services = append(services, serviceSLO{Service: summary.Service, Windows: windows})
+	}
+	structuredRes := structuredResponse{
+		Data: sloSummary{Objective: params.Objective, Services: services},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// serviceSnapshot is the response of the service snapshot API.
+type serviceSnapshot struct {
+	Service string `json:"service"`
+	// Operations is null when the metrics store is disabled.
+	Operations   []operationLatency  `json:"operations"`
+	ErrorTraces  []errorTrace        `json:"errorTraces"`
+	Dependencies []ui.DependencyLink `json:"dependencies"`
+}
+
+type operationLatency struct {
+	Operation string `json:"operation"`
+	// Latency is the latency quantile in milliseconds.
+	Latency float64 `json:"latency"`
+}
+
+type errorTrace struct {
+	TraceID   string `json:"traceID"`
+	Operation string `json:"operation"`
+	// StartTime is in microseconds since epoch and Duration in microseconds, like in traces.
+	StartTime  uint64 `json:"startTime"`
+	Duration   uint64 `json:"duration"`
+	Spans      int    `json:"spans"`
+	ErrorSpans int    `json:"errorSpans"`
+}
+
+func (aH *APIHandler) serviceSnapshot(w http.ResponseWriter, r *http.Request) {
+	params, err := aH.queryParser.parseServiceSnapshotQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	snapshot, err := aH.queryService.GetServiceSnapshot(r.Context(), aH.metricsQueryService, params)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	result := serviceSnapshot{
+		Service:      params.ServiceName,
+		ErrorTraces:  make([]errorTrace, 0, len(snapshot.ErrorTraces)),
+		Dependencies: toUIDependencyLinks(snapshot.Dependencies),
+	}
+	if snapshot.Operations != nil {
+		result.Operations = make([]operationLatency, 0, len(snapshot.Operations))
+		for _, op := range snapshot.Operations {
+			result.Operations = append(result.Operations, operationLatency{
+				Operation: op.Operation,
+				Latency:   float64(op.Latency) / float64(time.Millisecond),
+			})
+		}
+	}
+	for _, t := range snapshot.ErrorTraces {
+		result.ErrorTraces = append(result.ErrorTraces, errorTrace{
+			TraceID:    t.TraceID.String(),
+			Operation:  t.Operation,
+			StartTime:  model.TimeAsEpochMicroseconds(t.StartTime),
+			Duration:   model.DurationAsMicroseconds(t.Duration),
+			Spans:      t.Spans,
+			ErrorSpans: t.ErrorSpans,
+		})
+	}
+	aH.writeJSON(w, r, &structuredResponse{Data: result})
+}
+
 func (aH *APIHandler) metrics(w http.ResponseWriter, r *http.Request, getMetrics func(context.Context, metricsstore.BaseQueryParameters) (*metrics.MetricFamily, error)) {
 	requestParams, err := aH.queryParser.parseMetricsQueryParams(r)
 	if aH.handleError(w, err, http.StatusBadRequest) {
@@ -440,15 +876,37 @@ func (aH *APIHandler) parseTraceID(w http.ResponseWriter, r *http.Request) (mode
 	return traceID, true
 }
 
+// Parses trace ID from URL like /traces/{trace-id}?start={start}&end={end},
+// where the optional start and end define the time window of the trace.
+func (aH *APIHandler) parseGetTraceParameters(w http.ResponseWriter, r *http.Request) (spanstore.GetTraceParameters, bool) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return spanstore.GetTraceParameters{}, false
+	}
+	startTime, endTime, err := aH.queryParser.parseTraceTimeWindow(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return spanstore.GetTraceParameters{}, false
+	}
+	query := spanstore.GetTraceParameters{
+		TraceID:   traceID,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	if aH.handleError(w, query.Validate(), http.StatusBadRequest) {
+		return spanstore.GetTraceParameters{}, false
+	}
+	return query, true
+}
+
 // getTrace implements the REST API /traces/{trace-id}
 // It parses trace ID from the path, fetches the trace from QueryService,
 // formats it in the UI JSON format, and responds to the client.
 func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
-	traceID, ok := aH.parseTraceID(w, r)
+	query, ok := aH.parseGetTraceParameters(w, r)
 	if !ok {
 		return
 	}
-	trace, err := aH.queryService.GetTrace(r.Context(), traceID)
+	trace, err := aH.queryService.GetTrace(r.Context(), query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		aH.handleError(w, err, http.StatusNotFound)
 		return
@@ -457,11 +915,105 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	completeness := aH.queryService.TraceCompleteness(trace)
 	var uiErrors []structuredError
 	structuredRes := aH.tracesToResponse([]*model.Trace{trace}, shouldAdjust(r), uiErrors)
+	structuredRes.Data.([]*ui.Trace)[0].Completeness = &ui.TraceCompleteness{
+		Complete:           completeness.Complete(),
+		StillReceiving:     completeness.StillReceiving,
+		MissingParentSpans: completeness.MissingParentSpans,
+	}
 	aH.writeJSON(w, r, structuredRes)
 }
 
+// traceExists implements the REST API HEAD /traces/{trace-id}
+// It responds with 200 if the trace is stored and 404 otherwise, without transferring its spans,
+// e.g. for external systems verifying that a trace was ingested.
+func (aH *APIHandler) traceExists(w http.ResponseWriter, r *http.Request) {
+	query, ok := aH.parseGetTraceParameters(w, r)
+	if !ok {
+		return
+	}
+	exists, err := aH.queryService.TraceExists(r.Context(), query)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// getRawSpans implements the REST API /traces/{trace-id}/spans/{span-id}/raw
+// It responds with the span as stored by the span storage, before any translation
+// to the domain model, to help diagnose mapping issues.
+func (aH *APIHandler) getRawSpans(w http.ResponseWriter, r *http.Request) {
+	query, ok := aH.parseGetTraceParameters(w, r)
+	if !ok {
+		return
+	}
+	if aH.queryService.Redacts(r.Context()) {
+		aH.handleError(w, errRedactedSpans, http.StatusForbidden)
+		return
+	}
+	spanID, err := model.SpanIDFromString(mux.Vars(r)[spanIDParam])
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	rawSpans, err := aH.queryService.GetRawSpans(r.Context(), query, spanID)
+	if errors.Is(err, spanstore.ErrRawSpansNotSupported) {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	if len(rawSpans) == 0 {
+		aH.handleError(w, errSpanNotFound, http.StatusNotFound)
+		return
+	}
+	structuredRes := structuredResponse{
+		Data:   rawSpans,
+		Total:  len(rawSpans),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getSpanLinks implements the REST API /traces/{trace-id}/spans/{span-id}/links
+// It responds with the configured deep links to external systems resolved for the span.
+func (aH *APIHandler) getSpanLinks(w http.ResponseWriter, r *http.Request) {
+	query, ok := aH.parseGetTraceParameters(w, r)
+	if !ok {
+		return
+	}
+	spanID, err := model.SpanIDFromString(mux.Vars(r)[spanIDParam])
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	trace, err := aH.queryService.GetTrace(r.Context(), query)
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	span := trace.FindSpanByID(spanID)
+	if span == nil {
+		aH.handleError(w, errSpanNotFound, http.StatusNotFound)
+		return
+	}
+	links := aH.links.resolve(span)
+	structuredRes := structuredResponse{
+		Data:   links,
+		Total:  len(links),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func shouldAdjust(r *http.Request) bool {
 	raw := r.FormValue("raw")
 	isRaw, _ := strconv.ParseBool(raw)
@@ -471,13 +1023,13 @@ func shouldAdjust(r *http.Request) bool {
 // archiveTrace implements the REST API POST:/archive/{trace-id}.
 // It passes the traceID to queryService.ArchiveTrace for writing.
 func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
-	traceID, ok := aH.parseTraceID(w, r)
+	query, ok := aH.parseGetTraceParameters(w, r)
 	if !ok {
 		return
 	}
 
 	// QueryService.ArchiveTrace can now archive this traceID.
-	err := aH.queryService.ArchiveTrace(r.Context(), traceID)
+	err := aH.queryService.ArchiveTrace(r.Context(), query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		aH.handleError(w, err, http.StatusNotFound)
 		return
@@ -493,6 +1045,120 @@ func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// tailSpans implements the REST API /live/spans?service={service}&operation={operation}&tag={k:v}.
+// It streams the spans received by the collector that match the filter as
+// Server-Sent Events until the client disconnects. Each "span" event carries
+// a span in the UI JSON format; a "dropped" event reports spans skipped
+// because the client could not keep up.
+func (aH *APIHandler) tailSpans(w http.ResponseWriter, r *http.Request) {
+	filter, err := aH.queryParser.parseLiveTailFilter(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if aH.queryService.Redacts(r.Context()) {
+		aH.handleError(w, errRedactedSpans, http.StatusForbidden)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		aH.handleError(w, errStreamingNotSupported, http.StatusInternalServerError)
+		return
+	}
+	filter.Tenant = tenancy.GetTenant(r.Context())
+	sub, cancel := aH.liveTail.Subscribe(filter)
+	defer cancel()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(liveTailKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case span, ok := <-sub.Spans():
+			if !ok {
+				return
+			}
+			if dropped := sub.Dropped(); dropped > 0 {
+				if err := writeEvent(w, "dropped", map[string]int64{"count": dropped}); err != nil {
+					return
+				}
+			}
+			if err := writeEvent(w, "span", span); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+type embedToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	URL       string    `json:"url"`
+}
+
+// mintEmbedToken implements the REST API POST:/embed/tokens/{trace-id}.
+// It responds with a short-lived token granting read access to the trace
+// via the /embed/traces/{trace-id} endpoint.
+func (aH *APIHandler) mintEmbedToken(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	token, expiresAt, err := aH.embedTokens.mint(traceID, tenancy.GetTenant(r.Context()))
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	embedURL := path.Join(aH.basePath, aH.formatRoute("/embed/traces/%s", traceID.String())) +
+		"?" + url.Values{embedTokenParam: []string{token}}.Encode()
+	structuredRes := structuredResponse{
+		Data: embedToken{
+			Token:     token,
+			ExpiresAt: expiresAt.UTC(),
+			URL:       embedURL,
+		},
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getEmbeddedTrace implements the REST API /embed/traces/{trace-id}?token={token}.
+// It verifies the embed token and responds like /traces/{trace-id}, in the tenant the token was minted for.
+func (aH *APIHandler) getEmbeddedTrace(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	tenant, err := aH.embedTokens.verify(r.FormValue(embedTokenParam), traceID)
+	if aH.handleError(w, err, http.StatusUnauthorized) {
+		return
+	}
+	if tenant != "" {
+		r = r.WithContext(tenancy.WithTenant(r.Context(), tenant))
+	}
+	aH.getTrace(w, r)
+}
+
 func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode int) bool {
 	if err == nil {
 		return false
@@ -16,6 +16,7 @@
 package app
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -26,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,6 +47,7 @@ import (
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
@@ -154,7 +157,7 @@ func withTestServer(doTest func(s *testServer), queryOptions querysvc.QueryServi
 func TestGetTraceSuccess(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 
 	var response structuredResponse
@@ -324,7 +327,7 @@ func TestGetTrace(t *testing.T) {
 			ts := initializeTestServer(HandlerOptions.Tracer(&jTracer))
 			defer ts.server.Close()
 
-			ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 0x123456abc)).
+			ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 0x123456abc)}).
 				Return(makeMockTrace(t), nil).Once()
 
 			var response structuredResponse
@@ -338,14 +341,190 @@ func TestGetTrace(t *testing.T) {
 			traces := extractTraces(t, &response)
 			assert.Len(t, traces[0].Spans, 2)
 			assert.Len(t, traces[0].Spans[1].References, testCase.numSpanRefs)
+			assert.Equal(t, &ui.TraceCompleteness{Complete: true}, traces[0].Completeness)
 		})
 	}
 }
 
+func TestGetTraceWithTimeWindow(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	expectedQuery := spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 0x123456),
+		StartTime: time.UnixMicro(1000),
+		EndTime:   time.UnixMicro(2000),
+	}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), expectedQuery).
+		Return(mockTrace, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces/123456?start=1000&end=2000`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+}
+
+func TestGetTraceBadTimeWindow(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "bad start", query: "start=foo"},
+		{name: "bad end", query: "end=foo"},
+		{name: "end before start", query: "start=2000&end=1000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var response structuredResponse
+			err := getJSON(ts.server.URL+`/api/traces/123456?`+tc.query, &response)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "400 error from server")
+		})
+	}
+}
+
+type rawSpanReader struct {
+	*spanstoremocks.Reader
+	*spanstoremocks.RawSpanReader
+}
+
+func TestGetRawSpans(t *testing.T) {
+	rawReader := &spanstoremocks.RawSpanReader{}
+	qs := querysvc.NewQueryService(rawSpanReader{Reader: &spanstoremocks.Reader{}, RawSpanReader: rawReader}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, &tenancy.Manager{}, HandlerOptions.RawSpans(true)).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 0x123456)}
+	rawReader.On("GetRawSpans", mock.Anything, query, model.NewSpanID(1)).
+		Return([]spanstore.RawSpan{{Location: "jaeger-span-2024-01-01", Document: []byte(`{"spanID":"1"}`)}}, nil).Once()
+	rawReader.On("GetRawSpans", mock.Anything, query, model.NewSpanID(2)).
+		Return(nil, nil).Once()
+	rawReader.On("GetRawSpans", mock.Anything, query, model.NewSpanID(3)).
+		Return(nil, errStorage).Once()
+
+	var response struct {
+		Data []spanstore.RawSpan `json:"data"`
+	}
+	err := getJSON(server.URL+`/api/traces/123456/spans/1/raw`, &response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "jaeger-span-2024-01-01", response.Data[0].Location)
+	assert.JSONEq(t, `{"spanID":"1"}`, string(response.Data[0].Document))
+
+	testCases := []struct {
+		name   string
+		url    string
+		status string
+	}{
+		{name: "span not found", url: "/api/traces/123456/spans/2/raw", status: "404 error from server"},
+		{name: "storage error", url: "/api/traces/123456/spans/3/raw", status: "500 error from server"},
+		{name: "bad span ID", url: "/api/traces/123456/spans/xyz/raw", status: "400 error from server"},
+		{name: "bad trace ID", url: "/api/traces/xyz/spans/1/raw", status: "400 error from server"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := getJSON(server.URL+tc.url, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.status)
+		})
+	}
+}
+
+func TestGetRawSpansNotSupported(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.RawSpans(true))
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/traces/123456/spans/1/raw`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "501 error from server")
+}
+
+func TestGetRawSpansDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/traces/123456/spans/1/raw`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404 error from server")
+}
+
+func TestRedactedSpansForbidden(t *testing.T) {
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{
+		Redactor: querysvc.NewRedactor(querysvc.RedactionOptions{Keys: []string{"user.email"}}),
+	}, HandlerOptions.RawSpans(true), HandlerOptions.LiveTail(livetail.NewBroadcaster(10)))
+	defer ts.server.Close()
+	for _, path := range []string{"/api/traces/123456/spans/1/raw", "/api/live/spans?service=frontend"} {
+		err := getJSON(ts.server.URL+path, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403 error from server")
+		assert.Contains(t, err.Error(), errRedactedSpans.Error())
+	}
+}
+
+func TestTailSpans(t *testing.T) {
+	liveTail := livetail.NewBroadcaster(10)
+	ts := initializeTestServer(HandlerOptions.LiveTail(liveTail))
+	defer ts.server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.server.URL+`/api/live/spans?service=frontend&tag=k:v`, nil)
+	require.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// the subscription is registered once the headers are sent
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(2),
+		OperationName: "HTTP GET",
+		Tags:          model.KeyValues{model.String("k", "v")},
+		Process:       &model.Process{ServiceName: "frontend"},
+	}
+	liveTail.Publish(&model.Span{Process: &model.Process{ServiceName: "backend"}}, "")
+	liveTail.Publish(span, "")
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: span\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	var uiSpan ui.Span
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &uiSpan))
+	assert.Equal(t, "HTTP GET", uiSpan.OperationName)
+	assert.Equal(t, "frontend", uiSpan.Process.ServiceName)
+}
+
+func TestTailSpansBadRequest(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.LiveTail(livetail.NewBroadcaster(1)))
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/live/spans`, nil)
+	require.ErrorContains(t, err, "400 error from server")
+}
+
+func TestTailSpansDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/live/spans?service=frontend`, nil)
+	require.ErrorContains(t, err, "404 error from server")
+}
+
+func TestWriteEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeEvent(buf, "dropped", map[string]int64{"count": 3}))
+	assert.Equal(t, "event: dropped\ndata: {\"count\":3}\n\n", buf.String())
+	require.Error(t, writeEvent(buf, "span", make(chan int)))
+}
+
 func TestGetTraceDBFailure(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, errStorage).Once()
 
 	var response structuredResponse
@@ -356,7 +535,7 @@ func TestGetTraceDBFailure(t *testing.T) {
 func TestGetTraceNotFound(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
 
 	var response structuredResponse
@@ -364,6 +543,39 @@ func TestGetTraceNotFound(t *testing.T) {
 	require.EqualError(t, err, parsedError(404, "trace not found"))
 }
 
+func TestTraceExists(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		trace          *model.Trace
+		err            error
+		expectedStatus int
+	}{
+		{name: "found", url: "/api/traces/123456", trace: mockTrace, expectedStatus: http.StatusOK},
+		{name: "not found", url: "/api/traces/123456", err: spanstore.ErrTraceNotFound, expectedStatus: http.StatusNotFound},
+		{name: "storage error", url: "/api/traces/123456", err: errStorage, expectedStatus: http.StatusInternalServerError},
+		{name: "invalid trace ID", url: "/api/traces/chumbawumba", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := initializeTestServer()
+			defer ts.server.Close()
+			if tc.trace != nil || tc.err != nil {
+				ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
+					Return(tc.trace, tc.err).Once()
+			}
+
+			resp, err := http.Head(ts.server.URL + tc.url)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Empty(t, body)
+		})
+	}
+}
+
 func TestGetTraceAdjustmentFailure(t *testing.T) {
 	ts := initializeTestServerWithHandler(
 		querysvc.QueryServiceOptions{
@@ -373,7 +585,7 @@ func TestGetTraceAdjustmentFailure(t *testing.T) {
 		},
 	)
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 
 	var response structuredResponse
@@ -407,7 +619,7 @@ func TestSearchSuccess(t *testing.T) {
 func TestSearchByTraceIDSuccess(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Twice()
 
 	var response structuredResponse
@@ -417,15 +629,35 @@ func TestSearchByTraceIDSuccess(t *testing.T) {
 	assert.Len(t, response.Data, 2)
 }
 
+func TestSearchByTraceIDWithTimeWindow(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	for _, traceID := range []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)} {
+		expectedQuery := spanstore.GetTraceParameters{
+			TraceID:   traceID,
+			StartTime: time.UnixMicro(1000),
+			EndTime:   time.UnixMicro(2000),
+		}
+		ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), expectedQuery).
+			Return(mockTrace, nil).Once()
+	}
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces?traceID=1&traceID=2&start=1000&end=2000`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Len(t, response.Data, 2)
+}
+
 func TestSearchByTraceIDSuccessWithArchive(t *testing.T) {
 	archiveReadMock := &spanstoremocks.Reader{}
 	ts := initializeTestServerWithOptions(&tenancy.Manager{}, querysvc.QueryServiceOptions{
 		ArchiveSpanReader: archiveReadMock,
 	})
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Twice()
-	archiveReadMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	archiveReadMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Twice()
 
 	var response structuredResponse
@@ -438,7 +670,7 @@ func TestSearchByTraceIDSuccessWithArchive(t *testing.T) {
 func TestSearchByTraceIDNotFound(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
 
 	var response structuredResponse
@@ -452,7 +684,7 @@ func TestSearchByTraceIDFailure(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
 	whatsamattayou := "https://youtu.be/WrKFOCg13QQ"
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, fmt.Errorf(whatsamattayou)).Once()
 
 	var response structuredResponse
@@ -509,6 +741,146 @@ func TestSearchFailures(t *testing.T) {
 	}
 }
 
+func TestSampleTracesSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	var traces []*model.Trace
+	for i := 1; i <= 5; i++ {
+		traces = append(traces, &model.Trace{Spans: []*model.Span{{
+			TraceID: model.NewTraceID(0, uint64(i)),
+			SpanID:  model.NewSpanID(1),
+			Process: &model.Process{ServiceName: "service"},
+		}}})
+	}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.ServiceName == "service" && q.NumTraces == 50
+	})).Return(traces, nil).Twice()
+
+	sample := func() []string {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/traces/sample?service=service&limit=50&sampleSize=2&seed=42`, &response)
+		require.NoError(t, err)
+		assert.Empty(t, response.Errors)
+		assert.Equal(t, 5, response.Total)
+		assert.Equal(t, 2, response.Limit)
+		var traceIDs []string
+		for _, trace := range response.Data.([]any) {
+			traceIDs = append(traceIDs, trace.(map[string]any)["traceID"].(string))
+		}
+		return traceIDs
+	}
+	first := sample()
+	assert.Len(t, first, 2)
+	assert.Equal(t, first, sample())
+}
+
+func TestSampleTracesFailures(t *testing.T) {
+	tests := []struct {
+		urlStr string
+		errMsg string
+	}{
+		{
+			`/api/traces/sample?operation=operation`,
+			parsedError(400, "parameter 'service' is required"),
+		},
+		{
+			`/api/traces/sample?traceID=1`,
+			parsedError(400, "parameter 'traceID' is not supported when sampling traces"),
+		},
+		{
+			`/api/traces/sample?service=service&sampleSize=-1`,
+			parsedError(400, "sample size must be positive, got -1"),
+		},
+	}
+	for _, test := range tests {
+		testIndividualSearchFailures(t, test.urlStr, test.errMsg)
+	}
+}
+
+func TestSampleTracesDBFailure(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces/sample?service=service`, &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+}
+
+func TestCompareTracesSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	newTrace := func(duration time.Duration, hasError bool) *model.Trace {
+		span := &model.Span{
+			OperationName: "GET",
+			Process:       &model.Process{ServiceName: "service"},
+			Duration:      duration,
+		}
+		if hasError {
+			span.Tags = model.KeyValues{model.Bool("error", true)}
+		}
+		return &model.Trace{Spans: []*model.Span{span}}
+	}
+	var query strings.Builder
+	query.WriteString("baseTraceID=ff")
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 0xff)}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	for i := 1; i <= 5; i++ {
+		baseID, currentID := model.NewTraceID(0, uint64(i)), model.NewTraceID(1, uint64(i))
+		ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: baseID}).
+			Return(newTrace(time.Duration(i)*time.Millisecond, false), nil).Once()
+		ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: currentID}).
+			Return(newTrace(time.Duration(i+5)*time.Millisecond, i == 1), nil).Once()
+		fmt.Fprintf(&query, "&baseTraceID=%s&traceID=%s", baseID, currentID)
+	}
+
+	var response struct {
+		Data   traceComparison   `json:"data"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(ts.server.URL+"/api/traces/compare?"+query.String(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, []structuredError{{Msg: spanstore.ErrTraceNotFound.Error(), TraceID: "00000000000000ff"}}, response.Errors)
+	assert.Equal(t, 5, response.Data.BaseTraces)
+	assert.Equal(t, 5, response.Data.CurrentTraces)
+	require.Len(t, response.Data.Operations, 1)
+	op := response.Data.Operations[0]
+	assert.Equal(t, "service", op.Service)
+	assert.Equal(t, "GET", op.Operation)
+	assert.Equal(t, operationStats{Count: 5, MeanDuration: 3000, P50Duration: 3000, P95Duration: 5000, P99Duration: 5000}, op.Base)
+	assert.Equal(t, operationStats{Count: 5, Errors: 1, ErrorRate: 0.2, MeanDuration: 8000, P50Duration: 8000, P95Duration: 10000, P99Duration: 10000}, op.Current)
+	assert.InDelta(t, 5.0/3, op.MeanDurationChange, 1e-9)
+	assert.InDelta(t, 0.2, op.ErrorRateDelta, 1e-9)
+	require.NotNil(t, op.DurationPValue)
+	assert.True(t, op.DurationSignificant)
+	require.NotNil(t, op.ErrorRatePValue)
+	assert.False(t, op.ErrorRateSignificant)
+}
+
+func TestCompareTracesFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/traces/compare?traceID=1", &response)
+	require.EqualError(t, err, parsedError(400, "parameter 'baseTraceID' is required"))
+
+	// failure of the base traces
+	err = getJSON(ts.server.URL+"/api/traces/compare?baseTraceID=1&traceID=2", &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+
+	// failure of the current traces
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}).
+		Return(&model.Trace{}, nil).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 2)}).
+		Return(nil, errStorage).Once()
+	err = getJSON(ts.server.URL+"/api/traces/compare?baseTraceID=1&traceID=2", &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+}
+
 func testIndividualSearchFailures(t *testing.T, urlStr, errMsg string) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -576,6 +948,30 @@ func TestGetOperationsSuccess(t *testing.T) {
 	}
 }
 
+func TestGetOperationsWithStats(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	lastSeen := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	ts.spanReader.On(
+		"GetOperations",
+		mock.AnythingOfType("*context.valueCtx"),
+		spanstore.OperationQueryParameters{ServiceName: "frontend", WithStats: true},
+	).Return([]spanstore.Operation{{Name: "GET /", LastSeen: lastSeen, SpanCount: 42}, {Name: "POST /"}}, nil).Once()
+
+	var response struct {
+		Operations []ui.Operation `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/operations?service=frontend&withStats=true", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.Operation{
+		{Name: "GET /", LastSeen: model.TimeAsEpochMicroseconds(lastSeen), SpanCount: 42},
+		{Name: "POST /"},
+	}, response.Operations)
+
+	err = getJSON(ts.server.URL+"/api/operations?service=frontend&withStats=often", &response)
+	require.ErrorContains(t, err, "withStats")
+}
+
 func TestGetOperationsNoServiceName(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -632,6 +1028,44 @@ func TestGetOperationsLegacyStorageFailure(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestSearchServicesAndOperations(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetServices", mock.AnythingOfType("*context.valueCtx")).
+		Return([]string{"payments", "frontend", "paymen"}, nil).Once()
+	var response structuredResponse
+	require.NoError(t, getJSON(ts.server.URL+"/api/services?search=payment", &response))
+	assert.Equal(t, []any{"payments", "paymen"}, response.Data)
+
+	operations := []spanstore.Operation{{Name: "GET /pay", SpanKind: "server"}, {Name: "get-payment", SpanKind: "client"}}
+	ts.spanReader.On("GetOperations", mock.AnythingOfType("*context.valueCtx"),
+		spanstore.OperationQueryParameters{ServiceName: "payments", SpanKind: "server"}).Return(operations[:1], nil).Once()
+	var operationsResponse struct {
+		Operations []ui.Operation `json:"data"`
+	}
+	require.NoError(t, getJSON(ts.server.URL+"/api/operations?service=payments&spanKind=server&search=get&match=prefix", &operationsResponse))
+	assert.Equal(t, []ui.Operation{{Name: "GET /pay", SpanKind: "server"}}, operationsResponse.Operations)
+
+	ts.spanReader.On("GetOperations", mock.AnythingOfType("*context.valueCtx"),
+		spanstore.OperationQueryParameters{ServiceName: "payments"}).Return(operations, nil).Once()
+	require.NoError(t, getJSON(ts.server.URL+"/api/services/payments/operations?search=paymnt", &response))
+	assert.Equal(t, []any{"get-payment"}, response.Data)
+}
+
+func TestSearchNamesBadMatch(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	msg := parsedError(400, `unknown name match mode \"regex\", use \"prefix\" or \"fuzzy\"`)
+	for _, url := range []string{
+		"/api/services?search=pay&match=regex",
+		"/api/operations?service=payments&search=pay&match=regex",
+		"/api/services/payments/operations?search=pay&match=regex",
+	} {
+		var response structuredResponse
+		require.EqualError(t, getJSON(ts.server.URL+url, &response), msg, url)
+	}
+}
+
 func TestTransformOTLPSuccess(t *testing.T) {
 	reformat := func(in []byte) []byte {
 		obj := new(any)
@@ -764,6 +1198,120 @@ func TestGetMetricsSuccess(t *testing.T) {
 	}
 }
 
+func TestGetSLOSummary(t *testing.T) {
+	mr := &metricsmocks.Reader{}
+	ts := initializeTestServer(HandlerOptions.MetricsQueryService(mr))
+	defer ts.server.Close()
+	gauge := func(value float64) *metrics.MetricFamily {
+		return &metrics.MetricFamily{
+			Type: metrics.MetricType_GAUGE,
+			Metrics: []*metrics.Metric{{
+				Labels: []*metrics.Label{{Name: "service_name", Value: "emailservice"}},
+				MetricPoints: []*metrics.MetricPoint{{
+					Timestamp: &types.Timestamp{Seconds: 1476374248},
+					Value: &metrics.MetricPoint_GaugeValue{
+						GaugeValue: &metrics.GaugeValue{Value: &metrics.GaugeValue_DoubleValue{DoubleValue: value}},
+					},
+				}},
+			}},
+		}
+	}
+	mr.On("GetCallRates", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*metricsstore.CallRateQueryParameters")).
+		Return(gauge(2), nil).Once()
+	mr.On("GetErrorRates", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*metricsstore.ErrorRateQueryParameters")).
+		Return(gauge(0.02), nil).Once()
+
+	var response struct {
+		Data sloSummary `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/metrics/slo?service=emailservice&objective=0.99&window=3600000", &response)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.99, response.Data.Objective, 1e-9)
+	require.Len(t, response.Data.Services, 1)
+	assert.Equal(t, "emailservice", response.Data.Services[0].Service)
+	require.Len(t, response.Data.Services[0].Windows, 1)
+	window := response.Data.Services[0].Windows[0]
+	assert.Equal(t, int64(3600000), window.Window)
+	assert.InDelta(t, 120, window.Calls, 1e-9)
+	assert.InDelta(t, 0.02, window.ErrorRate, 1e-9)
+	assert.InDelta(t, 0.98, window.Availability, 1e-9)
+	assert.InDelta(t, 2, window.BurnRate, 1e-9)
+	assert.InDelta(t, -1, window.ErrorBudgetRemaining, 1e-9)
+}
+
+func TestGetSLOSummaryFailures(t *testing.T) {
+	mr := &metricsmocks.Reader{}
+	ts := initializeTestServer(HandlerOptions.MetricsQueryService(mr))
+	defer ts.server.Close()
+	mr.On("GetCallRates", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*metricsstore.CallRateQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/metrics/slo?service=emailservice&objective=2", &response)
+	require.EqualError(t, err, parsedError(400, "unable to parse param 'objective': objective must be between 0 and 1 (exclusive)"))
+
+	err = getJSON(ts.server.URL+"/api/metrics/slo?service=emailservice", &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+}
+
+func TestGetServiceSnapshot(t *testing.T) {
+	mr := &metricsmocks.Reader{}
+	ts := initializeTestServer(HandlerOptions.MetricsQueryService(mr))
+	defer ts.server.Close()
+	mr.On("GetLatencies", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*metricsstore.LatenciesQueryParameters")).
+		Return(&metrics.MetricFamily{
+			Type: metrics.MetricType_GAUGE,
+			Metrics: []*metrics.Metric{{
+				Labels: []*metrics.Label{{Name: "operation", Value: "GET /cart"}},
+				MetricPoints: []*metrics.MetricPoint{{
+					Timestamp: &types.Timestamp{Seconds: 1476374248},
+					Value: &metrics.MetricPoint_GaugeValue{
+						GaugeValue: &metrics.GaugeValue{Value: &metrics.GaugeValue_DoubleValue{DoubleValue: 12.5}},
+					},
+				}},
+			}},
+		}, nil).Once()
+	failed := *mockTrace.Spans[0]
+	failed.Tags = model.KeyValues{model.Bool("error", true)}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{{Spans: []*model.Span{&failed}}}, nil).Once()
+	ts.dependencyReader.On("GetDependencies", mock.Anything, mock.Anything, time.Hour).
+		Return([]model.DependencyLink{{Parent: "service", Child: "db", CallCount: 3}}, nil).Once()
+
+	var response struct {
+		Data serviceSnapshot `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/services/snapshot?service=service", &response)
+	require.NoError(t, err)
+	assert.Equal(t, serviceSnapshot{
+		Service:    "service",
+		Operations: []operationLatency{{Operation: "GET /cart", Latency: 12.5}},
+		ErrorTraces: []errorTrace{{
+			TraceID:    failed.TraceID.String(),
+			Operation:  failed.OperationName,
+			StartTime:  model.TimeAsEpochMicroseconds(failed.StartTime),
+			Duration:   model.DurationAsMicroseconds(failed.Duration),
+			Spans:      1,
+			ErrorSpans: 1,
+		}},
+		Dependencies: []ui.DependencyLink{{Parent: "service", Child: "db", CallCount: 3}},
+	}, response.Data)
+}
+
+func TestGetServiceSnapshotFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/services/snapshot", &response)
+	require.EqualError(t, err, parsedError(400, "parameter 'service' is required"))
+
+	err = getJSON(ts.server.URL+"/api/services/snapshot?service=service", &response)
+	require.EqualError(t, err, parsedError(500, errStorage.Error()))
+}
+
 func TestMetricsReaderError(t *testing.T) {
 	metricsReader := &metricsmocks.Reader{}
 	apiHandlerOptions := []HandlerOption{
@@ -950,7 +1498,7 @@ func TestSearchTenancyHTTP(t *testing.T) {
 		tenancy.NewManager(&tenancyOptions),
 		querysvc.QueryServiceOptions{})
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Twice()
 
 	var response structuredResponse
@@ -977,7 +1525,7 @@ func TestSearchTenancyRejectionHTTP(t *testing.T) {
 		tenancy.NewManager(&tenancyOptions),
 		querysvc.QueryServiceOptions{})
 	defer ts.server.Close()
-	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Twice()
 
 	req, err := http.NewRequest(http.MethodGet, ts.server.URL+`/api/traces?traceID=1&traceID=2`, nil)
@@ -1012,14 +1560,14 @@ func TestSearchTenancyFlowTenantHTTP(t *testing.T) {
 			return false
 		}
 		return true
-	}), mock.AnythingOfType("model.TraceID")).Return(mockTrace, nil).Twice()
+	}), mock.AnythingOfType("spanstore.GetTraceParameters")).Return(mockTrace, nil).Twice()
 	ts.spanReader.On("GetTrace", mock.MatchedBy(func(v any) bool {
 		ctx, ok := v.(context.Context)
 		if !ok || tenancy.GetTenant(ctx) != "megacorp" {
 			return false
 		}
 		return true
-	}), mock.AnythingOfType("model.TraceID")).Return(nil, errStorage).Once()
+	}), mock.AnythingOfType("spanstore.GetTraceParameters")).Return(nil, errStorage).Once()
 
 	var responseAcme structuredResponse
 	err := getJSONCustomHeaders(
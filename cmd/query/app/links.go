@@ -0,0 +1,153 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// linkVariable matches the #{name} variables of the link templates, as in the link patterns of the UI.
+var linkVariable = regexp.MustCompile(`#\{([^{}]+)\}`)
+
+// linksConfig is the format of the links configuration file.
+type linksConfig struct {
+	Links []linkTemplate `json:"links"`
+}
+
+// linkTemplate describes a deep link to an external system, e.g. the logs of a span in Kibana.
+// The URL and the text may refer to variables: traceID, spanID, service, operation,
+// startTime and endTime (RFC 3339), startTimeMs and endTimeMs (Unix milliseconds),
+// or the key of a span tag or, failing that, of a process tag.
+type linkTemplate struct {
+	// Type is the kind of data the link points to, e.g. logs or metrics.
+	Type string `json:"type"`
+	// Text is the label of the link.
+	Text string `json:"text"`
+	// URL is the template of the link; the substituted values are URL-escaped.
+	URL string `json:"url"`
+	// Services restricts the link to the spans of these services; the link applies to all spans when empty.
+	Services []string `json:"services,omitempty"`
+}
+
+// spanLink is a link resolved for a span.
+type spanLink struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// linkResolver resolves the configured link templates for spans, so that the UI
+// and other clients share the same links configuration.
+type linkResolver struct {
+	templates []linkTemplate
+}
+
+func newLinkResolver(templates []linkTemplate) (*linkResolver, error) {
+	for i, template := range templates {
+		if template.Type == "" || template.URL == "" {
+			return nil, fmt.Errorf("link %d must have a type and a URL", i)
+		}
+	}
+	return &linkResolver{templates: templates}, nil
+}
+
+// loadLinkResolver reads the link templates from a JSON file.
+func loadLinkResolver(configFile string) (*linkResolver, error) {
+	bytes, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read links configuration: %w", err)
+	}
+	var config linksConfig
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse links configuration %s: %w", configFile, err)
+	}
+	if len(config.Links) == 0 {
+		return nil, errors.New("the links configuration has no links")
+	}
+	return newLinkResolver(config.Links)
+}
+
+// resolve returns the links of a span. A link is skipped when one of its variables
+// is not defined for the span, rather than pointing to an incomplete URL.
+func (r *linkResolver) resolve(span *model.Span) []spanLink {
+	links := []spanLink{}
+	for _, template := range r.templates {
+		if !template.appliesTo(span.Process.GetServiceName()) {
+			continue
+		}
+		linkURL, ok := substitute(template.URL, span, url.QueryEscape)
+		if !ok {
+			continue
+		}
+		text, ok := substitute(template.Text, span, func(s string) string { return s })
+		if !ok {
+			continue
+		}
+		links = append(links, spanLink{Type: template.Type, Text: text, URL: linkURL})
+	}
+	return links
+}
+
+func (t *linkTemplate) appliesTo(service string) bool {
+	if len(t.Services) == 0 {
+		return true
+	}
+	for _, s := range t.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+func substitute(template string, span *model.Span, escape func(string) string) (string, bool) {
+	ok := true
+	result := linkVariable.ReplaceAllStringFunc(template, func(match string) string {
+		value, found := linkVariableValue(linkVariable.FindStringSubmatch(match)[1], span)
+		if !found {
+			ok = false
+		}
+		return escape(value)
+	})
+	return result, ok
+}
+
+func linkVariableValue(name string, span *model.Span) (string, bool) {
+	switch name {
+	case "traceID":
+		return span.TraceID.String(), true
+	case "spanID":
+		return span.SpanID.String(), true
+	case "service":
+		return span.Process.GetServiceName(), true
+	case "operation":
+		return span.OperationName, true
+	case "startTime":
+		return span.StartTime.UTC().Format(time.RFC3339Nano), true
+	case "endTime":
+		return span.StartTime.Add(span.Duration).UTC().Format(time.RFC3339Nano), true
+	case "startTimeMs":
+		return strconv.FormatInt(span.StartTime.UnixMilli(), 10), true
+	case "endTimeMs":
+		return strconv.FormatInt(span.StartTime.Add(span.Duration).UnixMilli(), 10), true
+	}
+	if tag, ok := model.KeyValues(span.Tags).FindByKey(name); ok {
+		return tag.AsString(), true
+	}
+	if span.Process != nil {
+		if tag, ok := model.KeyValues(span.Process.Tags).FindByKey(name); ok {
+			return tag.AsString(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func newTestLinkSpan() *model.Span {
+	return &model.Span{
+		TraceID:       model.NewTraceID(0, 0x123456),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "GET /orders",
+		StartTime:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Duration:      1500 * time.Millisecond,
+		Tags:          []model.KeyValue{model.String("http.route", "/orders/{id}")},
+		Process: &model.Process{
+			ServiceName: "frontend",
+			Tags:        []model.KeyValue{model.String("hostname", "web-1"), model.String("http.route", "ignored")},
+		},
+	}
+}
+
+func TestLinkResolver(t *testing.T) {
+	resolver, err := newLinkResolver([]linkTemplate{
+		{
+			Type: "logs",
+			Text: "Logs of #{service} on #{hostname}",
+			URL:  "https://kibana.example.com/app/discover?q=trace.id:#{traceID}&from=#{startTime}&to=#{endTime}",
+		},
+		{
+			Type: "metrics",
+			Text: "Dashboard",
+			URL:  "https://grafana.example.com/d/http?var-route=#{http.route}&var-op=#{operation}&from=#{startTimeMs}&to=#{endTimeMs}&span=#{spanID}",
+		},
+		{Type: "logs", Text: "Splunk", URL: "https://splunk.example.com?pod=#{k8s.pod.name}"},
+		{Type: "logs", Text: "#{k8s.pod.name}", URL: "https://splunk.example.com"},
+		{Type: "logs", Text: "Backend logs", URL: "https://logs.example.com", Services: []string{"backend"}},
+		{Type: "logs", Text: "Frontend logs", URL: "https://logs.example.com", Services: []string{"backend", "frontend"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []spanLink{
+		{
+			Type: "logs",
+			Text: "Logs of frontend on web-1",
+			URL:  "https://kibana.example.com/app/discover?q=trace.id:0000000000123456&from=2024-05-01T12%3A00%3A00Z&to=2024-05-01T12%3A00%3A01.5Z",
+		},
+		{
+			Type: "metrics",
+			Text: "Dashboard",
+			URL:  "https://grafana.example.com/d/http?var-route=%2Forders%2F%7Bid%7D&var-op=GET+%2Forders&from=1714564800000&to=1714564801500&span=0000000000000001",
+		},
+		{Type: "logs", Text: "Frontend logs", URL: "https://logs.example.com"},
+	}, resolver.resolve(newTestLinkSpan()))
+
+	assert.Equal(t, []spanLink{}, resolver.resolve(&model.Span{OperationName: "no process"}))
+}
+
+func TestNewLinkResolverInvalid(t *testing.T) {
+	_, err := newLinkResolver([]linkTemplate{{Type: "logs", URL: "https://logs.example.com"}, {Text: "no type"}})
+	require.EqualError(t, err, "link 1 must have a type and a URL")
+}
+
+func TestLoadLinkResolver(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "links.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"links": [{"type": "logs", "text": "Logs", "url": "https://logs.example.com?trace=#{traceID}"}]}`), 0o600))
+	resolver, err := loadLinkResolver(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, []linkTemplate{{Type: "logs", Text: "Logs", URL: "https://logs.example.com?trace=#{traceID}"}}, resolver.templates)
+
+	_, err = loadLinkResolver(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "failed to read links configuration")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"links": `), 0o600))
+	_, err = loadLinkResolver(configFile)
+	require.ErrorContains(t, err, "failed to parse links configuration")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"links": []}`), 0o600))
+	_, err = loadLinkResolver(configFile)
+	require.EqualError(t, err, "the links configuration has no links")
+}
+
+func TestGetSpanLinks(t *testing.T) {
+	resolver, err := newLinkResolver([]linkTemplate{{Type: "logs", Text: "Logs", URL: "https://logs.example.com?span=#{spanID}"}})
+	require.NoError(t, err)
+	ts := initializeTestServer(HandlerOptions.Links(resolver))
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID}).
+		Return(mockTrace, nil)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}).
+		Return(nil, spanstore.ErrTraceNotFound)
+	ts.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 2)}).
+		Return(nil, errStorage)
+
+	var response struct {
+		Data []spanLink `json:"data"`
+	}
+	require.NoError(t, getJSON(ts.server.URL+`/api/traces/1e240/spans/2/links`, &response))
+	assert.Equal(t, []spanLink{{Type: "logs", Text: "Logs", URL: "https://logs.example.com?span=0000000000000002"}}, response.Data)
+
+	testCases := []struct {
+		name   string
+		url    string
+		status string
+	}{
+		{name: "span not found", url: "/api/traces/1e240/spans/3/links", status: "404 error from server"},
+		{name: "trace not found", url: "/api/traces/1/spans/1/links", status: "404 error from server"},
+		{name: "storage error", url: "/api/traces/2/spans/1/links", status: "500 error from server"},
+		{name: "bad span ID", url: "/api/traces/1e240/spans/xyz/links", status: "400 error from server"},
+		{name: "bad trace ID", url: "/api/traces/xyz/spans/1/links", status: "400 error from server"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := getJSON(ts.server.URL+tc.url, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.status)
+		})
+	}
+}
+
+func TestGetSpanLinksDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/traces/1e240/spans/1/links`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404 error from server")
+}
@@ -24,7 +24,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -44,6 +46,15 @@ const (
 	spanKindParam    = "spanKind"
 	endTimeParam     = "end"
 	prettyPrintParam = "prettyPrint"
+	sampleSizeParam  = "sampleSize"
+	stratifyByParam  = "stratifyBy"
+	strataParam      = "strata"
+	seedParam        = "seed"
+
+	serviceNamespaceParam  = "serviceNamespace"
+	serviceInstanceIDParam = "serviceInstanceID"
+
+	defaultSampleSize = 10
 )
 
 var (
@@ -112,8 +123,10 @@ func newDurationUnitsParser(units time.Duration) durationParser {
 // Trace query syntax:
 //
 //	query ::= param | param '&' query
-//	param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags
+//	param ::= service | serviceNamespace | serviceInstanceID | operation | limit | start | end | minDuration | maxDuration | tag | tags
 //	service ::= 'service=' strValue
+//	serviceNamespace ::= 'serviceNamespace=' strValue
+//	serviceInstanceID ::= 'serviceInstanceID=' strValue
 //	operation ::= 'operation=' strValue
 //	limit ::= 'limit=' intValue
 //	start ::= 'start=' intValue in unix microseconds
@@ -174,14 +187,16 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 
 	traceQuery := &traceQueryParameters{
 		TraceQueryParameters: spanstore.TraceQueryParameters{
-			ServiceName:   service,
-			OperationName: operation,
-			StartTimeMin:  startTime,
-			StartTimeMax:  endTime,
-			Tags:          tags,
-			NumTraces:     limit,
-			DurationMin:   minDuration,
-			DurationMax:   maxDuration,
+			ServiceName:       service,
+			OperationName:     operation,
+			StartTimeMin:      startTime,
+			StartTimeMax:      endTime,
+			Tags:              tags,
+			NumTraces:         limit,
+			DurationMin:       minDuration,
+			DurationMax:       maxDuration,
+			ServiceNamespace:  r.FormValue(serviceNamespaceParam),
+			ServiceInstanceID: r.FormValue(serviceInstanceIDParam),
 		},
 		traceIDs: traceIDs,
 	}
@@ -192,6 +207,33 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 	return traceQuery, nil
 }
 
+// parseTraceSamplingParams takes a request and constructs the parameters of a trace sample,
+// drawn from the traces matching the trace query.
+//
+// Trace sampling syntax, in addition to the trace query syntax:
+//
+//	sampleSize ::= 'sampleSize=' intValue, 10 by default
+//	stratifyBy ::= 'stratifyBy=' ( 'uniform' | 'duration' | 'error' ), uniform by default
+//	strata ::= 'strata=' intValue, the number of duration strata, 4 by default
+//	seed ::= 'seed=' intValue, 0 by default
+func (*queryParser) parseTraceSamplingParams(r *http.Request) (querysvc.TraceSamplingParameters, error) {
+	var params querysvc.TraceSamplingParameters
+	var err error
+	if params.SampleSize, err = parseInt(r, sampleSizeParam, defaultSampleSize); err != nil {
+		return params, err
+	}
+	if params.DurationStrata, err = parseInt(r, strataParam, querysvc.DefaultDurationStrata); err != nil {
+		return params, err
+	}
+	if formValue := r.FormValue(seedParam); formValue != "" {
+		if params.Seed, err = strconv.ParseInt(formValue, 10, 64); err != nil {
+			return params, newParseError(err, seedParam)
+		}
+	}
+	params.Strategy = querysvc.SamplingStrategy(r.FormValue(stratifyByParam))
+	return params, params.Validate()
+}
+
 // parseDependenciesQueryParams takes a request and constructs a model of dependencies query parameters.
 //
 // The dependencies API does not operate on the latency space, instead its timestamps are just time range selections,
@@ -207,6 +249,75 @@ func (p *queryParser) parseDependenciesQueryParams(r *http.Request) (dqp depende
 	return dqp, err
 }
 
+// parseDependenciesDiffQueryParams takes a request and constructs the current and base time ranges
+// of the dependencies diff query. The current time range uses the same parameters as the dependencies API,
+// while the base time range defaults to the time range of the same length right before the current one.
+//
+// Dependencies diff query syntax:
+//
+//	query ::= [ param ] | param '&' query
+//	param ::= endTs | lookback | baseEndTs | baseLookback | service
+//	endTs ::= 'endTs=' intValue in unix milliseconds
+//	lookback ::= 'lookback=' intValue duration in milliseconds
+//	baseEndTs ::= 'baseEndTs=' intValue in unix milliseconds
+//	baseLookback ::= 'baseLookback=' intValue duration in milliseconds
+//	service ::= 'service=' strValue
+func (p *queryParser) parseDependenciesDiffQueryParams(r *http.Request) (current, base dependenciesQueryParameters, err error) {
+	current, err = p.parseDependenciesQueryParams(r)
+	if err != nil {
+		return current, base, err
+	}
+	base.lookback, err = parseDuration(r, baseLookbackParam, newDurationUnitsParser(time.Millisecond), current.lookback)
+	if err != nil {
+		return current, base, err
+	}
+	base.endTs = current.endTs.Add(-current.lookback)
+	if r.FormValue(baseEndTsParam) != "" {
+		base.endTs, err = p.parseTime(r, baseEndTsParam, time.Millisecond)
+	}
+	return current, base, err
+}
+
+// parseTraceComparisonParams takes a request and returns the IDs of the base and current traces to compare.
+//
+// Trace comparison query syntax:
+//
+//	query ::= baseTraceIDs '&' traceIDs
+//	baseTraceIDs ::= baseTraceID | baseTraceID '&' baseTraceIDs
+//	traceIDs ::= traceID | traceID '&' traceIDs
+//	baseTraceID ::= 'baseTraceID=' traceIDValue
+//	traceID ::= 'traceID=' traceIDValue
+func (*queryParser) parseTraceComparisonParams(r *http.Request) (base, current []model.TraceID, err error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, err
+	}
+	base, err = parseTraceIDs(r, baseTraceIDParam)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err = parseTraceIDs(r, traceIDParam)
+	if err != nil {
+		return nil, nil, err
+	}
+	return base, current, nil
+}
+
+func parseTraceIDs(r *http.Request, paramName string) ([]model.TraceID, error) {
+	values := r.Form[paramName]
+	if len(values) == 0 {
+		return nil, fmt.Errorf("parameter '%s' is required", paramName)
+	}
+	traceIDs := make([]model.TraceID, 0, len(values))
+	for _, id := range values {
+		traceID, err := model.TraceIDFromString(id)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s param: %w", paramName, err)
+		}
+		traceIDs = append(traceIDs, traceID)
+	}
+	return traceIDs, nil
+}
+
 // parseMetricsQueryParams takes a request and constructs a model of metrics query parameters.
 //
 // Why the API is designed using an end time (endTs) and lookback:
@@ -283,8 +394,153 @@ func (p *queryParser) parseMetricsQueryParams(r *http.Request) (bqp metricsstore
 	return bqp, err
 }
 
+// parseSLOQueryParams takes a request and constructs the parameters of an SLO summary.
+// The windows default to the last hour, day and week, and the objective to 99.9%.
+//
+// SLO summary query syntax:
+//
+//	query ::= services , [ '&' optionalParams ]
+//	optionalParams := param | param '&' optionalParams
+//	param ::=  endTs | objective | windows | spanKinds
+//	services ::= service | service '&' services
+//	service ::= 'service=' strValue
+//	endTs ::= 'endTs=' intValue in unix milliseconds
+//	objective ::= 'objective=' floatValue in the range (0, 1), e.g. 0.999
+//	windows ::= window | window '&' windows
+//	window ::= 'window=' intValue duration in milliseconds
+//	spanKinds ::= spanKind | spanKind '&' spanKinds
+//	spanKind ::= 'spanKind=' spanKindType
+//	spanKindType ::= "unspecified" | "internal" | "server" | "client" | "producer" | "consumer"
+func (p *queryParser) parseSLOQueryParams(r *http.Request) (params querysvc.SLOQueryParameters, err error) {
+	query := r.URL.Query()
+	services, ok := query[serviceParam]
+	if !ok {
+		return params, newParseError(errors.New("please provide at least one service name"), serviceParam)
+	}
+	params.ServiceNames = services
+
+	params.SpanKinds, err = parseSpanKinds(r, spanKindParam, defaultMetricsSpanKinds)
+	if err != nil {
+		return params, err
+	}
+	params.EndTime, err = p.parseTime(r, endTsParam, time.Millisecond)
+	if err != nil {
+		return params, err
+	}
+
+	params.Objective = defaultSLOObjective
+	if value := query.Get(objectiveParam); value != "" {
+		params.Objective, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return params, newParseError(err, objectiveParam)
+		}
+		if params.Objective <= 0 || params.Objective >= 1 {
+			return params, newParseError(errors.New("objective must be between 0 and 1 (exclusive)"), objectiveParam)
+		}
+	}
+
+	windows, ok := query[windowParam]
+	if !ok {
+		params.Windows = defaultSLOWindows
+		return params, nil
+	}
+	parser := newDurationUnitsParser(time.Millisecond)
+	for _, value := range windows {
+		window, err := parser(value)
+		if err != nil {
+			return params, newParseError(err, windowParam)
+		}
+		if window <= 0 {
+			return params, newParseError(errors.New("window must be positive"), windowParam)
+		}
+		params.Windows = append(params.Windows, window)
+	}
+	return params, nil
+}
+
+// parseServiceSnapshotQueryParams takes a request and constructs the parameters of a service performance snapshot.
+// The operations are ranked by their 95th percentile latency over the last hour by default.
+//
+// Service snapshot query syntax:
+//
+//	query ::= service , [ '&' optionalParams ]
+//	optionalParams := param | param '&' optionalParams
+//	param ::=  endTs | lookback | quantile | limit | spanKinds
+//	service ::= 'service=' strValue
+//	endTs ::= 'endTs=' intValue in unix milliseconds
+//	lookback ::= 'lookback=' intValue duration in milliseconds
+//	quantile ::= 'quantile=' floatValue in the range (0, 1], e.g. 0.95
+//	limit ::= 'limit=' intValue, the number of operations and error traces
+//	spanKinds ::= spanKind | spanKind '&' spanKinds
+//	spanKind ::= 'spanKind=' spanKindType
+//	spanKindType ::= "unspecified" | "internal" | "server" | "client" | "producer" | "consumer"
+func (p *queryParser) parseServiceSnapshotQueryParams(r *http.Request) (params querysvc.ServiceSnapshotParameters, err error) {
+	params.ServiceName = r.FormValue(serviceParam)
+	if params.ServiceName == "" {
+		return params, errServiceParameterRequired
+	}
+	params.SpanKinds, err = parseSpanKinds(r, spanKindParam, defaultMetricsSpanKinds)
+	if err != nil {
+		return params, err
+	}
+	params.EndTime, err = p.parseTime(r, endTsParam, time.Millisecond)
+	if err != nil {
+		return params, err
+	}
+	params.Lookback, err = parseDuration(r, lookbackParam, newDurationUnitsParser(time.Millisecond), defaultMetricsQueryLookbackDuration)
+	if err != nil {
+		return params, err
+	}
+	if params.Lookback <= 0 {
+		return params, newParseError(errors.New("lookback must be positive"), lookbackParam)
+	}
+
+	params.Quantile = defaultSnapshotQuantile
+	if value := r.FormValue(quantileParam); value != "" {
+		params.Quantile, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return params, newParseError(err, quantileParam)
+		}
+		if params.Quantile <= 0 || params.Quantile > 1 {
+			return params, newParseError(errors.New("quantile must be between 0 (exclusive) and 1"), quantileParam)
+		}
+	}
+
+	params.Limit, err = parseInt(r, limitParam, defaultSnapshotLimit)
+	if err != nil {
+		return params, err
+	}
+	if params.Limit <= 0 {
+		return params, newParseError(errors.New("limit must be positive"), limitParam)
+	}
+	return params, nil
+}
+
 // parseTime parses the time parameter of an HTTP request that is represented the number of "units" since epoch.
 // If the time parameter is empty, the current time will be returned.
+// parseLiveTailFilter takes a request and constructs a live tail filter.
+//
+// The following formats are supported:
+//
+//	?service=foo&operation=bar&tag=k:v&tags={"k2":"v2"}
+//
+// The service is required; operation and tags are optional.
+func (p *queryParser) parseLiveTailFilter(r *http.Request) (livetail.Filter, error) {
+	service := r.FormValue(serviceParam)
+	if service == "" {
+		return livetail.Filter{}, errServiceParameterRequired
+	}
+	tags, err := p.parseTags(r.Form[tagParam], r.Form[tagsParam])
+	if err != nil {
+		return livetail.Filter{}, err
+	}
+	return livetail.Filter{
+		ServiceName:   service,
+		OperationName: r.FormValue(operationParam),
+		Tags:          tags,
+	}, nil
+}
+
 func (p *queryParser) parseTime(r *http.Request, paramName string, units time.Duration) (time.Time, error) {
 	formValue := r.FormValue(paramName)
 	if formValue == "" {
@@ -300,6 +556,23 @@ func (p *queryParser) parseTime(r *http.Request, paramName string, units time.Du
 	return time.Unix(0, 0).Add(time.Duration(t) * units), nil
 }
 
+// parseTraceTimeWindow parses the optional start/end parameters (in unix microseconds) that callers
+// may pass when retrieving traces by ID to narrow the storage search window.
+// Absent parameters are returned as zero time, meaning no hint.
+func (p *queryParser) parseTraceTimeWindow(r *http.Request) (startTime, endTime time.Time, err error) {
+	if r.FormValue(startTimeParam) != "" {
+		if startTime, err = p.parseTime(r, startTimeParam, time.Microsecond); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if r.FormValue(endTimeParam) != "" {
+		if endTime, err = p.parseTime(r, endTimeParam, time.Microsecond); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return startTime, endTime, nil
+}
+
 // parseDuration parses the duration parameter of an HTTP request using the provided durationParser.
 // If the duration parameter is empty, the given defaultDuration will be returned.
 func parseDuration(r *http.Request, paramName string, parse durationParser, defaultDuration time.Duration) (time.Duration, error) {
@@ -314,6 +587,18 @@ func parseDuration(r *http.Request, paramName string, parse durationParser, defa
 	return d, nil
 }
 
+func parseInt(r *http.Request, paramName string, defaultValue int) (int, error) {
+	formVal := r.FormValue(paramName)
+	if formVal == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(formVal)
+	if err != nil {
+		return 0, newParseError(err, paramName)
+	}
+	return i, nil
+}
+
 func parseBool(r *http.Request, paramName string) (b bool, err error) {
 	formVal := r.FormValue(paramName)
 	if formVal == "" {
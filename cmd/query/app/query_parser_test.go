@@ -26,7 +26,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -62,6 +64,20 @@ func TestParseTraceQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			"x?service=service&serviceNamespace=shop&serviceInstanceID=service-1&start=0&end=0", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:       "service",
+					ServiceNamespace:  "shop",
+					ServiceInstanceID: "service-1",
+					StartTimeMin:      time.Unix(0, 0),
+					StartTimeMax:      time.Unix(0, 0),
+					NumTraces:         100,
+					Tags:              map[string]string{},
+				},
+			},
+		},
 		// tags=JSON with a non-string value 123
 		{`x?service=service&start=0&end=0&operation=operation&limit=200&tag=k:v&tags={"x":123}`, "malformed 'tags' parameter, cannot unmarshal JSON: json: cannot unmarshal number into Go value of type string", nil},
 		// tags=JSON
@@ -236,6 +252,269 @@ func TestParseRepeatedServices(t *testing.T) {
 	assert.Equal(t, []string{"foo", "bar"}, mqp.ServiceNames)
 }
 
+func TestParseTraceSamplingParams(t *testing.T) {
+	parser := &queryParser{
+		timeNow: time.Now,
+	}
+	testCases := []struct {
+		url    string
+		params querysvc.TraceSamplingParameters
+		errMsg string
+	}{
+		{
+			url:    "x?service=foo",
+			params: querysvc.TraceSamplingParameters{SampleSize: defaultSampleSize, DurationStrata: querysvc.DefaultDurationStrata},
+		},
+		{
+			url:    "x?sampleSize=5&stratifyBy=duration&strata=2&seed=-7",
+			params: querysvc.TraceSamplingParameters{SampleSize: 5, Strategy: querysvc.SampleByDuration, DurationStrata: 2, Seed: -7},
+		},
+		{url: "x?sampleSize=many", errMsg: "unable to parse param 'sampleSize'"},
+		{url: "x?strata=many", errMsg: "unable to parse param 'strata'"},
+		{url: "x?seed=random", errMsg: "unable to parse param 'seed'"},
+		{url: "x?sampleSize=0", errMsg: "sample size must be positive, got 0"},
+		{url: "x?stratifyBy=operation", errMsg: `unknown sampling strategy "operation"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			params, err := parser.parseTraceSamplingParams(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
+
+func TestParseDependenciesDiffQueryParams(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	parser := &queryParser{
+		timeNow: func() time.Time { return now },
+	}
+	endTs := time.UnixMilli(1476374248550)
+	testCases := []struct {
+		url     string
+		current dependenciesQueryParameters
+		base    dependenciesQueryParameters
+		errMsg  string
+	}{
+		{
+			url:     "x",
+			current: dependenciesQueryParameters{endTs: now, lookback: defaultDependencyLookbackDuration},
+			base:    dependenciesQueryParameters{endTs: now.Add(-defaultDependencyLookbackDuration), lookback: defaultDependencyLookbackDuration},
+		},
+		{
+			url:     "x?endTs=1476374248550&lookback=3600000",
+			current: dependenciesQueryParameters{endTs: endTs, lookback: time.Hour},
+			base:    dependenciesQueryParameters{endTs: endTs.Add(-time.Hour), lookback: time.Hour},
+		},
+		{
+			url:     "x?endTs=1476374248550&lookback=3600000&baseEndTs=1475769448550&baseLookback=7200000",
+			current: dependenciesQueryParameters{endTs: endTs, lookback: time.Hour},
+			base:    dependenciesQueryParameters{endTs: endTs.Add(-7 * 24 * time.Hour), lookback: 2 * time.Hour},
+		},
+		{url: "x?endTs=yesterday", errMsg: "unable to parse param 'endTs'"},
+		{url: "x?baseEndTs=yesterday", errMsg: "unable to parse param 'baseEndTs'"},
+		{url: "x?baseLookback=1h", errMsg: "unable to parse param 'baseLookback'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			current, base, err := parser.parseDependenciesDiffQueryParams(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.current, current)
+			assert.Equal(t, tc.base, base)
+		})
+	}
+}
+
+func TestParseTraceComparisonParams(t *testing.T) {
+	parser := &queryParser{}
+	testCases := []struct {
+		url     string
+		base    []model.TraceID
+		current []model.TraceID
+		errMsg  string
+	}{
+		{
+			url:     "x?baseTraceID=1&baseTraceID=2&traceID=3",
+			base:    []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)},
+			current: []model.TraceID{model.NewTraceID(0, 3)},
+		},
+		{url: "x?traceID=3", errMsg: "parameter 'baseTraceID' is required"},
+		{url: "x?baseTraceID=1", errMsg: "parameter 'traceID' is required"},
+		{url: "x?baseTraceID=x&traceID=3", errMsg: "cannot parse baseTraceID param"},
+		{url: "x?baseTraceID=1&traceID=x", errMsg: "cannot parse traceID param"},
+		{url: "x?baseTraceID=%zz", errMsg: "invalid URL escape"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			base, current, err := parser.parseTraceComparisonParams(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.base, base)
+			assert.Equal(t, tc.current, current)
+		})
+	}
+}
+
+func TestParseSLOQueryParams(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	parser := &queryParser{
+		timeNow: func() time.Time { return now },
+	}
+	testCases := []struct {
+		url    string
+		params querysvc.SLOQueryParameters
+		errMsg string
+	}{
+		{
+			url: "x?service=foo",
+			params: querysvc.SLOQueryParameters{
+				ServiceNames: []string{"foo"},
+				SpanKinds:    []string{"SPAN_KIND_SERVER"},
+				EndTime:      now,
+				Objective:    0.999,
+				Windows:      []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour},
+			},
+		},
+		{
+			url: "x?service=foo&service=bar&endTs=1476374248550&objective=0.99&window=3600000&window=2592000000&spanKind=client",
+			params: querysvc.SLOQueryParameters{
+				ServiceNames: []string{"foo", "bar"},
+				SpanKinds:    []string{"SPAN_KIND_CLIENT"},
+				EndTime:      time.UnixMilli(1476374248550),
+				Objective:    0.99,
+				Windows:      []time.Duration{time.Hour, 30 * 24 * time.Hour},
+			},
+		},
+		{url: "x", errMsg: "please provide at least one service name"},
+		{url: "x?service=foo&spanKind=other", errMsg: "unsupported span kind: 'other'"},
+		{url: "x?service=foo&endTs=yesterday", errMsg: "unable to parse param 'endTs'"},
+		{url: "x?service=foo&objective=high", errMsg: "unable to parse param 'objective'"},
+		{url: "x?service=foo&objective=1", errMsg: "objective must be between 0 and 1 (exclusive)"},
+		{url: "x?service=foo&window=1h", errMsg: "unable to parse param 'window'"},
+		{url: "x?service=foo&window=0", errMsg: "window must be positive"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			params, err := parser.parseSLOQueryParams(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
+
+func TestParseServiceSnapshotQueryParams(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	parser := &queryParser{
+		timeNow: func() time.Time { return now },
+	}
+	testCases := []struct {
+		url    string
+		params querysvc.ServiceSnapshotParameters
+		errMsg string
+	}{
+		{
+			url: "x?service=foo",
+			params: querysvc.ServiceSnapshotParameters{
+				ServiceName: "foo",
+				SpanKinds:   []string{"SPAN_KIND_SERVER"},
+				EndTime:     now,
+				Lookback:    time.Hour,
+				Quantile:    0.95,
+				Limit:       10,
+			},
+		},
+		{
+			url: "x?service=foo&endTs=1476374248550&lookback=86400000&quantile=0.99&limit=5&spanKind=client",
+			params: querysvc.ServiceSnapshotParameters{
+				ServiceName: "foo",
+				SpanKinds:   []string{"SPAN_KIND_CLIENT"},
+				EndTime:     time.UnixMilli(1476374248550),
+				Lookback:    24 * time.Hour,
+				Quantile:    0.99,
+				Limit:       5,
+			},
+		},
+		{url: "x", errMsg: "parameter 'service' is required"},
+		{url: "x?service=foo&spanKind=other", errMsg: "unsupported span kind: 'other'"},
+		{url: "x?service=foo&endTs=yesterday", errMsg: "unable to parse param 'endTs'"},
+		{url: "x?service=foo&lookback=1h", errMsg: "unable to parse param 'lookback'"},
+		{url: "x?service=foo&lookback=0", errMsg: "lookback must be positive"},
+		{url: "x?service=foo&quantile=p95", errMsg: "unable to parse param 'quantile'"},
+		{url: "x?service=foo&quantile=95", errMsg: "quantile must be between 0 (exclusive) and 1"},
+		{url: "x?service=foo&limit=ten", errMsg: "unable to parse param 'limit'"},
+		{url: "x?service=foo&limit=0", errMsg: "limit must be positive"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			params, err := parser.parseServiceSnapshotQueryParams(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
+
+func TestParseLiveTailFilter(t *testing.T) {
+	parser := &queryParser{
+		timeNow: time.Now,
+	}
+	testCases := []struct {
+		url    string
+		filter livetail.Filter
+		errMsg string
+	}{
+		{url: "x?service=foo", filter: livetail.Filter{ServiceName: "foo", Tags: map[string]string{}}},
+		{
+			url:    `x?service=foo&operation=bar&tag=k:v&tags={"k2":"v2"}`,
+			filter: livetail.Filter{ServiceName: "foo", OperationName: "bar", Tags: map[string]string{"k": "v", "k2": "v2"}},
+		},
+		{url: "x?operation=bar", errMsg: "parameter 'service' is required"},
+		{url: "x?service=foo&tag=k", errMsg: "malformed 'tag' parameter"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			require.NoError(t, request.ParseForm())
+			filter, err := parser.parseLiveTailFilter(request)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.filter, filter)
+		})
+	}
+}
+
 func TestParseRepeatedSpanKinds(t *testing.T) {
 	q := "x?service=foo&spanKind=unspecified&spanKind=internal&spanKind=server&spanKind=client&spanKind=producer&spanKind=consumer"
 	request, err := http.NewRequest(http.MethodGet, q, nil)
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// receivedTimeTag is the tag with the time a span was received by the collector, added when
+// the collector records the timestamp provenance attribute.
+const receivedTimeTag = "jaeger.ingest.timestamp"
+
+// TraceCompleteness estimates whether all the spans of a trace are stored, so that clients can
+// refresh a trace still being received instead of showing it partially.
+type TraceCompleteness struct {
+	// MissingParentSpans is the number of spans whose parent span is not in the trace,
+	// because it was not received yet or was dropped.
+	MissingParentSpans int
+	// LastReceived is the latest time a span of the trace was received, or ended when
+	// the collectors do not record the time the spans are received.
+	LastReceived time.Time
+	// StillReceiving is whether the last span was received within the completeness window,
+	// in which case more spans are likely to follow.
+	StillReceiving bool
+}
+
+// Complete returns whether the trace looks complete.
+func (c TraceCompleteness) Complete() bool {
+	return c.MissingParentSpans == 0 && !c.StillReceiving
+}
+
+// EstimateCompleteness inspects the span references and the times the spans were received to
+// estimate whether the trace is complete at the time now. The trace is considered still
+// receiving spans when the last one was received within the window, never if it is zero.
+func EstimateCompleteness(trace *model.Trace, now time.Time, window time.Duration) TraceCompleteness {
+	var completeness TraceCompleteness
+	spanIDs := make(map[model.SpanID]struct{}, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spanIDs[span.SpanID] = struct{}{}
+	}
+	for _, span := range trace.Spans {
+		if parentID := span.ParentSpanID(); parentID != 0 {
+			if _, ok := spanIDs[parentID]; !ok {
+				completeness.MissingParentSpans++
+			}
+		}
+		if received := receivedTime(span); received.After(completeness.LastReceived) {
+			completeness.LastReceived = received
+		}
+	}
+	completeness.StillReceiving = window > 0 && now.Sub(completeness.LastReceived) < window
+	return completeness
+}
+
+func receivedTime(span *model.Span) time.Time {
+	if tag, ok := model.KeyValues(span.Tags).FindByKey(receivedTimeTag); ok {
+		if received, err := time.Parse(time.RFC3339Nano, tag.AsString()); err == nil {
+			return received
+		}
+	}
+	return span.StartTime.Add(span.Duration)
+}
+
+// TraceCompleteness estimates whether the trace is complete, see EstimateCompleteness.
+func (qs QueryService) TraceCompleteness(trace *model.Trace) TraceCompleteness {
+	return EstimateCompleteness(trace, time.Now(), qs.options.CompletenessWindow)
+}
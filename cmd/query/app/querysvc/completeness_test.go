@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestEstimateCompleteness(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	traceID := model.NewTraceID(0, 1)
+	root := &model.Span{
+		TraceID:   traceID,
+		SpanID:    1,
+		StartTime: now.Add(-time.Minute),
+		Duration:  10 * time.Second,
+	}
+	child := &model.Span{
+		TraceID:    traceID,
+		SpanID:     2,
+		References: model.MaybeAddParentSpanID(traceID, 1, nil),
+		StartTime:  now.Add(-55 * time.Second),
+		Duration:   time.Second,
+	}
+	orphan := &model.Span{
+		TraceID:    traceID,
+		SpanID:     3,
+		References: model.MaybeAddParentSpanID(traceID, 4, nil),
+		StartTime:  now.Add(-time.Minute),
+		Duration:   time.Second,
+		Tags:       model.KeyValues{model.String(receivedTimeTag, now.Add(-5*time.Second).Format(time.RFC3339Nano))},
+	}
+
+	completeness := EstimateCompleteness(&model.Trace{Spans: []*model.Span{root, child}}, now, 30*time.Second)
+	assert.Equal(t, TraceCompleteness{LastReceived: now.Add(-50 * time.Second)}, completeness)
+	assert.True(t, completeness.Complete())
+
+	completeness = EstimateCompleteness(&model.Trace{Spans: []*model.Span{root, child, orphan}}, now, 30*time.Second)
+	assert.Equal(t, TraceCompleteness{
+		MissingParentSpans: 1,
+		LastReceived:       now.Add(-5 * time.Second),
+		StillReceiving:     true,
+	}, completeness, "the received time tag is used over the span end time")
+	assert.False(t, completeness.Complete())
+
+	completeness = EstimateCompleteness(&model.Trace{Spans: []*model.Span{root, orphan}}, now, 0)
+	assert.False(t, completeness.StillReceiving, "the window is disabled")
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// DependenciesTimeRange selects the dependency links reported within Lookback before EndTs.
+type DependenciesTimeRange struct {
+	EndTs    time.Time
+	Lookback time.Duration
+}
+
+// DependencyLinkDiff is a dependency link with its call counts in the two compared graphs.
+type DependencyLinkDiff struct {
+	Parent        string
+	Child         string
+	BaseCallCount uint64
+	CallCount     uint64
+}
+
+// CallCountDelta returns the change of the call count from the base graph.
+func (d DependencyLinkDiff) CallCountDelta() int64 {
+	return int64(d.CallCount) - int64(d.BaseCallCount)
+}
+
+// DependenciesDiff is the difference between a base and a current dependency graph.
+type DependenciesDiff struct {
+	// Base and Current are the compared graphs, with a single link per parent and child.
+	Base    []model.DependencyLink
+	Current []model.DependencyLink
+	// Added are the links only in the current graph.
+	Added []DependencyLinkDiff
+	// Removed are the links only in the base graph.
+	Removed []DependencyLinkDiff
+	// Changed are the links in both graphs with a different call count.
+	Changed []DependencyLinkDiff
+}
+
+// GetDependenciesDiff fetches the dependency graphs of the base and current time ranges
+// and computes their difference. When service is not empty, only the links from or to
+// the service are compared.
+func (qs QueryService) GetDependenciesDiff(
+	ctx context.Context,
+	base DependenciesTimeRange,
+	current DependenciesTimeRange,
+	service string,
+) (*DependenciesDiff, error) {
+	baseLinks, err := qs.GetDependencies(ctx, base.EndTs, base.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	currentLinks, err := qs.GetDependencies(ctx, current.EndTs, current.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	return DiffDependencies(baseLinks, currentLinks, service), nil
+}
+
+// DiffDependencies computes the difference between the base and current dependency links.
+// All results are sorted by parent and child.
+func DiffDependencies(base, current []model.DependencyLink, service string) *DependenciesDiff {
+	type edge struct {
+		parent string
+		child  string
+	}
+	edges := make(map[edge]*DependencyLinkDiff)
+	add := func(links []model.DependencyLink, count func(*DependencyLinkDiff) *uint64) {
+		for _, l := range links {
+			if service != "" && l.Parent != service && l.Child != service {
+				continue
+			}
+			e := edge{parent: l.Parent, child: l.Child}
+			d, ok := edges[e]
+			if !ok {
+				d = &DependencyLinkDiff{Parent: l.Parent, Child: l.Child}
+				edges[e] = d
+			}
+			*count(d) += l.CallCount
+		}
+	}
+	add(base, func(d *DependencyLinkDiff) *uint64 { return &d.BaseCallCount })
+	add(current, func(d *DependencyLinkDiff) *uint64 { return &d.CallCount })
+
+	links := make([]*DependencyLinkDiff, 0, len(edges))
+	for _, d := range edges {
+		links = append(links, d)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+
+	diff := &DependenciesDiff{
+		Base:    []model.DependencyLink{},
+		Current: []model.DependencyLink{},
+		Added:   []DependencyLinkDiff{},
+		Removed: []DependencyLinkDiff{},
+		Changed: []DependencyLinkDiff{},
+	}
+	for _, d := range links {
+		inBase, inCurrent := d.BaseCallCount > 0, d.CallCount > 0
+		if inBase {
+			diff.Base = append(diff.Base, model.DependencyLink{Parent: d.Parent, Child: d.Child, CallCount: d.BaseCallCount})
+		}
+		if inCurrent {
+			diff.Current = append(diff.Current, model.DependencyLink{Parent: d.Parent, Child: d.Child, CallCount: d.CallCount})
+		}
+		switch {
+		case inCurrent && !inBase:
+			diff.Added = append(diff.Added, *d)
+		case inBase && !inCurrent:
+			diff.Removed = append(diff.Removed, *d)
+		case d.BaseCallCount != d.CallCount:
+			diff.Changed = append(diff.Changed, *d)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestDiffDependencies(t *testing.T) {
+	base := []model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 10},
+		{Parent: "frontend", Child: "orders", CallCount: 5},
+		{Parent: "orders", Child: "mysql", CallCount: 30},
+		{Parent: "orders", Child: "legacy-billing", CallCount: 7},
+		{Parent: "frontend", Child: "search", CallCount: 3},
+	}
+	current := []model.DependencyLink{
+		{Parent: "orders", Child: "mysql", CallCount: 20},
+		{Parent: "frontend", Child: "orders", CallCount: 15},
+		{Parent: "orders", Child: "billing", CallCount: 8},
+		{Parent: "frontend", Child: "search", CallCount: 4},
+	}
+
+	diff := DiffDependencies(base, current, "")
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 15},
+		{Parent: "frontend", Child: "search", CallCount: 3},
+		{Parent: "orders", Child: "legacy-billing", CallCount: 7},
+		{Parent: "orders", Child: "mysql", CallCount: 30},
+	}, diff.Base)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 15},
+		{Parent: "frontend", Child: "search", CallCount: 4},
+		{Parent: "orders", Child: "billing", CallCount: 8},
+		{Parent: "orders", Child: "mysql", CallCount: 20},
+	}, diff.Current)
+	assert.Equal(t, []DependencyLinkDiff{{Parent: "orders", Child: "billing", CallCount: 8}}, diff.Added)
+	assert.Equal(t, []DependencyLinkDiff{{Parent: "orders", Child: "legacy-billing", BaseCallCount: 7}}, diff.Removed)
+	assert.Equal(t, []DependencyLinkDiff{
+		{Parent: "frontend", Child: "search", BaseCallCount: 3, CallCount: 4},
+		{Parent: "orders", Child: "mysql", BaseCallCount: 30, CallCount: 20},
+	}, diff.Changed)
+	assert.Equal(t, int64(1), diff.Changed[0].CallCountDelta())
+	assert.Equal(t, int64(-10), diff.Changed[1].CallCountDelta())
+	assert.Equal(t, int64(-7), diff.Removed[0].CallCountDelta())
+
+	diff = DiffDependencies(base, current, "mysql")
+	assert.Equal(t, []model.DependencyLink{{Parent: "orders", Child: "mysql", CallCount: 30}}, diff.Base)
+	assert.Equal(t, []model.DependencyLink{{Parent: "orders", Child: "mysql", CallCount: 20}}, diff.Current)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Len(t, diff.Changed, 1)
+}
+
+func TestDiffDependenciesEmpty(t *testing.T) {
+	diff := DiffDependencies(nil, nil, "")
+	assert.Equal(t, &DependenciesDiff{
+		Base:    []model.DependencyLink{},
+		Current: []model.DependencyLink{},
+		Added:   []DependencyLinkDiff{},
+		Removed: []DependencyLinkDiff{},
+		Changed: []DependencyLinkDiff{},
+	}, diff)
+}
+
+func TestGetDependenciesDiff(t *testing.T) {
+	tqs := initializeTestService()
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	baseEndTs := endTs.Add(-defaultDependencyLookbackDuration)
+	tqs.depsReader.On("GetDependencies", mock.Anything, baseEndTs, defaultDependencyLookbackDuration).
+		Return([]model.DependencyLink{{Parent: "killer", Child: "queen", CallCount: 12}}, nil).Once()
+	tqs.depsReader.On("GetDependencies", mock.Anything, endTs, time.Hour).
+		Return([]model.DependencyLink{{Parent: "killer", Child: "queen", CallCount: 2}}, nil).Once()
+
+	diff, err := tqs.queryService.GetDependenciesDiff(context.Background(),
+		DependenciesTimeRange{EndTs: baseEndTs, Lookback: defaultDependencyLookbackDuration},
+		DependenciesTimeRange{EndTs: endTs, Lookback: time.Hour},
+		"")
+	require.NoError(t, err)
+	assert.Equal(t, []DependencyLinkDiff{{Parent: "killer", Child: "queen", BaseCallCount: 12, CallCount: 2}}, diff.Changed)
+	tqs.depsReader.AssertExpectations(t)
+}
+
+func TestGetDependenciesDiffFailure(t *testing.T) {
+	errStorage := errors.New("storage error")
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	baseEndTs := endTs.Add(-time.Hour)
+
+	tqs := initializeTestService()
+	tqs.depsReader.On("GetDependencies", mock.Anything, baseEndTs, time.Hour).Return(nil, errStorage).Once()
+	_, err := tqs.queryService.GetDependenciesDiff(context.Background(),
+		DependenciesTimeRange{EndTs: baseEndTs, Lookback: time.Hour},
+		DependenciesTimeRange{EndTs: endTs, Lookback: time.Hour},
+		"")
+	require.ErrorIs(t, err, errStorage)
+
+	tqs = initializeTestService()
+	tqs.depsReader.On("GetDependencies", mock.Anything, baseEndTs, time.Hour).Return(nil, nil).Once()
+	tqs.depsReader.On("GetDependencies", mock.Anything, endTs, time.Hour).Return(nil, errStorage).Once()
+	_, err = tqs.queryService.GetDependenciesDiff(context.Background(),
+		DependenciesTimeRange{EndTs: baseEndTs, Lookback: time.Hour},
+		DependenciesTimeRange{EndTs: endTs, Lookback: time.Hour},
+		"")
+	require.ErrorIs(t, err, errStorage)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// dependenciesFallbackCacheSize is the maximum number of dependency graphs kept by a DependenciesFallback.
+const dependenciesFallbackCacheSize = 100
+
+// DependenciesFallbackOptions configures the computation of the dependencies from the spans
+// when the dependency storage has none, e.g. because no job aggregates them.
+type DependenciesFallbackOptions struct {
+	// MaxTraces is the maximum number of traces the dependencies are computed from.
+	MaxTraces int `mapstructure:"max_traces"`
+	// CacheTTL is how long the computed dependencies are reused for the requests of a similar time window.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// Enabled returns whether the dependencies are computed from the spans.
+func (o DependenciesFallbackOptions) Enabled() bool {
+	return o.MaxTraces > 0
+}
+
+// DependenciesFallback computes the dependency links on demand from the traces of the time
+// window, for the time windows without dependencies in the dependency storage.
+//
+// As for the cached dependency stores, the end of the time window is truncated to the cache TTL
+// to build the cache key, so that the repeated requests of the UI share the computed links.
+type DependenciesFallback struct {
+	maxTraces int
+	ttl       time.Duration
+	cache     cache.Cache
+}
+
+// NewDependenciesFallback creates a DependenciesFallback from the options.
+func NewDependenciesFallback(options DependenciesFallbackOptions) *DependenciesFallback {
+	f := &DependenciesFallback{
+		maxTraces: options.MaxTraces,
+		ttl:       options.CacheTTL,
+	}
+	if f.ttl > 0 {
+		f.cache = cache.NewLRUWithOptions(dependenciesFallbackCacheSize, &cache.Options{TTL: f.ttl})
+	}
+	return f
+}
+
+// GetDependencies returns the dependency links between the services of the traces found
+// in the time window, at most MaxTraces traces evenly split between the services.
+func (f *DependenciesFallback) GetDependencies(
+	ctx context.Context,
+	spanReader spanstore.Reader,
+	endTs time.Time,
+	lookback time.Duration,
+) ([]model.DependencyLink, error) {
+	var key string
+	if f.cache != nil {
+		key = fmt.Sprintf("%s|%d|%d", tenancy.GetTenant(ctx), endTs.Truncate(f.ttl).UnixNano(), lookback)
+		if links, ok := f.cache.Get(key).([]model.DependencyLink); ok {
+			return slices.Clone(links), nil
+		}
+	}
+
+	services, err := spanReader.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(services)
+	tracesPerService := max(f.maxTraces/max(len(services), 1), 1)
+	seen := make(map[model.TraceID]struct{})
+	counts := make(map[[2]string]uint64)
+	for _, service := range services {
+		if len(seen) >= f.maxTraces {
+			break
+		}
+		traces, err := spanReader.FindTraces(ctx, &spanstore.TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: endTs.Add(-lookback),
+			StartTimeMax: endTs,
+			NumTraces:    tracesPerService,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			if len(trace.Spans) == 0 {
+				continue
+			}
+			// the traces of the services calling each other are found for each of them
+			if _, ok := seen[trace.Spans[0].TraceID]; ok {
+				continue
+			}
+			seen[trace.Spans[0].TraceID] = struct{}{}
+			addTraceDependencies(trace, counts)
+		}
+	}
+
+	links := make([]model.DependencyLink, 0, len(counts))
+	for edge, count := range counts {
+		links = append(links, model.DependencyLink{Parent: edge[0], Child: edge[1], CallCount: count})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+	if f.cache != nil {
+		f.cache.Put(key, slices.Clone(links))
+	}
+	return links, nil
+}
+
+// addTraceDependencies counts a call for every span whose parent span is of another service.
+func addTraceDependencies(trace *model.Trace, counts map[[2]string]uint64) {
+	services := make(map[model.SpanID]string, len(trace.Spans))
+	for _, span := range trace.Spans {
+		services[span.SpanID] = span.GetProcess().GetServiceName()
+	}
+	for _, span := range trace.Spans {
+		parent, ok := services[span.ParentSpanID()]
+		if !ok {
+			continue
+		}
+		if child := span.GetProcess().GetServiceName(); parent != child {
+			counts[[2]string{parent, child}]++
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var fallbackEndTs = time.Unix(1700000000, 0)
+
+// callTrace creates a trace whose spans are each the child of the previous one, of the given services.
+func callTrace(id uint64, services ...string) *model.Trace {
+	trace := &model.Trace{}
+	for i, service := range services {
+		span := &model.Span{
+			TraceID: model.NewTraceID(0, id),
+			SpanID:  model.NewSpanID(uint64(i + 1)),
+			Process: &model.Process{ServiceName: service},
+		}
+		if i > 0 {
+			span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(uint64(i)))}
+		}
+		trace.Spans = append(trace.Spans, span)
+	}
+	return trace
+}
+
+func withDependenciesFallback(options DependenciesFallbackOptions) testOption {
+	return func(_ *testQueryService, opts *QueryServiceOptions) {
+		opts.DependenciesFallback = NewDependenciesFallback(options)
+	}
+}
+
+func findTracesOf(service string, numTraces int) any {
+	return mock.MatchedBy(func(p *spanstore.TraceQueryParameters) bool {
+		return p.ServiceName == service && p.NumTraces == numTraces &&
+			p.StartTimeMin.Equal(fallbackEndTs.Add(-time.Hour)) && p.StartTimeMax.Equal(fallbackEndTs)
+	})
+}
+
+func TestGetDependenciesFallback(t *testing.T) {
+	tqs := initializeTestService(withDependenciesFallback(DependenciesFallbackOptions{MaxTraces: 4, CacheTTL: time.Minute}))
+	tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, time.Hour).Return(nil, nil)
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"mysql", "frontend"}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, findTracesOf("frontend", 2)).Return([]*model.Trace{
+		callTrace(1, "frontend", "frontend", "orders", "mysql"),
+		callTrace(2, "frontend", "orders"),
+		{},
+	}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, findTracesOf("mysql", 2)).Return([]*model.Trace{
+		callTrace(1, "frontend", "frontend", "orders", "mysql"),
+		callTrace(3, "orders", "mysql"),
+	}, nil).Once()
+
+	expected := []model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 2},
+		{Parent: "orders", Child: "mysql", CallCount: 2},
+	}
+	links, err := tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, expected, links)
+
+	// the links are cached for the requests of a similar time window
+	links, err = tqs.queryService.GetDependencies(context.Background(), fallbackEndTs.Add(time.Millisecond), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, expected, links)
+	tqs.spanReader.AssertExpectations(t)
+}
+
+func TestGetDependenciesFallbackMaxTraces(t *testing.T) {
+	tqs := initializeTestService(withDependenciesFallback(DependenciesFallbackOptions{MaxTraces: 1}))
+	tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, time.Hour).Return([]model.DependencyLink{}, nil)
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend", "orders"}, nil).Twice()
+	tqs.spanReader.On("FindTraces", mock.Anything, findTracesOf("frontend", 1)).
+		Return([]*model.Trace{callTrace(1, "frontend", "orders")}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		links, err := tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{{Parent: "frontend", Child: "orders", CallCount: 1}}, links)
+	}
+	// the traces of the other services are not searched once there are enough, and the links are not cached
+	tqs.spanReader.AssertExpectations(t)
+}
+
+func TestGetDependenciesFallbackNotUsed(t *testing.T) {
+	stored := []model.DependencyLink{{Parent: "frontend", Child: "orders", CallCount: 10}}
+	tqs := initializeTestService(withDependenciesFallback(DependenciesFallbackOptions{MaxTraces: 10}))
+	tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, time.Hour).Return(stored, nil).Once()
+	tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, time.Minute).Return(nil, errors.New("storage error")).Once()
+
+	links, err := tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, stored, links)
+	_, err = tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Minute)
+	require.EqualError(t, err, "storage error")
+	tqs.spanReader.AssertNotCalled(t, "GetServices", mock.Anything)
+}
+
+func TestGetDependenciesFallbackErrors(t *testing.T) {
+	errStorage := errors.New("storage error")
+	tqs := initializeTestService(withDependenciesFallback(DependenciesFallbackOptions{MaxTraces: 10}))
+	tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	tqs.spanReader.On("GetServices", mock.Anything).Return(nil, errStorage).Once()
+	_, err := tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Hour)
+	require.ErrorIs(t, err, errStorage)
+
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errStorage).Once()
+	_, err = tqs.queryService.GetDependencies(context.Background(), fallbackEndTs, time.Hour)
+	require.ErrorIs(t, err, errStorage)
+}
+
+func TestDependenciesFallbackOptionsEnabled(t *testing.T) {
+	assert.False(t, DependenciesFallbackOptions{CacheTTL: time.Minute}.Enabled())
+	assert.True(t, DependenciesFallbackOptions{MaxTraces: 100}.Enabled())
+}
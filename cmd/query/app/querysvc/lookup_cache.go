@@ -0,0 +1,151 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// LookupCacheOptions configures the caching of the service and operation names lookups,
+// which the UI makes on every page load.
+type LookupCacheOptions struct {
+	// TTL is how long the names are reused before they are looked up again in the span storage.
+	TTL time.Duration `mapstructure:"ttl"`
+	// MaxEntries is the maximum number of lookups kept by the in-process cache.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// Enabled returns whether the lookups are cached.
+func (o LookupCacheOptions) Enabled() bool {
+	return o.TTL > 0
+}
+
+// LookupCacheStore stores the encoded lookups of a LookupCache. The in-process store is used
+// by default; a store shared by the query instances, e.g. backed by memcached or Redis, can be
+// used instead so that a lookup made by one instance is reused by the others.
+type LookupCacheStore interface {
+	// Get returns the value of the key, and false if there is none or it expired.
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Set stores the value of the key for the ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	// Purge removes all the values set in the store, e.g. by deleting the keys with
+	// the prefix of the LookupCache keys.
+	Purge(ctx context.Context)
+}
+
+const (
+	// LookupCacheKeyPrefix is the prefix of the keys the LookupCache sets in its store.
+	LookupCacheKeyPrefix = "jaeger-query-lookups|"
+	// defaultLookupCacheMaxEntries is the maximum number of lookups kept by the in-process store
+	// when LookupCacheOptions.MaxEntries is not set.
+	defaultLookupCacheMaxEntries = 1000
+)
+
+// LookupCache caches the services and operations returned by the span storage for the TTL.
+// The failed and empty lookups are not cached, so that the first services reporting spans
+// are visible without waiting for the TTL, and the lookups are purged when traces are deleted.
+type LookupCache struct {
+	ttl   time.Duration
+	store LookupCacheStore
+}
+
+// NewLookupCache creates a LookupCache with the store, or with an in-process store if it is nil.
+func NewLookupCache(options LookupCacheOptions, store LookupCacheStore) *LookupCache {
+	if store == nil {
+		maxEntries := options.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultLookupCacheMaxEntries
+		}
+		store = newInProcessLookupStore(maxEntries, options.TTL)
+	}
+	return &LookupCache{ttl: options.TTL, store: store}
+}
+
+// Purge removes the cached lookups, so that the next ones are made in the span storage.
+func (c *LookupCache) Purge(ctx context.Context) {
+	c.store.Purge(ctx)
+}
+
+// reader returns the span reader whose services and operations are looked up in the cache first.
+func (c *LookupCache) reader(spanReader spanstore.Reader) spanstore.Reader {
+	return &lookupCacheReader{Reader: spanReader, cache: c}
+}
+
+func (c *LookupCache) key(ctx context.Context, parts ...string) string {
+	return LookupCacheKeyPrefix + strings.Join(append([]string{tenancy.GetTenant(ctx)}, parts...), "|")
+}
+
+// lookup returns the cached value of the key, or the one of the span storage.
+func lookup[T any](ctx context.Context, c *LookupCache, key string, load func() ([]T, error)) ([]T, error) {
+	if data, ok := c.store.Get(ctx, key); ok {
+		var values []T
+		if err := json.Unmarshal(data, &values); err == nil {
+			return values, nil
+		}
+	}
+	values, err := load()
+	if err != nil || len(values) == 0 {
+		return values, err
+	}
+	if data, err := json.Marshal(values); err == nil {
+		c.store.Set(ctx, key, data, c.ttl)
+	}
+	return values, nil
+}
+
+type lookupCacheReader struct {
+	spanstore.Reader
+	cache *LookupCache
+}
+
+func (r *lookupCacheReader) GetServices(ctx context.Context) ([]string, error) {
+	return lookup(ctx, r.cache, r.cache.key(ctx, "services"), func() ([]string, error) {
+		return r.Reader.GetServices(ctx)
+	})
+}
+
+func (r *lookupCacheReader) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	key := r.cache.key(ctx, "operations", query.ServiceName, query.SpanKind)
+	if query.WithStats {
+		key = r.cache.key(ctx, "operation-stats", query.ServiceName, query.SpanKind)
+	}
+	return lookup(ctx, r.cache, key, func() ([]spanstore.Operation, error) {
+		return r.Reader.GetOperations(ctx, query)
+	})
+}
+
+// inProcessLookupStore is a LookupCacheStore keeping the lookups in an LRU cache.
+type inProcessLookupStore struct {
+	maxEntries int
+	ttl        time.Duration
+	cache      atomic.Pointer[cache.LRU]
+}
+
+func newInProcessLookupStore(maxEntries int, ttl time.Duration) *inProcessLookupStore {
+	s := &inProcessLookupStore{maxEntries: maxEntries, ttl: ttl}
+	s.Purge(context.Background())
+	return s
+}
+
+func (s *inProcessLookupStore) Get(_ context.Context, key string) ([]byte, bool) {
+	value, ok := s.cache.Load().Get(key).([]byte)
+	return value, ok
+}
+
+// Set stores the value with the TTL of the store, the lookups all having the same.
+func (s *inProcessLookupStore) Set(_ context.Context, key string, value []byte, _ time.Duration) {
+	s.cache.Load().Put(key, value)
+}
+
+func (s *inProcessLookupStore) Purge(context.Context) {
+	s.cache.Store(cache.NewLRUWithOptions(s.maxEntries, &cache.Options{TTL: s.ttl}))
+}
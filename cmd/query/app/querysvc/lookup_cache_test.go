@@ -0,0 +1,155 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func withLookupCache(lookupCache *LookupCache) testOption {
+	return func(_ *testQueryService, opts *QueryServiceOptions) {
+		opts.LookupCache = lookupCache
+	}
+}
+
+func TestLookupCacheServices(t *testing.T) {
+	tqs := initializeTestService(withLookupCache(NewLookupCache(LookupCacheOptions{TTL: time.Minute}, nil)))
+	tqs.spanReader.On("GetServices", mock.Anything).Return(nil, errors.New("storage error")).Once()
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{}, nil).Once()
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend", "orders"}, nil).Twice()
+
+	ctx := context.Background()
+	_, err := tqs.queryService.GetServices(ctx)
+	require.EqualError(t, err, "storage error")
+	services, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services, "the failed and empty lookups are not cached")
+
+	for i := 0; i < 2; i++ {
+		services, err = tqs.queryService.GetServices(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend", "orders"}, services)
+	}
+	services, err = tqs.queryService.SearchServices(ctx, spanstore.NameSearchParameters{Text: "ord", Mode: spanstore.NameMatchPrefix})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, services)
+
+	// the lookups are cached per tenant
+	services, err = tqs.queryService.GetServices(tenancy.WithTenant(ctx, "acme"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "orders"}, services)
+	tqs.spanReader.AssertExpectations(t)
+}
+
+func TestLookupCacheOperations(t *testing.T) {
+	tqs := initializeTestService(withLookupCache(NewLookupCache(LookupCacheOptions{TTL: time.Minute}, nil)))
+	serverOps := []spanstore.Operation{{Name: "GET /", SpanKind: "server"}}
+	allOps := []spanstore.Operation{{Name: "GET /", SpanKind: "server"}, {Name: "SELECT", SpanKind: "client"}}
+	tqs.spanReader.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "frontend", SpanKind: "server"}).
+		Return(serverOps, nil).Once()
+	tqs.spanReader.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "frontend"}).
+		Return(allOps, nil).Once()
+	statsOps := []spanstore.Operation{{Name: "GET /", SpanKind: "server", LastSeen: time.Unix(1714521600, 0), SpanCount: 42}}
+	tqs.spanReader.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "frontend", WithStats: true}).
+		Return(statsOps, nil).Once()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		operations, err := tqs.queryService.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "frontend", SpanKind: "server"})
+		require.NoError(t, err)
+		assert.Equal(t, serverOps, operations)
+		operations, err = tqs.queryService.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "frontend"})
+		require.NoError(t, err)
+		assert.Equal(t, allOps, operations)
+		operations, err = tqs.queryService.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "frontend", WithStats: true})
+		require.NoError(t, err)
+		assert.Equal(t, "GET /", operations[0].Name)
+		assert.Equal(t, int64(42), operations[0].SpanCount)
+		assert.True(t, statsOps[0].LastSeen.Equal(operations[0].LastSeen))
+	}
+	tqs.spanReader.AssertExpectations(t)
+}
+
+func TestLookupCachePurgedOnDelete(t *testing.T) {
+	traceIDs := []model.TraceID{model.NewTraceID(0, 1)}
+	deleter := &spanstoremocks.TraceDeleter{}
+	deleter.On("DeleteTraces", mock.Anything, traceIDs).Return(nil).Once()
+	tqs := initializeTestService(
+		withLookupCache(NewLookupCache(LookupCacheOptions{TTL: time.Minute}, nil)),
+		func(_ *testQueryService, options *QueryServiceOptions) {
+			options.TraceDeleter = deleter
+		},
+	)
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil).Twice()
+
+	ctx := context.Background()
+	_, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tqs.queryService.DeleteTraces(ctx, traceIDs))
+	_, err = tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	tqs.spanReader.AssertExpectations(t)
+}
+
+type mapLookupStore struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func (s *mapLookupStore) Get(_ context.Context, key string) ([]byte, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *mapLookupStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	s.values[key], s.ttls[key] = value, ttl
+}
+
+func (s *mapLookupStore) Purge(context.Context) {
+	for key := range s.values {
+		if strings.HasPrefix(key, LookupCacheKeyPrefix) {
+			delete(s.values, key)
+		}
+	}
+}
+
+func TestLookupCacheStore(t *testing.T) {
+	store := &mapLookupStore{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	lookupCache := NewLookupCache(LookupCacheOptions{TTL: time.Minute}, store)
+	tqs := initializeTestService(withLookupCache(lookupCache))
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil).Once()
+
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	_, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{LookupCacheKeyPrefix + "acme|services": []byte(`["frontend"]`)}, store.values)
+	assert.Equal(t, time.Minute, store.ttls[LookupCacheKeyPrefix+"acme|services"])
+
+	// the lookups set by another instance sharing the store are reused
+	store.values[LookupCacheKeyPrefix+"acme|services"] = []byte(`["frontend","orders"]`)
+	services, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "orders"}, services)
+
+	lookupCache.Purge(ctx)
+	assert.Empty(t, store.values)
+}
+
+func TestLookupCacheOptionsEnabled(t *testing.T) {
+	assert.False(t, LookupCacheOptions{MaxEntries: 10}.Enabled())
+	assert.True(t, LookupCacheOptions{TTL: time.Second}.Enabled())
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -39,11 +40,29 @@ type QueryServiceOptions struct {
 	ArchiveSpanReader spanstore.Reader
 	ArchiveSpanWriter spanstore.Writer
 	Adjuster          adjuster.Adjuster
+	// LiveTail is the source of spans streamed to live tail clients, if spans
+	// are received in the same process.
+	LiveTail *livetail.Broadcaster
+	// TraceDeleter deletes traces from the span storage, if deleting traces
+	// is enabled and supported by the span storage.
+	TraceDeleter spanstore.TraceDeleter
+	// Redactor masks span attributes in the traces returned to unprivileged callers,
+	// if redaction is configured.
+	Redactor *Redactor
+	// DependenciesFallback computes the dependencies from the spans when the dependency
+	// storage has none for the time window, if configured.
+	DependenciesFallback *DependenciesFallback
+	// LookupCache caches the services and operations of the span storage, if configured.
+	LookupCache *LookupCache
+	// CompletenessWindow is how long after its last span a trace is considered to be still
+	// receiving spans, see EstimateCompleteness.
+	CompletenessWindow time.Duration
 }
 
 // StorageCapabilities is a feature flag for query service
 type StorageCapabilities struct {
 	ArchiveStorage bool `json:"archiveStorage"`
+	LiveTail       bool `json:"liveTail,omitempty"`
 	// SupportRegex     bool
 	// SupportTagFilter bool
 }
@@ -53,6 +72,8 @@ type QueryService struct {
 	spanReader       spanstore.Reader
 	dependencyReader dependencystore.Reader
 	options          QueryServiceOptions
+	// namesReader is the span reader the services and operations are looked up with
+	namesReader spanstore.Reader
 }
 
 // NewQueryService returns a new QueryService.
@@ -61,8 +82,12 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 		spanReader:       spanReader,
 		dependencyReader: dependencyReader,
 		options:          options,
+		namesReader:      spanReader,
 	}
 
+	if qsvc.options.LookupCache != nil {
+		qsvc.namesReader = qsvc.options.LookupCache.reader(spanReader)
+	}
 	if qsvc.options.Adjuster == nil {
 		qsvc.options.Adjuster = adjuster.Sequence(StandardAdjusters(defaultMaxClockSkewAdjust)...)
 	}
@@ -70,20 +95,74 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 }
 
 // GetTrace is the queryService implementation of spanstore.Reader.GetTrace
-func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	trace, err := qs.spanReader.GetTrace(ctx, traceID)
+func (qs QueryService) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	trace, err := qs.getTrace(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return qs.options.Redactor.RedactTrace(ctx, trace), nil
+}
+
+func (qs QueryService) getTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	trace, err := qs.spanReader.GetTrace(ctx, query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		if qs.options.ArchiveSpanReader == nil {
 			return nil, err
 		}
-		trace, err = qs.options.ArchiveSpanReader.GetTrace(ctx, traceID)
+		trace, err = qs.options.ArchiveSpanReader.GetTrace(ctx, query)
 	}
 	return trace, err
 }
 
+// TraceExists checks whether the trace is stored, without loading its spans if the span storage supports it.
+func (qs QueryService) TraceExists(ctx context.Context, query spanstore.GetTraceParameters) (bool, error) {
+	exists, err := spanstore.TraceExists(ctx, qs.spanReader, query)
+	if err != nil || exists || qs.options.ArchiveSpanReader == nil {
+		return exists, err
+	}
+	return spanstore.TraceExists(ctx, qs.options.ArchiveSpanReader, query)
+}
+
+// GetRawSpans returns a span as stored by the span storage, if the storage supports it.
+func (qs QueryService) GetRawSpans(ctx context.Context, query spanstore.GetTraceParameters, spanID model.SpanID) ([]spanstore.RawSpan, error) {
+	rawReader, ok := qs.spanReader.(spanstore.RawSpanReader)
+	if !ok {
+		return nil, spanstore.ErrRawSpansNotSupported
+	}
+	return rawReader.GetRawSpans(ctx, query, spanID)
+}
+
+// GetSpans returns the spans with the given IDs, e.g. the targets of the links of a span,
+// without loading their whole traces if the span storage supports it. The spans missing
+// from the span storage are looked up in the archive storage.
+func (qs QueryService) GetSpans(ctx context.Context, ids []spanstore.TraceSpanID) ([]*model.Span, error) {
+	spans, err := spanstore.GetSpans(ctx, qs.spanReader, ids)
+	if err != nil {
+		return nil, err
+	}
+	if qs.options.ArchiveSpanReader != nil && len(spans) < len(ids) {
+		found := make(map[spanstore.TraceSpanID]struct{}, len(spans))
+		for _, span := range spans {
+			found[spanstore.TraceSpanID{TraceID: span.TraceID, SpanID: span.SpanID}] = struct{}{}
+		}
+		var missing []spanstore.TraceSpanID
+		for _, id := range ids {
+			if _, ok := found[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		archived, err := spanstore.GetSpans(ctx, qs.options.ArchiveSpanReader, missing)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, archived...)
+	}
+	return qs.options.Redactor.RedactTrace(ctx, &model.Trace{Spans: spans}).Spans, nil
+}
+
 // GetServices is the queryService implementation of spanstore.Reader.GetServices
 func (qs QueryService) GetServices(ctx context.Context) ([]string, error) {
-	return qs.spanReader.GetServices(ctx)
+	return qs.namesReader.GetServices(ctx)
 }
 
 // GetOperations is the queryService implementation of spanstore.Reader.GetOperations
@@ -91,20 +170,44 @@ func (qs QueryService) GetOperations(
 	ctx context.Context,
 	query spanstore.OperationQueryParameters,
 ) ([]spanstore.Operation, error) {
-	return qs.spanReader.GetOperations(ctx, query)
+	return qs.namesReader.GetOperations(ctx, query)
+}
+
+// SearchServices returns the service names matching the search, see spanstore.SearchServices.
+func (qs QueryService) SearchServices(ctx context.Context, search spanstore.NameSearchParameters) ([]string, error) {
+	return spanstore.SearchServices(ctx, qs.namesReader, search)
+}
+
+// SearchOperations returns the operations of the query whose name matches the search,
+// see spanstore.SearchOperations.
+func (qs QueryService) SearchOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+	search spanstore.NameSearchParameters,
+) ([]spanstore.Operation, error) {
+	return spanstore.SearchOperations(ctx, qs.namesReader, query, search)
 }
 
 // FindTraces is the queryService implementation of spanstore.Reader.FindTraces
 func (qs QueryService) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
-	return qs.spanReader.FindTraces(ctx, query)
+	traces, err := qs.spanReader.FindTraces(ctx, query)
+	if err != nil || !qs.options.Redactor.Redacts(ctx) {
+		return traces, err
+	}
+	redacted := make([]*model.Trace, len(traces))
+	for i, trace := range traces {
+		redacted[i] = qs.options.Redactor.RedactTrace(ctx, trace)
+	}
+	return redacted, nil
 }
 
 // ArchiveTrace is the queryService utility to archive traces.
-func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID) error {
+func (qs QueryService) ArchiveTrace(ctx context.Context, query spanstore.GetTraceParameters) error {
 	if qs.options.ArchiveSpanWriter == nil {
 		return errNoArchiveSpanStorage
 	}
-	trace, err := qs.GetTrace(ctx, traceID)
+	// the archived trace is not redacted, like the stored one
+	trace, err := qs.getTrace(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -119,23 +222,58 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 	return errors.Join(writeErrors...)
 }
 
+// DeleteTraces deletes the traces from the span storage, and from the archive storage
+// if it supports deleting traces, so that no copy of the traces is left behind.
+func (qs QueryService) DeleteTraces(ctx context.Context, traceIDs []model.TraceID) error {
+	if qs.options.TraceDeleter == nil {
+		return spanstore.ErrDeleteTracesNotSupported
+	}
+	if err := qs.options.TraceDeleter.DeleteTraces(ctx, traceIDs); err != nil {
+		return err
+	}
+	// the services and operations of the deleted traces may not be stored anymore
+	if qs.options.LookupCache != nil {
+		qs.options.LookupCache.Purge(ctx)
+	}
+	if archiveDeleter, ok := qs.options.ArchiveSpanWriter.(spanstore.TraceDeleter); ok {
+		return archiveDeleter.DeleteTraces(ctx, traceIDs)
+	}
+	return nil
+}
+
 // Adjust applies adjusters to the trace.
 func (qs QueryService) Adjust(trace *model.Trace) (*model.Trace, error) {
 	return qs.options.Adjuster.Adjust(trace)
 }
 
-// GetDependencies implements dependencystore.Reader.GetDependencies
+// Redacts returns whether span attributes are masked for the caller of the context.
+func (qs QueryService) Redacts(ctx context.Context) bool {
+	return qs.options.Redactor.Redacts(ctx)
+}
+
+// GetDependencies implements dependencystore.Reader.GetDependencies. The dependencies are
+// computed from the spans if the dependency storage has none and the fallback is configured.
 func (qs QueryService) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
-	return qs.dependencyReader.GetDependencies(ctx, endTs, lookback)
+	links, err := qs.dependencyReader.GetDependencies(ctx, endTs, lookback)
+	if err != nil || len(links) > 0 || qs.options.DependenciesFallback == nil {
+		return links, err
+	}
+	return qs.options.DependenciesFallback.GetDependencies(ctx, qs.namesReader, endTs, lookback)
 }
 
 // GetCapabilities returns the features supported by the query service.
 func (qs QueryService) GetCapabilities() StorageCapabilities {
 	return StorageCapabilities{
 		ArchiveStorage: qs.options.hasArchiveStorage(),
+		LiveTail:       qs.options.LiveTail != nil,
 	}
 }
 
+// LiveTail returns the source of spans for live tail, or nil if not available.
+func (qs QueryService) LiveTail() *livetail.Broadcaster {
+	return qs.options.LiveTail
+}
+
 // InitArchiveStorage tries to initialize archive storage reader/writer if storage factory supports them.
 func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Factory, logger *zap.Logger) bool {
 	archiveFactory, ok := storageFactory.(storage.ArchiveFactory)
@@ -166,6 +304,28 @@ func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Facto
 	return true
 }
 
+// InitTraceDeleter tries to initialize the deleter of traces if the span writer of the storage factory supports it.
+// It should be called after InitArchiveStorage, to warn when traces cannot be deleted from the archive storage.
+func (opts *QueryServiceOptions) InitTraceDeleter(storageFactory storage.Factory, logger *zap.Logger) bool {
+	writer, err := storageFactory.CreateSpanWriter()
+	if err != nil {
+		logger.Error("Cannot init span writer to delete traces", zap.Error(err))
+		return false
+	}
+	deleter, ok := writer.(spanstore.TraceDeleter)
+	if !ok {
+		logger.Info("Deleting traces not supported by the span storage")
+		return false
+	}
+	if opts.ArchiveSpanWriter != nil {
+		if _, ok := opts.ArchiveSpanWriter.(spanstore.TraceDeleter); !ok {
+			logger.Warn("Deleting traces not supported by the archive storage, archived traces will not be deleted")
+		}
+	}
+	opts.TraceDeleter = deleter
+	return true
+}
+
 // hasArchiveStorage returns true if archive storage reader/writer are initialized.
 func (opts *QueryServiceOptions) hasArchiveStorage() bool {
 	return opts.ArchiveSpanReader != nil && opts.ArchiveSpanWriter != nil
@@ -27,6 +27,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/livetail"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/storage"
@@ -118,12 +119,12 @@ func initializeTestService(optionAppliers ...testOption) *testQueryService {
 // Test QueryService.GetTrace()
 func TestGetTraceSuccess(t *testing.T) {
 	tqs := initializeTestService()
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 
 	type contextKey string
 	ctx := context.Background()
-	res, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	res, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	require.NoError(t, err)
 	assert.Equal(t, res, mockTrace)
 }
@@ -131,30 +132,114 @@ func TestGetTraceSuccess(t *testing.T) {
 // Test QueryService.GetTrace() without ArchiveSpanReader
 func TestGetTraceNotFound(t *testing.T) {
 	tqs := initializeTestService()
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
 
 	type contextKey string
 	ctx := context.Background()
-	_, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	_, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	assert.Equal(t, err, spanstore.ErrTraceNotFound)
 }
 
 // Test QueryService.GetTrace() with ArchiveSpanReader
 func TestGetTraceFromArchiveStorage(t *testing.T) {
 	tqs := initializeTestService(withArchiveSpanReader())
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
-	tqs.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 
 	type contextKey string
 	ctx := context.Background()
-	res, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	res, err := tqs.queryService.GetTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	require.NoError(t, err)
 	assert.Equal(t, res, mockTrace)
 }
 
+type traceExistenceChecker struct {
+	*spanstoremocks.Reader
+	*spanstoremocks.TraceExistenceChecker
+}
+
+// Test QueryService.TraceExists() with a span storage supporting existence checks.
+func TestTraceExists(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: mockTraceID}
+	checker := &spanstoremocks.TraceExistenceChecker{}
+	checker.On("TraceExists", mock.Anything, query).Return(true, nil).Once()
+	qs := NewQueryService(traceExistenceChecker{Reader: &spanstoremocks.Reader{}, TraceExistenceChecker: checker}, &depsmocks.Reader{}, QueryServiceOptions{})
+
+	exists, err := qs.TraceExists(context.Background(), query)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+// Test QueryService.TraceExists() falling back to GetTrace and to the archive storage.
+func TestTraceExistsInArchiveStorage(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: mockTraceID}
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, query).Return(nil, spanstore.ErrTraceNotFound).Once()
+	exists, err := tqs.queryService.TraceExists(context.Background(), query)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	tqs = initializeTestService(withArchiveSpanReader())
+	tqs.spanReader.On("GetTrace", mock.Anything, query).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.archiveSpanReader.On("GetTrace", mock.Anything, query).Return(mockTrace, nil).Once()
+	exists, err = tqs.queryService.TraceExists(context.Background(), query)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+type rawSpanReader struct {
+	*spanstoremocks.Reader
+	*spanstoremocks.RawSpanReader
+}
+
+// Test QueryService.GetRawSpans().
+func TestGetRawSpans(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: mockTraceID}
+	spanID := model.NewSpanID(1)
+
+	tqs := initializeTestService()
+	_, err := tqs.queryService.GetRawSpans(context.Background(), query, spanID)
+	require.ErrorIs(t, err, spanstore.ErrRawSpansNotSupported)
+
+	rawReader := &spanstoremocks.RawSpanReader{}
+	expected := []spanstore.RawSpan{{Location: "traces", Document: []byte(`{"span_id":1}`)}}
+	rawReader.On("GetRawSpans", mock.Anything, query, spanID).Return(expected, nil).Once()
+	qs := NewQueryService(rawSpanReader{Reader: &spanstoremocks.Reader{}, RawSpanReader: rawReader}, &depsmocks.Reader{}, QueryServiceOptions{})
+	rawSpans, err := qs.GetRawSpans(context.Background(), query, spanID)
+	require.NoError(t, err)
+	assert.Equal(t, expected, rawSpans)
+}
+
+// Test QueryService.GetSpans() falling back to GetTrace and to the archive storage.
+func TestGetSpans(t *testing.T) {
+	archivedTraceID := model.NewTraceID(0, 1)
+	ids := []spanstore.TraceSpanID{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(2)},
+		{TraceID: archivedTraceID, SpanID: model.NewSpanID(3)},
+	}
+	archivedTrace := &model.Trace{Spans: []*model.Span{{TraceID: archivedTraceID, SpanID: model.NewSpanID(3), Process: &model.Process{}}}}
+
+	tqs := initializeTestService(withArchiveSpanReader())
+	tqs.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID}).Return(mockTrace, nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: archivedTraceID}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.archiveSpanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: archivedTraceID}).
+		Return(archivedTrace, nil).Once()
+	spans, err := tqs.queryService.GetSpans(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Span{mockTrace.Spans[1], archivedTrace.Spans[0]}, spans)
+	tqs.spanReader.AssertExpectations(t)
+	tqs.archiveSpanReader.AssertExpectations(t)
+
+	tqs = initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: mockTraceID}).Return(nil, errors.New("storage error")).Once()
+	_, err = tqs.queryService.GetSpans(context.Background(), ids)
+	require.EqualError(t, err, "storage error")
+}
+
 // Test QueryService.GetServices() for success.
 func TestGetServices(t *testing.T) {
 	tqs := initializeTestService()
@@ -186,6 +271,22 @@ func TestGetOperations(t *testing.T) {
 	assert.Equal(t, expectedOperations, actualOperations)
 }
 
+func TestSearchServicesAndOperations(t *testing.T) {
+	tqs := initializeTestService()
+	search := spanstore.NameSearchParameters{Text: "trif", Mode: spanstore.NameMatchPrefix}
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"trifle", "bling"}, nil).Once()
+	services, err := tqs.queryService.SearchServices(context.Background(), search)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trifle"}, services)
+
+	operationQuery := spanstore.OperationQueryParameters{ServiceName: "trifle"}
+	tqs.spanReader.On("GetOperations", mock.Anything, operationQuery).
+		Return([]spanstore.Operation{{Name: "get"}, {Name: "trifle-cache"}}, nil).Once()
+	operations, err := tqs.queryService.SearchOperations(context.Background(), operationQuery, search)
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "trifle-cache"}}, operations)
+}
+
 // Test QueryService.FindTraces() for success.
 func TestFindTraces(t *testing.T) {
 	tqs := initializeTestService()
@@ -213,35 +314,35 @@ func TestArchiveTraceNoOptions(t *testing.T) {
 
 	type contextKey string
 	ctx := context.Background()
-	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	assert.Equal(t, errNoArchiveSpanStorage, err)
 }
 
 // Test QueryService.ArchiveTrace() with ArchiveSpanWriter but invalid traceID.
 func TestArchiveTraceWithInvalidTraceID(t *testing.T) {
 	tqs := initializeTestService(withArchiveSpanReader(), withArchiveSpanWriter())
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
-	tqs.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(nil, spanstore.ErrTraceNotFound).Once()
 
 	type contextKey string
 	ctx := context.Background()
-	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	assert.Equal(t, spanstore.ErrTraceNotFound, err)
 }
 
 // Test QueryService.ArchiveTrace(), save error with ArchiveSpanWriter.
 func TestArchiveTraceWithArchiveWriterError(t *testing.T) {
 	tqs := initializeTestService(withArchiveSpanWriter())
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 	tqs.archiveSpanWriter.On("WriteSpan", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.Span")).
 		Return(errors.New("cannot save")).Times(2)
 
 	type contextKey string
 	ctx := context.Background()
-	joinErr := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	joinErr := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	// There are two spans in the mockTrace, ArchiveTrace should return a wrapped error.
 	require.EqualError(t, joinErr, "cannot save\ncannot save")
 }
@@ -249,14 +350,14 @@ func TestArchiveTraceWithArchiveWriterError(t *testing.T) {
 // Test QueryService.ArchiveTrace() with correctly configured ArchiveSpanWriter.
 func TestArchiveTraceSuccess(t *testing.T) {
 	tqs := initializeTestService(withArchiveSpanWriter())
-	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+	tqs.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
 		Return(mockTrace, nil).Once()
 	tqs.archiveSpanWriter.On("WriteSpan", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.Span")).
 		Return(nil).Times(2)
 
 	type contextKey string
 	ctx := context.Background()
-	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), mockTraceID)
+	err := tqs.queryService.ArchiveTrace(context.WithValue(ctx, contextKey("foo"), "bar"), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	require.NoError(t, err)
 }
 
@@ -309,6 +410,19 @@ func TestGetCapabilitiesWithSupportsArchive(t *testing.T) {
 	assert.Equal(t, expectedStorageCapabilities, tqs.queryService.GetCapabilities())
 }
 
+func TestGetCapabilitiesWithLiveTail(t *testing.T) {
+	liveTail := livetail.NewBroadcaster(1)
+	tqs := initializeTestService(func(_ *testQueryService, options *QueryServiceOptions) {
+		options.LiveTail = liveTail
+	})
+
+	expectedStorageCapabilities := StorageCapabilities{
+		LiveTail: true,
+	}
+	assert.Equal(t, expectedStorageCapabilities, tqs.queryService.GetCapabilities())
+	assert.Same(t, liveTail, tqs.queryService.LiveTail())
+}
+
 type fakeStorageFactory1 struct{}
 
 type fakeStorageFactory2 struct {
@@ -370,6 +484,67 @@ func TestInitArchiveStorage(t *testing.T) {
 	assert.Equal(t, writer, opts.ArchiveSpanWriter)
 }
 
+// deletingSpanWriter is a span writer which supports deleting traces.
+type deletingSpanWriter struct {
+	*spanstoremocks.Writer
+	*spanstoremocks.TraceDeleter
+}
+
+type fakeSpanWriterFactory struct {
+	fakeStorageFactory1
+	w    spanstore.Writer
+	wErr error
+}
+
+func (f *fakeSpanWriterFactory) CreateSpanWriter() (spanstore.Writer, error) { return f.w, f.wErr }
+
+func TestDeleteTraces(t *testing.T) {
+	traceIDs := []model.TraceID{mockTraceID}
+	errStorage := errors.New("storage error")
+
+	tqs := initializeTestService()
+	err := tqs.queryService.DeleteTraces(context.Background(), traceIDs)
+	require.ErrorIs(t, err, spanstore.ErrDeleteTracesNotSupported)
+
+	deleter := &spanstoremocks.TraceDeleter{}
+	deleter.On("DeleteTraces", mock.Anything, traceIDs).Return(nil).Once()
+	archiveDeleter := &spanstoremocks.TraceDeleter{}
+	archiveDeleter.On("DeleteTraces", mock.Anything, traceIDs).Return(errStorage).Once()
+	tqs = initializeTestService(func(_ *testQueryService, options *QueryServiceOptions) {
+		options.TraceDeleter = deleter
+		options.ArchiveSpanWriter = deletingSpanWriter{Writer: &spanstoremocks.Writer{}, TraceDeleter: archiveDeleter}
+	})
+	err = tqs.queryService.DeleteTraces(context.Background(), traceIDs)
+	require.ErrorIs(t, err, errStorage)
+	deleter.AssertExpectations(t)
+	archiveDeleter.AssertExpectations(t)
+
+	deleter.On("DeleteTraces", mock.Anything, traceIDs).Return(errStorage).Once()
+	err = tqs.queryService.DeleteTraces(context.Background(), traceIDs)
+	require.ErrorIs(t, err, errStorage)
+	archiveDeleter.AssertNumberOfCalls(t, "DeleteTraces", 1)
+
+	deleter.On("DeleteTraces", mock.Anything, traceIDs).Return(nil).Once()
+	tqs = initializeTestService(withArchiveSpanWriter(), func(_ *testQueryService, options *QueryServiceOptions) {
+		options.TraceDeleter = deleter
+	})
+	require.NoError(t, tqs.queryService.DeleteTraces(context.Background(), traceIDs))
+}
+
+func TestInitTraceDeleter(t *testing.T) {
+	logger := zap.NewNop()
+
+	opts := &QueryServiceOptions{}
+	assert.False(t, opts.InitTraceDeleter(&fakeSpanWriterFactory{wErr: errors.New("error")}, logger))
+	assert.False(t, opts.InitTraceDeleter(&fakeSpanWriterFactory{w: &spanstoremocks.Writer{}}, logger))
+	assert.Nil(t, opts.TraceDeleter)
+
+	writer := deletingSpanWriter{Writer: &spanstoremocks.Writer{}, TraceDeleter: &spanstoremocks.TraceDeleter{}}
+	opts = &QueryServiceOptions{ArchiveSpanWriter: &spanstoremocks.Writer{}}
+	assert.True(t, opts.InitTraceDeleter(&fakeSpanWriterFactory{w: writer}, logger))
+	assert.Equal(t, writer, opts.TraceDeleter)
+}
+
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
@@ -0,0 +1,174 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// RedactedValue replaces the values of the redacted span attributes.
+const RedactedValue = "[REDACTED]"
+
+// RedactionOptions configures the masking of span attributes in the traces returned
+// to the callers that are neither of a privileged role nor of a privileged tenant.
+type RedactionOptions struct {
+	// Keys are the keys of the span, process and log attributes whose values are masked.
+	// A key ending with * masks the attributes whose key starts with the part before it.
+	Keys []string `mapstructure:"keys"`
+	// RoleHeader is the request header, or gRPC metadata, carrying the role of the caller.
+	// It must be set by a trusted proxy authenticating the callers.
+	RoleHeader string `mapstructure:"role_header"`
+	// PrivilegedRoles are the roles of the callers seeing the attributes unmasked.
+	PrivilegedRoles []string `mapstructure:"privileged_roles"`
+	// PrivilegedTenants are the tenants whose callers see the attributes unmasked.
+	PrivilegedTenants []string `mapstructure:"privileged_tenants"`
+}
+
+// Enabled returns whether any attribute is masked.
+func (o RedactionOptions) Enabled() bool {
+	return len(o.Keys) > 0
+}
+
+// roleKeyType is a custom type for the key "role", following context.Context convention
+type roleKeyType string
+
+const roleKey = roleKeyType("role")
+
+// WithRole creates a Context with the role of the caller.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// GetRole retrieves the role of the caller associated with a Context.
+func GetRole(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey).(string)
+	return role
+}
+
+// Redactor masks the configured span attributes for the unprivileged callers.
+type Redactor struct {
+	keys              map[string]struct{}
+	prefixes          []string
+	privilegedRoles   map[string]struct{}
+	privilegedTenants map[string]struct{}
+}
+
+// NewRedactor creates a Redactor from the options.
+func NewRedactor(options RedactionOptions) *Redactor {
+	r := &Redactor{
+		keys:              make(map[string]struct{}),
+		privilegedRoles:   toSet(options.PrivilegedRoles),
+		privilegedTenants: toSet(options.PrivilegedTenants),
+	}
+	for _, key := range options.Keys {
+		if prefix, ok := strings.CutSuffix(key, "*"); ok {
+			r.prefixes = append(r.prefixes, prefix)
+		} else {
+			r.keys[key] = struct{}{}
+		}
+	}
+	return r
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Redacts returns whether the attributes are masked for the caller of the context.
+// A nil Redactor masks nothing.
+func (r *Redactor) Redacts(ctx context.Context) bool {
+	if r == nil {
+		return false
+	}
+	if _, ok := r.privilegedRoles[GetRole(ctx)]; ok {
+		return false
+	}
+	if _, ok := r.privilegedTenants[tenancy.GetTenant(ctx)]; ok {
+		return false
+	}
+	return true
+}
+
+// RedactTrace returns the trace with the attributes masked for the caller of the context.
+// The spans with masked attributes are copied, leaving the spans of the storage intact.
+func (r *Redactor) RedactTrace(ctx context.Context, trace *model.Trace) *model.Trace {
+	if trace == nil || !r.Redacts(ctx) {
+		return trace
+	}
+	redacted := *trace
+	redacted.Spans = make([]*model.Span, len(trace.Spans))
+	for i, span := range trace.Spans {
+		redacted.Spans[i] = r.redactSpan(span)
+	}
+	if len(trace.ProcessMap) > 0 {
+		redacted.ProcessMap = make([]model.Trace_ProcessMapping, len(trace.ProcessMap))
+		for i, mapping := range trace.ProcessMap {
+			mapping.Process = *r.redactProcess(&mapping.Process)
+			redacted.ProcessMap[i] = mapping
+		}
+	}
+	return &redacted
+}
+
+func (r *Redactor) redactSpan(span *model.Span) *model.Span {
+	redacted := *span
+	redacted.Tags = r.redactKeyValues(span.Tags)
+	redacted.Process = r.redactProcess(span.Process)
+	if len(span.Logs) > 0 {
+		redacted.Logs = make([]model.Log, len(span.Logs))
+		for i, log := range span.Logs {
+			log.Fields = r.redactKeyValues(log.Fields)
+			redacted.Logs[i] = log
+		}
+	}
+	return &redacted
+}
+
+func (r *Redactor) redactProcess(process *model.Process) *model.Process {
+	if process == nil {
+		return nil
+	}
+	redacted := *process
+	redacted.Tags = r.redactKeyValues(process.Tags)
+	return &redacted
+}
+
+// redactKeyValues returns the attributes with the matching ones masked, copying them only if any matches.
+func (r *Redactor) redactKeyValues(kvs model.KeyValues) model.KeyValues {
+	var redacted model.KeyValues
+	for i, kv := range kvs {
+		if !r.redactsKey(kv.Key) {
+			continue
+		}
+		if redacted == nil {
+			redacted = make(model.KeyValues, len(kvs))
+			copy(redacted, kvs)
+		}
+		redacted[i] = model.String(kv.Key, RedactedValue)
+	}
+	if redacted == nil {
+		return kvs
+	}
+	return redacted
+}
+
+func (r *Redactor) redactsKey(key string) bool {
+	if _, ok := r.keys[key]; ok {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
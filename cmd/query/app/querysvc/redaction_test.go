@@ -0,0 +1,141 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var testRedactionOptions = RedactionOptions{
+	Keys:              []string{"user.email", "http.request.header.*"},
+	PrivilegedRoles:   []string{"admin"},
+	PrivilegedTenants: []string{"security"},
+}
+
+func newSensitiveTrace() *model.Trace {
+	process := model.NewProcess("frontend", []model.KeyValue{
+		model.String("hostname", "host1"),
+		model.String("user.email", "ops@example.com"),
+	})
+	return &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID: mockTraceID,
+				SpanID:  model.NewSpanID(1),
+				Tags: []model.KeyValue{
+					model.String("user.email", "jane@example.com"),
+					model.String("http.request.header.cookie", "session=secret"),
+					model.Int64("http.status_code", 200),
+				},
+				Logs: []model.Log{
+					{Fields: []model.KeyValue{model.String("event", "login"), model.String("user.email", "jane@example.com")}},
+				},
+				Process: process,
+			},
+			{
+				TraceID: mockTraceID,
+				SpanID:  model.NewSpanID(2),
+				Tags:    []model.KeyValue{model.String("http.method", "GET")},
+				Process: model.NewProcess("backend", nil),
+			},
+		},
+		ProcessMap: []model.Trace_ProcessMapping{{ProcessID: "p1", Process: *process}},
+	}
+}
+
+func TestRedactTrace(t *testing.T) {
+	r := NewRedactor(testRedactionOptions)
+	trace := newSensitiveTrace()
+
+	redacted := r.RedactTrace(context.Background(), trace)
+	span := redacted.Spans[0]
+	assert.Equal(t, []model.KeyValue{
+		model.String("user.email", RedactedValue),
+		model.String("http.request.header.cookie", RedactedValue),
+		model.Int64("http.status_code", 200),
+	}, []model.KeyValue(span.Tags))
+	assert.Equal(t, []model.KeyValue{model.String("event", "login"), model.String("user.email", RedactedValue)}, []model.KeyValue(span.Logs[0].Fields))
+	assert.Equal(t, []model.KeyValue{model.String("hostname", "host1"), model.String("user.email", RedactedValue)}, []model.KeyValue(span.Process.Tags))
+	assert.Equal(t, span.Process.Tags, redacted.ProcessMap[0].Process.Tags)
+	assert.Equal(t, trace.Spans[1].Tags, redacted.Spans[1].Tags)
+
+	assert.Equal(t, newSensitiveTrace(), trace, "the stored trace is not modified")
+}
+
+func TestRedactTracePrivilegedCallers(t *testing.T) {
+	r := NewRedactor(testRedactionOptions)
+	trace := newSensitiveTrace()
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		redacts bool
+	}{
+		{name: "anonymous", ctx: context.Background(), redacts: true},
+		{name: "unprivileged role", ctx: WithRole(context.Background(), "viewer"), redacts: true},
+		{name: "unprivileged tenant", ctx: tenancy.WithTenant(context.Background(), "acme"), redacts: true},
+		{name: "privileged role", ctx: WithRole(context.Background(), "admin")},
+		{name: "privileged tenant", ctx: tenancy.WithTenant(context.Background(), "security")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.redacts, r.Redacts(tc.ctx))
+			if tc.redacts {
+				assert.NotSame(t, trace, r.RedactTrace(tc.ctx, trace))
+			} else {
+				assert.Same(t, trace, r.RedactTrace(tc.ctx, trace))
+			}
+		})
+	}
+
+	var disabled *Redactor
+	assert.False(t, disabled.Redacts(context.Background()))
+	assert.Same(t, trace, disabled.RedactTrace(context.Background(), trace))
+	assert.Nil(t, r.RedactTrace(context.Background(), nil))
+}
+
+func withRedactor() testOption {
+	return func(_ *testQueryService, options *QueryServiceOptions) {
+		options.Redactor = NewRedactor(testRedactionOptions)
+	}
+}
+
+func TestQueryServiceRedaction(t *testing.T) {
+	tqs := initializeTestService(withRedactor(), withArchiveSpanWriter())
+	tqs.spanReader.On("GetTrace", mock.Anything, mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Return(newSensitiveTrace(), nil)
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{newSensitiveTrace()}, nil)
+	var archived []*model.Span
+	tqs.archiveSpanWriter.On("WriteSpan", mock.Anything, mock.AnythingOfType("*model.Span")).
+		Run(func(args mock.Arguments) { archived = append(archived, args.Get(1).(*model.Span)) }).
+		Return(nil)
+
+	ctx := context.Background()
+	assert.True(t, tqs.queryService.Redacts(ctx))
+	trace, err := tqs.queryService.GetTrace(ctx, spanstore.GetTraceParameters{TraceID: mockTraceID})
+	require.NoError(t, err)
+	assert.Equal(t, RedactedValue, trace.Spans[0].Tags[0].VStr)
+
+	traces, err := tqs.queryService.FindTraces(ctx, &spanstore.TraceQueryParameters{ServiceName: "frontend"})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, RedactedValue, traces[0].Spans[0].Tags[0].VStr)
+
+	trace, err = tqs.queryService.GetTrace(WithRole(ctx, "admin"), spanstore.GetTraceParameters{TraceID: mockTraceID})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", trace.Spans[0].Tags[0].VStr)
+
+	require.NoError(t, tqs.queryService.ArchiveTrace(ctx, spanstore.GetTraceParameters{TraceID: mockTraceID}))
+	require.Len(t, archived, 2)
+	assert.Equal(t, "jane@example.com", archived[0].Tags[0].VStr, "the archived trace is not redacted")
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// operationLabel is the label the metrics readers group the metrics by operation with.
+const operationLabel = "operation"
+
+// ServiceSnapshotParameters contains the parameters of a service performance snapshot.
+type ServiceSnapshotParameters struct {
+	ServiceName string
+	SpanKinds   []string
+	EndTime     time.Time
+	Lookback    time.Duration
+	// Quantile is the latency quantile the operations are ranked by, e.g. 0.95.
+	Quantile float64
+	// Limit is the maximum number of operations and error traces in the snapshot.
+	Limit int
+}
+
+// OperationLatency is the latency quantile of an operation over the snapshot time range.
+type OperationLatency struct {
+	Operation string
+	Latency   time.Duration
+}
+
+// ErrorTrace summarizes a trace of the service with errors.
+type ErrorTrace struct {
+	TraceID model.TraceID
+	// Operation is the operation of the earliest span of the trace.
+	Operation  string
+	StartTime  time.Time
+	Duration   time.Duration
+	Spans      int
+	ErrorSpans int
+}
+
+// ServiceSnapshot is the performance of a service over a time range, combining its
+// metrics, traces and dependencies.
+type ServiceSnapshot struct {
+	// Operations are the slowest operations, the slowest first. It is nil when
+	// the metrics store is disabled.
+	Operations []OperationLatency
+	// ErrorTraces are the slowest traces with errors, the slowest first.
+	ErrorTraces []ErrorTrace
+	// Dependencies are the dependency links from or to the service.
+	Dependencies []model.DependencyLink
+}
+
+// GetServiceSnapshot assembles the performance snapshot of a service from the latencies
+// in the metrics store, if any, the traces with errors and the dependency links.
+func (qs QueryService) GetServiceSnapshot(
+	ctx context.Context,
+	metricsReader metricsstore.Reader,
+	params ServiceSnapshotParameters,
+) (*ServiceSnapshot, error) {
+	var operations []OperationLatency
+	if metricsReader != nil {
+		var err error
+		operations, err = slowestOperations(ctx, metricsReader, params)
+		if err != nil && !errors.Is(err, disabled.ErrDisabled) {
+			return nil, err
+		}
+	}
+
+	traces, err := qs.FindTraces(ctx, &spanstore.TraceQueryParameters{
+		ServiceName:  params.ServiceName,
+		Tags:         map[string]string{"error": "true"},
+		StartTimeMin: params.EndTime.Add(-params.Lookback),
+		StartTimeMax: params.EndTime,
+		NumTraces:    params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := qs.GetDependencies(ctx, params.EndTime, params.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := make([]model.DependencyLink, 0, len(links))
+	for _, l := range links {
+		if l.Parent == params.ServiceName || l.Child == params.ServiceName {
+			dependencies = append(dependencies, l)
+		}
+	}
+
+	return &ServiceSnapshot{
+		Operations:   operations,
+		ErrorTraces:  slowestErrorTraces(traces, params.Limit),
+		Dependencies: dependencies,
+	}, nil
+}
+
+// slowestOperations queries the latency quantile of each operation over the whole time range,
+// as a single point at the end time, and returns the slowest operations.
+func slowestOperations(ctx context.Context, reader metricsstore.Reader, params ServiceSnapshotParameters) ([]OperationLatency, error) {
+	endTime, lookback := params.EndTime, params.Lookback
+	latencies, err := reader.GetLatencies(ctx, &metricsstore.LatenciesQueryParameters{
+		BaseQueryParameters: metricsstore.BaseQueryParameters{
+			ServiceNames:     []string{params.ServiceName},
+			GroupByOperation: true,
+			SpanKinds:        params.SpanKinds,
+			EndTime:          &endTime,
+			Lookback:         &lookback,
+			Step:             &lookback,
+			RatePer:          &lookback,
+		},
+		Quantile: params.Quantile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]OperationLatency, 0, len(latencies.GetMetrics()))
+	for _, metric := range latencies.GetMetrics() {
+		var operation string
+		for _, label := range metric.GetLabels() {
+			if label.GetName() == operationLabel {
+				operation = label.GetValue()
+			}
+		}
+		// the latest defined point, in milliseconds
+		latency := math.NaN()
+		for _, point := range metric.GetMetricPoints() {
+			if value := point.GetGaugeValue().GetDoubleValue(); !math.IsNaN(value) && !math.IsInf(value, 0) {
+				latency = value
+			}
+		}
+		if operation == "" || math.IsNaN(latency) {
+			continue
+		}
+		operations = append(operations, OperationLatency{
+			Operation: operation,
+			Latency:   time.Duration(latency * float64(time.Millisecond)),
+		})
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		if operations[i].Latency != operations[j].Latency {
+			return operations[i].Latency > operations[j].Latency
+		}
+		return operations[i].Operation < operations[j].Operation
+	})
+	if params.Limit > 0 && len(operations) > params.Limit {
+		operations = operations[:params.Limit]
+	}
+	return operations, nil
+}
+
+func slowestErrorTraces(traces []*model.Trace, limit int) []ErrorTrace {
+	result := make([]ErrorTrace, 0, len(traces))
+	for _, trace := range traces {
+		if len(trace.Spans) == 0 {
+			continue
+		}
+		summary := ErrorTrace{
+			TraceID:  traceID(trace),
+			Duration: traceDuration(trace),
+			Spans:    len(trace.Spans),
+		}
+		first := trace.Spans[0]
+		for _, span := range trace.Spans {
+			if span.StartTime.Before(first.StartTime) {
+				first = span
+			}
+			if spanHasError(span) {
+				summary.ErrorSpans++
+			}
+		}
+		if summary.ErrorSpans == 0 {
+			continue
+		}
+		summary.Operation, summary.StartTime = first.OperationName, first.StartTime
+		result = append(result, summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Duration > result[j].Duration })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	metricsmocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var snapshotEndTime = time.Unix(1700000000, 0)
+
+func snapshotParams() ServiceSnapshotParameters {
+	return ServiceSnapshotParameters{
+		ServiceName: "frontend",
+		SpanKinds:   []string{"SPAN_KIND_SERVER"},
+		EndTime:     snapshotEndTime,
+		Lookback:    time.Hour,
+		Quantile:    0.95,
+		Limit:       2,
+	}
+}
+
+// operationLatencies creates a metric family with a series per operation of the given latencies in milliseconds.
+func operationLatencies(series map[string][]float64) *metrics.MetricFamily {
+	family := gaugeFamily(series)
+	for _, metric := range family.Metrics {
+		metric.Labels[0].Name = "operation"
+	}
+	return family
+}
+
+func errorTrace(id uint64, start time.Time, durations ...time.Duration) *model.Trace {
+	trace := &model.Trace{}
+	for i, d := range durations {
+		span := &model.Span{
+			TraceID:       model.NewTraceID(0, id),
+			SpanID:        model.NewSpanID(uint64(i + 1)),
+			OperationName: "op" + string(rune('a'+i)),
+			StartTime:     start.Add(time.Duration(i) * time.Millisecond),
+			Duration:      d,
+		}
+		if i == len(durations)-1 {
+			span.Tags = model.KeyValues{model.Bool("error", true)}
+		}
+		trace.Spans = append(trace.Spans, span)
+	}
+	return trace
+}
+
+func TestGetServiceSnapshot(t *testing.T) {
+	tqs := initializeTestService()
+	reader := &metricsmocks.Reader{}
+	reader.On("GetLatencies", mock.Anything, mock.MatchedBy(func(p *metricsstore.LatenciesQueryParameters) bool {
+		return p.EndTime.Equal(snapshotEndTime) && *p.Lookback == time.Hour && *p.Step == time.Hour &&
+			*p.RatePer == time.Hour && p.GroupByOperation && p.Quantile == 0.95 &&
+			assert.ObjectsAreEqual([]string{"frontend"}, p.ServiceNames)
+	})).Return(operationLatencies(map[string][]float64{
+		"GET /":        {10},
+		"GET /cart":    {math.NaN(), 250},
+		"GET /health":  {1},
+		"POST /orders": {math.NaN()},
+	}), nil).Once()
+
+	start := snapshotEndTime.Add(-time.Minute)
+	tqs.spanReader.On("FindTraces", mock.Anything, &spanstore.TraceQueryParameters{
+		ServiceName:  "frontend",
+		Tags:         map[string]string{"error": "true"},
+		StartTimeMin: snapshotEndTime.Add(-time.Hour),
+		StartTimeMax: snapshotEndTime,
+		NumTraces:    2,
+	}).Return([]*model.Trace{
+		errorTrace(1, start, time.Second, 100*time.Millisecond),
+		errorTrace(2, start, 3*time.Second),
+		errorTrace(3, start, 2*time.Second),
+	}, nil).Once()
+	tqs.depsReader.On("GetDependencies", mock.Anything, snapshotEndTime, time.Hour).Return([]model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 10},
+		{Parent: "orders", Child: "mysql", CallCount: 5},
+		{Parent: "gateway", Child: "frontend", CallCount: 20},
+	}, nil).Once()
+
+	snapshot, err := tqs.queryService.GetServiceSnapshot(context.Background(), reader, snapshotParams())
+	require.NoError(t, err)
+	assert.Equal(t, []OperationLatency{
+		{Operation: "GET /cart", Latency: 250 * time.Millisecond},
+		{Operation: "GET /", Latency: 10 * time.Millisecond},
+	}, snapshot.Operations)
+	assert.Equal(t, []ErrorTrace{
+		{TraceID: model.NewTraceID(0, 2), Operation: "opa", StartTime: start, Duration: 3 * time.Second, Spans: 1, ErrorSpans: 1},
+		{TraceID: model.NewTraceID(0, 3), Operation: "opa", StartTime: start, Duration: 2 * time.Second, Spans: 1, ErrorSpans: 1},
+	}, snapshot.ErrorTraces)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "frontend", Child: "orders", CallCount: 10},
+		{Parent: "gateway", Child: "frontend", CallCount: 20},
+	}, snapshot.Dependencies)
+}
+
+func TestGetServiceSnapshotWithoutMetrics(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{{}, errorTrace(1, snapshotEndTime, time.Second)}, nil)
+	tqs.depsReader.On("GetDependencies", mock.Anything, snapshotEndTime, time.Hour).Return(nil, nil)
+
+	disabledReader, err := disabled.NewMetricsReader()
+	require.NoError(t, err)
+	for _, reader := range []metricsstore.Reader{nil, disabledReader} {
+		snapshot, err := tqs.queryService.GetServiceSnapshot(context.Background(), reader, snapshotParams())
+		require.NoError(t, err)
+		assert.Nil(t, snapshot.Operations)
+		assert.Len(t, snapshot.ErrorTraces, 1)
+		assert.Empty(t, snapshot.Dependencies)
+	}
+}
+
+func TestGetServiceSnapshotErrors(t *testing.T) {
+	errStorage := errors.New("storage error")
+	testCases := []struct {
+		name  string
+		setup func(tqs *testQueryService, reader *metricsmocks.Reader)
+	}{
+		{
+			name: "metrics",
+			setup: func(_ *testQueryService, reader *metricsmocks.Reader) {
+				reader.On("GetLatencies", mock.Anything, mock.Anything).Return(nil, errStorage)
+			},
+		},
+		{
+			name: "traces",
+			setup: func(tqs *testQueryService, reader *metricsmocks.Reader) {
+				reader.On("GetLatencies", mock.Anything, mock.Anything).Return(&metrics.MetricFamily{}, nil)
+				tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, errStorage)
+			},
+		},
+		{
+			name: "dependencies",
+			setup: func(tqs *testQueryService, reader *metricsmocks.Reader) {
+				reader.On("GetLatencies", mock.Anything, mock.Anything).Return(&metrics.MetricFamily{}, nil)
+				tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, nil)
+				tqs.depsReader.On("GetDependencies", mock.Anything, mock.Anything, mock.Anything).Return(nil, errStorage)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tqs := initializeTestService()
+			reader := &metricsmocks.Reader{}
+			tc.setup(tqs, reader)
+			_, err := tqs.queryService.GetServiceSnapshot(context.Background(), reader, snapshotParams())
+			require.ErrorIs(t, err, errStorage)
+		})
+	}
+}
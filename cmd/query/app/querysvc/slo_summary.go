@@ -0,0 +1,153 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+// sloPointsPerWindow is the number of data points fetched per service and window.
+// The step and rate duration of the metrics queries are derived from it, so that long
+// windows do not require a large number of data points.
+const sloPointsPerWindow = 60
+
+// serviceNameLabel is the label the metrics readers group the metrics by service with.
+const serviceNameLabel = "service_name"
+
+// SLOQueryParameters contains the parameters of an SLO summary.
+type SLOQueryParameters struct {
+	ServiceNames []string
+	SpanKinds    []string
+	EndTime      time.Time
+	// Objective is the target fraction of successful calls, e.g. 0.999. It must be less than 1.
+	Objective float64
+	// Windows are the durations before EndTime to compute the SLO status over.
+	Windows []time.Duration
+}
+
+// SLOWindow is the status of the SLO of a service over a window.
+type SLOWindow struct {
+	Window time.Duration
+	// Calls is the number of calls estimated from the call rates. When it is zero,
+	// there was no traffic and the other fields are zero.
+	Calls     float64
+	ErrorRate float64
+	// Availability is the fraction of successful calls.
+	Availability float64
+	// BurnRate is the error rate relative to the error budget: a burn rate of 1
+	// exhausts the error budget in exactly the window.
+	BurnRate float64
+	// ErrorBudgetRemaining is the fraction of the error budget of the window that is
+	// left. It is negative when the objective is missed.
+	ErrorBudgetRemaining float64
+}
+
+// ServiceSLO is the SLO status of a service over each of the requested windows.
+type ServiceSLO struct {
+	Service string
+	Windows []SLOWindow
+}
+
+// GetSLOSummary computes the availability and the error budget burn of the services
+// over each window from their call and error rates in the metrics store.
+func GetSLOSummary(ctx context.Context, reader metricsstore.Reader, params SLOQueryParameters) ([]ServiceSLO, error) {
+	summaries := make(map[string]*ServiceSLO, len(params.ServiceNames))
+	for _, service := range params.ServiceNames {
+		summaries[service] = &ServiceSLO{Service: service, Windows: make([]SLOWindow, 0, len(params.Windows))}
+	}
+	for _, window := range params.Windows {
+		endTime := params.EndTime
+		lookback := window
+		step := window / sloPointsPerWindow
+		bqp := metricsstore.BaseQueryParameters{
+			ServiceNames: params.ServiceNames,
+			SpanKinds:    params.SpanKinds,
+			EndTime:      &endTime,
+			Lookback:     &lookback,
+			Step:         &step,
+			RatePer:      &step,
+		}
+		callRates, err := reader.GetCallRates(ctx, &metricsstore.CallRateQueryParameters{BaseQueryParameters: bqp})
+		if err != nil {
+			return nil, err
+		}
+		errorRates, err := reader.GetErrorRates(ctx, &metricsstore.ErrorRateQueryParameters{BaseQueryParameters: bqp})
+		if err != nil {
+			return nil, err
+		}
+		calls, errors := countCalls(callRates, errorRates, step)
+		for _, summary := range summaries {
+			summary.Windows = append(summary.Windows, sloWindow(window, calls[summary.Service], errors[summary.Service], params.Objective))
+		}
+	}
+
+	result := make([]ServiceSLO, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Service < result[j].Service })
+	return result, nil
+}
+
+type pointKey struct {
+	service string
+	seconds int64
+	nanos   int32
+}
+
+// countCalls estimates the number of calls and failed calls per service from the
+// call rates and error rates sampled every step. A missing error rate means no errors.
+func countCalls(callRates, errorRates *metrics.MetricFamily, step time.Duration) (calls, errors map[string]float64) {
+	errorRateAt := make(map[pointKey]float64)
+	forEachPoint(errorRates, func(key pointKey, value float64) {
+		errorRateAt[key] = value
+	})
+	calls = make(map[string]float64)
+	errors = make(map[string]float64)
+	forEachPoint(callRates, func(key pointKey, rate float64) {
+		count := rate * step.Seconds()
+		calls[key.service] += count
+		errors[key.service] += count * errorRateAt[key]
+	})
+	return calls, errors
+}
+
+// forEachPoint calls f with the value of each point of the metric family, skipping the
+// undefined values of a metrics store computing a rate without any data.
+func forEachPoint(family *metrics.MetricFamily, f func(key pointKey, value float64)) {
+	for _, metric := range family.GetMetrics() {
+		var service string
+		for _, label := range metric.GetLabels() {
+			if label.GetName() == serviceNameLabel {
+				service = label.GetValue()
+			}
+		}
+		for _, point := range metric.GetMetricPoints() {
+			value := point.GetGaugeValue().GetDoubleValue()
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			ts := point.GetTimestamp()
+			f(pointKey{service: service, seconds: ts.GetSeconds(), nanos: ts.GetNanos()}, value)
+		}
+	}
+}
+
+func sloWindow(window time.Duration, calls, errors, objective float64) SLOWindow {
+	w := SLOWindow{Window: window, Calls: calls}
+	if calls == 0 {
+		return w
+	}
+	w.ErrorRate = errors / calls
+	w.Availability = 1 - w.ErrorRate
+	w.BurnRate = w.ErrorRate / (1 - objective)
+	w.ErrorBudgetRemaining = 1 - w.BurnRate
+	return w
+}
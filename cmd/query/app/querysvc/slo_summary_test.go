@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	metricsmocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+)
+
+// gaugeFamily creates a metric family with a series per service of the given values,
+// at timestamps one second apart.
+func gaugeFamily(series map[string][]float64) *metrics.MetricFamily {
+	family := &metrics.MetricFamily{Type: metrics.MetricType_GAUGE}
+	for service, values := range series {
+		metric := &metrics.Metric{Labels: []*metrics.Label{{Name: "service_name", Value: service}}}
+		for i, v := range values {
+			metric.MetricPoints = append(metric.MetricPoints, &metrics.MetricPoint{
+				Timestamp: &types.Timestamp{Seconds: int64(i)},
+				Value: &metrics.MetricPoint_GaugeValue{
+					GaugeValue: &metrics.GaugeValue{Value: &metrics.GaugeValue_DoubleValue{DoubleValue: v}},
+				},
+			})
+		}
+		family.Metrics = append(family.Metrics, metric)
+	}
+	return family
+}
+
+func TestGetSLOSummary(t *testing.T) {
+	endTime := time.Unix(1700000000, 0)
+	reader := &metricsmocks.Reader{}
+	hasStep := func(step time.Duration) func(metricsstore.BaseQueryParameters) bool {
+		return func(p metricsstore.BaseQueryParameters) bool {
+			return p.EndTime.Equal(endTime) && *p.Lookback == step*sloPointsPerWindow &&
+				*p.Step == step && *p.RatePer == step &&
+				assert.ObjectsAreEqual([]string{"SPAN_KIND_SERVER"}, p.SpanKinds)
+		}
+	}
+	// one hour window, with 1 minute steps
+	reader.On("GetCallRates", mock.Anything, mock.MatchedBy(func(p *metricsstore.CallRateQueryParameters) bool {
+		return hasStep(time.Minute)(p.BaseQueryParameters)
+	})).Return(gaugeFamily(map[string][]float64{
+		"frontend": {1, 1, 2},
+		"orders":   {0, math.NaN()},
+	}), nil).Once()
+	reader.On("GetErrorRates", mock.Anything, mock.MatchedBy(func(p *metricsstore.ErrorRateQueryParameters) bool {
+		return hasStep(time.Minute)(p.BaseQueryParameters)
+	})).Return(gaugeFamily(map[string][]float64{
+		// no error rate at the second timestamp
+		"frontend": {0.001, math.NaN(), 0.0005},
+	}), nil).Once()
+	// one day window, with 24 minutes steps
+	reader.On("GetCallRates", mock.Anything, mock.MatchedBy(func(p *metricsstore.CallRateQueryParameters) bool {
+		return hasStep(24 * time.Minute)(p.BaseQueryParameters)
+	})).Return(gaugeFamily(map[string][]float64{
+		"frontend": {1},
+	}), nil).Once()
+	reader.On("GetErrorRates", mock.Anything, mock.MatchedBy(func(p *metricsstore.ErrorRateQueryParameters) bool {
+		return hasStep(24 * time.Minute)(p.BaseQueryParameters)
+	})).Return(gaugeFamily(map[string][]float64{
+		"frontend": {0.01},
+	}), nil).Once()
+
+	summaries, err := GetSLOSummary(context.Background(), reader, SLOQueryParameters{
+		ServiceNames: []string{"orders", "frontend"},
+		SpanKinds:    []string{"SPAN_KIND_SERVER"},
+		EndTime:      endTime,
+		Objective:    0.999,
+		Windows:      []time.Duration{time.Hour, 24 * time.Hour},
+	})
+	require.NoError(t, err)
+	reader.AssertExpectations(t)
+	require.Len(t, summaries, 2)
+
+	frontend := summaries[0]
+	assert.Equal(t, "frontend", frontend.Service)
+	require.Len(t, frontend.Windows, 2)
+	hour := frontend.Windows[0]
+	assert.Equal(t, time.Hour, hour.Window)
+	// 60 + 60 + 120 calls, with 0.06 + 0 + 0.06 errors
+	assert.InDelta(t, 240, hour.Calls, 1e-9)
+	assert.InDelta(t, 0.0005, hour.ErrorRate, 1e-12)
+	assert.InDelta(t, 0.9995, hour.Availability, 1e-12)
+	assert.InDelta(t, 0.5, hour.BurnRate, 1e-9)
+	assert.InDelta(t, 0.5, hour.ErrorBudgetRemaining, 1e-9)
+	day := frontend.Windows[1]
+	assert.Equal(t, 24*time.Hour, day.Window)
+	assert.InDelta(t, 1440, day.Calls, 1e-9)
+	assert.InDelta(t, 10, day.BurnRate, 1e-9)
+	assert.InDelta(t, -9, day.ErrorBudgetRemaining, 1e-9)
+
+	orders := summaries[1]
+	assert.Equal(t, "orders", orders.Service)
+	assert.Equal(t, []SLOWindow{{Window: time.Hour}, {Window: 24 * time.Hour}}, orders.Windows)
+}
+
+func TestGetSLOSummaryErrors(t *testing.T) {
+	storageErr := errors.New("storage error")
+	params := SLOQueryParameters{
+		ServiceNames: []string{"frontend"},
+		Objective:    0.99,
+		Windows:      []time.Duration{time.Hour},
+	}
+
+	reader := &metricsmocks.Reader{}
+	reader.On("GetCallRates", mock.Anything, mock.Anything).Return(nil, storageErr).Once()
+	_, err := GetSLOSummary(context.Background(), reader, params)
+	require.ErrorIs(t, err, storageErr)
+
+	reader = &metricsmocks.Reader{}
+	reader.On("GetCallRates", mock.Anything, mock.Anything).Return(&metrics.MetricFamily{}, nil).Once()
+	reader.On("GetErrorRates", mock.Anything, mock.Anything).Return(nil, storageErr).Once()
+	_, err = GetSLOSummary(context.Background(), reader, params)
+	require.ErrorIs(t, err, storageErr)
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// SignificanceLevel is the p-value below which a difference between the compared
+	// traces is reported as significant.
+	SignificanceLevel = 0.05
+
+	// minComparisonSamples is the number of spans of an operation required in each set
+	// of traces for the normal approximations of the significance tests to hold.
+	minComparisonSamples = 5
+)
+
+// OperationStats are the statistics of the spans of an operation in a set of traces.
+type OperationStats struct {
+	Count        int
+	Errors       int
+	MeanDuration time.Duration
+	P50Duration  time.Duration
+	P95Duration  time.Duration
+	P99Duration  time.Duration
+}
+
+// ErrorRate returns the fraction of spans with an error.
+func (s OperationStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+// SignificanceTest is the result of a statistical test of the null hypothesis that
+// the base and current spans of an operation come from the same distribution.
+type SignificanceTest struct {
+	PValue float64
+}
+
+// Significant returns true if the difference is significant at SignificanceLevel.
+func (t SignificanceTest) Significant() bool {
+	return t.PValue < SignificanceLevel
+}
+
+// OperationComparison compares the spans of an operation in the base and current traces.
+type OperationComparison struct {
+	Service   string
+	Operation string
+	Base      OperationStats
+	Current   OperationStats
+	// DurationTest is a Mann-Whitney U test of the span durations, which does not assume
+	// them to be normally distributed. It is nil when either set has too few spans.
+	DurationTest *SignificanceTest
+	// ErrorRateTest is a two-proportion z-test of the error rates. It is nil when either
+	// set has too few spans.
+	ErrorRateTest *SignificanceTest
+}
+
+// MeanDurationChange returns the relative change of the mean duration from the base
+// traces, e.g. 0.1 for an operation 10% slower. It is 0 if either set has no spans.
+func (c OperationComparison) MeanDurationChange() float64 {
+	if c.Base.Count == 0 || c.Current.Count == 0 || c.Base.MeanDuration == 0 {
+		return 0
+	}
+	return float64(c.Current.MeanDuration-c.Base.MeanDuration) / float64(c.Base.MeanDuration)
+}
+
+// ErrorRateDelta returns the change of the error rate from the base traces.
+func (c OperationComparison) ErrorRateDelta() float64 {
+	return c.Current.ErrorRate() - c.Base.ErrorRate()
+}
+
+// TraceComparison compares the operations of two sets of traces, such as traces
+// recorded before and after a deployment.
+type TraceComparison struct {
+	BaseTraces    int
+	CurrentTraces int
+	// Operations contains every operation found in either set, sorted by service
+	// and operation name.
+	Operations []OperationComparison
+}
+
+// CompareTraces computes the per-operation duration and error rate statistics of the
+// base and current traces and tests whether their differences are significant.
+func CompareTraces(base, current []*model.Trace) *TraceComparison {
+	type operation struct {
+		service string
+		name    string
+	}
+	type samples struct {
+		durations []time.Duration
+		errors    int
+	}
+	baseSamples := make(map[operation]*samples)
+	currentSamples := make(map[operation]*samples)
+	collect := func(traces []*model.Trace, into map[operation]*samples) {
+		for _, trace := range traces {
+			for _, span := range trace.Spans {
+				op := operation{service: span.Process.GetServiceName(), name: span.OperationName}
+				s, ok := into[op]
+				if !ok {
+					s = &samples{}
+					into[op] = s
+				}
+				s.durations = append(s.durations, span.Duration)
+				if spanHasError(span) {
+					s.errors++
+				}
+			}
+		}
+	}
+	collect(base, baseSamples)
+	collect(current, currentSamples)
+
+	operations := make([]operation, 0, len(baseSamples)+len(currentSamples))
+	for op := range baseSamples {
+		operations = append(operations, op)
+	}
+	for op := range currentSamples {
+		if _, ok := baseSamples[op]; !ok {
+			operations = append(operations, op)
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].service != operations[j].service {
+			return operations[i].service < operations[j].service
+		}
+		return operations[i].name < operations[j].name
+	})
+
+	comparison := &TraceComparison{
+		BaseTraces:    len(base),
+		CurrentTraces: len(current),
+		Operations:    make([]OperationComparison, 0, len(operations)),
+	}
+	empty := &samples{}
+	for _, op := range operations {
+		b, c := baseSamples[op], currentSamples[op]
+		if b == nil {
+			b = empty
+		}
+		if c == nil {
+			c = empty
+		}
+		oc := OperationComparison{
+			Service:   op.service,
+			Operation: op.name,
+			Base:      operationStats(b.durations, b.errors),
+			Current:   operationStats(c.durations, c.errors),
+		}
+		if len(b.durations) >= minComparisonSamples && len(c.durations) >= minComparisonSamples {
+			oc.DurationTest = &SignificanceTest{PValue: mannWhitneyPValue(b.durations, c.durations)}
+			oc.ErrorRateTest = &SignificanceTest{
+				PValue: twoProportionPValue(b.errors, len(b.durations), c.errors, len(c.durations)),
+			}
+		}
+		comparison.Operations = append(comparison.Operations, oc)
+	}
+	return comparison
+}
+
+// operationStats computes the statistics of the durations, which it sorts.
+func operationStats(durations []time.Duration, errors int) OperationStats {
+	stats := OperationStats{Count: len(durations), Errors: errors}
+	if len(durations) == 0 {
+		return stats
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	stats.MeanDuration = total / time.Duration(len(durations))
+	stats.P50Duration = percentile(durations, 0.5)
+	stats.P95Duration = percentile(durations, 0.95)
+	stats.P99Duration = percentile(durations, 0.99)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// mannWhitneyPValue returns the two-sided p-value of the Mann-Whitney U test of the sorted
+// samples, using the normal approximation with tie and continuity corrections.
+func mannWhitneyPValue(a, b []time.Duration) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+
+	// rank the merged samples, averaging the ranks of ties
+	var rankSumA, tieCorrection float64
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var value time.Duration
+		if j == len(b) || (i < len(a) && a[i] <= b[j]) {
+			value = a[i]
+		} else {
+			value = b[j]
+		}
+		firstRank := float64(i + j + 1)
+		tiesA := 0
+		for i < len(a) && a[i] == value {
+			i++
+			tiesA++
+		}
+		for j < len(b) && b[j] == value {
+			j++
+		}
+		ties := float64(i+j) - firstRank + 1
+		rankSumA += float64(tiesA) * (firstRank + (ties-1)/2)
+		tieCorrection += ties*ties*ties - ties
+	}
+
+	u := rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		// all durations are equal
+		return 1
+	}
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / math.Sqrt(variance)
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// twoProportionPValue returns the two-sided p-value of the z-test of the difference
+// between the proportions x1/n1 and x2/n2.
+func twoProportionPValue(x1, n1, x2, n2 int) float64 {
+	pooled := float64(x1+x2) / float64(n1+n2)
+	variance := pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2))
+	if variance == 0 {
+		// no errors or only errors in both sets
+		return 1
+	}
+	z := math.Abs(float64(x1)/float64(n1)-float64(x2)/float64(n2)) / math.Sqrt(variance)
+	return math.Erfc(z / math.Sqrt2)
+}
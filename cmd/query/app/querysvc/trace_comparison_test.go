@@ -0,0 +1,138 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// comparisonTrace creates a trace with a span of the given operation of svc per duration,
+// where the first errors spans have an error.
+func comparisonTrace(svc, operation string, errors int, durations ...time.Duration) *model.Trace {
+	trace := &model.Trace{}
+	for i, d := range durations {
+		span := &model.Span{
+			OperationName: operation,
+			Process:       &model.Process{ServiceName: svc},
+			Duration:      d,
+		}
+		if i < errors {
+			span.Tags = model.KeyValues{model.Bool("error", true)}
+		}
+		trace.Spans = append(trace.Spans, span)
+	}
+	return trace
+}
+
+func milliseconds(values ...int) []time.Duration {
+	durations := make([]time.Duration, 0, len(values))
+	for _, v := range values {
+		durations = append(durations, time.Duration(v)*time.Millisecond)
+	}
+	return durations
+}
+
+func TestCompareTraces(t *testing.T) {
+	base := []*model.Trace{
+		comparisonTrace("frontend", "GET /", 0, milliseconds(1, 2, 3, 4, 5)...),
+		comparisonTrace("orders", "checkout", 0, milliseconds(10, 10, 10, 10, 10, 10, 10, 10, 10, 10)...),
+		comparisonTrace("orders", "legacy", 0, milliseconds(7)...),
+	}
+	current := []*model.Trace{
+		comparisonTrace("frontend", "GET /", 0, milliseconds(10, 9, 8, 7, 6)...),
+		comparisonTrace("orders", "checkout", 5, milliseconds(10, 10, 10, 10, 10, 10, 10, 10, 10, 10)...),
+		comparisonTrace("orders", "refund", 1, milliseconds(3)...),
+	}
+
+	comparison := CompareTraces(base, current)
+	assert.Equal(t, 3, comparison.BaseTraces)
+	assert.Equal(t, 3, comparison.CurrentTraces)
+	require.Len(t, comparison.Operations, 4)
+
+	frontend := comparison.Operations[0]
+	assert.Equal(t, "frontend", frontend.Service)
+	assert.Equal(t, "GET /", frontend.Operation)
+	assert.Equal(t, OperationStats{
+		Count:        5,
+		MeanDuration: 3 * time.Millisecond,
+		P50Duration:  3 * time.Millisecond,
+		P95Duration:  5 * time.Millisecond,
+		P99Duration:  5 * time.Millisecond,
+	}, frontend.Base)
+	assert.Equal(t, 8*time.Millisecond, frontend.Current.MeanDuration)
+	assert.InDelta(t, 5.0/3, frontend.MeanDurationChange(), 1e-9)
+	require.NotNil(t, frontend.DurationTest)
+	// separated samples of 5: U = 0, z = 12 / sqrt(275/12)
+	assert.InDelta(t, 0.01219, frontend.DurationTest.PValue, 1e-5)
+	assert.True(t, frontend.DurationTest.Significant())
+	require.NotNil(t, frontend.ErrorRateTest)
+	assert.InDelta(t, 1.0, frontend.ErrorRateTest.PValue, 1e-9)
+	assert.False(t, frontend.ErrorRateTest.Significant())
+
+	checkout := comparison.Operations[1]
+	assert.Equal(t, "checkout", checkout.Operation)
+	assert.Equal(t, 5, checkout.Current.Errors)
+	assert.InDelta(t, 0.5, checkout.Current.ErrorRate(), 1e-9)
+	assert.InDelta(t, 0.5, checkout.ErrorRateDelta(), 1e-9)
+	assert.Zero(t, checkout.MeanDurationChange())
+	require.NotNil(t, checkout.DurationTest)
+	assert.InDelta(t, 1.0, checkout.DurationTest.PValue, 1e-9)
+	require.NotNil(t, checkout.ErrorRateTest)
+	// pooled rate 0.25: z = 0.5 / sqrt(0.25 * 0.75 * 0.2)
+	assert.InDelta(t, 0.00982, checkout.ErrorRateTest.PValue, 1e-5)
+	assert.True(t, checkout.ErrorRateTest.Significant())
+
+	legacy := comparison.Operations[2]
+	assert.Equal(t, "legacy", legacy.Operation)
+	assert.Equal(t, 1, legacy.Base.Count)
+	assert.Zero(t, legacy.Current.Count)
+	assert.Zero(t, legacy.MeanDurationChange())
+	assert.Nil(t, legacy.DurationTest)
+	assert.Nil(t, legacy.ErrorRateTest)
+
+	refund := comparison.Operations[3]
+	assert.Equal(t, "refund", refund.Operation)
+	assert.Zero(t, refund.Base.Count)
+	assert.Zero(t, refund.Base.ErrorRate())
+	assert.InDelta(t, 1.0, refund.ErrorRateDelta(), 1e-9)
+	assert.Nil(t, refund.DurationTest)
+}
+
+func TestCompareTracesEmpty(t *testing.T) {
+	assert.Equal(t, &TraceComparison{Operations: []OperationComparison{}}, CompareTraces(nil, nil))
+}
+
+func TestMannWhitneyPValueTies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        []time.Duration
+		b        []time.Duration
+		expected float64
+	}{
+		{
+			name:     "identical samples",
+			a:        milliseconds(1, 2, 2, 3, 3, 3),
+			b:        milliseconds(1, 2, 2, 3, 3, 3),
+			expected: 1,
+		},
+		{
+			// ranks of a: 1.5, 3.5, 3.5, 5.5, 7.5, 9.5; U = 31 - 21 = 10 with mean 18
+			name:     "ties across samples",
+			a:        milliseconds(1, 2, 2, 3, 4, 5),
+			b:        milliseconds(1, 3, 4, 5, 6, 7),
+			expected: 0.22567,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, mannWhitneyPValue(tc.a, tc.b), 1e-5)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TraceGraphNode aggregates the spans of an operation of a trace.
+type TraceGraphNode struct {
+	Service   string
+	Operation string
+	Count     int
+	Errors    int
+	// Duration is the sum of the durations of the spans.
+	Duration time.Duration
+	// SelfDuration is the sum of the durations of the spans not covered by any of their children.
+	SelfDuration time.Duration
+}
+
+// TraceGraphEdge counts the calls from the spans of an operation to the spans of another one.
+// From and To are indexes of TraceGraph.Nodes.
+type TraceGraphEdge struct {
+	From  int
+	To    int
+	Count int
+}
+
+// TraceGraph is the compact representation of a trace shown by the Trace Graph view of the UI,
+// where the spans are grouped by service and operation.
+type TraceGraph struct {
+	Spans int
+	// Nodes are sorted by service and operation name.
+	Nodes []TraceGraphNode
+	// Edges are sorted by their From and To nodes.
+	Edges []TraceGraphEdge
+}
+
+// ComputeTraceGraph groups the spans of the trace by service and operation, and their parent-child
+// relationships by the operations of the parent and the child. It runs in O(n log n) time for n spans,
+// so that traces with tens of thousands of spans can be summarized before they reach the UI.
+func ComputeTraceGraph(trace *model.Trace) *TraceGraph {
+	type operation struct {
+		service string
+		name    string
+	}
+	type edge struct {
+		from int
+		to   int
+	}
+	nodeIndexes := make(map[operation]int)
+	var nodes []TraceGraphNode
+	spanNodes := make([]int, len(trace.Spans))
+	spansByID := make(map[model.SpanID]int, len(trace.Spans))
+	for i, span := range trace.Spans {
+		op := operation{service: span.Process.GetServiceName(), name: span.OperationName}
+		index, ok := nodeIndexes[op]
+		if !ok {
+			index = len(nodes)
+			nodeIndexes[op] = index
+			nodes = append(nodes, TraceGraphNode{Service: op.service, Operation: op.name})
+		}
+		spanNodes[i] = index
+		spansByID[span.SpanID] = i
+		node := &nodes[index]
+		node.Count++
+		node.Duration += span.Duration
+		if spanHasError(span) {
+			node.Errors++
+		}
+	}
+
+	edgeCounts := make(map[edge]int)
+	children := make(map[int][]int)
+	for i, span := range trace.Spans {
+		parent, ok := spansByID[span.ParentSpanID()]
+		if !ok || parent == i {
+			continue
+		}
+		edgeCounts[edge{from: spanNodes[parent], to: spanNodes[i]}]++
+		children[parent] = append(children[parent], i)
+	}
+	for i, span := range trace.Spans {
+		nodes[spanNodes[i]].SelfDuration += span.Duration - childrenCoverage(trace, span, children[i])
+	}
+
+	// sort the nodes and remap the node indexes of the edges
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := nodes[order[i]], nodes[order[j]]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Operation < b.Operation
+	})
+	sortedIndexes := make([]int, len(nodes))
+	graph := &TraceGraph{
+		Spans: len(trace.Spans),
+		Nodes: make([]TraceGraphNode, len(nodes)),
+		Edges: make([]TraceGraphEdge, 0, len(edgeCounts)),
+	}
+	for sorted, index := range order {
+		sortedIndexes[index] = sorted
+		graph.Nodes[sorted] = nodes[index]
+	}
+	for e, count := range edgeCounts {
+		graph.Edges = append(graph.Edges, TraceGraphEdge{From: sortedIndexes[e.from], To: sortedIndexes[e.to], Count: count})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return graph
+}
+
+// childrenCoverage returns how much of the span is covered by the union of its children.
+func childrenCoverage(trace *model.Trace, span *model.Span, children []int) time.Duration {
+	if len(children) == 0 {
+		return 0
+	}
+	type interval struct {
+		start time.Time
+		end   time.Time
+	}
+	spanEnd := span.StartTime.Add(span.Duration)
+	intervals := make([]interval, 0, len(children))
+	for _, child := range children {
+		childSpan := trace.Spans[child]
+		start, end := childSpan.StartTime, childSpan.StartTime.Add(childSpan.Duration)
+		// children are clipped to their parent, as their clocks may be skewed
+		if start.Before(span.StartTime) {
+			start = span.StartTime
+		}
+		if end.After(spanEnd) {
+			end = spanEnd
+		}
+		if end.After(start) {
+			intervals = append(intervals, interval{start: start, end: end})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+	var covered time.Duration
+	var current interval
+	for i, in := range intervals {
+		switch {
+		case i == 0:
+			current = in
+		case in.start.After(current.end):
+			covered += current.end.Sub(current.start)
+			current = in
+		case in.end.After(current.end):
+			current.end = in.end
+		}
+	}
+	if len(intervals) > 0 {
+		covered += current.end.Sub(current.start)
+	}
+	return covered
+}
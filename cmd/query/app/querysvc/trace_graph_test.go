@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func graphSpan(id, parent uint64, svc, operation string, startMs, durationMs int) *model.Span {
+	traceID := model.NewTraceID(0, 1)
+	span := &model.Span{
+		TraceID:       traceID,
+		SpanID:        model.NewSpanID(id),
+		OperationName: operation,
+		Process:       &model.Process{ServiceName: svc},
+		StartTime:     time.Unix(0, 0).Add(time.Duration(startMs) * time.Millisecond),
+		Duration:      time.Duration(durationMs) * time.Millisecond,
+	}
+	if parent != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(parent))}
+	}
+	return span
+}
+
+func TestComputeTraceGraph(t *testing.T) {
+	failed := graphSpan(5, 2, "db", "query", 50, 10)
+	failed.Tags = model.KeyValues{model.Bool("error", true)}
+	trace := &model.Trace{Spans: []*model.Span{
+		graphSpan(1, 0, "frontend", "GET /", 0, 100),
+		// overlapping calls to the same operation
+		graphSpan(2, 1, "orders", "checkout", 10, 50),
+		graphSpan(3, 1, "orders", "checkout", 30, 50),
+		graphSpan(4, 2, "db", "query", 20, 10),
+		failed,
+		// skewed child outside of its parent
+		graphSpan(6, 3, "db", "query", 70, 20),
+		// orphan span whose parent was not received
+		graphSpan(7, 99, "db", "query", 0, 5),
+	}}
+
+	graph := ComputeTraceGraph(trace)
+	assert.Equal(t, 7, graph.Spans)
+	ms := time.Millisecond
+	assert.Equal(t, []TraceGraphNode{
+		{Service: "db", Operation: "query", Count: 4, Errors: 1, Duration: 45 * ms, SelfDuration: 45 * ms},
+		{Service: "frontend", Operation: "GET /", Count: 1, Duration: 100 * ms, SelfDuration: 30 * ms},
+		{Service: "orders", Operation: "checkout", Count: 2, Duration: 100 * ms, SelfDuration: 70 * ms},
+	}, graph.Nodes)
+	assert.Equal(t, []TraceGraphEdge{
+		{From: 1, To: 2, Count: 2},
+		{From: 2, To: 0, Count: 3},
+	}, graph.Edges)
+}
+
+func TestComputeTraceGraphEmpty(t *testing.T) {
+	graph := ComputeTraceGraph(&model.Trace{})
+	assert.Equal(t, 0, graph.Spans)
+	assert.Empty(t, graph.Nodes)
+	assert.Empty(t, graph.Edges)
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// SamplingStrategy defines how traces are drawn from the traces matching a query.
+type SamplingStrategy string
+
+const (
+	// SampleUniform draws traces with equal probability.
+	SampleUniform SamplingStrategy = "uniform"
+	// SampleByDuration splits traces into strata of similar duration and draws
+	// the same number of traces from each of them.
+	SampleByDuration SamplingStrategy = "duration"
+	// SampleByError draws the same number of traces with and without errors.
+	SampleByError SamplingStrategy = "error"
+
+	// DefaultDurationStrata is the default number of duration strata.
+	DefaultDurationStrata = 4
+)
+
+// TraceSamplingParameters defines the sample drawn by SampleTraces.
+type TraceSamplingParameters struct {
+	// SampleSize is the maximum number of traces in the sample.
+	SampleSize int
+	// Strategy is the sampling strategy, SampleUniform if empty.
+	Strategy SamplingStrategy
+	// DurationStrata is the number of strata used by SampleByDuration.
+	DurationStrata int
+	// Seed seeds the random choice; the same seed and the same matching traces
+	// always produce the same sample.
+	Seed int64
+}
+
+// Validate checks the sampling parameters.
+func (p TraceSamplingParameters) Validate() error {
+	if p.SampleSize <= 0 {
+		return fmt.Errorf("sample size must be positive, got %d", p.SampleSize)
+	}
+	switch p.Strategy {
+	case "", SampleUniform, SampleByError:
+	case SampleByDuration:
+		if p.DurationStrata <= 0 {
+			return fmt.Errorf("number of duration strata must be positive, got %d", p.DurationStrata)
+		}
+	default:
+		return fmt.Errorf("unknown sampling strategy %q, expected one of %s, %s, %s", p.Strategy, SampleUniform, SampleByDuration, SampleByError)
+	}
+	return nil
+}
+
+// SampleTraces finds the traces matching the query, of which there are at most query.NumTraces,
+// and returns a random sample of them, along with the number of matching traces.
+func (qs QueryService) SampleTraces(
+	ctx context.Context,
+	query *spanstore.TraceQueryParameters,
+	params TraceSamplingParameters,
+) ([]*model.Trace, int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, 0, err
+	}
+	traces, err := qs.spanReader.FindTraces(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return SampleTraces(traces, params), len(traces), nil
+}
+
+// SampleTraces returns a random sample of the traces according to the parameters,
+// which must be valid. Traces without spans are ignored.
+func SampleTraces(traces []*model.Trace, params TraceSamplingParameters) []*model.Trace {
+	candidates := make([]*model.Trace, 0, len(traces))
+	for _, trace := range traces {
+		if len(trace.Spans) > 0 {
+			candidates = append(candidates, trace)
+		}
+	}
+	// storage backends return traces in no particular order, so they are ordered
+	// by trace ID to make the sample depend only on the seed and the set of traces
+	sort.Slice(candidates, func(i, j int) bool {
+		return traceID(candidates[i]).String() < traceID(candidates[j]).String()
+	})
+
+	var strata [][]*model.Trace
+	switch params.Strategy {
+	case SampleByDuration:
+		strata = durationStrata(candidates, params.DurationStrata)
+	case SampleByError:
+		var ok, failed []*model.Trace
+		for _, trace := range candidates {
+			if hasError(trace) {
+				failed = append(failed, trace)
+			} else {
+				ok = append(ok, trace)
+			}
+		}
+		strata = [][]*model.Trace{ok, failed}
+	default:
+		strata = [][]*model.Trace{candidates}
+	}
+
+	sizes := make([]int, len(strata))
+	for i, stratum := range strata {
+		sizes[i] = len(stratum)
+	}
+	rng := rand.New(rand.NewSource(params.Seed))
+	var sample []*model.Trace
+	for i, n := range allocateSample(sizes, params.SampleSize) {
+		stratum := strata[i]
+		rng.Shuffle(len(stratum), func(a, b int) {
+			stratum[a], stratum[b] = stratum[b], stratum[a]
+		})
+		sample = append(sample, stratum[:n]...)
+	}
+	return sample
+}
+
+// allocateSample splits the sample size evenly between the strata. The share that
+// a stratum cannot fill because it is too small is split between the other strata.
+func allocateSample(sizes []int, sampleSize int) []int {
+	alloc := make([]int, len(sizes))
+	for sampleSize > 0 {
+		open := 0
+		for i, size := range sizes {
+			if alloc[i] < size {
+				open++
+			}
+		}
+		if open == 0 {
+			break
+		}
+		share := max(sampleSize/open, 1)
+		for i, size := range sizes {
+			n := min(share, size-alloc[i], sampleSize)
+			alloc[i] += n
+			sampleSize -= n
+		}
+	}
+	return alloc
+}
+
+// durationStrata splits the traces, ordered by duration, into n strata of equal size.
+func durationStrata(traces []*model.Trace, n int) [][]*model.Trace {
+	sorted := append([]*model.Trace(nil), traces...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return traceDuration(sorted[i]) < traceDuration(sorted[j])
+	})
+	strata := make([][]*model.Trace, n)
+	for i := range strata {
+		strata[i] = sorted[i*len(sorted)/n : (i+1)*len(sorted)/n]
+	}
+	return strata
+}
+
+func traceID(trace *model.Trace) model.TraceID {
+	return trace.Spans[0].TraceID
+}
+
+func traceDuration(trace *model.Trace) time.Duration {
+	start, end := trace.Spans[0].StartTime, trace.Spans[0].StartTime
+	for _, span := range trace.Spans {
+		if span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if spanEnd := span.StartTime.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return end.Sub(start)
+}
+
+func hasError(trace *model.Trace) bool {
+	for _, span := range trace.Spans {
+		if spanHasError(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func spanHasError(span *model.Span) bool {
+	for _, tag := range span.Tags {
+		if tag.Key == "error" && tag.AsString() == "true" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func makeSamplingTrace(id uint64, duration time.Duration, failed bool) *model.Trace {
+	span := &model.Span{
+		TraceID:   model.NewTraceID(0, id),
+		SpanID:    model.NewSpanID(1),
+		StartTime: time.Unix(100, 0),
+		Duration:  duration,
+	}
+	if failed {
+		span.Tags = model.KeyValues{model.Bool("error", true)}
+	}
+	return &model.Trace{Spans: []*model.Span{span}}
+}
+
+func sampledIDs(traces []*model.Trace) []uint64 {
+	ids := make([]uint64, len(traces))
+	for i, trace := range traces {
+		ids[i] = traceID(trace).Low
+	}
+	return ids
+}
+
+func TestTraceSamplingParametersValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params TraceSamplingParameters
+		errMsg string
+	}{
+		{name: "uniform by default", params: TraceSamplingParameters{SampleSize: 1}},
+		{name: "error", params: TraceSamplingParameters{SampleSize: 1, Strategy: SampleByError}},
+		{name: "duration", params: TraceSamplingParameters{SampleSize: 1, Strategy: SampleByDuration, DurationStrata: 2}},
+		{name: "no sample size", params: TraceSamplingParameters{}, errMsg: "sample size must be positive, got 0"},
+		{
+			name:   "no strata",
+			params: TraceSamplingParameters{SampleSize: 1, Strategy: SampleByDuration},
+			errMsg: "number of duration strata must be positive, got 0",
+		},
+		{
+			name:   "unknown strategy",
+			params: TraceSamplingParameters{SampleSize: 1, Strategy: "latency"},
+			errMsg: `unknown sampling strategy "latency", expected one of uniform, duration, error`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.params.Validate()
+			if tc.errMsg != "" {
+				require.EqualError(t, err, tc.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSampleTracesUniform(t *testing.T) {
+	var traces []*model.Trace
+	for i := uint64(1); i <= 20; i++ {
+		traces = append(traces, makeSamplingTrace(i, time.Millisecond, false))
+	}
+	traces = append(traces, &model.Trace{})
+	params := TraceSamplingParameters{SampleSize: 5, Seed: 7}
+
+	sample := SampleTraces(traces, params)
+	assert.Len(t, sample, 5)
+
+	// the sample does not depend on the order of the traces
+	reversed := make([]*model.Trace, len(traces))
+	for i, trace := range traces {
+		reversed[len(traces)-1-i] = trace
+	}
+	assert.Equal(t, sampledIDs(sample), sampledIDs(SampleTraces(reversed, params)))
+
+	params.Seed = 8
+	assert.NotEqual(t, sampledIDs(sample), sampledIDs(SampleTraces(traces, params)))
+
+	params.SampleSize = 100
+	assert.Len(t, SampleTraces(traces, params), 20)
+}
+
+func TestSampleTracesByError(t *testing.T) {
+	var traces []*model.Trace
+	for i := uint64(1); i <= 20; i++ {
+		traces = append(traces, makeSamplingTrace(i, time.Millisecond, i <= 2))
+	}
+
+	sample := SampleTraces(traces, TraceSamplingParameters{SampleSize: 4, Strategy: SampleByError})
+	require.Len(t, sample, 4)
+	failed := 0
+	for _, trace := range sample {
+		if hasError(trace) {
+			failed++
+		}
+	}
+	assert.Equal(t, 2, failed)
+
+	// the traces without errors make up for the missing traces with errors
+	sample = SampleTraces(traces, TraceSamplingParameters{SampleSize: 10, Strategy: SampleByError})
+	assert.Len(t, sample, 10)
+}
+
+func TestSampleTracesByDuration(t *testing.T) {
+	var traces []*model.Trace
+	for i := uint64(1); i <= 20; i++ {
+		traces = append(traces, makeSamplingTrace(i, time.Duration(i)*time.Millisecond, false))
+	}
+
+	sample := SampleTraces(traces, TraceSamplingParameters{SampleSize: 4, Strategy: SampleByDuration, DurationStrata: 4})
+	require.Len(t, sample, 4)
+	for i, trace := range sample {
+		// stratum i holds the traces with durations from 5*i+1 to 5*(i+1) ms
+		assert.InDelta(t, 5*i+3, int(traceDuration(trace)/time.Millisecond), 2)
+	}
+}
+
+func TestTraceDuration(t *testing.T) {
+	trace := makeSamplingTrace(1, 10*time.Millisecond, false)
+	trace.Spans = append(trace.Spans, &model.Span{
+		StartTime: time.Unix(100, 0).Add(-5 * time.Millisecond),
+		Duration:  time.Millisecond,
+	})
+	assert.Equal(t, 15*time.Millisecond, traceDuration(trace))
+}
+
+func TestAllocateSample(t *testing.T) {
+	assert.Equal(t, []int{3, 3}, allocateSample([]int{10, 10}, 6))
+	assert.Equal(t, []int{1, 5}, allocateSample([]int{1, 10}, 6))
+	assert.Equal(t, []int{2, 1, 1}, allocateSample([]int{5, 5, 5}, 4))
+	assert.Equal(t, []int{1, 2}, allocateSample([]int{1, 2}, 6))
+	assert.Equal(t, []int{0}, allocateSample([]int{0}, 6))
+}
+
+func TestQueryServiceSampleTraces(t *testing.T) {
+	tqs := initializeTestService()
+	query := &spanstore.TraceQueryParameters{ServiceName: "service", NumTraces: 100}
+	traces := []*model.Trace{
+		makeSamplingTrace(1, time.Millisecond, false),
+		makeSamplingTrace(2, time.Millisecond, false),
+		makeSamplingTrace(3, time.Millisecond, false),
+	}
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return(traces, nil).Once()
+
+	sample, total, err := tqs.queryService.SampleTraces(context.Background(), query, TraceSamplingParameters{SampleSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, sample, 2)
+	assert.Equal(t, 3, total)
+
+	_, _, err = tqs.queryService.SampleTraces(context.Background(), query, TraceSamplingParameters{})
+	require.EqualError(t, err, "sample size must be positive, got 0")
+
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return(nil, assert.AnError).Once()
+	_, _, err = tqs.queryService.SampleTraces(context.Background(), query, TraceSamplingParameters{SampleSize: 2})
+	require.ErrorIs(t, err, assert.AnError)
+}
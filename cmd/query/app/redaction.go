@@ -0,0 +1,62 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+)
+
+var errRedactedSpans = errors.New("the spans cannot be redacted, which requires a privileged role or tenant")
+
+// roleHandler attaches the role of the caller, read from the header, to the context of the request.
+func roleHandler(h http.Handler, header string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role := r.Header.Get(header); role != "" {
+			r = r.WithContext(querysvc.WithRole(r.Context(), role))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func roleFromMetadata(ctx context.Context, header string) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if roles := md.Get(header); len(roles) > 0 && roles[0] != "" {
+		return querysvc.WithRole(ctx, roles[0])
+	}
+	return ctx
+}
+
+// newRoleUnaryInterceptor attaches the role of the caller, read from the metadata, to the context of the RPC.
+func newRoleUnaryInterceptor(header string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(roleFromMetadata(ctx, header), req)
+	}
+}
+
+// roleServerStream is a wrapper for ServerStream providing the context with the role of the caller
+type roleServerStream struct {
+	grpc.ServerStream
+	context context.Context
+}
+
+func (rss *roleServerStream) Context() context.Context {
+	return rss.context
+}
+
+// newRoleStreamInterceptor is the streaming version of newRoleUnaryInterceptor.
+func newRoleStreamInterceptor(header string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &roleServerStream{ServerStream: ss, context: roleFromMetadata(ss.Context(), header)})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+)
+
+func TestRoleHandler(t *testing.T) {
+	var role string
+	handler := roleHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		role = querysvc.GetRole(r.Context())
+	}), defaultRedactionRoleHeader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	req.Header.Set("X-Jaeger-Role", "admin")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "admin", role)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/traces", nil))
+	assert.Empty(t, role)
+}
+
+func TestRoleInterceptors(t *testing.T) {
+	unary := newRoleUnaryInterceptor(defaultRedactionRoleHeader)
+	stream := newRoleStreamInterceptor(defaultRedactionRoleHeader)
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		role string
+	}{
+		{name: "role", ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-jaeger-role", "admin")), role: "admin"},
+		{name: "no role", ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))},
+		{name: "no metadata", ctx: context.Background()},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var role string
+			_, err := unary(tc.ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				role = querysvc.GetRole(ctx)
+				return nil, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.role, role)
+
+			role = ""
+			err = stream(nil, &roleServerStream{context: tc.ctx}, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+				role = querysvc.GetRole(ss.Context())
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.role, role)
+		})
+	}
+}
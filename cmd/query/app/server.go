@@ -15,6 +15,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -30,6 +31,8 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
@@ -123,6 +126,23 @@ func createGRPCServer(querySvc *querysvc.QueryService, metricsQuerySvc querysvc.
 		)
 	}
 
+	if options.GRPCCompression != "" {
+		if encoding.GetCompressor(options.GRPCCompression) == nil {
+			return nil, fmt.Errorf("unsupported gRPC compression %q", options.GRPCCompression)
+		}
+		grpcOpts = append(grpcOpts,
+			grpc.ChainStreamInterceptor(newCompressionStreamInterceptor(options.GRPCCompression)),
+			grpc.ChainUnaryInterceptor(newCompressionUnaryInterceptor(options.GRPCCompression)),
+		)
+	}
+
+	if options.Redaction.Enabled() && options.Redaction.RoleHeader != "" {
+		grpcOpts = append(grpcOpts,
+			grpc.ChainStreamInterceptor(newRoleStreamInterceptor(options.Redaction.RoleHeader)),
+			grpc.ChainUnaryInterceptor(newRoleUnaryInterceptor(options.Redaction.RoleHeader)),
+		)
+	}
+
 	server := grpc.NewServer(grpcOpts...)
 	reflection.Register(server)
 
@@ -144,6 +164,23 @@ func createGRPCServer(querySvc *querysvc.QueryService, metricsQuerySvc querysvc.
 	return server, nil
 }
 
+// newCompressionUnaryInterceptor compresses the responses with the compressor name,
+// unless the client does not accept it, in which case they are sent as requested.
+func newCompressionUnaryInterceptor(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		_ = grpc.SetSendCompressor(ctx, name)
+		return handler(ctx, req)
+	}
+}
+
+// newCompressionStreamInterceptor is the streaming version of newCompressionUnaryInterceptor.
+func newCompressionStreamInterceptor(name string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		_ = grpc.SetSendCompressor(ss.Context(), name)
+		return handler(srv, ss)
+	}
+}
+
 type httpServer struct {
 	*http.Server
 	staticHandlerCloser io.Closer
@@ -163,6 +200,37 @@ func createHTTPServer(
 		HandlerOptions.Logger(logger),
 		HandlerOptions.Tracer(tracer),
 		HandlerOptions.MetricsQueryService(metricsQuerySvc),
+		HandlerOptions.RawSpans(queryOpts.EnableRawSpans),
+		HandlerOptions.BasePath(queryOpts.BasePath),
+		HandlerOptions.LiveTail(querySvc.LiveTail()),
+	}
+	if queryOpts.Embed.SigningKeyFile != "" {
+		signer, err := loadEmbedTokenSigner(queryOpts.Embed.SigningKeyFile, queryOpts.Embed.TokenTTL)
+		if err != nil {
+			return nil, err
+		}
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.EmbedTokens(signer))
+	}
+	if queryOpts.LinksConfig != "" {
+		resolver, err := loadLinkResolver(queryOpts.LinksConfig)
+		if err != nil {
+			return nil, err
+		}
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.Links(resolver))
+	}
+	if queryOpts.TimePresetsConfig != "" {
+		resolver, err := loadTimePresetsResolver(queryOpts.TimePresetsConfig)
+		if err != nil {
+			return nil, err
+		}
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.TimePresets(resolver))
+	}
+	if queryOpts.DeleteTracesTokenFile != "" {
+		token, err := loadDeleteTracesToken(queryOpts.DeleteTracesTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.DeleteTraces(token))
 	}
 
 	apiHandler := NewAPIHandler(
@@ -184,6 +252,11 @@ func createHTTPServer(
 	apiHandler.RegisterRoutes(r)
 	var handler http.Handler = r
 	handler = additionalHeadersHandler(handler, queryOpts.AdditionalHeaders)
+	handler = frameAncestorsHandler(handler, queryOpts.Embed.FrameAncestors)
+	handler = corsHandler(handler, queryOpts.CORS)
+	if queryOpts.Redaction.Enabled() && queryOpts.Redaction.RoleHeader != "" {
+		handler = roleHandler(handler, queryOpts.Redaction.RoleHeader)
+	}
 	if queryOpts.BearerTokenPropagation {
 		handler = bearertoken.PropagationHandler(logger, handler)
 	}
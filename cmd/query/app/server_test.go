@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
 
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
@@ -634,6 +636,87 @@ func TestServerSinglePort(t *testing.T) {
 	assert.Equal(t, querySvc.expectedServices, res.Services)
 }
 
+type compressionStatsHandler struct {
+	mu          sync.Mutex
+	compression []string
+}
+
+func (*compressionStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *compressionStatsHandler) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if header, ok := s.(*stats.InHeader); ok {
+		h.mu.Lock()
+		h.compression = append(h.compression, header.Compression)
+		h.mu.Unlock()
+	}
+}
+
+func (*compressionStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (*compressionStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func TestServerGRPCCompression(t *testing.T) {
+	hostPort := ports.GetAddressFromCLIOptions(ports.QueryGRPC, "")
+	querySvc := makeQuerySvc()
+	traceID := model.NewTraceID(0, 1)
+	querySvc.spanReader.On("GetTrace", mock.Anything, mock.Anything).
+		Return(&model.Trace{Spans: []*model.Span{{TraceID: traceID, SpanID: model.NewSpanID(1)}}}, nil)
+	server, err := NewServer(zaptest.NewLogger(t), healthcheck.New(), querySvc.qs, nil,
+		&QueryOptions{
+			GRPCHostPort:     hostPort,
+			HTTPHostPort:     ports.GetAddressFromCLIOptions(ports.QueryHTTP, ""),
+			QueryOptionsBase: QueryOptionsBase{GRPCCompression: "gzip"},
+		},
+		tenancy.NewManager(&tenancy.Options{}),
+		jtracer.NoOp())
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		require.NoError(t, server.Close())
+	})
+
+	statsHandler := &compressionStatsHandler{}
+	conn, err := grpc.NewClient(hostPort,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(statsHandler))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	client := api_v2.NewQueryServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	res, err := client.GetServices(ctx, &api_v2.GetServicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, querySvc.expectedServices, res.Services)
+
+	stream, err := client.GetTrace(ctx, &api_v2.GetTraceRequest{TraceID: traceID})
+	require.NoError(t, err)
+	chunk, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Len(t, chunk.Spans, 1)
+
+	statsHandler.mu.Lock()
+	defer statsHandler.mu.Unlock()
+	assert.Equal(t, []string{"gzip", "gzip"}, statsHandler.compression)
+}
+
+func TestServerGRPCCompressionUnsupported(t *testing.T) {
+	_, err := NewServer(zaptest.NewLogger(t), healthcheck.New(), &querysvc.QueryService{}, nil,
+		&QueryOptions{
+			GRPCHostPort:     ":8081",
+			HTTPHostPort:     ":8080",
+			QueryOptionsBase: QueryOptionsBase{GRPCCompression: "lz4"},
+		},
+		tenancy.NewManager(&tenancy.Options{}), jtracer.NoOp())
+	require.EqualError(t, err, `unsupported gRPC compression "lz4"`)
+}
+
 func TestServerGracefulExit(t *testing.T) {
 	flagsSvc := flags.NewService(ports.QueryAdminHTTP)
 
@@ -0,0 +1,359 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+const (
+	timeZoneParam = "timezone"
+	// clockLayout is the layout of the start and end of the business hours.
+	clockLayout = "15:04"
+)
+
+// timePresetsConfig is the format of the time presets configuration file. The tenants
+// override the time zone, locale, business hours and presets of the default configuration.
+type timePresetsConfig struct {
+	timePresetsSettings
+	Tenants map[string]timePresetsSettings `json:"tenants,omitempty"`
+}
+
+// timePresetsSettings are the time presets of the organization or of a tenant.
+type timePresetsSettings struct {
+	// TimeZone is the IANA name of the time zone in which the presets are resolved, e.g. Europe/Berlin.
+	TimeZone string `json:"timezone,omitempty"`
+	// Locale is the BCP 47 language tag the clients format the times with, e.g. de-DE.
+	Locale string `json:"locale,omitempty"`
+	// FirstDayOfWeek is the first day of the calendar weeks, e.g. monday; sunday by default.
+	FirstDayOfWeek string `json:"firstDayOfWeek,omitempty"`
+	// BusinessHours are the working hours of the organization.
+	BusinessHours *businessHours `json:"businessHours,omitempty"`
+	// Presets are the named search windows.
+	Presets []timePreset `json:"presets,omitempty"`
+}
+
+// businessHours are the working hours of the business days, in the time zone of the presets.
+type businessHours struct {
+	// Start and End are the times of day of the business hours, e.g. 09:00 and 17:00.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Days are the business days, e.g. monday; monday to friday by default.
+	Days []string `json:"days,omitempty"`
+}
+
+// timePreset is a named search window, defined by exactly one of Lookback, Days or Weeks.
+type timePreset struct {
+	// Name identifies the preset, e.g. yesterday-business-hours.
+	Name string `json:"name"`
+	// Text is the label of the preset.
+	Text string `json:"text,omitempty"`
+	// Lookback is the duration of a window ending now, e.g. 1h.
+	Lookback string `json:"lookback,omitempty"`
+	// Days is the offset of a calendar day from today, e.g. -1 for yesterday.
+	// With BusinessHours, it is the offset in business days from the current or last business day.
+	Days *int `json:"days,omitempty"`
+	// Weeks is the offset of a calendar week from the current week, e.g. -1 for last week.
+	Weeks *int `json:"weeks,omitempty"`
+	// BusinessHours restricts the day of Days to its business hours.
+	BusinessHours bool `json:"businessHours,omitempty"`
+}
+
+// timePresetsResponse is the time presets resolved for a tenant.
+type timePresetsResponse struct {
+	TimeZone       string               `json:"timezone"`
+	Locale         string               `json:"locale,omitempty"`
+	FirstDayOfWeek string               `json:"firstDayOfWeek"`
+	BusinessHours  *businessHours       `json:"businessHours,omitempty"`
+	Presets        []resolvedTimePreset `json:"presets"`
+}
+
+// resolvedTimePreset is the search window of a preset at the time of the request.
+type resolvedTimePreset struct {
+	Name  string    `json:"name"`
+	Text  string    `json:"text,omitempty"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// timePresetsResolver resolves the configured time presets, so that the UI and other clients
+// resolve named search windows such as yesterday's business hours consistently.
+type timePresetsResolver struct {
+	defaults timePresetsSettings
+	tenants  map[string]timePresetsSettings
+	now      func() time.Time
+}
+
+// compiledBusinessHours are the business hours parsed for resolving the presets.
+type compiledBusinessHours struct {
+	start, end time.Duration
+	days       map[time.Weekday]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+var defaultBusinessDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+
+func newTimePresetsResolver(config timePresetsConfig) (*timePresetsResolver, error) {
+	if len(config.Presets) == 0 {
+		return nil, errors.New("the time presets configuration has no presets")
+	}
+	if err := config.timePresetsSettings.validate(); err != nil {
+		return nil, err
+	}
+	tenants := make(map[string]timePresetsSettings, len(config.Tenants))
+	for tenant, settings := range config.Tenants {
+		settings = config.timePresetsSettings.override(settings)
+		if err := settings.validate(); err != nil {
+			return nil, fmt.Errorf("invalid time presets of tenant %s: %w", tenant, err)
+		}
+		tenants[tenant] = settings
+	}
+	return &timePresetsResolver{defaults: config.timePresetsSettings, tenants: tenants, now: time.Now}, nil
+}
+
+// loadTimePresetsResolver reads the time presets from a JSON file.
+func loadTimePresetsResolver(configFile string) (*timePresetsResolver, error) {
+	bytes, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time presets configuration: %w", err)
+	}
+	var config timePresetsConfig
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse time presets configuration %s: %w", configFile, err)
+	}
+	return newTimePresetsResolver(config)
+}
+
+// override returns the settings with the fields set by the tenant replaced.
+func (s timePresetsSettings) override(tenant timePresetsSettings) timePresetsSettings {
+	if tenant.TimeZone != "" {
+		s.TimeZone = tenant.TimeZone
+	}
+	if tenant.Locale != "" {
+		s.Locale = tenant.Locale
+	}
+	if tenant.FirstDayOfWeek != "" {
+		s.FirstDayOfWeek = tenant.FirstDayOfWeek
+	}
+	if tenant.BusinessHours != nil {
+		s.BusinessHours = tenant.BusinessHours
+	}
+	if len(tenant.Presets) > 0 {
+		s.Presets = tenant.Presets
+	}
+	return s
+}
+
+func (s timePresetsSettings) validate() error {
+	if _, err := s.location(""); err != nil {
+		return err
+	}
+	if _, err := s.firstDayOfWeek(); err != nil {
+		return err
+	}
+	if _, err := s.BusinessHours.compile(); err != nil {
+		return err
+	}
+	for i, preset := range s.Presets {
+		if err := preset.validate(s.BusinessHours != nil); err != nil {
+			return fmt.Errorf("time preset %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (p timePreset) validate(hasBusinessHours bool) error {
+	if p.Name == "" {
+		return errors.New("the preset must have a name")
+	}
+	kinds := 0
+	if p.Lookback != "" {
+		kinds++
+		lookback, err := time.ParseDuration(p.Lookback)
+		if err != nil || lookback <= 0 {
+			return fmt.Errorf("invalid lookback %q of preset %s", p.Lookback, p.Name)
+		}
+	}
+	if p.Days != nil {
+		kinds++
+		if *p.Days > 0 {
+			return fmt.Errorf("the days of preset %s must not be in the future", p.Name)
+		}
+	}
+	if p.Weeks != nil {
+		kinds++
+		if *p.Weeks > 0 {
+			return fmt.Errorf("the weeks of preset %s must not be in the future", p.Name)
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("preset %s must have exactly one of lookback, days or weeks", p.Name)
+	}
+	if p.BusinessHours && (p.Days == nil || !hasBusinessHours) {
+		return fmt.Errorf("preset %s with business hours requires days and the business hours configuration", p.Name)
+	}
+	return nil
+}
+
+// location returns the time zone requested by the client, or else the configured one, UTC by default.
+func (s timePresetsSettings) location(requested string) (*time.Location, error) {
+	name := requested
+	if name == "" {
+		name = s.TimeZone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+func (s timePresetsSettings) firstDayOfWeek() (time.Weekday, error) {
+	if s.FirstDayOfWeek == "" {
+		return time.Sunday, nil
+	}
+	day, ok := weekdays[strings.ToLower(s.FirstDayOfWeek)]
+	if !ok {
+		return 0, fmt.Errorf("unknown first day of week %q", s.FirstDayOfWeek)
+	}
+	return day, nil
+}
+
+func (bh *businessHours) compile() (*compiledBusinessHours, error) {
+	if bh == nil {
+		return nil, nil
+	}
+	start, err := time.Parse(clockLayout, bh.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start %q of business hours, expected HH:MM", bh.Start)
+	}
+	end, err := time.Parse(clockLayout, bh.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end %q of business hours, expected HH:MM", bh.End)
+	}
+	if !end.After(start) {
+		return nil, errors.New("the business hours must end after they start")
+	}
+	names := bh.Days
+	if len(names) == 0 {
+		names = defaultBusinessDays
+	}
+	days := make(map[time.Weekday]bool, len(names))
+	for _, name := range names {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown business day %q", name)
+		}
+		days[day] = true
+	}
+	return &compiledBusinessHours{start: sinceMidnight(start), end: sinceMidnight(end), days: days}, nil
+}
+
+func sinceMidnight(clock time.Time) time.Duration {
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute
+}
+
+// resolve returns the search windows of the presets of the tenant at the current time,
+// in the time zone requested by the client or else in the configured one.
+func (r *timePresetsResolver) resolve(tenant string, requestedTimeZone string) (*timePresetsResponse, error) {
+	settings, ok := r.tenants[tenant]
+	if !ok {
+		settings = r.defaults
+	}
+	location, err := settings.location(requestedTimeZone)
+	if err != nil {
+		return nil, err
+	}
+	// the settings were validated when the resolver was created
+	firstDayOfWeek, _ := settings.firstDayOfWeek()
+	hours, _ := settings.BusinessHours.compile()
+	now := r.now().In(location)
+	presets := make([]resolvedTimePreset, len(settings.Presets))
+	for i, preset := range settings.Presets {
+		start, end := preset.window(now, firstDayOfWeek, hours)
+		presets[i] = resolvedTimePreset{Name: preset.Name, Text: preset.Text, Start: start, End: end}
+	}
+	return &timePresetsResponse{
+		TimeZone:       location.String(),
+		Locale:         settings.Locale,
+		FirstDayOfWeek: strings.ToLower(firstDayOfWeek.String()),
+		BusinessHours:  settings.BusinessHours,
+		Presets:        presets,
+	}, nil
+}
+
+// window returns the start and end of the preset at the time now, in the time zone of now.
+func (p timePreset) window(now time.Time, firstDayOfWeek time.Weekday, hours *compiledBusinessHours) (time.Time, time.Time) {
+	today := startOfDay(now, 0)
+	switch {
+	case p.Lookback != "":
+		lookback, _ := time.ParseDuration(p.Lookback)
+		return now.Add(-lookback), now
+	case p.Weeks != nil:
+		daysSinceWeekStart := (int(now.Weekday()) - int(firstDayOfWeek) + 7) % 7
+		start := startOfDay(today, -daysSinceWeekStart+7**p.Weeks)
+		return start, startOfDay(start, 7)
+	case p.BusinessHours:
+		day := today
+		for !hours.days[day.Weekday()] {
+			day = startOfDay(day, -1)
+		}
+		for n := *p.Days; n < 0; n++ {
+			day = startOfDay(day, -1)
+			for !hours.days[day.Weekday()] {
+				day = startOfDay(day, -1)
+			}
+		}
+		return clockTime(day, hours.start), clockTime(day, hours.end)
+	default:
+		start := startOfDay(today, *p.Days)
+		return start, startOfDay(start, 1)
+	}
+}
+
+// startOfDay returns the midnight of the day the given number of calendar days after t,
+// which is not always 24 hours per day because of daylight saving time.
+func startOfDay(t time.Time, days int) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+days, 0, 0, 0, 0, t.Location())
+}
+
+// clockTime returns the time of day of the day starting at midnight.
+func clockTime(midnight time.Time, clock time.Duration) time.Time {
+	year, month, day := midnight.Date()
+	return time.Date(year, month, day, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+// getTimePresets implements the REST API /time-presets?timezone={time-zone}
+// It responds with the search windows of the configured time presets of the tenant,
+// resolved in the requested time zone or else in the configured one.
+func (aH *APIHandler) getTimePresets(w http.ResponseWriter, r *http.Request) {
+	presets, err := aH.timePresets.resolve(tenancy.GetTenant(r.Context()), r.FormValue(timeZoneParam))
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data:   presets,
+		Total:  len(presets.Presets),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newTestTimePresetsResolver(t *testing.T) *timePresetsResolver {
+	resolver, err := newTimePresetsResolver(timePresetsConfig{
+		timePresetsSettings: timePresetsSettings{
+			TimeZone:       "Europe/Berlin",
+			Locale:         "de-DE",
+			FirstDayOfWeek: "monday",
+			BusinessHours:  &businessHours{Start: "09:00", End: "17:00"},
+			Presets: []timePreset{
+				{Name: "last-hour", Text: "Last hour", Lookback: "1h"},
+				{Name: "today", Days: intPtr(0)},
+				{Name: "yesterday", Days: intPtr(-1)},
+				{Name: "yesterday-business-hours", Days: intPtr(-1), BusinessHours: true},
+				{Name: "this-week", Weeks: intPtr(0)},
+				{Name: "last-week", Weeks: intPtr(-1)},
+			},
+		},
+		Tenants: map[string]timePresetsSettings{
+			"acme": {
+				TimeZone: "America/New_York",
+				Presets:  []timePreset{{Name: "today-business-hours", Days: intPtr(0), BusinessHours: true}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	// Monday, 10:30 in Berlin and 04:30 in New York
+	resolver.now = func() time.Time { return time.Date(2024, 5, 6, 8, 30, 0, 0, time.UTC) }
+	return resolver
+}
+
+func TestTimePresetsResolve(t *testing.T) {
+	resolver := newTestTimePresetsResolver(t)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	response, err := resolver.resolve("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", response.TimeZone)
+	assert.Equal(t, "de-DE", response.Locale)
+	assert.Equal(t, "monday", response.FirstDayOfWeek)
+	assert.Equal(t, []resolvedTimePreset{
+		{Name: "last-hour", Text: "Last hour", Start: at(5, 6, 9, 30), End: at(5, 6, 10, 30)},
+		{Name: "today", Start: at(5, 6, 0, 0), End: at(5, 7, 0, 0)},
+		{Name: "yesterday", Start: at(5, 5, 0, 0), End: at(5, 6, 0, 0)},
+		{Name: "yesterday-business-hours", Start: at(5, 3, 9, 0), End: at(5, 3, 17, 0)},
+		{Name: "this-week", Start: at(5, 6, 0, 0), End: at(5, 13, 0, 0)},
+		{Name: "last-week", Start: at(4, 29, 0, 0), End: at(5, 6, 0, 0)},
+	}, response.Presets)
+
+	response, err = resolver.resolve("acme", "")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", response.TimeZone)
+	assert.Equal(t, "de-DE", response.Locale, "the locale is inherited")
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, []resolvedTimePreset{{
+		Name:  "today-business-hours",
+		Start: time.Date(2024, 5, 6, 9, 0, 0, 0, newYork),
+		End:   time.Date(2024, 5, 6, 17, 0, 0, 0, newYork),
+	}}, response.Presets)
+
+	response, err = resolver.resolve("other", "UTC")
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", response.TimeZone)
+	assert.Equal(t, time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), response.Presets[2].Start)
+
+	_, err = resolver.resolve("", "Mars/Olympus")
+	require.ErrorContains(t, err, `unknown time zone "Mars/Olympus"`)
+}
+
+func TestTimePresetsBusinessDays(t *testing.T) {
+	resolver := newTestTimePresetsResolver(t)
+	// Sunday: the last business day is Friday, the one before is Thursday
+	resolver.now = func() time.Time { return time.Date(2024, 5, 5, 12, 0, 0, 0, time.UTC) }
+	response, err := resolver.resolve("", "UTC")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC), response.Presets[3].Start)
+
+	response, err = resolver.resolve("acme", "UTC")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC), response.Presets[0].Start)
+}
+
+func TestTimePresetsDaylightSavingTime(t *testing.T) {
+	resolver := newTestTimePresetsResolver(t)
+	resolver.now = func() time.Time { return time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC) }
+	response, err := resolver.resolve("", "")
+	require.NoError(t, err)
+	yesterday := response.Presets[2]
+	assert.Equal(t, 23*time.Hour, yesterday.End.Sub(yesterday.Start), "the clocks moved forward on March 31")
+}
+
+func TestNewTimePresetsResolverInvalid(t *testing.T) {
+	presets := []timePreset{{Name: "today", Days: intPtr(0)}}
+	testCases := []struct {
+		name   string
+		config timePresetsConfig
+		err    string
+	}{
+		{name: "no presets", err: "the time presets configuration has no presets"},
+		{
+			name:   "time zone",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{TimeZone: "Mars/Olympus", Presets: presets}},
+			err:    `unknown time zone "Mars/Olympus"`,
+		},
+		{
+			name:   "first day of week",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{FirstDayOfWeek: "someday", Presets: presets}},
+			err:    `unknown first day of week "someday"`,
+		},
+		{
+			name: "business hours start",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				BusinessHours: &businessHours{Start: "9am", End: "17:00"}, Presets: presets,
+			}},
+			err: `invalid start "9am" of business hours, expected HH:MM`,
+		},
+		{
+			name: "business hours end",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				BusinessHours: &businessHours{Start: "09:00", End: "5pm"}, Presets: presets,
+			}},
+			err: `invalid end "5pm" of business hours, expected HH:MM`,
+		},
+		{
+			name: "business hours order",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				BusinessHours: &businessHours{Start: "17:00", End: "09:00"}, Presets: presets,
+			}},
+			err: "the business hours must end after they start",
+		},
+		{
+			name: "business day",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				BusinessHours: &businessHours{Start: "09:00", End: "17:00", Days: []string{"someday"}}, Presets: presets,
+			}},
+			err: `unknown business day "someday"`,
+		},
+		{
+			name:   "preset name",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{Presets: []timePreset{{Days: intPtr(0)}}}},
+			err:    "time preset 0: the preset must have a name",
+		},
+		{
+			name:   "lookback",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{Presets: []timePreset{{Name: "p", Lookback: "-1h"}}}},
+			err:    `time preset 0: invalid lookback "-1h" of preset p`,
+		},
+		{
+			name:   "future days",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{Presets: []timePreset{{Name: "p", Days: intPtr(1)}}}},
+			err:    "time preset 0: the days of preset p must not be in the future",
+		},
+		{
+			name:   "future weeks",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{Presets: []timePreset{{Name: "p", Weeks: intPtr(1)}}}},
+			err:    "time preset 0: the weeks of preset p must not be in the future",
+		},
+		{
+			name: "several windows",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				Presets: []timePreset{{Name: "p", Days: intPtr(0), Weeks: intPtr(0)}},
+			}},
+			err: "time preset 0: preset p must have exactly one of lookback, days or weeks",
+		},
+		{
+			name: "business hours missing",
+			config: timePresetsConfig{timePresetsSettings: timePresetsSettings{
+				Presets: []timePreset{{Name: "p", Days: intPtr(0), BusinessHours: true}},
+			}},
+			err: "time preset 0: preset p with business hours requires days and the business hours configuration",
+		},
+		{
+			name: "tenant",
+			config: timePresetsConfig{
+				timePresetsSettings: timePresetsSettings{Presets: presets},
+				Tenants:             map[string]timePresetsSettings{"acme": {TimeZone: "Mars/Olympus"}},
+			},
+			err: `invalid time presets of tenant acme: unknown time zone "Mars/Olympus"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newTimePresetsResolver(tc.config)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadTimePresetsResolver(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "time-presets.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{
+		"timezone": "Europe/Berlin",
+		"businessHours": {"start": "09:00", "end": "17:00"},
+		"presets": [{"name": "yesterday-business-hours", "days": -1, "businessHours": true}],
+		"tenants": {"acme": {"timezone": "America/New_York"}}
+	}`), 0o600))
+	resolver, err := loadTimePresetsResolver(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, []timePreset{{Name: "yesterday-business-hours", Days: intPtr(-1), BusinessHours: true}}, resolver.defaults.Presets)
+	assert.Equal(t, "America/New_York", resolver.tenants["acme"].TimeZone)
+	assert.Equal(t, resolver.defaults.Presets, resolver.tenants["acme"].Presets)
+
+	_, err = loadTimePresetsResolver(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "failed to read time presets configuration")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"presets": `), 0o600))
+	_, err = loadTimePresetsResolver(configFile)
+	require.ErrorContains(t, err, "failed to parse time presets configuration")
+}
+
+func TestGetTimePresets(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	qs := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, tm, HandlerOptions.TimePresets(newTestTimePresetsResolver(t))).RegisterRoutes(r)
+	server := httptest.NewServer(tenancy.ExtractTenantHTTPHandler(tm, r))
+	defer server.Close()
+
+	var response struct {
+		Data  timePresetsResponse `json:"data"`
+		Total int                 `json:"total"`
+	}
+	require.NoError(t, getJSONCustomHeaders(server.URL+`/api/time-presets`, map[string]string{tm.Header: "acme"}, &response))
+	assert.Equal(t, "America/New_York", response.Data.TimeZone)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, "today-business-hours", response.Data.Presets[0].Name)
+
+	require.NoError(t, getJSONCustomHeaders(server.URL+`/api/time-presets?timezone=UTC`, map[string]string{tm.Header: "other"}, &response))
+	assert.Equal(t, "UTC", response.Data.TimeZone)
+	assert.Equal(t, 6, response.Total)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 30, 0, 0, time.UTC), response.Data.Presets[0].Start.UTC())
+
+	err := getJSONCustomHeaders(server.URL+`/api/time-presets?timezone=Mars/Olympus`, map[string]string{tm.Header: "acme"}, nil)
+	require.ErrorContains(t, err, "400 error from server")
+}
+
+func TestGetTimePresetsDisabled(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	err := getJSON(ts.server.URL+`/api/time-presets`, nil)
+	require.ErrorContains(t, err, "404 error from server")
+}
+
+func TestTimePresetsEndpointMethod(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.TimePresets(newTestTimePresetsResolver(t)))
+	defer ts.server.Close()
+	req, err := http.NewRequest(http.MethodPost, ts.server.URL+`/api/time-presets`, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// traceGraph is the response of the trace graph API.
+type traceGraph struct {
+	TraceID ui.TraceID       `json:"traceID"`
+	Spans   int              `json:"spans"`
+	Nodes   []traceGraphNode `json:"nodes"`
+	Edges   []traceGraphEdge `json:"edges"`
+}
+
+// traceGraphNode has the same duration units as the spans of the UI model, i.e. microseconds.
+type traceGraphNode struct {
+	Service      string `json:"service"`
+	Operation    string `json:"operation"`
+	Count        int    `json:"count"`
+	Errors       int    `json:"errors"`
+	Duration     int64  `json:"duration"`
+	SelfDuration int64  `json:"selfDuration"`
+}
+
+// traceGraphEdge references the nodes by their index in traceGraph.Nodes.
+type traceGraphEdge struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// getTraceGraph implements the REST API /traces/{trace-id}/graph.
+// It responds with the spans of the trace grouped by service and operation, and with the number of
+// calls between these operations, as shown by the Trace Graph view of the UI. The graph is computed
+// server-side so that large traces do not have to be transferred and laid out by the browser.
+func (aH *APIHandler) getTraceGraph(w http.ResponseWriter, r *http.Request) {
+	query, ok := aH.parseGetTraceParameters(w, r)
+	if !ok {
+		return
+	}
+	trace, err := aH.queryService.GetTrace(r.Context(), query)
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	var uiErrors []structuredError
+	if shouldAdjust(r) {
+		// the self durations depend on the children being within their parents, i.e. on clock skew adjustment
+		trace, err = aH.queryService.Adjust(trace)
+		if err != nil {
+			uiErrors = append(uiErrors, structuredError{Msg: err.Error(), TraceID: ui.TraceID(query.TraceID.String())})
+		}
+	}
+	graph := querysvc.ComputeTraceGraph(trace)
+	nodes := make([]traceGraphNode, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		nodes[i] = traceGraphNode{
+			Service:      node.Service,
+			Operation:    node.Operation,
+			Count:        node.Count,
+			Errors:       node.Errors,
+			Duration:     node.Duration.Microseconds(),
+			SelfDuration: node.SelfDuration.Microseconds(),
+		}
+	}
+	edges := make([]traceGraphEdge, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		edges[i] = traceGraphEdge{From: edge.From, To: edge.To, Count: edge.Count}
+	}
+	structuredRes := structuredResponse{
+		Data: traceGraph{
+			TraceID: ui.TraceID(query.TraceID.String()),
+			Spans:   graph.Spans,
+			Nodes:   nodes,
+			Edges:   edges,
+		},
+		Errors: uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type structuredTraceGraphResponse struct {
+	Data   traceGraph        `json:"data"`
+	Errors []structuredError `json:"errors"`
+}
+
+func TestGetTraceGraph(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	start := time.Unix(1_700_000_000, 0)
+	trace := &model.Trace{Spans: []*model.Span{
+		{
+			TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "GET /",
+			Process: &model.Process{ServiceName: "frontend"}, StartTime: start, Duration: 10 * time.Millisecond,
+		},
+		{
+			TraceID: mockTraceID, SpanID: model.NewSpanID(2), OperationName: "checkout",
+			Process: &model.Process{ServiceName: "orders"}, StartTime: start, Duration: 4 * time.Millisecond,
+			References: []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+			Tags:       model.KeyValues{model.Bool("error", true)},
+		},
+	}}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Return(trace, nil).Once()
+
+	var response structuredTraceGraphResponse
+	require.NoError(t, getJSON(ts.server.URL+`/api/traces/123456/graph`, &response))
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, traceGraph{
+		TraceID: "0000000000123456",
+		Spans:   2,
+		Nodes: []traceGraphNode{
+			{Service: "frontend", Operation: "GET /", Count: 1, Duration: 10000, SelfDuration: 6000},
+			{Service: "orders", Operation: "checkout", Count: 1, Errors: 1, Duration: 4000, SelfDuration: 4000},
+		},
+		Edges: []traceGraphEdge{{From: 0, To: 1, Count: 1}},
+	}, response.Data)
+}
+
+func TestGetTraceGraphAdjustmentFailure(t *testing.T) {
+	ts := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
+		Return(mockTrace, nil).Once()
+
+	var response structuredTraceGraphResponse
+	require.NoError(t, getJSON(ts.server.URL+`/api/traces/123456/graph`, &response))
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, errAdjustment.Error(), response.Errors[0].Msg)
+	assert.Equal(t, 2, response.Data.Spans)
+}
+
+func TestGetTraceGraphErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		url  string
+		err  error
+		msg  string
+	}{
+		{name: "not found", url: "/api/traces/123456/graph", err: spanstore.ErrTraceNotFound, msg: parsedError(404, "trace not found")},
+		{name: "storage error", url: "/api/traces/123456/graph", err: errStorage, msg: parsedError(500, errStorageMsg)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := initializeTestServer()
+			defer ts.server.Close()
+			ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("spanstore.GetTraceParameters")).
+				Return(nil, tc.err).Once()
+
+			var response structuredResponse
+			require.EqualError(t, getJSON(ts.server.URL+tc.url, &response), tc.msg)
+		})
+	}
+
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	var response structuredResponse
+	require.Error(t, getJSON(ts.server.URL+`/api/traces/xyz/graph`, &response))
+}
@@ -21,12 +21,14 @@ import (
 	"log"
 	"os"
 
+	_ "github.com/mostynb/go-grpc-compression/nonclobbering/zstd" // registers the zstd compressor for the query gRPC server
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	_ "go.uber.org/automaxprocs"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/doctor"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
@@ -140,6 +142,7 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.QueryAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(doctor.Command(v))
 
 	config.AddFlags(
 		v,
@@ -17,6 +17,8 @@ package app
 import (
 	"flag"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -27,7 +29,10 @@ import (
 )
 
 const (
-	flagGRPCHostPort = "grpc.host-port"
+	flagGRPCHostPort   = "grpc.host-port"
+	flagTenantBackends = "multi-tenancy.backends"
+	flagTraceCacheSize = "trace-cache.size"
+	flagTraceCacheTTL  = "trace-cache.ttl"
 )
 
 var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -42,6 +47,12 @@ type Options struct {
 	TLSGRPC tlscfg.Options
 	// Tenancy configuration
 	Tenancy tenancy.Options
+	// TenantBackends maps tenants to the storage types of the backends holding their data
+	TenantBackends map[string]string
+	// TraceCacheSize is the number of traces kept in the read-through cache of GetTrace, 0 disables the cache
+	TraceCacheSize int
+	// TraceCacheTTL is how long the traces are served from the cache
+	TraceCacheTTL time.Duration
 }
 
 // AddFlags adds flags to flag set.
@@ -49,6 +60,15 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(flagGRPCHostPort, ports.PortToHostPort(ports.RemoteStorageGRPC), "The host:port (e.g. 127.0.0.1:17271 or :17271) of the gRPC server")
 	tlsGRPCFlagsConfig.AddFlags(flagSet)
 	tenancy.AddFlags(flagSet)
+	flagSet.String(flagTenantBackends, "",
+		"comma-separated list of tenant=storage-type pairs routing the requests of tenants to their own backend, "+
+			"e.g. acme=cassandra,globex=elasticsearch. Every storage type must be listed in SPAN_STORAGE_TYPE, "+
+			"other tenants use the first one")
+	flagSet.Int(flagTraceCacheSize, 0,
+		"The maximum number of traces kept in a least-recently-used cache serving the repeated fetches of a trace, "+
+			"e.g. when several engineers look at the trace of an incident, without reading the backend. 0 disables the cache")
+	flagSet.Duration(flagTraceCacheTTL, 30*time.Second,
+		"How long a trace is served from the cache, the spans written to the trace meanwhile are not returned before it expires")
 }
 
 // InitFromViper initializes Options with properties from CLI flags.
@@ -60,5 +80,39 @@ func (o *Options) InitFromViper(v *viper.Viper, _ *zap.Logger) (*Options, error)
 	}
 	o.TLSGRPC = tlsGrpc
 	o.Tenancy = tenancy.InitFromViper(v)
+	tenantBackends, err := parseTenantBackends(v.GetString(flagTenantBackends))
+	if err != nil {
+		return o, err
+	}
+	if len(tenantBackends) > 0 && !o.Tenancy.Enabled {
+		return o, fmt.Errorf("--%s requires multi-tenancy to be enabled", flagTenantBackends)
+	}
+	o.TenantBackends = tenantBackends
+	o.TraceCacheSize = v.GetInt(flagTraceCacheSize)
+	o.TraceCacheTTL = v.GetDuration(flagTraceCacheTTL)
+	if o.TraceCacheSize < 0 {
+		return o, fmt.Errorf("--%s must not be negative, got %d", flagTraceCacheSize, o.TraceCacheSize)
+	}
+	if o.TraceCacheSize > 0 && o.TraceCacheTTL <= 0 {
+		return o, fmt.Errorf("--%s must be positive, got %v", flagTraceCacheTTL, o.TraceCacheTTL)
+	}
 	return o, nil
 }
+
+func parseTenantBackends(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	backends := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		tenant, backend, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || tenant == "" || backend == "" {
+			return nil, fmt.Errorf("invalid --%s entry %q, expected tenant=storage-type", flagTenantBackends, pair)
+		}
+		if _, ok := backends[tenant]; ok {
+			return nil, fmt.Errorf("duplicate --%s entry for tenant %q", flagTenantBackends, tenant)
+		}
+		backends[tenant] = backend
+	}
+	return backends, nil
+}
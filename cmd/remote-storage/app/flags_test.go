@@ -16,6 +16,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,3 +46,70 @@ func TestFailedTLSFlags(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to process gRPC TLS options")
 }
+
+func TestTenantBackendsFlag(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--multi-tenancy.enabled=true",
+		"--multi-tenancy.backends=acme=cassandra, globex=elasticsearch",
+	}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"acme": "cassandra", "globex": "elasticsearch"}, opts.TenantBackends)
+}
+
+func TestTenantBackendsFlagErrors(t *testing.T) {
+	testCases := []struct {
+		flags []string
+		err   string
+	}{
+		{
+			flags: []string{"--multi-tenancy.backends=acme=cassandra"},
+			err:   "--multi-tenancy.backends requires multi-tenancy to be enabled",
+		},
+		{
+			flags: []string{"--multi-tenancy.enabled=true", "--multi-tenancy.backends=acme"},
+			err:   `invalid --multi-tenancy.backends entry "acme", expected tenant=storage-type`,
+		},
+		{
+			flags: []string{"--multi-tenancy.enabled=true", "--multi-tenancy.backends==cassandra"},
+			err:   `invalid --multi-tenancy.backends entry "=cassandra", expected tenant=storage-type`,
+		},
+		{
+			flags: []string{"--multi-tenancy.enabled=true", "--multi-tenancy.backends=acme=cassandra,acme=badger"},
+			err:   `duplicate --multi-tenancy.backends entry for tenant "acme"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.err, func(t *testing.T) {
+			v, command := config.Viperize(AddFlags)
+			require.NoError(t, command.ParseFlags(tc.flags))
+			_, err := new(Options).InitFromViper(v, zap.NewNop())
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestTraceCacheFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Zero(t, opts.TraceCacheSize)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--trace-cache.size=1000",
+		"--trace-cache.ttl=1m",
+	}))
+	opts, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 1000, opts.TraceCacheSize)
+	assert.Equal(t, time.Minute, opts.TraceCacheTTL)
+
+	require.NoError(t, command.ParseFlags([]string{"--trace-cache.ttl=0s"}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "--trace-cache.ttl must be positive, got 0s")
+
+	require.NoError(t, command.ParseFlags([]string{"--trace-cache.size=-1"}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "--trace-cache.size must not be negative, got -1")
+}
@@ -47,7 +47,7 @@ type Server struct {
 
 // NewServer creates and initializes Server.
 func NewServer(options *Options, storageFactory storage.Factory, tm *tenancy.Manager, logger *zap.Logger, healthcheck *healthcheck.HealthCheck) (*Server, error) {
-	handler, err := createGRPCHandler(storageFactory, logger)
+	handler, err := createGRPCHandler(storageFactory, options, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -65,11 +65,14 @@ func NewServer(options *Options, storageFactory storage.Factory, tm *tenancy.Man
 	}, nil
 }
 
-func createGRPCHandler(f storage.Factory, logger *zap.Logger) (*shared.GRPCHandler, error) {
+func createGRPCHandler(f storage.Factory, opts *Options, logger *zap.Logger) (*shared.GRPCHandler, error) {
 	reader, err := f.CreateSpanReader()
 	if err != nil {
 		return nil, err
 	}
+	if opts.TraceCacheSize > 0 {
+		reader = newCachedSpanReader(reader, opts.TraceCacheSize, opts.TraceCacheTTL)
+	}
 	writer, err := f.CreateSpanWriter()
 	if err != nil {
 		return nil, err
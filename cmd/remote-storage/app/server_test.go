@@ -137,7 +137,7 @@ func TestNewServer_TLSConfigError(t *testing.T) {
 
 func TestCreateGRPCHandler(t *testing.T) {
 	storageMocks := newStorageMocks()
-	h, err := createGRPCHandler(storageMocks.factory, zap.NewNop())
+	h, err := createGRPCHandler(storageMocks.factory, &Options{}, zap.NewNop())
 	require.NoError(t, err)
 
 	storageMocks.writer.On("WriteSpan", mock.Anything, mock.Anything).Return(errors.New("writer error"))
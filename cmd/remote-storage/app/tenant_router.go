@@ -0,0 +1,248 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var (
+	_ storage.Factory        = (*TenantRoutingFactory)(nil)
+	_ storage.ArchiveFactory = (*TenantRoutingFactory)(nil)
+)
+
+// BackendFactory is a meta-factory that gives access to the factories of its storage types,
+// like plugin/storage.Factory.
+type BackendFactory interface {
+	storage.Factory
+	Backend(storageType string) (storage.Factory, error)
+}
+
+// NewStorageFactory returns the factory of the storage served by the server. It is f itself unless
+// some tenants have a backend of their own, in which case requests are routed between the backends
+// of those tenants and the backend of defaultType, which serves all other tenants.
+func NewStorageFactory(f BackendFactory, defaultType string, tenantBackends map[string]string) (storage.Factory, error) {
+	if len(tenantBackends) == 0 {
+		return f, nil
+	}
+	defaultBackend, err := f.Backend(defaultType)
+	if err != nil {
+		return nil, err
+	}
+	backends := make(map[string]storage.Factory, len(tenantBackends))
+	for tenant, storageType := range tenantBackends {
+		backend, err := f.Backend(storageType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot route tenant %s: %w", tenant, err)
+		}
+		backends[tenant] = backend
+	}
+	return NewTenantRoutingFactory(defaultBackend, backends), nil
+}
+
+// TenantRoutingFactory is a storage.Factory whose components serve each request from the
+// backend of the tenant found in the request context. Requests of tenants without a backend
+// of their own, or without a tenant, are served from the default backend.
+type TenantRoutingFactory struct {
+	defaultBackend storage.Factory
+	tenantBackends map[string]storage.Factory
+}
+
+// NewTenantRoutingFactory creates a TenantRoutingFactory. The backends are owned by the caller,
+// who is responsible for initializing and closing them.
+func NewTenantRoutingFactory(defaultBackend storage.Factory, tenantBackends map[string]storage.Factory) *TenantRoutingFactory {
+	return &TenantRoutingFactory{
+		defaultBackend: defaultBackend,
+		tenantBackends: tenantBackends,
+	}
+}
+
+// Initialize implements storage.Factory. It does nothing, as the backends are initialized by the caller.
+func (*TenantRoutingFactory) Initialize(metrics.Factory, *zap.Logger) error {
+	return nil
+}
+
+// CreateSpanReader implements storage.Factory.
+func (f *TenantRoutingFactory) CreateSpanReader() (spanstore.Reader, error) {
+	r := &tenantSpanReader{tenants: make(map[string]spanstore.Reader)}
+	err := f.forEachBackend(func(tenant string, backend storage.Factory) error {
+		reader, err := backend.CreateSpanReader()
+		r.set(tenant, reader)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateSpanWriter implements storage.Factory.
+func (f *TenantRoutingFactory) CreateSpanWriter() (spanstore.Writer, error) {
+	w := &tenantSpanWriter{tenants: make(map[string]spanstore.Writer)}
+	err := f.forEachBackend(func(tenant string, backend storage.Factory) error {
+		writer, err := backend.CreateSpanWriter()
+		w.set(tenant, writer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CreateDependencyReader implements storage.Factory.
+func (f *TenantRoutingFactory) CreateDependencyReader() (dependencystore.Reader, error) {
+	r := &tenantDependencyReader{tenants: make(map[string]dependencystore.Reader)}
+	err := f.forEachBackend(func(tenant string, backend storage.Factory) error {
+		reader, err := backend.CreateDependencyReader()
+		r.set(tenant, reader)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateArchiveSpanReader implements storage.ArchiveFactory. Archive storage is only available
+// when every backend supports it, so that archived traces are never mixed between tenants.
+func (f *TenantRoutingFactory) CreateArchiveSpanReader() (spanstore.Reader, error) {
+	r := &tenantSpanReader{tenants: make(map[string]spanstore.Reader)}
+	err := f.forEachBackend(func(tenant string, backend storage.Factory) error {
+		archive, ok := backend.(storage.ArchiveFactory)
+		if !ok {
+			return storage.ErrArchiveStorageNotSupported
+		}
+		reader, err := archive.CreateArchiveSpanReader()
+		r.set(tenant, reader)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateArchiveSpanWriter implements storage.ArchiveFactory.
+func (f *TenantRoutingFactory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
+	w := &tenantSpanWriter{tenants: make(map[string]spanstore.Writer)}
+	err := f.forEachBackend(func(tenant string, backend storage.Factory) error {
+		archive, ok := backend.(storage.ArchiveFactory)
+		if !ok {
+			return storage.ErrArchiveStorageNotSupported
+		}
+		writer, err := archive.CreateArchiveSpanWriter()
+		w.set(tenant, writer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// forEachBackend calls fn with the default backend and an empty tenant,
+// then with every tenant that has a backend of its own.
+func (f *TenantRoutingFactory) forEachBackend(fn func(tenant string, backend storage.Factory) error) error {
+	if err := fn("", f.defaultBackend); err != nil {
+		return err
+	}
+	for tenant, backend := range f.tenantBackends {
+		if err := fn(tenant, backend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type tenantSpanReader struct {
+	defaultReader spanstore.Reader
+	tenants       map[string]spanstore.Reader
+}
+
+func (r *tenantSpanReader) set(tenant string, reader spanstore.Reader) {
+	if tenant == "" {
+		r.defaultReader = reader
+	} else {
+		r.tenants[tenant] = reader
+	}
+}
+
+func (r *tenantSpanReader) reader(ctx context.Context) spanstore.Reader {
+	if reader, ok := r.tenants[tenancy.GetTenant(ctx)]; ok {
+		return reader
+	}
+	return r.defaultReader
+}
+
+func (r *tenantSpanReader) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	return r.reader(ctx).GetTrace(ctx, query)
+}
+
+func (r *tenantSpanReader) GetServices(ctx context.Context) ([]string, error) {
+	return r.reader(ctx).GetServices(ctx)
+}
+
+func (r *tenantSpanReader) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	return r.reader(ctx).GetOperations(ctx, query)
+}
+
+func (r *tenantSpanReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	return r.reader(ctx).FindTraces(ctx, query)
+}
+
+func (r *tenantSpanReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	return r.reader(ctx).FindTraceIDs(ctx, query)
+}
+
+type tenantSpanWriter struct {
+	defaultWriter spanstore.Writer
+	tenants       map[string]spanstore.Writer
+}
+
+func (w *tenantSpanWriter) set(tenant string, writer spanstore.Writer) {
+	if tenant == "" {
+		w.defaultWriter = writer
+	} else {
+		w.tenants[tenant] = writer
+	}
+}
+
+func (w *tenantSpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	if writer, ok := w.tenants[tenancy.GetTenant(ctx)]; ok {
+		return writer.WriteSpan(ctx, span)
+	}
+	return w.defaultWriter.WriteSpan(ctx, span)
+}
+
+type tenantDependencyReader struct {
+	defaultReader dependencystore.Reader
+	tenants       map[string]dependencystore.Reader
+}
+
+func (r *tenantDependencyReader) set(tenant string, reader dependencystore.Reader) {
+	if tenant == "" {
+		r.defaultReader = reader
+	} else {
+		r.tenants[tenant] = reader
+	}
+}
+
+func (r *tenantDependencyReader) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	if reader, ok := r.tenants[tenancy.GetTenant(ctx)]; ok {
+		return reader.GetDependencies(ctx, endTs, lookback)
+	}
+	return r.defaultReader.GetDependencies(ctx, endTs, lookback)
+}
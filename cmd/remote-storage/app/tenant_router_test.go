@@ -0,0 +1,230 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage"
+	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	factoryMocks "github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type fakeBackendFactory struct {
+	*factoryMocks.Factory
+	backends map[string]storage.Factory
+}
+
+func (f *fakeBackendFactory) Backend(storageType string) (storage.Factory, error) {
+	if backend, ok := f.backends[storageType]; ok {
+		return backend, nil
+	}
+	return nil, fmt.Errorf("storage type %s is not configured", storageType)
+}
+
+type archiveFactory struct {
+	*factoryMocks.Factory
+	*factoryMocks.ArchiveFactory
+}
+
+func TestNewStorageFactory(t *testing.T) {
+	f := &fakeBackendFactory{
+		Factory: new(factoryMocks.Factory),
+		backends: map[string]storage.Factory{
+			"memory":    new(factoryMocks.Factory),
+			"cassandra": new(factoryMocks.Factory),
+		},
+	}
+
+	sf, err := NewStorageFactory(f, "memory", nil)
+	require.NoError(t, err)
+	assert.Same(t, f, sf)
+
+	sf, err = NewStorageFactory(f, "memory", map[string]string{"acme": "cassandra"})
+	require.NoError(t, err)
+	assert.Equal(t, NewTenantRoutingFactory(f.backends["memory"], map[string]storage.Factory{"acme": f.backends["cassandra"]}), sf)
+
+	_, err = NewStorageFactory(f, "badger", map[string]string{"acme": "cassandra"})
+	require.EqualError(t, err, "storage type badger is not configured")
+
+	_, err = NewStorageFactory(f, "memory", map[string]string{"acme": "badger"})
+	require.EqualError(t, err, "cannot route tenant acme: storage type badger is not configured")
+}
+
+func TestTenantRoutingFactory(t *testing.T) {
+	newBackend := func(service string) (*factoryMocks.Factory, *spanStoreMocks.Reader, *spanStoreMocks.Writer) {
+		reader := new(spanStoreMocks.Reader)
+		reader.On("GetServices", mock.Anything).Return([]string{service}, nil)
+		writer := new(spanStoreMocks.Writer)
+		writer.On("WriteSpan", mock.Anything, mock.Anything).Return(nil)
+		f := new(factoryMocks.Factory)
+		f.On("CreateSpanReader").Return(reader, nil)
+		f.On("CreateSpanWriter").Return(writer, nil)
+		return f, reader, writer
+	}
+	defaultBackend, _, defaultWriter := newBackend("default-service")
+	acmeBackend, _, acmeWriter := newBackend("acme-service")
+	f := NewTenantRoutingFactory(defaultBackend, map[string]storage.Factory{"acme": acmeBackend})
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	reader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		tenant   string
+		service  string
+		expected *spanStoreMocks.Writer
+		other    *spanStoreMocks.Writer
+	}{
+		{tenant: "acme", service: "acme-service", expected: acmeWriter, other: defaultWriter},
+		{tenant: "globex", service: "default-service", expected: defaultWriter, other: acmeWriter},
+		{tenant: "", service: "default-service", expected: defaultWriter, other: acmeWriter},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.tenant, func(t *testing.T) {
+			ctx := tenancy.WithTenant(context.Background(), tc.tenant)
+			services, err := reader.GetServices(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{tc.service}, services)
+
+			span := &model.Span{OperationName: tc.tenant}
+			require.NoError(t, writer.WriteSpan(ctx, span))
+			tc.expected.AssertCalled(t, "WriteSpan", ctx, span)
+			tc.other.AssertNotCalled(t, "WriteSpan", ctx, span)
+		})
+	}
+}
+
+func TestTenantSpanReader(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{{TraceID: traceID}}}
+	operations := []spanstore.Operation{{Name: "op"}}
+	query := &spanstore.TraceQueryParameters{ServiceName: "svc"}
+
+	acmeReader := new(spanStoreMocks.Reader)
+	acmeReader.On("GetTrace", mock.Anything, spanstore.GetTraceParameters{TraceID: traceID}).Return(trace, nil)
+	acmeReader.On("GetOperations", mock.Anything, spanstore.OperationQueryParameters{ServiceName: "svc"}).Return(operations, nil)
+	acmeReader.On("FindTraces", mock.Anything, query).Return([]*model.Trace{trace}, nil)
+	acmeReader.On("FindTraceIDs", mock.Anything, query).Return([]model.TraceID{traceID}, nil)
+	r := &tenantSpanReader{
+		defaultReader: new(spanStoreMocks.Reader),
+		tenants:       map[string]spanstore.Reader{"acme": acmeReader},
+	}
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+
+	actualTrace, err := r.GetTrace(ctx, spanstore.GetTraceParameters{TraceID: traceID})
+	require.NoError(t, err)
+	assert.Equal(t, trace, actualTrace)
+
+	actualOperations, err := r.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "svc"})
+	require.NoError(t, err)
+	assert.Equal(t, operations, actualOperations)
+
+	traces, err := r.FindTraces(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Trace{trace}, traces)
+
+	traceIDs, err := r.FindTraceIDs(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TraceID{traceID}, traceIDs)
+}
+
+func TestTenantDependencyReader(t *testing.T) {
+	endTs := time.Now()
+	newBackend := func(parent string) *factoryMocks.Factory {
+		reader := new(depStoreMocks.Reader)
+		reader.On("GetDependencies", mock.Anything, endTs, time.Hour).
+			Return([]model.DependencyLink{{Parent: parent, Child: "child"}}, nil)
+		f := new(factoryMocks.Factory)
+		f.On("CreateDependencyReader").Return(reader, nil)
+		return f
+	}
+	f := NewTenantRoutingFactory(newBackend("default"), map[string]storage.Factory{"acme": newBackend("acme")})
+	reader, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+
+	for _, tenant := range []string{"acme", "default"} {
+		deps, err := reader.GetDependencies(tenancy.WithTenant(context.Background(), tenant), endTs, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{{Parent: tenant, Child: "child"}}, deps)
+	}
+}
+
+func TestTenantRoutingFactoryArchive(t *testing.T) {
+	newBackend := func() (*archiveFactory, *spanStoreMocks.Writer) {
+		writer := new(spanStoreMocks.Writer)
+		writer.On("WriteSpan", mock.Anything, mock.Anything).Return(nil)
+		archive := new(factoryMocks.ArchiveFactory)
+		archive.On("CreateArchiveSpanReader").Return(new(spanStoreMocks.Reader), nil)
+		archive.On("CreateArchiveSpanWriter").Return(writer, nil)
+		return &archiveFactory{Factory: new(factoryMocks.Factory), ArchiveFactory: archive}, writer
+	}
+	defaultBackend, defaultWriter := newBackend()
+	acmeBackend, acmeWriter := newBackend()
+	f := NewTenantRoutingFactory(defaultBackend, map[string]storage.Factory{"acme": acmeBackend})
+
+	reader, err := f.CreateArchiveSpanReader()
+	require.NoError(t, err)
+	assert.NotNil(t, reader)
+	writer, err := f.CreateArchiveSpanWriter()
+	require.NoError(t, err)
+
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	span := &model.Span{}
+	require.NoError(t, writer.WriteSpan(ctx, span))
+	acmeWriter.AssertCalled(t, "WriteSpan", ctx, span)
+	defaultWriter.AssertNotCalled(t, "WriteSpan", ctx, span)
+
+	// archive storage is only available when every backend supports it
+	f = NewTenantRoutingFactory(defaultBackend, map[string]storage.Factory{"acme": new(factoryMocks.Factory)})
+	_, err = f.CreateArchiveSpanReader()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotSupported)
+	_, err = f.CreateArchiveSpanWriter()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotSupported)
+}
+
+func TestTenantRoutingFactoryErrors(t *testing.T) {
+	failing := new(factoryMocks.Factory)
+	failing.On("CreateSpanReader").Return(nil, errors.New("no reader"))
+	failing.On("CreateSpanWriter").Return(nil, errors.New("no writer"))
+	failing.On("CreateDependencyReader").Return(nil, errors.New("no deps"))
+	working := newStorageMocks().factory
+
+	for _, f := range []*TenantRoutingFactory{
+		NewTenantRoutingFactory(failing, map[string]storage.Factory{"acme": working}),
+		NewTenantRoutingFactory(working, map[string]storage.Factory{"acme": failing}),
+	} {
+		_, err := f.CreateSpanReader()
+		require.EqualError(t, err, "no reader")
+		_, err = f.CreateSpanWriter()
+		require.EqualError(t, err, "no writer")
+		_, err = f.CreateDependencyReader()
+		require.EqualError(t, err, "no deps")
+	}
+
+	archive := new(factoryMocks.ArchiveFactory)
+	archive.On("CreateArchiveSpanReader").Return(nil, storage.ErrArchiveStorageNotConfigured)
+	archive.On("CreateArchiveSpanWriter").Return(nil, storage.ErrArchiveStorageNotConfigured)
+	f := NewTenantRoutingFactory(&archiveFactory{Factory: new(factoryMocks.Factory), ArchiveFactory: archive}, nil)
+	_, err := f.CreateArchiveSpanReader()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotConfigured)
+	_, err = f.CreateArchiveSpanWriter()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotConfigured)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// cachedSpanReader is a span reader serving the traces read recently from a LRU cache, as a trace
+// is often fetched repeatedly, e.g. when several engineers look at the trace of an incident.
+//
+// The traces are cached by tenant and trace ID for the TTL, so that the spans written to a trace
+// in the meantime are returned once it expires. The traces not found are not cached.
+type cachedSpanReader struct {
+	spanstore.Reader
+	cache cache.Cache
+}
+
+func newCachedSpanReader(reader spanstore.Reader, size int, ttl time.Duration) *cachedSpanReader {
+	return &cachedSpanReader{
+		Reader: reader,
+		cache:  cache.NewLRUWithOptions(size, &cache.Options{TTL: ttl}),
+	}
+}
+
+// GetTrace implements spanstore.Reader#GetTrace. The returned trace is shared by the callers,
+// which must not modify it.
+func (r *cachedSpanReader) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	key := tenancy.GetTenant(ctx) + "|" + query.TraceID.String()
+	if trace, ok := r.cache.Get(key).(*model.Trace); ok {
+		return trace, nil
+	}
+	trace, err := r.Reader.GetTrace(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Put(key, trace)
+	return trace, nil
+}
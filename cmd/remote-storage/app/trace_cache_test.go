@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func TestCachedSpanReaderGetTrace(t *testing.T) {
+	reader := new(spanStoreMocks.Reader)
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	acmeCtx := tenancy.WithTenant(context.Background(), "acme")
+	acmeTrace := &model.Trace{Spans: []*model.Span{{OperationName: "acme"}}}
+	globexTrace := &model.Trace{Spans: []*model.Span{{OperationName: "globex"}}}
+	reader.On("GetTrace", acmeCtx, query).Return(acmeTrace, nil).Once()
+	reader.On("GetTrace", context.Background(), query).Return(globexTrace, nil).Once()
+	r := newCachedSpanReader(reader, 10, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		trace, err := r.GetTrace(acmeCtx, query)
+		require.NoError(t, err)
+		assert.Same(t, acmeTrace, trace)
+	}
+	trace, err := r.GetTrace(context.Background(), query)
+	require.NoError(t, err)
+	assert.Same(t, globexTrace, trace, "the traces are cached by tenant")
+	reader.AssertExpectations(t)
+}
+
+func TestCachedSpanReaderGetTraceErrors(t *testing.T) {
+	reader := new(spanStoreMocks.Reader)
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	reader.On("GetTrace", context.Background(), query).Return(nil, spanstore.ErrTraceNotFound).Once()
+	reader.On("GetTrace", context.Background(), query).Return(nil, errors.New("storage error")).Once()
+	r := newCachedSpanReader(reader, 10, time.Minute)
+
+	_, err := r.GetTrace(context.Background(), query)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	_, err = r.GetTrace(context.Background(), query)
+	require.EqualError(t, err, "storage error")
+	reader.AssertExpectations(t)
+}
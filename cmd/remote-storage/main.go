@@ -25,6 +25,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/doctor"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
@@ -76,8 +77,13 @@ func main() {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
 
+			serverStorage, err := app.NewStorageFactory(storageFactory, storageFactory.SpanReaderType, opts.TenantBackends)
+			if err != nil {
+				logger.Fatal("Failed to route tenants to their storage backends", zap.Error(err))
+			}
+
 			tm := tenancy.NewManager(&opts.Tenancy)
-			server, err := app.NewServer(opts, storageFactory, tm, svc.Logger, svc.HC())
+			server, err := app.NewServer(opts, serverStorage, tm, svc.Logger, svc.HC())
 			if err != nil {
 				logger.Fatal("Failed to create server", zap.Error(err))
 			}
@@ -101,6 +107,7 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.QueryAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(doctor.Command(v))
 
 	config.AddFlags(
 		v,
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	model2otel "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+const (
+	// FormatOTLPJSON writes the traces as OTLP JSON files.
+	FormatOTLPJSON = "otlp-json"
+	// FormatOTLPProto writes the traces as OTLP protobuf files.
+	FormatOTLPProto = "otlp-proto"
+)
+
+// Options are the parameters of an export.
+type Options struct {
+	// OutputDir is the root directory of the exported files, laid out as
+	// <OutputDir>/<date>/<service>/traces-<time>.<extension>.
+	OutputDir string
+	// Format is one of FormatOTLPJSON or FormatOTLPProto.
+	Format string
+	// Step is the time range of each query, smaller steps find more traces with a limited MaxTraces.
+	Step time.Duration
+	// MaxTraces is the maximum number of traces returned by each query.
+	MaxTraces int
+}
+
+// Exporter dumps the traces found by jaeger-query for a time range to files.
+type Exporter struct {
+	client api_v2.QueryServiceClient
+	opts   Options
+	logger *zap.Logger
+
+	marshaler ptrace.Marshaler
+	extension string
+}
+
+// New creates an Exporter.
+func New(client api_v2.QueryServiceClient, opts Options, logger *zap.Logger) (*Exporter, error) {
+	e := &Exporter{
+		client: client,
+		opts:   opts,
+		logger: logger,
+	}
+	switch opts.Format {
+	case FormatOTLPJSON:
+		e.marshaler, e.extension = &ptrace.JSONMarshaler{}, ".otlp.json"
+	case FormatOTLPProto:
+		e.marshaler, e.extension = &ptrace.ProtoMarshaler{}, ".otlp.pb"
+	default:
+		return nil, fmt.Errorf("unsupported format %q, must be one of %q or %q", opts.Format, FormatOTLPJSON, FormatOTLPProto)
+	}
+	if opts.Step <= 0 {
+		return nil, errors.New("the step must be positive")
+	}
+	return e, nil
+}
+
+// Export writes the traces that started between start and end, one step at a time.
+// Each trace is written once, in the directory of the service of its root span.
+func (e *Exporter) Export(ctx context.Context, start, end time.Time) error {
+	res, err := e.client.GetServices(ctx, &api_v2.GetServicesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get services: %w", err)
+	}
+	services := res.Services
+	sort.Strings(services)
+
+	exported := make(map[model.TraceID]struct{})
+	for stepStart := start; stepStart.Before(end); stepStart = stepStart.Add(e.opts.Step) {
+		stepEnd := stepStart.Add(e.opts.Step)
+		if stepEnd.After(end) {
+			stepEnd = end
+		}
+		tracesByService := make(map[string][]*model.Trace)
+		for _, service := range services {
+			traces, err := e.findTraces(ctx, service, stepStart, stepEnd)
+			if err != nil {
+				return err
+			}
+			for _, trace := range traces {
+				traceID := trace.Spans[0].TraceID
+				if _, ok := exported[traceID]; ok {
+					continue
+				}
+				exported[traceID] = struct{}{}
+				rootService := rootServiceName(trace)
+				tracesByService[rootService] = append(tracesByService[rootService], trace)
+			}
+		}
+		for service, traces := range tracesByService {
+			if err := e.writeTraces(service, stepStart, traces); err != nil {
+				return err
+			}
+		}
+	}
+	e.logger.Info("Exported traces", zap.Int("count", len(exported)), zap.Time("start", start), zap.Time("end", end))
+	return nil
+}
+
+// findTraces returns the traces of a service, in the order of their first span received.
+func (e *Exporter) findTraces(ctx context.Context, service string, start, end time.Time) ([]*model.Trace, error) {
+	stream, err := e.client.FindTraces(ctx, &api_v2.FindTracesRequest{
+		Query: &api_v2.TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: start,
+			StartTimeMax: end,
+			SearchDepth:  int32(e.opts.MaxTraces),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find traces of service %s: %w", service, err)
+	}
+	var traces []*model.Trace
+	traceIndex := make(map[model.TraceID]int)
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find traces of service %s: %w", service, err)
+		}
+		for i := range chunk.Spans {
+			span := &chunk.Spans[i]
+			index, ok := traceIndex[span.TraceID]
+			if !ok {
+				index = len(traces)
+				traceIndex[span.TraceID] = index
+				traces = append(traces, &model.Trace{})
+			}
+			traces[index].Spans = append(traces[index].Spans, span)
+		}
+	}
+	if e.opts.MaxTraces > 0 && len(traces) >= e.opts.MaxTraces {
+		e.logger.Warn("The maximum number of traces was found, some traces may be missing; use a smaller step",
+			zap.String("service", service), zap.Time("start", start), zap.Time("end", end))
+	}
+	return traces, nil
+}
+
+// rootServiceName returns the service of the root span of a trace,
+// or of its earliest span when the root span is missing.
+func rootServiceName(trace *model.Trace) string {
+	first := trace.Spans[0]
+	for _, span := range trace.Spans {
+		if span.ParentSpanID() == 0 {
+			return span.Process.GetServiceName()
+		}
+		if span.StartTime.Before(first.StartTime) {
+			first = span
+		}
+	}
+	return first.Process.GetServiceName()
+}
+
+// writeTraces writes the traces to a new file, replacing the file of a previous export of the same step.
+func (e *Exporter) writeTraces(service string, stepStart time.Time, traces []*model.Trace) error {
+	var spans []*model.Span
+	for _, trace := range traces {
+		spans = append(spans, trace.Spans...)
+	}
+	td, err := model2otel.ProtoToTraces([]*model.Batch{{Spans: spans}})
+	if err != nil {
+		return fmt.Errorf("failed to convert traces to OTLP: %w", err)
+	}
+	data, err := e.marshaler.MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces: %w", err)
+	}
+
+	stepStart = stepStart.UTC()
+	dir := filepath.Join(e.opts.OutputDir, stepStart.Format(time.DateOnly), url.PathEscape(service))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	path := filepath.Join(dir, "traces-"+stepStart.Format("20060102T150405Z")+e.extension)
+	// the file is renamed once complete, so that it is never read partially written
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write traces: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write traces: %w", err)
+	}
+	e.logger.Debug("Wrote traces", zap.String("path", path), zap.Int("traces", len(traces)))
+	return nil
+}
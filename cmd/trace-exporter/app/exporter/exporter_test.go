@@ -0,0 +1,221 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+var exportStart = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeQueryService returns the spans of the services that started within the queried time range.
+type fakeQueryService struct {
+	api_v2.UnimplementedQueryServiceServer
+	spans       []model.Span
+	servicesErr error
+	findErr     error
+	queries     []*api_v2.TraceQueryParameters
+}
+
+func (s *fakeQueryService) GetServices(context.Context, *api_v2.GetServicesRequest) (*api_v2.GetServicesResponse, error) {
+	if s.servicesErr != nil {
+		return nil, s.servicesErr
+	}
+	return &api_v2.GetServicesResponse{Services: []string{"frontend", "backend", "db/primary"}}, nil
+}
+
+func (s *fakeQueryService) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.QueryService_FindTracesServer) error {
+	s.queries = append(s.queries, r.Query)
+	if s.findErr != nil {
+		return s.findErr
+	}
+	// the traces that have a span of the service in the time range are returned whole
+	matches := make(map[model.TraceID]bool)
+	for _, span := range s.spans {
+		if span.Process.ServiceName == r.Query.ServiceName &&
+			!span.StartTime.Before(r.Query.StartTimeMin) && span.StartTime.Before(r.Query.StartTimeMax) {
+			matches[span.TraceID] = true
+		}
+	}
+	var spans []model.Span
+	for _, span := range s.spans {
+		if matches[span.TraceID] {
+			spans = append(spans, span)
+		}
+	}
+	return stream.Send(&api_v2.SpansResponseChunk{Spans: spans})
+}
+
+func testSpan(traceID, spanID, parentID uint64, service string, start time.Time) model.Span {
+	span := model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: "op",
+		StartTime:     start,
+		Duration:      time.Millisecond,
+		Process:       model.NewProcess(service, nil),
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+func startQueryService(t *testing.T, service *fakeQueryService) api_v2.QueryServiceClient {
+	server := grpc.NewServer()
+	api_v2.RegisterQueryServiceServer(server, service)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return api_v2.NewQueryServiceClient(conn)
+}
+
+func readTraces(t *testing.T, path string) ptrace.Traces {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(data)
+	require.NoError(t, err)
+	return td
+}
+
+func TestExport(t *testing.T) {
+	service := &fakeQueryService{spans: []model.Span{
+		// trace 1 has a root span in frontend and a child span in backend
+		testSpan(1, 1, 0, "frontend", exportStart.Add(10*time.Minute)),
+		testSpan(1, 2, 1, "backend", exportStart.Add(10*time.Minute)),
+		// trace 2 is missing its root span, its earliest span is in db/primary
+		testSpan(2, 4, 3, "backend", exportStart.Add(70*time.Minute)),
+		testSpan(2, 5, 4, "db/primary", exportStart.Add(65*time.Minute)),
+		// trace 3 is outside of the time range
+		testSpan(3, 6, 0, "frontend", exportStart.Add(-time.Minute)),
+	}}
+	outputDir := t.TempDir()
+	e, err := New(startQueryService(t, service), Options{
+		OutputDir: outputDir,
+		Format:    FormatOTLPJSON,
+		Step:      time.Hour,
+		MaxTraces: 1,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, e.Export(context.Background(), exportStart, exportStart.Add(90*time.Minute)))
+
+	assert.Len(t, service.queries, 6, "each service is queried for each step")
+	assert.Equal(t, exportStart.Add(time.Hour), service.queries[5].StartTimeMin)
+	assert.Equal(t, exportStart.Add(90*time.Minute), service.queries[5].StartTimeMax, "the last step ends with the time range")
+	assert.Equal(t, int32(1), service.queries[5].SearchDepth)
+
+	var files []string
+	err = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(outputDir, path)
+			files = append(files, rel)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"2024-05-01/frontend/traces-20240501T000000Z.otlp.json",
+		"2024-05-01/db%2Fprimary/traces-20240501T010000Z.otlp.json",
+	}, files)
+
+	td := readTraces(t, filepath.Join(outputDir, "2024-05-01/frontend/traces-20240501T000000Z.otlp.json"))
+	assert.Equal(t, 2, td.SpanCount(), "the trace is written once with all its spans")
+	td = readTraces(t, filepath.Join(outputDir, "2024-05-01/db%2Fprimary/traces-20240501T010000Z.otlp.json"))
+	assert.Equal(t, 2, td.SpanCount())
+}
+
+func TestExportProto(t *testing.T) {
+	service := &fakeQueryService{spans: []model.Span{testSpan(1, 1, 0, "frontend", exportStart)}}
+	outputDir := t.TempDir()
+	e, err := New(startQueryService(t, service), Options{
+		OutputDir: outputDir,
+		Format:    FormatOTLPProto,
+		Step:      time.Hour,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, e.Export(context.Background(), exportStart, exportStart.Add(time.Hour)))
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "2024-05-01/frontend/traces-20240501T000000Z.otlp.pb"))
+	require.NoError(t, err)
+	td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, td.SpanCount())
+}
+
+func TestExportErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		service *fakeQueryService
+		wantErr string
+	}{
+		{
+			name:    "services",
+			service: &fakeQueryService{servicesErr: errors.New("storage error")},
+			wantErr: "failed to get services",
+		},
+		{
+			name:    "traces",
+			service: &fakeQueryService{findErr: errors.New("storage error")},
+			wantErr: "failed to find traces of service backend",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := New(startQueryService(t, tc.service), Options{
+				OutputDir: t.TempDir(),
+				Format:    FormatOTLPJSON,
+				Step:      time.Hour,
+			}, zap.NewNop())
+			require.NoError(t, err)
+			err = e.Export(context.Background(), exportStart, exportStart.Add(time.Hour))
+			require.ErrorContains(t, err, tc.wantErr)
+			assert.ErrorContains(t, err, "storage error")
+		})
+	}
+}
+
+func TestExportWriteError(t *testing.T) {
+	service := &fakeQueryService{spans: []model.Span{testSpan(1, 1, 0, "frontend", exportStart)}}
+	outputFile := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(outputFile, nil, 0o600))
+	e, err := New(startQueryService(t, service), Options{
+		OutputDir: outputFile,
+		Format:    FormatOTLPJSON,
+		Step:      time.Hour,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	err = e.Export(context.Background(), exportStart, exportStart.Add(time.Hour))
+	require.ErrorContains(t, err, "failed to create directory")
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	_, err := New(nil, Options{Format: "parquet", Step: time.Hour}, zap.NewNop())
+	require.EqualError(t, err, `unsupported format "parquet", must be one of "otlp-json" or "otlp-proto"`)
+
+	_, err = New(nil, Options{Format: FormatOTLPJSON}, zap.NewNop())
+	require.EqualError(t, err, "the step must be positive")
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jaegertracing/jaeger/cmd/trace-exporter/app/exporter"
+)
+
+// Options represent configurable parameters for jaeger-trace-exporter
+type Options struct {
+	QueryGRPCHostPort string
+	OutputDir         string
+	Format            string
+	End               string
+	Window            time.Duration
+	Step              time.Duration
+	MaxTraces         int
+}
+
+const (
+	queryGRPCHostPortFlag = "query-host-port"
+	outputDirFlag         = "output-dir"
+	formatFlag            = "format"
+	endFlag               = "end"
+	windowFlag            = "window"
+	stepFlag              = "step"
+	maxTracesFlag         = "max-traces"
+)
+
+// AddFlags adds flags for trace exporter main program
+func (o *Options) AddFlags(command *cobra.Command) {
+	command.Flags().StringVar(
+		&o.QueryGRPCHostPort,
+		queryGRPCHostPortFlag,
+		"localhost:16685",
+		"The host:port of the jaeger-query gRPC endpoint")
+	command.Flags().StringVar(
+		&o.OutputDir,
+		outputDirFlag,
+		"/tmp/jaeger-traces",
+		"The directory to write the traces to, laid out as <date>/<service>/traces-<time>.<extension>. "+
+			"It may be an object storage bucket mounted in the file system, or a directory synchronized to object storage")
+	command.Flags().StringVar(
+		&o.Format,
+		formatFlag,
+		exporter.FormatOTLPJSON,
+		fmt.Sprintf("The format of the files, either %q or %q", exporter.FormatOTLPJSON, exporter.FormatOTLPProto))
+	command.Flags().StringVar(
+		&o.End,
+		endFlag,
+		"",
+		"The end of the exported time range in RFC3339 format, defaults to the last midnight UTC")
+	command.Flags().DurationVar(
+		&o.Window,
+		windowFlag,
+		24*time.Hour,
+		"The duration of the exported time range, ending at the end time")
+	command.Flags().DurationVar(
+		&o.Step,
+		stepFlag,
+		time.Hour,
+		"The time range of each query and of each file")
+	command.Flags().IntVar(
+		&o.MaxTraces,
+		maxTracesFlag,
+		1000,
+		"The maximum number of traces found by each query of a service")
+}
+
+// TimeRange returns the exported time range.
+func (o *Options) TimeRange(now time.Time) (start time.Time, end time.Time, err error) {
+	if o.End == "" {
+		end = now.UTC().Truncate(24 * time.Hour)
+	} else if end, err = time.Parse(time.RFC3339, o.End); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time: %w", err)
+	}
+	return end.Add(-o.Window), end, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsWithDefaultFlags(t *testing.T) {
+	o := Options{}
+	c := cobra.Command{}
+	o.AddFlags(&c)
+
+	assert.Equal(t, "localhost:16685", o.QueryGRPCHostPort)
+	assert.Equal(t, "/tmp/jaeger-traces", o.OutputDir)
+	assert.Equal(t, "otlp-json", o.Format)
+	assert.Equal(t, "", o.End)
+	assert.Equal(t, 24*time.Hour, o.Window)
+	assert.Equal(t, time.Hour, o.Step)
+	assert.Equal(t, 1000, o.MaxTraces)
+}
+
+func TestOptionsWithFlags(t *testing.T) {
+	o := Options{}
+	c := cobra.Command{}
+
+	o.AddFlags(&c)
+	c.ParseFlags([]string{
+		"--query-host-port=192.168.1.10:16685",
+		"--output-dir=/data/traces",
+		"--format=otlp-proto",
+		"--end=2024-05-01T06:00:00Z",
+		"--window=6h",
+		"--step=10m",
+		"--max-traces=50",
+	})
+
+	assert.Equal(t, "192.168.1.10:16685", o.QueryGRPCHostPort)
+	assert.Equal(t, "/data/traces", o.OutputDir)
+	assert.Equal(t, "otlp-proto", o.Format)
+	assert.Equal(t, "2024-05-01T06:00:00Z", o.End)
+	assert.Equal(t, 6*time.Hour, o.Window)
+	assert.Equal(t, 10*time.Minute, o.Step)
+	assert.Equal(t, 50, o.MaxTraces)
+}
+
+func TestOptionsTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 3, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	o := Options{Window: 24 * time.Hour}
+	start, end, err := o.TimeRange(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), end, "the last midnight UTC")
+
+	o = Options{Window: time.Hour, End: "2024-05-01T06:00:00Z"}
+	start, end, err = o.TimeRange(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC), end.UTC())
+
+	o = Options{End: "yesterday"}
+	_, _, err = o.TimeRange(now)
+	require.ErrorContains(t, err, "invalid end time")
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jaegertracing/jaeger/cmd/trace-exporter/app"
+	"github.com/jaegertracing/jaeger/cmd/trace-exporter/app/exporter"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+var logger, _ = zap.NewDevelopment()
+
+func main() {
+	options := app.Options{}
+
+	command := &cobra.Command{
+		Use:   "jaeger-trace-exporter",
+		Short: "Jaeger trace exporter dumps the traces of a time range to files",
+		Long: `Jaeger trace exporter queries Jaeger query for all the traces of a time range, ` +
+			`and writes them to OTLP files organized by date and service. It is meant to run periodically, e.g. nightly from a cron job.`,
+		Run: func(_ *cobra.Command, _ /* args */ []string) {
+			start, end, err := options.TimeRange(time.Now())
+			if err != nil {
+				logger.Fatal("invalid time range", zap.Error(err))
+			}
+
+			conn, err := grpc.NewClient(options.QueryGRPCHostPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				logger.Fatal("failed to connect with the jaeger-query service", zap.Error(err))
+			}
+
+			e, err := exporter.New(api_v2.NewQueryServiceClient(conn), exporter.Options{
+				OutputDir: options.OutputDir,
+				Format:    options.Format,
+				Step:      options.Step,
+				MaxTraces: options.MaxTraces,
+			}, logger)
+			if err != nil {
+				logger.Fatal("error while creating exporter", zap.Error(err))
+			}
+			if err := e.Export(context.Background(), start, end); err != nil {
+				logger.Fatal("error while exporting traces", zap.Error(err))
+			}
+			if err := conn.Close(); err != nil {
+				logger.Error("Failed to close grpc client connection", zap.Error(err))
+			}
+		},
+	}
+
+	options.AddFlags(command)
+
+	command.AddCommand(version.Command())
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
@@ -58,6 +58,11 @@ func main() {
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 	jaegerclientenv2otel.MapJaegerToOtelEnvVars(logger)
 
+	if cfg.Replay.Enabled() {
+		replay(cfg, logger)
+		return
+	}
+
 	tracers, shutdown := createTracers(cfg, logger)
 	defer shutdown(context.Background())
 
@@ -110,6 +115,34 @@ func createTracers(cfg *tracegen.Config, logger *zap.Logger) ([]trace.Tracer, fu
 	}
 }
 
+func replay(cfg *tracegen.Config, logger *zap.Logger) {
+	client, err := createOtelClient(cfg.TraceExporter)
+	if err != nil {
+		logger.Sugar().Fatalf("cannot create trace exporter %s: %s", cfg.TraceExporter, err)
+	}
+	ctx := context.Background()
+	batches, err := cfg.Replay.Load(ctx)
+	if err != nil {
+		logger.Fatal("cannot load the traces to replay", zap.Error(err))
+	}
+	if err := tracegen.Replay(ctx, &cfg.Replay, batches, client, logger); err != nil {
+		logger.Fatal("cannot replay the traces", zap.Error(err))
+	}
+}
+
+// createOtelClient creates the OTLP client submitting the replayed traces as they are,
+// rather than through the SDK spans of createOtelExporter.
+func createOtelClient(exporterType string) (otlptrace.Client, error) {
+	switch exporterType {
+	case "otlp", "otlp-http":
+		return otlptracehttp.NewClient(otlptracehttp.WithInsecure()), nil
+	case "otlp-grpc":
+		return otlptracegrpc.NewClient(otlptracegrpc.WithInsecure()), nil
+	default:
+		return nil, fmt.Errorf("exporter type %s is not supported to replay traces, please use otlp-http or otlp-grpc", exporterType)
+	}
+}
+
 func createOtelExporter(exporterType string) (sdktrace.SpanExporter, error) {
 	var exporter sdktrace.SpanExporter
 	var err error
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// AppendTagsFromAttributes converts OTLP attributes to Jaeger tags and appends them to dest.
+// Maps and slices have no Jaeger equivalent and are converted to strings with their JSON representation.
+func AppendTagsFromAttributes(dest []model.KeyValue, attrs pcommon.Map) []model.KeyValue {
+	attrs.Range(func(key string, value pcommon.Value) bool {
+		dest = append(dest, AttributeToTag(key, value))
+		return true
+	})
+	return dest
+}
+
+// AttributeToTag converts an OTLP attribute to a Jaeger tag.
+func AttributeToTag(key string, value pcommon.Value) model.KeyValue {
+	switch value.Type() {
+	case pcommon.ValueTypeInt:
+		return model.Int64(key, value.Int())
+	case pcommon.ValueTypeBool:
+		return model.Bool(key, value.Bool())
+	case pcommon.ValueTypeDouble:
+		return model.Float64(key, value.Double())
+	case pcommon.ValueTypeBytes:
+		return model.Binary(key, value.Bytes().AsRaw())
+	default:
+		// strings, and maps and slices as JSON
+		return model.String(key, value.AsString())
+	}
+}
+
+// TagsToAttributes converts Jaeger tags to OTLP attributes and puts them into dest.
+// A later tag overwrites an earlier one with the same key.
+func TagsToAttributes(tags []model.KeyValue, dest pcommon.Map) {
+	dest.EnsureCapacity(dest.Len() + len(tags))
+	for _, tag := range tags {
+		putTag(dest, tag)
+	}
+}
+
+func putTag(dest pcommon.Map, tag model.KeyValue) {
+	switch tag.GetVType() {
+	case model.ValueType_STRING:
+		dest.PutStr(tag.Key, tag.GetVStr())
+	case model.ValueType_BOOL:
+		dest.PutBool(tag.Key, tag.GetVBool())
+	case model.ValueType_INT64:
+		dest.PutInt(tag.Key, tag.GetVInt64())
+	case model.ValueType_FLOAT64:
+		dest.PutDouble(tag.Key, tag.GetVFloat64())
+	case model.ValueType_BINARY:
+		dest.PutEmptyBytes(tag.Key).FromRaw(tag.GetVBinary())
+	default:
+		dest.PutStr(tag.Key, fmt.Sprintf("<Unknown Jaeger TagType %q>", tag.GetVType()))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestAttributeToTag(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("str", "value")
+	attrs.PutInt("int", 42)
+	attrs.PutBool("bool", true)
+	attrs.PutDouble("double", 1.5)
+	attrs.PutEmptyBytes("bytes").FromRaw([]byte{1, 2})
+	attrs.PutEmptyMap("map").PutStr("k", "v")
+	attrs.PutEmptySlice("slice").AppendEmpty().SetInt(1)
+	attrs.PutEmpty("empty")
+
+	tags := AppendTagsFromAttributes([]model.KeyValue{model.String("existing", "tag")}, attrs)
+	assert.Equal(t, []model.KeyValue{
+		model.String("existing", "tag"),
+		model.String("str", "value"),
+		model.Int64("int", 42),
+		model.Bool("bool", true),
+		model.Float64("double", 1.5),
+		model.Binary("bytes", []byte{1, 2}),
+		model.String("map", `{"k":"v"}`),
+		model.String("slice", "[1]"),
+		model.String("empty", ""),
+	}, tags)
+}
+
+func TestTagsToAttributes(t *testing.T) {
+	tags := []model.KeyValue{
+		model.String("str", "value"),
+		model.Int64("int", 42),
+		model.Bool("bool", true),
+		model.Float64("double", 1.5),
+		model.Binary("bytes", []byte{1, 2}),
+		{Key: "unknown", VType: model.ValueType(-1)},
+		model.String("overwritten", "first"),
+		model.String("overwritten", "second"),
+	}
+	attrs := pcommon.NewMap()
+	attrs.PutStr("existing", "attribute")
+	TagsToAttributes(tags, attrs)
+
+	assert.Equal(t, map[string]any{
+		"existing":    "attribute",
+		"str":         "value",
+		"int":         int64(42),
+		"bool":        true,
+		"double":      1.5,
+		"bytes":       []byte{1, 2},
+		"unknown":     `<Unknown Jaeger TagType "-1">`,
+		"overwritten": "second",
+	}, attrs.AsRaw())
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	tags := []model.KeyValue{
+		model.String("str", "value"),
+		model.String("empty-str", ""),
+		model.Int64("int", -42),
+		model.Bool("bool", false),
+		model.Float64("double", 1e-10),
+		model.Binary("bytes", []byte{0, 255}),
+	}
+	attrs := pcommon.NewMap()
+	TagsToAttributes(tags, attrs)
+	assert.Equal(t, tags, AppendTagsFromAttributes(nil, attrs))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// EventNameKey is the key of the Jaeger log field holding the name of an OTLP span event,
+// see https://opentelemetry.io/docs/specs/otel/trace/sdk_exporters/jaeger/#events.
+// It is also the field that Zipkin annotations are converted to.
+const EventNameKey = "event"
+
+// SpanEventsToLogs converts OTLP span events to Jaeger span logs. The name of an event
+// becomes the EventNameKey field of the log, unless the event has such an attribute already.
+func SpanEventsToLogs(events ptrace.SpanEventSlice) []model.Log {
+	if events.Len() == 0 {
+		return nil
+	}
+	logs := make([]model.Log, 0, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		fields := make([]model.KeyValue, 0, event.Attributes().Len()+1)
+		if _, ok := event.Attributes().Get(EventNameKey); !ok && event.Name() != "" {
+			fields = append(fields, model.String(EventNameKey, event.Name()))
+		}
+		logs = append(logs, model.Log{
+			Timestamp: event.Timestamp().AsTime(),
+			Fields:    AppendTagsFromAttributes(fields, event.Attributes()),
+		})
+	}
+	return logs
+}
+
+// LogsToSpanEvents converts Jaeger span logs to OTLP span events and appends them to dest.
+// A string EventNameKey field becomes the name of the event, other fields become its attributes
+// in the same order.
+func LogsToSpanEvents(logs []model.Log, dest ptrace.SpanEventSlice) {
+	dest.EnsureCapacity(dest.Len() + len(logs))
+	for _, log := range logs {
+		event := dest.AppendEmpty()
+		event.SetTimestamp(pcommon.NewTimestampFromTime(log.Timestamp))
+		attrs := event.Attributes()
+		for _, field := range log.Fields {
+			if field.Key == EventNameKey && field.VType == model.StringType {
+				event.SetName(field.VStr)
+				continue
+			}
+			putTag(attrs, field)
+		}
+	}
+}
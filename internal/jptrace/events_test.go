@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"testing"
+	"time"
+
+	jaegertranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var eventTime = time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC)
+
+func testSpanEvents() ptrace.SpanEventSlice {
+	events := ptrace.NewSpanEventSlice()
+
+	named := events.AppendEmpty()
+	named.SetName("exception")
+	named.SetTimestamp(pcommon.NewTimestampFromTime(eventTime))
+	named.Attributes().PutStr("exception.type", "io.EOF")
+	named.Attributes().PutInt("retries", 3)
+	named.Attributes().PutBool("fatal", false)
+	named.Attributes().PutDouble("ratio", 0.5)
+	named.Attributes().PutEmptyBytes("payload").FromRaw([]byte("raw"))
+
+	unnamed := events.AppendEmpty()
+	unnamed.SetTimestamp(pcommon.NewTimestampFromTime(eventTime.Add(time.Millisecond)))
+	unnamed.Attributes().PutStr("message", "no name")
+
+	empty := events.AppendEmpty()
+	empty.SetTimestamp(pcommon.NewTimestampFromTime(eventTime.Add(2 * time.Millisecond)))
+	empty.SetName("empty")
+	return events
+}
+
+func testLogs() []model.Log {
+	return []model.Log{
+		{
+			Timestamp: eventTime,
+			Fields: []model.KeyValue{
+				model.String(EventNameKey, "exception"),
+				model.String("exception.type", "io.EOF"),
+				model.Int64("retries", 3),
+				model.Bool("fatal", false),
+				model.Float64("ratio", 0.5),
+				model.Binary("payload", []byte("raw")),
+			},
+		},
+		{
+			Timestamp: eventTime.Add(time.Millisecond),
+			Fields:    []model.KeyValue{model.String("message", "no name")},
+		},
+		{
+			Timestamp: eventTime.Add(2 * time.Millisecond),
+			Fields:    []model.KeyValue{model.String(EventNameKey, "empty")},
+		},
+	}
+}
+
+func TestSpanEventsToLogs(t *testing.T) {
+	assert.Equal(t, testLogs(), SpanEventsToLogs(testSpanEvents()))
+	assert.Nil(t, SpanEventsToLogs(ptrace.NewSpanEventSlice()))
+}
+
+func TestSpanEventsToLogsWithEventAttribute(t *testing.T) {
+	events := ptrace.NewSpanEventSlice()
+	event := events.AppendEmpty()
+	event.SetName("name")
+	event.Attributes().PutStr(EventNameKey, "attribute")
+
+	logs := SpanEventsToLogs(events)
+	require.Len(t, logs, 1)
+	assert.Equal(t, []model.KeyValue{model.String(EventNameKey, "attribute")}, logs[0].Fields)
+}
+
+func TestLogsToSpanEvents(t *testing.T) {
+	events := ptrace.NewSpanEventSlice()
+	events.AppendEmpty().SetName("existing")
+	LogsToSpanEvents(testLogs(), events)
+
+	expected := ptrace.NewSpanEventSlice()
+	expected.AppendEmpty().SetName("existing")
+	testSpanEvents().MoveAndAppendTo(expected)
+	assert.Equal(t, expected, events)
+}
+
+func TestLogsToSpanEventsKeepsNonStringEventField(t *testing.T) {
+	logs := []model.Log{{Timestamp: eventTime, Fields: []model.KeyValue{model.Int64(EventNameKey, 1)}}}
+	events := ptrace.NewSpanEventSlice()
+	LogsToSpanEvents(logs, events)
+
+	require.Equal(t, 1, events.Len())
+	assert.Empty(t, events.At(0).Name())
+	assert.Equal(t, map[string]any{EventNameKey: int64(1)}, events.At(0).Attributes().AsRaw())
+	assert.Equal(t, logs, SpanEventsToLogs(events))
+}
+
+func TestEventsRoundTrip(t *testing.T) {
+	events := ptrace.NewSpanEventSlice()
+	LogsToSpanEvents(SpanEventsToLogs(testSpanEvents()), events)
+	assert.Equal(t, testSpanEvents(), events)
+
+	logs := testLogs()
+	events = ptrace.NewSpanEventSlice()
+	LogsToSpanEvents(logs, events)
+	assert.Equal(t, logs, SpanEventsToLogs(events))
+}
+
+// TestTranslatorFidelity verifies that the conversions match those of the OTLP <-> Jaeger
+// translator used for whole traces, so that both paths produce the same spans. The translator
+// may reorder the attributes of events, which are compared as maps.
+func TestTranslatorFidelity(t *testing.T) {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	testSpanEvents().CopyTo(span.Events())
+
+	batches, err := jaegertranslator.ProtoFromTraces(traces)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Spans, 1)
+	translatedLogs := batches[0].Spans[0].Logs
+	assert.Equal(t, translatedLogs, SpanEventsToLogs(span.Events()))
+
+	translatedTraces, err := jaegertranslator.ProtoToTraces(batches)
+	require.NoError(t, err)
+	translatedEvents := translatedTraces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+	events := ptrace.NewSpanEventSlice()
+	LogsToSpanEvents(translatedLogs, events)
+	require.Equal(t, translatedEvents.Len(), events.Len())
+	for i := 0; i < events.Len(); i++ {
+		assert.Equal(t, translatedEvents.At(i).Name(), events.At(i).Name())
+		assert.Equal(t, translatedEvents.At(i).Timestamp(), events.At(i).Timestamp())
+		assert.Equal(t, translatedEvents.At(i).Attributes().AsRaw(), events.At(i).Attributes().AsRaw())
+	}
+}
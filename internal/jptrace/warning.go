@@ -0,0 +1,195 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// WarningsAttribute is the span attribute holding warnings about the span that Jaeger recorded
+// when it was stored, e.g. because parts of it were dropped. Unlike model.Span.Warnings, which
+// are only computed when traces are read, these warnings are stored along with the span.
+const WarningsAttribute = "@jaeger@warnings"
+
+// WarningSeverity tells how much a warning affects the span, e.g. so that informational
+// adjustments are shown differently than data losses.
+type WarningSeverity string
+
+const (
+	// WarningSeverityInfo is the severity of the warnings about adjustments of the span that lose
+	// no data, e.g. the repair of an invalid value.
+	WarningSeverityInfo WarningSeverity = "info"
+	// WarningSeverityWarning is the severity of the warnings about possible problems of the span.
+	// It is the severity of the plain string warnings.
+	WarningSeverityWarning WarningSeverity = "warning"
+	// WarningSeverityDataLoss is the severity of the warnings about parts of the span that were dropped.
+	WarningSeverityDataLoss WarningSeverity = "data_loss"
+)
+
+// Warning is a structured warning about a span, recorded in the WarningsAttribute as a map
+// with the keys of its JSON fields.
+type Warning struct {
+	// Code identifies the kind of warning, e.g. for the UI to link to its documentation.
+	Code string `json:"code,omitempty"`
+	// Severity tells how much the warning affects the span.
+	Severity WarningSeverity `json:"severity"`
+	// Message describes the warning for users.
+	Message string `json:"message"`
+	// Source is the component that recorded the warning, e.g. the storage backend.
+	Source string `json:"source,omitempty"`
+}
+
+const (
+	warningCodeKey     = "code"
+	warningSeverityKey = "severity"
+	warningMessageKey  = "message"
+	warningSourceKey   = "source"
+)
+
+// plainWarning returns the structured warning of a plain string warning.
+func plainWarning(message string) Warning {
+	return Warning{Severity: WarningSeverityWarning, Message: message}
+}
+
+// warningsSlice returns the slice of the WarningsAttribute of an OTLP span, creating it if needed.
+func warningsSlice(span ptrace.Span) pcommon.Slice {
+	attr, ok := span.Attributes().Get(WarningsAttribute)
+	switch {
+	case !ok:
+		return span.Attributes().PutEmptySlice(WarningsAttribute)
+	case attr.Type() == pcommon.ValueTypeSlice:
+		return attr.Slice()
+	default:
+		// a single warning, e.g. from a Jaeger tag
+		previous := attr.AsString()
+		slice := span.Attributes().PutEmptySlice(WarningsAttribute)
+		slice.AppendEmpty().SetStr(previous)
+		return slice
+	}
+}
+
+// AddWarnings appends warnings to the WarningsAttribute of an OTLP span.
+func AddWarnings(span ptrace.Span, warnings ...string) {
+	slice := warningsSlice(span)
+	for _, warning := range warnings {
+		slice.AppendEmpty().SetStr(warning)
+	}
+}
+
+// AddStructuredWarnings appends structured warnings to the WarningsAttribute of an OTLP span,
+// as maps in the same slice as the plain string warnings.
+func AddStructuredWarnings(span ptrace.Span, warnings ...Warning) {
+	slice := warningsSlice(span)
+	for _, warning := range warnings {
+		m := slice.AppendEmpty().SetEmptyMap()
+		if warning.Code != "" {
+			m.PutStr(warningCodeKey, warning.Code)
+		}
+		m.PutStr(warningSeverityKey, string(warning.Severity))
+		m.PutStr(warningMessageKey, warning.Message)
+		if warning.Source != "" {
+			m.PutStr(warningSourceKey, warning.Source)
+		}
+	}
+}
+
+// GetWarnings returns the messages of the warnings recorded in the WarningsAttribute of an OTLP span.
+func GetWarnings(span ptrace.Span) []string {
+	structured := GetStructuredWarnings(span)
+	if structured == nil {
+		return nil
+	}
+	warnings := make([]string, len(structured))
+	for i, warning := range structured {
+		warnings[i] = warning.Message
+	}
+	return warnings
+}
+
+// GetStructuredWarnings returns the warnings recorded in the WarningsAttribute of an OTLP span.
+// The plain string warnings are returned with the warning severity.
+func GetStructuredWarnings(span ptrace.Span) []Warning {
+	attr, ok := span.Attributes().Get(WarningsAttribute)
+	if !ok {
+		return nil
+	}
+	if attr.Type() != pcommon.ValueTypeSlice {
+		return []Warning{parseWarning(attr.AsString())}
+	}
+	warnings := make([]Warning, 0, attr.Slice().Len())
+	for i := 0; i < attr.Slice().Len(); i++ {
+		value := attr.Slice().At(i)
+		if value.Type() != pcommon.ValueTypeMap {
+			warnings = append(warnings, parseWarning(value.AsString()))
+			continue
+		}
+		warning := Warning{Severity: WarningSeverityWarning}
+		value.Map().Range(func(k string, v pcommon.Value) bool {
+			switch k {
+			case warningCodeKey:
+				warning.Code = v.AsString()
+			case warningSeverityKey:
+				warning.Severity = WarningSeverity(v.AsString())
+			case warningMessageKey:
+				warning.Message = v.AsString()
+			case warningSourceKey:
+				warning.Source = v.AsString()
+			}
+			return true
+		})
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// AddWarningTags records warnings as WarningsAttribute tags of a Jaeger span,
+// one tag per warning, as Jaeger tags cannot hold lists.
+func AddWarningTags(span *model.Span, warnings ...string) {
+	for _, warning := range warnings {
+		span.Tags = append(span.Tags, model.String(WarningsAttribute, warning))
+	}
+}
+
+// AddStructuredWarningTags records structured warnings as WarningsAttribute tags of a Jaeger span,
+// holding the JSON of the warnings, as Jaeger tags cannot hold maps.
+func AddStructuredWarningTags(span *model.Span, warnings ...Warning) {
+	for _, warning := range warnings {
+		// the warning has only string fields, it cannot fail to marshal
+		data, _ := json.Marshal(warning)
+		span.Tags = append(span.Tags, model.String(WarningsAttribute, string(data)))
+	}
+}
+
+// GetStructuredWarningTags returns the warnings recorded as WarningsAttribute tags of a Jaeger span.
+// The plain string warnings are returned with the warning severity.
+func GetStructuredWarningTags(span *model.Span) []Warning {
+	var warnings []Warning
+	for _, tag := range span.Tags {
+		if tag.Key == WarningsAttribute {
+			warnings = append(warnings, parseWarning(tag.AsString()))
+		}
+	}
+	return warnings
+}
+
+// parseWarning returns the structured warning of a string, which holds either the JSON
+// of a structured warning or a plain warning message.
+func parseWarning(s string) Warning {
+	if strings.HasPrefix(s, "{") {
+		var warning Warning
+		if err := json.Unmarshal([]byte(s), &warning); err == nil && warning.Message != "" {
+			if warning.Severity == "" {
+				warning.Severity = WarningSeverityWarning
+			}
+			return warning
+		}
+	}
+	return plainWarning(s)
+}
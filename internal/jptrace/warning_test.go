@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jptrace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestAddWarnings(t *testing.T) {
+	span := ptrace.NewSpan()
+	assert.Nil(t, GetWarnings(span))
+
+	AddWarnings(span, "first")
+	AddWarnings(span, "second", "third")
+	assert.Equal(t, []string{"first", "second", "third"}, GetWarnings(span))
+}
+
+func TestAddWarningsToTag(t *testing.T) {
+	// spans read from Jaeger storage hold the warnings as string attributes
+	span := ptrace.NewSpan()
+	span.Attributes().PutStr(WarningsAttribute, "stored")
+	assert.Equal(t, []string{"stored"}, GetWarnings(span))
+
+	AddWarnings(span, "added")
+	assert.Equal(t, []string{"stored", "added"}, GetWarnings(span))
+}
+
+func TestAddWarningTags(t *testing.T) {
+	span := &model.Span{Tags: []model.KeyValue{model.String("k", "v")}}
+	AddWarningTags(span, "first", "second")
+	assert.Equal(t, []model.KeyValue{
+		model.String("k", "v"),
+		model.String(WarningsAttribute, "first"),
+		model.String(WarningsAttribute, "second"),
+	}, span.Tags)
+}
+
+func TestAddStructuredWarnings(t *testing.T) {
+	span := ptrace.NewSpan()
+	assert.Nil(t, GetStructuredWarnings(span))
+
+	dataLoss := Warning{Code: "dropped", Severity: WarningSeverityDataLoss, Message: "logs dropped", Source: "storage"}
+	AddWarnings(span, "plain")
+	AddStructuredWarnings(span, dataLoss, Warning{Severity: WarningSeverityInfo, Message: "repaired"})
+	assert.Equal(t, []Warning{
+		{Severity: WarningSeverityWarning, Message: "plain"},
+		dataLoss,
+		{Severity: WarningSeverityInfo, Message: "repaired"},
+	}, GetStructuredWarnings(span))
+	assert.Equal(t, []string{"plain", "logs dropped", "repaired"}, GetWarnings(span))
+}
+
+func TestGetStructuredWarningsFromTag(t *testing.T) {
+	span := ptrace.NewSpan()
+	span.Attributes().PutStr(WarningsAttribute, `{"severity":"info","message":"stored","source":"collector"}`)
+	assert.Equal(t, []Warning{
+		{Severity: WarningSeverityInfo, Message: "stored", Source: "collector"},
+	}, GetStructuredWarnings(span))
+	assert.Equal(t, []string{"stored"}, GetWarnings(span))
+}
+
+func TestAddStructuredWarningTags(t *testing.T) {
+	span := &model.Span{Tags: []model.KeyValue{model.String("k", "v")}}
+	AddWarningTags(span, "plain", "{not json")
+	AddStructuredWarningTags(span,
+		Warning{Code: "dropped", Severity: WarningSeverityDataLoss, Message: "logs dropped", Source: "storage"},
+		Warning{Severity: WarningSeverityInfo, Message: "repaired"},
+	)
+	assert.Equal(t, []model.KeyValue{
+		model.String("k", "v"),
+		model.String(WarningsAttribute, "plain"),
+		model.String(WarningsAttribute, "{not json"),
+		model.String(WarningsAttribute, `{"code":"dropped","severity":"data_loss","message":"logs dropped","source":"storage"}`),
+		model.String(WarningsAttribute, `{"severity":"info","message":"repaired"}`),
+	}, span.Tags)
+	assert.Equal(t, []Warning{
+		{Severity: WarningSeverityWarning, Message: "plain"},
+		{Severity: WarningSeverityWarning, Message: "{not json"},
+		{Code: "dropped", Severity: WarningSeverityDataLoss, Message: "logs dropped", Source: "storage"},
+		{Severity: WarningSeverityInfo, Message: "repaired"},
+	}, GetStructuredWarningTags(span))
+}
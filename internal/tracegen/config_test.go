@@ -7,6 +7,7 @@ import (
 	"errors"
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -76,6 +77,10 @@ func Test_Flags(t *testing.T) {
 		Service:       "tracegen",
 		Services:      1,
 		TraceExporter: "otlp-http",
+		Replay: ReplayConfig{
+			Lookback:  time.Hour,
+			MaxTraces: 100,
+		},
 	}
 
 	config.Flags(fs)
@@ -0,0 +1,344 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	model2otel "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// ReplayConfig describes the replay of previously captured traces, read from files
+// or from jaeger-query, instead of the generation of new traces.
+type ReplayConfig struct {
+	// Files is an OTLP JSON (.json) or protobuf (.pb) file, or a directory of such files,
+	// e.g. written by jaeger-trace-exporter.
+	Files string
+	// QueryEndpoint is the gRPC host:port of the jaeger-query the traces are read from.
+	QueryEndpoint string
+	// Service is the service of the traces read from jaeger-query, or any service if empty.
+	Service string
+	// Lookback is how far back the traces are read from jaeger-query.
+	Lookback time.Duration
+	// MaxTraces is the maximum number of traces read from jaeger-query for each service.
+	MaxTraces int
+	// NewIDs replaces the trace and span IDs with random ones, so that a trace can be replayed
+	// several times, or next to the original trace.
+	NewIDs bool
+}
+
+// Flags registers the replay flags.
+func (c *ReplayConfig) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Files, "replay-files", "", "Replay the traces of an OTLP JSON (.json) or protobuf (.pb) file, or of a directory of such files (e.g. written by jaeger-trace-exporter), instead of generating traces")
+	fs.StringVar(&c.QueryEndpoint, "replay-query-endpoint", "", "Replay the traces read from the gRPC host:port of jaeger-query, instead of generating traces")
+	fs.StringVar(&c.Service, "replay-service", "", "The service of the traces read from jaeger-query; all services when empty")
+	fs.DurationVar(&c.Lookback, "replay-lookback", time.Hour, "How far back the traces are read from jaeger-query")
+	fs.IntVar(&c.MaxTraces, "replay-max-traces", 100, "The maximum number of traces read from jaeger-query for each service")
+	fs.BoolVar(&c.NewIDs, "replay-new-ids", false, "Replace the trace and span IDs of the replayed traces with random ones")
+}
+
+// Enabled returns whether traces are replayed instead of generated.
+func (c *ReplayConfig) Enabled() bool {
+	return c.Files != "" || c.QueryEndpoint != ""
+}
+
+// Load reads the traces to replay from the files or from jaeger-query.
+func (c *ReplayConfig) Load(ctx context.Context) ([]ptrace.Traces, error) {
+	if c.Files != "" {
+		return LoadTraceFiles(c.Files)
+	}
+	conn, err := grpc.NewClient(c.QueryEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to jaeger-query: %w", err)
+	}
+	defer conn.Close()
+	end := time.Now()
+	return QueryTraces(ctx, api_v2.NewQueryServiceClient(conn), c.Service, end.Add(-c.Lookback), end, c.MaxTraces)
+}
+
+// LoadTraceFiles reads the traces of an OTLP JSON or protobuf file, or of the files of a directory
+// whose extension is .json or .pb. Other files, e.g. partially written .tmp files, are skipped.
+func LoadTraceFiles(path string) ([]ptrace.Traces, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		unmarshaler := traceFileUnmarshaler(path)
+		if unmarshaler == nil {
+			return nil, fmt.Errorf("unsupported trace file %s, the extension must be .json or .pb", path)
+		}
+		td, err := loadTraceFile(path, unmarshaler)
+		if err != nil {
+			return nil, err
+		}
+		return []ptrace.Traces{td}, nil
+	}
+	var batches []ptrace.Traces
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		unmarshaler := traceFileUnmarshaler(file)
+		if unmarshaler == nil {
+			return nil
+		}
+		td, err := loadTraceFile(file, unmarshaler)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, td)
+		return nil
+	})
+	return batches, err
+}
+
+func traceFileUnmarshaler(path string) ptrace.Unmarshaler {
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		return &ptrace.JSONUnmarshaler{}
+	case strings.HasSuffix(path, ".pb"):
+		return &ptrace.ProtoUnmarshaler{}
+	default:
+		return nil
+	}
+}
+
+func loadTraceFile(path string, unmarshaler ptrace.Unmarshaler) (ptrace.Traces, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	td, err := unmarshaler.UnmarshalTraces(data)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to read traces of %s: %w", path, err)
+	}
+	return td, nil
+}
+
+// QueryTraces reads from jaeger-query the traces of a service, or of every service if empty,
+// that started between start and end, returning the traces of each service as a batch.
+// A trace found for several services is returned once.
+func QueryTraces(
+	ctx context.Context,
+	client api_v2.QueryServiceClient,
+	service string,
+	start, end time.Time,
+	maxTraces int,
+) ([]ptrace.Traces, error) {
+	services := []string{service}
+	if service == "" {
+		res, err := client.GetServices(ctx, &api_v2.GetServicesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services: %w", err)
+		}
+		services = res.Services
+	}
+	var batches []ptrace.Traces
+	found := make(map[model.TraceID]struct{})
+	for _, service := range services {
+		serviceSpans, err := findSpans(ctx, client, service, start, end, maxTraces)
+		if err != nil {
+			return nil, err
+		}
+		var spans []*model.Span
+		for _, span := range serviceSpans {
+			if _, ok := found[span.TraceID]; !ok {
+				spans = append(spans, span)
+			}
+		}
+		for _, span := range spans {
+			found[span.TraceID] = struct{}{}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		td, err := model2otel.ProtoToTraces([]*model.Batch{{Spans: spans}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert traces to OTLP: %w", err)
+		}
+		batches = append(batches, td)
+	}
+	return batches, nil
+}
+
+func findSpans(ctx context.Context, client api_v2.QueryServiceClient, service string, start, end time.Time, maxTraces int) ([]*model.Span, error) {
+	stream, err := client.FindTraces(ctx, &api_v2.FindTracesRequest{
+		Query: &api_v2.TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: start,
+			StartTimeMax: end,
+			SearchDepth:  int32(maxTraces),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find traces of service %s: %w", service, err)
+	}
+	var spans []*model.Span
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return spans, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find traces of service %s: %w", service, err)
+		}
+		for i := range chunk.Spans {
+			spans = append(spans, &chunk.Spans[i])
+		}
+	}
+}
+
+// forEachSpan calls fn with each span of the batches.
+func forEachSpan(batches []ptrace.Traces, fn func(span ptrace.Span)) {
+	for _, td := range batches {
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			scopeSpans := td.ResourceSpans().At(i).ScopeSpans()
+			for j := 0; j < scopeSpans.Len(); j++ {
+				spans := scopeSpans.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					fn(spans.At(k))
+				}
+			}
+		}
+	}
+}
+
+// ShiftTimestamps moves the spans of the batches in time, so that the latest span ends at now.
+// The offsets between the spans, including the spans of different traces, are preserved.
+func ShiftTimestamps(batches []ptrace.Traces, now time.Time) {
+	var latest pcommon.Timestamp
+	forEachSpan(batches, func(span ptrace.Span) {
+		latest = max(latest, span.EndTimestamp(), span.StartTimestamp())
+	})
+	if latest == 0 {
+		return
+	}
+	shift := now.Sub(latest.AsTime())
+	shiftTimestamp := func(ts pcommon.Timestamp) pcommon.Timestamp {
+		if ts == 0 {
+			return 0
+		}
+		return pcommon.NewTimestampFromTime(ts.AsTime().Add(shift))
+	}
+	forEachSpan(batches, func(span ptrace.Span) {
+		span.SetStartTimestamp(shiftTimestamp(span.StartTimestamp()))
+		span.SetEndTimestamp(shiftTimestamp(span.EndTimestamp()))
+		for i := 0; i < span.Events().Len(); i++ {
+			event := span.Events().At(i)
+			event.SetTimestamp(shiftTimestamp(event.Timestamp()))
+		}
+	})
+}
+
+type spanKey struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+}
+
+// RegenerateIDs replaces the trace and span IDs of the batches with random ones. The parents
+// and the links of the spans refer to the new IDs, unless they refer to spans of other traces.
+func RegenerateIDs(batches []ptrace.Traces) {
+	traceIDs := make(map[pcommon.TraceID]pcommon.TraceID)
+	spanIDs := make(map[spanKey]pcommon.SpanID)
+	forEachSpan(batches, func(span ptrace.Span) {
+		if _, ok := traceIDs[span.TraceID()]; !ok {
+			traceIDs[span.TraceID()] = randomTraceID()
+		}
+		spanIDs[spanKey{span.TraceID(), span.SpanID()}] = randomSpanID()
+	})
+	remap := func(traceID pcommon.TraceID, spanID pcommon.SpanID) (pcommon.TraceID, pcommon.SpanID) {
+		newSpanID, ok := spanIDs[spanKey{traceID, spanID}]
+		if !ok {
+			newSpanID = spanID
+		}
+		if newTraceID, ok := traceIDs[traceID]; ok {
+			return newTraceID, newSpanID
+		}
+		return traceID, newSpanID
+	}
+	forEachSpan(batches, func(span ptrace.Span) {
+		traceID := span.TraceID()
+		if !span.ParentSpanID().IsEmpty() {
+			_, parentSpanID := remap(traceID, span.ParentSpanID())
+			span.SetParentSpanID(parentSpanID)
+		}
+		for i := 0; i < span.Links().Len(); i++ {
+			link := span.Links().At(i)
+			linkTraceID, linkSpanID := remap(link.TraceID(), link.SpanID())
+			link.SetTraceID(linkTraceID)
+			link.SetSpanID(linkSpanID)
+		}
+		newTraceID, newSpanID := remap(traceID, span.SpanID())
+		span.SetTraceID(newTraceID)
+		span.SetSpanID(newSpanID)
+	})
+}
+
+func randomTraceID() pcommon.TraceID {
+	var id pcommon.TraceID
+	for id.IsEmpty() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func randomSpanID() pcommon.SpanID {
+	var id pcommon.SpanID
+	for id.IsEmpty() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// Replay submits the batches through the OTLP client, after shifting their timestamps
+// to now and, if configured, replacing their IDs.
+func Replay(ctx context.Context, c *ReplayConfig, batches []ptrace.Traces, client otlptrace.Client, logger *zap.Logger) error {
+	ShiftTimestamps(batches, time.Now())
+	if c.NewIDs {
+		RegenerateIDs(batches)
+	}
+	if err := client.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start the OTLP client: %w", err)
+	}
+	var spans int
+	for _, td := range batches {
+		data, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+		if err != nil {
+			return fmt.Errorf("failed to marshal traces: %w", err)
+		}
+		var request coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(data, &request); err != nil {
+			return fmt.Errorf("failed to marshal traces: %w", err)
+		}
+		if err := client.UploadTraces(ctx, request.ResourceSpans); err != nil {
+			return fmt.Errorf("failed to submit traces: %w", err)
+		}
+		spans += td.SpanCount()
+	}
+	logger.Info("Replayed traces", zap.Int("batches", len(batches)), zap.Int("spans", spans))
+	return client.Stop(ctx)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+var capturedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newCapturedTraces returns a trace with a root span and a child span linked to another trace.
+func newCapturedTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	root := spans.AppendEmpty()
+	root.SetTraceID(pcommon.TraceID{1})
+	root.SetSpanID(pcommon.SpanID{1})
+	root.SetName("root")
+	root.SetStartTimestamp(pcommon.NewTimestampFromTime(capturedAt))
+	root.SetEndTimestamp(pcommon.NewTimestampFromTime(capturedAt.Add(time.Second)))
+	child := spans.AppendEmpty()
+	child.SetTraceID(pcommon.TraceID{1})
+	child.SetSpanID(pcommon.SpanID{2})
+	child.SetParentSpanID(pcommon.SpanID{1})
+	child.SetName("child")
+	child.SetStartTimestamp(pcommon.NewTimestampFromTime(capturedAt.Add(100 * time.Millisecond)))
+	child.SetEndTimestamp(pcommon.NewTimestampFromTime(capturedAt.Add(2 * time.Second)))
+	event := child.Events().AppendEmpty()
+	event.SetTimestamp(pcommon.NewTimestampFromTime(capturedAt.Add(time.Second)))
+	link := child.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID{9})
+	link.SetSpanID(pcommon.SpanID{9})
+	return td
+}
+
+func spanAt(td ptrace.Traces, i int) ptrace.Span {
+	return td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(i)
+}
+
+func TestLoadTraceFiles(t *testing.T) {
+	dir := t.TempDir()
+	jsonData, err := (&ptrace.JSONMarshaler{}).MarshalTraces(newCapturedTraces())
+	require.NoError(t, err)
+	protoData, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(newCapturedTraces())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2024-05-01", "frontend"), 0o755))
+	files := map[string][]byte{
+		"2024-05-01/frontend/traces-20240501T120000Z.otlp.json":     jsonData,
+		"2024-05-01/frontend/traces-20240501T130000Z.otlp.pb":       protoData,
+		"2024-05-01/frontend/traces-20240501T140000Z.otlp.json.tmp": []byte("partial"),
+		"README.md": []byte("captured traces"),
+	}
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	batches, err := LoadTraceFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, newCapturedTraces(), batches[0])
+	assert.Equal(t, newCapturedTraces(), batches[1])
+
+	batches, err = LoadTraceFiles(filepath.Join(dir, "2024-05-01/frontend/traces-20240501T130000Z.otlp.pb"))
+	require.NoError(t, err)
+	assert.Len(t, batches, 1)
+
+	_, err = LoadTraceFiles(filepath.Join(dir, "README.md"))
+	require.ErrorContains(t, err, "the extension must be .json or .pb")
+	_, err = LoadTraceFiles(filepath.Join(dir, "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0o600))
+	_, err = LoadTraceFiles(dir)
+	require.ErrorContains(t, err, "failed to read traces of")
+}
+
+func TestShiftTimestamps(t *testing.T) {
+	td := newCapturedTraces()
+	now := time.Now()
+	ShiftTimestamps([]ptrace.Traces{td, ptrace.NewTraces()}, now)
+
+	root, child := spanAt(td, 0), spanAt(td, 1)
+	assert.Equal(t, now.UnixNano(), child.EndTimestamp().AsTime().UnixNano(), "the latest span ends at now")
+	assert.Equal(t, now.Add(-2*time.Second).UnixNano(), root.StartTimestamp().AsTime().UnixNano())
+	assert.Equal(t, time.Second, root.EndTimestamp().AsTime().Sub(root.StartTimestamp().AsTime()))
+	assert.Equal(t, 100*time.Millisecond, child.StartTimestamp().AsTime().Sub(root.StartTimestamp().AsTime()))
+	assert.Equal(t, now.Add(-time.Second).UnixNano(), child.Events().At(0).Timestamp().AsTime().UnixNano())
+
+	empty := ptrace.NewTraces()
+	ShiftTimestamps([]ptrace.Traces{empty}, now)
+	assert.Equal(t, ptrace.NewTraces(), empty)
+}
+
+func TestRegenerateIDs(t *testing.T) {
+	td := newCapturedTraces()
+	RegenerateIDs([]ptrace.Traces{td})
+
+	root, child := spanAt(td, 0), spanAt(td, 1)
+	assert.NotEqual(t, pcommon.TraceID{1}, root.TraceID())
+	assert.Equal(t, root.TraceID(), child.TraceID())
+	assert.NotEqual(t, pcommon.SpanID{1}, root.SpanID())
+	assert.NotEqual(t, pcommon.SpanID{2}, child.SpanID())
+	assert.NotEqual(t, root.SpanID(), child.SpanID())
+	assert.True(t, root.ParentSpanID().IsEmpty())
+	assert.Equal(t, root.SpanID(), child.ParentSpanID())
+	assert.Equal(t, pcommon.TraceID{9}, child.Links().At(0).TraceID(), "links to other traces are kept")
+	assert.Equal(t, pcommon.SpanID{9}, child.Links().At(0).SpanID())
+}
+
+type fakeOTLPClient struct {
+	started, stopped bool
+	uploaded         []*tracepb.ResourceSpans
+	err              error
+}
+
+func (c *fakeOTLPClient) Start(context.Context) error {
+	c.started = true
+	return nil
+}
+
+func (c *fakeOTLPClient) Stop(context.Context) error {
+	c.stopped = true
+	return nil
+}
+
+func (c *fakeOTLPClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	c.uploaded = append(c.uploaded, spans...)
+	return c.err
+}
+
+func TestReplay(t *testing.T) {
+	client := &fakeOTLPClient{}
+	cfg := &ReplayConfig{NewIDs: true}
+	before := time.Now()
+	require.NoError(t, Replay(context.Background(), cfg, []ptrace.Traces{newCapturedTraces()}, client, zap.NewNop()))
+	assert.True(t, client.started)
+	assert.True(t, client.stopped)
+
+	require.Len(t, client.uploaded, 1)
+	spans := client.uploaded[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[1].Name)
+	assert.NotEqual(t, pcommon.TraceID{1}, pcommon.TraceID(spans[1].TraceId))
+	assert.GreaterOrEqual(t, spans[1].EndTimeUnixNano, uint64(before.UnixNano()))
+
+	client.err = errors.New("unavailable")
+	err := Replay(context.Background(), cfg, []ptrace.Traces{newCapturedTraces()}, client, zap.NewNop())
+	require.ErrorContains(t, err, "failed to submit traces: unavailable")
+}
+
+// fakeQueryService returns every span of the service's traces.
+type fakeQueryService struct {
+	api_v2.UnimplementedQueryServiceServer
+	spans   []model.Span
+	queries []*api_v2.TraceQueryParameters
+}
+
+func (*fakeQueryService) GetServices(context.Context, *api_v2.GetServicesRequest) (*api_v2.GetServicesResponse, error) {
+	return &api_v2.GetServicesResponse{Services: []string{"frontend", "backend"}}, nil
+}
+
+func (s *fakeQueryService) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.QueryService_FindTracesServer) error {
+	s.queries = append(s.queries, r.Query)
+	matches := make(map[model.TraceID]bool)
+	for _, span := range s.spans {
+		if span.Process.ServiceName == r.Query.ServiceName {
+			matches[span.TraceID] = true
+		}
+	}
+	var spans []model.Span
+	for _, span := range s.spans {
+		if matches[span.TraceID] {
+			spans = append(spans, span)
+		}
+	}
+	return stream.Send(&api_v2.SpansResponseChunk{Spans: spans})
+}
+
+func startQueryService(t *testing.T, service *fakeQueryService) api_v2.QueryServiceClient {
+	server := grpc.NewServer()
+	api_v2.RegisterQueryServiceServer(server, service)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return api_v2.NewQueryServiceClient(conn)
+}
+
+func TestQueryTraces(t *testing.T) {
+	testSpan := func(traceID, spanID uint64, service string) model.Span {
+		return model.Span{
+			TraceID:       model.NewTraceID(0, traceID),
+			SpanID:        model.NewSpanID(spanID),
+			OperationName: "op",
+			StartTime:     capturedAt,
+			Duration:      time.Millisecond,
+			Process:       model.NewProcess(service, nil),
+		}
+	}
+	service := &fakeQueryService{spans: []model.Span{
+		testSpan(1, 1, "frontend"),
+		testSpan(1, 2, "backend"),
+		testSpan(2, 3, "backend"),
+	}}
+	client := startQueryService(t, service)
+
+	batches, err := QueryTraces(context.Background(), client, "", capturedAt.Add(-time.Hour), capturedAt, 10)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches[0].SpanCount(), "the trace of frontend")
+	assert.Equal(t, 1, batches[1].SpanCount(), "the trace of backend not found for frontend")
+	require.Len(t, service.queries, 2)
+	assert.Equal(t, int32(10), service.queries[0].SearchDepth)
+	assert.Equal(t, capturedAt.Add(-time.Hour), service.queries[0].StartTimeMin)
+
+	batches, err = QueryTraces(context.Background(), client, "payments", capturedAt.Add(-time.Hour), capturedAt, 10)
+	require.NoError(t, err)
+	assert.Empty(t, batches)
+}
+
+func TestReplayConfigLoad(t *testing.T) {
+	cfg := &ReplayConfig{}
+	assert.False(t, cfg.Enabled())
+
+	cfg.Files = filepath.Join(t.TempDir(), "missing")
+	assert.True(t, cfg.Enabled())
+	_, err := cfg.Load(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	cfg = &ReplayConfig{QueryEndpoint: "localhost:0", Service: "frontend", Lookback: time.Hour}
+	_, err = cfg.Load(context.Background())
+	require.ErrorContains(t, err, "failed to find traces of service frontend")
+}
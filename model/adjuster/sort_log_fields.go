@@ -16,6 +16,7 @@
 package adjuster
 
 import (
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/model"
 )
 
@@ -35,7 +36,7 @@ func SortLogFields() Adjuster {
 				// first move "event" field into the first position
 				offset := 0
 				for i, field := range log.Fields {
-					if field.Key == "event" && field.VType == model.StringType {
+					if field.Key == jptrace.EventNameKey && field.VType == model.StringType {
 						if i > 0 {
 							log.Fields[0], log.Fields[i] = log.Fields[i], log.Fields[0]
 						}
@@ -25,6 +25,7 @@ import (
 
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
@@ -62,8 +63,7 @@ var (
 	// DefaultLogFieldKey is the log field key which translates directly into Zipkin's Annotation.Value,
 	// provided it's the only field in the log.
 	// In all other cases the fields are encoded into Annotation.Value as JSON string.
-	// TODO move to domain model
-	DefaultLogFieldKey = "event"
+	DefaultLogFieldKey = jptrace.EventNameKey
 
 	// IPTagName is the Jaeger tag name for an IPv4/IPv6 IP address.
 	// TODO move to domain model
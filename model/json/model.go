@@ -54,6 +54,16 @@ type Trace struct {
 	Spans     []Span                `json:"spans"`
 	Processes map[ProcessID]Process `json:"processes"`
 	Warnings  []string              `json:"warnings"`
+	// Completeness is set when the trace is returned alone, to hint whether it is complete
+	Completeness *TraceCompleteness `json:"completeness,omitempty"`
+}
+
+// TraceCompleteness hints whether all the spans of a trace are stored, so that clients can
+// refresh a trace still being received instead of showing it partially.
+type TraceCompleteness struct {
+	Complete           bool `json:"complete"`
+	StillReceiving     bool `json:"stillReceiving"`
+	MissingParentSpans int  `json:"missingParentSpans"`
 }
 
 // Span is a span denoting a piece of work in some infrastructure
@@ -114,4 +124,7 @@ type DependencyLink struct {
 type Operation struct {
 	Name     string `json:"name"`
 	SpanKind string `json:"spanKind"`
+	// LastSeen and SpanCount are set when the operation stats are asked for and known by the storage.
+	LastSeen  uint64 `json:"lastSeen,omitempty"` // microseconds since Unix epoch
+	SpanCount int64  `json:"spanCount,omitempty"`
 }
@@ -19,6 +19,15 @@ import (
 	"io"
 )
 
+const (
+	// ServiceNamespaceKey is the process tag of the OpenTelemetry service.namespace resource attribute,
+	// which distinguishes the services with the same name, e.g. deployed by different teams.
+	ServiceNamespaceKey = "service.namespace"
+	// ServiceInstanceIDKey is the process tag of the OpenTelemetry service.instance.id resource attribute,
+	// which identifies an instance of a service.
+	ServiceInstanceIDKey = "service.instance.id"
+)
+
 // NewProcess creates a new Process for given serviceName and tags.
 // The tags are sorted in place and kept in the same array/slice,
 // in order to store the Process in a canonical form that is relied
@@ -44,3 +53,23 @@ func (p *Process) Hash(w io.Writer) (err error) {
 	}
 	return KeyValues(p.Tags).Hash(w)
 }
+
+// ServiceNamespace returns the service.namespace tag of the process, or an empty string if it has none.
+func (p *Process) ServiceNamespace() string {
+	return p.stringTag(ServiceNamespaceKey)
+}
+
+// ServiceInstanceID returns the service.instance.id tag of the process, or an empty string if it has none.
+func (p *Process) ServiceInstanceID() string {
+	return p.stringTag(ServiceInstanceIDKey)
+}
+
+func (p *Process) stringTag(key string) string {
+	if p == nil {
+		return ""
+	}
+	if kv, ok := KeyValues(p.Tags).FindByKey(key); ok {
+		return kv.AsString()
+	}
+	return ""
+}
@@ -103,3 +103,17 @@ func TestProcessHashError(t *testing.T) {
 	}
 	assert.Equal(t, someErr, p1.Hash(w))
 }
+
+func TestProcessServiceAttributes(t *testing.T) {
+	p := model.NewProcess("checkout", []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, "shop"),
+		model.Int64(model.ServiceInstanceIDKey, 42),
+	})
+	assert.Equal(t, "shop", p.ServiceNamespace())
+	assert.Equal(t, "42", p.ServiceInstanceID())
+
+	p = model.NewProcess("checkout", nil)
+	assert.Empty(t, p.ServiceNamespace())
+	var nilProcess *model.Process
+	assert.Empty(t, nilProcess.ServiceInstanceID())
+}
@@ -17,8 +17,11 @@ package model
 
 import (
 	"encoding/gob"
+	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -43,6 +46,11 @@ const (
 	keySamplerType  = "sampler.type"
 	keySpanKind     = "span.kind"
 	keySamplerParam = "sampler.param"
+
+	// RetentionTagKey is the span or process tag with which applications request that a trace is kept
+	// for longer than the default retention of the storage, e.g. `jaeger.retention=30d`.
+	// All spans of the trace must carry the tag for the whole trace to be retained.
+	RetentionTagKey = "jaeger.retention"
 )
 
 // Flags is a bit map of flags for a span
@@ -194,6 +202,46 @@ func (s *Span) GetSamplerParams(logger *zap.Logger) (SamplerType, float64) {
 	return samplerType, samplerParam
 }
 
+// GetRetention returns the retention requested by the `jaeger.retention` tag of the span,
+// or of its process, and whether a valid one was found.
+func (s *Span) GetRetention() (time.Duration, bool) {
+	tag, ok := KeyValues(s.Tags).FindByKey(RetentionTagKey)
+	if !ok && s.Process != nil {
+		tag, ok = KeyValues(s.Process.Tags).FindByKey(RetentionTagKey)
+	}
+	if !ok {
+		return 0, false
+	}
+	retention, err := ParseRetention(tag.AsString())
+	if err != nil {
+		return 0, false
+	}
+	return retention, true
+}
+
+// ParseRetention parses a retention given as a number of days such as `30d`,
+// or as a duration accepted by time.ParseDuration such as `720h`.
+func ParseRetention(value string) (time.Duration, error) {
+	var retention time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q: %w", value, err)
+		}
+		retention = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q: %w", value, err)
+		}
+		retention = d
+	}
+	if retention <= 0 {
+		return 0, fmt.Errorf("invalid retention %q: must be positive", value)
+	}
+	return retention, nil
+}
+
 // ------- Flags -------
 
 // SetSampled sets the Flags as sampled
@@ -509,3 +509,64 @@ func TestGetSamplerParams(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRetention(t *testing.T) {
+	tests := []struct {
+		name              string
+		span              model.Span
+		expectedRetention time.Duration
+		expectedFound     bool
+	}{
+		{
+			name:              "days on span",
+			span:              model.Span{Tags: model.KeyValues{model.String(model.RetentionTagKey, "30d")}},
+			expectedRetention: 30 * 24 * time.Hour,
+			expectedFound:     true,
+		},
+		{
+			name:              "duration on process",
+			span:              model.Span{Process: &model.Process{Tags: model.KeyValues{model.String(model.RetentionTagKey, "36h")}}},
+			expectedRetention: 36 * time.Hour,
+			expectedFound:     true,
+		},
+		{
+			name: "span overrides process",
+			span: model.Span{
+				Tags:    model.KeyValues{model.String(model.RetentionTagKey, "2d")},
+				Process: &model.Process{Tags: model.KeyValues{model.String(model.RetentionTagKey, "36h")}},
+			},
+			expectedRetention: 48 * time.Hour,
+			expectedFound:     true,
+		},
+		{
+			name: "invalid",
+			span: model.Span{Tags: model.KeyValues{model.String(model.RetentionTagKey, "forever")}},
+		},
+		{
+			name: "negative",
+			span: model.Span{Tags: model.KeyValues{model.String(model.RetentionTagKey, "-1d")}},
+		},
+		{
+			name: "absent",
+			span: model.Span{Process: &model.Process{}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			retention, found := test.span.GetRetention()
+			assert.Equal(t, test.expectedFound, found)
+			assert.Equal(t, test.expectedRetention, retention)
+		})
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	retention, err := model.ParseRetention("7d")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, retention)
+
+	_, err = model.ParseRetention("xd")
+	require.ErrorContains(t, err, `invalid retention "xd"`)
+	_, err = model.ParseRetention("0s")
+	require.ErrorContains(t, err, "must be positive")
+}
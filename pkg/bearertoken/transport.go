@@ -28,6 +28,10 @@ type RoundTripper struct {
 	// StaticToken is the pre-configured bearer token. Optional.
 	StaticToken string
 
+	// TokenFn returns the pre-configured bearer token, e.g. read from a file that is rotated.
+	// Optional, takes precedence over StaticToken.
+	TokenFn func() string
+
 	// OverrideFromCtx enables reading bearer token from Context.
 	OverrideFromCtx bool
 }
@@ -39,6 +43,9 @@ func (tr RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 		return nil, errors.New("no http.RoundTripper provided")
 	}
 	token := tr.StaticToken
+	if tr.TokenFn != nil {
+		token = tr.TokenFn()
+	}
 	if tr.OverrideFromCtx {
 		headerToken, _ := GetBearerToken(r.Context())
 		if headerToken != "" {
@@ -34,6 +34,7 @@ func TestRoundTripper(t *testing.T) {
 	for _, tc := range []struct {
 		name             string
 		staticToken      string
+		tokenFn          func() string
 		overrideFromCtx  bool
 		wrappedTransport http.RoundTripper
 		requestContext   context.Context
@@ -80,6 +81,16 @@ func TestRoundTripper(t *testing.T) {
 			}),
 			requestContext: ContextWithBearerToken(context.Background(), "tokenFromContext"),
 		},
+		{
+			name:        "Token function provided should take precedence over the static token",
+			staticToken: "initToken",
+			tokenFn:     func() string { return "rotatedToken" },
+			wrappedTransport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, "Bearer rotatedToken", r.Header.Get("Authorization"))
+				return &http.Response{}, nil
+			}),
+			requestContext: context.Background(),
+		},
 		{
 			name:           "Nil roundTripper provided should return an error",
 			requestContext: context.Background(),
@@ -96,6 +107,7 @@ func TestRoundTripper(t *testing.T) {
 				Transport:       tc.wrappedTransport,
 				OverrideFromCtx: tc.overrideFromCtx,
 				StaticToken:     tc.staticToken,
+				TokenFn:         tc.tokenFn,
 			}
 			resp, err := tr.RoundTrip(req)
 
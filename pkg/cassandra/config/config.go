@@ -26,6 +26,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	gocqlw "github.com/jaegertracing/jaeger/pkg/cassandra/gocql"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
 
 // Configuration describes the configuration properties needed to connect to a Cassandra cluster
@@ -41,6 +42,9 @@ type Configuration struct {
 	MaxRetryAttempts     int            `mapstructure:"max_retry_attempts"`
 	ProtoVersion         int            `mapstructure:"proto_version"`
 	Consistency          string         `mapstructure:"consistency"`
+	ReadConsistency      string         `mapstructure:"read_consistency"`
+	WriteConsistency     string         `mapstructure:"write_consistency"`
+	DowngradeConsistency []string       `mapstructure:"downgrade_consistency"`
 	DisableCompression   bool           `mapstructure:"disable_compression"`
 	Port                 int            `mapstructure:"port"`
 	Authenticator        Authenticator  `mapstructure:",squash"`
@@ -103,12 +107,16 @@ func (c *Configuration) ApplyDefaults(source *Configuration) {
 
 // SessionBuilder creates new cassandra.Session
 type SessionBuilder interface {
-	NewSession(logger *zap.Logger) (cassandra.Session, error)
+	NewSession(logger *zap.Logger, metricsFactory metrics.Factory) (cassandra.Session, error)
 }
 
 // NewSession creates a new Cassandra session
-func (c *Configuration) NewSession(logger *zap.Logger) (cassandra.Session, error) {
-	cluster, err := c.NewCluster(logger)
+func (c *Configuration) NewSession(logger *zap.Logger, metricsFactory metrics.Factory) (cassandra.Session, error) {
+	cluster, err := c.NewCluster(logger, metricsFactory)
+	if err != nil {
+		return nil, err
+	}
+	read, write, err := c.readWriteConsistency(cluster.Consistency)
 	if err != nil {
 		return nil, err
 	}
@@ -116,11 +124,30 @@ func (c *Configuration) NewSession(logger *zap.Logger) (cassandra.Session, error
 	if err != nil {
 		return nil, err
 	}
-	return gocqlw.WrapCQLSession(session), nil
+	if read == cluster.Consistency && write == cluster.Consistency {
+		return gocqlw.WrapCQLSession(session), nil
+	}
+	return &consistencySession{
+		Session: gocqlw.WrapCQLSession(session),
+		read:    cassandra.Consistency(read),
+		write:   cassandra.Consistency(write),
+	}, nil
+}
+
+// readWriteConsistency returns the consistency levels of the reads and the writes,
+// which default to the consistency level of the cluster.
+func (c *Configuration) readWriteConsistency(defaultLevel gocql.Consistency) (read, write gocql.Consistency, err error) {
+	if read, err = parseConsistency(c.ReadConsistency, defaultLevel); err != nil {
+		return 0, 0, err
+	}
+	if write, err = parseConsistency(c.WriteConsistency, defaultLevel); err != nil {
+		return 0, 0, err
+	}
+	return read, write, nil
 }
 
 // NewCluster creates a new gocql cluster from the configuration
-func (c *Configuration) NewCluster(logger *zap.Logger) (*gocql.ClusterConfig, error) {
+func (c *Configuration) NewCluster(logger *zap.Logger, metricsFactory metrics.Factory) (*gocql.ClusterConfig, error) {
 	cluster := gocql.NewCluster(c.Servers...)
 	cluster.Keyspace = c.Keyspace
 	cluster.NumConns = c.ConnectionsPerHost
@@ -142,10 +169,20 @@ func (c *Configuration) NewCluster(logger *zap.Logger) (*gocql.ClusterConfig, er
 		cluster.Compressor = gocql.SnappyCompressor{}
 	}
 
-	if c.Consistency == "" {
-		cluster.Consistency = gocql.LocalOne
-	} else {
-		cluster.Consistency = gocql.ParseConsistency(c.Consistency)
+	consistency, err := parseConsistency(c.Consistency, gocql.LocalOne)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Consistency = consistency
+	if len(c.DowngradeConsistency) > 0 {
+		levels := make([]gocql.Consistency, len(c.DowngradeConsistency))
+		for i, name := range c.DowngradeConsistency {
+			if levels[i], err = parseConsistency(name, gocql.LocalOne); err != nil {
+				return nil, err
+			}
+		}
+		// the downgrades replace the retries with the same consistency level
+		cluster.RetryPolicy = newDowngradingRetryPolicy(levels, metricsFactory)
 	}
 
 	fallbackHostSelectionPolicy := gocql.RoundRobinHostPolicy()
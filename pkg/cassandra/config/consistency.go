@@ -0,0 +1,96 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gocql/gocql"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// parseConsistency parses a consistency level name, returning defaultLevel for an empty name.
+func parseConsistency(name string, defaultLevel gocql.Consistency) (gocql.Consistency, error) {
+	if name == "" {
+		return defaultLevel, nil
+	}
+	level, err := gocql.ParseConsistencyWrapper(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Cassandra consistency level %q", name)
+	}
+	return level, nil
+}
+
+// consistencySession is a cassandra.Session that runs the SELECT statements with the read
+// consistency level and the other statements with the write consistency level.
+type consistencySession struct {
+	cassandra.Session
+	read  cassandra.Consistency
+	write cassandra.Consistency
+}
+
+// Query implements cassandra.Session.
+func (s *consistencySession) Query(stmt string, values ...any) cassandra.Query {
+	level := s.write
+	if isRead(stmt) {
+		level = s.read
+	}
+	return s.Session.Query(stmt, values...).Consistency(level)
+}
+
+func isRead(stmt string) bool {
+	const selectKeyword = "SELECT"
+	stmt = strings.TrimSpace(stmt)
+	return len(stmt) >= len(selectKeyword) && strings.EqualFold(stmt[:len(selectKeyword)], selectKeyword)
+}
+
+// downgradingRetryPolicy retries the queries with the next of the downgrade consistency levels
+// when not enough replicas are available, and counts the downgrades by consistency levels.
+type downgradingRetryPolicy struct {
+	gocql.DowngradingConsistencyRetryPolicy
+	metricsFactory metrics.Factory
+
+	mu         sync.Mutex
+	downgrades map[[2]gocql.Consistency]metrics.Counter
+}
+
+func newDowngradingRetryPolicy(levels []gocql.Consistency, metricsFactory metrics.Factory) *downgradingRetryPolicy {
+	return &downgradingRetryPolicy{
+		DowngradingConsistencyRetryPolicy: gocql.DowngradingConsistencyRetryPolicy{ConsistencyLevelsToTry: levels},
+		metricsFactory:                    metricsFactory,
+		downgrades:                        make(map[[2]gocql.Consistency]metrics.Counter),
+	}
+}
+
+// Attempt implements gocql.RetryPolicy.
+func (p *downgradingRetryPolicy) Attempt(q gocql.RetryableQuery) bool {
+	from := q.GetConsistency()
+	if !p.DowngradingConsistencyRetryPolicy.Attempt(q) {
+		return false
+	}
+	if to := q.GetConsistency(); to != from {
+		p.downgradeCounter(from, to).Inc(1)
+	}
+	return true
+}
+
+func (p *downgradingRetryPolicy) downgradeCounter(from, to gocql.Consistency) metrics.Counter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := [2]gocql.Consistency{from, to}
+	counter, ok := p.downgrades[key]
+	if !ok {
+		counter = p.metricsFactory.Counter(metrics.Options{
+			Name: "consistency_downgrades",
+			Tags: map[string]string{"from": from.String(), "to": to.String()},
+			Help: "The number of queries retried with a lower consistency level",
+		})
+		p.downgrades[key] = counter
+	}
+	return counter
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+func TestParseConsistency(t *testing.T) {
+	level, err := parseConsistency("", gocql.LocalOne)
+	require.NoError(t, err)
+	assert.Equal(t, gocql.LocalOne, level)
+
+	level, err = parseConsistency("local_quorum", gocql.LocalOne)
+	require.NoError(t, err)
+	assert.Equal(t, gocql.LocalQuorum, level)
+
+	_, err = parseConsistency("MOST", gocql.LocalOne)
+	require.EqualError(t, err, `invalid Cassandra consistency level "MOST"`)
+}
+
+func TestConsistencySession(t *testing.T) {
+	testCases := []struct {
+		stmt     string
+		expected cassandra.Consistency
+	}{
+		{stmt: "\n\t\tSELECT trace_id FROM traces", expected: cassandra.LocalQuorum},
+		{stmt: "select trace_id from traces", expected: cassandra.LocalQuorum},
+		{stmt: "INSERT INTO traces(trace_id) VALUES (?)", expected: cassandra.One},
+		{stmt: "TRUNCATE traces", expected: cassandra.One},
+		{stmt: "SEL", expected: cassandra.One},
+	}
+	for _, test := range testCases {
+		t.Run(test.stmt, func(t *testing.T) {
+			query := &mocks.Query{}
+			query.On("Consistency", test.expected).Return(query)
+			session := &mocks.Session{}
+			session.On("Query", test.stmt, mock.Anything).Return(query)
+			s := &consistencySession{Session: session, read: cassandra.LocalQuorum, write: cassandra.One}
+			assert.Equal(t, query, s.Query(test.stmt, 1))
+			query.AssertExpectations(t)
+		})
+	}
+}
+
+type retryableQuery struct {
+	attempts    int
+	consistency gocql.Consistency
+}
+
+func (q *retryableQuery) Attempts() int                      { return q.attempts }
+func (q *retryableQuery) SetConsistency(c gocql.Consistency) { q.consistency = c }
+func (q *retryableQuery) GetConsistency() gocql.Consistency  { return q.consistency }
+func (*retryableQuery) Context() context.Context             { return context.Background() }
+
+func TestDowngradingRetryPolicy(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	p := newDowngradingRetryPolicy([]gocql.Consistency{gocql.LocalOne, gocql.One}, metricsFactory)
+
+	q := &retryableQuery{consistency: gocql.LocalQuorum}
+	assert.True(t, p.Attempt(q))
+	assert.Equal(t, gocql.LocalQuorum, q.consistency)
+	for _, expected := range []gocql.Consistency{gocql.LocalOne, gocql.One} {
+		q.attempts++
+		assert.True(t, p.Attempt(q))
+		assert.Equal(t, expected, q.consistency)
+	}
+	q.attempts++
+	assert.False(t, p.Attempt(q))
+
+	// a query already at the first downgrade level is not counted as downgraded
+	assert.True(t, p.Attempt(&retryableQuery{attempts: 1, consistency: gocql.LocalOne}))
+
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "consistency_downgrades", Tags: map[string]string{"from": "LOCAL_QUORUM", "to": "LOCAL_ONE"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "consistency_downgrades", Tags: map[string]string{"from": "LOCAL_ONE", "to": "ONE"}, Value: 1},
+	)
+	assert.Equal(t, gocql.Retry, p.GetRetryType(&gocql.RequestErrUnavailable{Alive: 1}))
+}
+
+func TestNewClusterConsistency(t *testing.T) {
+	cfg := DefaultConfiguration()
+	cluster, err := cfg.NewCluster(zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Close())
+	assert.Equal(t, gocql.LocalOne, cluster.Consistency)
+	assert.IsType(t, &gocql.SimpleRetryPolicy{}, cluster.RetryPolicy)
+
+	cfg.Consistency = "QUORUM"
+	cfg.DowngradeConsistency = []string{"ONE"}
+	cluster, err = cfg.NewCluster(zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Close())
+	assert.Equal(t, gocql.Quorum, cluster.Consistency)
+	require.IsType(t, &downgradingRetryPolicy{}, cluster.RetryPolicy)
+	assert.Equal(t, []gocql.Consistency{gocql.One}, cluster.RetryPolicy.(*downgradingRetryPolicy).ConsistencyLevelsToTry)
+
+	read, write, err := cfg.readWriteConsistency(cluster.Consistency)
+	require.NoError(t, err)
+	assert.Equal(t, gocql.Quorum, read)
+	assert.Equal(t, gocql.Quorum, write)
+
+	cfg.ReadConsistency = "LOCAL_ONE"
+	read, write, err = cfg.readWriteConsistency(cluster.Consistency)
+	require.NoError(t, err)
+	assert.Equal(t, gocql.LocalOne, read)
+	assert.Equal(t, gocql.Quorum, write)
+}
+
+func TestNewClusterInvalidConsistency(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(cfg *Configuration)
+	}{
+		{name: "consistency", modify: func(cfg *Configuration) { cfg.Consistency = "MOST" }},
+		{name: "downgrade", modify: func(cfg *Configuration) { cfg.DowngradeConsistency = []string{"ONE", "MOST"} }},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := DefaultConfiguration()
+			defer cfg.Close()
+			test.modify(&cfg)
+			_, err := cfg.NewCluster(zap.NewNop(), metrics.NullFactory)
+			require.EqualError(t, err, `invalid Cassandra consistency level "MOST"`)
+		})
+	}
+	for _, cfg := range []Configuration{{ReadConsistency: "MOST"}, {WriteConsistency: "MOST"}} {
+		_, _, err := cfg.readWriteConsistency(gocql.LocalOne)
+		require.EqualError(t, err, `invalid Cassandra consistency level "MOST"`)
+	}
+}
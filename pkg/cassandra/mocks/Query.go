@@ -8,6 +8,8 @@
 package mocks
 
 import (
+	context "context"
+
 	cassandra "github.com/jaegertracing/jaeger/pkg/cassandra"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -161,6 +163,26 @@ func (_m *Query) String() string {
 	return r0
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *Query) WithContext(ctx context.Context) cassandra.Query {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 cassandra.Query
+	if rf, ok := ret.Get(0).(func(context.Context) cassandra.Query); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(cassandra.Query)
+		}
+	}
+
+	return r0
+}
+
 // NewQuery creates a new instance of Query. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQuery(t interface {
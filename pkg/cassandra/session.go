@@ -15,6 +15,8 @@
 
 package cassandra
 
+import "context"
+
 // Consistency is Cassandra's consistency level for queries.
 type Consistency uint16
 
@@ -64,6 +66,7 @@ type Query interface {
 	Bind(v ...any) Query
 	Consistency(level Consistency) Query
 	PageSize(int) Query
+	WithContext(ctx context.Context) Query
 }
 
 // Iterator is an abstraction of gocql.Iter
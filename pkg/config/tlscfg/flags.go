@@ -36,6 +36,8 @@ const (
 	tlsMinVersion     = tlsPrefix + ".min-version"
 	tlsMaxVersion     = tlsPrefix + ".max-version"
 	tlsReloadInterval = tlsPrefix + ".reload-interval"
+	tlsSPIFFESocket   = tlsPrefix + ".spiffe.socket"
+	tlsSPIFFEClients  = tlsPrefix + ".spiffe.client-auth"
 )
 
 // ClientFlagsConfig describes which CLI flags for TLS client should be generated.
@@ -47,6 +49,7 @@ type ClientFlagsConfig struct {
 type ServerFlagsConfig struct {
 	Prefix                   string
 	EnableCertReloadInterval bool
+	EnableSPIFFE             bool
 }
 
 // AddFlags adds flags for TLS to the FlagSet.
@@ -71,6 +74,10 @@ func (c ServerFlagsConfig) AddFlags(flags *flag.FlagSet) {
 	if c.EnableCertReloadInterval {
 		flags.Duration(c.Prefix+tlsReloadInterval, 0, "The duration after which the certificate will be reloaded (0s means will not be reloaded)")
 	}
+	if c.EnableSPIFFE {
+		flags.String(c.Prefix+tlsSPIFFESocket, "", "Address of the SPIFFE Workload API (e.g. unix:///run/spire/sockets/agent.sock) from which the server obtains and rotates its X.509 SVID, instead of using certificate and key files")
+		flags.Bool(c.Prefix+tlsSPIFFEClients, false, "Require client certificates verified by the trust bundle obtained from the SPIFFE Workload API")
+	}
 }
 
 // InitFromViper creates tls.Config populated with values retrieved from Viper.
@@ -106,6 +113,8 @@ func (c ServerFlagsConfig) InitFromViper(v *viper.Viper) (Options, error) {
 	p.MinVersion = v.GetString(c.Prefix + tlsMinVersion)
 	p.MaxVersion = v.GetString(c.Prefix + tlsMaxVersion)
 	p.ReloadInterval = v.GetDuration(c.Prefix + tlsReloadInterval)
+	p.SPIFFESocket = v.GetString(c.Prefix + tlsSPIFFESocket)
+	p.SPIFFEClientAuth = v.GetBool(c.Prefix + tlsSPIFFEClients)
 
 	if !p.Enabled {
 		var empty Options
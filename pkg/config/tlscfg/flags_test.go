@@ -156,6 +156,28 @@ func TestServerCertReloadInterval(t *testing.T) {
 	}
 }
 
+func TestServerSPIFFEFlags(t *testing.T) {
+	flagCfg := ServerFlagsConfig{Prefix: "prefix", EnableSPIFFE: true}
+	v, command := config.Viperize(flagCfg.AddFlags)
+	err := command.ParseFlags([]string{
+		"--prefix.tls.enabled=true",
+		"--prefix.tls.spiffe.socket=unix:///run/spire/agent.sock",
+		"--prefix.tls.spiffe.client-auth=true",
+	})
+	require.NoError(t, err)
+	tlsOpts, err := flagCfg.InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		Enabled:          true,
+		SPIFFESocket:     "unix:///run/spire/agent.sock",
+		SPIFFEClientAuth: true,
+	}, tlsOpts)
+
+	_, command = config.Viperize(ServerFlagsConfig{Prefix: "prefix"}.AddFlags)
+	err = command.ParseFlags([]string{"--prefix.tls.spiffe.socket=unix:///run/spire/agent.sock"})
+	require.ErrorContains(t, err, "unknown flag")
+}
+
 // TestFailedTLSFlags verifies that TLS options cannot be used when tls.enabled=false
 func TestFailedTLSFlags(t *testing.T) {
 	clientTests := []string{
@@ -17,6 +17,7 @@ package tlscfg
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -29,18 +30,21 @@ import (
 
 // Options describes the configuration properties for TLS Connections.
 type Options struct {
-	Enabled        bool          `mapstructure:"enabled"`
-	CAPath         string        `mapstructure:"ca"`
-	CertPath       string        `mapstructure:"cert"`
-	KeyPath        string        `mapstructure:"key"`
-	ServerName     string        `mapstructure:"server_name"` // only for client-side TLS config
-	ClientCAPath   string        `mapstructure:"client_ca"`   // only for server-side TLS config for client auth
-	CipherSuites   []string      `mapstructure:"cipher_suites"`
-	MinVersion     string        `mapstructure:"min_version"`
-	MaxVersion     string        `mapstructure:"max_version"`
-	SkipHostVerify bool          `mapstructure:"skip_host_verify"`
-	ReloadInterval time.Duration `mapstructure:"reload_interval"`
-	certWatcher    *certWatcher
+	Enabled          bool          `mapstructure:"enabled"`
+	CAPath           string        `mapstructure:"ca"`
+	CertPath         string        `mapstructure:"cert"`
+	KeyPath          string        `mapstructure:"key"`
+	ServerName       string        `mapstructure:"server_name"` // only for client-side TLS config
+	ClientCAPath     string        `mapstructure:"client_ca"`   // only for server-side TLS config for client auth
+	CipherSuites     []string      `mapstructure:"cipher_suites"`
+	MinVersion       string        `mapstructure:"min_version"`
+	MaxVersion       string        `mapstructure:"max_version"`
+	SkipHostVerify   bool          `mapstructure:"skip_host_verify"`
+	ReloadInterval   time.Duration `mapstructure:"reload_interval"`
+	SPIFFESocket     string        `mapstructure:"spiffe_socket"`      // SPIFFE Workload API providing the certificate and key
+	SPIFFEClientAuth bool          `mapstructure:"spiffe_client_auth"` // verify clients with the SPIFFE trust bundle
+	certWatcher      *certWatcher
+	spiffeSource     *spiffeSource
 }
 
 var systemCertPool = x509.SystemCertPool // to allow overriding in unit test
@@ -98,6 +102,13 @@ func (o *Options) Config(logger *zap.Logger) (*tls.Config, error) {
 		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
+	if o.SPIFFESocket != "" && (o.CertPath != "" || o.KeyPath != "") {
+		return nil, fmt.Errorf("TLS certificate and key files cannot be used along with the SPIFFE Workload API")
+	}
+	if o.SPIFFEClientAuth && (o.SPIFFESocket == "" || o.ClientCAPath != "") {
+		return nil, fmt.Errorf("SPIFFE client authentication requires the SPIFFE Workload API and no client CA file")
+	}
+
 	certWatcher, err := newCertWatcher(*o, logger, tlsCfg.RootCAs, tlsCfg.ClientCAs)
 	if err != nil {
 		return nil, err
@@ -116,10 +127,42 @@ func (o *Options) Config(logger *zap.Logger) (*tls.Config, error) {
 			return o.certWatcher.certificate(), nil
 		}
 	}
+	if o.SPIFFESocket != "" {
+		if err := o.configureSPIFFE(tlsCfg, logger); err != nil {
+			return nil, err
+		}
+	}
 
 	return tlsCfg, nil
 }
 
+// configureSPIFFE makes the TLS config use the current X.509 SVID obtained from the SPIFFE Workload API,
+// and the current trust bundle to verify clients when SPIFFEClientAuth is enabled.
+func (o *Options) configureSPIFFE(tlsCfg *tls.Config, logger *zap.Logger) error {
+	source, err := newSPIFFESource(o.SPIFFESocket, spiffeInitialFetchTimeout, logger)
+	if err != nil {
+		return err
+	}
+	o.spiffeSource = source
+	tlsCfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return source.certificate(), nil
+	}
+	tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return source.certificate(), nil
+	}
+	if o.SPIFFEClientAuth {
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		// the trust bundle is rotated along with the SVID, so it is looked up on every handshake
+		tlsCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := tlsCfg.Clone()
+			cfg.GetConfigForClient = nil
+			cfg.ClientCAs = source.bundle()
+			return cfg, nil
+		}
+	}
+	return nil
+}
+
 func (o Options) loadCertPool() (*x509.CertPool, error) {
 	if len(o.CAPath) == 0 { // no truststore given, use SystemCertPool
 		certPool, err := loadSystemCertPool()
@@ -167,10 +210,14 @@ func addCertToPool(caPath string, certPool *x509.CertPool) error {
 
 var _ io.Closer = (*Options)(nil)
 
-// Close shuts down the embedded certificate watcher.
+// Close shuts down the embedded certificate watcher and SPIFFE Workload API client.
 func (o *Options) Close() error {
+	var errs []error
 	if o.certWatcher != nil {
-		return o.certWatcher.Close()
+		errs = append(errs, o.certWatcher.Close())
 	}
-	return nil
+	if o.spiffeSource != nil {
+		errs = append(errs, o.spiffeSource.Close())
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,290 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// spiffeHeader must be set on every request to the SPIFFE Workload API.
+	spiffeHeader = "workload.spiffe.io"
+	// fetchX509SVIDMethod streams the X.509 SVIDs of the workload, and a new response on every rotation.
+	fetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+
+	spiffeInitialFetchTimeout = 30 * time.Second
+	spiffeMaxRetryInterval    = 30 * time.Second
+
+	logMsgSVIDUpdated    = "Received updated X.509 SVID from the SPIFFE Workload API"
+	logMsgSVIDNotUpdated = "Failed to watch X.509 SVIDs from the SPIFFE Workload API, retrying"
+)
+
+// x509SVID is an X.509 SVID along with the trust bundle of its trust domain.
+type x509SVID struct {
+	id     string
+	cert   *tls.Certificate
+	bundle *x509.CertPool
+}
+
+// spiffeSource obtains X.509 SVIDs from the SPIFFE Workload API and keeps them up to date,
+// as the SPIFFE agent streams a new SVID to the workload whenever the previous one is rotated.
+// The SVID and the trust bundle can be obtained via spiffeSource.certificate and spiffeSource.bundle.
+type spiffeSource struct {
+	mu     sync.RWMutex
+	svid   x509SVID
+	logger *zap.Logger
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newSPIFFESource connects to the Workload API at the given address, e.g. unix:///run/spire/agent.sock,
+// and waits for the first SVID for at most timeout.
+func newSPIFFESource(address string, timeout time.Duration, logger *zap.Logger) (*spiffeSource, error) {
+	target, err := spiffeTarget(address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SPIFFE Workload API %s: %w", address, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &spiffeSource{
+		logger: logger,
+		conn:   conn,
+		cancel: cancel,
+	}
+
+	ready := make(chan struct{})
+	var readyOnce sync.Once
+	// lastErr is only read after Close, which waits for the watch to return
+	lastErr := errors.New("timed out")
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.watch(ctx,
+			func() { readyOnce.Do(func() { close(ready) }) },
+			func(err error) { lastErr = err },
+		)
+	}()
+
+	select {
+	case <-ready:
+		return s, nil
+	case <-time.After(timeout):
+		s.Close()
+		return nil, fmt.Errorf("failed to fetch X.509 SVID from SPIFFE Workload API %s: %w", address, lastErr)
+	}
+}
+
+// spiffeTarget converts a Workload API address to a gRPC target.
+func spiffeTarget(address string) (string, error) {
+	switch {
+	case strings.HasPrefix(address, "unix:"):
+		return address, nil
+	case strings.HasPrefix(address, "tcp://"):
+		return "passthrough:///" + strings.TrimPrefix(address, "tcp://"), nil
+	default:
+		return "", fmt.Errorf("invalid SPIFFE Workload API address %q, expected unix:///path or tcp://host:port", address)
+	}
+}
+
+func (s *spiffeSource) certificate() *tls.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.svid.cert
+}
+
+func (s *spiffeSource) bundle() *x509.CertPool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.svid.bundle
+}
+
+// watch streams the SVIDs until the context is cancelled, reconnecting after failures.
+func (s *spiffeSource) watch(ctx context.Context, onUpdate func(), onError func(error)) {
+	retryInterval := time.Second
+	for {
+		err := s.stream(ctx, func(svid x509SVID) {
+			s.mu.Lock()
+			s.svid = svid
+			s.mu.Unlock()
+			retryInterval = time.Second
+			s.logger.Info(logMsgSVIDUpdated,
+				zap.String("spiffe-id", svid.id), zap.Time("expiry", svid.cert.Leaf.NotAfter))
+			onUpdate()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn(logMsgSVIDNotUpdated, zap.Error(err), zap.Duration("retry-interval", retryInterval))
+		onError(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+		retryInterval = min(2*retryInterval, spiffeMaxRetryInterval)
+	}
+}
+
+// stream calls FetchX509SVID and applies every response until the stream fails.
+func (s *spiffeSource) stream(ctx context.Context, apply func(x509SVID)) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, spiffeHeader, "true")
+	stream, err := s.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fetchX509SVIDMethod,
+		grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+	// X509SVIDRequest has no fields
+	if err := stream.SendMsg([]byte{}); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		var msg []byte
+		if err := stream.RecvMsg(&msg); err != nil {
+			return err
+		}
+		svid, err := parseX509SVIDResponse(msg)
+		if err != nil {
+			return err
+		}
+		apply(svid)
+	}
+}
+
+// Close stops watching the SVIDs and closes the connection to the Workload API.
+func (s *spiffeSource) Close() error {
+	s.cancel()
+	err := s.conn.Close()
+	s.wg.Wait()
+	return err
+}
+
+// parseX509SVIDResponse decodes the X509SVIDResponse message of the Workload API and returns its first SVID,
+// which is the default SVID of the workload:
+//
+//	message X509SVIDResponse { repeated X509SVID svids = 1; ... }
+//	message X509SVID {
+//	  string spiffe_id = 1;
+//	  bytes x509_svid = 2;     // ASN.1 DER certificate chain
+//	  bytes x509_svid_key = 3; // ASN.1 DER PKCS#8 private key
+//	  bytes bundle = 4;        // ASN.1 DER trust bundle certificates
+//	}
+func parseX509SVIDResponse(msg []byte) (x509SVID, error) {
+	fields, err := parseProtoBytesFields(msg)
+	if err != nil {
+		return x509SVID{}, err
+	}
+	if len(fields[1]) == 0 {
+		return x509SVID{}, errors.New("X.509 SVID response contains no SVID")
+	}
+	svidFields, err := parseProtoBytesFields(fields[1][0])
+	if err != nil {
+		return x509SVID{}, err
+	}
+	field := func(num protowire.Number) []byte {
+		if values := svidFields[num]; len(values) > 0 {
+			return values[len(values)-1]
+		}
+		return nil
+	}
+
+	svid := x509SVID{id: string(field(1))}
+	chain, err := x509.ParseCertificates(field(2))
+	if err != nil {
+		return x509SVID{}, fmt.Errorf("invalid certificates in X.509 SVID %s: %w", svid.id, err)
+	}
+	if len(chain) == 0 {
+		return x509SVID{}, fmt.Errorf("X.509 SVID %s contains no certificate", svid.id)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(field(3))
+	if err != nil {
+		return x509SVID{}, fmt.Errorf("invalid private key in X.509 SVID %s: %w", svid.id, err)
+	}
+	bundle, err := x509.ParseCertificates(field(4))
+	if err != nil {
+		return x509SVID{}, fmt.Errorf("invalid trust bundle of X.509 SVID %s: %w", svid.id, err)
+	}
+
+	svid.cert = &tls.Certificate{PrivateKey: key, Leaf: chain[0]}
+	for _, c := range chain {
+		svid.cert.Certificate = append(svid.cert.Certificate, c.Raw)
+	}
+	svid.bundle = x509.NewCertPool()
+	for _, c := range bundle {
+		svid.bundle.AddCert(c)
+	}
+	return svid, nil
+}
+
+// parseProtoBytesFields returns the values of the length-delimited fields of a protobuf message,
+// skipping fields of other types.
+func parseProtoBytesFields(msg []byte) (map[protowire.Number][][]byte, error) {
+	fields := make(map[protowire.Number][][]byte)
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(n))
+		}
+		msg = msg[n:]
+		if typ == protowire.BytesType {
+			value, m := protowire.ConsumeBytes(msg)
+			if m < 0 {
+				return nil, fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(m))
+			}
+			fields[num] = append(fields[num], value)
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid protobuf message: %w", protowire.ParseError(n))
+			}
+		}
+		msg = msg[n:]
+	}
+	return fields, nil
+}
+
+// rawCodec passes already encoded protobuf messages through gRPC, which avoids
+// depending on the generated code of the Workload API for its two messages.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
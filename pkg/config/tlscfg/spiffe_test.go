@@ -0,0 +1,320 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testSVID struct {
+	id     string
+	chain  []byte
+	key    []byte
+	bundle []byte
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"SPIFFE"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, id string, serial int64) testSVID {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	uri, err := url.Parse(id)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{uri},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return testSVID{id: id, chain: der, key: pkcs8, bundle: ca.cert.Raw}
+}
+
+func (s testSVID) tlsCertificate(t *testing.T) tls.Certificate {
+	key, err := x509.ParsePKCS8PrivateKey(s.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{s.chain}, PrivateKey: key}
+}
+
+func encodeX509SVIDResponse(svids ...testSVID) []byte {
+	var msg []byte
+	for _, s := range svids {
+		var svid []byte
+		svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+		svid = protowire.AppendString(svid, s.id)
+		svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+		svid = protowire.AppendBytes(svid, s.chain)
+		svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+		svid = protowire.AppendBytes(svid, s.key)
+		svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+		svid = protowire.AppendBytes(svid, s.bundle)
+		msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+		msg = protowire.AppendBytes(msg, svid)
+	}
+	// federated_bundles are ignored
+	msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, []byte{})
+	return msg
+}
+
+// startWorkloadAPI starts a fake SPIFFE Workload API which streams the responses sent to the returned channel.
+func startWorkloadAPI(t *testing.T) (address string, responses chan<- []byte) {
+	dir, err := os.MkdirTemp("", "spiffe")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	updates := make(chan []byte, 10)
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			if method != fetchX509SVIDMethod {
+				return status.Error(codes.Unimplemented, method)
+			}
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			if len(md.Get(spiffeHeader)) == 0 {
+				return status.Error(codes.InvalidArgument, "security header missing from request")
+			}
+			var req []byte
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			for {
+				select {
+				case msg := <-updates:
+					if err := stream.SendMsg(msg); err != nil {
+						return err
+					}
+				case <-stream.Context().Done():
+					return nil
+				}
+			}
+		}),
+	)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return "unix://" + socket, updates
+}
+
+func TestSPIFFESourceRotation(t *testing.T) {
+	ca := newTestCA(t)
+	address, responses := startWorkloadAPI(t)
+	first := ca.issue(t, "spiffe://example.org/collector", 2)
+	responses <- encodeX509SVIDResponse(first)
+
+	source, err := newSPIFFESource(address, 5*time.Second, zap.NewNop())
+	require.NoError(t, err)
+	defer source.Close()
+	assert.Equal(t, first.chain, source.certificate().Certificate[0])
+	assert.Equal(t, "spiffe://example.org/collector", source.certificate().Leaf.URIs[0].String())
+	_, err = source.certificate().Leaf.Verify(x509.VerifyOptions{Roots: source.bundle()})
+	require.NoError(t, err)
+
+	second := ca.issue(t, "spiffe://example.org/collector", 3)
+	responses <- encodeX509SVIDResponse(second)
+	assert.Eventually(t, func() bool {
+		return source.certificate().Leaf.SerialNumber.Int64() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSPIFFESourceErrors(t *testing.T) {
+	_, err := newSPIFFESource("/run/spire/agent.sock", time.Second, zap.NewNop())
+	require.ErrorContains(t, err, `invalid SPIFFE Workload API address "/run/spire/agent.sock"`)
+
+	address, responses := startWorkloadAPI(t)
+	responses <- encodeX509SVIDResponse()
+	_, err = newSPIFFESource(address, 100*time.Millisecond, zap.NewNop())
+	require.ErrorContains(t, err, "X.509 SVID response contains no SVID")
+
+	_, err = newSPIFFESource("unix:///does/not/exist.sock", 100*time.Millisecond, zap.NewNop())
+	require.ErrorContains(t, err, "failed to fetch X.509 SVID from SPIFFE Workload API unix:///does/not/exist.sock")
+}
+
+func TestSPIFFETarget(t *testing.T) {
+	target, err := spiffeTarget("tcp://127.0.0.1:8081")
+	require.NoError(t, err)
+	assert.Equal(t, "passthrough:///127.0.0.1:8081", target)
+
+	target, err = spiffeTarget("unix:///run/spire/agent.sock")
+	require.NoError(t, err)
+	assert.Equal(t, "unix:///run/spire/agent.sock", target)
+}
+
+func TestParseX509SVIDResponseErrors(t *testing.T) {
+	svid := newTestCA(t).issue(t, "spiffe://example.org/query", 2)
+	withChain := svid
+	withChain.chain = []byte("garbage")
+	withoutChain := svid
+	withoutChain.chain = nil
+	withKey := svid
+	withKey.key = []byte("garbage")
+	withBundle := svid
+	withBundle.bundle = []byte("garbage")
+
+	testCases := []struct {
+		name   string
+		msg    []byte
+		errMsg string
+	}{
+		{name: "invalid message", msg: []byte{0x0a, 0x05}, errMsg: "invalid protobuf message"},
+		{name: "invalid SVID message", msg: protowire.AppendBytes([]byte{0x0a}, []byte{0xff}), errMsg: "invalid protobuf message"},
+		{name: "invalid tag", msg: []byte{0xff}, errMsg: "invalid protobuf message"},
+		{name: "invalid varint", msg: []byte{0x08, 0xff}, errMsg: "invalid protobuf message"},
+		{name: "no SVID", msg: encodeX509SVIDResponse(), errMsg: "X.509 SVID response contains no SVID"},
+		{name: "invalid chain", msg: encodeX509SVIDResponse(withChain), errMsg: "invalid certificates in X.509 SVID spiffe://example.org/query"},
+		{name: "no chain", msg: encodeX509SVIDResponse(withoutChain), errMsg: "X.509 SVID spiffe://example.org/query contains no certificate"},
+		{name: "invalid key", msg: encodeX509SVIDResponse(withKey), errMsg: "invalid private key in X.509 SVID spiffe://example.org/query"},
+		{name: "invalid bundle", msg: encodeX509SVIDResponse(withBundle), errMsg: "invalid trust bundle of X.509 SVID spiffe://example.org/query"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseX509SVIDResponse(tc.msg)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+
+	// fields of other types are skipped
+	msg := protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), 1)
+	parsed, err := parseX509SVIDResponse(append(msg, encodeX509SVIDResponse(svid)...))
+	require.NoError(t, err)
+	assert.Equal(t, "spiffe://example.org/query", parsed.id)
+}
+
+func TestRawCodec(t *testing.T) {
+	_, err := rawCodec{}.Marshal("message")
+	require.ErrorContains(t, err, "unexpected message type string")
+	require.ErrorContains(t, rawCodec{}.Unmarshal(nil, "message"), "unexpected message type string")
+}
+
+func TestOptionsSPIFFEMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	address, responses := startWorkloadAPI(t)
+	responses <- encodeX509SVIDResponse(ca.issue(t, "spiffe://example.org/collector", 2))
+
+	opts := &Options{Enabled: true, SPIFFESocket: address, SPIFFEClientAuth: true}
+	serverCfg, err := opts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer opts.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	handshake := func(clientCerts []tls.Certificate) error {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+			RootCAs:      roots,
+			Certificates: clientCerts,
+			MinVersion:   tls.VersionTLS12,
+			MaxVersion:   tls.VersionTLS12, // handshake errors of the server are only reported by TLS 1.2 clients
+		})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	require.NoError(t, handshake([]tls.Certificate{ca.issue(t, "spiffe://example.org/agent", 3).tlsCertificate(t)}))
+	require.Error(t, handshake(nil))
+	require.Error(t, handshake([]tls.Certificate{newTestCA(t).issue(t, "spiffe://other.org/agent", 4).tlsCertificate(t)}))
+}
+
+func TestOptionsSPIFFEErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opts   Options
+		errMsg string
+	}{
+		{
+			name:   "cert files",
+			opts:   Options{SPIFFESocket: "unix:///run/spire/agent.sock", CertPath: serverCert, KeyPath: serverKey},
+			errMsg: "TLS certificate and key files cannot be used along with the SPIFFE Workload API",
+		},
+		{
+			name:   "client auth without socket",
+			opts:   Options{SPIFFEClientAuth: true},
+			errMsg: "SPIFFE client authentication requires the SPIFFE Workload API and no client CA file",
+		},
+		{
+			name:   "client auth with client CA",
+			opts:   Options{SPIFFESocket: "unix:///run/spire/agent.sock", SPIFFEClientAuth: true, ClientCAPath: caCert},
+			errMsg: "SPIFFE client authentication requires the SPIFFE Workload API and no client CA file",
+		},
+		{
+			name:   "invalid address",
+			opts:   Options{SPIFFESocket: "/run/spire/agent.sock"},
+			errMsg: "invalid SPIFFE Workload API address",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.opts.Config(zap.NewNop())
+			require.ErrorContains(t, err, tc.errMsg)
+			require.NoError(t, tc.opts.Close())
+		})
+	}
+}
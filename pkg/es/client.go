@@ -17,6 +17,7 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 
 	"github.com/olivere/elastic"
@@ -25,12 +26,17 @@ import (
 // Client is an abstraction for elastic.Client
 type Client interface {
 	IndexExists(index string) IndicesExistsService
+	AliasExists(alias string) IndicesExistsService
 	CreateIndex(index string) IndicesCreateService
 	CreateTemplate(id string) TemplateCreateService
+	GetTemplate(id string) TemplateGetService
 	Index() IndexService
 	Search(indices ...string) SearchService
 	MultiSearch() MultiSearchService
+	Count(indices ...string) CountService
+	DeleteByQuery(indices ...string) DeleteByQueryService
 	DeleteIndex(index string) IndicesDeleteService
+	Document(index, id string) DocumentService
 	io.Closer
 	GetVersion() uint
 }
@@ -57,11 +63,44 @@ type TemplateCreateService interface {
 	Do(ctx context.Context) (*elastic.IndicesPutTemplateResponse, error)
 }
 
+// TemplateGetService is an abstraction for reading an index template
+type TemplateGetService interface {
+	// Do returns the definition of the template, or nil if the template does not exist
+	Do(ctx context.Context) (json.RawMessage, error)
+}
+
+// DocumentService is an abstraction for the single document APIs of elastic, changing a document
+// only if it was not changed since it was read, using optimistic concurrency control.
+type DocumentService interface {
+	// Get returns the document, or nil if it does not exist.
+	Get(ctx context.Context) (*VersionedDocument, error)
+	// Create creates the document, returning false if it already exists.
+	Create(ctx context.Context, body any) (bool, error)
+	// Update replaces the document of the version, returning false if it was changed or deleted since.
+	Update(ctx context.Context, body any, version DocumentVersion) (bool, error)
+	// Delete deletes the document of the version, returning false if it was changed or deleted since.
+	Delete(ctx context.Context, version DocumentVersion) (bool, error)
+}
+
+// DocumentVersion identifies a change of a document, see the if_seq_no and if_primary_term parameters.
+type DocumentVersion struct {
+	SeqNo       int64
+	PrimaryTerm int64
+}
+
+// VersionedDocument is the source of a document with its version.
+type VersionedDocument struct {
+	Source  json.RawMessage
+	Version DocumentVersion
+}
+
 // IndexService is an abstraction for elastic BulkService
 type IndexService interface {
 	Index(index string) IndexService
 	Type(typ string) IndexService
 	Id(id string) IndexService
+	// Routing sets the value the shard of the document is chosen by, the document ID by default
+	Routing(routing string) IndexService
 	BodyJson(body any) IndexService
 	Add()
 }
@@ -75,6 +114,23 @@ type SearchService interface {
 	Do(ctx context.Context) (*elastic.SearchResult, error)
 }
 
+// CountService is an abstraction for elastic.CountService
+type CountService interface {
+	Query(query elastic.Query) CountService
+	IgnoreUnavailable(ignoreUnavailable bool) CountService
+	TerminateAfter(terminateAfter int) CountService
+	Do(ctx context.Context) (int64, error)
+}
+
+// DeleteByQueryService is an abstraction for elastic.DeleteByQueryService
+type DeleteByQueryService interface {
+	Query(query elastic.Query) DeleteByQueryService
+	IgnoreUnavailable(ignoreUnavailable bool) DeleteByQueryService
+	ProceedOnVersionConflict() DeleteByQueryService
+	Refresh(refresh string) DeleteByQueryService
+	Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error)
+}
+
 // MultiSearchService is an abstraction for elastic.MultiSearchService
 type MultiSearchService interface {
 	Add(requests ...*elastic.SearchRequest) MultiSearchService
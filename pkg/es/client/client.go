@@ -90,7 +90,8 @@ func (c *Client) request(esRequest elasticRequest) ([]byte, error) {
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
+	// creating a resource, e.g. an ISM policy, returns 201 Created
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
 		return []byte{}, c.handleFailedRequest(res)
 	}
 
@@ -29,21 +29,35 @@ type ClusterClient struct {
 	Client
 }
 
-// Version returns the major version of the ES cluster
-func (c *ClusterClient) Version() (uint, error) {
-	type clusterInfo struct {
-		Version map[string]any `json:"version"`
-		TagLine string         `json:"tagline"`
-	}
+type clusterInfo struct {
+	Version map[string]any `json:"version"`
+	TagLine string         `json:"tagline"`
+}
+
+func (c *ClusterClient) info() (clusterInfo, error) {
 	body, err := c.request(elasticRequest{
 		endpoint: "",
 		method:   http.MethodGet,
 	})
 	if err != nil {
-		return 0, err
+		return clusterInfo{}, err
 	}
 	var info clusterInfo
 	if err = json.Unmarshal(body, &info); err != nil {
+		return clusterInfo{}, err
+	}
+	return info, nil
+}
+
+// isOpenSearch tells whether the cluster is an OpenSearch cluster, by its distribution or its tagline.
+func (info clusterInfo) isOpenSearch() bool {
+	return info.Version["distribution"] == "opensearch" || strings.Contains(info.TagLine, "OpenSearch")
+}
+
+// Version returns the major version of the ES cluster
+func (c *ClusterClient) Version() (uint, error) {
+	info, err := c.info()
+	if err != nil {
 		return 0, err
 	}
 
@@ -57,8 +71,17 @@ func (c *ClusterClient) Version() (uint, error) {
 	if err != nil {
 		return 0, fmt.Errorf("invalid version format: %s", version[0])
 	}
-	if strings.Contains(info.TagLine, "OpenSearch") && (major == 1 || major == 2) {
+	if info.isOpenSearch() && (major == 1 || major == 2) {
 		return 7, nil
 	}
 	return uint(major), nil
 }
+
+// IsOpenSearch tells whether the cluster is an OpenSearch cluster rather than an Elasticsearch one
+func (c *ClusterClient) IsOpenSearch() (bool, error) {
+	info, err := c.info()
+	if err != nil {
+		return false, err
+	}
+	return info.isOpenSearch(), nil
+}
@@ -244,3 +244,70 @@ func TestVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestIsOpenSearch(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseCode   int
+		response       string
+		errContains    string
+		expectedResult bool
+	}{
+		{
+			name:         "elasticsearch 7",
+			responseCode: http.StatusOK,
+			response:     elasticsearch7,
+		},
+		{
+			name:           "opensearch 1",
+			responseCode:   http.StatusOK,
+			response:       opensearch1,
+			expectedResult: true,
+		},
+		{
+			name:           "opensearch 2",
+			responseCode:   http.StatusOK,
+			response:       opensearch2,
+			expectedResult: true,
+		},
+		{
+			name:         "client error",
+			responseCode: http.StatusBadRequest,
+			response:     esErrResponse,
+			errContains:  "request failed, status code: 400",
+		},
+		{
+			name:         "unmarshal error",
+			responseCode: http.StatusOK,
+			response:     "thisisaninvalidjson",
+			errContains:  "invalid character",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodGet, req.Method)
+				assert.Equal(t, "Basic foobar", req.Header.Get("Authorization"))
+				res.WriteHeader(test.responseCode)
+				res.Write([]byte(test.response))
+			}))
+			defer testServer.Close()
+
+			c := &ClusterClient{
+				Client: Client{
+					Client:    testServer.Client(),
+					Endpoint:  testServer.URL,
+					BasicAuth: "foobar",
+				},
+			}
+			result, err := c.IsOpenSearch()
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
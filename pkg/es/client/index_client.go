@@ -44,7 +44,24 @@ type Alias struct {
 	IsWriteIndex bool
 }
 
-var _ IndexAPI = (*IndicesClient)(nil)
+// Task represents the progress of an asynchronous ES task.
+type Task struct {
+	// Task ID, in the node:id form.
+	ID string
+	// Completed is true once the task has finished, successfully or not.
+	Completed bool
+	// Total number of documents the task has to process.
+	Total int64
+	// Done is the number of documents processed so far.
+	Done int64
+	// Error is the failure reason if the task failed.
+	Error string
+}
+
+var (
+	_ IndexAPI   = (*IndicesClient)(nil)
+	_ ReindexAPI = (*IndicesClient)(nil)
+)
 
 // IndicesClient is a client used to manipulate indices.
 type IndicesClient struct {
@@ -294,3 +311,81 @@ func (i IndicesClient) Rollover(rolloverTarget string, conditions map[string]any
 	}
 	return nil
 }
+
+// Reindex starts an asynchronous copy of all documents from the source index into the destination index
+// and returns the ID of the task tracking its progress.
+func (i IndicesClient) Reindex(source, dest string) (string, error) {
+	body := map[string]any{
+		"source": map[string]any{"index": source},
+		"dest":   map[string]any{"index": dest},
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	respBody, err := i.request(elasticRequest{
+		endpoint: "_reindex?wait_for_completion=false",
+		method:   http.MethodPost,
+		body:     bodyBytes,
+	})
+	if err != nil {
+		var responseError ResponseError
+		if errors.As(err, &responseError) {
+			if responseError.StatusCode != http.StatusOK {
+				return "", responseError.prefixMessage(fmt.Sprintf("failed to reindex %s into %s", source, dest))
+			}
+		}
+		return "", fmt.Errorf("failed to reindex: %w", err)
+	}
+	var response struct {
+		Task string `json:"task"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return "", fmt.Errorf("failed to unmarshall reindex response body: %q: %w", respBody, err)
+	}
+	return response.Task, nil
+}
+
+// GetTask returns the progress of a task started by an asynchronous request such as Reindex.
+func (i IndicesClient) GetTask(taskID string) (Task, error) {
+	respBody, err := i.request(elasticRequest{
+		endpoint: fmt.Sprintf("_tasks/%s", taskID),
+		method:   http.MethodGet,
+	})
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to query task %s: %w", taskID, err)
+	}
+	var response struct {
+		Completed bool `json:"completed"`
+		Task      struct {
+			Status struct {
+				Total   int64 `json:"total"`
+				Created int64 `json:"created"`
+				Updated int64 `json:"updated"`
+				Deleted int64 `json:"deleted"`
+			} `json:"status"`
+		} `json:"task"`
+		Error *struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+		Response struct {
+			Failures []json.RawMessage `json:"failures"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return Task{}, fmt.Errorf("failed to unmarshall task response body: %q: %w", respBody, err)
+	}
+	status := response.Task.Status
+	task := Task{
+		ID:        taskID,
+		Completed: response.Completed,
+		Total:     status.Total,
+		Done:      status.Created + status.Updated + status.Deleted,
+	}
+	if response.Error != nil {
+		task.Error = response.Error.Reason
+	} else if len(response.Response.Failures) > 0 {
+		task.Error = fmt.Sprintf("%d documents failed, first failure: %s", len(response.Response.Failures), response.Response.Failures[0])
+	}
+	return task, nil
+}
@@ -596,3 +596,139 @@ func TestRollover(t *testing.T) {
 		})
 	}
 }
+
+func TestReindex(t *testing.T) {
+	expectedRequestBody := `{"dest":{"index":"new-jaeger-span-2021-08-06"},"source":{"index":"jaeger-span-2021-08-06"}}`
+
+	tests := []struct {
+		name         string
+		responseCode int
+		response     string
+		taskID       string
+		errContains  string
+	}{
+		{
+			name:         "success",
+			responseCode: http.StatusOK,
+			response:     `{"task":"node1:42"}`,
+			taskID:       "node1:42",
+		},
+		{
+			name:         "client error",
+			responseCode: http.StatusBadRequest,
+			response:     esErrResponse,
+			errContains:  "failed to reindex jaeger-span-2021-08-06 into new-jaeger-span-2021-08-06",
+		},
+		{
+			name:         "unmarshall error",
+			responseCode: http.StatusOK,
+			response:     "AAA",
+			errContains:  `failed to unmarshall reindex response body: "AAA"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				assert.True(t, strings.HasSuffix(req.URL.String(), "_reindex?wait_for_completion=false"))
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "Basic foobar", req.Header.Get("Authorization"))
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, expectedRequestBody, string(body))
+
+				res.WriteHeader(test.responseCode)
+				res.Write([]byte(test.response))
+			}))
+			defer testServer.Close()
+
+			c := &IndicesClient{
+				Client: Client{
+					Client:    testServer.Client(),
+					Endpoint:  testServer.URL,
+					BasicAuth: "foobar",
+				},
+			}
+			taskID, err := c.Reindex("jaeger-span-2021-08-06", "new-jaeger-span-2021-08-06")
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.taskID, taskID)
+		})
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseCode int
+		response     string
+		task         Task
+		errContains  string
+	}{
+		{
+			name:         "in progress",
+			responseCode: http.StatusOK,
+			response:     `{"completed":false,"task":{"status":{"total":100,"created":40,"updated":2}}}`,
+			task:         Task{ID: "node1:42", Total: 100, Done: 42},
+		},
+		{
+			name:         "completed",
+			responseCode: http.StatusOK,
+			response:     `{"completed":true,"task":{"status":{"total":100,"created":100}},"response":{"failures":[]}}`,
+			task:         Task{ID: "node1:42", Completed: true, Total: 100, Done: 100},
+		},
+		{
+			name:         "task error",
+			responseCode: http.StatusOK,
+			response:     `{"completed":true,"task":{"status":{"total":100}},"error":{"reason":"index_not_found"}}`,
+			task:         Task{ID: "node1:42", Completed: true, Total: 100, Error: "index_not_found"},
+		},
+		{
+			name:         "document failures",
+			responseCode: http.StatusOK,
+			response:     `{"completed":true,"task":{"status":{"total":2,"created":1}},"response":{"failures":[{"id":"a"}]}}`,
+			task:         Task{ID: "node1:42", Completed: true, Total: 2, Done: 1, Error: `1 documents failed, first failure: {"id":"a"}`},
+		},
+		{
+			name:         "client error",
+			responseCode: http.StatusNotFound,
+			response:     esErrResponse,
+			errContains:  "failed to query task node1:42",
+		},
+		{
+			name:         "unmarshall error",
+			responseCode: http.StatusOK,
+			response:     "AAA",
+			errContains:  `failed to unmarshall task response body: "AAA"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				assert.True(t, strings.HasSuffix(req.URL.String(), "_tasks/node1:42"))
+				assert.Equal(t, http.MethodGet, req.Method)
+				res.WriteHeader(test.responseCode)
+				res.Write([]byte(test.response))
+			}))
+			defer testServer.Close()
+
+			c := &IndicesClient{
+				Client: Client{
+					Client:   testServer.Client(),
+					Endpoint: testServer.URL,
+				},
+			}
+			task, err := c.GetTask("node1:42")
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.task, task)
+		})
+	}
+}
@@ -24,10 +24,22 @@ type IndexAPI interface {
 	Rollover(rolloverTarget string, conditions map[string]any) error
 }
 
+type ReindexAPI interface {
+	Reindex(source, dest string) (string, error)
+	GetTask(taskID string) (Task, error)
+}
+
 type ClusterAPI interface {
 	Version() (uint, error)
+	IsOpenSearch() (bool, error)
 }
 
 type IndexManagementLifecycleAPI interface {
 	Exists(name string) (bool, error)
 }
+
+type IndexStateManagementAPI interface {
+	GetPolicy(name string) (*StoredISMPolicy, error)
+	CreatePolicy(name string, policy ISMPolicy) error
+	UpdatePolicy(name string, policy ISMPolicy, seqNo, primaryTerm int64) error
+}
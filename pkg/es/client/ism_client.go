@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var _ IndexStateManagementAPI = (*ISMClient)(nil)
+
+// ISMPolicy is an OpenSearch Index State Management policy. Only the fields managed
+// by es-rollover are decoded, so that stored policies can be compared with desired ones.
+type ISMPolicy struct {
+	Description  string        `json:"description"`
+	DefaultState string        `json:"default_state"`
+	States       []ISMState    `json:"states"`
+	ISMTemplate  []ISMTemplate `json:"ism_template,omitempty"`
+}
+
+// ISMState is a state of an ISM policy, e.g. hot or delete.
+type ISMState struct {
+	Name        string          `json:"name"`
+	Actions     []ISMAction     `json:"actions,omitempty"`
+	Transitions []ISMTransition `json:"transitions,omitempty"`
+}
+
+// ISMAction is an action of an ISM state, of which a single field is set.
+type ISMAction struct {
+	Rollover *ISMRollover `json:"rollover,omitempty"`
+	Delete   *ISMDelete   `json:"delete,omitempty"`
+}
+
+// ISMRollover rolls the index over when any of its conditions is met.
+type ISMRollover struct {
+	MinIndexAge string `json:"min_index_age,omitempty"`
+	MinSize     string `json:"min_size,omitempty"`
+}
+
+// ISMDelete deletes the index.
+type ISMDelete struct{}
+
+// ISMTransition moves the index to another state once its conditions are met.
+type ISMTransition struct {
+	StateName  string         `json:"state_name"`
+	Conditions *ISMConditions `json:"conditions,omitempty"`
+}
+
+// ISMConditions are the conditions of an ISM transition.
+type ISMConditions struct {
+	MinIndexAge string `json:"min_index_age,omitempty"`
+}
+
+// ISMTemplate applies the policy to the indices matching its patterns when they are created.
+type ISMTemplate struct {
+	IndexPatterns []string `json:"index_patterns"`
+	Priority      int      `json:"priority"`
+}
+
+// Equal tells whether two policies have the same managed fields.
+func (p ISMPolicy) Equal(other ISMPolicy) bool {
+	a, errA := json.Marshal(p)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// StoredISMPolicy is an ISM policy with the sequence number and primary term
+// of its stored version, which are required to update it.
+type StoredISMPolicy struct {
+	Policy      ISMPolicy `json:"policy"`
+	SeqNo       int64     `json:"_seq_no"`
+	PrimaryTerm int64     `json:"_primary_term"`
+}
+
+// ISMClient is a client used to manipulate OpenSearch Index State Management policies.
+type ISMClient struct {
+	Client
+}
+
+// GetPolicy returns the stored ISM policy, or nil if it does not exist
+func (i ISMClient) GetPolicy(name string) (*StoredISMPolicy, error) {
+	body, err := i.request(elasticRequest{
+		endpoint: "_plugins/_ism/policies/" + name,
+		method:   http.MethodGet,
+	})
+
+	var respError ResponseError
+	if errors.As(err, &respError) {
+		if respError.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ISM policy: %s, %w", name, err)
+	}
+	var policy StoredISMPolicy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse ISM policy: %s, %w", name, err)
+	}
+	return &policy, nil
+}
+
+// CreatePolicy creates an ISM policy
+func (i ISMClient) CreatePolicy(name string, policy ISMPolicy) error {
+	return i.putPolicy("_plugins/_ism/policies/"+name, name, policy)
+}
+
+// UpdatePolicy updates an ISM policy, provided that its stored version has the given sequence number and primary term
+func (i ISMClient) UpdatePolicy(name string, policy ISMPolicy, seqNo, primaryTerm int64) error {
+	endpoint := fmt.Sprintf("_plugins/_ism/policies/%s?if_seq_no=%d&if_primary_term=%d", name, seqNo, primaryTerm)
+	return i.putPolicy(endpoint, name, policy)
+}
+
+func (i ISMClient) putPolicy(endpoint, name string, policy ISMPolicy) error {
+	body, err := json.Marshal(map[string]ISMPolicy{"policy": policy})
+	if err != nil {
+		return err
+	}
+	_, err = i.request(elasticRequest{
+		endpoint: endpoint,
+		body:     body,
+		method:   http.MethodPut,
+	})
+	if err != nil {
+		var responseError ResponseError
+		if errors.As(err, &responseError) {
+			return responseError.prefixMessage(fmt.Sprintf("failed to put ISM policy: %s", name))
+		}
+		return fmt.Errorf("failed to put ISM policy: %s, %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const storedISMPolicy = `
+{
+	"_id": "jaeger-ism-policy",
+	"_version": 2,
+	"_seq_no": 7,
+	"_primary_term": 1,
+	"policy": {
+		"policy_id": "jaeger-ism-policy",
+		"description": "jaeger",
+		"last_updated_time": 1700000000000,
+		"schema_version": 17,
+		"default_state": "hot",
+		"states": [
+			{
+				"name": "hot",
+				"actions": [{"retry": {"count": 3}, "rollover": {"min_index_age": "1d"}}],
+				"transitions": [{"state_name": "delete", "conditions": {"min_index_age": "7d"}}]
+			},
+			{
+				"name": "delete",
+				"actions": [{"delete": {}}],
+				"transitions": []
+			}
+		],
+		"ism_template": [{"index_patterns": ["jaeger-span-*"], "priority": 100, "last_updated_time": 1700000000000}]
+	}
+}
+`
+
+func testISMPolicy() ISMPolicy {
+	return ISMPolicy{
+		Description:  "jaeger",
+		DefaultState: "hot",
+		States: []ISMState{
+			{
+				Name:        "hot",
+				Actions:     []ISMAction{{Rollover: &ISMRollover{MinIndexAge: "1d"}}},
+				Transitions: []ISMTransition{{StateName: "delete", Conditions: &ISMConditions{MinIndexAge: "7d"}}},
+			},
+			{
+				Name:    "delete",
+				Actions: []ISMAction{{Delete: &ISMDelete{}}},
+			},
+		},
+		ISMTemplate: []ISMTemplate{{IndexPatterns: []string{"jaeger-span-*"}, Priority: 100}},
+	}
+}
+
+func newTestISMClient(handler http.HandlerFunc) (*ISMClient, func()) {
+	testServer := httptest.NewServer(handler)
+	return &ISMClient{
+		Client: Client{
+			Client:    testServer.Client(),
+			Endpoint:  testServer.URL,
+			BasicAuth: "foobar",
+		},
+	}, testServer.Close
+}
+
+func TestGetISMPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseCode   int
+		response       string
+		errContains    string
+		expectedResult *StoredISMPolicy
+	}{
+		{
+			name:           "found",
+			responseCode:   http.StatusOK,
+			response:       storedISMPolicy,
+			expectedResult: &StoredISMPolicy{Policy: testISMPolicy(), SeqNo: 7, PrimaryTerm: 1},
+		},
+		{
+			name:         "not found",
+			responseCode: http.StatusNotFound,
+			response:     esErrResponse,
+		},
+		{
+			name:         "client error",
+			responseCode: http.StatusBadRequest,
+			response:     esErrResponse,
+			errContains:  "failed to get ISM policy: jaeger-ism-policy",
+		},
+		{
+			name:         "unmarshal error",
+			responseCode: http.StatusOK,
+			response:     "thisisaninvalidjson",
+			errContains:  "failed to parse ISM policy: jaeger-ism-policy",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, closeServer := newTestISMClient(func(res http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "/_plugins/_ism/policies/jaeger-ism-policy", req.URL.Path)
+				assert.Equal(t, http.MethodGet, req.Method)
+				assert.Equal(t, "Basic foobar", req.Header.Get("Authorization"))
+				res.WriteHeader(test.responseCode)
+				res.Write([]byte(test.response))
+			})
+			defer closeServer()
+
+			result, err := c.GetPolicy("jaeger-ism-policy")
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			if test.expectedResult == nil {
+				assert.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, test.expectedResult.SeqNo, result.SeqNo)
+			assert.Equal(t, test.expectedResult.PrimaryTerm, result.PrimaryTerm)
+			// fields set by OpenSearch, e.g. retry or last_updated_time, do not affect the comparison
+			assert.True(t, test.expectedResult.Policy.Equal(result.Policy))
+		})
+	}
+}
+
+func TestPutISMPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseCode  int
+		response      string
+		update        bool
+		expectedQuery string
+		errContains   string
+	}{
+		{
+			name:         "create",
+			responseCode: http.StatusCreated,
+			response:     `{"_id": "jaeger-ism-policy"}`,
+		},
+		{
+			name:          "update",
+			responseCode:  http.StatusOK,
+			response:      `{"_id": "jaeger-ism-policy"}`,
+			update:        true,
+			expectedQuery: "if_seq_no=7&if_primary_term=1",
+		},
+		{
+			name:         "create error",
+			responseCode: http.StatusConflict,
+			response:     esErrResponse,
+			errContains:  "failed to put ISM policy: jaeger-ism-policy",
+		},
+		{
+			name:          "update error",
+			responseCode:  http.StatusConflict,
+			response:      esErrResponse,
+			update:        true,
+			expectedQuery: "if_seq_no=7&if_primary_term=1",
+			errContains:   "failed to put ISM policy: jaeger-ism-policy",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, closeServer := newTestISMClient(func(res http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "/_plugins/_ism/policies/jaeger-ism-policy", req.URL.Path)
+				assert.Equal(t, test.expectedQuery, req.URL.RawQuery)
+				assert.Equal(t, http.MethodPut, req.Method)
+				assert.Equal(t, "Basic foobar", req.Header.Get("Authorization"))
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				var request map[string]ISMPolicy
+				assert.NoError(t, json.Unmarshal(body, &request))
+				assert.Equal(t, map[string]ISMPolicy{"policy": testISMPolicy()}, request)
+				res.WriteHeader(test.responseCode)
+				res.Write([]byte(test.response))
+			})
+			defer closeServer()
+
+			var err error
+			if test.update {
+				err = c.UpdatePolicy("jaeger-ism-policy", testISMPolicy(), 7, 1)
+			} else {
+				err = c.CreatePolicy("jaeger-ism-policy", testISMPolicy())
+			}
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestISMPolicyEqual(t *testing.T) {
+	other := testISMPolicy()
+	assert.True(t, testISMPolicy().Equal(other))
+	other.States[0].Actions[0].Rollover.MinSize = "50gb"
+	assert.False(t, testISMPolicy().Equal(other))
+}
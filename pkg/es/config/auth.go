@@ -0,0 +1,122 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+// credentialFile holds a credential read from a file. When reloadInterval is positive, the file
+// is read again once the interval has elapsed, so that rotated credentials such as Kubernetes
+// projected service account tokens are picked up. The last credential is kept if the file
+// cannot be read again.
+type credentialFile struct {
+	path           string
+	reloadInterval time.Duration
+	logger         *zap.Logger
+	now            func() time.Time
+
+	mu       sync.Mutex
+	value    string
+	loadedAt time.Time
+}
+
+func newCredentialFile(path string, reloadInterval time.Duration, logger *zap.Logger) (*credentialFile, error) {
+	value, err := loadTokenFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialFile{
+		path:           path,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+		now:            time.Now,
+		value:          value,
+		loadedAt:       time.Now(),
+	}, nil
+}
+
+// Get returns the credential, reading the file again if it is due.
+func (f *credentialFile) Get() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.reloadInterval <= 0 || f.now().Sub(f.loadedAt) < f.reloadInterval {
+		return f.value
+	}
+	f.loadedAt = f.now()
+	value, err := loadTokenFromFile(f.path)
+	if err != nil {
+		f.logger.Warn("Failed to reload Elasticsearch credential, using the previous one", zap.String("path", f.path), zap.Error(err))
+		return f.value
+	}
+	f.value = value
+	return f.value
+}
+
+// apiKeyRoundTripper authenticates the requests with an Elasticsearch API key,
+// which is the base64 encoding of the ID and the key joined by a colon.
+type apiKeyRoundTripper struct {
+	transport http.RoundTripper
+	apiKey    func() string
+}
+
+func (tr apiKeyRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if apiKey := tr.apiKey(); apiKey != "" {
+		r.Header.Set("Authorization", "ApiKey "+apiKey)
+	}
+	return tr.transport.RoundTrip(r)
+}
+
+// wrapAuthentication wraps the transport to authenticate the requests with the bearer token or
+// the API key, static or read from a file. A bearer token propagated in the context of a request,
+// if allowed, takes precedence over both.
+func (c *Configuration) wrapAuthentication(transport http.RoundTripper, logger *zap.Logger) (http.RoundTripper, error) {
+	if c.APIKey != "" && c.APIKeyFilePath != "" {
+		return nil, errors.New("both APIKey and APIKeyFilePath are set")
+	}
+	if c.TokenFilePath != "" && (c.APIKey != "" || c.APIKeyFilePath != "") {
+		return nil, errors.New("both a bearer token and an API key are set")
+	}
+	if c.AllowTokenFromContext {
+		// the inner round tripper overrides the credentials set by the outer ones
+		transport = bearertoken.RoundTripper{
+			Transport:       transport,
+			OverrideFromCtx: true,
+		}
+	}
+
+	if c.TokenFilePath != "" {
+		if c.AllowTokenFromContext {
+			logger.Warn("Token file and token propagation are both enabled, token from file won't be used")
+		}
+		tokenFile, err := newCredentialFile(c.TokenFilePath, c.TokenReloadInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		return bearertoken.RoundTripper{
+			Transport: transport,
+			TokenFn:   tokenFile.Get,
+		}, nil
+	}
+
+	apiKey := func() string { return c.APIKey }
+	if c.APIKeyFilePath != "" {
+		apiKeyFile, err := newCredentialFile(c.APIKeyFilePath, c.TokenReloadInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		apiKey = apiKeyFile.Get
+	}
+	if c.APIKey != "" || c.APIKeyFilePath != "" {
+		return apiKeyRoundTripper{transport: transport, apiKey: apiKey}, nil
+	}
+	return transport, nil
+}
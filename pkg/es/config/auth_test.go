@@ -0,0 +1,168 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+func writeCredential(t *testing.T, path, value string) {
+	require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+}
+
+func TestCredentialFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeCredential(t, path, "token1")
+	f, err := newCredentialFile(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+	assert.Equal(t, "token1", f.Get())
+
+	writeCredential(t, path, "token2")
+	assert.Equal(t, "token1", f.Get(), "the file is not read again before the interval")
+	now = now.Add(time.Minute)
+	assert.Equal(t, "token2", f.Get())
+
+	require.NoError(t, os.Remove(path))
+	now = now.Add(time.Minute)
+	assert.Equal(t, "token2", f.Get(), "the previous token is kept if the file cannot be read")
+
+	_, err = newCredentialFile(path, time.Minute, zap.NewNop())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCredentialFileWithoutReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeCredential(t, path, "token1")
+	f, err := newCredentialFile(path, 0, zap.NewNop())
+	require.NoError(t, err)
+	f.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+
+	writeCredential(t, path, "token2")
+	assert.Equal(t, "token1", f.Get())
+}
+
+func TestGetHTTPRoundTripperAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath, apiKeyPath := filepath.Join(dir, "token"), filepath.Join(dir, "api-key")
+	writeCredential(t, tokenPath, "tokenFromFile")
+	writeCredential(t, apiKeyPath, "apiKeyFromFile")
+
+	testCases := []struct {
+		name          string
+		config        Configuration
+		ctx           context.Context
+		authorization string
+	}{
+		{
+			name:   "no authentication",
+			config: Configuration{},
+			ctx:    context.Background(),
+		},
+		{
+			name:          "token file",
+			config:        Configuration{TokenFilePath: tokenPath},
+			ctx:           context.Background(),
+			authorization: "Bearer tokenFromFile",
+		},
+		{
+			name:          "token from context over token file",
+			config:        Configuration{TokenFilePath: tokenPath, AllowTokenFromContext: true},
+			ctx:           bearertoken.ContextWithBearerToken(context.Background(), "tokenFromContext"),
+			authorization: "Bearer tokenFromContext",
+		},
+		{
+			name:          "API key",
+			config:        Configuration{APIKey: "apiKey"},
+			ctx:           context.Background(),
+			authorization: "ApiKey apiKey",
+		},
+		{
+			name:          "API key file",
+			config:        Configuration{APIKeyFilePath: apiKeyPath, TokenReloadInterval: time.Minute},
+			ctx:           context.Background(),
+			authorization: "ApiKey apiKeyFromFile",
+		},
+		{
+			name:          "token from context over API key",
+			config:        Configuration{APIKey: "apiKey", AllowTokenFromContext: true},
+			ctx:           bearertoken.ContextWithBearerToken(context.Background(), "tokenFromContext"),
+			authorization: "Bearer tokenFromContext",
+		},
+		{
+			name:          "API key without token in context",
+			config:        Configuration{APIKey: "apiKey", AllowTokenFromContext: true},
+			ctx:           context.Background(),
+			authorization: "ApiKey apiKey",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+			}))
+			defer server.Close()
+
+			transport, err := GetHTTPRoundTripper(&tc.config, zap.NewNop())
+			require.NoError(t, err)
+			client := &http.Client{Transport: transport}
+			defer client.CloseIdleConnections()
+			req, err := http.NewRequestWithContext(tc.ctx, http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tc.authorization, authorization)
+		})
+	}
+}
+
+func TestGetHTTPRoundTripperAuthenticationErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Configuration
+		err    string
+	}{
+		{
+			name:   "API key and API key file",
+			config: Configuration{APIKey: "apiKey", APIKeyFilePath: "/etc/jaeger/api-key"},
+			err:    "both APIKey and APIKeyFilePath are set",
+		},
+		{
+			name:   "token file and API key",
+			config: Configuration{TokenFilePath: "/etc/jaeger/token", APIKey: "apiKey"},
+			err:    "both a bearer token and an API key are set",
+		},
+		{
+			name:   "missing token file",
+			config: Configuration{TokenFilePath: filepath.Join(t.TempDir(), "token")},
+			err:    "no such file or directory",
+		},
+		{
+			name:   "missing API key file",
+			config: Configuration{APIKeyFilePath: filepath.Join(t.TempDir(), "api-key")},
+			err:    "no such file or directory",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := GetHTTPRoundTripper(&tc.config, zap.NewNop())
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olivere/elastic"
+	"go.uber.org/zap"
+)
+
+// ClusterValidationConfig configures the checks of the Elasticsearch or OpenSearch cluster made when
+// the client is created, so that a cluster Jaeger cannot write to is reported at startup with the
+// reason, rather than by bulk requests failing at runtime.
+type ClusterValidationConfig struct {
+	// Enabled runs the checks of the cluster version, write blocks, disk usage and index privileges.
+	Enabled bool `mapstructure:"enabled"`
+	// Fail makes the startup fail when a check fails, instead of logging a warning.
+	Fail bool `mapstructure:"fail"`
+}
+
+// supportedVersions are the major Elasticsearch versions Jaeger has index mappings for,
+// OpenSearch 1.x and 2.x being handled as Elasticsearch 7.x.
+var supportedVersions = []uint{7, 8}
+
+// requiredIndexPrivileges are the privileges needed on the Jaeger indices to create them and write
+// and read the spans.
+var requiredIndexPrivileges = []string{"create_index", "write", "read"}
+
+// validateCluster checks that Jaeger can write to the cluster, returning the problems found.
+func validateCluster(ctx context.Context, client *elastic.Client, c *Configuration, logger *zap.Logger) error {
+	errs := []error{checkVersion(c.Version)}
+	for _, check := range []func(context.Context, *elastic.Client, *Configuration) error{
+		checkBlocks,
+		checkDiskWatermark,
+	} {
+		errs = append(errs, check(ctx, client, c))
+	}
+	if err := checkIndexPrivileges(ctx, client, c); err != nil {
+		if !errors.Is(err, errPrivilegesUnavailable) {
+			errs = append(errs, err)
+		} else {
+			logger.Info("Skipping the check of the Elasticsearch index privileges", zap.Error(err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkVersion(version uint) error {
+	for _, v := range supportedVersions {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("version %d of Elasticsearch is not supported, use a cluster of version %v or OpenSearch 1.x or 2.x", version, supportedVersions)
+}
+
+// jaegerIndexPattern returns the pattern matching the Jaeger indices of the configuration.
+func (c *Configuration) jaegerIndexPattern() string {
+	prefix := c.IndexPrefix
+	if prefix != "" && !strings.HasSuffix(prefix, "-") {
+		prefix += "-"
+	}
+	return prefix + "jaeger-*"
+}
+
+// checkBlocks reports the cluster-wide blocks and the write blocks of the Jaeger indices, which
+// Elasticsearch sets e.g. when a node exceeds the flood stage disk watermark.
+func checkBlocks(ctx context.Context, client *elastic.Client, c *Configuration) error {
+	var state struct {
+		Blocks struct {
+			Global  map[string]clusterBlock            `json:"global"`
+			Indices map[string]map[string]clusterBlock `json:"indices"`
+		} `json:"blocks"`
+	}
+	if err := getJSON(ctx, client, "/_cluster/state/blocks", nil, &state); err != nil {
+		return fmt.Errorf("cannot check the cluster blocks: %w", err)
+	}
+	var errs []error
+	for _, id := range sortedKeys(state.Blocks.Global) {
+		errs = append(errs, fmt.Errorf("the cluster is blocked: %s", state.Blocks.Global[id].Description))
+	}
+	prefix := strings.TrimSuffix(c.jaegerIndexPattern(), "*")
+	for _, index := range sortedKeys(state.Blocks.Indices) {
+		if !strings.HasPrefix(index, prefix) {
+			continue
+		}
+		for _, id := range sortedKeys(state.Blocks.Indices[index]) {
+			if block := state.Blocks.Indices[index][id]; block.blocksWrites() {
+				errs = append(errs, fmt.Errorf("index %s is blocked: %s", index, block.Description))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type clusterBlock struct {
+	Description string   `json:"description"`
+	Levels      []string `json:"levels"`
+}
+
+func (b clusterBlock) blocksWrites() bool {
+	for _, level := range b.Levels {
+		if level == "write" || level == "metadata_write" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDiskWatermark reports the nodes whose disk usage reached the flood stage watermark, at which
+// Elasticsearch makes the indices with a shard on the node read-only. Only watermarks set as
+// percentages are checked.
+func checkDiskWatermark(ctx context.Context, client *elastic.Client, _ *Configuration) error {
+	var settings struct {
+		Persistent map[string]any `json:"persistent"`
+		Transient  map[string]any `json:"transient"`
+		Defaults   map[string]any `json:"defaults"`
+	}
+	params := url.Values{"include_defaults": {"true"}, "flat_settings": {"true"}}
+	if err := getJSON(ctx, client, "/_cluster/settings", params, &settings); err != nil {
+		return fmt.Errorf("cannot check the disk watermark: %w", err)
+	}
+	const floodStage = "cluster.routing.allocation.disk.watermark.flood_stage"
+	watermark, _ := settings.Defaults[floodStage].(string)
+	for _, s := range []map[string]any{settings.Persistent, settings.Transient} {
+		if v, ok := s[floodStage].(string); ok {
+			watermark = v
+		}
+	}
+	limit, err := strconv.ParseFloat(strings.TrimSuffix(watermark, "%"), 64)
+	if err != nil || !strings.HasSuffix(watermark, "%") {
+		return nil
+	}
+	var nodes []struct {
+		Node        string `json:"node"`
+		DiskPercent string `json:"disk.percent"`
+	}
+	if err := getJSON(ctx, client, "/_cat/allocation", url.Values{"format": {"json"}}, &nodes); err != nil {
+		return fmt.Errorf("cannot check the disk usage: %w", err)
+	}
+	var errs []error
+	for _, node := range nodes {
+		usage, err := strconv.ParseFloat(node.DiskPercent, 64)
+		if err == nil && usage >= limit {
+			errs = append(errs, fmt.Errorf("the disk usage of node %s is %s%%, above the flood stage watermark %s", node.Node, node.DiskPercent, watermark))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var errPrivilegesUnavailable = errors.New("the security API is not available")
+
+// checkIndexPrivileges reports the privileges on the Jaeger indices the user lacks. The check is
+// skipped when the cluster does not have the security API, e.g. when security is disabled.
+func checkIndexPrivileges(ctx context.Context, client *elastic.Client, c *Configuration) error {
+	pattern := c.jaegerIndexPattern()
+	resp, err := client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodPost,
+		Path:   "/_security/user/_has_privileges",
+		Body: map[string]any{
+			"index": []map[string]any{{"names": []string{pattern}, "privileges": requiredIndexPrivileges}},
+		},
+		IgnoreErrors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot check the index privileges: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", errPrivilegesUnavailable, resp.StatusCode)
+	}
+	var privileges struct {
+		HasAllRequested bool                       `json:"has_all_requested"`
+		Index           map[string]map[string]bool `json:"index"`
+	}
+	if err := json.Unmarshal(resp.Body, &privileges); err != nil {
+		return fmt.Errorf("cannot parse the index privileges: %w", err)
+	}
+	if privileges.HasAllRequested {
+		return nil
+	}
+	var missing []string
+	for _, privilege := range requiredIndexPrivileges {
+		if !privileges.Index[pattern][privilege] {
+			missing = append(missing, privilege)
+		}
+	}
+	return fmt.Errorf("the user lacks the privileges %v on the indices %s", missing, pattern)
+}
+
+func getJSON(ctx context.Context, client *elastic.Client, path string, params url.Values, v any) error {
+	resp, err := client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   path,
+		Params: params,
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp.Body, v)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const healthyClusterBlocks = `{"blocks": {"indices": {"other-index": {"8": {"description": "index read-only / allow delete (api)", "levels": ["write"]}}}}}`
+
+func newClusterServer(t *testing.T, responses map[string]string, privilegesStatus int) *elastic.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_security/user/_has_privileges" && privilegesStatus != http.StatusOK {
+			w.WriteHeader(privilegesStatus)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	client, err := elastic.NewClient(elastic.SetURL(server.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	require.NoError(t, err)
+	return client
+}
+
+func TestValidateCluster(t *testing.T) {
+	healthy := map[string]string{
+		"/_cluster/state/blocks":          healthyClusterBlocks,
+		"/_cluster/settings":              `{"persistent": {}, "transient": {}, "defaults": {"cluster.routing.allocation.disk.watermark.flood_stage": "95%"}}`,
+		"/_cat/allocation":                `[{"node": "es-0", "disk.percent": "42"}]`,
+		"/_security/user/_has_privileges": `{"has_all_requested": true}`,
+	}
+	client := newClusterServer(t, healthy, http.StatusOK)
+	require.NoError(t, validateCluster(context.Background(), client, &Configuration{Version: 7}, zap.NewNop()))
+
+	// the security API is missing when security is disabled
+	client = newClusterServer(t, healthy, http.StatusInternalServerError)
+	require.NoError(t, validateCluster(context.Background(), client, &Configuration{Version: 8}, zap.NewNop()))
+
+	unhealthy := map[string]string{
+		"/_cluster/state/blocks": `{"blocks": {
+			"global": {"12": {"description": "disk usage exceeded flood-stage watermark", "levels": ["write"]}},
+			"indices": {"prod-jaeger-span-2024-05-01": {"8": {"description": "index read-only / allow delete (api)", "levels": ["write", "metadata_write"]}}}
+		}}`,
+		"/_cluster/settings":              `{"persistent": {"cluster.routing.allocation.disk.watermark.flood_stage": "90%"}, "defaults": {"cluster.routing.allocation.disk.watermark.flood_stage": "95%"}}`,
+		"/_cat/allocation":                `[{"node": "es-0", "disk.percent": "42"}, {"node": "es-1", "disk.percent": "91"}]`,
+		"/_security/user/_has_privileges": `{"has_all_requested": false, "index": {"prod-jaeger-*": {"create_index": false, "write": true, "read": true}}}`,
+	}
+	client = newClusterServer(t, unhealthy, http.StatusOK)
+	err := validateCluster(context.Background(), client, &Configuration{Version: 9, IndexPrefix: "prod"}, zap.NewNop())
+	require.Error(t, err)
+	assert.Equal(t, `version 9 of Elasticsearch is not supported, use a cluster of version [7 8] or OpenSearch 1.x or 2.x
+the cluster is blocked: disk usage exceeded flood-stage watermark
+index prod-jaeger-span-2024-05-01 is blocked: index read-only / allow delete (api)
+the disk usage of node es-1 is 91%, above the flood stage watermark 90%
+the user lacks the privileges [create_index] on the indices prod-jaeger-*`, err.Error())
+}
+
+func TestValidateClusterUnreachable(t *testing.T) {
+	client := newClusterServer(t, map[string]string{}, http.StatusOK)
+	err := validateCluster(context.Background(), client, &Configuration{Version: 7}, zap.NewNop())
+	require.ErrorContains(t, err, "cannot check the cluster blocks")
+	require.ErrorContains(t, err, "cannot check the disk watermark")
+}
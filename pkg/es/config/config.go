@@ -36,7 +36,6 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zapgrpc"
 
-	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	eswrapper "github.com/jaegertracing/jaeger/pkg/es/wrapper"
@@ -52,6 +51,9 @@ type Configuration struct {
 	Password                       string         `mapstructure:"password" json:"-"`
 	TokenFilePath                  string         `mapstructure:"token_file"`
 	PasswordFilePath               string         `mapstructure:"password_file"`
+	APIKey                         string         `mapstructure:"api_key" json:"-"`
+	APIKeyFilePath                 string         `mapstructure:"api_key_file"`
+	TokenReloadInterval            time.Duration  `mapstructure:"token_reload_interval"`
 	AllowTokenFromContext          bool           `mapstructure:"-"`
 	Sniffer                        bool           `mapstructure:"sniffer"` // https://github.com/olivere/elastic/wiki/Sniffing
 	SnifferTLSEnabled              bool           `mapstructure:"sniffer_tls_enabled"`
@@ -78,14 +80,59 @@ type Configuration struct {
 	ServiceCacheTTL                time.Duration  `mapstructure:"service_cache_ttl"`
 	AdaptiveSamplingLookback       time.Duration  `mapstructure:"-"`
 	Tags                           TagsAsFields   `mapstructure:"tags_as_fields"`
+	DocValuesOnly                  DocValuesOnly  `mapstructure:"doc_values_only"`
 	Enabled                        bool           `mapstructure:"-"`
 	TLS                            tlscfg.Options `mapstructure:"tls"`
 	UseReadWriteAliases            bool           `mapstructure:"use_aliases"`
+	BootstrapAliases               bool           `mapstructure:"bootstrap_aliases"`
+	UseRetentionIndices            bool           `mapstructure:"use_retention_indices"`
+	RouteByTraceID                 bool           `mapstructure:"route_by_trace_id"`
 	CreateIndexTemplates           bool           `mapstructure:"create_mappings"`
 	UseILM                         bool           `mapstructure:"use_ilm"`
+	UseComposableTemplates         bool           `mapstructure:"use_composable_templates"`
+	ComponentTemplates             []string       `mapstructure:"component_templates"`
+	VerifyIndexTemplates           bool           `mapstructure:"verify_index_templates"`
 	Version                        uint           `mapstructure:"version"`
 	LogLevel                       string         `mapstructure:"log_level"`
 	SendGetBodyAs                  string         `mapstructure:"send_get_body_as"`
+
+	HedgedReads       HedgedReadsConfig       `mapstructure:"hedged_reads"`
+	SpanLogsLimit     SpanLogsLimitConfig     `mapstructure:"span_logs_limit"`
+	SearchTimeWindow  SearchTimeWindowConfig  `mapstructure:"search_time_window"`
+	ClusterValidation ClusterValidationConfig `mapstructure:"cluster_validation"`
+}
+
+// HedgedReadsConfig configures hedged trace reads, which send a duplicate search for a trace
+// when the previous one is not answered within Delay, so that a single slow shard copy or node
+// does not dominate the latency of trace reads. The first answer wins and the other searches are cancelled.
+type HedgedReadsConfig struct {
+	// Delay is how long a trace search may be outstanding before a duplicate is sent.
+	Delay time.Duration `mapstructure:"delay"`
+	// MaxAttempts is the maximum number of concurrent searches for a trace, including the first one.
+	// Hedged reads are disabled with less than two attempts.
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// SpanLogsLimitConfig limits the number of logs (span events) stored with each span, so that spans
+// with thousands of logs do not bloat the span indices. When a span has more logs than KeepFirst and
+// KeepLast together, only its first KeepFirst and last KeepLast logs are written, along with the logs
+// recording errors, and a warning about the dropped logs is added to the span. Zero values disable the limit.
+type SpanLogsLimitConfig struct {
+	// KeepFirst is the number of earliest logs of a span that are kept.
+	KeepFirst int `mapstructure:"keep_first"`
+	// KeepLast is the number of latest logs of a span that are kept.
+	KeepLast int `mapstructure:"keep_last"`
+}
+
+// SearchTimeWindowConfig limits the time range of trace searches, so that a search over the
+// last 30 days does not fan out to a span index per day. A search over a longer time range
+// is rejected, or with Clamp, narrowed to its most recent Max and answered with a warning
+// added to the found traces. A zero Max disables the limit.
+type SearchTimeWindowConfig struct {
+	// Max is the longest time range of a trace search.
+	Max time.Duration `mapstructure:"max"`
+	// Clamp narrows the longer searches instead of rejecting them.
+	Clamp bool `mapstructure:"clamp"`
 }
 
 // TagsAsFields holds configuration for tag schema.
@@ -102,6 +149,51 @@ type TagsAsFields struct {
 	Include string `mapstructure:"include"`
 }
 
+// Names of the span field groups accepted by ParseDocValuesOnly.
+const (
+	DocValuesOnlyLogs        = "logs"
+	DocValuesOnlyReferences  = "references"
+	DocValuesOnlyTags        = "tags"
+	DocValuesOnlyProcessTags = "process-tags"
+)
+
+// DocValuesOnly selects groups of span fields that are mapped with "index": false,
+// keeping only doc_values and _source. This substantially shrinks span indices,
+// but spans can no longer be searched by the values of these fields.
+// Tags stored as object fields (see TagsAsFields) are always indexed.
+type DocValuesOnly struct {
+	// Logs disables indexing of span log fields
+	Logs bool `mapstructure:"logs"`
+	// References disables indexing of span references
+	References bool `mapstructure:"references"`
+	// Tags disables indexing of span tags stored as nested objects
+	Tags bool `mapstructure:"tags"`
+	// ProcessTags disables indexing of process tags stored as nested objects
+	ProcessTags bool `mapstructure:"process_tags"`
+}
+
+// ParseDocValuesOnly parses a comma-separated list of span field group names.
+func ParseDocValuesOnly(groups string) (DocValuesOnly, error) {
+	var d DocValuesOnly
+	for _, group := range strings.Split(groups, ",") {
+		switch strings.TrimSpace(group) {
+		case "":
+		case DocValuesOnlyLogs:
+			d.Logs = true
+		case DocValuesOnlyReferences:
+			d.References = true
+		case DocValuesOnlyTags:
+			d.Tags = true
+		case DocValuesOnlyProcessTags:
+			d.ProcessTags = true
+		default:
+			return DocValuesOnly{}, fmt.Errorf("unknown doc-values-only field group %q, expected one of %s, %s, %s, %s",
+				group, DocValuesOnlyLogs, DocValuesOnlyReferences, DocValuesOnlyTags, DocValuesOnlyProcessTags)
+		}
+	}
+	return d, nil
+}
+
 // NewClient creates a new ElasticSearch client
 func NewClient(c *Configuration, logger *zap.Logger, metricsFactory metrics.Factory) (es.Client, error) {
 	if len(c.Servers) < 1 {
@@ -193,6 +285,15 @@ func NewClient(c *Configuration, logger *zap.Logger, metricsFactory metrics.Fact
 		c.Version = uint(esVersion)
 	}
 
+	if c.ClusterValidation.Enabled {
+		if err := validateCluster(context.Background(), rawClient, c, logger); err != nil {
+			if c.ClusterValidation.Fail {
+				return nil, fmt.Errorf("failed to validate the Elasticsearch cluster: %w", err)
+			}
+			logger.Warn("Elasticsearch cluster validation failed, writing spans may fail", zap.Error(err))
+		}
+	}
+
 	var rawClientV8 *esV8.Client
 	if c.Version >= 8 {
 		rawClientV8, err = newElasticsearchV8(c, logger)
@@ -201,7 +302,7 @@ func NewClient(c *Configuration, logger *zap.Logger, metricsFactory metrics.Fact
 		}
 	}
 
-	return eswrapper.WrapESClient(rawClient, bulkProc, c.Version, rawClientV8), nil
+	return eswrapper.WrapESClient(rawClient, bulkProc, c.Version, rawClientV8, c.UseComposableTemplates), nil
 }
 
 func newElasticsearchV8(c *Configuration, logger *zap.Logger) (*esV8.Client, error) {
@@ -280,6 +381,15 @@ func (c *Configuration) ApplyDefaults(source *Configuration) {
 	if c.Tags.File == "" {
 		c.Tags.File = source.Tags.File
 	}
+	if !c.UseComposableTemplates {
+		c.UseComposableTemplates = source.UseComposableTemplates
+	}
+	if len(c.ComponentTemplates) == 0 {
+		c.ComponentTemplates = source.ComponentTemplates
+	}
+	if c.DocValuesOnly == (DocValuesOnly{}) {
+		c.DocValuesOnly = source.DocValuesOnly
+	}
 	if c.MaxDocCount == 0 {
 		c.MaxDocCount = source.MaxDocCount
 	}
@@ -289,6 +399,15 @@ func (c *Configuration) ApplyDefaults(source *Configuration) {
 	if c.SendGetBodyAs == "" {
 		c.SendGetBodyAs = source.SendGetBodyAs
 	}
+	if c.HedgedReads == (HedgedReadsConfig{}) {
+		c.HedgedReads = source.HedgedReads
+	}
+	if c.SpanLogsLimit == (SpanLogsLimitConfig{}) {
+		c.SpanLogsLimit = source.SpanLogsLimit
+	}
+	if c.SearchTimeWindow == (SearchTimeWindowConfig{}) {
+		c.SearchTimeWindow = source.SearchTimeWindow
+	}
 }
 
 // GetIndexRolloverFrequencySpansDuration returns jaeger-span index rollover frequency duration
@@ -431,10 +550,10 @@ func GetHTTPRoundTripper(c *Configuration, logger *zap.Logger) (http.RoundTrippe
 		if err != nil {
 			return nil, err
 		}
-		return &http.Transport{
+		return c.wrapAuthentication(&http.Transport{
 			Proxy:           http.ProxyFromEnvironment,
 			TLSClientConfig: ctlsConfig,
-		}, nil
+		}, logger)
 	}
 	var transport http.RoundTripper
 	httpTransport := &http.Transport{
@@ -451,27 +570,17 @@ func GetHTTPRoundTripper(c *Configuration, logger *zap.Logger) (http.RoundTrippe
 		transport = httpTransport
 	}
 
-	token := ""
-	if c.TokenFilePath != "" {
-		if c.AllowTokenFromContext {
-			logger.Warn("Token file and token propagation are both enabled, token from file won't be used")
-		}
-		tokenFromFile, err := loadTokenFromFile(c.TokenFilePath)
-		if err != nil {
-			return nil, err
-		}
-		token = tokenFromFile
-	}
-	if token != "" || c.AllowTokenFromContext {
-		transport = bearertoken.RoundTripper{
-			Transport:       httpTransport,
-			OverrideFromCtx: c.AllowTokenFromContext,
-			StaticToken:     token,
-		}
+	if c.authenticatesRequests() {
+		return c.wrapAuthentication(httpTransport, logger)
 	}
 	return transport, nil
 }
 
+// authenticatesRequests returns true if the requests carry a bearer token or an API key.
+func (c *Configuration) authenticatesRequests() bool {
+	return c.TokenFilePath != "" || c.AllowTokenFromContext || c.APIKey != "" || c.APIKeyFilePath != ""
+}
+
 func loadTokenFromFile(path string) (string, error) {
 	b, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
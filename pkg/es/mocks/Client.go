@@ -17,6 +17,26 @@ type Client struct {
 	mock.Mock
 }
 
+// AliasExists provides a mock function with given fields: alias
+func (_m *Client) AliasExists(alias string) es.IndicesExistsService {
+	ret := _m.Called(alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AliasExists")
+	}
+
+	var r0 es.IndicesExistsService
+	if rf, ok := ret.Get(0).(func(string) es.IndicesExistsService); ok {
+		r0 = rf(alias)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesExistsService)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Client) Close() error {
 	ret := _m.Called()
@@ -35,6 +55,58 @@ func (_m *Client) Close() error {
 	return r0
 }
 
+// Count provides a mock function with given fields: indices
+func (_m *Client) Count(indices ...string) es.CountService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 es.CountService
+	if rf, ok := ret.Get(0).(func(...string) es.CountService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.CountService)
+		}
+	}
+
+	return r0
+}
+
+// DeleteByQuery provides a mock function with given fields: indices
+func (_m *Client) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByQuery")
+	}
+
+	var r0 es.DeleteByQueryService
+	if rf, ok := ret.Get(0).(func(...string) es.DeleteByQueryService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DeleteByQueryService)
+		}
+	}
+
+	return r0
+}
+
 // CreateIndex provides a mock function with given fields: index
 func (_m *Client) CreateIndex(index string) es.IndicesCreateService {
 	ret := _m.Called(index)
@@ -95,6 +167,46 @@ func (_m *Client) DeleteIndex(index string) es.IndicesDeleteService {
 	return r0
 }
 
+// Document provides a mock function with given fields: index, id
+func (_m *Client) Document(index string, id string) es.DocumentService {
+	ret := _m.Called(index, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Document")
+	}
+
+	var r0 es.DocumentService
+	if rf, ok := ret.Get(0).(func(string, string) es.DocumentService); ok {
+		r0 = rf(index, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DocumentService)
+		}
+	}
+
+	return r0
+}
+
+// GetTemplate provides a mock function with given fields: id
+func (_m *Client) GetTemplate(id string) es.TemplateGetService {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplate")
+	}
+
+	var r0 es.TemplateGetService
+	if rf, ok := ret.Get(0).(func(string) es.TemplateGetService); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.TemplateGetService)
+		}
+	}
+
+	return r0
+}
+
 // GetVersion provides a mock function with given fields:
 func (_m *Client) GetVersion() uint {
 	ret := _m.Called()
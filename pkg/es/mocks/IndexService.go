@@ -82,6 +82,26 @@ func (_m *IndexService) Index(index string) es.IndexService {
 	return r0
 }
 
+// Routing provides a mock function with given fields: routing
+func (_m *IndexService) Routing(routing string) es.IndexService {
+	ret := _m.Called(routing)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Routing")
+	}
+
+	var r0 es.IndexService
+	if rf, ok := ret.Get(0).(func(string) es.IndexService); ok {
+		r0 = rf(routing)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndexService)
+		}
+	}
+
+	return r0
+}
+
 // Type provides a mock function with given fields: typ
 func (_m *IndexService) Type(typ string) es.IndexService {
 	ret := _m.Called(typ)
@@ -17,7 +17,11 @@ package eswrapper
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	esV8 "github.com/elastic/go-elasticsearch/v8"
@@ -31,10 +35,11 @@ import (
 
 // ClientWrapper is a wrapper around elastic.Client
 type ClientWrapper struct {
-	client      *elastic.Client
-	bulkService *elastic.BulkProcessor
-	esVersion   uint
-	clientV8    *esV8.Client
+	client              *elastic.Client
+	bulkService         *elastic.BulkProcessor
+	esVersion           uint
+	clientV8            *esV8.Client
+	composableTemplates bool
 }
 
 // GetVersion returns the ElasticSearch Version
@@ -43,12 +48,15 @@ func (c ClientWrapper) GetVersion() uint {
 }
 
 // WrapESClient creates a ESClient out of *elastic.Client.
-func WrapESClient(client *elastic.Client, s *elastic.BulkProcessor, esVersion uint, clientV8 *esV8.Client) ClientWrapper {
+// If composableTemplates is true, Elasticsearch 7 templates are created with the
+// composable _index_template API, which is always used with Elasticsearch 8.
+func WrapESClient(client *elastic.Client, s *elastic.BulkProcessor, esVersion uint, clientV8 *esV8.Client, composableTemplates bool) ClientWrapper {
 	return ClientWrapper{
-		client:      client,
-		bulkService: s,
-		esVersion:   esVersion,
-		clientV8:    clientV8,
+		client:              client,
+		bulkService:         s,
+		esVersion:           esVersion,
+		clientV8:            clientV8,
+		composableTemplates: composableTemplates,
 	}
 }
 
@@ -57,6 +65,15 @@ func (c ClientWrapper) IndexExists(index string) es.IndicesExistsService {
 	return WrapESIndicesExistsService(c.client.IndexExists(index))
 }
 
+// AliasExists returns a service checking that an alias exists. Unlike IndexExists,
+// it returns false for an index with the name of the alias.
+func (c ClientWrapper) AliasExists(alias string) es.IndicesExistsService {
+	return AliasExistsWrapper{
+		client: c.client,
+		alias:  alias,
+	}
+}
+
 // CreateIndex calls this function to internal client.
 func (c ClientWrapper) CreateIndex(index string) es.IndicesCreateService {
 	return WrapESIndicesCreateService(c.client.CreateIndex(index))
@@ -75,9 +92,33 @@ func (c ClientWrapper) CreateTemplate(ttype string) es.TemplateCreateService {
 			templateName: ttype,
 		}
 	}
+	if c.composableTemplates {
+		return TemplateCreatorWrapperComposable{
+			client:       c.client,
+			templateName: ttype,
+		}
+	}
 	return WrapESTemplateCreateService(c.client.IndexPutTemplate(ttype))
 }
 
+// GetTemplate calls this function to internal client.
+func (c ClientWrapper) GetTemplate(name string) es.TemplateGetService {
+	return TemplateGetterWrapper{
+		client:       c.client,
+		templateName: name,
+		composable:   c.esVersion >= 8 || c.composableTemplates,
+	}
+}
+
+// Document returns a service for the document of the index with the id.
+func (c ClientWrapper) Document(index, id string) es.DocumentService {
+	return DocumentWrapper{
+		client: c.client,
+		index:  index,
+		id:     id,
+	}
+}
+
 // Index calls this function to internal client.
 func (c ClientWrapper) Index() es.IndexService {
 	r := elastic.NewBulkIndexRequest()
@@ -102,6 +143,16 @@ func (c ClientWrapper) MultiSearch() es.MultiSearchService {
 	return WrapESMultiSearchService(multiSearchService)
 }
 
+// Count calls this function to internal client.
+func (c ClientWrapper) Count(indices ...string) es.CountService {
+	return WrapESCountService(c.client.Count(indices...))
+}
+
+// DeleteByQuery calls this function to internal client.
+func (c ClientWrapper) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(c.client.DeleteByQuery(indices...))
+}
+
 // Close closes ESClient and flushes all data to the storage.
 func (c ClientWrapper) Close() error {
 	c.client.Stop()
@@ -212,6 +263,188 @@ func (c TemplateCreatorWrapperV8) Do(context.Context) (*elastic.IndicesPutTempla
 
 // ---
 
+// TemplateCreatorWrapperComposable implements es.TemplateCreateService
+// with the composable index template API of Elasticsearch 7.8+.
+type TemplateCreatorWrapperComposable struct {
+	client          *elastic.Client
+	templateName    string
+	templateMapping string
+}
+
+// Body adds mapping to the future request.
+func (c TemplateCreatorWrapperComposable) Body(mapping string) es.TemplateCreateService {
+	cc := c // clone
+	cc.templateMapping = mapping
+	return cc
+}
+
+// Do executes Put Index Template command.
+func (c TemplateCreatorWrapperComposable) Do(ctx context.Context) (*elastic.IndicesPutTemplateResponse, error) {
+	_, err := c.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodPut,
+		Path:   "/_index_template/" + url.PathEscape(c.templateName),
+		Body:   c.templateMapping,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating index template %s: %w", c.templateName, err)
+	}
+	return nil, nil // no response expected by span writer
+}
+
+// ---
+
+// TemplateGetterWrapper implements es.TemplateGetService.
+type TemplateGetterWrapper struct {
+	client       *elastic.Client
+	templateName string
+	composable   bool
+}
+
+// Do executes Get Template or Get Index Template command.
+func (c TemplateGetterWrapper) Do(ctx context.Context) (json.RawMessage, error) {
+	path := "/_template/"
+	if c.composable {
+		path = "/_index_template/"
+	}
+	resp, err := c.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodGet,
+		Path:         path + url.PathEscape(c.templateName),
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting index template %s: %w", c.templateName, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if c.composable {
+		var body struct {
+			IndexTemplates []struct {
+				Name          string          `json:"name"`
+				IndexTemplate json.RawMessage `json:"index_template"`
+			} `json:"index_templates"`
+		}
+		if err := json.Unmarshal(resp.Body, &body); err != nil {
+			return nil, fmt.Errorf("error parsing index template %s: %w", c.templateName, err)
+		}
+		for _, t := range body.IndexTemplates {
+			if t.Name == c.templateName {
+				return t.IndexTemplate, nil
+			}
+		}
+		return nil, nil
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return nil, fmt.Errorf("error parsing index template %s: %w", c.templateName, err)
+	}
+	return body[c.templateName], nil
+}
+
+// ---
+
+// DocumentWrapper implements es.DocumentService.
+type DocumentWrapper struct {
+	client *elastic.Client
+	index  string
+	id     string
+}
+
+func (d DocumentWrapper) path(api string) string {
+	return "/" + url.PathEscape(d.index) + "/" + api + "/" + url.PathEscape(d.id)
+}
+
+func versionParams(version es.DocumentVersion) url.Values {
+	return url.Values{
+		"if_seq_no":       {strconv.FormatInt(version.SeqNo, 10)},
+		"if_primary_term": {strconv.FormatInt(version.PrimaryTerm, 10)},
+	}
+}
+
+// Get executes Get Document command.
+func (d DocumentWrapper) Get(ctx context.Context) (*es.VersionedDocument, error) {
+	resp, err := d.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodGet,
+		Path:         d.path("_doc"),
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting document %s of index %s: %w", d.id, d.index, err)
+	}
+	var body struct {
+		Found       bool            `json:"found"`
+		Source      json.RawMessage `json:"_source"`
+		SeqNo       int64           `json:"_seq_no"`
+		PrimaryTerm int64           `json:"_primary_term"`
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		if err := json.Unmarshal(resp.Body, &body); err != nil {
+			return nil, fmt.Errorf("error parsing document %s of index %s: %w", d.id, d.index, err)
+		}
+	}
+	if !body.Found {
+		return nil, nil
+	}
+	return &es.VersionedDocument{
+		Source:  body.Source,
+		Version: es.DocumentVersion{SeqNo: body.SeqNo, PrimaryTerm: body.PrimaryTerm},
+	}, nil
+}
+
+// Create executes Create Document command.
+func (d DocumentWrapper) Create(ctx context.Context, body any) (bool, error) {
+	return d.do(ctx, http.MethodPut, d.path("_create"), nil, body, http.StatusConflict)
+}
+
+// Update executes Index Document command for the version of the document.
+func (d DocumentWrapper) Update(ctx context.Context, body any, version es.DocumentVersion) (bool, error) {
+	return d.do(ctx, http.MethodPut, d.path("_doc"), versionParams(version), body, http.StatusConflict)
+}
+
+// Delete executes Delete Document command for the version of the document.
+func (d DocumentWrapper) Delete(ctx context.Context, version es.DocumentVersion) (bool, error) {
+	return d.do(ctx, http.MethodDelete, d.path("_doc"), versionParams(version), nil, http.StatusConflict, http.StatusNotFound)
+}
+
+// do executes the request, returning false if it failed with one of the ignored statuses,
+// e.g. because of a version conflict.
+func (d DocumentWrapper) do(ctx context.Context, method, path string, params url.Values, body any, ignored ...int) (bool, error) {
+	resp, err := d.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       method,
+		Path:         path,
+		Params:       params,
+		Body:         body,
+		IgnoreErrors: ignored,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error writing document %s of index %s: %w", d.id, d.index, err)
+	}
+	return resp.StatusCode < http.StatusMultipleChoices, nil
+}
+
+// ---
+
+// AliasExistsWrapper implements es.IndicesExistsService for aliases.
+type AliasExistsWrapper struct {
+	client *elastic.Client
+	alias  string
+}
+
+// Do executes the Alias Exists command.
+func (c AliasExistsWrapper) Do(ctx context.Context) (bool, error) {
+	resp, err := c.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodHead,
+		Path:         "/_alias/" + url.PathEscape(c.alias),
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking alias %s: %w", c.alias, err)
+	}
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// ---
+
 // IndexServiceWrapper is a wrapper around elastic.ESIndexService.
 // See wrapper_nolint.go for more functions.
 type IndexServiceWrapper struct {
@@ -238,6 +471,11 @@ func (i IndexServiceWrapper) Type(typ string) es.IndexService {
 	return WrapESIndexService(i.bulkIndexReq.Type(typ), i.bulkService, i.esVersion)
 }
 
+// Routing calls this function to internal service.
+func (i IndexServiceWrapper) Routing(routing string) es.IndexService {
+	return WrapESIndexService(i.bulkIndexReq.Routing(routing), i.bulkService, i.esVersion)
+}
+
 // Add adds the request to bulk service
 func (i IndexServiceWrapper) Add() {
 	i.bulkService.Add(i.bulkIndexReq)
@@ -280,6 +518,71 @@ func (s SearchServiceWrapper) Do(ctx context.Context) (*elastic.SearchResult, er
 	return s.searchService.Do(ctx)
 }
 
+// CountServiceWrapper is a wrapper around elastic.CountService
+type CountServiceWrapper struct {
+	countService *elastic.CountService
+}
+
+// WrapESCountService creates an es.CountService out of *elastic.CountService.
+func WrapESCountService(countService *elastic.CountService) CountServiceWrapper {
+	return CountServiceWrapper{countService: countService}
+}
+
+// Query calls this function to internal service.
+func (s CountServiceWrapper) Query(query elastic.Query) es.CountService {
+	return WrapESCountService(s.countService.Query(query))
+}
+
+// IgnoreUnavailable calls this function to internal service.
+func (s CountServiceWrapper) IgnoreUnavailable(ignoreUnavailable bool) es.CountService {
+	return WrapESCountService(s.countService.IgnoreUnavailable(ignoreUnavailable))
+}
+
+// TerminateAfter calls this function to internal service.
+func (s CountServiceWrapper) TerminateAfter(terminateAfter int) es.CountService {
+	return WrapESCountService(s.countService.TerminateAfter(terminateAfter))
+}
+
+// Do calls this function to internal service.
+func (s CountServiceWrapper) Do(ctx context.Context) (int64, error) {
+	return s.countService.Do(ctx)
+}
+
+// DeleteByQueryServiceWrapper is a wrapper around elastic.DeleteByQueryService
+type DeleteByQueryServiceWrapper struct {
+	deleteByQueryService *elastic.DeleteByQueryService
+}
+
+// WrapESDeleteByQueryService creates an es.DeleteByQueryService out of *elastic.DeleteByQueryService.
+func WrapESDeleteByQueryService(deleteByQueryService *elastic.DeleteByQueryService) DeleteByQueryServiceWrapper {
+	return DeleteByQueryServiceWrapper{deleteByQueryService: deleteByQueryService}
+}
+
+// Query calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Query(query elastic.Query) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.Query(query))
+}
+
+// IgnoreUnavailable calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) IgnoreUnavailable(ignoreUnavailable bool) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.IgnoreUnavailable(ignoreUnavailable))
+}
+
+// ProceedOnVersionConflict calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) ProceedOnVersionConflict() es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.ProceedOnVersionConflict())
+}
+
+// Refresh calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Refresh(refresh string) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.Refresh(refresh))
+}
+
+// Do calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error) {
+	return s.deleteByQueryService.Do(ctx)
+}
+
 // MultiSearchServiceWrapper is a wrapper around elastic.ESMultiSearchService
 type MultiSearchServiceWrapper struct {
 	multiSearchService *elastic.MultiSearchService
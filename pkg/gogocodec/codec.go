@@ -27,6 +27,8 @@ import (
 const (
 	jaegerProtoGenPkgPath = "github.com/jaegertracing/jaeger/proto-gen"
 	jaegerModelPkgPath    = "github.com/jaegertracing/jaeger/model"
+	// api_v3 types use gogo's stdtime, which the default codec cannot marshal.
+	jaegerAPIv3PkgPath = "github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
 )
 
 var defaultCodec encoding.Codec
@@ -98,5 +100,8 @@ func useGogo(t reflect.Type) bool {
 	if strings.HasPrefix(pkg, jaegerModelPkgPath) {
 		return true
 	}
+	if strings.HasPrefix(pkg, jaegerAPIv3PkgPath) {
+		return true
+	}
 	return false
 }
@@ -30,6 +30,8 @@ const (
 	kerberos  = "kerberos"
 	tls       = "tls"
 	plaintext = "plaintext"
+
+	delegationToken = "delegation-token"
 )
 
 var authTypes = []string{
@@ -37,6 +39,7 @@ var authTypes = []string{
 	kerberos,
 	tls,
 	plaintext,
+	delegationToken,
 }
 
 // AuthenticationConfig describes the configuration properties needed authenticate with kafka cluster
@@ -45,6 +48,8 @@ type AuthenticationConfig struct {
 	Kerberos       KerberosConfig  `mapstructure:"kerberos"`
 	TLS            tlscfg.Options  `mapstructure:"tls"`
 	PlainText      PlainTextConfig `mapstructure:"plaintext"`
+	// DelegationToken configures authentication with Kafka delegation tokens
+	DelegationToken DelegationTokenConfig `mapstructure:"delegation_token"`
 }
 
 // SetConfiguration set configure authentication into sarama config structure
@@ -69,6 +74,8 @@ func (config *AuthenticationConfig) SetConfiguration(saramaConfig *sarama.Config
 		return nil
 	case plaintext:
 		return setPlainTextConfiguration(&config.PlainText, saramaConfig)
+	case delegationToken:
+		return setDelegationTokenConfiguration(&config.DelegationToken, saramaConfig, logger)
 	default:
 		return fmt.Errorf("Unknown/Unsupported authentication method %s to kafka cluster", config.Authentication)
 	}
@@ -102,5 +109,11 @@ func (config *AuthenticationConfig) InitFromViper(configPrefix string, v *viper.
 	config.PlainText.Username = v.GetString(configPrefix + plainTextPrefix + suffixPlainTextUsername)
 	config.PlainText.Password = v.GetString(configPrefix + plainTextPrefix + suffixPlainTextPassword)
 	config.PlainText.Mechanism = v.GetString(configPrefix + plainTextPrefix + suffixPlainTextMechanism)
+
+	config.DelegationToken.TokenID = v.GetString(configPrefix + delegationTokenPrefix + suffixDelegationTokenID)
+	config.DelegationToken.Token = v.GetString(configPrefix + delegationTokenPrefix + suffixDelegationToken)
+	config.DelegationToken.Mechanism = v.GetString(configPrefix + delegationTokenPrefix + suffixDelegationTokenMechanism)
+	config.DelegationToken.TokenFile = v.GetString(configPrefix + delegationTokenPrefix + suffixDelegationTokenFile)
+	config.DelegationToken.RefreshBeforeExpiry = v.GetDuration(configPrefix + delegationTokenPrefix + suffixDelegationTokenRefresh)
 	return nil
 }
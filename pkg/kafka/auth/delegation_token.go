@@ -0,0 +1,253 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// DelegationTokenConfig describes the configuration properties needed for authentication
+// with Kafka delegation tokens, which use SASL/SCRAM with the token ID as the user name
+// and the token HMAC as the password.
+type DelegationTokenConfig struct {
+	TokenID   string `mapstructure:"token_id"`
+	Token     string `mapstructure:"token" json:"-"`
+	Mechanism string `mapstructure:"mechanism"`
+	// TokenFile is a JSON file with the tokenId, hmac and expiryTimestamp (in milliseconds)
+	// of the token, which takes precedence over TokenID and Token. It is read again
+	// before each authentication once the token is within RefreshBeforeExpiry of its expiry,
+	// so that a renewed or reissued token is used before the current one expires.
+	TokenFile           string        `mapstructure:"token_file"`
+	RefreshBeforeExpiry time.Duration `mapstructure:"refresh_before_expiry"`
+}
+
+// delegationTokenCredentials is the content of the token file.
+type delegationTokenCredentials struct {
+	TokenID         string `json:"tokenId"`
+	HMAC            string `json:"hmac"`
+	ExpiryTimestamp int64  `json:"expiryTimestamp"`
+}
+
+func (t delegationTokenCredentials) expiry() time.Time {
+	return time.UnixMilli(t.ExpiryTimestamp)
+}
+
+func setDelegationTokenConfiguration(config *DelegationTokenConfig, saramaConfig *sarama.Config, logger *zap.Logger) error {
+	var hashFn func() hash.Hash
+	switch strings.ToUpper(config.Mechanism) {
+	case "SCRAM-SHA-256":
+		hashFn = sha256.New
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+	case "SCRAM-SHA-512":
+		hashFn = sha512.New
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+	default:
+		return fmt.Errorf("config delegation-token.mechanism error: %s, only support 'SCRAM-SHA-256' or 'SCRAM-SHA-512'", config.Mechanism)
+	}
+	source := &delegationTokenSource{config: config, logger: logger, timeNow: time.Now}
+	token, err := source.get()
+	if err != nil {
+		return err
+	}
+	saramaConfig.Net.SASL.Enable = true
+	// the credentials are obtained from the source by the SCRAM client on every
+	// authentication, these are only required by the validation of the configuration
+	saramaConfig.Net.SASL.User = token.TokenID
+	saramaConfig.Net.SASL.Password = token.HMAC
+	saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+		return &delegationTokenSCRAMClient{source: source, hashFn: hashFn}
+	}
+	return nil
+}
+
+// delegationTokenSource provides the current delegation token.
+type delegationTokenSource struct {
+	config  *DelegationTokenConfig
+	logger  *zap.Logger
+	timeNow func() time.Time
+
+	mu      sync.Mutex
+	current delegationTokenCredentials
+}
+
+func (s *delegationTokenSource) get() (delegationTokenCredentials, error) {
+	if s.config.TokenFile == "" {
+		if s.config.TokenID == "" || s.config.Token == "" {
+			return delegationTokenCredentials{}, errors.New("delegation token ID and token, or a token file, must be provided")
+		}
+		return delegationTokenCredentials{TokenID: s.config.TokenID, HMAC: s.config.Token}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.timeNow()
+	if s.current.TokenID != "" && s.current.ExpiryTimestamp != 0 &&
+		now.Before(s.current.expiry().Add(-s.config.RefreshBeforeExpiry)) {
+		return s.current, nil
+	}
+	token, err := readDelegationToken(s.config.TokenFile)
+	if err != nil {
+		if s.current.TokenID != "" && now.Before(s.current.expiry()) {
+			s.logger.Warn("Failed to refresh Kafka delegation token, using the current one", zap.Error(err))
+			return s.current, nil
+		}
+		return delegationTokenCredentials{}, err
+	}
+	if token.ExpiryTimestamp != 0 && !now.Before(token.expiry().Add(-s.config.RefreshBeforeExpiry)) {
+		s.logger.Warn("Kafka delegation token expires soon and has not been renewed",
+			zap.String("token-id", token.TokenID), zap.Time("expiry", token.expiry()))
+	}
+	s.current = token
+	return token, nil
+}
+
+func readDelegationToken(path string) (delegationTokenCredentials, error) {
+	var token delegationTokenCredentials
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return token, fmt.Errorf("failed to read delegation token file: %w", err)
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, fmt.Errorf("failed to parse delegation token file %s: %w", path, err)
+	}
+	if token.TokenID == "" || token.HMAC == "" {
+		return token, fmt.Errorf("delegation token file %s must contain tokenId and hmac", path)
+	}
+	return token, nil
+}
+
+// delegationTokenSCRAMClient implements the SCRAM exchange (RFC 5802) with the
+// "tokenauth=true" extension, which tells Kafka to verify the delegation token.
+type delegationTokenSCRAMClient struct {
+	source *delegationTokenSource
+	hashFn func() hash.Hash
+
+	token       delegationTokenCredentials
+	nonce       string
+	clientFirst string
+	serverSig   []byte
+	step        int
+}
+
+var _ sarama.SCRAMClient = (*delegationTokenSCRAMClient)(nil)
+
+// Begin obtains the current delegation token; the credentials passed by sarama are ignored.
+func (c *delegationTokenSCRAMClient) Begin(_, _, _ string) error {
+	token, err := c.source.get()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	c.token = token
+	c.nonce = base64.RawStdEncoding.EncodeToString(nonce)
+	c.step = 0
+	return nil
+}
+
+// Step returns the response to the server challenge.
+func (c *delegationTokenSCRAMClient) Step(challenge string) (string, error) {
+	c.step++
+	switch c.step {
+	case 1:
+		c.clientFirst = fmt.Sprintf("n=%s,r=%s,tokenauth=true", scramName(c.token.TokenID), c.nonce)
+		return "n,," + c.clientFirst, nil
+	case 2:
+		return c.clientFinal(challenge)
+	case 3:
+		return "", c.verifyServerFinal(challenge)
+	default:
+		return "", errors.New("unexpected SCRAM challenge after the exchange completed")
+	}
+}
+
+// Done returns true when the SCRAM exchange is over.
+func (c *delegationTokenSCRAMClient) Done() bool {
+	return c.step >= 3
+}
+
+func (c *delegationTokenSCRAMClient) clientFinal(serverFirst string) (string, error) {
+	attrs := scramAttributes(serverFirst)
+	nonce, salt64, iter := attrs["r"], attrs["s"], attrs["i"]
+	if !strings.HasPrefix(nonce, c.nonce) || len(nonce) == len(c.nonce) {
+		return "", errors.New("SCRAM server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return "", fmt.Errorf("invalid SCRAM salt: %w", err)
+	}
+	iterations, err := strconv.Atoi(iter)
+	if err != nil || iterations <= 0 {
+		return "", fmt.Errorf("invalid SCRAM iteration count %q", iter)
+	}
+
+	saltedPassword := pbkdf2.Key([]byte(c.token.HMAC), salt, iterations, c.hashFn().Size(), c.hashFn)
+	clientKey := c.hmac(saltedPassword, "Client Key")
+	h := c.hashFn()
+	h.Write(clientKey)
+	storedKey := h.Sum(nil)
+
+	// "biws" is the base64 of the GS2 header "n,,"
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := c.clientFirst + "," + serverFirst + "," + withoutProof
+	proof := c.hmac(storedKey, authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	c.serverSig = c.hmac(c.hmac(saltedPassword, "Server Key"), authMessage)
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (c *delegationTokenSCRAMClient) verifyServerFinal(serverFinal string) error {
+	attrs := scramAttributes(serverFinal)
+	if e, ok := attrs["e"]; ok {
+		return fmt.Errorf("SCRAM authentication with delegation token %s failed: %s", c.token.TokenID, e)
+	}
+	sig, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil || !hmac.Equal(sig, c.serverSig) {
+		return errors.New("invalid SCRAM server signature")
+	}
+	return nil
+}
+
+func (c *delegationTokenSCRAMClient) hmac(key []byte, msg string) []byte {
+	mac := hmac.New(c.hashFn, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
+
+// scramAttributes parses the comma separated key=value attributes of a SCRAM message.
+func scramAttributes(msg string) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range strings.Split(msg, ",") {
+		if key, value, ok := strings.Cut(attr, "="); ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// scramName escapes the user name as required by SCRAM.
+func scramName(name string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(name)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/sha512"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xdg-go/scram"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func writeTokenFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// runSCRAMExchange authenticates the client with a SCRAM server that knows the given token,
+// mimicking the Kafka broker, and returns the extensions received by the server.
+func runSCRAMExchange(t *testing.T, client sarama.SCRAMClient, tokenID, hmac string) (string, error) {
+	serverCredentials, err := scram.SHA512.NewClientUnprepped(tokenID, hmac, "")
+	require.NoError(t, err)
+	stored := serverCredentials.GetStoredCredentials(scram.KeyFactors{Salt: "salt", Iters: 4096})
+	server, err := scram.SHA512.NewServer(func(user string) (scram.StoredCredentials, error) {
+		require.Equal(t, tokenID, user)
+		return stored, nil
+	})
+	require.NoError(t, err)
+	conv := server.NewConversation()
+
+	require.NoError(t, client.Begin("ignored", "ignored", ""))
+	msg, err := client.Step("")
+	require.NoError(t, err)
+	_, extensions, _ := strings.Cut(msg, ",r=")
+	_, extensions, _ = strings.Cut(extensions, ",")
+	for !client.Done() {
+		challenge, serverErr := conv.Step(msg)
+		if serverErr != nil && challenge == "" {
+			return extensions, serverErr
+		}
+		if msg, err = client.Step(challenge); err != nil {
+			return extensions, err
+		}
+	}
+	assert.True(t, conv.Valid())
+	return extensions, nil
+}
+
+func TestDelegationTokenConfiguration(t *testing.T) {
+	saramaConfig := sarama.NewConfig()
+	config := &DelegationTokenConfig{TokenID: "token-id", Token: "token-hmac", Mechanism: "scram-sha-512"}
+	require.NoError(t, setDelegationTokenConfiguration(config, saramaConfig, zap.NewNop()))
+	assert.True(t, saramaConfig.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), saramaConfig.Net.SASL.Mechanism)
+	assert.Equal(t, "token-id", saramaConfig.Net.SASL.User)
+	assert.Equal(t, "token-hmac", saramaConfig.Net.SASL.Password)
+
+	extensions, err := runSCRAMExchange(t, saramaConfig.Net.SASL.SCRAMClientGeneratorFunc(), "token-id", "token-hmac")
+	require.NoError(t, err)
+	assert.Equal(t, "tokenauth=true", extensions)
+
+	config.Mechanism = "SCRAM-SHA-256"
+	require.NoError(t, setDelegationTokenConfiguration(config, saramaConfig, zap.NewNop()))
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA256), saramaConfig.Net.SASL.Mechanism)
+}
+
+func TestDelegationTokenConfigurationErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config DelegationTokenConfig
+		errMsg string
+	}{
+		{
+			name:   "mechanism",
+			config: DelegationTokenConfig{TokenID: "id", Token: "hmac", Mechanism: "PLAIN"},
+			errMsg: "config delegation-token.mechanism error: PLAIN",
+		},
+		{
+			name:   "no token",
+			config: DelegationTokenConfig{TokenID: "id", Mechanism: "SCRAM-SHA-512"},
+			errMsg: "delegation token ID and token, or a token file, must be provided",
+		},
+		{
+			name:   "missing file",
+			config: DelegationTokenConfig{TokenFile: "/does/not/exist", Mechanism: "SCRAM-SHA-512"},
+			errMsg: "failed to read delegation token file",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := setDelegationTokenConfiguration(&tc.config, sarama.NewConfig(), zap.NewNop())
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestDelegationTokenWrongHMAC(t *testing.T) {
+	source := &delegationTokenSource{config: &DelegationTokenConfig{TokenID: "token-id", Token: "stale"}}
+	client := &delegationTokenSCRAMClient{source: source, hashFn: sha512.New}
+	_, err := runSCRAMExchange(t, client, "token-id", "token-hmac")
+	require.Error(t, err)
+}
+
+func TestDelegationTokenRefreshBeforeExpiry(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	writeTokenFile(t, tokenFile, `{"tokenId":"token-1","hmac":"hmac-1","expiryTimestamp":1700003600000}`)
+	now := time.UnixMilli(1700000000000)
+	core, logs := observer.New(zap.WarnLevel)
+	source := &delegationTokenSource{
+		config:  &DelegationTokenConfig{TokenFile: tokenFile, RefreshBeforeExpiry: 10 * time.Minute},
+		logger:  zap.New(core),
+		timeNow: func() time.Time { return now },
+	}
+	token, err := source.get()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.TokenID)
+
+	// the file is not read again while the token is far from its expiry
+	writeTokenFile(t, tokenFile, `{"tokenId":"token-2","hmac":"hmac-2","expiryTimestamp":1700007200000}`)
+	now = now.Add(45 * time.Minute)
+	token, err = source.get()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.TokenID)
+
+	// within the refresh window the renewed token is picked up
+	now = now.Add(10 * time.Minute)
+	token, err = source.get()
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.TokenID)
+	assert.Equal(t, "hmac-2", token.HMAC)
+
+	client := &delegationTokenSCRAMClient{source: source, hashFn: sha512.New}
+	_, err = runSCRAMExchange(t, client, "token-2", "hmac-2")
+	require.NoError(t, err)
+
+	// a broken file does not replace a token that is still valid
+	writeTokenFile(t, tokenFile, `not json`)
+	now = time.UnixMilli(1700007200000).Add(-time.Minute)
+	token, err = source.get()
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.TokenID)
+	assert.Equal(t, 1, logs.FilterMessage("Failed to refresh Kafka delegation token, using the current one").Len())
+
+	// an expired token is not used
+	now = now.Add(time.Minute)
+	_, err = source.get()
+	require.ErrorContains(t, err, "failed to parse delegation token file")
+
+	// a token that is not renewed in time is used, with a warning
+	writeTokenFile(t, tokenFile, `{"tokenId":"token-3","hmac":"hmac-3","expiryTimestamp":1700007500000}`)
+	token, err = source.get()
+	require.NoError(t, err)
+	assert.Equal(t, "token-3", token.TokenID)
+	assert.Equal(t, 1, logs.FilterMessage("Kafka delegation token expires soon and has not been renewed").Len())
+}
+
+func TestReadDelegationTokenIncomplete(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	writeTokenFile(t, tokenFile, `{"tokenId":"token-1"}`)
+	_, err := readDelegationToken(tokenFile)
+	require.ErrorContains(t, err, "must contain tokenId and hmac")
+}
+
+func TestDelegationTokenSCRAMClientErrors(t *testing.T) {
+	source := &delegationTokenSource{config: &DelegationTokenConfig{TokenID: "token-id", Token: "hmac"}}
+	newClient := func(t *testing.T) *delegationTokenSCRAMClient {
+		client := &delegationTokenSCRAMClient{source: source, hashFn: sha512.New}
+		require.NoError(t, client.Begin("", "", ""))
+		_, err := client.Step("")
+		require.NoError(t, err)
+		return client
+	}
+	testCases := []struct {
+		name        string
+		serverFirst func(nonce string) string
+		errMsg      string
+	}{
+		{
+			name:        "nonce",
+			serverFirst: func(string) string { return "r=other,s=c2FsdA==,i=4096" },
+			errMsg:      "SCRAM server nonce does not extend the client nonce",
+		},
+		{
+			name:        "salt",
+			serverFirst: func(nonce string) string { return "r=" + nonce + "x,s=!,i=4096" },
+			errMsg:      "invalid SCRAM salt",
+		},
+		{
+			name:        "iterations",
+			serverFirst: func(nonce string) string { return "r=" + nonce + "x,s=c2FsdA==,i=0" },
+			errMsg:      `invalid SCRAM iteration count "0"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newClient(t)
+			_, err := client.Step(tc.serverFirst(client.nonce))
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+
+	client := newClient(t)
+	_, err := client.Step("r=" + client.nonce + "x,s=c2FsdA==,i=1")
+	require.NoError(t, err)
+	_, err = client.Step("e=invalid-proof")
+	require.EqualError(t, err, "SCRAM authentication with delegation token token-id failed: invalid-proof")
+	_, err = client.Step("")
+	require.EqualError(t, err, "unexpected SCRAM challenge after the exchange completed")
+
+	client = newClient(t)
+	_, err = client.Step("r=" + client.nonce + "x,s=c2FsdA==,i=1")
+	require.NoError(t, err)
+	_, err = client.Step("v=c2lnbmF0dXJl")
+	require.EqualError(t, err, "invalid SCRAM server signature")
+}
+
+func TestScramName(t *testing.T) {
+	assert.Equal(t, "a=3Db=2Cc", scramName("a=b,c"))
+}
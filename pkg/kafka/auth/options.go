@@ -17,6 +17,7 @@ package auth
 import (
 	"flag"
 	"strings"
+	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 )
@@ -53,6 +54,16 @@ const (
 	defaultPlainTextUsername  = ""
 	defaultPlainTextPassword  = ""
 	defaultPlainTextMechanism = "PLAIN"
+
+	delegationTokenPrefix          = ".delegation-token"
+	suffixDelegationTokenID        = ".token-id"
+	suffixDelegationToken          = ".token"
+	suffixDelegationTokenMechanism = ".mechanism"
+	suffixDelegationTokenFile      = ".token-file"
+	suffixDelegationTokenRefresh   = ".refresh-before-expiry"
+
+	defaultDelegationTokenMechanism = "SCRAM-SHA-512"
+	defaultDelegationTokenRefresh   = 5 * time.Minute
 )
 
 func addKerberosFlags(configPrefix string, flagSet *flag.FlagSet) {
@@ -105,6 +116,29 @@ func addPlainTextFlags(configPrefix string, flagSet *flag.FlagSet) {
 		"The plaintext Mechanism for SASL/PLAIN authentication, e.g. 'SCRAM-SHA-256' or 'SCRAM-SHA-512' or 'PLAIN'")
 }
 
+func addDelegationTokenFlags(configPrefix string, flagSet *flag.FlagSet) {
+	flagSet.String(
+		configPrefix+delegationTokenPrefix+suffixDelegationTokenID,
+		"",
+		"The ID of the delegation token, used as the SCRAM user name")
+	flagSet.String(
+		configPrefix+delegationTokenPrefix+suffixDelegationToken,
+		"",
+		"The HMAC of the delegation token, used as the SCRAM password")
+	flagSet.String(
+		configPrefix+delegationTokenPrefix+suffixDelegationTokenMechanism,
+		defaultDelegationTokenMechanism,
+		"The SCRAM mechanism for delegation token authentication, 'SCRAM-SHA-256' or 'SCRAM-SHA-512'")
+	flagSet.String(
+		configPrefix+delegationTokenPrefix+suffixDelegationTokenFile,
+		"",
+		`Path to a JSON file with the delegation token, e.g. {"tokenId":"...","hmac":"...","expiryTimestamp":1700000000000}, which takes precedence over the token ID and token flags; the file is read again when the token is about to expire`)
+	flagSet.Duration(
+		configPrefix+delegationTokenPrefix+suffixDelegationTokenRefresh,
+		defaultDelegationTokenRefresh,
+		"How long before the expiry of the delegation token the token file is read again to obtain a renewed token")
+}
+
 // AddFlags add configuration flags to a flagSet.
 func AddFlags(configPrefix string, flagSet *flag.FlagSet) {
 	flagSet.String(
@@ -120,4 +154,5 @@ func AddFlags(configPrefix string, flagSet *flag.FlagSet) {
 	tlsClientConfig.AddFlags(flagSet)
 
 	addPlainTextFlags(configPrefix, flagSet)
+	addDelegationTokenFlags(configPrefix, flagSet)
 }
@@ -50,6 +50,8 @@ type Configuration struct {
 	ProtocolVersion      string `mapstructure:"protocol_version"`
 	RackID               string `mapstructure:"rack_id"`
 	FetchMaxMessageBytes int32  `mapstructure:"fetch_max_message_bytes"`
+	// Topics are consumed along with Topic, e.g. during a migration to a new topic
+	Topics []string `mapstructure:"topics"`
 }
 
 // NewConsumer creates a new kafka consumer
@@ -77,5 +79,5 @@ func (c *Configuration) NewConsumer(logger *zap.Logger) (Consumer, error) {
 	if c.InitialOffset != 0 {
 		saramaConfig.Consumer.Offsets.Initial = c.InitialOffset
 	}
-	return cluster.NewConsumer(c.Brokers, c.GroupID, []string{c.Topic}, saramaConfig)
+	return cluster.NewConsumer(c.Brokers, c.GroupID, append([]string{c.Topic}, c.Topics...), saramaConfig)
 }
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package encryption implements envelope encryption of span payloads sent through Kafka,
+// so that spans transiting a shared Kafka cluster are unreadable to other consumers of the topic.
+//
+// Each payload is encrypted with a random data key using AES-GCM, and the data key is
+// encrypted (wrapped) with the key of the tenant that owns the span. The envelope carries
+// the ID of the tenant key, which the consumer uses to look up the key and unwrap the data key.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+const (
+	version     = 1
+	dataKeySize = 32
+	maxKeyIDLen = 255
+)
+
+// magic identifies encrypted payloads, followed by the version of the envelope format.
+var magic = []byte("JEE")
+
+// ErrNotEncrypted is returned when decrypting a payload that is not an encryption envelope.
+var ErrNotEncrypted = errors.New("payload is not encrypted")
+
+// KeyProvider provides the AES keys that wrap the data keys of the payloads.
+type KeyProvider interface {
+	// EncryptionKey returns the ID and the key used to encrypt the payloads of the tenant.
+	EncryptionKey(tenant string) (keyID string, key []byte, err error)
+	// DecryptionKey returns the key with the given ID.
+	DecryptionKey(keyID string) ([]byte, error)
+}
+
+// Encryptor encrypts and decrypts payloads with the keys of a KeyProvider.
+//
+// The envelope layout is:
+//
+//	magic "JEE" | version (1 byte) | key ID length (1 byte) | key ID |
+//	data key nonce | wrapped data key | payload nonce | encrypted payload
+//
+// The header, up to and including the key ID, is authenticated as additional data.
+type Encryptor struct {
+	keys KeyProvider
+}
+
+// NewEncryptor creates an Encryptor using the keys of the provider.
+func NewEncryptor(keys KeyProvider) *Encryptor {
+	return &Encryptor{keys: keys}
+}
+
+// Encrypt encrypts the payload of the tenant.
+func (e *Encryptor) Encrypt(tenant string, payload []byte) ([]byte, error) {
+	keyID, key, err := e.keys.EncryptionKey(tenant)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) > maxKeyIDLen {
+		return nil, fmt.Errorf("encryption key ID %q is longer than %d bytes", keyID, maxKeyIDLen)
+	}
+	kek, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key %s: %w", keyID, err)
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	dek, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(magic)+2+len(keyID))
+	header = append(header, magic...)
+	header = append(header, version, byte(len(keyID)))
+	header = append(header, keyID...)
+
+	out := bytes.NewBuffer(make([]byte, 0, len(header)+
+		kek.NonceSize()+dataKeySize+kek.Overhead()+dek.NonceSize()+len(payload)+dek.Overhead()))
+	out.Write(header)
+	if err := seal(out, kek, dataKey, header); err != nil {
+		return nil, err
+	}
+	if err := seal(out, dek, payload, header); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Decrypt decrypts an envelope created by Encrypt.
+func (e *Encryptor) Decrypt(envelope []byte) ([]byte, error) {
+	if !IsEncrypted(envelope) {
+		return nil, ErrNotEncrypted
+	}
+	if v := envelope[len(magic)]; v != version {
+		return nil, fmt.Errorf("unsupported encryption envelope version %d", v)
+	}
+	keyIDLen := int(envelope[len(magic)+1])
+	headerLen := len(magic) + 2 + keyIDLen
+	if len(envelope) < headerLen {
+		return nil, errors.New("truncated encryption envelope")
+	}
+	header, rest := envelope[:headerLen], envelope[headerLen:]
+	keyID := string(header[len(magic)+2:])
+
+	key, err := e.keys.DecryptionKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key %s: %w", keyID, err)
+	}
+	dataKey, rest, err := open(kek, rest, dataKeySize+kek.Overhead(), header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt data key with key %s: %w", keyID, err)
+	}
+	dek, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, _, err := open(dek, rest, len(rest)-dek.NonceSize(), header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt payload: %w", err)
+	}
+	return payload, nil
+}
+
+// IsEncrypted returns true if the payload starts like an encryption envelope.
+func IsEncrypted(payload []byte) bool {
+	return len(payload) > len(magic)+1 && bytes.HasPrefix(payload, magic)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal writes a random nonce followed by the sealed plaintext.
+func seal(out *bytes.Buffer, aead cipher.AEAD, plaintext, additionalData []byte) error {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	out.Write(nonce)
+	out.Write(aead.Seal(nil, nonce, plaintext, additionalData))
+	return nil
+}
+
+// open reads a nonce followed by a ciphertext of the given length, and returns
+// the opened plaintext and the remaining data.
+func open(aead cipher.AEAD, data []byte, ciphertextLen int, additionalData []byte) ([]byte, []byte, error) {
+	n := aead.NonceSize()
+	if ciphertextLen < aead.Overhead() || len(data) < n+ciphertextLen {
+		return nil, nil, errors.New("truncated encryption envelope")
+	}
+	plaintext, err := aead.Open(nil, data[:n], data[n:n+ciphertextLen], additionalData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plaintext, data[n+ciphertextLen:], nil
+}
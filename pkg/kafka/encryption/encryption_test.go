@@ -0,0 +1,161 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticKeys map[string][]byte
+
+func (k staticKeys) EncryptionKey(tenant string) (string, []byte, error) {
+	key, ok := k[tenant]
+	if !ok {
+		return "", nil, errors.New("no key")
+	}
+	return tenant, key, nil
+}
+
+func (k staticKeys) DecryptionKey(keyID string) ([]byte, error) {
+	key, ok := k[keyID]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+func testKeys() staticKeys {
+	return staticKeys{
+		"acme":   bytes.Repeat([]byte{1}, 32),
+		"globex": bytes.Repeat([]byte{2}, 16),
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	e := NewEncryptor(testKeys())
+	payload := []byte("span payload")
+	for _, tenant := range []string{"acme", "globex"} {
+		t.Run(tenant, func(t *testing.T) {
+			envelope, err := e.Encrypt(tenant, payload)
+			require.NoError(t, err)
+			assert.True(t, IsEncrypted(envelope))
+			assert.NotContains(t, string(envelope), string(payload))
+
+			decrypted, err := e.Decrypt(envelope)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decrypted)
+		})
+	}
+
+	// each message has its own data key and nonces
+	first, err := e.Encrypt("acme", payload)
+	require.NoError(t, err)
+	second, err := e.Encrypt("acme", payload)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	empty, err := e.Encrypt("acme", nil)
+	require.NoError(t, err)
+	decrypted, err := e.Decrypt(empty)
+	require.NoError(t, err)
+	assert.Empty(t, decrypted)
+}
+
+func TestEncryptErrors(t *testing.T) {
+	_, err := NewEncryptor(testKeys()).Encrypt("initech", []byte("x"))
+	require.EqualError(t, err, "no key")
+
+	_, err = NewEncryptor(staticKeys{"acme": []byte("short")}).Encrypt("acme", []byte("x"))
+	require.ErrorContains(t, err, "invalid encryption key acme")
+
+	longID := string(bytes.Repeat([]byte{'k'}, maxKeyIDLen+1))
+	_, err = NewEncryptor(staticKeys{longID: bytes.Repeat([]byte{1}, 32)}).Encrypt(longID, []byte("x"))
+	require.ErrorContains(t, err, "is longer than 255 bytes")
+}
+
+func TestDecryptErrors(t *testing.T) {
+	e := NewEncryptor(testKeys())
+	envelope, err := e.Encrypt("acme", []byte("span payload"))
+	require.NoError(t, err)
+	headerLen := len(magic) + 2 + len("acme")
+
+	tampered := func(i int) []byte {
+		data := bytes.Clone(envelope)
+		data[i] ^= 0xff
+		return data
+	}
+	withVersion := bytes.Clone(envelope)
+	withVersion[len(magic)] = 2
+
+	testCases := []struct {
+		name     string
+		envelope []byte
+		errMsg   string
+	}{
+		{
+			name:     "not encrypted",
+			envelope: []byte("plain span"),
+			errMsg:   ErrNotEncrypted.Error(),
+		},
+		{
+			name:     "version",
+			envelope: withVersion,
+			errMsg:   "unsupported encryption envelope version 2",
+		},
+		{
+			name:     "truncated header",
+			envelope: envelope[:headerLen-1],
+			errMsg:   "truncated encryption envelope",
+		},
+		{
+			name:     "truncated data key",
+			envelope: envelope[:headerLen+20],
+			errMsg:   "cannot decrypt data key with key acme: truncated encryption envelope",
+		},
+		{
+			name:     "truncated payload",
+			envelope: envelope[:len(envelope)-len("span payload")-17],
+			errMsg:   "cannot decrypt payload: truncated encryption envelope",
+		},
+		{
+			name:     "tampered key ID",
+			envelope: append(append(bytes.Clone(envelope[:len(magic)+1]), 6), append([]byte("globex"), envelope[headerLen:]...)...),
+			errMsg:   "cannot decrypt data key with key globex",
+		},
+		{
+			name:     "tampered data key",
+			envelope: tampered(headerLen + 15),
+			errMsg:   "cannot decrypt data key with key acme",
+		},
+		{
+			name:     "tampered payload",
+			envelope: tampered(len(envelope) - 1),
+			errMsg:   "cannot decrypt payload",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := e.Decrypt(tc.envelope)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+
+	_, err = NewEncryptor(staticKeys{}).Decrypt(envelope)
+	require.EqualError(t, err, "unknown key")
+
+	_, err = NewEncryptor(staticKeys{"acme": []byte("short")}).Decrypt(envelope)
+	require.ErrorContains(t, err, "invalid encryption key acme")
+}
+
+func TestIsEncrypted(t *testing.T) {
+	assert.False(t, IsEncrypted(nil))
+	assert.False(t, IsEncrypted([]byte("JEE")))
+	assert.False(t, IsEncrypted([]byte(`{"traceId":"1"}`)))
+	assert.True(t, IsEncrypted([]byte("JEE\x01\x00")))
+}
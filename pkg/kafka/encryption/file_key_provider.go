@@ -0,0 +1,96 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+var _ KeyProvider = (*FileKeyProvider)(nil)
+
+// keyFile is the format of the key file, e.g.
+//
+//	{
+//	  "keys": {"acme-2024": "<base64 AES key>", "shared-1": "<base64 AES key>"},
+//	  "tenants": {"acme": "acme-2024"},
+//	  "defaultKey": "shared-1"
+//	}
+type keyFile struct {
+	// Keys maps key IDs to base64-encoded AES-128, AES-192 or AES-256 keys.
+	Keys map[string]string `json:"keys"`
+	// Tenants maps tenants to the ID of the key encrypting their spans.
+	Tenants map[string]string `json:"tenants"`
+	// DefaultKey is the ID of the key for tenants not listed in Tenants,
+	// and for spans without a tenant. Such spans cannot be encrypted when empty.
+	DefaultKey string `json:"defaultKey"`
+}
+
+// FileKeyProvider provides keys loaded from a JSON key file.
+// Keys that are no longer used for encryption can be kept in the file,
+// so that messages encrypted with them can still be decrypted.
+type FileKeyProvider struct {
+	keys       map[string][]byte
+	tenants    map[string]string
+	defaultKey string
+}
+
+// NewFileKeyProvider loads the keys from the file at the given path.
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+	var f keyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse encryption key file %s: %w", path, err)
+	}
+	p := &FileKeyProvider{
+		keys:       make(map[string][]byte, len(f.Keys)),
+		tenants:    f.Tenants,
+		defaultKey: f.DefaultKey,
+	}
+	for id, encoded := range f.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s is not base64-encoded: %w", id, err)
+		}
+		if _, err := newGCM(key); err != nil {
+			return nil, fmt.Errorf("invalid encryption key %s: %w", id, err)
+		}
+		p.keys[id] = key
+	}
+	for tenant, id := range f.Tenants {
+		if _, ok := p.keys[id]; !ok {
+			return nil, fmt.Errorf("encryption key %s of tenant %s is not defined", id, tenant)
+		}
+	}
+	if _, ok := p.keys[f.DefaultKey]; f.DefaultKey != "" && !ok {
+		return nil, fmt.Errorf("default encryption key %s is not defined", f.DefaultKey)
+	}
+	return p, nil
+}
+
+// EncryptionKey implements KeyProvider.
+func (p *FileKeyProvider) EncryptionKey(tenant string) (string, []byte, error) {
+	id, ok := p.tenants[tenant]
+	if !ok {
+		id = p.defaultKey
+	}
+	if id == "" {
+		return "", nil, fmt.Errorf("no encryption key for tenant %q", tenant)
+	}
+	return id, p.keys[id], nil
+}
+
+// DecryptionKey implements KeyProvider.
+func (p *FileKeyProvider) DecryptionKey(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %s", keyID)
+	}
+	return key, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, 32)
+	key2 = bytes.Repeat([]byte{2}, 24)
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	path := writeKeyFile(t, `{
+		"keys": {"acme-1": "`+base64.StdEncoding.EncodeToString(key1)+`", "shared": "`+base64.StdEncoding.EncodeToString(key2)+`"},
+		"tenants": {"acme": "acme-1"},
+		"defaultKey": "shared"
+	}`)
+	p, err := NewFileKeyProvider(path)
+	require.NoError(t, err)
+
+	id, key, err := p.EncryptionKey("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "acme-1", id)
+	assert.Equal(t, key1, key)
+
+	id, key, err = p.EncryptionKey("")
+	require.NoError(t, err)
+	assert.Equal(t, "shared", id)
+	assert.Equal(t, key2, key)
+
+	key, err = p.DecryptionKey("acme-1")
+	require.NoError(t, err)
+	assert.Equal(t, key1, key)
+
+	_, err = p.DecryptionKey("acme-0")
+	require.EqualError(t, err, "unknown encryption key acme-0")
+
+	e := NewEncryptor(p)
+	envelope, err := e.Encrypt("globex", []byte("span"))
+	require.NoError(t, err)
+	payload, err := e.Decrypt(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("span"), payload)
+}
+
+func TestFileKeyProviderWithoutDefaultKey(t *testing.T) {
+	path := writeKeyFile(t, `{"keys": {"acme-1": "`+base64.StdEncoding.EncodeToString(key1)+`"}, "tenants": {"acme": "acme-1"}}`)
+	p, err := NewFileKeyProvider(path)
+	require.NoError(t, err)
+	_, _, err = p.EncryptionKey("globex")
+	require.EqualError(t, err, `no encryption key for tenant "globex"`)
+}
+
+func TestNewFileKeyProviderErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "json",
+			content: `not json`,
+			errMsg:  "failed to parse encryption key file",
+		},
+		{
+			name:    "base64",
+			content: `{"keys": {"k": "!"}}`,
+			errMsg:  "encryption key k is not base64-encoded",
+		},
+		{
+			name:    "key size",
+			content: `{"keys": {"k": "c2hvcnQ="}}`,
+			errMsg:  "invalid encryption key k",
+		},
+		{
+			name:    "tenant key",
+			content: `{"keys": {}, "tenants": {"acme": "k"}}`,
+			errMsg:  "encryption key k of tenant acme is not defined",
+		},
+		{
+			name:    "default key",
+			content: `{"keys": {}, "defaultKey": "k"}`,
+			errMsg:  "default encryption key k is not defined",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewFileKeyProvider(writeKeyFile(t, tc.content))
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+
+	_, err := NewFileKeyProvider("/does/not/exist")
+	require.ErrorContains(t, err, "failed to read encryption key file")
+}
@@ -41,10 +41,29 @@ type Configuration struct {
 	BatchMaxMessages          int                     `mapstructure:"batch_max_messages"`
 	MaxMessageBytes           int                     `mapstructure:"max_message_bytes"`
 	auth.AuthenticationConfig `mapstructure:"authentication"`
+	// Partitioner chooses the partitions of the messages, sarama's hash of their key if nil
+	Partitioner sarama.PartitionerConstructor `mapstructure:"-"`
 }
 
 // NewProducer creates a new asynchronous kafka producer
 func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, error) {
+	saramaConfig, err := c.newSaramaConfig(logger)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewAsyncProducer(c.Brokers, saramaConfig)
+}
+
+// NewSyncProducer creates a new synchronous kafka producer
+func (c *Configuration) NewSyncProducer(logger *zap.Logger) (sarama.SyncProducer, error) {
+	saramaConfig, err := c.newSaramaConfig(logger)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewSyncProducer(c.Brokers, saramaConfig)
+}
+
+func (c *Configuration) newSaramaConfig(logger *zap.Logger) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Producer.RequiredAcks = c.RequiredAcks
 	saramaConfig.Producer.Compression = c.Compression
@@ -55,6 +74,9 @@ func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, e
 	saramaConfig.Producer.Flush.Messages = c.BatchMinMessages
 	saramaConfig.Producer.Flush.MaxMessages = c.BatchMaxMessages
 	saramaConfig.Producer.MaxMessageBytes = c.MaxMessageBytes
+	if c.Partitioner != nil {
+		saramaConfig.Producer.Partitioner = c.Partitioner
+	}
 	if len(c.ProtocolVersion) > 0 {
 		ver, err := sarama.ParseKafkaVersion(c.ProtocolVersion)
 		if err != nil {
@@ -65,5 +87,5 @@ func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, e
 	if err := c.AuthenticationConfig.SetConfiguration(saramaConfig, logger); err != nil {
 		return nil, err
 	}
-	return sarama.NewAsyncProducer(c.Brokers, saramaConfig)
+	return saramaConfig, nil
 }
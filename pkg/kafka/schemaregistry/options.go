@@ -0,0 +1,57 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+)
+
+const (
+	schemaRegistryPrefix = ".schema-registry"
+	suffixURL            = ".url"
+	suffixUsername       = ".username"
+	suffixPassword       = ".password"
+	suffixTimeout        = ".timeout"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// AddFlags adds the flags of the schema registry to a flagSet.
+func AddFlags(configPrefix string, flagSet *flag.FlagSet) {
+	prefix := configPrefix + schemaRegistryPrefix
+	flagSet.String(
+		prefix+suffixURL,
+		"",
+		"(experimental) The URL of the Confluent Schema Registry, required by the avro encoding")
+	flagSet.String(
+		prefix+suffixUsername,
+		"",
+		"The username for the basic authentication with the schema registry")
+	flagSet.String(
+		prefix+suffixPassword,
+		"",
+		"The password for the basic authentication with the schema registry")
+	flagSet.Duration(
+		prefix+suffixTimeout,
+		defaultTimeout,
+		"The timeout of the requests to the schema registry")
+	tlscfg.ClientFlagsConfig{Prefix: prefix}.AddFlags(flagSet)
+}
+
+// InitFromViper initializes the Config with properties from viper.
+func (c *Config) InitFromViper(configPrefix string, v *viper.Viper) error {
+	prefix := configPrefix + schemaRegistryPrefix
+	c.URL = v.GetString(prefix + suffixURL)
+	c.Username = v.GetString(prefix + suffixUsername)
+	c.Password = v.GetString(prefix + suffixPassword)
+	c.Timeout = v.GetDuration(prefix + suffixTimeout)
+	var err error
+	c.TLS, err = tlscfg.ClientFlagsConfig{Prefix: prefix}.InitFromViper(v)
+	return err
+}
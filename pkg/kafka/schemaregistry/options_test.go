@@ -0,0 +1,38 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestInitFromViper(t *testing.T) {
+	v, command := config.Viperize(func(flagSet *flag.FlagSet) { AddFlags("kafka.producer", flagSet) })
+	require.NoError(t, command.ParseFlags([]string{
+		"--kafka.producer.schema-registry.url=https://registry:8081",
+		"--kafka.producer.schema-registry.username=user",
+		"--kafka.producer.schema-registry.password=secret",
+		"--kafka.producer.schema-registry.tls.enabled=true",
+		"--kafka.producer.schema-registry.tls.ca=/etc/ca.pem",
+	}))
+	var cfg Config
+	require.NoError(t, cfg.InitFromViper("kafka.producer", v))
+	assert.Equal(t, "https://registry:8081", cfg.URL)
+	assert.Equal(t, "user", cfg.Username)
+	assert.Equal(t, "secret", cfg.Password)
+	assert.Equal(t, 10*time.Second, cfg.Timeout)
+	assert.True(t, cfg.TLS.Enabled)
+	assert.Equal(t, "/etc/ca.pem", cfg.TLS.CAPath)
+
+	v, command = config.Viperize(func(flagSet *flag.FlagSet) { AddFlags("kafka.producer", flagSet) })
+	require.NoError(t, command.ParseFlags([]string{"--kafka.producer.schema-registry.tls.ca=/etc/ca.pem"}))
+	require.ErrorContains(t, cfg.InitFromViper("kafka.producer", v), "cannot be used when kafka.producer.schema-registry.tls.enabled is false")
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistry implements a client of the Confluent Schema Registry, which registers
+// the schemas of the messages sent through Kafka and resolves the schemas of the messages received,
+// and the wire format of the messages, which prefixes the payload with the ID of its schema.
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+)
+
+const (
+	// TopicNameStrategy names the subject of the schema after the topic, as <topic>-value.
+	TopicNameStrategy = "topic"
+	// RecordNameStrategy names the subject of the schema after the full name of the record.
+	RecordNameStrategy = "record"
+	// TopicRecordNameStrategy names the subject of the schema after the topic and the full name of the record, as <topic>-<record>.
+	TopicRecordNameStrategy = "topic-record"
+
+	contentType = "application/vnd.schemaregistry.v1+json"
+
+	// magicByte starts the messages in the wire format, followed by the ID of the schema as a 4 bytes big-endian integer.
+	magicByte    = 0
+	headerLength = 5
+)
+
+// AllSubjectNameStrategies is a list of all supported subject name strategies.
+var AllSubjectNameStrategies = []string{TopicNameStrategy, RecordNameStrategy, TopicRecordNameStrategy}
+
+// Config describes the connection to the schema registry.
+type Config struct {
+	URL      string         `mapstructure:"url"`
+	Username string         `mapstructure:"username"`
+	Password string         `mapstructure:"password"`
+	Timeout  time.Duration  `mapstructure:"timeout"`
+	TLS      tlscfg.Options `mapstructure:"tls"`
+}
+
+// Client registers and fetches schemas. The schema IDs are immutable, so they are cached for the life of the client.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	ids     map[string]int // by subject and schema
+	schemas map[int]string
+}
+
+type registerRequest struct {
+	Schema string `json:"schema"`
+}
+
+type registerResponse struct {
+	ID int `json:"id"`
+}
+
+type schemaResponse struct {
+	Schema string `json:"schema"`
+}
+
+type errorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// NewClient creates a Client of the schema registry at the URL of the configuration.
+func NewClient(config Config, logger *zap.Logger) (*Client, error) {
+	if config.URL == "" {
+		return nil, errors.New("the schema registry URL is required")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid schema registry URL: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.TLS.Enabled {
+		tlsConfig, err := config.TLS.Config(logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config of the schema registry: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Transport: transport, Timeout: config.Timeout},
+		ids:        make(map[string]int),
+		schemas:    make(map[int]string),
+	}, nil
+}
+
+// Register registers the schema under the subject, unless already registered, and returns its ID.
+func (c *Client) Register(ctx context.Context, subject, schema string) (int, error) {
+	key := subject + "\x00" + schema
+	c.mu.Lock()
+	id, ok := c.ids[key]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	body, err := json.Marshal(registerRequest{Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+	var response registerResponse
+	path := "/subjects/" + url.PathEscape(subject) + "/versions"
+	if err := c.do(ctx, http.MethodPost, path, body, &response); err != nil {
+		return 0, fmt.Errorf("failed to register schema of subject %s: %w", subject, err)
+	}
+	c.mu.Lock()
+	c.ids[key] = response.ID
+	c.schemas[response.ID] = schema
+	c.mu.Unlock()
+	return response.ID, nil
+}
+
+// Schema returns the schema with the given ID.
+func (c *Client) Schema(ctx context.Context, id int) (string, error) {
+	c.mu.Lock()
+	schema, ok := c.schemas[id]
+	c.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+	var response schemaResponse
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &response); err != nil {
+		return "", fmt.Errorf("failed to fetch schema %d: %w", id, err)
+	}
+	c.mu.Lock()
+	c.schemas[id] = response.Schema
+	c.mu.Unlock()
+	return response.Schema, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, response any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.config.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResponse errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err == nil && errResponse.Message != "" {
+			return fmt.Errorf("schema registry error %d: %s", errResponse.ErrorCode, errResponse.Message)
+		}
+		return fmt.Errorf("schema registry returned status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return nil
+}
+
+// Close releases the connections of the client.
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return c.config.TLS.Close()
+}
+
+// Subject returns the subject of the schema of the record sent to the topic, according to the strategy.
+func Subject(strategy, topic, recordName string) (string, error) {
+	switch strategy {
+	case TopicNameStrategy:
+		return topic + "-value", nil
+	case RecordNameStrategy:
+		return recordName, nil
+	case TopicRecordNameStrategy:
+		return topic + "-" + recordName, nil
+	default:
+		return "", fmt.Errorf(`subject name strategy '%s' not recognised, use one of ("%s")`,
+			strategy, strings.Join(AllSubjectNameStrategies, "\", \""))
+	}
+}
+
+// Encode prefixes the payload with the ID of its schema.
+func Encode(id int, payload []byte) []byte {
+	msg := make([]byte, headerLength, headerLength+len(payload))
+	msg[0] = magicByte
+	binary.BigEndian.PutUint32(msg[1:headerLength], uint32(id))
+	return append(msg, payload...)
+}
+
+// Decode returns the ID of the schema and the payload of a message.
+func Decode(msg []byte) (int, []byte, error) {
+	if len(msg) < headerLength || msg[0] != magicByte {
+		return 0, nil, errors.New("message is not in the schema registry wire format")
+	}
+	return int(binary.BigEndian.Uint32(msg[1:headerLength])), msg[headerLength:], nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+)
+
+const testSchema = `{"type":"record","name":"Span","fields":[]}`
+
+type fakeRegistry struct {
+	*httptest.Server
+	registrations int
+	fetches       int
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/subjects/", func(w http.ResponseWriter, req *http.Request) {
+		r.registrations++
+		user, password, ok := req.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code": 401, "message": "Unauthorized"}`))
+			return
+		}
+		assert.Equal(t, contentType, req.Header.Get("Content-Type"))
+		var body registerRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, http.MethodPost, req.Method)
+		if req.URL.Path != "/subjects/jaeger-spans-value/versions" || body.Schema != testSchema {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_code": 409, "message": "Schema being registered is incompatible"}`))
+			return
+		}
+		w.Write([]byte(`{"id": 42}`))
+	})
+	mux.HandleFunc("/schemas/ids/", func(w http.ResponseWriter, req *http.Request) {
+		r.fetches++
+		if req.URL.Path != "/schemas/ids/7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"schema": "{\"type\":\"string\"}"}`))
+	})
+	r.Server = httptest.NewServer(mux)
+	t.Cleanup(r.Close)
+	return r
+}
+
+func newTestClient(t *testing.T, config Config) *Client {
+	client, err := NewClient(config, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, client.Close()) })
+	return client
+}
+
+func TestClientRegister(t *testing.T) {
+	registry := newFakeRegistry(t)
+	client := newTestClient(t, Config{URL: registry.URL + "/", Username: "user", Password: "secret"})
+
+	for i := 0; i < 2; i++ {
+		id, err := client.Register(context.Background(), "jaeger-spans-value", testSchema)
+		require.NoError(t, err)
+		assert.Equal(t, 42, id)
+	}
+	assert.Equal(t, 1, registry.registrations)
+
+	schema, err := client.Schema(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, testSchema, schema)
+	assert.Zero(t, registry.fetches)
+
+	_, err = client.Register(context.Background(), "other-value", testSchema)
+	require.EqualError(t, err, "failed to register schema of subject other-value: schema registry error 409: Schema being registered is incompatible")
+}
+
+func TestClientRegisterUnauthorized(t *testing.T) {
+	registry := newFakeRegistry(t)
+	client := newTestClient(t, Config{URL: registry.URL})
+	_, err := client.Register(context.Background(), "jaeger-spans-value", testSchema)
+	require.EqualError(t, err, "failed to register schema of subject jaeger-spans-value: schema registry error 401: Unauthorized")
+}
+
+func TestClientSchema(t *testing.T) {
+	registry := newFakeRegistry(t)
+	client := newTestClient(t, Config{URL: registry.URL})
+
+	for i := 0; i < 2; i++ {
+		schema, err := client.Schema(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"string"}`, schema)
+	}
+	assert.Equal(t, 1, registry.fetches)
+
+	_, err := client.Schema(context.Background(), 8)
+	require.EqualError(t, err, "failed to fetch schema 8: schema registry returned status 404 Not Found")
+}
+
+func TestClientUnreachable(t *testing.T) {
+	registry := newFakeRegistry(t)
+	registry.Close()
+	client := newTestClient(t, Config{URL: registry.URL})
+	_, err := client.Schema(context.Background(), 7)
+	require.ErrorContains(t, err, "failed to fetch schema 7")
+}
+
+func TestNewClientInvalid(t *testing.T) {
+	_, err := NewClient(Config{}, zap.NewNop())
+	require.EqualError(t, err, "the schema registry URL is required")
+
+	_, err = NewClient(Config{URL: "http://registry:8081\x7f"}, zap.NewNop())
+	require.ErrorContains(t, err, "invalid schema registry URL")
+
+	_, err = NewClient(Config{URL: "https://registry:8081", TLS: tlscfg.Options{Enabled: true, CAPath: "/not/there"}}, zap.NewNop())
+	require.ErrorContains(t, err, "failed to load TLS config of the schema registry")
+}
+
+func TestSubject(t *testing.T) {
+	testCases := []struct {
+		strategy string
+		expected string
+	}{
+		{strategy: TopicNameStrategy, expected: "jaeger-spans-value"},
+		{strategy: RecordNameStrategy, expected: "jaeger.api_v2.Span"},
+		{strategy: TopicRecordNameStrategy, expected: "jaeger-spans-jaeger.api_v2.Span"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.strategy, func(t *testing.T) {
+			subject, err := Subject(tc.strategy, "jaeger-spans", "jaeger.api_v2.Span")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, subject)
+		})
+	}
+	_, err := Subject("schema", "jaeger-spans", "jaeger.api_v2.Span")
+	require.EqualError(t, err, `subject name strategy 'schema' not recognised, use one of ("topic", "record", "topic-record")`)
+}
+
+func TestWireFormat(t *testing.T) {
+	msg := Encode(258, []byte("payload"))
+	assert.Equal(t, []byte{0, 0, 0, 1, 2, 'p', 'a', 'y', 'l', 'o', 'a', 'd'}, msg)
+	id, payload, err := Decode(msg)
+	require.NoError(t, err)
+	assert.Equal(t, 258, id)
+	assert.Equal(t, []byte("payload"), payload)
+
+	for _, invalid := range [][]byte{{0, 0, 0}, {1, 0, 0, 0, 1}} {
+		_, _, err := Decode(invalid)
+		require.EqualError(t, err, "message is not in the schema registry wire format")
+	}
+}
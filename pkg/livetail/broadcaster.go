@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package livetail
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/jaegertracing/jaeger/model"
+	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
+	ui "github.com/jaegertracing/jaeger/model/json"
+)
+
+// DefaultBufferSize is the number of spans buffered per subscriber before spans are dropped.
+const DefaultBufferSize = 256
+
+// Filter selects the spans delivered to a subscriber. Empty fields match any span.
+type Filter struct {
+	// Tenant restricts spans to a single tenant.
+	Tenant string
+	// ServiceName restricts spans to a service.
+	ServiceName string
+	// OperationName restricts spans to an operation.
+	OperationName string
+	// Tags restricts spans to those having all the given span or process tags.
+	Tags map[string]string
+}
+
+// Matches returns true if the span received for the tenant passes the filter.
+func (f Filter) Matches(span *model.Span, tenant string) bool {
+	if f.Tenant != "" && f.Tenant != tenant {
+		return false
+	}
+	if f.ServiceName != "" && (span.Process == nil || span.Process.ServiceName != f.ServiceName) {
+		return false
+	}
+	if f.OperationName != "" && span.OperationName != f.OperationName {
+		return false
+	}
+	for k, v := range f.Tags {
+		if !hasTag(span, k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(span *model.Span, key, value string) bool {
+	if kv, ok := model.KeyValues(span.Tags).FindByKey(key); ok && kv.AsString() == value {
+		return true
+	}
+	if span.Process != nil {
+		if kv, ok := model.KeyValues(span.Process.Tags).FindByKey(key); ok && kv.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives the spans matching its filter.
+type Subscription struct {
+	filter  Filter
+	spans   chan *ui.Span
+	dropped atomic.Int64
+}
+
+// Spans returns the channel the matching spans are delivered on.
+// The channel is closed when the subscription is cancelled.
+func (s *Subscription) Spans() <-chan *ui.Span {
+	return s.spans
+}
+
+// Dropped returns and resets the number of spans dropped because the subscriber was too slow.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Broadcaster fans out spans received by the collector to live tail subscribers.
+// Publishing never blocks: spans are dropped for subscribers whose buffer is full.
+type Broadcaster struct {
+	bufferSize int
+
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	// count mirrors len(subscribers) so that Publish is cheap when nobody is tailing
+	count atomic.Int32
+}
+
+// NewBroadcaster creates a Broadcaster with the given per-subscriber buffer size.
+func NewBroadcaster(bufferSize int) *Broadcaster {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broadcaster{
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for spans matching the filter.
+// The returned function cancels the subscription and must be called.
+func (b *Broadcaster) Subscribe(filter Filter) (*Subscription, func()) {
+	sub := &Subscription{
+		filter: filter,
+		spans:  make(chan *ui.Span, b.bufferSize),
+	}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.count.Store(int32(len(b.subscribers)))
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.count.Store(int32(len(b.subscribers)))
+			b.mu.Unlock()
+			close(sub.spans)
+		})
+	}
+}
+
+// Publish delivers the span to the matching subscribers. Its signature matches
+// the collector's ProcessSpan so it can be used as an additional span processor.
+func (b *Broadcaster) Publish(span *model.Span, tenant string) {
+	if b.count.Load() == 0 {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var uiSpan *ui.Span
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(span, tenant) {
+			continue
+		}
+		if uiSpan == nil {
+			// converted once and shared, so subscribers never see the collector's span
+			uiSpan = uiconv.FromDomainEmbedProcess(span)
+		}
+		select {
+		case sub.spans <- uiSpan:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
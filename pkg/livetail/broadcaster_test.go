@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package livetail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func testSpan(service, operation string) *model.Span {
+	return &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(2),
+		OperationName: operation,
+		Tags:          model.KeyValues{model.String("http.method", "GET")},
+		Process: &model.Process{
+			ServiceName: service,
+			Tags:        model.KeyValues{model.String("version", "v2")},
+		},
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	span := testSpan("frontend", "HTTP GET")
+	testCases := []struct {
+		name   string
+		filter Filter
+		tenant string
+		match  bool
+	}{
+		{name: "empty", filter: Filter{}, match: true},
+		{name: "service", filter: Filter{ServiceName: "frontend"}, match: true},
+		{name: "other service", filter: Filter{ServiceName: "backend"}, match: false},
+		{name: "operation", filter: Filter{ServiceName: "frontend", OperationName: "HTTP GET"}, match: true},
+		{name: "other operation", filter: Filter{OperationName: "HTTP POST"}, match: false},
+		{name: "span tag", filter: Filter{Tags: map[string]string{"http.method": "GET"}}, match: true},
+		{name: "process tag", filter: Filter{Tags: map[string]string{"version": "v2"}}, match: true},
+		{name: "tag value mismatch", filter: Filter{Tags: map[string]string{"version": "v1"}}, match: false},
+		{name: "missing tag", filter: Filter{Tags: map[string]string{"missing": "x"}}, match: false},
+		{name: "tenant", filter: Filter{Tenant: "acme"}, tenant: "acme", match: true},
+		{name: "other tenant", filter: Filter{Tenant: "acme"}, tenant: "other", match: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.match, tc.filter.Matches(span, tc.tenant))
+		})
+	}
+	assert.False(t, Filter{ServiceName: "frontend"}.Matches(&model.Span{}, ""))
+}
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster(1)
+	// no subscribers, must not block
+	b.Publish(testSpan("frontend", "op"), "")
+
+	frontend, cancelFrontend := b.Subscribe(Filter{ServiceName: "frontend"})
+	backend, cancelBackend := b.Subscribe(Filter{ServiceName: "backend"})
+	defer cancelBackend()
+
+	b.Publish(testSpan("frontend", "op1"), "")
+	b.Publish(testSpan("frontend", "op2"), "") // buffer is full, dropped
+
+	span := <-frontend.Spans()
+	assert.Equal(t, "op1", span.OperationName)
+	assert.Equal(t, "frontend", span.Process.ServiceName)
+	assert.Equal(t, int64(1), frontend.Dropped())
+	assert.Equal(t, int64(0), frontend.Dropped())
+	assert.Empty(t, backend.Spans())
+
+	cancelFrontend()
+	cancelFrontend()
+	_, ok := <-frontend.Spans()
+	assert.False(t, ok)
+	assert.Equal(t, int32(1), b.count.Load())
+}
+
+func TestNewBroadcasterDefaultBufferSize(t *testing.T) {
+	b := NewBroadcaster(0)
+	require.Equal(t, DefaultBufferSize, b.bufferSize)
+}
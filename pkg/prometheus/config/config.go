@@ -20,6 +20,14 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 )
 
+// Label presets name the span metrics and their labels, which differ between the span metrics generators.
+const (
+	// LabelPresetOTel matches the spanmetrics connector of the OpenTelemetry Collector.
+	LabelPresetOTel = "otel"
+	// LabelPresetLegacy matches the deprecated spanmetrics processor of the OpenTelemetry Collector.
+	LabelPresetLegacy = "legacy"
+)
+
 // Configuration describes the options to customize the storage behavior.
 type Configuration struct {
 	ServerURL                string
@@ -27,9 +35,27 @@ type Configuration struct {
 	TLS                      tlscfg.Options
 	TokenFilePath            string
 	TokenOverrideFromContext bool
+	// ProxyURL is the URL of the HTTP proxy of the Prometheus servers, the one of the
+	// HTTP_PROXY and HTTPS_PROXY environment variables if empty.
+	ProxyURL string
+	// Datasources are additional Prometheus servers queried along with ServerURL, whose span metrics are merged.
+	Datasources []Datasource
 
 	MetricNamespace   string
 	LatencyUnit       string
 	NormalizeCalls    bool
 	NormalizeDuration bool
+	LabelPreset       string
+}
+
+// Datasource is an additional Prometheus server with its own label preset, sharing the
+// connection settings of the Configuration unless it overrides them.
+type Datasource struct {
+	ServerURL   string
+	LabelPreset string
+	// TLS replaces the TLS settings of the Configuration for this server if set, e.g. with the
+	// CA of another cluster. The certificates are reloaded when their files change.
+	TLS *tlscfg.Options
+	// ProxyURL replaces the ProxyURL of the Configuration for this server if set.
+	ProxyURL string
 }
@@ -24,6 +24,9 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
 
+// produceRetryInterval is how often ProduceWithTimeout checks whether there is room in a full queue.
+const produceRetryInterval = time.Millisecond
+
 // Consumer consumes data from a bounded queue
 type Consumer interface {
 	Consume(item any)
@@ -35,7 +38,9 @@ type Consumer interface {
 // channels, with a special Reaper goroutine that wakes up when the queue is full and consumers
 // the items from the top of the queue until its size drops back to maxSize
 type BoundedQueue struct {
+	workersMu     sync.Mutex
 	workers       int
+	workerStopChs []chan struct{} // one per worker consuming from the current items channel
 	stopWG        sync.WaitGroup
 	size          atomic.Int32
 	capacity      atomic.Uint32
@@ -62,12 +67,22 @@ func NewBoundedQueue(capacity int, onDroppedItem func(item any)) *BoundedQueue {
 // StartConsumersWithFactory creates a given number of consumers consuming items
 // from the queue in separate goroutines.
 func (q *BoundedQueue) StartConsumersWithFactory(num int, factory func() Consumer) {
+	q.workersMu.Lock()
+	defer q.workersMu.Unlock()
 	q.workers = num
 	q.factory = factory
+	q.startWorkers(num)
+}
+
+// startWorkers starts num more consumers of the current items channel. It must be
+// called with workersMu held.
+func (q *BoundedQueue) startWorkers(num int) {
 	var startWG sync.WaitGroup
-	for i := 0; i < q.workers; i++ {
+	for i := 0; i < num; i++ {
 		q.stopWG.Add(1)
 		startWG.Add(1)
+		workerStopCh := make(chan struct{})
+		q.workerStopChs = append(q.workerStopChs, workerStopCh)
 		go func() {
 			startWG.Done()
 			defer q.stopWG.Done()
@@ -83,6 +98,9 @@ func (q *BoundedQueue) StartConsumersWithFactory(num int, factory func() Consume
 						// channel closed, finish worker
 						return
 					}
+				case <-workerStopCh:
+					// the number of workers was reduced, finish worker
+					return
 				case <-q.stopCh:
 					// the whole queue is closing, finish worker
 					return
@@ -93,6 +111,33 @@ func (q *BoundedQueue) StartConsumersWithFactory(num int, factory func() Consume
 	startWG.Wait()
 }
 
+// SetWorkers changes the number of consumers, starting new ones or stopping the surplus
+// ones once they are done with their current item. It must be called after the consumers
+// are started.
+func (q *BoundedQueue) SetWorkers(num int) {
+	q.workersMu.Lock()
+	defer q.workersMu.Unlock()
+	if q.stopped.Load() != 0 || num < 0 || num == q.workers {
+		return
+	}
+	if num > q.workers {
+		q.startWorkers(num - q.workers)
+	} else {
+		for _, workerStopCh := range q.workerStopChs[num:] {
+			close(workerStopCh)
+		}
+		q.workerStopChs = q.workerStopChs[:num]
+	}
+	q.workers = num
+}
+
+// Workers returns the number of consumers
+func (q *BoundedQueue) Workers() int {
+	q.workersMu.Lock()
+	defer q.workersMu.Unlock()
+	return q.workers
+}
+
 // ConsumerFunc is an adapter to allow the use of
 // a consume function callback as a Consumer.
 type ConsumerFunc func(item any)
@@ -140,6 +185,28 @@ func (q *BoundedQueue) Produce(item any) bool {
 	}
 }
 
+// ProduceWithTimeout is like Produce, but when the queue is full it waits for up to timeout for
+// the consumers to make room for the item, which is dropped if there is still none.
+func (q *BoundedQueue) ProduceWithTimeout(item any, timeout time.Duration) bool {
+	if q.Size() < q.Capacity() {
+		return q.Produce(item)
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(produceRetryInterval)
+	defer ticker.Stop()
+	for q.Size() >= q.Capacity() && q.stopped.Load() == 0 {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return q.Produce(item)
+		case <-q.stopCh:
+			return q.Produce(item)
+		}
+	}
+	return q.Produce(item)
+}
+
 // Stop stops all consumers, as well as the length reporter if started,
 // and releases the items channel. It blocks until all consumers have stopped.
 func (q *BoundedQueue) Stop() {
@@ -184,6 +251,9 @@ func (q *BoundedQueue) Resize(capacity int) bool {
 		return false
 	}
 
+	q.workersMu.Lock()
+	defer q.workersMu.Unlock()
+
 	previous := *q.items
 	queue := make(chan any, capacity)
 
@@ -191,8 +261,10 @@ func (q *BoundedQueue) Resize(capacity int) bool {
 	// #nosec
 	swapped := atomic.CompareAndSwapPointer((*unsafe.Pointer)(unsafe.Pointer(&q.items)), unsafe.Pointer(q.items), unsafe.Pointer(&queue))
 	if swapped {
-		// start a new set of consumers, based on the information given previously
-		q.StartConsumersWithFactory(q.workers, q.factory)
+		// start a new set of consumers, based on the information given previously,
+		// the current ones finish once they have drained the previous queue
+		q.workerStopChs = nil
+		q.startWorkers(q.workers)
 
 		// gracefully drain the existing queue
 		close(previous)
@@ -317,6 +317,44 @@ func TestResizeOldQueueIsDrained(t *testing.T) {
 	expected.Wait() // once this returns, we've consumed all items, meaning that both queues are drained
 }
 
+func TestSetWorkers(t *testing.T) {
+	q := NewBoundedQueue(10, func( /* item */ any) {})
+
+	started, release := make(chan string), make(chan struct{})
+	q.StartConsumers(1, func(item any) {
+		started <- item.(string)
+		<-release
+	})
+
+	assert.True(t, q.Produce("a"))
+	<-started
+
+	q.SetWorkers(3)
+	assert.Equal(t, 3, q.Workers())
+	assert.True(t, q.Produce("b"))
+	assert.True(t, q.Produce("c"))
+	// the two new workers consume concurrently with the first, still blocked one
+	assert.ElementsMatch(t, []string{"b", "c"}, []string{<-started, <-started})
+
+	q.SetWorkers(1)
+	assert.Equal(t, 1, q.Workers())
+	assert.Len(t, q.workerStopChs, 1)
+
+	close(release)
+	go func() {
+		for range started {
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		assert.True(t, q.Produce("d"))
+	}
+	q.Stop()
+	close(started)
+
+	q.SetWorkers(5)
+	assert.Equal(t, 1, q.Workers(), "the workers are not changed once the queue is stopped")
+}
+
 func TestNoopResize(t *testing.T) {
 	q := NewBoundedQueue(2, func( /* item */ any) {})
 
@@ -332,6 +370,42 @@ func TestZeroSize(t *testing.T) {
 	assert.False(t, q.Produce("a")) // in process
 }
 
+func TestProduceWithTimeout(t *testing.T) {
+	var dropped atomic.Int32
+	q := NewBoundedQueue(1, func( /* item */ any) {
+		dropped.Add(1)
+	})
+	consumed := make(chan string)
+	release := make(chan struct{})
+	q.StartConsumers(1, func(item any) {
+		consumed <- item.(string)
+		<-release
+	})
+
+	require.True(t, q.ProduceWithTimeout("a", time.Minute))
+	assert.Equal(t, "a", <-consumed)
+	require.True(t, q.ProduceWithTimeout("b", time.Minute), "queue has room")
+
+	// the queue is full and the consumer blocked
+	assert.False(t, q.ProduceWithTimeout("c", 10*time.Millisecond))
+	assert.EqualValues(t, 1, dropped.Load())
+
+	produced := make(chan bool)
+	go func() {
+		produced <- q.ProduceWithTimeout("d", time.Minute)
+	}()
+	release <- struct{}{}
+	assert.Equal(t, "b", <-consumed)
+	assert.True(t, <-produced, "waits for the consumer to make room")
+	release <- struct{}{}
+	assert.Equal(t, "d", <-consumed)
+	close(release)
+
+	q.Stop()
+	assert.False(t, q.ProduceWithTimeout("e", time.Minute), "stopped queue does not block")
+	assert.EqualValues(t, 2, dropped.Load())
+}
+
 func BenchmarkBoundedQueue(b *testing.B) {
 	q := NewBoundedQueue(1000, func( /* item */ any) {})
 	q.StartConsumers(10, func( /* item */ any) {})
@@ -16,6 +16,8 @@ package prometheus
 
 import (
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -24,6 +26,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	promCfg "github.com/jaegertracing/jaeger/pkg/prometheus/config"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/storage"
 )
@@ -96,6 +100,49 @@ func TestWithConfiguration(t *testing.T) {
 		assert.Equal(t, "mynamespace", f.options.Primary.MetricNamespace)
 		assert.Equal(t, "ms", f.options.Primary.LatencyUnit)
 	})
+	t.Run("with additional servers and label presets", func(t *testing.T) {
+		f := NewFactory()
+		v, command := config.Viperize(f.AddFlags)
+		err := command.ParseFlags([]string{
+			"--prometheus.query.label-preset=legacy",
+			"--prometheus.extra-server-urls=http://localhost:1234, otel=http://localhost:5678,http://localhost:9012/?a=b,",
+		})
+		require.NoError(t, err)
+		f.InitFromViper(v, zap.NewNop())
+		assert.Equal(t, "legacy", f.options.Primary.LabelPreset)
+		assert.Equal(t, []promCfg.Datasource{
+			{ServerURL: "http://localhost:1234", LabelPreset: "legacy"},
+			{ServerURL: "http://localhost:5678", LabelPreset: "otel"},
+			{ServerURL: "http://localhost:9012/?a=b", LabelPreset: "legacy"},
+		}, f.options.Primary.Datasources)
+	})
+	t.Run("with datasources file and proxy", func(t *testing.T) {
+		datasourcesFile := filepath.Join(t.TempDir(), "datasources.json")
+		require.NoError(t, os.WriteFile(datasourcesFile, []byte(`[
+			{"server_url": "https://prometheus.eu-west-1:9090", "proxy_url": "http://proxy-eu:3128", "tls": {"ca": "/etc/eu-west-1/ca.pem", "server_name": "prometheus"}},
+			{"server_url": "http://prometheus.us-east-1:9090", "label_preset": "legacy"}
+		]`), 0o600))
+		f := NewFactory()
+		v, command := config.Viperize(f.AddFlags)
+		err := command.ParseFlags([]string{
+			"--prometheus.extra-server-urls=http://localhost:1234",
+			"--prometheus.datasources-file=" + datasourcesFile,
+			"--prometheus.proxy-url=http://proxy:3128",
+		})
+		require.NoError(t, err)
+		f.InitFromViper(v, zap.NewNop())
+		assert.Equal(t, "http://proxy:3128", f.options.Primary.ProxyURL)
+		assert.Equal(t, []promCfg.Datasource{
+			{ServerURL: "http://localhost:1234", LabelPreset: "otel"},
+			{
+				ServerURL:   "https://prometheus.eu-west-1:9090",
+				LabelPreset: "otel",
+				ProxyURL:    "http://proxy-eu:3128",
+				TLS:         &tlscfg.Options{Enabled: true, CAPath: "/etc/eu-west-1/ca.pem", ServerName: "prometheus"},
+			},
+			{ServerURL: "http://prometheus.us-east-1:9090", LabelPreset: "legacy"},
+		}, f.options.Primary.Datasources)
+	})
 	t.Run("with invalid prometheus.query.duration-unit", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r == nil {
@@ -114,6 +161,58 @@ func TestWithConfiguration(t *testing.T) {
 	})
 }
 
+func TestInvalidLabelPreset(t *testing.T) {
+	for _, tc := range []struct {
+		flag    string
+		wantErr string
+	}{
+		{
+			flag:    "--prometheus.query.label-preset=prometheus",
+			wantErr: `label-preset must be one of "otel" or "legacy", not "prometheus"`,
+		},
+		{
+			flag:    "--prometheus.extra-server-urls=prometheus=http://localhost:1234",
+			wantErr: `label preset of http://localhost:1234 must be one of "otel" or "legacy", not "prometheus"`,
+		},
+	} {
+		t.Run(tc.flag, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags([]string{tc.flag}))
+			require.EqualError(t, f.options.InitFromViper(v), tc.wantErr)
+		})
+	}
+}
+
+func TestInvalidDatasourcesFile(t *testing.T) {
+	datasourcesFile := filepath.Join(t.TempDir(), "datasources.json")
+	for _, tc := range []struct {
+		content string
+		wantErr string
+	}{
+		{
+			content: `[{"url": "http://localhost:1234"}]`,
+			wantErr: "failed to parse Prometheus datasources",
+		},
+		{
+			content: `[{"label_preset": "otel"}]`,
+			wantErr: "a Prometheus datasource of " + datasourcesFile + " has no server_url",
+		},
+		{
+			content: `[{"server_url": "http://localhost:1234", "label_preset": "prometheus"}]`,
+			wantErr: `label preset of http://localhost:1234 must be one of "otel" or "legacy", not "prometheus"`,
+		},
+	} {
+		t.Run(tc.content, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(datasourcesFile, []byte(tc.content), 0o600))
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags([]string{"--prometheus.datasources-file=" + datasourcesFile}))
+			require.ErrorContains(t, f.options.InitFromViper(v), tc.wantErr)
+		})
+	}
+}
+
 func TestFailedTLSOptions(t *testing.T) {
 	f := NewFactory()
 	v, command := config.Viperize(f.AddFlags)
@@ -17,12 +17,15 @@ package metricsstore
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -35,6 +38,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/prometheus/config"
 	"github.com/jaegertracing/jaeger/plugin/metrics/prometheus/metricsstore/dbmodel"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
@@ -48,9 +52,15 @@ const (
 type (
 	// MetricsReader is a Prometheus metrics reader.
 	MetricsReader struct {
-		client promapi.API
-		logger *zap.Logger
-		tracer trace.Tracer
+		datasources []datasource
+		logger      *zap.Logger
+		tracer      trace.Tracer
+	}
+
+	// datasource is a Prometheus server along with the names of its span metrics and labels.
+	datasource struct {
+		client    promapi.API
+		serverURL string
 
 		metricsTranslator dbmodel.Translator
 		latencyMetricName string
@@ -59,10 +69,12 @@ type (
 	}
 
 	promQueryParams struct {
-		groupBy        string
-		spanKindFilter string
-		serviceFilter  string
-		rate           string
+		groupBy           string
+		spanKindFilter    string
+		serviceFilter     string
+		rate              string
+		latencyMetricName string
+		callsMetricName   string
 	}
 
 	metricsQueryParams struct {
@@ -82,31 +94,73 @@ func NewMetricsReader(cfg config.Configuration, logger *zap.Logger, tracer trace
 	if err != nil {
 		return nil, err
 	}
-	client, err := api.NewClient(api.Config{
-		Address:      cfg.ServerURL,
-		RoundTripper: roundTripper,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize prometheus client: %w", err)
-	}
-
-	const operationLabel = "span_name"
 
 	mr := &MetricsReader{
-		client: promapi.NewAPI(client),
 		logger: logger,
 		tracer: tracer.Tracer("prom-metrics-reader"),
-
-		metricsTranslator: dbmodel.New(operationLabel),
-		callsMetricName:   buildFullCallsMetricName(cfg),
-		latencyMetricName: buildFullLatencyMetricName(cfg),
-		operationLabel:    operationLabel,
 	}
-
-	logger.Info("Prometheus reader initialized", zap.String("addr", cfg.ServerURL))
+	sources := append([]config.Datasource{{ServerURL: cfg.ServerURL, LabelPreset: cfg.LabelPreset}}, cfg.Datasources...)
+	for _, source := range sources {
+		rt := roundTripper
+		if source.TLS != nil || source.ProxyURL != "" {
+			tlsOpts, proxyURL := &cfg.TLS, cfg.ProxyURL
+			if source.TLS != nil {
+				tlsOpts = source.TLS
+			}
+			if source.ProxyURL != "" {
+				proxyURL = source.ProxyURL
+			}
+			if rt, err = newHTTPRoundTripper(&cfg, tlsOpts, proxyURL, logger); err != nil {
+				return nil, fmt.Errorf("failed to initialize the connection to Prometheus server %s: %w", source.ServerURL, err)
+			}
+		}
+		ds, err := newDatasource(cfg, source, rt)
+		if err != nil {
+			return nil, err
+		}
+		mr.datasources = append(mr.datasources, ds)
+		logger.Info("Prometheus reader initialized", zap.String("addr", source.ServerURL))
+	}
 	return mr, nil
 }
 
+func newDatasource(cfg config.Configuration, source config.Datasource, roundTripper http.RoundTripper) (datasource, error) {
+	client, err := api.NewClient(api.Config{
+		Address:      source.ServerURL,
+		RoundTripper: roundTripper,
+	})
+	if err != nil {
+		return datasource{}, fmt.Errorf("failed to initialize prometheus client: %w", err)
+	}
+
+	ds := datasource{
+		client:    promapi.NewAPI(client),
+		serverURL: source.ServerURL,
+	}
+	switch source.LabelPreset {
+	case "", config.LabelPresetOTel:
+		ds.operationLabel = "span_name"
+		ds.callsMetricName = buildFullCallsMetricName(cfg)
+		ds.latencyMetricName = buildFullLatencyMetricName(cfg)
+	case config.LabelPresetLegacy:
+		// The spanmetrics processor names its metrics regardless of the normalization settings.
+		ds.operationLabel = "operation"
+		ds.callsMetricName = withMetricNamespace(cfg, "calls_total")
+		ds.latencyMetricName = withMetricNamespace(cfg, "latency")
+	default:
+		return datasource{}, fmt.Errorf("unknown label preset %q of Prometheus server %s", source.LabelPreset, source.ServerURL)
+	}
+	ds.metricsTranslator = dbmodel.New(ds.operationLabel)
+	return ds, nil
+}
+
+func withMetricNamespace(cfg config.Configuration, metricName string) string {
+	if cfg.MetricNamespace != "" {
+		return cfg.MetricNamespace + "_" + metricName
+	}
+	return metricName
+}
+
 // GetLatencies gets the latency metrics for the given set of latency query parameters.
 func (m MetricsReader) GetLatencies(ctx context.Context, requestParams *metricsstore.LatenciesQueryParameters) (*metrics.MetricFamily, error) {
 	metricsParams := metricsQueryParams{
@@ -119,7 +173,7 @@ func (m MetricsReader) GetLatencies(ctx context.Context, requestParams *metricss
 				// Note: p.spanKindFilter can be ""; trailing commas are okay within a timeseries selection.
 				`histogram_quantile(%.2f, sum(rate(%s_bucket{service_name =~ "%s", %s}[%s])) by (%s))`,
 				requestParams.Quantile,
-				m.latencyMetricName,
+				p.latencyMetricName,
 				p.serviceFilter,
 				p.spanKindFilter,
 				p.rate,
@@ -131,11 +185,7 @@ func (m MetricsReader) GetLatencies(ctx context.Context, requestParams *metricss
 }
 
 func buildFullLatencyMetricName(cfg config.Configuration) string {
-	metricName := "duration"
-
-	if cfg.MetricNamespace != "" {
-		metricName = cfg.MetricNamespace + "_" + metricName
-	}
+	metricName := withMetricNamespace(cfg, "duration")
 
 	if !cfg.NormalizeDuration {
 		return metricName
@@ -161,7 +211,7 @@ func (m MetricsReader) GetCallRates(ctx context.Context, requestParams *metricss
 			return fmt.Sprintf(
 				// Note: p.spanKindFilter can be ""; trailing commas are okay within a timeseries selection.
 				`sum(rate(%s{service_name =~ "%s", %s}[%s])) by (%s)`,
-				m.callsMetricName,
+				p.callsMetricName,
 				p.serviceFilter,
 				p.spanKindFilter,
 				p.rate,
@@ -173,10 +223,7 @@ func (m MetricsReader) GetCallRates(ctx context.Context, requestParams *metricss
 }
 
 func buildFullCallsMetricName(cfg config.Configuration) string {
-	metricName := "calls"
-	if cfg.MetricNamespace != "" {
-		metricName = cfg.MetricNamespace + "_" + metricName
-	}
+	metricName := withMetricNamespace(cfg, "calls")
 
 	if !cfg.NormalizeCalls {
 		return metricName
@@ -195,8 +242,8 @@ func (m MetricsReader) GetErrorRates(ctx context.Context, requestParams *metrics
 			return fmt.Sprintf(
 				// Note: p.spanKindFilter can be ""; trailing commas are okay within a timeseries selection.
 				`sum(rate(%s{service_name =~ "%s", status_code = "STATUS_CODE_ERROR", %s}[%s])) by (%s) / sum(rate(%s{service_name =~ "%s", %s}[%s])) by (%s)`,
-				m.callsMetricName, p.serviceFilter, p.spanKindFilter, p.rate, p.groupBy,
-				m.callsMetricName, p.serviceFilter, p.spanKindFilter, p.rate, p.groupBy,
+				p.callsMetricName, p.serviceFilter, p.spanKindFilter, p.rate, p.groupBy,
+				p.callsMetricName, p.serviceFilter, p.spanKindFilter, p.rate, p.groupBy,
 			)
 		},
 	}
@@ -239,13 +286,53 @@ func (MetricsReader) GetMinStepDuration(_ context.Context, _ *metricsstore.MinSt
 	return minStep, nil
 }
 
-// executeQuery executes a query against a Prometheus-compliant metrics backend.
+// executeQuery executes a query against all the Prometheus-compliant metrics backends and merges their metrics.
+// The metrics of the backends that fail are left out unless all of them fail.
 func (m MetricsReader) executeQuery(ctx context.Context, p metricsQueryParams) (*metrics.MetricFamily, error) {
 	if p.GroupByOperation {
 		p.metricName = strings.Replace(p.metricName, "service", "service_operation", 1)
 		p.metricDesc += " & operation"
 	}
-	promQuery := m.buildPromQuery(p)
+
+	families := make([]*metrics.MetricFamily, len(m.datasources))
+	errs := make([]error, len(m.datasources))
+	var wg sync.WaitGroup
+	for i := range m.datasources {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			families[i], errs[i] = m.executeDatasourceQuery(ctx, m.datasources[i], p)
+		}(i)
+	}
+	wg.Wait()
+
+	var merged *metrics.MetricFamily
+	var failed []error
+	for i, family := range families {
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
+			continue
+		}
+		if merged == nil {
+			merged = family
+		} else {
+			merged.Metrics = append(merged.Metrics, family.Metrics...)
+		}
+	}
+	if merged == nil {
+		return &metrics.MetricFamily{}, errors.Join(failed...)
+	}
+	for i, err := range errs {
+		if err != nil {
+			m.logger.Warn("Leaving out the metrics of a failed Prometheus server", zap.String("addr", m.datasources[i].serverURL), zap.Error(err))
+		}
+	}
+	return merged, nil
+}
+
+// executeDatasourceQuery executes a query against a Prometheus-compliant metrics backend.
+func (m MetricsReader) executeDatasourceQuery(ctx context.Context, ds datasource, p metricsQueryParams) (*metrics.MetricFamily, error) {
+	promQuery := ds.buildPromQuery(p)
 
 	ctx, span := startSpanForQuery(ctx, p.metricName, promQuery, m.tracer)
 	defer span.End()
@@ -256,7 +343,7 @@ func (m MetricsReader) executeQuery(ctx context.Context, p metricsQueryParams) (
 		Step:  *p.Step,
 	}
 
-	mv, warnings, err := m.client.QueryRange(ctx, promQuery, queryRange)
+	mv, warnings, err := ds.client.QueryRange(ctx, promQuery, queryRange)
 	if err != nil {
 		err = fmt.Errorf("failed executing metrics query: %w", err)
 		logErrorToSpan(span, err)
@@ -268,17 +355,17 @@ func (m MetricsReader) executeQuery(ctx context.Context, p metricsQueryParams) (
 
 	m.logger.Debug("Prometheus query results", zap.String("results", mv.String()), zap.String("query", promQuery), zap.Any("range", queryRange))
 
-	return m.metricsTranslator.ToDomainMetricsFamily(
+	return ds.metricsTranslator.ToDomainMetricsFamily(
 		p.metricName,
 		p.metricDesc,
 		mv,
 	)
 }
 
-func (m MetricsReader) buildPromQuery(metricsParams metricsQueryParams) string {
+func (ds datasource) buildPromQuery(metricsParams metricsQueryParams) string {
 	groupBy := []string{"service_name"}
 	if metricsParams.GroupByOperation {
-		groupBy = append(groupBy, m.operationLabel)
+		groupBy = append(groupBy, ds.operationLabel)
 	}
 	if metricsParams.groupByHistBucket {
 		// Group by the bucket value ("le" => "less than or equal to").
@@ -290,10 +377,12 @@ func (m MetricsReader) buildPromQuery(metricsParams metricsQueryParams) string {
 		spanKindFilter = fmt.Sprintf(`span_kind =~ "%s"`, strings.Join(metricsParams.SpanKinds, "|"))
 	}
 	promParams := promQueryParams{
-		serviceFilter:  strings.Join(metricsParams.ServiceNames, "|"),
-		spanKindFilter: spanKindFilter,
-		rate:           promqlDurationString(metricsParams.RatePer),
-		groupBy:        strings.Join(groupBy, ","),
+		serviceFilter:     strings.Join(metricsParams.ServiceNames, "|"),
+		spanKindFilter:    spanKindFilter,
+		rate:              promqlDurationString(metricsParams.RatePer),
+		groupBy:           strings.Join(groupBy, ","),
+		latencyMetricName: ds.latencyMetricName,
+		callsMetricName:   ds.callsMetricName,
 	}
 	return metricsParams.buildPromQuery(promParams)
 }
@@ -327,16 +416,30 @@ func logErrorToSpan(span trace.Span, err error) {
 }
 
 func getHTTPRoundTripper(c *config.Configuration, logger *zap.Logger) (rt http.RoundTripper, err error) {
+	return newHTTPRoundTripper(c, &c.TLS, c.ProxyURL, logger)
+}
+
+// newHTTPRoundTripper returns the round tripper of the Configuration with the TLS settings and
+// proxy of a Prometheus server.
+func newHTTPRoundTripper(c *config.Configuration, tlsOpts *tlscfg.Options, proxyURL string, logger *zap.Logger) (rt http.RoundTripper, err error) {
 	var ctlsConfig *tls.Config
-	if c.TLS.Enabled {
-		if ctlsConfig, err = c.TLS.Config(logger); err != nil {
+	if tlsOpts.Enabled {
+		if ctlsConfig, err = tlsOpts.Config(logger); err != nil {
 			return nil, err
 		}
 	}
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
 	// KeepAlive and TLSHandshake timeouts are kept to existing Prometheus client's
 	// DefaultRoundTripper to simplify user configuration and may be made configurable when required.
 	httpTransport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
+		Proxy: proxy,
 		DialContext: (&net.Dialer{
 			Timeout:   c.ConnectTimeout,
 			KeepAlive: 30 * time.Second,
@@ -16,12 +16,14 @@ package metricsstore
 
 import (
 	"context"
+	"encoding/pem"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -239,6 +241,24 @@ func TestGetLatencies(t *testing.T) {
 			wantPromQlQuery: `histogram_quantile(0.95, sum(rate(duration_seconds_bucket{service_name =~ "emailservice", ` +
 				`span_kind =~ "SPAN_KIND_SERVER"}[10m])) by (service_name,span_name,le))`,
 		},
+		{
+			name:             "legacy label preset ignores the metric name normalization",
+			serviceNames:     []string{"emailservice"},
+			spanKinds:        []string{"SPAN_KIND_SERVER"},
+			groupByOperation: false,
+			updateConfig: func(cfg config.Configuration) config.Configuration {
+				cfg.LabelPreset = config.LabelPresetLegacy
+				cfg.NormalizeDuration = true
+				return cfg
+			},
+			wantName:        "service_latencies",
+			wantDescription: "0.95th quantile latency, grouped by service",
+			wantLabels: map[string]string{
+				"service_name": "emailservice",
+			},
+			wantPromQlQuery: `histogram_quantile(0.95, sum(rate(latency_bucket{service_name =~ "emailservice", ` +
+				`span_kind =~ "SPAN_KIND_SERVER"}[10m])) by (service_name,le))`,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			params := metricsstore.LatenciesQueryParameters{
@@ -340,6 +360,24 @@ func TestGetCallRates(t *testing.T) {
 			wantPromQlQuery: `sum(rate(calls_total{service_name =~ "emailservice", ` +
 				`span_kind =~ "SPAN_KIND_SERVER"}[10m])) by (service_name,span_name)`,
 		},
+		{
+			name:             "legacy label preset with a namespace",
+			serviceNames:     []string{"emailservice"},
+			spanKinds:        []string{"SPAN_KIND_SERVER"},
+			groupByOperation: false,
+			updateConfig: func(cfg config.Configuration) config.Configuration {
+				cfg.LabelPreset = config.LabelPresetLegacy
+				cfg.MetricNamespace = "span_metrics"
+				return cfg
+			},
+			wantName:        "service_call_rate",
+			wantDescription: "calls/sec, grouped by service",
+			wantLabels: map[string]string{
+				"service_name": "emailservice",
+			},
+			wantPromQlQuery: `sum(rate(span_metrics_calls_total{service_name =~ "emailservice", ` +
+				`span_kind =~ "SPAN_KIND_SERVER"}[10m])) by (service_name)`,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			params := metricsstore.CallRateQueryParameters{
@@ -688,6 +726,161 @@ func TestGetErrorRatesErrors(t *testing.T) {
 	}
 }
 
+func TestMultipleDatasources(t *testing.T) {
+	params := metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: buildTestBaseQueryParametersFrom(metricsTestCase{
+			serviceNames:     []string{"emailservice"},
+			groupByOperation: true,
+		}),
+	}
+	otelPrometheus := startMockPrometheusServer(t, `sum(rate(calls{service_name =~ "emailservice", }[10m])) by (service_name,span_name)`, nil)
+	defer otelPrometheus.Close()
+	legacyPrometheus := startMockPrometheusServer(t, `sum(rate(calls_total{service_name =~ "emailservice", }[10m])) by (service_name,operation)`, nil)
+	defer legacyPrometheus.Close()
+	failingPrometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}))
+	defer failingPrometheus.Close()
+
+	tracer, exp, closer := tracerProvider(t)
+	defer closer()
+	reader, err := NewMetricsReader(config.Configuration{
+		ServerURL:      otelPrometheus.URL,
+		ConnectTimeout: defaultTimeout,
+		LatencyUnit:    "ms",
+		Datasources: []config.Datasource{
+			{ServerURL: legacyPrometheus.URL, LabelPreset: config.LabelPresetLegacy},
+			{ServerURL: failingPrometheus.URL},
+		},
+	}, zap.NewNop(), tracer)
+	require.NoError(t, err)
+
+	m, err := reader.GetCallRates(context.Background(), &params)
+	require.NoError(t, err, "the metrics of the failing server are left out")
+	assert.Equal(t, "service_operation_call_rate", m.Name)
+	assert.Len(t, m.Metrics, 2, "the metrics of the otel and legacy servers are merged")
+	assert.Len(t, exp.GetSpans(), 3, "a query is traced for each server")
+}
+
+func TestAllDatasourcesFail(t *testing.T) {
+	failingPrometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}))
+	defer failingPrometheus.Close()
+
+	tracer, _, closer := tracerProvider(t)
+	defer closer()
+	reader, err := NewMetricsReader(config.Configuration{
+		ServerURL:      failingPrometheus.URL,
+		ConnectTimeout: defaultTimeout,
+		Datasources:    []config.Datasource{{ServerURL: failingPrometheus.URL}},
+	}, zap.NewNop(), tracer)
+	require.NoError(t, err)
+
+	params := metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: buildTestBaseQueryParametersFrom(metricsTestCase{serviceNames: []string{"emailservice"}}),
+	}
+	m, err := reader.GetCallRates(context.Background(), &params)
+	assert.NotNil(t, m)
+	require.ErrorContains(t, err, "failed executing metrics query")
+}
+
+func TestDatasourceConnectionSettings(t *testing.T) {
+	const wantPromQlQuery = `sum(rate(calls{service_name =~ "emailservice", }[10m])) by (service_name)`
+	params := metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: buildTestBaseQueryParametersFrom(metricsTestCase{serviceNames: []string{"emailservice"}}),
+	}
+	failingPrometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}))
+	defer failingPrometheus.Close()
+
+	tlsPrometheus := httptest.NewTLSServer(mockPrometheusHandler(t, wantPromQlQuery, nil))
+	defer tlsPrometheus.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsPrometheus.Certificate().Raw}), 0o600))
+	tlsOpts := &tlscfg.Options{Enabled: true, CAPath: caFile}
+	defer tlsOpts.Close()
+
+	var proxiedHost atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost.Store(r.URL.Host)
+		r.RequestURI = r.URL.RequestURI()
+		mockPrometheusHandler(t, wantPromQlQuery, nil)(w, r)
+	}))
+	defer proxy.Close()
+
+	for _, tc := range []struct {
+		name       string
+		datasource config.Datasource
+	}{
+		{
+			name:       "custom CA",
+			datasource: config.Datasource{ServerURL: tlsPrometheus.URL, TLS: tlsOpts},
+		},
+		{
+			name:       "proxy",
+			datasource: config.Datasource{ServerURL: "http://prometheus.cluster-b:9090", ProxyURL: proxy.URL},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tracer, _, closer := tracerProvider(t)
+			defer closer()
+			reader, err := NewMetricsReader(config.Configuration{
+				ServerURL:      failingPrometheus.URL,
+				ConnectTimeout: defaultTimeout,
+				Datasources:    []config.Datasource{tc.datasource},
+			}, zap.NewNop(), tracer)
+			require.NoError(t, err)
+
+			m, err := reader.GetCallRates(context.Background(), &params)
+			require.NoError(t, err, "the datasource is reached with its own connection settings")
+			assert.Len(t, m.Metrics, 1)
+		})
+	}
+	assert.Equal(t, "prometheus.cluster-b:9090", proxiedHost.Load())
+
+	tracer, _, closer := tracerProvider(t)
+	defer closer()
+	_, err := NewMetricsReader(config.Configuration{
+		ServerURL:      failingPrometheus.URL,
+		ConnectTimeout: defaultTimeout,
+		Datasources:    []config.Datasource{{ServerURL: "http://localhost:1234", ProxyURL: "http://proxy:port"}},
+	}, zap.NewNop(), tracer)
+	require.ErrorContains(t, err, "failed to initialize the connection to Prometheus server http://localhost:1234: invalid proxy URL")
+}
+
+func TestNewMetricsReaderInvalidDatasource(t *testing.T) {
+	tracer, _, closer := tracerProvider(t)
+	defer closer()
+	for _, tc := range []struct {
+		name       string
+		datasource config.Datasource
+		wantErr    string
+	}{
+		{
+			name:       "invalid address",
+			datasource: config.Datasource{ServerURL: "\n"},
+			wantErr:    "failed to initialize prometheus client",
+		},
+		{
+			name:       "unknown label preset",
+			datasource: config.Datasource{ServerURL: "http://localhost:1234", LabelPreset: "prometheus"},
+			wantErr:    `unknown label preset "prometheus" of Prometheus server http://localhost:1234`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, err := NewMetricsReader(config.Configuration{
+				ServerURL:      "http://localhost:5678",
+				ConnectTimeout: defaultTimeout,
+				Datasources:    []config.Datasource{tc.datasource},
+			}, zap.NewNop(), tracer)
+			require.ErrorContains(t, err, tc.wantErr)
+			assert.Nil(t, reader)
+		})
+	}
+}
+
 func TestInvalidLatencyUnit(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -880,7 +1073,11 @@ func TestInvalidCertFile(t *testing.T) {
 }
 
 func startMockPrometheusServer(t *testing.T, wantPromQlQuery string, wantWarnings []string) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return httptest.NewServer(mockPrometheusHandler(t, wantPromQlQuery, wantWarnings))
+}
+
+func mockPrometheusHandler(t *testing.T, wantPromQlQuery string, wantWarnings []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		if len(wantWarnings) > 0 {
 			sendResponse(t, w, "testdata/warning_response.json")
 			return
@@ -901,7 +1098,7 @@ func startMockPrometheusServer(t *testing.T, wantPromQlQuery string, wantWarning
 			mockResponsePayloadFile = "testdata/service_span_name_datapoint_response.json"
 		}
 		sendResponse(t, w, mockResponsePayloadFile)
-	}))
+	}
 }
 
 func sendResponse(t *testing.T, w http.ResponseWriter, responseFile string) {
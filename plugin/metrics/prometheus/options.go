@@ -15,8 +15,11 @@
 package prometheus
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -31,11 +34,15 @@ const (
 	suffixConnectTimeout      = ".connect-timeout"
 	suffixTokenFilePath       = ".token-file"
 	suffixOverrideFromContext = ".token-override-from-context"
+	suffixExtraServerURLs     = ".extra-server-urls"
+	suffixDatasourcesFile     = ".datasources-file"
+	suffixProxyURL            = ".proxy-url"
 
 	suffixMetricNamespace   = ".query.namespace"
 	suffixLatencyUnit       = ".query.duration-unit"
 	suffixNormalizeCalls    = ".query.normalize-calls"
 	suffixNormalizeDuration = ".query.normalize-duration"
+	suffixLabelPreset       = ".query.label-preset"
 
 	defaultServerURL      = "http://localhost:9090"
 	defaultConnectTimeout = 30 * time.Second
@@ -46,6 +53,7 @@ const (
 	defaultLatencyUnit                 = "ms"
 	defaultNormalizeCalls              = false
 	defaultNormalizeDuration           = false
+	defaultLabelPreset                 = config.LabelPresetOTel
 )
 
 type namespaceConfig struct {
@@ -68,6 +76,7 @@ func NewOptions(primaryNamespace string) *Options {
 		LatencyUnit:       defaultLatencyUnit,
 		NormalizeCalls:    defaultNormalizeCalls,
 		NormalizeDuration: defaultNormalizeCalls,
+		LabelPreset:       defaultLabelPreset,
 	}
 
 	return &Options{
@@ -89,6 +98,18 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		"The path to a file containing the bearer token which will be included when executing queries against the Prometheus API.")
 	flagSet.Bool(nsConfig.namespace+suffixOverrideFromContext, true,
 		"Whether the bearer token should be overridden from context (incoming request)")
+	flagSet.String(nsConfig.namespace+suffixExtraServerURLs, "",
+		`The comma-separated list of additional Prometheus servers' URLs, whose span metrics are merged with the ones of the server-url. `+
+			`Each URL may be prefixed with the label preset of the server and "=", e.g. legacy=http://prometheus-legacy:9090, `+
+			`and uses the query.label-preset otherwise. The servers share the connection settings of the server-url.`)
+	flagSet.String(nsConfig.namespace+suffixDatasourcesFile, "",
+		`The path to a JSON file with a list of additional Prometheus servers, whose span metrics are merged with the ones of the server-url, `+
+			`e.g. [{"server_url": "https://prometheus.eu-west-1:9090", "label_preset": "otel", "proxy_url": "http://proxy:3128", `+
+			`"tls": {"ca": "/etc/eu-west-1/ca.pem", "cert": "/etc/eu-west-1/cert.pem", "key": "/etc/eu-west-1/key.pem", "server_name": "", "skip_host_verify": false}}]. `+
+			`The servers share the connection settings of the server-url, unless they set their own proxy or TLS settings, `+
+			`whose certificates are reloaded when their files change.`)
+	flagSet.String(nsConfig.namespace+suffixProxyURL, "",
+		"The URL of the HTTP proxy of the Prometheus servers, the one of the HTTP_PROXY and HTTPS_PROXY environment variables if empty.")
 	flagSet.String(nsConfig.namespace+suffixMetricNamespace, defaultMetricNamespace,
 		`The metric namespace that is prefixed to the metric name. A '.' separator will be added between `+
 			`the namespace and the metric name.`)
@@ -107,6 +128,10 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 			`https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/main/pkg/translator/prometheus/README.md. `+
 			`For example: `+
 			`"duration_bucket" (not normalized) -> "duration_milliseconds_bucket (normalized)"`)
+	flagSet.String(nsConfig.namespace+suffixLabelPreset, defaultLabelPreset,
+		`The naming of the span metrics and their labels. It can be either "otel" for the spanmetrics connector, `+
+			`or "legacy" for the deprecated spanmetrics processor, whose metrics are "calls_total" and "latency" `+
+			`with an "operation" label and are not affected by the normalization options.`)
 
 	nsConfig.getTLSFlagsConfig().AddFlags(flagSet)
 }
@@ -117,19 +142,35 @@ func (opt *Options) InitFromViper(v *viper.Viper) error {
 	cfg.ServerURL = stripWhiteSpace(v.GetString(cfg.namespace + suffixServerURL))
 	cfg.ConnectTimeout = v.GetDuration(cfg.namespace + suffixConnectTimeout)
 	cfg.TokenFilePath = v.GetString(cfg.namespace + suffixTokenFilePath)
+	cfg.ProxyURL = v.GetString(cfg.namespace + suffixProxyURL)
 
 	cfg.MetricNamespace = v.GetString(cfg.namespace + suffixMetricNamespace)
 	cfg.LatencyUnit = v.GetString(cfg.namespace + suffixLatencyUnit)
 	cfg.NormalizeCalls = v.GetBool(cfg.namespace + suffixNormalizeCalls)
 	cfg.NormalizeDuration = v.GetBool(cfg.namespace + suffixNormalizeDuration)
 	cfg.TokenOverrideFromContext = v.GetBool(cfg.namespace + suffixOverrideFromContext)
+	cfg.LabelPreset = v.GetString(cfg.namespace + suffixLabelPreset)
 
 	isValidUnit := map[string]bool{"ms": true, "s": true}
 	if _, ok := isValidUnit[cfg.LatencyUnit]; !ok {
 		return fmt.Errorf(`duration-unit must be one of "ms" or "s", not %q`, cfg.LatencyUnit)
 	}
+	if !isValidLabelPreset(cfg.LabelPreset) {
+		return fmt.Errorf(`label-preset must be one of "otel" or "legacy", not %q`, cfg.LabelPreset)
+	}
+	datasources, err := parseDatasources(stripWhiteSpace(v.GetString(cfg.namespace+suffixExtraServerURLs)), cfg.LabelPreset)
+	if err != nil {
+		return err
+	}
+	if path := v.GetString(cfg.namespace + suffixDatasourcesFile); path != "" {
+		fileDatasources, err := loadDatasources(path, cfg.LabelPreset)
+		if err != nil {
+			return err
+		}
+		datasources = append(datasources, fileDatasources...)
+	}
+	cfg.Datasources = datasources
 
-	var err error
 	cfg.TLS, err = cfg.getTLSFlagsConfig().InitFromViper(v)
 	if err != nil {
 		return fmt.Errorf("failed to process Prometheus TLS options: %w", err)
@@ -144,6 +185,88 @@ func (config *namespaceConfig) getTLSFlagsConfig() tlscfg.ClientFlagsConfig {
 }
 
 // stripWhiteSpace removes all whitespace characters from a string.
+func isValidLabelPreset(preset string) bool {
+	return preset == config.LabelPresetOTel || preset == config.LabelPresetLegacy
+}
+
+// parseDatasources parses a comma-separated list of server URLs, each optionally prefixed with "<label preset>=".
+func parseDatasources(serverURLs string, defaultLabelPreset string) ([]config.Datasource, error) {
+	if serverURLs == "" {
+		return nil, nil
+	}
+	var datasources []config.Datasource
+	for _, serverURL := range strings.Split(serverURLs, ",") {
+		if serverURL == "" {
+			continue
+		}
+		ds := config.Datasource{ServerURL: serverURL, LabelPreset: defaultLabelPreset}
+		if preset, url, found := strings.Cut(serverURL, "="); found && !strings.Contains(preset, "/") {
+			if !isValidLabelPreset(preset) {
+				return nil, fmt.Errorf(`label preset of %s must be one of "otel" or "legacy", not %q`, url, preset)
+			}
+			ds = config.Datasource{ServerURL: url, LabelPreset: preset}
+		}
+		datasources = append(datasources, ds)
+	}
+	return datasources, nil
+}
+
+// datasourceEntry is a Prometheus server of the datasources file.
+type datasourceEntry struct {
+	ServerURL   string `json:"server_url"`
+	LabelPreset string `json:"label_preset"`
+	ProxyURL    string `json:"proxy_url"`
+	TLS         *struct {
+		CAPath         string `json:"ca"`
+		CertPath       string `json:"cert"`
+		KeyPath        string `json:"key"`
+		ServerName     string `json:"server_name"`
+		SkipHostVerify bool   `json:"skip_host_verify"`
+	} `json:"tls"`
+}
+
+// loadDatasources reads a JSON array of datasourceEntry from a file.
+func loadDatasources(path string, defaultLabelPreset string) ([]config.Datasource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Prometheus datasources: %w", err)
+	}
+	var entries []datasourceEntry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse Prometheus datasources %s: %w", path, err)
+	}
+	datasources := make([]config.Datasource, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ServerURL == "" {
+			return nil, fmt.Errorf("a Prometheus datasource of %s has no server_url", path)
+		}
+		ds := config.Datasource{
+			ServerURL:   entry.ServerURL,
+			LabelPreset: entry.LabelPreset,
+			ProxyURL:    entry.ProxyURL,
+		}
+		if ds.LabelPreset == "" {
+			ds.LabelPreset = defaultLabelPreset
+		} else if !isValidLabelPreset(ds.LabelPreset) {
+			return nil, fmt.Errorf(`label preset of %s must be one of "otel" or "legacy", not %q`, ds.ServerURL, ds.LabelPreset)
+		}
+		if entry.TLS != nil {
+			ds.TLS = &tlscfg.Options{
+				Enabled:        true,
+				CAPath:         entry.TLS.CAPath,
+				CertPath:       entry.TLS.CertPath,
+				KeyPath:        entry.TLS.KeyPath,
+				ServerName:     entry.TLS.ServerName,
+				SkipHostVerify: entry.TLS.SkipHostVerify,
+			}
+		}
+		datasources = append(datasources, ds)
+	}
+	return datasources, nil
+}
+
 func stripWhiteSpace(str string) string {
 	return strings.ReplaceAll(str, " ", "")
 }
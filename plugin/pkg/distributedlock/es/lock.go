@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+)
+
+// Lock is a distributed lock based off Elasticsearch, storing a lease document per resource
+// and changing it with optimistic concurrency control.
+type Lock struct {
+	client   func() es.Client
+	index    string
+	tenantID string
+	now      func() time.Time
+}
+
+const (
+	defaultTTL = 60 * time.Second
+
+	leasesIndex          = "jaeger-leases"
+	indexPrefixSeparator = "-"
+)
+
+var errLockOwnership = errors.New("this host does not own the resource lock")
+
+// lease is the document of the lock of a resource.
+type lease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewLock creates a new instance of a distributed locking mechanism based off Elasticsearch,
+// storing the leases in the jaeger-leases index with the prefix.
+func NewLock(client func() es.Client, indexPrefix string, tenantID string) *Lock {
+	index := leasesIndex
+	if indexPrefix != "" {
+		index = indexPrefix + indexPrefixSeparator + leasesIndex
+	}
+	return &Lock{
+		client:   client,
+		index:    index,
+		tenantID: tenantID,
+		now:      time.Now,
+	}
+}
+
+// Acquire acquires a lease around a given resource, taking over the lease of another host if it expired.
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	ctx := context.Background()
+	doc := l.client().Document(l.index, resource)
+	newLease := lease{Owner: l.tenantID, ExpiresAt: l.now().Add(ttl)}
+	created, err := doc.Create(ctx, newLease)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire resource lock due to elasticsearch error: %w", err)
+	}
+	if created {
+		// The lock was successfully created
+		return true, nil
+	}
+	current, err := l.getLease(ctx, doc)
+	if err != nil || current == nil {
+		// The lock was forfeited since it was created, it is acquired on the next attempt
+		return false, err
+	}
+	if current.lease.Owner != l.tenantID && l.now().Before(current.lease.ExpiresAt) {
+		return false, nil
+	}
+	// This host already owns the lock or the lease expired, extend or take over the lease
+	updated, err := doc.Update(ctx, newLease, current.version)
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lease on resource lock: %w", err)
+	}
+	return updated, nil
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	ctx := context.Background()
+	doc := l.client().Document(l.index, resource)
+	current, err := l.getLease(ctx, doc)
+	if err != nil {
+		return false, err
+	}
+	if current == nil || current.lease.Owner != l.tenantID {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	deleted, err := doc.Delete(ctx, current.version)
+	if err != nil {
+		return false, fmt.Errorf("failed to forfeit resource lock due to elasticsearch error: %w", err)
+	}
+	if !deleted {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	// The lock was successfully deleted
+	return true, nil
+}
+
+type versionedLease struct {
+	lease   lease
+	version es.DocumentVersion
+}
+
+// getLease returns the current lease of the resource, or nil if there is none.
+func (*Lock) getLease(ctx context.Context, doc es.DocumentService) (*versionedLease, error) {
+	current, err := doc.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource lock due to elasticsearch error: %w", err)
+	}
+	if current == nil {
+		return nil, nil
+	}
+	var l lease
+	if err := json.Unmarshal(current.Source, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse resource lock: %w", err)
+	}
+	return &versionedLease{lease: l, version: current.Version}, nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package es
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+)
+
+const (
+	localhost    = "localhost"
+	samplingLock = "sampling_lock"
+)
+
+var (
+	now          = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	leaseVersion = es.DocumentVersion{SeqNo: 3, PrimaryTerm: 1}
+)
+
+func withLock(t *testing.T, fn func(lock *Lock, doc *mocks.DocumentService)) {
+	doc := mocks.NewDocumentService(t)
+	client := &mocks.Client{}
+	client.On("Document", "prod-jaeger-leases", samplingLock).Return(doc)
+	lock := NewLock(func() es.Client { return client }, "prod", localhost)
+	lock.now = func() time.Time { return now }
+	fn(lock, doc)
+}
+
+func storedLease(t *testing.T, owner string, expiresAt time.Time) *es.VersionedDocument {
+	source, err := json.Marshal(lease{Owner: owner, ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	return &es.VersionedDocument{Source: source, Version: leaseVersion}
+}
+
+func TestNewLockIndex(t *testing.T) {
+	assert.Equal(t, "jaeger-leases", NewLock(nil, "", localhost).index)
+	assert.Equal(t, "prod-jaeger-leases", NewLock(nil, "prod", localhost).index)
+}
+
+func TestAcquire(t *testing.T) {
+	newLease := lease{Owner: localhost, ExpiresAt: now.Add(time.Minute)}
+	testCases := []struct {
+		caption         string
+		created         bool
+		errCreate       error
+		current         *es.VersionedDocument
+		errGet          error
+		updated         bool
+		errUpdate       error
+		expectUpdate    bool
+		expectedAcquire bool
+		expectedErrMsg  string
+	}{
+		{caption: "created", created: true, expectedAcquire: true},
+		{caption: "create error", errCreate: errors.New("failed"), expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: failed"},
+		{caption: "get error", errGet: errors.New("failed"), expectedErrMsg: "failed to read resource lock due to elasticsearch error: failed"},
+		{caption: "forfeited since created"},
+		{caption: "owned by another host", current: storedLease(t, "otherhost", now.Add(time.Second))},
+		{
+			caption: "owned by this host", current: storedLease(t, localhost, now.Add(time.Second)),
+			expectUpdate: true, updated: true, expectedAcquire: true,
+		},
+		{
+			caption: "expired lease of another host", current: storedLease(t, "otherhost", now.Add(-time.Second)),
+			expectUpdate: true, updated: true, expectedAcquire: true,
+		},
+		{
+			caption: "lease changed since read", current: storedLease(t, "otherhost", now.Add(-time.Second)),
+			expectUpdate: true,
+		},
+		{
+			caption: "update error", current: storedLease(t, localhost, now),
+			expectUpdate: true, errUpdate: errors.New("failed"), expectedErrMsg: "failed to extend lease on resource lock: failed",
+		},
+		{
+			caption: "corrupted lease", current: &es.VersionedDocument{Source: []byte("{")},
+			expectedErrMsg: "failed to parse resource lock",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withLock(t, func(lock *Lock, doc *mocks.DocumentService) {
+				doc.On("Create", mock.Anything, newLease).Return(testCase.created, testCase.errCreate)
+				if !testCase.created && testCase.errCreate == nil {
+					doc.On("Get", mock.Anything).Return(testCase.current, testCase.errGet)
+				}
+				if testCase.expectUpdate {
+					doc.On("Update", mock.Anything, newLease, leaseVersion).Return(testCase.updated, testCase.errUpdate)
+				}
+				acquired, err := lock.Acquire(samplingLock, time.Minute)
+				if testCase.expectedErrMsg == "" {
+					require.NoError(t, err)
+				} else {
+					require.ErrorContains(t, err, testCase.expectedErrMsg)
+				}
+				assert.Equal(t, testCase.expectedAcquire, acquired)
+			})
+		})
+	}
+}
+
+func TestAcquireDefaultTTL(t *testing.T) {
+	withLock(t, func(lock *Lock, doc *mocks.DocumentService) {
+		doc.On("Create", mock.Anything, lease{Owner: localhost, ExpiresAt: now.Add(defaultTTL)}).Return(true, nil)
+		acquired, err := lock.Acquire(samplingLock, 0)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func TestForfeit(t *testing.T) {
+	testCases := []struct {
+		caption           string
+		current           *es.VersionedDocument
+		errGet            error
+		expectDelete      bool
+		deleted           bool
+		errDelete         error
+		expectedForfeited bool
+		expectedErrMsg    string
+	}{
+		{
+			caption: "deleted", current: storedLease(t, localhost, now),
+			expectDelete: true, deleted: true, expectedForfeited: true,
+		},
+		{caption: "get error", errGet: errors.New("failed"), expectedErrMsg: "failed to read resource lock due to elasticsearch error: failed"},
+		{caption: "no lease", expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock"},
+		{
+			caption: "owned by another host", current: storedLease(t, "otherhost", now),
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption: "lease changed since read", current: storedLease(t, localhost, now),
+			expectDelete: true, expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption: "delete error", current: storedLease(t, localhost, now),
+			expectDelete: true, errDelete: errors.New("failed"), expectedErrMsg: "failed to forfeit resource lock due to elasticsearch error: failed",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withLock(t, func(lock *Lock, doc *mocks.DocumentService) {
+				doc.On("Get", mock.Anything).Return(testCase.current, testCase.errGet)
+				if testCase.expectDelete {
+					doc.On("Delete", mock.Anything, leaseVersion).Return(testCase.deleted, testCase.errDelete)
+				}
+				forfeited, err := lock.Forfeit(samplingLock)
+				if testCase.expectedErrMsg == "" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, testCase.expectedErrMsg)
+				}
+				assert.Equal(t, testCase.expectedForfeited, forfeited)
+			})
+		})
+	}
+}
+
+func TestMain(m *testing.M) {
+	testutils.VerifyGoLeaks(m)
+}
@@ -41,6 +41,7 @@ type aggregator struct {
 	currentThroughput   serviceOperationThroughput
 	postAggregator      *PostAggregator
 	aggregationInterval time.Duration
+	checkpointInterval  time.Duration
 	storage             samplingstore.Store
 	stop                chan struct{}
 	bgFinished          sync.WaitGroup
@@ -65,6 +66,7 @@ func NewAggregator(options Options, logger *zap.Logger, metricsFactory metrics.F
 		servicesCounter:     metricsFactory.Counter(metrics.Options{Name: "sampling_services"}),
 		currentThroughput:   make(serviceOperationThroughput),
 		aggregationInterval: options.CalculationInterval,
+		checkpointInterval:  options.CheckpointInterval,
 		postAggregator:      postAggregator,
 		storage:             store,
 		stop:                make(chan struct{}),
@@ -73,14 +75,23 @@ func NewAggregator(options Options, logger *zap.Logger, metricsFactory metrics.F
 
 func (a *aggregator) runAggregationLoop() {
 	ticker := time.NewTicker(a.aggregationInterval)
+	var checkpoints <-chan time.Time // nil channel never fires when checkpoints are disabled
+	if a.checkpointInterval > 0 {
+		checkpointTicker := time.NewTicker(a.checkpointInterval)
+		defer checkpointTicker.Stop()
+		checkpoints = checkpointTicker.C
+	}
 	for {
 		select {
 		case <-ticker.C:
 			a.Lock()
 			a.saveThroughput()
-			a.currentThroughput = make(serviceOperationThroughput)
 			a.postAggregator.runCalculation()
 			a.Unlock()
+		case <-checkpoints:
+			a.Lock()
+			a.saveThroughput()
+			a.Unlock()
 		case <-a.stop:
 			ticker.Stop()
 			return
@@ -88,7 +99,12 @@ func (a *aggregator) runAggregationLoop() {
 	}
 }
 
+// saveThroughput writes the throughput counted since the last write to storage and starts
+// counting from zero. Callers must hold the lock.
 func (a *aggregator) saveThroughput() {
+	if len(a.currentThroughput) == 0 {
+		return
+	}
 	totalOperations := 0
 	var throughputSlice []*model.Throughput
 	for _, opThroughput := range a.currentThroughput {
@@ -100,6 +116,7 @@ func (a *aggregator) saveThroughput() {
 	a.operationsCounter.Inc(int64(totalOperations))
 	a.servicesCounter.Inc(int64(len(a.currentThroughput)))
 	a.storage.InsertThroughput(throughputSlice)
+	a.currentThroughput = make(serviceOperationThroughput)
 }
 
 func (a *aggregator) RecordThroughput(service, operation string, samplerType span_model.SamplerType, probability float64) {
@@ -142,6 +159,10 @@ func (a *aggregator) Start() {
 func (a *aggregator) Close() error {
 	close(a.stop)
 	a.bgFinished.Wait()
+	// save the throughput of the interval in progress, otherwise it would be lost on restart
+	a.Lock()
+	a.saveThroughput()
+	a.Unlock()
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	samplingmodel "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	epmocks "github.com/jaegertracing/jaeger/plugin/sampling/leaderelection/mocks"
@@ -71,6 +72,68 @@ func TestAggregator(t *testing.T) {
 	}...)
 }
 
+func TestAggregatorSavesThroughputOnClose(t *testing.T) {
+	mockStorage := &mocks.Store{}
+	mockStorage.On("InsertThroughput", mock.AnythingOfType("[]*model.Throughput")).Return(nil)
+	testOpts := Options{
+		CalculationInterval:   time.Hour,
+		AggregationBuckets:    1,
+		BucketsForCalculation: 1,
+	}
+
+	a, err := NewAggregator(testOpts, zap.NewNop(), metricstest.NewFactory(0), &epmocks.ElectionParticipant{}, mockStorage)
+	require.NoError(t, err)
+	a.RecordThroughput("A", "GET", model.SamplerTypeProbabilistic, 0.001)
+	a.RecordThroughput("A", "GET", model.SamplerTypeProbabilistic, 0.001)
+	require.NoError(t, a.Close())
+
+	mockStorage.AssertNumberOfCalls(t, "InsertThroughput", 1)
+	saved := mockStorage.Calls[0].Arguments.Get(0).([]*samplingmodel.Throughput)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "A", saved[0].Service)
+	assert.EqualValues(t, 2, saved[0].Count)
+	assert.Empty(t, a.(*aggregator).currentThroughput)
+}
+
+func TestAggregatorNothingToSaveOnClose(t *testing.T) {
+	mockStorage := &mocks.Store{}
+	testOpts := Options{
+		CalculationInterval:   time.Hour,
+		AggregationBuckets:    1,
+		BucketsForCalculation: 1,
+	}
+
+	a, err := NewAggregator(testOpts, zap.NewNop(), metricstest.NewFactory(0), &epmocks.ElectionParticipant{}, mockStorage)
+	require.NoError(t, err)
+	require.NoError(t, a.Close())
+	mockStorage.AssertNotCalled(t, "InsertThroughput", mock.Anything)
+}
+
+func TestAggregatorCheckpoint(t *testing.T) {
+	mockStorage := &mocks.Store{}
+	mockStorage.On("InsertThroughput", mock.AnythingOfType("[]*model.Throughput")).Return(nil)
+	mockStorage.On("GetThroughput", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil, nil)
+	testOpts := Options{
+		CalculationInterval:   time.Hour,
+		CheckpointInterval:    time.Millisecond,
+		AggregationBuckets:    1,
+		BucketsForCalculation: 1,
+	}
+
+	a, err := NewAggregator(testOpts, zap.NewNop(), metricstest.NewFactory(0), &epmocks.ElectionParticipant{}, mockStorage)
+	require.NoError(t, err)
+	a.RecordThroughput("A", "GET", model.SamplerTypeProbabilistic, 0.001)
+	a.Start()
+	defer a.Close()
+
+	assert.Eventually(t, func() bool {
+		a.(*aggregator).Lock()
+		defer a.(*aggregator).Unlock()
+		return len(a.(*aggregator).currentThroughput) == 0
+	}, time.Second, time.Millisecond)
+	mockStorage.AssertCalled(t, "InsertThroughput", mock.AnythingOfType("[]*model.Throughput"))
+}
+
 func TestIncrementThroughput(t *testing.T) {
 	metricsFactory := metricstest.NewFactory(0)
 	mockStorage := &mocks.Store{}
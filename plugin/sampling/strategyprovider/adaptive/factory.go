@@ -100,7 +100,9 @@ func (f *Factory) CreateStrategyProvider() (samplingstrategy.Provider, samplings
 		return nil, nil, err
 	}
 
-	s.Start()
+	if err := s.Start(); err != nil {
+		return nil, nil, err
+	}
 	a.Start()
 
 	return s, a, nil
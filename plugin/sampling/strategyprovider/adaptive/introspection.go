@@ -0,0 +1,99 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package adaptive
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const introspectionServiceParam = "service"
+
+var _ Introspector = (*aggregator)(nil)
+
+// Introspector is implemented by the aggregator created by Factory.CreateStrategyProvider,
+// and exposes what the adaptive sampling engine decided for troubleshooting.
+type Introspector interface {
+	Introspect() Introspection
+}
+
+// Introspection is a snapshot of the state of the adaptive sampling engine of a collector.
+type Introspection struct {
+	Hostname string `json:"hostname"`
+	// Leader is true if this collector calculates the probabilities. Followers serve
+	// the probabilities calculated by the leader, and report what they last calculated
+	// themselves as leader, if ever.
+	Leader bool `json:"leader"`
+	// LastCalculation is when this collector last calculated the probabilities.
+	LastCalculation        *time.Time `json:"lastCalculation,omitempty"`
+	TargetSamplesPerSecond float64    `json:"targetSamplesPerSecond"`
+	// Services maps services and operations to their sampling state.
+	Services map[string]map[string]OperationSamplingState `json:"services"`
+}
+
+// OperationSamplingState is the sampling probability calculated for an operation
+// and the weighted QPS of its sampled root spans it was calculated from.
+type OperationSamplingState struct {
+	Probability float64 `json:"probability"`
+	QPS         float64 `json:"qps"`
+}
+
+// Introspect implements Introspector.
+func (a *aggregator) Introspect() Introspection {
+	return a.postAggregator.introspect()
+}
+
+func (p *PostAggregator) introspect() Introspection {
+	p.RLock()
+	defer p.RUnlock()
+	result := Introspection{
+		Hostname:               p.hostname,
+		Leader:                 p.isLeader(),
+		TargetSamplesPerSecond: p.TargetSamplesPerSecond,
+		Services:               make(map[string]map[string]OperationSamplingState, len(p.probabilities)),
+	}
+	if !p.lastCalculationTime.IsZero() {
+		lastCalculation := p.lastCalculationTime
+		result.LastCalculation = &lastCalculation
+	}
+	for svc, opProbabilities := range p.probabilities {
+		operations := make(map[string]OperationSamplingState, len(opProbabilities))
+		for op, probability := range opProbabilities {
+			operations[op] = OperationSamplingState{
+				Probability: probability,
+				QPS:         p.qps[svc][op],
+			}
+		}
+		result.Services[svc] = operations
+	}
+	return result
+}
+
+// NewIntrospectionHandler creates an HTTP handler responding with the Introspection of the engine
+// as JSON. The optional "service" query parameter restricts the response to a single service.
+func NewIntrospectionHandler(introspector Introspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		introspection := introspector.Introspect()
+		if service := r.URL.Query().Get(introspectionServiceParam); service != "" {
+			operations, ok := introspection.Services[service]
+			if !ok {
+				http.Error(w, "no sampling probabilities calculated for service "+service, http.StatusNotFound)
+				return
+			}
+			introspection.Services = map[string]map[string]OperationSamplingState{service: operations}
+		}
+		data, err := json.Marshal(introspection)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package adaptive
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	epmocks "github.com/jaegertracing/jaeger/plugin/sampling/leaderelection/mocks"
+	smocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
+)
+
+func newTestPostAggregator(t *testing.T, isLeader bool, throughput []*model.Throughput, throughputErr error) (*PostAggregator, *metricstest.Factory) {
+	mockStorage := &smocks.Store{}
+	mockStorage.On("GetThroughput", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(throughput, throughputErr)
+	mockStorage.On("InsertProbabilitiesAndQPS", "host", mock.AnythingOfType("model.ServiceOperationProbabilities"),
+		mock.AnythingOfType("model.ServiceOperationQPS")).Return(nil)
+	mockEP := &epmocks.ElectionParticipant{}
+	mockEP.On("IsLeader").Return(isLeader)
+	cfg := Options{
+		TargetSamplesPerSecond:     1.0,
+		DeltaTolerance:             0.1,
+		InitialSamplingProbability: 0.001,
+		CalculationInterval:        time.Minute,
+		AggregationBuckets:         1,
+		BucketsForCalculation:      1,
+	}
+	metricsFactory := metricstest.NewFactory(0)
+	t.Cleanup(metricsFactory.Stop)
+	p, err := newPostAggregator(cfg, "host", mockStorage, mockEP, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	p.lastCheckedTime = time.Now().Add(-time.Second)
+	return p, metricsFactory
+}
+
+func TestRunCalculationMetricsAndIntrospection(t *testing.T) {
+	p, metricsFactory := newTestPostAggregator(t, true, testThroughputs(), nil)
+	p.runCalculation()
+
+	metricsFactory.AssertGaugeMetrics(t, []metricstest.ExpectedMetric{
+		{Name: "adaptive_sampling_processor.leader", Value: 1},
+		{Name: "adaptive_sampling_processor.services_calculated", Value: 2},
+		{Name: "adaptive_sampling_processor.operations_calculated", Value: 3},
+	}...)
+	// every operation is sampled above the target QPS
+	metricsFactory.AssertCounterMetrics(t, []metricstest.ExpectedMetric{
+		{Name: "adaptive_sampling_processor.probability_changes", Tags: map[string]string{"direction": "decreased"}, Value: 3},
+		{Name: "adaptive_sampling_processor.probability_changes", Tags: map[string]string{"direction": "increased"}, Value: 0},
+	}...)
+
+	introspection := p.introspect()
+	assert.Equal(t, "host", introspection.Hostname)
+	assert.True(t, introspection.Leader)
+	require.NotNil(t, introspection.LastCalculation)
+	assert.InDelta(t, 1.0, introspection.TargetSamplesPerSecond, 0.01)
+	require.Len(t, introspection.Services, 2)
+	require.Len(t, introspection.Services["svcA"], 2)
+	getState := introspection.Services["svcA"]["GET"]
+	assert.Equal(t, p.probabilities["svcA"]["GET"], getState.Probability)
+	assert.Equal(t, p.qps["svcA"]["GET"], getState.QPS)
+	assert.Less(t, getState.Probability, p.InitialSamplingProbability)
+}
+
+func TestRunCalculationMetricsOnFollower(t *testing.T) {
+	p, metricsFactory := newTestPostAggregator(t, false, nil, errTestStorage())
+	p.runCalculation()
+
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{
+		Name: "adaptive_sampling_processor.leader", Value: 0,
+	})
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "adaptive_sampling_processor.get_throughput_errors", Value: 1,
+	})
+
+	introspection := p.introspect()
+	assert.False(t, introspection.Leader)
+	assert.Nil(t, introspection.LastCalculation)
+	assert.Empty(t, introspection.Services)
+}
+
+func TestRecordProbabilityChanges(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	p, err := newPostAggregator(Options{
+		InitialSamplingProbability: 0.001,
+		CalculationInterval:        time.Minute,
+		AggregationBuckets:         1,
+		BucketsForCalculation:      1,
+	}, "host", nil, nil, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+
+	p.recordProbabilityChanges(
+		model.ServiceOperationProbabilities{"svcA": {"GET": 0.1, "PUT": 0.1, "POST": 0.1}},
+		model.ServiceOperationProbabilities{
+			"svcA": {"GET": 0.2, "PUT": 0.05, "POST": 0.1},
+			"svcB": {"GET": 0.002, "PUT": 0.001},
+		},
+	)
+	metricsFactory.AssertCounterMetrics(t, []metricstest.ExpectedMetric{
+		{Name: "adaptive_sampling_processor.probability_changes", Tags: map[string]string{"direction": "increased"}, Value: 2},
+		{Name: "adaptive_sampling_processor.probability_changes", Tags: map[string]string{"direction": "decreased"}, Value: 1},
+	}...)
+}
+
+func TestAggregatorIntrospect(t *testing.T) {
+	mockEP := &epmocks.ElectionParticipant{}
+	mockEP.On("IsLeader").Return(false)
+	agg, err := NewAggregator(Options{
+		CalculationInterval:   time.Minute,
+		AggregationBuckets:    1,
+		BucketsForCalculation: 1,
+	}, zap.NewNop(), metrics.NullFactory, mockEP, &smocks.Store{})
+	require.NoError(t, err)
+
+	introspector, ok := agg.(Introspector)
+	require.True(t, ok)
+	introspection := introspector.Introspect()
+	assert.False(t, introspection.Leader)
+	assert.Empty(t, introspection.Services)
+}
+
+type fixedIntrospector Introspection
+
+func (i fixedIntrospector) Introspect() Introspection {
+	return Introspection(i)
+}
+
+func TestIntrospectionHandler(t *testing.T) {
+	introspector := fixedIntrospector{
+		Hostname:               "host",
+		Leader:                 true,
+		TargetSamplesPerSecond: 1,
+		Services: map[string]map[string]OperationSamplingState{
+			"svcA": {"GET": {Probability: 0.5, QPS: 2}},
+			"svcB": {"GET": {Probability: 0.001, QPS: 1}},
+		},
+	}
+	handler := NewIntrospectionHandler(introspector)
+
+	testCases := []struct {
+		name             string
+		method           string
+		url              string
+		expectedStatus   int
+		expectedServices []string
+	}{
+		{
+			name:             "all services",
+			method:           http.MethodGet,
+			url:              "/",
+			expectedStatus:   http.StatusOK,
+			expectedServices: []string{"svcA", "svcB"},
+		},
+		{
+			name:             "single service",
+			method:           http.MethodGet,
+			url:              "/?service=svcB",
+			expectedStatus:   http.StatusOK,
+			expectedServices: []string{"svcB"},
+		},
+		{
+			name:           "unknown service",
+			method:         http.MethodGet,
+			url:            "/?service=svcC",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unsupported method",
+			method:         http.MethodPost,
+			url:            "/",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.url, nil))
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var introspection Introspection
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &introspection))
+			assert.True(t, introspection.Leader)
+			assert.Len(t, introspection.Services, len(tc.expectedServices))
+			for _, svc := range tc.expectedServices {
+				assert.Equal(t, introspector.Services[svc], introspection.Services[svc])
+			}
+		})
+	}
+}
@@ -31,8 +31,11 @@ const (
 	initialSamplingProbability   = "sampling.initial-sampling-probability"
 	minSamplingProbability       = "sampling.min-sampling-probability"
 	minSamplesPerSecond          = "sampling.min-samples-per-second"
+	maxSamplesPerSecond          = "sampling.max-samples-per-second"
 	leaderLeaseRefreshInterval   = "sampling.leader-lease-refresh-interval"
 	followerLeaseRefreshInterval = "sampling.follower-lease-refresh-interval"
+	checkpointInterval           = "sampling.checkpoint-interval"
+	probabilityOverridesFile     = "sampling.probability-overrides-file"
 
 	defaultTargetSamplesPerSecond       = 1
 	defaultDeltaTolerance               = 0.3
@@ -45,6 +48,7 @@ const (
 	defaultMinSamplesPerSecond          = 1.0 / float64(time.Minute/time.Second) // once every 1 minute
 	defaultLeaderLeaseRefreshInterval   = 5 * time.Second
 	defaultFollowerLeaseRefreshInterval = 60 * time.Second
+	defaultCheckpointInterval           = 0
 )
 
 // Options holds configuration for the adaptive sampling strategy store.
@@ -52,7 +56,6 @@ const (
 // of the optimization/control implemented by the adaptive sampling.
 type Options struct {
 	// TargetSamplesPerSecond is the global target rate of samples per operation.
-	// TODO implement manual overrides per service/operation.
 	TargetSamplesPerSecond float64
 
 	// DeltaTolerance is the acceptable amount of deviation between the observed and the desired (target)
@@ -103,6 +106,10 @@ type Options struct {
 	// that may never be sampled by the probabilistic sampler.
 	MinSamplesPerSecond float64
 
+	// MaxSamplesPerSecond is the upper bound rate limit sent to the SDKs with the sampling probabilities
+	// of the operations, if greater than zero. Few SDKs enforce it.
+	MaxSamplesPerSecond float64
+
 	// LeaderLeaseRefreshInterval is the duration to sleep if this processor is elected leader before
 	// attempting to renew the lease on the leader lock. NB. This should be less than FollowerLeaseRefreshInterval
 	// to reduce lock thrashing.
@@ -111,6 +118,19 @@ type Options struct {
 	// FollowerLeaseRefreshInterval is the duration to sleep if this processor is a follower
 	// (ie. failed to gain the leader lock).
 	FollowerLeaseRefreshInterval time.Duration
+
+	// CheckpointInterval determines how often the throughput counted since the last write is saved
+	// to storage in between CalculationIntervals, so that a collector that crashes or is restarted
+	// loses at most one CheckpointInterval of counts. The calculation sums all throughput saved within
+	// its window, so checkpointed counts are neither lost nor counted twice. Zero disables checkpoints;
+	// the pending throughput is always saved on shutdown.
+	CheckpointInterval time.Duration
+
+	// ProbabilityOverridesFile is the path to a JSON file with the floors and ceilings of the sampling
+	// probabilities of some services or operations, and of the traces sampled per second of some services,
+	// see ProbabilityOverride. The calculated probabilities of the other operations are in the range
+	// [MinSamplingProbability, 1.0].
+	ProbabilityOverridesFile string
 }
 
 // AddFlags adds flags for Options
@@ -142,12 +162,22 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Float64(minSamplesPerSecond, defaultMinSamplesPerSecond,
 		"The minimum number of traces that are sampled per second.",
 	)
+	flagSet.Float64(maxSamplesPerSecond, 0,
+		"The upper bound rate limit of the traces sampled per second sent to the SDKs with the sampling probabilities of the operations (few SDKs enforce it); not sent when 0.",
+	)
 	flagSet.Duration(leaderLeaseRefreshInterval, defaultLeaderLeaseRefreshInterval,
 		"The duration to sleep if this processor is elected leader before attempting to renew the lease on the leader lock. This should be less than follower-lease-refresh-interval to reduce lock thrashing.",
 	)
 	flagSet.Duration(followerLeaseRefreshInterval, defaultFollowerLeaseRefreshInterval,
 		"The duration to sleep if this processor is a follower.",
 	)
+	flagSet.Duration(checkpointInterval, defaultCheckpointInterval,
+		"How often the throughput counted so far is saved to storage in between calculations, so that restarts do not lose it. Zero disables checkpoints; the throughput is always saved on shutdown.",
+	)
+	flagSet.String(probabilityOverridesFile, "",
+		`The path to a JSON file with per-service or per-operation bounds of the sampling probabilities, e.g. {"overrides": [{"service": "payments", "min_probability": 0.01}, {"service": "payments", "operation": "POST /charge", "min_probability": 1, "max_probability": 1}]}. Equal bounds pin the probability. `+
+			`The overrides of a service can also replace the `+minSamplesPerSecond+` and `+maxSamplesPerSecond+` rate limits of its operations, e.g. {"service": "search", "min_samples_per_second": 1, "max_samples_per_second": 100}.`,
+	)
 }
 
 // InitFromViper initializes Options with properties from viper
@@ -161,7 +191,10 @@ func (opts *Options) InitFromViper(v *viper.Viper) *Options {
 	opts.InitialSamplingProbability = v.GetFloat64(initialSamplingProbability)
 	opts.MinSamplingProbability = v.GetFloat64(minSamplingProbability)
 	opts.MinSamplesPerSecond = v.GetFloat64(minSamplesPerSecond)
+	opts.MaxSamplesPerSecond = v.GetFloat64(maxSamplesPerSecond)
 	opts.LeaderLeaseRefreshInterval = v.GetDuration(leaderLeaseRefreshInterval)
 	opts.FollowerLeaseRefreshInterval = v.GetDuration(followerLeaseRefreshInterval)
+	opts.CheckpointInterval = v.GetDuration(checkpointInterval)
+	opts.ProbabilityOverridesFile = v.GetString(probabilityOverridesFile)
 	return opts
 }
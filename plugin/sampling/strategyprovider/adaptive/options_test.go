@@ -24,8 +24,11 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--sampling.initial-sampling-probability=0.002",
 		"--sampling.min-sampling-probability=1e-4",
 		"--sampling.min-samples-per-second=0.016666666666666666",
+		"--sampling.max-samples-per-second=100",
 		"--sampling.leader-lease-refresh-interval=5s",
 		"--sampling.follower-lease-refresh-interval=1m0s",
+		"--sampling.checkpoint-interval=15s",
+		"--sampling.probability-overrides-file=/etc/jaeger/overrides.json",
 	})
 	opts := &Options{}
 
@@ -40,6 +43,9 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, 0.002, opts.InitialSamplingProbability)
 	assert.Equal(t, 1e-4, opts.MinSamplingProbability)
 	assert.Equal(t, 0.016666666666666666, opts.MinSamplesPerSecond)
+	assert.Equal(t, 100.0, opts.MaxSamplesPerSecond)
 	assert.Equal(t, time.Duration(5000000000), opts.LeaderLeaseRefreshInterval)
 	assert.Equal(t, time.Duration(60000000000), opts.FollowerLeaseRefreshInterval)
+	assert.Equal(t, 15*time.Second, opts.CheckpointInterval)
+	assert.Equal(t, "/etc/jaeger/overrides.json", opts.ProbabilityOverridesFile)
 }
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package adaptive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ProbabilityOverride bounds the calculated sampling probabilities of a service, or of one of its operations.
+// Equal bounds pin the probability. The override of a service can also replace the rate limits of the
+// traces sampled per second of its operations.
+type ProbabilityOverride struct {
+	Service string `json:"service"`
+	// Operation restricts the override to an operation; it applies to all the operations of the service
+	// when empty. The override of an operation takes precedence over the override of its service.
+	Operation string `json:"operation,omitempty"`
+	// MinProbability replaces the global MinSamplingProbability when greater than zero.
+	MinProbability float64 `json:"min_probability,omitempty"`
+	// MaxProbability caps the probability when greater than zero.
+	MaxProbability float64 `json:"max_probability,omitempty"`
+	// MinSamplesPerSecond replaces the global MinSamplesPerSecond of the service when greater than zero.
+	MinSamplesPerSecond float64 `json:"min_samples_per_second,omitempty"`
+	// MaxSamplesPerSecond replaces the global MaxSamplesPerSecond of the service when greater than zero.
+	MaxSamplesPerSecond float64 `json:"max_samples_per_second,omitempty"`
+}
+
+type probabilityOverridesConfig struct {
+	Overrides []ProbabilityOverride `json:"overrides"`
+}
+
+// probabilityBounds is the range of the sampling probabilities of an operation.
+type probabilityBounds struct {
+	min float64
+	max float64
+}
+
+// probabilityOverrides holds the validated overrides by service and operation.
+type probabilityOverrides struct {
+	services   map[string]ProbabilityOverride
+	operations map[string]map[string]ProbabilityOverride
+}
+
+func loadProbabilityOverrides(path string) ([]ProbabilityOverride, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sampling probability overrides: %w", err)
+	}
+	var config probabilityOverridesConfig
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse sampling probability overrides %s: %w", path, err)
+	}
+	return config.Overrides, nil
+}
+
+func newProbabilityOverrides(overrides []ProbabilityOverride) (*probabilityOverrides, error) {
+	o := &probabilityOverrides{
+		services:   make(map[string]ProbabilityOverride),
+		operations: make(map[string]map[string]ProbabilityOverride),
+	}
+	for _, override := range overrides {
+		if err := override.validate(); err != nil {
+			return nil, err
+		}
+		if override.Operation == "" {
+			if _, ok := o.services[override.Service]; ok {
+				return nil, fmt.Errorf("duplicate sampling probability override for service %q", override.Service)
+			}
+			o.services[override.Service] = override
+			continue
+		}
+		if _, ok := o.operations[override.Service][override.Operation]; ok {
+			return nil, fmt.Errorf("duplicate sampling probability override for operation %q of service %q", override.Operation, override.Service)
+		}
+		if _, ok := o.operations[override.Service]; !ok {
+			o.operations[override.Service] = make(map[string]ProbabilityOverride)
+		}
+		o.operations[override.Service][override.Operation] = override
+	}
+	return o, nil
+}
+
+func (o *ProbabilityOverride) validate() error {
+	if o.Service == "" {
+		return errors.New("sampling probability override without service")
+	}
+	for _, probability := range []float64{o.MinProbability, o.MaxProbability} {
+		if probability < 0 || probability > maxSamplingProbability || math.IsNaN(probability) {
+			return fmt.Errorf("sampling probability override for service %q must be between 0 and 1", o.Service)
+		}
+	}
+	if o.MaxProbability > 0 && o.MinProbability > o.MaxProbability {
+		return fmt.Errorf("sampling probability override for service %q has min_probability greater than max_probability", o.Service)
+	}
+	if o.MinSamplesPerSecond == 0 && o.MaxSamplesPerSecond == 0 {
+		return nil
+	}
+	if o.Operation != "" {
+		return fmt.Errorf("sampling probability override for operation %q of service %q cannot set samples per second, which apply to all the operations of the service", o.Operation, o.Service)
+	}
+	if o.MinSamplesPerSecond < 0 || o.MaxSamplesPerSecond < 0 {
+		return fmt.Errorf("sampling probability override for service %q must have positive samples per second", o.Service)
+	}
+	if o.MaxSamplesPerSecond > 0 && o.MinSamplesPerSecond > o.MaxSamplesPerSecond {
+		return fmt.Errorf("sampling probability override for service %q has min_samples_per_second greater than max_samples_per_second", o.Service)
+	}
+	return nil
+}
+
+// rateLimits returns the bounds of the traces sampled per second of the operations of a service,
+// defaultMin and defaultMax being the global ones.
+func (o *probabilityOverrides) rateLimits(service string, defaultMin, defaultMax float64) (minSPS, maxSPS float64) {
+	minSPS, maxSPS = defaultMin, defaultMax
+	if o == nil {
+		return minSPS, maxSPS
+	}
+	override := o.services[service]
+	if override.MinSamplesPerSecond > 0 {
+		minSPS = override.MinSamplesPerSecond
+	}
+	if override.MaxSamplesPerSecond > 0 {
+		maxSPS = override.MaxSamplesPerSecond
+	}
+	return minSPS, maxSPS
+}
+
+// bounds returns the range of the probabilities of an operation, defaultMin being the global minimum,
+// and whether it is overridden.
+func (o *probabilityOverrides) bounds(service, operation string, defaultMin float64) (probabilityBounds, bool) {
+	bounds := probabilityBounds{min: defaultMin, max: maxSamplingProbability}
+	if o == nil {
+		return bounds, false
+	}
+	override, ok := o.operations[service][operation]
+	if !ok {
+		override, ok = o.services[service]
+	}
+	if !ok {
+		return bounds, false
+	}
+	if override.MinProbability > 0 {
+		bounds.min = override.MinProbability
+	}
+	if override.MaxProbability > 0 {
+		bounds.max = override.MaxProbability
+	}
+	// an override lowering the ceiling below the global minimum lowers the floor too
+	bounds.min = math.Min(bounds.min, bounds.max)
+	return bounds, true
+}
+
+func (b probabilityBounds) clamp(probability float64) float64 {
+	return math.Min(b.max, math.Max(b.min, probability))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package adaptive
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProbabilityOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"overrides": [
+		{"service": "payments", "min_probability": 0.01},
+		{"service": "payments", "operation": "POST /charge", "min_probability": 1, "max_probability": 1}
+	]}`), 0o600))
+	overrides, err := loadProbabilityOverrides(path)
+	require.NoError(t, err)
+	assert.Equal(t, []ProbabilityOverride{
+		{Service: "payments", MinProbability: 0.01},
+		{Service: "payments", Operation: "POST /charge", MinProbability: 1, MaxProbability: 1},
+	}, overrides)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"overrides": [`), 0o600))
+	_, err = loadProbabilityOverrides(path)
+	require.ErrorContains(t, err, "failed to parse sampling probability overrides")
+}
+
+func TestNewProbabilityOverridesInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []ProbabilityOverride
+		err       string
+	}{
+		{
+			name:      "no service",
+			overrides: []ProbabilityOverride{{Operation: "GET", MaxProbability: 0.5}},
+			err:       "sampling probability override without service",
+		},
+		{
+			name:      "negative",
+			overrides: []ProbabilityOverride{{Service: "svc", MinProbability: -0.1}},
+			err:       `sampling probability override for service "svc" must be between 0 and 1`,
+		},
+		{
+			name:      "not a number",
+			overrides: []ProbabilityOverride{{Service: "svc", MaxProbability: math.NaN()}},
+			err:       `sampling probability override for service "svc" must be between 0 and 1`,
+		},
+		{
+			name:      "min greater than max",
+			overrides: []ProbabilityOverride{{Service: "svc", MinProbability: 0.5, MaxProbability: 0.1}},
+			err:       `sampling probability override for service "svc" has min_probability greater than max_probability`,
+		},
+		{
+			name:      "duplicate service",
+			overrides: []ProbabilityOverride{{Service: "svc", MaxProbability: 0.5}, {Service: "svc", MinProbability: 0.1}},
+			err:       `duplicate sampling probability override for service "svc"`,
+		},
+		{
+			name:      "duplicate operation",
+			overrides: []ProbabilityOverride{{Service: "svc", Operation: "GET", MaxProbability: 0.5}, {Service: "svc", Operation: "GET"}},
+			err:       `duplicate sampling probability override for operation "GET" of service "svc"`,
+		},
+		{
+			name:      "samples per second of operation",
+			overrides: []ProbabilityOverride{{Service: "svc", Operation: "GET", MaxSamplesPerSecond: 10}},
+			err:       `sampling probability override for operation "GET" of service "svc" cannot set samples per second, which apply to all the operations of the service`,
+		},
+		{
+			name:      "negative samples per second",
+			overrides: []ProbabilityOverride{{Service: "svc", MinSamplesPerSecond: -1}},
+			err:       `sampling probability override for service "svc" must have positive samples per second`,
+		},
+		{
+			name:      "min samples per second greater than max",
+			overrides: []ProbabilityOverride{{Service: "svc", MinSamplesPerSecond: 10, MaxSamplesPerSecond: 1}},
+			err:       `sampling probability override for service "svc" has min_samples_per_second greater than max_samples_per_second`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newProbabilityOverrides(test.overrides)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestProbabilityOverridesBounds(t *testing.T) {
+	overrides, err := newProbabilityOverrides([]ProbabilityOverride{
+		{Service: "payments", MinProbability: 0.01},
+		{Service: "payments", Operation: "POST", MinProbability: 1, MaxProbability: 1},
+		{Service: "search", Operation: "GET", MaxProbability: 0.5},
+	})
+	require.NoError(t, err)
+	tests := []struct {
+		service    string
+		operation  string
+		bounds     probabilityBounds
+		overridden bool
+	}{
+		{"payments", "GET", probabilityBounds{min: 0.01, max: 1}, true},
+		{"payments", "POST", probabilityBounds{min: 1, max: 1}, true},
+		{"search", "GET", probabilityBounds{min: 0.001, max: 0.5}, true},
+		{"search", "PUT", probabilityBounds{min: 0.001, max: 1}, false},
+		{"checkout", "GET", probabilityBounds{min: 0.001, max: 1}, false},
+	}
+	for _, test := range tests {
+		bounds, overridden := overrides.bounds(test.service, test.operation, 0.001)
+		assert.Equal(t, test.bounds, bounds, test.service+" "+test.operation)
+		assert.Equal(t, test.overridden, overridden, test.service+" "+test.operation)
+	}
+
+	var none *probabilityOverrides
+	bounds, overridden := none.bounds("payments", "GET", 0.001)
+	assert.Equal(t, probabilityBounds{min: 0.001, max: 1}, bounds)
+	assert.False(t, overridden)
+}
+
+func TestProbabilityOverridesRateLimits(t *testing.T) {
+	overrides, err := newProbabilityOverrides([]ProbabilityOverride{
+		{Service: "search", MinSamplesPerSecond: 1, MaxSamplesPerSecond: 100},
+		{Service: "payments", MaxSamplesPerSecond: 5},
+	})
+	require.NoError(t, err)
+	minSPS, maxSPS := overrides.rateLimits("search", 0.1, 10)
+	assert.Equal(t, []float64{1, 100}, []float64{minSPS, maxSPS})
+	minSPS, maxSPS = overrides.rateLimits("payments", 0.1, 10)
+	assert.Equal(t, []float64{0.1, 5}, []float64{minSPS, maxSPS})
+	minSPS, maxSPS = overrides.rateLimits("checkout", 0.1, 10)
+	assert.Equal(t, []float64{0.1, 10}, []float64{minSPS, maxSPS})
+
+	var none *probabilityOverrides
+	minSPS, maxSPS = none.rateLimits("search", 0.1, 0)
+	assert.Equal(t, []float64{0.1, 0}, []float64{minSPS, maxSPS})
+}
@@ -97,13 +97,24 @@ type PostAggregator struct {
 
 	probabilityCalculator calculationstrategy.ProbabilityCalculator
 
+	// overrides bounds the probabilities of some services and operations; nil when there are none.
+	overrides *probabilityOverrides
+
 	serviceCache []SamplingCache
 
 	shutdown chan struct{}
 
 	operationsCalculatedGauge     metrics.Gauge
+	servicesCalculatedGauge       metrics.Gauge
+	leaderGauge                   metrics.Gauge
 	calculateProbabilitiesLatency metrics.Timer
+	getThroughputErrors           metrics.Counter
+	probabilitiesIncreased        metrics.Counter
+	probabilitiesDecreased        metrics.Counter
 	lastCheckedTime               time.Time
+
+	// lastCalculationTime is when this host last calculated the probabilities as the leader.
+	lastCalculationTime time.Time
 }
 
 // newPostAggregator creates a new sampling postAggregator that generates sampling rates for service operations.
@@ -121,6 +132,16 @@ func newPostAggregator(
 	if opts.BucketsForCalculation < 1 {
 		return nil, errBucketsForCalculation
 	}
+	var overrides *probabilityOverrides
+	if opts.ProbabilityOverridesFile != "" {
+		list, err := loadProbabilityOverrides(opts.ProbabilityOverridesFile)
+		if err != nil {
+			return nil, err
+		}
+		if overrides, err = newProbabilityOverrides(list); err != nil {
+			return nil, err
+		}
+	}
 	metricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "adaptive_sampling_processor"})
 	return &PostAggregator{
 		Options:             opts,
@@ -133,9 +154,15 @@ func newPostAggregator(
 		// TODO make weightsCache and probabilityCalculator configurable
 		weightVectorCache:             NewWeightVectorCache(),
 		probabilityCalculator:         calculationstrategy.NewPercentageIncreaseCappedCalculator(1.0),
+		overrides:                     overrides,
 		serviceCache:                  []SamplingCache{},
 		operationsCalculatedGauge:     metricsFactory.Gauge(metrics.Options{Name: "operations_calculated"}),
+		servicesCalculatedGauge:       metricsFactory.Gauge(metrics.Options{Name: "services_calculated"}),
+		leaderGauge:                   metricsFactory.Gauge(metrics.Options{Name: "leader"}),
 		calculateProbabilitiesLatency: metricsFactory.Timer(metrics.TimerOptions{Name: "calculate_probabilities"}),
+		getThroughputErrors:           metricsFactory.Counter(metrics.Options{Name: "get_throughput_errors"}),
+		probabilitiesIncreased:        metricsFactory.Counter(metrics.Options{Name: "probability_changes", Tags: map[string]string{"direction": "increased"}}),
+		probabilitiesDecreased:        metricsFactory.Counter(metrics.Options{Name: "probability_changes", Tags: map[string]string{"direction": "decreased"}}),
 		shutdown:                      make(chan struct{}),
 	}, nil
 }
@@ -147,7 +174,7 @@ func (p *Provider) GetSamplingStrategy(_ context.Context, service string) (*api_
 	if strategy, ok := p.strategyResponses[service]; ok {
 		return strategy, nil
 	}
-	return p.generateDefaultSamplingStrategyResponse(), nil
+	return p.generateDefaultSamplingStrategyResponse(service), nil
 }
 
 // Start initializes and starts the sampling postAggregator which regularly calculates sampling probabilities.
@@ -216,8 +243,15 @@ func addJitter(jitterAmount time.Duration) time.Duration {
 func (p *PostAggregator) runCalculation() {
 	endTime := time.Now().Add(p.Delay * -1)
 	startTime := p.lastCheckedTime
+	isLeader := p.isLeader()
+	if isLeader {
+		p.leaderGauge.Update(1)
+	} else {
+		p.leaderGauge.Update(0)
+	}
 	throughput, err := p.storage.GetThroughput(startTime, endTime)
 	if err != nil {
+		p.getThroughputErrors.Inc(1)
 		p.logger.Error(getThroughputErrMsg, zap.Error(err))
 		return
 	}
@@ -232,13 +266,16 @@ func (p *PostAggregator) runCalculation() {
 	// has the throughput ready in memory. However, only run the actual calculations
 	// if this host becomes leader.
 	// TODO fill the throughput buffer only when we're leader
-	if p.isLeader() {
+	if isLeader {
 		startTime := time.Now()
 		probabilities, qps := p.calculateProbabilitiesAndQPS()
 		p.Lock()
+		p.recordProbabilityChanges(p.probabilities, probabilities)
 		p.probabilities = probabilities
 		p.qps = qps
+		p.lastCalculationTime = startTime
 		p.Unlock()
+		p.servicesCalculatedGauge.Update(int64(len(probabilities)))
 		// NB: This has the potential of running into a race condition if the CalculationInterval
 		// is set to an extremely low value. The worst case scenario is that probabilities is calculated
 		// and swapped more than once before generateStrategyResponses() and saveProbabilities() are called.
@@ -251,6 +288,28 @@ func (p *PostAggregator) runCalculation() {
 	}
 }
 
+// recordProbabilityChanges counts the operations whose probability was raised or lowered by the latest calculation.
+func (p *PostAggregator) recordProbabilityChanges(previous, latest model.ServiceOperationProbabilities) {
+	var increased, decreased int64
+	for svc, opProbabilities := range latest {
+		for op, probability := range opProbabilities {
+			oldProbability, ok := previous[svc][op]
+			if !ok {
+				oldProbability = p.InitialSamplingProbability
+			}
+			switch {
+			case FloatEquals(probability, oldProbability):
+			case probability > oldProbability:
+				increased++
+			default:
+				decreased++
+			}
+		}
+	}
+	p.probabilitiesIncreased.Inc(increased)
+	p.probabilitiesDecreased.Inc(decreased)
+}
+
 func (p *PostAggregator) saveProbabilitiesAndQPS() {
 	p.RLock()
 	defer p.RUnlock()
@@ -412,9 +471,14 @@ func (p *PostAggregator) calculateProbability(service, operation string, qps flo
 		UsingAdaptive: usingAdaptiveSampling,
 	})
 
+	bounds, overridden := p.overrides.bounds(service, operation, p.MinSamplingProbability)
 	// Short circuit if the qps is close enough to targetQPS or if the service doesn't appear to be using
 	// adaptive sampling.
 	if p.withinTolerance(qps, p.TargetSamplesPerSecond) || !usingAdaptiveSampling {
+		if overridden {
+			// the override may have changed since the probability was calculated
+			return bounds.clamp(oldProbability)
+		}
 		return oldProbability
 	}
 	var newProbability float64
@@ -425,7 +489,7 @@ func (p *PostAggregator) calculateProbability(service, operation string, qps flo
 	} else {
 		newProbability = p.probabilityCalculator.Calculate(p.TargetSamplesPerSecond, qps, oldProbability)
 	}
-	return math.Min(maxSamplingProbability, math.Max(p.MinSamplingProbability, newProbability))
+	return bounds.clamp(newProbability)
 }
 
 // is actual value within p.DeltaTolerance percentage of expected value.
@@ -485,7 +549,7 @@ func (p *Provider) generateStrategyResponses() {
 			}
 			idx++
 		}
-		strategy := p.generateDefaultSamplingStrategyResponse()
+		strategy := p.generateDefaultSamplingStrategyResponse(svc)
 		strategy.OperationSampling.PerOperationStrategies = opStrategies
 		strategies[svc] = strategy
 	}
@@ -496,12 +560,16 @@ func (p *Provider) generateStrategyResponses() {
 	p.strategyResponses = strategies
 }
 
-func (p *Provider) generateDefaultSamplingStrategyResponse() *api_v2.SamplingStrategyResponse {
+// generateDefaultSamplingStrategyResponse generates the strategy of the service without its operations.
+// The SDKs combine the probabilistic sampling of each operation with the rate limits of the service.
+func (p *Provider) generateDefaultSamplingStrategyResponse(service string) *api_v2.SamplingStrategyResponse {
+	minSPS, maxSPS := p.overrides.rateLimits(service, p.MinSamplesPerSecond, p.MaxSamplesPerSecond)
 	return &api_v2.SamplingStrategyResponse{
 		StrategyType: api_v2.SamplingStrategyType_PROBABILISTIC,
 		OperationSampling: &api_v2.PerOperationSamplingStrategies{
 			DefaultSamplingProbability:       p.InitialSamplingProbability,
-			DefaultLowerBoundTracesPerSecond: p.MinSamplesPerSecond,
+			DefaultLowerBoundTracesPerSecond: minSPS,
+			DefaultUpperBoundTracesPerSecond: maxSPS,
 		},
 	}
 }
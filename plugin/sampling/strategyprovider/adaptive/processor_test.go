@@ -17,6 +17,8 @@ package adaptive
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -295,6 +297,56 @@ func TestCalculateProbability(t *testing.T) {
 	}
 }
 
+func TestCalculateProbabilityWithOverrides(t *testing.T) {
+	throughputs := []*throughputBucket{
+		{
+			throughput: serviceOperationThroughput{
+				"payments": map[string]*model.Throughput{
+					"GET": {Probabilities: map[string]struct{}{"0.500000": {}}},
+				},
+			},
+		},
+	}
+	overrides, err := newProbabilityOverrides([]ProbabilityOverride{
+		{Service: "payments", MinProbability: 0.01, MaxProbability: 0.4},
+		{Service: "payments", Operation: "POST", MinProbability: 1, MaxProbability: 1},
+		{Service: "healthcheck", MaxProbability: 0.000001},
+	})
+	require.NoError(t, err)
+	p := &PostAggregator{
+		Options: Options{
+			TargetSamplesPerSecond:     1.0,
+			DeltaTolerance:             0.2,
+			InitialSamplingProbability: 0.001,
+			MinSamplingProbability:     0.00001,
+		},
+		probabilities:         model.ServiceOperationProbabilities{"payments": {"GET": 0.5}},
+		probabilityCalculator: testCalculator(),
+		throughputs:           throughputs,
+		serviceCache:          []SamplingCache{{}},
+		overrides:             overrides,
+	}
+	tests := []struct {
+		service             string
+		operation           string
+		qps                 float64
+		expectedProbability float64
+		errMsg              string
+	}{
+		{"payments", "GET", 0.5, 0.4, "capped by the service override"},
+		{"payments", "GET", 1.0, 0.4, "previous probability above the service override within tolerance"},
+		{"payments", "PUT", 1000000000, 0.01, "floor of the service override"},
+		{"payments", "POST", 1000000000, 1.0, "pinned by the operation override"},
+		{"payments", "POST", 1.0, 1.0, "pinned within tolerance"},
+		{"healthcheck", "GET", 0.000001, 0.000001, "ceiling below the global minimum"},
+		{"checkout", "GET", 1000000000, 0.00001, "global minimum without override"},
+	}
+	for _, test := range tests {
+		probability := p.calculateProbability(test.service, test.operation, test.qps)
+		assert.Equal(t, test.expectedProbability, probability, test.errMsg)
+	}
+}
+
 func TestCalculateProbabilitiesAndQPS(t *testing.T) {
 	prevProbabilities := model.ServiceOperationProbabilities{
 		"svcB": map[string]float64{
@@ -556,6 +608,16 @@ func TestConstructorFailure(t *testing.T) {
 	cfg.BucketsForCalculation = -1
 	_, err = newPostAggregator(cfg, "host", nil, nil, metrics.NullFactory, logger)
 	require.EqualError(t, err, "BucketsForCalculation cannot be less than 1")
+
+	cfg.BucketsForCalculation = 1
+	cfg.ProbabilityOverridesFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = newPostAggregator(cfg, "host", nil, nil, metrics.NullFactory, logger)
+	require.ErrorContains(t, err, "failed to read sampling probability overrides")
+
+	cfg.ProbabilityOverridesFile = filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(cfg.ProbabilityOverridesFile, []byte(`{"overrides": [{"service": "payments", "min_probability": 2}]}`), 0o600))
+	_, err = newPostAggregator(cfg, "host", nil, nil, metrics.NullFactory, logger)
+	require.EqualError(t, err, `sampling probability override for service "payments" must be between 0 and 1`)
 }
 
 func TestGenerateStrategyResponses(t *testing.T) {
@@ -564,12 +626,16 @@ func TestGenerateStrategyResponses(t *testing.T) {
 			"GET": 0.5,
 		},
 	}
+	overrides, err := newProbabilityOverrides([]ProbabilityOverride{{Service: "svcA", MaxSamplesPerSecond: 10}})
+	require.NoError(t, err)
 	p := &Provider{
 		probabilities: probabilities,
 		Options: Options{
 			InitialSamplingProbability: 0.001,
 			MinSamplesPerSecond:        0.0001,
+			MaxSamplesPerSecond:        100,
 		},
+		overrides: overrides,
 	}
 	p.generateStrategyResponses()
 
@@ -579,6 +645,7 @@ func TestGenerateStrategyResponses(t *testing.T) {
 			OperationSampling: &api_v2.PerOperationSamplingStrategies{
 				DefaultSamplingProbability:       0.001,
 				DefaultLowerBoundTracesPerSecond: 0.0001,
+				DefaultUpperBoundTracesPerSecond: 10,
 				PerOperationStrategies: []*api_v2.OperationSamplingStrategy{
 					{
 						Operation: "GET",
@@ -591,6 +658,26 @@ func TestGenerateStrategyResponses(t *testing.T) {
 		},
 	}
 	assert.Equal(t, expectedResponse, p.strategyResponses)
+
+	strategy, err := p.GetSamplingStrategy(context.Background(), "svcB")
+	require.NoError(t, err)
+	assert.Equal(t, &api_v2.SamplingStrategyResponse{
+		StrategyType: api_v2.SamplingStrategyType_PROBABILISTIC,
+		OperationSampling: &api_v2.PerOperationSamplingStrategies{
+			DefaultSamplingProbability:       0.001,
+			DefaultLowerBoundTracesPerSecond: 0.0001,
+			DefaultUpperBoundTracesPerSecond: 100,
+		},
+	}, strategy)
+}
+
+func TestProviderStartOverridesErrors(t *testing.T) {
+	p := NewProvider(Options{ProbabilityOverridesFile: filepath.Join(t.TempDir(), "missing.json")}, zap.NewNop(), nil, nil)
+	require.ErrorContains(t, p.Start(), "failed to read sampling probability overrides")
+
+	p.ProbabilityOverridesFile = filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(p.ProbabilityOverridesFile, []byte(`{"overrides": [{"service": "svc", "operation": "GET", "min_samples_per_second": 1}]}`), 0o600))
+	require.ErrorContains(t, p.Start(), "cannot set samples per second")
 }
 
 func TestUsingAdaptiveSampling(t *testing.T) {
@@ -42,6 +42,9 @@ type Provider struct {
 	// strategyResponses is the cache of the sampling strategies for every service, in protobuf format.
 	strategyResponses map[string]*api_v2.SamplingStrategyResponse
 
+	// overrides replaces the rate limits of some services; nil when there are none.
+	overrides *probabilityOverrides
+
 	// followerRefreshInterval determines how often the follower processor updates its probabilities.
 	// Given only the leader writes probabilities, the followers need to fetch the probabilities into
 	// cache.
@@ -68,6 +71,15 @@ func NewProvider(options Options, logger *zap.Logger, participant leaderelection
 // Start initializes and starts the sampling service which regularly loads sampling probabilities and generates strategies.
 func (ss *Provider) Start() error {
 	ss.logger.Info("starting adaptive sampling service")
+	if ss.ProbabilityOverridesFile != "" {
+		list, err := loadProbabilityOverrides(ss.ProbabilityOverridesFile)
+		if err != nil {
+			return err
+		}
+		if ss.overrides, err = newProbabilityOverrides(list); err != nil {
+			return err
+		}
+	}
 	ss.loadProbabilities()
 	ss.generateStrategyResponses()
 
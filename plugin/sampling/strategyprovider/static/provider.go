@@ -255,6 +255,7 @@ func (h *samplingProvider) parseStrategies_deprecated(strategies *strategies) {
 				opS.PerOperationStrategies,
 				newStore.defaultStrategy.OperationSampling.PerOperationStrategies)
 		}
+		inheritRateLimits(opS, newStore.defaultStrategy.OperationSampling)
 	}
 	h.storedStrategies.Store(newStore)
 }
@@ -293,6 +294,7 @@ func (h *samplingProvider) parseStrategies(strategies *strategies) {
 		opS.PerOperationStrategies = mergePerOperationSamplingStrategies(
 			opS.PerOperationStrategies,
 			newStore.defaultStrategy.OperationSampling.PerOperationStrategies)
+		inheritRateLimits(opS, newStore.defaultStrategy.OperationSampling)
 	}
 	h.storedStrategies.Store(newStore)
 }
@@ -314,13 +316,31 @@ func mergePerOperationSamplingStrategies(
 	return a
 }
 
+// inheritRateLimits sets the traces per second bounds of the default strategy that the service does not set.
+func inheritRateLimits(opS, defaultOpS *api_v2.PerOperationSamplingStrategies) {
+	if opS == nil || defaultOpS == nil {
+		return
+	}
+	if opS.DefaultLowerBoundTracesPerSecond == 0 {
+		opS.DefaultLowerBoundTracesPerSecond = defaultOpS.DefaultLowerBoundTracesPerSecond
+	}
+	if opS.DefaultUpperBoundTracesPerSecond == 0 {
+		opS.DefaultUpperBoundTracesPerSecond = defaultOpS.DefaultUpperBoundTracesPerSecond
+	}
+}
+
 func (h *samplingProvider) parseServiceStrategies(strategy *serviceStrategy) *api_v2.SamplingStrategyResponse {
 	resp := h.parseStrategy(&strategy.strategy)
-	if len(strategy.OperationStrategies) == 0 {
+	lowerBound, upperBound := h.parseRateLimits(strategy)
+	if len(strategy.OperationStrategies) == 0 && lowerBound == 0 && upperBound == 0 {
 		return resp
 	}
+	// the rate limits are only sent with the per-operation strategies, where the SDKs
+	// combine them with the probabilistic sampling of each operation
 	opS := &api_v2.PerOperationSamplingStrategies{
-		DefaultSamplingProbability: defaultSamplingProbability,
+		DefaultSamplingProbability:       defaultSamplingProbability,
+		DefaultLowerBoundTracesPerSecond: lowerBound,
+		DefaultUpperBoundTracesPerSecond: upperBound,
 	}
 	if resp.StrategyType == api_v2.SamplingStrategyType_PROBABILISTIC {
 		opS.DefaultSamplingProbability = resp.ProbabilisticSampling.SamplingRate
@@ -341,6 +361,20 @@ func (h *samplingProvider) parseServiceStrategies(strategy *serviceStrategy) *ap
 	return resp
 }
 
+// parseRateLimits returns the traces per second bounds of the strategy, or none if they are invalid.
+func (h *samplingProvider) parseRateLimits(strategy *serviceStrategy) (lowerBound, upperBound float64) {
+	lowerBound, upperBound = strategy.LowerBoundTracesPerSecond, strategy.UpperBoundTracesPerSecond
+	if lowerBound < 0 || upperBound < 0 || (upperBound > 0 && lowerBound > upperBound) {
+		h.logger.Warn("Ignoring invalid traces per second bounds of sampling strategy, "+
+			"they must be positive and the lower bound must not exceed the upper bound",
+			zap.String("service", strategy.Service),
+			zap.Float64("lower-bound", lowerBound),
+			zap.Float64("upper-bound", upperBound))
+		return 0, 0
+	}
+	return lowerBound, upperBound
+}
+
 func (h *samplingProvider) parseOperationStrategy(
 	strategy *operationStrategy,
 	parent *api_v2.PerOperationSamplingStrategies,
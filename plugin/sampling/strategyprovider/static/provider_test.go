@@ -254,6 +254,70 @@ func TestPerOperationSamplingStrategies(t *testing.T) {
 	}
 }
 
+func TestRateLimitsSamplingStrategies(t *testing.T) {
+	logger, buf := testutils.NewLogger()
+	provider, err := NewProvider(Options{StrategiesFile: "fixtures/rate_limits.json"}, logger)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Ignoring invalid traces per second bounds of sampling strategy")
+
+	testCases := []struct {
+		service  string
+		expected *api_v2.SamplingStrategyResponse
+	}{
+		{
+			service: "foo",
+			expected: &api_v2.SamplingStrategyResponse{
+				StrategyType:          api_v2.SamplingStrategyType_PROBABILISTIC,
+				ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.8},
+				OperationSampling: &api_v2.PerOperationSamplingStrategies{
+					DefaultSamplingProbability:       0.8,
+					DefaultLowerBoundTracesPerSecond: 0.1,
+					DefaultUpperBoundTracesPerSecond: 100,
+					PerOperationStrategies: []*api_v2.OperationSamplingStrategy{{
+						Operation:             "op1",
+						ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.2},
+					}},
+				},
+			},
+		},
+		{
+			service: "bar",
+			expected: &api_v2.SamplingStrategyResponse{
+				StrategyType:          api_v2.SamplingStrategyType_PROBABILISTIC,
+				ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.3},
+				OperationSampling: &api_v2.PerOperationSamplingStrategies{
+					DefaultSamplingProbability:       0.3,
+					DefaultLowerBoundTracesPerSecond: 0.1,
+					DefaultUpperBoundTracesPerSecond: 50,
+				},
+			},
+		},
+		{
+			service: "baz",
+			expected: &api_v2.SamplingStrategyResponse{
+				StrategyType:          api_v2.SamplingStrategyType_PROBABILISTIC,
+				ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.4},
+				OperationSampling: &api_v2.PerOperationSamplingStrategies{
+					DefaultSamplingProbability:       0.4,
+					DefaultLowerBoundTracesPerSecond: 0.1,
+					DefaultUpperBoundTracesPerSecond: 50,
+					PerOperationStrategies: []*api_v2.OperationSamplingStrategy{{
+						Operation:             "op1",
+						ProbabilisticSampling: &api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.2},
+					}},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.service, func(t *testing.T) {
+			strategy, err := provider.GetSamplingStrategy(context.Background(), tc.service)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, strategy)
+		})
+	}
+}
+
 func TestMissingServiceSamplingStrategyTypes(t *testing.T) {
 	logger, buf := testutils.NewLogger()
 	provider, err := NewProvider(Options{StrategiesFile: "fixtures/missing-service-types.json"}, logger)
@@ -27,10 +27,13 @@ type operationStrategy struct {
 	strategy
 }
 
-// serviceStrategy defines a service specific sampling strategy.
+// serviceStrategy defines a service specific sampling strategy. The traces per second bounds
+// are the rate limits combined with the probabilistic sampling of each operation of the service.
 type serviceStrategy struct {
-	Service             string               `json:"service"`
-	OperationStrategies []*operationStrategy `json:"operation_strategies"`
+	Service                   string               `json:"service"`
+	OperationStrategies       []*operationStrategy `json:"operation_strategies"`
+	LowerBoundTracesPerSecond float64              `json:"lower_bound_traces_per_second"`
+	UpperBoundTracesPerSecond float64              `json:"upper_bound_traces_per_second"`
 	strategy
 }
 
@@ -16,46 +16,139 @@ package dependencystore
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
 	"time"
 
+	"github.com/dgraph-io/badger/v4"
+
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// dependenciesKeyPrefix is the prefix of the keys of the dependencies written by WriteDependencies,
+// which are followed by the timestamp of the dependencies.
+const dependenciesKeyPrefix byte = 0x0A
+
 // DependencyStore handles all queries and insertions to Badger dependencies
 type DependencyStore struct {
 	reader spanstore.Reader
+	store  *badger.DB
+	ttl    time.Duration
 }
 
-// NewDependencyStore returns a DependencyStore
-func NewDependencyStore(store spanstore.Reader) *DependencyStore {
+// NewDependencyStore returns a DependencyStore aggregating dependencies from the spans
+// read with the reader and storing written dependencies in the database for the TTL.
+func NewDependencyStore(reader spanstore.Reader, db *badger.DB, ttl time.Duration) *DependencyStore {
 	return &DependencyStore{
-		reader: store,
+		reader: reader,
+		store:  db,
+		ttl:    ttl,
 	}
 }
 
-// GetDependencies returns all interservice dependencies, implements DependencyReader
+// GetDependencies returns all interservice dependencies, implements DependencyReader.
+// They are aggregated from the spans of the traces in the time window and from the
+// dependencies written for the time window. Links of different sources are returned separately.
 func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	deps := map[string]*model.DependencyLink{}
+	startTs := endTs.Add(-1 * lookback)
 
 	params := &spanstore.TraceQueryParameters{
-		StartTimeMin: endTs.Add(-1 * lookback),
+		StartTimeMin: startTs,
 		StartTimeMax: endTs,
+		// all the traces of the time window are aggregated, not only the default number of traces
+		NumTraces: math.MaxInt32,
 	}
 
 	// We need to do a full table scan - if this becomes a bottleneck, we can write an index that describes
 	// dependencyKeyPrefix + timestamp + parent + child key and do a key-only seek (which is fast - but requires additional writes)
+	traceIDs, err := s.reader.FindTraceIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// the traces are read one at a time to bound the memory used for large time windows
+	for _, traceID := range traceIDs {
+		trace, err := s.reader.GetTrace(ctx, spanstore.GetTraceParameters{TraceID: traceID})
+		if errors.Is(err, spanstore.ErrTraceNotFound) {
+			// expired since its ID was found
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		processTrace(deps, trace)
+	}
 
-	// GetDependencies is not shipped with a context like the SpanReader / SpanWriter
-	traces, err := s.reader.FindTraces(ctx, params)
+	written, err := s.getWrittenDependencies(startTs, endTs)
 	if err != nil {
 		return nil, err
 	}
-	for _, tr := range traces {
-		processTrace(deps, tr)
+	for _, link := range written {
+		addLink(deps, link)
 	}
+	return depMapToSlice(deps), nil
+}
 
-	return depMapToSlice(deps), err
+// WriteDependencies stores dependencies computed elsewhere, e.g. by a batch job, implements DependencyWriter.
+// Dependencies written with the same timestamp replace each other.
+func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	value, err := json.Marshal(dependencies)
+	if err != nil {
+		return err
+	}
+	return s.store.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(&badger.Entry{
+			Key:       dependenciesKey(ts),
+			Value:     value,
+			ExpiresAt: uint64(time.Now().Add(s.ttl).Unix()),
+		})
+	})
+}
+
+// getWrittenDependencies returns the links written with a timestamp after startTs and up to endTs.
+func (s *DependencyStore) getWrittenDependencies(startTs, endTs time.Time) ([]model.DependencyLink, error) {
+	var links []model.DependencyLink
+	err := s.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte{dependenciesKeyPrefix}
+		startMicros := model.TimeAsEpochMicroseconds(startTs)
+		endMicros := model.TimeAsEpochMicroseconds(endTs)
+		for it.Seek(dependenciesKey(startTs)); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			ts := binary.BigEndian.Uint64(item.Key()[1:])
+			if ts == startMicros {
+				continue
+			}
+			if ts > endMicros {
+				break
+			}
+			err := item.Value(func(val []byte) error {
+				var written []model.DependencyLink
+				if err := json.Unmarshal(val, &written); err != nil {
+					return err
+				}
+				links = append(links, written...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return links, err
+}
+
+func dependenciesKey(ts time.Time) []byte {
+	key := make([]byte, 9)
+	key[0] = dependenciesKeyPrefix
+	binary.BigEndian.PutUint64(key[1:], model.TimeAsEpochMicroseconds(ts))
+	return key
 }
 
 // depMapToSlice modifies the spans to DependencyLink in the same way as the memory storage plugin
@@ -67,6 +160,16 @@ func depMapToSlice(deps map[string]*model.DependencyLink) []model.DependencyLink
 	return retMe
 }
 
+// addLink adds the calls of the link to the aggregated links of the same parent, child and source.
+func addLink(deps map[string]*model.DependencyLink, link model.DependencyLink) {
+	depKey := link.Parent + "&&&" + link.Child + "&&&" + link.Source
+	if dep, ok := deps[depKey]; ok {
+		dep.CallCount += link.CallCount
+	} else {
+		deps[depKey] = &link
+	}
+}
+
 // processTrace is copy from the memory storage plugin
 func processTrace(deps map[string]*model.DependencyLink, trace *model.Trace) {
 	for _, s := range trace.Spans {
@@ -75,16 +178,11 @@ func processTrace(deps map[string]*model.DependencyLink, trace *model.Trace) {
 			if parentSpan.Process.ServiceName == s.Process.ServiceName {
 				continue
 			}
-			depKey := parentSpan.Process.ServiceName + "&&&" + s.Process.ServiceName
-			if _, ok := deps[depKey]; !ok {
-				deps[depKey] = &model.DependencyLink{
-					Parent:    parentSpan.Process.ServiceName,
-					Child:     s.Process.ServiceName,
-					CallCount: 1,
-				}
-			} else {
-				deps[depKey].CallCount++
-			}
+			addLink(deps, model.DependencyLink{
+				Parent:    parentSpan.Process.ServiceName,
+				Child:     s.Process.ServiceName,
+				CallCount: 1,
+			})
 		}
 	}
 }
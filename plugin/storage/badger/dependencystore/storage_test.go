@@ -44,6 +44,7 @@ func runFactoryTest(tb testing.TB, test func(tb testing.TB, sw spanstore.Writer,
 	command.ParseFlags([]string{
 		"--badger.ephemeral=true",
 		"--badger.consistency=false",
+		"--badger.dependencies-cache-ttl=0",
 	})
 	f.InitFromViper(v, zap.NewNop())
 
@@ -66,7 +67,7 @@ func TestDependencyReader(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, links)
 
-		traces := 40
+		traces := 150 // more than the default number of traces of a search
 		spans := 3
 		for i := 0; i < traces; i++ {
 			for j := 0; j < spans; j++ {
@@ -97,3 +98,41 @@ func TestDependencyReader(t *testing.T) {
 		assert.Equal(t, uint64(traces), links[0].CallCount) // Each trace calls the same services
 	})
 }
+
+func TestDependencyWriter(t *testing.T) {
+	runFactoryTest(t, func(_ testing.TB, sw spanstore.Writer, dr dependencystore.Reader) {
+		endTs := time.Now()
+		for j := 0; j < 2; j++ {
+			s := model.Span{
+				TraceID:   model.NewTraceID(1, 1),
+				SpanID:    model.SpanID(j),
+				Process:   &model.Process{ServiceName: fmt.Sprintf("service-%d", j)},
+				StartTime: endTs.Add(-time.Minute),
+			}
+			if j > 0 {
+				s.References = []model.SpanRef{model.NewChildOfRef(s.TraceID, model.SpanID(j-1))}
+			}
+			require.NoError(t, sw.WriteSpan(context.Background(), &s))
+		}
+
+		dw, ok := dr.(dependencystore.Writer)
+		require.True(t, ok)
+		require.NoError(t, dw.WriteDependencies(endTs.Add(-time.Minute), []model.DependencyLink{
+			{Parent: "service-0", Child: "service-1", CallCount: 2},
+			{Parent: "service-1", Child: "db", CallCount: 5, Source: model.JaegerDependencyLinkSource},
+		}))
+		require.NoError(t, dw.WriteDependencies(endTs.Add(-2*time.Hour), []model.DependencyLink{
+			{Parent: "service-0", Child: "cache", CallCount: 1},
+		}))
+		require.NoError(t, dw.WriteDependencies(endTs.Add(time.Minute), []model.DependencyLink{
+			{Parent: "service-0", Child: "queue", CallCount: 1},
+		}))
+
+		links, err := dr.GetDependencies(context.Background(), endTs, time.Hour)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "service-0", Child: "service-1", CallCount: 3},
+			{Parent: "service-1", Child: "db", CallCount: 5, Source: model.JaegerDependencyLinkSource},
+		}, links)
+	})
+}
@@ -45,6 +45,7 @@ const (
 	keyLogSpaceAvailableName   = "badger_key_log_bytes_available"
 	lastMaintenanceRunName     = "badger_storage_maintenance_last_run"
 	lastValueLogCleanedName    = "badger_storage_valueloggc_last_run"
+	purgedTracesName           = "badger_storage_service_ttl_purged_traces"
 )
 
 var ( // interface comformance checks
@@ -65,6 +66,8 @@ type Factory struct {
 	store   *badger.DB
 	cache   *badgerStore.CacheStore
 	logger  *zap.Logger
+	// dependencyStore aggregates the dependencies from the spans, possibly caching them
+	dependencyStore dependencystore.ReaderWriter
 
 	tmpDir          string
 	maintenanceDone chan bool
@@ -79,6 +82,8 @@ type Factory struct {
 		LastMaintenanceRun metrics.Gauge
 		// LastValueLogCleaned stores the timestamp (UnixNano) of the previous ValueLogGC run
 		LastValueLogCleaned metrics.Gauge
+		// PurgedTraces counts the traces deleted because their services TTLs elapsed
+		PurgedTraces metrics.Counter
 
 		// Expose badger's internal expvar metrics, which are all gauge's at this point
 		badgerMetrics map[string]metrics.Gauge
@@ -160,10 +165,16 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 
 	f.cache = badgerStore.NewCacheStore(f.store, f.Options.Primary.SpanStoreTTL, true)
 
+	f.dependencyStore = depStore.NewDependencyStore(badgerStore.NewTraceReader(f.store, f.cache), f.store, f.Options.Primary.SpanStoreTTL)
+	if ttl := f.Options.Primary.DependenciesCacheTTL; ttl > 0 {
+		f.dependencyStore = dependencystore.NewCachedStore(f.dependencyStore, ttl)
+	}
+
 	f.metrics.ValueLogSpaceAvailable = metricsFactory.Gauge(metrics.Options{Name: valueLogSpaceAvailableName})
 	f.metrics.KeyLogSpaceAvailable = metricsFactory.Gauge(metrics.Options{Name: keyLogSpaceAvailableName})
 	f.metrics.LastMaintenanceRun = metricsFactory.Gauge(metrics.Options{Name: lastMaintenanceRunName})
 	f.metrics.LastValueLogCleaned = metricsFactory.Gauge(metrics.Options{Name: lastValueLogCleanedName})
+	f.metrics.PurgedTraces = metricsFactory.Counter(metrics.Options{Name: purgedTracesName})
 
 	f.registerBadgerExpvarMetrics(metricsFactory)
 
@@ -189,13 +200,16 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return badgerStore.NewSpanWriter(f.store, f.cache, f.Options.Primary.SpanStoreTTL), nil
+	return f.newSpanWriter(), nil
+}
+
+func (f *Factory) newSpanWriter() *badgerStore.SpanWriter {
+	return badgerStore.NewSpanWriterWithServiceTTLs(f.store, f.cache, f.Options.Primary.SpanStoreTTL, f.Options.Primary.ServiceTTLs)
 }
 
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
-	sr, _ := f.CreateSpanReader() // err is always nil
-	return depStore.NewDependencyStore(sr), nil
+	return f.dependencyStore, nil
 }
 
 // CreateSamplingStore implements storage.SamplingStoreFactory
@@ -227,6 +241,18 @@ func (f *Factory) Close() error {
 	return err
 }
 
+// purgeServiceTTLs deletes the traces whose services TTLs elapsed, if any are configured.
+func (f *Factory) purgeServiceTTLs(now time.Time) {
+	if len(f.Options.Primary.ServiceTTLs) == 0 || f.Options.Primary.ReadOnly {
+		return
+	}
+	purged, err := f.newSpanWriter().PurgeServiceTTLs(context.Background(), now)
+	f.metrics.PurgedTraces.Inc(int64(purged))
+	if err != nil {
+		f.logger.Error("Failed to purge the traces of the service TTLs", zap.Error(err))
+	}
+}
+
 // Maintenance starts a background maintenance job for the badger K/V store, such as ValueLogGC
 func (f *Factory) maintenance() {
 	maintenanceTicker := time.NewTicker(f.Options.Primary.MaintenanceInterval)
@@ -236,6 +262,9 @@ func (f *Factory) maintenance() {
 		case <-f.maintenanceDone:
 			return
 		case t := <-maintenanceTicker.C:
+			// the traces are purged first so that the value log GC reclaims their space
+			f.purgeServiceTTLs(t)
+
 			var err error
 
 			// After there's nothing to clean, the err is raised
@@ -15,6 +15,7 @@
 package badger
 
 import (
+	"context"
 	"expvar"
 	"fmt"
 	"io"
@@ -27,8 +28,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 func TestInitializationErrors(t *testing.T) {
@@ -66,8 +69,9 @@ func TestForCodecov(t *testing.T) {
 	_, err = f.CreateSpanWriter()
 	require.NoError(t, err)
 
-	_, err = f.CreateDependencyReader()
+	depReader, err := f.CreateDependencyReader()
 	require.NoError(t, err)
+	assert.IsType(t, &dependencystore.CachedStore{}, depReader, "dependencies are cached by default")
 
 	lock, err := f.CreateLock()
 	require.NoError(t, err)
@@ -152,6 +156,29 @@ func TestMaintenanceCodecov(t *testing.T) {
 	waiter() // This should trigger the logging of error
 }
 
+func TestPurgeServiceTTLs(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{
+		"--badger.span-store-ttl-per-service=health-check=1h",
+	})
+	f.InitFromViper(v, zap.NewNop())
+	mFactory := metricstest.NewFactory(0)
+	require.NoError(t, f.Initialize(mFactory, zap.NewNop()))
+	defer f.Close()
+
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteSpan(context.Background(), &model.Span{
+		TraceID:   model.NewTraceID(0, 1),
+		Process:   model.NewProcess("health-check", nil),
+		StartTime: time.Now(),
+	}))
+
+	f.purgeServiceTTLs(time.Now().Add(2 * time.Hour))
+	mFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: purgedTracesName, Value: 1})
+}
+
 func TestBadgerMetrics(t *testing.T) {
 	// The expvar is leaking keyparams between tests. We need to clean up a bit..
 	eMap := expvar.Get("badger_size_bytes_lsm").(*expvar.Map)
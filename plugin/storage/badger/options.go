@@ -18,6 +18,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -32,22 +33,29 @@ type Options struct {
 
 // NamespaceConfig is badger's internal configuration data
 type NamespaceConfig struct {
-	namespace      string
-	SpanStoreTTL   time.Duration `mapstructure:"span_store_ttl"`
-	ValueDirectory string        `mapstructure:"directory_value"`
-	KeyDirectory   string        `mapstructure:"directory_key"`
+	namespace    string
+	SpanStoreTTL time.Duration `mapstructure:"span_store_ttl"`
+	// ServiceTTLs are how long the spans of some services are stored instead of SpanStoreTTL.
+	// The spans of a trace are all stored for the longest duration of the services of the trace.
+	ServiceTTLs    map[string]time.Duration `mapstructure:"span_store_ttl_per_service"`
+	ValueDirectory string                   `mapstructure:"directory_value"`
+	KeyDirectory   string                   `mapstructure:"directory_key"`
 	// Setting this to true will ignore ValueDirectory and KeyDirectory
 	Ephemeral             bool          `mapstructure:"ephemeral"`
 	SyncWrites            bool          `mapstructure:"consistency"`
 	MaintenanceInterval   time.Duration `mapstructure:"maintenance_interval"`
 	MetricsUpdateInterval time.Duration `mapstructure:"metrics_update_interval"`
 	ReadOnly              bool          `mapstructure:"read_only"`
+	// DependenciesCacheTTL is how long the dependencies aggregated from the spans are cached.
+	// Caching is disabled when it is zero.
+	DependenciesCacheTTL time.Duration `mapstructure:"dependencies_cache_ttl"`
 }
 
 const (
 	defaultMaintenanceInterval   time.Duration = 5 * time.Minute
 	defaultMetricsUpdateInterval time.Duration = 10 * time.Second
 	defaultTTL                   time.Duration = time.Hour * 72
+	defaultDependenciesCacheTTL  time.Duration = time.Minute
 )
 
 const (
@@ -55,10 +63,12 @@ const (
 	suffixValueDirectory      = ".directory-value"
 	suffixEphemeral           = ".ephemeral"
 	suffixSpanstoreTTL        = ".span-store-ttl"
+	suffixServiceTTLs         = ".span-store-ttl-per-service"
 	suffixSyncWrite           = ".consistency"
 	suffixMaintenanceInterval = ".maintenance-interval"
 	suffixMetricsInterval     = ".metrics-update-interval" // Intended only for testing purposes
 	suffixReadOnly            = ".read-only"
+	suffixDependenciesCache   = ".dependencies-cache-ttl"
 	defaultDataDir            = string(os.PathSeparator) + "data"
 	defaultValueDir           = defaultDataDir + string(os.PathSeparator) + "values"
 	defaultKeysDir            = defaultDataDir + string(os.PathSeparator) + "keys"
@@ -74,6 +84,7 @@ func DefaultNamespaceConfig() NamespaceConfig {
 		KeyDirectory:          defaultBadgerDataDir + defaultKeysDir,
 		MaintenanceInterval:   defaultMaintenanceInterval,
 		MetricsUpdateInterval: defaultMetricsUpdateInterval,
+		DependenciesCacheTTL:  defaultDependenciesCacheTTL,
 	}
 }
 
@@ -111,6 +122,11 @@ func addFlags(flagSet *flag.FlagSet, nsConfig NamespaceConfig) {
 		nsConfig.SpanStoreTTL,
 		"How long to store the data. Format is time.Duration (https://golang.org/pkg/time/#Duration)",
 	)
+	flagSet.String(
+		nsConfig.namespace+suffixServiceTTLs,
+		"",
+		"How long to store the spans of some services instead of --"+nsConfig.namespace+suffixSpanstoreTTL+", the traces being stored as long as the longest duration of their services. Ex: payments=720h,health-check=1h",
+	)
 	flagSet.String(
 		nsConfig.namespace+suffixKeyDirectory,
 		nsConfig.KeyDirectory,
@@ -141,6 +157,11 @@ func addFlags(flagSet *flag.FlagSet, nsConfig NamespaceConfig) {
 		nsConfig.ReadOnly,
 		"Allows to open badger database in read only mode. Multiple instances can open same database in read-only mode. Values still in the write-ahead-log must be replayed before opening.",
 	)
+	flagSet.Duration(
+		nsConfig.namespace+suffixDependenciesCache,
+		nsConfig.DependenciesCacheTTL,
+		"How long the dependencies aggregated from the stored spans are cached. Zero disables caching. Format is time.Duration (https://golang.org/pkg/time/#Duration)",
+	)
 }
 
 // InitFromViper initializes Options with properties from viper
@@ -148,15 +169,38 @@ func (opt *Options) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 	initFromViper(&opt.Primary, v, logger)
 }
 
-func initFromViper(cfg *NamespaceConfig, v *viper.Viper, _ *zap.Logger) {
+func initFromViper(cfg *NamespaceConfig, v *viper.Viper, logger *zap.Logger) {
 	cfg.Ephemeral = v.GetBool(cfg.namespace + suffixEphemeral)
 	cfg.KeyDirectory = v.GetString(cfg.namespace + suffixKeyDirectory)
 	cfg.ValueDirectory = v.GetString(cfg.namespace + suffixValueDirectory)
 	cfg.SyncWrites = v.GetBool(cfg.namespace + suffixSyncWrite)
 	cfg.SpanStoreTTL = v.GetDuration(cfg.namespace + suffixSpanstoreTTL)
+	cfg.ServiceTTLs = parseServiceTTLs(v.GetString(cfg.namespace+suffixServiceTTLs), logger)
 	cfg.MaintenanceInterval = v.GetDuration(cfg.namespace + suffixMaintenanceInterval)
 	cfg.MetricsUpdateInterval = v.GetDuration(cfg.namespace + suffixMetricsInterval)
 	cfg.ReadOnly = v.GetBool(cfg.namespace + suffixReadOnly)
+	cfg.DependenciesCacheTTL = v.GetDuration(cfg.namespace + suffixDependenciesCache)
+}
+
+// parseServiceTTLs parses the service=duration pairs, ignoring the invalid ones.
+func parseServiceTTLs(serviceTTLs string, logger *zap.Logger) map[string]time.Duration {
+	var ttls map[string]time.Duration
+	for _, pair := range strings.Split(serviceTTLs, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		service, value, _ := strings.Cut(pair, "=")
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || ttl <= 0 {
+			logger.Error("Ignoring invalid service TTL, expected service=duration", zap.String("pair", pair), zap.Error(err))
+			continue
+		}
+		if ttls == nil {
+			ttls = make(map[string]time.Duration)
+		}
+		ttls[strings.TrimSpace(service)] = ttl
+	}
+	return ttls
 }
 
 // GetPrimary returns the primary namespace configuration
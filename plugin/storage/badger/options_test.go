@@ -33,6 +33,7 @@ func TestDefaultOptionsParsing(t *testing.T) {
 	assert.True(t, opts.GetPrimary().Ephemeral)
 	assert.False(t, opts.GetPrimary().SyncWrites)
 	assert.Equal(t, time.Duration(72*time.Hour), opts.GetPrimary().SpanStoreTTL)
+	assert.Equal(t, time.Minute, opts.GetPrimary().DependenciesCacheTTL)
 }
 
 func TestParseOptions(t *testing.T) {
@@ -44,6 +45,7 @@ func TestParseOptions(t *testing.T) {
 		"--badger.directory-key=/var/lib/badger",
 		"--badger.directory-value=/mnt/slow/badger",
 		"--badger.span-store-ttl=168h",
+		"--badger.dependencies-cache-ttl=5m",
 	})
 	opts.InitFromViper(v, zap.NewNop())
 
@@ -53,6 +55,21 @@ func TestParseOptions(t *testing.T) {
 	assert.Equal(t, "/var/lib/badger", opts.GetPrimary().KeyDirectory)
 	assert.Equal(t, "/mnt/slow/badger", opts.GetPrimary().ValueDirectory)
 	assert.False(t, opts.GetPrimary().ReadOnly)
+	assert.Equal(t, 5*time.Minute, opts.GetPrimary().DependenciesCacheTTL)
+}
+
+func TestParseServiceTTLs(t *testing.T) {
+	opts := NewOptions("badger")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--badger.span-store-ttl-per-service=payments=720h, health-check = 1h,orders,search=-1h",
+	})
+	opts.InitFromViper(v, zap.NewNop())
+
+	assert.Equal(t, map[string]time.Duration{
+		"payments":     720 * time.Hour,
+		"health-check": time.Hour,
+	}, opts.GetPrimary().ServiceTTLs, "the invalid durations are ignored")
 }
 
 func TestReadOnlyOptions(t *testing.T) {
@@ -79,10 +79,10 @@ func TestWriteReadBack(t *testing.T) {
 		}
 
 		for i := 0; i < traces; i++ {
-			tr, err := sr.GetTrace(context.Background(), model.TraceID{
+			tr, err := sr.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{
 				Low:  uint64(i),
 				High: 1,
-			})
+			}})
 			require.NoError(t, err)
 
 			assert.Len(t, tr.Spans, spans)
@@ -302,12 +302,57 @@ func TestFindNothing(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, trs)
 
-		tr, err := sr.GetTrace(context.Background(), model.TraceID{High: 0, Low: 0})
+		tr, err := sr.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{High: 0, Low: 0}})
 		assert.Equal(t, spanstore.ErrTraceNotFound, err)
 		assert.Nil(t, tr)
 	})
 }
 
+func TestDeleteTraces(t *testing.T) {
+	runFactoryTest(t, func(_ testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		startTime := time.Now()
+		for i, user := range []string{"alice", "bob"} {
+			for j := 0; j < 3; j++ {
+				err := sw.WriteSpan(context.Background(), &model.Span{
+					TraceID:       model.NewTraceID(1, uint64(i)),
+					SpanID:        model.NewSpanID(uint64(j + 1)),
+					OperationName: "operation",
+					Process:       &model.Process{ServiceName: "service"},
+					StartTime:     startTime.Add(time.Duration(j) * time.Millisecond),
+					Duration:      time.Millisecond,
+					Tags:          []model.KeyValue{model.String("user.id", user)},
+				})
+				require.NoError(t, err)
+			}
+		}
+
+		deleter, ok := sw.(spanstore.TraceDeleter)
+		require.True(t, ok)
+		require.NoError(t, deleter.DeleteTraces(context.Background(), []model.TraceID{model.NewTraceID(1, 0), model.NewTraceID(2, 0)}))
+
+		_, err := sr.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(1, 0)})
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+		trace, err := sr.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(1, 1)})
+		require.NoError(t, err)
+		assert.Len(t, trace.Spans, 3)
+
+		query := &spanstore.TraceQueryParameters{
+			ServiceName:  "service",
+			StartTimeMin: startTime.Add(-time.Second),
+			StartTimeMax: startTime.Add(time.Second),
+			NumTraces:    10,
+		}
+		traceIDs, err := sr.FindTraceIDs(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(1, 1)}, traceIDs)
+
+		query.Tags = map[string]string{"user.id": "alice"}
+		traceIDs, err = sr.FindTraceIDs(context.Background(), query)
+		require.NoError(t, err)
+		assert.Empty(t, traceIDs)
+	})
+}
+
 func TestWriteDuplicates(t *testing.T) {
 	runFactoryTest(t, func(_ testing.TB, sw spanstore.Writer, _ spanstore.Reader) {
 		tid := time.Now()
@@ -429,10 +474,10 @@ func TestPersist(t *testing.T) {
 	})
 
 	p(t, dir, func(t *testing.T, _ spanstore.Writer, sr spanstore.Reader) {
-		trace, err := sr.GetTrace(context.Background(), model.TraceID{
+		trace, err := sr.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{
 			Low:  uint64(1),
 			High: 1,
-		})
+		}})
 		require.NoError(t, err)
 		assert.Equal(t, "operation-p", trace.Spans[0].OperationName)
 
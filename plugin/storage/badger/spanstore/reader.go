@@ -157,8 +157,8 @@ func (r *TraceReader) getTraces(traceIDs []model.TraceID) ([]*model.Trace, error
 }
 
 // GetTrace takes a traceID and returns a Trace associated with that traceID
-func (r *TraceReader) GetTrace(_ context.Context, traceID model.TraceID) (*model.Trace, error) {
-	traces, err := r.getTraces([]model.TraceID{traceID})
+func (r *TraceReader) GetTrace(_ context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	traces, err := r.getTraces([]model.TraceID{query.TraceID})
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +265,7 @@ func serviceQueries(query *spanstore.TraceQueryParameters, indexSeeks [][]byte)
 	if query.ServiceName != "" {
 		indexSearchKey := make([]byte, 0, 64) // 64 is a magic guess
 		tagQueryUsed := false
-		for k, v := range query.Tags {
+		for k, v := range query.AllTags() {
 			tagSearch := []byte(query.ServiceName + k + v)
 			tagSearchKey := make([]byte, 0, len(tagSearch)+1)
 			tagSearchKey = append(tagSearchKey, tagIndexKey)
@@ -509,7 +509,7 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	if p.ServiceName == "" && len(p.AllTags()) > 0 {
 		return ErrServiceNameNotSet
 	}
 	if p.ServiceName == "" && p.OperationName != "" {
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// ServiceTTLs are the durations the spans of some services are kept, instead of the TTL of the
+// SpanWriter. The spans of a trace are all kept for the longest duration of the services of the
+// trace, so that a trace is never partially expired.
+type ServiceTTLs map[string]time.Duration
+
+// maxTTL returns the longest of the durations and of the ttl.
+func (s ServiceTTLs) maxTTL(ttl time.Duration) time.Duration {
+	for _, serviceTTL := range s {
+		ttl = max(ttl, serviceTTL)
+	}
+	return ttl
+}
+
+// purgeBatchSize is the number of expired traces deleted at once.
+const purgeBatchSize = 100
+
+// PurgeServiceTTLs deletes the traces that are older than the longest duration of their
+// services, the services not in ServiceTTLs being kept for the TTL of the SpanWriter.
+// Badger expires the spans only after the longest duration of all the services, so this must
+// be run periodically. It returns the number of traces deleted.
+func (w *SpanWriter) PurgeServiceTTLs(ctx context.Context, now time.Time) (int, error) {
+	if len(w.serviceTTLs) == 0 {
+		return 0, nil
+	}
+	var expired []model.TraceID
+	err := w.store.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte{spanKeyPrefix}
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		var traceID model.TraceID
+		var traceTTL time.Duration
+		var lastWrite time.Time
+		flush := func() {
+			if !lastWrite.IsZero() && lastWrite.Add(traceTTL).Before(now) {
+				expired = append(expired, traceID)
+			}
+		}
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			// the secondary indexes share the prefix bit, the spans have exactly the prefix
+			if key[0] != spanKeyPrefix || item.ExpiresAt() == 0 {
+				continue
+			}
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			span, err := decodeValue(val, item.UserMeta()&encodingTypeBits)
+			if err != nil {
+				return err
+			}
+			if span.TraceID != traceID {
+				flush()
+				traceID, traceTTL, lastWrite = span.TraceID, 0, time.Time{}
+			}
+			serviceTTL, ok := w.serviceTTLs[span.Process.ServiceName]
+			if !ok {
+				serviceTTL = w.ttl
+			}
+			traceTTL = max(traceTTL, serviceTTL)
+			// the spans expire after the longest TTL, which tells when they were written
+			if written := time.Unix(int64(item.ExpiresAt()), 0).Add(-w.maxTTL); written.After(lastWrite) {
+				lastWrite = written
+			}
+		}
+		flush()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(expired); i += purgeBatchSize {
+		if err := w.DeleteTraces(ctx, expired[i:min(i+purgeBatchSize, len(expired))]); err != nil {
+			return i, err
+		}
+	}
+	return len(expired), nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestPurgeServiceTTLs(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, 24*time.Hour, true)
+		sw := NewSpanWriterWithServiceTTLs(store, cache, 24*time.Hour, ServiceTTLs{
+			"payments":     720 * time.Hour,
+			"health-check": time.Hour,
+		})
+		rw := NewTraceReader(store, cache)
+
+		paymentsTrace, healthTrace, ordersTrace := model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)
+		for i, span := range []struct {
+			traceID model.TraceID
+			service string
+		}{
+			{paymentsTrace, "payments"},
+			{paymentsTrace, "health-check"},
+			{healthTrace, "health-check"},
+			{ordersTrace, "orders"},
+		} {
+			require.NoError(t, sw.WriteSpan(context.Background(), &model.Span{
+				TraceID:       span.traceID,
+				SpanID:        model.SpanID(i + 1),
+				OperationName: "operation",
+				Process:       model.NewProcess(span.service, nil),
+				StartTime:     time.Now(),
+			}))
+		}
+		spansOf := func(traceID model.TraceID) int {
+			trace, err := rw.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: traceID})
+			if err != nil {
+				require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+				return 0
+			}
+			return len(trace.Spans)
+		}
+
+		purged, err := sw.PurgeServiceTTLs(context.Background(), time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, 0, spansOf(healthTrace))
+		assert.Equal(t, 2, spansOf(paymentsTrace), "the longest service TTL wins")
+		assert.Equal(t, 1, spansOf(ordersTrace))
+
+		purged, err = sw.PurgeServiceTTLs(context.Background(), time.Now().Add(25*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, 0, spansOf(ordersTrace), "the other services are kept for the TTL")
+		assert.Equal(t, 2, spansOf(paymentsTrace))
+
+		purged, err = sw.PurgeServiceTTLs(context.Background(), time.Now().Add(721*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, 0, spansOf(paymentsTrace))
+	})
+}
+
+func TestPurgeServiceTTLsDisabled(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		sw := NewSpanWriter(store, NewCacheStore(store, time.Hour, false), time.Hour)
+		testSpan := createDummySpan()
+		require.NoError(t, sw.WriteSpan(context.Background(), &testSpan))
+
+		purged, err := sw.PurgeServiceTTLs(context.Background(), time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged, "Badger expires the spans itself")
+	})
+}
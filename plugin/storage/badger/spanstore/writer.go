@@ -52,21 +52,34 @@ type SpanWriter struct {
 	ttl          time.Duration
 	cache        *CacheStore
 	encodingType byte
+	serviceTTLs  ServiceTTLs
+	// maxTTL is the longest of the ttl and of the serviceTTLs
+	maxTTL time.Duration
 }
 
 // NewSpanWriter returns a SpawnWriter with cache
 func NewSpanWriter(db *badger.DB, c *CacheStore, ttl time.Duration) *SpanWriter {
+	return NewSpanWriterWithServiceTTLs(db, c, ttl, nil)
+}
+
+// NewSpanWriterWithServiceTTLs returns a SpanWriter keeping the spans of some services for
+// another duration than the ttl, see ServiceTTLs.
+func NewSpanWriterWithServiceTTLs(db *badger.DB, c *CacheStore, ttl time.Duration, serviceTTLs ServiceTTLs) *SpanWriter {
 	return &SpanWriter{
 		store:        db,
 		ttl:          ttl,
 		cache:        c,
 		encodingType: defaultEncoding, // TODO Make configurable
+		serviceTTLs:  serviceTTLs,
+		maxTTL:       serviceTTLs.maxTTL(ttl),
 	}
 }
 
 // WriteSpan writes the encoded span as well as creates indexes with defined TTL
 func (w *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
-	expireTime := uint64(time.Now().Add(w.ttl).Unix())
+	// with service TTLs, every span expires after the longest one and the shorter ones are
+	// enforced by PurgeServiceTTLs
+	expireTime := uint64(time.Now().Add(w.maxTTL).Unix())
 	startTime := model.TimeAsEpochMicroseconds(span.StartTime)
 
 	// Avoid doing as much as possible inside the transaction boundary, create entries here
@@ -78,28 +91,8 @@ func (w *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
 	}
 
 	entriesToStore = append(entriesToStore, trace)
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), startTime, span.TraceID), nil, expireTime))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), startTime, span.TraceID), nil, expireTime))
-
-	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
-	durationValue := make([]byte, 8)
-	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(durationIndexKey, durationValue, startTime, span.TraceID), nil, expireTime))
-
-	for _, kv := range span.Tags {
-		// Convert everything to string since queries are done that way also
-		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-	}
-
-	for _, kv := range span.Process.Tags {
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-	}
-
-	for _, log := range span.Logs {
-		for _, kv := range log.Fields {
-			entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-		}
+	for _, key := range createIndexKeys(span, startTime) {
+		entriesToStore = append(entriesToStore, w.createBadgerEntry(key, nil, expireTime))
 	}
 
 	err = w.store.Update(func(txn *badger.Txn) error {
@@ -124,6 +117,76 @@ func (w *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
 	return err
 }
 
+// DeleteTraces deletes the spans of the given traces along with their index keys,
+// implementing spanstore.TraceDeleter.
+func (w *SpanWriter) DeleteTraces(_ context.Context, traceIDs []model.TraceID) error {
+	var keys [][]byte
+	err := w.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for _, traceID := range traceIDs {
+			prefix := createPrimaryKeySeekPrefix(traceID)
+			for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+				item := it.Item()
+				val, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				span, err := decodeValue(val, item.UserMeta()&encodingTypeBits)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, item.KeyCopy(nil))
+				keys = append(keys, createIndexKeys(span, model.TimeAsEpochMicroseconds(span.StartTime))...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// a write batch splits the deletes of large traces into several transactions
+	batch := w.store.NewWriteBatch()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			batch.Cancel()
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// createIndexKeys returns the keys of the secondary indexes of the span.
+func createIndexKeys(span *model.Span, startTime uint64) [][]byte {
+	keys := make([][]byte, 0, len(span.Tags)+3+len(span.Process.Tags)+len(span.Logs)*4)
+	keys = append(keys, createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), startTime, span.TraceID))
+	keys = append(keys, createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), startTime, span.TraceID))
+
+	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
+	durationValue := make([]byte, 8)
+	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
+	keys = append(keys, createIndexKey(durationIndexKey, durationValue, startTime, span.TraceID))
+
+	for _, kv := range span.Tags {
+		// Convert everything to string since queries are done that way also
+		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
+		keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+	}
+
+	for _, kv := range span.Process.Tags {
+		keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+	}
+
+	for _, log := range span.Logs {
+		for _, kv := range log.Fields {
+			keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+		}
+	}
+	return keys
+}
+
 func createIndexKey(indexPrefixKey byte, value []byte, startTime uint64, traceID model.TraceID) []byte {
 	// KEY: indexKey<indexValue><startTime><traceId> (traceId is last 16 bytes of the key)
 	key := make([]byte, 1+len(value)+8+sizeOfTraceID)
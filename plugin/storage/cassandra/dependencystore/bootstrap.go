@@ -16,14 +16,29 @@
 package dependencystore
 
 import (
+	"time"
+
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 )
 
 // GetDependencyVersion attempts to determine the version of the dependencies table.
 // TODO: Remove this once we've migrated to V2 permanently. https://github.com/jaegertracing/jaeger/issues/1344
 func GetDependencyVersion(s cassandra.Session) Version {
+	if hasDependenciesV3(s) {
+		return V3
+	}
 	if err := s.Query("SELECT ts from dependencies_v2 limit 1;").Exec(); err != nil {
 		return V1
 	}
 	return V2
 }
+
+// hasDependenciesV3 returns true if the dependencies_v3 table contains dependencies. The table
+// existing is not enough, because it is created along with dependencies_v2 while the job
+// aggregating the dependencies may still be writing to dependencies_v2.
+func hasDependenciesV3(s cassandra.Session) bool {
+	var ts time.Time
+	iter := s.Query("SELECT ts from dependencies_v3 limit 1;").Iter()
+	found := iter.Scan(&ts)
+	return iter.Close() == nil && found
+}
@@ -17,6 +17,7 @@ package dependencystore
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,11 +26,23 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
 )
 
+func mockDependenciesV3(session *mocks.Session, hasRows bool, err error) {
+	iter := &mocks.Iterator{}
+	iter.On("Scan", mock.Anything).Return(hasRows)
+	iter.On("Close").Return(err)
+	query := &mocks.Query{}
+	query.On("Iter").Return(iter)
+	session.On("Query", mock.MatchedBy(func(q string) bool {
+		return strings.Contains(q, "dependencies_v3")
+	}), mock.Anything).Return(query)
+}
+
 func TestGetDependencyVersionV1(t *testing.T) {
 	var (
 		session = &mocks.Session{}
 		query   = &mocks.Query{}
 	)
+	mockDependenciesV3(session, false, errors.New("error"))
 	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
 	query.On("Exec").Return(errors.New("error"))
 
@@ -41,7 +54,15 @@ func TestGetDependencyVersionV2(t *testing.T) {
 		session = &mocks.Session{}
 		query   = &mocks.Query{}
 	)
+	// dependencies_v3 exists but has not been written to yet
+	mockDependenciesV3(session, false, nil)
 	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
 	query.On("Exec").Return(nil)
 	assert.Equal(t, V2, GetDependencyVersion(session))
 }
+
+func TestGetDependencyVersionV3(t *testing.T) {
+	session := &mocks.Session{}
+	mockDependenciesV3(session, true, nil)
+	assert.Equal(t, V3, GetDependencyVersion(session))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	// compactorLock is the resource of the distributed lock held by the instance running the compaction.
+	compactorLock = "dependencies_compaction"
+	// compactionDelay is how long after its end a day is compacted, so that no dependency is written to it anymore.
+	compactionDelay = 24 * time.Hour
+	// compactionLookback is how many days before compactionDelay are compacted on every run,
+	// to catch up with the runs missed while no instance held the lock.
+	compactionLookback = 7 * rollupGranularity
+)
+
+// CompactorOptions configures a Compactor.
+type CompactorOptions struct {
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// Retention is how long the dependencies are kept before their partitions are pruned.
+	// Zero leaves them to the TTL of the table.
+	Retention time.Duration
+}
+
+type compactorMetrics struct {
+	compactedRows metrics.Counter
+	prunedBuckets metrics.Counter
+	failures      metrics.Counter
+}
+
+// Compactor is a maintenance routine rolling the dependencies of every past day up into a single
+// row with DependencyStore.CompactDay, and pruning the partitions older than the retention, which
+// reduces the rows read by long lookbacks. The instances of the Jaeger components share the work
+// through a distributed lock: the one holding it runs the compaction.
+type Compactor struct {
+	store   *DependencyStore
+	lock    distributedlock.Lock
+	options CompactorOptions
+	metrics compactorMetrics
+	logger  *zap.Logger
+	now     func() time.Time
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewCompactor creates a Compactor of the dependencies of the store.
+func NewCompactor(
+	store *DependencyStore,
+	lock distributedlock.Lock,
+	options CompactorOptions,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) *Compactor {
+	metricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "dependencies_compaction"})
+	return &Compactor{
+		store:   store,
+		lock:    lock,
+		options: options,
+		metrics: compactorMetrics{
+			compactedRows: metricsFactory.Counter(metrics.Options{Name: "compacted_rows", Help: "Number of dependency rows rolled up into daily rows"}),
+			prunedBuckets: metricsFactory.Counter(metrics.Options{Name: "pruned_buckets", Help: "Number of dependency partitions pruned after the retention"}),
+			failures:      metricsFactory.Counter(metrics.Options{Name: "failures", Help: "Number of failed dependency compactions"}),
+		},
+		logger: logger,
+		now:    time.Now,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start runs the compaction in the background, every interval.
+func (c *Compactor) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.options.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.run()
+			case <-c.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the compaction.
+func (c *Compactor) Close() error {
+	close(c.stopCh)
+	c.wg.Wait()
+	return nil
+}
+
+// run compacts the days of the lookback and prunes the partitions that expired since the previous run,
+// if this instance holds the lock.
+func (c *Compactor) run() {
+	acquired, err := c.lock.Acquire(compactorLock, c.options.Interval)
+	if err != nil {
+		c.logger.Error("Failed to acquire the dependencies compaction lock", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+	now := c.now()
+	end := now.Add(-compactionDelay).Truncate(rollupGranularity)
+	for day := end.Add(-compactionLookback); day.Before(end); day = day.Add(rollupGranularity) {
+		deleted, err := c.store.CompactDay(day)
+		c.metrics.compactedRows.Inc(int64(deleted))
+		if err != nil {
+			c.metrics.failures.Inc(1)
+			c.logger.Error("Failed to compact dependencies", zap.Time("day", day), zap.Error(err))
+			return
+		}
+	}
+	if c.options.Retention > 0 {
+		expiry := now.Add(-c.options.Retention)
+		pruned, err := c.store.PruneBuckets(expiry.Add(-c.options.Interval), expiry)
+		if err != nil {
+			c.metrics.failures.Inc(1)
+			c.logger.Error("Failed to prune dependencies", zap.Time("expiry", expiry), zap.Error(err))
+			return
+		}
+		c.metrics.prunedBuckets.Inc(int64(pruned))
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	lockMocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
+)
+
+var compactedDay = time.Date(2017, time.January, 24, 0, 0, 0, 0, time.UTC)
+
+type dependencyRow struct {
+	ts           time.Time
+	granularity  time.Duration
+	dependencies []Dependency
+}
+
+// mockDependencyRows makes the session return the given rows of dependencies_v3.
+func mockDependencyRows(s *depStorageTest, rows []dependencyRow) {
+	scanFunc := func(args []any) bool {
+		if len(rows) == 0 {
+			return false
+		}
+		*(args[0].(*time.Time)) = rows[0].ts
+		*(args[1].(*int)) = int(rows[0].granularity.Seconds())
+		*(args[2].(*[]Dependency)) = rows[0].dependencies
+		rows = rows[1:]
+		return true
+	}
+	iter := &mocks.Iterator{}
+	iter.On("Scan", mock.MatchedBy(scanFunc)).Return(true)
+	iter.On("Scan", matchEverything()).Return(false)
+	iter.On("Close").Return(nil)
+
+	query := &mocks.Query{}
+	query.On("Consistency", cassandra.One).Return(query)
+	query.On("Iter").Return(iter)
+	s.session.On("Query", depsSelectStmtV3, matchEverything()).Return(query)
+}
+
+// mockExec makes the session execute the given statement, and returns the arguments of every query.
+func mockExec(s *depStorageTest, stmt string, err error) *[][]any {
+	var args [][]any
+	query := &mocks.Query{}
+	query.On("Exec").Return(err)
+	query.On("String").Return(stmt)
+	s.session.On("Query", stmt, matchEverything()).Return(query).Run(func(a mock.Arguments) {
+		args = append(args, a.Get(1).([]any))
+	})
+	return &args
+}
+
+func TestCompactDay(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		ts1 := compactedDay
+		ts2 := compactedDay.Add(90 * time.Minute)
+		mockDependencyRows(s, []dependencyRow{
+			{ts: ts1, granularity: time.Hour, dependencies: []Dependency{
+				{Parent: "a", Child: "b", CallCount: 10, Source: "jaeger", EdgeSource: "client"},
+				{Parent: "a", Child: "b", CallCount: 8, Source: "jaeger", EdgeSource: "server"},
+			}},
+			{ts: ts2, granularity: time.Hour, dependencies: []Dependency{
+				{Parent: "a", Child: "b", CallCount: 5, Source: "jaeger", EdgeSource: "client"},
+				{Parent: "b", Child: "c", CallCount: 1, Source: "jaeger"},
+			}},
+		})
+		inserts := mockExec(s, depsInsertStmtV3, nil)
+		deletes := mockExec(s, depsDeleteStmtV3, nil)
+
+		deleted, err := s.storage.CompactDay(compactedDay.Add(3 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+		assert.Equal(t, [][]any{{
+			compactedDay,
+			compactedDay,
+			86400,
+			[]Dependency{
+				{Parent: "a", Child: "b", CallCount: 15, Source: "jaeger", EdgeSource: "client"},
+				{Parent: "a", Child: "b", CallCount: 8, Source: "jaeger", EdgeSource: "server"},
+				{Parent: "b", Child: "c", CallCount: 1, Source: "jaeger"},
+			},
+		}}, *inserts)
+		// the row at the beginning of the day is overwritten by the rollup
+		assert.Equal(t, [][]any{{compactedDay.Add(time.Hour), ts2}}, *deletes)
+	})
+}
+
+func TestCompactDayWithRollup(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		ts := compactedDay.Add(90 * time.Minute)
+		mockDependencyRows(s, []dependencyRow{
+			{ts: compactedDay, granularity: rollupGranularity, dependencies: []Dependency{{Parent: "a", Child: "b", CallCount: 15}}},
+			{ts: ts, granularity: time.Hour, dependencies: []Dependency{{Parent: "a", Child: "b", CallCount: 5}}},
+		})
+		deletes := mockExec(s, depsDeleteStmtV3, nil)
+
+		deleted, err := s.storage.CompactDay(compactedDay)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+		assert.Equal(t, [][]any{{compactedDay.Add(time.Hour), ts}}, *deletes)
+		s.session.AssertNotCalled(t, "Query", depsInsertStmtV3, matchEverything())
+	})
+}
+
+func TestCompactDayNothingToCompact(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		mockDependencyRows(s, []dependencyRow{
+			{ts: compactedDay, granularity: rollupGranularity, dependencies: []Dependency{{Parent: "a", Child: "b", CallCount: 15}}},
+		})
+		deleted, err := s.storage.CompactDay(compactedDay)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func TestCompactDayErrors(t *testing.T) {
+	withDepStore(V2, func(s *depStorageTest) {
+		_, err := s.storage.CompactDay(compactedDay)
+		require.ErrorIs(t, err, errCompactionNotSupported)
+		_, err = s.storage.PruneBuckets(compactedDay, compactedDay.Add(time.Hour))
+		require.ErrorIs(t, err, errCompactionNotSupported)
+	})
+	rows := []dependencyRow{{ts: compactedDay.Add(time.Hour), granularity: time.Hour}}
+	withDepStore(V3, func(s *depStorageTest) {
+		mockDependencyRows(s, rows)
+		mockExec(s, depsInsertStmtV3, errors.New("insert error"))
+		_, err := s.storage.CompactDay(compactedDay)
+		require.ErrorContains(t, err, "insert error")
+	})
+	withDepStore(V3, func(s *depStorageTest) {
+		mockDependencyRows(s, rows)
+		mockExec(s, depsInsertStmtV3, nil)
+		mockExec(s, depsDeleteStmtV3, errors.New("delete error"))
+		_, err := s.storage.CompactDay(compactedDay)
+		require.ErrorContains(t, err, "delete error")
+	})
+}
+
+func TestPruneBuckets(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		prunes := mockExec(s, depsPruneStmtV3, nil)
+		pruned, err := s.storage.PruneBuckets(compactedDay.Add(30*time.Minute), compactedDay.Add(150*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 2, pruned)
+		assert.Equal(t, [][]any{{[]time.Time{compactedDay, compactedDay.Add(time.Hour)}}}, *prunes)
+
+		pruned, err = s.storage.PruneBuckets(compactedDay.Add(30*time.Minute), compactedDay.Add(50*time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, pruned, "the partition still has rows newer than the end")
+	})
+	withDepStore(V3, func(s *depStorageTest) {
+		mockExec(s, depsPruneStmtV3, errors.New("prune error"))
+		_, err := s.storage.PruneBuckets(compactedDay, compactedDay.Add(time.Hour))
+		require.ErrorContains(t, err, "prune error")
+	})
+}
+
+func withCompactor(t *testing.T, options CompactorOptions, fn func(s *depStorageTest, lock *lockMocks.Lock, c *Compactor, mf *metricstest.Factory)) {
+	withDepStore(V3, func(s *depStorageTest) {
+		lock := lockMocks.NewLock(t)
+		mf := metricstest.NewFactory(time.Hour)
+		defer mf.Stop()
+		c := NewCompactor(s.storage, lock, options, mf, s.logger)
+		c.now = func() time.Time { return compactedDay.Add(50*time.Hour + 30*time.Minute) }
+		fn(s, lock, c, mf)
+	})
+}
+
+func TestCompactorRun(t *testing.T) {
+	withCompactor(t, CompactorOptions{Interval: time.Hour, Retention: 24 * time.Hour}, func(s *depStorageTest, lock *lockMocks.Lock, c *Compactor, mf *metricstest.Factory) {
+		lock.On("Acquire", compactorLock, time.Hour).Return(true, nil)
+		var compactedDays []time.Time
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+		query := &mocks.Query{}
+		query.On("Consistency", cassandra.One).Return(query)
+		query.On("Iter").Return(iter)
+		s.session.On("Query", depsSelectStmtV3, matchEverything()).Return(query).Run(func(a mock.Arguments) {
+			compactedDays = append(compactedDays, a.Get(1).([]any)[1].(time.Time))
+		})
+		prunes := mockExec(s, depsPruneStmtV3, nil)
+
+		c.run()
+		// the days over for a day are compacted, on the 26th at 2:30, up to the 24th
+		require.Len(t, compactedDays, 7)
+		assert.Equal(t, compactedDay.Add(-6*rollupGranularity), compactedDays[0])
+		assert.Equal(t, compactedDay, compactedDays[6])
+		assert.Equal(t, [][]any{{[]time.Time{compactedDay.Add(25 * time.Hour)}}}, *prunes)
+		mf.AssertCounterMetrics(t,
+			metricstest.ExpectedMetric{Name: "dependencies_compaction.compacted_rows", Value: 0},
+			metricstest.ExpectedMetric{Name: "dependencies_compaction.pruned_buckets", Value: 1},
+		)
+	})
+}
+
+func TestCompactorRunWithoutLock(t *testing.T) {
+	withCompactor(t, CompactorOptions{Interval: time.Hour}, func(s *depStorageTest, lock *lockMocks.Lock, c *Compactor, _ *metricstest.Factory) {
+		lock.On("Acquire", compactorLock, time.Hour).Return(false, nil).Once()
+		c.run()
+		lock.On("Acquire", compactorLock, time.Hour).Return(false, errors.New("lock error")).Once()
+		c.run()
+		assert.Contains(t, s.logBuffer.String(), "Failed to acquire the dependencies compaction lock")
+		s.session.AssertNotCalled(t, "Query", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCompactorRunErrors(t *testing.T) {
+	withCompactor(t, CompactorOptions{Interval: time.Hour}, func(s *depStorageTest, lock *lockMocks.Lock, c *Compactor, mf *metricstest.Factory) {
+		lock.On("Acquire", compactorLock, time.Hour).Return(true, nil)
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(errors.New("query error"))
+		query := &mocks.Query{}
+		query.On("Consistency", cassandra.One).Return(query)
+		query.On("Iter").Return(iter)
+		s.session.On("Query", depsSelectStmtV3, matchEverything()).Return(query).Once()
+
+		c.run()
+		assert.Contains(t, s.logBuffer.String(), "Failed to compact dependencies")
+		mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dependencies_compaction.failures", Value: 1})
+	})
+	withCompactor(t, CompactorOptions{Interval: time.Hour, Retention: time.Hour}, func(s *depStorageTest, lock *lockMocks.Lock, c *Compactor, mf *metricstest.Factory) {
+		lock.On("Acquire", compactorLock, time.Hour).Return(true, nil)
+		mockDependencyRows(s, nil)
+		mockExec(s, depsPruneStmtV3, errors.New("prune error"))
+
+		c.run()
+		assert.Contains(t, s.logBuffer.String(), "Failed to prune dependencies")
+		mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dependencies_compaction.failures", Value: 1})
+	})
+}
+
+func TestCompactorStartClose(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		mf := metricstest.NewFactory(time.Hour)
+		defer mf.Stop()
+		c := NewCompactor(s.storage, lockMocks.NewLock(t), CompactorOptions{Interval: time.Hour}, mf, zap.NewNop())
+		c.Start()
+		require.NoError(t, c.Close())
+	})
+}
@@ -23,10 +23,11 @@ import (
 
 // Dependency is the UDT representation of a Jaeger Dependency.
 type Dependency struct {
-	Parent    string `cql:"parent"`
-	Child     string `cql:"child"`
-	CallCount int64  `cql:"call_count"` // always unsigned, but we cannot explicitly read uint64 from Cassandra
-	Source    string `cql:"source"`
+	Parent     string `cql:"parent"`
+	Child      string `cql:"child"`
+	CallCount  int64  `cql:"call_count"` // always unsigned, but we cannot explicitly read uint64 from Cassandra
+	Source     string `cql:"source"`
+	EdgeSource string `cql:"edge_source"` // only stored in the dependencies_v3 table
 }
 
 // MarshalUDT handles marshalling a Dependency.
@@ -40,6 +41,8 @@ func (d *Dependency) MarshalUDT(name string, info gocql.TypeInfo) ([]byte, error
 		return gocql.Marshal(info, d.CallCount)
 	case "source":
 		return gocql.Marshal(info, d.Source)
+	case "edge_source":
+		return gocql.Marshal(info, d.EdgeSource)
 	default:
 		return nil, fmt.Errorf("unknown column for position: %q", name)
 	}
@@ -56,6 +59,8 @@ func (d *Dependency) UnmarshalUDT(name string, info gocql.TypeInfo, data []byte)
 		return gocql.Unmarshal(info, data, &d.CallCount)
 	case "source":
 		return gocql.Unmarshal(info, data, &d.Source)
+	case "edge_source":
+		return gocql.Unmarshal(info, data, &d.EdgeSource)
 	default:
 		return fmt.Errorf("unknown column for position: %q", name)
 	}
@@ -25,10 +25,11 @@ import (
 
 func TestDependencyUDT(t *testing.T) {
 	dependency := &Dependency{
-		Parent:    "bi",
-		Child:     "ng",
-		CallCount: 123,
-		Source:    "jaeger",
+		Parent:     "bi",
+		Child:      "ng",
+		CallCount:  123,
+		Source:     "jaeger",
+		EdgeSource: "client",
 	}
 
 	testCase := testutils.UDTTestCase{
@@ -40,6 +41,7 @@ func TestDependencyUDT(t *testing.T) {
 			{Name: "child", Type: gocql.TypeAscii, ValIn: []byte("ng"), Err: false},
 			{Name: "call_count", Type: gocql.TypeBigInt, ValIn: []byte{0, 0, 0, 0, 0, 0, 0, 123}, Err: false},
 			{Name: "source", Type: gocql.TypeAscii, ValIn: []byte("jaeger"), Err: false},
+			{Name: "edge_source", Type: gocql.TypeAscii, ValIn: []byte("client"), Err: false},
 			{Name: "wrong-field", Err: true},
 		},
 	}
@@ -43,18 +43,69 @@ const (
 
 	// V2 is used when the dependency table is NOT SASI indexed.
 	V2
+
+	// V3 is used when the dependency table records the source of every edge and the
+	// granularity of the time window the dependencies were aggregated over.
+	V3
 	versionEnumEnd
 
 	depsInsertStmtV1 = "INSERT INTO dependencies(ts, ts_index, dependencies) VALUES (?, ?, ?)"
 	depsInsertStmtV2 = "INSERT INTO dependencies_v2(ts, ts_bucket, dependencies) VALUES (?, ?, ?)"
+	depsInsertStmtV3 = "INSERT INTO dependencies_v3(ts_bucket, ts, granularity, dependencies) VALUES (?, ?, ?, ?)"
 	depsSelectStmtV1 = "SELECT ts, dependencies FROM dependencies WHERE ts_index >= ? AND ts_index < ?"
 	depsSelectStmtV2 = "SELECT ts, dependencies FROM dependencies_v2 WHERE ts_bucket IN ? AND ts >= ? AND ts < ?"
+	depsSelectStmtV3 = "SELECT ts, granularity, dependencies FROM dependencies_v3 WHERE ts_bucket IN ? AND ts >= ? AND ts < ?"
+	depsDeleteStmtV3 = "DELETE FROM dependencies_v3 WHERE ts_bucket = ? AND ts = ?"
+	depsPruneStmtV3  = "DELETE FROM dependencies_v3 WHERE ts_bucket IN ?"
 
 	// TODO: Make this customizable.
 	tsBucket = 24 * time.Hour
+	// tsBucketV3 keeps the partitions of dependencies_v3 small, so that short lookbacks,
+	// which are the most common, only read the hours they cover.
+	tsBucketV3 = time.Hour
+	// rollupGranularity is the time window of the rows compacted by CompactDay.
+	rollupGranularity = 24 * time.Hour
+)
+
+// EdgeSource identifies the spans a dependency edge was derived from.
+type EdgeSource string
+
+const (
+	// EdgeSourceUnknown is used for edges written without attribution.
+	EdgeSourceUnknown EdgeSource = ""
+	// EdgeSourceClientSpan is used for edges derived from client spans calling the child.
+	EdgeSourceClientSpan EdgeSource = "client"
+	// EdgeSourceServerSpan is used for edges derived from server spans of the child, with a parent span in the parent.
+	EdgeSourceServerSpan EdgeSource = "server"
+	// EdgeSourceInferred is used for edges inferred without a span on either side, e.g. from peer.service tags.
+	EdgeSourceInferred EdgeSource = "inferred"
 )
 
-var errInvalidVersion = errors.New("invalid version")
+// edgeSourcePreference orders the edge sources from the most to the least accurate.
+// A call between two instrumented services is reported by both its client and its server span,
+// and counting it once per side would double it.
+var edgeSourcePreference = map[EdgeSource]int{
+	EdgeSourceClientSpan: 0,
+	EdgeSourceServerSpan: 1,
+	EdgeSourceInferred:   2,
+	EdgeSourceUnknown:    3,
+}
+
+// AttributedDependencyLink is a dependency link along with the source of its edge.
+type AttributedDependencyLink struct {
+	model.DependencyLink
+	EdgeSource EdgeSource
+	// Timestamp and Granularity are the start and the length of the time window the link
+	// was aggregated over. They are set when reading, and ignored when writing.
+	Timestamp   time.Time
+	Granularity time.Duration
+}
+
+var (
+	errInvalidVersion          = errors.New("invalid version")
+	errAttributionNotSupported = errors.New("dependency edge attribution requires the dependencies_v3 table")
+	errCompactionNotSupported  = errors.New("dependency compaction requires the dependencies_v3 table")
+)
 
 // DependencyStore handles all queries and insertions to Cassandra dependencies
 type DependencyStore struct {
@@ -86,62 +137,263 @@ func NewDependencyStore(
 func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
 	deps := make([]Dependency, len(dependencies))
 	for i, d := range dependencies {
-		deps[i] = Dependency{
-			Parent:    d.Parent,
-			Child:     d.Child,
-			CallCount: int64(d.CallCount),
-			Source:    string(d.Source),
-		}
+		deps[i] = toDependency(d, EdgeSourceUnknown)
 	}
+	return s.writeDependencies(ts, 0, deps)
+}
 
+// WriteAttributedDependencies writes the dependency links aggregated over the time window of the given
+// granularity starting at ts, along with the source of their edges. Before V3 the attribution and
+// the granularity cannot be stored, and the links are written as by WriteDependencies.
+func (s *DependencyStore) WriteAttributedDependencies(ts time.Time, granularity time.Duration, dependencies []AttributedDependencyLink) error {
+	deps := make([]Dependency, len(dependencies))
+	for i, d := range dependencies {
+		deps[i] = toDependency(d.DependencyLink, d.EdgeSource)
+	}
+	return s.writeDependencies(ts, granularity, deps)
+}
+
+func (s *DependencyStore) writeDependencies(ts time.Time, granularity time.Duration, deps []Dependency) error {
 	var query cassandra.Query
 	switch s.version {
 	case V1:
 		query = s.session.Query(depsInsertStmtV1, ts, ts, deps)
 	case V2:
 		query = s.session.Query(depsInsertStmtV2, ts, ts.Truncate(tsBucket), deps)
+	case V3:
+		query = s.session.Query(depsInsertStmtV3, ts.Truncate(tsBucketV3), ts, int(granularity.Seconds()), deps)
 	}
 	return s.dependenciesTableMetrics.Exec(query, s.logger)
 }
 
+func toDependency(d model.DependencyLink, edgeSource EdgeSource) Dependency {
+	return Dependency{
+		Parent:     d.Parent,
+		Child:      d.Child,
+		CallCount:  int64(d.CallCount),
+		Source:     string(d.Source),
+		EdgeSource: string(edgeSource),
+	}
+}
+
+func toDependencyLink(d Dependency) model.DependencyLink {
+	return model.DependencyLink{
+		Parent:    d.Parent,
+		Child:     d.Child,
+		CallCount: uint64(d.CallCount),
+		Source:    d.Source,
+	}.ApplyDefaults()
+}
+
 // GetDependencies returns all interservice dependencies
 func (s *DependencyStore) GetDependencies(_ context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	var mDependency []model.DependencyLink
+	err := s.readDependencies(endTs, lookback, func(_ time.Time, _ time.Duration, dependencies []Dependency) {
+		if s.version == V3 {
+			dependencies = mergeEdgeSources(dependencies)
+		}
+		for _, dependency := range dependencies {
+			mDependency = append(mDependency, toDependencyLink(dependency))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mDependency, nil
+}
+
+// GetAttributedDependencies returns the dependency links along with the source of their edges and
+// the time window they were aggregated over. Unlike GetDependencies, the links reported for the same
+// call by different sources are not merged. It requires the V3 dependencies table.
+func (s *DependencyStore) GetAttributedDependencies(_ context.Context, endTs time.Time, lookback time.Duration) ([]AttributedDependencyLink, error) {
+	if s.version != V3 {
+		return nil, errAttributionNotSupported
+	}
+	var links []AttributedDependencyLink
+	err := s.readDependencies(endTs, lookback, func(ts time.Time, granularity time.Duration, dependencies []Dependency) {
+		for _, dependency := range dependencies {
+			links = append(links, AttributedDependencyLink{
+				DependencyLink: toDependencyLink(dependency),
+				EdgeSource:     EdgeSource(dependency.EdgeSource),
+				Timestamp:      ts,
+				Granularity:    granularity,
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// CompactDay rolls the rows of the dependencies of the day starting at day, aggregated over windows
+// shorter than a day, up into a single row aggregated over the day, and deletes them, so that reading
+// long lookbacks scans one row per day. The call counts of the dependencies with the same parent, child,
+// source and edge source are added up. It returns the number of rows deleted.
+//
+// The day must be over, as the dependencies written to it afterwards are not added to its rollup:
+// if the day has a rollup already, e.g. because a previous compaction failed to delete the rows,
+// the rows are deleted without being added up again. It requires the V3 dependencies table.
+func (s *DependencyStore) CompactDay(day time.Time) (int, error) {
+	if s.version != V3 {
+		return 0, errCompactionNotSupported
+	}
+	day = day.Truncate(rollupGranularity)
+	var rows []time.Time
+	var dependencies []Dependency
+	hasRollup := false
+	err := s.readDependencies(day.Add(rollupGranularity), rollupGranularity, func(ts time.Time, granularity time.Duration, deps []Dependency) {
+		if granularity >= rollupGranularity {
+			hasRollup = true
+			return
+		}
+		rows = append(rows, ts)
+		dependencies = append(dependencies, deps...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if !hasRollup {
+		// a row starting at the beginning of the day is overwritten by the rollup
+		if err := s.writeDependencies(day, rollupGranularity, sumCallCounts(dependencies)); err != nil {
+			return 0, err
+		}
+	}
+	deleted := 0
+	for _, ts := range rows {
+		if ts.Equal(day) {
+			continue
+		}
+		query := s.session.Query(depsDeleteStmtV3, ts.Truncate(tsBucketV3), ts)
+		if err := s.dependenciesTableMetrics.Exec(query, s.logger); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// PruneBuckets deletes the partitions of the dependencies from the one of start to the last one
+// ending before end, e.g. once they are older than the retention, rather than leaving their rows
+// to expire one by one. It returns the number of partitions deleted.
+// It requires the V3 dependencies table.
+func (s *DependencyStore) PruneBuckets(start time.Time, end time.Time) (int, error) {
+	if s.version != V3 {
+		return 0, errCompactionNotSupported
+	}
+	buckets := getBuckets(start, end.Truncate(tsBucketV3), tsBucketV3)
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+	query := s.session.Query(depsPruneStmtV3, buckets)
+	if err := s.dependenciesTableMetrics.Exec(query, s.logger); err != nil {
+		return 0, err
+	}
+	return len(buckets), nil
+}
+
+// sumCallCounts adds up the call counts of the dependencies with the same parent, child, source and edge source.
+func sumCallCounts(dependencies []Dependency) []Dependency {
+	type edge struct {
+		parent     string
+		child      string
+		source     string
+		edgeSource string
+	}
+	summed := make([]Dependency, 0, len(dependencies))
+	index := make(map[edge]int, len(dependencies))
+	for _, d := range dependencies {
+		e := edge{parent: d.Parent, child: d.Child, source: d.Source, edgeSource: d.EdgeSource}
+		if i, ok := index[e]; ok {
+			summed[i].CallCount += d.CallCount
+			continue
+		}
+		index[e] = len(summed)
+		summed = append(summed, d)
+	}
+	return summed
+}
+
+// readDependencies calls fn with every row of dependencies within the lookback before endTs.
+func (s *DependencyStore) readDependencies(
+	endTs time.Time,
+	lookback time.Duration,
+	fn func(ts time.Time, granularity time.Duration, dependencies []Dependency),
+) error {
 	startTs := endTs.Add(-1 * lookback)
 	var query cassandra.Query
 	switch s.version {
 	case V1:
 		query = s.session.Query(depsSelectStmtV1, startTs, endTs)
 	case V2:
-		query = s.session.Query(depsSelectStmtV2, getBuckets(startTs, endTs), startTs, endTs)
+		query = s.session.Query(depsSelectStmtV2, getBuckets(startTs, endTs, tsBucket), startTs, endTs)
+	case V3:
+		query = s.session.Query(depsSelectStmtV3, getBuckets(startTs, endTs, tsBucketV3), startTs, endTs)
 	}
 	iter := query.Consistency(cassandra.One).Iter()
 
-	var mDependency []model.DependencyLink
 	var dependencies []Dependency
 	var ts time.Time
-	for iter.Scan(&ts, &dependencies) {
-		for _, dependency := range dependencies {
-			dl := model.DependencyLink{
-				Parent:    dependency.Parent,
-				Child:     dependency.Child,
-				CallCount: uint64(dependency.CallCount),
-				Source:    dependency.Source,
-			}.ApplyDefaults()
-			mDependency = append(mDependency, dl)
-		}
+	var granularitySeconds int
+	dest := []any{&ts, &dependencies}
+	if s.version == V3 {
+		dest = []any{&ts, &granularitySeconds, &dependencies}
+	}
+	for iter.Scan(dest...) {
+		fn(ts, time.Duration(granularitySeconds)*time.Second, dependencies)
 	}
 
 	if err := iter.Close(); err != nil {
 		s.logger.Error("Failure to read Dependencies", zap.Time("endTs", endTs), zap.Duration("lookback", lookback), zap.Error(err))
-		return nil, fmt.Errorf("error reading dependencies from storage: %w", err)
+		return fmt.Errorf("error reading dependencies from storage: %w", err)
 	}
-	return mDependency, nil
+	return nil
+}
+
+// mergeEdgeSources keeps a single dependency per parent, child and source among the dependencies
+// of a time window, taking the call count of the most accurate edge source. The call counts of
+// dependencies with the same edge source are added up.
+func mergeEdgeSources(dependencies []Dependency) []Dependency {
+	type edge struct {
+		parent string
+		child  string
+		source string
+	}
+	merged := make([]Dependency, 0, len(dependencies))
+	index := make(map[edge]int, len(dependencies))
+	for _, d := range dependencies {
+		e := edge{parent: d.Parent, child: d.Child, source: d.Source}
+		i, ok := index[e]
+		if !ok {
+			index[e] = len(merged)
+			merged = append(merged, d)
+			continue
+		}
+		preference, current := edgeSourcePreferenceOf(d.EdgeSource), edgeSourcePreferenceOf(merged[i].EdgeSource)
+		switch {
+		case preference < current:
+			merged[i] = d
+		case preference == current:
+			merged[i].CallCount += d.CallCount
+		}
+	}
+	return merged
+}
+
+func edgeSourcePreferenceOf(edgeSource string) int {
+	if preference, ok := edgeSourcePreference[EdgeSource(edgeSource)]; ok {
+		return preference
+	}
+	return edgeSourcePreference[EdgeSourceUnknown]
 }
 
-func getBuckets(startTs time.Time, endTs time.Time) []time.Time {
+func getBuckets(startTs time.Time, endTs time.Time, bucket time.Duration) []time.Time {
 	// TODO: Preallocate the array using some maths and maybe use a pool? This endpoint probably isn't used enough to warrant this.
 	var tsBuckets []time.Time
-	for ts := startTs.Truncate(tsBucket); ts.Before(endTs); ts = ts.Add(tsBucket) {
+	for ts := startTs.Truncate(bucket); ts.Before(endTs); ts = ts.Add(bucket) {
 		tsBuckets = append(tsBuckets, ts)
 	}
 	return tsBuckets
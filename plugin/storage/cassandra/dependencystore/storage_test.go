@@ -66,6 +66,7 @@ var (
 func TestVersionIsValid(t *testing.T) {
 	assert.True(t, V1.IsValid())
 	assert.True(t, V2.IsValid())
+	assert.True(t, V3.IsValid())
 	assert.False(t, versionEnumEnd.IsValid())
 }
 
@@ -185,6 +186,19 @@ func TestDependencyStoreGetDependencies(t *testing.T) {
 			},
 			version: V2,
 		},
+		{
+			caption: "success V3",
+			version: V3,
+		},
+		{
+			caption:       "failure V3",
+			queryError:    errors.New("query error"),
+			expectedError: "error reading dependencies from storage: query error",
+			expectedLogs: []string{
+				"Failure to read Dependencies",
+			},
+			version: V3,
+		},
 	}
 	for _, tc := range testCases {
 		testCase := tc // capture loop var
@@ -264,7 +278,169 @@ func TestGetBuckets(t *testing.T) {
 			time.Date(2017, time.January, 26, 0, 0, 0, 0, time.UTC),
 		}
 	)
-	assert.Equal(t, expected, getBuckets(start, end))
+	assert.Equal(t, expected, getBuckets(start, end, tsBucket))
+}
+
+func TestDependencyStoreWriteV3(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		query := &mocks.Query{}
+		query.On("Exec").Return(nil)
+		var args []any
+		s.session.On("Query", depsInsertStmtV3, mock.MatchedBy(func(v []any) bool {
+			args = v
+			return true
+		})).Return(query)
+
+		ts := time.Date(2017, time.January, 24, 11, 15, 17, 12345, time.UTC)
+		err := s.storage.WriteAttributedDependencies(ts, 10*time.Minute, []AttributedDependencyLink{
+			{
+				DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 42, Source: model.JaegerDependencyLinkSource},
+				EdgeSource:     EdgeSourceClientSpan,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []any{
+			time.Date(2017, time.January, 24, 11, 0, 0, 0, time.UTC),
+			ts,
+			600,
+			[]Dependency{{Parent: "a", Child: "b", CallCount: 42, Source: "jaeger", EdgeSource: "client"}},
+		}, args)
+
+		err = s.storage.WriteDependencies(ts, []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, 0, args[2])
+		assert.Equal(t, []Dependency{{Parent: "a", Child: "b", CallCount: 1}}, args[3])
+	})
+}
+
+func TestDependencyStoreWriteAttributedBeforeV3(t *testing.T) {
+	withDepStore(V2, func(s *depStorageTest) {
+		query := &mocks.Query{}
+		query.On("Exec").Return(nil)
+		var args []any
+		s.session.On("Query", depsInsertStmtV2, mock.MatchedBy(func(v []any) bool {
+			args = v
+			return true
+		})).Return(query)
+
+		ts := time.Date(2017, time.January, 24, 11, 15, 17, 12345, time.UTC)
+		err := s.storage.WriteAttributedDependencies(ts, time.Hour, []AttributedDependencyLink{
+			{DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 42}, EdgeSource: EdgeSourceServerSpan},
+		})
+		require.NoError(t, err)
+		require.Len(t, args, 3)
+		// the edge source is ignored by the dependency UDT of dependencies_v2
+		assert.Equal(t, []Dependency{{Parent: "a", Child: "b", CallCount: 42, EdgeSource: "server"}}, args[2])
+	})
+}
+
+// mockDependencyRowsV3 makes the session return the given rows of dependencies_v3,
+// each one aggregated over a 10 minutes window.
+func mockDependencyRowsV3(s *depStorageTest, rows map[time.Time][]Dependency, timestamps []time.Time) {
+	scanFunc := func(args []any) bool {
+		if len(timestamps) == 0 {
+			return false
+		}
+		*(args[0].(*time.Time)) = timestamps[0]
+		*(args[1].(*int)) = 600
+		*(args[2].(*[]Dependency)) = rows[timestamps[0]]
+		timestamps = timestamps[1:]
+		return true
+	}
+	iter := &mocks.Iterator{}
+	iter.On("Scan", mock.MatchedBy(scanFunc)).Return(true)
+	iter.On("Scan", matchEverything()).Return(false)
+	iter.On("Close").Return(nil)
+
+	query := &mocks.Query{}
+	query.On("Consistency", cassandra.One).Return(query)
+	query.On("Iter").Return(iter)
+	s.session.On("Query", depsSelectStmtV3, matchEverything()).Return(query)
+}
+
+func TestDependencyStoreGetDependenciesV3MergesEdgeSources(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		ts1 := time.Date(2017, time.January, 24, 11, 0, 0, 0, time.UTC)
+		ts2 := ts1.Add(10 * time.Minute)
+		mockDependencyRowsV3(s, map[time.Time][]Dependency{
+			ts1: {
+				{Parent: "a", Child: "b", CallCount: 10, EdgeSource: "server"},
+				{Parent: "a", Child: "b", CallCount: 12, EdgeSource: "client"},
+				{Parent: "a", Child: "b", CallCount: 5, EdgeSource: "inferred"},
+				{Parent: "b", Child: "c", CallCount: 3, EdgeSource: "inferred"},
+				{Parent: "b", Child: "c", CallCount: 4, EdgeSource: "unexpected"},
+				{Parent: "b", Child: "c", CallCount: 2, EdgeSource: "inferred"},
+			},
+			ts2: {
+				{Parent: "a", Child: "b", CallCount: 7, EdgeSource: "server"},
+			},
+		}, []time.Time{ts1, ts2})
+
+		deps, err := s.storage.GetDependencies(context.Background(), ts2.Add(10*time.Minute), time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{
+			{Parent: "a", Child: "b", CallCount: 12, Source: model.JaegerDependencyLinkSource},
+			{Parent: "b", Child: "c", CallCount: 5, Source: model.JaegerDependencyLinkSource},
+			{Parent: "a", Child: "b", CallCount: 7, Source: model.JaegerDependencyLinkSource},
+		}, deps)
+	})
+}
+
+func TestDependencyStoreGetAttributedDependencies(t *testing.T) {
+	withDepStore(V3, func(s *depStorageTest) {
+		ts := time.Date(2017, time.January, 24, 11, 0, 0, 0, time.UTC)
+		mockDependencyRowsV3(s, map[time.Time][]Dependency{
+			ts: {
+				{Parent: "a", Child: "b", CallCount: 10, EdgeSource: "server"},
+				{Parent: "a", Child: "b", CallCount: 12, EdgeSource: "client"},
+			},
+		}, []time.Time{ts})
+
+		links, err := s.storage.GetAttributedDependencies(context.Background(), ts.Add(time.Hour), time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []AttributedDependencyLink{
+			{
+				DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 10, Source: model.JaegerDependencyLinkSource},
+				EdgeSource:     EdgeSourceServerSpan,
+				Timestamp:      ts,
+				Granularity:    10 * time.Minute,
+			},
+			{
+				DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 12, Source: model.JaegerDependencyLinkSource},
+				EdgeSource:     EdgeSourceClientSpan,
+				Timestamp:      ts,
+				Granularity:    10 * time.Minute,
+			},
+		}, links)
+	})
+}
+
+func TestDependencyStoreGetAttributedDependenciesErrors(t *testing.T) {
+	withDepStore(V2, func(s *depStorageTest) {
+		_, err := s.storage.GetAttributedDependencies(context.Background(), time.Now(), time.Hour)
+		require.ErrorIs(t, err, errAttributionNotSupported)
+	})
+	withDepStore(V3, func(s *depStorageTest) {
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(errors.New("query error"))
+		query := &mocks.Query{}
+		query.On("Consistency", cassandra.One).Return(query)
+		query.On("Iter").Return(iter)
+		s.session.On("Query", depsSelectStmtV3, matchEverything()).Return(query)
+
+		_, err := s.storage.GetAttributedDependencies(context.Background(), time.Now(), time.Hour)
+		require.EqualError(t, err, "error reading dependencies from storage: query error")
+	})
+}
+
+func TestGetBucketsV3(t *testing.T) {
+	start := time.Date(2017, time.January, 24, 11, 15, 17, 12345, time.UTC)
+	expected := []time.Time{
+		time.Date(2017, time.January, 24, 11, 0, 0, 0, time.UTC),
+		time.Date(2017, time.January, 24, 12, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, expected, getBuckets(start, start.Add(time.Hour), tsBucketV3))
 }
 
 func matchEverything() any {
@@ -70,6 +70,8 @@ type Factory struct {
 	primarySession cassandra.Session
 	archiveConfig  config.SessionBuilder
 	archiveSession cassandra.Session
+
+	dependenciesCompactor *cDepStore.Compactor
 }
 
 // NewFactory creates a new Factory.
@@ -148,14 +150,14 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.archiveMetricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "cassandra-archive", Tags: nil})
 	f.logger = logger
 
-	primarySession, err := f.primaryConfig.NewSession(logger)
+	primarySession, err := f.primaryConfig.NewSession(logger, f.primaryMetricsFactory)
 	if err != nil {
 		return err
 	}
 	f.primarySession = primarySession
 
 	if f.archiveConfig != nil {
-		archiveSession, err := f.archiveConfig.NewSession(logger)
+		archiveSession, err := f.archiveConfig.NewSession(logger, f.archiveMetricsFactory)
 		if err != nil {
 			return err
 		}
@@ -163,17 +165,44 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	} else {
 		logger.Info("Cassandra archive storage configuration is empty, skipping")
 	}
+	return f.startDependenciesCompaction()
+}
+
+// startDependenciesCompaction starts the compaction of the dependencies if it is enabled.
+func (f *Factory) startDependenciesCompaction() error {
+	if f.Options.DependenciesCompaction.Interval <= 0 {
+		return nil
+	}
+	version := cDepStore.GetDependencyVersion(f.primarySession)
+	if version != cDepStore.V3 {
+		f.logger.Warn("Dependencies compaction requires the dependencies_v3 table, skipping")
+		return nil
+	}
+	store, err := cDepStore.NewDependencyStore(f.primarySession, f.primaryMetricsFactory, f.logger, version)
+	if err != nil {
+		return err
+	}
+	lock, err := f.CreateLock()
+	if err != nil {
+		return err
+	}
+	f.dependenciesCompactor = cDepStore.NewCompactor(store, lock, cDepStore.CompactorOptions{
+		Interval:  f.Options.DependenciesCompaction.Interval,
+		Retention: f.Options.DependenciesCompaction.Retention,
+	}, f.primaryMetricsFactory, f.logger)
+	f.dependenciesCompactor.Start()
 	return nil
 }
 
 // CreateSpanReader implements storage.Factory
 func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
-	return cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader")), nil
+	reader := cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader"))
+	return f.hedgeReads(reader, f.primaryMetricsFactory), nil
 }
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	options, err := writerOptions(f.Options)
+	options, err := writerOptions(f.Options, f.primaryMetricsFactory)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +220,20 @@ func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if f.archiveSession == nil {
 		return nil, storage.ErrArchiveStorageNotConfigured
 	}
-	return cSpanStore.NewSpanReader(f.archiveSession, f.archiveMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader")), nil
+	reader := cSpanStore.NewSpanReader(f.archiveSession, f.archiveMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader"))
+	return f.hedgeReads(reader, f.archiveMetricsFactory), nil
+}
+
+// hedgeReads wraps the span reader into a spanstore.HedgedReader if hedged reads are enabled.
+func (f *Factory) hedgeReads(reader spanstore.Reader, metricsFactory metrics.Factory) spanstore.Reader {
+	if f.Options.HedgedReads.MaxAttempts < 2 {
+		return reader
+	}
+	return spanstore.NewHedgedReader(reader, spanstore.HedgedReadOptions{
+		Delay:          f.Options.HedgedReads.Delay,
+		MaxAttempts:    f.Options.HedgedReads.MaxAttempts,
+		MetricsFactory: metricsFactory,
+	})
 }
 
 // CreateArchiveSpanWriter implements storage.ArchiveFactory
@@ -199,7 +241,7 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	if f.archiveSession == nil {
 		return nil, storage.ErrArchiveStorageNotConfigured
 	}
-	options, err := writerOptions(f.Options)
+	options, err := writerOptions(f.Options, f.archiveMetricsFactory)
 	if err != nil {
 		return nil, err
 	}
@@ -219,10 +261,17 @@ func (f *Factory) CreateLock() (distributedlock.Lock, error) {
 
 // CreateSamplingStore implements storage.SamplingStoreFactory
 func (f *Factory) CreateSamplingStore(int /* maxBuckets */) (samplingstore.Store, error) {
-	return cSamplingStore.New(f.primarySession, f.primaryMetricsFactory, f.logger), nil
+	return cSamplingStore.New(
+		f.primarySession,
+		f.primaryMetricsFactory,
+		f.logger,
+		cSamplingStore.TTL(f.Options.Sampling.TTL),
+		cSamplingStore.PartitionInterval(f.Options.Sampling.PartitionInterval),
+		cSamplingStore.MaxReadWindow(f.Options.Sampling.MaxReadWindow),
+	), nil
 }
 
-func writerOptions(opts *Options) ([]cSpanStore.Option, error) {
+func writerOptions(opts *Options, metricsFactory metrics.Factory) ([]cSpanStore.Option, error) {
 	var tagFilters []dbmodel.TagFilter
 
 	// drop all tag filters
@@ -242,19 +291,38 @@ func writerOptions(opts *Options) ([]cSpanStore.Option, error) {
 		tagFilters = append(tagFilters, dbmodel.NewWhitelistFilter(tagIndexWhitelist))
 	}
 
-	if len(tagFilters) == 0 {
-		return nil, nil
-	} else if len(tagFilters) == 1 {
-		return []cSpanStore.Option{cSpanStore.TagFilter(tagFilters[0])}, nil
+	// tag index rules filter
+	if opts.Index.TagRulesFile != "" {
+		rules, err := dbmodel.LoadTagIndexRules(opts.Index.TagRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rulesFilter, err := dbmodel.NewRulesTagFilter(rules, opts.Index.TagRulesDryRun, metricsFactory)
+		if err != nil {
+			return nil, err
+		}
+		tagFilters = append(tagFilters, rulesFilter)
 	}
 
-	return []cSpanStore.Option{cSpanStore.TagFilter(dbmodel.NewChainedTagFilter(tagFilters...))}, nil
+	var options []cSpanStore.Option
+	if len(tagFilters) == 1 {
+		options = append(options, cSpanStore.TagFilter(tagFilters[0]))
+	} else if len(tagFilters) > 1 {
+		options = append(options, cSpanStore.TagFilter(dbmodel.NewChainedTagFilter(tagFilters...)))
+	}
+	if opts.RetentionTTL {
+		options = append(options, cSpanStore.RetentionTTL())
+	}
+	return options, nil
 }
 
 var _ io.Closer = (*Factory)(nil)
 
 // Close closes the resources held by the factory
 func (f *Factory) Close() error {
+	if f.dependenciesCompactor != nil {
+		f.dependenciesCompactor.Close()
+	}
 	if f.primarySession != nil {
 		f.primarySession.Close()
 	}
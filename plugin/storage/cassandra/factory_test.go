@@ -18,6 +18,9 @@ package cassandra
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,6 +34,8 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	cSpanStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 type mockSessionBuilder struct {
@@ -45,7 +50,7 @@ func newMockSessionBuilder(session *mocks.Session, err error) *mockSessionBuilde
 	}
 }
 
-func (m *mockSessionBuilder) NewSession(*zap.Logger) (cassandra.Session, error) {
+func (m *mockSessionBuilder) NewSession(*zap.Logger, metrics.Factory) (cassandra.Session, error) {
 	return m.session, m.err
 }
 
@@ -64,10 +69,14 @@ func TestCassandraFactory(t *testing.T) {
 	var (
 		session = &mocks.Session{}
 		query   = &mocks.Query{}
+		iter    = &mocks.Iterator{}
 	)
 	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
 	session.On("Close").Return()
 	query.On("Exec").Return(nil)
+	query.On("Iter").Return(iter)
+	iter.On("Scan", mock.Anything).Return(false)
+	iter.On("Close").Return(nil)
 	f.primaryConfig = newMockSessionBuilder(session, nil)
 	f.archiveConfig = newMockSessionBuilder(nil, errors.New("made-up error"))
 	require.EqualError(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "made-up error")
@@ -109,6 +118,85 @@ func TestCassandraFactory(t *testing.T) {
 	require.NoError(t, f.Close())
 }
 
+func TestCassandraFactoryDependenciesCompaction(t *testing.T) {
+	for _, hasV3 := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dependencies_v3=%v", hasV3), func(t *testing.T) {
+			logger, logBuf := testutils.NewLogger()
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			command.ParseFlags([]string{"--cassandra.dependencies.compaction-interval=1h"})
+			f.InitFromViper(v, zap.NewNop())
+
+			var (
+				session = &mocks.Session{}
+				query   = &mocks.Query{}
+				iter    = &mocks.Iterator{}
+			)
+			session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+			session.On("Close").Return()
+			query.On("Exec").Return(nil)
+			query.On("Iter").Return(iter)
+			iter.On("Scan", mock.Anything).Return(hasV3)
+			iter.On("Close").Return(nil)
+			f.primaryConfig = newMockSessionBuilder(session, nil)
+			f.archiveConfig = nil
+			require.NoError(t, f.Initialize(metrics.NullFactory, logger))
+			if hasV3 {
+				assert.NotNil(t, f.dependenciesCompactor)
+			} else {
+				assert.Nil(t, f.dependenciesCompactor)
+				assert.Contains(t, logBuf.String(), "Dependencies compaction requires the dependencies_v3 table, skipping")
+			}
+			require.NoError(t, f.Close())
+		})
+	}
+}
+
+func TestCassandraFactoryHedgedReads(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flags    []string
+		expected spanstore.Reader
+	}{
+		{
+			name:     "disabled by default",
+			expected: &cSpanStore.SpanReader{},
+		},
+		{
+			name:     "enabled",
+			flags:    []string{"--cassandra.hedged-reads.max-attempts=2", "--cassandra.hedged-reads.delay=100ms"},
+			expected: &spanstore.HedgedReader{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			command.ParseFlags(append(tc.flags, "--cassandra-archive.enabled=true"))
+			f.InitFromViper(v, zap.NewNop())
+
+			session := &mocks.Session{}
+			query := &mocks.Query{}
+			session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+			session.On("Close").Return()
+			query.On("Exec").Return(nil)
+			f.primaryConfig = newMockSessionBuilder(session, nil)
+			f.archiveConfig = newMockSessionBuilder(session, nil)
+			require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+			reader, err := f.CreateSpanReader()
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, reader)
+
+			reader, err = f.CreateArchiveSpanReader()
+			require.NoError(t, err)
+			assert.IsType(t, tc.expected, reader)
+
+			require.NoError(t, f.Close())
+		})
+	}
+}
+
 func TestExclusiveWhitelistBlacklist(t *testing.T) {
 	logger, logBuf := testutils.NewLogger()
 	f := NewFactory()
@@ -155,7 +243,7 @@ func TestWriterOptions(t *testing.T) {
 	command.ParseFlags([]string{"--cassandra.index.tag-whitelist=a,b,c"})
 	opts.InitFromViper(v)
 
-	options, _ := writerOptions(opts)
+	options, _ := writerOptions(opts, metrics.NullFactory)
 	assert.Len(t, options, 1)
 
 	opts = NewOptions("cassandra")
@@ -163,7 +251,7 @@ func TestWriterOptions(t *testing.T) {
 	command.ParseFlags([]string{"--cassandra.index.tag-blacklist=a,b,c"})
 	opts.InitFromViper(v)
 
-	options, _ = writerOptions(opts)
+	options, _ = writerOptions(opts, metrics.NullFactory)
 	assert.Len(t, options, 1)
 
 	opts = NewOptions("cassandra")
@@ -171,7 +259,7 @@ func TestWriterOptions(t *testing.T) {
 	command.ParseFlags([]string{"--cassandra.index.tags=false"})
 	opts.InitFromViper(v)
 
-	options, _ = writerOptions(opts)
+	options, _ = writerOptions(opts, metrics.NullFactory)
 	assert.Len(t, options, 1)
 
 	opts = NewOptions("cassandra")
@@ -179,7 +267,7 @@ func TestWriterOptions(t *testing.T) {
 	command.ParseFlags([]string{"--cassandra.index.tags=false", "--cassandra.index.tag-blacklist=a,b,c"})
 	opts.InitFromViper(v)
 
-	options, _ = writerOptions(opts)
+	options, _ = writerOptions(opts, metrics.NullFactory)
 	assert.Len(t, options, 1)
 
 	opts = NewOptions("cassandra")
@@ -187,8 +275,40 @@ func TestWriterOptions(t *testing.T) {
 	command.ParseFlags([]string{""})
 	opts.InitFromViper(v)
 
-	options, _ = writerOptions(opts)
+	options, _ = writerOptions(opts, metrics.NullFactory)
 	assert.Empty(t, options)
+
+	opts = NewOptions("cassandra")
+	v, command = config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{"--cassandra.retention-ttl=true", "--cassandra.index.tags=false"})
+	opts.InitFromViper(v)
+
+	options, _ = writerOptions(opts, metrics.NullFactory)
+	assert.Len(t, options, 2)
+
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[{"key": "^sql$"}]`), 0o600))
+	opts = NewOptions("cassandra")
+	v, command = config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{"--cassandra.index.tag-rules-file=" + rulesFile, "--cassandra.index.tag-blacklist=a,b,c"})
+	opts.InitFromViper(v)
+
+	options, err := writerOptions(opts, metrics.NullFactory)
+	require.NoError(t, err)
+	assert.Len(t, options, 1)
+}
+
+func TestWriterOptionsTagRulesErrors(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[{"action": "index"}]`), 0o600))
+	opts := NewOptions("cassandra")
+	opts.Index.TagRulesFile = rulesFile
+	_, err := writerOptions(opts, metrics.NullFactory)
+	require.ErrorContains(t, err, `unknown action "index"`)
+
+	opts.Index.TagRulesFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = writerOptions(opts, metrics.NullFactory)
+	require.ErrorContains(t, err, "failed to read tag index rules")
 }
 
 func TestConfigureFromOptions(t *testing.T) {
@@ -23,29 +23,33 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 )
 
 const (
 	// session settings
-	suffixEnabled            = ".enabled"
-	suffixConnPerHost        = ".connections-per-host"
-	suffixMaxRetryAttempts   = ".max-retry-attempts"
-	suffixTimeout            = ".timeout"
-	suffixConnectTimeout     = ".connect-timeout"
-	suffixReconnectInterval  = ".reconnect-interval"
-	suffixServers            = ".servers"
-	suffixPort               = ".port"
-	suffixKeyspace           = ".keyspace"
-	suffixDC                 = ".local-dc"
-	suffixConsistency        = ".consistency"
-	suffixDisableCompression = ".disable-compression"
-	suffixProtoVer           = ".proto-version"
-	suffixSocketKeepAlive    = ".socket-keep-alive"
-	suffixUsername           = ".username"
-	suffixPassword           = ".password"
-	suffixAuth               = ".basic.allowed-authenticators"
+	suffixEnabled              = ".enabled"
+	suffixConnPerHost          = ".connections-per-host"
+	suffixMaxRetryAttempts     = ".max-retry-attempts"
+	suffixTimeout              = ".timeout"
+	suffixConnectTimeout       = ".connect-timeout"
+	suffixReconnectInterval    = ".reconnect-interval"
+	suffixServers              = ".servers"
+	suffixPort                 = ".port"
+	suffixKeyspace             = ".keyspace"
+	suffixDC                   = ".local-dc"
+	suffixConsistency          = ".consistency"
+	suffixReadConsistency      = ".read-consistency"
+	suffixWriteConsistency     = ".write-consistency"
+	suffixDowngradeConsistency = ".downgrade-consistency"
+	suffixDisableCompression   = ".disable-compression"
+	suffixProtoVer             = ".proto-version"
+	suffixSocketKeepAlive      = ".socket-keep-alive"
+	suffixUsername             = ".username"
+	suffixPassword             = ".password"
+	suffixAuth                 = ".basic.allowed-authenticators"
 	// common storage settings
 	suffixSpanStoreWriteCacheTTL = ".span-store-write-cache-ttl"
 	suffixIndexTagsBlacklist     = ".index.tag-blacklist"
@@ -53,6 +57,19 @@ const (
 	suffixIndexLogs              = ".index.logs"
 	suffixIndexTags              = ".index.tags"
 	suffixIndexProcessTags       = ".index.process-tags"
+	suffixIndexTagRulesFile      = ".index.tag-rules-file"
+	suffixIndexTagRulesDryRun    = ".index.tag-rules-dry-run"
+	suffixRetentionTTL           = ".retention-ttl"
+	// adaptive sampling settings
+	suffixSamplingTTL               = ".sampling.ttl"
+	suffixSamplingPartitionInterval = ".sampling.partition-interval"
+	suffixSamplingMaxReadWindow     = ".sampling.max-read-window"
+	// hedged reads settings
+	suffixHedgedReadsDelay       = ".hedged-reads.delay"
+	suffixHedgedReadsMaxAttempts = ".hedged-reads.max-attempts"
+	// dependencies compaction settings
+	suffixDependenciesCompactionInterval = ".dependencies.compaction-interval"
+	suffixDependenciesRetention          = ".dependencies.retention"
 )
 
 // Options contains various type of Cassandra configs and provides the ability
@@ -61,8 +78,13 @@ const (
 type Options struct {
 	Primary                NamespaceConfig `mapstructure:",squash"`
 	others                 map[string]*NamespaceConfig
-	SpanStoreWriteCacheTTL time.Duration `mapstructure:"span_store_write_cache_ttl"`
-	Index                  IndexConfig   `mapstructure:"index"`
+	SpanStoreWriteCacheTTL time.Duration     `mapstructure:"span_store_write_cache_ttl"`
+	Index                  IndexConfig       `mapstructure:"index"`
+	Sampling               SamplingConfig    `mapstructure:"sampling"`
+	HedgedReads            HedgedReadsConfig `mapstructure:"hedged_reads"`
+	RetentionTTL           bool              `mapstructure:"retention_ttl"`
+	// DependenciesCompaction configures the compaction of the dependencies_v3 table.
+	DependenciesCompaction DependenciesCompactionConfig `mapstructure:"dependencies_compaction"`
 }
 
 // IndexConfig configures indexing.
@@ -73,6 +95,42 @@ type IndexConfig struct {
 	ProcessTags  bool   `mapstructure:"process_tags"`
 	TagBlackList string `mapstructure:"tag_blacklist"`
 	TagWhiteList string `mapstructure:"tag_whitelist"`
+	// TagRulesFile is the path of a JSON file with the dbmodel.TagIndexRule deciding which tags are indexed.
+	TagRulesFile string `mapstructure:"tag_rules_file"`
+	// TagRulesDryRun only counts the tags the rules would skip, and still indexes them.
+	TagRulesDryRun bool `mapstructure:"tag_rules_dry_run"`
+}
+
+// SamplingConfig configures the retention and partitioning of the adaptive sampling tables.
+type SamplingConfig struct {
+	// TTL is the time-to-live of the throughput and probabilities rows. Zero uses the table default.
+	TTL time.Duration `mapstructure:"ttl"`
+	// PartitionInterval is the time range covered by each partition of the sampling tables.
+	// Zero keeps the constant partitions used by older versions, which grow without bound.
+	PartitionInterval time.Duration `mapstructure:"partition_interval"`
+	// MaxReadWindow bounds how far back a throughput read may go. Zero does not bound reads.
+	MaxReadWindow time.Duration `mapstructure:"max_read_window"`
+}
+
+// HedgedReadsConfig configures hedged trace reads, which send a duplicate query for a trace
+// when the previous one is not answered within Delay, so that the query is likely to be served
+// by another replica than a slow one. The first answer wins and the other queries are cancelled.
+type HedgedReadsConfig struct {
+	// Delay is how long a trace query may be outstanding before a duplicate is sent.
+	Delay time.Duration `mapstructure:"delay"`
+	// MaxAttempts is the maximum number of concurrent queries for a trace, including the first one.
+	// Hedged reads are disabled with less than two attempts.
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// DependenciesCompactionConfig configures the maintenance routine rolling the dependencies of every
+// past day up into a single row and pruning the expired partitions, see dependencystore.Compactor.
+type DependenciesCompactionConfig struct {
+	// Interval is the time between two compactions. Zero disables the compaction.
+	Interval time.Duration `mapstructure:"interval"`
+	// Retention is how long the dependencies are kept before their partitions are pruned.
+	// Zero leaves them to the TTL of the table.
+	Retention time.Duration `mapstructure:"retention"`
 }
 
 // the Servers field in config.Configuration is a list, which we cannot represent with flags.
@@ -95,6 +153,13 @@ func NewOptions(primaryNamespace string, otherNamespaces ...string) *Options {
 		},
 		others:                 make(map[string]*NamespaceConfig, len(otherNamespaces)),
 		SpanStoreWriteCacheTTL: time.Hour * 12,
+		Sampling: SamplingConfig{
+			TTL: time.Hour * 48,
+		},
+		HedgedReads: HedgedReadsConfig{
+			Delay:       time.Second,
+			MaxAttempts: 1,
+		},
 	}
 
 	for _, namespace := range otherNamespaces {
@@ -133,6 +198,52 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		opt.Primary.namespace+suffixIndexProcessTags,
 		!opt.Index.ProcessTags,
 		"Controls process tag indexing. Set to false to disable.")
+	flagSet.String(
+		opt.Primary.namespace+suffixIndexTagRulesFile,
+		opt.Index.TagRulesFile,
+		"(experimental) Path to a JSON file with the list of rules deciding which tags, process tags and log fields are indexed, applied after the blacklist or whitelist. "+
+			`Each rule, e.g. {"name": "headers", "service": "frontend", "key": "^http\\.request\\.header\\.", "maxValueLength": 256, "action": "drop"}, matches the tags of the service (any if empty) `+
+			"whose key matches the regular expression (any if empty) and whose value is longer than maxValueLength (any if 0). "+
+			`The action ("drop" by default or "keep") of the first matching rule applies, and tags matching no rule are indexed. The tags skipped by each rule are counted in the tag_index_rules_skipped metric.`)
+	flagSet.Bool(
+		opt.Primary.namespace+suffixIndexTagRulesDryRun,
+		opt.Index.TagRulesDryRun,
+		"(experimental) Index all the tags, and only count in the tag_index_rules_skipped metric the tags the tag rules would skip, to evaluate the rules before enforcing them.")
+	flagSet.Bool(
+		opt.Primary.namespace+suffixRetentionTTL,
+		opt.RetentionTTL,
+		"(experimental) Keep spans tagged with "+model.RetentionTagKey+" for the requested retention instead of the default TTL of the traces table. "+
+			"The retained traces can be fetched by ID, but are not searchable once the indexes expire.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixSamplingTTL,
+		opt.Sampling.TTL,
+		"The time-to-live of the adaptive sampling throughput and probabilities. Set to 0 to use the default TTL of the tables.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixSamplingPartitionInterval,
+		opt.Sampling.PartitionInterval,
+		"The time range covered by each partition of the adaptive sampling tables, at least 1m. "+
+			"Set to 0 to keep the constant partitions of older versions; all collectors must use the same value.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixSamplingMaxReadWindow,
+		opt.Sampling.MaxReadWindow,
+		"The maximum time range of adaptive sampling throughput read at once. Set to 0 for no limit.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixHedgedReadsDelay,
+		opt.HedgedReads.Delay,
+		"How long a trace query may be outstanding before a duplicate query is sent, likely to another replica.")
+	flagSet.Int(
+		opt.Primary.namespace+suffixHedgedReadsMaxAttempts,
+		opt.HedgedReads.MaxAttempts,
+		"The maximum number of concurrent queries for a trace, including the first one. Set to 2 or more to enable hedged reads.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixDependenciesCompactionInterval,
+		opt.DependenciesCompaction.Interval,
+		"(experimental) The interval between two compactions of the dependencies_v3 table, rolling the dependencies of every past day up into a single row, "+
+			"which reduces the rows read by long lookbacks. A single Jaeger instance runs the compaction at a time. Set to 0 to disable the compaction.")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixDependenciesRetention,
+		opt.DependenciesCompaction.Retention,
+		"(experimental) How long the dependencies are kept before their partitions are pruned by the compaction. Set to 0 to leave them to the TTL of the table.")
 }
 
 func addFlags(flagSet *flag.FlagSet, nsConfig NamespaceConfig) {
@@ -185,6 +296,19 @@ func addFlags(flagSet *flag.FlagSet, nsConfig NamespaceConfig) {
 		nsConfig.namespace+suffixConsistency,
 		nsConfig.Consistency,
 		"The Cassandra consistency level, e.g. ANY, ONE, TWO, THREE, QUORUM, ALL, LOCAL_QUORUM, EACH_QUORUM, LOCAL_ONE (default LOCAL_ONE)")
+	flagSet.String(
+		nsConfig.namespace+suffixReadConsistency,
+		nsConfig.ReadConsistency,
+		"The Cassandra consistency level of the reads, defaults to the consistency level")
+	flagSet.String(
+		nsConfig.namespace+suffixWriteConsistency,
+		nsConfig.WriteConsistency,
+		"The Cassandra consistency level of the writes, defaults to the consistency level")
+	flagSet.String(
+		nsConfig.namespace+suffixDowngradeConsistency,
+		strings.Join(nsConfig.DowngradeConsistency, ","),
+		"The comma-separated list of lower consistency levels to retry a query with, in order, when not enough replicas are available, e.g. ONE. "+
+			"The downgrades are counted by the consistency_downgrades metric and replace the retries of max-retry-attempts")
 	flagSet.Bool(
 		nsConfig.namespace+suffixDisableCompression,
 		false,
@@ -226,6 +350,16 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Index.Tags = v.GetBool(opt.Primary.namespace + suffixIndexTags)
 	opt.Index.Logs = v.GetBool(opt.Primary.namespace + suffixIndexLogs)
 	opt.Index.ProcessTags = v.GetBool(opt.Primary.namespace + suffixIndexProcessTags)
+	opt.Index.TagRulesFile = v.GetString(opt.Primary.namespace + suffixIndexTagRulesFile)
+	opt.Index.TagRulesDryRun = v.GetBool(opt.Primary.namespace + suffixIndexTagRulesDryRun)
+	opt.RetentionTTL = v.GetBool(opt.Primary.namespace + suffixRetentionTTL)
+	opt.Sampling.TTL = v.GetDuration(opt.Primary.namespace + suffixSamplingTTL)
+	opt.Sampling.PartitionInterval = v.GetDuration(opt.Primary.namespace + suffixSamplingPartitionInterval)
+	opt.Sampling.MaxReadWindow = v.GetDuration(opt.Primary.namespace + suffixSamplingMaxReadWindow)
+	opt.HedgedReads.Delay = v.GetDuration(opt.Primary.namespace + suffixHedgedReadsDelay)
+	opt.HedgedReads.MaxAttempts = v.GetInt(opt.Primary.namespace + suffixHedgedReadsMaxAttempts)
+	opt.DependenciesCompaction.Interval = v.GetDuration(opt.Primary.namespace + suffixDependenciesCompactionInterval)
+	opt.DependenciesCompaction.Retention = v.GetDuration(opt.Primary.namespace + suffixDependenciesRetention)
 }
 
 func tlsFlagsConfig(namespace string) tlscfg.ClientFlagsConfig {
@@ -250,6 +384,11 @@ func (cfg *NamespaceConfig) initFromViper(v *viper.Viper) {
 	cfg.Keyspace = v.GetString(cfg.namespace + suffixKeyspace)
 	cfg.LocalDC = v.GetString(cfg.namespace + suffixDC)
 	cfg.Consistency = v.GetString(cfg.namespace + suffixConsistency)
+	cfg.ReadConsistency = v.GetString(cfg.namespace + suffixReadConsistency)
+	cfg.WriteConsistency = v.GetString(cfg.namespace + suffixWriteConsistency)
+	if downgrades := stripWhiteSpace(v.GetString(cfg.namespace + suffixDowngradeConsistency)); downgrades != "" {
+		cfg.DowngradeConsistency = strings.Split(downgrades, ",")
+	}
 	cfg.ProtoVersion = v.GetInt(cfg.namespace + suffixProtoVer)
 	cfg.SocketKeepAlive = v.GetDuration(cfg.namespace + suffixSocketKeepAlive)
 	cfg.Authenticator.Basic.Username = v.GetString(cfg.namespace + suffixUsername)
@@ -58,15 +58,26 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--cas.timeout=42s",
 		"--cas.port=4242",
 		"--cas.consistency=ONE",
+		"--cas.read-consistency=LOCAL_QUORUM",
+		"--cas.downgrade-consistency=LOCAL_ONE, ONE",
 		"--cas.proto-version=3",
 		"--cas.socket-keep-alive=42s",
 		"--cas.index.tag-blacklist=blerg, blarg,blorg ",
 		"--cas.index.tag-whitelist=flerg, flarg,florg ",
 		"--cas.index.tags=true",
 		"--cas.index.process-tags=false",
+		"--cas.index.tag-rules-file=/etc/jaeger/tag-rules.json",
+		"--cas.index.tag-rules-dry-run=true",
 		"--cas.basic.allowed-authenticators=org.apache.cassandra.auth.PasswordAuthenticator,com.datastax.bdp.cassandra.auth.DseAuthenticator",
 		"--cas.username=username",
 		"--cas.password=password",
+		"--cas.sampling.ttl=24h",
+		"--cas.sampling.partition-interval=1h",
+		"--cas.sampling.max-read-window=30m",
+		"--cas.hedged-reads.delay=200ms",
+		"--cas.hedged-reads.max-attempts=2",
+		"--cas.dependencies.compaction-interval=1h",
+		"--cas.dependencies.retention=720h",
 		// enable aux with a couple overrides
 		"--cas-aux.enabled=true",
 		"--cas-aux.keyspace=jaeger-archive",
@@ -83,11 +94,23 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, []string{"1.1.1.1", "2.2.2.2"}, primary.Servers)
 	assert.Equal(t, []string{"org.apache.cassandra.auth.PasswordAuthenticator", "com.datastax.bdp.cassandra.auth.DseAuthenticator"}, primary.Authenticator.Basic.AllowedAuthenticators)
 	assert.Equal(t, "ONE", primary.Consistency)
+	assert.Equal(t, "LOCAL_QUORUM", primary.ReadConsistency)
+	assert.Equal(t, "", primary.WriteConsistency)
+	assert.Equal(t, []string{"LOCAL_ONE", "ONE"}, primary.DowngradeConsistency)
 	assert.Equal(t, []string{"blerg", "blarg", "blorg"}, opts.TagIndexBlacklist())
 	assert.Equal(t, []string{"flerg", "flarg", "florg"}, opts.TagIndexWhitelist())
 	assert.True(t, opts.Index.Tags)
 	assert.False(t, opts.Index.ProcessTags)
+	assert.Equal(t, "/etc/jaeger/tag-rules.json", opts.Index.TagRulesFile)
+	assert.True(t, opts.Index.TagRulesDryRun)
 	assert.True(t, opts.Index.Logs)
+	assert.Equal(t, 24*time.Hour, opts.Sampling.TTL)
+	assert.Equal(t, time.Hour, opts.Sampling.PartitionInterval)
+	assert.Equal(t, 30*time.Minute, opts.Sampling.MaxReadWindow)
+	assert.Equal(t, 200*time.Millisecond, opts.HedgedReads.Delay)
+	assert.Equal(t, 2, opts.HedgedReads.MaxAttempts)
+	assert.Equal(t, time.Hour, opts.DependenciesCompaction.Interval)
+	assert.Equal(t, 30*24*time.Hour, opts.DependenciesCompaction.Retention)
 
 	aux := opts.Get("cas-aux")
 	require.NotNil(t, aux)
@@ -100,6 +123,7 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, 42*time.Second, aux.ReconnectInterval)
 	assert.Equal(t, 4242, aux.Port)
 	assert.Equal(t, "", aux.Consistency, "aux storage does not inherit consistency from primary")
+	assert.Empty(t, aux.DowngradeConsistency)
 	assert.Equal(t, 3, aux.ProtoVersion)
 	assert.Equal(t, 42*time.Second, aux.SocketKeepAlive)
 }
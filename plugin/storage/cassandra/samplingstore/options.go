@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingstore
+
+import (
+	"time"
+)
+
+// Option is a function that sets some option on the SamplingStore.
+type Option func(o *Options)
+
+// Options control the retention and partitioning of the sampling tables.
+type Options struct {
+	ttl               time.Duration
+	partitionInterval time.Duration
+	maxReadWindow     time.Duration
+}
+
+// TTL sets the time-to-live of the throughput and probabilities rows written by the store.
+// When zero, the rows inherit the default_time_to_live of the tables.
+func TTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.ttl = ttl
+	}
+}
+
+// PartitionInterval spreads the rows across partitions covering the given time interval, so that
+// no partition grows unbounded. When zero, all rows share the ten constant partitions of older versions.
+// The interval must be at least a minute for the partition keys to fit the bucket column.
+func PartitionInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.partitionInterval = interval
+	}
+}
+
+// MaxReadWindow bounds how far back from its end a throughput read may go. When zero, reads are not bounded.
+func MaxReadWindow(window time.Duration) Option {
+	return func(o *Options) {
+		o.maxReadWindow = window
+	}
+}
+
+func applyOptions(opts ...Option) Options {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.partitionInterval > 0 && o.partitionInterval < time.Minute {
+		o.partitionInterval = time.Minute
+	}
+	return o
+}
@@ -36,13 +36,20 @@ import (
 
 const (
 	buckets        = `(0,1,2,3,4,5,6,7,8,9)`
+	numBuckets     = 10
 	constBucket    = 1
 	constBucketStr = `1`
+	// maxReadPartitions bounds the number of time partitions a single throughput read touches
+	maxReadPartitions = 24
 
-	insertThroughput       = `INSERT INTO operation_throughput(bucket, ts, throughput) VALUES (?, ?, ?)`
-	getThroughput          = `SELECT throughput FROM operation_throughput WHERE bucket IN ` + buckets + ` AND ts > ? AND ts <= ?`
-	insertProbabilities    = `INSERT INTO sampling_probabilities(bucket, ts, hostname, probabilities) VALUES (?, ?, ?, ?)`
-	getLatestProbabilities = `SELECT probabilities FROM sampling_probabilities WHERE bucket = ` + constBucketStr + ` LIMIT 1`
+	insertThroughput               = `INSERT INTO operation_throughput(bucket, ts, throughput) VALUES (?, ?, ?)`
+	insertThroughputWithTTL        = insertThroughput + ` USING TTL ?`
+	getThroughput                  = `SELECT throughput FROM operation_throughput WHERE bucket IN ` + buckets + ` AND ts > ? AND ts <= ?`
+	getThroughputFromBuckets       = `SELECT throughput FROM operation_throughput WHERE bucket IN ? AND ts > ? AND ts <= ?`
+	insertProbabilities            = `INSERT INTO sampling_probabilities(bucket, ts, hostname, probabilities) VALUES (?, ?, ?, ?)`
+	insertProbabilitiesWithTTL     = insertProbabilities + ` USING TTL ?`
+	getLatestProbabilities         = `SELECT probabilities FROM sampling_probabilities WHERE bucket = ` + constBucketStr + ` LIMIT 1`
+	getLatestProbabilitiesInBucket = `SELECT probabilities FROM sampling_probabilities WHERE bucket = ? LIMIT 1`
 )
 
 type samplingStoreMetrics struct {
@@ -55,30 +62,53 @@ type SamplingStore struct {
 	session cassandra.Session
 	metrics samplingStoreMetrics
 	logger  *zap.Logger
+	options Options
 }
 
 // New creates a new cassandra sampling store.
-func New(session cassandra.Session, factory metrics.Factory, logger *zap.Logger) *SamplingStore {
+func New(session cassandra.Session, factory metrics.Factory, logger *zap.Logger, opts ...Option) *SamplingStore {
 	return &SamplingStore{
 		session: session,
 		metrics: samplingStoreMetrics{
 			operationThroughput: casMetrics.NewTable(factory, "operation_throughput"),
 			probabilities:       casMetrics.NewTable(factory, "probabilities"),
 		},
-		logger: logger,
+		logger:  logger,
+		options: applyOptions(opts...),
 	}
 }
 
 // InsertThroughput implements samplingstore.Writer#InsertThroughput.
 func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
 	throughputStr := throughputToString(throughput)
-	query := s.session.Query(insertThroughput, generateRandomBucket(), gocql.TimeUUID(), throughputStr)
+	bucket := generateRandomBucket()
+	if s.options.partitionInterval > 0 {
+		bucket += s.partition(time.Now()) * numBuckets
+	}
+	var query cassandra.Query
+	if s.options.ttl > 0 {
+		query = s.session.Query(insertThroughputWithTTL, bucket, gocql.TimeUUID(), throughputStr, ttlSeconds(s.options.ttl))
+	} else {
+		query = s.session.Query(insertThroughput, bucket, gocql.TimeUUID(), throughputStr)
+	}
 	return s.metrics.operationThroughput.Exec(query, s.logger)
 }
 
 // GetThroughput implements samplingstore.Reader#GetThroughput.
 func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
-	iter := s.session.Query(getThroughput, gocql.UUIDFromTime(start), gocql.UUIDFromTime(end)).Iter()
+	if s.options.maxReadWindow > 0 && end.Sub(start) > s.options.maxReadWindow {
+		start = end.Add(-s.options.maxReadWindow)
+	}
+	var query cassandra.Query
+	if s.options.partitionInterval > 0 {
+		if earliest := end.Add(-maxReadPartitions * s.options.partitionInterval); start.Before(earliest) {
+			start = earliest
+		}
+		query = s.session.Query(getThroughputFromBuckets, s.throughputBuckets(start, end), gocql.UUIDFromTime(start), gocql.UUIDFromTime(end))
+	} else {
+		query = s.session.Query(getThroughput, gocql.UUIDFromTime(start), gocql.UUIDFromTime(end))
+	}
+	iter := query.Iter()
 	var throughput []*model.Throughput
 	var throughputStr string
 	for iter.Scan(&throughputStr) {
@@ -98,25 +128,79 @@ func (s *SamplingStore) InsertProbabilitiesAndQPS(
 	qps model.ServiceOperationQPS,
 ) error {
 	probabilitiesAndQPSStr := probabilitiesAndQPSToString(probabilities, qps)
-	query := s.session.Query(insertProbabilities, constBucket, gocql.TimeUUID(), hostname, probabilitiesAndQPSStr)
+	bucket := constBucket
+	if s.options.partitionInterval > 0 {
+		bucket = int(s.partition(time.Now()))*numBuckets + constBucket
+	}
+	var query cassandra.Query
+	if s.options.ttl > 0 {
+		query = s.session.Query(insertProbabilitiesWithTTL, bucket, gocql.TimeUUID(), hostname, probabilitiesAndQPSStr, ttlSeconds(s.options.ttl))
+	} else {
+		query = s.session.Query(insertProbabilities, bucket, gocql.TimeUUID(), hostname, probabilitiesAndQPSStr)
+	}
 	return s.metrics.probabilities.Exec(query, s.logger)
 }
 
 // GetLatestProbabilities implements samplingstore.Reader#GetLatestProbabilities.
 func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
-	iter := s.session.Query(getLatestProbabilities).Iter()
+	if s.options.partitionInterval == 0 {
+		probabilitiesStr, err := s.getLatestProbabilities(s.session.Query(getLatestProbabilities))
+		if err != nil {
+			return nil, err
+		}
+		return s.stringToProbabilities(probabilitiesStr), nil
+	}
+	// the latest probabilities are in the current partition, unless none were calculated since it started
+	current := s.partition(time.Now())
+	var probabilitiesStr string
+	for _, partition := range []int64{current, current - 1} {
+		var err error
+		bucket := int(partition)*numBuckets + constBucket
+		probabilitiesStr, err = s.getLatestProbabilities(s.session.Query(getLatestProbabilitiesInBucket, bucket))
+		if err != nil {
+			return nil, err
+		}
+		if probabilitiesStr != "" {
+			break
+		}
+	}
+	return s.stringToProbabilities(probabilitiesStr), nil
+}
+
+func (*SamplingStore) getLatestProbabilities(query cassandra.Query) (string, error) {
+	iter := query.Iter()
 	var probabilitiesStr string
 	iter.Scan(&probabilitiesStr)
 	if err := iter.Close(); err != nil {
 		err = fmt.Errorf("error reading probabilities from storage: %w", err)
-		return nil, err
+		return "", err
 	}
-	return s.stringToProbabilities(probabilitiesStr), nil
+	return probabilitiesStr, nil
+}
+
+// partition returns the index of the time partition t falls into.
+func (s *SamplingStore) partition(t time.Time) int64 {
+	return t.UnixNano() / int64(s.options.partitionInterval)
+}
+
+// throughputBuckets returns the buckets of all the time partitions overlapping [start, end].
+func (s *SamplingStore) throughputBuckets(start, end time.Time) []int64 {
+	var bucketList []int64
+	for partition := s.partition(start); partition <= s.partition(end); partition++ {
+		for bucket := int64(0); bucket < numBuckets; bucket++ {
+			bucketList = append(bucketList, partition*numBuckets+bucket)
+		}
+	}
+	return bucketList
+}
+
+func ttlSeconds(ttl time.Duration) int {
+	return int(ttl.Seconds())
 }
 
 // This is random enough for storage purposes
 func generateRandomBucket() int64 {
-	return time.Now().UnixNano() % 10
+	return time.Now().UnixNano() % numBuckets
 }
 
 func probabilitiesAndQPSToString(probabilities model.ServiceOperationProbabilities, qps model.ServiceOperationQPS) string {
@@ -42,7 +42,7 @@ type samplingStoreTest struct {
 	store     *SamplingStore
 }
 
-func withSamplingStore(fn func(r *samplingStoreTest)) {
+func withSamplingStore(fn func(r *samplingStoreTest), opts ...Option) {
 	session := &mocks.Session{}
 	logger, logBuffer := testutils.NewLogger()
 	metricsFactory := metricstest.NewFactory(0)
@@ -50,7 +50,7 @@ func withSamplingStore(fn func(r *samplingStoreTest)) {
 		session:   session,
 		logger:    logger,
 		logBuffer: logBuffer,
-		store:     New(session, metricsFactory, logger),
+		store:     New(session, metricsFactory, logger, opts...),
 	}
 	fn(r)
 }
@@ -288,6 +288,148 @@ func TestGetLatestProbabilities(t *testing.T) {
 	}
 }
 
+func TestInsertWithTTLAndPartitions(t *testing.T) {
+	withSamplingStore(func(s *samplingStoreTest) {
+		query := &mocks.Query{}
+		query.On("Exec").Return(nil)
+
+		var queries []string
+		var args [][]any
+		captureQuery := mock.MatchedBy(func(q string) bool {
+			queries = append(queries, q)
+			return true
+		})
+		captureArgs := mock.MatchedBy(func(v []any) bool {
+			args = append(args, v)
+			return true
+		})
+		s.session.On("Query", captureQuery, captureArgs).Return(query)
+
+		partition := time.Now().UnixNano() / int64(time.Hour)
+		require.NoError(t, s.store.InsertThroughput([]*model.Throughput{{Service: "svc", Operation: "op", Count: 1}}))
+		require.NoError(t, s.store.InsertProbabilitiesAndQPS("hostname", model.ServiceOperationProbabilities{}, model.ServiceOperationQPS{}))
+
+		require.Len(t, args, 2)
+		assert.Equal(t, insertThroughputWithTTL, queries[0])
+		require.Len(t, args[0], 4)
+		assert.GreaterOrEqual(t, args[0][0].(int64), partition*numBuckets)
+		assert.Less(t, args[0][0].(int64), (partition+2)*numBuckets)
+		assert.Equal(t, 172800, args[0][3])
+
+		assert.Equal(t, insertProbabilitiesWithTTL, queries[1])
+		require.Len(t, args[1], 5)
+		assert.Contains(t, []int{int(partition)*numBuckets + constBucket, int(partition+1)*numBuckets + constBucket}, args[1][0])
+		assert.Equal(t, 172800, args[1][4])
+	}, TTL(48*time.Hour), PartitionInterval(time.Hour))
+}
+
+func TestGetThroughputBounded(t *testing.T) {
+	end := testTime
+	testCases := []struct {
+		caption         string
+		options         []Option
+		start           time.Time
+		expectedQuery   string
+		expectedStart   time.Time
+		expectedBuckets int
+	}{
+		{
+			caption:       "unbounded",
+			start:         end.Add(-time.Hour),
+			expectedQuery: getThroughput,
+			expectedStart: end.Add(-time.Hour),
+		},
+		{
+			caption:       "max read window",
+			options:       []Option{MaxReadWindow(10 * time.Minute)},
+			start:         end.Add(-time.Hour),
+			expectedQuery: getThroughput,
+			expectedStart: end.Add(-10 * time.Minute),
+		},
+		{
+			caption:         "partitions within the window",
+			options:         []Option{PartitionInterval(time.Hour)},
+			start:           end.Add(-time.Hour),
+			expectedQuery:   getThroughputFromBuckets,
+			expectedStart:   end.Add(-time.Hour),
+			expectedBuckets: 2 * numBuckets,
+		},
+		{
+			caption:         "partitions capped",
+			options:         []Option{PartitionInterval(time.Hour)},
+			start:           end.Add(-1000 * time.Hour),
+			expectedQuery:   getThroughputFromBuckets,
+			expectedStart:   end.Add(-maxReadPartitions * time.Hour),
+			expectedBuckets: (maxReadPartitions + 1) * numBuckets,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSamplingStore(func(s *samplingStoreTest) {
+				iter := &mocks.Iterator{}
+				iter.On("Scan", matchEverything()).Return(false)
+				iter.On("Close").Return(nil)
+
+				query := &mocks.Query{}
+				query.On("Iter").Return(iter)
+
+				var args []any
+				captureArgs := mock.MatchedBy(func(v []any) bool {
+					args = v
+					return true
+				})
+				s.session.On("Query", testCase.expectedQuery, captureArgs).Return(query)
+
+				_, err := s.store.GetThroughput(testCase.start, end)
+				require.NoError(t, err)
+
+				if testCase.expectedBuckets > 0 {
+					require.Len(t, args, 3)
+					assert.Len(t, args[0], testCase.expectedBuckets)
+					args = args[1:]
+				}
+				require.Len(t, args, 2)
+				assert.Equal(t, gocql.UUIDFromTime(testCase.expectedStart).Time(), args[0].(gocql.UUID).Time())
+				assert.Equal(t, gocql.UUIDFromTime(end).Time(), args[1].(gocql.UUID).Time())
+			}, testCase.options...)
+		})
+	}
+}
+
+func TestGetLatestProbabilitiesFromPreviousPartition(t *testing.T) {
+	withSamplingStore(func(s *samplingStoreTest) {
+		emptyIter := &mocks.Iterator{}
+		emptyIter.On("Scan", matchEverything()).Return(false)
+		emptyIter.On("Close").Return(nil)
+		emptyQuery := &mocks.Query{}
+		emptyQuery.On("Iter").Return(emptyIter)
+
+		iter := &mocks.Iterator{}
+		iter.On("Scan", mock.MatchedBy(func(args []any) bool {
+			*args[0].(*string) = "svc,op,0.84,40\n"
+			return true
+		})).Return(true)
+		iter.On("Close").Return(nil)
+		query := &mocks.Query{}
+		query.On("Iter").Return(iter)
+
+		partition := int(time.Now().UnixNano() / int64(time.Hour))
+		s.session.On("Query", getLatestProbabilitiesInBucket, []any{partition*numBuckets + constBucket}).Return(emptyQuery)
+		s.session.On("Query", getLatestProbabilitiesInBucket, []any{(partition-1)*numBuckets + constBucket}).Return(query)
+
+		probabilities, err := s.store.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.InDelta(t, 0.84, probabilities["svc"]["op"], 0.01)
+	}, PartitionInterval(time.Hour))
+}
+
+func TestPartitionIntervalAtLeastAMinute(t *testing.T) {
+	assert.Equal(t, time.Minute, applyOptions(PartitionInterval(time.Second)).partitionInterval)
+	assert.Equal(t, time.Hour, applyOptions(PartitionInterval(time.Hour)).partitionInterval)
+	assert.Zero(t, applyOptions().partitionInterval)
+}
+
 func matchEverything() any {
 	return mock.MatchedBy(func(_ []any) bool { return true })
 }
@@ -41,7 +41,7 @@ func main() {
 		ProtoVersion:       4,
 		Keyspace:           "jaeger_v1_test",
 	}
-	cqlSession, err := cConfig.NewSession(logger)
+	cqlSession, err := cConfig.NewSession(logger, noScope)
 	if err != nil {
 		logger.Fatal("Cannot create Cassandra session", zap.Error(err))
 	}
@@ -58,7 +58,7 @@ func main() {
 		logger.Info("Saved span", zap.String("spanID", getSomeSpan().SpanID.String()))
 	}
 	s := getSomeSpan()
-	trace, err := spanReader.GetTrace(ctx, s.TraceID)
+	trace, err := spanReader.GetTrace(ctx, spanstore.GetTraceParameters{TraceID: s.TraceID})
 	if err != nil {
 		logger.Fatal("Failed to read", zap.Error(err))
 	} else {
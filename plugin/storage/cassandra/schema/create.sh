@@ -10,6 +10,7 @@ function usage {
     >&2 echo "  DATACENTER         - datacenter name for network topology used in prod (optional in MODE=test)"
     >&2 echo "  TRACE_TTL          - time to live for trace data, in seconds (default: 172800, 2 days)"
     >&2 echo "  DEPENDENCIES_TTL   - time to live for dependencies data, in seconds (default: 0, no TTL)"
+    >&2 echo "  SAMPLING_TTL       - time to live for adaptive sampling data, in seconds (default: 172800, 2 days)"
     >&2 echo "  KEYSPACE           - keyspace (default: jaeger_v1_{datacenter})"
     >&2 echo "  REPLICATION_FACTOR - replication factor for prod (default: 2 for prod, 1 for test)"
     >&2 echo "  VERSION            - Cassandra backend version, 3 or 4 (default: 4). Ignored if template is provided."
@@ -21,6 +22,7 @@ function usage {
 
 trace_ttl=${TRACE_TTL:-172800}
 dependencies_ttl=${DEPENDENCIES_TTL:-0}
+sampling_ttl=${SAMPLING_TTL:-172800}
 cas_version=${VERSION:-4}
 
 template=$1
@@ -87,6 +89,7 @@ Using template file $template with parameters:
     replication = ${replication}
     trace_ttl = ${trace_ttl}
     dependencies_ttl = ${dependencies_ttl}
+    sampling_ttl = ${sampling_ttl}
     compaction_window_size = ${compaction_window_size}
     compaction_window_unit = ${compaction_window_unit}
 EOF
@@ -99,5 +102,6 @@ cat $template | sed \
     -e "s/\${replication}/${replication}/g"                       \
     -e "s/\${trace_ttl}/${trace_ttl}/g"                           \
     -e "s/\${dependencies_ttl}/${dependencies_ttl}/g"             \
+    -e "s/\${sampling_ttl}/${sampling_ttl}/g"                     \
     -e "s/\${compaction_window_size}/${compaction_window_size}/g" \
     -e "s/\${compaction_window_unit}/${compaction_window_unit}/g" | cat -s
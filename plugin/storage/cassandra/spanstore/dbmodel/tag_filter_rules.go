@@ -0,0 +1,154 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dbmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// Actions of the tag index rules.
+const (
+	TagIndexRuleDrop = "drop"
+	TagIndexRuleKeep = "keep"
+)
+
+// TagIndexRule decides whether the tags, process tags and log fields it matches are indexed.
+// A rule matches a tag when all its conditions hold; a rule without conditions matches every tag.
+type TagIndexRule struct {
+	// Name identifies the rule in the metrics, rule-<index> by default.
+	Name string `json:"name"`
+	// Service is the name of the service of the spans, any service if empty.
+	Service string `json:"service"`
+	// Key is a regular expression the key of the tag must match, any key if empty.
+	Key string `json:"key"`
+	// MaxValueLength restricts the rule to the tags whose value as a string is longer, if positive.
+	MaxValueLength int `json:"maxValueLength"`
+	// Action is TagIndexRuleDrop, the default, or TagIndexRuleKeep.
+	Action string `json:"action"`
+}
+
+// LoadTagIndexRules reads a JSON array of TagIndexRule from a file.
+func LoadTagIndexRules(path string) ([]TagIndexRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tag index rules: %w", err)
+	}
+	var rules []TagIndexRule
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse tag index rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+type tagIndexRule struct {
+	service        string
+	key            *regexp.Regexp
+	maxValueLength int
+	keep           bool
+	skipped        metrics.Counter
+}
+
+func (r *tagIndexRule) matches(span *model.Span, tag *model.KeyValue) bool {
+	if r.service != "" && r.service != span.Process.GetServiceName() {
+		return false
+	}
+	if r.key != nil && !r.key.MatchString(tag.Key) {
+		return false
+	}
+	return r.maxValueLength <= 0 || len(tag.AsString()) > r.maxValueLength
+}
+
+// RulesTagFilter filters the tags with a list of TagIndexRule. The first rule matching a tag
+// decides whether it is indexed, and tags that no rule matches are indexed. In dry-run mode,
+// every tag is indexed, and only the metrics count the tags the rules would have skipped.
+type RulesTagFilter struct {
+	rules  []tagIndexRule
+	dryRun bool
+}
+
+// NewRulesTagFilter creates a RulesTagFilter counting the tags skipped by each rule
+// in the tag_index_rules_skipped metric.
+func NewRulesTagFilter(rules []TagIndexRule, dryRun bool, metricsFactory metrics.Factory) (*RulesTagFilter, error) {
+	tf := &RulesTagFilter{dryRun: dryRun}
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = "rule-" + strconv.Itoa(i)
+		}
+		r := tagIndexRule{
+			service:        rule.Service,
+			maxValueLength: rule.MaxValueLength,
+		}
+		switch rule.Action {
+		case "", TagIndexRuleDrop:
+		case TagIndexRuleKeep:
+			r.keep = true
+		default:
+			return nil, fmt.Errorf("unknown action %q of tag index rule %s, use %q or %q", rule.Action, name, TagIndexRuleDrop, TagIndexRuleKeep)
+		}
+		if rule.Key != "" {
+			key, err := regexp.Compile(rule.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key of tag index rule %s: %w", name, err)
+			}
+			r.key = key
+		}
+		if !r.keep {
+			r.skipped = metricsFactory.Counter(metrics.Options{
+				Name: "tag_index_rules_skipped",
+				Tags: map[string]string{"rule": name, "dry_run": strconv.FormatBool(dryRun)},
+			})
+		}
+		tf.rules = append(tf.rules, r)
+	}
+	return tf, nil
+}
+
+// FilterProcessTags implements TagFilter
+func (tf *RulesTagFilter) FilterProcessTags(span *model.Span, processTags model.KeyValues) model.KeyValues {
+	return tf.filter(span, processTags)
+}
+
+// FilterTags implements TagFilter
+func (tf *RulesTagFilter) FilterTags(span *model.Span, tags model.KeyValues) model.KeyValues {
+	return tf.filter(span, tags)
+}
+
+// FilterLogFields implements TagFilter
+func (tf *RulesTagFilter) FilterLogFields(span *model.Span, logFields model.KeyValues) model.KeyValues {
+	return tf.filter(span, logFields)
+}
+
+func (tf *RulesTagFilter) filter(span *model.Span, tags model.KeyValues) model.KeyValues {
+	var filteredTags model.KeyValues
+	for i := range tags {
+		if tf.index(span, &tags[i]) || tf.dryRun {
+			filteredTags = append(filteredTags, tags[i])
+		}
+	}
+	return filteredTags
+}
+
+func (tf *RulesTagFilter) index(span *model.Span, tag *model.KeyValue) bool {
+	for i := range tf.rules {
+		rule := &tf.rules[i]
+		if rule.matches(span, tag) {
+			if !rule.keep {
+				rule.skipped.Inc(1)
+			}
+			return rule.keep
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dbmodel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+var testTagIndexRules = []TagIndexRule{
+	{Name: "headers", Key: `^http\.request\.header\.`},
+	{Service: "checkout", Key: "^(cart|user)$", Action: TagIndexRuleKeep},
+	{Name: "checkout", Service: "checkout"},
+	{MaxValueLength: 8},
+}
+
+func rulesTestTags() model.KeyValues {
+	return model.KeyValues{
+		model.String("http.request.header.accept", "json"),
+		model.String("cart", "c1"),
+		model.String("user", "a-long-user-name"),
+		model.String("sku", "s1"),
+		model.Int64("http.status_code", 200),
+		model.String("sql", "SELECT * FROM items"),
+	}
+}
+
+func TestRulesTagFilter(t *testing.T) {
+	testCases := []struct {
+		service  string
+		expected []string
+	}{
+		{service: "checkout", expected: []string{"cart", "user"}},
+		{service: "frontend", expected: []string{"cart", "sku", "http.status_code"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.service, func(t *testing.T) {
+			tf, err := NewRulesTagFilter(testTagIndexRules, false, metrics.NullFactory)
+			require.NoError(t, err)
+			span := &model.Span{Process: &model.Process{ServiceName: tc.service}}
+			for _, filter := range []func(*model.Span, model.KeyValues) model.KeyValues{tf.FilterTags, tf.FilterProcessTags, tf.FilterLogFields} {
+				var keys []string
+				for _, tag := range filter(span, rulesTestTags()) {
+					keys = append(keys, tag.Key)
+				}
+				assert.Equal(t, tc.expected, keys)
+			}
+		})
+	}
+}
+
+func TestRulesTagFilterDryRun(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	tf, err := NewRulesTagFilter(testTagIndexRules, true, metricsFactory)
+	require.NoError(t, err)
+
+	span := &model.Span{Process: &model.Process{ServiceName: "checkout"}}
+	assert.Equal(t, rulesTestTags(), tf.FilterTags(span, rulesTestTags()))
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"tag_index_rules_skipped|dry_run=true|rule=headers":  1,
+		"tag_index_rules_skipped|dry_run=true|rule=checkout": 3,
+	}, counters)
+}
+
+func TestRulesTagFilterErrors(t *testing.T) {
+	_, err := NewRulesTagFilter([]TagIndexRule{{Action: "index"}}, false, metrics.NullFactory)
+	require.EqualError(t, err, `unknown action "index" of tag index rule rule-0, use "drop" or "keep"`)
+	_, err = NewRulesTagFilter([]TagIndexRule{{Name: "bad", Key: "("}}, false, metrics.NullFactory)
+	require.ErrorContains(t, err, "invalid key of tag index rule bad")
+}
+
+func TestLoadTagIndexRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "headers", "key": "^http\\.request\\.header\\."},
+		{"service": "checkout", "key": "^(cart|user)$", "action": "keep"},
+		{"name": "checkout", "service": "checkout"},
+		{"maxValueLength": 8}
+	]`), 0o600))
+	rules, err := LoadTagIndexRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, testTagIndexRules, rules)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"keys": "a"}]`), 0o600))
+	_, err = LoadTagIndexRules(path)
+	require.ErrorContains(t, err, `unknown field "keys"`)
+
+	_, err = LoadTagIndexRules(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "failed to read tag index rules")
+}
@@ -17,6 +17,7 @@ package spanstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -41,6 +42,15 @@ const (
 		SELECT trace_id, span_id, parent_id, operation_name, flags, start_time, duration, tags, logs, refs, process
 		FROM traces
 		WHERE trace_id = ?`
+	queryTraceExists = `
+		SELECT span_id
+		FROM traces
+		WHERE trace_id = ?
+		LIMIT 1`
+	queryRawSpan = `
+		SELECT JSON *
+		FROM traces
+		WHERE trace_id = ? AND span_id = ?`
 	queryByTag = `
 		SELECT trace_id
 		FROM tag_index
@@ -164,9 +174,9 @@ func (s *SpanReader) readTrace(ctx context.Context, traceID dbmodel.TraceID) (*m
 	return trace, err
 }
 
-func (s *SpanReader) readTraceInSpan(_ context.Context, traceID dbmodel.TraceID) (*model.Trace, error) {
+func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceID) (*model.Trace, error) {
 	start := time.Now()
-	q := s.session.Query(querySpanByTraceID, traceID)
+	q := s.session.Query(querySpanByTraceID, traceID).WithContext(ctx)
 	i := q.Iter()
 	var traceIDFromSpan dbmodel.TraceID
 	var startTime, spanID, duration, parentID int64
@@ -211,16 +221,60 @@ func (s *SpanReader) readTraceInSpan(_ context.Context, traceID dbmodel.TraceID)
 	return retMe, nil
 }
 
-// GetTrace takes a traceID and returns a Trace associated with that traceID
-func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	return s.readTrace(ctx, dbmodel.TraceIDFromDomain(traceID))
+// TraceExists reads at most one span of the trace from its partition of the traces table.
+// The time window hints in the query are ignored, as in GetTrace.
+func (s *SpanReader) TraceExists(ctx context.Context, query spanstore.GetTraceParameters) (bool, error) {
+	traceID := dbmodel.TraceIDFromDomain(query.TraceID)
+	_, span := s.startSpanForQuery(ctx, "TraceExists", queryTraceExists)
+	defer span.End()
+	span.SetAttributes(attribute.Key("trace_id").String(traceID.String()))
+
+	i := s.session.Query(queryTraceExists, traceID).Iter()
+	var spanID int64
+	exists := i.Scan(&spanID)
+	if err := i.Close(); err != nil {
+		logErrorToSpan(span, err)
+		return false, fmt.Errorf("error checking trace existence in storage: %w", err)
+	}
+	return exists, nil
+}
+
+// GetRawSpans returns the rows stored for the given span in the traces table, encoded as JSON.
+// The time window hints in the query are ignored, as in GetTrace.
+func (s *SpanReader) GetRawSpans(ctx context.Context, query spanstore.GetTraceParameters, spanID model.SpanID) ([]spanstore.RawSpan, error) {
+	traceID := dbmodel.TraceIDFromDomain(query.TraceID)
+	_, span := s.startSpanForQuery(ctx, "GetRawSpans", queryRawSpan)
+	defer span.End()
+	span.SetAttributes(attribute.Key("trace_id").String(traceID.String()))
+
+	i := s.session.Query(queryRawSpan, traceID, int64(spanID)).Iter()
+	rawSpans := []spanstore.RawSpan{}
+	var row string
+	for i.Scan(&row) {
+		rawSpans = append(rawSpans, spanstore.RawSpan{
+			Location: "traces",
+			Document: json.RawMessage(row),
+		})
+	}
+	if err := i.Close(); err != nil {
+		logErrorToSpan(span, err)
+		return nil, fmt.Errorf("error reading raw spans from storage: %w", err)
+	}
+	return rawSpans, nil
+}
+
+// GetTrace takes a traceID and returns a Trace associated with that traceID.
+// The time window hints in the query are ignored: traces are partitioned by
+// trace ID, so the read is already a single-partition lookup.
+func (s *SpanReader) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	return s.readTrace(ctx, dbmodel.TraceIDFromDomain(query.TraceID))
 }
 
 func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	if p.ServiceName == "" && len(p.AllTags()) > 0 {
 		return ErrServiceNameNotSet
 	}
 	if p.StartTimeMin.IsZero() || p.StartTimeMax.IsZero() {
@@ -232,7 +286,7 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p.DurationMin != 0 && p.DurationMax != 0 && p.DurationMin > p.DurationMax {
 		return ErrDurationMinGreaterThanMax
 	}
-	if (p.DurationMin != 0 || p.DurationMax != 0) && len(p.Tags) > 0 {
+	if (p.DurationMin != 0 || p.DurationMax != 0) && len(p.AllTags()) > 0 {
 		return ErrDurationAndTagQueryNotSupported
 	}
 	return nil
@@ -246,7 +300,7 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	}
 	var retMe []*model.Trace
 	for _, traceID := range uniqueTraceIDs {
-		jTrace, err := s.GetTrace(ctx, traceID)
+		jTrace, err := s.GetTrace(ctx, spanstore.GetTraceParameters{TraceID: traceID})
 		if err != nil {
 			s.logger.Error("Failure to read trace", zap.String("trace_id", traceID.String()), zap.Error(err))
 			continue
@@ -290,7 +344,7 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		if err != nil {
 			return nil, err
 		}
-		if len(traceQuery.Tags) > 0 {
+		if len(traceQuery.AllTags()) > 0 {
 			tagTraceIds, err := s.queryByTagsAndLogs(ctx, traceQuery)
 			if err != nil {
 				return nil, err
@@ -302,7 +356,7 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		}
 		return traceIds, nil
 	}
-	if len(traceQuery.Tags) > 0 {
+	if len(traceQuery.AllTags()) > 0 {
 		return s.queryByTagsAndLogs(ctx, traceQuery)
 	}
 	return s.queryByService(ctx, traceQuery)
@@ -312,8 +366,9 @@ func (s *SpanReader) queryByTagsAndLogs(ctx context.Context, tq *spanstore.Trace
 	ctx, span := s.startSpanForQuery(ctx, "queryByTagsAndLogs", queryByTag)
 	defer span.End()
 
-	results := make([]dbmodel.UniqueTraceIDs, 0, len(tq.Tags))
-	for k, v := range tq.Tags {
+	tags := tq.AllTags()
+	results := make([]dbmodel.UniqueTraceIDs, 0, len(tags))
+	for k, v := range tags {
 		_, childSpan := s.tracer.Start(ctx, "queryByTag")
 		childSpan.SetAttributes(
 			attribute.Key("tag.key").String(k),
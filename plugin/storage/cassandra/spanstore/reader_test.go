@@ -148,11 +148,12 @@ func TestSpanReaderGetTrace(t *testing.T) {
 
 				query := &mocks.Query{}
 				query.On("Consistency", cassandra.One).Return(query)
+				query.On("WithContext", mock.Anything).Return(query)
 				query.On("Iter").Return(iter)
 
 				r.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
 
-				trace, err := r.reader.GetTrace(context.Background(), model.TraceID{})
+				trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 				if testCase.expectedErr == "" {
 					require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 					require.NoError(t, err)
@@ -167,6 +168,92 @@ func TestSpanReaderGetTrace(t *testing.T) {
 	}
 }
 
+func TestSpanReaderGetRawSpans(t *testing.T) {
+	testCases := []struct {
+		name        string
+		closeErr    error
+		expected    []spanstore.RawSpan
+		expectedErr string
+	}{
+		{
+			name:     "span found",
+			expected: []spanstore.RawSpan{{Location: "traces", Document: []byte(`{"span_id": 2}`)}},
+		},
+		{
+			name:        "close error",
+			closeErr:    errors.New("error on close()"),
+			expectedErr: "error reading raw spans from storage: error on close()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				iter := &mocks.Iterator{}
+				iter.On("Scan", matchOnceWithSideEffect(func(args []any) {
+					*(args[0].(*string)) = `{"span_id": 2}`
+				})).Return(true)
+				iter.On("Scan", matchEverything()).Return(false)
+				iter.On("Close").Return(tc.closeErr)
+
+				query := &mocks.Query{}
+				query.On("Iter").Return(iter)
+				r.session.On("Query", stringMatcher("SELECT JSON"), matchEverything()).Return(query)
+
+				rawSpans, err := r.reader.GetRawSpans(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}, model.NewSpanID(2))
+				if tc.expectedErr != "" {
+					require.EqualError(t, err, tc.expectedErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, rawSpans)
+			})
+		})
+	}
+}
+
+func TestSpanReaderTraceExists(t *testing.T) {
+	testCases := []struct {
+		name        string
+		found       bool
+		closeErr    error
+		expectedErr string
+	}{
+		{
+			name:  "trace found",
+			found: true,
+		},
+		{
+			name: "trace not found",
+		},
+		{
+			name:        "close error",
+			closeErr:    errors.New("error on close()"),
+			expectedErr: "error checking trace existence in storage: error on close()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				iter := &mocks.Iterator{}
+				iter.On("Scan", matchEverything()).Return(tc.found)
+				iter.On("Close").Return(tc.closeErr)
+
+				query := &mocks.Query{}
+				query.On("Iter").Return(iter)
+				r.session.On("Query", stringMatcher("LIMIT 1"), matchEverything()).Return(query)
+
+				exists, err := r.reader.TraceExists(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
+				if tc.expectedErr != "" {
+					require.EqualError(t, err, tc.expectedErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.found, exists)
+			})
+		})
+	}
+}
+
 func TestSpanReaderGetTrace_TraceNotFound(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		iter := &mocks.Iterator{}
@@ -175,11 +262,12 @@ func TestSpanReaderGetTrace_TraceNotFound(t *testing.T) {
 
 		query := &mocks.Query{}
 		query.On("Consistency", cassandra.One).Return(query)
+		query.On("WithContext", mock.Anything).Return(query)
 		query.On("Iter").Return(iter)
 
 		r.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.TraceID{})
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		assert.Nil(t, trace)
 		require.EqualError(t, err, "trace not found")
@@ -360,6 +448,7 @@ func TestSpanReaderFindTraces(t *testing.T) {
 
 					loadQuery := &mocks.Query{}
 					loadQuery.On("Consistency", cassandra.One).Return(loadQuery)
+					loadQuery.On("WithContext", mock.Anything).Return(loadQuery)
 					loadQuery.On("Iter").Return(loadQueryIter)
 					loadQuery.On("PageSize", matchEverything()).Return(loadQuery)
 					return loadQuery
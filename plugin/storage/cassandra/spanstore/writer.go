@@ -30,6 +30,7 @@ import (
 	casMetrics "github.com/jaegertracing/jaeger/pkg/cassandra/metrics"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
 
 const (
@@ -39,6 +40,14 @@ const (
 				    start_time, duration, tags, logs, refs, process)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
+	insertSpanWithTTL = insertSpan + `
+		USING TTL ?`
+
+	deleteTrace = `
+		DELETE
+		FROM traces
+		WHERE trace_id = ?`
+
 	serviceNameIndex = `
 		INSERT
 		INTO service_name_index(service_name, bucket, start_time, trace_id)
@@ -98,6 +107,7 @@ type SpanWriter struct {
 	tagFilter            dbmodel.TagFilter
 	storageMode          storageMode
 	indexFilter          dbmodel.IndexFilter
+	retentionTTL         bool
 }
 
 // NewSpanWriter returns a SpanWriter
@@ -128,6 +138,7 @@ func NewSpanWriter(
 		tagFilter:       opts.tagFilter,
 		storageMode:     opts.storageMode,
 		indexFilter:     opts.indexFilter,
+		retentionTTL:    opts.retentionTTL,
 	}
 }
 
@@ -138,24 +149,35 @@ func (s *SpanWriter) Close() error {
 }
 
 // WriteSpan saves the span into Cassandra
-func (s *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
+func (s *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	ds := dbmodel.FromDomain(span)
 	if s.storageMode&storeFlag == storeFlag {
-		if err := s.writeSpan(span, ds); err != nil {
+		if err := s.writeSpan(ctx, span, ds); err != nil {
 			return err
 		}
 	}
 	if s.storageMode&indexFlag == indexFlag {
-		if err := s.writeIndexes(span, ds); err != nil {
+		if err := s.writeIndexes(ctx, span, ds); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *SpanWriter) writeSpan(_ *model.Span, ds *dbmodel.Span) error {
-	mainQuery := s.session.Query(
-		insertSpan,
+// DeleteTraces deletes the spans of the traces. The entries of the traces in the search indexes
+// are left to expire with their TTL, the reader skipping the traces they point to that are gone.
+func (s *SpanWriter) DeleteTraces(_ context.Context, traceIDs []model.TraceID) error {
+	for _, traceID := range traceIDs {
+		query := s.session.Query(deleteTrace, dbmodel.TraceIDFromDomain(traceID))
+		if err := s.writerMetrics.traces.Exec(query, s.logger); err != nil {
+			return fmt.Errorf("failed to delete trace %s: %w", traceID, err)
+		}
+	}
+	return nil
+}
+
+func (s *SpanWriter) writeSpan(ctx context.Context, span *model.Span, ds *dbmodel.Span) error {
+	stmt, values := insertSpan, []any{
 		ds.TraceID,
 		ds.SpanID,
 		ds.SpanHash,
@@ -168,32 +190,41 @@ func (s *SpanWriter) writeSpan(_ *model.Span, ds *dbmodel.Span) error {
 		ds.Logs,
 		ds.Refs,
 		ds.Process,
-	)
-	if err := s.writerMetrics.traces.Exec(mainQuery, s.logger); err != nil {
+	}
+	// only the span is kept for the requested retention, the search indexes expire with their default TTL
+	if retention, ok := span.GetRetention(); ok && s.retentionTTL {
+		stmt, values = insertSpanWithTTL, append(values, int(retention.Seconds()))
+	}
+	mainQuery := s.session.Query(stmt, values...)
+	if err := timedWrite(ctx, storageMetrics.WriteOperationSpan, func() error {
+		return s.writerMetrics.traces.Exec(mainQuery, s.logger)
+	}); err != nil {
 		return s.logError(ds, err, "Failed to insert span", s.logger)
 	}
 	return nil
 }
 
-func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
+func (s *SpanWriter) writeIndexes(ctx context.Context, span *model.Span, ds *dbmodel.Span) error {
 	spanKind, _ := span.GetSpanKind()
-	if err := s.saveServiceNameAndOperationName(dbmodel.Operation{
-		ServiceName:   ds.ServiceName,
-		SpanKind:      spanKind.String(),
-		OperationName: ds.OperationName,
+	if err := timedWrite(ctx, storageMetrics.WriteOperationLookup, func() error {
+		return s.saveServiceNameAndOperationName(dbmodel.Operation{
+			ServiceName:   ds.ServiceName,
+			SpanKind:      spanKind.String(),
+			OperationName: ds.OperationName,
+		})
 	}); err != nil {
 		// should this be a soft failure?
 		return s.logError(ds, err, "Failed to insert service name and operation name", s.logger)
 	}
 
 	if s.indexFilter(ds, dbmodel.ServiceIndex) {
-		if err := s.indexByService(ds); err != nil {
+		if err := timedWrite(ctx, storageMetrics.WriteOperationIndex, func() error { return s.indexByService(ds) }); err != nil {
 			return s.logError(ds, err, "Failed to index service name", s.logger)
 		}
 	}
 
 	if s.indexFilter(ds, dbmodel.OperationIndex) {
-		if err := s.indexByOperation(ds); err != nil {
+		if err := timedWrite(ctx, storageMetrics.WriteOperationIndex, func() error { return s.indexByOperation(ds) }); err != nil {
 			return s.logError(ds, err, "Failed to index operation name", s.logger)
 		}
 	}
@@ -202,18 +233,26 @@ func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
 		return nil // skipping expensive indexing
 	}
 
-	if err := s.indexByTags(span, ds); err != nil {
+	if err := timedWrite(ctx, storageMetrics.WriteOperationIndex, func() error { return s.indexByTags(span, ds) }); err != nil {
 		return s.logError(ds, err, "Failed to index tags", s.logger)
 	}
 
 	if s.indexFilter(ds, dbmodel.DurationIndex) {
-		if err := s.indexByDuration(ds, span.StartTime); err != nil {
+		if err := timedWrite(ctx, storageMetrics.WriteOperationIndex, func() error { return s.indexByDuration(ds, span.StartTime) }); err != nil {
 			return s.logError(ds, err, "Failed to index duration", s.logger)
 		}
 	}
 	return nil
 }
 
+// timedWrite runs the write and reports its latency to the WriteMetricsDecorator wrapping the writer, if any.
+func timedWrite(ctx context.Context, operation storageMetrics.WriteOperation, write func() error) error {
+	start := time.Now()
+	err := write()
+	storageMetrics.EmitWriteOperation(ctx, operation, err, time.Since(start))
+	return err
+}
+
 func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span) error {
 	for _, v := range dbmodel.GetAllUniqueTags(span, s.tagFilter) {
 		// we should introduce retries or just ignore failures imo, retrying each individual tag insertion might be better
@@ -24,9 +24,10 @@ type Option func(c *Options)
 
 // Options control behavior of the writer.
 type Options struct {
-	tagFilter   dbmodel.TagFilter
-	storageMode storageMode
-	indexFilter dbmodel.IndexFilter
+	tagFilter    dbmodel.TagFilter
+	storageMode  storageMode
+	indexFilter  dbmodel.IndexFilter
+	retentionTTL bool
 }
 
 // TagFilter can be provided to filter any tags that should not be indexed.
@@ -57,6 +58,14 @@ func IndexFilter(indexFilter dbmodel.IndexFilter) Option {
 	}
 }
 
+// RetentionTTL can be provided to write spans tagged with jaeger.retention with that retention as TTL,
+// instead of the default TTL of the traces table.
+func RetentionTTL() Option {
+	return func(o *Options) {
+		o.retentionTTL = true
+	}
+}
+
 func applyOptions(opts ...Option) Options {
 	o := Options{}
 	for _, opt := range opts {
@@ -63,7 +63,10 @@ func withSpanWriter(writeCacheTTL time.Duration, fn func(w *spanWriterTest), opt
 	fn(w)
 }
 
-var _ spanstore.Writer = &SpanWriter{} // check API conformance
+var (
+	_ spanstore.Writer       = &SpanWriter{} // check API conformance
+	_ spanstore.TraceDeleter = &SpanWriter{}
+)
 
 func TestClientClose(t *testing.T) {
 	withSpanWriter(0, func(w *spanWriterTest) {
@@ -435,3 +438,89 @@ func TestStorageMode_StoreWithoutIndexing(t *testing.T) {
 		w.session.AssertNotCalled(t, "Query", stringMatcher(serviceNameIndex), matchEverything())
 	}, StoreWithoutIndexing())
 }
+
+func TestSpanWriterRetentionTTL(t *testing.T) {
+	testCases := []struct {
+		caption      string
+		options      []Option
+		tags         model.KeyValues
+		expectedStmt string
+		expectedTTL  any
+	}{
+		{
+			caption:      "retention honored",
+			options:      []Option{StoreWithoutIndexing(), RetentionTTL()},
+			tags:         model.KeyValues{model.String(model.RetentionTagKey, "30d")},
+			expectedStmt: insertSpanWithTTL,
+			expectedTTL:  30 * 24 * 3600,
+		},
+		{
+			caption:      "retention ignored when disabled",
+			options:      []Option{StoreWithoutIndexing()},
+			tags:         model.KeyValues{model.String(model.RetentionTagKey, "30d")},
+			expectedStmt: insertSpan,
+		},
+		{
+			caption:      "no retention tag",
+			options:      []Option{StoreWithoutIndexing(), RetentionTTL()},
+			expectedStmt: insertSpan,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanWriter(0, func(w *spanWriterTest) {
+				span := &model.Span{
+					TraceID: model.NewTraceID(0, 1),
+					Tags:    testCase.tags,
+					Process: &model.Process{
+						ServiceName: "service-a",
+					},
+				}
+				var args []any
+				spanQuery := &mocks.Query{}
+				spanQuery.On("Exec").Return(nil)
+				w.session.On("Query", testCase.expectedStmt, mock.MatchedBy(func(v []any) bool {
+					args = v
+					return true
+				})).Return(spanQuery)
+
+				require.NoError(t, w.writer.WriteSpan(context.Background(), span))
+				spanQuery.AssertExpectations(t)
+				if testCase.expectedTTL != nil {
+					require.Len(t, args, 13)
+					assert.Equal(t, testCase.expectedTTL, args[12])
+				} else {
+					assert.Len(t, args, 12)
+				}
+			}, testCase.options...)
+		})
+	}
+}
+
+func TestSpanWriterDeleteTraces(t *testing.T) {
+	withSpanWriter(0, func(w *spanWriterTest) {
+		deleteQuery := &mocks.Query{}
+		deleteQuery.On("Exec").Return(nil)
+		w.session.On("Query", deleteTrace, mock.Anything).Return(deleteQuery)
+
+		traceIDs := []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)}
+		require.NoError(t, w.writer.DeleteTraces(context.Background(), traceIDs))
+		w.session.AssertCalled(t, "Query", deleteTrace, []any{dbmodel.TraceIDFromDomain(traceIDs[0])})
+		w.session.AssertCalled(t, "Query", deleteTrace, []any{dbmodel.TraceIDFromDomain(traceIDs[1])})
+		deleteQuery.AssertNumberOfCalls(t, "Exec", 2)
+	})
+}
+
+func TestSpanWriterDeleteTracesError(t *testing.T) {
+	withSpanWriter(0, func(w *spanWriterTest) {
+		deleteQuery := &mocks.Query{}
+		deleteQuery.On("Exec").Return(errors.New("delete error"))
+		deleteQuery.On("String").Return("delete from traces")
+		w.session.On("Query", deleteTrace, mock.Anything).Return(deleteQuery)
+
+		err := w.writer.DeleteTraces(context.Background(), []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)})
+		require.EqualError(t, err, "failed to delete trace 0000000000000001: failed to Exec query 'delete from traces': delete error")
+		deleteQuery.AssertNumberOfCalls(t, "Exec", 1)
+	})
+}
@@ -31,11 +31,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+	"github.com/jaegertracing/jaeger/pkg/hostname"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin"
+	esLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/es"
 	esDepStore "github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
 	esSampleStore "github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore"
@@ -52,11 +55,12 @@ const (
 )
 
 var ( // interface comformance checks
-	_ storage.Factory        = (*Factory)(nil)
-	_ storage.ArchiveFactory = (*Factory)(nil)
-	_ io.Closer              = (*Factory)(nil)
-	_ plugin.Configurable    = (*Factory)(nil)
-	_ storage.Purger         = (*Factory)(nil)
+	_ storage.Factory              = (*Factory)(nil)
+	_ storage.ArchiveFactory       = (*Factory)(nil)
+	_ io.Closer                    = (*Factory)(nil)
+	_ plugin.Configurable          = (*Factory)(nil)
+	_ storage.Purger               = (*Factory)(nil)
+	_ storage.SamplingStoreFactory = (*Factory)(nil)
 )
 
 // Factory implements storage.Factory for Elasticsearch backend.
@@ -148,6 +152,12 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	}
 	f.primaryClient.Store(&primaryClient)
 
+	if f.primaryConfig.VerifyIndexTemplates {
+		if err := verifyIndexTemplates(primaryClient, f.primaryConfig); err != nil {
+			return fmt.Errorf("failed to verify Elasticsearch index templates: %w", err)
+		}
+	}
+
 	if f.primaryConfig.PasswordFilePath != "" {
 		primaryWatcher, err := fswatcher.New([]string{f.primaryConfig.PasswordFilePath}, f.onPrimaryPasswordChange, f.logger)
 		if err != nil {
@@ -231,7 +241,7 @@ func createSpanReader(
 	if cfg.UseILM && !cfg.UseReadWriteAliases {
 		return nil, fmt.Errorf("--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
 	}
-	return esSpanStore.NewSpanReader(esSpanStore.SpanReaderParams{
+	reader := esSpanStore.NewSpanReader(esSpanStore.SpanReaderParams{
 		Client:                        clientFn,
 		MaxDocCount:                   cfg.MaxDocCount,
 		MaxSpanAge:                    cfg.MaxSpanAge,
@@ -242,11 +252,23 @@ func createSpanReader(
 		ServiceIndexRolloverFrequency: cfg.GetIndexRolloverFrequencyServicesDuration(),
 		TagDotReplacement:             cfg.Tags.DotReplacement,
 		UseReadWriteAliases:           cfg.UseReadWriteAliases,
+		UseRetentionIndices:           cfg.UseRetentionIndices,
+		RouteByTraceID:                cfg.RouteByTraceID,
 		Archive:                       archive,
 		RemoteReadClusters:            cfg.RemoteReadClusters,
+		DocValuesOnly:                 cfg.DocValuesOnly,
+		SearchTimeWindow:              cfg.SearchTimeWindow,
 		Logger:                        logger,
 		MetricsFactory:                mFactory,
 		Tracer:                        tp.Tracer("esSpanStore.SpanReader"),
+	})
+	if cfg.HedgedReads.MaxAttempts < 2 {
+		return reader, nil
+	}
+	return spanstore.NewHedgedReader(reader, spanstore.HedgedReadOptions{
+		Delay:          cfg.HedgedReads.Delay,
+		MaxAttempts:    cfg.HedgedReads.MaxAttempts,
+		MetricsFactory: mFactory,
 	}), nil
 }
 
@@ -277,6 +299,10 @@ func createSpanWriter(
 		TagDotReplacement:      cfg.Tags.DotReplacement,
 		Archive:                archive,
 		UseReadWriteAliases:    cfg.UseReadWriteAliases,
+		UseRetentionIndices:    cfg.UseRetentionIndices,
+		RouteByTraceID:         cfg.RouteByTraceID,
+		SpanLogsKeepFirst:      cfg.SpanLogsLimit.KeepFirst,
+		SpanLogsKeepLast:       cfg.SpanLogsLimit.KeepLast,
 		Logger:                 logger,
 		MetricsFactory:         mFactory,
 		ServiceCacheTTL:        cfg.ServiceCacheTTL,
@@ -285,6 +311,7 @@ func createSpanWriter(
 	// Creating a template here would conflict with the one created for ILM resulting to no index rollover
 	if cfg.CreateIndexTemplates && !cfg.UseILM {
 		mappingBuilder := mappingBuilderFromConfig(cfg)
+		mappingBuilder.Archive = archive
 		spanMapping, serviceMapping, err := mappingBuilder.GetSpanServiceMappings()
 		if err != nil {
 			return nil, err
@@ -293,9 +320,24 @@ func createSpanWriter(
 			return nil, err
 		}
 	}
+	// the aliases are checked after the templates are created, so that bootstrapped indices get their mappings
+	if err := writer.EnsureAliases(context.Background(), cfg.BootstrapAliases, cfg.UseILM); err != nil {
+		return nil, fmt.Errorf("failed to verify Elasticsearch index aliases: %w", err)
+	}
 	return writer, nil
 }
 
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := hostname.AsIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info("Using unique participantName in the distributed lock", zap.String("participantName", hostname))
+
+	return esLock.NewLock(f.getPrimaryClient, f.primaryConfig.IndexPrefix, hostname), nil
+}
+
 func (f *Factory) CreateSamplingStore(int /* maxBuckets */) (samplingstore.Store, error) {
 	params := esSampleStore.Params{
 		Client:                 f.getPrimaryClient,
@@ -333,7 +375,57 @@ func mappingBuilderFromConfig(cfg *config.Configuration) mappings.MappingBuilder
 		PrioritySpanTemplate:         cfg.PrioritySpanTemplate,
 		PriorityServiceTemplate:      cfg.PriorityServiceTemplate,
 		PriorityDependenciesTemplate: cfg.PriorityDependenciesTemplate,
+		DocValuesOnly:                cfg.DocValuesOnly,
+		UseComposableTemplates:       cfg.UseComposableTemplates,
+		ComponentTemplates:           cfg.ComponentTemplates,
+	}
+}
+
+// verifyIndexTemplates checks that the span, service and dependencies index templates
+// installed in Elasticsearch match the templates that would be created with this configuration.
+func verifyIndexTemplates(client es.Client, cfg *config.Configuration) error {
+	if cfg.CreateIndexTemplates {
+		return errors.New("verifying index templates requires disabling their creation with --es.create-index-templates=false")
 	}
+	mappingBuilder := mappingBuilderFromConfig(cfg)
+	spanMapping, serviceMapping, err := mappingBuilder.GetSpanServiceMappings()
+	if err != nil {
+		return err
+	}
+	dependenciesMapping, err := mappingBuilder.GetDependenciesMappings()
+	if err != nil {
+		return err
+	}
+	// the template names used by SpanWriter.CreateTemplates and DependencyStore.CreateTemplates
+	templates := []struct {
+		name    string
+		mapping string
+	}{
+		{name: mappingBuilder.IndexPrefix + "jaeger-span", mapping: spanMapping},
+		{name: mappingBuilder.IndexPrefix + "jaeger-service", mapping: serviceMapping},
+		{name: "jaeger-dependencies", mapping: dependenciesMapping},
+	}
+	var errs []error
+	for _, t := range templates {
+		installed, err := client.GetTemplate(t.name).Do(context.Background())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if installed == nil {
+			errs = append(errs, fmt.Errorf("index template %s does not exist", t.name))
+			continue
+		}
+		diff, err := mappings.DiffTemplate(t.mapping, installed)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot compare index template %s: %w", t.name, err))
+			continue
+		}
+		if len(diff) > 0 {
+			errs = append(errs, fmt.Errorf("index template %s drifted from the expected definition:\n  %s", t.name, strings.Join(diff, "\n  ")))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func createDependencyReader(
@@ -18,6 +18,7 @@ package es
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -41,6 +42,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	esSpanStore "github.com/jaegertracing/jaeger/plugin/storage/es/spanstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -55,6 +57,7 @@ var mockEsServerResponse = []byte(`
 type mockClientBuilder struct {
 	err                 error
 	createTemplateError error
+	aliasesMissing      bool
 }
 
 func (m *mockClientBuilder) NewClient(*escfg.Configuration, *zap.Logger, metrics.Factory) (es.Client, error) {
@@ -64,6 +67,12 @@ func (m *mockClientBuilder) NewClient(*escfg.Configuration, *zap.Logger, metrics
 		tService.On("Body", mock.Anything).Return(tService)
 		tService.On("Do", context.Background()).Return(nil, m.createTemplateError)
 		c.On("CreateTemplate", mock.Anything).Return(tService)
+		aService := &mocks.IndicesExistsService{}
+		aService.On("Do", mock.Anything).Return(!m.aliasesMissing, nil)
+		c.On("AliasExists", mock.Anything).Return(aService)
+		iService := &mocks.IndicesExistsService{}
+		iService.On("Do", mock.Anything).Return(false, nil)
+		c.On("IndexExists", mock.Anything).Return(iService)
 		c.On("GetVersion").Return(uint(6))
 		c.On("Close").Return(nil)
 		return c, nil
@@ -110,6 +119,9 @@ func TestElasticsearchFactory(t *testing.T) {
 	_, err = f.CreateSamplingStore(1)
 	require.NoError(t, err)
 
+	_, err = f.CreateLock()
+	require.NoError(t, err)
+
 	require.NoError(t, f.Close())
 }
 
@@ -222,6 +234,66 @@ func TestCreateTemplateError(t *testing.T) {
 	require.Error(t, err, "template-error")
 }
 
+func TestVerifyIndexTemplates(t *testing.T) {
+	cfg := &escfg.Configuration{IndexPrefix: "prod", NumShards: 5, NumReplicas: 1}
+	mappingBuilder := mappingBuilderFromConfig(cfg)
+	spanMapping, serviceMapping, err := mappingBuilder.GetSpanServiceMappings()
+	require.NoError(t, err)
+	dependenciesMapping, err := mappingBuilder.GetDependenciesMappings()
+	require.NoError(t, err)
+	installed := map[string]json.RawMessage{
+		"prod-jaeger-span":    json.RawMessage(spanMapping),
+		"prod-jaeger-service": json.RawMessage(serviceMapping),
+		"jaeger-dependencies": json.RawMessage(dependenciesMapping),
+	}
+	newClient := func(templates map[string]json.RawMessage, getErr error) *mocks.Client {
+		c := &mocks.Client{}
+		c.On("GetTemplate", mock.Anything).Return(func(name string) es.TemplateGetService {
+			s := &mocks.TemplateGetService{}
+			s.On("Do", mock.Anything).Return(templates[name], getErr)
+			return s
+		})
+		return c
+	}
+
+	t.Run("match", func(t *testing.T) {
+		require.NoError(t, verifyIndexTemplates(newClient(installed, nil), cfg))
+	})
+	t.Run("drift and missing", func(t *testing.T) {
+		drifted := map[string]json.RawMessage{
+			"prod-jaeger-span":    installed["prod-jaeger-span"],
+			"prod-jaeger-service": json.RawMessage(strings.Replace(serviceMapping, `"index.number_of_shards": 5`, `"index.number_of_shards": 3`, 1)),
+		}
+		err := verifyIndexTemplates(newClient(drifted, nil), cfg)
+		require.ErrorContains(t, err, "index template prod-jaeger-service drifted from the expected definition:\n  settings.index.number_of_shards: expected 5, got 3")
+		require.ErrorContains(t, err, "index template jaeger-dependencies does not exist")
+		assert.NotContains(t, err.Error(), "prod-jaeger-span")
+	})
+	t.Run("get error", func(t *testing.T) {
+		err := verifyIndexTemplates(newClient(nil, errors.New("get-error")), cfg)
+		require.ErrorContains(t, err, "get-error")
+	})
+	t.Run("template creation enabled", func(t *testing.T) {
+		err := verifyIndexTemplates(newClient(installed, nil), &escfg.Configuration{CreateIndexTemplates: true})
+		require.ErrorContains(t, err, "requires disabling their creation")
+	})
+}
+
+func TestInitializeVerifiesIndexTemplates(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{VerifyIndexTemplates: true}
+	f.archiveConfig = &escfg.Configuration{}
+	f.newClientFn = func(*escfg.Configuration, *zap.Logger, metrics.Factory) (es.Client, error) {
+		s := &mocks.TemplateGetService{}
+		s.On("Do", mock.Anything).Return(nil, nil)
+		c := &mocks.Client{}
+		c.On("GetTemplate", mock.Anything).Return(s)
+		return c, nil
+	}
+	err := f.Initialize(metrics.NullFactory, zap.NewNop())
+	require.ErrorContains(t, err, "failed to verify Elasticsearch index templates: index template jaeger-span does not exist")
+}
+
 func TestILMDisableTemplateCreation(t *testing.T) {
 	f := NewFactory()
 	f.primaryConfig = &escfg.Configuration{UseILM: true, UseReadWriteAliases: true, CreateIndexTemplates: true}
@@ -234,6 +306,36 @@ func TestILMDisableTemplateCreation(t *testing.T) {
 	require.NoError(t, err) // as the createTemplate is not called, CreateSpanWriter should not return an error
 }
 
+func TestElasticsearchMissingAliases(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{UseReadWriteAliases: true}
+	f.archiveConfig = &escfg.Configuration{}
+	f.newClientFn = (&mockClientBuilder{aliasesMissing: true}).NewClient
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+	_, err := f.CreateSpanWriter()
+	require.ErrorContains(t, err, "failed to verify Elasticsearch index aliases: index aliases jaeger-span-read, jaeger-span-write do not exist")
+}
+
+func TestElasticsearchHedgedReads(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{
+		HedgedReads: escfg.HedgedReadsConfig{Delay: 100 * time.Millisecond, MaxAttempts: 2},
+	}
+	f.archiveConfig = &escfg.Configuration{Enabled: true}
+	f.newClientFn = (&mockClientBuilder{}).NewClient
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+
+	r, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	assert.IsType(t, &spanstore.HedgedReader{}, r)
+
+	r, err = f.CreateArchiveSpanReader()
+	require.NoError(t, err)
+	assert.IsType(t, &esSpanStore.SpanReader{}, r)
+}
+
 func TestArchiveDisabled(t *testing.T) {
 	f := NewFactory()
 	f.archiveConfig = &escfg.Configuration{Enabled: false}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/config"
 )
 
 // MAPPINGS contains embedded index templates.
@@ -40,11 +41,52 @@ type MappingBuilder struct {
 	IndexPrefix                  string
 	UseILM                       bool
 	ILMPolicyName                string
+	// UseISM renders the read alias and the rollover alias setting of OpenSearch Index State Management
+	UseISM bool
+	// UseComposableTemplates renders composable index templates, which are always used with Elasticsearch 8
+	UseComposableTemplates bool
+	// ComponentTemplates are the component templates that composable index templates are composed of
+	ComponentTemplates []string
+	// DocValuesOnly selects span fields mapped without an index
+	DocValuesOnly config.DocValuesOnly
+	// Archive renders the span template of the archive indices, with their own aliases
+	// and a priority above the span template, whose index pattern also matches them
+	Archive bool
+}
+
+const (
+	spanIndex        = "jaeger-span"
+	archiveSpanIndex = "jaeger-span-archive"
+)
+
+// SpanIndexName returns the name of the span indices without the prefix, used by the span template.
+func (mb *MappingBuilder) SpanIndexName() string {
+	if mb.Archive {
+		return archiveSpanIndex
+	}
+	return spanIndex
+}
+
+// SpanIndexPattern returns the index pattern of the span template without the prefix.
+// The archive pattern matches both the archive index and the archive rollover indices.
+func (mb *MappingBuilder) SpanIndexPattern() string {
+	if mb.Archive {
+		return archiveSpanIndex + "*"
+	}
+	return spanIndex + "-*"
+}
+
+// SpanTemplatePriority returns the priority of the composable span template.
+func (mb *MappingBuilder) SpanTemplatePriority() int64 {
+	if mb.Archive {
+		return mb.PrioritySpanTemplate + 1
+	}
+	return mb.PrioritySpanTemplate
 }
 
 // GetMapping returns the rendered mapping based on elasticsearch version
 func (mb *MappingBuilder) GetMapping(mapping string) (string, error) {
-	if mb.EsVersion == 8 {
+	if mb.EsVersion == 8 || mb.UseComposableTemplates {
 		return mb.fixMapping(mapping + "-8.json")
 	}
 	return mb.fixMapping(mapping + "-7.json")
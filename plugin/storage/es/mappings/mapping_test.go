@@ -16,6 +16,7 @@ package mappings
 
 import (
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
@@ -73,6 +75,139 @@ func TestMappingBuilder_GetMapping(t *testing.T) {
 	}
 }
 
+func TestMappingBuilder_GetMappingISM(t *testing.T) {
+	for _, mapping := range []string{"jaeger-span", "jaeger-service", "jaeger-dependencies", "jaeger-sampling"} {
+		for _, esVersion := range []uint{7, 8} {
+			t.Run(fmt.Sprintf("%s-%d", mapping, esVersion), func(t *testing.T) {
+				mb := &MappingBuilder{
+					TemplateBuilder: es.TextTemplateBuilder{},
+					EsVersion:       esVersion,
+					IndexPrefix:     "test-",
+					UseISM:          true,
+				}
+				got, err := mb.GetMapping(mapping)
+				require.NoError(t, err)
+				var tmpl map[string]any
+				require.NoError(t, json.Unmarshal([]byte(got), &tmpl))
+				if esVersion == 8 {
+					tmpl = tmpl["template"].(map[string]any)
+				}
+				assert.Equal(t, map[string]any{"test-" + mapping + "-read": map[string]any{}}, tmpl["aliases"])
+				settings := tmpl["settings"].(map[string]any)
+				assert.Equal(t, "test-"+mapping+"-write", settings["plugins.index_state_management.rollover_alias"])
+				assert.NotContains(t, settings, "lifecycle")
+			})
+		}
+	}
+}
+
+func TestMappingBuilder_GetMappingArchive(t *testing.T) {
+	for _, esVersion := range []uint{7, 8} {
+		t.Run(fmt.Sprintf("v%d", esVersion), func(t *testing.T) {
+			mb := &MappingBuilder{
+				TemplateBuilder:      es.TextTemplateBuilder{},
+				EsVersion:            esVersion,
+				IndexPrefix:          "test-",
+				PrioritySpanTemplate: 500,
+				UseILM:               true,
+				ILMPolicyName:        "jaeger-archive-policy",
+				Archive:              true,
+			}
+			got, err := mb.GetMapping("jaeger-span")
+			require.NoError(t, err)
+			var tmpl map[string]any
+			require.NoError(t, json.Unmarshal([]byte(got), &tmpl))
+			if esVersion == 8 {
+				assert.Equal(t, "test-jaeger-span-archive*", tmpl["index_patterns"])
+				assert.EqualValues(t, 501, tmpl["priority"])
+				tmpl = tmpl["template"].(map[string]any)
+			} else {
+				assert.Equal(t, "*test-jaeger-span-archive*", tmpl["index_patterns"])
+				assert.EqualValues(t, 1, tmpl["order"])
+			}
+			assert.Equal(t, map[string]any{"test-jaeger-span-archive-read": map[string]any{}}, tmpl["aliases"])
+			settings := tmpl["settings"].(map[string]any)
+			assert.Equal(t, map[string]any{
+				"name":           "jaeger-archive-policy",
+				"rollover_alias": "test-jaeger-span-archive-write",
+			}, settings["lifecycle"])
+		})
+	}
+}
+
+func TestMappingBuilder_GetMappingDocValuesOnly(t *testing.T) {
+	// leafFields navigates the rendered span mapping to the mapping of each leaf field.
+	leafFields := func(t *testing.T, rendered string, esVersion uint) map[string]map[string]any {
+		var tmpl map[string]any
+		require.NoError(t, json.Unmarshal([]byte(rendered), &tmpl))
+		if esVersion == 8 {
+			tmpl = tmpl["template"].(map[string]any)
+		}
+		props := tmpl["mappings"].(map[string]any)["properties"].(map[string]any)
+		get := func(m map[string]any, path ...string) map[string]any {
+			for _, p := range path {
+				m = m[p].(map[string]any)
+			}
+			return m
+		}
+		return map[string]map[string]any{
+			"logs.timestamp":       get(props, "logs", "properties", "timestamp"),
+			"logs.fields.value":    get(props, "logs", "properties", "fields", "properties", "value"),
+			"references.traceID":   get(props, "references", "properties", "traceID"),
+			"tags.key":             get(props, "tags", "properties", "key"),
+			"process.tags.value":   get(props, "process", "properties", "tags", "properties", "value"),
+			"process.serviceName":  get(props, "process", "properties", "serviceName"),
+			"operationName":        get(props, "operationName"),
+			"references.refType":   get(props, "references", "properties", "refType"),
+			"process.tags.tagType": get(props, "process", "properties", "tags", "properties", "tagType"),
+		}
+	}
+	for _, esVersion := range []uint{7, 8} {
+		t.Run(fmt.Sprintf("v%d", esVersion), func(t *testing.T) {
+			mb := &MappingBuilder{
+				TemplateBuilder: es.TextTemplateBuilder{},
+				EsVersion:       esVersion,
+				DocValuesOnly: config.DocValuesOnly{
+					Logs:        true,
+					References:  true,
+					ProcessTags: true,
+				},
+			}
+			got, err := mb.GetMapping("jaeger-span")
+			require.NoError(t, err)
+			fields := leafFields(t, got, esVersion)
+			for _, name := range []string{"logs.timestamp", "logs.fields.value", "references.traceID", "references.refType", "process.tags.value", "process.tags.tagType"} {
+				assert.Equal(t, false, fields[name]["index"], name)
+			}
+			for _, name := range []string{"tags.key", "process.serviceName", "operationName"} {
+				assert.NotContains(t, fields[name], "index", name)
+			}
+		})
+	}
+}
+
+func TestMappingBuilder_GetMappingComposable(t *testing.T) {
+	for _, mapping := range []string{"jaeger-span", "jaeger-service", "jaeger-dependencies", "jaeger-sampling"} {
+		t.Run(mapping, func(t *testing.T) {
+			mb := &MappingBuilder{
+				TemplateBuilder:        es.TextTemplateBuilder{},
+				EsVersion:              7,
+				UseComposableTemplates: true,
+				ComponentTemplates:     []string{"jaeger-analyzers", "jaeger-lifecycle"},
+			}
+			got, err := mb.GetMapping(mapping)
+			require.NoError(t, err)
+			var tmpl struct {
+				ComposedOf []string       `json:"composed_of"`
+				Template   map[string]any `json:"template"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(got), &tmpl))
+			assert.Equal(t, []string{"jaeger-analyzers", "jaeger-lifecycle"}, tmpl.ComposedOf)
+			assert.Contains(t, tmpl.Template, "mappings")
+		})
+	}
+}
+
 func TestMappingBuilder_loadMapping(t *testing.T) {
 	tests := []struct {
 		name string
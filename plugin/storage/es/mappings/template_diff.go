@@ -0,0 +1,119 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package mappings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	composedOfField = "composed_of"
+	settingsField   = "settings"
+	indexSettings   = "index."
+)
+
+// DiffTemplate compares an index template installed in Elasticsearch with the expected
+// rendered template and returns the differences, one per field, sorted by field path.
+//
+// Elasticsearch adds defaults to stored templates and stores settings as strings,
+// so only the fields of the expected template are compared, by their string values.
+// The list of component templates must match exactly. Lifecycle settings are ignored
+// because they are typically managed by ILM or supplied by component templates.
+func DiffTemplate(expected string, actual []byte) ([]string, error) {
+	want, err := flattenTemplate([]byte(expected))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected template: %w", err)
+	}
+	got, err := flattenTemplate(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse installed template: %w", err)
+	}
+	var diff []string
+	for path, w := range want {
+		if g, ok := got[path]; !ok {
+			diff = append(diff, fmt.Sprintf("%s: expected %s, missing", path, w))
+		} else if g != w {
+			diff = append(diff, fmt.Sprintf("%s: expected %s, got %s", path, w, g))
+		}
+	}
+	for path, g := range got {
+		if _, ok := want[path]; !ok && strings.HasPrefix(path, composedOfField+".") {
+			diff = append(diff, fmt.Sprintf("%s: unexpected %s", path, g))
+		}
+	}
+	sort.Strings(diff)
+	return diff, nil
+}
+
+// flattenTemplate maps the dotted path of every leaf field of the template to its string value.
+func flattenTemplate(template []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(template))
+	dec.UseNumber()
+	var root map[string]any
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	// a single pattern is stored as a list
+	if pattern, ok := root["index_patterns"].(string); ok {
+		root["index_patterns"] = []any{pattern}
+	}
+	leaves := make(map[string]string)
+	flatten("", root, leaves)
+	fields := make(map[string]string, len(leaves))
+	for path, value := range leaves {
+		if normalized, ok := normalizeSettingPath(path); ok {
+			fields[normalized] = value
+		}
+	}
+	return fields, nil
+}
+
+func flatten(path string, value any, fields map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			fields[path] = "{}"
+		}
+		for key, child := range v {
+			flatten(join(key), child, fields)
+		}
+	case []any:
+		if len(v) == 0 {
+			fields[path] = "[]"
+		}
+		for i, child := range v {
+			flatten(join(strconv.Itoa(i)), child, fields)
+		}
+	default:
+		fields[path] = fmt.Sprint(v)
+	}
+}
+
+// normalizeSettingPath qualifies setting names with the "index." prefix, which
+// Elasticsearch adds when storing templates. It returns false for ignored settings.
+func normalizeSettingPath(path string) (string, bool) {
+	for _, prefix := range []string{settingsField + ".", "template." + settingsField + "."} {
+		if setting, ok := strings.CutPrefix(path, prefix); ok {
+			if !strings.HasPrefix(setting, indexSettings) {
+				setting = indexSettings + setting
+			}
+			if strings.HasPrefix(setting, indexSettings+"lifecycle.") {
+				return "", false
+			}
+			return prefix + setting, true
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package mappings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+)
+
+const expectedTemplate = `{
+  "priority": 500,
+  "composed_of": ["jaeger-analyzers"],
+  "index_patterns": "jaeger-span-*",
+  "template": {
+    "settings": {
+      "index.number_of_shards": 5,
+      "index.mapping.nested_fields.limit": 50,
+      "lifecycle": {"name": "jaeger-ilm-policy"}
+    },
+    "mappings": {
+      "properties": {
+        "traceID": {"type": "keyword", "ignore_above": 256},
+        "logs": {"type": "nested", "dynamic": false}
+      }
+    }
+  }
+}`
+
+func TestDiffTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed string
+		want      []string
+	}{
+		{
+			name: "stored form matches",
+			installed: `{
+				"index_patterns": ["jaeger-span-*"],
+				"composed_of": ["jaeger-analyzers"],
+				"priority": 500,
+				"version": 3,
+				"template": {
+					"settings": {"index": {"number_of_shards": "5", "mapping": {"nested_fields": {"limit": "50"}}}},
+					"mappings": {"properties": {
+						"traceID": {"type": "keyword", "ignore_above": 256},
+						"logs": {"type": "nested", "dynamic": "false"}
+					}}
+				}
+			}`,
+		},
+		{
+			name: "drifted",
+			installed: `{
+				"index_patterns": ["jaeger-span-*"],
+				"composed_of": ["jaeger-analyzers", "custom"],
+				"priority": 500,
+				"template": {
+					"settings": {"index": {"number_of_shards": "3", "mapping": {"nested_fields": {"limit": "50"}}}},
+					"mappings": {"properties": {
+						"logs": {"type": "nested", "dynamic": "false"}
+					}}
+				}
+			}`,
+			want: []string{
+				"composed_of.1: unexpected custom",
+				"template.mappings.properties.traceID.ignore_above: expected 256, missing",
+				"template.mappings.properties.traceID.type: expected keyword, missing",
+				"template.settings.index.number_of_shards: expected 5, got 3",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff, err := DiffTemplate(expectedTemplate, []byte(test.installed))
+			require.NoError(t, err)
+			assert.Equal(t, test.want, diff)
+		})
+	}
+}
+
+func TestDiffTemplateRenderedLegacyTemplate(t *testing.T) {
+	mb := &MappingBuilder{
+		TemplateBuilder: es.TextTemplateBuilder{},
+		Shards:          5,
+		Replicas:        1,
+		EsVersion:       7,
+		UseILM:          true,
+		ILMPolicyName:   "jaeger-ilm-policy",
+	}
+	rendered, err := mb.GetMapping("jaeger-span")
+	require.NoError(t, err)
+	diff, err := DiffTemplate(rendered, []byte(rendered))
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffTemplateInvalidJSON(t *testing.T) {
+	_, err := DiffTemplate("{", []byte("{}"))
+	require.ErrorContains(t, err, "failed to parse expected template")
+	_, err = DiffTemplate("{}", []byte("["))
+	require.ErrorContains(t, err, "failed to parse installed template")
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
@@ -35,6 +36,9 @@ const (
 	suffixSnifferTLSEnabled              = ".sniffer-tls-enabled"
 	suffixTokenPath                      = ".token-file"
 	suffixPasswordPath                   = ".password-file"
+	suffixTokenReloadInterval            = ".token-reload-interval"
+	suffixAPIKey                         = ".api-key"
+	suffixAPIKeyPath                     = ".api-key-file"
 	suffixServerURLs                     = ".server-urls"
 	suffixRemoteReadClusters             = ".remote-read-clusters"
 	suffixMaxSpanAge                     = ".max-span-age"
@@ -60,14 +64,29 @@ const (
 	suffixTagsAsFieldsInclude            = suffixTagsAsFields + ".include"
 	suffixTagsFile                       = suffixTagsAsFields + ".config-file"
 	suffixTagDeDotChar                   = suffixTagsAsFields + ".dot-replacement"
+	suffixDocValuesOnly                  = ".doc-values-only"
 	suffixReadAlias                      = ".use-aliases"
+	suffixBootstrapAliases               = ".bootstrap-aliases"
+	suffixRetentionIndices               = ".use-retention-indices"
+	suffixRouteByTraceID                 = ".route-by-trace-id"
 	suffixUseILM                         = ".use-ilm"
+	suffixComposableTemplates            = ".use-composable-templates"
+	suffixComponentTemplates             = ".component-templates"
+	suffixVerifyIndexTemplates           = ".verify-index-templates"
 	suffixCreateIndexTemplate            = ".create-index-templates"
 	suffixEnabled                        = ".enabled"
 	suffixVersion                        = ".version"
 	suffixMaxDocCount                    = ".max-doc-count"
 	suffixLogLevel                       = ".log-level"
 	suffixSendGetBodyAs                  = ".send-get-body-as"
+	suffixHedgedReadsDelay               = ".hedged-reads.delay"
+	suffixHedgedReadsMaxAttempts         = ".hedged-reads.max-attempts"
+	suffixSpanLogsLimitKeepFirst         = ".span-logs-limit.keep-first"
+	suffixSpanLogsLimitKeepLast          = ".span-logs-limit.keep-last"
+	suffixSearchTimeWindowMax            = ".search-time-window.max"
+	suffixSearchTimeWindowClamp          = ".search-time-window.clamp"
+	suffixClusterValidationEnabled       = ".cluster-validation.enabled"
+	suffixClusterValidationFail          = ".cluster-validation.fail"
 	// default number of documents to return from a query (elasticsearch allowed limit)
 	// see search.max_buckets and index.max_result_window
 	defaultMaxDocCount        = 10_000
@@ -151,6 +170,18 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 		nsConfig.namespace+suffixPasswordPath,
 		nsConfig.PasswordFilePath,
 		"Path to a file containing password. This file is watched for changes.")
+	flagSet.Duration(
+		nsConfig.namespace+suffixTokenReloadInterval,
+		nsConfig.TokenReloadInterval,
+		"How often the bearer token and API key files are read again, to pick up rotated credentials such as Kubernetes projected service account tokens. The files are read once when 0.")
+	flagSet.String(
+		nsConfig.namespace+suffixAPIKey,
+		nsConfig.APIKey,
+		"The API key authenticating to Elasticsearch, encoded in base64 as returned by the create API key API")
+	flagSet.String(
+		nsConfig.namespace+suffixAPIKeyPath,
+		nsConfig.APIKeyFilePath,
+		"Path to a file containing the API key authenticating to Elasticsearch, encoded in base64 as returned by the create API key API")
 	flagSet.Bool(
 		nsConfig.namespace+suffixSniffer,
 		nsConfig.Sniffer,
@@ -248,12 +279,37 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 		nsConfig.namespace+suffixTagDeDotChar,
 		nsConfig.Tags.DotReplacement,
 		"(experimental) The character used to replace dots (\".\") in tag keys stored as object fields.")
+	flagSet.String(
+		nsConfig.namespace+suffixDocValuesOnly,
+		"",
+		"(experimental) Comma delimited list of span field groups stored without indexing, keeping only doc_values, to shrink the span indices: "+
+			config.DocValuesOnlyLogs+", "+config.DocValuesOnlyReferences+", "+config.DocValuesOnlyTags+", "+config.DocValuesOnlyProcessTags+". "+
+			"Spans can no longer be searched by tags stored in these groups; tags stored as object fields ("+suffixTagsAsFields+") remain searchable. "+
+			"Applies to index templates created by "+nsConfig.namespace+suffixCreateIndexTemplate+".")
 	flagSet.Bool(
 		nsConfig.namespace+suffixReadAlias,
 		nsConfig.UseReadWriteAliases,
 		"Use read and write aliases for indices. Use this option with Elasticsearch rollover "+
 			"API. It requires an external component to create aliases before startup and then performing its management. "+
-			"Note that es"+suffixMaxSpanAge+" will influence trace search window start times.")
+			"Note that es"+suffixMaxSpanAge+" will influence trace search window start times. "+
+			"The span writer fails to start when the aliases do not exist.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixBootstrapAliases,
+		nsConfig.BootstrapAliases,
+		"Create the initial span and service indices and their read and write aliases at startup when they do not exist, like es-rollover init. "+
+			"Use this option with "+nsConfig.namespace+suffixReadAlias+".")
+	flagSet.Bool(
+		nsConfig.namespace+suffixRetentionIndices,
+		nsConfig.UseRetentionIndices,
+		"(experimental) Write spans tagged with "+model.RetentionTagKey+" to index families named after the requested retention, "+
+			"e.g. jaeger-span-retention-30d-2024-05-01, which es-index-cleaner deletes once they are older than that retention. "+
+			"Not supported with "+nsConfig.namespace+suffixReadAlias+". Raise "+nsConfig.namespace+suffixMaxSpanAge+" to search the retained traces.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixRouteByTraceID,
+		nsConfig.RouteByTraceID,
+		"(experimental) Route the spans to the shards of the span indices by trace ID, so that reading a trace searches a single shard instead of all of them. "+
+			"The spans of a trace then all land in the same shard, so the shards holding very large traces grow bigger and get more writes than the others. "+
+			"The traces not found in their shard, e.g. written before this option was enabled, are searched again in all the shards.")
 	flagSet.Bool(
 		nsConfig.namespace+suffixUseILM,
 		nsConfig.UseILM,
@@ -264,6 +320,20 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 		nsConfig.namespace+suffixCreateIndexTemplate,
 		nsConfig.CreateIndexTemplates,
 		"Create index templates at application startup. Set to false when templates are installed manually.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixComposableTemplates,
+		nsConfig.UseComposableTemplates,
+		"Use composable index templates (the _index_template API) instead of legacy templates. Requires Elasticsearch 7.8+; always used with Elasticsearch 8.")
+	flagSet.String(
+		nsConfig.namespace+suffixComponentTemplates,
+		strings.Join(nsConfig.ComponentTemplates, ","),
+		"Comma delimited list of component templates, e.g. with custom analyzers or lifecycle settings, that the composable index templates are composed of. "+
+			"The component templates must exist in Elasticsearch before the index templates are created.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixVerifyIndexTemplates,
+		nsConfig.VerifyIndexTemplates,
+		"Verify at startup that the span, service and dependencies index templates installed in Elasticsearch match the templates Jaeger would create, "+
+			"and fail with the differences if they drifted. Lifecycle settings are not verified.")
 	flagSet.Uint(
 		nsConfig.namespace+suffixVersion,
 		0,
@@ -284,6 +354,43 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 		nsConfig.namespace+suffixSendGetBodyAs,
 		nsConfig.SendGetBodyAs,
 		"HTTP verb for requests that contain a body [GET, POST].")
+	flagSet.Duration(
+		nsConfig.namespace+suffixHedgedReadsDelay,
+		nsConfig.HedgedReads.Delay,
+		"How long a trace search may be outstanding before a duplicate search is sent, likely served by other shard copies.")
+	flagSet.Int(
+		nsConfig.namespace+suffixHedgedReadsMaxAttempts,
+		nsConfig.HedgedReads.MaxAttempts,
+		"The maximum number of concurrent searches for a trace, including the first one. Set to 2 or more to enable hedged reads.")
+	flagSet.Int(
+		nsConfig.namespace+suffixSpanLogsLimitKeepFirst,
+		nsConfig.SpanLogsLimit.KeepFirst,
+		"The number of earliest logs kept when a span has more logs than "+suffixSpanLogsLimitKeepFirst+" and "+suffixSpanLogsLimitKeepLast+" together. "+
+			"Logs recording errors are always kept, and a warning about the dropped logs is added to the span. "+
+			"The limit is disabled when both are 0.")
+	flagSet.Int(
+		nsConfig.namespace+suffixSpanLogsLimitKeepLast,
+		nsConfig.SpanLogsLimit.KeepLast,
+		"The number of latest logs kept when a span has more logs than "+suffixSpanLogsLimitKeepFirst+" and "+suffixSpanLogsLimitKeepLast+" together.")
+	flagSet.Duration(
+		nsConfig.namespace+suffixSearchTimeWindowMax,
+		nsConfig.SearchTimeWindow.Max,
+		"The longest time range of a trace search, e.g. 72h, so that long searches do not fan out to many span indices. "+
+			"Longer searches are rejected unless "+nsConfig.namespace+suffixSearchTimeWindowClamp+" is set. The limit is disabled when 0.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixSearchTimeWindowClamp,
+		nsConfig.SearchTimeWindow.Clamp,
+		"Narrow the trace searches longer than "+nsConfig.namespace+suffixSearchTimeWindowMax+" to their most recent part, "+
+			"and add a warning to the found traces, instead of rejecting them.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixClusterValidationEnabled,
+		nsConfig.ClusterValidation.Enabled,
+		"Check at startup that the cluster version is supported, that the cluster and the Jaeger indices are not blocked for writes, "+
+			"that no node reached the flood stage disk watermark, and that the user has the privileges to create and write the Jaeger indices.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixClusterValidationFail,
+		nsConfig.ClusterValidation.Fail,
+		"Fail the startup when a check of "+nsConfig.namespace+suffixClusterValidationEnabled+" fails, instead of logging a warning.")
 	flagSet.Duration(
 		nsConfig.namespace+suffixAdaptiveSamplingLookback,
 		nsConfig.AdaptiveSamplingLookback,
@@ -317,6 +424,9 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 	cfg.Password = v.GetString(cfg.namespace + suffixPassword)
 	cfg.TokenFilePath = v.GetString(cfg.namespace + suffixTokenPath)
 	cfg.PasswordFilePath = v.GetString(cfg.namespace + suffixPasswordPath)
+	cfg.TokenReloadInterval = v.GetDuration(cfg.namespace + suffixTokenReloadInterval)
+	cfg.APIKey = v.GetString(cfg.namespace + suffixAPIKey)
+	cfg.APIKeyFilePath = v.GetString(cfg.namespace + suffixAPIKeyPath)
 	cfg.Sniffer = v.GetBool(cfg.namespace + suffixSniffer)
 	cfg.SnifferTLSEnabled = v.GetBool(cfg.namespace + suffixSnifferTLSEnabled)
 	cfg.Servers = strings.Split(stripWhiteSpace(v.GetString(cfg.namespace+suffixServerURLs)), ",")
@@ -338,15 +448,37 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 	cfg.Tags.Include = v.GetString(cfg.namespace + suffixTagsAsFieldsInclude)
 	cfg.Tags.File = v.GetString(cfg.namespace + suffixTagsFile)
 	cfg.Tags.DotReplacement = v.GetString(cfg.namespace + suffixTagDeDotChar)
+	var err error
+	cfg.DocValuesOnly, err = config.ParseDocValuesOnly(v.GetString(cfg.namespace + suffixDocValuesOnly))
+	if err != nil {
+		// TODO refactor to be able to return error
+		log.Fatal(err)
+	}
 	cfg.UseReadWriteAliases = v.GetBool(cfg.namespace + suffixReadAlias)
+	cfg.BootstrapAliases = v.GetBool(cfg.namespace + suffixBootstrapAliases)
+	cfg.UseRetentionIndices = v.GetBool(cfg.namespace + suffixRetentionIndices)
+	cfg.RouteByTraceID = v.GetBool(cfg.namespace + suffixRouteByTraceID)
 	cfg.Enabled = v.GetBool(cfg.namespace + suffixEnabled)
 	cfg.CreateIndexTemplates = v.GetBool(cfg.namespace + suffixCreateIndexTemplate)
 	cfg.Version = uint(v.GetInt(cfg.namespace + suffixVersion))
 	cfg.LogLevel = v.GetString(cfg.namespace + suffixLogLevel)
 	cfg.SendGetBodyAs = v.GetString(cfg.namespace + suffixSendGetBodyAs)
+	cfg.HedgedReads.Delay = v.GetDuration(cfg.namespace + suffixHedgedReadsDelay)
+	cfg.HedgedReads.MaxAttempts = v.GetInt(cfg.namespace + suffixHedgedReadsMaxAttempts)
+	cfg.SpanLogsLimit.KeepFirst = v.GetInt(cfg.namespace + suffixSpanLogsLimitKeepFirst)
+	cfg.SpanLogsLimit.KeepLast = v.GetInt(cfg.namespace + suffixSpanLogsLimitKeepLast)
+	cfg.SearchTimeWindow.Max = v.GetDuration(cfg.namespace + suffixSearchTimeWindowMax)
+	cfg.SearchTimeWindow.Clamp = v.GetBool(cfg.namespace + suffixSearchTimeWindowClamp)
+	cfg.ClusterValidation.Enabled = v.GetBool(cfg.namespace + suffixClusterValidationEnabled)
+	cfg.ClusterValidation.Fail = v.GetBool(cfg.namespace + suffixClusterValidationFail)
 
 	cfg.MaxDocCount = v.GetInt(cfg.namespace + suffixMaxDocCount)
 	cfg.UseILM = v.GetBool(cfg.namespace + suffixUseILM)
+	cfg.UseComposableTemplates = v.GetBool(cfg.namespace + suffixComposableTemplates)
+	if componentTemplates := stripWhiteSpace(v.GetString(cfg.namespace + suffixComponentTemplates)); componentTemplates != "" {
+		cfg.ComponentTemplates = strings.Split(componentTemplates, ",")
+	}
+	cfg.VerifyIndexTemplates = v.GetBool(cfg.namespace + suffixVerifyIndexTemplates)
 
 	// TODO: Need to figure out a better way for do this.
 	cfg.AllowTokenFromContext = v.GetBool(bearertoken.StoragePropagationKey)
@@ -367,7 +499,6 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 
 	// Dependencies calculation should be daily, and this index size is very small
 	cfg.IndexDateLayoutDependencies = initDateLayout(defaultIndexRolloverFrequency, separator)
-	var err error
 	cfg.TLS, err = cfg.getTLSFlagsConfig().InitFromViper(v)
 	if err != nil {
 		// TODO refactor to be able to return error
@@ -437,5 +568,9 @@ func DefaultConfig() config.Configuration {
 		MaxDocCount:          defaultMaxDocCount,
 		LogLevel:             "error",
 		SendGetBodyAs:        defaultSendGetBodyAs,
+		HedgedReads: config.HedgedReadsConfig{
+			Delay:       time.Second,
+			MaxAttempts: 1,
+		},
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	escfg "github.com/jaegertracing/jaeger/pkg/es/config"
 )
 
 func TestOptions(t *testing.T) {
@@ -55,6 +56,8 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--es.password=world",
 		"--es.token-file=/foo/bar",
 		"--es.password-file=/foo/bar/baz",
+		"--es.token-reload-interval=1m",
+		"--es.api-key-file=/foo/api-key",
 		"--es.sniffer=true",
 		"--es.sniffer-tls-enabled=true",
 		"--es.max-span-age=48h",
@@ -78,15 +81,24 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--es.tags-as-fields.dot-replacement=!",
 		"--es.use-ilm=true",
 		"--es.send-get-body-as=POST",
+		"--es.use-retention-indices=true",
+		"--es.bootstrap-aliases=true",
+		"--es.route-by-trace-id=true",
 	})
 	require.NoError(t, err)
 	opts.InitFromViper(v)
 
 	primary := opts.GetPrimary()
+	assert.True(t, primary.UseRetentionIndices)
+	assert.True(t, primary.BootstrapAliases)
+	assert.True(t, primary.RouteByTraceID)
 	assert.Equal(t, "hello", primary.Username)
 	assert.Equal(t, "world", primary.Password)
 	assert.Equal(t, "/foo/bar", primary.TokenFilePath)
 	assert.Equal(t, "/foo/bar/baz", primary.PasswordFilePath)
+	assert.Equal(t, time.Minute, primary.TokenReloadInterval)
+	assert.Equal(t, "/foo/api-key", primary.APIKeyFilePath)
+	assert.Empty(t, primary.APIKey)
 	assert.Equal(t, []string{"1.1.1.1", "2.2.2.2"}, primary.Servers)
 	assert.Equal(t, []string{"cluster_one", "cluster_two"}, primary.RemoteReadClusters)
 	assert.Equal(t, 48*time.Hour, primary.MaxSpanAge)
@@ -161,6 +173,74 @@ func TestMaxDocCount(t *testing.T) {
 	}
 }
 
+func TestHedgedReads(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--es.hedged-reads.delay=200ms",
+		"--es.hedged-reads.max-attempts=3",
+		"--es.aux.hedged-reads.max-attempts=2",
+	})
+	opts.InitFromViper(v)
+
+	primary := opts.GetPrimary()
+	assert.Equal(t, 200*time.Millisecond, primary.HedgedReads.Delay)
+	assert.Equal(t, 3, primary.HedgedReads.MaxAttempts)
+	aux := opts.Get("es.aux")
+	assert.Equal(t, time.Second, aux.HedgedReads.Delay)
+	assert.Equal(t, 2, aux.HedgedReads.MaxAttempts)
+}
+
+func TestSpanLogsLimit(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--es.span-logs-limit.keep-first=10",
+		"--es.span-logs-limit.keep-last=5",
+		"--es.aux.span-logs-limit.keep-last=20",
+	})
+	opts.InitFromViper(v)
+
+	primary := opts.GetPrimary()
+	assert.Equal(t, 10, primary.SpanLogsLimit.KeepFirst)
+	assert.Equal(t, 5, primary.SpanLogsLimit.KeepLast)
+	aux := opts.Get("es.aux")
+	assert.Equal(t, 0, aux.SpanLogsLimit.KeepFirst)
+	assert.Equal(t, 20, aux.SpanLogsLimit.KeepLast)
+}
+
+func TestSearchTimeWindow(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--es.search-time-window.max=72h",
+		"--es.search-time-window.clamp=true",
+		"--es.aux.search-time-window.max=24h",
+	})
+	opts.InitFromViper(v)
+
+	primary := opts.GetPrimary()
+	assert.Equal(t, 72*time.Hour, primary.SearchTimeWindow.Max)
+	assert.True(t, primary.SearchTimeWindow.Clamp)
+	aux := opts.Get("es.aux")
+	assert.Equal(t, 24*time.Hour, aux.SearchTimeWindow.Max)
+	assert.False(t, aux.SearchTimeWindow.Clamp)
+}
+
+func TestClusterValidation(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{
+		"--es.cluster-validation.enabled=true",
+		"--es.cluster-validation.fail=true",
+		"--es.aux.cluster-validation.enabled=true",
+	})
+	opts.InitFromViper(v)
+
+	assert.Equal(t, escfg.ClusterValidationConfig{Enabled: true, Fail: true}, opts.GetPrimary().ClusterValidation)
+	assert.Equal(t, escfg.ClusterValidationConfig{Enabled: true}, opts.Get("es.aux").ClusterValidation)
+}
+
 func TestIndexDateSeparator(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -243,3 +323,52 @@ func TestIndexRollover(t *testing.T) {
 		})
 	}
 }
+
+func TestDocValuesOnly(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	err := command.ParseFlags([]string{
+		"--es.doc-values-only=logs, references,process-tags",
+	})
+	require.NoError(t, err)
+	opts.InitFromViper(v)
+
+	want := escfg.DocValuesOnly{Logs: true, References: true, ProcessTags: true}
+	assert.Equal(t, want, opts.GetPrimary().DocValuesOnly)
+	assert.Equal(t, want, opts.Get("es.aux").DocValuesOnly)
+}
+
+func TestParseDocValuesOnly(t *testing.T) {
+	d, err := escfg.ParseDocValuesOnly("")
+	require.NoError(t, err)
+	assert.Equal(t, escfg.DocValuesOnly{}, d)
+
+	d, err = escfg.ParseDocValuesOnly("tags")
+	require.NoError(t, err)
+	assert.Equal(t, escfg.DocValuesOnly{Tags: true}, d)
+
+	_, err = escfg.ParseDocValuesOnly("logs,operations")
+	require.ErrorContains(t, err, `unknown doc-values-only field group "operations"`)
+}
+
+func TestIndexTemplateOptions(t *testing.T) {
+	opts := NewOptions("es", "es.aux")
+	v, command := config.Viperize(opts.AddFlags)
+	err := command.ParseFlags([]string{
+		"--es.use-composable-templates=true",
+		"--es.component-templates=jaeger-analyzers, jaeger-lifecycle",
+		"--es.verify-index-templates=true",
+	})
+	require.NoError(t, err)
+	opts.InitFromViper(v)
+
+	primary := opts.GetPrimary()
+	assert.True(t, primary.UseComposableTemplates)
+	assert.Equal(t, []string{"jaeger-analyzers", "jaeger-lifecycle"}, primary.ComponentTemplates)
+	assert.True(t, primary.VerifyIndexTemplates)
+
+	aux := opts.Get("es.aux")
+	assert.True(t, aux.UseComposableTemplates)
+	assert.Equal(t, primary.ComponentTemplates, aux.ComponentTemplates)
+	assert.False(t, aux.VerifyIndexTemplates)
+}
@@ -46,6 +46,7 @@ func (fd FromDomain) FromDomainEmbedProcess(span *model.Span) *Span {
 func (fd FromDomain) convertSpanInternal(span *model.Span) Span {
 	tags, tagsMap := fd.convertKeyValuesString(span.Tags)
 	return Span{
+		SchemaVersion:   CurrentSchemaVersion,
 		TraceID:         TraceID(span.TraceID.String()),
 		SpanID:          SpanID(span.SpanID.String()),
 		Flags:           uint32(span.Flags),
@@ -122,9 +123,11 @@ func (FromDomain) convertLogs(logs []model.Log) []Log {
 func (fd FromDomain) convertProcess(process *model.Process) Process {
 	tags, tagsMap := fd.convertKeyValuesString(process.Tags)
 	return Process{
-		ServiceName: process.ServiceName,
-		Tags:        tags,
-		Tag:         tagsMap,
+		ServiceName:       process.ServiceName,
+		Tags:              tags,
+		Tag:               tagsMap,
+		ServiceNamespace:  process.ServiceNamespace(),
+		ServiceInstanceID: process.ServiceInstanceID(),
 	}
 }
 
@@ -86,6 +86,18 @@ func TestEmptyTags(t *testing.T) {
 	assert.Empty(t, dbSpan.Tag)
 }
 
+func TestServiceAttributes(t *testing.T) {
+	process := model.NewProcess("checkout", []model.KeyValue{
+		model.String(model.ServiceNamespaceKey, "shop"),
+		model.String(model.ServiceInstanceIDKey, "checkout-1"),
+	})
+	converter := NewFromDomain(false, nil, ":")
+	dbSpan := converter.FromDomainEmbedProcess(&model.Span{Process: process})
+	assert.Equal(t, "shop", dbSpan.Process.ServiceNamespace)
+	assert.Equal(t, "checkout-1", dbSpan.Process.ServiceInstanceID)
+	assert.Len(t, dbSpan.Process.Tags, 2, "the tags are kept")
+}
+
 func TestTagMap(t *testing.T) {
 	tags := []model.KeyValue{
 		model.String("foo", "foo"),
@@ -45,8 +45,22 @@ const (
 	BinaryType ValueType = "binary"
 )
 
+const (
+	// SchemaVersionLegacy is the schema version of the span documents written before the
+	// documents were versioned, without a schemaVersion field. Their parent span can be
+	// stored in the deprecated parentSpanID field only.
+	SchemaVersionLegacy = 1
+	// SchemaVersionReferences is the schema version of the span documents whose parent
+	// span is in the references only.
+	SchemaVersionReferences = 2
+	// CurrentSchemaVersion is the schema version of the span documents written by this release.
+	CurrentSchemaVersion = SchemaVersionReferences
+)
+
 // Span is ES database representation of the domain span.
 type Span struct {
+	// SchemaVersion is the version of the layout of the document, see Version.
+	SchemaVersion int         `json:"schemaVersion,omitempty"`
 	TraceID       TraceID     `json:"traceID"`
 	SpanID        SpanID      `json:"spanID"`
 	ParentSpanID  SpanID      `json:"parentSpanID,omitempty"` // deprecated
@@ -66,6 +80,14 @@ type Span struct {
 	Process Process        `json:"process,omitempty"`
 }
 
+// Version returns the schema version of the document, SchemaVersionLegacy if it is not versioned.
+func (s *Span) Version() int {
+	if s.SchemaVersion == 0 {
+		return SchemaVersionLegacy
+	}
+	return s.SchemaVersion
+}
+
 // Reference is a reference from one span to another
 type Reference struct {
 	RefType ReferenceType `json:"refType"`
@@ -77,6 +99,10 @@ type Reference struct {
 type Process struct {
 	ServiceName string     `json:"serviceName"`
 	Tags        []KeyValue `json:"tags"`
+	// ServiceNamespace and ServiceInstanceID are copies of the service.namespace and
+	// service.instance.id tags, indexed as keywords to search the services by them.
+	ServiceNamespace  string `json:"serviceNamespace,omitempty"`
+	ServiceInstanceID string `json:"serviceInstanceID,omitempty"`
 	// Alternative representation of tags for better kibana support
 	Tag map[string]any `json:"tag,omitempty"`
 }
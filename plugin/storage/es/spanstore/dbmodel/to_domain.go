@@ -45,7 +45,11 @@ func (td ToDomain) ReplaceDotReplacement(k string) string {
 	return strings.ReplaceAll(k, td.tagDotReplacement, ".")
 }
 
-// SpanToDomain converts db span into model Span
+// SpanToDomain converts db span into model Span, according to the schema version of the document,
+// so that the documents written before and after a schema migration can be read side by side.
+// The documents of a newer schema version than CurrentSchemaVersion are read as documents of
+// the current version, since the schema only evolves in ways the previous release can still read,
+// e.g. during a rolling upgrade.
 func (td ToDomain) SpanToDomain(dbSpan *Span) (*model.Span, error) {
 	tags, err := td.convertKeyValues(dbSpan.Tags)
 	if err != nil {
@@ -73,7 +77,7 @@ func (td ToDomain) SpanToDomain(dbSpan *Span) (*model.Span, error) {
 		return nil, err
 	}
 
-	if dbSpan.ParentSpanID != "" {
+	if dbSpan.Version() == SchemaVersionLegacy && dbSpan.ParentSpanID != "" {
 		parentSpanID, err := model.SpanIDFromString(string(dbSpan.ParentSpanID))
 		if err != nil {
 			return nil, err
@@ -43,6 +43,7 @@ func testToDomain(t *testing.T, testParentSpanID bool) {
 		span, err := loadESSpanFixture(i)
 		require.NoError(t, err)
 		if testParentSpanID {
+			span.SchemaVersion = SchemaVersionLegacy
 			span.ParentSpanID = "3"
 		}
 
@@ -278,10 +279,35 @@ func TestFailureBadSpanID(t *testing.T) {
 func TestFailureBadParentSpanID(t *testing.T) {
 	badParentSpanIDESSpan, err := loadESSpanFixture(1)
 	require.NoError(t, err)
+	badParentSpanIDESSpan.SchemaVersion = SchemaVersionLegacy
 	badParentSpanIDESSpan.ParentSpanID = "zz"
 	failingSpanTransformAnyMsg(t, &badParentSpanIDESSpan)
 }
 
+func TestSchemaVersions(t *testing.T) {
+	legacySpan := Span{
+		TraceID:      "1",
+		SpanID:       "2",
+		ParentSpanID: "3",
+		Process:      Process{ServiceName: "service"},
+	}
+	assert.Equal(t, SchemaVersionLegacy, legacySpan.Version())
+	domainSpan, err := NewToDomain(":").SpanToDomain(&legacySpan)
+	require.NoError(t, err)
+	assert.Equal(t, []model.SpanRef{
+		model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(3)),
+	}, domainSpan.References, "the parent span of the legacy documents is read from parentSpanID")
+
+	for _, version := range []int{SchemaVersionReferences, CurrentSchemaVersion + 1} {
+		span := legacySpan
+		span.SchemaVersion = version
+		assert.Equal(t, version, span.Version())
+		domainSpan, err := NewToDomain(":").SpanToDomain(&span)
+		require.NoError(t, err)
+		assert.Empty(t, domainSpan.References, "the parent span is only in the references since version 2")
+	}
+}
+
 func TestFailureBadSpanFieldTag(t *testing.T) {
 	badParentSpanIDESSpan, err := loadESSpanFixture(1)
 	require.NoError(t, err)
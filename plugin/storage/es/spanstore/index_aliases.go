@@ -0,0 +1,112 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	readAliasSuffix  = "read"
+	writeAliasSuffix = "write"
+	// initialIndexSuffix is the suffix of the first index of a family of rolled over indices,
+	// the same as the one created by es-rollover init.
+	initialIndexSuffix = "000001"
+)
+
+// getAliasedIndices returns the name prefixes of the index families written with read and write aliases,
+// e.g. jaeger-span- for the jaeger-span-read and jaeger-span-write aliases of the jaeger-span-000001 index.
+func getAliasedIndices(archive, useReadWriteAliases bool, prefix string) []string {
+	if !useReadWriteAliases {
+		return nil
+	}
+	if prefix != "" {
+		prefix += indexPrefixSeparator
+	}
+	if archive {
+		return []string{archiveIndex(prefix+spanIndex, archiveIndexSuffix+"-")}
+	}
+	return []string{prefix + spanIndex, prefix + serviceIndex}
+}
+
+// EnsureAliases verifies that the read and write aliases of the indices are present when
+// the writer uses aliases, as otherwise Elasticsearch would create a regular index named
+// after the write alias on the first write. When bootstrap is true, the missing aliases are
+// created along with the initial index they point to, like es-rollover init does; the write
+// alias is the write index of the initial index when useILM is true.
+func (s *SpanWriter) EnsureAliases(ctx context.Context, bootstrap, useILM bool) error {
+	var errs []error
+	for _, indexPrefix := range s.aliasedIndices {
+		if err := s.ensureAliases(ctx, indexPrefix, bootstrap, useILM); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SpanWriter) ensureAliases(ctx context.Context, indexPrefix string, bootstrap, useILM bool) error {
+	readAlias, writeAlias := indexPrefix+readAliasSuffix, indexPrefix+writeAliasSuffix
+	readExists, err := s.client().AliasExists(readAlias).Do(ctx)
+	if err != nil {
+		return err
+	}
+	writeExists, err := s.client().AliasExists(writeAlias).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if readExists && writeExists {
+		return nil
+	}
+	var missing []string
+	if !readExists {
+		missing = append(missing, readAlias)
+	}
+	if !writeExists {
+		missing = append(missing, writeAlias)
+	}
+	// an index named after the write alias is left by writes made before the aliases were created
+	for _, alias := range missing {
+		isIndex, err := s.client().IndexExists(alias).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if isIndex {
+			return fmt.Errorf("%s is an index instead of an alias, it must be deleted or reindexed before the aliases are created", alias)
+		}
+	}
+	if !bootstrap {
+		return fmt.Errorf("index aliases %s do not exist, create them with es-rollover init", strings.Join(missing, ", "))
+	}
+	if len(missing) == 1 {
+		// the initial index exists already, the alias must be repaired by hand
+		return fmt.Errorf("cannot bootstrap index alias %s as the other alias of %s exists", missing[0], indexPrefix)
+	}
+
+	index := indexPrefix + initialIndexSuffix
+	body, err := json.Marshal(map[string]any{
+		"aliases": map[string]any{
+			readAlias:  map[string]any{},
+			writeAlias: map[string]any{"is_write_index": useILM},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.client().CreateIndex(index).Body(string(body)).Do(ctx); err != nil {
+		// another instance may have bootstrapped the aliases concurrently
+		if exists, _ := s.client().AliasExists(writeAlias).Do(ctx); exists {
+			return nil
+		}
+		return fmt.Errorf("failed to bootstrap index %s with aliases %s and %s: %w", index, readAlias, writeAlias, err)
+	}
+	s.logger.Info("Bootstrapped index aliases",
+		zap.String("index", index), zap.String("read_alias", readAlias), zap.String("write_alias", writeAlias))
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+)
+
+func existsService(exists bool, err error) *mocks.IndicesExistsService {
+	s := &mocks.IndicesExistsService{}
+	s.On("Do", mock.Anything).Return(exists, err)
+	return s
+}
+
+func TestGetAliasedIndices(t *testing.T) {
+	assert.Nil(t, getAliasedIndices(false, false, ""))
+	assert.Equal(t, []string{"jaeger-span-", "jaeger-service-"}, getAliasedIndices(false, true, ""))
+	assert.Equal(t, []string{"foo-jaeger-span-", "foo-jaeger-service-"}, getAliasedIndices(false, true, "foo"))
+	assert.Equal(t, []string{"jaeger-span-archive-"}, getAliasedIndices(true, true, ""))
+}
+
+func TestSpanWriterEnsureAliases(t *testing.T) {
+	testCases := []struct {
+		name          string
+		archive       bool
+		bootstrap     bool
+		useILM        bool
+		aliases       map[string]bool
+		indices       map[string]bool
+		createError   error
+		expectedBody  map[string]string
+		expectedError string
+	}{
+		{
+			name:    "aliases exist",
+			aliases: map[string]bool{"jaeger-span-read": true, "jaeger-span-write": true, "jaeger-service-read": true, "jaeger-service-write": true},
+		},
+		{
+			name:          "aliases missing",
+			aliases:       map[string]bool{"jaeger-span-read": true, "jaeger-service-read": true, "jaeger-service-write": true},
+			expectedError: "index aliases jaeger-span-write do not exist, create them with es-rollover init",
+		},
+		{
+			name:          "write alias is an index",
+			aliases:       map[string]bool{"jaeger-span-read": true, "jaeger-service-read": true, "jaeger-service-write": true},
+			indices:       map[string]bool{"jaeger-span-write": true},
+			bootstrap:     true,
+			expectedError: "jaeger-span-write is an index instead of an alias, it must be deleted or reindexed before the aliases are created",
+		},
+		{
+			name:          "one alias missing",
+			aliases:       map[string]bool{"jaeger-span-read": true, "jaeger-service-read": true, "jaeger-service-write": true},
+			bootstrap:     true,
+			expectedError: "cannot bootstrap index alias jaeger-span-write as the other alias of jaeger-span- exists",
+		},
+		{
+			name:      "bootstrap",
+			bootstrap: true,
+			expectedBody: map[string]string{
+				"jaeger-span-000001":    `{"aliases":{"jaeger-span-read":{},"jaeger-span-write":{"is_write_index":false}}}`,
+				"jaeger-service-000001": `{"aliases":{"jaeger-service-read":{},"jaeger-service-write":{"is_write_index":false}}}`,
+			},
+		},
+		{
+			name:      "bootstrap archive with ILM",
+			archive:   true,
+			bootstrap: true,
+			useILM:    true,
+			expectedBody: map[string]string{
+				"jaeger-span-archive-000001": `{"aliases":{"jaeger-span-archive-read":{},"jaeger-span-archive-write":{"is_write_index":true}}}`,
+			},
+		},
+		{
+			name:          "bootstrap error",
+			archive:       true,
+			bootstrap:     true,
+			createError:   errors.New("create error"),
+			expectedBody:  map[string]string{"jaeger-span-archive-000001": `{"aliases":{"jaeger-span-archive-read":{},"jaeger-span-archive-write":{"is_write_index":false}}}`},
+			expectedError: "failed to bootstrap index jaeger-span-archive-000001 with aliases jaeger-span-archive-read and jaeger-span-archive-write: create error",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			client := &mocks.Client{}
+			client.On("AliasExists", mock.Anything).Return(func(alias string) es.IndicesExistsService {
+				return existsService(test.aliases[alias], nil)
+			})
+			client.On("IndexExists", mock.Anything).Return(func(index string) es.IndicesExistsService {
+				return existsService(test.indices[index], nil)
+			})
+			created := map[string]string{}
+			client.On("CreateIndex", mock.Anything).Return(func(index string) es.IndicesCreateService {
+				s := &mocks.IndicesCreateService{}
+				s.On("Body", mock.Anything).Return(func(body string) es.IndicesCreateService {
+					created[index] = body
+					return s
+				})
+				s.On("Do", mock.Anything).Return(nil, test.createError)
+				return s
+			})
+			w := NewSpanWriter(SpanWriterParams{
+				Client:              func() es.Client { return client },
+				Logger:              zap.NewNop(),
+				MetricsFactory:      metricstest.NewFactory(0),
+				Archive:             test.archive,
+				UseReadWriteAliases: true,
+			})
+			err := w.EnsureAliases(context.Background(), test.bootstrap, test.useILM)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+			for index, body := range test.expectedBody {
+				assert.JSONEq(t, body, created[index])
+			}
+			assert.Len(t, created, len(test.expectedBody))
+		})
+	}
+}
+
+func TestSpanWriterEnsureAliasesConcurrentBootstrap(t *testing.T) {
+	client := &mocks.Client{}
+	// the aliases are created by another instance between the check and the index creation
+	client.On("AliasExists", mock.Anything).Return(existsService(false, nil)).Times(2)
+	client.On("AliasExists", mock.Anything).Return(existsService(true, nil))
+	client.On("IndexExists", mock.Anything).Return(existsService(false, nil))
+	createService := &mocks.IndicesCreateService{}
+	createService.On("Body", mock.Anything).Return(createService)
+	createService.On("Do", mock.Anything).Return(nil, errors.New("resource_already_exists_exception"))
+	client.On("CreateIndex", mock.Anything).Return(createService)
+
+	w := NewSpanWriter(SpanWriterParams{
+		Client:              func() es.Client { return client },
+		Logger:              zap.NewNop(),
+		MetricsFactory:      metricstest.NewFactory(0),
+		Archive:             true,
+		UseReadWriteAliases: true,
+	})
+	require.NoError(t, w.EnsureAliases(context.Background(), true, false))
+}
+
+func TestSpanWriterEnsureAliasesErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		readError   error
+		writeError  error
+		existsError error
+	}{
+		{name: "read alias", readError: errors.New("alias error")},
+		{name: "write alias", writeError: errors.New("alias error")},
+		{name: "index", existsError: errors.New("alias error")},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			client := &mocks.Client{}
+			client.On("AliasExists", "jaeger-span-archive-read").Return(existsService(false, test.readError))
+			client.On("AliasExists", "jaeger-span-archive-write").Return(existsService(false, test.writeError))
+			client.On("IndexExists", mock.Anything).Return(existsService(false, test.existsError))
+			w := NewSpanWriter(SpanWriterParams{
+				Client:              func() es.Client { return client },
+				Logger:              zap.NewNop(),
+				MetricsFactory:      metricstest.NewFactory(0),
+				Archive:             true,
+				UseReadWriteAliases: true,
+			})
+			require.EqualError(t, w.EnsureAliases(context.Background(), true, false), "alias error")
+		})
+	}
+}
+
+func TestSpanWriterEnsureAliasesDisabled(t *testing.T) {
+	w := NewSpanWriter(SpanWriterParams{
+		Client:         func() es.Client { return &mocks.Client{} },
+		Logger:         zap.NewNop(),
+		MetricsFactory: metricstest.NewFactory(0),
+	})
+	require.NoError(t, w.EnsureAliases(context.Background(), true, false))
+}
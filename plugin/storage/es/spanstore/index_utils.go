@@ -15,9 +15,15 @@
 package spanstore
 
 import (
+	"fmt"
 	"time"
 )
 
+const (
+	retentionInfix = "retention-"
+	day            = 24 * time.Hour
+)
+
 // returns index name with date
 func indexWithDate(indexPrefix, indexDateLayout string, date time.Time) string {
 	spanDate := date.UTC().Format(indexDateLayout)
@@ -28,3 +34,9 @@ func indexWithDate(indexPrefix, indexDateLayout string, date time.Time) string {
 func archiveIndex(indexPrefix, archiveSuffix string) string {
 	return indexPrefix + archiveSuffix
 }
+
+// returns the prefix of the span index family for the retention, rounded up to whole days
+func retentionIndexPrefix(spanIndexPrefix string, retention time.Duration) string {
+	days := (retention + day - 1) / day
+	return fmt.Sprintf("%s%s%dd-", spanIndexPrefix, retentionInfix, days)
+}
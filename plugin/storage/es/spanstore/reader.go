@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/olivere/elastic"
@@ -31,25 +32,31 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
-	spanIndex               = "jaeger-span-"
-	serviceIndex            = "jaeger-service-"
-	archiveIndexSuffix      = "archive"
-	archiveReadIndexSuffix  = archiveIndexSuffix + "-read"
-	archiveWriteIndexSuffix = archiveIndexSuffix + "-write"
-	traceIDAggregation      = "traceIDs"
-	indexPrefixSeparator    = "-"
+	spanIndex                 = "jaeger-span-"
+	serviceIndex              = "jaeger-service-"
+	archiveIndexSuffix        = "archive"
+	archiveReadIndexSuffix    = archiveIndexSuffix + "-read"
+	archiveWriteIndexSuffix   = archiveIndexSuffix + "-write"
+	traceIDAggregation        = "traceIDs"
+	operationStatsAggregation = "operation_stats"
+	lastSeenAggregation       = "last_seen"
+	indexPrefixSeparator      = "-"
 
 	traceIDField           = "traceID"
+	spanIDField            = "spanID"
 	durationField          = "duration"
 	startTimeField         = "startTime"
 	startTimeMillisField   = "startTimeMillis"
 	serviceNameField       = "process.serviceName"
+	serviceNamespaceField  = "process.serviceNamespace"
+	serviceInstanceIDField = "process.serviceInstanceID"
 	operationNameField     = "operationName"
 	objectTagsField        = "tag"
 	objectProcessTagsField = "process.tag"
@@ -83,6 +90,9 @@ var (
 	// ErrUnableToFindTraceIDAggregation occurs when an aggregation query for TraceIDs fail.
 	ErrUnableToFindTraceIDAggregation = errors.New("could not find aggregation of traceIDs")
 
+	// ErrSearchTimeWindowTooLong occurs when the time range of a trace search exceeds the configured maximum
+	ErrSearchTimeWindowTooLong = errors.New("the search time window is too long")
+
 	defaultMaxDuration = model.DurationAsMicroseconds(time.Hour * 24)
 
 	objectTagFieldList = []string{objectTagsField, objectProcessTagsField}
@@ -108,8 +118,12 @@ type SpanReader struct {
 	sourceFn                      sourceFn
 	maxDocCount                   int
 	useReadWriteAliases           bool
+	routeByTraceID                bool
+	nestedTagFields               []string
+	searchTimeWindow              config.SearchTimeWindowConfig
 	logger                        *zap.Logger
 	tracer                        trace.Tracer
+	searchMetrics                 map[string]*searchMetrics
 }
 
 // SpanReaderParams holds constructor params for NewSpanReader
@@ -125,7 +139,11 @@ type SpanReaderParams struct {
 	TagDotReplacement             string
 	Archive                       bool
 	UseReadWriteAliases           bool
+	UseRetentionIndices           bool
+	RouteByTraceID                bool
 	RemoteReadClusters            []string
+	DocValuesOnly                 config.DocValuesOnly
+	SearchTimeWindow              config.SearchTimeWindowConfig
 	MetricsFactory                metrics.Factory
 	Logger                        *zap.Logger
 	Tracer                        trace.Tracer
@@ -139,6 +157,10 @@ func NewSpanReader(p SpanReaderParams) *SpanReader {
 	if p.UseReadWriteAliases {
 		maxSpanAge = rolloverMaxSpanAge
 	}
+	metricsFactory := p.MetricsFactory
+	if metricsFactory == nil {
+		metricsFactory = metrics.NullFactory
+	}
 	return &SpanReader{
 		client:                        p.Client,
 		maxSpanAge:                    maxSpanAge,
@@ -150,20 +172,39 @@ func NewSpanReader(p SpanReaderParams) *SpanReader {
 		spanIndexRolloverFrequency:    p.SpanIndexRolloverFrequency,
 		serviceIndexRolloverFrequency: p.SpanIndexRolloverFrequency,
 		spanConverter:                 dbmodel.NewToDomain(p.TagDotReplacement),
-		timeRangeIndices:              getTimeRangeIndexFn(p.Archive, p.UseReadWriteAliases, p.RemoteReadClusters),
+		timeRangeIndices:              getTimeRangeIndexFn(p.Archive, p.UseReadWriteAliases, p.UseRetentionIndices, p.RemoteReadClusters),
 		sourceFn:                      getSourceFn(p.Archive, p.MaxDocCount),
 		maxDocCount:                   p.MaxDocCount,
 		useReadWriteAliases:           p.UseReadWriteAliases,
+		routeByTraceID:                p.RouteByTraceID,
+		nestedTagFields:               getNestedTagFields(p.DocValuesOnly),
+		searchTimeWindow:              p.SearchTimeWindow,
 		logger:                        p.Logger,
 		tracer:                        p.Tracer,
+		searchMetrics:                 newSearchMetrics(metricsFactory),
+	}
+}
+
+// getNestedTagFields returns the nested tag fields that can be searched.
+func getNestedTagFields(docValuesOnly config.DocValuesOnly) []string {
+	fields := make([]string, 0, len(nestedTagFieldList))
+	for _, field := range nestedTagFieldList {
+		switch {
+		case field == nestedTagsField && docValuesOnly.Tags,
+			field == nestedProcessTagsField && docValuesOnly.ProcessTags,
+			field == nestedLogFieldsField && docValuesOnly.Logs:
+			continue
+		}
+		fields = append(fields, field)
 	}
+	return fields
 }
 
 type timeRangeIndexFn func(indexName string, indexDateLayout string, startTime time.Time, endTime time.Time, reduceDuration time.Duration) []string
 
 type sourceFn func(query elastic.Query, nextTime uint64) *elastic.SearchSource
 
-func getTimeRangeIndexFn(archive, useReadWriteAliases bool, remoteReadClusters []string) timeRangeIndexFn {
+func getTimeRangeIndexFn(archive, useReadWriteAliases, useRetentionIndices bool, remoteReadClusters []string) timeRangeIndexFn {
 	if archive {
 		var archiveSuffix string
 		if useReadWriteAliases {
@@ -180,9 +221,28 @@ func getTimeRangeIndexFn(archive, useReadWriteAliases bool, remoteReadClusters [
 			return []string{indexPrefix + "read"}
 		}, remoteReadClusters)
 	}
+	if useRetentionIndices {
+		return addRemoteReadClusters(addRetentionIndices(timeRangeIndices), remoteReadClusters)
+	}
 	return addRemoteReadClusters(timeRangeIndices, remoteReadClusters)
 }
 
+// addRetentionIndices adds, for each daily span index, a pattern matching the indices of the same day
+// holding the spans that requested a retention, e.g. jaeger-span-retention-*-2024-05-01.
+func addRetentionIndices(fn timeRangeIndexFn) timeRangeIndexFn {
+	return func(indexPrefix string, indexDateLayout string, startTime time.Time, endTime time.Time, reduceDuration time.Duration) []string {
+		jaegerIndices := fn(indexPrefix, indexDateLayout, startTime, endTime, reduceDuration)
+		if !strings.HasSuffix(indexPrefix, spanIndex) {
+			return jaegerIndices
+		}
+		for _, jaegerIndex := range jaegerIndices {
+			date := strings.TrimPrefix(jaegerIndex, indexPrefix)
+			jaegerIndices = append(jaegerIndices, indexPrefix+retentionInfix+"*-"+date)
+		}
+		return jaegerIndices
+	}
+}
+
 // Add a remote cluster prefix for each cluster and for each index and add it to the list of original indices.
 // Elasticsearch cross cluster api example GET /twitter,cluster_one:twitter,cluster_two:twitter/_search.
 func addRemoteReadClusters(fn timeRangeIndexFn, remoteReadClusters []string) timeRangeIndexFn {
@@ -239,12 +299,13 @@ func indexNames(prefix, index string) string {
 	return index
 }
 
-// GetTrace takes a traceID and returns a Trace associated with that traceID
-func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+// GetTrace takes a traceID and returns a Trace associated with that traceID.
+// If the query carries a time window, only the indices covering that window are searched.
+func (s *SpanReader) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
 	ctx, span := s.tracer.Start(ctx, "GetTrace")
 	defer span.End()
-	currentTime := time.Now()
-	traces, err := s.multiRead(ctx, []model.TraceID{traceID}, currentTime.Add(-s.maxSpanAge), currentTime)
+	startTime, endTime := s.traceTimeWindow(query, time.Now())
+	traces, err := s.multiRead(ctx, []model.TraceID{query.TraceID}, startTime, endTime)
 	if err != nil {
 		return nil, es.DetailedError(err)
 	}
@@ -254,6 +315,119 @@ func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return traces[0], nil
 }
 
+// TraceExists counts the span documents of the trace, stopping at the first one found,
+// in the indices searched by GetTrace.
+func (s *SpanReader) TraceExists(ctx context.Context, query spanstore.GetTraceParameters) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "TraceExists")
+	defer span.End()
+	startTime, endTime := s.traceTimeWindow(query, time.Now())
+	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
+	count, err := s.client().Count(indices...).
+		Query(buildTraceByIDQuery(query.TraceID)).
+		IgnoreUnavailable(true).
+		TerminateAfter(1).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		logErrorToSpan(span, err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetRawSpans returns the documents stored for the given span, as found in the span indices.
+func (s *SpanReader) GetRawSpans(ctx context.Context, query spanstore.GetTraceParameters, spanID model.SpanID) ([]spanstore.RawSpan, error) {
+	ctx, span := s.tracer.Start(ctx, "GetRawSpans")
+	defer span.End()
+	startTime, endTime := s.traceTimeWindow(query, time.Now())
+	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
+	boolQuery := elastic.NewBoolQuery().
+		Must(buildTraceByIDQuery(query.TraceID)).
+		Must(elastic.NewTermQuery(spanIDField, spanID.String()))
+	searchResult, err := s.client().Search(indices...).
+		Size(s.maxDocCount).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		logErrorToSpan(span, err)
+		return nil, err
+	}
+	var stats searchStats
+	stats.addSearchResult(searchResult)
+	stats.record(s.searchMetrics[rawSpansQuery], span, indices)
+	rawSpans := []spanstore.RawSpan{}
+	if searchResult.Hits == nil {
+		return rawSpans, nil
+	}
+	for _, hit := range searchResult.Hits.Hits {
+		if hit.Source == nil {
+			continue
+		}
+		rawSpans = append(rawSpans, spanstore.RawSpan{
+			Location: hit.Index,
+			Document: *hit.Source,
+		})
+	}
+	return rawSpans, nil
+}
+
+// GetSpans implements spanstore.SpanGetter#GetSpans with a single search of the spans
+// matching one of the trace and span ID pairs in the span indices of the lookback.
+func (s *SpanReader) GetSpans(ctx context.Context, ids []spanstore.TraceSpanID) ([]*model.Span, error) {
+	ctx, span := s.tracer.Start(ctx, "GetSpans")
+	defer span.End()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	currentTime := time.Now()
+	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.spanIndexRolloverFrequency)
+	boolQuery := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+	for _, id := range ids {
+		boolQuery.Should(elastic.NewBoolQuery().
+			Must(buildTraceByIDQuery(id.TraceID)).
+			Must(elastic.NewTermQuery(spanIDField, id.SpanID.String())))
+	}
+	searchResult, err := s.client().Search(indices...).
+		Size(s.maxDocCount).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		logErrorToSpan(span, err)
+		return nil, err
+	}
+	var stats searchStats
+	stats.addSearchResult(searchResult)
+	stats.record(s.searchMetrics[getSpansQuery], span, indices)
+	if searchResult.Hits == nil {
+		return nil, nil
+	}
+	return s.collectSpans(searchResult.Hits.Hits)
+}
+
+// traceTimeWindow resolves the time range searched by GetTrace. Without hints it is
+// [now - maxSpanAge, now]. When only one bound is given, the other one is derived
+// from it using maxSpanAge. An inverted range falls back to the default one, since
+// walking the indices backwards from endTime would never reach startTime.
+func (s *SpanReader) traceTimeWindow(query spanstore.GetTraceParameters, currentTime time.Time) (time.Time, time.Time) {
+	startTime, endTime := query.StartTime, query.EndTime
+	switch {
+	case startTime.IsZero() && endTime.IsZero():
+		return currentTime.Add(-s.maxSpanAge), currentTime
+	case startTime.IsZero():
+		startTime = endTime.Add(-s.maxSpanAge)
+	case endTime.IsZero():
+		endTime = startTime.Add(s.maxSpanAge)
+	}
+	if endTime.Before(startTime) {
+		return currentTime.Add(-s.maxSpanAge), currentTime
+	}
+	return startTime, endTime
+}
+
 func (s *SpanReader) collectSpans(esSpansRaw []*elastic.SearchHit) ([]*model.Span, error) {
 	spans := make([]*model.Span, len(esSpansRaw))
 
@@ -288,9 +462,23 @@ func (*SpanReader) unmarshalJSONSpan(esSpanRaw *elastic.SearchHit) (*dbmodel.Spa
 func (s *SpanReader) GetServices(ctx context.Context) ([]string, error) {
 	ctx, span := s.tracer.Start(ctx, "GetService")
 	defer span.End()
-	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
-	return s.serviceOperationStorage.getServices(ctx, jaegerIndices, s.maxDocCount)
+	return s.serviceOperationStorage.getServices(ctx, s.serviceIndices(), "", s.maxDocCount)
+}
+
+// SearchServices implements spanstore.NameSearcher#SearchServices. Prefixes are searched
+// with a match_phrase_prefix query, which is case-sensitive on the keyword fields of the
+// services, and fuzzy searches filter the result of GetServices.
+func (s *SpanReader) SearchServices(ctx context.Context, search spanstore.NameSearchParameters) ([]string, error) {
+	if search.Mode != spanstore.NameMatchPrefix {
+		services, err := s.GetServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.FilterNames(search, services), nil
+	}
+	ctx, span := s.tracer.Start(ctx, "SearchServices")
+	defer span.End()
+	return s.serviceOperationStorage.getServices(ctx, s.serviceIndices(), search.Text, s.maxDocCount)
 }
 
 // GetOperations returns all operations for a specific service traced by Jaeger
@@ -300,9 +488,82 @@ func (s *SpanReader) GetOperations(
 ) ([]spanstore.Operation, error) {
 	ctx, span := s.tracer.Start(ctx, "GetOperations")
 	defer span.End()
+	operations, err := s.getOperations(ctx, query.ServiceName, "")
+	if err != nil || !query.WithStats {
+		return operations, err
+	}
+	return s.addOperationStats(ctx, query.ServiceName, operations)
+}
+
+// addOperationStats sets the LastSeen and SpanCount of the operations from the spans of the service
+// within the lookback, with a terms aggregation of their operation names and the latest start time
+// of each. The operations without spans in the lookback keep a zero LastSeen and SpanCount.
+func (s *SpanReader) addOperationStats(ctx context.Context, service string, operations []spanstore.Operation) ([]spanstore.Operation, error) {
 	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
-	operations, err := s.serviceOperationStorage.getOperations(ctx, jaegerIndices, query.ServiceName, s.maxDocCount)
+	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.spanIndexRolloverFrequency)
+	aggregation := elastic.NewTermsAggregation().
+		Field(operationNameField).
+		Size(s.maxDocCount).
+		SubAggregation(lastSeenAggregation, elastic.NewMaxAggregation().Field(startTimeField))
+	result, err := s.client().Search(indices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		Query(s.buildServiceNameQuery(service)).
+		IgnoreUnavailable(true).
+		Aggregation(operationStatsAggregation, aggregation).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("search operation stats failed: %w", es.DetailedError(err))
+	}
+	if result.Aggregations == nil {
+		return operations, nil
+	}
+	terms, found := result.Aggregations.Terms(operationStatsAggregation)
+	if !found {
+		return nil, errors.New("could not find aggregation of " + operationStatsAggregation)
+	}
+	stats := make(map[string]spanstore.Operation, len(terms.Buckets))
+	for _, bucket := range terms.Buckets {
+		name, ok := bucket.Key.(string)
+		if !ok {
+			return nil, errors.New("non-string key found in aggregation")
+		}
+		operation := spanstore.Operation{SpanCount: bucket.DocCount}
+		if lastSeen, ok := bucket.Max(lastSeenAggregation); ok && lastSeen.Value != nil {
+			operation.LastSeen = model.EpochMicrosecondsAsTime(uint64(*lastSeen.Value))
+		}
+		stats[name] = operation
+	}
+	for i, operation := range operations {
+		operations[i].LastSeen, operations[i].SpanCount = stats[operation.Name].LastSeen, stats[operation.Name].SpanCount
+	}
+	return operations, nil
+}
+
+// SearchOperations implements spanstore.NameSearcher#SearchOperations, like SearchServices.
+func (s *SpanReader) SearchOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+	search spanstore.NameSearchParameters,
+) ([]spanstore.Operation, error) {
+	if search.Mode != spanstore.NameMatchPrefix {
+		operations, err := s.GetOperations(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.FilterOperations(search, operations), nil
+	}
+	ctx, span := s.tracer.Start(ctx, "SearchOperations")
+	defer span.End()
+	return s.getOperations(ctx, query.ServiceName, search.Text)
+}
+
+func (s *SpanReader) serviceIndices() []string {
+	currentTime := time.Now()
+	return s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
+}
+
+func (s *SpanReader) getOperations(ctx context.Context, service string, prefix string) ([]spanstore.Operation, error) {
+	operations, err := s.serviceOperationStorage.getOperations(ctx, s.serviceIndices(), service, prefix, s.maxDocCount)
 	if err != nil {
 		return nil, err
 	}
@@ -335,11 +596,24 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	ctx, span := s.tracer.Start(ctx, "FindTraces")
 	defer span.End()
 
+	traceQuery, warning, err := s.limitSearchTimeWindow(traceQuery)
+	if err != nil {
+		return nil, err
+	}
 	uniqueTraceIDs, err := s.FindTraceIDs(ctx, traceQuery)
 	if err != nil {
 		return nil, es.DetailedError(err)
 	}
-	return s.multiRead(ctx, uniqueTraceIDs, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
+	traces, err := s.multiRead(ctx, uniqueTraceIDs, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		for _, trace := range traces {
+			trace.Warnings = append(trace.Warnings, warning)
+		}
+	}
+	return traces, nil
 }
 
 // FindTraceIDs retrieves traces IDs that match the traceQuery
@@ -347,7 +621,8 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	ctx, span := s.tracer.Start(ctx, "FindTraceIDs")
 	defer span.End()
 
-	if err := validateQuery(traceQuery); err != nil {
+	traceQuery, _, err := s.limitSearchTimeWindow(traceQuery)
+	if err != nil {
 		return nil, err
 	}
 	if traceQuery.NumTraces == 0 {
@@ -362,6 +637,29 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	return convertTraceIDsStringsToModels(esTraceIDs)
 }
 
+// limitSearchTimeWindow validates the query and checks that its time range does not exceed
+// the configured maximum. With clamping, a longer time range is narrowed to its most recent part
+// in a copy of the query, and a warning for the found traces is returned.
+func (s *SpanReader) limitSearchTimeWindow(traceQuery *spanstore.TraceQueryParameters) (*spanstore.TraceQueryParameters, string, error) {
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, "", err
+	}
+	maxWindow := s.searchTimeWindow.Max
+	window := traceQuery.StartTimeMax.Sub(traceQuery.StartTimeMin)
+	if maxWindow <= 0 || window <= maxWindow {
+		return traceQuery, "", nil
+	}
+	if !s.searchTimeWindow.Clamp {
+		return nil, "", fmt.Errorf("%w: %v is longer than the maximum of %v", ErrSearchTimeWindowTooLong, window, maxWindow)
+	}
+	clamped := *traceQuery
+	clamped.StartTimeMin = traceQuery.StartTimeMax.Add(-maxWindow)
+	s.logger.Debug("Narrowing the search time window",
+		zap.Duration("window", window), zap.Duration("max", maxWindow))
+	warning := fmt.Sprintf("the search time window of %v was narrowed to its last %v, traces started earlier are not searched", window, maxWindow)
+	return &clamped, warning, nil
+}
+
 func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, startTime, endTime time.Time) ([]*model.Trace, error) {
 	ctx, childSpan := s.tracer.Start(ctx, "multiRead")
 	defer childSpan.End()
@@ -381,10 +679,51 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 	// Add an hour in both directions so that traces that straddle two indexes are retrieved.
 	// i.e starts in one and ends in another.
 	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
+	tracesMap := make(map[model.TraceID]*model.Trace)
+	var stats searchStats
+	defer func() { stats.record(s.searchMetrics[multiReadQuery], childSpan, indices) }()
+	err := s.multiSearch(ctx, childSpan, traceIDs, indices, startTime, endTime, s.routeByTraceID, tracesMap, &stats)
+	if err != nil {
+		return nil, err
+	}
+	if s.routeByTraceID {
+		// the spans written before the routing by trace ID was enabled can be in any shard
+		var notFound []model.TraceID
+		for _, traceID := range traceIDs {
+			if _, ok := tracesMap[traceID]; !ok {
+				notFound = append(notFound, traceID)
+			}
+		}
+		if len(notFound) > 0 {
+			err := s.multiSearch(ctx, childSpan, notFound, indices, startTime, endTime, false, tracesMap, &stats)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var traces []*model.Trace
+	for _, trace := range tracesMap {
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+// multiSearch adds the spans of the traces found in the indices to tracesMap, fetching the spans of
+// each trace in pages of the max doc count. With routed, only the shard the trace ID is routed to is searched.
+func (s *SpanReader) multiSearch(
+	ctx context.Context,
+	childSpan trace.Span,
+	traceIDs []model.TraceID,
+	indices []string,
+	startTime, endTime time.Time,
+	routed bool,
+	tracesMap map[model.TraceID]*model.Trace,
+	stats *searchStats,
+) error {
 	nextTime := model.TimeAsEpochMicroseconds(startTime.Add(-time.Hour))
 	searchAfterTime := make(map[model.TraceID]uint64)
 	totalDocumentsFetched := make(map[model.TraceID]int)
-	tracesMap := make(map[model.TraceID]*model.Trace)
 	for {
 		if len(traceIDs) == 0 {
 			break
@@ -407,6 +746,9 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 			searchRequests[i] = elastic.NewSearchRequest().
 				IgnoreUnavailable(true).
 				Source(s)
+			if routed {
+				searchRequests[i] = searchRequests[i].Routing(traceID.String())
+			}
 		}
 		// set traceIDs to empty
 		traceIDs = nil
@@ -414,8 +756,9 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 		if err != nil {
 			err = es.DetailedError(err)
 			logErrorToSpan(childSpan, err)
-			return nil, err
+			return err
 		}
+		stats.addMultiSearchResult(results)
 
 		if results.Responses == nil || len(results.Responses) == 0 {
 			break
@@ -429,7 +772,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 			if err != nil {
 				err = es.DetailedError(err)
 				logErrorToSpan(childSpan, err)
-				return nil, err
+				return err
 			}
 			lastSpan := spans[len(spans)-1]
 
@@ -446,12 +789,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 			}
 		}
 	}
-
-	var traces []*model.Trace
-	for _, trace := range tracesMap {
-		traces = append(traces, trace)
-	}
-	return traces, nil
+	return nil
 }
 
 func buildTraceByIDQuery(traceID model.TraceID) elastic.Query {
@@ -586,6 +924,9 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		s.logger.Info("es search services failed", zap.Any("traceQuery", traceQuery), zap.Error(err))
 		return nil, fmt.Errorf("search services failed: %w", err)
 	}
+	var stats searchStats
+	stats.addSearchResult(searchResult)
+	stats.record(s.searchMetrics[findTraceIDsQuery], childSpan, jaegerIndices)
 	if searchResult.Aggregations == nil {
 		return []string{}, nil
 	}
@@ -630,6 +971,14 @@ func (s *SpanReader) buildFindTraceIDsQuery(traceQuery *spanstore.TraceQueryPara
 		boolQuery.Must(serviceNameQuery)
 	}
 
+	// add process.serviceNamespace and process.serviceInstanceID queries
+	if traceQuery.ServiceNamespace != "" {
+		boolQuery.Must(elastic.NewTermQuery(serviceNamespaceField, traceQuery.ServiceNamespace))
+	}
+	if traceQuery.ServiceInstanceID != "" {
+		boolQuery.Must(elastic.NewTermQuery(serviceInstanceIDField, traceQuery.ServiceInstanceID))
+	}
+
 	// add operationName query
 	if traceQuery.OperationName != "" {
 		operationNameQuery := s.buildOperationNameQuery(traceQuery.OperationName)
@@ -671,13 +1020,13 @@ func (*SpanReader) buildOperationNameQuery(operationName string) elastic.Query {
 
 func (s *SpanReader) buildTagQuery(k string, v string) elastic.Query {
 	objectTagListLen := len(objectTagFieldList)
-	queries := make([]elastic.Query, len(nestedTagFieldList)+objectTagListLen)
+	queries := make([]elastic.Query, len(s.nestedTagFields)+objectTagListLen)
 	kd := s.spanConverter.ReplaceDot(k)
 	for i := range objectTagFieldList {
 		queries[i] = s.buildObjectQuery(objectTagFieldList[i], kd, v)
 	}
-	for i := range nestedTagFieldList {
-		queries[i+objectTagListLen] = s.buildNestedQuery(nestedTagFieldList[i], k, v)
+	for i := range s.nestedTagFields {
+		queries[i+objectTagListLen] = s.buildNestedQuery(s.nestedTagFields[i], k, v)
 	}
 
 	// but configuration can change over time
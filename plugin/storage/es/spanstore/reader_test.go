@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
@@ -284,6 +286,22 @@ func TestSpanReaderIndices(t *testing.T) {
 				"cluster_two:" + serviceIndex + "read",
 			},
 		},
+		{
+			params: SpanReaderParams{
+				IndexPrefix: "foo:", UseRetentionIndices: true, SpanIndexDateLayout: spanDataLayout, ServiceIndexDateLayout: serviceDataLayout,
+			},
+			indices: []string{
+				"foo:" + indexPrefixSeparator + spanIndex + spanDataLayoutFormat,
+				"foo:" + indexPrefixSeparator + spanIndex + "retention-*-" + spanDataLayoutFormat,
+				"foo:" + indexPrefixSeparator + serviceIndex + serviceDataLayoutFormat,
+			},
+		},
+		{
+			params: SpanReaderParams{
+				IndexPrefix: "", UseRetentionIndices: true, UseReadWriteAliases: true,
+			},
+			indices: []string{spanIndex + "read", serviceIndex + "read"},
+		},
 		{
 			params: SpanReaderParams{
 				IndexPrefix: "", Archive: true, UseReadWriteAliases: true, RemoteReadClusters: []string{"cluster_one", "cluster_two"},
@@ -327,7 +345,7 @@ func TestSpanReader_GetTrace(t *testing.T) {
 				},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.NoError(t, err)
 		require.NotNil(t, trace)
@@ -340,6 +358,269 @@ func TestSpanReader_GetTrace(t *testing.T) {
 	})
 }
 
+func TestSpanReader_GetTraceWithTimeWindow(t *testing.T) {
+	date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		query    spanstore.GetTraceParameters
+		expected []string
+	}{
+		{
+			name: "window within a single index",
+			query: spanstore.GetTraceParameters{
+				StartTime: date,
+				EndTime:   date.Add(time.Hour),
+			},
+			expected: []string{"jaeger-span-2019-10-10"},
+		},
+		{
+			name: "window spanning several indices",
+			query: spanstore.GetTraceParameters{
+				StartTime: date.Add(-48 * time.Hour),
+				EndTime:   date,
+			},
+			expected: []string{"jaeger-span-2019-10-10", "jaeger-span-2019-10-09", "jaeger-span-2019-10-08"},
+		},
+		{
+			name: "start time only",
+			query: spanstore.GetTraceParameters{
+				StartTime: date,
+			},
+			expected: []string{"jaeger-span-2019-10-12", "jaeger-span-2019-10-11", "jaeger-span-2019-10-10"},
+		},
+		{
+			name: "end time only",
+			query: spanstore.GetTraceParameters{
+				EndTime: date,
+			},
+			expected: []string{"jaeger-span-2019-10-10", "jaeger-span-2019-10-09", "jaeger-span-2019-10-08"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				r.reader.maxSpanAge = 48 * time.Hour
+				r.reader.spanIndexDateLayout = "2006-01-02"
+				r.reader.spanIndexRolloverFrequency = -24 * time.Hour
+				hits := []*elastic.SearchHit{
+					{Source: (*json.RawMessage)(&exampleESSpan)},
+				}
+				mockMultiSearchServiceWithIndices(r, tc.expected...).
+					Return(&elastic.MultiSearchResult{
+						Responses: []*elastic.SearchResult{
+							{Hits: &elastic.SearchHits{Hits: hits}},
+						},
+					}, nil)
+
+				query := tc.query
+				query.TraceID = model.NewTraceID(0, 1)
+				trace, err := r.reader.GetTrace(context.Background(), query)
+				require.NoError(t, err)
+				require.NotNil(t, trace)
+				require.Len(t, trace.Spans, 1)
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetRawSpans(t *testing.T) {
+	date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+	query := spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 1),
+		StartTime: date,
+		EndTime:   date,
+	}
+	testCases := []struct {
+		name        string
+		result      *elastic.SearchResult
+		err         error
+		expected    []spanstore.RawSpan
+		expectedErr string
+	}{
+		{
+			name: "span found",
+			result: &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{
+				{Index: "jaeger-span-2019-10-10", Source: (*json.RawMessage)(&exampleESSpan)},
+				{Index: "jaeger-span-2019-10-10"},
+			}}},
+			expected: []spanstore.RawSpan{{Location: "jaeger-span-2019-10-10", Document: exampleESSpan}},
+		},
+		{
+			name:     "no hits",
+			result:   &elastic.SearchResult{},
+			expected: []spanstore.RawSpan{},
+		},
+		{
+			name:        "search error",
+			err:         errors.New("search failure"),
+			expectedErr: "search failure",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				r.reader.spanIndexDateLayout = "2006-01-02"
+				r.reader.spanIndexRolloverFrequency = -24 * time.Hour
+				searchService := &mocks.SearchService{}
+				searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Size", defaultMaxDocCount).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(tc.result, tc.err)
+				r.client.On("Search", "jaeger-span-2019-10-10").Return(searchService)
+
+				rawSpans, err := r.reader.GetRawSpans(context.Background(), query, model.NewSpanID(2))
+				if tc.expectedErr != "" {
+					require.ErrorContains(t, err, tc.expectedErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, rawSpans)
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetSpans(t *testing.T) {
+	ids := []spanstore.TraceSpanID{
+		{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(3)},
+		{TraceID: model.NewTraceID(0, 2), SpanID: model.NewSpanID(1)},
+	}
+	withSpanReader(t, func(r *spanReaderTest) {
+		spans, err := r.reader.GetSpans(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, spans)
+
+		searchService := &mocks.SearchService{}
+		searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		searchService.On("Size", defaultMaxDocCount).Return(searchService)
+		searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{
+			{Index: "jaeger-span-2019-10-10", Source: (*json.RawMessage)(&exampleESSpan)},
+		}}}, nil).Once()
+		searchService.On("Do", mock.Anything).Return(nil, errors.New("search failure")).Once()
+		r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+
+		spans, err = r.reader.GetSpans(context.Background(), ids)
+		require.NoError(t, err)
+		require.Len(t, spans, 1)
+		assert.Equal(t, model.NewSpanID(3), spans[0].SpanID)
+		assert.Equal(t, "op", spans[0].OperationName)
+		searchService.AssertCalled(t, "Query", mock.MatchedBy(func(q *elastic.BoolQuery) bool {
+			source, err := q.Source()
+			require.NoError(t, err)
+			data, err := json.Marshal(source)
+			require.NoError(t, err)
+			return strings.Count(string(data), `{"term":{"spanID":`) == 2 && strings.Contains(string(data), `"minimum_should_match":"1"`)
+		}))
+
+		_, err = r.reader.GetSpans(context.Background(), ids)
+		require.ErrorContains(t, err, "search failure")
+	})
+}
+
+func TestSpanReader_TraceExists(t *testing.T) {
+	date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+	query := spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 1),
+		StartTime: date,
+		EndTime:   date,
+	}
+	testCases := []struct {
+		name        string
+		count       int64
+		err         error
+		expected    bool
+		expectedErr string
+	}{
+		{
+			name:     "trace found",
+			count:    1,
+			expected: true,
+		},
+		{
+			name: "trace not found",
+		},
+		{
+			name:        "count error",
+			err:         errors.New("count failure"),
+			expectedErr: "count failure",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				r.reader.spanIndexDateLayout = "2006-01-02"
+				r.reader.spanIndexRolloverFrequency = -24 * time.Hour
+				countService := &mocks.CountService{}
+				countService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(countService)
+				countService.On("IgnoreUnavailable", true).Return(countService)
+				countService.On("TerminateAfter", 1).Return(countService)
+				countService.On("Do", mock.Anything).Return(tc.count, tc.err)
+				r.client.On("Count", "jaeger-span-2019-10-10").Return(countService)
+
+				exists, err := r.reader.TraceExists(context.Background(), query)
+				if tc.expectedErr != "" {
+					require.ErrorContains(t, err, tc.expectedErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, exists)
+			})
+		})
+	}
+}
+
+func TestSpanReader_traceTimeWindow(t *testing.T) {
+	now := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+	maxSpanAge := 48 * time.Hour
+	testCases := []struct {
+		name          string
+		query         spanstore.GetTraceParameters
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "no hints",
+			expectedStart: now.Add(-maxSpanAge),
+			expectedEnd:   now,
+		},
+		{
+			name:          "start time in the future",
+			query:         spanstore.GetTraceParameters{StartTime: now.Add(48 * time.Hour)},
+			expectedStart: now.Add(48 * time.Hour),
+			expectedEnd:   now.Add(96 * time.Hour),
+		},
+		{
+			name:          "end time older than max span age",
+			query:         spanstore.GetTraceParameters{EndTime: now.Add(-96 * time.Hour)},
+			expectedStart: now.Add(-144 * time.Hour),
+			expectedEnd:   now.Add(-96 * time.Hour),
+		},
+		{
+			name: "inverted window",
+			query: spanstore.GetTraceParameters{
+				StartTime: now,
+				EndTime:   now.Add(-time.Hour),
+			},
+			expectedStart: now.Add(-maxSpanAge),
+			expectedEnd:   now,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				r.reader.maxSpanAge = maxSpanAge
+				startTime, endTime := r.reader.traceTimeWindow(tc.query, now)
+				assert.Equal(t, tc.expectedStart, startTime)
+				assert.Equal(t, tc.expectedEnd, endTime)
+				// the resolved window must always produce a bounded list of indices
+				indices := timeRangeIndices("jaeger-span-", "2006-01-02", startTime, endTime, -24*time.Hour)
+				assert.Len(t, indices, 3)
+			})
+		})
+	}
+}
+
 func TestSpanReader_multiRead_followUp_query(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
@@ -423,6 +704,55 @@ func TestSpanReader_multiRead_followUp_query(t *testing.T) {
 	})
 }
 
+func TestSpanReader_multiRead_routeByTraceID(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		r.reader.routeByTraceID = true
+		date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+		traceID1, traceID2 := model.NewTraceID(0x10, 1), model.NewTraceID(0x10, 2)
+		span1 := dbmodel.Span{SpanID: "0", TraceID: dbmodel.TraceID(traceID1.String()), StartTime: model.TimeAsEpochMicroseconds(date)}
+		spanBytes1, err := json.Marshal(span1)
+		require.NoError(t, err)
+		span2 := dbmodel.Span{SpanID: "0", TraceID: dbmodel.TraceID(traceID2.String()), StartTime: model.TimeAsEpochMicroseconds(date)}
+		spanBytes2, err := json.Marshal(span2)
+		require.NoError(t, err)
+
+		search := func(traceID model.TraceID) *elastic.SearchRequest {
+			query := elastic.NewBoolQuery().Must(buildTraceByIDQuery(traceID))
+			return elastic.NewSearchRequest().
+				IgnoreUnavailable(true).
+				Source(r.reader.sourceFn(query, model.TimeAsEpochMicroseconds(date.Add(-time.Hour))))
+		}
+
+		multiSearchService := &mocks.MultiSearchService{}
+		routedMultiSearch := &mocks.MultiSearchService{}
+		unroutedMultiSearch := &mocks.MultiSearchService{}
+		multiSearchService.On("Add", search(traceID1).Routing(traceID1.String()), search(traceID2).Routing(traceID2.String())).Return(routedMultiSearch)
+		multiSearchService.On("Add", search(traceID2)).Return(unroutedMultiSearch)
+		routedMultiSearch.On("Index", mock.AnythingOfType("string")).Return(routedMultiSearch)
+		unroutedMultiSearch.On("Index", mock.AnythingOfType("string")).Return(unroutedMultiSearch)
+		r.client.On("MultiSearch").Return(multiSearchService)
+
+		// the second trace was written before the routing was enabled, so it is not found in its shard
+		routedMultiSearch.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{
+				{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{{Source: (*json.RawMessage)(&spanBytes1)}}, TotalHits: 1}},
+				{Hits: &elastic.SearchHits{}},
+			},
+		}, nil)
+		unroutedMultiSearch.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{
+				{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{{Source: (*json.RawMessage)(&spanBytes2)}}, TotalHits: 1}},
+			},
+		}, nil)
+
+		traces, err := r.reader.multiRead(context.Background(), []model.TraceID{traceID1, traceID2}, date, date)
+		require.NoError(t, err)
+		require.Len(t, traces, 2)
+		routedMultiSearch.AssertNumberOfCalls(t, "Do", 1)
+		unroutedMultiSearch.AssertNumberOfCalls(t, "Do", 1)
+	})
+}
+
 func TestSpanReader_SearchAfter(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		var hits []*elastic.SearchHit
@@ -442,7 +772,7 @@ func TestSpanReader_SearchAfter(t *testing.T) {
 				},
 			}, nil).Times(2)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.NoError(t, err)
 		require.NotNil(t, trace)
@@ -462,7 +792,7 @@ func TestSpanReader_GetTraceQueryError(t *testing.T) {
 			Return(&elastic.MultiSearchResult{
 				Responses: []*elastic.SearchResult{},
 			}, nil)
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.EqualError(t, err, "trace not found")
 		require.Nil(t, trace)
@@ -482,7 +812,7 @@ func TestSpanReader_GetTraceNilHits(t *testing.T) {
 				},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.EqualError(t, err, "trace not found")
 		require.Nil(t, trace)
@@ -506,7 +836,7 @@ func TestSpanReader_GetTraceInvalidSpanError(t *testing.T) {
 				},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.Error(t, err, "invalid span")
 		require.Nil(t, trace)
@@ -531,7 +861,7 @@ func TestSpanReader_GetTraceSpanConversionError(t *testing.T) {
 				},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.Error(t, err, "span conversion error, because lacks elements")
 		require.Nil(t, trace)
@@ -701,6 +1031,58 @@ func returnSearchFunc(typ string, r *spanReaderTest) (any, error) {
 	return nil, errors.New("Specify services, operations, traceIDs only")
 }
 
+func TestSpanReader_SearchServicesAndOperations(t *testing.T) {
+	aggregations := func(typ string) elastic.Aggregations {
+		rawMessage := json.RawMessage(`{"buckets": [{"key": "payment","doc_count": 16},{"key": "refund","doc_count": 4}]}`)
+		return elastic.Aggregations{typ: &rawMessage}
+	}
+	querySource := func(t *testing.T, query elastic.Query) string {
+		source, err := query.Source()
+		require.NoError(t, err)
+		data, err := json.Marshal(source)
+		require.NoError(t, err)
+		return string(data)
+	}
+	prefix := spanstore.NameSearchParameters{Text: "pay", Mode: spanstore.NameMatchPrefix}
+	fuzzy := spanstore.NameSearchParameters{Text: "refunf", Mode: spanstore.NameMatchFuzzy}
+	query := spanstore.OperationQueryParameters{ServiceName: "billing"}
+
+	withSpanReader(t, func(r *spanReaderTest) {
+		searchService := mockSearchService(r).Return(&elastic.SearchResult{Aggregations: aggregations(servicesAggregation)}, nil).Parent
+		services, err := r.reader.SearchServices(context.Background(), prefix)
+		require.NoError(t, err)
+		// the storage already filtered the services
+		assert.Equal(t, []string{"payment", "refund"}, services)
+		searchService.AssertCalled(t, "Query", mock.MatchedBy(func(q elastic.Query) bool {
+			return querySource(t, q) == `{"match_phrase_prefix":{"serviceName":{"query":"pay"}}}`
+		}))
+
+		services, err = r.reader.SearchServices(context.Background(), fuzzy)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"refund"}, services)
+	})
+	withSpanReader(t, func(r *spanReaderTest) {
+		searchService := mockSearchService(r).Return(&elastic.SearchResult{Aggregations: aggregations(operationsAggregation)}, nil).Parent
+		operations, err := r.reader.SearchOperations(context.Background(), query, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "payment"}, {Name: "refund"}}, operations)
+		searchService.AssertCalled(t, "Query", mock.MatchedBy(func(q elastic.Query) bool {
+			return querySource(t, q) == `{"bool":{"must":[{"term":{"serviceName":"billing"}},{"match_phrase_prefix":{"operationName":{"query":"pay"}}}]}}`
+		}))
+
+		operations, err = r.reader.SearchOperations(context.Background(), query, fuzzy)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "refund"}}, operations)
+	})
+	withSpanReader(t, func(r *spanReaderTest) {
+		mockSearchService(r).Return(nil, errors.New("Search failure"))
+		_, err := r.reader.SearchServices(context.Background(), fuzzy)
+		require.EqualError(t, err, "search services failed: Search failure")
+		_, err = r.reader.SearchOperations(context.Background(), query, fuzzy)
+		require.EqualError(t, err, "search operations failed: Search failure")
+	})
+}
+
 func TestSpanReader_bucketToStringArray(t *testing.T) {
 	withSpanReader(t, func(_ *spanReaderTest) {
 		buckets := make([]*elastic.AggregationBucketKeyItem, 3)
@@ -813,6 +1195,73 @@ func TestSpanReader_FindTracesInvalidQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_FindTracesSearchTimeWindow(t *testing.T) {
+	goodAggregations := make(map[string]*json.RawMessage)
+	rawMessage := []byte(`{"buckets": [{"key": "1","doc_count": 16}]}`)
+	goodAggregations[traceIDAggregation] = (*json.RawMessage)(&rawMessage)
+
+	hits := make([]*elastic.SearchHit, 1)
+	hits[0] = &elastic.SearchHit{
+		Source: (*json.RawMessage)(&exampleESSpan),
+	}
+	searchHits := &elastic.SearchHits{Hits: hits}
+
+	now := time.Now()
+	newQuery := func() *spanstore.TraceQueryParameters {
+		return &spanstore.TraceQueryParameters{
+			ServiceName:  serviceName,
+			StartTimeMin: now.Add(-30 * 24 * time.Hour),
+			StartTimeMax: now,
+			NumTraces:    1,
+		}
+	}
+
+	t.Run("rejected", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			r.reader.searchTimeWindow = config.SearchTimeWindowConfig{Max: 72 * time.Hour}
+
+			traces, err := r.reader.FindTraces(context.Background(), newQuery())
+			require.ErrorIs(t, err, ErrSearchTimeWindowTooLong)
+			require.ErrorContains(t, err, "720h0m0s is longer than the maximum of 72h0m0s")
+			assert.Nil(t, traces)
+
+			_, err = r.reader.FindTraceIDs(context.Background(), newQuery())
+			require.ErrorIs(t, err, ErrSearchTimeWindowTooLong)
+			r.client.AssertNotCalled(t, "Search")
+		})
+	})
+
+	t.Run("clamped", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			r.reader.searchTimeWindow = config.SearchTimeWindowConfig{Max: time.Hour, Clamp: true}
+			var searchedFrom []time.Time
+			timeRangeIndices := r.reader.timeRangeIndices
+			r.reader.timeRangeIndices = func(indexName, indexDateLayout string, startTime, endTime time.Time, reduceDuration time.Duration) []string {
+				searchedFrom = append(searchedFrom, startTime)
+				return timeRangeIndices(indexName, indexDateLayout, startTime, endTime, reduceDuration)
+			}
+			mockSearchService(r).
+				Return(&elastic.SearchResult{Aggregations: elastic.Aggregations(goodAggregations), Hits: searchHits}, nil)
+			mockMultiSearchService(r).
+				Return(&elastic.MultiSearchResult{
+					Responses: []*elastic.SearchResult{
+						{Hits: searchHits},
+					},
+				}, nil)
+
+			traceQuery := newQuery()
+			traces, err := r.reader.FindTraces(context.Background(), traceQuery)
+			require.NoError(t, err)
+			require.Len(t, traces, 1)
+			assert.Equal(t, []string{
+				"the search time window of 720h0m0s was narrowed to its last 1h0m0s, traces started earlier are not searched",
+			}, traces[0].Warnings)
+			assert.Equal(t, []time.Time{now.Add(-time.Hour), now.Add(-2 * time.Hour)}, searchedFrom)
+			assert.Equal(t, now.Add(-30*24*time.Hour), traceQuery.StartTimeMin, "the query is not modified")
+		})
+	})
+}
+
 func TestSpanReader_FindTracesAggregationFailure(t *testing.T) {
 	goodAggregations := make(map[string]*json.RawMessage)
 
@@ -989,6 +1438,18 @@ func mockMultiSearchService(r *spanReaderTest) *mock.Call {
 	return multiSearchService.On("Do", mock.Anything)
 }
 
+func mockMultiSearchServiceWithIndices(r *spanReaderTest, indices ...string) *mock.Call {
+	multiSearchService := &mocks.MultiSearchService{}
+	multiSearchService.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(multiSearchService)
+	args := make([]any, len(indices))
+	for i, index := range indices {
+		args[i] = index
+	}
+	multiSearchService.On("Index", args...).Return(multiSearchService)
+	r.client.On("MultiSearch").Return(multiSearchService)
+	return multiSearchService.On("Do", mock.Anything)
+}
+
 func mockArchiveMultiSearchService(r *spanReaderTest, indexName string) *mock.Call {
 	multiSearchService := &mocks.MultiSearchService{}
 	multiSearchService.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(multiSearchService)
@@ -1111,6 +1572,30 @@ func TestSpanReader_buildFindTraceIDsQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildFindTraceIDsQueryServiceAttributes(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		traceQuery := &spanstore.TraceQueryParameters{
+			StartTimeMin:      time.Time{},
+			StartTimeMax:      time.Time{}.Add(time.Second),
+			ServiceName:       "s",
+			ServiceNamespace:  "shop",
+			ServiceInstanceID: "s-1",
+		}
+
+		actual, err := r.reader.buildFindTraceIDsQuery(traceQuery).Source()
+		require.NoError(t, err)
+		expected, err := elastic.NewBoolQuery().
+			Must(
+				r.reader.buildStartTimeQuery(time.Time{}, time.Time{}.Add(time.Second)),
+				r.reader.buildServiceNameQuery("s"),
+				elastic.NewTermQuery("process.serviceNamespace", "shop"),
+				elastic.NewTermQuery("process.serviceInstanceID", "s-1"),
+			).Source()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
+
 func TestSpanReader_buildDurationQuery(t *testing.T) {
 	expectedStr := `{ "range":
 			{ "duration": { "include_lower": true,
@@ -1204,6 +1689,23 @@ func TestSpanReader_buildTagQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildTagQueryDocValuesOnly(t *testing.T) {
+	reader := NewSpanReader(SpanReaderParams{
+		Client:        func() es.Client { return &mocks.Client{} },
+		Logger:        zap.NewNop(),
+		DocValuesOnly: config.DocValuesOnly{Logs: true, ProcessTags: true},
+	})
+	actual, err := reader.buildTagQuery("foo", "spook").Source()
+	require.NoError(t, err)
+	expected, err := elastic.NewBoolQuery().Should(
+		reader.buildObjectQuery(objectTagsField, "foo", "spook"),
+		reader.buildObjectQuery(objectProcessTagsField, "foo", "spook"),
+		reader.buildNestedQuery(nestedTagsField, "foo", "spook"),
+	).Source()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
 func TestSpanReader_buildTagRegexQuery(t *testing.T) {
 	inStr, err := os.ReadFile("fixtures/query_02.json")
 	require.NoError(t, err)
@@ -1269,7 +1771,7 @@ func TestSpanReader_ArchiveTraces(t *testing.T) {
 				Responses: []*elastic.SearchResult{},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.TraceID{})
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.Nil(t, trace)
 		require.EqualError(t, err, "trace not found")
@@ -1285,7 +1787,7 @@ func TestSpanReader_ArchiveTraces_ReadAlias(t *testing.T) {
 				Responses: []*elastic.SearchResult{},
 			}, nil)
 
-		trace, err := r.reader.GetTrace(context.Background(), model.TraceID{})
+		trace, err := r.reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
 		require.Nil(t, trace)
 		require.EqualError(t, err, "trace not found")
@@ -1359,3 +1861,40 @@ func TestTerminateAfterNotSet(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, 99, size)
 }
+
+func TestSpanReader_GetOperationsWithStats(t *testing.T) {
+	operations := json.RawMessage(`{"buckets": [{"key": "GET /","doc_count": 3},{"key": "POST /","doc_count": 1}]}`)
+	stats := json.RawMessage(`{"buckets": [{"key": "GET /","doc_count": 42,"last_seen": {"value": 1714521600000000}}]}`)
+	withSpanReader(t, func(r *spanReaderTest) {
+		serviceSearch, spanSearch := &mocks.SearchService{}, &mocks.SearchService{}
+		for _, searchService := range []*mocks.SearchService{serviceSearch, spanSearch} {
+			searchService.On("Query", mock.Anything).Return(searchService)
+			searchService.On("IgnoreUnavailable", true).Return(searchService)
+			searchService.On("Size", 0).Return(searchService)
+			searchService.On("Aggregation", mock.AnythingOfType("string"), mock.AnythingOfType("*elastic.TermsAggregation")).Return(searchService)
+		}
+		serviceSearch.On("Do", mock.Anything).Return(&elastic.SearchResult{Aggregations: elastic.Aggregations{operationsAggregation: &operations}}, nil)
+		spanSearch.On("Do", mock.Anything).Return(&elastic.SearchResult{Aggregations: elastic.Aggregations{operationStatsAggregation: &stats}}, nil).Once()
+		spanSearch.On("Do", mock.Anything).Return(nil, errors.New("search failure")).Once()
+		r.client.On("Search", mock.MatchedBy(func(index string) bool { return strings.HasPrefix(index, serviceIndex) })).Return(serviceSearch)
+		r.client.On("Search", mock.MatchedBy(func(index string) bool { return strings.HasPrefix(index, spanIndex) })).Return(spanSearch)
+
+		query := spanstore.OperationQueryParameters{ServiceName: "frontend", WithStats: true}
+		actual, err := r.reader.GetOperations(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{
+			{Name: "GET /", LastSeen: model.EpochMicrosecondsAsTime(1714521600000000), SpanCount: 42},
+			{Name: "POST /"},
+		}, actual, "the operations without spans in the lookback have no stats")
+		spanSearch.AssertCalled(t, "Aggregation", operationStatsAggregation, mock.MatchedBy(func(aggregation *elastic.TermsAggregation) bool {
+			source, err := aggregation.Source()
+			require.NoError(t, err)
+			data, err := json.Marshal(source)
+			require.NoError(t, err)
+			return string(data) == `{"aggregations":{"last_seen":{"max":{"field":"startTime"}}},"terms":{"field":"operationName","size":10000}}`
+		}))
+
+		_, err = r.reader.GetOperations(context.Background(), query)
+		require.EqualError(t, err, "search operation stats failed: search failure")
+	})
+}
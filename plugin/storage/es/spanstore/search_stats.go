@@ -0,0 +1,157 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"time"
+
+	"github.com/olivere/elastic"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// Names of the queries whose searches are measured, used as the query tag of the search metrics.
+const (
+	findTraceIDsQuery = "find_trace_ids"
+	multiReadQuery    = "multi_read"
+	rawSpansQuery     = "raw_spans"
+	getSpansQuery     = "get_spans"
+)
+
+// hitsBuckets are the buckets of the histogram of the number of documents matched by a query.
+var hitsBuckets = []float64{0, 1, 10, 100, 1000, 10000, 100000, 1000000}
+
+// searchMetrics are the metrics of the statistics that Elasticsearch returns with the results of the searches of a query.
+type searchMetrics struct {
+	// took is the time spent by Elasticsearch executing the searches, excluding the network and the queueing.
+	took metrics.Timer
+	// hits is the number of documents matched by the searches.
+	hits metrics.Histogram
+	// failedShards counts the shards that failed to execute a search, whose documents are missing from the results.
+	failedShards metrics.Counter
+	// timeouts counts the queries of which a search timed out and returned partial results.
+	timeouts metrics.Counter
+}
+
+func newSearchMetrics(factory metrics.Factory) map[string]*searchMetrics {
+	searchFactory := factory.Namespace(metrics.NSOptions{Name: "search"})
+	m := make(map[string]*searchMetrics)
+	for _, query := range []string{findTraceIDsQuery, multiReadQuery, rawSpansQuery, getSpansQuery} {
+		tags := map[string]string{"query": query}
+		m[query] = &searchMetrics{
+			took: searchFactory.Timer(metrics.TimerOptions{
+				Name: "took",
+				Tags: tags,
+				Help: "Time spent by Elasticsearch executing the searches of a query",
+			}),
+			hits: searchFactory.Histogram(metrics.HistogramOptions{
+				Name:    "hits",
+				Tags:    tags,
+				Help:    "Number of documents matched by the searches of a query",
+				Buckets: hitsBuckets,
+			}),
+			failedShards: searchFactory.Counter(metrics.Options{
+				Name: "failed_shards",
+				Tags: tags,
+				Help: "Number of shards that failed to execute the searches of a query",
+			}),
+			timeouts: searchFactory.Counter(metrics.Options{
+				Name: "timeouts",
+				Tags: tags,
+				Help: "Number of queries of which a search timed out",
+			}),
+		}
+	}
+	return m
+}
+
+// searchStats accumulates the statistics of the searches of a query, which may take several round trips.
+// The relation of the total hits (exact or lower bound) is not known, since the total hits are requested
+// as an integer for the compatibility with Elasticsearch 6.x.
+type searchStats struct {
+	searches int
+	took     time.Duration
+	timedOut bool
+	shards   elastic.ShardsInfo
+	hits     int64
+	failures []*elastic.ShardFailure
+}
+
+// addSearchResult adds the statistics of the result of a search.
+func (st *searchStats) addSearchResult(result *elastic.SearchResult) {
+	st.searches++
+	st.took += time.Duration(result.TookInMillis) * time.Millisecond
+	st.addResponse(result)
+}
+
+// addMultiSearchResult adds the statistics of the results of a multi search, whose took time covers all its searches.
+func (st *searchStats) addMultiSearchResult(result *elastic.MultiSearchResult) {
+	st.searches++
+	st.took += time.Duration(result.TookInMillis) * time.Millisecond
+	for _, response := range result.Responses {
+		st.addResponse(response)
+	}
+}
+
+func (st *searchStats) addResponse(result *elastic.SearchResult) {
+	if result == nil {
+		return
+	}
+	st.timedOut = st.timedOut || result.TimedOut
+	if result.Shards != nil {
+		st.shards.Total += result.Shards.Total
+		st.shards.Successful += result.Shards.Successful
+		st.shards.Failed += result.Shards.Failed
+		st.shards.Skipped += result.Shards.Skipped
+		st.failures = append(st.failures, result.Shards.Failures...)
+	}
+	if result.Hits != nil {
+		st.hits += result.Hits.TotalHits
+	}
+}
+
+// record reports the statistics to the metrics of the query, and as attributes and events of its span.
+// Nothing is reported when no search returned.
+func (st *searchStats) record(m *searchMetrics, span trace.Span, indices []string) {
+	if st.searches == 0 {
+		return
+	}
+	m.took.Record(st.took)
+	m.hits.Record(float64(st.hits))
+	m.failedShards.Inc(int64(st.shards.Failed))
+	if st.timedOut {
+		m.timeouts.Inc(1)
+	}
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(
+		attribute.StringSlice("es.indices", indices),
+		attribute.Int("es.searches", st.searches),
+		attribute.Int64("es.took_ms", st.took.Milliseconds()),
+		attribute.Bool("es.timed_out", st.timedOut),
+		attribute.Int("es.shards.total", st.shards.Total),
+		attribute.Int("es.shards.successful", st.shards.Successful),
+		attribute.Int("es.shards.failed", st.shards.Failed),
+		attribute.Int("es.shards.skipped", st.shards.Skipped),
+		attribute.Int64("es.hits.total", st.hits),
+	)
+	for _, failure := range st.failures {
+		if failure == nil {
+			continue
+		}
+		attributes := []attribute.KeyValue{
+			attribute.String("es.index", failure.Index),
+			attribute.Int("es.shard", failure.Shard),
+			attribute.String("es.node", failure.Node),
+			attribute.String("es.status", failure.Status),
+		}
+		if reason, ok := failure.Reason["reason"].(string); ok {
+			attributes = append(attributes, attribute.String("es.reason", reason))
+		}
+		span.AddEvent("shard failure", trace.WithAttributes(attributes...))
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestSearchStatsRecord(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	m := newSearchMetrics(metricsFactory)
+	tp, exporter, closer := tracerProvider(t)
+	defer closer()
+
+	var stats searchStats
+	stats.addMultiSearchResult(&elastic.MultiSearchResult{
+		TookInMillis: 12,
+		Responses: []*elastic.SearchResult{
+			{
+				Shards: &elastic.ShardsInfo{Total: 3, Successful: 2, Failed: 1, Failures: []*elastic.ShardFailure{
+					{Index: "jaeger-span-2024-05-01", Shard: 2, Node: "node-1", Status: "INTERNAL_SERVER_ERROR", Reason: map[string]any{"reason": "too many buckets"}},
+				}},
+				Hits: &elastic.SearchHits{TotalHits: 7},
+			},
+			nil,
+			{Shards: &elastic.ShardsInfo{Total: 3, Successful: 3}, TimedOut: true},
+		},
+	})
+	stats.addSearchResult(&elastic.SearchResult{
+		TookInMillis: 8,
+		Shards:       &elastic.ShardsInfo{Total: 3, Successful: 1, Skipped: 2},
+		Hits:         &elastic.SearchHits{TotalHits: 3},
+	})
+	_, span := tp.Tracer("test").Start(context.Background(), "multiRead")
+	stats.record(m[multiReadQuery], span, []string{"jaeger-span-2024-05-01"})
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.StringSlice("es.indices", []string{"jaeger-span-2024-05-01"}),
+		attribute.Int("es.searches", 2),
+		attribute.Int64("es.took_ms", 20),
+		attribute.Bool("es.timed_out", true),
+		attribute.Int("es.shards.total", 9),
+		attribute.Int("es.shards.successful", 6),
+		attribute.Int("es.shards.failed", 1),
+		attribute.Int("es.shards.skipped", 2),
+		attribute.Int64("es.hits.total", 10),
+	}, spans[0].Attributes)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "shard failure", spans[0].Events[0].Name)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("es.index", "jaeger-span-2024-05-01"),
+		attribute.Int("es.shard", 2),
+		attribute.String("es.node", "node-1"),
+		attribute.String("es.status", "INTERNAL_SERVER_ERROR"),
+		attribute.String("es.reason", "too many buckets"),
+	}, spans[0].Events[0].Attributes)
+
+	tags := map[string]string{"query": multiReadQuery}
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "search.failed_shards", Tags: tags, Value: 1},
+		metricstest.ExpectedMetric{Name: "search.timeouts", Tags: tags, Value: 1},
+	)
+	// the percentiles of the timers and histograms are reported as gauges, in milliseconds for the timers
+	_, gauges := metricsFactory.Snapshot()
+	assert.EqualValues(t, 20, gauges["search.took|query=multi_read.P50"])
+	assert.EqualValues(t, 10, gauges["search.hits|query=multi_read.P50"])
+}
+
+func TestSearchStatsRecordWithoutSearch(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	tp, exporter, closer := tracerProvider(t)
+	defer closer()
+
+	var stats searchStats
+	_, span := tp.Tracer("test").Start(context.Background(), "multiRead")
+	stats.record(newSearchMetrics(metricsFactory)[multiReadQuery], span, nil)
+	span.End()
+
+	assert.Empty(t, exporter.GetSpans()[0].Attributes)
+	counters, _ := metricsFactory.Snapshot()
+	assert.Zero(t, counters["search.failed_shards|query=multi_read"])
+}
+
+func TestSpanReaderSearchStats(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	tp, exporter, closer := tracerProvider(t)
+	defer closer()
+	client := &mocks.Client{}
+	reader := NewSpanReader(SpanReaderParams{
+		Client:              func() es.Client { return client },
+		Logger:              zap.NewNop(),
+		Tracer:              tp.Tracer("test"),
+		MetricsFactory:      metricsFactory,
+		MaxDocCount:         defaultMaxDocCount,
+		SpanIndexDateLayout: "2006-01-02",
+	})
+	reader.spanIndexRolloverFrequency = -24 * time.Hour
+
+	searchService := &mocks.SearchService{}
+	searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(searchService)
+	searchService.On("IgnoreUnavailable", true).Return(searchService)
+	searchService.On("Size", defaultMaxDocCount).Return(searchService)
+	searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{
+		TookInMillis: 5,
+		Shards:       &elastic.ShardsInfo{Total: 1, Successful: 1},
+		Hits: &elastic.SearchHits{TotalHits: 1, Hits: []*elastic.SearchHit{
+			{Index: "jaeger-span-2019-10-10", Source: (*json.RawMessage)(&exampleESSpan)},
+		}},
+	}, nil)
+	client.On("Search", "jaeger-span-2019-10-10").Return(searchService)
+
+	date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
+	_, err := reader.GetRawSpans(context.Background(), spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 1),
+		StartTime: date,
+		EndTime:   date,
+	}, model.NewSpanID(2))
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes, attribute.StringSlice("es.indices", []string{"jaeger-span-2019-10-10"}))
+	assert.Contains(t, spans[0].Attributes, attribute.Int64("es.took_ms", 5))
+	assert.Contains(t, spans[0].Attributes, attribute.Int64("es.hits.total", 1))
+	_, gauges := metricsFactory.Snapshot()
+	assert.EqualValues(t, 5, gauges["search.took|query=raw_spans.P50"])
+	assert.EqualValues(t, 1, gauges["search.hits|query=raw_spans.P50"])
+}
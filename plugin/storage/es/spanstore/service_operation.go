@@ -77,13 +77,17 @@ func (s *ServiceOperationStorage) Write(indexName string, jsonSpan *dbmodel.Span
 	}
 }
 
-func (s *ServiceOperationStorage) getServices(context context.Context, indices []string, maxDocCount int) ([]string, error) {
+// getServices returns the services, or only those starting with prefix if it is not empty.
+func (s *ServiceOperationStorage) getServices(context context.Context, indices []string, prefix string, maxDocCount int) ([]string, error) {
 	serviceAggregation := getServicesAggregation(maxDocCount)
 
 	searchService := s.client().Search(indices...).
 		Size(0). // set to 0 because we don't want actual documents.
 		IgnoreUnavailable(true).
 		Aggregation(servicesAggregation, serviceAggregation)
+	if prefix != "" {
+		searchService = searchService.Query(elastic.NewMatchPhrasePrefixQuery(serviceName, prefix))
+	}
 
 	searchResult, err := searchService.Do(context)
 	if err != nil {
@@ -106,8 +110,12 @@ func getServicesAggregation(maxDocCount int) elastic.Query {
 		Size(maxDocCount) // ES deprecated size omission for aggregating all. https://github.com/elastic/elasticsearch/issues/18838
 }
 
-func (s *ServiceOperationStorage) getOperations(context context.Context, indices []string, service string, maxDocCount int) ([]string, error) {
-	serviceQuery := elastic.NewTermQuery(serviceName, service)
+// getOperations returns the operations of the service, or only those starting with prefix if it is not empty.
+func (s *ServiceOperationStorage) getOperations(context context.Context, indices []string, service string, prefix string, maxDocCount int) ([]string, error) {
+	var serviceQuery elastic.Query = elastic.NewTermQuery(serviceName, service)
+	if prefix != "" {
+		serviceQuery = elastic.NewBoolQuery().Must(serviceQuery, elastic.NewMatchPhrasePrefixQuery(operationNameField, prefix))
+	}
 	serviceFilter := getOperationsAggregation(maxDocCount)
 
 	searchService := s.client().Search(indices...).
@@ -21,8 +21,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/olivere/elastic"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cache"
 	"github.com/jaegertracing/jaeger/pkg/es"
@@ -36,6 +38,11 @@ const (
 	serviceType            = "service"
 	serviceCacheTTLDefault = 12 * time.Hour
 	indexCacheTTLDefault   = 48 * time.Hour
+	// deleteTracesBatchSize is the number of traces deleted by each delete by query request.
+	deleteTracesBatchSize = 500
+
+	logsDroppedWarningCode = "span_logs_dropped"
+	warningSource          = "elasticsearch"
 )
 
 type spanWriterMetrics struct {
@@ -53,6 +60,13 @@ type SpanWriter struct {
 	serviceWriter    serviceWriter
 	spanConverter    dbmodel.FromDomain
 	spanServiceIndex spanAndServiceIndexFn
+	retentionIndex   retentionIndexFn
+	logsKeepFirst    int
+	logsKeepLast     int
+	aliasedIndices   []string
+	deleteIndices    []string
+	archive          bool
+	routeByTraceID   bool
 }
 
 // SpanWriterParams holds constructor parameters for NewSpanWriter
@@ -68,7 +82,12 @@ type SpanWriterParams struct {
 	TagDotReplacement      string
 	Archive                bool
 	UseReadWriteAliases    bool
+	UseRetentionIndices    bool
+	RouteByTraceID         bool
 	ServiceCacheTTL        time.Duration
+	// SpanLogsKeepFirst and SpanLogsKeepLast limit the logs written with each span, see config.SpanLogsLimitConfig.
+	SpanLogsKeepFirst int
+	SpanLogsKeepLast  int
 }
 
 // NewSpanWriter creates a new SpanWriter for use
@@ -88,14 +107,29 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 		serviceWriter:    serviceOperationStorage.Write,
 		spanConverter:    dbmodel.NewFromDomain(p.AllTagsAsFields, p.TagKeysAsFields, p.TagDotReplacement),
 		spanServiceIndex: getSpanAndServiceIndexFn(p.Archive, p.UseReadWriteAliases, p.IndexPrefix, p.SpanIndexDateLayout, p.ServiceIndexDateLayout),
+		retentionIndex:   getRetentionIndexFn(p.Archive, p.UseReadWriteAliases, p.UseRetentionIndices, p.IndexPrefix, p.SpanIndexDateLayout),
+		logsKeepFirst:    p.SpanLogsKeepFirst,
+		logsKeepLast:     p.SpanLogsKeepLast,
+		aliasedIndices:   getAliasedIndices(p.Archive, p.UseReadWriteAliases, p.IndexPrefix),
+		deleteIndices:    getDeleteIndices(p.Archive, p.UseReadWriteAliases, p.IndexPrefix),
+		archive:          p.Archive,
+		routeByTraceID:   p.RouteByTraceID,
 	}
 }
 
 // CreateTemplates creates index templates.
+// The archive writer creates the template of the archive span indices only, as it writes no services.
 func (s *SpanWriter) CreateTemplates(spanTemplate, serviceTemplate, indexPrefix string) error {
 	if indexPrefix != "" && !strings.HasSuffix(indexPrefix, "-") {
 		indexPrefix += "-"
 	}
+	if s.archive {
+		name := archiveIndex(indexPrefix+spanIndex, archiveIndexSuffix)
+		if _, err := s.client().CreateTemplate(name).Body(spanTemplate).Do(context.Background()); err != nil {
+			return fmt.Errorf("failed to create template %q: %w", name, err)
+		}
+		return nil
+	}
 	_, err := s.client().CreateTemplate(indexPrefix + "jaeger-span").Body(spanTemplate).Do(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to create template %q: %w", indexPrefix+"jaeger-span", err)
@@ -135,10 +169,33 @@ func getSpanAndServiceIndexFn(archive, useReadWriteAliases bool, prefix, spanDat
 	}
 }
 
+// retentionIndexFn returns the name of the span index for spans requesting a retention
+type retentionIndexFn func(spanTime time.Time, retention time.Duration) string
+
+// getRetentionIndexFn returns nil unless spans requesting a retention are written to their own index family,
+// which is only supported with daily indices. The family of a retention is named after it in whole days,
+// e.g. jaeger-span-retention-30d-2024-05-01, so that the index cleaner can delete each family on its own schedule.
+func getRetentionIndexFn(archive, useReadWriteAliases, useRetentionIndices bool, prefix, spanDateLayout string) retentionIndexFn {
+	if archive || useReadWriteAliases || !useRetentionIndices {
+		return nil
+	}
+	if prefix != "" {
+		prefix += indexPrefixSeparator
+	}
+	return func(date time.Time, retention time.Duration) string {
+		return indexWithDate(retentionIndexPrefix(prefix+spanIndex, retention), spanDateLayout, date)
+	}
+}
+
 // WriteSpan writes a span and its corresponding service:operation in ElasticSearch
 func (s *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
 	spanIndexName, serviceIndexName := s.spanServiceIndex(span.StartTime)
-	jsonSpan := s.spanConverter.FromDomainEmbedProcess(span)
+	if s.retentionIndex != nil {
+		if retention, ok := span.GetRetention(); ok {
+			spanIndexName = s.retentionIndex(span.StartTime, retention)
+		}
+	}
+	jsonSpan := s.spanConverter.FromDomainEmbedProcess(s.limitLogs(span))
 	if serviceIndexName != "" {
 		s.writeService(serviceIndexName, jsonSpan)
 	}
@@ -146,6 +203,90 @@ func (s *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
 	return nil
 }
 
+// limitLogs returns a copy of the span with its logs downsampled when it has more logs than the limit,
+// keeping the first and last ones and those recording errors. The span itself is left untouched,
+// as it may be written to other storage backends too.
+func (s *SpanWriter) limitLogs(span *model.Span) *model.Span {
+	limit := s.logsKeepFirst + s.logsKeepLast
+	if limit == 0 || len(span.Logs) <= limit {
+		return span
+	}
+	logs := make([]model.Log, 0, limit)
+	lastStart := len(span.Logs) - s.logsKeepLast
+	for i, log := range span.Logs {
+		if i < s.logsKeepFirst || i >= lastStart || isErrorLog(log) {
+			logs = append(logs, log)
+		}
+	}
+	limited := *span
+	limited.Logs = logs
+	limited.Tags = append(make([]model.KeyValue, 0, len(span.Tags)+1), span.Tags...)
+	jptrace.AddStructuredWarningTags(&limited, jptrace.Warning{
+		Code:     logsDroppedWarningCode,
+		Severity: jptrace.WarningSeverityDataLoss,
+		Message:  fmt.Sprintf("%d of %d span logs were dropped when the span was stored", len(span.Logs)-len(logs), len(span.Logs)),
+		Source:   warningSource,
+	})
+	return &limited
+}
+
+// isErrorLog returns true for logs recording an error, i.e. OpenTracing error logs
+// and OpenTelemetry exception events.
+func isErrorLog(log model.Log) bool {
+	for _, field := range log.Fields {
+		if field.Key == jptrace.EventNameKey && (field.AsString() == "error" || field.AsString() == "exception") {
+			return true
+		}
+		if strings.HasPrefix(field.Key, "error.") || strings.HasPrefix(field.Key, "exception.") {
+			return true
+		}
+	}
+	return false
+}
+
+// getDeleteIndices returns the indices holding the spans of the writer, from which traces are deleted.
+// Without aliases, these are the daily span indices, including those of the spans requesting a retention,
+// but not the archive index, which has a span writer of its own.
+func getDeleteIndices(archive, useReadWriteAliases bool, prefix string) []string {
+	if prefix != "" {
+		prefix += indexPrefixSeparator
+	}
+	spanIndexPrefix := prefix + spanIndex
+	if archive {
+		if useReadWriteAliases {
+			return []string{archiveIndex(spanIndexPrefix, archiveReadIndexSuffix)}
+		}
+		return []string{archiveIndex(spanIndexPrefix, archiveIndexSuffix)}
+	}
+	if useReadWriteAliases {
+		return []string{spanIndexPrefix + readAliasSuffix}
+	}
+	return []string{spanIndexPrefix + "*", "-" + archiveIndex(spanIndexPrefix, archiveIndexSuffix) + "*"}
+}
+
+// DeleteTraces deletes the spans of the traces from the span indices, in batches of traces.
+// The spans of each batch are deleted by a single delete by query request, which proceeds
+// on version conflicts with spans written meanwhile and refreshes the indices once done.
+func (s *SpanWriter) DeleteTraces(ctx context.Context, traceIDs []model.TraceID) error {
+	for start := 0; start < len(traceIDs); start += deleteTracesBatchSize {
+		end := min(start+deleteTracesBatchSize, len(traceIDs))
+		query := elastic.NewBoolQuery()
+		for _, traceID := range traceIDs[start:end] {
+			query.Should(buildTraceByIDQuery(traceID))
+		}
+		_, err := s.client().DeleteByQuery(s.deleteIndices...).
+			Query(query).
+			IgnoreUnavailable(true).
+			ProceedOnVersionConflict().
+			Refresh("true").
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete traces: %w", err)
+		}
+	}
+	return nil
+}
+
 // Close closes SpanWriter
 func (s *SpanWriter) Close() error {
 	return s.client().Close()
@@ -164,5 +305,9 @@ func (s *SpanWriter) writeService(indexName string, jsonSpan *dbmodel.Span) {
 }
 
 func (s *SpanWriter) writeSpan(indexName string, jsonSpan *dbmodel.Span) {
-	s.client().Index().Index(indexName).Type(spanType).BodyJson(&jsonSpan).Add()
+	indexService := s.client().Index().Index(indexName).Type(spanType)
+	if s.routeByTraceID {
+		indexService = indexService.Routing(string(jsonSpan.TraceID))
+	}
+	indexService.BodyJson(&jsonSpan).Add()
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/internal/jptrace"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
@@ -56,7 +57,10 @@ func withSpanWriter(fn func(w *spanWriterTest)) {
 	fn(w)
 }
 
-var _ spanstore.Writer = &SpanWriter{} // check API conformance
+var (
+	_ spanstore.Writer       = &SpanWriter{} // check API conformance
+	_ spanstore.TraceDeleter = &SpanWriter{}
+)
 
 func TestSpanWriterIndices(t *testing.T) {
 	client := &mocks.Client{}
@@ -301,6 +305,26 @@ func TestCreateTemplates(t *testing.T) {
 	}
 }
 
+func TestSpanWriterCreateArchiveTemplate(t *testing.T) {
+	for _, templateErr := range []error{nil, errors.New("span-template-error")} {
+		client := &mocks.Client{}
+		tService := &mocks.TemplateCreateService{}
+		tService.On("Body", "archive-template").Return(tService)
+		tService.On("Do", context.Background()).Return(nil, templateErr)
+		client.On("CreateTemplate", "test-jaeger-span-archive").Return(tService)
+		writer := NewSpanWriter(SpanWriterParams{
+			Client: func() es.Client { return client }, Logger: zap.NewNop(), MetricsFactory: metricstest.NewFactory(0), Archive: true,
+		})
+		err := writer.CreateTemplates("archive-template", "service-template", "test")
+		if templateErr != nil {
+			require.EqualError(t, err, `failed to create template "test-jaeger-span-archive": span-template-error`)
+		} else {
+			require.NoError(t, err)
+		}
+		client.AssertExpectations(t)
+	}
+}
+
 func TestSpanIndexName(t *testing.T) {
 	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
 	require.NoError(t, err)
@@ -313,6 +337,93 @@ func TestSpanIndexName(t *testing.T) {
 	assert.Equal(t, "jaeger-service-1995-04-21", serviceIndexName)
 }
 
+func TestSpanWriterRetentionIndices(t *testing.T) {
+	client := &mocks.Client{}
+	clientFn := func() es.Client { return client }
+	logger, _ := testutils.NewLogger()
+	metricsFactory := metricstest.NewFactory(0)
+	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
+	require.NoError(t, err)
+	testCases := []struct {
+		name      string
+		params    SpanWriterParams
+		retention time.Duration
+		index     string
+	}{
+		{
+			name: "whole days",
+			params: SpanWriterParams{
+				Client: clientFn, Logger: logger, MetricsFactory: metricsFactory,
+				SpanIndexDateLayout: "2006-01-02", UseRetentionIndices: true,
+			},
+			retention: 30 * 24 * time.Hour,
+			index:     "jaeger-span-retention-30d-1995-04-21",
+		},
+		{
+			name: "rounded up with prefix",
+			params: SpanWriterParams{
+				Client: clientFn, Logger: logger, MetricsFactory: metricsFactory,
+				IndexPrefix: "foo", SpanIndexDateLayout: "2006-01-02", UseRetentionIndices: true,
+			},
+			retention: 36 * time.Hour,
+			index:     "foo-jaeger-span-retention-2d-1995-04-21",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := NewSpanWriter(testCase.params)
+			require.NotNil(t, w.retentionIndex)
+			assert.Equal(t, testCase.index, w.retentionIndex(date, testCase.retention))
+		})
+	}
+
+	for _, params := range []SpanWriterParams{
+		{Client: clientFn, Logger: logger, MetricsFactory: metricsFactory},
+		{Client: clientFn, Logger: logger, MetricsFactory: metricsFactory, UseRetentionIndices: true, UseReadWriteAliases: true},
+		{Client: clientFn, Logger: logger, MetricsFactory: metricsFactory, UseRetentionIndices: true, Archive: true},
+	} {
+		assert.Nil(t, NewSpanWriter(params).retentionIndex)
+	}
+}
+
+func TestSpanWriterWriteSpanWithRetention(t *testing.T) {
+	client := &mocks.Client{}
+	logger, _ := testutils.NewLogger()
+	writer := NewSpanWriter(SpanWriterParams{
+		Client:                 func() es.Client { return client },
+		Logger:                 logger,
+		MetricsFactory:         metricstest.NewFactory(0),
+		SpanIndexDateLayout:    "2006-01-02",
+		ServiceIndexDateLayout: "2006-01-02",
+		UseRetentionIndices:    true,
+	})
+	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
+	require.NoError(t, err)
+	span := &model.Span{
+		TraceID:   model.NewTraceID(0, 1),
+		StartTime: date,
+		Tags:      model.KeyValues{model.String(model.RetentionTagKey, "30d")},
+		Process:   model.NewProcess("service", nil),
+	}
+
+	spanIndex := &mocks.IndexService{}
+	spanIndex.On("Index", stringMatcher("jaeger-span-retention-30d-1995-04-21")).Return(spanIndex)
+	spanIndex.On("Type", stringMatcher(spanType)).Return(spanIndex)
+	spanIndex.On("BodyJson", mock.AnythingOfType("**dbmodel.Span")).Return(spanIndex)
+	spanIndex.On("Add")
+	serviceIndex := &mocks.IndexService{}
+	serviceIndex.On("Index", stringMatcher("jaeger-service-1995-04-21")).Return(serviceIndex)
+	serviceIndex.On("Type", stringMatcher(serviceType)).Return(serviceIndex)
+	serviceIndex.On("Id", mock.AnythingOfType("string")).Return(serviceIndex)
+	serviceIndex.On("BodyJson", mock.AnythingOfType("dbmodel.Service")).Return(serviceIndex)
+	serviceIndex.On("Add")
+	client.On("Index").Return(serviceIndex).Once()
+	client.On("Index").Return(spanIndex).Once()
+
+	require.NoError(t, writer.WriteSpan(context.Background(), span))
+	spanIndex.AssertNumberOfCalls(t, "Add", 1)
+}
+
 func TestWriteSpanInternal(t *testing.T) {
 	withSpanWriter(func(w *spanWriterTest) {
 		indexService := &mocks.IndexService{}
@@ -333,6 +444,28 @@ func TestWriteSpanInternal(t *testing.T) {
 	})
 }
 
+func TestWriteSpanInternalRouteByTraceID(t *testing.T) {
+	withSpanWriter(func(w *spanWriterTest) {
+		w.writer.routeByTraceID = true
+		indexService := &mocks.IndexService{}
+
+		indexName := "jaeger-1995-04-21"
+		indexService.On("Index", stringMatcher(indexName)).Return(indexService)
+		indexService.On("Type", stringMatcher(spanType)).Return(indexService)
+		indexService.On("Routing", "00000000000000010000000000000002").Return(indexService)
+		indexService.On("BodyJson", mock.AnythingOfType("**dbmodel.Span")).Return(indexService)
+		indexService.On("Add")
+
+		w.client.On("Index").Return(indexService)
+
+		jsonSpan := &dbmodel.Span{TraceID: dbmodel.TraceID(model.NewTraceID(1, 2).String())}
+
+		w.writer.writeSpan(indexName, jsonSpan)
+		indexService.AssertNumberOfCalls(t, "Routing", 1)
+		indexService.AssertNumberOfCalls(t, "Add", 1)
+	})
+}
+
 func TestWriteSpanInternalError(t *testing.T) {
 	withSpanWriter(func(w *spanWriterTest) {
 		indexService := &mocks.IndexService{}
@@ -420,6 +553,103 @@ func TestNewSpanTags(t *testing.T) {
 	}
 }
 
+func TestSpanWriterLimitLogs(t *testing.T) {
+	newLog := func(fields ...model.KeyValue) model.Log {
+		return model.Log{Fields: fields}
+	}
+	logs := []model.Log{
+		newLog(model.String("message", "0")),
+		newLog(model.String("message", "1")),
+		newLog(model.String("event", "error")),
+		newLog(model.String("message", "3")),
+		newLog(model.String("exception.type", "IOException")),
+		newLog(model.String("message", "5")),
+		newLog(model.String("message", "6")),
+	}
+	span := &model.Span{
+		Tags: []model.KeyValue{model.String("foo", "bar")},
+		Logs: logs,
+	}
+
+	testCases := []struct {
+		name         string
+		keepFirst    int
+		keepLast     int
+		expectedLogs []model.Log
+		expectedTags []model.KeyValue
+	}{
+		{
+			name:         "disabled",
+			expectedLogs: logs,
+			expectedTags: span.Tags,
+		},
+		{
+			name:         "under the limit",
+			keepFirst:    4,
+			keepLast:     3,
+			expectedLogs: logs,
+			expectedTags: span.Tags,
+		},
+		{
+			name:         "over the limit",
+			keepFirst:    1,
+			keepLast:     1,
+			expectedLogs: []model.Log{logs[0], logs[2], logs[4], logs[6]},
+			expectedTags: []model.KeyValue{
+				model.String("foo", "bar"),
+				model.String(jptrace.WarningsAttribute, `{"code":"span_logs_dropped","severity":"data_loss",`+
+					`"message":"3 of 7 span logs were dropped when the span was stored","source":"elasticsearch"}`),
+			},
+		},
+		{
+			name:         "keep last only",
+			keepLast:     2,
+			expectedLogs: []model.Log{logs[2], logs[4], logs[5], logs[6]},
+			expectedTags: []model.KeyValue{
+				model.String("foo", "bar"),
+				model.String(jptrace.WarningsAttribute, `{"code":"span_logs_dropped","severity":"data_loss",`+
+					`"message":"3 of 7 span logs were dropped when the span was stored","source":"elasticsearch"}`),
+			},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewSpanWriter(SpanWriterParams{
+				Client:            func() es.Client { return &mocks.Client{} },
+				Logger:            zap.NewNop(),
+				MetricsFactory:    metricstest.NewFactory(0),
+				SpanLogsKeepFirst: test.keepFirst,
+				SpanLogsKeepLast:  test.keepLast,
+			})
+			limited := w.limitLogs(span)
+			assert.Equal(t, test.expectedLogs, limited.Logs)
+			assert.Equal(t, test.expectedTags, limited.Tags)
+			// the original span is left untouched
+			assert.Equal(t, logs, span.Logs)
+			assert.Equal(t, []model.KeyValue{model.String("foo", "bar")}, span.Tags)
+		})
+	}
+}
+
+func TestIsErrorLog(t *testing.T) {
+	testCases := []struct {
+		field    model.KeyValue
+		expected bool
+	}{
+		{field: model.String("event", "error"), expected: true},
+		{field: model.String("event", "exception"), expected: true},
+		{field: model.String("error.kind", "timeout"), expected: true},
+		{field: model.String("exception.message", "boom"), expected: true},
+		{field: model.String("event", "retry"), expected: false},
+		{field: model.String("message", "error"), expected: false},
+	}
+	for _, test := range testCases {
+		t.Run(test.field.Key+"="+test.field.AsString(), func(t *testing.T) {
+			assert.Equal(t, test.expected, isErrorLog(model.Log{Fields: []model.KeyValue{test.field}}))
+		})
+	}
+}
+
 func TestSpanWriterParamsTTL(t *testing.T) {
 	logger, _ := testutils.NewLogger()
 	metricsFactory := metricstest.NewFactory(0)
@@ -491,3 +721,59 @@ func stringMatcher(q string) any {
 	}
 	return mock.MatchedBy(matchFunc)
 }
+
+func TestGetDeleteIndices(t *testing.T) {
+	testCases := []struct {
+		name     string
+		archive  bool
+		aliases  bool
+		prefix   string
+		expected []string
+	}{
+		{name: "daily indices", expected: []string{"jaeger-span-*", "-jaeger-span-archive*"}},
+		{name: "daily indices with prefix", prefix: "foo", expected: []string{"foo-jaeger-span-*", "-foo-jaeger-span-archive*"}},
+		{name: "aliases", aliases: true, expected: []string{"jaeger-span-read"}},
+		{name: "archive", archive: true, prefix: "foo", expected: []string{"foo-jaeger-span-archive"}},
+		{name: "archive with aliases", archive: true, aliases: true, expected: []string{"jaeger-span-archive-read"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getDeleteIndices(tc.archive, tc.aliases, tc.prefix))
+		})
+	}
+}
+
+func TestSpanWriterDeleteTraces(t *testing.T) {
+	withSpanWriter(func(w *spanWriterTest) {
+		traceIDs := make([]model.TraceID, deleteTracesBatchSize+1)
+		for i := range traceIDs {
+			traceIDs[i] = model.NewTraceID(0, uint64(i+1))
+		}
+		deleteService := &mocks.DeleteByQueryService{}
+		deleteService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(deleteService)
+		deleteService.On("IgnoreUnavailable", true).Return(deleteService)
+		deleteService.On("ProceedOnVersionConflict").Return(deleteService)
+		deleteService.On("Refresh", "true").Return(deleteService)
+		deleteService.On("Do", mock.Anything).Return(nil, nil)
+		w.client.On("DeleteByQuery", "jaeger-span-*", "-jaeger-span-archive*").Return(deleteService)
+
+		require.NoError(t, w.writer.DeleteTraces(context.Background(), traceIDs))
+		w.client.AssertNumberOfCalls(t, "DeleteByQuery", 2)
+		deleteService.AssertNumberOfCalls(t, "Do", 2)
+	})
+}
+
+func TestSpanWriterDeleteTracesError(t *testing.T) {
+	withSpanWriter(func(w *spanWriterTest) {
+		deleteService := &mocks.DeleteByQueryService{}
+		deleteService.On("Query", mock.Anything).Return(deleteService)
+		deleteService.On("IgnoreUnavailable", true).Return(deleteService)
+		deleteService.On("ProceedOnVersionConflict").Return(deleteService)
+		deleteService.On("Refresh", "true").Return(deleteService)
+		deleteService.On("Do", mock.Anything).Return(nil, errors.New("delete error"))
+		w.client.On("DeleteByQuery", mock.Anything, mock.Anything).Return(deleteService)
+
+		err := w.writer.DeleteTraces(context.Background(), []model.TraceID{model.NewTraceID(0, 1)})
+		require.EqualError(t, err, "failed to delete traces: delete error")
+	})
+}
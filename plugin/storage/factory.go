@@ -52,6 +52,7 @@ const (
 
 	downsamplingRatio    = "downsampling.ratio"
 	downsamplingHashSalt = "downsampling.hashsalt"
+	spanRoutingRulesFile = "span-storage.routing-rules-file"
 	spanStorageType      = "span-storage-type"
 
 	// defaultDownsamplingRatio is the default downsampling ratio.
@@ -183,9 +184,21 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 		writers = append(writers, writer)
 	}
 	var spanWriter spanstore.Writer
-	if len(f.SpanWriterTypes) == 1 {
+	switch {
+	case f.SpanRoutingRulesFile != "":
+		rules, err := LoadSpanRoutingRules(f.SpanRoutingRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		routes, err := writerRoutes(rules, f.SpanWriterTypes, writers)
+		if err != nil {
+			return nil, err
+		}
+		// the spans matching no rule are written with the first type, as for reading and archiving
+		spanWriter = spanstore.NewRoutingWriter(routes, writers[0], f.metricsFactory.Namespace(metrics.NSOptions{Name: "routing_writer"}))
+	case len(f.SpanWriterTypes) == 1:
 		spanWriter = writers[0]
-	} else {
+	default:
 		spanWriter = spanstore.NewCompositeWriter(writers...)
 	}
 	// Turn off DownsamplingWriter entirely if ratio == defaultDownsamplingRatio.
@@ -236,6 +249,16 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return factory.CreateDependencyReader()
 }
 
+// Backend returns the factory of one of the configured storage types, e.g. to serve some
+// requests from a backend other than the span reader's.
+func (f *Factory) Backend(storageType string) (storage.Factory, error) {
+	factory, ok := f.factories[storageType]
+	if !ok {
+		return nil, fmt.Errorf("storage type %s is not configured", storageType)
+	}
+	return factory, nil
+}
+
 // AddFlags implements plugin.Configurable
 func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 	for _, factory := range f.factories {
@@ -251,6 +274,14 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 func (f *Factory) AddPipelineFlags(flagSet *flag.FlagSet) {
 	f.AddFlags(flagSet)
 	f.addDownsamplingFlags(flagSet)
+	flagSet.String(
+		spanRoutingRulesFile,
+		"",
+		"(experimental) Path to a JSON file with the list of rules selecting the storage types, among those of "+SpanStorageTypeEnvVar+", each span is written to, "+
+			"instead of writing the spans to all of them. "+
+			`Each rule, e.g. {"name": "high-volume", "services": ["frontend"], "tags": {"tier": "bulk"}, "storage": ["kafka"]}, matches the spans of the services (any if empty) `+
+			"whose span or process tags have the given values (any if empty). The spans are written to the storage types of the first matching rule, "+
+			"or to the first storage type if no rule matches. The spans written by each rule are counted in the routing_writer_routed_spans metric.")
 }
 
 // addDownsamplingFlags add flags for Downsampling params
@@ -276,6 +307,10 @@ func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 		}
 	}
 	f.initDownsamplingFromViper(v)
+	// the routing flag is a pipeline flag, as the downsampling ones
+	if f.downsamplingFlagsAdded {
+		f.FactoryConfig.SpanRoutingRulesFile = v.GetString(spanRoutingRulesFile)
+	}
 }
 
 func (f *Factory) initDownsamplingFromViper(v *viper.Viper) {
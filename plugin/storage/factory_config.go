@@ -43,6 +43,9 @@ type FactoryConfig struct {
 	DependenciesStorageType string
 	DownsamplingRatio       float64
 	DownsamplingHashSalt    string
+	// SpanRoutingRulesFile is the path of a JSON file with the SpanRoutingRule selecting
+	// the span writer types of each span, instead of writing the spans with all of them.
+	SpanRoutingRulesFile string
 }
 
 // FactoryConfigFromEnvAndCLI reads the desired types of storage backends from SPAN_STORAGE_TYPE and
@@ -16,20 +16,25 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"expvar"
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/storage"
@@ -159,6 +164,18 @@ func TestCreate(t *testing.T) {
 	assert.Equal(t, spanWriter, w)
 }
 
+func TestBackend(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+
+	backend, err := f.Backend(cassandraStorageType)
+	require.NoError(t, err)
+	assert.Equal(t, f.factories[cassandraStorageType], backend)
+
+	_, err = f.Backend(memoryStorageType)
+	require.EqualError(t, err, "storage type memory is not configured")
+}
+
 func TestCreateDownsamplingWriter(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
@@ -225,6 +242,48 @@ func TestCreateMulti(t *testing.T) {
 	assert.Equal(t, spanstore.NewCompositeWriter(spanWriter, spanWriter2), w)
 }
 
+func TestCreateRoutingWriter(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = append(cfg.SpanWriterTypes, kafkaStorageType)
+	cfg.SpanRoutingRulesFile = filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(cfg.SpanRoutingRulesFile, []byte(`[{"services": ["frontend"], "storage": ["kafka"]}]`), 0o600))
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	cassandraFactory := new(mocks.Factory)
+	kafkaFactory := new(mocks.Factory)
+	f.factories[cassandraStorageType] = cassandraFactory
+	f.factories[kafkaStorageType] = kafkaFactory
+	spanWriter := new(spanStoreMocks.Writer)
+	spanWriter2 := new(spanStoreMocks.Writer)
+	cassandraFactory.On("CreateSpanWriter").Return(spanWriter, nil)
+	kafkaFactory.On("CreateSpanWriter").Return(spanWriter2, nil)
+	m := metrics.NullFactory
+	l := zap.NewNop()
+	cassandraFactory.On("Initialize", m, l).Return(nil)
+	kafkaFactory.On("Initialize", m, l).Return(nil)
+	require.NoError(t, f.Initialize(m, l))
+
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	frontendSpan := &model.Span{Process: model.NewProcess("frontend", nil)}
+	otherSpan := &model.Span{Process: model.NewProcess("orders", nil)}
+	spanWriter2.On("WriteSpan", mock.Anything, frontendSpan).Return(nil).Once()
+	spanWriter.On("WriteSpan", mock.Anything, otherSpan).Return(nil).Once()
+	require.NoError(t, w.WriteSpan(context.Background(), frontendSpan))
+	require.NoError(t, w.WriteSpan(context.Background(), otherSpan))
+	spanWriter.AssertExpectations(t)
+	spanWriter2.AssertExpectations(t)
+
+	require.NoError(t, os.WriteFile(cfg.SpanRoutingRulesFile, []byte(`[{"name": "frontend", "storage": ["memory"]}]`), 0o600))
+	_, err = f.CreateSpanWriter()
+	require.ErrorContains(t, err, "storage type memory of span routing rule frontend is not one of the span storage types")
+
+	f.SpanRoutingRulesFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = f.CreateSpanWriter()
+	require.ErrorContains(t, err, "failed to read span routing rules")
+}
+
 func TestCreateArchive(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
@@ -292,7 +351,7 @@ func TestCreateError(t *testing.T) {
 }
 
 func TestAllSamplingStorageTypes(t *testing.T) {
-	assert.Equal(t, []string{"cassandra", "memory", "badger"}, AllSamplingStorageTypes())
+	assert.Equal(t, []string{"cassandra", "opensearch", "elasticsearch", "memory", "badger"}, AllSamplingStorageTypes())
 }
 
 func TestCreateSamplingStoreFactory(t *testing.T) {
@@ -314,12 +373,12 @@ func TestCreateSamplingStoreFactory(t *testing.T) {
 
 	// if an incompatible factory is specified return err
 	cfg := defaultCfg()
-	cfg.SamplingStorageType = "elasticsearch"
+	cfg.SamplingStorageType = "blackhole"
 	f, err = NewFactory(cfg)
 	require.NoError(t, err)
 	ssFactory, err = f.CreateSamplingStoreFactory()
 	assert.Nil(t, ssFactory)
-	require.EqualError(t, err, "storage factory of type elasticsearch does not support sampling store")
+	require.EqualError(t, err, "storage factory of type blackhole does not support sampling store")
 
 	// if a compatible factory is specified then return it
 	cfg.SamplingStorageType = "cassandra"
@@ -388,6 +447,14 @@ func TestParsingDownsamplingRatio(t *testing.T) {
 	assert.Equal(t, 0.5, f.FactoryConfig.DownsamplingRatio)
 }
 
+func TestParsingSpanRoutingRulesFile(t *testing.T) {
+	f := Factory{}
+	v, command := config.Viperize(f.AddPipelineFlags)
+	require.NoError(t, command.ParseFlags([]string{"--span-storage.routing-rules-file=rules.json"}))
+	f.InitFromViper(v, zap.NewNop())
+	assert.Equal(t, "rules.json", f.FactoryConfig.SpanRoutingRulesFile)
+}
+
 func TestDefaultDownsamplingWithAddFlags(t *testing.T) {
 	f := Factory{}
 	v, command := config.Viperize(f.AddFlags)
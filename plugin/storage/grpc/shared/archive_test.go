@@ -70,7 +70,7 @@ func TestArchiveReader_GetTrace(t *testing.T) {
 	}).Return(traceClient, nil)
 	reader := &archiveReader{client: archiveSpanReader}
 
-	trace, err := reader.GetTrace(context.Background(), mockTraceID)
+	trace, err := reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	require.NoError(t, err)
 	assert.Equal(t, expected, trace)
 }
@@ -84,7 +84,7 @@ func TestArchiveReaderGetTrace_NoTrace(t *testing.T) {
 	}).Return(nil, status.Errorf(codes.NotFound, ""))
 	reader := &archiveReader{client: archiveSpanReader}
 
-	_, err := reader.GetTrace(context.Background(), mockTraceID)
+	_, err := reader.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: mockTraceID})
 	assert.Equal(t, spanstore.ErrTraceNotFound, err)
 }
 
@@ -127,9 +127,9 @@ func (c *GRPCClient) ArchiveSpanWriter() spanstore.Writer {
 }
 
 // GetTrace takes a traceID and returns a Trace associated with that traceID
-func (c *GRPCClient) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+func (c *GRPCClient) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
 	stream, err := c.readerClient.GetTrace(upgradeContext(ctx), &storage_v1.GetTraceRequest{
-		TraceID: traceID,
+		TraceID: query.TraceID,
 	})
 	if status.Code(err) == codes.NotFound {
 		return nil, spanstore.ErrTraceNotFound
@@ -188,7 +188,7 @@ func (c *GRPCClient) FindTraces(ctx context.Context, query *spanstore.TraceQuery
 		Query: &storage_v1.TraceQueryParameters{
 			ServiceName:   query.ServiceName,
 			OperationName: query.OperationName,
-			Tags:          query.Tags,
+			Tags:          query.AllTags(),
 			StartTimeMin:  query.StartTimeMin,
 			StartTimeMax:  query.StartTimeMax,
 			DurationMin:   query.DurationMin,
@@ -226,7 +226,7 @@ func (c *GRPCClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQue
 		Query: &storage_v1.TraceQueryParameters{
 			ServiceName:   query.ServiceName,
 			OperationName: query.OperationName,
-			Tags:          query.Tags,
+			Tags:          query.AllTags(),
 			StartTimeMin:  query.StartTimeMin,
 			StartTimeMax:  query.StartTimeMax,
 			DurationMin:   query.DurationMin,
@@ -288,6 +288,8 @@ func (c *GRPCClient) Capabilities() (*Capabilities, error) {
 		ArchiveSpanReader:   capabilities.ArchiveSpanReader,
 		ArchiveSpanWriter:   capabilities.ArchiveSpanWriter,
 		StreamingSpanWriter: capabilities.StreamingSpanWriter,
+		AttributePredicates: capabilities.AttributePredicates,
+		Pagination:          capabilities.Pagination,
 	}, nil
 }
 
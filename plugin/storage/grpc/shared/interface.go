@@ -47,6 +47,10 @@ type Capabilities struct {
 	ArchiveSpanReader   bool
 	ArchiveSpanWriter   bool
 	StreamingSpanWriter bool
+	// AttributePredicates indicates that FindTraces and FindTraceIDs support the attributes of the query.
+	AttributePredicates bool
+	// Pagination indicates that FindTraces and FindTraceIDs support the page tokens of the query.
+	Pagination bool
 }
 
 // PluginServices defines services plugin can expose
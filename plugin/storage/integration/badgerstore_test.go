@@ -24,10 +24,12 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/badger"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 type BadgerIntegrationStorage struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 	factory *badger.Factory
 }
 
@@ -48,6 +50,10 @@ func (s *BadgerIntegrationStorage) initialize(t *testing.T) {
 	s.SpanReader, err = s.factory.CreateSpanReader()
 	require.NoError(t, err)
 
+	s.DependencyReader, err = s.factory.CreateDependencyReader()
+	require.NoError(t, err)
+	s.DependencyWriter = s.DependencyReader.(dependencystore.Writer)
+
 	s.SamplingStore, err = s.factory.CreateSamplingStore(0)
 	require.NoError(t, err)
 }
@@ -57,9 +63,9 @@ func (s *BadgerIntegrationStorage) cleanUp(t *testing.T) {
 }
 
 func TestBadgerStorage(t *testing.T) {
-	SkipUnlessEnv(t, "badger")
+	storagetest.SkipUnlessEnv(t, "badger")
 	s := &BadgerIntegrationStorage{
-		StorageIntegration: StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			SkipArchiveTest: true,
 
 			// TODO: remove this badger supports returning spanKind from GetOperations
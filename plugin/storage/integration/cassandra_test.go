@@ -27,19 +27,20 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 type CassandraStorageIntegration struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 	factory *cassandra.Factory
 }
 
 func newCassandraStorageIntegration() *CassandraStorageIntegration {
 	s := &CassandraStorageIntegration{
-		StorageIntegration: StorageIntegration{
+		StorageIntegration: storagetest.StorageIntegration{
 			GetDependenciesReturnsSource: true,
 
-			SkipList: CassandraSkippedTests,
+			SkipList: storagetest.CassandraSkippedTests,
 		},
 	}
 	s.CleanUp = s.cleanUp
@@ -103,7 +104,7 @@ func (s *CassandraStorageIntegration) initializeDependencyReaderAndWriter(t *tes
 }
 
 func TestCassandraStorage(t *testing.T) {
-	SkipUnlessEnv(t, "cassandra")
+	storagetest.SkipUnlessEnv(t, "cassandra")
 	s := newCassandraStorageIntegration()
 	s.initializeCassandra(t)
 	s.RunAll(t)
@@ -36,6 +36,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/es"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 const (
@@ -56,7 +57,7 @@ const (
 )
 
 type ESStorageIntegration struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 
 	client   *elastic.Client
 	v8Client *elasticsearch8.Client
@@ -166,13 +167,13 @@ func healthCheck() error {
 }
 
 func testElasticsearchStorage(t *testing.T, allTagsAsFields bool) {
-	SkipUnlessEnv(t, "elasticsearch", "opensearch")
+	storagetest.SkipUnlessEnv(t, "elasticsearch", "opensearch")
 	if err := healthCheck(); err != nil {
 		t.Fatal(err)
 	}
 	s := &ESStorageIntegration{
-		StorageIntegration: StorageIntegration{
-			Fixtures:        LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
+		StorageIntegration: storagetest.StorageIntegration{
+			Fixtures:        storagetest.LoadAndParseQueryTestCases(t, "fixtures/queries_es.json"),
 			SkipArchiveTest: false,
 			// TODO: remove this flag after ES supports returning spanKind
 			//  Issue https://github.com/jaegertracing/jaeger/issues/1923
@@ -192,7 +193,7 @@ func TestElasticsearchStorage_AllTagsAsObjectFields(t *testing.T) {
 }
 
 func TestElasticsearchStorage_IndexTemplates(t *testing.T) {
-	SkipUnlessEnv(t, "elasticsearch", "opensearch")
+	storagetest.SkipUnlessEnv(t, "elasticsearch", "opensearch")
 	if err := healthCheck(); err != nil {
 		t.Fatal(err)
 	}
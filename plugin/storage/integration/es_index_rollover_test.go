@@ -23,6 +23,8 @@ import (
 	"github.com/olivere/elastic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 const (
@@ -30,7 +32,7 @@ const (
 )
 
 func TestIndexRollover_FailIfILMNotPresent(t *testing.T) {
-	SkipUnlessEnv(t, "elasticsearch", "opensearch")
+	storagetest.SkipUnlessEnv(t, "elasticsearch", "opensearch")
 	client, err := createESClient()
 	require.NoError(t, err)
 	require.NoError(t, err)
@@ -46,7 +48,7 @@ func TestIndexRollover_FailIfILMNotPresent(t *testing.T) {
 }
 
 func TestIndexRollover_CreateIndicesWithILM(t *testing.T) {
-	SkipUnlessEnv(t, "elasticsearch", "opensearch")
+	storagetest.SkipUnlessEnv(t, "elasticsearch", "opensearch")
 	// Test using the default ILM Policy Name, i.e. do not pass the ES_ILM_POLICY_NAME env var to the rollover script.
 	t.Run("DefaultPolicyName", func(t *testing.T) {
 		runCreateIndicesWithILM(t, defaultILMPolicyName)
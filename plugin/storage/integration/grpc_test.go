@@ -25,10 +25,11 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 type GRPCStorageIntegrationTestSuite struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 	flags         []string
 	factory       *grpc.Factory
 	remoteStorage *RemoteMemoryStorage
@@ -71,7 +72,7 @@ func (s *GRPCStorageIntegrationTestSuite) cleanUp(t *testing.T) {
 }
 
 func TestGRPCRemoteStorage(t *testing.T) {
-	SkipUnlessEnv(t, "grpc")
+	storagetest.SkipUnlessEnv(t, "grpc")
 	s := &GRPCStorageIntegrationTestSuite{
 		flags: []string{
 			"--grpc-storage.server=localhost:17271",
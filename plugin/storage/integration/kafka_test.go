@@ -34,12 +34,13 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 const defaultLocalKafkaBroker = "127.0.0.1:9092"
 
 type KafkaIntegrationTestSuite struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 }
 
 func (s *KafkaIntegrationTestSuite) initialize(t *testing.T) {
@@ -106,8 +107,8 @@ type ingester struct {
 	traceStore *memory.Store
 }
 
-func (r *ingester) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	return r.traceStore.GetTrace(ctx, traceID)
+func (r *ingester) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	return r.traceStore.GetTrace(ctx, query)
 }
 
 func (*ingester) GetServices(context.Context) ([]string, error) {
@@ -130,8 +131,10 @@ func (*ingester) FindTraceIDs(context.Context, *spanstore.TraceQueryParameters)
 }
 
 func TestKafkaStorage(t *testing.T) {
-	SkipUnlessEnv(t, "kafka")
+	storagetest.SkipUnlessEnv(t, "kafka")
 	s := &KafkaIntegrationTestSuite{}
 	s.initialize(t)
-	t.Run("GetTrace", s.testGetTrace)
+	// the ingester reader only supports GetTrace
+	s.SkipList = []string{"GetServices", "GetOperations", "GetLargeSpans", "FindTraces"}
+	s.RunSpanStoreTests(t)
 }
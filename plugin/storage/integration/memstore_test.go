@@ -22,10 +22,11 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/storagetest"
 )
 
 type MemStorageIntegrationTestSuite struct {
-	StorageIntegration
+	storagetest.StorageIntegration
 	logger *zap.Logger
 }
 
@@ -39,14 +40,14 @@ func (s *MemStorageIntegrationTestSuite) initialize(_ *testing.T) {
 	s.SpanWriter = store
 	s.ArchiveSpanReader = archiveStore
 	s.ArchiveSpanWriter = archiveStore
-
-	// TODO DependencyWriter is not implemented in memory store
+	s.DependencyReader = store
+	s.DependencyWriter = store
 
 	s.CleanUp = s.initialize
 }
 
 func TestMemoryStorage(t *testing.T) {
-	SkipUnlessEnv(t, "memory")
+	storagetest.SkipUnlessEnv(t, "memory")
 	s := &MemStorageIntegrationTestSuite{}
 	s.initialize(t)
 	s.RunAll(t)
@@ -0,0 +1,355 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// avroSpanRecordName is the full name of the span record, used by the subject name strategies.
+const avroSpanRecordName = "jaeger.api_v2.Span"
+
+// avroSpanSchema is the Avro schema of the spans, which mirrors model.Span. The IDs are hex strings,
+// the times are in microseconds since the epoch and the durations in microseconds.
+const avroSpanSchema = `{
+  "type": "record",
+  "name": "Span",
+  "namespace": "jaeger.api_v2",
+  "fields": [
+    {"name": "traceId", "type": "string"},
+    {"name": "spanId", "type": "string"},
+    {"name": "operationName", "type": "string"},
+    {"name": "references", "type": {"type": "array", "items": {
+      "type": "record",
+      "name": "SpanRef",
+      "fields": [
+        {"name": "traceId", "type": "string"},
+        {"name": "spanId", "type": "string"},
+        {"name": "refType", "type": {"type": "enum", "name": "SpanRefType", "symbols": ["CHILD_OF", "FOLLOWS_FROM"]}}
+      ]
+    }}},
+    {"name": "flags", "type": "long"},
+    {"name": "startTime", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+    {"name": "duration", "type": "long"},
+    {"name": "tags", "type": {"type": "array", "items": {
+      "type": "record",
+      "name": "KeyValue",
+      "fields": [
+        {"name": "key", "type": "string"},
+        {"name": "value", "type": ["string", "boolean", "long", "double", "bytes"]}
+      ]
+    }}},
+    {"name": "logs", "type": {"type": "array", "items": {
+      "type": "record",
+      "name": "Log",
+      "fields": [
+        {"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+        {"name": "fields", "type": {"type": "array", "items": "KeyValue"}}
+      ]
+    }}},
+    {"name": "process", "type": {
+      "type": "record",
+      "name": "Process",
+      "fields": [
+        {"name": "serviceName", "type": "string"},
+        {"name": "tags", "type": {"type": "array", "items": "KeyValue"}}
+      ]
+    }},
+    {"name": "processId", "type": "string"},
+    {"name": "warnings", "type": {"type": "array", "items": "string"}}
+  ]
+}`
+
+// indices of the types of the union of the values of the tags
+const (
+	avroStringValue = iota
+	avroBoolValue
+	avroInt64Value
+	avroFloat64Value
+	avroBinaryValue
+)
+
+var errAvroTruncated = errors.New("truncated avro span")
+
+// avroWriter encodes values in the Avro binary encoding.
+type avroWriter struct {
+	buf []byte
+}
+
+func (w *avroWriter) long(v int64) {
+	// varint of Go is the zig-zag encoding of Avro
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *avroWriter) boolean(v bool) {
+	if v {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *avroWriter) double(v float64) {
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(v))
+}
+
+func (w *avroWriter) bytes(v []byte) {
+	w.long(int64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+func (w *avroWriter) string(v string) {
+	w.long(int64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// array writes the items in a single block, followed by the empty block ending the array.
+func (w *avroWriter) array(n int, item func(i int)) {
+	if n > 0 {
+		w.long(int64(n))
+		for i := 0; i < n; i++ {
+			item(i)
+		}
+	}
+	w.long(0)
+}
+
+func (w *avroWriter) keyValues(kvs []model.KeyValue) {
+	w.array(len(kvs), func(i int) {
+		kv := &kvs[i]
+		w.string(kv.Key)
+		switch kv.VType {
+		case model.ValueType_BOOL:
+			w.long(avroBoolValue)
+			w.boolean(kv.Bool())
+		case model.ValueType_INT64:
+			w.long(avroInt64Value)
+			w.long(kv.Int64())
+		case model.ValueType_FLOAT64:
+			w.long(avroFloat64Value)
+			w.double(kv.Float64())
+		case model.ValueType_BINARY:
+			w.long(avroBinaryValue)
+			w.bytes(kv.Binary())
+		default:
+			w.long(avroStringValue)
+			w.string(kv.VStr)
+		}
+	})
+}
+
+// avroReader decodes values in the Avro binary encoding. The first error is kept,
+// after which the reads return zero values.
+type avroReader struct {
+	buf []byte
+	err error
+}
+
+func (r *avroReader) long() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errAvroTruncated
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *avroReader) boolean() bool {
+	if r.err != nil {
+		return false
+	}
+	if len(r.buf) < 1 {
+		r.err = errAvroTruncated
+		return false
+	}
+	v := r.buf[0] != 0
+	r.buf = r.buf[1:]
+	return v
+}
+
+func (r *avroReader) double() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 8 {
+		r.err = errAvroTruncated
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf))
+	r.buf = r.buf[8:]
+	return v
+}
+
+func (r *avroReader) bytes() []byte {
+	n := r.long()
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > int64(len(r.buf)) {
+		r.err = errAvroTruncated
+		return nil
+	}
+	v := make([]byte, n)
+	copy(v, r.buf)
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *avroReader) string() string {
+	return string(r.bytes())
+}
+
+// array reads the blocks of an array, a negative count of items being followed by the size of the block.
+func (r *avroReader) array(item func()) {
+	for {
+		n := r.long()
+		if r.err != nil || n == 0 {
+			return
+		}
+		if n < 0 {
+			n = -n
+			r.long()
+		}
+		for i := int64(0); i < n && r.err == nil; i++ {
+			item()
+		}
+	}
+}
+
+func (r *avroReader) keyValues() []model.KeyValue {
+	var kvs []model.KeyValue
+	r.array(func() {
+		key := r.string()
+		switch index := r.long(); index {
+		case avroStringValue:
+			kvs = append(kvs, model.String(key, r.string()))
+		case avroBoolValue:
+			kvs = append(kvs, model.Bool(key, r.boolean()))
+		case avroInt64Value:
+			kvs = append(kvs, model.Int64(key, r.long()))
+		case avroFloat64Value:
+			kvs = append(kvs, model.Float64(key, r.double()))
+		case avroBinaryValue:
+			kvs = append(kvs, model.Binary(key, r.bytes()))
+		default:
+			if r.err == nil {
+				r.err = fmt.Errorf("invalid avro union index %d of tag %s", index, key)
+			}
+		}
+	})
+	return kvs
+}
+
+func (r *avroReader) traceID() model.TraceID {
+	s := r.string()
+	if r.err != nil {
+		return model.TraceID{}
+	}
+	traceID, err := model.TraceIDFromString(s)
+	if err != nil {
+		r.err = err
+	}
+	return traceID
+}
+
+func (r *avroReader) spanID() model.SpanID {
+	s := r.string()
+	if r.err != nil {
+		return 0
+	}
+	spanID, err := model.SpanIDFromString(s)
+	if err != nil {
+		r.err = err
+	}
+	return spanID
+}
+
+// encodeAvroSpan encodes a span with avroSpanSchema.
+func encodeAvroSpan(span *model.Span) []byte {
+	w := &avroWriter{}
+	w.string(span.TraceID.String())
+	w.string(span.SpanID.String())
+	w.string(span.OperationName)
+	w.array(len(span.References), func(i int) {
+		ref := &span.References[i]
+		w.string(ref.TraceID.String())
+		w.string(ref.SpanID.String())
+		w.long(int64(ref.RefType))
+	})
+	w.long(int64(span.Flags))
+	w.long(int64(model.TimeAsEpochMicroseconds(span.StartTime)))
+	w.long(int64(model.DurationAsMicroseconds(span.Duration)))
+	w.keyValues(span.Tags)
+	w.array(len(span.Logs), func(i int) {
+		log := &span.Logs[i]
+		w.long(int64(model.TimeAsEpochMicroseconds(log.Timestamp)))
+		w.keyValues(log.Fields)
+	})
+	w.string(span.Process.GetServiceName())
+	w.keyValues(span.Process.GetTags())
+	w.string(span.ProcessID)
+	w.array(len(span.Warnings), func(i int) {
+		w.string(span.Warnings[i])
+	})
+	return w.buf
+}
+
+// decodeAvroSpan decodes a span encoded with avroSpanSchema.
+func decodeAvroSpan(payload []byte) (*model.Span, error) {
+	r := &avroReader{buf: payload}
+	span := &model.Span{}
+	span.TraceID = r.traceID()
+	span.SpanID = r.spanID()
+	span.OperationName = r.string()
+	r.array(func() {
+		ref := model.SpanRef{TraceID: r.traceID(), SpanID: r.spanID()}
+		refType := r.long()
+		if _, ok := model.SpanRefType_name[int32(refType)]; !ok && r.err == nil {
+			r.err = fmt.Errorf("invalid avro span reference type %d", refType)
+		}
+		ref.RefType = model.SpanRefType(refType)
+		span.References = append(span.References, ref)
+	})
+	span.Flags = model.Flags(r.long())
+	span.StartTime = model.EpochMicrosecondsAsTime(uint64(r.long()))
+	span.Duration = model.MicrosecondsAsDuration(uint64(r.long()))
+	span.Tags = r.keyValues()
+	r.array(func() {
+		log := model.Log{Timestamp: model.EpochMicrosecondsAsTime(uint64(r.long()))}
+		log.Fields = r.keyValues()
+		span.Logs = append(span.Logs, log)
+	})
+	span.Process = &model.Process{ServiceName: r.string(), Tags: r.keyValues()}
+	span.ProcessID = r.string()
+	r.array(func() {
+		span.Warnings = append(span.Warnings, r.string())
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.buf) > 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after avro span", len(r.buf))
+	}
+	return span, nil
+}
+
+// sameAvroSchema tells whether two schemas are the same, regardless of their formatting.
+func sameAvroSchema(a, b string) bool {
+	var aJSON, bJSON any
+	if json.Unmarshal([]byte(a), &aJSON) != nil || json.Unmarshal([]byte(b), &bJSON) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aJSON, bJSON)
+}
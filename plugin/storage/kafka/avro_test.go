@@ -0,0 +1,180 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
+)
+
+// newTestSchemaRegistry returns a client of a schema registry holding the avro span schema as ID 1,
+// reformatted as the registry does, and another schema as ID 2.
+func newTestSchemaRegistry(t *testing.T) *schemaregistry.Client {
+	var compactSchema json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(avroSpanSchema), &compactSchema))
+	compact, err := json.Marshal(compactSchema)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/subjects/jaeger-spans-value/versions":
+			w.Write([]byte(`{"id": 1}`))
+		case "/schemas/ids/1":
+			json.NewEncoder(w).Encode(map[string]string{"schema": string(compact)})
+		case "/schemas/ids/2":
+			w.Write([]byte(`{"schema": "\"string\""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	registry, err := schemaregistry.NewClient(schemaregistry.Config{URL: server.URL}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, registry.Close()) })
+	return registry
+}
+
+func TestAvroMarshallerAndUnmarshaller(t *testing.T) {
+	testMarshallerAndUnmarshaller(t,
+		newAvroMarshaller(newTestSchemaRegistry(t), "jaeger-spans-value"),
+		NewAvroUnmarshaller(newTestSchemaRegistry(t)))
+}
+
+func TestAvroSpanAllValueTypes(t *testing.T) {
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(2),
+		OperationName: "op",
+		References: []model.SpanRef{
+			model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(3)),
+			model.NewFollowsFromRef(model.NewTraceID(4, 5), model.NewSpanID(6)),
+		},
+		StartTime: time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC),
+		Duration:  1500 * time.Microsecond,
+		Tags: []model.KeyValue{
+			model.String("string", "€"),
+			model.Bool("bool", true),
+			model.Int64("int64", -42),
+			model.Float64("float64", 3.14),
+			model.Binary("binary", []byte{0, 1, 2}),
+		},
+		Process:   &model.Process{ServiceName: "svc"},
+		ProcessID: "p1",
+		Warnings:  []string{"clock skew", "invalid tag"},
+	}
+	decoded, err := decodeAvroSpan(encodeAvroSpan(span))
+	require.NoError(t, err)
+	assert.Equal(t, span, decoded)
+}
+
+func TestAvroSpanNegativeBlockCount(t *testing.T) {
+	w := &avroWriter{}
+	w.string(model.NewTraceID(0, 1).String())
+	w.string(model.NewSpanID(2).String())
+	w.string("op")
+	w.long(0) // references
+	w.long(0) // flags
+	w.long(0) // startTime
+	w.long(0) // duration
+	// the tags in a block of -1 item followed by the size of the block
+	item := &avroWriter{}
+	item.string("k")
+	item.long(avroStringValue)
+	item.string("v")
+	w.long(-1)
+	w.long(int64(len(item.buf)))
+	w.buf = append(w.buf, item.buf...)
+	w.long(0)
+	w.long(0)    // logs
+	w.string("") // service name
+	w.long(0)    // process tags
+	w.string("") // process ID
+	w.long(0)    // warnings
+
+	span, err := decodeAvroSpan(w.buf)
+	require.NoError(t, err)
+	assert.Equal(t, []model.KeyValue{model.String("k", "v")}, span.Tags)
+}
+
+func TestAvroSpanInvalid(t *testing.T) {
+	valid := encodeAvroSpan(sampleSpan)
+
+	_, err := decodeAvroSpan(valid[:len(valid)-1])
+	require.ErrorIs(t, err, errAvroTruncated)
+
+	_, err = decodeAvroSpan(append(valid, 0))
+	require.EqualError(t, err, "1 unexpected bytes after avro span")
+
+	w := &avroWriter{}
+	w.string("not a trace ID")
+	_, err = decodeAvroSpan(w.buf)
+	require.Error(t, err)
+
+	w = &avroWriter{}
+	w.string(model.NewTraceID(0, 1).String())
+	w.string("not a span ID")
+	_, err = decodeAvroSpan(w.buf)
+	require.Error(t, err)
+
+	w = &avroWriter{}
+	w.string(model.NewTraceID(0, 1).String())
+	w.string(model.NewSpanID(2).String())
+	w.string("op")
+	w.array(1, func(int) {
+		w.string(model.NewTraceID(0, 1).String())
+		w.string(model.NewSpanID(3).String())
+		w.long(2)
+	})
+	_, err = decodeAvroSpan(w.buf)
+	require.EqualError(t, err, "invalid avro span reference type 2")
+
+	w = &avroWriter{}
+	w.string(model.NewTraceID(0, 1).String())
+	w.string(model.NewSpanID(2).String())
+	w.string("op")
+	w.long(0)
+	w.long(0)
+	w.long(0)
+	w.long(0)
+	w.array(1, func(int) {
+		w.string("k")
+		w.long(5)
+	})
+	_, err = decodeAvroSpan(w.buf)
+	require.EqualError(t, err, "invalid avro union index 5 of tag k")
+}
+
+func TestAvroUnmarshallerErrors(t *testing.T) {
+	unmarshaller := NewAvroUnmarshaller(newTestSchemaRegistry(t))
+	payload := encodeAvroSpan(sampleSpan)
+
+	_, err := unmarshaller.Unmarshal(payload)
+	require.EqualError(t, err, "message is not in the schema registry wire format")
+
+	_, err = unmarshaller.Unmarshal(schemaregistry.Encode(2, payload))
+	require.EqualError(t, err, "schema 2 is not the avro span schema")
+
+	_, err = unmarshaller.Unmarshal(schemaregistry.Encode(3, payload))
+	require.ErrorContains(t, err, "failed to fetch schema 3")
+}
+
+func TestAvroMarshallerRegistryError(t *testing.T) {
+	_, err := newAvroMarshaller(newTestSchemaRegistry(t), "other-value").Marshal(sampleSpan)
+	require.ErrorContains(t, err, "failed to register schema of subject other-value")
+}
+
+func TestSameAvroSchema(t *testing.T) {
+	assert.True(t, sameAvroSchema(`{"type": "string"}`, `{"type":"string"}`))
+	assert.False(t, sameAvroSchema(`{"type": "string"}`, `{"type": "long"}`))
+	assert.False(t, sameAvroSchema(`{`, `{`))
+}
@@ -17,13 +17,18 @@ package kafka
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"github.com/Shopify/sarama"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
 	"github.com/jaegertracing/jaeger/pkg/kafka/producer"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin"
 	"github.com/jaegertracing/jaeger/storage"
@@ -46,6 +51,9 @@ type Factory struct {
 
 	producer   sarama.AsyncProducer
 	marshaller Marshaller
+	encryptor  *encryption.Encryptor
+	registry   *schemaregistry.Client
+	headers    []sarama.RecordHeader
 	producer.Builder
 }
 
@@ -82,14 +90,51 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 		f.marshaller = newProtobufMarshaller()
 	case EncodingJSON:
 		f.marshaller = newJSONMarshaller()
+	case EncodingAvro:
+		subject, err := schemaregistry.Subject(f.options.SubjectNameStrategy, f.options.Topic, avroSpanRecordName)
+		if err != nil {
+			return err
+		}
+		f.registry, err = schemaregistry.NewClient(f.options.SchemaRegistry, logger)
+		if err != nil {
+			return err
+		}
+		f.marshaller = newAvroMarshaller(f.registry, subject)
+		logger.Info("Kafka spans encoded as avro", zap.String("schema-registry", f.options.SchemaRegistry.URL), zap.String("subject", subject))
 	default:
-		return errors.New("kafka encoding is not one of '" + EncodingJSON + "' or '" + EncodingProto + "'")
+		return errors.New("kafka encoding is not one of '" + EncodingJSON + "', '" + EncodingProto + "' or '" + EncodingAvro + "'")
+	}
+	if f.options.EncryptionKeyFile != "" {
+		keys, err := encryption.NewFileKeyProvider(f.options.EncryptionKeyFile)
+		if err != nil {
+			return err
+		}
+		f.encryptor = encryption.NewEncryptor(keys)
+		logger.Info("Kafka span payload encryption enabled", zap.String("key-file", f.options.EncryptionKeyFile))
+	}
+	switch f.options.Partitioner {
+	case "", PartitionerHash:
+	case PartitionerTraceID:
+		f.options.Config.Partitioner = NewTraceIDPartitioner
+		logger.Info("Kafka spans partitioned by trace ID ranges")
+	default:
+		return fmt.Errorf(`kafka partitioner '%s' not recognised, use one of ("%s")`,
+			f.options.Partitioner, strings.Join(AllPartitioners, "\", \""))
 	}
 	p, err := f.NewProducer(logger)
 	if err != nil {
 		return err
 	}
 	f.producer = p
+	if version := f.options.Config.ProtocolVersion; version != "" {
+		// the version was validated when creating the producer
+		if v, _ := sarama.ParseKafkaVersion(version); !v.IsAtLeast(sarama.V0_11_0_0) {
+			logger.Info("Kafka span headers disabled, they require at least Kafka v0.11", zap.String("protocol-version", version))
+			return nil
+		}
+	}
+	hostname, _ := os.Hostname()
+	f.headers = newRecordHeaders(f.options.Encoding, hostname)
 	return nil
 }
 
@@ -100,7 +145,10 @@ func (*Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return NewSpanWriter(f.producer, f.marshaller, f.options.Topic, f.metricsFactory, f.logger), nil
+	writer := NewSpanWriter(f.producer, f.marshaller, f.options.Topic, f.metricsFactory, f.logger)
+	writer.encryptor = f.encryptor
+	writer.headers = f.headers
+	return writer, nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -116,6 +164,9 @@ func (f *Factory) Close() error {
 	if f.producer != nil {
 		errs = append(errs, f.producer.Close())
 	}
+	if f.registry != nil {
+		errs = append(errs, f.registry.Close())
+	}
 	errs = append(errs, f.options.Config.TLS.Close())
 	return errors.Join(errs...)
 }
@@ -96,6 +96,138 @@ func TestKafkaFactoryEncoding(t *testing.T) {
 	}
 }
 
+func TestKafkaFactoryAvroEncoding(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--kafka.producer.encoding=avro",
+		"--kafka.producer.topic=spans",
+		"--kafka.producer.schema-registry.url=http://registry:8081",
+		"--kafka.producer.schema-registry.subject-name-strategy=topic-record",
+	}))
+	f.InitFromViper(v, zap.NewNop())
+
+	f.Builder = &mockProducerBuilder{t: t}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	require.IsType(t, &avroMarshaller{}, f.marshaller)
+	assert.Equal(t, "spans-jaeger.api_v2.Span", f.marshaller.(*avroMarshaller).subject)
+	require.NoError(t, f.Close())
+}
+
+func TestKafkaFactoryAvroEncodingErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       []string
+		expectedErr string
+	}{
+		{
+			name:        "missing schema registry",
+			flags:       []string{"--kafka.producer.encoding=avro"},
+			expectedErr: "the schema registry URL is required",
+		},
+		{
+			name: "invalid subject name strategy",
+			flags: []string{
+				"--kafka.producer.encoding=avro",
+				"--kafka.producer.schema-registry.url=http://registry:8081",
+				"--kafka.producer.schema-registry.subject-name-strategy=bad-input",
+			},
+			expectedErr: "subject name strategy 'bad-input' not recognised",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags(test.flags))
+			f.InitFromViper(v, zap.NewNop())
+
+			f.Builder = &mockProducerBuilder{t: t}
+			require.ErrorContains(t, f.Initialize(metrics.NullFactory, zap.NewNop()), test.expectedErr)
+		})
+	}
+}
+
+func TestKafkaFactoryEncryption(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--kafka.producer.encryption.key-file=" + writeEncryptionKeyFile(t)}))
+	f.InitFromViper(v, zap.NewNop())
+
+	f.Builder = &mockProducerBuilder{t: t}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	require.NotNil(t, f.encryptor)
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.Same(t, f.encryptor, writer.(*SpanWriter).encryptor)
+	require.NoError(t, f.Close())
+}
+
+func TestKafkaFactoryHeaders(t *testing.T) {
+	tests := []struct {
+		version string
+		enabled bool
+	}{
+		{version: "", enabled: true},
+		{version: "0.11.0.0", enabled: true},
+		{version: "0.10.2.0", enabled: false},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags([]string{
+				"--kafka.producer.encoding=json",
+				"--kafka.producer.protocol-version=" + test.version,
+			}))
+			f.InitFromViper(v, zap.NewNop())
+
+			f.Builder = &mockProducerBuilder{t: t}
+			require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+			writer, err := f.CreateSpanWriter()
+			require.NoError(t, err)
+			headers := writer.(*SpanWriter).headers
+			if test.enabled {
+				require.NotEmpty(t, headers)
+				assert.Equal(t, sarama.RecordHeader{Key: []byte(HeaderEncoding), Value: []byte("json")}, headers[0])
+			} else {
+				assert.Nil(t, headers)
+			}
+			require.NoError(t, f.Close())
+		})
+	}
+}
+
+func TestKafkaFactoryEncryptionKeyFileErr(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--kafka.producer.encryption.key-file=/does/not/exist"}))
+	f.InitFromViper(v, zap.NewNop())
+
+	f.Builder = &mockProducerBuilder{t: t}
+	require.ErrorContains(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "failed to read encryption key file")
+}
+
+func TestKafkaFactoryPartitioner(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--kafka.producer.partitioner=trace-id"}))
+	f.InitFromViper(v, zap.NewNop())
+	require.Nil(t, f.options.Config.Partitioner)
+
+	f.Builder = &mockProducerBuilder{t: t}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	require.NotNil(t, f.options.Config.Partitioner)
+	assert.IsType(t, traceIDPartitioner{}, f.options.Config.Partitioner("topic"))
+	require.NoError(t, f.Close())
+
+	require.NoError(t, command.ParseFlags([]string{"--kafka.producer.partitioner=round-robin"}))
+	f = NewFactory()
+	f.InitFromViper(v, zap.NewNop())
+	f.Builder = &mockProducerBuilder{t: t}
+	require.ErrorContains(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "kafka partitioner 'round-robin' not recognised")
+}
+
 func TestKafkaFactoryMarshallerErr(t *testing.T) {
 	f := NewFactory()
 	v, command := config.Viperize(f.AddFlags)
@@ -0,0 +1,37 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+// Headers of the span messages, describing their payload so that the ingester can consume topics
+// mixing encodings and tenants.
+const (
+	// HeaderTenant is the tenant of the span, absent for the spans without tenant.
+	HeaderTenant = "jaeger-tenant"
+	// HeaderEncoding is the encoding of the span, one of AllEncodings.
+	HeaderEncoding = "jaeger-encoding"
+	// HeaderSchemaVersion is the version of the span model encoded in the payload, see SchemaVersion.
+	HeaderSchemaVersion = "jaeger-schema-version"
+	// HeaderProducer is the instance that produced the span, i.e. its hostname.
+	HeaderProducer = "jaeger-producer"
+)
+
+// SchemaVersion is the version of the span model written by the span writer, which is
+// bumped on incompatible changes so that the ingesters can refuse the spans they cannot read.
+const SchemaVersion = "1"
+
+// newRecordHeaders returns the headers shared by the spans written with the encoding by the producer.
+func newRecordHeaders(encoding, producer string) []sarama.RecordHeader {
+	headers := []sarama.RecordHeader{
+		{Key: []byte(HeaderEncoding), Value: []byte(encoding)},
+		{Key: []byte(HeaderSchemaVersion), Value: []byte(SchemaVersion)},
+	}
+	if producer != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderProducer), Value: []byte(producer)})
+	}
+	return headers
+}
@@ -16,11 +16,13 @@ package kafka
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 )
 
 // Marshaller encodes a span into a byte array to be sent to Kafka
@@ -53,3 +55,22 @@ func (h *jsonMarshaller) Marshal(span *model.Span) ([]byte, error) {
 	err := h.pbMarshaller.Marshal(out, span)
 	return out.Bytes(), err
 }
+
+type avroMarshaller struct {
+	registry *schemaregistry.Client
+	subject  string
+}
+
+func newAvroMarshaller(registry *schemaregistry.Client, subject string) *avroMarshaller {
+	return &avroMarshaller{registry: registry, subject: subject}
+}
+
+// Marshal encodes a span as an Avro byte array, prefixed with the ID of its schema in the schema registry.
+// The schema is registered when the first span is encoded.
+func (m *avroMarshaller) Marshal(span *model.Span) ([]byte, error) {
+	id, err := m.registry.Register(context.Background(), m.subject, avroSpanSchema)
+	if err != nil {
+		return nil, err
+	}
+	return schemaregistry.Encode(id, encodeAvroSpan(span)), nil
+}
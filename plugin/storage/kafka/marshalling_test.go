@@ -15,13 +15,18 @@
 package kafka
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
 
@@ -45,6 +50,37 @@ func testMarshallerAndUnmarshaller(t *testing.T, marshaller Marshaller, unmarsha
 	assert.Equal(t, sampleSpan, resultSpan)
 }
 
+// writeEncryptionKeyFile writes a key file with a key for the tenant "acme" and a default key.
+func writeEncryptionKeyFile(t *testing.T) string {
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32)) }
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := `{"keys":{"acme-1":"` + key(1) + `","shared":"` + key(2) + `"},"tenants":{"acme":"acme-1"},"defaultKey":"shared"}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func newTestEncryptor(t *testing.T) *encryption.Encryptor {
+	keys, err := encryption.NewFileKeyProvider(writeEncryptionKeyFile(t))
+	require.NoError(t, err)
+	return encryption.NewEncryptor(keys)
+}
+
+func TestDecryptingUnmarshaller(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	payload, err := newProtobufMarshaller().Marshal(sampleSpan)
+	require.NoError(t, err)
+	envelope, err := encryptor.Encrypt("acme", payload)
+	require.NoError(t, err)
+
+	unmarshaller := NewDecryptingUnmarshaller(encryptor, NewProtobufUnmarshaller())
+	resultSpan, err := unmarshaller.Unmarshal(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, sampleSpan, resultSpan)
+
+	_, err = unmarshaller.Unmarshal(payload)
+	require.ErrorIs(t, err, encryption.ErrNotEncrypted)
+}
+
 func TestZipkinThriftUnmarshaller(t *testing.T) {
 	operationName := "foo"
 	bytes := zipkin.SerializeThrift(context.Background(), []*zipkincore.Span{
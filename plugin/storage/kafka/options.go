@@ -25,6 +25,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/kafka/auth"
 	"github.com/jaegertracing/jaeger/pkg/kafka/producer"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 )
 
 const (
@@ -34,6 +35,8 @@ const (
 	EncodingProto = "protobuf"
 	// EncodingZipkinThrift is used for spans encoded as Zipkin Thrift.
 	EncodingZipkinThrift = "zipkin-thrift"
+	// EncodingAvro is used for spans encoded as Avro, with their schema in a Confluent Schema Registry.
+	EncodingAvro = "avro"
 
 	configPrefix           = "kafka.producer"
 	suffixBrokers          = ".brokers"
@@ -48,6 +51,9 @@ const (
 	suffixBatchMinMessages = ".batch-min-messages"
 	suffixBatchMaxMessages = ".batch-max-messages"
 	suffixMaxMessageBytes  = ".max-message-bytes"
+	suffixPartitioner      = ".partitioner"
+	suffixEncryptionKey    = ".encryption.key-file"
+	suffixSubjectStrategy  = ".schema-registry.subject-name-strategy"
 
 	defaultBroker           = "127.0.0.1:9092"
 	defaultTopic            = "jaeger-spans"
@@ -60,11 +66,13 @@ const (
 	defaultBatchMinMessages = 0
 	defaultBatchMaxMessages = 0
 	defaultMaxMessageBytes  = 1000000 // https://github.com/IBM/sarama/blob/main/config.go#L177
+	defaultSubjectStrategy  = schemaregistry.TopicNameStrategy
+	defaultPartitioner      = PartitionerHash
 )
 
 var (
 	// AllEncodings is a list of all supported encodings.
-	AllEncodings = []string{EncodingJSON, EncodingProto, EncodingZipkinThrift}
+	AllEncodings = []string{EncodingJSON, EncodingProto, EncodingZipkinThrift, EncodingAvro}
 
 	// requiredAcks is mapping of sarama supported requiredAcks
 	requiredAcks = map[string]sarama.RequiredAcks{
@@ -112,9 +120,13 @@ var (
 
 // Options stores the configuration options for Kafka
 type Options struct {
-	Config   producer.Configuration `mapstructure:",squash"`
-	Topic    string                 `mapstructure:"topic"`
-	Encoding string                 `mapstructure:"encoding"`
+	Config              producer.Configuration `mapstructure:",squash"`
+	Topic               string                 `mapstructure:"topic"`
+	Encoding            string                 `mapstructure:"encoding"`
+	EncryptionKeyFile   string                 `mapstructure:"encryption_key_file"`
+	SchemaRegistry      schemaregistry.Config  `mapstructure:"schema_registry"`
+	SubjectNameStrategy string                 `mapstructure:"subject_name_strategy"`
+	Partitioner         string                 `mapstructure:"partitioner"`
 }
 
 // AddFlags adds flags for Options
@@ -174,9 +186,31 @@ func (*Options) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(
 		configPrefix+suffixEncoding,
 		defaultEncoding,
-		fmt.Sprintf(`Encoding of spans ("%s" or "%s") sent to kafka.`, EncodingJSON, EncodingProto),
+		fmt.Sprintf(`Encoding of spans ("%s", "%s" or "%s") sent to kafka.`, EncodingJSON, EncodingProto, EncodingAvro),
+	)
+	flagSet.String(
+		configPrefix+suffixPartitioner,
+		defaultPartitioner,
+		fmt.Sprintf(`(experimental) How spans are assigned to the partitions ("%s"): "%s" hashes their trace ID like any message key, `+
+			`"%s" splits the trace ID hashes into contiguous ranges, one per partition, which consumers can verify `+
+			`to rely on all the spans of a trace being in the same partition`,
+			strings.Join(AllPartitioners, "\", \""), PartitionerHash, PartitionerTraceID),
+	)
+	flagSet.String(
+		configPrefix+suffixEncryptionKey,
+		"",
+		"(experimental) Path to a JSON file with the AES keys encrypting span payloads per tenant. Encryption is disabled when empty.",
 	)
 
+	flagSet.String(
+		configPrefix+suffixSubjectStrategy,
+		defaultSubjectStrategy,
+		fmt.Sprintf(`(experimental) The strategy naming the subject of the avro span schema in the schema registry ("%s"): `+
+			`<topic>-value, the record name %s, or <topic>-%s`,
+			strings.Join(schemaregistry.AllSubjectNameStrategies, "\", \""), avroSpanRecordName, avroSpanRecordName),
+	)
+	schemaregistry.AddFlags(configPrefix, flagSet)
+
 	auth.AddFlags(configPrefix, flagSet)
 }
 
@@ -218,6 +252,12 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	}
 	opt.Topic = v.GetString(configPrefix + suffixTopic)
 	opt.Encoding = v.GetString(configPrefix + suffixEncoding)
+	opt.EncryptionKeyFile = v.GetString(configPrefix + suffixEncryptionKey)
+	if err := opt.SchemaRegistry.InitFromViper(configPrefix, v); err != nil {
+		log.Fatal(err)
+	}
+	opt.SubjectNameStrategy = v.GetString(configPrefix + suffixSubjectStrategy)
+	opt.Partitioner = v.GetString(configPrefix + suffixPartitioner)
 }
 
 // stripWhiteSpace removes all whitespace characters from a string
@@ -43,6 +43,10 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--kafka.producer.batch-min-messages=50",
 		"--kafka.producer.batch-max-messages=100",
 		"--kafka.producer.max-message-bytes=10485760",
+		"--kafka.producer.encryption.key-file=/etc/jaeger/keys.json",
+		"--kafka.producer.schema-registry.url=http://registry:8081",
+		"--kafka.producer.schema-registry.subject-name-strategy=record",
+		"--kafka.producer.partitioner=trace-id",
 	})
 	opts.InitFromViper(v)
 
@@ -58,6 +62,10 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, 100, opts.Config.BatchMaxMessages)
 	assert.Equal(t, 100, opts.Config.BatchMaxMessages)
 	assert.Equal(t, 10485760, opts.Config.MaxMessageBytes)
+	assert.Equal(t, "/etc/jaeger/keys.json", opts.EncryptionKeyFile)
+	assert.Equal(t, "http://registry:8081", opts.SchemaRegistry.URL)
+	assert.Equal(t, "record", opts.SubjectNameStrategy)
+	assert.Equal(t, PartitionerTraceID, opts.Partitioner)
 }
 
 func TestFlagDefaults(t *testing.T) {
@@ -77,6 +85,10 @@ func TestFlagDefaults(t *testing.T) {
 	assert.Equal(t, 0, opts.Config.BatchMinMessages)
 	assert.Equal(t, 0, opts.Config.BatchMaxMessages)
 	assert.Equal(t, defaultMaxMessageBytes, opts.Config.MaxMessageBytes)
+	assert.Empty(t, opts.EncryptionKeyFile)
+	assert.Empty(t, opts.SchemaRegistry.URL)
+	assert.Equal(t, defaultSubjectStrategy, opts.SubjectNameStrategy)
+	assert.Equal(t, PartitionerHash, opts.Partitioner)
 }
 
 func TestCompressionLevelDefaults(t *testing.T) {
@@ -178,29 +190,30 @@ func TestRequiredAcksFailures(t *testing.T) {
 func TestTLSFlags(t *testing.T) {
 	kerb := auth.KerberosConfig{ServiceName: "kafka", ConfigPath: "/etc/krb5.conf", KeyTabPath: "/etc/security/kafka.keytab"}
 	plain := auth.PlainTextConfig{Username: "", Password: "", Mechanism: "PLAIN"}
+	delegation := auth.DelegationTokenConfig{Mechanism: "SCRAM-SHA-512", RefreshBeforeExpiry: 5 * time.Minute}
 	tests := []struct {
 		flags    []string
 		expected auth.AuthenticationConfig
 	}{
 		{
 			flags:    []string{},
-			expected: auth.AuthenticationConfig{Authentication: "none", Kerberos: kerb, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "none", Kerberos: kerb, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.producer.authentication=foo"},
-			expected: auth.AuthenticationConfig{Authentication: "foo", Kerberos: kerb, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "foo", Kerberos: kerb, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.producer.authentication=kerberos", "--kafka.producer.tls.enabled=true"},
-			expected: auth.AuthenticationConfig{Authentication: "kerberos", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "kerberos", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.producer.authentication=tls"},
-			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 		{
 			flags:    []string{"--kafka.producer.authentication=tls", "--kafka.producer.tls.enabled=false"},
-			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain},
+			expected: auth.AuthenticationConfig{Authentication: "tls", Kerberos: kerb, TLS: tlscfg.Options{Enabled: true}, PlainText: plain, DelegationToken: delegation},
 		},
 	}
 
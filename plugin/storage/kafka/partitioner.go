@@ -0,0 +1,89 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// PartitionerHash distributes the spans by the sarama hash of their message key, i.e. of their trace ID.
+	PartitionerHash = "hash"
+	// PartitionerTraceID assigns contiguous ranges of trace ID hashes to the partitions, see TraceIDPartition.
+	PartitionerTraceID = "trace-id"
+)
+
+// AllPartitioners is a list of all supported partitioners.
+var AllPartitioners = []string{PartitionerHash, PartitionerTraceID}
+
+var errMissingTraceIDKey = errors.New("the kafka message has no trace ID key")
+
+// TraceIDPartition returns the partition of the spans of a trace among numPartitions partitions.
+// The space of the 64-bit hashes of the trace IDs is split into numPartitions contiguous ranges
+// of the same size, so that all the spans of a trace are written to the same partition, as long
+// as the number of partitions does not change.
+func TraceIDPartition(traceID model.TraceID, numPartitions int32) int32 {
+	partition, _ := bits.Mul64(hashTraceID(traceID), uint64(numPartitions))
+	return int32(partition)
+}
+
+// hashTraceID mixes the trace ID with the finalizer of MurmurHash3, so that the high bits of the
+// hash, which select the range, depend on all the bits of trace IDs that are not random, e.g.
+// sequential or prefixed with a timestamp.
+func hashTraceID(traceID model.TraceID) uint64 {
+	return fmix64(fmix64(traceID.High) ^ traceID.Low)
+}
+
+func fmix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// KeyPartition returns the partition of the message with the given key, which is the trace ID
+// of the span written by SpanWriter, as assigned by the trace ID partitioner.
+func KeyPartition(key []byte, numPartitions int32) (int32, error) {
+	if len(key) == 0 {
+		return 0, errMissingTraceIDKey
+	}
+	traceID, err := model.TraceIDFromString(string(key))
+	if err != nil {
+		return 0, fmt.Errorf("the kafka message key is not a trace ID: %w", err)
+	}
+	return TraceIDPartition(traceID, numPartitions), nil
+}
+
+type traceIDPartitioner struct{}
+
+// NewTraceIDPartitioner creates a sarama.Partitioner writing the messages of SpanWriter
+// to the partitions returned by TraceIDPartition. It implements sarama.PartitionerConstructor.
+func NewTraceIDPartitioner(string) sarama.Partitioner {
+	return traceIDPartitioner{}
+}
+
+func (traceIDPartitioner) Partition(message *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if message.Key == nil {
+		return 0, errMissingTraceIDKey
+	}
+	key, err := message.Key.Encode()
+	if err != nil {
+		return 0, err
+	}
+	return KeyPartition(key, numPartitions)
+}
+
+// RequiresConsistency is true so that the messages are not written to other partitions
+// when their partition is unavailable.
+func (traceIDPartitioner) RequiresConsistency() bool {
+	return true
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestTraceIDPartition(t *testing.T) {
+	const numPartitions = 8
+	counts := make([]int, numPartitions)
+	for i := uint64(0); i < 8000; i++ {
+		// sequential trace IDs must be spread too
+		partition := TraceIDPartition(model.NewTraceID(0, i), numPartitions)
+		require.GreaterOrEqual(t, partition, int32(0))
+		require.Less(t, partition, int32(numPartitions))
+		counts[partition]++
+	}
+	for _, count := range counts {
+		assert.InDelta(t, 1000, count, 150)
+	}
+
+	traceID := model.NewTraceID(1, 2)
+	assert.Equal(t, TraceIDPartition(traceID, numPartitions), TraceIDPartition(traceID, numPartitions))
+	assert.Equal(t, int32(0), TraceIDPartition(traceID, 1))
+}
+
+func TestTraceIDPartitioner(t *testing.T) {
+	partitioner := NewTraceIDPartitioner("topic")
+	assert.True(t, partitioner.RequiresConsistency())
+
+	traceID := model.NewTraceID(1, 2)
+	partition, err := partitioner.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder(traceID.String())}, 16)
+	require.NoError(t, err)
+	assert.Equal(t, TraceIDPartition(traceID, 16), partition)
+
+	keyPartition, err := KeyPartition([]byte(traceID.String()), 16)
+	require.NoError(t, err)
+	assert.Equal(t, partition, keyPartition)
+
+	_, err = partitioner.Partition(&sarama.ProducerMessage{}, 16)
+	require.ErrorIs(t, err, errMissingTraceIDKey)
+	_, err = partitioner.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder("not-a-trace-id")}, 16)
+	require.ErrorContains(t, err, "the kafka message key is not a trace ID")
+	_, err = KeyPartition(nil, 16)
+	require.ErrorIs(t, err, errMissingTraceIDKey)
+}
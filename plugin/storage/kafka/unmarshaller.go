@@ -17,12 +17,16 @@ package kafka
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
+	"github.com/jaegertracing/jaeger/pkg/kafka/schemaregistry"
 )
 
 // Unmarshaller decodes a byte array to a span
@@ -80,3 +84,56 @@ func (*ZipkinThriftUnmarshaller) Unmarshal(msg []byte) (*model.Span, error) {
 	}
 	return mSpans[0], err
 }
+
+// AvroUnmarshaller implements Unmarshaller
+type AvroUnmarshaller struct {
+	registry *schemaregistry.Client
+	// schemaIDs holds the IDs of the schemas known to be the span schema
+	schemaIDs sync.Map
+}
+
+// NewAvroUnmarshaller constructs an AvroUnmarshaller resolving the schemas of the messages with the schema registry
+func NewAvroUnmarshaller(registry *schemaregistry.Client) *AvroUnmarshaller {
+	return &AvroUnmarshaller{registry: registry}
+}
+
+// Unmarshal decodes an Avro byte array, prefixed with the ID of its schema, to a span.
+// Only the spans written with the span schema of Jaeger are accepted.
+func (u *AvroUnmarshaller) Unmarshal(msg []byte) (*model.Span, error) {
+	id, payload, err := schemaregistry.Decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := u.schemaIDs.Load(id); !ok {
+		schema, err := u.registry.Schema(context.Background(), id)
+		if err != nil {
+			return nil, err
+		}
+		if !sameAvroSchema(schema, avroSpanSchema) {
+			return nil, fmt.Errorf("schema %d is not the avro span schema", id)
+		}
+		u.schemaIDs.Store(id, struct{}{})
+	}
+	return decodeAvroSpan(payload)
+}
+
+// DecryptingUnmarshaller decrypts the span payloads before decoding them with another Unmarshaller.
+// Payloads that are not encrypted are rejected.
+type DecryptingUnmarshaller struct {
+	encryptor    *encryption.Encryptor
+	unmarshaller Unmarshaller
+}
+
+// NewDecryptingUnmarshaller constructs a DecryptingUnmarshaller
+func NewDecryptingUnmarshaller(encryptor *encryption.Encryptor, unmarshaller Unmarshaller) *DecryptingUnmarshaller {
+	return &DecryptingUnmarshaller{encryptor: encryptor, unmarshaller: unmarshaller}
+}
+
+// Unmarshal decrypts and decodes a byte array to a span
+func (u *DecryptingUnmarshaller) Unmarshal(msg []byte) (*model.Span, error) {
+	payload, err := u.encryptor.Decrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	return u.unmarshaller.Unmarshal(payload)
+}
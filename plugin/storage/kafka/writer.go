@@ -16,12 +16,15 @@ package kafka
 
 import (
 	"context"
+	"slices"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 type spanWriterMetrics struct {
@@ -35,6 +38,11 @@ type SpanWriter struct {
 	producer   sarama.AsyncProducer
 	marshaller Marshaller
 	topic      string
+	// encryptor encrypts the span payloads with the key of their tenant, when set.
+	encryptor *encryption.Encryptor
+	// headers are set on every message, along with the tenant of the span. They are nil
+	// when the protocol version of the brokers does not support headers.
+	headers []sarama.RecordHeader
 }
 
 // NewSpanWriter initiates and returns a new kafka spanwriter
@@ -73,20 +81,32 @@ func NewSpanWriter(
 }
 
 // WriteSpan writes the span to kafka.
-func (w *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
+func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	spanBytes, err := w.marshaller.Marshal(span)
 	if err != nil {
 		w.metrics.SpansWrittenFailure.Inc(1)
 		return err
 	}
+	if w.encryptor != nil {
+		spanBytes, err = w.encryptor.Encrypt(tenancy.GetTenant(ctx), spanBytes)
+		if err != nil {
+			w.metrics.SpansWrittenFailure.Inc(1)
+			return err
+		}
+	}
 
 	// The AsyncProducer accepts messages on a channel and produces them asynchronously
 	// in the background as efficiently as possible
-	w.producer.Input() <- &sarama.ProducerMessage{
-		Topic: w.topic,
-		Key:   sarama.StringEncoder(span.TraceID.String()),
-		Value: sarama.ByteEncoder(spanBytes),
+	message := &sarama.ProducerMessage{
+		Topic:   w.topic,
+		Key:     sarama.StringEncoder(span.TraceID.String()),
+		Value:   sarama.ByteEncoder(spanBytes),
+		Headers: w.headers,
+	}
+	if tenant := tenancy.GetTenant(ctx); tenant != "" && w.headers != nil {
+		message.Headers = append(slices.Clip(w.headers), sarama.RecordHeader{Key: []byte(HeaderTenant), Value: []byte(tenant)})
 	}
+	w.producer.Input() <- message
 	return nil
 }
 
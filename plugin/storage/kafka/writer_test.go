@@ -22,12 +22,15 @@ import (
 
 	"github.com/Shopify/sarama"
 	saramaMocks "github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/kafka/encryption"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -183,3 +186,91 @@ func TestMarshallerErr(t *testing.T) {
 			})
 	})
 }
+
+func TestKafkaWriterEncryption(t *testing.T) {
+	withSpanWriter(t, func(span *model.Span, w *spanWriterTest) {
+		encryptor := newTestEncryptor(t)
+		w.writer.encryptor = encryptor
+		var written []byte
+		w.producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			value, err := msg.Value.Encode()
+			written = value
+			return err
+		})
+
+		err := w.writer.WriteSpan(tenancy.WithTenant(context.Background(), "acme"), span)
+		require.NoError(t, err)
+		w.writer.Close()
+
+		payload, err := encryptor.Decrypt(written)
+		require.NoError(t, err)
+		assert.Empty(t, payload)
+		assert.Contains(t, string(written), "acme-1")
+	})
+}
+
+func TestKafkaWriterEncryptionErr(t *testing.T) {
+	withSpanWriter(t, func(span *model.Span, w *spanWriterTest) {
+		keys := &failingKeyProvider{}
+		w.writer.encryptor = encryption.NewEncryptor(keys)
+
+		err := w.writer.WriteSpan(context.Background(), span)
+		require.EqualError(t, err, "no key")
+
+		w.writer.Close()
+
+		w.metricsFactory.AssertCounterMetrics(t,
+			metricstest.ExpectedMetric{
+				Name:  "kafka_spans_written",
+				Tags:  map[string]string{"status": "failure"},
+				Value: 1,
+			})
+	})
+}
+
+func TestKafkaWriterHeaders(t *testing.T) {
+	withSpanWriter(t, func(span *model.Span, w *spanWriterTest) {
+		w.writer.headers = newRecordHeaders(EncodingJSON, "collector-1")
+		var headers [][]sarama.RecordHeader
+		checker := func(msg *sarama.ProducerMessage) error {
+			headers = append(headers, msg.Headers)
+			return nil
+		}
+		w.producer.ExpectInputWithMessageCheckerFunctionAndSucceed(checker)
+		w.producer.ExpectInputWithMessageCheckerFunctionAndSucceed(checker)
+
+		require.NoError(t, w.writer.WriteSpan(tenancy.WithTenant(context.Background(), "acme"), span))
+		require.NoError(t, w.writer.WriteSpan(context.Background(), span))
+		w.writer.Close()
+
+		expected := []sarama.RecordHeader{
+			{Key: []byte(HeaderEncoding), Value: []byte("json")},
+			{Key: []byte(HeaderSchemaVersion), Value: []byte(SchemaVersion)},
+			{Key: []byte(HeaderProducer), Value: []byte("collector-1")},
+		}
+		require.Len(t, headers, 2)
+		assert.Equal(t, append(expected, sarama.RecordHeader{Key: []byte(HeaderTenant), Value: []byte("acme")}), headers[0])
+		assert.Equal(t, expected, headers[1], "the tenant header is not shared by the messages")
+	})
+}
+
+func TestKafkaWriterHeadersDisabled(t *testing.T) {
+	withSpanWriter(t, func(span *model.Span, w *spanWriterTest) {
+		w.producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			assert.Nil(t, msg.Headers)
+			return nil
+		})
+		require.NoError(t, w.writer.WriteSpan(tenancy.WithTenant(context.Background(), "acme"), span))
+		w.writer.Close()
+	})
+}
+
+type failingKeyProvider struct{}
+
+func (*failingKeyProvider) EncryptionKey(string) (string, []byte, error) {
+	return "", nil, errors.New("no key")
+}
+
+func (*failingKeyProvider) DecryptionKey(string) ([]byte, error) {
+	return nil, errors.New("no key")
+}
@@ -14,7 +14,12 @@
 
 package memory
 
+import "time"
+
 // Configuration describes the options to customize the storage behavior
 type Configuration struct {
 	MaxTraces int `mapstructure:"max_traces"`
+	// DependenciesCacheTTL is how long the dependencies aggregated from the spans are cached.
+	// Caching is disabled when it is zero.
+	DependenciesCacheTTL time.Duration `mapstructure:"dependencies_cache_ttl"`
 }
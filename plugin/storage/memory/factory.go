@@ -16,6 +16,7 @@
 package memory
 
 import (
+	"context"
 	"flag"
 
 	"github.com/spf13/viper"
@@ -35,6 +36,7 @@ var ( // interface comformance checks
 	_ storage.Factory              = (*Factory)(nil)
 	_ storage.ArchiveFactory       = (*Factory)(nil)
 	_ storage.SamplingStoreFactory = (*Factory)(nil)
+	_ storage.Purger               = (*Factory)(nil)
 	_ plugin.Configurable          = (*Factory)(nil)
 )
 
@@ -44,6 +46,8 @@ type Factory struct {
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 	store          *Store
+	// dependencyStore is the store, possibly caching the dependencies aggregated from the spans
+	dependencyStore dependencystore.ReaderWriter
 }
 
 // NewFactory creates a new Factory.
@@ -82,6 +86,10 @@ func (f *Factory) configureFromOptions(opts Options) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
 	f.store = WithConfiguration(f.options.Configuration)
+	f.dependencyStore = f.store
+	if ttl := f.options.Configuration.DependenciesCacheTTL; ttl > 0 {
+		f.dependencyStore = dependencystore.NewCachedStore(f.store, ttl)
+	}
 	logger.Info("Memory storage initialized", zap.Any("configuration", f.store.defaultConfig))
 	f.publishOpts()
 
@@ -110,7 +118,7 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
-	return f.store, nil
+	return f.dependencyStore, nil
 }
 
 // CreateSamplingStore implements storage.SamplingStoreFactory
@@ -123,6 +131,11 @@ func (*Factory) CreateLock() (distributedlock.Lock, error) {
 	return &lock{}, nil
 }
 
+// Purge implements storage.Purger
+func (f *Factory) Purge(ctx context.Context) error {
+	return f.store.purge(ctx)
+}
+
 func (f *Factory) publishOpts() {
 	safeexpvar.SetInt("jaeger_storage_memory_max_traces", int64(f.options.Configuration.MaxTraces))
 }
@@ -16,16 +16,20 @@
 package memory
 
 import (
+	"context"
 	"expvar"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 var _ storage.Factory = new(Factory)
@@ -51,6 +55,37 @@ func TestMemoryStorageFactory(t *testing.T) {
 	assert.NotNil(t, lock)
 }
 
+func TestMemoryStorageFactoryDependenciesCache(t *testing.T) {
+	f := NewFactoryWithConfig(Configuration{DependenciesCacheTTL: time.Minute}, metrics.NullFactory, zap.NewNop())
+	depReader, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+	require.IsType(t, &dependencystore.CachedStore{}, depReader)
+
+	endTs := time.Now()
+	links := []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}
+	require.NoError(t, depReader.(dependencystore.Writer).WriteDependencies(endTs, links))
+	got, err := depReader.GetDependencies(context.Background(), endTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, links, got)
+}
+
+func TestPurge(t *testing.T) {
+	f := NewFactory()
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	require.NoError(t, f.store.WriteSpan(context.Background(), &model.Span{
+		TraceID: model.NewTraceID(0, 1),
+		Process: model.NewProcess("svc", nil),
+	}))
+	services, err := f.store.GetServices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc"}, services)
+
+	require.NoError(t, f.Purge(context.Background()))
+	services, err = f.store.GetServices(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, services)
+}
+
 func TestWithConfiguration(t *testing.T) {
 	f := NewFactory()
 	v, command := config.Viperize(f.AddFlags)
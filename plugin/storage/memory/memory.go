@@ -46,9 +46,19 @@ type Tenant struct {
 	traces     map[model.TraceID]*model.Trace
 	services   map[string]struct{}
 	operations map[string]map[spanstore.Operation]struct{}
-	deduper    adjuster.Adjuster
-	config     Configuration
-	index      int
+	// serviceIndex and operationIndex, by service, index the names for SearchServices and SearchOperations
+	serviceIndex   *trigramIndex
+	operationIndex map[string]*trigramIndex
+	deduper        adjuster.Adjuster
+	config         Configuration
+	index          int
+	// dependencies written by WriteDependencies, in addition to those aggregated from the spans
+	dependencies []writtenDependencies
+}
+
+type writtenDependencies struct {
+	ts    time.Time
+	links []model.DependencyLink
 }
 
 // NewStore creates an unbounded in-memory store
@@ -66,12 +76,14 @@ func WithConfiguration(cfg Configuration) *Store {
 
 func newTenant(cfg Configuration) *Tenant {
 	return &Tenant{
-		ids:        make([]*model.TraceID, cfg.MaxTraces),
-		traces:     map[model.TraceID]*model.Trace{},
-		services:   map[string]struct{}{},
-		operations: map[string]map[spanstore.Operation]struct{}{},
-		deduper:    adjuster.SpanIDDeduper(),
-		config:     cfg,
+		ids:            make([]*model.TraceID, cfg.MaxTraces),
+		traces:         map[model.TraceID]*model.Trace{},
+		services:       map[string]struct{}{},
+		operations:     map[string]map[spanstore.Operation]struct{}{},
+		serviceIndex:   newTrigramIndex(),
+		operationIndex: map[string]*trigramIndex{},
+		deduper:        adjuster.SpanIDDeduper(),
+		config:         cfg,
 	}
 }
 
@@ -92,13 +104,31 @@ func (st *Store) getTenant(tenantID string) *Tenant {
 	return tenant
 }
 
-// GetDependencies returns dependencies between services
+// purge removes all traces of all tenants.
+func (st *Store) purge(context.Context) error {
+	st.Lock()
+	st.perTenant = make(map[string]*Tenant)
+	st.Unlock()
+	return nil
+}
+
+// GetDependencies returns dependencies between services, aggregated from the spans of the traces
+// in the time window and from the dependencies written for the time window.
+// Links of different sources are returned separately.
 func (st *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	m := st.getTenant(tenancy.GetTenant(ctx))
 	// deduper used below can modify the spans, so we take an exclusive lock
 	m.Lock()
 	defer m.Unlock()
 	deps := map[string]*model.DependencyLink{}
+	addLink := func(link model.DependencyLink) {
+		depKey := link.Parent + "&&&" + link.Child + "&&&" + link.Source
+		if dep, ok := deps[depKey]; ok {
+			dep.CallCount += link.CallCount
+		} else {
+			deps[depKey] = &link
+		}
+	}
 	startTs := endTs.Add(-1 * lookback)
 	for _, orig := range m.traces {
 		// SpanIDDeduper never returns an err
@@ -110,20 +140,22 @@ func (st *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback
 					if parentSpan.Process.ServiceName == s.Process.ServiceName {
 						continue
 					}
-					depKey := parentSpan.Process.ServiceName + "&&&" + s.Process.ServiceName
-					if _, ok := deps[depKey]; !ok {
-						deps[depKey] = &model.DependencyLink{
-							Parent:    parentSpan.Process.ServiceName,
-							Child:     s.Process.ServiceName,
-							CallCount: 1,
-						}
-					} else {
-						deps[depKey].CallCount++
-					}
+					addLink(model.DependencyLink{
+						Parent:    parentSpan.Process.ServiceName,
+						Child:     s.Process.ServiceName,
+						CallCount: 1,
+					})
 				}
 			}
 		}
 	}
+	for _, written := range m.dependencies {
+		if written.ts.After(startTs) && !written.ts.After(endTs) {
+			for _, link := range written.links {
+				addLink(link)
+			}
+		}
+	}
 	retMe := make([]model.DependencyLink, 0, len(deps))
 	for _, dep := range deps {
 		retMe = append(retMe, *dep)
@@ -131,6 +163,19 @@ func (st *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback
 	return retMe, nil
 }
 
+// WriteDependencies stores dependencies between services computed elsewhere, e.g. by a batch job,
+// implementing dependencystore.Writer. Since no context is given, they are stored for the default tenant.
+func (st *Store) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	m := st.getTenant(tenancy.GetTenant(context.Background()))
+	m.Lock()
+	defer m.Unlock()
+	m.dependencies = append(m.dependencies, writtenDependencies{
+		ts:    ts,
+		links: append([]model.DependencyLink(nil), dependencies...),
+	})
+	return nil
+}
+
 func findSpan(trace *model.Trace, spanID model.SpanID) *model.Span {
 	for _, s := range trace.Spans {
 		if s.SpanID == spanID {
@@ -166,9 +211,13 @@ func (st *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 
 	if _, ok := m.operations[span.Process.ServiceName][operation]; !ok {
 		m.operations[span.Process.ServiceName][operation] = struct{}{}
+		m.indexOperation(span.Process.ServiceName, operation.Name)
 	}
 
-	m.services[span.Process.ServiceName] = struct{}{}
+	if _, ok := m.services[span.Process.ServiceName]; !ok {
+		m.services[span.Process.ServiceName] = struct{}{}
+		m.serviceIndex.add(span.Process.ServiceName)
+	}
 	if _, ok := m.traces[span.TraceID]; !ok {
 		m.traces[span.TraceID] = &model.Trace{}
 
@@ -192,12 +241,38 @@ func (st *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 	return nil
 }
 
+// DeleteTraces deletes the given traces, implementing spanstore.TraceDeleter.
+// The services and operations of the deleted traces are kept.
+func (st *Store) DeleteTraces(ctx context.Context, traceIDs []model.TraceID) error {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.Lock()
+	defer m.Unlock()
+	deleted := make(map[model.TraceID]struct{}, len(traceIDs))
+	for _, traceID := range traceIDs {
+		if _, ok := m.traces[traceID]; ok {
+			delete(m.traces, traceID)
+			deleted[traceID] = struct{}{}
+		}
+	}
+	// free the slots of the deleted traces in the ring, lest the eviction of a slot
+	// removes a trace written again with the same ID
+	for i, id := range m.ids {
+		if id == nil {
+			continue
+		}
+		if _, ok := deleted[*id]; ok {
+			m.ids[i] = nil
+		}
+	}
+	return nil
+}
+
 // GetTrace gets a trace
-func (st *Store) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+func (st *Store) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
 	m := st.getTenant(tenancy.GetTenant(ctx))
 	m.RLock()
 	defer m.RUnlock()
-	trace, ok := m.traces[traceID]
+	trace, ok := m.traces[query.TraceID]
 	if !ok {
 		return nil, spanstore.ErrTraceNotFound
 	}
@@ -247,6 +322,50 @@ func (st *Store) GetOperations(
 	return retMe, nil
 }
 
+// SearchServices implements spanstore.NameSearcher#SearchServices with a trigram index of the services
+func (st *Store) SearchServices(ctx context.Context, search spanstore.NameSearchParameters) ([]string, error) {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	return m.serviceIndex.search(search), nil
+}
+
+// SearchOperations implements spanstore.NameSearcher#SearchOperations with a trigram index of the operations of each service
+func (st *Store) SearchOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+	search spanstore.NameSearchParameters,
+) ([]spanstore.Operation, error) {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	idx, ok := m.operationIndex[query.ServiceName]
+	if !ok {
+		return nil, nil
+	}
+	byName := make(map[string][]spanstore.Operation)
+	for operation := range m.operations[query.ServiceName] {
+		if query.SpanKind == "" || query.SpanKind == operation.SpanKind {
+			byName[operation.Name] = append(byName[operation.Name], operation)
+		}
+	}
+	var retMe []spanstore.Operation
+	for _, name := range idx.search(search) {
+		retMe = append(retMe, byName[name]...)
+	}
+	return retMe, nil
+}
+
+// indexOperation adds the name of an operation to the index of the service.
+func (m *Tenant) indexOperation(service, name string) {
+	idx, ok := m.operationIndex[service]
+	if !ok {
+		idx = newTrigramIndex()
+		m.operationIndex[service] = idx
+	}
+	idx.add(name)
+}
+
 // FindTraces returns all traces in the query parameters are satisfied by a trace's span
 func (st *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	m := st.getTenant(tenancy.GetTenant(ctx))
@@ -319,7 +438,7 @@ func validSpan(span *model.Span, query *spanstore.TraceQueryParameters) bool {
 		return false
 	}
 	spanKVs := flattenTags(span)
-	for queryK, queryV := range query.Tags {
+	for queryK, queryV := range query.AllTags() {
 		// (NB): we cannot use the KeyValues.FindKey function because there can be multiple tags with the same key
 		if _, ok := findKeyValueMatch(spanKVs, queryK, queryV); !ok {
 			return false
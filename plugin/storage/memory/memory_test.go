@@ -160,6 +160,33 @@ func TestStoreGetDependencies(t *testing.T) {
 	})
 }
 
+func TestStoreWriteDependencies(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		require.NoError(t, store.WriteSpan(context.Background(), testingSpan))
+		require.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+		endTs := time.Unix(0, 0).Add(time.Hour)
+		require.NoError(t, store.WriteDependencies(endTs.Add(-time.Minute), []model.DependencyLink{
+			{Parent: "serviceName", Child: "childService", CallCount: 3},
+			{Parent: "childService", Child: "db", CallCount: 5, Source: model.JaegerDependencyLinkSource},
+		}))
+		require.NoError(t, store.WriteDependencies(endTs.Add(-2*time.Hour), []model.DependencyLink{
+			{Parent: "serviceName", Child: "cache", CallCount: 1},
+		}))
+
+		links, err := store.GetDependencies(context.Background(), endTs, time.Hour)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []model.DependencyLink{
+			{Parent: "serviceName", Child: "childService", CallCount: 4},
+			{Parent: "childService", Child: "db", CallCount: 5, Source: model.JaegerDependencyLinkSource},
+		}, links)
+
+		// written dependencies are stored for the default tenant
+		links, err = store.GetDependencies(tenancy.WithTenant(context.Background(), "acme"), endTs, time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, links)
+	})
+}
+
 func TestStoreWriteSpan(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		err := store.WriteSpan(context.Background(), testingSpan)
@@ -196,9 +223,43 @@ func TestStoreWithLimit(t *testing.T) {
 	assert.Len(t, store.getTenant("").ids, maxTraces)
 }
 
+func TestStoreDeleteTraces(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		other := model.NewTraceID(0, 42)
+		require.NoError(t, store.DeleteTraces(context.Background(), []model.TraceID{testingSpan.TraceID, other}))
+		_, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+		services, err := store.GetServices(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{testingSpan.Process.ServiceName}, services)
+	})
+}
+
+func TestStoreDeleteTracesWithLimit(t *testing.T) {
+	store := WithConfiguration(Configuration{MaxTraces: 2})
+	write := func(low uint64) {
+		require.NoError(t, store.WriteSpan(context.Background(), &model.Span{
+			TraceID: model.NewTraceID(1, low),
+			Process: &model.Process{ServiceName: "svc"},
+		}))
+	}
+	write(1)
+	write(2)
+	require.NoError(t, store.DeleteTraces(context.Background(), []model.TraceID{model.NewTraceID(1, 1)}))
+	// the trace written again must not be evicted by the slot it had before its deletion
+	write(1)
+	write(3)
+	assert.Len(t, store.getTenant("").traces, 2)
+	_, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(1, 1)})
+	require.NoError(t, err)
+	_, err = store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.NewTraceID(1, 2)})
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
 func TestStoreGetTraceSuccess(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
-		trace, err := store.GetTrace(context.Background(), testingSpan.TraceID)
+		trace, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.NoError(t, err)
 		assert.Len(t, trace.Spans, 1)
 		assert.Equal(t, testingSpan, trace.Spans[0])
@@ -207,7 +268,7 @@ func TestStoreGetTraceSuccess(t *testing.T) {
 
 func TestStoreGetAndMutateTrace(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
-		trace, err := store.GetTrace(context.Background(), testingSpan.TraceID)
+		trace, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.NoError(t, err)
 		assert.Len(t, trace.Spans, 1)
 		assert.Equal(t, testingSpan, trace.Spans[0])
@@ -215,7 +276,7 @@ func TestStoreGetAndMutateTrace(t *testing.T) {
 
 		trace.Spans[0].Warnings = append(trace.Spans[0].Warnings, "the end is near")
 
-		trace, err = store.GetTrace(context.Background(), testingSpan.TraceID)
+		trace, err = store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.NoError(t, err)
 		assert.Len(t, trace.Spans, 1)
 		assert.Equal(t, testingSpan, trace.Spans[0])
@@ -228,14 +289,14 @@ func TestStoreGetTraceError(t *testing.T) {
 		store.getTenant("").traces[testingSpan.TraceID] = &model.Trace{
 			Spans: []*model.Span{nonSerializableSpan},
 		}
-		_, err := store.GetTrace(context.Background(), testingSpan.TraceID)
+		_, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.Error(t, err)
 	})
 }
 
 func TestStoreGetTraceFailure(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
-		trace, err := store.GetTrace(context.Background(), model.TraceID{})
+		trace, err := store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 		require.EqualError(t, err, spanstore.ErrTraceNotFound.Error())
 		assert.Nil(t, trace)
 	})
@@ -314,6 +375,29 @@ func TestStoreFindTracesError(t *testing.T) {
 	})
 }
 
+func TestStoreFindTracesByServiceNamespace(t *testing.T) {
+	memStore := NewStore()
+	for i, namespace := range []string{"shop", "billing"} {
+		span := &model.Span{
+			TraceID:   model.NewTraceID(1, uint64(i)),
+			SpanID:    model.NewSpanID(1),
+			StartTime: time.Unix(300, 0),
+			Process: model.NewProcess("checkout", []model.KeyValue{
+				model.String(model.ServiceNamespaceKey, namespace),
+			}),
+		}
+		require.NoError(t, memStore.WriteSpan(context.Background(), span))
+	}
+
+	traces, err := memStore.FindTraces(context.Background(), &spanstore.TraceQueryParameters{
+		ServiceName:      "checkout",
+		ServiceNamespace: "billing",
+	})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, model.NewTraceID(1, 1), traces[0].Spans[0].TraceID)
+}
+
 func TestStoreFindTracesLimitGetsMostRecent(t *testing.T) {
 	storeSize, querySize := 100, 10
 
@@ -459,12 +543,12 @@ func TestTenantStore(t *testing.T) {
 		require.NoError(t, store.WriteSpan(ctxWonka, testingSpan2))
 
 		// Can we retrieve the spans with correct tenancy
-		trace1, err := store.GetTrace(ctxAcme, testingSpan.TraceID)
+		trace1, err := store.GetTrace(ctxAcme, spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.NoError(t, err)
 		assert.Len(t, trace1.Spans, 1)
 		assert.Equal(t, testingSpan, trace1.Spans[0])
 
-		trace2, err := store.GetTrace(ctxWonka, testingSpan2.TraceID)
+		trace2, err := store.GetTrace(ctxWonka, spanstore.GetTraceParameters{TraceID: testingSpan2.TraceID})
 		require.NoError(t, err)
 		assert.Len(t, trace2.Spans, 1)
 		assert.Equal(t, testingSpan2, trace2.Spans[0])
@@ -487,13 +571,13 @@ func TestTenantStore(t *testing.T) {
 		assert.Equal(t, testingSpan2, traces2[0].Spans[0])
 
 		// Do the spans fail with incorrect tenancy?
-		_, err = store.GetTrace(ctxAcme, testingSpan2.TraceID)
+		_, err = store.GetTrace(ctxAcme, spanstore.GetTraceParameters{TraceID: testingSpan2.TraceID})
 		require.Error(t, err)
 
-		_, err = store.GetTrace(ctxWonka, testingSpan.TraceID)
+		_, err = store.GetTrace(ctxWonka, spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.Error(t, err)
 
-		_, err = store.GetTrace(context.Background(), testingSpan.TraceID)
+		_, err = store.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: testingSpan.TraceID})
 		require.Error(t, err)
 	})
 }
@@ -0,0 +1,71 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"math"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// trigramIndex indexes names by their trigrams, so that searching a few names among
+// thousands does not compute the similarity of each of them. Names are never removed,
+// like the services and operations of the store.
+type trigramIndex struct {
+	names    []string
+	indexed  map[string]struct{}
+	postings map[string][]int // trigram -> indices of the names containing it
+}
+
+func newTrigramIndex() *trigramIndex {
+	return &trigramIndex{
+		indexed:  make(map[string]struct{}),
+		postings: make(map[string][]int),
+	}
+}
+
+// add indexes a name, unless it is already indexed, e.g. for an operation with several span kinds.
+func (idx *trigramIndex) add(name string) {
+	if _, ok := idx.indexed[name]; ok {
+		return
+	}
+	idx.indexed[name] = struct{}{}
+	i := len(idx.names)
+	idx.names = append(idx.names, name)
+	for _, trigram := range spanstore.Trigrams(name) {
+		idx.postings[trigram] = append(idx.postings[trigram], i)
+	}
+}
+
+// search returns the indexed names matching the search, best matches first.
+func (idx *trigramIndex) search(search spanstore.NameSearchParameters) []string {
+	return spanstore.FilterNames(search, idx.candidates(search))
+}
+
+// candidates returns the names that can match the search. A name starting with the text
+// contains all its trigrams, and a fuzzy match contains at least MinFuzzySimilarity of them.
+// Texts shorter than a trigram can match any name.
+func (idx *trigramIndex) candidates(search spanstore.NameSearchParameters) []string {
+	trigrams := spanstore.Trigrams(search.Text)
+	if len(trigrams) == 0 {
+		return idx.names
+	}
+	minCommon := len(trigrams)
+	if search.Mode == spanstore.NameMatchFuzzy {
+		minCommon = int(math.Ceil(spanstore.MinFuzzySimilarity * float64(len(trigrams))))
+	}
+	common := make(map[int]int)
+	for _, trigram := range trigrams {
+		for _, i := range idx.postings[trigram] {
+			common[i]++
+		}
+	}
+	var candidates []string
+	for i, count := range common {
+		if count >= minCommon {
+			candidates = append(candidates, idx.names[i])
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestTrigramIndex(t *testing.T) {
+	idx := newTrigramIndex()
+	for _, name := range []string{"frontend", "checkout-service", "payment-service", "payments", "cart", "cart"} {
+		idx.add(name)
+	}
+	assert.Len(t, idx.names, 5)
+
+	testCases := []struct {
+		search   spanstore.NameSearchParameters
+		expected []string
+	}{
+		{search: spanstore.NameSearchParameters{Text: "Pay", Mode: spanstore.NameMatchPrefix}, expected: []string{"payment-service", "payments"}},
+		{search: spanstore.NameSearchParameters{Text: "service", Mode: spanstore.NameMatchPrefix}, expected: []string{}},
+		{search: spanstore.NameSearchParameters{Text: "chekout", Mode: spanstore.NameMatchFuzzy}, expected: []string{"checkout-service"}},
+		{search: spanstore.NameSearchParameters{Text: "servce", Mode: spanstore.NameMatchFuzzy}, expected: []string{"checkout-service", "payment-service"}},
+		{search: spanstore.NameSearchParameters{Text: "ar", Mode: spanstore.NameMatchFuzzy}, expected: []string{"cart"}},
+		{search: spanstore.NameSearchParameters{Text: "zzz", Mode: spanstore.NameMatchFuzzy}, expected: []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.search.Mode+"-"+tc.search.Text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, idx.search(tc.search))
+		})
+	}
+}
+
+func TestTrigramIndexMatchesFilterNames(t *testing.T) {
+	idx := newTrigramIndex()
+	var names []string
+	for i := 0; i < 200; i++ {
+		name := fmt.Sprintf("service-%d-%c%c", i, 'a'+i%26, 'a'+i%7)
+		names = append(names, name)
+		idx.add(name)
+	}
+	for _, text := range []string{"service-1", "srvice-12", "-3-", "ab", "vice-19-tg"} {
+		for _, mode := range []string{spanstore.NameMatchPrefix, spanstore.NameMatchFuzzy} {
+			search := spanstore.NameSearchParameters{Text: text, Mode: mode}
+			assert.Equal(t, spanstore.FilterNames(search, names), idx.search(search), "%s %s", mode, text)
+		}
+	}
+}
+
+func TestStoreSearchServicesAndOperations(t *testing.T) {
+	store := NewStore()
+	for _, span := range []*model.Span{
+		{OperationName: "GET /cart", Process: &model.Process{ServiceName: "checkout-service"}, Tags: model.KeyValues{model.String("span.kind", "server")}},
+		{OperationName: "GET /cart", Process: &model.Process{ServiceName: "checkout-service"}, Tags: model.KeyValues{model.String("span.kind", "client")}},
+		{OperationName: "POST /checkout", Process: &model.Process{ServiceName: "checkout-service"}},
+		{OperationName: "charge", Process: &model.Process{ServiceName: "payment-service"}},
+	} {
+		require.NoError(t, store.WriteSpan(context.Background(), span))
+	}
+
+	services, err := store.SearchServices(context.Background(), spanstore.NameSearchParameters{Text: "chekout", Mode: spanstore.NameMatchFuzzy})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"checkout-service"}, services)
+
+	search := spanstore.NameSearchParameters{Text: "get", Mode: spanstore.NameMatchPrefix}
+	operations, err := store.SearchOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "checkout-service", SpanKind: "server"}, search)
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "GET /cart", SpanKind: "server"}}, operations)
+	operations, err = store.SearchOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "checkout-service"}, search)
+	require.NoError(t, err)
+	assert.Len(t, operations, 2)
+
+	operations, err = store.SearchOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "frontend"}, search)
+	require.NoError(t, err)
+	assert.Empty(t, operations)
+}
@@ -16,11 +16,15 @@ package memory
 
 import (
 	"flag"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
-const limit = "memory.max-traces"
+const (
+	limit                = "memory.max-traces"
+	dependenciesCacheTTL = "memory.dependencies-cache-ttl"
+)
 
 // Options stores the configuration entries for this storage
 type Options struct {
@@ -30,9 +34,11 @@ type Options struct {
 // AddFlags from this storage to the CLI
 func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Int(limit, 0, "The maximum amount of traces to store in memory. The default number of traces is unbounded.")
+	flagSet.Duration(dependenciesCacheTTL, time.Minute, "How long the dependencies aggregated from the stored spans are cached. Zero disables caching.")
 }
 
 // InitFromViper initializes the options struct with values from Viper
 func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Configuration.MaxTraces = v.GetInt(limit)
+	opt.Configuration.DependenciesCacheTTL = v.GetDuration(dependenciesCacheTTL)
 }
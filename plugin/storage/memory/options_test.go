@@ -16,6 +16,7 @@ package memory
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -24,9 +25,19 @@ import (
 
 func TestOptionsWithFlags(t *testing.T) {
 	v, command := config.Viperize(AddFlags)
-	command.ParseFlags([]string{"--memory.max-traces=100"})
+	command.ParseFlags([]string{"--memory.max-traces=100", "--memory.dependencies-cache-ttl=5m"})
 	opts := Options{}
 	opts.InitFromViper(v)
 
 	assert.Equal(t, 100, opts.Configuration.MaxTraces)
+	assert.Equal(t, 5*time.Minute, opts.Configuration.DependenciesCacheTTL)
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := Options{}
+	opts.InitFromViper(v)
+
+	assert.Equal(t, 0, opts.Configuration.MaxTraces)
+	assert.Equal(t, time.Minute, opts.Configuration.DependenciesCacheTTL)
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// SpanRoutingRule sends the spans it matches to some of the span storage types. A rule matches
+// a span when all its conditions hold; a rule without conditions matches every span.
+type SpanRoutingRule struct {
+	// Name identifies the rule in the metrics, rule-<index> by default.
+	Name string `json:"name"`
+	// Services are the names of the services of the spans, any service if empty.
+	Services []string `json:"services"`
+	// Tags are the values the span or process tags of the spans must have, as strings.
+	Tags map[string]string `json:"tags"`
+	// Storage are the span storage types the spans are written to.
+	Storage []string `json:"storage"`
+}
+
+// LoadSpanRoutingRules reads a JSON array of SpanRoutingRule from a file.
+func LoadSpanRoutingRules(path string) ([]SpanRoutingRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read span routing rules: %w", err)
+	}
+	var rules []SpanRoutingRule
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse span routing rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// writerRoutes resolves the storage types of the rules to the span writers of the types.
+func writerRoutes(rules []SpanRoutingRule, storageTypes []string, writers []spanstore.Writer) ([]spanstore.WriterRoute, error) {
+	var routes []spanstore.WriterRoute
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = "rule-" + strconv.Itoa(i)
+		}
+		if len(rule.Storage) == 0 {
+			return nil, fmt.Errorf("span routing rule %s has no storage", name)
+		}
+		var ruleWriters []spanstore.Writer
+		for _, storageType := range rule.Storage {
+			idx := slices.Index(storageTypes, storageType)
+			if idx < 0 {
+				return nil, fmt.Errorf("storage type %s of span routing rule %s is not one of the span storage types %v", storageType, name, storageTypes)
+			}
+			ruleWriters = append(ruleWriters, writers[idx])
+		}
+		writer := ruleWriters[0]
+		if len(ruleWriters) > 1 {
+			writer = spanstore.NewCompositeWriter(ruleWriters...)
+		}
+		routes = append(routes, spanstore.WriterRoute{
+			Name:     name,
+			Services: rule.Services,
+			Tags:     rule.Tags,
+			Writer:   writer,
+		})
+	}
+	return routes, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func TestLoadSpanRoutingRules(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[
+		{"name": "high-volume", "services": ["frontend", "cart"], "storage": ["kafka"]},
+		{"tags": {"tier": "cold"}, "storage": ["kafka", "elasticsearch"]}
+	]`), 0o600))
+	rules, err := LoadSpanRoutingRules(rulesFile)
+	require.NoError(t, err)
+	assert.Equal(t, []SpanRoutingRule{
+		{Name: "high-volume", Services: []string{"frontend", "cart"}, Storage: []string{"kafka"}},
+		{Tags: map[string]string{"tier": "cold"}, Storage: []string{"kafka", "elasticsearch"}},
+	}, rules)
+
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[{"service": "frontend"}]`), 0o600))
+	_, err = LoadSpanRoutingRules(rulesFile)
+	require.ErrorContains(t, err, "failed to parse span routing rules")
+}
+
+func TestWriterRoutes(t *testing.T) {
+	esWriter, kafkaWriter := new(spanStoreMocks.Writer), new(spanStoreMocks.Writer)
+	storageTypes := []string{elasticsearchStorageType, kafkaStorageType}
+	writers := []spanstore.Writer{esWriter, kafkaWriter}
+
+	routes, err := writerRoutes([]SpanRoutingRule{
+		{Name: "high-volume", Services: []string{"frontend"}, Storage: []string{kafkaStorageType}},
+		{Tags: map[string]string{"tier": "cold"}, Storage: []string{kafkaStorageType, elasticsearchStorageType}},
+	}, storageTypes, writers)
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.WriterRoute{
+		{Name: "high-volume", Services: []string{"frontend"}, Writer: kafkaWriter},
+		{Name: "rule-1", Tags: map[string]string{"tier": "cold"}, Writer: spanstore.NewCompositeWriter(kafkaWriter, esWriter)},
+	}, routes)
+
+	_, err = writerRoutes([]SpanRoutingRule{{Services: []string{"frontend"}}}, storageTypes, writers)
+	require.EqualError(t, err, "span routing rule rule-0 has no storage")
+}
@@ -5,6 +5,7 @@ package storage_v1
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -33,6 +34,42 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// Operator is the kind of condition.
+type AttributePredicate_Operator int32
+
+const (
+	// EQUALS matches the values equal to string_value, as the tags of the query.
+	AttributePredicate_EQUALS AttributePredicate_Operator = 0
+	// EXISTS matches any value of the key.
+	AttributePredicate_EXISTS AttributePredicate_Operator = 1
+	// PREFIX matches the string values starting with string_value.
+	AttributePredicate_PREFIX AttributePredicate_Operator = 2
+	// RANGE matches the numeric values between min and max, both inclusive.
+	AttributePredicate_RANGE AttributePredicate_Operator = 3
+)
+
+var AttributePredicate_Operator_name = map[int32]string{
+	0: "EQUALS",
+	1: "EXISTS",
+	2: "PREFIX",
+	3: "RANGE",
+}
+
+var AttributePredicate_Operator_value = map[string]int32{
+	"EQUALS": 0,
+	"EXISTS": 1,
+	"PREFIX": 2,
+	"RANGE":  3,
+}
+
+func (x AttributePredicate_Operator) String() string {
+	return proto.EnumName(AttributePredicate_Operator_name, int32(x))
+}
+
+func (AttributePredicate_Operator) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{12, 0}
+}
+
 type GetDependenciesRequest struct {
 	StartTime            time.Time `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3,stdtime" json:"start_time"`
 	EndTime              time.Time `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3,stdtime" json:"end_time"`
@@ -593,25 +630,114 @@ func (m *GetOperationsResponse) GetOperations() []*Operation {
 	return nil
 }
 
+// AttributePredicate is a condition on the value of a span tag, a process tag or a log field.
+type AttributePredicate struct {
+	Key      string                      `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Operator AttributePredicate_Operator `protobuf:"varint,2,opt,name=operator,proto3,enum=jaeger.storage.v1.AttributePredicate_Operator" json:"operator,omitempty"`
+	// string_value is the operand of EQUALS and PREFIX.
+	StringValue string `protobuf:"bytes,3,opt,name=string_value,json=stringValue,proto3" json:"string_value,omitempty"`
+	// min and max are the bounds of RANGE; -Infinity and +Infinity leave a bound open.
+	Min                  float64  `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  float64  `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttributePredicate) Reset()         { *m = AttributePredicate{} }
+func (m *AttributePredicate) String() string { return proto.CompactTextString(m) }
+func (*AttributePredicate) ProtoMessage()    {}
+func (*AttributePredicate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{12}
+}
+func (m *AttributePredicate) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AttributePredicate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttributePredicate.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AttributePredicate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributePredicate.Merge(m, src)
+}
+func (m *AttributePredicate) XXX_Size() int {
+	return m.Size()
+}
+func (m *AttributePredicate) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributePredicate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttributePredicate proto.InternalMessageInfo
+
+func (m *AttributePredicate) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *AttributePredicate) GetOperator() AttributePredicate_Operator {
+	if m != nil {
+		return m.Operator
+	}
+	return AttributePredicate_EQUALS
+}
+
+func (m *AttributePredicate) GetStringValue() string {
+	if m != nil {
+		return m.StringValue
+	}
+	return ""
+}
+
+func (m *AttributePredicate) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *AttributePredicate) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
 type TraceQueryParameters struct {
-	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
-	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	StartTimeMin         time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
-	StartTimeMax         time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
-	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
-	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
-	NumTraces            int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags          map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimeMin  time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax  time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	DurationMin   time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
+	DurationMax   time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
+	NumTraces     int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
+	// attributes must all match a span of the trace, in addition to the tags.
+	// Only sent to the plugins reporting the attributePredicates capability.
+	Attributes []AttributePredicate `protobuf:"bytes,9,rep,name=attributes,proto3" json:"attributes"`
+	// page_token continues a search from the next_page_token of the previous page,
+	// num_traces being the size of the pages.
+	// Only sent to the plugins reporting the pagination capability.
+	PageToken            string   `protobuf:"bytes,10,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TraceQueryParameters) Reset()         { *m = TraceQueryParameters{} }
 func (m *TraceQueryParameters) String() string { return proto.CompactTextString(m) }
 func (*TraceQueryParameters) ProtoMessage()    {}
 func (*TraceQueryParameters) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{12}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{13}
 }
 func (m *TraceQueryParameters) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -696,6 +822,20 @@ func (m *TraceQueryParameters) GetNumTraces() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetAttributes() []AttributePredicate {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *TraceQueryParameters) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type FindTracesRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -707,7 +847,7 @@ func (m *FindTracesRequest) Reset()         { *m = FindTracesRequest{} }
 func (m *FindTracesRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTracesRequest) ProtoMessage()    {}
 func (*FindTracesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{13}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{14}
 }
 func (m *FindTracesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -744,17 +884,19 @@ func (m *FindTracesRequest) GetQuery() *TraceQueryParameters {
 }
 
 type SpansResponseChunk struct {
-	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Spans []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	// next_page_token is set in the last chunk of a page when more traces match the query.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
 func (m *SpansResponseChunk) String() string { return proto.CompactTextString(m) }
 func (*SpansResponseChunk) ProtoMessage()    {}
 func (*SpansResponseChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{14}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{15}
 }
 func (m *SpansResponseChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -790,6 +932,13 @@ func (m *SpansResponseChunk) GetSpans() []model.Span {
 	return nil
 }
 
+func (m *SpansResponseChunk) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type FindTraceIDsRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -801,7 +950,7 @@ func (m *FindTraceIDsRequest) Reset()         { *m = FindTraceIDsRequest{} }
 func (m *FindTraceIDsRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTraceIDsRequest) ProtoMessage()    {}
 func (*FindTraceIDsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{15}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{16}
 }
 func (m *FindTraceIDsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -838,17 +987,19 @@ func (m *FindTraceIDsRequest) GetQuery() *TraceQueryParameters {
 }
 
 type FindTraceIDsResponse struct {
-	TraceIDs             []github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,rep,name=trace_ids,json=traceIds,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_ids"`
-	XXX_NoUnkeyedLiteral struct{}                                        `json:"-"`
-	XXX_unrecognized     []byte                                          `json:"-"`
-	XXX_sizecache        int32                                           `json:"-"`
+	TraceIDs []github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,rep,name=trace_ids,json=traceIds,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_ids"`
+	// next_page_token is set when more traces match the query.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FindTraceIDsResponse) Reset()         { *m = FindTraceIDsResponse{} }
 func (m *FindTraceIDsResponse) String() string { return proto.CompactTextString(m) }
 func (*FindTraceIDsResponse) ProtoMessage()    {}
 func (*FindTraceIDsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{16}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{17}
 }
 func (m *FindTraceIDsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -877,6 +1028,13 @@ func (m *FindTraceIDsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_FindTraceIDsResponse proto.InternalMessageInfo
 
+func (m *FindTraceIDsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 // empty; extensible in the future
 type CapabilitiesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -888,7 +1046,7 @@ func (m *CapabilitiesRequest) Reset()         { *m = CapabilitiesRequest{} }
 func (m *CapabilitiesRequest) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesRequest) ProtoMessage()    {}
 func (*CapabilitiesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{17}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{18}
 }
 func (m *CapabilitiesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -921,6 +1079,8 @@ type CapabilitiesResponse struct {
 	ArchiveSpanReader    bool     `protobuf:"varint,1,opt,name=archiveSpanReader,proto3" json:"archiveSpanReader,omitempty"`
 	ArchiveSpanWriter    bool     `protobuf:"varint,2,opt,name=archiveSpanWriter,proto3" json:"archiveSpanWriter,omitempty"`
 	StreamingSpanWriter  bool     `protobuf:"varint,3,opt,name=streamingSpanWriter,proto3" json:"streamingSpanWriter,omitempty"`
+	AttributePredicates  bool     `protobuf:"varint,4,opt,name=attributePredicates,proto3" json:"attributePredicates,omitempty"`
+	Pagination           bool     `protobuf:"varint,5,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -930,7 +1090,7 @@ func (m *CapabilitiesResponse) Reset()         { *m = CapabilitiesResponse{} }
 func (m *CapabilitiesResponse) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesResponse) ProtoMessage()    {}
 func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{18}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{19}
 }
 func (m *CapabilitiesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -980,7 +1140,22 @@ func (m *CapabilitiesResponse) GetStreamingSpanWriter() bool {
 	return false
 }
 
+func (m *CapabilitiesResponse) GetAttributePredicates() bool {
+	if m != nil {
+		return m.AttributePredicates
+	}
+	return false
+}
+
+func (m *CapabilitiesResponse) GetPagination() bool {
+	if m != nil {
+		return m.Pagination
+	}
+	return false
+}
+
 func init() {
+	proto.RegisterEnum("jaeger.storage.v1.AttributePredicate_Operator", AttributePredicate_Operator_name, AttributePredicate_Operator_value)
 	proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.storage.v1.GetDependenciesRequest")
 	proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.storage.v1.GetDependenciesResponse")
 	proto.RegisterType((*WriteSpanRequest)(nil), "jaeger.storage.v1.WriteSpanRequest")
@@ -993,6 +1168,7 @@ func init() {
 	proto.RegisterType((*GetOperationsRequest)(nil), "jaeger.storage.v1.GetOperationsRequest")
 	proto.RegisterType((*Operation)(nil), "jaeger.storage.v1.Operation")
 	proto.RegisterType((*GetOperationsResponse)(nil), "jaeger.storage.v1.GetOperationsResponse")
+	proto.RegisterType((*AttributePredicate)(nil), "jaeger.storage.v1.AttributePredicate")
 	proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.storage.v1.TraceQueryParameters")
 	proto.RegisterMapType((map[string]string)(nil), "jaeger.storage.v1.TraceQueryParameters.TagsEntry")
 	proto.RegisterType((*FindTracesRequest)(nil), "jaeger.storage.v1.FindTracesRequest")
@@ -1006,77 +1182,90 @@ func init() {
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 1117 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0xdc, 0xc4,
-	0x1b, 0xfe, 0x39, 0xd9, 0x6d, 0x76, 0xdf, 0xdd, 0xb4, 0xc9, 0xec, 0xf6, 0x57, 0xd7, 0xd0, 0x24,
-	0x18, 0x9a, 0x04, 0x04, 0xde, 0x66, 0x39, 0x80, 0xa0, 0x08, 0x9a, 0x3f, 0x8d, 0x02, 0x14, 0x8a,
-	0x13, 0xb5, 0x12, 0x85, 0xac, 0x66, 0xe3, 0xc1, 0x19, 0xb2, 0x1e, 0x6f, 0xed, 0xf1, 0x2a, 0x11,
-	0xe2, 0xc6, 0x07, 0xe0, 0xc8, 0x89, 0x13, 0x12, 0xdf, 0x83, 0x53, 0x8f, 0x9c, 0x39, 0x04, 0x94,
-	0x2b, 0x5f, 0x02, 0x79, 0x66, 0xec, 0xd8, 0x6b, 0x2b, 0x49, 0xa3, 0xdc, 0x3c, 0xef, 0x3c, 0xf3,
-	0xbc, 0xff, 0x66, 0x9e, 0xd7, 0x30, 0x1d, 0x72, 0x3f, 0xc0, 0x2e, 0xb1, 0x86, 0x81, 0xcf, 0x7d,
-	0x34, 0xfb, 0x3d, 0x26, 0x2e, 0x09, 0xac, 0xc4, 0x3a, 0x5a, 0x31, 0xda, 0xae, 0xef, 0xfa, 0x62,
-	0xb7, 0x13, 0x7f, 0x49, 0xa0, 0x31, 0xef, 0xfa, 0xbe, 0x3b, 0x20, 0x1d, 0xb1, 0xea, 0x47, 0xdf,
-	0x75, 0x38, 0xf5, 0x48, 0xc8, 0xb1, 0x37, 0x54, 0x80, 0xb9, 0x71, 0x80, 0x13, 0x05, 0x98, 0x53,
-	0x9f, 0xa9, 0xfd, 0x86, 0xe7, 0x3b, 0x64, 0x20, 0x17, 0xe6, 0xaf, 0x1a, 0xfc, 0x7f, 0x93, 0xf0,
-	0x75, 0x32, 0x24, 0xcc, 0x21, 0x6c, 0x8f, 0x92, 0xd0, 0x26, 0xcf, 0x23, 0x12, 0x72, 0xb4, 0x06,
-	0x10, 0x72, 0x1c, 0xf0, 0x5e, 0xec, 0x40, 0xd7, 0x16, 0xb4, 0xe5, 0x46, 0xd7, 0xb0, 0x24, 0xb9,
-	0x95, 0x90, 0x5b, 0x3b, 0x89, 0xf7, 0xd5, 0xda, 0x8b, 0xe3, 0xf9, 0xff, 0xfd, 0xfc, 0xf7, 0xbc,
-	0x66, 0xd7, 0xc5, 0xb9, 0x78, 0x07, 0x7d, 0x0c, 0x35, 0xc2, 0x1c, 0x49, 0x31, 0xf1, 0x12, 0x14,
-	0x53, 0x84, 0x39, 0xb1, 0xdd, 0xec, 0xc3, 0xad, 0x42, 0x7c, 0xe1, 0xd0, 0x67, 0x21, 0x41, 0x9b,
-	0xd0, 0x74, 0x32, 0x76, 0x5d, 0x5b, 0x98, 0x5c, 0x6e, 0x74, 0xef, 0x58, 0xaa, 0x92, 0x78, 0x48,
-	0x7b, 0xa3, 0xae, 0x95, 0x1e, 0x3d, 0xfa, 0x9c, 0xb2, 0x83, 0xd5, 0x4a, 0xec, 0xc2, 0xce, 0x1d,
-	0x34, 0x3f, 0x84, 0x99, 0xa7, 0x01, 0xe5, 0x64, 0x7b, 0x88, 0x59, 0x92, 0xfd, 0x12, 0x54, 0xc2,
-	0x21, 0x66, 0x2a, 0xef, 0xd6, 0x18, 0xa9, 0x40, 0x0a, 0x80, 0xd9, 0x82, 0xd9, 0xcc, 0x61, 0x19,
-	0x9a, 0xd9, 0x06, 0xb4, 0x36, 0xf0, 0x43, 0x22, 0x76, 0x02, 0xc5, 0x69, 0xde, 0x84, 0x56, 0xce,
-	0xaa, 0xc0, 0x0c, 0x6e, 0x6c, 0x12, 0xbe, 0x13, 0xe0, 0x3d, 0x92, 0x78, 0x7f, 0x06, 0x35, 0x1e,
-	0xaf, 0x7b, 0xd4, 0x11, 0x11, 0x34, 0x57, 0x3f, 0x89, 0xe3, 0xfe, 0xeb, 0x78, 0xfe, 0x1d, 0x97,
-	0xf2, 0xfd, 0xa8, 0x6f, 0xed, 0xf9, 0x5e, 0x47, 0xc6, 0x14, 0x03, 0x29, 0x73, 0xd5, 0xaa, 0x23,
-	0xbb, 0x2b, 0xd8, 0xb6, 0xd6, 0x4f, 0x8e, 0xe7, 0xa7, 0xd4, 0xa7, 0x3d, 0x25, 0x18, 0xb7, 0x9c,
-	0x38, 0xb8, 0x4d, 0xc2, 0xb7, 0x49, 0x30, 0xa2, 0x7b, 0x69, 0xbb, 0xcd, 0x15, 0x68, 0xe5, 0xac,
-	0xaa, 0xc8, 0x06, 0xd4, 0x42, 0x65, 0x13, 0x05, 0xae, 0xdb, 0xe9, 0xda, 0x7c, 0x04, 0xed, 0x4d,
-	0xc2, 0xbf, 0x1c, 0x12, 0x79, 0xbf, 0xd2, 0x9b, 0xa3, 0xc3, 0x94, 0xc2, 0x88, 0xe0, 0xeb, 0x76,
-	0xb2, 0x44, 0xaf, 0x40, 0x3d, 0x2e, 0x5a, 0xef, 0x80, 0x32, 0x47, 0xdc, 0x87, 0x98, 0x6e, 0x88,
-	0xd9, 0x67, 0x94, 0x39, 0xe6, 0x7d, 0xa8, 0xa7, 0x5c, 0x08, 0x41, 0x85, 0x61, 0x2f, 0x21, 0x10,
-	0xdf, 0x67, 0x9f, 0xfe, 0x11, 0x6e, 0x8e, 0x05, 0xa3, 0x32, 0x58, 0x84, 0xeb, 0x7e, 0x62, 0xfd,
-	0x02, 0x7b, 0x69, 0x1e, 0x63, 0x56, 0x74, 0x1f, 0x20, 0xb5, 0x84, 0xfa, 0x84, 0xb8, 0x4c, 0xaf,
-	0x5a, 0x85, 0x67, 0x69, 0xa5, 0x2e, 0xec, 0x0c, 0xde, 0xfc, 0xbd, 0x02, 0x6d, 0x51, 0xe9, 0xaf,
-	0x22, 0x12, 0x1c, 0x3d, 0xc6, 0x01, 0xf6, 0x08, 0x27, 0x41, 0x88, 0x5e, 0x83, 0xa6, 0xca, 0xbe,
-	0x97, 0x49, 0xa8, 0xa1, 0x6c, 0xb1, 0x6b, 0x74, 0x37, 0x13, 0xa1, 0x04, 0xc9, 0xe4, 0xa6, 0x73,
-	0x11, 0xa2, 0x0d, 0xa8, 0x70, 0xec, 0x86, 0xfa, 0xa4, 0x08, 0x6d, 0xa5, 0x24, 0xb4, 0xb2, 0x00,
-	0xac, 0x1d, 0xec, 0x86, 0x1b, 0x8c, 0x07, 0x47, 0xb6, 0x38, 0x8e, 0x3e, 0x85, 0xeb, 0xa7, 0xef,
-	0xba, 0xe7, 0x51, 0xa6, 0x57, 0x5e, 0xe2, 0x61, 0x36, 0xd3, 0xb7, 0xfd, 0x88, 0xb2, 0x71, 0x2e,
-	0x7c, 0xa8, 0x57, 0x2f, 0xc7, 0x85, 0x0f, 0xd1, 0x43, 0x68, 0x26, 0x4a, 0x25, 0xa2, 0xba, 0x26,
-	0x98, 0x6e, 0x17, 0x98, 0xd6, 0x15, 0x48, 0x12, 0xfd, 0x12, 0x13, 0x35, 0x92, 0x83, 0x71, 0x4c,
-	0x39, 0x1e, 0x7c, 0xa8, 0x4f, 0x5d, 0x86, 0x07, 0x1f, 0xa2, 0x3b, 0x00, 0x2c, 0xf2, 0x7a, 0xe2,
-	0xd5, 0x84, 0x7a, 0x6d, 0x41, 0x5b, 0xae, 0xda, 0x75, 0x16, 0x79, 0xa2, 0xc8, 0xa1, 0xf1, 0x1e,
-	0xd4, 0xd3, 0xca, 0xa2, 0x19, 0x98, 0x3c, 0x20, 0x47, 0xaa, 0xb7, 0xf1, 0x27, 0x6a, 0x43, 0x75,
-	0x84, 0x07, 0x51, 0xd2, 0x4a, 0xb9, 0xf8, 0x60, 0xe2, 0x7d, 0xcd, 0xb4, 0x61, 0xf6, 0x21, 0x65,
-	0x8e, 0xa4, 0x49, 0x9e, 0xcc, 0x47, 0x50, 0x7d, 0x1e, 0xf7, 0x4d, 0xe9, 0xcd, 0xd2, 0x05, 0x9b,
-	0x6b, 0xcb, 0x53, 0xe6, 0x06, 0xa0, 0x58, 0x7f, 0xd2, 0x4b, 0xbf, 0xb6, 0x1f, 0xb1, 0x03, 0xd4,
-	0x81, 0x6a, 0xfc, 0x3c, 0x12, 0x65, 0x2c, 0x13, 0x31, 0xa5, 0x87, 0x12, 0x67, 0xee, 0x40, 0x2b,
-	0x0d, 0x6d, 0x6b, 0xfd, 0xaa, 0x82, 0x1b, 0x41, 0x3b, 0xcf, 0xaa, 0x1e, 0xe6, 0x2e, 0xd4, 0x13,
-	0x91, 0x93, 0x21, 0x36, 0x57, 0x1f, 0x5c, 0x56, 0xe5, 0x6a, 0x29, 0x7b, 0x4d, 0xc9, 0x5c, 0x28,
-	0xe4, 0x16, 0x0f, 0x71, 0x9f, 0x0e, 0x28, 0x3f, 0x9d, 0x6b, 0xe6, 0x6f, 0x1a, 0xb4, 0xf3, 0x76,
-	0x15, 0xcf, 0xdb, 0x30, 0x8b, 0x83, 0xbd, 0x7d, 0x3a, 0x52, 0x5a, 0x8e, 0x1d, 0x12, 0x88, 0x94,
-	0x6b, 0x76, 0x71, 0x63, 0x0c, 0x2d, 0x25, 0x5d, 0x34, 0x3b, 0x8f, 0x96, 0x1b, 0xe8, 0x1e, 0xb4,
-	0x42, 0x1e, 0x10, 0xec, 0x51, 0xe6, 0x66, 0xf0, 0x93, 0x02, 0x5f, 0xb6, 0xd5, 0xfd, 0x43, 0x83,
-	0x99, 0xd3, 0xe5, 0xe3, 0x41, 0xe4, 0x52, 0x86, 0x9e, 0x40, 0x3d, 0x1d, 0x36, 0xe8, 0xf5, 0x92,
-	0x3e, 0x8c, 0xcf, 0x31, 0xe3, 0x8d, 0xb3, 0x41, 0x2a, 0xf5, 0x27, 0x50, 0x15, 0x93, 0x09, 0xdd,
-	0x2d, 0x81, 0x17, 0x27, 0x99, 0xb1, 0x78, 0x1e, 0x4c, 0xf2, 0x76, 0x7f, 0x80, 0xdb, 0xdb, 0xc5,
-	0xdc, 0x54, 0x32, 0xbb, 0x70, 0x23, 0x8d, 0x44, 0xa2, 0xae, 0x30, 0xa5, 0x65, 0xad, 0xfb, 0xef,
-	0xa4, 0xac, 0xa0, 0x6c, 0x98, 0x72, 0xfa, 0x14, 0x6a, 0xc9, 0xb0, 0x45, 0x66, 0x09, 0xd1, 0xd8,
-	0x24, 0x36, 0xca, 0x0a, 0x52, 0x7c, 0x6a, 0xf7, 0x34, 0xf4, 0x0d, 0x34, 0x32, 0xf3, 0xb3, 0xb4,
-	0x90, 0xc5, 0xa9, 0x5b, 0x5a, 0xc8, 0xb2, 0x31, 0xdc, 0x87, 0xe9, 0xdc, 0x74, 0x43, 0x4b, 0xe5,
-	0x07, 0x0b, 0xc3, 0xd8, 0x58, 0x3e, 0x1f, 0xa8, 0x7c, 0x3c, 0x03, 0x38, 0x15, 0x26, 0x54, 0x56,
-	0xe5, 0x82, 0x6e, 0x5d, 0xbc, 0x3c, 0x3d, 0x68, 0x66, 0x45, 0x00, 0x2d, 0x9e, 0x45, 0x7f, 0xaa,
-	0x3d, 0xc6, 0xd2, 0xb9, 0x38, 0x75, 0xd5, 0x0e, 0xe1, 0xd6, 0x83, 0xf1, 0x67, 0xa7, 0x7a, 0xfe,
-	0xad, 0xfa, 0xbf, 0xcb, 0xec, 0x5f, 0xe1, 0x4d, 0xeb, 0x1e, 0xe5, 0x3c, 0xe7, 0x6e, 0xdb, 0xae,
-	0xf8, 0xb5, 0x53, 0xbb, 0x57, 0x7f, 0xe9, 0xba, 0x3f, 0x69, 0xa0, 0xe7, 0xff, 0x8d, 0x33, 0xce,
-	0xf7, 0x85, 0xf3, 0xec, 0x36, 0x7a, 0xb3, 0xdc, 0x79, 0xc9, 0xef, 0xbf, 0xf1, 0xd6, 0x45, 0xa0,
-	0xaa, 0x02, 0x11, 0x20, 0xe9, 0x33, 0xab, 0xab, 0x71, 0xcb, 0x73, 0xeb, 0x52, 0xd1, 0x28, 0x0a,
-	0x74, 0x69, 0xcb, 0xcb, 0x04, 0x7b, 0x55, 0x7f, 0x71, 0x32, 0xa7, 0xfd, 0x79, 0x32, 0xa7, 0xfd,
-	0x73, 0x32, 0xa7, 0x7d, 0x0d, 0x0a, 0xde, 0x1b, 0xad, 0xf4, 0xaf, 0x89, 0x29, 0xff, 0xee, 0x7f,
-	0x01, 0x00, 0x00, 0xff, 0xff, 0x6c, 0xa3, 0xc0, 0x72, 0x65, 0x0d, 0x00, 0x00,
+	// 1316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xcd, 0x73, 0xdb, 0x44,
+	0x14, 0xaf, 0xe2, 0xb8, 0x91, 0x9f, 0xdd, 0xd6, 0xd9, 0xb8, 0x54, 0x15, 0x34, 0x09, 0x82, 0x26,
+	0x81, 0x01, 0xa7, 0x31, 0x07, 0xbe, 0xca, 0x40, 0xd2, 0xa4, 0x99, 0xf4, 0x8b, 0x54, 0x09, 0x6d,
+	0x87, 0x42, 0x3d, 0xeb, 0x68, 0x51, 0x96, 0xc4, 0x2b, 0x57, 0x5a, 0x67, 0x9c, 0x61, 0xb8, 0xf1,
+	0x07, 0x30, 0xc3, 0x85, 0x53, 0xff, 0x18, 0x4e, 0x3d, 0x72, 0xe6, 0x50, 0x98, 0x5c, 0xb9, 0x32,
+	0x9c, 0x99, 0xfd, 0x90, 0x22, 0x59, 0x9a, 0xc6, 0x74, 0x72, 0xdb, 0x7d, 0xfb, 0xdb, 0xdf, 0xfb,
+	0xd4, 0xdb, 0x27, 0x38, 0x17, 0xf1, 0x20, 0xc4, 0x3e, 0x69, 0xf6, 0xc2, 0x80, 0x07, 0x68, 0xf2,
+	0x7b, 0x4c, 0x7c, 0x12, 0x36, 0x63, 0xe9, 0xc1, 0x92, 0xdd, 0xf0, 0x03, 0x3f, 0x90, 0xa7, 0x8b,
+	0x62, 0xa5, 0x80, 0xf6, 0x8c, 0x1f, 0x04, 0xfe, 0x3e, 0x59, 0x94, 0xbb, 0x4e, 0xff, 0xbb, 0x45,
+	0x4e, 0xbb, 0x24, 0xe2, 0xb8, 0xdb, 0xd3, 0x80, 0xe9, 0x61, 0x80, 0xd7, 0x0f, 0x31, 0xa7, 0x01,
+	0xd3, 0xe7, 0xd5, 0x6e, 0xe0, 0x91, 0x7d, 0xb5, 0x71, 0x9e, 0x19, 0xf0, 0xda, 0x3a, 0xe1, 0xab,
+	0xa4, 0x47, 0x98, 0x47, 0xd8, 0x0e, 0x25, 0x91, 0x4b, 0x9e, 0xf6, 0x49, 0xc4, 0xd1, 0x0d, 0x80,
+	0x88, 0xe3, 0x90, 0xb7, 0x85, 0x02, 0xcb, 0x98, 0x35, 0x16, 0xaa, 0x2d, 0xbb, 0xa9, 0xc8, 0x9b,
+	0x31, 0x79, 0x73, 0x3b, 0xd6, 0xbe, 0x62, 0x3e, 0x7f, 0x31, 0x73, 0xe6, 0xe7, 0x3f, 0x67, 0x0c,
+	0xb7, 0x22, 0xef, 0x89, 0x13, 0xf4, 0x39, 0x98, 0x84, 0x79, 0x8a, 0x62, 0xec, 0x7f, 0x50, 0x4c,
+	0x10, 0xe6, 0x09, 0xb9, 0xd3, 0x81, 0x4b, 0x39, 0xfb, 0xa2, 0x5e, 0xc0, 0x22, 0x82, 0xd6, 0xa1,
+	0xe6, 0xa5, 0xe4, 0x96, 0x31, 0x5b, 0x5a, 0xa8, 0xb6, 0xae, 0x34, 0x75, 0x24, 0x71, 0x8f, 0xb6,
+	0x0f, 0x5a, 0xcd, 0xe4, 0xea, 0xe1, 0x1d, 0xca, 0xf6, 0x56, 0xc6, 0x85, 0x0a, 0x37, 0x73, 0xd1,
+	0xf9, 0x14, 0xea, 0x0f, 0x43, 0xca, 0xc9, 0x56, 0x0f, 0xb3, 0xd8, 0xfb, 0x79, 0x18, 0x8f, 0x7a,
+	0x98, 0x69, 0xbf, 0xa7, 0x86, 0x48, 0x25, 0x52, 0x02, 0x9c, 0x29, 0x98, 0x4c, 0x5d, 0x56, 0xa6,
+	0x39, 0x0d, 0x40, 0x37, 0xf6, 0x83, 0x88, 0xc8, 0x93, 0x50, 0x73, 0x3a, 0x17, 0x61, 0x2a, 0x23,
+	0xd5, 0x60, 0x06, 0x17, 0xd6, 0x09, 0xdf, 0x0e, 0xf1, 0x0e, 0x89, 0xb5, 0x3f, 0x06, 0x93, 0x8b,
+	0x7d, 0x9b, 0x7a, 0xd2, 0x82, 0xda, 0xca, 0x17, 0xc2, 0xee, 0x3f, 0x5e, 0xcc, 0xbc, 0xef, 0x53,
+	0xbe, 0xdb, 0xef, 0x34, 0x77, 0x82, 0xee, 0xa2, 0xb2, 0x49, 0x00, 0x29, 0xf3, 0xf5, 0x6e, 0x51,
+	0x65, 0x57, 0xb2, 0x6d, 0xac, 0x1e, 0xbd, 0x98, 0x99, 0xd0, 0x4b, 0x77, 0x42, 0x32, 0x6e, 0x78,
+	0xc2, 0xb8, 0x75, 0xc2, 0xb7, 0x48, 0x78, 0x40, 0x77, 0x92, 0x74, 0x3b, 0x4b, 0x30, 0x95, 0x91,
+	0xea, 0x20, 0xdb, 0x60, 0x46, 0x5a, 0x26, 0x03, 0x5c, 0x71, 0x93, 0xbd, 0x73, 0x17, 0x1a, 0xeb,
+	0x84, 0x7f, 0xd9, 0x23, 0xaa, 0xbe, 0x92, 0xca, 0xb1, 0x60, 0x42, 0x63, 0xa4, 0xf1, 0x15, 0x37,
+	0xde, 0xa2, 0xd7, 0xa1, 0x22, 0x82, 0xd6, 0xde, 0xa3, 0xcc, 0x93, 0xf5, 0x20, 0xe8, 0x7a, 0x98,
+	0xdd, 0xa6, 0xcc, 0x73, 0xae, 0x43, 0x25, 0xe1, 0x42, 0x08, 0xc6, 0x19, 0xee, 0xc6, 0x04, 0x72,
+	0xfd, 0xf2, 0xdb, 0x3f, 0xc2, 0xc5, 0x21, 0x63, 0xb4, 0x07, 0x73, 0x70, 0x3e, 0x88, 0xa5, 0xf7,
+	0x70, 0x37, 0xf1, 0x63, 0x48, 0x8a, 0xae, 0x03, 0x24, 0x92, 0xc8, 0x1a, 0x93, 0xc5, 0xf4, 0x46,
+	0x33, 0xf7, 0x59, 0x36, 0x13, 0x15, 0x6e, 0x0a, 0xef, 0xfc, 0x63, 0x00, 0x5a, 0xe6, 0x3c, 0xa4,
+	0x9d, 0x3e, 0x27, 0x9b, 0x21, 0xf1, 0xe8, 0x0e, 0xe6, 0x04, 0xd5, 0xa1, 0xb4, 0x47, 0x0e, 0xb5,
+	0x17, 0x62, 0x89, 0x6e, 0x81, 0xa9, 0xae, 0x05, 0xa1, 0xf4, 0xe1, 0x7c, 0xab, 0x59, 0xa0, 0x24,
+	0x4f, 0xa5, 0xf5, 0x06, 0xa1, 0x9b, 0xdc, 0x47, 0x6f, 0x42, 0x2d, 0xe2, 0x21, 0x65, 0x7e, 0xfb,
+	0x00, 0xef, 0xf7, 0x89, 0x55, 0x92, 0x6a, 0xaa, 0x4a, 0xf6, 0x40, 0x88, 0x84, 0x01, 0x5d, 0xca,
+	0xac, 0xf1, 0x59, 0x63, 0xc1, 0x70, 0xc5, 0x52, 0x4a, 0xf0, 0xc0, 0x2a, 0x6b, 0x09, 0x1e, 0x38,
+	0x1f, 0x83, 0x19, 0x93, 0x23, 0x80, 0xb3, 0x6b, 0xf7, 0xbf, 0x5a, 0xbe, 0xb3, 0x55, 0x3f, 0x23,
+	0xd7, 0x8f, 0x36, 0xb6, 0xb6, 0xb7, 0xea, 0x86, 0x58, 0x6f, 0xba, 0x6b, 0x37, 0x37, 0x1e, 0xd5,
+	0xc7, 0x50, 0x05, 0xca, 0xee, 0xf2, 0xbd, 0xf5, 0xb5, 0x7a, 0xc9, 0xf9, 0xa5, 0x0c, 0x0d, 0x59,
+	0x60, 0xf7, 0xfb, 0x24, 0x3c, 0xdc, 0xc4, 0x21, 0xee, 0x12, 0x4e, 0xc2, 0x48, 0x9a, 0xa6, 0x92,
+	0xde, 0x4e, 0xe5, 0xb1, 0xaa, 0x65, 0x22, 0xe2, 0xe8, 0x6a, 0x2a, 0x31, 0x0a, 0xa4, 0x72, 0x7a,
+	0x2e, 0x93, 0x18, 0xb4, 0x06, 0xe3, 0x1c, 0xfb, 0x91, 0x55, 0x92, 0x19, 0x59, 0x2a, 0x08, 0x56,
+	0x91, 0x01, 0xcd, 0x6d, 0xec, 0x47, 0x6b, 0x8c, 0x87, 0x87, 0xae, 0xbc, 0x8e, 0x6e, 0xc1, 0xf9,
+	0xe3, 0x76, 0xd6, 0x8e, 0x63, 0x32, 0x6a, 0x3f, 0xaa, 0x25, 0x2d, 0xed, 0x2e, 0x65, 0xc3, 0x5c,
+	0x3a, 0x9a, 0xaf, 0xc0, 0x85, 0x07, 0xe8, 0x26, 0xd4, 0xe2, 0x06, 0x2d, 0xad, 0x3a, 0x2b, 0x99,
+	0x2e, 0xe7, 0x98, 0x56, 0x35, 0x48, 0x11, 0xfd, 0x2a, 0x88, 0xaa, 0xf1, 0x45, 0x61, 0x53, 0x86,
+	0x07, 0x0f, 0xac, 0x89, 0x57, 0xe1, 0xc1, 0x03, 0x74, 0x05, 0x80, 0xf5, 0xbb, 0x6d, 0xd9, 0x2c,
+	0x22, 0xcb, 0x9c, 0x35, 0x16, 0xca, 0x6e, 0x85, 0xf5, 0xbb, 0x32, 0xc8, 0x11, 0xba, 0x0d, 0x80,
+	0xe3, 0xda, 0x8c, 0xac, 0x8a, 0xcc, 0xc9, 0xd5, 0x91, 0x0a, 0x58, 0xb7, 0xde, 0xd4, 0x75, 0xa1,
+	0xab, 0x87, 0x7d, 0xd2, 0xe6, 0xc1, 0x1e, 0x61, 0x16, 0xc8, 0xec, 0x57, 0x84, 0x64, 0x5b, 0x08,
+	0xec, 0x0f, 0xa1, 0x92, 0x64, 0xb1, 0xe0, 0x4b, 0x6a, 0x40, 0x59, 0x95, 0xbd, 0x2a, 0x1b, 0xb5,
+	0xf9, 0x64, 0xec, 0x23, 0xc3, 0x71, 0x61, 0xf2, 0x26, 0x65, 0x9e, 0x32, 0x39, 0xee, 0x4a, 0x9f,
+	0x41, 0xf9, 0xa9, 0xa8, 0x11, 0xdd, 0xd2, 0xe7, 0x47, 0x2c, 0x24, 0x57, 0xdd, 0x72, 0xba, 0x80,
+	0x44, 0x8b, 0x4f, 0xfa, 0xca, 0x8d, 0xdd, 0x3e, 0xdb, 0x43, 0x8b, 0x50, 0x16, 0x1d, 0x28, 0x7e,
+	0x7c, 0x8a, 0xde, 0x09, 0xed, 0xb7, 0xc2, 0xa1, 0x39, 0xb8, 0xc0, 0xc8, 0x80, 0xb7, 0x53, 0x7e,
+	0xeb, 0xaa, 0x17, 0xe2, 0xcd, 0xd8, 0x77, 0x67, 0x1b, 0xa6, 0x12, 0x17, 0x36, 0x56, 0x4f, 0xcb,
+	0x89, 0x67, 0x06, 0x34, 0xb2, 0xb4, 0xba, 0x49, 0x3e, 0x81, 0x4a, 0xfc, 0xe0, 0x28, 0x5f, 0x6a,
+	0x2b, 0xcb, 0xaf, 0xfa, 0xe2, 0x98, 0x09, 0xbb, 0xa9, 0x9f, 0x9c, 0xd1, 0xdd, 0x16, 0x4f, 0x24,
+	0xee, 0xe1, 0x0e, 0xdd, 0xa7, 0xfc, 0x78, 0x16, 0x71, 0xfe, 0x35, 0xa0, 0x91, 0x95, 0x6b, 0xbb,
+	0xdf, 0x83, 0x49, 0x1c, 0xee, 0xec, 0xd2, 0x03, 0xfd, 0xfe, 0x62, 0x8f, 0x84, 0x32, 0x36, 0xa6,
+	0x9b, 0x3f, 0x18, 0x42, 0xab, 0x67, 0xd8, 0x1a, 0xcb, 0xa1, 0xd5, 0x01, 0xba, 0x06, 0x53, 0x11,
+	0x0f, 0x09, 0xee, 0x52, 0xe6, 0xa7, 0xf0, 0x25, 0x89, 0x2f, 0x3a, 0x12, 0x37, 0x70, 0xae, 0xee,
+	0x23, 0xd9, 0x68, 0x4c, 0xb7, 0xe8, 0x08, 0x4d, 0xcb, 0x2f, 0x80, 0x32, 0xf9, 0xf9, 0xc9, 0x2e,
+	0x62, 0xba, 0x29, 0x49, 0xeb, 0x37, 0x03, 0xea, 0xc7, 0x0a, 0x36, 0xf7, 0xfb, 0x3e, 0x65, 0xe8,
+	0x01, 0x54, 0x92, 0x91, 0x03, 0xbd, 0x55, 0x50, 0x02, 0xc3, 0xd3, 0x8c, 0xfd, 0xf6, 0xcb, 0x41,
+	0x3a, 0x98, 0x0f, 0xa0, 0x2c, 0xe7, 0x13, 0x54, 0xf4, 0x41, 0xe7, 0xe7, 0x19, 0x7b, 0xee, 0x24,
+	0x98, 0xe2, 0x6d, 0xfd, 0x00, 0x97, 0xb7, 0xf2, 0xd1, 0xd2, 0xce, 0x3c, 0x81, 0x0b, 0x89, 0x25,
+	0x0a, 0x75, 0x8a, 0x2e, 0x2d, 0x18, 0xad, 0xbf, 0x4b, 0x50, 0x3f, 0x2e, 0x01, 0xad, 0xf4, 0x21,
+	0x98, 0xf1, 0xc8, 0x85, 0x9c, 0x02, 0xa2, 0xa1, 0x79, 0xcc, 0x2e, 0x0a, 0x48, 0xbe, 0x1b, 0x5c,
+	0x33, 0xd0, 0x37, 0x50, 0x4d, 0x4d, 0x51, 0x85, 0x81, 0xcc, 0xcf, 0x5e, 0xf6, 0xdc, 0x49, 0x30,
+	0x9d, 0xa0, 0x0e, 0x9c, 0xcb, 0xcc, 0x38, 0x68, 0xbe, 0xf8, 0x62, 0x6e, 0x24, 0xb3, 0x17, 0x4e,
+	0x06, 0x6a, 0x1d, 0x8f, 0x01, 0x8e, 0x7b, 0x27, 0x2a, 0x8a, 0x72, 0xae, 0xb5, 0x8e, 0x1e, 0x9e,
+	0x36, 0xd4, 0xd2, 0xed, 0x07, 0xcd, 0xbd, 0x8c, 0xfe, 0xb8, 0xed, 0xd9, 0xf3, 0x27, 0xe2, 0x74,
+	0xa9, 0x0d, 0xe0, 0xd2, 0xf2, 0xf0, 0x87, 0xac, 0x73, 0xfe, 0xad, 0x9e, 0xf2, 0x53, 0xe7, 0xa7,
+	0x58, 0x69, 0xad, 0xc3, 0x8c, 0xe6, 0x4c, 0xb5, 0x3d, 0x91, 0x03, 0xbe, 0x3e, 0x3d, 0xfd, 0xa2,
+	0x6b, 0xfd, 0x64, 0x80, 0x95, 0xfd, 0x43, 0x4a, 0x29, 0xdf, 0x95, 0xca, 0xd3, 0xc7, 0xe8, 0x9d,
+	0x62, 0xe5, 0x05, 0x3f, 0x81, 0xf6, 0xbb, 0xa3, 0x40, 0x75, 0x04, 0xfa, 0x80, 0x94, 0xce, 0x74,
+	0xa7, 0x16, 0x29, 0xcf, 0xec, 0x0b, 0x9b, 0x46, 0xbe, 0xe5, 0xdb, 0xf3, 0x27, 0xe2, 0x94, 0xda,
+	0x15, 0xeb, 0xf9, 0xd1, 0xb4, 0xf1, 0xfb, 0xd1, 0xb4, 0xf1, 0xd7, 0xd1, 0xb4, 0xf1, 0x35, 0x68,
+	0x78, 0xfb, 0x60, 0xa9, 0x73, 0x56, 0x0e, 0x3d, 0x1f, 0xfc, 0x37, 0x00, 0x5b, 0x96, 0x70, 0x99,
+	0x6b, 0x0f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2333,6 +2522,64 @@ func (m *GetOperationsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *AttributePredicate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttributePredicate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AttributePredicate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Max != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Max))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if m.Min != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Min))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if len(m.StringValue) > 0 {
+		i -= len(m.StringValue)
+		copy(dAtA[i:], m.StringValue)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.StringValue)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Operator != 0 {
+		i = encodeVarintStorage(dAtA, i, uint64(m.Operator))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *TraceQueryParameters) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2357,6 +2604,27 @@ func (m *TraceQueryParameters) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.PageToken) > 0 {
+		i -= len(m.PageToken)
+		copy(dAtA[i:], m.PageToken)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.PageToken)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStorage(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
 	if m.NumTraces != 0 {
 		i = encodeVarintStorage(dAtA, i, uint64(m.NumTraces))
 		i--
@@ -2493,6 +2761,13 @@ func (m *SpansResponseChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Spans) > 0 {
 		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -2573,6 +2848,13 @@ func (m *FindTraceIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.TraceIDs) > 0 {
 		for iNdEx := len(m.TraceIDs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -2641,6 +2923,26 @@ func (m *CapabilitiesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Pagination {
+		i--
+		if m.Pagination {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.AttributePredicates {
+		i--
+		if m.AttributePredicates {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.StreamingSpanWriter {
 		i--
 		if m.StreamingSpanWriter {
@@ -2879,6 +3181,35 @@ func (m *GetOperationsResponse) Size() (n int) {
 	return n
 }
 
+func (m *AttributePredicate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.Operator != 0 {
+		n += 1 + sovStorage(uint64(m.Operator))
+	}
+	l = len(m.StringValue)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.Min != 0 {
+		n += 9
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *TraceQueryParameters) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2912,6 +3243,16 @@ func (m *TraceQueryParameters) Size() (n int) {
 	if m.NumTraces != 0 {
 		n += 1 + sovStorage(uint64(m.NumTraces))
 	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2946,6 +3287,10 @@ func (m *SpansResponseChunk) Size() (n int) {
 			n += 1 + l + sovStorage(uint64(l))
 		}
 	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2980,6 +3325,10 @@ func (m *FindTraceIDsResponse) Size() (n int) {
 			n += 1 + l + sovStorage(uint64(l))
 		}
 	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3013,6 +3362,12 @@ func (m *CapabilitiesResponse) Size() (n int) {
 	if m.StreamingSpanWriter {
 		n += 2
 	}
+	if m.AttributePredicates {
+		n += 2
+	}
+	if m.Pagination {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4032,6 +4387,162 @@ func (m *GetOperationsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *AttributePredicate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AttributePredicate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AttributePredicate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operator", wireType)
+			}
+			m.Operator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Operator |= AttributePredicate_Operator(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StringValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StringValue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Min = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Max = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -4403,6 +4914,72 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, AttributePredicate{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4575,6 +5152,38 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4748,6 +5357,38 @@ func (m *FindTraceIDsResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4910,6 +5551,46 @@ func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.StreamingSpanWriter = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AttributePredicates", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AttributePredicates = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pagination", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Pagination = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// cachedStoreSize is the maximum number of dependency graphs kept by a CachedStore.
+const cachedStoreSize = 100
+
+var _ ReaderWriter = (*CachedStore)(nil)
+
+// CachedStore is a dependency store caching the dependencies read from the underlying store,
+// for storages aggregating them from the stored spans on every read, which is costly.
+//
+// The end of the requested time window is truncated to the TTL to build the cache key, so that
+// the repeated requests of the UI, whose windows end now, share an entry: the dependencies returned
+// are at most one TTL old. Writing dependencies invalidates all the entries.
+type CachedStore struct {
+	store      ReaderWriter
+	ttl        time.Duration
+	cache      cache.Cache
+	generation atomic.Uint64
+}
+
+// NewCachedStore creates a CachedStore keeping the dependencies for the given TTL.
+func NewCachedStore(store ReaderWriter, ttl time.Duration) *CachedStore {
+	return &CachedStore{
+		store: store,
+		ttl:   ttl,
+		cache: cache.NewLRUWithOptions(cachedStoreSize, &cache.Options{TTL: ttl}),
+	}
+}
+
+// GetDependencies implements Reader#GetDependencies.
+func (c *CachedStore) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	key := fmt.Sprintf("%d|%s|%d|%d", c.generation.Load(), tenancy.GetTenant(ctx), endTs.Truncate(c.ttl).UnixNano(), lookback)
+	if links, ok := c.cache.Get(key).([]model.DependencyLink); ok {
+		return slices.Clone(links), nil
+	}
+	links, err := c.store.GetDependencies(ctx, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Put(key, slices.Clone(links))
+	return links, nil
+}
+
+// WriteDependencies implements Writer#WriteDependencies.
+func (c *CachedStore) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	if err := c.store.WriteDependencies(ts, dependencies); err != nil {
+		return err
+	}
+	c.generation.Add(1)
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// countingStore returns its links and counts the reads.
+type countingStore struct {
+	links []model.DependencyLink
+	err   error
+	reads int
+}
+
+func (s *countingStore) GetDependencies(context.Context, time.Time, time.Duration) ([]model.DependencyLink, error) {
+	s.reads++
+	return s.links, s.err
+}
+
+func (s *countingStore) WriteDependencies(_ time.Time, dependencies []model.DependencyLink) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.links = append(s.links, dependencies...)
+	return nil
+}
+
+func TestCachedStoreGetDependencies(t *testing.T) {
+	store := &countingStore{links: []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}}
+	cached := NewCachedStore(store, time.Minute)
+	ctx := context.Background()
+	endTs := time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC)
+
+	links, err := cached.GetDependencies(ctx, endTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, store.links, links)
+	links[0].CallCount = 100 // the cached links are not shared with the callers
+
+	links, err = cached.GetDependencies(ctx, endTs.Add(30*time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}, links)
+	assert.Equal(t, 1, store.reads)
+
+	// another window, another tenant
+	_, err = cached.GetDependencies(ctx, endTs.Add(time.Minute), time.Hour)
+	require.NoError(t, err)
+	_, err = cached.GetDependencies(ctx, endTs, 2*time.Hour)
+	require.NoError(t, err)
+	_, err = cached.GetDependencies(tenancy.WithTenant(ctx, "acme"), endTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 4, store.reads)
+}
+
+func TestCachedStoreWriteDependencies(t *testing.T) {
+	store := &countingStore{}
+	cached := NewCachedStore(store, time.Minute)
+	ctx := context.Background()
+	endTs := time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC)
+
+	links, err := cached.GetDependencies(ctx, endTs, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	written := []model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}
+	require.NoError(t, cached.WriteDependencies(endTs, written))
+	links, err = cached.GetDependencies(ctx, endTs, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, written, links)
+	assert.Equal(t, 2, store.reads)
+}
+
+func TestCachedStoreErrors(t *testing.T) {
+	store := &countingStore{err: errors.New("storage error")}
+	cached := NewCachedStore(store, time.Minute)
+	endTs := time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC)
+
+	_, err := cached.GetDependencies(context.Background(), endTs, time.Hour)
+	require.EqualError(t, err, "storage error")
+	_, err = cached.GetDependencies(context.Background(), endTs, time.Hour)
+	require.EqualError(t, err, "storage error")
+	assert.Equal(t, 2, store.reads, "errors are not cached")
+
+	require.EqualError(t, cached.WriteDependencies(endTs, nil), "storage error")
+}
@@ -31,3 +31,9 @@ type Writer interface {
 type Reader interface {
 	GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error)
 }
+
+// ReaderWriter can both load and store service dependencies.
+type ReaderWriter interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// hedgedReaderMetrics keeps track of the duplicate GetTrace requests and how often they win.
+type hedgedReaderMetrics struct {
+	HedgedRequests metrics.Counter `metric:"hedged_requests"`
+	HedgedWins     metrics.Counter `metric:"hedged_wins"`
+}
+
+// HedgedReadOptions contains the options for constructing a HedgedReader.
+type HedgedReadOptions struct {
+	// Delay is how long a GetTrace request may be outstanding before a duplicate is sent.
+	Delay time.Duration
+	// MaxAttempts is the maximum number of concurrent requests of a single GetTrace call,
+	// including the first one. Hedging is disabled with less than two attempts.
+	MaxAttempts    int
+	MetricsFactory metrics.Factory
+}
+
+// HedgedReader is a span Reader that sends a duplicate GetTrace request to the underlying
+// reader whenever the previous one is not answered within a delay, so that a single slow
+// shard, replica or node does not dominate the latency of trace reads. The first answer wins
+// and the requests still in flight are cancelled. Other methods are passed through.
+type HedgedReader struct {
+	Reader
+	options HedgedReadOptions
+	metrics hedgedReaderMetrics
+}
+
+type hedgedResult struct {
+	trace   *model.Trace
+	err     error
+	attempt int
+}
+
+// NewHedgedReader creates a HedgedReader.
+func NewHedgedReader(reader Reader, options HedgedReadOptions) *HedgedReader {
+	hedgedMetrics := &hedgedReaderMetrics{}
+	metrics.Init(hedgedMetrics, options.MetricsFactory, nil)
+	return &HedgedReader{
+		Reader:  reader,
+		options: options,
+		metrics: *hedgedMetrics,
+	}
+}
+
+// GetTrace implements Reader#GetTrace. A failed request does not trigger a new one, hedging
+// is not a retry mechanism: the error is returned once no other request is in flight.
+// ErrTraceNotFound is a definitive answer like a found trace.
+func (h *HedgedReader) GetTrace(ctx context.Context, query GetTraceParameters) (*model.Trace, error) {
+	if h.options.MaxAttempts < 2 {
+		return h.Reader.GetTrace(ctx, query)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that the losing requests can complete after GetTrace returned
+	results := make(chan hedgedResult, h.options.MaxAttempts)
+	send := func(attempt int) {
+		go func() {
+			trace, err := h.Reader.GetTrace(ctx, query)
+			results <- hedgedResult{trace: trace, err: err, attempt: attempt}
+		}()
+	}
+	send(0)
+	attempts, inFlight := 1, 1
+	timer := time.NewTimer(h.options.Delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if ctx.Err() != nil {
+				continue
+			}
+			send(attempts)
+			attempts++
+			inFlight++
+			h.metrics.HedgedRequests.Inc(1)
+			if attempts < h.options.MaxAttempts {
+				timer.Reset(h.options.Delay)
+			}
+		case result := <-results:
+			inFlight--
+			if result.err == nil || errors.Is(result.err, ErrTraceNotFound) {
+				if result.attempt > 0 {
+					h.metrics.HedgedWins.Inc(1)
+				}
+				return result.trace, result.err
+			}
+			if inFlight == 0 {
+				return nil, result.err
+			}
+		}
+	}
+}
+
+// GetRawSpans implements RawSpanReader#GetRawSpans if the underlying reader supports it.
+func (h *HedgedReader) GetRawSpans(ctx context.Context, query GetTraceParameters, spanID model.SpanID) ([]RawSpan, error) {
+	rawReader, ok := h.Reader.(RawSpanReader)
+	if !ok {
+		return nil, ErrRawSpansNotSupported
+	}
+	return rawReader.GetRawSpans(ctx, query, spanID)
+}
+
+// GetSpans implements SpanGetter#GetSpans, falling back
+// to GetTrace if the underlying reader does not support it.
+func (h *HedgedReader) GetSpans(ctx context.Context, ids []TraceSpanID) ([]*model.Span, error) {
+	return GetSpans(ctx, h.Reader, ids)
+}
+
+// TraceExists implements TraceExistenceChecker#TraceExists, falling back
+// to GetTrace if the underlying reader does not support it.
+func (h *HedgedReader) TraceExists(ctx context.Context, query GetTraceParameters) (bool, error) {
+	return TraceExists(ctx, h.Reader, query)
+}
+
+// SearchServices implements NameSearcher#SearchServices, falling back
+// to GetServices if the underlying reader does not support it.
+func (h *HedgedReader) SearchServices(ctx context.Context, search NameSearchParameters) ([]string, error) {
+	return SearchServices(ctx, h.Reader, search)
+}
+
+// SearchOperations implements NameSearcher#SearchOperations, falling back
+// to GetOperations if the underlying reader does not support it.
+func (h *HedgedReader) SearchOperations(ctx context.Context, query OperationQueryParameters, search NameSearchParameters) ([]Operation, error) {
+	return SearchOperations(ctx, h.Reader, query, search)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type rawSpanReader struct {
+	*mocks.Reader
+	*mocks.RawSpanReader
+}
+
+func newHedgedReader(t *testing.T, reader spanstore.Reader, maxAttempts int) (*spanstore.HedgedReader, *metricstest.Factory) {
+	metricsFactory := metricstest.NewFactory(0)
+	t.Cleanup(metricsFactory.Stop)
+	return spanstore.NewHedgedReader(reader, spanstore.HedgedReadOptions{
+		Delay:          10 * time.Millisecond,
+		MaxAttempts:    maxAttempts,
+		MetricsFactory: metricsFactory,
+	}), metricsFactory
+}
+
+func assertHedgedMetrics(t *testing.T, metricsFactory *metricstest.Factory, requests, wins int) {
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "hedged_requests", Value: requests},
+		metricstest.ExpectedMetric{Name: "hedged_wins", Value: wins},
+	)
+}
+
+// blockUntilCancelled makes a GetTrace call wait for its context to be cancelled,
+// and signals the cancellation on the returned channel.
+func blockUntilCancelled(call *mock.Call) <-chan struct{} {
+	cancelled := make(chan struct{})
+	call.Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+		close(cancelled)
+	})
+	return cancelled
+}
+
+func TestHedgedReaderDisabled(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	trace := &model.Trace{}
+	reader := mocks.NewReader(t)
+	reader.On("GetTrace", context.Background(), query).Return(trace, nil).After(20 * time.Millisecond).Once()
+	hedgedReader, metricsFactory := newHedgedReader(t, reader, 1)
+
+	actual, err := hedgedReader.GetTrace(context.Background(), query)
+	require.NoError(t, err)
+	assert.Same(t, trace, actual)
+	assertHedgedMetrics(t, metricsFactory, 0, 0)
+}
+
+func TestHedgedReaderFirstAttemptWins(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	trace := &model.Trace{}
+	reader := mocks.NewReader(t)
+	reader.On("GetTrace", mock.Anything, query).Return(trace, nil).Once()
+	hedgedReader, metricsFactory := newHedgedReader(t, reader, 3)
+
+	actual, err := hedgedReader.GetTrace(context.Background(), query)
+	require.NoError(t, err)
+	assert.Same(t, trace, actual)
+	assertHedgedMetrics(t, metricsFactory, 0, 0)
+}
+
+func TestHedgedReaderHedgedAttemptWins(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	testCases := []struct {
+		name  string
+		trace *model.Trace
+		err   error
+	}{
+		{name: "trace found", trace: &model.Trace{}},
+		{name: "trace not found", err: spanstore.ErrTraceNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := mocks.NewReader(t)
+			cancelled := blockUntilCancelled(reader.On("GetTrace", mock.Anything, query).Return(nil, errors.New("cancelled")).Once())
+			reader.On("GetTrace", mock.Anything, query).Return(tc.trace, tc.err).Once()
+			hedgedReader, metricsFactory := newHedgedReader(t, reader, 2)
+
+			actual, err := hedgedReader.GetTrace(context.Background(), query)
+			assert.Equal(t, tc.err, err)
+			assert.Same(t, tc.trace, actual)
+			select {
+			case <-cancelled:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the losing request was not cancelled")
+			}
+			assertHedgedMetrics(t, metricsFactory, 1, 1)
+		})
+	}
+}
+
+func TestHedgedReaderMaxAttempts(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	trace := &model.Trace{}
+	reader := mocks.NewReader(t)
+	first := blockUntilCancelled(reader.On("GetTrace", mock.Anything, query).Return(nil, context.Canceled).Once())
+	second := blockUntilCancelled(reader.On("GetTrace", mock.Anything, query).Return(nil, context.Canceled).Once())
+	reader.On("GetTrace", mock.Anything, query).Return(trace, nil).After(50 * time.Millisecond).Once()
+	hedgedReader, metricsFactory := newHedgedReader(t, reader, 3)
+
+	actual, err := hedgedReader.GetTrace(context.Background(), query)
+	require.NoError(t, err)
+	assert.Same(t, trace, actual)
+	<-first
+	<-second
+	// no fourth request was sent while the third one was outstanding
+	assertHedgedMetrics(t, metricsFactory, 2, 1)
+}
+
+func TestHedgedReaderErrors(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	storageErr := errors.New("storage error")
+
+	t.Run("failure is not retried", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.On("GetTrace", mock.Anything, query).Return(nil, storageErr).Once()
+		hedgedReader, metricsFactory := newHedgedReader(t, reader, 2)
+
+		_, err := hedgedReader.GetTrace(context.Background(), query)
+		require.ErrorIs(t, err, storageErr)
+		assertHedgedMetrics(t, metricsFactory, 0, 0)
+	})
+
+	t.Run("failure waits for requests in flight", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.On("GetTrace", mock.Anything, query).Return(nil, storageErr).After(20 * time.Millisecond).Once()
+		reader.On("GetTrace", mock.Anything, query).Return(nil, storageErr).After(50 * time.Millisecond).Once()
+		hedgedReader, metricsFactory := newHedgedReader(t, reader, 2)
+
+		_, err := hedgedReader.GetTrace(context.Background(), query)
+		require.ErrorIs(t, err, storageErr)
+		// both requests must have completed for the expectations of the mock to be met
+		assertHedgedMetrics(t, metricsFactory, 1, 0)
+	})
+}
+
+func TestHedgedReaderPassesOptionalInterfaces(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	spanID := model.NewSpanID(2)
+	rawSpans := []spanstore.RawSpan{{Location: "index"}}
+	rawReader := mocks.NewRawSpanReader(t)
+	rawReader.On("GetRawSpans", context.Background(), query, spanID).Return(rawSpans, nil).Once()
+	reader := mocks.NewReader(t)
+	reader.On("GetTrace", context.Background(), query).Return(nil, spanstore.ErrTraceNotFound).Once()
+	hedgedReader, _ := newHedgedReader(t, rawSpanReader{Reader: reader, RawSpanReader: rawReader}, 1)
+
+	actual, err := hedgedReader.GetRawSpans(context.Background(), query, spanID)
+	require.NoError(t, err)
+	assert.Equal(t, rawSpans, actual)
+
+	exists, err := hedgedReader.TraceExists(context.Background(), query)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	hedgedReader, _ = newHedgedReader(t, reader, 1)
+	_, err = hedgedReader.GetRawSpans(context.Background(), query, spanID)
+	require.ErrorIs(t, err, spanstore.ErrRawSpansNotSupported)
+
+	search := spanstore.NameSearchParameters{Text: "front", Mode: spanstore.NameMatchPrefix}
+	operationQuery := spanstore.OperationQueryParameters{ServiceName: "frontend"}
+	reader.On("GetServices", context.Background()).Return([]string{"frontend", "backend"}, nil).Once()
+	reader.On("GetOperations", context.Background(), operationQuery).Return([]spanstore.Operation{{Name: "login"}}, nil).Once()
+	services, err := hedgedReader.SearchServices(context.Background(), search)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, services)
+	operations, err := hedgedReader.SearchOperations(context.Background(), operationQuery, search)
+	require.NoError(t, err)
+	assert.Empty(t, operations)
+}
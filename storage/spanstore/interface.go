@@ -17,6 +17,7 @@ package spanstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -26,6 +27,15 @@ import (
 // ErrTraceNotFound is returned by Reader's GetTrace if no data is found for given trace ID.
 var ErrTraceNotFound = errors.New("trace not found")
 
+// ErrInvalidTimeWindow is returned by GetTraceParameters.Validate if the end time precedes the start time.
+var ErrInvalidTimeWindow = errors.New("end time must not be before start time")
+
+// ErrRawSpansNotSupported is returned when the span storage cannot expose spans in their stored form.
+var ErrRawSpansNotSupported = errors.New("reading raw spans is not supported by the span storage")
+
+// ErrDeleteTracesNotSupported is returned when the span storage cannot delete traces.
+var ErrDeleteTracesNotSupported = errors.New("deleting traces is not supported by the span storage")
+
 // Writer writes spans to storage.
 type Writer interface {
 	WriteSpan(ctx context.Context, span *model.Span) error
@@ -36,7 +46,7 @@ type Reader interface {
 	// GetTrace retrieves the trace with a given id.
 	//
 	// If no spans are stored for this trace, it returns ErrTraceNotFound.
-	GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error)
+	GetTrace(ctx context.Context, query GetTraceParameters) (*model.Trace, error)
 
 	// GetServices returns all service names known to the backend from spans
 	// within its retention period.
@@ -61,6 +71,87 @@ type Reader interface {
 	FindTraceIDs(ctx context.Context, query *TraceQueryParameters) ([]model.TraceID, error)
 }
 
+// RawSpanReader is an optional interface implemented by span readers that can return
+// spans in the native representation of their backend, e.g. to diagnose mapping issues
+// between what is stored and what the API returns.
+type RawSpanReader interface {
+	// GetRawSpans returns every stored copy of the span with the given ID in the trace.
+	//
+	// If nothing is stored for the span, it returns an empty slice.
+	GetRawSpans(ctx context.Context, query GetTraceParameters, spanID model.SpanID) ([]RawSpan, error)
+}
+
+// SpanGetter is an optional interface implemented by span readers that can fetch spans of
+// several traces without reading the whole traces, e.g. to follow the links between traces.
+type SpanGetter interface {
+	// GetSpans returns the stored spans with the given IDs. The spans that are not stored are omitted.
+	GetSpans(ctx context.Context, ids []TraceSpanID) ([]*model.Span, error)
+}
+
+// TraceSpanID identifies a span by its trace ID and span ID.
+type TraceSpanID struct {
+	TraceID model.TraceID
+	SpanID  model.SpanID
+}
+
+// TraceDeleter is an optional interface implemented by span writers that can delete
+// traces, e.g. to honor the deletion requests of data subjects.
+type TraceDeleter interface {
+	// DeleteTraces deletes all the spans of the given traces.
+	//
+	// Deleting a trace that is not stored is not an error.
+	DeleteTraces(ctx context.Context, traceIDs []model.TraceID) error
+}
+
+// TraceExistenceChecker is an optional interface implemented by span readers that can tell
+// whether a trace is stored more cheaply than by loading all of its spans.
+type TraceExistenceChecker interface {
+	// TraceExists returns true if at least one span of the trace is stored.
+	TraceExists(ctx context.Context, query GetTraceParameters) (bool, error)
+}
+
+// NameSearcher is an optional interface implemented by span readers that can search
+// service and operation names without returning all of them, see SearchServices.
+type NameSearcher interface {
+	// SearchServices returns the service names matching the search, best matches first.
+	SearchServices(ctx context.Context, search NameSearchParameters) ([]string, error)
+
+	// SearchOperations returns the operations of query.ServiceName whose name matches
+	// the search, best matches first.
+	SearchOperations(ctx context.Context, query OperationQueryParameters, search NameSearchParameters) ([]Operation, error)
+}
+
+// RawSpan is a span in the native representation of the storage backend.
+type RawSpan struct {
+	// Location identifies where the span is stored, e.g. an index or a table.
+	Location string `json:"location"`
+	// Document is the stored span encoded as JSON, e.g. an Elasticsearch _source or a Cassandra row.
+	Document json.RawMessage `json:"document"`
+}
+
+// GetTraceParameters contains parameters for retrieving a single trace.
+type GetTraceParameters struct {
+	TraceID model.TraceID
+	// StartTime and EndTime are optional hints of the time window in which
+	// the trace is expected to exist. Backends may use them to narrow the
+	// search, e.g. to limit the set of indices being queried. A zero value
+	// means no hint, and the backend falls back to its default window.
+	// When only one bound is set, backends derive the other one themselves.
+	//
+	// The hints are not forwarded to remote storage plugins, because
+	// storage_v1.GetTraceRequest has no fields for them.
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Validate checks that the time window, when both bounds are set, is not inverted.
+func (p GetTraceParameters) Validate() error {
+	if !p.StartTime.IsZero() && !p.EndTime.IsZero() && p.EndTime.Before(p.StartTime) {
+		return ErrInvalidTimeWindow
+	}
+	return nil
+}
+
 // TraceQueryParameters contains parameters of a trace query.
 type TraceQueryParameters struct {
 	ServiceName   string
@@ -71,16 +162,47 @@ type TraceQueryParameters struct {
 	DurationMin   time.Duration
 	DurationMax   time.Duration
 	NumTraces     int
+	// ServiceNamespace and ServiceInstanceID match the service.namespace and service.instance.id
+	// process tags, see AllTags for the storage backends that do not index them separately.
+	ServiceNamespace  string
+	ServiceInstanceID string
+}
+
+// AllTags returns the tags of the query with the service namespace and instance ID,
+// for the storage backends searching them as any other process tag.
+func (p *TraceQueryParameters) AllTags() map[string]string {
+	if p.ServiceNamespace == "" && p.ServiceInstanceID == "" {
+		return p.Tags
+	}
+	tags := make(map[string]string, len(p.Tags)+2)
+	for k, v := range p.Tags {
+		tags[k] = v
+	}
+	if p.ServiceNamespace != "" {
+		tags[model.ServiceNamespaceKey] = p.ServiceNamespace
+	}
+	if p.ServiceInstanceID != "" {
+		tags[model.ServiceInstanceIDKey] = p.ServiceInstanceID
+	}
+	return tags
 }
 
 // OperationQueryParameters contains parameters of query operations, empty spanKind means get operations for all kinds of span.
 type OperationQueryParameters struct {
 	ServiceName string
 	SpanKind    string
+	// WithStats asks for the LastSeen and SpanCount of the operations, which are left
+	// unset by the storage backends that do not support it.
+	WithStats bool
 }
 
 // Operation contains operation name and span kind
 type Operation struct {
 	Name     string
 	SpanKind string
+	// LastSeen is the start time of the latest span of the operation, set when the operation stats are asked for.
+	LastSeen time.Time
+	// SpanCount is the number of spans of the operation within the lookback of the storage,
+	// set when the operation stats are asked for.
+	SpanCount int64
 }
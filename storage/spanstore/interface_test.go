@@ -17,9 +17,25 @@ package spanstore
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
 
+func TestTraceQueryParametersAllTags(t *testing.T) {
+	query := &TraceQueryParameters{Tags: map[string]string{"http.method": "GET"}}
+	assert.Equal(t, query.Tags, query.AllTags())
+
+	query.ServiceNamespace, query.ServiceInstanceID = "shop", "checkout-1"
+	assert.Equal(t, map[string]string{
+		"http.method":              "GET",
+		model.ServiceNamespaceKey:  "shop",
+		model.ServiceInstanceIDKey: "checkout-1",
+	}, query.AllTags())
+	assert.Len(t, query.Tags, 1, "the tags of the query are not changed")
+}
+
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
@@ -89,13 +89,50 @@ func (m *ReadMetricsDecorator) FindTraceIDs(ctx context.Context, traceQuery *spa
 }
 
 // GetTrace implements spanstore.Reader#GetTrace
-func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
 	start := time.Now()
-	retMe, err := m.spanReader.GetTrace(ctx, traceID)
+	retMe, err := m.spanReader.GetTrace(ctx, query)
 	m.getTraceMetrics.emit(err, time.Since(start), 1)
 	return retMe, err
 }
 
+// GetSpans implements spanstore.SpanGetter#GetSpans, falling back
+// to GetTrace if the underlying reader does not support it.
+func (m *ReadMetricsDecorator) GetSpans(ctx context.Context, ids []spanstore.TraceSpanID) ([]*model.Span, error) {
+	return spanstore.GetSpans(ctx, m.spanReader, ids)
+}
+
+// TraceExists implements spanstore.TraceExistenceChecker#TraceExists, falling back
+// to GetTrace if the underlying reader does not support it.
+func (m *ReadMetricsDecorator) TraceExists(ctx context.Context, query spanstore.GetTraceParameters) (bool, error) {
+	return spanstore.TraceExists(ctx, m.spanReader, query)
+}
+
+// SearchServices implements spanstore.NameSearcher#SearchServices, falling back
+// to GetServices if the underlying reader does not support it.
+func (m *ReadMetricsDecorator) SearchServices(ctx context.Context, search spanstore.NameSearchParameters) ([]string, error) {
+	return spanstore.SearchServices(ctx, m.spanReader, search)
+}
+
+// SearchOperations implements spanstore.NameSearcher#SearchOperations, falling back
+// to GetOperations if the underlying reader does not support it.
+func (m *ReadMetricsDecorator) SearchOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+	search spanstore.NameSearchParameters,
+) ([]spanstore.Operation, error) {
+	return spanstore.SearchOperations(ctx, m.spanReader, query, search)
+}
+
+// GetRawSpans implements spanstore.RawSpanReader#GetRawSpans if the underlying reader supports it.
+func (m *ReadMetricsDecorator) GetRawSpans(ctx context.Context, query spanstore.GetTraceParameters, spanID model.SpanID) ([]spanstore.RawSpan, error) {
+	rawReader, ok := m.spanReader.(spanstore.RawSpanReader)
+	if !ok {
+		return nil, spanstore.ErrRawSpansNotSupported
+	}
+	return rawReader.GetRawSpans(ctx, query, spanID)
+}
+
 // GetServices implements spanstore.Reader#GetServices
 func (m *ReadMetricsDecorator) GetServices(ctx context.Context) ([]string, error) {
 	start := time.Now()
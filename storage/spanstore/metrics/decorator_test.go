@@ -40,8 +40,8 @@ func TestSuccessfulUnderlyingCalls(t *testing.T) {
 	mockReader.On("GetOperations", context.Background(), operationQuery).
 		Return([]spanstore.Operation{}, nil)
 	mrs.GetOperations(context.Background(), operationQuery)
-	mockReader.On("GetTrace", context.Background(), model.TraceID{}).Return(&model.Trace{}, nil)
-	mrs.GetTrace(context.Background(), model.TraceID{})
+	mockReader.On("GetTrace", context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}}).Return(&model.Trace{}, nil)
+	mrs.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 	mockReader.On("FindTraces", context.Background(), &spanstore.TraceQueryParameters{}).
 		Return([]*model.Trace{}, nil)
 	mrs.FindTraces(context.Background(), &spanstore.TraceQueryParameters{})
@@ -108,9 +108,9 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 	mockReader.On("GetOperations", context.Background(), operationQuery).
 		Return(nil, errors.New("Failure"))
 	mrs.GetOperations(context.Background(), operationQuery)
-	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+	mockReader.On("GetTrace", context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}}).
 		Return(nil, errors.New("Failure"))
-	mrs.GetTrace(context.Background(), model.TraceID{})
+	mrs.GetTrace(context.Background(), spanstore.GetTraceParameters{TraceID: model.TraceID{}})
 	mockReader.On("FindTraces", context.Background(), &spanstore.TraceQueryParameters{}).
 		Return(nil, errors.New("Failure"))
 	mrs.FindTraces(context.Background(), &spanstore.TraceQueryParameters{})
@@ -143,3 +143,64 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
 }
+
+type rawSpanReader struct {
+	*mocks.Reader
+	*mocks.RawSpanReader
+}
+
+func TestGetRawSpans(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	spanID := model.NewSpanID(2)
+
+	mrs := metrics.NewReadMetricsDecorator(&mocks.Reader{}, metricstest.NewFactory(0))
+	_, err := mrs.GetRawSpans(context.Background(), query, spanID)
+	assert.ErrorIs(t, err, spanstore.ErrRawSpansNotSupported)
+
+	rawReader := &mocks.RawSpanReader{}
+	expected := []spanstore.RawSpan{{Location: "jaeger-span-2024-01-01", Document: []byte(`{}`)}}
+	rawReader.On("GetRawSpans", context.Background(), query, spanID).Return(expected, nil)
+	mrs = metrics.NewReadMetricsDecorator(rawSpanReader{Reader: &mocks.Reader{}, RawSpanReader: rawReader}, metricstest.NewFactory(0))
+	rawSpans, err := mrs.GetRawSpans(context.Background(), query, spanID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rawSpans)
+}
+
+type traceExistenceChecker struct {
+	*mocks.Reader
+	*mocks.TraceExistenceChecker
+}
+
+func TestTraceExists(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+
+	reader := &mocks.Reader{}
+	reader.On("GetTrace", context.Background(), query).Return(nil, spanstore.ErrTraceNotFound)
+	mrs := metrics.NewReadMetricsDecorator(reader, metricstest.NewFactory(0))
+	exists, err := mrs.TraceExists(context.Background(), query)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	checker := &mocks.TraceExistenceChecker{}
+	checker.On("TraceExists", context.Background(), query).Return(true, nil)
+	mrs = metrics.NewReadMetricsDecorator(traceExistenceChecker{Reader: &mocks.Reader{}, TraceExistenceChecker: checker}, metricstest.NewFactory(0))
+	exists, err = mrs.TraceExists(context.Background(), query)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestSearchNames(t *testing.T) {
+	search := spanstore.NameSearchParameters{Text: "front", Mode: spanstore.NameMatchPrefix}
+	query := spanstore.OperationQueryParameters{ServiceName: "frontend"}
+	reader := &mocks.Reader{}
+	reader.On("GetServices", context.Background()).Return([]string{"frontend", "backend"}, nil)
+	reader.On("GetOperations", context.Background(), query).Return([]spanstore.Operation{{Name: "frontpage"}, {Name: "login"}}, nil)
+	mrs := metrics.NewReadMetricsDecorator(reader, metricstest.NewFactory(0))
+
+	services, err := mrs.SearchServices(context.Background(), search)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, services)
+	operations, err := mrs.SearchOperations(context.Background(), query, search)
+	assert.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "frontpage"}}, operations)
+}
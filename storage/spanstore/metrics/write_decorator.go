@@ -0,0 +1,112 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// WriteOperation is the type of a storage write made to store a span, e.g. the write of
+// the span itself or of one of the search indexes pointing to it.
+type WriteOperation string
+
+const (
+	// WriteOperationSpan is the write of the span itself.
+	WriteOperationSpan WriteOperation = "span"
+	// WriteOperationIndex is the write of a search index entry, e.g. by tag or duration.
+	WriteOperationIndex WriteOperation = "index"
+	// WriteOperationService is the write of the service and operation document of the span.
+	WriteOperationService WriteOperation = "service"
+	// WriteOperationLookup is the write of a lookup table, e.g. of the service or operation names.
+	WriteOperationLookup WriteOperation = "lookup"
+	// WriteOperationOther is any other write, the operation types being a fixed set so that
+	// the cardinality of the metrics label stays bounded.
+	WriteOperationOther WriteOperation = "other"
+)
+
+var writeOperations = []WriteOperation{
+	WriteOperationSpan,
+	WriteOperationIndex,
+	WriteOperationService,
+	WriteOperationLookup,
+	WriteOperationOther,
+}
+
+// WriteMetricsDecorator wraps a spanstore.Writer and collects metrics around each span write,
+// as well as around the storage writes the span writer reports with EmitWriteOperation.
+type WriteMetricsDecorator struct {
+	spanWriter       spanstore.Writer
+	writeSpanMetrics *writeMetrics
+	operationMetrics map[WriteOperation]*writeMetrics
+}
+
+type writeMetrics struct {
+	Errors     metrics.Counter `metric:"requests" tags:"result=err"`
+	Successes  metrics.Counter `metric:"requests" tags:"result=ok"`
+	ErrLatency metrics.Timer   `metric:"latency" tags:"result=err"`
+	OKLatency  metrics.Timer   `metric:"latency" tags:"result=ok"`
+}
+
+func (w *writeMetrics) emit(err error, latency time.Duration) {
+	if err != nil {
+		w.Errors.Inc(1)
+		w.ErrLatency.Record(latency)
+	} else {
+		w.Successes.Inc(1)
+		w.OKLatency.Record(latency)
+	}
+}
+
+func buildWriteMetrics(operation string, metricsFactory metrics.Factory) *writeMetrics {
+	wMetrics := &writeMetrics{}
+	scoped := metricsFactory.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"operation": operation}})
+	metrics.Init(wMetrics, scoped, nil)
+	return wMetrics
+}
+
+// NewWriteMetricsDecorator returns a new WriteMetricsDecorator.
+func NewWriteMetricsDecorator(spanWriter spanstore.Writer, metricsFactory metrics.Factory) *WriteMetricsDecorator {
+	operationMetrics := make(map[WriteOperation]*writeMetrics, len(writeOperations))
+	operationsFactory := metricsFactory.Namespace(metrics.NSOptions{Name: "write_operations"})
+	for _, operation := range writeOperations {
+		operationMetrics[operation] = buildWriteMetrics(string(operation), operationsFactory)
+	}
+	return &WriteMetricsDecorator{
+		spanWriter:       spanWriter,
+		writeSpanMetrics: buildWriteMetrics("write_span", metricsFactory),
+		operationMetrics: operationMetrics,
+	}
+}
+
+// WriteSpan implements spanstore.Writer#WriteSpan
+func (m *WriteMetricsDecorator) WriteSpan(ctx context.Context, span *model.Span) error {
+	start := time.Now()
+	err := m.spanWriter.WriteSpan(context.WithValue(ctx, writeOperationsKey{}, m), span)
+	m.writeSpanMetrics.emit(err, time.Since(start))
+	return err
+}
+
+func (m *WriteMetricsDecorator) emitOperation(operation WriteOperation, err error, latency time.Duration) {
+	operationMetrics, ok := m.operationMetrics[operation]
+	if !ok {
+		operationMetrics = m.operationMetrics[WriteOperationOther]
+	}
+	operationMetrics.emit(err, latency)
+}
+
+type writeOperationsKey struct{}
+
+// EmitWriteOperation records the outcome and latency of a storage write made by a span writer
+// while writing a span with the context. It does nothing if the span writer is not wrapped
+// by a WriteMetricsDecorator.
+func EmitWriteOperation(ctx context.Context, operation WriteOperation, err error, latency time.Duration) {
+	if m, ok := ctx.Value(writeOperationsKey{}).(*WriteMetricsDecorator); ok {
+		m.emitOperation(operation, err, latency)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore/metrics"
+)
+
+type writeOperationsWriter struct {
+	err error
+}
+
+func (w writeOperationsWriter) WriteSpan(ctx context.Context, _ *model.Span) error {
+	metrics.EmitWriteOperation(ctx, metrics.WriteOperationSpan, nil, time.Millisecond)
+	metrics.EmitWriteOperation(ctx, metrics.WriteOperationIndex, nil, time.Millisecond)
+	metrics.EmitWriteOperation(ctx, metrics.WriteOperationIndex, w.err, time.Millisecond)
+	metrics.EmitWriteOperation(ctx, metrics.WriteOperation("tag_index"), nil, time.Millisecond)
+	return w.err
+}
+
+func TestWriteMetricsDecorator(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	defer mf.Stop()
+
+	require.NoError(t, metrics.NewWriteMetricsDecorator(writeOperationsWriter{}, mf).WriteSpan(context.Background(), &model.Span{}))
+	writeErr := errors.New("write error")
+	require.ErrorIs(t, metrics.NewWriteMetricsDecorator(writeOperationsWriter{err: writeErr}, mf).WriteSpan(context.Background(), &model.Span{}), writeErr)
+
+	counters, gauges := mf.Snapshot()
+	assert.Equal(t, map[string]int64{
+		"requests|operation=write_span|result=ok":              1,
+		"requests|operation=write_span|result=err":             1,
+		"write_operations.requests|operation=span|result=ok":   2,
+		"write_operations.requests|operation=index|result=ok":  3,
+		"write_operations.requests|operation=index|result=err": 1,
+		"write_operations.requests|operation=other|result=ok":  2,
+	}, counters, "the unknown operations are reported as other")
+	assert.Contains(t, gauges, "write_operations.latency|operation=index|result=err.P50")
+
+	// the writes outside of a decorator are not reported
+	metrics.EmitWriteOperation(context.Background(), metrics.WriteOperationSpan, nil, time.Millisecond)
+	counters, _ = mf.Snapshot()
+	assert.Equal(t, int64(2), counters["write_operations.requests|operation=span|result=ok"])
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// NameMatchPrefix matches the names starting with the searched text, ignoring case.
+	NameMatchPrefix = "prefix"
+	// NameMatchFuzzy matches the names containing the searched text, ignoring case,
+	// or enough of its trigrams to tolerate typos, see MinFuzzySimilarity.
+	NameMatchFuzzy = "fuzzy"
+
+	// MinFuzzySimilarity is the share of the trigrams of the searched text
+	// that a name must contain to match it in NameMatchFuzzy mode.
+	MinFuzzySimilarity = 0.5
+)
+
+// NameSearchParameters contains the parameters of a search of service or operation names.
+type NameSearchParameters struct {
+	Text string
+	// Mode is NameMatchPrefix or NameMatchFuzzy.
+	Mode string
+}
+
+// Validate checks that the mode of the search is known.
+func (p NameSearchParameters) Validate() error {
+	switch p.Mode {
+	case NameMatchPrefix, NameMatchFuzzy:
+		return nil
+	default:
+		return fmt.Errorf("unknown name match mode %q, use %q or %q", p.Mode, NameMatchPrefix, NameMatchFuzzy)
+	}
+}
+
+// Trigrams returns the distinct sequences of three characters of the lower-cased string.
+func Trigrams(s string) []string {
+	runes := []rune(strings.ToLower(s))
+	seen := make(map[string]struct{})
+	var trigrams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if _, ok := seen[trigram]; !ok {
+			seen[trigram] = struct{}{}
+			trigrams = append(trigrams, trigram)
+		}
+	}
+	return trigrams
+}
+
+// NameScore returns whether the name matches the search, and how well, between 0 and 1.
+func NameScore(search NameSearchParameters, name string) (float64, bool) {
+	text, lowerName := strings.ToLower(search.Text), strings.ToLower(name)
+	if search.Mode == NameMatchPrefix {
+		return 1, strings.HasPrefix(lowerName, text)
+	}
+	if strings.Contains(lowerName, text) {
+		return 1, true
+	}
+	textTrigrams := Trigrams(text)
+	if len(textTrigrams) == 0 {
+		return 0, false
+	}
+	nameTrigrams := make(map[string]struct{})
+	for _, trigram := range Trigrams(lowerName) {
+		nameTrigrams[trigram] = struct{}{}
+	}
+	common := 0
+	for _, trigram := range textTrigrams {
+		if _, ok := nameTrigrams[trigram]; ok {
+			common++
+		}
+	}
+	score := float64(common) / float64(len(textTrigrams))
+	return score, score >= MinFuzzySimilarity
+}
+
+// rankNames returns the indices of the names matching the search,
+// ordered by decreasing score, then by name.
+func rankNames(search NameSearchParameters, names []string) []int {
+	var matches []int
+	scores := make(map[int]float64)
+	for i, name := range names {
+		if score, ok := NameScore(search, name); ok {
+			matches = append(matches, i)
+			scores[i] = score
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		i, j := matches[a], matches[b]
+		if scores[i] != scores[j] {
+			return scores[i] > scores[j]
+		}
+		return names[i] < names[j]
+	})
+	return matches
+}
+
+// FilterNames returns the names matching the search, best matches first.
+func FilterNames(search NameSearchParameters, names []string) []string {
+	matches := rankNames(search, names)
+	filtered := make([]string, len(matches))
+	for i, match := range matches {
+		filtered[i] = names[match]
+	}
+	return filtered
+}
+
+// FilterOperations returns the operations whose name matches the search, best matches first.
+func FilterOperations(search NameSearchParameters, operations []Operation) []Operation {
+	names := make([]string, len(operations))
+	for i, operation := range operations {
+		names[i] = operation.Name
+	}
+	matches := rankNames(search, names)
+	filtered := make([]Operation, len(matches))
+	for i, match := range matches {
+		filtered[i] = operations[match]
+	}
+	return filtered
+}
+
+// SearchServices searches the service names with the NameSearcher of the reader
+// if it implements one, and filters the result of GetServices otherwise.
+func SearchServices(ctx context.Context, reader Reader, search NameSearchParameters) ([]string, error) {
+	if searcher, ok := reader.(NameSearcher); ok {
+		return searcher.SearchServices(ctx, search)
+	}
+	services, err := reader.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FilterNames(search, services), nil
+}
+
+// SearchOperations searches the operation names with the NameSearcher of the reader
+// if it implements one, and filters the result of GetOperations otherwise.
+func SearchOperations(ctx context.Context, reader Reader, query OperationQueryParameters, search NameSearchParameters) ([]Operation, error) {
+	if searcher, ok := reader.(NameSearcher); ok {
+		return searcher.SearchOperations(ctx, query, search)
+	}
+	operations, err := reader.GetOperations(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return FilterOperations(search, operations), nil
+}
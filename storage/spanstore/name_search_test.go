@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type nameSearcher struct {
+	*mocks.Reader
+	*mocks.NameSearcher
+}
+
+var testServices = []string{"frontend", "checkout-service", "payment-service", "Payments", "cart"}
+
+func TestTrigrams(t *testing.T) {
+	assert.Equal(t, []string{"che", "hec", "eck"}, spanstore.Trigrams("Check"))
+	assert.Equal(t, []string{"aaa"}, spanstore.Trigrams("aaaa"))
+	assert.Empty(t, spanstore.Trigrams("ab"))
+}
+
+func TestFilterNames(t *testing.T) {
+	testCases := []struct {
+		name     string
+		search   spanstore.NameSearchParameters
+		expected []string
+	}{
+		{
+			name:     "prefix",
+			search:   spanstore.NameSearchParameters{Text: "pay", Mode: spanstore.NameMatchPrefix},
+			expected: []string{"Payments", "payment-service"},
+		},
+		{
+			name:     "prefix does not match the middle",
+			search:   spanstore.NameSearchParameters{Text: "service", Mode: spanstore.NameMatchPrefix},
+			expected: []string{},
+		},
+		{
+			name:     "fuzzy substring",
+			search:   spanstore.NameSearchParameters{Text: "SERVICE", Mode: spanstore.NameMatchFuzzy},
+			expected: []string{"checkout-service", "payment-service"},
+		},
+		{
+			name:     "fuzzy typo",
+			search:   spanstore.NameSearchParameters{Text: "chekout", Mode: spanstore.NameMatchFuzzy},
+			expected: []string{"checkout-service"},
+		},
+		{
+			name:     "fuzzy typo with too few common trigrams",
+			search:   spanstore.NameSearchParameters{Text: "paymnts", Mode: spanstore.NameMatchFuzzy},
+			expected: []string{"Payments"},
+		},
+		{
+			name:     "fuzzy short text",
+			search:   spanstore.NameSearchParameters{Text: "ca", Mode: spanstore.NameMatchFuzzy},
+			expected: []string{"cart"},
+		},
+		{
+			name:     "empty text",
+			search:   spanstore.NameSearchParameters{Mode: spanstore.NameMatchFuzzy},
+			expected: []string{"Payments", "cart", "checkout-service", "frontend", "payment-service"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, spanstore.FilterNames(tc.search, testServices))
+		})
+	}
+}
+
+func TestNameScore(t *testing.T) {
+	search := spanstore.NameSearchParameters{Text: "paymnt", Mode: spanstore.NameMatchFuzzy}
+	score, ok := spanstore.NameScore(search, "payment-service")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, score, 0.01)
+	_, ok = spanstore.NameScore(search, "frontend")
+	assert.False(t, ok)
+
+	// the names containing the text rank before those only sharing trigrams with it
+	search.Text = "payment"
+	assert.Equal(t, []string{"payments", "paymen"}, spanstore.FilterNames(search, []string{"paymen", "payments"}))
+}
+
+func TestFilterOperations(t *testing.T) {
+	operations := []spanstore.Operation{{Name: "GET /cart", SpanKind: "server"}, {Name: "HTTP GET", SpanKind: "client"}, {Name: "SELECT"}}
+	search := spanstore.NameSearchParameters{Text: "get", Mode: spanstore.NameMatchPrefix}
+	assert.Equal(t, []spanstore.Operation{{Name: "GET /cart", SpanKind: "server"}}, spanstore.FilterOperations(search, operations))
+	search.Mode = spanstore.NameMatchFuzzy
+	assert.Equal(t, operations[:2], spanstore.FilterOperations(search, operations))
+}
+
+func TestNameSearchParametersValidate(t *testing.T) {
+	require.NoError(t, spanstore.NameSearchParameters{Mode: spanstore.NameMatchPrefix}.Validate())
+	require.NoError(t, spanstore.NameSearchParameters{Mode: spanstore.NameMatchFuzzy}.Validate())
+	require.EqualError(t, spanstore.NameSearchParameters{Mode: "regex"}.Validate(), `unknown name match mode "regex", use "prefix" or "fuzzy"`)
+}
+
+func TestSearchServicesAndOperations(t *testing.T) {
+	ctx := context.Background()
+	search := spanstore.NameSearchParameters{Text: "pay", Mode: spanstore.NameMatchPrefix}
+	query := spanstore.OperationQueryParameters{ServiceName: "payment-service"}
+
+	reader := mocks.NewReader(t)
+	reader.On("GetServices", ctx).Return(testServices, nil).Once()
+	reader.On("GetOperations", ctx, query).Return([]spanstore.Operation{{Name: "pay"}, {Name: "refund"}}, nil).Once()
+	services, err := spanstore.SearchServices(ctx, reader, search)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Payments", "payment-service"}, services)
+	operations, err := spanstore.SearchOperations(ctx, reader, query, search)
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "pay"}}, operations)
+
+	searcher := mocks.NewNameSearcher(t)
+	searcher.On("SearchServices", ctx, search).Return([]string{"payment-service"}, nil).Once()
+	searcher.On("SearchOperations", ctx, query, search).Return([]spanstore.Operation{{Name: "payment"}}, nil).Once()
+	services, err = spanstore.SearchServices(ctx, nameSearcher{Reader: mocks.NewReader(t), NameSearcher: searcher}, search)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payment-service"}, services)
+	operations, err = spanstore.SearchOperations(ctx, nameSearcher{Reader: mocks.NewReader(t), NameSearcher: searcher}, query, search)
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "payment"}}, operations)
+}
+
+func TestSearchServicesAndOperationsErrors(t *testing.T) {
+	ctx := context.Background()
+	search := spanstore.NameSearchParameters{Text: "pay", Mode: spanstore.NameMatchFuzzy}
+	query := spanstore.OperationQueryParameters{ServiceName: "payment-service"}
+	reader := mocks.NewReader(t)
+	reader.On("GetServices", ctx).Return(nil, errors.New("storage error")).Once()
+	reader.On("GetOperations", ctx, query).Return(nil, errors.New("storage error")).Once()
+	_, err := spanstore.SearchServices(ctx, reader, search)
+	require.EqualError(t, err, "storage error")
+	_, err = spanstore.SearchOperations(ctx, reader, query, search)
+	require.EqualError(t, err, "storage error")
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// WriterRoute sends the spans it matches to a span Writer. A route matches a span when
+// all its conditions hold; a route without conditions matches every span.
+type WriterRoute struct {
+	// Name identifies the route in the metrics.
+	Name string
+	// Services are the names of the services of the spans, any service if empty.
+	Services []string
+	// Tags are the values the span or process tags of the spans must have, as strings.
+	Tags map[string]string
+	// Writer saves the spans of the route.
+	Writer Writer
+}
+
+func (r *WriterRoute) matches(span *model.Span) bool {
+	if len(r.Services) > 0 && !slices.Contains(r.Services, span.Process.GetServiceName()) {
+		return false
+	}
+	for key, value := range r.Tags {
+		tag, ok := model.KeyValues(span.Tags).FindByKey(key)
+		if !ok {
+			tag, ok = model.KeyValues(span.Process.GetTags()).FindByKey(key)
+		}
+		if !ok || tag.AsString() != value {
+			return false
+		}
+	}
+	return true
+}
+
+type writerRouteMetrics struct {
+	SpansWritten metrics.Counter `metric:"routed_spans" tags:"result=ok"`
+	SpansFailed  metrics.Counter `metric:"routed_spans" tags:"result=err"`
+}
+
+type writerRoute struct {
+	WriterRoute
+	metrics writerRouteMetrics
+}
+
+// RoutingWriter is a span Writer that saves each span with the Writer of the first route matching it,
+// or with the default Writer when no route matches, e.g. to send the spans of some services to another
+// storage backend. The spans saved by each route are counted in the routed_spans metric, tagged
+// with the route name, "default" for the default Writer.
+type RoutingWriter struct {
+	routes       []writerRoute
+	defaultRoute writerRoute
+}
+
+// NewRoutingWriter creates a RoutingWriter.
+func NewRoutingWriter(routes []WriterRoute, defaultWriter Writer, metricsFactory metrics.Factory) *RoutingWriter {
+	newRoute := func(route WriterRoute) writerRoute {
+		r := writerRoute{WriterRoute: route}
+		metrics.MustInit(&r.metrics, metricsFactory, map[string]string{"route": route.Name})
+		return r
+	}
+	w := &RoutingWriter{
+		defaultRoute: newRoute(WriterRoute{Name: "default", Writer: defaultWriter}),
+	}
+	for _, route := range routes {
+		w.routes = append(w.routes, newRoute(route))
+	}
+	return w
+}
+
+// WriteSpan calls WriteSpan on the span writer of the route of the span.
+func (w *RoutingWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	route := &w.defaultRoute
+	for i := range w.routes {
+		if w.routes[i].matches(span) {
+			route = &w.routes[i]
+			break
+		}
+	}
+	if err := route.Writer.WriteSpan(ctx, span); err != nil {
+		route.metrics.SpansFailed.Inc(1)
+		return err
+	}
+	route.metrics.SpansWritten.Inc(1)
+	return nil
+}
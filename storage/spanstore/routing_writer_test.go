@@ -0,0 +1,67 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type recordingWriteSpanStore struct {
+	spans []string
+}
+
+func (w *recordingWriteSpanStore) WriteSpan(_ context.Context, span *model.Span) error {
+	w.spans = append(w.spans, span.OperationName)
+	return nil
+}
+
+func routedSpansMetric(route, result string, value int) metricstest.ExpectedMetric {
+	return metricstest.ExpectedMetric{
+		Name:  "routed_spans",
+		Tags:  map[string]string{"route": route, "result": result},
+		Value: value,
+	}
+}
+
+func TestRoutingWriter(t *testing.T) {
+	highVolume, tiered, fallback := &recordingWriteSpanStore{}, &recordingWriteSpanStore{}, &recordingWriteSpanStore{}
+	mFactory := metricstest.NewFactory(time.Hour)
+	defer mFactory.Stop()
+	w := spanstore.NewRoutingWriter([]spanstore.WriterRoute{
+		{Name: "high-volume", Services: []string{"frontend", "cart"}, Writer: highVolume},
+		{Name: "cold", Tags: map[string]string{"tier": "cold", "sampled": "true"}, Writer: tiered},
+		{Name: "failing", Services: []string{"payments"}, Writer: &errProneWriteSpanStore{}},
+	}, fallback, mFactory)
+
+	spans := []*model.Span{
+		{OperationName: "a", Process: model.NewProcess("frontend", nil), Tags: model.KeyValues{model.String("tier", "cold")}},
+		{OperationName: "b", Process: model.NewProcess("cart", nil)},
+		{OperationName: "c", Process: model.NewProcess("orders", []model.KeyValue{model.String("tier", "cold")}), Tags: model.KeyValues{model.Bool("sampled", true)}},
+		{OperationName: "d", Process: model.NewProcess("orders", nil), Tags: model.KeyValues{model.String("tier", "cold")}},
+		{OperationName: "e", Process: model.NewProcess("mysql", nil)},
+	}
+	for _, span := range spans {
+		require.NoError(t, w.WriteSpan(context.Background(), span))
+	}
+	require.ErrorIs(t, w.WriteSpan(context.Background(), &model.Span{Process: model.NewProcess("payments", nil)}), errIWillAlwaysFail)
+
+	assert.Equal(t, []string{"a", "b"}, highVolume.spans, "the first matching route is used")
+	assert.Equal(t, []string{"c"}, tiered.spans, "the tags can be span or process tags")
+	assert.Equal(t, []string{"d", "e"}, fallback.spans)
+	mFactory.AssertCounterMetrics(t,
+		routedSpansMetric("high-volume", "ok", 2),
+		routedSpansMetric("cold", "ok", 1),
+		routedSpansMetric("default", "ok", 2),
+		routedSpansMetric("failing", "err", 1),
+	)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// GetSpans returns the stored spans with the given IDs, using the SpanGetter of the reader
+// if it implements one, and falling back to GetTrace for each of their traces otherwise.
+func GetSpans(ctx context.Context, reader Reader, ids []TraceSpanID) ([]*model.Span, error) {
+	if getter, ok := reader.(SpanGetter); ok {
+		return getter.GetSpans(ctx, ids)
+	}
+	var traceIDs []model.TraceID
+	spanIDs := make(map[model.TraceID]map[model.SpanID]struct{})
+	for _, id := range ids {
+		if _, ok := spanIDs[id.TraceID]; !ok {
+			traceIDs = append(traceIDs, id.TraceID)
+			spanIDs[id.TraceID] = make(map[model.SpanID]struct{})
+		}
+		spanIDs[id.TraceID][id.SpanID] = struct{}{}
+	}
+	var spans []*model.Span
+	for _, traceID := range traceIDs {
+		trace, err := reader.GetTrace(ctx, GetTraceParameters{TraceID: traceID})
+		if errors.Is(err, ErrTraceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, span := range trace.Spans {
+			if _, ok := spanIDs[traceID][span.SpanID]; ok {
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans, nil
+}
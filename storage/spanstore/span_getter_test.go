@@ -0,0 +1,59 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type spanGetter struct {
+	*mocks.Reader
+	*mocks.SpanGetter
+}
+
+func TestGetSpansWithGetter(t *testing.T) {
+	ids := []spanstore.TraceSpanID{{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(1)}}
+	spans := []*model.Span{{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(1)}}
+	getter := mocks.NewSpanGetter(t)
+	getter.On("GetSpans", context.Background(), ids).Return(spans, nil).Once()
+	reader := spanGetter{Reader: mocks.NewReader(t), SpanGetter: getter}
+
+	actual, err := spanstore.GetSpans(context.Background(), reader, ids)
+	require.NoError(t, err)
+	assert.Equal(t, spans, actual)
+}
+
+func TestGetSpansFallsBackToGetTrace(t *testing.T) {
+	traceID1, traceID2, traceID3 := model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)
+	span := func(traceID model.TraceID, spanID uint64) *model.Span {
+		return &model.Span{TraceID: traceID, SpanID: model.NewSpanID(spanID)}
+	}
+	reader := mocks.NewReader(t)
+	reader.On("GetTrace", context.Background(), spanstore.GetTraceParameters{TraceID: traceID1}).
+		Return(&model.Trace{Spans: []*model.Span{span(traceID1, 1), span(traceID1, 2), span(traceID1, 3)}}, nil).Once()
+	reader.On("GetTrace", context.Background(), spanstore.GetTraceParameters{TraceID: traceID2}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	spans, err := spanstore.GetSpans(context.Background(), reader, []spanstore.TraceSpanID{
+		{TraceID: traceID1, SpanID: model.NewSpanID(3)},
+		{TraceID: traceID2, SpanID: model.NewSpanID(1)},
+		{TraceID: traceID1, SpanID: model.NewSpanID(1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Span{span(traceID1, 1), span(traceID1, 3)}, spans, "each trace is read once")
+
+	reader.On("GetTrace", context.Background(), spanstore.GetTraceParameters{TraceID: traceID3}).
+		Return(nil, errors.New("storage error")).Once()
+	_, err = spanstore.GetSpans(context.Background(), reader, []spanstore.TraceSpanID{{TraceID: traceID3, SpanID: model.NewSpanID(1)}})
+	require.EqualError(t, err, "storage error")
+}
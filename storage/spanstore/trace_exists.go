@@ -0,0 +1,22 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+)
+
+// TraceExists checks whether the trace is stored, using the TraceExistenceChecker
+// of the reader if it implements one, and falling back to GetTrace otherwise.
+func TraceExists(ctx context.Context, reader Reader, query GetTraceParameters) (bool, error) {
+	if checker, ok := reader.(TraceExistenceChecker); ok {
+		return checker.TraceExists(ctx, query)
+	}
+	_, err := reader.GetTrace(ctx, query)
+	if errors.Is(err, ErrTraceNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
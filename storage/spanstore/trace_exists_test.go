@@ -0,0 +1,62 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type traceExistenceChecker struct {
+	*mocks.Reader
+	*mocks.TraceExistenceChecker
+}
+
+func TestTraceExistsWithChecker(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	checker := mocks.NewTraceExistenceChecker(t)
+	checker.On("TraceExists", context.Background(), query).Return(true, nil).Once()
+	reader := traceExistenceChecker{Reader: mocks.NewReader(t), TraceExistenceChecker: checker}
+
+	exists, err := spanstore.TraceExists(context.Background(), reader, query)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestTraceExistsFallsBackToGetTrace(t *testing.T) {
+	query := spanstore.GetTraceParameters{TraceID: model.NewTraceID(0, 1)}
+	testCases := []struct {
+		name      string
+		trace     *model.Trace
+		err       error
+		exists    bool
+		expectErr string
+	}{
+		{name: "found", trace: &model.Trace{}, exists: true},
+		{name: "not found", err: spanstore.ErrTraceNotFound},
+		{name: "error", err: errors.New("storage error"), expectErr: "storage error"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := mocks.NewReader(t)
+			reader.On("GetTrace", context.Background(), query).Return(tc.trace, tc.err).Once()
+
+			exists, err := spanstore.TraceExists(context.Background(), reader, query)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.exists, exists)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storagetest provides the storage conformance test suite used to
+// certify span, dependency and sampling store implementations.
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+// conformanceSamplingBuckets is the number of buckets requested from the sampling store.
+const conformanceSamplingBuckets = 10
+
+// ConformanceOption adjusts the StorageIntegration built by RunConformance,
+// e.g. to skip tests of features the storage does not support.
+type ConformanceOption func(s *StorageIntegration)
+
+// RunConformance runs the storage integration tests against the components created
+// by an initialized storage.Factory, to certify a storage implementation,
+// e.g. a remote storage accessed via the gRPC storage plugin.
+//
+// The factory is expected to implement storage.Purger, which is used to clean
+// the storage between tests, unless a CleanUp function is provided with an option.
+// Archive, dependency and sampling tests run only when the factory supports them:
+// storage.ArchiveFactory, a dependency reader that also implements dependencystore.Writer,
+// and storage.SamplingStoreFactory, respectively.
+func RunConformance(t *testing.T, factory storage.Factory, options ...ConformanceOption) {
+	s := &StorageIntegration{}
+	var err error
+	s.SpanReader, err = factory.CreateSpanReader()
+	require.NoError(t, err)
+	s.SpanWriter, err = factory.CreateSpanWriter()
+	require.NoError(t, err)
+	s.DependencyReader, err = factory.CreateDependencyReader()
+	require.NoError(t, err)
+	if w, ok := s.DependencyReader.(dependencystore.Writer); ok {
+		s.DependencyWriter = w
+	}
+
+	if af, ok := factory.(storage.ArchiveFactory); ok {
+		s.ArchiveSpanReader, err = af.CreateArchiveSpanReader()
+		if err == nil {
+			s.ArchiveSpanWriter, err = af.CreateArchiveSpanWriter()
+		}
+		s.SkipArchiveTest = err != nil
+	} else {
+		s.SkipArchiveTest = true
+	}
+
+	if sf, ok := factory.(storage.SamplingStoreFactory); ok {
+		s.SamplingStore, err = sf.CreateSamplingStore(conformanceSamplingBuckets)
+		require.NoError(t, err)
+	}
+
+	if purger, ok := factory.(storage.Purger); ok {
+		s.CleanUp = func(t *testing.T) {
+			require.NoError(t, purger.Purge(context.Background()))
+		}
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+	require.NotNil(t, s.CleanUp, "factory must implement storage.Purger or a CleanUp function must be provided")
+	s.RunAll(t)
+}
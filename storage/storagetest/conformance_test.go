@@ -0,0 +1,43 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage"
+)
+
+func TestRunConformanceMemory(t *testing.T) {
+	f := memory.NewFactory()
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	RunConformance(t, f)
+}
+
+// spanOnlyFactory hides the optional interfaces of the wrapped factory.
+type spanOnlyFactory struct {
+	storage.Factory
+}
+
+func TestRunConformanceWithCleanUpOption(t *testing.T) {
+	f := memory.NewFactory()
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	cleanUps := 0
+	RunConformance(t, spanOnlyFactory{Factory: f}, func(s *StorageIntegration) {
+		assert.True(t, s.SkipArchiveTest)
+		assert.Nil(t, s.SamplingStore)
+		s.CleanUp = func(t *testing.T) {
+			cleanUps++
+			require.NoError(t, f.Purge(context.Background()))
+		}
+	})
+	assert.Positive(t, cleanUps)
+}
@@ -6,9 +6,15 @@ package spanstore
 import (
 	"context"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	spanstore_v1 "github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// ErrDeleteTracesNotSupported is returned by Writer's DeleteTraces if the backend cannot delete traces.
+var ErrDeleteTracesNotSupported = spanstore_v1.ErrDeleteTracesNotSupported
+
 // Writer writes spans to storage.
 type Writer interface {
 	// WriteTrace writes a batch of spans to storage. Idempotent.
@@ -16,4 +22,11 @@ type Writer interface {
 	// so if any of the spans fail to be written an error is returned.
 	// Compatible with OTLP Exporter API.
 	WriteTraces(ctx context.Context, td ptrace.Traces) error
+
+	// DeleteTraces deletes all the spans of the given traces, e.g. to honor
+	// the deletion requests of data subjects. Deleting a trace that is not
+	// stored is not an error.
+	//
+	// Backends that cannot delete traces return ErrDeleteTracesNotSupported.
+	DeleteTraces(ctx context.Context, traceIDs []pcommon.TraceID) error
 }